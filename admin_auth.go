@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+
+	"abac_go_example/adminauth"
+	"abac_go_example/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminPrincipalKey is the gin context key holding the principal authenticated by AdminAuthMiddleware
+const adminPrincipalKey = "abac_admin_principal"
+
+// adminScopeOverrides are the admin operations whose scope differs from the method/tag default of adminScope
+var adminScopeOverrides = map[string]adminauth.Scope{
+	"policyImpact":              adminauth.ScopeRead, // POST, but only simulates the change
+	"restorePolicy":             adminauth.ScopeApprove,
+	"importBundle":              adminauth.ScopeApprove,
+	"importRecords":             adminauth.ScopeApprove, // Imported policies take effect immediately
+	"createException":           adminauth.ScopeApprove,
+	"deleteException":           adminauth.ScopeApprove,
+	"createRevocation":          adminauth.ScopeApprove,
+	"deleteRevocation":          adminauth.ScopeApprove,
+	"createElevation":           adminauth.ScopeApprove,
+	"deleteElevation":           adminauth.ScopeApprove,
+	"togglePolicies":            adminauth.ScopeApprove,
+	"createPolicySchedule":      adminauth.ScopeApprove,
+	"deletePolicySchedule":      adminauth.ScopeApprove,
+	"transferResourceOwnership": adminauth.ScopeApprove,
+	"setLockdown":               adminauth.ScopeApprove,
+	"liftLockdown":              adminauth.ScopeApprove,
+	"invalidateAttributeCache":  adminauth.ScopeApprove,
+	"releasePolicyQuarantine":   adminauth.ScopeApprove, // Puts the policy back into evaluation
+}
+
+// isAdminRoute reports whether route is an admin/PAP endpoint guarded by admin tokens; the demo
+// endpoints and the PDP endpoints called by PEPs stay authorized by ABACMiddleware alone
+func isAdminRoute(route openapi.Route) bool {
+	return route.Permission != "" && route.Tag != "demo" && route.Tag != "pdp"
+}
+
+// adminScope returns the scope an admin route requires: reads of audit trails and statistics need
+// ScopeAudit, other reads ScopeRead and writes ScopeAuthor, unless adminScopeOverrides says otherwise
+func adminScope(route openapi.Route) adminauth.Scope {
+	if scope, ok := adminScopeOverrides[route.OperationID]; ok {
+		return scope
+	}
+	if route.Method != http.MethodGet {
+		return adminauth.ScopeAuthor
+	}
+	if route.Tag == "audit" || route.Tag == "stats" {
+		return adminauth.ScopeAudit
+	}
+	return adminauth.ScopeRead
+}
+
+// AdminAuthMiddleware requires an admin bearer token whose roles grant scope. The principal is the
+// actor of audit trails; ABACMiddleware additionally authorizes its subject when the token names one.
+func (service *ABACService) AdminAuthMiddleware(scope adminauth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := service.adminAuth.Authenticate(c.Request)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="abac-admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required", "details": err.Error()})
+			return
+		}
+		if !principal.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":          "Admin role does not allow this operation",
+				"principal":      principal.Name,
+				"roles":          principal.Roles,
+				"required_scope": scope,
+			})
+			return
+		}
+
+		c.Set(adminPrincipalKey, principal)
+		c.Set(actorContextKey, principal.Name)
+		c.Next()
+	}
+}
+
+// requireApproval rejects with 403 a policy write that changes the live policy set when the principal
+// lacks ScopeApprove. Authors only create, edit and delete disabled (draft) policies; an approver puts
+// a draft into effect by enabling it. Without admin tokens there is no principal and writes pass.
+func requireApproval(c *gin.Context, policyID string, live bool) bool {
+	principal := adminPrincipal(c)
+	if !live || principal == nil || principal.Allows(adminauth.ScopeApprove) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":          "Enabled policies need approval: save the policy disabled and have an approver enable it",
+		"policy_id":      policyID,
+		"principal":      principal.Name,
+		"required_scope": adminauth.ScopeApprove,
+	})
+	return false
+}
+
+// adminPrincipal returns the principal authenticated by AdminAuthMiddleware, or nil
+func adminPrincipal(c *gin.Context) *adminauth.Principal {
+	principal, _ := c.Get(adminPrincipalKey)
+	p, _ := principal.(*adminauth.Principal)
+	return p
+}
@@ -0,0 +1,204 @@
+// Package adminauth authenticates callers of the admin/PAP endpoints with static bearer tokens and
+// separates their duties by role: policy authors change policies, approvers put changes into effect
+// and auditors only read. A token may also name an ABAC subject, so the PDP authorizes its own admin
+// API on top of the role check.
+package adminauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+var (
+	// ErrMissingToken is returned when a request carries no bearer token
+	ErrMissingToken = errors.New("missing admin bearer token")
+	// ErrInvalidToken is returned when the bearer token matches no configured principal
+	ErrInvalidToken = errors.New("invalid admin bearer token")
+)
+
+// Role is a duty an admin principal is trusted with
+type Role string
+
+const (
+	RoleAdmin          Role = "admin"           // Every scope; for break-glass and bootstrap tokens
+	RolePolicyAuthor   Role = "policy_author"   // Drafts and edits disabled policies
+	RolePolicyApprover Role = "policy_approver" // Puts changes into effect: enabling policies, bundles, exceptions, lockdown
+	RoleAuditor        Role = "auditor"         // Reads policies, audit trails and statistics
+)
+
+// Scope is the kind of access an admin endpoint requires
+type Scope string
+
+const (
+	ScopeRead    Scope = "read"    // Read policies and PAP entities
+	ScopeAudit   Scope = "audit"   // Read audit trails, decision streams and statistics
+	ScopeAuthor  Scope = "author"  // Create, update and delete disabled (draft) policies
+	ScopeApprove Scope = "approve" // Enable and disable policies, bulk and bundle imports, exceptions, lockdown, restores
+)
+
+// roleScopes grants scopes per role. Authors and approvers do not share a write scope: an author
+// drafts a disabled policy and only an approver can enable it, so a policy change needs two
+// principals to take effect. Enabled policies are edited by disabling them first; only the admin
+// role, which holds both scopes for break-glass, writes them directly.
+var roleScopes = map[Role][]Scope{
+	RoleAdmin:          {ScopeRead, ScopeAudit, ScopeAuthor, ScopeApprove},
+	RolePolicyAuthor:   {ScopeRead, ScopeAuthor},
+	RolePolicyApprover: {ScopeRead, ScopeApprove},
+	RoleAuditor:        {ScopeRead, ScopeAudit},
+}
+
+// Principal is an authenticated admin caller
+type Principal struct {
+	Name      string `json:"name"`
+	Roles     []Role `json:"roles"`
+	SubjectID string `json:"subject_id,omitempty"` // ABAC subject the PDP authorizes; empty relies on roles alone
+}
+
+// Allows reports whether one of the principal's roles grants scope
+func (p *Principal) Allows(scope Scope) bool {
+	for _, role := range p.Roles {
+		for _, granted := range roleScopes[role] {
+			if granted == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scopes lists the scopes granted by the principal's roles
+func (p *Principal) Scopes() []Scope {
+	seen := map[Scope]bool{}
+	var scopes []Scope
+	for _, role := range p.Roles {
+		for _, scope := range roleScopes[role] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i] < scopes[j] })
+	return scopes
+}
+
+// TokenEntry configures one static token. Prefer TokenSHA256 (hex SHA-256 of the token) so the
+// token file does not hold usable secrets; Token is accepted for local development.
+type TokenEntry struct {
+	Name        string `json:"name"`
+	Token       string `json:"token,omitempty"`
+	TokenSHA256 string `json:"token_sha256,omitempty"`
+	Roles       []Role `json:"roles"`
+	SubjectID   string `json:"subject_id,omitempty"`
+}
+
+// Authenticator resolves bearer tokens to principals
+type Authenticator struct {
+	principals map[[sha256.Size]byte]*Principal
+}
+
+// NewAuthenticator creates an authenticator for entries. Every entry needs a name, a token and known roles.
+func NewAuthenticator(entries []TokenEntry) (*Authenticator, error) {
+	a := &Authenticator{principals: make(map[[sha256.Size]byte]*Principal, len(entries))}
+	for i, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("admin token %d: name is required", i)
+		}
+		if len(entry.Roles) == 0 {
+			return nil, fmt.Errorf("admin token %q: at least one role is required", entry.Name)
+		}
+		for _, role := range entry.Roles {
+			if _, ok := roleScopes[role]; !ok {
+				return nil, fmt.Errorf("admin token %q: unknown role %q (allowed: %s)", entry.Name, role, strings.Join(roleNames(), ", "))
+			}
+		}
+
+		var hash [sha256.Size]byte
+		switch {
+		case entry.TokenSHA256 != "":
+			decoded, err := hex.DecodeString(entry.TokenSHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("admin token %q: token_sha256 must be a hex SHA-256 digest", entry.Name)
+			}
+			copy(hash[:], decoded)
+		case entry.Token != "":
+			hash = sha256.Sum256([]byte(entry.Token))
+		default:
+			return nil, fmt.Errorf("admin token %q: token or token_sha256 is required", entry.Name)
+		}
+		if existing, ok := a.principals[hash]; ok {
+			return nil, fmt.Errorf("admin tokens %q and %q are identical", existing.Name, entry.Name)
+		}
+		a.principals[hash] = &Principal{Name: entry.Name, Roles: entry.Roles, SubjectID: entry.SubjectID}
+	}
+	return a, nil
+}
+
+// LoadTokens reads a JSON array of TokenEntry from path
+func LoadTokens(path string) (*Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admin tokens: %w", err)
+	}
+	var entries []TokenEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse admin tokens %s: %w", path, err)
+	}
+	return NewAuthenticator(entries)
+}
+
+// AuthenticatorFromEnv loads the token file named by ABAC_ADMIN_TOKENS.
+// It returns nil (admin endpoints authorized by ABACMiddleware alone) when the variable is unset.
+func AuthenticatorFromEnv() (*Authenticator, error) {
+	path := os.Getenv(constants.EnvAdminTokens)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadTokens(path)
+}
+
+// Authenticate resolves the "Authorization: Bearer" token of r
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, ErrMissingToken
+	}
+	hash := sha256.Sum256([]byte(strings.TrimSpace(token)))
+
+	// Compare against every entry so lookup time does not depend on which token matched
+	var found *Principal
+	for candidate, principal := range a.principals {
+		if subtle.ConstantTimeCompare(candidate[:], hash[:]) == 1 {
+			found = principal
+		}
+	}
+	if found == nil {
+		return nil, ErrInvalidToken
+	}
+	return found, nil
+}
+
+// HashToken returns the token_sha256 value of a token
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func roleNames() []string {
+	names := make([]string, 0, len(roleScopes))
+	for role := range roleScopes {
+		names = append(names, string(role))
+	}
+	sort.Strings(names)
+	return names
+}
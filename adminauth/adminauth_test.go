@@ -0,0 +1,122 @@
+package adminauth
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func testAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+	a, err := NewAuthenticator([]TokenEntry{
+		{Name: "alice", Token: "author-token", Roles: []Role{RolePolicyAuthor}},
+		{Name: "bob", TokenSHA256: HashToken("approver-token"), Roles: []Role{RolePolicyApprover}, SubjectID: "user-002"},
+		{Name: "carol", Token: "auditor-token", Roles: []Role{RoleAuditor}},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	return a
+}
+
+func TestAuthenticate(t *testing.T) {
+	a := testAuthenticator(t)
+	authenticate := func(header string) (*Principal, error) {
+		req := httptest.NewRequest("GET", "/api/v1/policies", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		return a.Authenticate(req)
+	}
+
+	if p, err := authenticate("Bearer author-token"); err != nil || p.Name != "alice" {
+		t.Errorf("Expected alice, got %+v %v", p, err)
+	}
+	if p, err := authenticate("Bearer approver-token"); err != nil || p.Name != "bob" || p.SubjectID != "user-002" {
+		t.Errorf("Expected bob by token hash, got %+v %v", p, err)
+	}
+	if _, err := authenticate(""); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("Expected ErrMissingToken, got %v", err)
+	}
+	if _, err := authenticate("Basic YWxpY2U6c2VjcmV0"); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("Expected ErrMissingToken for basic auth, got %v", err)
+	}
+	if _, err := authenticate("Bearer guessed"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestRoleSeparation(t *testing.T) {
+	author := &Principal{Roles: []Role{RolePolicyAuthor}}
+	approver := &Principal{Roles: []Role{RolePolicyApprover}}
+	auditor := &Principal{Roles: []Role{RoleAuditor}}
+	admin := &Principal{Roles: []Role{RoleAdmin}}
+
+	tests := []struct {
+		principal *Principal
+		scope     Scope
+		expected  bool
+	}{
+		{author, ScopeAuthor, true},
+		{author, ScopeApprove, false},
+		{author, ScopeAudit, false},
+		{approver, ScopeApprove, true},
+		{approver, ScopeAuthor, false},
+		{auditor, ScopeRead, true},
+		{auditor, ScopeAudit, true},
+		{auditor, ScopeAuthor, false},
+		{auditor, ScopeApprove, false},
+		{admin, ScopeApprove, true},
+	}
+	for _, tt := range tests {
+		if result := tt.principal.Allows(tt.scope); result != tt.expected {
+			t.Errorf("%v Allows(%s) = %v, expected %v", tt.principal.Roles, tt.scope, result, tt.expected)
+		}
+	}
+
+	both := &Principal{Roles: []Role{RolePolicyAuthor, RoleAuditor}}
+	if scopes := both.Scopes(); !reflect.DeepEqual(scopes, []Scope{ScopeAudit, ScopeAuthor, ScopeRead}) {
+		t.Errorf("Unexpected scopes %v", scopes)
+	}
+}
+
+func TestNewAuthenticatorValidation(t *testing.T) {
+	invalid := map[string][]TokenEntry{
+		"missing name":    {{Token: "t", Roles: []Role{RoleAuditor}}},
+		"missing roles":   {{Name: "a", Token: "t"}},
+		"unknown role":    {{Name: "a", Token: "t", Roles: []Role{"superuser"}}},
+		"missing token":   {{Name: "a", Roles: []Role{RoleAuditor}}},
+		"malformed hash":  {{Name: "a", TokenSHA256: "abc", Roles: []Role{RoleAuditor}}},
+		"duplicate token": {{Name: "a", Token: "t", Roles: []Role{RoleAuditor}}, {Name: "b", TokenSHA256: HashToken("t"), Roles: []Role{RoleAdmin}}},
+	}
+	for name, entries := range invalid {
+		if _, err := NewAuthenticator(entries); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAuthenticatorFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvAdminTokens, "")
+	if a, err := AuthenticatorFromEnv(); a != nil || err != nil {
+		t.Errorf("Expected no authenticator, got %v %v", a, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "admin_tokens.json")
+	os.WriteFile(path, []byte(`[{"name": "ops", "token_sha256": "`+HashToken("ops-token")+`", "roles": ["admin"]}]`), 0o600)
+	t.Setenv(constants.EnvAdminTokens, path)
+	a, err := AuthenticatorFromEnv()
+	if err != nil {
+		t.Fatalf("AuthenticatorFromEnv failed: %v", err)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer ops-token")
+	if p, err := a.Authenticate(req); err != nil || p.Name != "ops" {
+		t.Errorf("Expected ops, got %+v %v", p, err)
+	}
+}
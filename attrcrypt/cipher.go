@@ -0,0 +1,188 @@
+// Package attrcrypt encrypts designated sensitive attribute values (PII such as "ssn" or "email")
+// of subjects and resources at rest with AES-GCM. Storage encrypts them on write and decrypts
+// them on read, so attribute enrichment and policies see plaintext values.
+package attrcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// tokenPrefix marks an encrypted attribute value: "enc:v1:<key id>:<base64(nonce || ciphertext)>"
+const tokenPrefix = "enc:v1:"
+
+// ErrMalformedToken is returned when an encrypted value cannot be parsed
+var ErrMalformedToken = errors.New("malformed encrypted attribute value")
+
+// Cipher encrypts the values of sensitive attribute keys. The attribute key is authenticated
+// with each value, so ciphertexts cannot be moved to another attribute.
+type Cipher struct {
+	keys      KeyProvider
+	sensitive map[string]bool
+}
+
+// NewCipher creates a cipher for the given top-level attribute keys
+func NewCipher(keys KeyProvider, sensitiveKeys []string) *Cipher {
+	sensitive := make(map[string]bool, len(sensitiveKeys))
+	for _, key := range sensitiveKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			sensitive[key] = true
+		}
+	}
+	return &Cipher{keys: keys, sensitive: sensitive}
+}
+
+// CipherFromEnv builds a cipher from ABAC_ENCRYPTED_ATTRIBUTES and ABAC_ATTRIBUTE_ENCRYPTION_KEYS.
+// It returns nil when no attribute keys are configured.
+func CipherFromEnv() (*Cipher, error) {
+	attributes := os.Getenv(constants.EnvEncryptedAttributes)
+	if strings.TrimSpace(attributes) == "" {
+		return nil, nil
+	}
+	rawKeys := os.Getenv(constants.EnvAttributeEncryptionKeys)
+	if rawKeys == "" {
+		return nil, fmt.Errorf("%s is set but %s is empty", constants.EnvEncryptedAttributes, constants.EnvAttributeEncryptionKeys)
+	}
+	keys, err := ParseKeys(rawKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", constants.EnvAttributeEncryptionKeys, err)
+	}
+	return NewCipher(keys, strings.Split(attributes, ",")), nil
+}
+
+// SensitiveKeys returns the encrypted attribute keys in sorted order
+func (c *Cipher) SensitiveKeys() []string {
+	keys := make([]string, 0, len(c.sensitive))
+	for key := range c.sensitive {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsSensitive reports whether values of the attribute key are encrypted
+func (c *Cipher) IsSensitive(key string) bool {
+	return c.sensitive[key]
+}
+
+// IsEncrypted reports whether value is an encrypted attribute value
+func IsEncrypted(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, tokenPrefix)
+}
+
+// EncryptAttributes returns a copy of attrs with the values of sensitive keys encrypted under
+// the current key. Values that are already encrypted or nil are kept as they are.
+func (c *Cipher) EncryptAttributes(attrs map[string]interface{}) (map[string]interface{}, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	encrypted := make(map[string]interface{}, len(attrs))
+	for key, value := range attrs {
+		if !c.sensitive[key] || value == nil || IsEncrypted(value) {
+			encrypted[key] = value
+			continue
+		}
+		token, err := c.Encrypt(key, value)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[key] = token
+	}
+	return encrypted, nil
+}
+
+// DecryptAttributes returns a copy of attrs with every encrypted value decrypted, including
+// values of keys no longer listed as sensitive
+func (c *Cipher) DecryptAttributes(attrs map[string]interface{}) (map[string]interface{}, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	decrypted := make(map[string]interface{}, len(attrs))
+	for key, value := range attrs {
+		if !IsEncrypted(value) {
+			decrypted[key] = value
+			continue
+		}
+		plain, err := c.Decrypt(key, value.(string))
+		if err != nil {
+			return nil, err
+		}
+		decrypted[key] = plain
+	}
+	return decrypted, nil
+}
+
+// Encrypt encrypts the JSON encoding of value for attribute key
+func (c *Cipher) Encrypt(key string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode attribute %q: %w", key, err)
+	}
+	dataKey, err := c.keys.CurrentKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(key))
+	return tokenPrefix + dataKey.ID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts an encrypted value of attribute key
+func (c *Cipher) Decrypt(key, token string) (interface{}, error) {
+	keyID, encoded, found := strings.Cut(strings.TrimPrefix(token, tokenPrefix), ":")
+	if !strings.HasPrefix(token, tokenPrefix) || !found {
+		return nil, fmt.Errorf("attribute %q: %w", key, ErrMalformedToken)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("attribute %q: %w", key, ErrMalformedToken)
+	}
+	dataKey, err := c.keys.Key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("attribute %q: %w", key, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("attribute %q: %w", key, ErrMalformedToken)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt attribute %q: %w", key, err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode attribute %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func newAEAD(key Key) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.Material)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key %q: %w", key.ID, err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,119 @@
+package attrcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func testKey(id string, b byte) Key {
+	return Key{ID: id, Material: []byte(strings.Repeat(string(b), 32))}
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	keys, err := NewStaticKeyProvider(testKey("k1", 'a'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher := NewCipher(keys, []string{"ssn", " email ", "phones", "salary"})
+
+	attrs := map[string]interface{}{
+		"ssn":        "123-45-6789",
+		"email":      "pii@example.com",
+		"phones":     []interface{}{"+84 1", "+84 2"},
+		"salary":     float64(1000),
+		"department": "engineering",
+		"nothing":    nil,
+	}
+	encrypted, err := cipher.EncryptAttributes(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"ssn", "email", "phones", "salary"} {
+		if !IsEncrypted(encrypted[key]) {
+			t.Errorf("expected %s to be encrypted, got %v", key, encrypted[key])
+		}
+	}
+	if encrypted["department"] != "engineering" || attrs["ssn"] != "123-45-6789" {
+		t.Errorf("expected only sensitive keys encrypted and the input unchanged, got %v / %v", encrypted, attrs)
+	}
+	if again, _ := cipher.EncryptAttributes(encrypted); again["ssn"] != encrypted["ssn"] {
+		t.Error("expected encrypted values to be kept as they are")
+	}
+
+	decrypted, err := cipher.DecryptAttributes(encrypted)
+	if err != nil || !reflect.DeepEqual(decrypted, attrs) {
+		t.Errorf("DecryptAttributes = %v, %v", decrypted, err)
+	}
+
+	// A ciphertext moved to another attribute fails authentication
+	if _, err := cipher.Decrypt("email", encrypted["ssn"].(string)); err == nil {
+		t.Error("expected a ciphertext of another attribute to be rejected")
+	}
+	if _, err := cipher.Decrypt("ssn", "enc:v1:k1:not base64!"); !errors.Is(err, ErrMalformedToken) {
+		t.Errorf("expected ErrMalformedToken, got %v", err)
+	}
+}
+
+func TestCipherKeyRotation(t *testing.T) {
+	old, _ := NewStaticKeyProvider(testKey("k1", 'a'))
+	token, err := NewCipher(old, []string{"ssn"}).Encrypt("ssn", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, _ := NewStaticKeyProvider(testKey("k2", 'b'), testKey("k1", 'a'))
+	cipher := NewCipher(rotated, []string{"ssn"})
+	if value, err := cipher.Decrypt("ssn", token); err != nil || value != "123" {
+		t.Errorf("expected the old key to decrypt, got %v, %v", value, err)
+	}
+	if newToken, _ := cipher.Encrypt("ssn", "123"); !strings.HasPrefix(newToken, "enc:v1:k2:") {
+		t.Errorf("expected new values under k2, got %s", newToken)
+	}
+
+	withoutOld, _ := NewStaticKeyProvider(testKey("k2", 'b'))
+	if _, err := NewCipher(withoutOld, nil).Decrypt("ssn", token); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))
+	k2 := base64.RawURLEncoding.EncodeToString([]byte(strings.Repeat("b", 16)))
+	provider, err := ParseKeys("k2=" + k2 + ", k1=" + k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := provider.CurrentKey(); current.ID != "k2" || len(current.Material) != 16 {
+		t.Errorf("expected k2 as the current key, got %+v", current)
+	}
+
+	for _, raw := range []string{"", "k1", "k1=" + base64.StdEncoding.EncodeToString([]byte("short")), "k1=" + k1 + ",k1=" + k1, "a:b=" + k1} {
+		if _, err := ParseKeys(raw); err == nil {
+			t.Errorf("expected ParseKeys(%q) to fail", raw)
+		}
+	}
+}
+
+func TestCipherFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvEncryptedAttributes, "")
+	if cipher, err := CipherFromEnv(); cipher != nil || err != nil {
+		t.Errorf("expected encryption disabled, got %v, %v", cipher, err)
+	}
+
+	t.Setenv(constants.EnvEncryptedAttributes, "ssn, email")
+	t.Setenv(constants.EnvAttributeEncryptionKeys, "")
+	if _, err := CipherFromEnv(); err == nil {
+		t.Error("expected an error without keys")
+	}
+
+	t.Setenv(constants.EnvAttributeEncryptionKeys, "k1="+base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32))))
+	cipher, err := CipherFromEnv()
+	if err != nil || !reflect.DeepEqual(cipher.SensitiveKeys(), []string{"email", "ssn"}) {
+		t.Errorf("CipherFromEnv = %v, %v", cipher, err)
+	}
+}
@@ -0,0 +1,99 @@
+package attrcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownKey is returned by KeyProvider.Key for key IDs the provider does not hold
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Key is an AES key (16, 24 or 32 bytes) identified by ID; the ID is stored with every
+// ciphertext so keys can be rotated without re-encrypting existing values first
+type Key struct {
+	ID       string
+	Material []byte
+}
+
+// KeyProvider supplies data keys. A KMS-backed provider typically unwraps data keys with the
+// KMS at startup (or caches them) so Key does not call the KMS per attribute.
+type KeyProvider interface {
+	// CurrentKey returns the key new values are encrypted with
+	CurrentKey() (Key, error)
+	// Key returns the key with the given ID, or ErrUnknownKey
+	Key(id string) (Key, error)
+}
+
+// StaticKeyProvider holds keys from configuration
+type StaticKeyProvider struct {
+	current string
+	keys    map[string]Key
+}
+
+// NewStaticKeyProvider creates a provider whose first key is the current one
+func NewStaticKeyProvider(keys ...Key) (*StaticKeyProvider, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+	provider := &StaticKeyProvider{current: keys[0].ID, keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" || strings.ContainsAny(key.ID, ":,=") {
+			return nil, fmt.Errorf("invalid encryption key id %q", key.ID)
+		}
+		if _, exists := provider.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate encryption key id %q", key.ID)
+		}
+		switch len(key.Material) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("encryption key %q has %d bytes, expected 16, 24 or 32", key.ID, len(key.Material))
+		}
+		provider.keys[key.ID] = key
+	}
+	return provider, nil
+}
+
+// ParseKeys parses "id=base64,id=base64" (standard or URL-safe base64) into a StaticKeyProvider
+func ParseKeys(raw string) (*StaticKeyProvider, error) {
+	var keys []Key
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid encryption key %q: expected id=base64", entry)
+		}
+		material, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", id, err)
+		}
+		keys = append(keys, Key{ID: strings.TrimSpace(id), Material: material})
+	}
+	return NewStaticKeyProvider(keys...)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if material, err := encoding.DecodeString(encoded); err == nil {
+			return material, nil
+		}
+	}
+	return nil, fmt.Errorf("key is not base64")
+}
+
+func (p *StaticKeyProvider) CurrentKey() (Key, error) {
+	return p.keys[p.current], nil
+}
+
+func (p *StaticKeyProvider) Key(id string) (Key, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return Key{}, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	return key, nil
+}
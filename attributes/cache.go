@@ -0,0 +1,274 @@
+package attributes
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// EntityAction labels cached actions; subjects and resources use EntitySubject and EntityResource
+const EntityAction = "action"
+
+// AttributeCacheConfig configures the cache of storage lookups made during context enrichment.
+// A zero TTL disables caching for that entity type.
+type AttributeCacheConfig struct {
+	SubjectTTL  time.Duration `json:"subject_ttl"`  // Resolved group membership of a subject
+	ResourceTTL time.Duration `json:"resource_ttl"` // Stored resources
+	ActionTTL   time.Duration `json:"action_ttl"`   // Stored actions
+	MaxEntries  int           `json:"max_entries"`
+}
+
+// DefaultAttributeCacheConfig returns the default attribute cache configuration
+func DefaultAttributeCacheConfig() *AttributeCacheConfig {
+	ttl := constants.DefaultAttributeCacheTTLMs * time.Millisecond
+	return &AttributeCacheConfig{
+		SubjectTTL:  ttl,
+		ResourceTTL: ttl,
+		ActionTTL:   ttl,
+		MaxEntries:  constants.DefaultAttributeCacheMaxEntries,
+	}
+}
+
+// AttributeCacheConfigFromEnv reads ABAC_ATTRIBUTE_CACHE_TTL, the per-entity overrides
+// ABAC_ATTRIBUTE_CACHE_{SUBJECT,RESOURCE,ACTION}_TTL and ABAC_ATTRIBUTE_CACHE_MAX_ENTRIES.
+// It returns nil (cache disabled) when the TTL is unset, zero or invalid.
+func AttributeCacheConfigFromEnv() *AttributeCacheConfig {
+	ttl, err := time.ParseDuration(os.Getenv(constants.EnvAttributeCacheTTL))
+	if err != nil || ttl <= 0 {
+		return nil
+	}
+
+	config := DefaultAttributeCacheConfig()
+	config.SubjectTTL = ttlFromEnv(constants.EnvAttributeCacheSubjectTTL, ttl)
+	config.ResourceTTL = ttlFromEnv(constants.EnvAttributeCacheResourceTTL, ttl)
+	config.ActionTTL = ttlFromEnv(constants.EnvAttributeCacheActionTTL, ttl)
+	if maxEntries, err := strconv.Atoi(os.Getenv(constants.EnvAttributeCacheMaxEntries)); err == nil && maxEntries > 0 {
+		config.MaxEntries = maxEntries
+	}
+	return config
+}
+
+// ttlFromEnv reads a non-negative duration from name, falling back to fallback when unset or invalid
+func ttlFromEnv(name string, fallback time.Duration) time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || ttl < 0 {
+		return fallback
+	}
+	return ttl
+}
+
+// AttributeCacheStats reports attribute cache activity
+type AttributeCacheStats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"` // Entries dropped by Invalidate* and Purge
+	Evictions     int64 `json:"evictions"`
+	Size          int   `json:"size"`
+}
+
+// attributeCacheKey identifies a cached entity
+type attributeCacheKey struct {
+	entityType string
+	id         string
+}
+
+// attributeCacheEntry is a cached lookup result and its expiry
+type attributeCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// AttributeCache is a small TTL cache of the subject, resource and action lookups made by
+// AttributeResolver, since the same entities are looked up on every request of a session.
+// Only successful lookups are cached. It is safe for concurrent use.
+type AttributeCache struct {
+	mu         sync.Mutex
+	ttls       map[string]time.Duration
+	maxEntries int
+	clock      clock.Clock
+	entries    map[attributeCacheKey]attributeCacheEntry
+	stats      AttributeCacheStats
+}
+
+// NewAttributeCache creates an attribute cache; c supplies the time used for expiry (nil = system clock)
+func NewAttributeCache(config *AttributeCacheConfig, c clock.Clock) *AttributeCache {
+	if config == nil {
+		config = DefaultAttributeCacheConfig()
+	}
+	return &AttributeCache{
+		ttls: map[string]time.Duration{
+			EntitySubject:  config.SubjectTTL,
+			EntityResource: config.ResourceTTL,
+			EntityAction:   config.ActionTTL,
+		},
+		maxEntries: config.MaxEntries,
+		clock:      clock.OrReal(c),
+		entries:    make(map[attributeCacheKey]attributeCacheEntry),
+	}
+}
+
+// get returns the cached value of an entity, if caching is enabled for its type and it has not expired
+func (c *AttributeCache) get(entityType, id string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttls[entityType] <= 0 {
+		return nil, false
+	}
+	key := attributeCacheKey{entityType: entityType, id: id}
+	entry, ok := c.entries[key]
+	if ok && !c.clock.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	return entry.value, true
+}
+
+// store caches the value of an entity. When the cache is full, expired entries are
+// dropped first and then the entry closest to expiry is evicted.
+func (c *AttributeCache) store(entityType, id string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.ttls[entityType]
+	if ttl <= 0 {
+		return
+	}
+	now := c.clock.Now()
+	key := attributeCacheKey{entityType: entityType, id: id}
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = attributeCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// evict makes room for one entry; callers must hold the lock
+func (c *AttributeCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			c.stats.Evictions++
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	var oldestKey attributeCacheKey
+	var oldest time.Time
+	found := false
+	for key, entry := range c.entries {
+		if !found || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest, found = key, entry.expiresAt, true
+		}
+	}
+	delete(c.entries, oldestKey)
+	c.stats.Evictions++
+}
+
+// Invalidate drops the cached entity of entityType with the given ID. An empty id drops every
+// entity of that type (e.g. after group membership changes); an empty entityType drops everything.
+func (c *AttributeCache) Invalidate(entityType, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if (entityType == "" || key.entityType == entityType) && (id == "" || key.id == id) {
+			delete(c.entries, key)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+// InvalidateSubject drops the cached lookups of a subject, e.g. after its groups change
+func (c *AttributeCache) InvalidateSubject(subjectID string) {
+	c.Invalidate(EntitySubject, subjectID)
+}
+
+// InvalidateResource drops a cached resource, e.g. after its attributes change
+func (c *AttributeCache) InvalidateResource(resourceID string) {
+	c.Invalidate(EntityResource, resourceID)
+}
+
+// InvalidateAction drops a cached action
+func (c *AttributeCache) InvalidateAction(name string) {
+	c.Invalidate(EntityAction, name)
+}
+
+// Purge drops every cached entity
+func (c *AttributeCache) Purge() {
+	c.Invalidate("", "")
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *AttributeCache) Stats() AttributeCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
+
+// SetAttributeCache configures the cache of storage lookups (nil disables caching)
+func (r *AttributeResolver) SetAttributeCache(cache *AttributeCache) {
+	r.cache = cache
+}
+
+// GetAttributeCache returns the configured attribute cache, or nil when caching is disabled
+func (r *AttributeResolver) GetAttributeCache() *AttributeCache {
+	return r.cache
+}
+
+// getResource looks up a resource, through the cache when one is configured.
+// Cached resources are shared between requests and must not be mutated.
+func (r *AttributeResolver) getResource(resourceID string) (*models.Resource, error) {
+	if r.cache != nil {
+		if cached, ok := r.cache.get(EntityResource, resourceID); ok {
+			return cached.(*models.Resource), nil
+		}
+	}
+	resource, err := r.storage.GetResource(resourceID)
+	if err == nil && resource != nil && r.cache != nil {
+		r.cache.store(EntityResource, resourceID, resource)
+	}
+	return resource, err
+}
+
+// getAction looks up an action, through the cache when one is configured
+func (r *AttributeResolver) getAction(name string) (*models.Action, error) {
+	if r.cache != nil {
+		if cached, ok := r.cache.get(EntityAction, name); ok {
+			return cached.(*models.Action), nil
+		}
+	}
+	action, err := r.storage.GetAction(name)
+	if err == nil && action != nil && r.cache != nil {
+		r.cache.store(EntityAction, name, action)
+	}
+	return action, err
+}
+
+// lookupSubjectGroups resolves the groups of a subject, through the cache when one is configured
+func (r *AttributeResolver) lookupSubjectGroups(groupStore storage.GroupStore, subjectID string) ([]string, error) {
+	if r.cache != nil {
+		if cached, ok := r.cache.get(EntitySubject, subjectID); ok {
+			return cached.([]string), nil
+		}
+	}
+	groups, err := storage.ResolveGroups(groupStore, models.GroupMemberSubject, subjectID)
+	if err == nil && r.cache != nil {
+		r.cache.store(EntitySubject, subjectID, groups)
+	}
+	return groups, err
+}
@@ -0,0 +1,147 @@
+package attributes
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// countingStorage counts the lookups made during enrichment
+type countingStorage struct {
+	*storage.MockStorage
+	resourceLookups int
+	actionLookups   int
+	groupLookups    int
+}
+
+func (s *countingStorage) GetResource(id string) (*models.Resource, error) {
+	s.resourceLookups++
+	return s.MockStorage.GetResource(id)
+}
+
+func (s *countingStorage) GetAction(name string) (*models.Action, error) {
+	s.actionLookups++
+	return s.MockStorage.GetAction(name)
+}
+
+func (s *countingStorage) GetDirectGroups(memberType, memberID string) ([]string, error) {
+	s.groupLookups++
+	return s.MockStorage.GetDirectGroups(memberType, memberID)
+}
+
+func TestAttributeCache_EnrichContext(t *testing.T) {
+	store := &countingStorage{MockStorage: storage.NewMockStorage()}
+	store.CreateResource(&models.Resource{ID: "doc-1", ResourceID: "doc-1", Attributes: map[string]interface{}{"classification": "internal"}})
+	store.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	store.CreateGroup(&models.Group{ID: "finance"})
+	store.AddGroupMember("finance", models.GroupMemberSubject, "alice")
+
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	resolver := NewAttributeResolver(store)
+	resolver.SetClock(mockClock)
+	cache := NewAttributeCache(&AttributeCacheConfig{SubjectTTL: time.Minute, ResourceTTL: time.Minute, ActionTTL: time.Hour}, mockClock)
+	resolver.SetAttributeCache(cache)
+
+	enrich := func() *models.EvaluationContext {
+		t.Helper()
+		context, err := resolver.EnrichContext(&models.EvaluationRequest{
+			RequestID:  "cache-001",
+			Subject:    models.NewMockUserSubject("alice", "alice"),
+			ResourceID: "doc-1",
+			Action:     "read",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		return context
+	}
+
+	for i := 0; i < 3; i++ {
+		enrich()
+	}
+	if store.resourceLookups != 1 || store.actionLookups != 1 || store.groupLookups != 2 {
+		t.Errorf("Expected one lookup per entity, got resource=%d action=%d groups=%d",
+			store.resourceLookups, store.actionLookups, store.groupLookups)
+	}
+	if stats := cache.Stats(); stats.Hits != 6 || stats.Misses != 3 || stats.Size != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Changes are picked up after explicit invalidation
+	store.UpdateResource(&models.Resource{ID: "doc-1", ResourceID: "doc-1", Attributes: map[string]interface{}{"classification": "secret"}})
+	store.AddGroupMember("finance", models.GroupMemberSubject, "bob")
+	store.CreateGroup(&models.Group{ID: "auditors"})
+	store.AddGroupMember("auditors", models.GroupMemberSubject, "alice")
+	if got := enrich().Resource.Attributes["classification"]; got != "internal" {
+		t.Errorf("Expected cached resource before invalidation, got %v", got)
+	}
+	cache.InvalidateResource("doc-1")
+	cache.InvalidateSubject("alice")
+	context := enrich()
+	if got := context.Resource.Attributes["classification"]; got != "secret" {
+		t.Errorf("Expected updated resource after invalidation, got %v", got)
+	}
+	if groups, _ := context.Subject.Attributes[constants.ContextKeyGroups].([]interface{}); len(groups) != 2 {
+		t.Errorf("Expected updated groups after invalidation, got %v", context.Subject.Attributes[constants.ContextKeyGroups])
+	}
+
+	// Per-entity TTLs: subjects and resources expire, the action is still cached
+	mockClock.Advance(2 * time.Minute)
+	enrich()
+	if store.resourceLookups != 3 || store.actionLookups != 1 {
+		t.Errorf("Expected TTL expiry of the resource only, got resource=%d action=%d", store.resourceLookups, store.actionLookups)
+	}
+
+	cache.Purge()
+	if stats := cache.Stats(); stats.Size != 0 {
+		t.Errorf("Expected empty cache after purge, got %+v", stats)
+	}
+}
+
+func TestAttributeCache_Eviction(t *testing.T) {
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	cache := NewAttributeCache(&AttributeCacheConfig{ResourceTTL: time.Minute, MaxEntries: 2}, mockClock)
+
+	cache.store(EntitySubject, "alice", []string{"finance"}) // Subject caching disabled
+	cache.store(EntityResource, "doc-1", &models.Resource{ID: "doc-1"})
+	mockClock.Advance(time.Second)
+	cache.store(EntityResource, "doc-2", &models.Resource{ID: "doc-2"})
+	cache.store(EntityResource, "doc-3", &models.Resource{ID: "doc-3"})
+
+	if _, ok := cache.get(EntitySubject, "alice"); ok {
+		t.Error("Expected subject caching to be disabled by a zero TTL")
+	}
+	if _, ok := cache.get(EntityResource, "doc-1"); ok {
+		t.Error("Expected the entry closest to expiry to be evicted")
+	}
+	if _, ok := cache.get(EntityResource, "doc-3"); !ok {
+		t.Error("Expected the newest entry to be cached")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Size != 2 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestAttributeCacheConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvAttributeCacheTTL, "")
+	if config := AttributeCacheConfigFromEnv(); config != nil {
+		t.Fatalf("Expected cache disabled, got %+v", config)
+	}
+
+	t.Setenv(constants.EnvAttributeCacheTTL, "30s")
+	t.Setenv(constants.EnvAttributeCacheSubjectTTL, "5s")
+	t.Setenv(constants.EnvAttributeCacheActionTTL, "0")
+	t.Setenv(constants.EnvAttributeCacheMaxEntries, "500")
+	config := AttributeCacheConfigFromEnv()
+	if config == nil {
+		t.Fatal("Expected cache enabled")
+	}
+	if config.SubjectTTL != 5*time.Second || config.ResourceTTL != 30*time.Second || config.ActionTTL != 0 || config.MaxEntries != 500 {
+		t.Errorf("Unexpected config %+v", config)
+	}
+}
@@ -0,0 +1,128 @@
+package attributes
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// UnknownPatchAgeDays is reported as device.patch_age_days when the patch level is unknown,
+// so "patched within N days" conditions fail closed
+const UnknownPatchAgeDays = math.MaxInt32
+
+// osPatchLevelLayout is the date format of DevicePosture.OSPatchLevel (security patch date)
+const osPatchLevelLayout = "2006-01-02"
+
+// DevicePosture is the security posture of the device a request comes from
+type DevicePosture struct {
+	Managed       bool                   // Enrolled in device management (MDM)
+	DiskEncrypted bool                   // Full disk encryption enabled
+	OSPatchLevel  string                 // Date of the installed OS security patch, e.g. "2024-10-01"
+	Compliant     bool                   // Overall compliance verdict of the management system
+	Attributes    map[string]interface{} // Provider-specific attributes, added as device attributes
+}
+
+// DevicePostureProvider looks up device posture during context enrichment so zero-trust policies
+// can require managed, encrypted and patched devices (e.g. device.managed, device.patch_age_days).
+// deviceID is empty when the request does not identify its device.
+type DevicePostureProvider interface {
+	DevicePosture(request *models.EvaluationRequest, deviceID string) (*DevicePosture, error)
+}
+
+// DevicePostureProviderFunc adapts a function to the DevicePostureProvider interface
+type DevicePostureProviderFunc func(request *models.EvaluationRequest, deviceID string) (*DevicePosture, error)
+
+// DevicePosture calls f
+func (f DevicePostureProviderFunc) DevicePosture(request *models.EvaluationRequest, deviceID string) (*DevicePosture, error) {
+	return f(request, deviceID)
+}
+
+// StaticDevicePostureProvider serves postures from a fixed inventory keyed by device ID.
+// Unknown devices are reported as unmanaged.
+type StaticDevicePostureProvider map[string]DevicePosture
+
+// DevicePosture returns the inventory entry of deviceID
+func (p StaticDevicePostureProvider) DevicePosture(request *models.EvaluationRequest, deviceID string) (*DevicePosture, error) {
+	posture, ok := p[deviceID]
+	if !ok {
+		return &DevicePosture{}, nil
+	}
+	return &posture, nil
+}
+
+// SetDevicePostureProvider configures the provider invoked during enrichment (nil disables device attributes)
+func (r *AttributeResolver) SetDevicePostureProvider(provider DevicePostureProvider) {
+	r.deviceProvider = provider
+}
+
+// resolveDevicePosture returns the device attributes of the request.
+// Provider failures fail closed: the device is reported unmanaged, unencrypted and non-compliant.
+func (r *AttributeResolver) resolveDevicePosture(request *models.EvaluationRequest, subject *models.Subject, at time.Time) map[string]interface{} {
+	if r.deviceProvider == nil {
+		return nil
+	}
+
+	deviceID := RequestDeviceID(request, subject)
+	posture, err := r.deviceProvider.DevicePosture(request, deviceID)
+	if err != nil || posture == nil {
+		log.Printf("Warning: device posture unavailable for device %q: %v", deviceID, err)
+		return map[string]interface{}{
+			constants.DeviceKeyID:                 deviceID,
+			constants.DeviceKeyManaged:            false,
+			constants.DeviceKeyDiskEncrypted:      false,
+			constants.DeviceKeyCompliant:          false,
+			constants.DeviceKeyOSPatchLevel:       "",
+			constants.DeviceKeyPatchAgeDays:       UnknownPatchAgeDays,
+			constants.DeviceKeyPostureUnavailable: true,
+		}
+	}
+
+	device := make(map[string]interface{}, len(posture.Attributes)+7)
+	for key, value := range posture.Attributes {
+		device[key] = value
+	}
+	device[constants.DeviceKeyID] = deviceID
+	device[constants.DeviceKeyManaged] = posture.Managed
+	device[constants.DeviceKeyDiskEncrypted] = posture.DiskEncrypted
+	device[constants.DeviceKeyCompliant] = posture.Compliant
+	device[constants.DeviceKeyOSPatchLevel] = posture.OSPatchLevel
+	device[constants.DeviceKeyPatchAgeDays] = patchAgeDays(posture.OSPatchLevel, at)
+	device[constants.DeviceKeyPostureUnavailable] = false
+	return device
+}
+
+// RequestDeviceID returns the device_id of the request context, the environment attributes or
+// the subject attributes (device subjects), in that order
+func RequestDeviceID(request *models.EvaluationRequest, subject *models.Subject) string {
+	if id, ok := request.Context[constants.DeviceKeyID]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	if request.Environment != nil {
+		if id, ok := request.Environment.Attributes[constants.DeviceKeyID]; ok && id != nil {
+			return fmt.Sprint(id)
+		}
+	}
+	if subject != nil {
+		if id, ok := subject.Attributes[constants.DeviceKeyID]; ok && id != nil {
+			return fmt.Sprint(id)
+		}
+	}
+	return ""
+}
+
+// patchAgeDays returns the whole days between the patch level date and at, or UnknownPatchAgeDays
+func patchAgeDays(patchLevel string, at time.Time) int {
+	patched, err := time.Parse(osPatchLevelLayout, patchLevel)
+	if err != nil {
+		return UnknownPatchAgeDays
+	}
+	age := int(at.Sub(patched).Hours() / 24)
+	if age < 0 {
+		return 0
+	}
+	return age
+}
@@ -0,0 +1,118 @@
+package attributes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestDevicePostureProvider(t *testing.T) {
+	mockStore := storage.NewMockStorage()
+	mockStore.CreateResource(&models.Resource{ID: "res-001", ResourceID: "res-001"})
+	mockStore.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+
+	now := time.Date(2024, time.November, 1, 9, 0, 0, 0, time.UTC)
+	request := &models.EvaluationRequest{
+		RequestID:  "device-001",
+		Subject:    models.NewMockUserSubject("sub-001", "testuser"),
+		ResourceID: "res-001",
+		Action:     "read",
+		Context:    map[string]interface{}{constants.DeviceKeyID: "laptop-42"},
+	}
+
+	t.Run("no provider", func(t *testing.T) {
+		context, err := NewAttributeResolver(mockStore).EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		if context.Device != nil {
+			t.Errorf("Expected no device attributes, got %v", context.Device)
+		}
+	})
+
+	t.Run("inventory posture", func(t *testing.T) {
+		resolver := NewAttributeResolver(mockStore)
+		resolver.SetClock(clock.NewMockClock(now))
+		resolver.SetDevicePostureProvider(StaticDevicePostureProvider{
+			"laptop-42": {Managed: true, DiskEncrypted: true, OSPatchLevel: "2024-10-01", Compliant: true,
+				Attributes: map[string]interface{}{"platform": "macos"}},
+		})
+
+		context, err := resolver.EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		expected := map[string]interface{}{
+			constants.DeviceKeyID:                 "laptop-42",
+			constants.DeviceKeyManaged:            true,
+			constants.DeviceKeyDiskEncrypted:      true,
+			constants.DeviceKeyCompliant:          true,
+			constants.DeviceKeyOSPatchLevel:       "2024-10-01",
+			constants.DeviceKeyPatchAgeDays:       31,
+			constants.DeviceKeyPostureUnavailable: false,
+			"platform":                            "macos",
+		}
+		for key, value := range expected {
+			if context.Device[key] != value {
+				t.Errorf("Expected device %s = %v, got %v", key, value, context.Device[key])
+			}
+		}
+	})
+
+	t.Run("unknown device is unmanaged", func(t *testing.T) {
+		resolver := NewAttributeResolver(mockStore)
+		resolver.SetDevicePostureProvider(StaticDevicePostureProvider{})
+
+		context, err := resolver.EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		if context.Device[constants.DeviceKeyManaged] != false || context.Device[constants.DeviceKeyPatchAgeDays] != UnknownPatchAgeDays {
+			t.Errorf("Expected an unmanaged device with unknown patch age, got %v", context.Device)
+		}
+	})
+
+	t.Run("provider failure fails closed", func(t *testing.T) {
+		resolver := NewAttributeResolver(mockStore)
+		resolver.SetDevicePostureProvider(DevicePostureProviderFunc(func(req *models.EvaluationRequest, deviceID string) (*DevicePosture, error) {
+			return nil, fmt.Errorf("MDM unreachable")
+		}))
+
+		context, err := resolver.EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		if context.Device[constants.DeviceKeyPostureUnavailable] != true || context.Device[constants.DeviceKeyManaged] != false ||
+			context.Device[constants.DeviceKeyDiskEncrypted] != false || context.Device[constants.DeviceKeyCompliant] != false {
+			t.Errorf("Expected a fail-closed posture, got %v", context.Device)
+		}
+	})
+}
+
+func TestRequestDeviceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *models.EvaluationRequest
+		subject  *models.Subject
+		expected string
+	}{
+		{"request context", &models.EvaluationRequest{Context: map[string]interface{}{"device_id": "ctx"},
+			Environment: &models.EnvironmentInfo{Attributes: map[string]interface{}{"device_id": "env"}}}, nil, "ctx"},
+		{"environment attribute", &models.EvaluationRequest{
+			Environment: &models.EnvironmentInfo{Attributes: map[string]interface{}{"device_id": "env"}}}, nil, "env"},
+		{"device subject", &models.EvaluationRequest{}, &models.Subject{Attributes: models.JSONMap{"device_id": 7}}, "7"},
+		{"unidentified", &models.EvaluationRequest{}, &models.Subject{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id := RequestDeviceID(tt.request, tt.subject); id != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, id)
+			}
+		})
+	}
+}
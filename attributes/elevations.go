@@ -0,0 +1,68 @@
+package attributes
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/storage"
+)
+
+// resolveSubjectElevations merges the subject's elevations active at `at` into attrs when the storage
+// implements storage.ElevationStore: elevated roles are appended to attrs["roles"] and elevated
+// attributes replace stored ones, later elevations (by ID) winning. It returns the IDs of the applied
+// elevations. Elevations are looked up on every evaluation, never cached, so they end exactly at
+// their expiry; lookup errors fail enrichment.
+func (r *AttributeResolver) resolveSubjectElevations(subjectID string, attrs map[string]interface{}, at time.Time) ([]string, error) {
+	elevationStore, ok := r.storage.(storage.ElevationStore)
+	if !ok {
+		return nil, nil
+	}
+
+	elevations, err := elevationStore.FindActiveElevations(subjectID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve elevations of subject '%s': %w", subjectID, err)
+	}
+	if len(elevations) == 0 {
+		return nil, nil
+	}
+
+	var roles []interface{}
+	seen := make(map[string]bool)
+	addRole := func(role string) {
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	switch existing := attrs[constants.ContextKeyRoles].(type) {
+	case []interface{}:
+		for _, role := range existing {
+			if s, ok := role.(string); ok {
+				addRole(s)
+			}
+		}
+	case []string:
+		for _, role := range existing {
+			addRole(role)
+		}
+	case string:
+		addRole(existing)
+	}
+
+	applied := make([]string, 0, len(elevations))
+	for _, elevation := range elevations {
+		for _, role := range elevation.Roles {
+			addRole(role)
+		}
+		for key, value := range elevation.Attributes {
+			attrs[key] = value
+		}
+		applied = append(applied, elevation.ID)
+	}
+	// []interface{} so array operators (ArrayContains) can walk the list
+	if roles != nil {
+		attrs[constants.ContextKeyRoles] = roles
+	}
+	return applied, nil
+}
@@ -0,0 +1,59 @@
+package attributes
+
+import (
+	"fmt"
+	"sort"
+
+	"abac_go_example/constants"
+	"abac_go_example/storage"
+)
+
+// resolveSubjectGroups sets attrs["groups"] to every group the subject belongs to, directly or through
+// nested groups, when the storage implements storage.GroupStore. Groups already present in the subject
+// attributes (e.g. from the identity provider) are kept. Lookup errors fail enrichment so policies
+// never evaluate against a partial group list.
+func (r *AttributeResolver) resolveSubjectGroups(subjectID string, attrs map[string]interface{}) error {
+	groupStore, ok := r.storage.(storage.GroupStore)
+	if !ok {
+		return nil
+	}
+
+	resolved, err := r.lookupSubjectGroups(groupStore, subjectID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve groups of subject '%s': %w", subjectID, err)
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(resolved))
+	for _, group := range resolved {
+		seen[group] = true
+	}
+	switch existing := attrs[constants.ContextKeyGroups].(type) {
+	case []interface{}:
+		for _, group := range existing {
+			if s, ok := group.(string); ok {
+				seen[s] = true
+			}
+		}
+	case []string:
+		for _, group := range existing {
+			seen[group] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for group := range seen {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	// []interface{} so array operators (ArrayContains) can walk the list
+	groups := make([]interface{}, len(names))
+	for i, group := range names {
+		groups[i] = group
+	}
+	attrs[constants.ContextKeyGroups] = groups
+	return nil
+}
@@ -0,0 +1,90 @@
+package attributes
+
+import (
+	"reflect"
+	"sort"
+
+	"abac_go_example/models"
+)
+
+// MergeStrategy controls how request-supplied attributes are combined with stored attributes
+type MergeStrategy string
+
+const (
+	// MergeRequestOverrides lets request-supplied attributes replace stored values
+	MergeRequestOverrides MergeStrategy = "request_overrides"
+	// MergeStoredWins keeps stored values and only fills keys missing from storage (default)
+	MergeStoredWins MergeStrategy = "stored_wins"
+	// MergeStoredOnly ignores request-supplied attributes entirely
+	MergeStoredOnly MergeStrategy = "stored_only"
+)
+
+// Conflict resolution labels reported in AttributeConflict.Resolution
+const (
+	ResolutionRequest = "request"
+	ResolutionStored  = "stored"
+)
+
+// Entity labels reported in AttributeConflict.Entity
+const (
+	EntitySubject  = "subject"
+	EntityResource = "resource"
+)
+
+// mergeAttributes returns a copy of stored merged with overrides according to strategy.
+// Keys present in both maps with different values are reported as conflicts.
+func mergeAttributes(entity string, stored, overrides map[string]interface{}, strategy MergeStrategy) (map[string]interface{}, []models.AttributeConflict) {
+	merged := make(map[string]interface{}, len(stored)+len(overrides))
+	for k, v := range stored {
+		merged[k] = v
+	}
+
+	if strategy == MergeStoredOnly {
+		return merged, nil
+	}
+
+	var conflicts []models.AttributeConflict
+	for key, requestValue := range overrides {
+		storedValue, exists := stored[key]
+		if !exists {
+			merged[key] = requestValue
+			continue
+		}
+
+		if reflect.DeepEqual(storedValue, requestValue) {
+			continue
+		}
+
+		resolution := ResolutionRequest
+		if strategy == MergeStoredWins {
+			resolution = ResolutionStored
+		} else {
+			merged[key] = requestValue
+		}
+
+		conflicts = append(conflicts, models.AttributeConflict{
+			Entity:       entity,
+			Key:          key,
+			StoredValue:  storedValue,
+			RequestValue: requestValue,
+			Resolution:   resolution,
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+
+	return merged, conflicts
+}
+
+// requestOverrides extracts the attribute override map stored under key in the request context
+func requestOverrides(context map[string]interface{}, key string) map[string]interface{} {
+	switch v := context[key].(type) {
+	case map[string]interface{}:
+		return v
+	case models.JSONMap:
+		return v
+	}
+	return nil
+}
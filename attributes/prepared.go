@@ -0,0 +1,196 @@
+package attributes
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// PreparedSubjectConfig configures the prepared subject contexts of long-lived sessions
+type PreparedSubjectConfig struct {
+	TTL        time.Duration `json:"ttl"`         // How long a prepared context is reused; bounds staleness of group membership
+	MaxEntries int           `json:"max_entries"` // Maximum prepared sessions; the one closest to expiry is evicted
+}
+
+// DefaultPreparedSubjectConfig returns the default prepared subject configuration
+func DefaultPreparedSubjectConfig() *PreparedSubjectConfig {
+	return &PreparedSubjectConfig{
+		TTL:        constants.DefaultPreparedSubjectTTLMs * time.Millisecond,
+		MaxEntries: constants.DefaultPreparedSubjectMaxEntries,
+	}
+}
+
+// PreparedSubject is the subject portion of an evaluation context built once at session start: the
+// stored attributes plus resolved group membership. Evaluations of the session reuse it instead of
+// loading the subject and expanding its groups again. Elevations are still resolved per evaluation.
+type PreparedSubject struct {
+	SessionID   string                 `json:"session_id"`
+	SubjectID   string                 `json:"subject_id"`
+	SubjectType string                 `json:"subject_type"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	PreparedAt  time.Time              `json:"prepared_at"`
+	ExpiresAt   time.Time              `json:"expires_at"`
+}
+
+// GetID, GetType, GetAttributes, GetDisplayName and IsActive make a prepared subject usable as the
+// subject of an evaluation request
+func (p *PreparedSubject) GetID() string                         { return p.SubjectID }
+func (p *PreparedSubject) GetType() models.SubjectType           { return models.SubjectType(p.SubjectType) }
+func (p *PreparedSubject) GetAttributes() map[string]interface{} { return p.Attributes }
+func (p *PreparedSubject) GetDisplayName() string                { return p.SubjectID }
+func (p *PreparedSubject) IsActive() bool                        { return true }
+
+// PreparedSubjects keeps prepared subject contexts by session ID. It is safe for concurrent use.
+type PreparedSubjects struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	clock      clock.Clock
+	entries    map[string]*PreparedSubject
+}
+
+// NewPreparedSubjects creates a prepared subject store; c supplies the time used for expiry (nil = system clock)
+func NewPreparedSubjects(config *PreparedSubjectConfig, c clock.Clock) *PreparedSubjects {
+	if config == nil {
+		config = DefaultPreparedSubjectConfig()
+	}
+	return &PreparedSubjects{
+		ttl:        config.TTL,
+		maxEntries: config.MaxEntries,
+		clock:      clock.OrReal(c),
+		entries:    make(map[string]*PreparedSubject),
+	}
+}
+
+// Get returns the unexpired prepared context of a session, when it was prepared for subjectID
+func (p *PreparedSubjects) Get(sessionID, subjectID string) (*PreparedSubject, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prepared, ok := p.entries[sessionID]
+	if !ok {
+		return nil, false
+	}
+	if !p.clock.Now().Before(prepared.ExpiresAt) {
+		delete(p.entries, sessionID)
+		return nil, false
+	}
+	if prepared.SubjectID != subjectID {
+		return nil, false
+	}
+	return prepared, true
+}
+
+// store keeps a prepared context, replacing the session's previous one
+func (p *PreparedSubjects) store(prepared *PreparedSubject) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+	prepared.PreparedAt = now
+	prepared.ExpiresAt = now.Add(p.ttl)
+	if _, exists := p.entries[prepared.SessionID]; !exists && p.maxEntries > 0 && len(p.entries) >= p.maxEntries {
+		p.evict(now)
+	}
+	p.entries[prepared.SessionID] = prepared
+}
+
+// evict makes room for one entry; callers must hold the lock
+func (p *PreparedSubjects) evict(now time.Time) {
+	var oldest *PreparedSubject
+	for sessionID, prepared := range p.entries {
+		if !now.Before(prepared.ExpiresAt) {
+			delete(p.entries, sessionID)
+			continue
+		}
+		if oldest == nil || prepared.ExpiresAt.Before(oldest.ExpiresAt) {
+			oldest = prepared
+		}
+	}
+	if len(p.entries) >= p.maxEntries && oldest != nil {
+		delete(p.entries, oldest.SessionID)
+	}
+}
+
+// Release drops the prepared context of a session, e.g. at logout. It reports whether one existed.
+func (p *PreparedSubjects) Release(sessionID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.entries[sessionID]
+	delete(p.entries, sessionID)
+	return ok
+}
+
+// InvalidateSubject drops every prepared context of a subject, e.g. after its attributes change.
+// An empty subjectID drops them all.
+func (p *PreparedSubjects) InvalidateSubject(subjectID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for sessionID, prepared := range p.entries {
+		if subjectID == "" || prepared.SubjectID == subjectID {
+			delete(p.entries, sessionID)
+		}
+	}
+}
+
+// Size returns the number of prepared sessions, expired ones included until they are looked up
+func (p *PreparedSubjects) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// SetPreparedSubjects configures the prepared subject store (nil disables prepared contexts)
+func (r *AttributeResolver) SetPreparedSubjects(prepared *PreparedSubjects) {
+	r.prepared = prepared
+}
+
+// GetPreparedSubjects returns the prepared subject store, or nil when prepared contexts are disabled
+func (r *AttributeResolver) GetPreparedSubjects() *PreparedSubjects {
+	return r.prepared
+}
+
+// PrepareSubject builds and keeps the subject portion of the evaluation context of a session
+func (r *AttributeResolver) PrepareSubject(sessionID string, subject models.SubjectInterface) (*PreparedSubject, error) {
+	if r.prepared == nil {
+		return nil, fmt.Errorf("prepared subject contexts are disabled")
+	}
+	if sessionID == "" || subject == nil || subject.GetID() == "" {
+		return nil, fmt.Errorf("session ID and subject are required")
+	}
+
+	attrs := make(map[string]interface{}, len(subject.GetAttributes()))
+	for key, value := range subject.GetAttributes() {
+		attrs[key] = value
+	}
+	if err := r.resolveSubjectGroups(subject.GetID(), attrs); err != nil {
+		return nil, err
+	}
+
+	prepared := &PreparedSubject{
+		SessionID:   sessionID,
+		SubjectID:   subject.GetID(),
+		SubjectType: string(subject.GetType()),
+		Attributes:  attrs,
+	}
+	r.prepared.store(prepared)
+	return prepared, nil
+}
+
+// preparedSubject returns the prepared context usable for request: same session and subject, and
+// neither AsOf nor a supplied subject snapshot asking for other attributes
+func (r *AttributeResolver) preparedSubject(request *models.EvaluationRequest) (*PreparedSubject, bool) {
+	if r.prepared == nil || request.Session == nil || request.Session.SessionID == "" || request.AsOf != nil {
+		return nil, false
+	}
+	if request.Snapshots != nil && request.Snapshots.Subject != nil {
+		return nil, false
+	}
+	return r.prepared.Get(request.Session.SessionID, request.Subject.GetID())
+}
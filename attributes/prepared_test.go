@@ -0,0 +1,110 @@
+package attributes
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestPreparedSubject_EnrichContext(t *testing.T) {
+	store := &countingStorage{MockStorage: storage.NewMockStorage()}
+	store.CreateResource(&models.Resource{ID: "doc-1", ResourceID: "doc-1"})
+	store.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	store.CreateGroup(&models.Group{ID: "finance"})
+	store.AddGroupMember("finance", models.GroupMemberSubject, "alice")
+
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	resolver := NewAttributeResolver(store)
+	resolver.SetClock(mockClock)
+	prepared := NewPreparedSubjects(&PreparedSubjectConfig{TTL: time.Minute}, mockClock)
+	resolver.SetPreparedSubjects(prepared)
+
+	alice := models.NewMockUserSubject("alice", "alice")
+	if _, err := resolver.PrepareSubject("sess-1", alice); err != nil {
+		t.Fatalf("Failed to prepare subject: %v", err)
+	}
+	lookups := store.groupLookups
+
+	enrich := func(subject models.SubjectInterface, sessionID string) *models.EvaluationContext {
+		t.Helper()
+		context, err := resolver.EnrichContext(&models.EvaluationRequest{
+			RequestID:  "prepared-001",
+			Subject:    subject,
+			ResourceID: "doc-1",
+			Action:     "read",
+			Context:    map[string]interface{}{constants.ContextKeySubjectAttributes: map[string]interface{}{"device": "laptop"}},
+			Session:    &models.SessionInfo{SessionID: sessionID},
+		})
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		return context
+	}
+
+	context := enrich(alice, "sess-1")
+	if source := context.AttributeSources[EntitySubject]; source != models.AttributeSourcePrepared {
+		t.Errorf("Expected the prepared subject, got source %q", source)
+	}
+	if store.groupLookups != lookups {
+		t.Errorf("Expected no group lookups for a prepared session, got %d", store.groupLookups-lookups)
+	}
+	if groups, _ := context.Subject.Attributes[constants.ContextKeyGroups].([]interface{}); len(groups) != 1 {
+		t.Errorf("Expected prepared groups, got %v", context.Subject.Attributes[constants.ContextKeyGroups])
+	}
+	if context.Subject.Attributes["device"] != "laptop" {
+		t.Errorf("Expected request overrides on top of the prepared subject, got %v", context.Subject.Attributes)
+	}
+	if _, ok := prepared.entries["sess-1"].Attributes["device"]; ok {
+		t.Error("Request overrides must not leak into the prepared subject")
+	}
+
+	// Another subject or session enriches as usual
+	if source := enrich(models.NewMockUserSubject("bob", "bob"), "sess-1").AttributeSources[EntitySubject]; source != models.AttributeSourceCurrent {
+		t.Errorf("Expected a subject mismatch to bypass the prepared context, got %q", source)
+	}
+	if source := enrich(alice, "sess-2").AttributeSources[EntitySubject]; source != models.AttributeSourceCurrent {
+		t.Errorf("Expected an unprepared session to enrich as usual, got %q", source)
+	}
+
+	// Invalidation and expiry drop the prepared context
+	prepared.InvalidateSubject("alice")
+	if _, ok := prepared.Get("sess-1", "alice"); ok {
+		t.Error("Expected invalidation to drop the prepared subject")
+	}
+	resolver.PrepareSubject("sess-1", alice)
+	mockClock.Advance(time.Minute)
+	if source := enrich(alice, "sess-1").AttributeSources[EntitySubject]; source != models.AttributeSourceCurrent {
+		t.Errorf("Expected an expired prepared context to be ignored, got %q", source)
+	}
+	if prepared.Size() != 0 {
+		t.Errorf("Expected the expired context to be dropped, got %d", prepared.Size())
+	}
+}
+
+func TestPreparedSubjects_EvictionAndRelease(t *testing.T) {
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	prepared := NewPreparedSubjects(&PreparedSubjectConfig{TTL: time.Minute, MaxEntries: 2}, mockClock)
+
+	prepared.store(&PreparedSubject{SessionID: "sess-1", SubjectID: "alice"})
+	mockClock.Advance(time.Second)
+	prepared.store(&PreparedSubject{SessionID: "sess-2", SubjectID: "bob"})
+	prepared.store(&PreparedSubject{SessionID: "sess-3", SubjectID: "carol"})
+
+	if _, ok := prepared.Get("sess-1", "alice"); ok {
+		t.Error("Expected the session closest to expiry to be evicted")
+	}
+	if prepared.Size() != 2 {
+		t.Errorf("Expected 2 prepared sessions, got %d", prepared.Size())
+	}
+	if !prepared.Release("sess-2") || prepared.Release("sess-2") {
+		t.Error("Expected Release to report whether the session existed")
+	}
+
+	if _, err := NewAttributeResolver(storage.NewMockStorage()).PrepareSubject("sess-1", models.NewMockUserSubject("alice", "alice")); err == nil {
+		t.Error("Expected an error when prepared contexts are disabled")
+	}
+}
@@ -0,0 +1,75 @@
+package attributes
+
+import (
+	"fmt"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// ResolveRelationships derives boolean subject/resource relationships so policies can express
+// common ReBAC-style checks as plain conditions (e.g. "Bool": {"relationship.is_owner": true}):
+//
+//   - is_owner: resource.owner_id equals the subject ID
+//   - same_team: resource.team_id is one of user.team_ids (or equals user.team_id)
+//
+// Missing attributes resolve to false, never to an error.
+func ResolveRelationships(subject *models.Subject, resource *models.Resource) map[string]bool {
+	relationships := map[string]bool{
+		constants.RelationshipKeyIsOwner:  false,
+		constants.RelationshipKeySameTeam: false,
+	}
+	if subject == nil || resource == nil {
+		return relationships
+	}
+
+	if ownerID, ok := relationshipID(resource.Attributes[constants.ContextKeyOwnerID]); ok {
+		relationships[constants.RelationshipKeyIsOwner] = ownerID == subject.ID
+	}
+
+	if teamID, ok := relationshipID(resource.Attributes[constants.ContextKeyTeamID]); ok {
+		for _, subjectTeam := range subjectTeamIDs(subject.Attributes) {
+			if subjectTeam == teamID {
+				relationships[constants.RelationshipKeySameTeam] = true
+				break
+			}
+		}
+	}
+
+	return relationships
+}
+
+// subjectTeamIDs collects user.team_ids (array or single value) and user.team_id
+func subjectTeamIDs(attributes map[string]interface{}) []string {
+	var teams []string
+	switch v := attributes[constants.ContextKeyTeamIDs].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if id, ok := relationshipID(item); ok {
+				teams = append(teams, id)
+			}
+		}
+	case []string:
+		teams = append(teams, v...)
+	default:
+		if id, ok := relationshipID(v); ok {
+			teams = append(teams, id)
+		}
+	}
+	if id, ok := relationshipID(attributes[constants.ContextKeyTeamID]); ok {
+		teams = append(teams, id)
+	}
+	return teams
+}
+
+// relationshipID converts an ID attribute to its string form; empty and non-scalar values are ignored
+func relationshipID(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64, int, int64:
+		return fmt.Sprintf("%v", v), true
+	default:
+		return "", false
+	}
+}
@@ -9,23 +9,55 @@ import (
 	"strings"
 	"time"
 
+	"abac_go_example/clock"
 	"abac_go_example/constants"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/holidays"
 	"abac_go_example/models"
 	"abac_go_example/storage"
 )
 
 // AttributeResolver handles attribute resolution and context enrichment
 type AttributeResolver struct {
-	storage storage.Storage
+	storage        storage.Storage
+	mergeStrategy  MergeStrategy
+	riskProvider   RiskProvider
+	deviceProvider DevicePostureProvider
+	clock          clock.Clock
+	holidays       holidays.Calendar
+	cache          *AttributeCache
+	prepared       *PreparedSubjects
 }
 
 // NewAttributeResolver creates a new attribute resolver
 func NewAttributeResolver(storage storage.Storage) *AttributeResolver {
 	return &AttributeResolver{
-		storage: storage,
+		storage:       storage,
+		mergeStrategy: MergeStoredWins,
+		clock:         clock.NewRealClock(),
 	}
 }
 
+// SetHolidayCalendar configures the holidays excluded from is_business_hours (nil: no holidays)
+func (r *AttributeResolver) SetHolidayCalendar(calendar holidays.Calendar) {
+	r.holidays = calendar
+}
+
+// SetClock configures the clock used for evaluation time (nil restores real time)
+func (r *AttributeResolver) SetClock(c clock.Clock) {
+	r.clock = clock.OrReal(c)
+}
+
+// SetMergeStrategy configures how request-supplied attributes are merged with stored ones
+func (r *AttributeResolver) SetMergeStrategy(strategy MergeStrategy) {
+	r.mergeStrategy = strategy
+}
+
+// GetMergeStrategy returns the configured merge strategy
+func (r *AttributeResolver) GetMergeStrategy() MergeStrategy {
+	return r.mergeStrategy
+}
+
 // validateRequest validates the evaluation request
 func (r *AttributeResolver) validateRequest(request *models.EvaluationRequest) error {
 	if request == nil {
@@ -47,14 +79,70 @@ func (r *AttributeResolver) validateRequest(request *models.EvaluationRequest) e
 	return nil
 }
 
-// EnrichContext enriches the evaluation context with all necessary attributes
+// EnrichContext enriches the evaluation context with all necessary attributes.
+//
+// Attributes come from two sources:
+//  1. Stored attributes (SubjectInterface.GetAttributes, resource attributes from storage)
+//  2. Request overrides in Context["attributes"] (subject) and Context["resource_attributes"] (resource)
+//
+// The merge strategy decides precedence. The default MergeStoredWins treats storage as authoritative
+// and only lets the request fill missing keys; MergeRequestOverrides gives the request precedence.
+//
+// Other flat Context keys (e.g. Context["department"]) are never merged into entity attributes;
+// they stay request attributes and are exposed as "request:<key>". Keys supplied by both sources
+// with different values are reported in EvaluationContext.Conflicts.
+//
+// Point-in-time requests replace the stored base attributes before merging: request.Snapshots wins,
+// otherwise request.AsOf selects the latest AttributeSnapshot at or before that time (when the storage
+// implements storage.AttributeHistoryStore). AsOf is also the evaluation time for time-based attributes.
+// EvaluationContext.AttributeSources reports which source was used for the subject and the resource.
+// Active elevations then add their roles and attributes to the subject (EvaluationContext.Elevations).
 func (r *AttributeResolver) EnrichContext(request *models.EvaluationRequest) (*models.EvaluationContext, error) {
 	if err := r.validateRequest(request); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Get Subject attributes directly from SubjectInterface
-	subjectAttrs := request.Subject.GetAttributes()
+	// Point-in-time evaluation uses AsOf for the clock and for attribute history lookups
+	at := r.clock.Now()
+	if request.AsOf != nil {
+		at = *request.AsOf
+	}
+	var suppliedSubject, suppliedResource map[string]interface{}
+	if request.Snapshots != nil {
+		suppliedSubject, suppliedResource = request.Snapshots.Subject, request.Snapshots.Resource
+	}
+
+	// A session's prepared context already holds its stored attributes and group membership
+	prepared, usePrepared := r.preparedSubject(request)
+	var baseSubjectAttrs map[string]interface{}
+	var subjectSource string
+	if usePrepared {
+		baseSubjectAttrs, subjectSource = prepared.Attributes, models.AttributeSourcePrepared
+	} else {
+		var err error
+		baseSubjectAttrs, subjectSource, err = r.pointInTimeAttributes(models.SnapshotEntitySubject,
+			request.Subject.GetID(), request.Subject.GetAttributes(), suppliedSubject, request.AsOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge stored subject attributes with request-supplied overrides
+	subjectAttrs, conflicts := mergeAttributes(EntitySubject, baseSubjectAttrs,
+		requestOverrides(request.Context, constants.ContextKeySubjectAttributes), r.mergeStrategy)
+
+	// Expand group membership (including nested groups) into user.groups
+	if !usePrepared {
+		if err := r.resolveSubjectGroups(request.Subject.GetID(), subjectAttrs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Grant the roles and attributes of active elevations ("sudo mode")
+	elevations, err := r.resolveSubjectElevations(request.Subject.GetID(), subjectAttrs, at)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a legacy Subject for backward compatibility with existing code
 	subject := &models.Subject{
@@ -64,7 +152,7 @@ func (r *AttributeResolver) EnrichContext(request *models.EvaluationRequest) (*m
 	}
 
 	// Get resource
-	resource, err := r.storage.GetResource(request.ResourceID)
+	resource, err := r.getResource(request.ResourceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve resource '%s': %w", request.ResourceID, err)
 	}
@@ -73,7 +161,7 @@ func (r *AttributeResolver) EnrichContext(request *models.EvaluationRequest) (*m
 	}
 
 	// Get action
-	action, err := r.storage.GetAction(request.Action)
+	action, err := r.getAction(request.Action)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve action '%s': %w", request.Action, err)
 	}
@@ -81,21 +169,77 @@ func (r *AttributeResolver) EnrichContext(request *models.EvaluationRequest) (*m
 		return nil, fmt.Errorf("action '%s' not found", request.Action)
 	}
 
+	baseResourceAttrs, resourceSource, err := r.pointInTimeAttributes(models.SnapshotEntityResource,
+		resource.ID, resource.Attributes, suppliedResource, request.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge stored resource attributes on a copy so the stored entity is never mutated
+	resourceOverrides := requestOverrides(request.Context, constants.ContextKeyResourceAttributes)
+	if len(resourceOverrides) > 0 || resourceSource != models.AttributeSourceCurrent {
+		resourceAttrs, resourceConflicts := mergeAttributes(EntityResource, baseResourceAttrs, resourceOverrides, r.mergeStrategy)
+		resourceCopy := *resource
+		resourceCopy.Attributes = resourceAttrs
+		resource = &resourceCopy
+		conflicts = append(conflicts, resourceConflicts...)
+	}
+
 	// Enrich environment context
-	environment := r.enrichEnvironmentContext(request.Context)
+	environment := r.enrichEnvironmentContext(request.Context, at)
+
+	// Score request risk (adaptive authorization)
+	r.applyRiskAssessment(request, environment)
+
+	// Look up the posture of the calling device (zero-trust device policies)
+	device := r.resolveDevicePosture(request, subject, at)
 
 	// Resolve dynamic attributes
-	r.resolveDynamicAttributes(subject, environment)
+	r.resolveDynamicAttributes(subject, environment, at)
+
+	// Derive ownership/team relationships from the merged attributes
+	relationships := ResolveRelationships(subject, resource)
 
 	return &models.EvaluationContext{
 		Subject:     subject,
 		Resource:    resource,
 		Action:      action,
 		Environment: environment,
-		Timestamp:   time.Now(),
+		Timestamp:   at,
+		Conflicts:   conflicts,
+		AttributeSources: map[string]string{
+			EntitySubject:  subjectSource,
+			EntityResource: resourceSource,
+		},
+		Relationships: relationships,
+		Device:        device,
+		Elevations:    elevations,
 	}, nil
 }
 
+// pointInTimeAttributes picks the base attributes of an entity: request-supplied snapshots first,
+// then the stored snapshot valid at asOf (when the storage keeps history), then current attributes.
+func (r *AttributeResolver) pointInTimeAttributes(entityType, entityID string, current, supplied map[string]interface{}, asOf *time.Time) (map[string]interface{}, string, error) {
+	if supplied != nil {
+		return supplied, models.AttributeSourceRequest, nil
+	}
+
+	if asOf != nil {
+		if history, ok := r.storage.(storage.AttributeHistoryStore); ok {
+			snapshot, err := history.GetAttributeSnapshot(entityType, entityID, *asOf)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to retrieve %s '%s' attributes as of %s: %w",
+					entityType, entityID, asOf.Format(time.RFC3339), err)
+			}
+			if snapshot != nil {
+				return snapshot.Attributes, models.AttributeSourceHistory, nil
+			}
+		}
+	}
+
+	return current, models.AttributeSourceCurrent, nil
+}
+
 // EnrichContextWithTimeout enriches context with timeout support
 func (r *AttributeResolver) EnrichContextWithTimeout(ctx context.Context, request *models.EvaluationRequest) (*models.EvaluationContext, error) {
 	// Check context cancellation
@@ -109,7 +253,7 @@ func (r *AttributeResolver) EnrichContextWithTimeout(ctx context.Context, reques
 }
 
 // enrichEnvironmentContext adds computed environment attributes
-func (r *AttributeResolver) enrichEnvironmentContext(context map[string]interface{}) map[string]interface{} {
+func (r *AttributeResolver) enrichEnvironmentContext(context map[string]interface{}, at time.Time) map[string]interface{} {
 	enriched := make(map[string]interface{})
 
 	// Copy existing context
@@ -119,7 +263,7 @@ func (r *AttributeResolver) enrichEnvironmentContext(context map[string]interfac
 
 	// Add current timestamp if not present
 	if _, exists := enriched[constants.ContextKeyTimestamp]; !exists {
-		enriched[constants.ContextKeyTimestamp] = time.Now().Format(time.RFC3339)
+		enriched[constants.ContextKeyTimestamp] = at.Format(time.RFC3339)
 	}
 
 	// Extract time_of_day from timestamp
@@ -129,6 +273,7 @@ func (r *AttributeResolver) enrichEnvironmentContext(context map[string]interfac
 			enriched[constants.ContextKeyDayOfWeekShort] = strings.ToLower(t.Weekday().String())
 			enriched[constants.ContextKeyHour] = t.Hour()
 			enriched[constants.ContextKeyIsBusinessHours] = r.isBusinessHours(t)
+			enriched[constants.ContextKeyIsHoliday] = holidays.IsHoliday(r.holidays, t)
 		}
 	}
 
@@ -148,7 +293,7 @@ func (r *AttributeResolver) enrichEnvironmentContext(context map[string]interfac
 }
 
 // resolveDynamicAttributes computes dynamic subject attributes
-func (r *AttributeResolver) resolveDynamicAttributes(subject *models.Subject, environment map[string]interface{}) {
+func (r *AttributeResolver) resolveDynamicAttributes(subject *models.Subject, environment map[string]interface{}, at time.Time) {
 	if subject.Attributes == nil {
 		subject.Attributes = make(map[string]interface{})
 	}
@@ -156,15 +301,14 @@ func (r *AttributeResolver) resolveDynamicAttributes(subject *models.Subject, en
 	// Calculate years_of_service if hire_date is available
 	if hireDateStr, ok := subject.Attributes[constants.ContextKeyHireDate].(string); ok {
 		if hireDate, err := time.Parse("2006-01-02", hireDateStr); err == nil {
-			years := time.Since(hireDate).Hours() / (24 * 365.25)
+			years := at.Sub(hireDate).Hours() / (24 * 365.25)
 			subject.Attributes[constants.ContextKeyYearsOfService] = int(years)
 		}
 	}
 
-	// Add computed attributes based on current time
-	now := time.Now()
-	subject.Attributes[constants.ContextKeyCurrentHour] = now.Hour()
-	subject.Attributes[constants.ContextKeyCurrentDay] = strings.ToLower(now.Weekday().String())
+	// Add computed attributes based on evaluation time
+	subject.Attributes[constants.ContextKeyCurrentHour] = at.Hour()
+	subject.Attributes[constants.ContextKeyCurrentDay] = strings.ToLower(at.Weekday().String())
 }
 
 // GetAttributeValue retrieves a nested attribute value using dot notation
@@ -271,11 +415,12 @@ func (r *AttributeResolver) isBusinessHours(t time.Time) bool {
 	hour := t.Hour()
 	weekday := t.Weekday()
 
-	// Use constants from business rules
+	// Use constants from business rules; holidays are treated like weekends
 	return weekday >= constants.BusinessDayStart &&
 		weekday <= constants.BusinessDayEnd &&
 		hour >= constants.BusinessHoursStart &&
-		hour < constants.BusinessHoursEnd
+		hour < constants.BusinessHoursEnd &&
+		!holidays.IsHoliday(r.holidays, t)
 }
 
 func (r *AttributeResolver) isInternalIP(ip string) bool {
@@ -335,33 +480,7 @@ func (r *AttributeResolver) MatchResourcePattern(pattern, resource string) bool
 	return false
 }
 
+// simpleWildcardMatch matches '*' wildcards anywhere in the pattern
 func (r *AttributeResolver) simpleWildcardMatch(pattern, str string) bool {
-	// Simple wildcard matching implementation
-	if pattern == "*" {
-		return true
-	}
-
-	if !strings.Contains(pattern, "*") {
-		return pattern == str
-	}
-
-	// Split by * and check each part
-	parts := strings.Split(pattern, "*")
-	if len(parts) == 0 {
-		return true
-	}
-
-	// Check if string starts with first part
-	if parts[0] != "" && !strings.HasPrefix(str, parts[0]) {
-		return false
-	}
-
-	// Check if string ends with last part
-	if parts[len(parts)-1] != "" && !strings.HasSuffix(str, parts[len(parts)-1]) {
-		return false
-	}
-
-	// For more complex patterns, we'd need proper regex
-	// This is a simplified implementation
-	return true
+	return matchers.WildcardMatch(pattern, str)
 }
@@ -2,9 +2,12 @@ package attributes
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
+	"abac_go_example/clock"
 	"abac_go_example/constants"
 	"abac_go_example/models"
 	"abac_go_example/storage"
@@ -54,6 +57,18 @@ func (m *mockStorage) GetAllActions() ([]*models.Action, error) {
 	return []*models.Action{}, nil
 }
 
+func (m *mockStorage) FindSubjectsByAttribute(key string, value interface{}) ([]*models.Subject, error) {
+	return []*models.Subject{}, nil
+}
+
+func (m *mockStorage) FindResourcesByAttribute(key string, value interface{}) ([]*models.Resource, error) {
+	return []*models.Resource{}, nil
+}
+
+func (m *mockStorage) FindResourcesByTag(tag string) ([]*models.Resource, error) {
+	return []*models.Resource{}, nil
+}
+
 // Additional methods to implement Storage interface
 func (m *mockStorage) CreateSubject(subject *models.Subject) error    { return nil }
 func (m *mockStorage) CreateResource(resource *models.Resource) error { return nil }
@@ -324,7 +339,7 @@ func TestEnvironmentEnrichment(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		enriched := resolver.enrichEnvironmentContext(tc.input)
+		enriched := resolver.enrichEnvironmentContext(tc.input, time.Now())
 
 		for key, expectedValue := range tc.expected {
 			if actualValue, exists := enriched[key]; !exists {
@@ -350,7 +365,7 @@ func TestDynamicAttributeResolution(t *testing.T) {
 		constants.ContextKeyTimestamp: time.Now().Format(time.RFC3339),
 	}
 
-	resolver.resolveDynamicAttributes(subject, environment)
+	resolver.resolveDynamicAttributes(subject, environment, time.Now())
 
 	// Check that years_of_service was calculated
 	if yearsOfService, exists := subject.Attributes[constants.ContextKeyYearsOfService]; !exists {
@@ -677,3 +692,261 @@ func TestEnrichContextErrorHandling(t *testing.T) {
 		t.Error("Expected error for non-existent action")
 	}
 }
+
+func TestEnrichContextAttributeMergeStrategies(t *testing.T) {
+	mockStore := storage.NewMockStorage()
+	mockStore.CreateResource(&models.Resource{
+		ID:           "res-001",
+		ResourceType: "document",
+		Attributes: map[string]interface{}{
+			"classification": "confidential",
+		},
+	})
+	mockStore.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+
+	newRequest := func() *models.EvaluationRequest {
+		return &models.EvaluationRequest{
+			Subject:    models.CreateMockSubjectWithAttributes("sub-001", map[string]interface{}{"department": "Engineering"}),
+			ResourceID: "res-001",
+			Action:     "read",
+			Context: map[string]interface{}{
+				"department": "Ignored",
+				constants.ContextKeySubjectAttributes: map[string]interface{}{
+					"department": "Finance",
+					"project":    "alpha",
+				},
+				constants.ContextKeyResourceAttributes: map[string]interface{}{
+					"classification": "public",
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		strategy           MergeStrategy
+		expectedDepartment string
+		expectedProject    interface{}
+		expectedClass      string
+		expectedConflicts  int
+		expectedResolution string
+	}{
+		{MergeStoredWins, "Engineering", "alpha", "confidential", 2, ResolutionStored},
+		{MergeRequestOverrides, "Finance", "alpha", "public", 2, ResolutionRequest},
+		{MergeStoredOnly, "Engineering", nil, "confidential", 0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			resolver := NewAttributeResolver(mockStore)
+			resolver.SetMergeStrategy(tc.strategy)
+
+			context, err := resolver.EnrichContext(newRequest())
+			if err != nil {
+				t.Fatalf("Failed to enrich context: %v", err)
+			}
+
+			if got := context.Subject.Attributes["department"]; got != tc.expectedDepartment {
+				t.Errorf("Expected department %v, got %v", tc.expectedDepartment, got)
+			}
+			if got := context.Subject.Attributes["project"]; got != tc.expectedProject {
+				t.Errorf("Expected project %v, got %v", tc.expectedProject, got)
+			}
+			if got := context.Resource.Attributes["classification"]; got != tc.expectedClass {
+				t.Errorf("Expected classification %v, got %v", tc.expectedClass, got)
+			}
+			if len(context.Conflicts) != tc.expectedConflicts {
+				t.Fatalf("Expected %d conflicts, got %d: %+v", tc.expectedConflicts, len(context.Conflicts), context.Conflicts)
+			}
+			for _, conflict := range context.Conflicts {
+				if conflict.Resolution != tc.expectedResolution {
+					t.Errorf("Expected resolution %s, got %s", tc.expectedResolution, conflict.Resolution)
+				}
+			}
+		})
+	}
+
+	// Stored resource must never be mutated by request overrides
+	stored, _ := mockStore.GetResource("res-001")
+	if stored.Attributes["classification"] != "confidential" {
+		t.Errorf("Stored resource was mutated: %v", stored.Attributes["classification"])
+	}
+}
+
+func TestRiskProvider(t *testing.T) {
+	mockStore := storage.NewMockStorage()
+	mockStore.CreateResource(&models.Resource{ID: "res-001", ResourceID: "res-001"})
+	mockStore.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+
+	request := &models.EvaluationRequest{
+		RequestID:  "risk-001",
+		Subject:    models.NewMockUserSubject("sub-001", "testuser"),
+		ResourceID: "res-001",
+		Action:     "read",
+		Context: map[string]interface{}{
+			constants.ContextKeyRiskScore: 0, // Request-supplied scores must not win
+		},
+	}
+
+	t.Run("assessment", func(t *testing.T) {
+		resolver := NewAttributeResolver(mockStore)
+		resolver.SetRiskProvider(RiskProviderFunc(func(req *models.EvaluationRequest, env map[string]interface{}) (*RiskAssessment, error) {
+			return &RiskAssessment{
+				Score:      72.5,
+				NewDevice:  true,
+				Factors:    []string{"new_device"},
+				Attributes: map[string]interface{}{"device_id": "dev-42"},
+			}, nil
+		}))
+
+		context, err := resolver.EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		if context.Environment[constants.ContextKeyRiskScore] != 72.5 {
+			t.Errorf("Expected risk_score 72.5, got %v", context.Environment[constants.ContextKeyRiskScore])
+		}
+		if context.Environment[constants.ContextKeyNewDevice] != true || context.Environment[constants.ContextKeyImpossibleTravel] != false {
+			t.Errorf("Unexpected device/travel flags: %v", context.Environment)
+		}
+		if context.Environment["device_id"] != "dev-42" {
+			t.Errorf("Expected provider attributes to be added, got %v", context.Environment["device_id"])
+		}
+	})
+
+	t.Run("provider failure fails closed", func(t *testing.T) {
+		resolver := NewAttributeResolver(mockStore)
+		resolver.SetRiskProvider(RiskProviderFunc(func(req *models.EvaluationRequest, env map[string]interface{}) (*RiskAssessment, error) {
+			return nil, fmt.Errorf("risk service timeout")
+		}))
+
+		context, err := resolver.EnrichContext(request)
+		if err != nil {
+			t.Fatalf("Failed to enrich context: %v", err)
+		}
+		if context.Environment[constants.ContextKeyRiskScore] != MaxRiskScore {
+			t.Errorf("Expected max risk score, got %v", context.Environment[constants.ContextKeyRiskScore])
+		}
+		if context.Environment[constants.ContextKeyRiskUnavailable] != true {
+			t.Error("Expected risk_unavailable to be set")
+		}
+	})
+}
+
+func TestAttributeResolverClock(t *testing.T) {
+	mockStore := storage.NewMockStorage()
+	mockStore.CreateResource(&models.Resource{ID: "res-001", ResourceID: "res-001"})
+	mockStore.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+
+	now := time.Date(2024, time.June, 1, 14, 30, 0, 0, time.UTC) // Saturday
+	resolver := NewAttributeResolver(mockStore)
+	resolver.SetClock(clock.NewMockClock(now))
+
+	context, err := resolver.EnrichContext(&models.EvaluationRequest{
+		RequestID:  "clock-001",
+		Subject:    models.NewMockUserSubject("sub-001", "testuser"),
+		ResourceID: "res-001",
+		Action:     "read",
+		Context:    map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to enrich context: %v", err)
+	}
+
+	if !context.Timestamp.Equal(now) {
+		t.Errorf("Expected timestamp %v, got %v", now, context.Timestamp)
+	}
+	if context.Environment[constants.ContextKeyTimeOfDayShort] != "14:30" {
+		t.Errorf("Expected time_of_day 14:30, got %v", context.Environment[constants.ContextKeyTimeOfDayShort])
+	}
+	if context.Environment[constants.ContextKeyDayOfWeekShort] != "saturday" {
+		t.Errorf("Expected day_of_week saturday, got %v", context.Environment[constants.ContextKeyDayOfWeekShort])
+	}
+	if context.Subject.Attributes[constants.ContextKeyCurrentHour] != 14 {
+		t.Errorf("Expected current_hour 14, got %v", context.Subject.Attributes[constants.ContextKeyCurrentHour])
+	}
+}
+
+func TestResolveRelationships(t *testing.T) {
+	subject := &models.Subject{
+		ID:         "user-1",
+		Attributes: models.JSONMap{"team_ids": []interface{}{"team-a", "team-b"}},
+	}
+
+	tests := []struct {
+		name     string
+		subject  *models.Subject
+		resource models.JSONMap
+		owner    bool
+		sameTeam bool
+	}{
+		{"owner and team", subject, models.JSONMap{"owner_id": "user-1", "team_id": "team-b"}, true, true},
+		{"other owner", subject, models.JSONMap{"owner_id": "user-2", "team_id": "team-c"}, false, false},
+		{"no relationship attributes", subject, models.JSONMap{}, false, false},
+		{"single team_id", &models.Subject{ID: "user-3", Attributes: models.JSONMap{"team_id": float64(7)}},
+			models.JSONMap{"owner_id": "user-1", "team_id": float64(7)}, false, true},
+		{"empty owner", &models.Subject{ID: ""}, models.JSONMap{"owner_id": ""}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relationships := ResolveRelationships(tt.subject, &models.Resource{Attributes: tt.resource})
+			if relationships[constants.RelationshipKeyIsOwner] != tt.owner {
+				t.Errorf("Expected is_owner %v, got %v", tt.owner, relationships[constants.RelationshipKeyIsOwner])
+			}
+			if relationships[constants.RelationshipKeySameTeam] != tt.sameTeam {
+				t.Errorf("Expected same_team %v, got %v", tt.sameTeam, relationships[constants.RelationshipKeySameTeam])
+			}
+		})
+	}
+}
+
+func TestEnrichContextElevations(t *testing.T) {
+	mockStore := storage.NewMockStorage()
+	mockStore.CreateResource(&models.Resource{ID: "res-001", ResourceID: "res-001"})
+	mockStore.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	expiresAt := time.Now().Add(time.Hour)
+	for _, elevation := range []*models.Elevation{
+		{ID: "elev-1", SubjectID: "sub-001", Roles: models.JSONStringSlice{"incident-responder", "employee"},
+			Attributes: models.JSONMap{"access_level": 5}, Justification: "INC-42", ExpiresAt: expiresAt},
+		{ID: "elev-2", SubjectID: "sub-002", Roles: models.JSONStringSlice{"dba"}, Justification: "Migration", ExpiresAt: expiresAt},
+	} {
+		if err := mockStore.CreateElevation(elevation); err != nil {
+			t.Fatalf("CreateElevation failed: %v", err)
+		}
+	}
+	resolver := NewAttributeResolver(mockStore)
+	request := &models.EvaluationRequest{
+		RequestID: "elevation-001",
+		Subject: models.NewUserSubject(&models.User{ID: "sub-001", Username: "oncall", Status: "active"},
+			&models.UserProfile{UserID: "sub-001", AccessLevel: 2}, []models.Role{{RoleCode: "employee"}}),
+		ResourceID: "res-001",
+		Action:     "read",
+		Context:    map[string]interface{}{},
+	}
+
+	context, err := resolver.EnrichContext(request)
+	if err != nil {
+		t.Fatalf("Failed to enrich context: %v", err)
+	}
+	roles := context.Subject.Attributes[constants.ContextKeyRoles]
+	if !reflect.DeepEqual(roles, []interface{}{"employee", "incident-responder"}) {
+		t.Errorf("Expected the elevated role appended once, got %v", roles)
+	}
+	if context.Subject.Attributes["access_level"] != 5 {
+		t.Errorf("Expected the elevated access_level, got %v", context.Subject.Attributes["access_level"])
+	}
+	if !reflect.DeepEqual(context.Elevations, []string{"elev-1"}) {
+		t.Errorf("Expected elev-1 to be applied, got %v", context.Elevations)
+	}
+
+	// Point-in-time requests see only the elevations active at AsOf
+	before := time.Now().Add(-time.Hour)
+	request.AsOf = &before
+	context, err = resolver.EnrichContext(request)
+	if err != nil {
+		t.Fatalf("Failed to enrich context: %v", err)
+	}
+	if context.Subject.Attributes["access_level"] != 2 || len(context.Elevations) != 0 {
+		t.Errorf("Expected no elevation before it was created, got %v and %v", context.Subject.Attributes, context.Elevations)
+	}
+}
@@ -0,0 +1,77 @@
+package attributes
+
+import (
+	"log"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// MaxRiskScore is the highest risk score; it is also assigned when risk cannot be assessed
+const MaxRiskScore = 100.0
+
+// RiskAssessment is the result of scoring a request
+type RiskAssessment struct {
+	Score            float64                // 0 (no risk) to MaxRiskScore
+	ImpossibleTravel bool                   // Location change faster than physically possible
+	NewDevice        bool                   // Device not seen before for this subject
+	Factors          []string               // Human-readable reasons behind the score
+	Attributes       map[string]interface{} // Provider-specific attributes, added as environment attributes
+}
+
+// RiskProvider scores requests during context enrichment so policies can adapt to risk
+// (e.g. require step-up authentication when environment.risk_score is high).
+type RiskProvider interface {
+	AssessRisk(request *models.EvaluationRequest, environment map[string]interface{}) (*RiskAssessment, error)
+}
+
+// RiskProviderFunc adapts a function to the RiskProvider interface
+type RiskProviderFunc func(request *models.EvaluationRequest, environment map[string]interface{}) (*RiskAssessment, error)
+
+// AssessRisk calls f
+func (f RiskProviderFunc) AssessRisk(request *models.EvaluationRequest, environment map[string]interface{}) (*RiskAssessment, error) {
+	return f(request, environment)
+}
+
+// SetRiskProvider configures the provider invoked during enrichment (nil disables risk scoring)
+func (r *AttributeResolver) SetRiskProvider(provider RiskProvider) {
+	r.riskProvider = provider
+}
+
+// applyRiskAssessment adds risk attributes to the environment.
+// Provider values override request-supplied ones; provider failures fail closed with MaxRiskScore.
+func (r *AttributeResolver) applyRiskAssessment(request *models.EvaluationRequest, environment map[string]interface{}) {
+	if r.riskProvider == nil {
+		return
+	}
+
+	assessment, err := r.riskProvider.AssessRisk(request, environment)
+	if err != nil || assessment == nil {
+		log.Printf("Warning: risk assessment unavailable: %v", err)
+		environment[constants.ContextKeyRiskScore] = MaxRiskScore
+		environment[constants.ContextKeyRiskUnavailable] = true
+		return
+	}
+
+	for key, value := range assessment.Attributes {
+		environment[key] = value
+	}
+
+	score := assessment.Score
+	if score < 0 {
+		score = 0
+	} else if score > MaxRiskScore {
+		score = MaxRiskScore
+	}
+
+	factors := assessment.Factors
+	if factors == nil {
+		factors = []string{}
+	}
+
+	environment[constants.ContextKeyRiskScore] = score
+	environment[constants.ContextKeyImpossibleTravel] = assessment.ImpossibleTravel
+	environment[constants.ContextKeyNewDevice] = assessment.NewDevice
+	environment[constants.ContextKeyRiskFactors] = factors
+	environment[constants.ContextKeyRiskUnavailable] = false
+}
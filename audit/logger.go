@@ -7,17 +7,30 @@ import (
 	"os"
 	"time"
 
+	"abac_go_example/constants"
 	"abac_go_example/models"
+	"abac_go_example/redaction"
 )
 
 // AuditLogger handles audit logging for policy evaluations
 type AuditLogger struct {
-	logFile *os.File
-	logger  *log.Logger
+	logFile  *os.File
+	logger   *log.Logger
+	config   *AuditConfig
+	redactor *redaction.Redactor
 }
 
 // NewAuditLogger creates a new audit logger
 func NewAuditLogger(logFilePath string) (*AuditLogger, error) {
+	return NewAuditLoggerWithConfig(logFilePath, nil)
+}
+
+// NewAuditLoggerWithConfig creates a new audit logger with the given configuration
+func NewAuditLoggerWithConfig(logFilePath string, config *AuditConfig) (*AuditLogger, error) {
+	if config == nil {
+		config = DefaultAuditConfig()
+	}
+
 	var logFile *os.File
 	var err error
 
@@ -33,16 +46,34 @@ func NewAuditLogger(logFilePath string) (*AuditLogger, error) {
 	logger := log.New(logFile, "", 0) // No default timestamp, we'll add our own
 
 	return &AuditLogger{
-		logFile: logFile,
-		logger:  logger,
+		logFile:  logFile,
+		logger:   logger,
+		config:   config,
+		redactor: config.Redactor(),
 	}, nil
 }
 
-// LogEvaluation logs a policy evaluation result
+// LogEvaluation logs a policy evaluation result. The audit obligation of the decision sets the
+// verbosity: "minimal" records only the decision and matched policies, "full" adds the evaluation
+// context snapshot even when SnapshotContext is off (allowlist and redaction still apply).
 func (a *AuditLogger) LogEvaluation(request *models.EvaluationRequest, decision *models.Decision, context *models.EvaluationContext) error {
+	level := AuditLevel(decision.Obligations)
+	if level == constants.AuditLevelMinimal {
+		return a.logEntry(evaluationEntry(request, decision, map[string]interface{}{
+			"matched_policies": decision.MatchedPolicies,
+			"audit_level":      level,
+		}))
+	}
+
 	auditContext := map[string]interface{}{
 		"matched_policies": decision.MatchedPolicies,
-		"reason":           decision.Reason,
+		"reason":           a.redactor.RedactText(decision.Reason, contextAttributes(context)),
+	}
+	if level != constants.AuditLevelStandard {
+		auditContext["audit_level"] = level
+	}
+	if rule, ok := decision.ReasonDetails[constants.ReasonDetailDefaultRule]; ok {
+		auditContext["default_rule"] = rule // Permitted by a default decision rule, not by a policy
 	}
 
 	// Safely add environment context
@@ -73,13 +104,64 @@ func (a *AuditLogger) LogEvaluation(request *models.EvaluationRequest, decision
 		auditContext["action_category"] = context.Action.ActionCategory
 	}
 
+	// Persist the enriched context so investigators can see which values drove the decision
+	if a.config.SnapshotContext || level == constants.AuditLevelFull {
+		auditContext["evaluation_context"] = a.config.filterSnapshot(BuildContextSnapshot(context))
+	}
+
+	return a.logEntry(evaluationEntry(request, decision, auditContext))
+}
+
+// DecisionAuditLog builds the audit_logs entry of a PEP decision, marked with models.AuditSourceEvaluation
+// so decision history excludes administrative entries. Like LogEvaluation, a "minimal" audit obligation
+// records only the decision and matched policies; otherwise the reason, with attribute values redacted by
+// redactor, and the client address and user agent used by impact analysis replays are added.
+func DecisionAuditLog(request *models.EvaluationRequest, decision *models.Decision, redactor *redaction.Redactor) *models.AuditLog {
+	level := AuditLevel(decision.Obligations)
+	auditContext := map[string]interface{}{
+		"matched_policies": decision.MatchedPolicies,
+	}
+	if level != constants.AuditLevelStandard {
+		auditContext["audit_level"] = level
+	}
+	if level != constants.AuditLevelMinimal {
+		auditContext["reason"] = redactor.RedactText(decision.Reason, request.Context)
+		if rule, ok := decision.ReasonDetails[constants.ReasonDetailDefaultRule]; ok {
+			auditContext["default_rule"] = rule
+		}
+		if request.Environment != nil && request.Environment.ClientIP != "" {
+			auditContext["source_ip"] = request.Environment.ClientIP
+		}
+		if request.Environment != nil && request.Environment.UserAgent != "" {
+			auditContext["user_agent"] = request.Environment.UserAgent
+		}
+	}
+
+	entry := evaluationEntry(request, decision, auditContext)
+	entry.Source = models.AuditSourceEvaluation
+	return &entry
+}
+
+// AuditLevel returns the audit verbosity requested by the audit obligation of a decision;
+// decisions without one, or with an unknown level, are audited at AuditLevelStandard
+func AuditLevel(obligations map[string]string) string {
+	switch level := obligations[constants.ObligationAudit]; level {
+	case constants.AuditLevelMinimal, constants.AuditLevelFull:
+		return level
+	default:
+		return constants.AuditLevelStandard
+	}
+}
+
+// evaluationEntry builds the audit entry of an evaluation with the given context
+func evaluationEntry(request *models.EvaluationRequest, decision *models.Decision, auditContext map[string]interface{}) models.AuditLog {
 	// Get subject ID from Subject interface
 	subjectID := ""
 	if request.Subject != nil {
 		subjectID = request.Subject.GetID()
 	}
 
-	auditEntry := models.AuditLog{
+	return models.AuditLog{
 		RequestID:    request.RequestID,
 		SubjectID:    subjectID,
 		ResourceID:   request.ResourceID,
@@ -89,8 +171,6 @@ func (a *AuditLogger) LogEvaluation(request *models.EvaluationRequest, decision
 		CreatedAt:    time.Now(),
 		Context:      auditContext,
 	}
-
-	return a.logEntry(auditEntry)
 }
 
 // LogAccessAttempt logs an access attempt with additional context
@@ -112,14 +192,17 @@ func (a *AuditLogger) LogAccessAttempt(request *models.EvaluationRequest, decisi
 		Context:      make(map[string]interface{}),
 	}
 
-	// Copy request context
-	for k, v := range request.Context {
+	// Copy request context with sensitive attributes masked
+	for k, v := range a.redactor.RedactMap(request.Context) {
 		auditEntry.Context[k] = v
 	}
 
 	// Add decision context
 	auditEntry.Context["matched_policies"] = decision.MatchedPolicies
-	auditEntry.Context["reason"] = decision.Reason
+	auditEntry.Context["reason"] = a.redactor.RedactText(decision.Reason, request.Context)
+	if rule, ok := decision.ReasonDetails[constants.ReasonDetailDefaultRule]; ok {
+		auditEntry.Context["default_rule"] = rule
+	}
 
 	// Add additional context
 	for k, v := range additionalContext {
@@ -185,6 +268,11 @@ func (a *AuditLogger) GetAuditTrail(subjectID, resourceID string, limit int) ([]
 	return []*models.AuditLog{}, nil
 }
 
+// GetConfig returns current audit configuration
+func (a *AuditLogger) GetConfig() *AuditConfig {
+	return a.config
+}
+
 // Close closes the audit logger and any open files
 func (a *AuditLogger) Close() error {
 	if a.logFile != nil && a.logFile != os.Stdout {
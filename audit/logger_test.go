@@ -2,12 +2,15 @@ package audit
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"abac_go_example/constants"
 	"abac_go_example/models"
 )
 
@@ -155,6 +158,126 @@ func TestLogEvaluation(t *testing.T) {
 	} else if reason != decision.Reason {
 		t.Errorf("Expected reason %s, got %s", decision.Reason, reason)
 	}
+	if _, exists := logEntry.Context["default_rule"]; exists {
+		t.Error("Policy permits should not record a default rule")
+	}
+}
+
+func TestLogEvaluationDefaultRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := NewAuditLogger(path)
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+	defer logger.Close()
+
+	request := &models.EvaluationRequest{RequestID: "default-001", Subject: models.NewMockUserSubject("sub-001", "sub-001"), ResourceID: "res-001", Action: "read"}
+	decision := &models.Decision{
+		Result:          constants.ResultPermit,
+		MatchedPolicies: []string{},
+		Reason:          fmt.Sprintf(constants.ReasonDefaultPermit, "public-read"),
+		ReasonCode:      constants.ReasonCodeDefaultPermit,
+		ReasonDetails:   map[string]string{constants.ReasonDetailDefaultRule: "public-read"},
+	}
+	if err := logger.LogEvaluation(request, decision, &models.EvaluationContext{Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to log evaluation: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	var logEntry models.AuditLog
+	if err := json.Unmarshal(content, &logEntry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if logEntry.Context["default_rule"] != "public-read" {
+		t.Errorf("Expected default_rule public-read, got %v", logEntry.Context["default_rule"])
+	}
+	if logEntry.Context["reason"] != decision.Reason {
+		t.Errorf("Expected reason %q, got %v", decision.Reason, logEntry.Context["reason"])
+	}
+}
+
+func TestLogEvaluationContextSnapshot(t *testing.T) {
+	tempFile, err := ioutil.TempFile("", "audit_test_*.log")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	config := DefaultAuditConfig()
+	config.SnapshotContext = true
+	config.SnapshotAllowedKeys = []string{"subject", "environment.source_ip"}
+
+	logger, err := NewAuditLoggerWithConfig(tempFile.Name(), config)
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+	defer logger.Close()
+
+	request := &models.EvaluationRequest{
+		RequestID:  "snapshot-001",
+		Subject:    models.NewMockUserSubject("sub-001", "sub-001"),
+		ResourceID: "res-001",
+		Action:     "read",
+	}
+	decision := &models.Decision{Result: "permit", MatchedPolicies: []string{"pol-001"}}
+	context := &models.EvaluationContext{
+		Subject: &models.Subject{
+			ID:          "sub-001",
+			SubjectType: "user",
+			Attributes: map[string]interface{}{
+				"department": "Engineering",
+				"salary":     120000,
+			},
+		},
+		Resource: &models.Resource{ID: "res-001", ResourceType: "document"},
+		Environment: map[string]interface{}{
+			"source_ip":   "10.0.1.100",
+			"time_of_day": "14:00",
+		},
+	}
+
+	if err := logger.LogEvaluation(request, decision, context); err != nil {
+		t.Fatalf("Failed to log evaluation: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	var logEntry models.AuditLog
+	if err := json.Unmarshal(content, &logEntry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+
+	snapshot, ok := logEntry.Context["evaluation_context"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Log entry should contain evaluation_context, got %v", logEntry.Context)
+	}
+
+	subject := snapshot["subject"].(map[string]interface{})
+	if subject["department"] != "Engineering" {
+		t.Errorf("Expected department in snapshot, got %v", subject["department"])
+	}
+	if subject["salary"] != RedactedValue {
+		t.Errorf("Expected salary to be redacted, got %v", subject["salary"])
+	}
+
+	if _, exists := snapshot["resource"]; exists {
+		t.Error("Resource should be excluded by the allowlist")
+	}
+
+	environment := snapshot["environment"].(map[string]interface{})
+	if environment["source_ip"] != "10.0.1.100" {
+		t.Errorf("Expected source_ip in snapshot, got %v", environment["source_ip"])
+	}
+	if _, exists := environment["time_of_day"]; exists {
+		t.Error("time_of_day should be excluded by the allowlist")
+	}
 }
 
 func TestLogAccessAttempt(t *testing.T) {
@@ -517,3 +640,97 @@ func TestCloseLogger(t *testing.T) {
 		t.Errorf("Closing stdout logger should not return error: %v", err)
 	}
 }
+
+func TestLogEvaluationAuditLevels(t *testing.T) {
+	request := &models.EvaluationRequest{
+		RequestID:  "verbosity-001",
+		Subject:    models.NewMockUserSubject("sub-001", "sub-001"),
+		ResourceID: "res-001",
+		Action:     "read",
+	}
+	context := &models.EvaluationContext{
+		Subject:     &models.Subject{ID: "sub-001", SubjectType: "user", Attributes: map[string]interface{}{"salary": 120000}},
+		Resource:    &models.Resource{ID: "res-001", ResourceType: "document"},
+		Environment: map[string]interface{}{"source_ip": "10.0.1.100"},
+	}
+
+	logEvaluation := func(level string) map[string]interface{} {
+		t.Helper()
+		tempFile, err := ioutil.TempFile("", "audit_test_*.log")
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		defer os.Remove(tempFile.Name())
+		tempFile.Close()
+
+		logger, err := NewAuditLogger(tempFile.Name()) // SnapshotContext off by default
+		if err != nil {
+			t.Fatalf("Failed to create audit logger: %v", err)
+		}
+		defer logger.Close()
+
+		decision := &models.Decision{Result: "permit", MatchedPolicies: []string{"pol-001"}, Reason: "Allowed by statements: ReadDocs",
+			Obligations: map[string]string{constants.ObligationAudit: level}}
+		if err := logger.LogEvaluation(request, decision, context); err != nil {
+			t.Fatalf("Failed to log evaluation: %v", err)
+		}
+		content, err := ioutil.ReadFile(tempFile.Name())
+		if err != nil {
+			t.Fatalf("Failed to read log file: %v", err)
+		}
+		var logEntry models.AuditLog
+		if err := json.Unmarshal(content, &logEntry); err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		return logEntry.Context
+	}
+
+	minimal := logEvaluation(constants.AuditLevelMinimal)
+	if len(minimal) != 2 || minimal["audit_level"] != constants.AuditLevelMinimal || minimal["matched_policies"] == nil {
+		t.Errorf("Expected only matched_policies and audit_level, got %v", minimal)
+	}
+
+	standard := logEvaluation("")
+	if _, ok := standard["audit_level"]; ok || standard["source_ip"] != "10.0.1.100" || standard["evaluation_context"] != nil {
+		t.Errorf("Expected the standard entry without a snapshot, got %v", standard)
+	}
+
+	full := logEvaluation(constants.AuditLevelFull)
+	snapshot, ok := full["evaluation_context"].(map[string]interface{})
+	if !ok || full["audit_level"] != constants.AuditLevelFull {
+		t.Fatalf("Expected a full entry with evaluation_context, got %v", full)
+	}
+	if subject := snapshot["subject"].(map[string]interface{}); subject["salary"] != RedactedValue {
+		t.Errorf("Expected redaction to apply to full entries, got %v", subject["salary"])
+	}
+}
+
+func TestDecisionAuditLog(t *testing.T) {
+	request := &models.EvaluationRequest{
+		RequestID:   "decision-001",
+		Subject:     models.NewMockUserSubject("sub-001", "sub-001"),
+		ResourceID:  "res-001",
+		Action:      "read",
+		Context:     map[string]interface{}{"email": "john@company.com"},
+		Environment: &models.EnvironmentInfo{ClientIP: "10.0.1.100", UserAgent: "curl/8.0"},
+	}
+	decision := &models.Decision{Result: "deny", MatchedPolicies: []string{"pol-001"}, EvaluationTimeMs: 3,
+		Reason: "Denied for john@company.com"}
+	redactor := DefaultAuditConfig().Redactor()
+
+	entry := DecisionAuditLog(request, decision, redactor)
+	if entry.Source != models.AuditSourceEvaluation || entry.SubjectID != "sub-001" || entry.Decision != "deny" || entry.EvaluationMs != 3 {
+		t.Errorf("Unexpected decision audit entry %+v", entry)
+	}
+	if entry.Context["source_ip"] != "10.0.1.100" || entry.Context["user_agent"] != "curl/8.0" {
+		t.Errorf("Expected the client environment, got %v", entry.Context)
+	}
+	if reason, _ := entry.Context["reason"].(string); strings.Contains(reason, "john@company.com") {
+		t.Errorf("Expected the reason to be redacted, got %q", reason)
+	}
+
+	decision.Obligations = map[string]string{constants.ObligationAudit: constants.AuditLevelMinimal}
+	if minimal := DecisionAuditLog(request, decision, redactor).Context; len(minimal) != 2 || minimal["audit_level"] != constants.AuditLevelMinimal {
+		t.Errorf("Expected only matched_policies and audit_level, got %v", minimal)
+	}
+}
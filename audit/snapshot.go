@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"strings"
+
+	"abac_go_example/models"
+	"abac_go_example/redaction"
+)
+
+// RedactedValue replaces the value of redacted attributes in context snapshots
+const RedactedValue = redaction.DefaultMask
+
+// AuditConfig holds configuration for AuditLogger
+type AuditConfig struct {
+	// SnapshotContext persists the enriched evaluation context in AuditLog.Context
+	SnapshotContext bool `json:"snapshot_context"`
+	// SnapshotAllowedKeys restricts the snapshot to these paths (e.g. "subject.department", "environment").
+	// An empty list keeps every key.
+	SnapshotAllowedKeys []string `json:"snapshot_allowed_keys,omitempty"`
+	// RedactedKeys lists attribute names whose values are masked wherever they appear (case-insensitive)
+	RedactedKeys []string `json:"redacted_keys,omitempty"`
+}
+
+// DefaultAuditConfig returns default configuration for AuditLogger
+func DefaultAuditConfig() *AuditConfig {
+	return &AuditConfig{
+		SnapshotContext: false,
+		RedactedKeys:    []string{"salary", "ssn", "email", "phone", "password"},
+	}
+}
+
+// BuildContextSnapshot converts an enriched evaluation context into a serializable map
+// grouped by entity ("subject", "resource", "action", "environment").
+func BuildContextSnapshot(context *models.EvaluationContext) map[string]interface{} {
+	snapshot := make(map[string]interface{})
+	if context == nil {
+		return snapshot
+	}
+
+	if context.Subject != nil {
+		subject := copyAttributes(context.Subject.Attributes)
+		subject["id"] = context.Subject.ID
+		subject["subject_type"] = context.Subject.SubjectType
+		snapshot["subject"] = subject
+	}
+
+	if context.Resource != nil {
+		resource := copyAttributes(context.Resource.Attributes)
+		resource["id"] = context.Resource.ID
+		resource["resource_type"] = context.Resource.ResourceType
+		resource["resource_id"] = context.Resource.ResourceID
+		snapshot["resource"] = resource
+	}
+
+	if context.Action != nil {
+		snapshot["action"] = map[string]interface{}{
+			"action_name":     context.Action.ActionName,
+			"action_category": context.Action.ActionCategory,
+		}
+	}
+
+	if context.Environment != nil {
+		snapshot["environment"] = copyAttributes(context.Environment)
+	}
+
+	if !context.Timestamp.IsZero() {
+		snapshot["timestamp"] = context.Timestamp
+	}
+
+	return snapshot
+}
+
+// filterSnapshot applies the allowlist and redaction rules of the config to a snapshot
+func (c *AuditConfig) filterSnapshot(snapshot map[string]interface{}) map[string]interface{} {
+	return c.Redactor().RedactMap(filterMap(snapshot, "", c.SnapshotAllowedKeys))
+}
+
+// Redactor builds the redactor that masks RedactedKeys in audit entries
+func (c *AuditConfig) Redactor() *redaction.Redactor {
+	return redaction.NewRedactor(c.RedactedKeys...)
+}
+
+// filterMap recursively copies m keeping only allowed paths
+func filterMap(m map[string]interface{}, prefix string, allowed []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+
+	for key, value := range m {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if !isPathAllowed(path, allowed) {
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			result[key] = filterMap(nested, path, allowed)
+			continue
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// isPathAllowed reports whether path is covered by the allowlist.
+// A path is allowed when it equals an entry, is nested under an entry, or is an ancestor of an entry.
+func isPathAllowed(path string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, entry := range allowed {
+		if path == entry || strings.HasPrefix(path, entry+".") || strings.HasPrefix(entry, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contextAttributes flattens subject, resource and environment attributes into "entity.key" entries,
+// used to locate sensitive values embedded in decision reasons
+func contextAttributes(context *models.EvaluationContext) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	if context == nil {
+		return attrs
+	}
+
+	if context.Subject != nil {
+		for k, v := range context.Subject.Attributes {
+			attrs["subject."+k] = v
+		}
+	}
+	if context.Resource != nil {
+		for k, v := range context.Resource.Attributes {
+			attrs["resource."+k] = v
+		}
+	}
+	for k, v := range context.Environment {
+		attrs["environment."+k] = v
+	}
+
+	return attrs
+}
+
+func copyAttributes(attrs map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,269 @@
+// Package bundle exports and imports policies as signed bundles. Each policy gets a content
+// digest, the bundle hash covers every digest and the hash is signed with Ed25519, so a
+// tampered file or database row no longer matches what was signed.
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"abac_go_example/models"
+)
+
+// FormatVersion is the bundle format written by Signer. Version 2 signs CreatedAt with the hash;
+// version 1 bundles must be re-signed.
+const FormatVersion = 2
+
+var (
+	// ErrUnsigned is returned when a bundle carries no signature
+	ErrUnsigned = errors.New("bundle is not signed")
+	// ErrUnknownKey is returned when the bundle was signed by a key the verifier does not trust
+	ErrUnknownKey = errors.New("bundle signed by an untrusted key")
+	// ErrHashMismatch is returned when the policies do not match the bundle hash
+	ErrHashMismatch = errors.New("bundle content does not match its hash")
+	// ErrInvalidSignature is returned when the signature does not verify
+	ErrInvalidSignature = errors.New("bundle signature is invalid")
+	// ErrStaleBundle is returned when a bundle was not created after the bundle already applied
+	ErrStaleBundle = errors.New("bundle is not newer than the applied bundle")
+)
+
+// untrackedFields are bookkeeping fields that may differ between databases and are not signed
+var untrackedFields = []string{"revision", "created_at", "updated_at"}
+
+// toggleFields are signed, but approvers may change them after a bundle is loaded: bulk toggles and
+// policy schedules enable and disable bundle policies. PolicyContentDigest leaves them out.
+var toggleFields = []string{"enabled"}
+
+// Bundle is a signed set of policies
+type Bundle struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"` // Signed, so bundles can be ordered to reject replays
+	KeyID     string           `json:"key_id"`
+	Policies  []*models.Policy `json:"policies"`
+	Hash      string           `json:"hash"`      // Hex SHA-256 over the sorted policy digests
+	Signature string           `json:"signature"` // Base64 Ed25519 signature of Hash and CreatedAt
+}
+
+// NewerThan reports whether b was created after other; every bundle is newer than nil
+func (b *Bundle) NewerThan(other *Bundle) bool {
+	return other == nil || b.CreatedAt.After(other.CreatedAt)
+}
+
+// signedPayload is the message signed for a bundle: its format version, hash and creation time
+func signedPayload(version int, hash string, createdAt time.Time) []byte {
+	return []byte(fmt.Sprintf("abac-bundle/v%d\n%s\n%s", version, hash, createdAt.UTC().Format(time.RFC3339Nano)))
+}
+
+// PolicyDigest returns the hex SHA-256 of a policy's canonical JSON document.
+// Revision and timestamps are excluded so a bundle verifies in any database it is loaded into.
+func PolicyDigest(policy *models.Policy) (string, error) {
+	return policyDigest(policy, untrackedFields)
+}
+
+// PolicyContentDigest is PolicyDigest without the enabled flag. Policy integrity checks compare stored
+// policies by it, so enabling or disabling a signed policy keeps it trusted while any edit does not.
+func PolicyContentDigest(policy *models.Policy) (string, error) {
+	return policyDigest(policy, append(append([]string{}, untrackedFields...), toggleFields...))
+}
+
+// policyDigest hashes the canonical JSON document of policy without the excluded fields
+func policyDigest(policy *models.Policy, excluded []string) (string, error) {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal policy %s: %w", policy.ID, err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return "", fmt.Errorf("failed to decode policy %s: %w", policy.ID, err)
+	}
+	for _, field := range excluded {
+		delete(document, field)
+	}
+
+	// encoding/json sorts map keys, which makes the document canonical
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize policy %s: %w", policy.ID, err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Digests returns policy ID -> PolicyDigest, rejecting empty and duplicate IDs
+func Digests(policies []*models.Policy) (map[string]string, error) {
+	digests := make(map[string]string, len(policies))
+	for _, policy := range policies {
+		if policy.ID == "" {
+			return nil, fmt.Errorf("bundle policy without id")
+		}
+		if _, exists := digests[policy.ID]; exists {
+			return nil, fmt.Errorf("duplicate bundle policy %s", policy.ID)
+		}
+		digest, err := PolicyDigest(policy)
+		if err != nil {
+			return nil, err
+		}
+		digests[policy.ID] = digest
+	}
+	return digests, nil
+}
+
+// PolicySetHash returns the content hash of a policy set, equal to the Hash of a bundle of the same policies.
+// It changes whenever a policy is added, removed or edited, but not when a policy is re-saved unchanged.
+func PolicySetHash(policies []*models.Policy) (string, error) {
+	digests, err := Digests(policies)
+	if err != nil {
+		return "", err
+	}
+	return contentHash(digests), nil
+}
+
+// contentHash hashes the digests in policy ID order
+func contentHash(digests map[string]string) string {
+	ids := make([]string, 0, len(digests))
+	for id := range digests {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	for _, id := range ids {
+		fmt.Fprintf(h, "%s\x00%s\n", id, digests[id])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// KeyID identifies a public key: the first 16 hex characters of its SHA-256
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Signer creates signed bundles
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+	now   func() time.Time
+}
+
+// NewSigner creates a signer for an Ed25519 private key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key, keyID: KeyID(key.Public().(ed25519.PublicKey)), now: time.Now}
+}
+
+// Sign bundles the policies, sorted by ID, and signs their content hash
+func (s *Signer) Sign(policies []*models.Policy) (*Bundle, error) {
+	digests, err := Digests(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]*models.Policy, len(policies))
+	copy(sorted, policies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	hash := contentHash(digests)
+	createdAt := s.now().UTC()
+	return &Bundle{
+		Version:   FormatVersion,
+		CreatedAt: createdAt,
+		KeyID:     s.keyID,
+		Policies:  sorted,
+		Hash:      hash,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, signedPayload(FormatVersion, hash, createdAt))),
+	}, nil
+}
+
+// Verifier checks bundles against a set of trusted public keys
+type Verifier struct {
+	keys map[string]ed25519.PublicKey
+}
+
+// NewVerifier creates a verifier trusting the given public keys
+func NewVerifier(keys ...ed25519.PublicKey) *Verifier {
+	v := &Verifier{keys: make(map[string]ed25519.PublicKey, len(keys))}
+	for _, key := range keys {
+		v.keys[KeyID(key)] = key
+	}
+	return v
+}
+
+// Verify recomputes the policy digests and bundle hash and checks the signature over the hash and
+// CreatedAt. It returns the verified digests (policy ID -> digest).
+func (v *Verifier) Verify(b *Bundle) (map[string]string, error) {
+	if b == nil || b.Signature == "" {
+		return nil, ErrUnsigned
+	}
+	if b.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	key, trusted := v.keys[b.KeyID]
+	if !trusted {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, b.KeyID)
+	}
+
+	digests, err := Digests(b.Policies)
+	if err != nil {
+		return nil, err
+	}
+	if contentHash(digests) != b.Hash {
+		return nil, ErrHashMismatch
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(b.Signature)
+	if err != nil || !ed25519.Verify(key, signedPayload(b.Version, b.Hash, b.CreatedAt), signature) {
+		return nil, ErrInvalidSignature
+	}
+	return digests, nil
+}
+
+// Read decodes a bundle from JSON
+func Read(r io.Reader) (*Bundle, error) {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	return &b, nil
+}
+
+// LoadFile reads a bundle file
+func LoadFile(path string) (*Bundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// WriteFile writes the bundle as indented JSON, replacing path atomically
+func (b *Bundle) WriteFile(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// ShortHash returns the first 12 characters of the bundle hash, for logs
+func (b *Bundle) ShortHash() string {
+	return b.Hash[:min(12, len(b.Hash))]
+}
@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+func testPolicies() []*models.Policy {
+	return []*models.Policy{
+		{
+			ID: "pol-b", PolicyName: "Read", Version: "2024-10-21", Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+			},
+		},
+		{
+			ID: "pol-a", PolicyName: "DenyDelete", Version: "2024-10-21", Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "DenyDelete", Effect: "Deny", Action: models.JSONActionResource{Single: "document:delete"}, Resource: models.JSONActionResource{Single: "*"}},
+			},
+		},
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := NewSigner(privateKey).Sign(testPolicies())
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if signed.Policies[0].ID != "pol-a" || signed.KeyID != KeyID(publicKey) {
+		t.Errorf("Expected policies sorted by ID and the signer key ID, got %s / %s", signed.Policies[0].ID, signed.KeyID)
+	}
+
+	verifier := NewVerifier(publicKey)
+	digests, err := verifier.Verify(signed)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(digests) != 2 || digests["pol-a"] == "" {
+		t.Errorf("Unexpected digests %v", digests)
+	}
+
+	// Bookkeeping fields are not signed
+	signed.Policies[0].Revision = 7
+	signed.Policies[0].UpdatedAt = time.Now()
+	if _, err := verifier.Verify(signed); err != nil {
+		t.Errorf("Expected revision and timestamps to be ignored, got %v", err)
+	}
+
+	// The creation time is signed, so a replayed bundle cannot be made to look newer
+	createdAt := signed.CreatedAt
+	signed.CreatedAt = createdAt.Add(time.Hour)
+	if _, err := verifier.Verify(signed); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a changed creation time, got %v", err)
+	}
+	signed.CreatedAt = createdAt
+
+	// A permit-all statement injected into a signed policy
+	signed.Policies[1].Statement[0].Resource = models.JSONActionResource{Single: "*"}
+	if _, err := verifier.Verify(signed); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Expected ErrHashMismatch, got %v", err)
+	}
+
+	// Recomputing the hash without the key breaks the signature
+	digests, _ = Digests(signed.Policies)
+	signed.Hash = contentHash(digests)
+	if _, err := verifier.Verify(signed); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	otherKey, _, _ := GenerateKey()
+	if _, err := NewVerifier(otherKey).Verify(signed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+	if _, err := verifier.Verify(&Bundle{Version: FormatVersion}); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestSign_DuplicateIDs(t *testing.T) {
+	_, privateKey, _ := GenerateKey()
+	policies := append(testPolicies(), &models.Policy{ID: "pol-a"})
+	if _, err := NewSigner(privateKey).Sign(policies); err == nil {
+		t.Error("Expected duplicate policy IDs to be rejected")
+	}
+}
+
+func TestKeysAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	publicKey, privateKey, _ := GenerateKey()
+	privatePEM, _ := EncodePrivateKey(privateKey)
+	publicPEM, _ := EncodePublicKey(publicKey)
+	os.WriteFile(filepath.Join(dir, "bundle.key"), privatePEM, 0o600)
+	os.WriteFile(filepath.Join(dir, "bundle.pub"), publicPEM, 0o644)
+
+	t.Setenv(constants.EnvBundlePublicKey, "")
+	t.Setenv(constants.EnvBundleSigningKey, "")
+	if verifier, err := VerifierFromEnv(); verifier != nil || err != nil {
+		t.Errorf("Expected nil verifier when unset, got %v (err=%v)", verifier, err)
+	}
+
+	t.Setenv(constants.EnvBundlePublicKey, filepath.Join(dir, "bundle.pub"))
+	t.Setenv(constants.EnvBundleSigningKey, filepath.Join(dir, "bundle.key"))
+	verifier, err := VerifierFromEnv()
+	if err != nil {
+		t.Fatalf("VerifierFromEnv failed: %v", err)
+	}
+	signer, err := SignerFromEnv()
+	if err != nil {
+		t.Fatalf("SignerFromEnv failed: %v", err)
+	}
+
+	signed, _ := signer.Sign(testPolicies())
+	path := filepath.Join(dir, "policies.bundle.json")
+	if err := signed.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if _, err := verifier.Verify(loaded); err != nil {
+		t.Errorf("Expected the written bundle to verify, got %v", err)
+	}
+
+	if _, err := LoadPublicKey(filepath.Join(dir, "bundle.key")); err == nil {
+		t.Error("Expected a private key file to be rejected as public key")
+	}
+}
+
+func TestPolicySetHash(t *testing.T) {
+	_, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies := testPolicies()
+	signed, err := NewSigner(privateKey).Sign(policies)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	hash, err := PolicySetHash(policies)
+	if err != nil {
+		t.Fatalf("PolicySetHash failed: %v", err)
+	}
+	if hash != signed.Hash {
+		t.Errorf("Expected the bundle hash %s, got %s", signed.Hash, hash)
+	}
+
+	policies[0].Revision = 9
+	if unchanged, _ := PolicySetHash(policies); unchanged != hash {
+		t.Error("Expected revision changes to keep the hash")
+	}
+	policies[0].Enabled = false
+	if changed, _ := PolicySetHash(policies); changed == hash {
+		t.Error("Expected disabling a policy to change the hash")
+	}
+}
@@ -0,0 +1,248 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// DefaultPollInterval is the time between bundle downloads of fetchers configured without one
+const DefaultPollInterval = 60 * time.Second
+
+// maxBundleBytes bounds a downloaded bundle
+const maxBundleBytes = 64 << 20
+
+// FetcherConfig configures a bundle fetcher
+type FetcherConfig struct {
+	// URL is an http(s):// URL or s3://bucket/key
+	URL string `json:"url"`
+	// Interval is the time between downloads
+	Interval time.Duration `json:"interval"`
+	// S3 signs s3:// requests; nil reads credentials from the AWS_* environment variables
+	S3 *S3Config `json:"-"`
+}
+
+// FetcherConfigFromEnv reads ABAC_BUNDLE_URL and ABAC_BUNDLE_POLL_INTERVAL.
+// It returns nil (no fetcher) when ABAC_BUNDLE_URL is unset.
+func FetcherConfigFromEnv() (*FetcherConfig, error) {
+	url := os.Getenv(constants.EnvBundleURL)
+	if url == "" {
+		return nil, nil
+	}
+	config := &FetcherConfig{URL: url, Interval: DefaultPollInterval}
+	if value := os.Getenv(constants.EnvBundlePollInterval); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a duration such as \"1m\"", constants.EnvBundlePollInterval, value)
+		}
+		config.Interval = interval
+	}
+	return config, nil
+}
+
+// FetcherStatus reports the downloads of a fetcher
+type FetcherStatus struct {
+	URL         string    `json:"url"`
+	BundleHash  string    `json:"bundle_hash,omitempty"` // Hash of the last applied bundle
+	KeyID       string    `json:"key_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`           // Signed creation time of the last applied bundle
+	LastFetch   time.Time `json:"last_fetch"`           // Last successful download, changed or not
+	LastApplied time.Time `json:"last_applied"`         // When the current bundle was applied
+	LastError   string    `json:"last_error,omitempty"` // Error of the last failed fetch
+	Fetches     int64     `json:"fetches"`
+	Updates     int64     `json:"updates"` // Bundles applied
+	Errors      int64     `json:"errors"`
+}
+
+// ApplyFunc hot-swaps a verified bundle into the running PDP
+type ApplyFunc func(b *Bundle) error
+
+// Fetcher periodically downloads a signed bundle over HTTP or from S3, verifies it and applies it
+// when its hash changed. A bundle that fails to download or verify is never applied, and neither is
+// one not created after the applied bundle, so an older signed bundle replayed from the source cannot
+// roll policies back.
+type Fetcher struct {
+	config   FetcherConfig
+	verifier *Verifier
+	apply    ApplyFunc
+	client   *http.Client
+	target   string // Download URL; s3:// URLs are resolved to the bucket endpoint
+	s3       *S3Config
+
+	mu      sync.Mutex
+	etag    string
+	current *Bundle // Last applied bundle
+	status  FetcherStatus
+}
+
+// NewFetcher creates a fetcher applying bundles verified by verifier, which is required
+func NewFetcher(config *FetcherConfig, verifier *Verifier, apply ApplyFunc) (*Fetcher, error) {
+	if config == nil || config.URL == "" {
+		return nil, errors.New("bundle fetcher requires a URL")
+	}
+	if verifier == nil {
+		return nil, errors.New("bundle fetcher requires a verifier")
+	}
+	f := &Fetcher{
+		config:   *config,
+		verifier: verifier,
+		apply:    apply,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		target:   config.URL,
+		status:   FetcherStatus{URL: config.URL},
+	}
+	if f.config.Interval <= 0 {
+		f.config.Interval = DefaultPollInterval
+	}
+
+	switch {
+	case strings.HasPrefix(config.URL, "s3://"):
+		f.s3 = config.S3
+		if f.s3 == nil {
+			f.s3 = S3ConfigFromEnv()
+		}
+		target, err := f.s3.objectURL(config.URL)
+		if err != nil {
+			return nil, err
+		}
+		f.target = target
+	case strings.HasPrefix(config.URL, "http://"), strings.HasPrefix(config.URL, "https://"):
+	default:
+		return nil, fmt.Errorf("unsupported bundle URL %q: expected http(s):// or s3://", config.URL)
+	}
+	return f, nil
+}
+
+// SetCurrent records b, such as the bundle loaded at startup, as applied.
+// Downloads not newer than b are rejected.
+func (f *Fetcher) SetCurrent(b *Bundle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current = b
+	f.status.BundleHash = b.Hash
+	f.status.KeyID = b.KeyID
+	f.status.CreatedAt = b.CreatedAt
+}
+
+// SetHTTPClient replaces the client used for downloads
+func (f *Fetcher) SetHTTPClient(client *http.Client) {
+	f.client = client
+}
+
+// Start fetches immediately and then every Interval until ctx is cancelled.
+// Failures are logged; the running PDP keeps its current bundle.
+func (f *Fetcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(f.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := f.Fetch(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to fetch policy bundle from %s: %v", f.config.URL, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Fetch downloads the bundle once and applies it when it changed. It reports whether a bundle was applied.
+func (f *Fetcher) Fetch(ctx context.Context) (bool, error) {
+	applied, err := f.fetch(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		f.status.Errors++
+		f.status.LastError = err.Error()
+		return false, err
+	}
+	f.status.Fetches++
+	f.status.LastFetch = time.Now()
+	f.status.LastError = ""
+	return applied, nil
+}
+
+func (f *Fetcher) fetch(ctx context.Context) (bool, error) {
+	f.mu.Lock()
+	etag, current := f.etag, f.current
+	f.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.target, nil)
+	if err != nil {
+		return false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if f.s3 != nil {
+		f.s3.sign(req, time.Now())
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	downloaded, err := Read(io.LimitReader(resp.Body, maxBundleBytes))
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.verifier.Verify(downloaded); err != nil {
+		return false, err
+	}
+
+	newETag := resp.Header.Get("ETag")
+	if current != nil && downloaded.Hash == current.Hash {
+		f.mu.Lock()
+		f.etag = newETag
+		f.mu.Unlock()
+		return false, nil
+	}
+	if !downloaded.NewerThan(current) {
+		return false, fmt.Errorf("%w: bundle %s created %s, applied bundle %s created %s", ErrStaleBundle,
+			downloaded.ShortHash(), downloaded.CreatedAt.Format(time.RFC3339), current.ShortHash(), current.CreatedAt.Format(time.RFC3339))
+	}
+
+	if err := f.apply(downloaded); err != nil {
+		return false, fmt.Errorf("failed to apply bundle %s: %w", downloaded.ShortHash(), err)
+	}
+	log.Printf("Policy bundle %s applied from %s (%d policies, key %s)", downloaded.ShortHash(), f.config.URL, len(downloaded.Policies), downloaded.KeyID)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.etag = newETag
+	f.current = downloaded
+	f.status.BundleHash = downloaded.Hash
+	f.status.KeyID = downloaded.KeyID
+	f.status.CreatedAt = downloaded.CreatedAt
+	f.status.LastApplied = time.Now()
+	f.status.Updates++
+	return true, nil
+}
+
+// Status returns a snapshot of the fetcher counters
+func (f *Fetcher) Status() FetcherStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status
+}
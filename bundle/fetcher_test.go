@@ -0,0 +1,266 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// bundleServer serves a bundle with an ETag and honours If-None-Match
+type bundleServer struct {
+	mu       sync.Mutex
+	bundle   *Bundle
+	requests int
+	header   http.Header
+}
+
+func (s *bundleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.header = r.Header.Clone()
+
+	etag := `"` + s.bundle.Hash + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	json.NewEncoder(w).Encode(s.bundle)
+}
+
+func TestFetcher(t *testing.T) {
+	pub, priv, _ := GenerateKey()
+	signer := NewSigner(priv)
+	signed, _ := signer.Sign(testPolicies())
+	source := &bundleServer{bundle: signed}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	var applied []*Bundle
+	fetcher, err := NewFetcher(&FetcherConfig{URL: server.URL + "/bundle.json"}, NewVerifier(pub), func(b *Bundle) error {
+		applied = append(applied, b)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewFetcher failed: %v", err)
+	}
+
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || !ok {
+		t.Fatalf("Expected the first bundle to be applied, got %v %v", ok, err)
+	}
+	if len(applied) != 1 || applied[0].Hash != signed.Hash {
+		t.Fatalf("Expected bundle %s to be applied, got %d bundles", signed.ShortHash(), len(applied))
+	}
+
+	// An unchanged bundle is answered with 304 and not applied again
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || ok {
+		t.Errorf("Expected an unchanged bundle to be skipped, got %v %v", ok, err)
+	}
+	if source.header.Get("If-None-Match") != `"`+signed.Hash+`"` {
+		t.Errorf("Expected the ETag to be sent, got %q", source.header.Get("If-None-Match"))
+	}
+
+	// A tampered bundle is never applied
+	tampered, _ := signer.Sign(testPolicies()[:1])
+	tampered.Policies[0].PolicyName = "Tampered"
+	source.mu.Lock()
+	source.bundle = tampered
+	source.mu.Unlock()
+	if _, err := fetcher.Fetch(context.Background()); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Expected ErrHashMismatch, got %v", err)
+	}
+
+	// A new signed bundle is hot-swapped in
+	updated, _ := signer.Sign(testPolicies()[:1])
+	source.mu.Lock()
+	source.bundle = updated
+	source.mu.Unlock()
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || !ok {
+		t.Fatalf("Expected the updated bundle to be applied, got %v %v", ok, err)
+	}
+
+	status := fetcher.Status()
+	if status.BundleHash != updated.Hash || status.Updates != 2 || status.Fetches != 3 || status.Errors != 1 || status.LastError != "" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
+
+func TestFetcherRejectsReplayedBundle(t *testing.T) {
+	pub, priv, _ := GenerateKey()
+	signer := NewSigner(priv)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+	older, _ := signer.Sign(testPolicies())
+	now = now.Add(time.Hour)
+	newer, _ := signer.Sign(testPolicies()[:1])
+
+	source := &bundleServer{bundle: newer}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	var applied []*Bundle
+	fetcher, _ := NewFetcher(&FetcherConfig{URL: server.URL}, NewVerifier(pub), func(b *Bundle) error {
+		applied = append(applied, b)
+		return nil
+	})
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || !ok {
+		t.Fatalf("Expected the newer bundle to be applied, got %v %v", ok, err)
+	}
+
+	// An older validly signed bundle put back at the source does not roll policies back
+	source.mu.Lock()
+	source.bundle = older
+	source.mu.Unlock()
+	if ok, err := fetcher.Fetch(context.Background()); !errors.Is(err, ErrStaleBundle) || ok {
+		t.Errorf("Expected ErrStaleBundle, got %v %v", ok, err)
+	}
+	if len(applied) != 1 || fetcher.Status().BundleHash != newer.Hash || !fetcher.Status().CreatedAt.Equal(newer.CreatedAt) {
+		t.Errorf("Expected only the newer bundle to be applied, got %d bundles and %+v", len(applied), fetcher.Status())
+	}
+
+	// A fetcher seeded with the bundle loaded at startup rejects older downloads from the first fetch
+	seeded, _ := NewFetcher(&FetcherConfig{URL: server.URL}, NewVerifier(pub), func(b *Bundle) error {
+		t.Error("Expected no bundle to be applied")
+		return nil
+	})
+	seeded.SetCurrent(newer)
+	if _, err := seeded.Fetch(context.Background()); !errors.Is(err, ErrStaleBundle) {
+		t.Errorf("Expected ErrStaleBundle, got %v", err)
+	}
+}
+
+func TestFetcherApplyError(t *testing.T) {
+	pub, priv, _ := GenerateKey()
+	signed, _ := NewSigner(priv).Sign(testPolicies())
+	server := httptest.NewServer(&bundleServer{bundle: signed})
+	defer server.Close()
+
+	calls := 0
+	fetcher, _ := NewFetcher(&FetcherConfig{URL: server.URL}, NewVerifier(pub), func(b *Bundle) error {
+		calls++
+		if calls == 1 {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	})
+
+	if _, err := fetcher.Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "storage unavailable") {
+		t.Fatalf("Expected the apply error, got %v", err)
+	}
+	// The ETag of a failed apply is not remembered, so the next fetch retries
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || !ok {
+		t.Errorf("Expected the retry to apply the bundle, got %v %v", ok, err)
+	}
+}
+
+func TestNewFetcherValidation(t *testing.T) {
+	pub, _, _ := GenerateKey()
+	apply := func(*Bundle) error { return nil }
+	if _, err := NewFetcher(&FetcherConfig{URL: "http://pap/bundle.json"}, nil, apply); err == nil {
+		t.Error("Expected a verifier to be required")
+	}
+	if _, err := NewFetcher(&FetcherConfig{URL: "ftp://pap/bundle.json"}, NewVerifier(pub), apply); err == nil {
+		t.Error("Expected ftp:// to be rejected")
+	}
+	if _, err := NewFetcher(&FetcherConfig{URL: "s3://bucket-only"}, NewVerifier(pub), apply); err == nil {
+		t.Error("Expected an S3 URL without key to be rejected")
+	}
+}
+
+func TestFetcherConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvBundleURL, "")
+	if config, err := FetcherConfigFromEnv(); config != nil || err != nil {
+		t.Errorf("Expected no fetcher, got %+v %v", config, err)
+	}
+
+	t.Setenv(constants.EnvBundleURL, "s3://policies/prod.bundle.json")
+	t.Setenv(constants.EnvBundlePollInterval, "5m")
+	config, err := FetcherConfigFromEnv()
+	if err != nil || config.URL != "s3://policies/prod.bundle.json" || config.Interval != 5*time.Minute {
+		t.Errorf("Unexpected config %+v %v", config, err)
+	}
+
+	t.Setenv(constants.EnvBundlePollInterval, "often")
+	if _, err := FetcherConfigFromEnv(); err == nil {
+		t.Error("Expected an invalid interval to be rejected")
+	}
+}
+
+func TestS3Fetch(t *testing.T) {
+	pub, priv, _ := GenerateKey()
+	signed, _ := NewSigner(priv).Sign(testPolicies())
+	var gotPath string
+	source := &bundleServer{bundle: signed}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		source.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	s3 := &S3Config{Region: "eu-west-1", Endpoint: server.URL, AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"}
+	fetcher, err := NewFetcher(&FetcherConfig{URL: "s3://policies/prod/bundle.json", S3: s3}, NewVerifier(pub), func(*Bundle) error { return nil })
+	if err != nil {
+		t.Fatalf("NewFetcher failed: %v", err)
+	}
+	if ok, err := fetcher.Fetch(context.Background()); err != nil || !ok {
+		t.Fatalf("Expected the S3 bundle to be applied, got %v %v", ok, err)
+	}
+
+	if gotPath != "/policies/prod/bundle.json" {
+		t.Errorf("Expected a path-style request, got %s", gotPath)
+	}
+	authorization := source.header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(authorization, "/eu-west-1/s3/aws4_request") ||
+		!strings.Contains(authorization, "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token") {
+		t.Errorf("Unexpected Authorization header %q", authorization)
+	}
+	if source.header.Get("X-Amz-Security-Token") != "token" || source.header.Get("X-Amz-Date") == "" {
+		t.Errorf("Expected the SigV4 headers, got %v", source.header)
+	}
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	aws := &S3Config{Region: "eu-west-1"}
+	if target, _ := aws.objectURL("s3://policies/prod bundle.json"); target != "https://policies.s3.eu-west-1.amazonaws.com/prod%20bundle.json" {
+		t.Errorf("Unexpected AWS URL %s", target)
+	}
+	minio := &S3Config{Endpoint: "http://minio:9000/"}
+	if target, _ := minio.objectURL("s3://policies/prod/bundle.json"); target != "http://minio:9000/policies/prod/bundle.json" {
+		t.Errorf("Unexpected MinIO URL %s", target)
+	}
+}
+
+func TestS3Sign(t *testing.T) {
+	config := &S3Config{Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now := time.Date(2013, 5, 24, 0, 0, 0, 0, time.UTC)
+
+	sign := func() string {
+		req, _ := http.NewRequest(http.MethodGet, "https://examplebucket.s3.amazonaws.com/test.txt", nil)
+		config.sign(req, now)
+		return req.Header.Get("Authorization")
+	}
+	first := sign()
+	if first != sign() {
+		t.Error("Expected signing to be deterministic")
+	}
+	if !strings.HasPrefix(first, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Errorf("Unexpected Authorization header %q", first)
+	}
+
+	// Anonymous configs leave requests unsigned
+	req, _ := http.NewRequest(http.MethodGet, "https://examplebucket.s3.amazonaws.com/test.txt", nil)
+	(&S3Config{Region: "us-east-1"}).sign(req, now)
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Expected an anonymous request")
+	}
+}
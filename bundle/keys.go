@@ -0,0 +1,110 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"abac_go_example/constants"
+)
+
+// GenerateKey creates an Ed25519 key pair
+func GenerateKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+// EncodePrivateKey returns the PKCS#8 PEM form of a private key
+func EncodePrivateKey(key ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePublicKey returns the PKIX PEM form of a public key
+func EncodePublicKey(key ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// LoadPrivateKey reads a PKCS#8 PEM Ed25519 private key
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not Ed25519", path)
+	}
+	return key, nil
+}
+
+// LoadPublicKey reads a PKIX PEM Ed25519 public key
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not Ed25519", path)
+	}
+	return key, nil
+}
+
+// readPEM returns the DER bytes of the first PEM block of blockType in path
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not contain a PEM %s", path, blockType)
+	}
+	return block.Bytes, nil
+}
+
+// VerifierFromEnv trusts the public key named by ABAC_BUNDLE_PUBLIC_KEY.
+// It returns nil (bundle verification disabled) when the variable is unset.
+func VerifierFromEnv() (*Verifier, error) {
+	path := os.Getenv(constants.EnvBundlePublicKey)
+	if path == "" {
+		return nil, nil
+	}
+	key, err := LoadPublicKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(key), nil
+}
+
+// SignerFromEnv signs with the private key named by ABAC_BUNDLE_SIGNING_KEY.
+// It returns nil (bundle export disabled) when the variable is unset.
+func SignerFromEnv() (*Signer, error) {
+	path := os.Getenv(constants.EnvBundleSigningKey)
+	if path == "" {
+		return nil, nil
+	}
+	key, err := LoadPrivateKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key), nil
+}
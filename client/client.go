@@ -0,0 +1,180 @@
+// Package client is a typed Go client of the ABAC HTTP service for PEPs in other repositories.
+// The request and response types and one method per endpoint are generated from the service's
+// OpenAPI document into client_gen.go; regenerate it with `make client` after changing routes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds requests of clients created without an HTTP client
+const DefaultTimeout = 10 * time.Second
+
+// maxErrorBodyBytes bounds the body read from an error response
+const maxErrorBodyBytes = 64 << 10
+
+// Client calls the ABAC service at a base URL, e.g. "http://abac:8081"
+type Client struct {
+	baseURL string
+	http    *http.Client
+	header  http.Header
+}
+
+// New creates a client of the service at baseURL
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: DefaultTimeout},
+		header:  http.Header{},
+	}
+}
+
+// SetHTTPClient replaces the client used to send requests (nil restores the default).
+// Streaming calls need a client without a timeout.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	c.http = client
+}
+
+// SetUserID authenticates requests to protected endpoints with X-User-ID
+func (c *Client) SetUserID(subjectID string) {
+	c.header.Set("X-User-ID", subjectID)
+}
+
+// SetBearerToken authenticates requests to protected endpoints with a JWT/OIDC token
+func (c *Client) SetBearerToken(token string) {
+	c.header.Set("Authorization", "Bearer "+token)
+}
+
+// Error is a non-2xx response of the service
+type Error struct {
+	StatusCode int
+	Message    string // "error" of the response body
+	Details    string // "details" of the response body
+	Body       []byte // Raw response body
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.Details != "" {
+		return fmt.Sprintf("abac: %d %s: %s", e.StatusCode, message, e.Details)
+	}
+	return fmt.Sprintf("abac: %d %s", e.StatusCode, message)
+}
+
+// do sends a request and decodes the JSON response into out when non-nil. body is sent as
+// contentType when it is an io.Reader and JSON-encoded otherwise.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, contentType string, body, out interface{}) error {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		if isNilPointer(body) {
+			break
+		}
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("abac: encoding request body: %w", err)
+		}
+		reader, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if reader != nil && contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("abac: decoding %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// stream sends a request and returns the response body unread
+func (c *Client) stream(ctx context.Context, method, path string, query url.Values) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, method, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("abac: %w", err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// send performs req and turns non-2xx responses into *Error
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("abac: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	apiErr := &Error{StatusCode: resp.StatusCode, Body: body}
+	var decoded struct {
+		Error   string `json:"error"`
+		Details string `json:"details"`
+	}
+	if json.Unmarshal(body, &decoded) == nil {
+		apiErr.Message, apiErr.Details = decoded.Error, decoded.Details
+	}
+	return nil, apiErr
+}
+
+// isNilPointer reports whether body is a typed nil, e.g. a nil *EvaluateRequestBody
+func isNilPointer(body interface{}) bool {
+	value := reflect.ValueOf(body)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
@@ -0,0 +1,1865 @@
+// Code generated by openapi.GenerateClient from ABAC Authorization Service 1.0.0. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AccessException mirrors the AccessException schema
+type AccessException struct {
+	Action        string     `json:"action,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+	Effect        string     `json:"effect,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	Justification string     `json:"justification,omitempty"`
+	ResourceID    string     `json:"resource_id,omitempty"`
+	SubjectID     string     `json:"subject_id,omitempty"`
+}
+
+// Action mirrors the Action schema
+type Action struct {
+	ActionCategory string     `json:"action_category,omitempty"`
+	ActionName     string     `json:"action_name,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	ID             string     `json:"id,omitempty"`
+	IsSystem       bool       `json:"is_system"`
+}
+
+// ActionListResponse mirrors the ActionListResponse schema
+type ActionListResponse struct {
+	Actions    []Action `json:"actions,omitempty"`
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Total      int64    `json:"total"`
+}
+
+// AttributeCacheStats mirrors the AttributeCacheStats schema
+type AttributeCacheStats struct {
+	Evictions     int64 `json:"evictions"`
+	Hits          int64 `json:"hits"`
+	Invalidations int64 `json:"invalidations"`
+	Misses        int64 `json:"misses"`
+	Size          int   `json:"size"`
+}
+
+// AttributeCacheStatsResponse mirrors the AttributeCacheStatsResponse schema
+type AttributeCacheStatsResponse struct {
+	Enabled bool                 `json:"enabled"`
+	Stats   *AttributeCacheStats `json:"stats,omitempty"`
+}
+
+// AttributeConflict mirrors the AttributeConflict schema
+type AttributeConflict struct {
+	Entity       string      `json:"entity,omitempty"`
+	Key          string      `json:"key,omitempty"`
+	RequestValue interface{} `json:"request_value,omitempty"`
+	Resolution   string      `json:"resolution,omitempty"`
+	StoredValue  interface{} `json:"stored_value,omitempty"`
+}
+
+// AttributeSnapshots mirrors the AttributeSnapshots schema
+type AttributeSnapshots struct {
+	Resource map[string]interface{} `json:"resource,omitempty"`
+	Subject  map[string]interface{} `json:"subject,omitempty"`
+}
+
+// AuditLog mirrors the AuditLog schema
+type AuditLog struct {
+	ActionID     string                 `json:"action_id,omitempty"`
+	Context      map[string]interface{} `json:"context,omitempty"`
+	CreatedAt    *time.Time             `json:"created_at,omitempty"`
+	Decision     string                 `json:"decision,omitempty"`
+	EvaluationMs int                    `json:"evaluation_ms"`
+	ID           int64                  `json:"id"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	Source       string                 `json:"source,omitempty"`
+	SubjectID    string                 `json:"subject_id,omitempty"`
+}
+
+// Bundle mirrors the Bundle schema
+type Bundle struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Hash      string     `json:"hash,omitempty"`
+	KeyID     string     `json:"key_id,omitempty"`
+	Policies  []Policy   `json:"policies,omitempty"`
+	Signature string     `json:"signature,omitempty"`
+	Version   int        `json:"version"`
+}
+
+// BundleImportResponse mirrors the BundleImportResponse schema
+type BundleImportResponse struct {
+	BundleHash string `json:"bundle_hash,omitempty"`
+	Created    int    `json:"created"`
+	KeyID      string `json:"key_id,omitempty"`
+	Unchanged  int    `json:"unchanged"`
+	Updated    int    `json:"updated"`
+}
+
+// BundleStatusResponse mirrors the BundleStatusResponse schema
+type BundleStatusResponse struct {
+	Enabled bool             `json:"enabled"`
+	Fetcher *FetcherStatus   `json:"fetcher,omitempty"`
+	Status  *IntegrityStatus `json:"status,omitempty"`
+}
+
+// CertIssuer mirrors the CertIssuer schema
+type CertIssuer struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+	SubjectDn   string `json:"subject_dn,omitempty"`
+}
+
+// ClientCertInfo mirrors the ClientCertInfo schema
+type ClientCertInfo struct {
+	CommonName     string       `json:"common_name,omitempty"`
+	DnsNames       []string     `json:"dns_names,omitempty"`
+	EmailAddresses []string     `json:"email_addresses,omitempty"`
+	Fingerprint    string       `json:"fingerprint,omitempty"`
+	IPAddresses    []string     `json:"ip_addresses,omitempty"`
+	IssuerChain    []CertIssuer `json:"issuer_chain,omitempty"`
+	IssuerDn       string       `json:"issuer_dn,omitempty"`
+	NotAfter       *time.Time   `json:"not_after,omitempty"`
+	NotBefore      *time.Time   `json:"not_before,omitempty"`
+	SerialNumber   string       `json:"serial_number,omitempty"`
+	SubjectDn      string       `json:"subject_dn,omitempty"`
+	Uris           []string     `json:"uris,omitempty"`
+	Verified       bool         `json:"verified"`
+}
+
+// CompileStats mirrors the CompileStats schema
+type CompileStats struct {
+	AvgCompileTimeUs  float64      `json:"avg_compile_time_us"`
+	CacheHits         int64        `json:"cache_hits"`
+	CachedPolicies    int          `json:"cached_policies"`
+	Compilations      int64        `json:"compilations"`
+	LastCompileTimeUs float64      `json:"last_compile_time_us"`
+	Mode              string       `json:"mode,omitempty"`
+	WarmUp            *WarmUpStats `json:"warm_up,omitempty"`
+}
+
+// ConditionCoverage mirrors the ConditionCoverage schema
+type ConditionCoverage struct {
+	AlwaysMissing bool   `json:"always_missing"`
+	Evaluations   int64  `json:"evaluations"`
+	Key           string `json:"key,omitempty"`
+	Missing       int64  `json:"missing"`
+	Operator      string `json:"operator,omitempty"`
+	Statement     string `json:"statement,omitempty"`
+	Unused        bool   `json:"unused"`
+}
+
+// ConditionTrace mirrors the ConditionTrace schema
+type ConditionTrace struct {
+	Key      string `json:"key,omitempty"`
+	Matched  bool   `json:"matched"`
+	Operator string `json:"operator,omitempty"`
+}
+
+// Constraints mirrors the Constraints schema
+type Constraints struct {
+	Fields   []string `json:"fields,omitempty"`
+	ReadOnly bool     `json:"read_only"`
+}
+
+// Decision mirrors the Decision schema
+type Decision struct {
+	CacheTTL            int               `json:"cache_ttl"`
+	Constraints         *Constraints      `json:"constraints,omitempty"`
+	EvaluationTimeMs    int               `json:"evaluation_time_ms"`
+	MatchedPolicies     []string          `json:"matched_policies,omitempty"`
+	Obligations         map[string]string `json:"obligations,omitempty"`
+	QuarantinedPolicies []string          `json:"quarantined_policies,omitempty"`
+	Reason              string            `json:"reason,omitempty"`
+	ReasonCode          string            `json:"reason_code,omitempty"`
+	ReasonDetails       map[string]string `json:"reason_details,omitempty"`
+	Result              string            `json:"result,omitempty"`
+	Shadow              *ShadowDecision   `json:"shadow,omitempty"`
+}
+
+// DeletedPoliciesResponse mirrors the DeletedPoliciesResponse schema
+type DeletedPoliciesResponse struct {
+	Count    int      `json:"count"`
+	Policies []Policy `json:"policies,omitempty"`
+}
+
+// DeniedResource mirrors the DeniedResource schema
+type DeniedResource struct {
+	Actions      []string   `json:"actions,omitempty"`
+	Denies       int        `json:"denies"`
+	LastDeniedAt *time.Time `json:"last_denied_at,omitempty"`
+	ResourceID   string     `json:"resource_id,omitempty"`
+}
+
+// DenyCacheStats mirrors the DenyCacheStats schema
+type DenyCacheStats struct {
+	Evictions  int64 `json:"evictions"`
+	Misses     int64 `json:"misses"`
+	Size       int   `json:"size"`
+	Stored     int64 `json:"stored"`
+	Suppressed int64 `json:"suppressed"`
+}
+
+// DenyCacheStatsResponse mirrors the DenyCacheStatsResponse schema
+type DenyCacheStatsResponse struct {
+	Enabled bool            `json:"enabled"`
+	Stats   *DenyCacheStats `json:"stats,omitempty"`
+}
+
+// Elevation mirrors the Elevation schema
+type Elevation struct {
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt     *time.Time             `json:"created_at,omitempty"`
+	CreatedBy     string                 `json:"created_by,omitempty"`
+	ExpiresAt     *time.Time             `json:"expires_at,omitempty"`
+	ID            string                 `json:"id,omitempty"`
+	Justification string                 `json:"justification,omitempty"`
+	Roles         []string               `json:"roles,omitempty"`
+	SubjectID     string                 `json:"subject_id,omitempty"`
+}
+
+// ElevationListResponse mirrors the ElevationListResponse schema
+type ElevationListResponse struct {
+	Count      int         `json:"count"`
+	Elevations []Elevation `json:"elevations,omitempty"`
+}
+
+// ElevationRequestBody mirrors the ElevationRequestBody schema
+type ElevationRequestBody struct {
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	ExpiresAt     time.Time              `json:"expires_at"`
+	ID            string                 `json:"id,omitempty"`
+	Justification string                 `json:"justification"`
+	Roles         []string               `json:"roles,omitempty"`
+	SubjectID     string                 `json:"subject_id"`
+}
+
+// EnvironmentInfo mirrors the EnvironmentInfo schema
+type EnvironmentInfo struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	ClientCert *ClientCertInfo        `json:"client_cert,omitempty"`
+	ClientIP   string                 `json:"client_ip,omitempty"`
+	Country    string                 `json:"country,omitempty"`
+	DayOfWeek  string                 `json:"day_of_week,omitempty"`
+	Region     string                 `json:"region,omitempty"`
+	TimeOfDay  string                 `json:"time_of_day,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+}
+
+// ErrorResponse mirrors the ErrorResponse schema
+type ErrorResponse struct {
+	Details string `json:"details,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// EvaluateRequestBody mirrors the EvaluateRequestBody schema
+type EvaluateRequestBody struct {
+	Action      string                 `json:"action"`
+	AsOf        *time.Time             `json:"as_of,omitempty"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+	Environment *EnvironmentInfo       `json:"environment,omitempty"`
+	Fields      []string               `json:"fields,omitempty"`
+	Namespace   string                 `json:"namespace,omitempty"`
+	RequestID   string                 `json:"request_id,omitempty"`
+	ResourceID  string                 `json:"resource_id"`
+	Session     *SessionInfo           `json:"session,omitempty"`
+	Snapshots   *AttributeSnapshots    `json:"snapshots,omitempty"`
+	SubjectID   string                 `json:"subject_id"`
+	Timestamp   *time.Time             `json:"timestamp,omitempty"`
+}
+
+// EvaluateResponse mirrors the EvaluateResponse schema
+type EvaluateResponse struct {
+	Decision  *Decision                 `json:"decision,omitempty"`
+	Fields    map[string]FieldDirective `json:"fields,omitempty"`
+	RequestID string                    `json:"request_id,omitempty"`
+}
+
+// ExceptionListResponse mirrors the ExceptionListResponse schema
+type ExceptionListResponse struct {
+	Count      int               `json:"count"`
+	Exceptions []AccessException `json:"exceptions,omitempty"`
+}
+
+// ExceptionRequestBody mirrors the ExceptionRequestBody schema
+type ExceptionRequestBody struct {
+	Action        string    `json:"action"`
+	Effect        string    `json:"effect"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ID            string    `json:"id,omitempty"`
+	Justification string    `json:"justification"`
+	ResourceID    string    `json:"resource_id"`
+	SubjectID     string    `json:"subject_id"`
+}
+
+// ExplainNode mirrors the ExplainNode schema
+type ExplainNode struct {
+	Actual   interface{}   `json:"actual,omitempty"`
+	Children []ExplainNode `json:"children,omitempty"`
+	Expected interface{}   `json:"expected,omitempty"`
+	Kind     string        `json:"kind,omitempty"`
+	Label    string        `json:"label,omitempty"`
+	Passed   bool          `json:"passed"`
+}
+
+// ExplainResponse mirrors the ExplainResponse schema
+type ExplainResponse struct {
+	Explanation *Explanation `json:"explanation,omitempty"`
+	RequestID   string       `json:"request_id,omitempty"`
+}
+
+// Explanation mirrors the Explanation schema
+type Explanation struct {
+	AsOf               *time.Time             `json:"as_of,omitempty"`
+	AttributeConflicts []AttributeConflict    `json:"attribute_conflicts,omitempty"`
+	AttributeSources   map[string]string      `json:"attribute_sources,omitempty"`
+	Decision           *Decision              `json:"decision,omitempty"`
+	Device             map[string]interface{} `json:"device,omitempty"`
+	Elevations         []string               `json:"elevations,omitempty"`
+	Relationships      map[string]bool        `json:"relationships,omitempty"`
+	Statements         []StatementTrace       `json:"statements,omitempty"`
+	Tree               *ExplainNode           `json:"tree,omitempty"`
+}
+
+// Failure mirrors the Failure schema
+type Failure struct {
+	Error     string `json:"error,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FetcherStatus mirrors the FetcherStatus schema
+type FetcherStatus struct {
+	BundleHash  string     `json:"bundle_hash,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Errors      int64      `json:"errors"`
+	Fetches     int64      `json:"fetches"`
+	KeyID       string     `json:"key_id,omitempty"`
+	LastApplied *time.Time `json:"last_applied,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastFetch   *time.Time `json:"last_fetch,omitempty"`
+	Updates     int64      `json:"updates"`
+	URL         string     `json:"url,omitempty"`
+}
+
+// FieldDirective mirrors the FieldDirective schema
+type FieldDirective struct {
+	Effect   string `json:"effect,omitempty"`
+	Field    string `json:"field,omitempty"`
+	PolicyID string `json:"policy_id,omitempty"`
+	Sid      string `json:"sid,omitempty"`
+}
+
+// Flip mirrors the Flip schema
+type Flip struct {
+	Action         string   `json:"action,omitempty"`
+	After          string   `json:"after,omitempty"`
+	AfterPolicies  []string `json:"after_policies,omitempty"`
+	Before         string   `json:"before,omitempty"`
+	BeforePolicies []string `json:"before_policies,omitempty"`
+	RequestID      string   `json:"request_id,omitempty"`
+	ResourceID     string   `json:"resource_id,omitempty"`
+	SubjectID      string   `json:"subject_id,omitempty"`
+}
+
+// ImportResponse mirrors the ImportResponse schema
+type ImportResponse struct {
+	Report *ImporterReport `json:"report,omitempty"`
+}
+
+// ImporterReport mirrors the ImporterReport schema
+type ImporterReport struct {
+	Errors   []RecordError `json:"errors,omitempty"`
+	Failed   int           `json:"failed"`
+	Imported int           `json:"imported"`
+	Kind     string        `json:"kind,omitempty"`
+}
+
+// IntegrityStatus mirrors the IntegrityStatus schema
+type IntegrityStatus struct {
+	BundleHash       string     `json:"bundle_hash,omitempty"`
+	KeyID            string     `json:"key_id,omitempty"`
+	LoadedAt         *time.Time `json:"loaded_at,omitempty"`
+	Policies         int        `json:"policies"`
+	RejectedPolicies []string   `json:"rejected_policies,omitempty"`
+	Rejections       int64      `json:"rejections"`
+}
+
+// InvalidateAttributeCacheRequestBody mirrors the InvalidateAttributeCacheRequestBody schema
+type InvalidateAttributeCacheRequestBody struct {
+	EntityType string `json:"entity_type,omitempty"`
+	ID         string `json:"id,omitempty"`
+}
+
+// InvalidateAttributeCacheResponse mirrors the InvalidateAttributeCacheResponse schema
+type InvalidateAttributeCacheResponse struct {
+	EntityType  string `json:"entity_type,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Invalidated bool   `json:"invalidated"`
+}
+
+// Lockdown mirrors the Lockdown schema
+type Lockdown struct {
+	Actor       string     `json:"actor,omitempty"`
+	Mode        string     `json:"mode,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	SafeActions []string   `json:"safe_actions,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+// LockdownRequestBody mirrors the LockdownRequestBody schema
+type LockdownRequestBody struct {
+	Mode        string   `json:"mode"`
+	Reason      string   `json:"reason,omitempty"`
+	SafeActions []string `json:"safe_actions,omitempty"`
+}
+
+// LockdownResponse mirrors the LockdownResponse schema
+type LockdownResponse struct {
+	Active   bool      `json:"active"`
+	Lifted   *Lockdown `json:"lifted,omitempty"`
+	Lockdown *Lockdown `json:"lockdown,omitempty"`
+}
+
+// MissingAttributeCount mirrors the MissingAttributeCount schema
+type MissingAttributeCount struct {
+	Count     int64      `json:"count"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+	Path      string     `json:"path,omitempty"`
+	PolicyID  string     `json:"policy_id,omitempty"`
+	Statement string     `json:"statement,omitempty"`
+}
+
+// MissingAttributesResponse mirrors the MissingAttributesResponse schema
+type MissingAttributesResponse struct {
+	Attributes []MissingAttributeCount `json:"attributes,omitempty"`
+	Enabled    bool                    `json:"enabled"`
+}
+
+// OwnershipTransferRequestBody mirrors the OwnershipTransferRequestBody schema
+type OwnershipTransferRequestBody struct {
+	AcceptAccessLoss bool                  `json:"accept_access_loss"`
+	Commit           bool                  `json:"commit"`
+	From             map[string]string     `json:"from,omitempty"`
+	Requests         []EvaluateRequestBody `json:"requests,omitempty"`
+	ResourceIds      []string              `json:"resource_ids,omitempty"`
+	SampleSize       int                   `json:"sample_size"`
+	To               map[string]string     `json:"to"`
+}
+
+// OwnershipTransferResponse mirrors the OwnershipTransferResponse schema
+type OwnershipTransferResponse struct {
+	Committed    bool              `json:"committed"`
+	LosingAccess []string          `json:"losing_access,omitempty"`
+	Report       *Report           `json:"report,omitempty"`
+	Resources    []string          `json:"resources,omitempty"`
+	Source       string            `json:"source,omitempty"`
+	To           map[string]string `json:"to,omitempty"`
+}
+
+// Policy mirrors the Policy schema
+type Policy struct {
+	CreatedAt      *time.Time        `json:"created_at,omitempty"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Effect         string            `json:"effect,omitempty"`
+	EffectiveFrom  *time.Time        `json:"effective_from,omitempty"`
+	Enabled        bool              `json:"enabled"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	Owner          string            `json:"owner,omitempty"`
+	PolicyName     string            `json:"policy_name,omitempty"`
+	Revision       int64             `json:"revision"`
+	RolloutPercent *int              `json:"rollout_percent,omitempty"`
+	Statement      []PolicyStatement `json:"statement,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	TenantID       string            `json:"tenant_id,omitempty"`
+	UpdatedAt      *time.Time        `json:"updated_at,omitempty"`
+	Version        string            `json:"version,omitempty"`
+}
+
+// PolicyChange mirrors the PolicyChange schema
+type PolicyChange struct {
+	Action    string         `json:"action,omitempty"`
+	Actor     string         `json:"actor,omitempty"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+	Diff      []PolicyDiffOp `json:"diff,omitempty"`
+	ID        int64          `json:"id"`
+	PolicyID  string         `json:"policy_id,omitempty"`
+	Revision  int64          `json:"revision"`
+}
+
+// PolicyChangesResponse mirrors the PolicyChangesResponse schema
+type PolicyChangesResponse struct {
+	Changes  []PolicyChange `json:"changes,omitempty"`
+	Count    int            `json:"count"`
+	PolicyID string         `json:"policy_id,omitempty"`
+}
+
+// PolicyCoverage mirrors the PolicyCoverage schema
+type PolicyCoverage struct {
+	Conditions  []ConditionCoverage `json:"conditions,omitempty"`
+	Evaluations int64               `json:"evaluations"`
+	PolicyID    string              `json:"policy_id,omitempty"`
+	Revision    int64               `json:"revision"`
+}
+
+// PolicyCoverageResponse mirrors the PolicyCoverageResponse schema
+type PolicyCoverageResponse struct {
+	Coverage *PolicyCoverage `json:"coverage,omitempty"`
+	Enabled  bool            `json:"enabled"`
+	PolicyID string          `json:"policy_id,omitempty"`
+}
+
+// PolicyDiffOp mirrors the PolicyDiffOp schema
+type PolicyDiffOp struct {
+	New  interface{} `json:"new,omitempty"`
+	Old  interface{} `json:"old,omitempty"`
+	Op   string      `json:"op,omitempty"`
+	Path string      `json:"path,omitempty"`
+}
+
+// PolicyFingerprint mirrors the PolicyFingerprint schema
+type PolicyFingerprint struct {
+	ComputedAt *time.Time `json:"computed_at,omitempty"`
+	Hash       string     `json:"hash,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Policies   int        `json:"policies"`
+}
+
+// PolicyImpactRequestBody mirrors the PolicyImpactRequestBody schema
+type PolicyImpactRequestBody struct {
+	Delete     []string              `json:"delete,omitempty"`
+	Policies   []interface{}         `json:"policies,omitempty"`
+	Requests   []EvaluateRequestBody `json:"requests,omitempty"`
+	SampleSize int                   `json:"sample_size"`
+}
+
+// PolicyImpactResponse mirrors the PolicyImpactResponse schema
+type PolicyImpactResponse struct {
+	Report *Report `json:"report,omitempty"`
+	Source string  `json:"source,omitempty"`
+}
+
+// PolicyListResponse mirrors the PolicyListResponse schema
+type PolicyListResponse struct {
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Policies   []Policy `json:"policies,omitempty"`
+	Total      int64    `json:"total"`
+}
+
+// PolicyQuarantineResponse mirrors the PolicyQuarantineResponse schema
+type PolicyQuarantineResponse struct {
+	Enabled  bool                `json:"enabled"`
+	Policies []QuarantinedPolicy `json:"policies,omitempty"`
+}
+
+// PolicyRegexError mirrors the PolicyRegexError schema
+type PolicyRegexError struct {
+	Key       string `json:"key,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Operator  string `json:"operator,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Sid       string `json:"sid,omitempty"`
+	Statement int    `json:"statement"`
+}
+
+// PolicyRegexReport mirrors the PolicyRegexReport schema
+type PolicyRegexReport struct {
+	Enabled  bool               `json:"enabled"`
+	Errors   []PolicyRegexError `json:"errors,omitempty"`
+	PolicyID string             `json:"policy_id,omitempty"`
+}
+
+// PolicyResponse mirrors the PolicyResponse schema
+type PolicyResponse struct {
+	Policy *Policy `json:"policy,omitempty"`
+}
+
+// PolicySchedule mirrors the PolicySchedule schema
+type PolicySchedule struct {
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	Cron        string     `json:"cron,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Enabled     bool       `json:"enabled"`
+	ID          string     `json:"id,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	PolicyIds   []string   `json:"policy_ids,omitempty"`
+	RunAt       *time.Time `json:"run_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+}
+
+// PolicyScheduleListResponse mirrors the PolicyScheduleListResponse schema
+type PolicyScheduleListResponse struct {
+	Count     int              `json:"count"`
+	Schedules []PolicySchedule `json:"schedules,omitempty"`
+}
+
+// PolicyScheduleRequestBody mirrors the PolicyScheduleRequestBody schema
+type PolicyScheduleRequestBody struct {
+	Cron        string     `json:"cron,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Enabled     bool       `json:"enabled"`
+	ID          string     `json:"id,omitempty"`
+	PolicyIds   []string   `json:"policy_ids,omitempty"`
+	RunAt       *time.Time `json:"run_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+}
+
+// PolicyStatement mirrors the PolicyStatement schema
+type PolicyStatement struct {
+	Action      interface{}            `json:"Action,omitempty"`
+	Condition   map[string]interface{} `json:"Condition,omitempty"`
+	Constraints *Constraints           `json:"Constraints,omitempty"`
+	Effect      string                 `json:"Effect,omitempty"`
+	Fields      interface{}            `json:"Fields,omitempty"`
+	NotResource interface{}            `json:"NotResource,omitempty"`
+	Obligations map[string]string      `json:"Obligations,omitempty"`
+	Resource    interface{}            `json:"Resource,omitempty"`
+	Sid         string                 `json:"Sid,omitempty"`
+}
+
+// PolicyStats mirrors the PolicyStats schema
+type PolicyStats struct {
+	AvgConditionTimeUs   float64          `json:"avg_condition_time_us"`
+	ConditionEvaluations int64            `json:"condition_evaluations"`
+	Denies               int64            `json:"denies"`
+	Evaluations          int64            `json:"evaluations"`
+	LastMatchedAt        *time.Time       `json:"last_matched_at,omitempty"`
+	Matches              int64            `json:"matches"`
+	PolicyID             string           `json:"policy_id,omitempty"`
+	Statements           []StatementStats `json:"statements,omitempty"`
+}
+
+// PolicyStatsResponse mirrors the PolicyStatsResponse schema
+type PolicyStatsResponse struct {
+	PolicyID string       `json:"policy_id,omitempty"`
+	Stats    *PolicyStats `json:"stats,omitempty"`
+}
+
+// PolicyToggleRequestBody mirrors the PolicyToggleRequestBody schema
+type PolicyToggleRequestBody struct {
+	Enabled   bool     `json:"enabled"`
+	PolicyIds []string `json:"policy_ids,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// PolicyToggleResponse mirrors the PolicyToggleResponse schema
+type PolicyToggleResponse struct {
+	Changed []string `json:"changed,omitempty"`
+	Enabled bool     `json:"enabled"`
+	Matched []string `json:"matched,omitempty"`
+}
+
+// PrepareSessionRequestBody mirrors the PrepareSessionRequestBody schema
+type PrepareSessionRequestBody struct {
+	SessionID string `json:"session_id"`
+	SubjectID string `json:"subject_id"`
+}
+
+// PreparedSubject mirrors the PreparedSubject schema
+type PreparedSubject struct {
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	PreparedAt  *time.Time             `json:"prepared_at,omitempty"`
+	SessionID   string                 `json:"session_id,omitempty"`
+	SubjectID   string                 `json:"subject_id,omitempty"`
+	SubjectType string                 `json:"subject_type,omitempty"`
+}
+
+// QuarantinedPolicy mirrors the QuarantinedPolicy schema
+type QuarantinedPolicy struct {
+	LastEvaluationMs float64    `json:"last_evaluation_ms"`
+	PolicyID         string     `json:"policy_id,omitempty"`
+	QuarantinedAt    *time.Time `json:"quarantined_at,omitempty"`
+	ReleaseAt        *time.Time `json:"release_at,omitempty"`
+	Revision         int64      `json:"revision"`
+	SlowEvaluations  int        `json:"slow_evaluations"`
+}
+
+// RecordError mirrors the RecordError schema
+type RecordError struct {
+	Error string `json:"error,omitempty"`
+	ID    string `json:"id,omitempty"`
+	Line  int    `json:"line"`
+}
+
+// RegexReportResponse mirrors the RegexReportResponse schema
+type RegexReportResponse struct {
+	Checked  int                 `json:"checked"`
+	Policies []PolicyRegexReport `json:"policies,omitempty"`
+}
+
+// Report mirrors the Report schema
+type Report struct {
+	DenyToPermit int       `json:"deny_to_permit"`
+	Evaluated    int       `json:"evaluated"`
+	Failures     []Failure `json:"failures,omitempty"`
+	Flips        []Flip    `json:"flips,omitempty"`
+	PermitToDeny int       `json:"permit_to_deny"`
+	Unchanged    int       `json:"unchanged"`
+}
+
+// Resource mirrors the Resource schema
+type Resource struct {
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt    *time.Time             `json:"created_at,omitempty"`
+	DeletedAt    *time.Time             `json:"deleted_at,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	ParentID     string                 `json:"parent_id,omitempty"`
+	Path         string                 `json:"path,omitempty"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	ResourceType string                 `json:"resource_type,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	TenantID     string                 `json:"tenant_id,omitempty"`
+}
+
+// ResourceListResponse mirrors the ResourceListResponse schema
+type ResourceListResponse struct {
+	Count      int        `json:"count"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Resources  []Resource `json:"resources,omitempty"`
+	Total      int64      `json:"total"`
+}
+
+// ResourceSearchResponse mirrors the ResourceSearchResponse schema
+type ResourceSearchResponse struct {
+	Count     int         `json:"count"`
+	Key       string      `json:"key,omitempty"`
+	Resources []Resource  `json:"resources,omitempty"`
+	Tag       string      `json:"tag,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// Revocation mirrors the Revocation schema
+type Revocation struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	SessionID string     `json:"session_id,omitempty"`
+	SubjectID string     `json:"subject_id,omitempty"`
+}
+
+// RevocationListResponse mirrors the RevocationListResponse schema
+type RevocationListResponse struct {
+	Count       int          `json:"count"`
+	Revocations []Revocation `json:"revocations,omitempty"`
+}
+
+// RevocationRequestBody mirrors the RevocationRequestBody schema
+type RevocationRequestBody struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Reason    string     `json:"reason"`
+	SessionID string     `json:"session_id,omitempty"`
+	SubjectID string     `json:"subject_id,omitempty"`
+}
+
+// SessionInfo mirrors the SessionInfo schema
+type SessionInfo struct {
+	AuthMethod  string     `json:"auth_method,omitempty"`
+	AuthTime    *time.Time `json:"auth_time,omitempty"`
+	MfaVerified bool       `json:"mfa_verified"`
+	SessionID   string     `json:"session_id,omitempty"`
+}
+
+// ShadowDecision mirrors the ShadowDecision schema
+type ShadowDecision struct {
+	CanaryPolicies  []string `json:"canary_policies,omitempty"`
+	MatchedPolicies []string `json:"matched_policies,omitempty"`
+	Result          string   `json:"result,omitempty"`
+}
+
+// StatementStats mirrors the StatementStats schema
+type StatementStats struct {
+	AvgConditionTimeUs   float64 `json:"avg_condition_time_us"`
+	ConditionEvaluations int64   `json:"condition_evaluations"`
+	Denies               int64   `json:"denies"`
+	Evaluations          int64   `json:"evaluations"`
+	Matches              int64   `json:"matches"`
+	Sid                  string  `json:"sid,omitempty"`
+}
+
+// StatementTrace mirrors the StatementTrace schema
+type StatementTrace struct {
+	ActionMatched     bool             `json:"action_matched"`
+	Conditions        []ConditionTrace `json:"conditions,omitempty"`
+	ConditionsMatched bool             `json:"conditions_matched"`
+	Effect            string           `json:"effect,omitempty"`
+	Matched           bool             `json:"matched"`
+	PolicyID          string           `json:"policy_id,omitempty"`
+	ResourceMatched   bool             `json:"resource_matched"`
+	Sid               string           `json:"sid,omitempty"`
+}
+
+// Subject mirrors the Subject schema
+type Subject struct {
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt   *time.Time             `json:"created_at,omitempty"`
+	DeletedAt   *time.Time             `json:"deleted_at,omitempty"`
+	ExternalID  string                 `json:"external_id,omitempty"`
+	ID          string                 `json:"id,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	SubjectType string                 `json:"subject_type,omitempty"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
+	UpdatedAt   *time.Time             `json:"updated_at,omitempty"`
+}
+
+// SubjectDecisionSummary mirrors the SubjectDecisionSummary schema
+type SubjectDecisionSummary struct {
+	Denies             int              `json:"denies"`
+	DenyRate           float64          `json:"deny_rate"`
+	Permits            int              `json:"permits"`
+	TopDeniedResources []DeniedResource `json:"top_denied_resources,omitempty"`
+	Total              int              `json:"total"`
+}
+
+// SubjectDecisionsResponse mirrors the SubjectDecisionsResponse schema
+type SubjectDecisionsResponse struct {
+	Decisions []AuditLog              `json:"decisions,omitempty"`
+	Since     *time.Time              `json:"since,omitempty"`
+	SubjectID string                  `json:"subject_id,omitempty"`
+	Summary   *SubjectDecisionSummary `json:"summary,omitempty"`
+}
+
+// SubjectListResponse mirrors the SubjectListResponse schema
+type SubjectListResponse struct {
+	Count      int       `json:"count"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Subjects   []Subject `json:"subjects,omitempty"`
+	Total      int64     `json:"total"`
+}
+
+// SubjectSearchResponse mirrors the SubjectSearchResponse schema
+type SubjectSearchResponse struct {
+	Count    int         `json:"count"`
+	Key      string      `json:"key,omitempty"`
+	Subjects []Subject   `json:"subjects,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// SubjectTypeDefinition mirrors the SubjectTypeDefinition schema
+type SubjectTypeDefinition struct {
+	Description        string   `json:"description,omitempty"`
+	RequiredAttributes []string `json:"required_attributes,omitempty"`
+	Type               string   `json:"type,omitempty"`
+}
+
+// SubjectTypesResponse mirrors the SubjectTypesResponse schema
+type SubjectTypesResponse struct {
+	Enforced bool                    `json:"enforced"`
+	Enums    map[string][]string     `json:"enums,omitempty"`
+	Types    []SubjectTypeDefinition `json:"types,omitempty"`
+}
+
+// WarmUpStats mirrors the WarmUpStats schema
+type WarmUpStats struct {
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DurationMs  float64    `json:"duration_ms"`
+	Policies    int        `json:"policies"`
+}
+
+// ListActionsParams holds the optional parameters of ListActions
+type ListActionsParams struct {
+	// Page size
+	Limit int
+	// Items to skip, instead of a cursor
+	Offset int
+	// next_cursor of the previous page
+	Cursor string
+	// Filter by type
+	Type string
+	// Field name, "-" prefix for descending (e.g. "-updated_at")
+	Sort string
+	// Filter policies by enabled flag
+	Enabled *bool
+	// RFC 3339 timestamp
+	UpdatedSince string
+	// Filter policies by tag (e.g. "pci" or "domain=pci")
+	Tag string
+	// Filter policies by owning team
+	Owner string
+}
+
+func (p *ListActionsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset != 0 {
+		values.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+	if p.Type != "" {
+		values.Set("type", p.Type)
+	}
+	if p.Sort != "" {
+		values.Set("sort", p.Sort)
+	}
+	if p.Enabled != nil {
+		values.Set("enabled", strconv.FormatBool(*p.Enabled))
+	}
+	if p.UpdatedSince != "" {
+		values.Set("updated_since", p.UpdatedSince)
+	}
+	if p.Tag != "" {
+		values.Set("tag", p.Tag)
+	}
+	if p.Owner != "" {
+		values.Set("owner", p.Owner)
+	}
+	return values
+}
+
+// ListActions calls GET /api/v1/actions: Page of actions
+// The caller must be permitted "admin".
+func (c *Client) ListActions(ctx context.Context, params *ListActionsParams) (*ActionListResponse, error) {
+	var out ActionListResponse
+	if err := c.do(ctx, "GET", "/api/v1/actions", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAdminPanel calls GET /api/v1/admin: Admin panel
+// The caller must be permitted "admin".
+func (c *Client) GetAdminPanel(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", "/api/v1/admin", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvalidateAttributeCache calls POST /api/v1/attribute-cache/invalidate: Drop cached subject, resource or action lookups
+// The caller must be permitted "admin".
+func (c *Client) InvalidateAttributeCache(ctx context.Context, body *InvalidateAttributeCacheRequestBody) (*InvalidateAttributeCacheResponse, error) {
+	var out InvalidateAttributeCacheResponse
+	if err := c.do(ctx, "POST", "/api/v1/attribute-cache/invalidate", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAttributeCacheStats calls GET /api/v1/attribute-cache/stats: Attribute cache counters
+// The caller must be permitted "admin".
+func (c *Client) GetAttributeCacheStats(ctx context.Context) (*AttributeCacheStatsResponse, error) {
+	var out AttributeCacheStatsResponse
+	if err := c.do(ctx, "GET", "/api/v1/attribute-cache/stats", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMissingAttributes calls GET /api/v1/attributes/missing: Condition attribute paths that resolved to nothing, by policy and statement
+// The caller must be permitted "admin".
+func (c *Client) GetMissingAttributes(ctx context.Context) (*MissingAttributesResponse, error) {
+	var out MissingAttributesResponse
+	if err := c.do(ctx, "GET", "/api/v1/attributes/missing", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportBundle calls GET /api/v1/bundles/export: Export enabled policies as a signed bundle
+// The caller must be permitted "admin".
+func (c *Client) ExportBundle(ctx context.Context) (*Bundle, error) {
+	var out Bundle
+	if err := c.do(ctx, "GET", "/api/v1/bundles/export", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportBundle calls POST /api/v1/bundles/import: Verify and load a signed policy bundle
+// The caller must be permitted "admin".
+func (c *Client) ImportBundle(ctx context.Context, body *Bundle) (*BundleImportResponse, error) {
+	var out BundleImportResponse
+	if err := c.do(ctx, "POST", "/api/v1/bundles/import", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBundleStatus calls GET /api/v1/bundles/status: Trusted bundle and rejected policies
+// The caller must be permitted "admin".
+func (c *Client) GetBundleStatus(ctx context.Context) (*BundleStatusResponse, error) {
+	var out BundleStatusResponse
+	if err := c.do(ctx, "GET", "/api/v1/bundles/status", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCompileStats calls GET /api/v1/compile/stats: Policy compile mode, durations and last warm-up
+// The caller must be permitted "admin".
+func (c *Client) GetCompileStats(ctx context.Context) (*CompileStats, error) {
+	var out CompileStats
+	if err := c.do(ctx, "GET", "/api/v1/compile/stats", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StreamDecisionsParams holds the optional parameters of StreamDecisions
+type StreamDecisionsParams struct {
+	// Subject ID filter, supports '*'
+	Subject string
+	// Resource ID filter, supports '*'
+	Resource string
+	// permit or deny
+	Result string
+}
+
+func (p *StreamDecisionsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Subject != "" {
+		values.Set("subject", p.Subject)
+	}
+	if p.Resource != "" {
+		values.Set("resource", p.Resource)
+	}
+	if p.Result != "" {
+		values.Set("result", p.Result)
+	}
+	return values
+}
+
+// StreamDecisions calls GET /api/v1/decisions/stream: Live decision events as Server-Sent Events
+// The caller must be permitted "admin".
+// The caller must close the returned body.
+func (c *Client) StreamDecisions(ctx context.Context, params *StreamDecisionsParams) (io.ReadCloser, error) {
+	return c.stream(ctx, "GET", "/api/v1/decisions/stream", params.values())
+}
+
+// GetDenyCacheStats calls GET /api/v1/deny-cache/stats: Negative cache counters
+// The caller must be permitted "admin".
+func (c *Client) GetDenyCacheStats(ctx context.Context) (*DenyCacheStatsResponse, error) {
+	var out DenyCacheStatsResponse
+	if err := c.do(ctx, "GET", "/api/v1/deny-cache/stats", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListElevationsParams holds the optional parameters of ListElevations
+type ListElevationsParams struct {
+	// Only elevations of this subject
+	SubjectID string
+}
+
+func (p *ListElevationsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.SubjectID != "" {
+		values.Set("subject_id", p.SubjectID)
+	}
+	return values
+}
+
+// ListElevations calls GET /api/v1/elevations: Time-limited role and attribute elevations
+// The caller must be permitted "admin".
+func (c *Client) ListElevations(ctx context.Context, params *ListElevationsParams) (*ElevationListResponse, error) {
+	var out ElevationListResponse
+	if err := c.do(ctx, "GET", "/api/v1/elevations", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateElevation calls POST /api/v1/elevations: Grant a subject roles and attributes until expires_at
+// The caller must be permitted "admin".
+func (c *Client) CreateElevation(ctx context.Context, body *ElevationRequestBody) (*Elevation, error) {
+	var out Elevation
+	if err := c.do(ctx, "POST", "/api/v1/elevations", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteElevation calls DELETE /api/v1/elevations/{id}: End an elevation
+// The caller must be permitted "admin".
+func (c *Client) DeleteElevation(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/elevations/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// Evaluate calls POST /api/v1/evaluate: Evaluate a request
+func (c *Client) Evaluate(ctx context.Context, body *EvaluateRequestBody) (*EvaluateResponse, error) {
+	var out EvaluateResponse
+	if err := c.do(ctx, "POST", "/api/v1/evaluate", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PrepareEvaluationSession calls POST /api/v1/evaluate/sessions: Prepare the subject context of an evaluation session
+// The caller must be permitted "pdp:session".
+func (c *Client) PrepareEvaluationSession(ctx context.Context, body *PrepareSessionRequestBody) (*PreparedSubject, error) {
+	var out PreparedSubject
+	if err := c.do(ctx, "POST", "/api/v1/evaluate/sessions", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReleaseEvaluationSession calls DELETE /api/v1/evaluate/sessions/{id}: Release the prepared subject context of a session
+// The caller must be permitted "pdp:session".
+func (c *Client) ReleaseEvaluationSession(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/evaluate/sessions/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// ListExceptionsParams holds the optional parameters of ListExceptions
+type ListExceptionsParams struct {
+	// Only exceptions of this subject
+	SubjectID string
+}
+
+func (p *ListExceptionsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.SubjectID != "" {
+		values.Set("subject_id", p.SubjectID)
+	}
+	return values
+}
+
+// ListExceptions calls GET /api/v1/exceptions: Access exceptions
+// The caller must be permitted "admin".
+func (c *Client) ListExceptions(ctx context.Context, params *ListExceptionsParams) (*ExceptionListResponse, error) {
+	var out ExceptionListResponse
+	if err := c.do(ctx, "GET", "/api/v1/exceptions", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateException calls POST /api/v1/exceptions: Allow or deny one subject/resource/action until expires_at
+// The caller must be permitted "admin".
+func (c *Client) CreateException(ctx context.Context, body *ExceptionRequestBody) (*AccessException, error) {
+	var out AccessException
+	if err := c.do(ctx, "POST", "/api/v1/exceptions", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteException calls DELETE /api/v1/exceptions/{id}: Revoke an access exception
+// The caller must be permitted "admin".
+func (c *Client) DeleteException(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/exceptions/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// Explain calls POST /api/v1/explain: Evaluate a request and explain statement matching
+// The caller must be permitted "pdp:explain".
+func (c *Client) Explain(ctx context.Context, body *EvaluateRequestBody) (*ExplainResponse, error) {
+	var out ExplainResponse
+	if err := c.do(ctx, "POST", "/api/v1/explain", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFinancialData calls GET /api/v1/financial: Financial data
+// The caller must be permitted "read".
+func (c *Client) GetFinancialData(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", "/api/v1/financial", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImportRecords calls POST /api/v1/import/{kind}: NDJSON bulk import of subjects, resources or policies
+// The caller must be permitted "admin".
+func (c *Client) ImportRecords(ctx context.Context, kind string, body io.Reader) (*ImportResponse, error) {
+	var out ImportResponse
+	if err := c.do(ctx, "POST", "/api/v1/import/"+url.PathEscape(kind), nil, nil, "application/x-ndjson", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LiftLockdown calls DELETE /api/v1/lockdown: Return to normal evaluation
+// The caller must be permitted "lockdown:manage".
+func (c *Client) LiftLockdown(ctx context.Context) (*LockdownResponse, error) {
+	var out LockdownResponse
+	if err := c.do(ctx, "DELETE", "/api/v1/lockdown", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLockdown calls GET /api/v1/lockdown: Whether a lockdown is active
+// The caller must be permitted "lockdown:manage".
+func (c *Client) GetLockdown(ctx context.Context) (*LockdownResponse, error) {
+	var out LockdownResponse
+	if err := c.do(ctx, "GET", "/api/v1/lockdown", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetLockdown calls PUT /api/v1/lockdown: Enable the deny-all / safelist kill switch
+// The caller must be permitted "lockdown:manage".
+func (c *Client) SetLockdown(ctx context.Context, body *LockdownRequestBody) (*LockdownResponse, error) {
+	var out LockdownResponse
+	if err := c.do(ctx, "PUT", "/api/v1/lockdown", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPoliciesParams holds the optional parameters of ListPolicies
+type ListPoliciesParams struct {
+	// Page size
+	Limit int
+	// Items to skip, instead of a cursor
+	Offset int
+	// next_cursor of the previous page
+	Cursor string
+	// Filter by type
+	Type string
+	// Field name, "-" prefix for descending (e.g. "-updated_at")
+	Sort string
+	// Filter policies by enabled flag
+	Enabled *bool
+	// RFC 3339 timestamp
+	UpdatedSince string
+	// Filter policies by tag (e.g. "pci" or "domain=pci")
+	Tag string
+	// Filter policies by owning team
+	Owner string
+}
+
+func (p *ListPoliciesParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset != 0 {
+		values.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+	if p.Type != "" {
+		values.Set("type", p.Type)
+	}
+	if p.Sort != "" {
+		values.Set("sort", p.Sort)
+	}
+	if p.Enabled != nil {
+		values.Set("enabled", strconv.FormatBool(*p.Enabled))
+	}
+	if p.UpdatedSince != "" {
+		values.Set("updated_since", p.UpdatedSince)
+	}
+	if p.Tag != "" {
+		values.Set("tag", p.Tag)
+	}
+	if p.Owner != "" {
+		values.Set("owner", p.Owner)
+	}
+	return values
+}
+
+// ListPolicies calls GET /api/v1/policies: Page of policies, including disabled ones
+// The caller must be permitted "admin".
+func (c *Client) ListPolicies(ctx context.Context, params *ListPoliciesParams) (*PolicyListResponse, error) {
+	var out PolicyListResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePolicy calls POST /api/v1/policies: Create a schema-validated policy
+// The caller must be permitted "admin".
+func (c *Client) CreatePolicy(ctx context.Context, body *Policy) (*PolicyResponse, error) {
+	var out PolicyResponse
+	if err := c.do(ctx, "POST", "/api/v1/policies", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TogglePolicies calls POST /api/v1/policies/bulk-toggle: Enable or disable the policies selected by ID or tag
+// The caller must be permitted "admin".
+func (c *Client) TogglePolicies(ctx context.Context, body *PolicyToggleRequestBody) (*PolicyToggleResponse, error) {
+	var out PolicyToggleResponse
+	if err := c.do(ctx, "POST", "/api/v1/policies/bulk-toggle", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListDeletedPolicies calls GET /api/v1/policies/deleted: Soft-deleted policies, most recently deleted first
+// The caller must be permitted "admin".
+func (c *Client) ListDeletedPolicies(ctx context.Context) (*DeletedPoliciesResponse, error) {
+	var out DeletedPoliciesResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/deleted", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyFingerprintParams holds the optional parameters of GetPolicyFingerprint
+type GetPolicyFingerprintParams struct {
+	// Evaluation namespace, default the PDP namespace
+	Namespace string
+	// ETag of a previously fetched fingerprint
+	IfNoneMatch string
+}
+
+func (p *GetPolicyFingerprintParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Namespace != "" {
+		values.Set("namespace", p.Namespace)
+	}
+	return values
+}
+
+func (p *GetPolicyFingerprintParams) header() http.Header {
+	values := http.Header{}
+	if p == nil {
+		return values
+	}
+	if p.IfNoneMatch != "" {
+		values.Set("If-None-Match", p.IfNoneMatch)
+	}
+	return values
+}
+
+// GetPolicyFingerprint calls GET /api/v1/policies/fingerprint: Hash of the evaluated policy set for PEP cache synchronization
+func (c *Client) GetPolicyFingerprint(ctx context.Context, params *GetPolicyFingerprintParams) (*PolicyFingerprint, error) {
+	var out PolicyFingerprint
+	if err := c.do(ctx, "GET", "/api/v1/policies/fingerprint", params.values(), params.header(), "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PolicyImpact calls POST /api/v1/policies/impact: Decision flips of a proposed policy change
+// The caller must be permitted "admin".
+func (c *Client) PolicyImpact(ctx context.Context, body *PolicyImpactRequestBody) (*PolicyImpactResponse, error) {
+	var out PolicyImpactResponse
+	if err := c.do(ctx, "POST", "/api/v1/policies/impact", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListQuarantinedPolicies calls GET /api/v1/policies/quarantine: Policies skipped by evaluation because they were consistently slow
+// The caller must be permitted "admin".
+func (c *Client) ListQuarantinedPolicies(ctx context.Context) (*PolicyQuarantineResponse, error) {
+	var out PolicyQuarantineResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/quarantine", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyRegexReport calls GET /api/v1/policies/regex-report: StringRegex patterns of stored policies that do not compile or exceed the regex limits
+// The caller must be permitted "admin".
+func (c *Client) GetPolicyRegexReport(ctx context.Context) (*RegexReportResponse, error) {
+	var out RegexReportResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/regex-report", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePolicy calls DELETE /api/v1/policies/{id}: Delete a policy
+// The caller must be permitted "admin".
+func (c *Client) DeletePolicy(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/policies/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// GetPolicy calls GET /api/v1/policies/{id}: Get a policy; its revision is sent as ETag
+// The caller must be permitted "admin".
+func (c *Client) GetPolicy(ctx context.Context, id string) (*PolicyResponse, error) {
+	var out PolicyResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/"+url.PathEscape(id), nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePolicyParams holds the optional parameters of UpdatePolicy
+type UpdatePolicyParams struct {
+	// ETag of the revision being replaced
+	IfMatch string
+}
+
+func (p *UpdatePolicyParams) header() http.Header {
+	values := http.Header{}
+	if p == nil {
+		return values
+	}
+	if p.IfMatch != "" {
+		values.Set("If-Match", p.IfMatch)
+	}
+	return values
+}
+
+// UpdatePolicy calls PUT /api/v1/policies/{id}: Replace a policy
+// The caller must be permitted "admin".
+func (c *Client) UpdatePolicy(ctx context.Context, id string, params *UpdatePolicyParams, body *Policy) (*PolicyResponse, error) {
+	var out PolicyResponse
+	if err := c.do(ctx, "PUT", "/api/v1/policies/"+url.PathEscape(id), nil, params.header(), "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPolicyChangesParams holds the optional parameters of ListPolicyChanges
+type ListPolicyChangesParams struct {
+	// Maximum changes, default 100
+	Limit int
+}
+
+func (p *ListPolicyChangesParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return values
+}
+
+// ListPolicyChanges calls GET /api/v1/policies/{id}/changes: Policy change audit trail with diffs, newest first
+// The caller must be permitted "admin".
+func (c *Client) ListPolicyChanges(ctx context.Context, id string, params *ListPolicyChangesParams) (*PolicyChangesResponse, error) {
+	var out PolicyChangesResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/"+url.PathEscape(id)+"/changes", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyCoverage calls GET /api/v1/policies/{id}/coverage: Condition operators and attribute keys exercised by live evaluations
+// The caller must be permitted "admin".
+func (c *Client) GetPolicyCoverage(ctx context.Context, id string) (*PolicyCoverageResponse, error) {
+	var out PolicyCoverageResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/"+url.PathEscape(id)+"/coverage", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReleasePolicyQuarantine calls DELETE /api/v1/policies/{id}/quarantine: Evaluate a quarantined policy again
+// The caller must be permitted "admin".
+func (c *Client) ReleasePolicyQuarantine(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/policies/"+url.PathEscape(id)+"/quarantine", nil, nil, "", nil, nil)
+}
+
+// RestorePolicy calls POST /api/v1/policies/{id}/restore: Restore a soft-deleted policy
+// The caller must be permitted "admin".
+func (c *Client) RestorePolicy(ctx context.Context, id string) (*PolicyResponse, error) {
+	var out PolicyResponse
+	if err := c.do(ctx, "POST", "/api/v1/policies/"+url.PathEscape(id)+"/restore", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPolicyStats calls GET /api/v1/policies/{id}/stats: Policy evaluation statistics
+// The caller must be permitted "admin".
+func (c *Client) GetPolicyStats(ctx context.Context, id string) (*PolicyStatsResponse, error) {
+	var out PolicyStatsResponse
+	if err := c.do(ctx, "GET", "/api/v1/policies/"+url.PathEscape(id)+"/stats", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPolicySchedules calls GET /api/v1/policy-schedules: Scheduled bulk policy enables and disables
+// The caller must be permitted "admin".
+func (c *Client) ListPolicySchedules(ctx context.Context) (*PolicyScheduleListResponse, error) {
+	var out PolicyScheduleListResponse
+	if err := c.do(ctx, "GET", "/api/v1/policy-schedules", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePolicySchedule calls POST /api/v1/policy-schedules: Enable or disable selected policies at run_at or whenever cron fires
+// The caller must be permitted "admin".
+func (c *Client) CreatePolicySchedule(ctx context.Context, body *PolicyScheduleRequestBody) (*PolicySchedule, error) {
+	var out PolicySchedule
+	if err := c.do(ctx, "POST", "/api/v1/policy-schedules", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePolicySchedule calls DELETE /api/v1/policy-schedules/{id}: Cancel a policy schedule
+// The caller must be permitted "admin".
+func (c *Client) DeletePolicySchedule(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/policy-schedules/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// ListResourcesParams holds the optional parameters of ListResources
+type ListResourcesParams struct {
+	// Page size
+	Limit int
+	// Items to skip, instead of a cursor
+	Offset int
+	// next_cursor of the previous page
+	Cursor string
+	// Filter by type
+	Type string
+	// Field name, "-" prefix for descending (e.g. "-updated_at")
+	Sort string
+	// Filter policies by enabled flag
+	Enabled *bool
+	// RFC 3339 timestamp
+	UpdatedSince string
+	// Filter policies by tag (e.g. "pci" or "domain=pci")
+	Tag string
+	// Filter policies by owning team
+	Owner string
+}
+
+func (p *ListResourcesParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset != 0 {
+		values.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+	if p.Type != "" {
+		values.Set("type", p.Type)
+	}
+	if p.Sort != "" {
+		values.Set("sort", p.Sort)
+	}
+	if p.Enabled != nil {
+		values.Set("enabled", strconv.FormatBool(*p.Enabled))
+	}
+	if p.UpdatedSince != "" {
+		values.Set("updated_since", p.UpdatedSince)
+	}
+	if p.Tag != "" {
+		values.Set("tag", p.Tag)
+	}
+	if p.Owner != "" {
+		values.Set("owner", p.Owner)
+	}
+	return values
+}
+
+// ListResources calls GET /api/v1/resources: Page of resources
+// The caller must be permitted "admin".
+func (c *Client) ListResources(ctx context.Context, params *ListResourcesParams) (*ResourceListResponse, error) {
+	var out ResourceListResponse
+	if err := c.do(ctx, "GET", "/api/v1/resources", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchResourcesParams holds the optional parameters of SearchResources
+type SearchResourcesParams struct {
+	// Attribute name
+	Key string
+	// Attribute value, parsed as a JSON literal when possible
+	Value string
+	// Resource tag, instead of key and value
+	Tag string
+}
+
+func (p *SearchResourcesParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Key != "" {
+		values.Set("key", p.Key)
+	}
+	if p.Value != "" {
+		values.Set("value", p.Value)
+	}
+	if p.Tag != "" {
+		values.Set("tag", p.Tag)
+	}
+	return values
+}
+
+// SearchResources calls GET /api/v1/resources/search: Resources by attribute or tag
+// The caller must be permitted "admin".
+func (c *Client) SearchResources(ctx context.Context, params *SearchResourcesParams) (*ResourceSearchResponse, error) {
+	var out ResourceSearchResponse
+	if err := c.do(ctx, "GET", "/api/v1/resources/search", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TransferResourceOwnership calls POST /api/v1/resources/transfer-ownership: Preview or commit a bulk resource ownership transfer with its access impact
+// The caller must be permitted "admin".
+func (c *Client) TransferResourceOwnership(ctx context.Context, body *OwnershipTransferRequestBody) (*OwnershipTransferResponse, error) {
+	var out OwnershipTransferResponse
+	if err := c.do(ctx, "POST", "/api/v1/resources/transfer-ownership", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRevocations calls GET /api/v1/revocations: Revoked subjects and sessions
+// The caller must be permitted "admin".
+func (c *Client) ListRevocations(ctx context.Context) (*RevocationListResponse, error) {
+	var out RevocationListResponse
+	if err := c.do(ctx, "GET", "/api/v1/revocations", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRevocation calls POST /api/v1/revocations: Suspend all access of a subject or session
+// The caller must be permitted "admin".
+func (c *Client) CreateRevocation(ctx context.Context, body *RevocationRequestBody) (*Revocation, error) {
+	var out Revocation
+	if err := c.do(ctx, "POST", "/api/v1/revocations", nil, nil, "", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRevocation calls DELETE /api/v1/revocations/{id}: Lift a revocation
+// The caller must be permitted "admin".
+func (c *Client) DeleteRevocation(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v1/revocations/"+url.PathEscape(id), nil, nil, "", nil, nil)
+}
+
+// GetPolicySchema calls GET /api/v1/schema/policy: Policy document JSON Schema
+// The caller must close the returned body.
+func (c *Client) GetPolicySchema(ctx context.Context) (io.ReadCloser, error) {
+	return c.stream(ctx, "GET", "/api/v1/schema/policy", nil)
+}
+
+// ListSubjectTypes calls GET /api/v1/subject-types: Registered subject types and the user.subject_type enum
+func (c *Client) ListSubjectTypes(ctx context.Context) (*SubjectTypesResponse, error) {
+	var out SubjectTypesResponse
+	if err := c.do(ctx, "GET", "/api/v1/subject-types", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSubjectsParams holds the optional parameters of ListSubjects
+type ListSubjectsParams struct {
+	// Page size
+	Limit int
+	// Items to skip, instead of a cursor
+	Offset int
+	// next_cursor of the previous page
+	Cursor string
+	// Filter by type
+	Type string
+	// Field name, "-" prefix for descending (e.g. "-updated_at")
+	Sort string
+	// Filter policies by enabled flag
+	Enabled *bool
+	// RFC 3339 timestamp
+	UpdatedSince string
+	// Filter policies by tag (e.g. "pci" or "domain=pci")
+	Tag string
+	// Filter policies by owning team
+	Owner string
+}
+
+func (p *ListSubjectsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset != 0 {
+		values.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+	if p.Type != "" {
+		values.Set("type", p.Type)
+	}
+	if p.Sort != "" {
+		values.Set("sort", p.Sort)
+	}
+	if p.Enabled != nil {
+		values.Set("enabled", strconv.FormatBool(*p.Enabled))
+	}
+	if p.UpdatedSince != "" {
+		values.Set("updated_since", p.UpdatedSince)
+	}
+	if p.Tag != "" {
+		values.Set("tag", p.Tag)
+	}
+	if p.Owner != "" {
+		values.Set("owner", p.Owner)
+	}
+	return values
+}
+
+// ListSubjects calls GET /api/v1/subjects: Page of subjects
+// The caller must be permitted "admin".
+func (c *Client) ListSubjects(ctx context.Context, params *ListSubjectsParams) (*SubjectListResponse, error) {
+	var out SubjectListResponse
+	if err := c.do(ctx, "GET", "/api/v1/subjects", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchSubjectsParams holds the optional parameters of SearchSubjects
+type SearchSubjectsParams struct {
+	// Attribute name
+	Key string
+	// Attribute value, parsed as a JSON literal when possible
+	Value string
+}
+
+func (p *SearchSubjectsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Key != "" {
+		values.Set("key", p.Key)
+	}
+	if p.Value != "" {
+		values.Set("value", p.Value)
+	}
+	return values
+}
+
+// SearchSubjects calls GET /api/v1/subjects/search: Subjects by attribute
+// The caller must be permitted "admin".
+func (c *Client) SearchSubjects(ctx context.Context, params *SearchSubjectsParams) (*SubjectSearchResponse, error) {
+	var out SubjectSearchResponse
+	if err := c.do(ctx, "GET", "/api/v1/subjects/search", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSubjectDecisionsParams holds the optional parameters of ListSubjectDecisions
+type ListSubjectDecisionsParams struct {
+	// RFC 3339 start, default 7 days ago
+	Since string
+	// Maximum decisions, default 100, at most 1000
+	Limit int
+	// Top denied resources, default 5
+	Top int
+}
+
+func (p *ListSubjectDecisionsParams) values() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+	if p.Since != "" {
+		values.Set("since", p.Since)
+	}
+	if p.Limit != 0 {
+		values.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Top != 0 {
+		values.Set("top", strconv.Itoa(p.Top))
+	}
+	return values
+}
+
+// ListSubjectDecisions calls GET /api/v1/subjects/{id}/decisions: Recent decisions of a subject with deny rate and top denied resources
+// The caller must be permitted "admin".
+func (c *Client) ListSubjectDecisions(ctx context.Context, id string, params *ListSubjectDecisionsParams) (*SubjectDecisionsResponse, error) {
+	var out SubjectDecisionsResponse
+	if err := c.do(ctx, "GET", "/api/v1/subjects/"+url.PathEscape(id)+"/decisions", params.values(), nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListUsers calls GET /api/v1/users: List users
+// The caller must be permitted "read".
+func (c *Client) ListUsers(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", "/api/v1/users", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateUser calls POST /api/v1/users/create: Create user
+// The caller must be permitted "write".
+func (c *Client) CreateUser(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "POST", "/api/v1/users/create", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Health calls GET /health: Health check
+func (c *Client) Health(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", "/health", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOpenAPI calls GET /openapi.json: This OpenAPI document
+func (c *Client) GetOpenAPI(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", "/openapi.json", nil, nil, "", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
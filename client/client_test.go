@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientDo(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"policy": map[string]interface{}{"id": "pol-001", "revision": 3}})
+	}))
+	defer server.Close()
+
+	c := New(server.URL + "/")
+	c.SetUserID("admin-001")
+	response, err := c.UpdatePolicy(context.Background(), "pol/001", &UpdatePolicyParams{IfMatch: `"2"`}, &Policy{ID: "pol/001"})
+	if err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+
+	if got.Method != http.MethodPut || got.URL.EscapedPath() != "/api/v1/policies/pol%2F001" {
+		t.Errorf("unexpected request %s %s", got.Method, got.URL.EscapedPath())
+	}
+	if got.Header.Get("X-User-ID") != "admin-001" || got.Header.Get("If-Match") != `"2"` {
+		t.Errorf("expected X-User-ID and If-Match headers, got %v", got.Header)
+	}
+	if got.Header.Get("Content-Type") != "application/json" || !strings.Contains(string(gotBody), `"id":"pol/001"`) {
+		t.Errorf("expected JSON body, got %q (%s)", gotBody, got.Header.Get("Content-Type"))
+	}
+	if response.Policy == nil || response.Policy.ID != "pol-001" || response.Policy.Revision != 3 {
+		t.Errorf("unexpected response %+v", response.Policy)
+	}
+}
+
+func TestClientQuery(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"policies": [], "count": 0, "total": 0, "next_cursor": ""}`))
+	}))
+	defer server.Close()
+
+	enabled := false
+	if _, err := New(server.URL).ListPolicies(context.Background(), &ListPoliciesParams{Limit: 10, Enabled: &enabled}); err != nil {
+		t.Fatalf("ListPolicies failed: %v", err)
+	}
+	if query != "enabled=false&limit=10" {
+		t.Errorf("expected zero values to be left out, got %q", query)
+	}
+
+	if _, err := New(server.URL).ListPolicies(context.Background(), nil); err != nil {
+		t.Fatalf("ListPolicies without params failed: %v", err)
+	}
+	if query != "" {
+		t.Errorf("expected no query, got %q", query)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "Invalid request body", "details": "missing action"}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).Evaluate(context.Background(), &EvaluateRequestBody{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "Invalid request body" || apiErr.Details != "missing action" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+	if apiErr.Error() != "abac: 400 Invalid request body: missing action" {
+		t.Errorf("unexpected message %q", apiErr.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time. The PDP and attribute resolver read evaluation
+// time through a Clock so callers and tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock returns the system time
+type RealClock struct{}
+
+// NewRealClock creates a clock backed by time.Now
+func NewRealClock() RealClock {
+	return RealClock{}
+}
+
+// Now returns the current system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// MockClock is a manually controlled clock for deterministic tests
+type MockClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewMockClock creates a clock frozen at now
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+// Now returns the frozen time
+func (c *MockClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set moves the clock to now
+func (c *MockClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// OrReal returns c, or a RealClock when c is nil
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return RealClock{}
+	}
+	return c
+}
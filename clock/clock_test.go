@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMockClock(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+	c := NewMockClock(start)
+
+	if !c.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, c.Now())
+	}
+
+	c.Advance(90 * time.Minute)
+	if want := start.Add(90 * time.Minute); !c.Now().Equal(want) {
+		t.Errorf("Expected %v after Advance, got %v", want, c.Now())
+	}
+
+	later := start.Add(24 * time.Hour)
+	c.Set(later)
+	if !c.Now().Equal(later) {
+		t.Errorf("Expected %v after Set, got %v", later, c.Now())
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	if _, ok := OrReal(nil).(RealClock); !ok {
+		t.Error("Expected RealClock for nil clock")
+	}
+
+	mock := NewMockClock(time.Time{})
+	if OrReal(mock) != Clock(mock) {
+		t.Error("Expected the given clock to be returned")
+	}
+}
@@ -0,0 +1,239 @@
+// Command loadgen generates synthetic evaluation traffic against an in-process PDP or a running
+// HTTP service and reports latency percentiles and the decision mix, for capacity planning.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errFailedRequests is returned when some evaluations failed; the report is still printed
+var errFailedRequests = errors.New("some evaluations failed")
+
+// Targets
+const (
+	targetLocal = "local"
+	targetHTTP  = "http"
+)
+
+const usage = `loadgen - synthetic evaluation traffic generator
+
+Usage:
+  loadgen -target local -policies policies.json -workload workload.json [flags]
+  loadgen -target http -url http://localhost:8081 -subjects user-1=3,user-2 -resources ... -actions ... [flags]
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// options are the command-line flags of a run
+type options struct {
+	target       string
+	policyFile   string
+	url          string
+	userID       string
+	workloadFile string
+	subjects     string
+	resources    string
+	actions      string
+	distribution string
+	zipfS        float64
+	requests     int
+	duration     time.Duration
+	concurrency  int
+	think        time.Duration
+	seed         int64
+	asJSON       bool
+	debug        bool
+}
+
+// run parses flags, drives the workload and prints the report. Exit status: 0 success,
+// 1 usage or setup error, 2 some evaluations failed.
+func run(args []string, stdout, stderr io.Writer) int {
+	o := &options{}
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&o.target, "target", targetLocal, "local (in-process PDP) or http (running service)")
+	fs.StringVar(&o.policyFile, "policies", "", "policy JSON file evaluated by the local target")
+	fs.StringVar(&o.url, "url", "http://localhost:8081", "service base URL of the http target")
+	fs.StringVar(&o.userID, "user", "", "X-User-ID sent by the http target")
+	fs.StringVar(&o.workloadFile, "workload", "", "workload JSON file with weighted subjects, resources and actions")
+	fs.StringVar(&o.subjects, "subjects", "", "subject IDs as id[=weight],... (overrides the workload file)")
+	fs.StringVar(&o.resources, "resources", "", "resource IDs as id[=weight],... (overrides the workload file)")
+	fs.StringVar(&o.actions, "actions", "", "actions as name[=weight],... (overrides the workload file)")
+	fs.StringVar(&o.distribution, "distribution", distributionUniform, "key distribution: uniform or zipf (earlier entries are hotter)")
+	fs.Float64Var(&o.zipfS, "zipf-s", 1.1, "zipf exponent")
+	fs.IntVar(&o.requests, "requests", 1000, "total requests; 0 runs until -duration elapses")
+	fs.DurationVar(&o.duration, "duration", 0, "stop after this long (0 = no limit)")
+	fs.IntVar(&o.concurrency, "concurrency", 8, "concurrent workers")
+	fs.DurationVar(&o.think, "think", 0, "think time of a worker between requests")
+	fs.Int64Var(&o.seed, "seed", 1, "random seed; worker i draws from seed+i")
+	fs.BoolVar(&o.asJSON, "json", false, "print the report as JSON")
+	fs.BoolVar(&o.debug, "debug", false, "show PDP logs of the local target")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+
+	err := execute(o, stdout)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errFailedRequests):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "❌ %v\n", err)
+		return 1
+	}
+}
+
+func execute(o *options, stdout io.Writer) error {
+	if o.concurrency < 1 {
+		return errors.New("-concurrency must be at least 1")
+	}
+	if o.requests < 0 || (o.requests == 0 && o.duration <= 0) {
+		return errors.New("set -requests, -duration or both")
+	}
+
+	w, err := resolveWorkload(o)
+	if err != nil {
+		return err
+	}
+	generator, err := newGenerator(w, o.distribution, o.zipfS)
+	if err != nil {
+		return err
+	}
+
+	var t target
+	switch o.target {
+	case targetLocal:
+		if o.policyFile == "" {
+			return errors.New("-policies is required for the local target")
+		}
+		if !o.debug {
+			log.SetOutput(io.Discard)
+		}
+		policies, err := loadPolicies(o.policyFile)
+		if err != nil {
+			return err
+		}
+		if t, err = newLocalTarget(policies, w); err != nil {
+			return err
+		}
+	case targetHTTP:
+		t = newHTTPTarget(o.url, o.userID)
+	default:
+		return fmt.Errorf("unknown target %q (local or http)", o.target)
+	}
+
+	r := drive(t, generator, o)
+
+	if o.asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		r.print(stdout)
+	}
+
+	if r.Errors > 0 {
+		return errFailedRequests
+	}
+	return nil
+}
+
+// resolveWorkload loads the workload file, if any, and applies the list flags
+func resolveWorkload(o *options) (*workload, error) {
+	w := &workload{}
+	if o.workloadFile != "" {
+		loaded, err := loadWorkload(o.workloadFile)
+		if err != nil {
+			return nil, err
+		}
+		w = loaded
+	}
+	for _, override := range []struct {
+		raw  string
+		dest *[]entry
+	}{{o.subjects, &w.Subjects}, {o.resources, &w.Resources}, {o.actions, &w.Actions}} {
+		if override.raw == "" {
+			continue
+		}
+		entries, err := parseEntries(override.raw)
+		if err != nil {
+			return nil, err
+		}
+		*override.dest = entries
+	}
+	return w, nil
+}
+
+// drive runs the workers until the request budget is spent or the duration elapses
+func drive(t target, g *generator, o *options) *report {
+	ctx := context.Background()
+	if o.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.duration)
+		defer cancel()
+	}
+
+	var issued atomic.Int64
+	perWorker := make([][]result, o.concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < o.concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(o.seed + int64(worker)))
+			for ctx.Err() == nil {
+				if o.requests > 0 && issued.Add(1) > int64(o.requests) {
+					return
+				}
+				req := g.next(rng)
+				began := time.Now()
+				decision, err := t.Evaluate(ctx, req)
+				if err != nil && ctx.Err() != nil {
+					return // cut off by -duration, not a failure
+				}
+				perWorker[worker] = append(perWorker[worker], result{latency: time.Since(began), decision: decision, err: err})
+
+				if o.think > 0 {
+					select {
+					case <-ctx.Done():
+					case <-time.After(o.think):
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var results []result
+	for _, worker := range perWorker {
+		results = append(results, worker...)
+	}
+	return buildReport(o.target, o.concurrency, elapsed, results)
+}
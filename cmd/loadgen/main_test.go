@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+const examplePolicies = "../../policy_examples_corrected.json"
+
+func TestLocalTarget(t *testing.T) {
+	workloadPath := filepath.Join(t.TempDir(), "workload.json")
+	body := `{
+		"subjects": [{"id": "user-1", "attributes": {"Department": "engineering"}}],
+		"resources": [
+			{"id": "api:documents:dept-engineering", "weight": 3},
+			{"id": "api:documents:board-minutes", "attributes": {"Sensitivity": "confidential"}}
+		],
+		"actions": [{"id": "document-service:file:delete"}]
+	}`
+	if err := os.WriteFile(workloadPath, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-policies", examplePolicies, "-workload", workloadPath,
+		"-requests", "200", "-concurrency", "4", "-json"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var r report
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatalf("invalid JSON report: %v\n%s", err, stdout.String())
+	}
+	if r.Requests != 200 || r.Errors != 0 {
+		t.Fatalf("expected 200 successful requests, got %+v", r)
+	}
+	// Deleting the confidential resource (weight 1 of 4) is denied, everything else permitted
+	permits, denies := r.Decisions["permit"], r.Decisions["deny"]
+	if permits+denies != 200 || permits < 100 || denies == 0 {
+		t.Errorf("expected mostly permits and some denies, got %v", r.Decisions)
+	}
+	if r.LatencyMs.P99 < r.LatencyMs.P50 || r.LatencyMs.Max < r.LatencyMs.P99 {
+		t.Errorf("percentiles out of order: %+v", r.LatencyMs)
+	}
+}
+
+func TestHTTPTarget(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Action string `json:"action"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/v1/evaluate" || r.Header.Get("X-User-ID") != "loadgen" {
+			http.Error(w, `{"error":"unexpected request"}`, http.StatusBadRequest)
+			return
+		}
+		calls.Add(1)
+		result := "permit"
+		if body.Action == "write" {
+			result = "deny"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"decision": map[string]string{"result": result}})
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-target", "http", "-url", server.URL, "-user", "loadgen",
+		"-subjects", "user-1,user-2", "-resources", "res-1", "-actions", "read,write",
+		"-distribution", "zipf", "-requests", "50", "-concurrency", "5", "-json"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	var r report
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 50 || r.Decisions["permit"]+r.Decisions["deny"] != 50 {
+		t.Errorf("expected 50 evaluations, got %d calls and %v", calls.Load(), r.Decisions)
+	}
+
+	// A failing service yields exit 2 and the errors in the report
+	stdout.Reset()
+	args = []string{"-target", "http", "-url", server.URL, "-subjects", "user-1", "-resources", "res-1",
+		"-actions", "read", "-requests", "3", "-concurrency", "1"}
+	if code := run(args, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit 2 for failed evaluations, got %d", code)
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("Requests:    3 (3 errors)")) {
+		t.Errorf("expected failed requests in report:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// result is the outcome of one evaluation
+type result struct {
+	latency  time.Duration
+	decision string
+	err      error
+}
+
+// latencySummary holds latency percentiles in milliseconds
+type latencySummary struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// report summarizes a run. Latencies cover successful evaluations only.
+type report struct {
+	Target       string         `json:"target"`
+	Requests     int            `json:"requests"`
+	Errors       int            `json:"errors"`
+	Concurrency  int            `json:"concurrency"`
+	DurationSec  float64        `json:"duration_seconds"`
+	Throughput   float64        `json:"throughput_rps"`
+	LatencyMs    latencySummary `json:"latency_ms"`
+	Decisions    map[string]int `json:"decisions"`
+	ErrorSamples map[string]int `json:"error_samples,omitempty"`
+}
+
+// maxErrorSamples bounds the distinct error messages kept in a report
+const maxErrorSamples = 10
+
+func buildReport(targetName string, concurrency int, elapsed time.Duration, results []result) *report {
+	r := &report{
+		Target:      targetName,
+		Requests:    len(results),
+		Concurrency: concurrency,
+		DurationSec: elapsed.Seconds(),
+		Decisions:   map[string]int{},
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(len(results)) / elapsed.Seconds()
+	}
+
+	latencies := make([]time.Duration, 0, len(results))
+	for _, res := range results {
+		if res.err != nil {
+			r.Errors++
+			message := res.err.Error()
+			if r.ErrorSamples == nil {
+				r.ErrorSamples = map[string]int{}
+			}
+			if _, seen := r.ErrorSamples[message]; seen || len(r.ErrorSamples) < maxErrorSamples {
+				r.ErrorSamples[message]++
+			}
+			continue
+		}
+		r.Decisions[res.decision]++
+		latencies = append(latencies, res.latency)
+	}
+	r.LatencyMs = summarizeLatencies(latencies)
+	return r
+}
+
+func summarizeLatencies(latencies []time.Duration) latencySummary {
+	if len(latencies) == 0 {
+		return latencySummary{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	return latencySummary{
+		Min:  milliseconds(latencies[0]),
+		Mean: milliseconds(total / time.Duration(len(latencies))),
+		P50:  milliseconds(percentile(latencies, 50)),
+		P90:  milliseconds(percentile(latencies, 90)),
+		P95:  milliseconds(percentile(latencies, 95)),
+		P99:  milliseconds(percentile(latencies, 99)),
+		Max:  milliseconds(latencies[len(latencies)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile p (0-100] of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// print writes the report as a human-readable table
+func (r *report) print(w io.Writer) {
+	fmt.Fprintf(w, "Target:      %s\n", r.Target)
+	fmt.Fprintf(w, "Requests:    %d (%d errors)\n", r.Requests, r.Errors)
+	fmt.Fprintf(w, "Concurrency: %d\n", r.Concurrency)
+	fmt.Fprintf(w, "Duration:    %.2fs\n", r.DurationSec)
+	fmt.Fprintf(w, "Throughput:  %.1f req/s\n", r.Throughput)
+
+	l := r.LatencyMs
+	fmt.Fprintln(w, "\nLatency (ms):")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  MIN\tMEAN\tP50\tP90\tP95\tP99\tMAX")
+	fmt.Fprintf(tw, "  %.3f\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\n", l.Min, l.Mean, l.P50, l.P90, l.P95, l.P99, l.Max)
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nDecisions:")
+	for _, decision := range sortedKeys(r.Decisions) {
+		count := r.Decisions[decision]
+		fmt.Fprintf(w, "  %-16s %8d  %5.1f%%\n", decision, count, 100*float64(count)/float64(r.Requests))
+	}
+
+	if len(r.ErrorSamples) > 0 {
+		fmt.Fprintln(w, "\nErrors:")
+		for _, message := range sortedKeys(r.ErrorSamples) {
+			fmt.Fprintf(w, "  %6d  %s\n", r.ErrorSamples[message], message)
+		}
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
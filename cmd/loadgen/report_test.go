@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for p, want := range map[float64]time.Duration{50: 50 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond, 0.1: time.Millisecond} {
+		if got := percentile(latencies, p); got != want {
+			t.Errorf("p%v: expected %v, got %v", p, want, got)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	results := []result{
+		{latency: 4 * time.Millisecond, decision: "permit"},
+		{latency: 2 * time.Millisecond, decision: "deny"},
+		{latency: 3 * time.Millisecond, decision: "permit"},
+		{latency: time.Second, err: errors.New("connection refused")},
+	}
+	r := buildReport("local", 2, 2*time.Second, results)
+
+	if r.Requests != 4 || r.Errors != 1 || r.Throughput != 2 {
+		t.Errorf("unexpected totals %+v", r)
+	}
+	if r.Decisions["permit"] != 2 || r.Decisions["deny"] != 1 {
+		t.Errorf("unexpected decision mix %v", r.Decisions)
+	}
+	// Failed requests do not skew the latencies
+	if r.LatencyMs.Min != 2 || r.LatencyMs.Max != 4 || r.LatencyMs.P50 != 3 {
+		t.Errorf("unexpected latencies %+v", r.LatencyMs)
+	}
+
+	var out bytes.Buffer
+	r.print(&out)
+	for _, want := range []string{"Requests:    4 (1 errors)", "permit", "connection refused"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in report:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"abac_go_example/client"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// target evaluates one generated request and returns the decision result (permit, deny, ...)
+type target interface {
+	Evaluate(ctx context.Context, req request) (string, error)
+}
+
+// localTarget evaluates in process against a PDP over an in-memory copy of the policy set.
+// Resources and actions of the workload are stored up front so workers only read the storage.
+type localTarget struct {
+	pdp core.PolicyDecisionPointInterface
+}
+
+func newLocalTarget(policies []*models.Policy, w *workload) (*localTarget, error) {
+	store := storage.NewMockStorage()
+	store.SetPolicies(policies)
+	for _, resource := range w.Resources {
+		resourceType, _ := resource.Attributes["resource_type"].(string)
+		if err := store.CreateResource(&models.Resource{
+			ID:           resource.ID,
+			ResourceID:   resource.ID,
+			ResourceType: resourceType,
+			Attributes:   models.JSONMap(resource.Attributes),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, action := range w.Actions {
+		if err := store.CreateAction(&models.Action{ID: action.ID, ActionName: action.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	config := core.DefaultPDPConfig()
+	config.EnableStats = false
+	return &localTarget{pdp: core.NewPolicyDecisionPointWithConfig(store, config)}, nil
+}
+
+func (t *localTarget) Evaluate(_ context.Context, req request) (string, error) {
+	decision, err := t.pdp.Evaluate(&models.EvaluationRequest{
+		RequestID:  "loadgen",
+		Subject:    &workloadSubject{id: req.Subject.ID, attributes: req.Subject.Attributes},
+		ResourceID: req.Resource.ID,
+		Action:     req.Action,
+		Context:    copyMap(req.Context),
+	})
+	if err != nil {
+		return "", err
+	}
+	return decision.Result, nil
+}
+
+// httpTarget sends each request to POST /api/v1/evaluate of a running service.
+// Subjects and resources are loaded by the service, so workload attributes are ignored.
+type httpTarget struct {
+	client *client.Client
+}
+
+func newHTTPTarget(baseURL, userID string) *httpTarget {
+	c := client.New(baseURL)
+	if userID != "" {
+		c.SetUserID(userID)
+	}
+	return &httpTarget{client: c}
+}
+
+func (t *httpTarget) Evaluate(ctx context.Context, req request) (string, error) {
+	response, err := t.client.Evaluate(ctx, &client.EvaluateRequestBody{
+		SubjectID:  req.Subject.ID,
+		ResourceID: req.Resource.ID,
+		Action:     req.Action,
+		Context:    req.Context,
+	})
+	if err != nil {
+		return "", err
+	}
+	if response.Decision == nil {
+		return "", errors.New("response without decision")
+	}
+	if response.Decision.Result == "" {
+		return "", fmt.Errorf("decision without result (request %s)", response.RequestID)
+	}
+	return response.Decision.Result, nil
+}
+
+// workloadSubject is a subject described only by its workload attributes
+type workloadSubject struct {
+	id         string
+	attributes map[string]interface{}
+}
+
+func (s *workloadSubject) GetID() string { return s.id }
+
+func (s *workloadSubject) GetType() models.SubjectType {
+	if subjectType, ok := s.attributes["subject_type"].(string); ok && subjectType != "" {
+		return models.SubjectType(subjectType)
+	}
+	return models.SubjectTypeUser
+}
+
+func (s *workloadSubject) GetAttributes() map[string]interface{} {
+	attributes := copyMap(s.attributes)
+	attributes["id"] = s.id
+	return attributes
+}
+
+func (s *workloadSubject) GetDisplayName() string { return s.id }
+
+func (s *workloadSubject) IsActive() bool { return true }
+
+// copyMap gives every evaluation its own map, since the PDP may enrich request maps
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m)+1)
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
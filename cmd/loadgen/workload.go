@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"abac_go_example/models"
+)
+
+// Supported key distributions
+const (
+	distributionUniform = "uniform"
+	distributionZipf    = "zipf"
+)
+
+// entry is one subject, resource or action of the workload with its relative weight.
+// Attributes are only used by the local target, which has no stored entities.
+type entry struct {
+	ID         string                 `json:"id"`
+	Weight     float64                `json:"weight,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// workload describes the generated traffic; it is read from -workload and overridden by flags
+type workload struct {
+	Subjects  []entry                `json:"subjects"`
+	Resources []entry                `json:"resources"`
+	Actions   []entry                `json:"actions"`
+	Context   map[string]interface{} `json:"context,omitempty"`
+}
+
+// loadWorkload reads a workload JSON file
+func loadWorkload(filename string) (*workload, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var w workload
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("invalid workload file %s: %w", filename, err)
+	}
+	return &w, nil
+}
+
+// loadPolicies reads a JSON file containing {"policies": [...]} or a bare array of policies
+func loadPolicies(filename string) ([]*models.Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var policies []*models.Policy
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &policies)
+	} else {
+		var document struct {
+			Policies []*models.Policy `json:"policies"`
+		}
+		err = json.Unmarshal(data, &document)
+		policies = document.Policies
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+	}
+	return policies, nil
+}
+
+// parseEntries parses "id[=weight],..." lists such as "user-1=3,user-2"
+func parseEntries(raw string) ([]entry, error) {
+	var entries []entry
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, weight, found := strings.Cut(item, "=")
+		e := entry{ID: strings.TrimSpace(id)}
+		if found {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid weight in %q", item)
+			}
+			e.Weight = parsed
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (w *workload) validate() error {
+	if len(w.Subjects) == 0 || len(w.Resources) == 0 || len(w.Actions) == 0 {
+		return errors.New("subjects, resources and actions are required (flags or -workload file)")
+	}
+	for _, entries := range [][]entry{w.Subjects, w.Resources, w.Actions} {
+		for _, e := range entries {
+			if e.ID == "" {
+				return errors.New("workload entry without id")
+			}
+			if e.Weight < 0 {
+				return fmt.Errorf("negative weight for %s", e.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// sampler draws entries by weight. Under the zipf distribution the weight of the entry at rank
+// i (0-based, in list order) is further scaled by 1/(i+1)^s, so the first entries are hot keys.
+type sampler struct {
+	entries    []entry
+	cumulative []float64
+}
+
+func newSampler(entries []entry, distribution string, zipfS float64) (*sampler, error) {
+	s := &sampler{entries: entries, cumulative: make([]float64, len(entries))}
+	total := 0.0
+	for i, e := range entries {
+		weight := e.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		switch distribution {
+		case distributionUniform:
+		case distributionZipf:
+			weight /= math.Pow(float64(i+1), zipfS)
+		default:
+			return nil, fmt.Errorf("unknown distribution %q (uniform or zipf)", distribution)
+		}
+		total += weight
+		s.cumulative[i] = total
+	}
+	return s, nil
+}
+
+func (s *sampler) next(rng *rand.Rand) *entry {
+	target := rng.Float64() * s.cumulative[len(s.cumulative)-1]
+	i := sort.SearchFloat64s(s.cumulative, target)
+	if i == len(s.entries) {
+		i--
+	}
+	return &s.entries[i]
+}
+
+// generator produces the (subject, resource, action) triples of the workload
+type generator struct {
+	subjects, resources, actions *sampler
+	context                      map[string]interface{}
+}
+
+func newGenerator(w *workload, distribution string, zipfS float64) (*generator, error) {
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	g := &generator{context: w.Context}
+	var err error
+	if g.subjects, err = newSampler(w.Subjects, distribution, zipfS); err != nil {
+		return nil, err
+	}
+	if g.resources, err = newSampler(w.Resources, distribution, zipfS); err != nil {
+		return nil, err
+	}
+	if g.actions, err = newSampler(w.Actions, distribution, zipfS); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// request is one generated evaluation
+type request struct {
+	Subject  *entry
+	Resource *entry
+	Action   string
+	Context  map[string]interface{}
+}
+
+func (g *generator) next(rng *rand.Rand) request {
+	return request{
+		Subject:  g.subjects.next(rng),
+		Resource: g.resources.next(rng),
+		Action:   g.actions.next(rng).ID,
+		Context:  g.context,
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseEntries(t *testing.T) {
+	entries, err := parseEntries("user-1=3, user-2,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "user-1" || entries[0].Weight != 3 || entries[1].Weight != 0 {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if _, err := parseEntries("user-1=heavy"); err == nil {
+		t.Error("expected an invalid weight to fail")
+	}
+}
+
+func TestSamplerDistributions(t *testing.T) {
+	entries := []entry{{ID: "a"}, {ID: "b"}, {ID: "c", Weight: 2}}
+	draw := func(distribution string) map[string]int {
+		s, err := newSampler(entries, distribution, 1.1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rng := rand.New(rand.NewSource(42))
+		counts := map[string]int{}
+		for i := 0; i < 40000; i++ {
+			counts[s.next(rng).ID]++
+		}
+		return counts
+	}
+
+	// Uniform honours explicit weights: c is drawn about twice as often as a
+	uniform := draw(distributionUniform)
+	if ratio := float64(uniform["c"]) / float64(uniform["a"]); ratio < 1.8 || ratio > 2.2 {
+		t.Errorf("expected c twice as likely as a, got %v", uniform)
+	}
+	// Zipf makes the first entry the hottest key
+	zipf := draw(distributionZipf)
+	if zipf["a"] <= zipf["b"] || zipf["a"] <= zipf["c"] {
+		t.Errorf("expected a to be the hot key under zipf, got %v", zipf)
+	}
+
+	if _, err := newSampler(entries, "normal", 1); err == nil {
+		t.Error("expected an unknown distribution to fail")
+	}
+}
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	w := &workload{
+		Subjects:  []entry{{ID: "user-1"}, {ID: "user-2"}},
+		Resources: []entry{{ID: "res-1"}, {ID: "res-2"}, {ID: "res-3"}},
+		Actions:   []entry{{ID: "read"}, {ID: "write"}},
+	}
+	g, err := newGenerator(w, distributionZipf, 1.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		a, b := g.next(first), g.next(second)
+		if a.Subject.ID != b.Subject.ID || a.Resource.ID != b.Resource.ID || a.Action != b.Action {
+			t.Fatalf("request %d differs for the same seed: %+v vs %+v", i, a, b)
+		}
+	}
+
+	if _, err := newGenerator(&workload{Subjects: w.Subjects}, distributionUniform, 1); err == nil {
+		t.Error("expected a workload without resources and actions to fail")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"abac_go_example/models"
+	"abac_go_example/policy"
 	"abac_go_example/storage"
 )
 
@@ -200,45 +201,37 @@ func seedPolicies(storage *storage.PostgreSQLStorage, filename string) error {
 	}
 
 	var policiesData struct {
-		Policies []struct {
-			ID               string                 `json:"id"`
-			PolicyName       string                 `json:"policy_name"`
-			Description      string                 `json:"description"`
-			Effect           string                 `json:"effect"`
-			Enabled          bool                   `json:"enabled"`
-			Version          int                    `json:"version"`
-			Conditions       map[string]interface{} `json:"conditions"`
-			Rules            []models.PolicyRule    `json:"rules"`
-			Actions          []string               `json:"actions"`
-			ResourcePatterns []string               `json:"resource_patterns"`
-		} `json:"policies"`
+		Policies []json.RawMessage `json:"policies"`
 	}
 
 	if err := json.Unmarshal(data, &policiesData); err != nil {
 		return err
 	}
 
-	// TODO: Update to use new policy format
-	/*
-		for _, policyData := range policiesData.Policies {
-			policy := &models.Policy{
-				ID:               policyData.ID,
-				PolicyName:       policyData.PolicyName,
-				Description:      policyData.Description,
-				Version:          "2024-10-21", // Convert to string
-				Enabled:          policyData.Enabled,
-				// Statement:        // TODO: Convert from old format to new format
-			}
+	converted := 0
+	for _, policyData := range policiesData.Policies {
+		// Legacy policies (rules, actions, resource_patterns) are converted to statements
+		if policy.IsLegacyDocument(policyData) {
+			converted++
+		}
+		decoded, err := policy.DecodePolicy(policyData)
+		if err != nil {
+			return err
+		}
 
-			if err := storage.CreatePolicy(policy); err != nil {
-				// If policy already exists, update it
-				if err := storage.UpdatePolicy(policy); err != nil {
-					return fmt.Errorf("failed to create/update policy %s: %w", policy.ID, err)
-				}
+		if err := storage.CreatePolicy(decoded); err != nil {
+			// If policy already exists, update it based on the stored revision
+			existing, getErr := storage.GetPolicy(decoded.ID)
+			if getErr != nil {
+				return fmt.Errorf("failed to create policy %s: %w", decoded.ID, err)
+			}
+			decoded.Revision = existing.Revision
+			if err := storage.UpdatePolicy(decoded); err != nil {
+				return fmt.Errorf("failed to create/update policy %s: %w", decoded.ID, err)
 			}
 		}
-	*/
+	}
 
-	fmt.Printf("✅ Seeded %d policies\n", len(policiesData.Policies))
+	fmt.Printf("✅ Seeded %d policies (%d converted from the legacy format)\n", len(policiesData.Policies), converted)
 	return nil
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"abac_go_example/bundle"
+)
+
+const bundleUsage = `Usage:
+  policyctl bundle keygen -out <prefix>                          Write <prefix>.key (private) and <prefix>.pub (public)
+  policyctl bundle sign -policies <file> -key <key> -out <file>   Sign policies into a bundle
+  policyctl bundle verify -bundle <file> -pub <key>               Verify a bundle's hash and signature
+`
+
+// runBundle implements "policyctl bundle"
+func runBundle(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing bundle subcommand\n\n%s", bundleUsage)
+	}
+
+	switch args[0] {
+	case "keygen":
+		return runBundleKeygen(args[1:], stdout)
+	case "sign":
+		return runBundleSign(args[1:], stdout)
+	case "verify":
+		return runBundleVerify(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown bundle subcommand %q\n\n%s", args[0], bundleUsage)
+	}
+}
+
+func runBundleKeygen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bundle keygen", flag.ContinueOnError)
+	out := fs.String("out", "bundle", "output path prefix")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	publicKey, privateKey, err := bundle.GenerateKey()
+	if err != nil {
+		return err
+	}
+	privatePEM, err := bundle.EncodePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	publicPEM, err := bundle.EncodePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out+".key", privatePEM, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out+".pub", publicPEM, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Key ID:  %s\nPrivate: %s.key\nPublic:  %s.pub\n", bundle.KeyID(publicKey), *out, *out)
+	return nil
+}
+
+func runBundleSign(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bundle sign", flag.ContinueOnError)
+	policiesFile := fs.String("policies", "", "JSON policy file")
+	keyFile := fs.String("key", "", "PEM Ed25519 private key")
+	out := fs.String("out", "policies.bundle.json", "bundle file to write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *policiesFile == "" || *keyFile == "" {
+		return fmt.Errorf("-policies and -key are required")
+	}
+
+	policies, err := loadPolicies(*policiesFile)
+	if err != nil {
+		return err
+	}
+	key, err := bundle.LoadPrivateKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	signed, err := bundle.NewSigner(key).Sign(policies)
+	if err != nil {
+		return err
+	}
+	if err := signed.WriteFile(*out); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Signed %d policies into %s (hash %s, key %s)\n", len(signed.Policies), *out, signed.ShortHash(), signed.KeyID)
+	return nil
+}
+
+func runBundleVerify(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bundle verify", flag.ContinueOnError)
+	bundleFile := fs.String("bundle", "", "bundle file")
+	publicKeyFile := fs.String("pub", "", "PEM Ed25519 public key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bundleFile == "" || *publicKeyFile == "" {
+		return fmt.Errorf("-bundle and -pub are required")
+	}
+
+	signed, err := bundle.LoadFile(*bundleFile)
+	if err != nil {
+		return err
+	}
+	key, err := bundle.LoadPublicKey(*publicKeyFile)
+	if err != nil {
+		return err
+	}
+	if _, err := bundle.NewVerifier(key).Verify(signed); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "✅ Bundle %s verified: %d policies signed by %s at %s\n",
+		signed.ShortHash(), len(signed.Policies), signed.KeyID, signed.CreatedAt.Format(time.RFC3339))
+	return nil
+}
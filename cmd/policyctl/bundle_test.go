@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleCommand(t *testing.T) {
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "signing")
+	bundlePath := filepath.Join(dir, "policies.bundle.json")
+
+	steps := [][]string{
+		{"bundle", "keygen", "-out", prefix},
+		{"bundle", "sign", "-policies", examplePolicies, "-key", prefix + ".key", "-out", bundlePath},
+		{"bundle", "verify", "-bundle", bundlePath, "-pub", prefix + ".pub"},
+	}
+	var stdout, stderr bytes.Buffer
+	for _, args := range steps {
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: expected exit 0, got %d (stderr: %s)", args, code, stderr.String())
+		}
+	}
+	if !strings.Contains(stdout.String(), "verified") {
+		t.Errorf("Expected verification output, got:\n%s", stdout.String())
+	}
+
+	// Editing a policy inside the signed file is detected
+	data, _ := os.ReadFile(bundlePath)
+	tampered := strings.Replace(string(data), `"Effect": "Deny"`, `"Effect": "Allow"`, 1)
+	if tampered == string(data) {
+		t.Fatal("Expected the example bundle to contain a Deny statement")
+	}
+	os.WriteFile(bundlePath, []byte(tampered), 0o644)
+
+	stderr.Reset()
+	if code := run(steps[2], nil, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "does not match") {
+		t.Errorf("Expected verification to fail, got exit %d (stderr: %s)", code, stderr.String())
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"abac_go_example/impact"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// errDecisionsDiffer is returned by "diff-decisions" when at least one decision changed
+var errDecisionsDiffer = errors.New("decisions differ between the policy sets")
+
+// runDiffDecisions implements "policyctl diff-decisions": it evaluates a request corpus against
+// two policy directories and reports every request whose decision differs, e.g. to validate a
+// refactor of the policy library.
+func runDiffDecisions(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("diff-decisions", flag.ContinueOnError)
+	beforeDir := fs.String("before", "", "directory of policy JSON files in effect today")
+	afterDir := fs.String("after", "", "directory of refactored policy JSON files")
+	requestsFile := fs.String("requests", "", "request corpus: a JSON array of requests or {\"requests\": [...]}")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	debug := fs.Bool("debug", false, "show PDP debug logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *beforeDir == "" || *afterDir == "" || *requestsFile == "" {
+		return errors.New("-before, -after and -requests are required")
+	}
+	if !*debug {
+		log.SetOutput(io.Discard)
+	}
+
+	before, err := newPolicyDirSession(*beforeDir)
+	if err != nil {
+		return err
+	}
+	after, err := newPolicyDirSession(*afterDir)
+	if err != nil {
+		return err
+	}
+	requests, err := loadRequestCorpus(*requestsFile)
+	if err != nil {
+		return err
+	}
+
+	report := diffDecisions(before, after, requests)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printDecisionDiff(stdout, report)
+	}
+
+	if len(report.Flips) > 0 {
+		return errDecisionsDiffer
+	}
+	return nil
+}
+
+// newPolicyDirSession builds a session over every policy below dir
+func newPolicyDirSession(dir string) (*session, error) {
+	policies, err := loadPolicyDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{store: storage.NewMockStorage()}
+	s.store.SetPolicies(policies)
+	s.pdp = newLocalPDP(s.store, false)
+	return s, nil
+}
+
+// loadPolicyDir reads every *.json policy file below dir in lexical order (see loadPolicies).
+// A policy ID declared twice is an error.
+func loadPolicyDir(dir string) ([]*models.Policy, error) {
+	policies := []*models.Policy{}
+	sources := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		loaded, err := loadPolicies(path)
+		if err != nil {
+			return err
+		}
+		for _, policy := range loaded {
+			if previous, duplicate := sources[policy.ID]; duplicate {
+				return fmt.Errorf("duplicate policy id %q in %s, first declared in %s", policy.ID, path, previous)
+			}
+			sources[policy.ID] = path
+			policies = append(policies, policy)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies in %s: %w", dir, err)
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("no policies found in %s", dir)
+	}
+	return policies, nil
+}
+
+// loadRequestCorpus reads requests in the -request file format, as a JSON array or {"requests": [...]}.
+// Requests without request_id are named after their position.
+func loadRequestCorpus(filename string) ([]*requestFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []*requestFile
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &requests)
+	} else {
+		var document struct {
+			Requests []*requestFile `json:"requests"`
+		}
+		err = json.Unmarshal(data, &document)
+		requests = document.Requests
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid request corpus %s: %w", filename, err)
+	}
+
+	for i, request := range requests {
+		if request.RequestID == "" {
+			request.RequestID = fmt.Sprintf("#%d", i+1)
+		}
+	}
+	return requests, nil
+}
+
+// diffDecisions evaluates every request under both sessions. Incomplete requests and evaluation
+// errors are reported as failures.
+func diffDecisions(before, after *session, requests []*requestFile) *impact.Report {
+	report := &impact.Report{Flips: []impact.Flip{}, Failures: []impact.Failure{}}
+	for _, request := range requests {
+		if request.SubjectID == "" || request.ResourceID == "" || request.Action == "" {
+			report.Failures = append(report.Failures, impact.Failure{RequestID: request.RequestID, Error: "subject_id, resource_id and action are required"})
+			continue
+		}
+
+		evaluated, beforeDecision, err := before.evaluate(request)
+		if err != nil {
+			report.Failures = append(report.Failures, impact.Failure{RequestID: request.RequestID, Error: "before: " + err.Error()})
+			continue
+		}
+		_, afterDecision, err := after.evaluate(request)
+		if err != nil {
+			report.Failures = append(report.Failures, impact.Failure{RequestID: request.RequestID, Error: "after: " + err.Error()})
+			continue
+		}
+		report.Record(evaluated, beforeDecision, afterDecision)
+	}
+	return report
+}
+
+// evaluate makes request the session's current request and evaluates it
+func (s *session) evaluate(request *requestFile) (*models.EvaluationRequest, *models.Decision, error) {
+	s.request = request
+	evaluationRequest, err := s.evaluationRequest()
+	if err != nil {
+		return nil, nil, err
+	}
+	decision, err := s.pdp.Evaluate(evaluationRequest)
+	return evaluationRequest, decision, err
+}
+
+// printDecisionDiff writes one line per changed decision followed by a summary
+func printDecisionDiff(w io.Writer, report *impact.Report) {
+	for _, flip := range report.Flips {
+		fmt.Fprintf(w, "~ %s: %s %s %s: %s → %s (policies %s → %s)\n",
+			flip.RequestID, flip.SubjectID, flip.Action, flip.ResourceID,
+			strings.ToUpper(flip.Before), strings.ToUpper(flip.After),
+			orDash(strings.Join(flip.BeforePolicies, ",")), orDash(strings.Join(flip.AfterPolicies, ",")))
+	}
+	for _, failure := range report.Failures {
+		fmt.Fprintf(w, "❌ %s: %s\n", failure.RequestID, failure.Error)
+	}
+	fmt.Fprintf(w, "\n%d requests evaluated: %d unchanged, %d changed (%d permit→deny, %d deny→permit), %d failed\n",
+		report.Evaluated, report.Unchanged, len(report.Flips), report.PermitToDeny, report.DenyToPermit, len(report.Failures))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"abac_go_example/impact"
+)
+
+func TestDiffDecisionsCommand(t *testing.T) {
+	writeFile := func(path, body string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	readPolicy := `{"id": "pol-read", "policy_name": "Read", "version": "2024-10-21", "enabled": true,
+		"statement": [{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"}]}`
+	// The refactor splits the library into files and narrows reads to engineering
+	writeFile(filepath.Join(dir, "before", "policies.json"), `{"policies": [`+readPolicy+`]}`)
+	writeFile(filepath.Join(dir, "after", "read.json"), `[{"id": "pol-read", "policy_name": "Read", "version": "2024-10-21", "enabled": true,
+		"statement": [{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*",
+			"Condition": {"StringEquals": {"user.Department": "engineering"}}}]}]`)
+	writeFile(filepath.Join(dir, "after", "nested", "write.json"), `{"id": "pol-write", "policy_name": "Write", "version": "2024-10-21", "enabled": true,
+		"statement": [{"Sid": "Write", "Effect": "Allow", "Action": "document:write", "Resource": "api:documents:*"}]}`)
+	writeFile(filepath.Join(dir, "requests.json"), `[
+		{"request_id": "eng-read", "subject_id": "user-1", "subject_attributes": {"Department": "engineering"}, "resource_id": "api:documents:a", "action": "document:read"},
+		{"subject_id": "user-2", "subject_attributes": {"Department": "sales"}, "resource_id": "api:documents:a", "action": "document:read"},
+		{"request_id": "write", "subject_id": "user-2", "resource_id": "api:documents:a", "action": "document:write"},
+		{"request_id": "incomplete", "subject_id": "user-3"}
+	]`)
+
+	args := []string{"diff-decisions", "-before", filepath.Join(dir, "before"), "-after", filepath.Join(dir, "after"),
+		"-requests", filepath.Join(dir, "requests.json")}
+	var stdout, stderr bytes.Buffer
+	if code := run(args, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit 2, got %d (stderr: %s)", code, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"~ #2: user-2 document:read api:documents:a: PERMIT → DENY (policies pol-read → -)",
+		"~ write: user-2 document:write api:documents:a: DENY → PERMIT",
+		"❌ incomplete:",
+		"3 requests evaluated: 1 unchanged, 2 changed (1 permit→deny, 1 deny→permit), 1 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	stdout.Reset()
+	run(append(args, "-json"), nil, &stdout, &stderr)
+	var report impact.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil || len(report.Flips) != 2 || report.PermitToDeny != 1 {
+		t.Errorf("unexpected JSON report %+v (%v):\n%s", report, err, stdout.String())
+	}
+
+	// Identical policy sets exit 0
+	stdout.Reset()
+	same := []string{"diff-decisions", "-before", filepath.Join(dir, "before"), "-after", filepath.Join(dir, "before"),
+		"-requests", filepath.Join(dir, "requests.json")}
+	if code := run(same, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit 0 for identical sets, got %d:\n%s", code, stdout.String())
+	}
+
+	writeFile(filepath.Join(dir, "after", "copy.json"), readPolicy)
+	stderr.Reset()
+	if code := run(args, nil, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), `duplicate policy id "pol-read"`) {
+		t.Errorf("expected duplicate policy error, got %d: %s", code, stderr.String())
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"abac_go_example/attrcrypt"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestEncryptAttributesCommand(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "abac.db"))
+	t.Setenv(constants.EnvEncryptedAttributes, "ssn")
+	t.Setenv(constants.EnvAttributeEncryptionKeys, "k1="+base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+
+	// A subject written before encryption was enabled
+	store, err := storage.NewSQLiteStorage(storage.DefaultSQLiteConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateSubject(&models.Subject{ID: "sub-001", SubjectType: "user", Attributes: models.JSONMap{"ssn": "123-45-6789"}}); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"encrypt-attributes"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Re-encrypted [ssn] in 2 rows.") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	store, err = storage.NewSQLiteStorage(storage.DefaultSQLiteConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	subject, err := store.GetSubject("sub-001")
+	if err != nil || !attrcrypt.IsEncrypted(subject.Attributes["ssn"]) {
+		t.Errorf("expected ssn encrypted at rest, got %v (%v)", subject, err)
+	}
+}
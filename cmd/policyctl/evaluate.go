@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// runEvaluate implements "policyctl evaluate"
+func runEvaluate(args []string, stdout io.Writer) error {
+	options := newSourceOptions()
+	fs := flag.NewFlagSet("evaluate", flag.ContinueOnError)
+	options.register(fs)
+	verbose := fs.Bool("v", false, "print the statement trace and attribute conflicts")
+	asJSON := fs.Bool("json", false, "print the full explanation as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := newSession(options)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := s.explain()
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(explanation); err != nil {
+			return err
+		}
+	} else {
+		printExplanation(stdout, explanation, *verbose)
+	}
+
+	if explanation.Decision.Result != constants.ResultPermit {
+		return errNotPermitted
+	}
+	return nil
+}
+
+// printExplanation writes a human-readable decision and, when verbose, the statement trace
+func printExplanation(w io.Writer, explanation *models.Explanation, verbose bool) {
+	decision := explanation.Decision
+	fmt.Fprintf(w, "Decision: %s\n", strings.ToUpper(decision.Result))
+	if decision.Reason != "" {
+		fmt.Fprintf(w, "Reason:   %s\n", decision.Reason)
+	}
+	if len(decision.MatchedPolicies) > 0 {
+		fmt.Fprintf(w, "Policies: %s\n", strings.Join(decision.MatchedPolicies, ", "))
+	}
+
+	if !verbose {
+		return
+	}
+
+	fmt.Fprintln(w, "\nStatement trace:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  POLICY\tSID\tEFFECT\tACTION\tRESOURCE\tCONDITIONS\tMATCHED")
+	for _, trace := range explanation.Statements {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			trace.PolicyID, orDash(trace.Sid), trace.Effect,
+			mark(trace.ActionMatched), mark(trace.ResourceMatched), mark(trace.ConditionsMatched), mark(trace.Matched))
+	}
+	tw.Flush()
+
+	if len(explanation.AttributeConflicts) > 0 {
+		fmt.Fprintln(w, "\nAttribute conflicts:")
+		for _, conflict := range explanation.AttributeConflicts {
+			fmt.Fprintf(w, "  %s.%s: stored=%v request=%v (resolved: %s)\n",
+				conflict.Entity, conflict.Key, conflict.StoredValue, conflict.RequestValue, conflict.Resolution)
+		}
+	}
+}
+
+func mark(ok bool) string {
+	if ok {
+		return "✓"
+	}
+	return "✗"
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
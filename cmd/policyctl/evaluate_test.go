@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const examplePolicies = "../../policy_examples_corrected.json"
+
+func TestEvaluateCommand(t *testing.T) {
+	base := []string{"evaluate", "-policies", examplePolicies,
+		"-subject", "user-1", "-subject-attr", "Department=engineering",
+		"-resource", "api:documents:dept-engineering"}
+
+	t.Run("permit", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		args := append(append([]string{}, base...), "-action", "document-service:file:read", "-v")
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+		}
+		output := stdout.String()
+		if !strings.Contains(output, "Decision: PERMIT") {
+			t.Errorf("expected permit decision, got:\n%s", output)
+		}
+		if !strings.Contains(output, "DepartmentDocumentsRead") {
+			t.Errorf("expected statement trace in verbose output, got:\n%s", output)
+		}
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		args := append(append([]string{}, base...), "-action", "document-service:file:delete",
+			"-resource-attr", "Sensitivity=confidential")
+		if code := run(args, nil, &stdout, &stderr); code != 2 {
+			t.Fatalf("expected exit 2, got %d (stderr: %s)", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "Decision: DENY") {
+			t.Errorf("expected deny decision, got:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("request file", func(t *testing.T) {
+		requestPath := filepath.Join(t.TempDir(), "request.json")
+		body := `{"subject_id":"user-1","subject_attributes":{"Department":"engineering"},
+			"resource_id":"api:documents:dept-engineering","action":"document-service:file:read"}`
+		if err := os.WriteFile(requestPath, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"evaluate", "-policies", examplePolicies, "-request", requestPath, "-json"}
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), `"statements"`) {
+			t.Errorf("expected JSON explanation, got:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		args := []string{"evaluate", "-subject", "user-1", "-resource", "r", "-action", "a"}
+		if code := run(args, nil, &stdout, &stderr); code != 1 {
+			t.Fatalf("expected exit 1, got %d", code)
+		}
+	})
+}
+
+func TestParseAssignment(t *testing.T) {
+	tests := []struct {
+		raw   string
+		key   string
+		value interface{}
+	}{
+		{"level=9", "level", float64(9)},
+		{"mfa=true", "mfa", true},
+		{"department=engineering", "department", "engineering"},
+		{"note=a=b", "note", "a=b"},
+	}
+
+	for _, tt := range tests {
+		key, value, err := parseAssignment(tt.raw)
+		if err != nil {
+			t.Fatalf("parseAssignment(%q) error: %v", tt.raw, err)
+		}
+		if key != tt.key || value != tt.value {
+			t.Errorf("parseAssignment(%q) = %q, %v; want %q, %v", tt.raw, key, value, tt.key, tt.value)
+		}
+	}
+
+	if _, _, err := parseAssignment("novalue"); err == nil {
+		t.Error("expected error for assignment without '='")
+	}
+}
+
+func TestEvaluateCommandLegacyPolicies(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "legacy.json")
+	document := `{"policies": [{
+		"id": "pol-legacy", "policy_name": "Legacy engineering read", "effect": "permit", "enabled": true, "version": 2,
+		"actions": ["document-service:file:read"], "resource_patterns": ["api:documents:*"],
+		"rules": [{"target_type": "subject", "attribute_path": "attributes.Department", "operator": "in", "expected_value": ["engineering", "qa"]}]
+	}]}`
+	if err := os.WriteFile(file, []byte(document), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	evaluate := func(department string) int {
+		var stdout, stderr bytes.Buffer
+		args := []string{"evaluate", "-policies", file, "-subject", "user-1", "-subject-attr", "Department=" + department,
+			"-resource", "api:documents:spec", "-action", "document-service:file:read"}
+		return run(args, nil, &stdout, &stderr)
+	}
+	if code := evaluate("engineering"); code != 0 {
+		t.Errorf("expected legacy rule to permit engineering, got exit %d", code)
+	}
+	if code := evaluate("sales"); code != 2 {
+		t.Errorf("expected legacy rule to deny sales, got exit %d", code)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/policy"
+)
+
+// requestFile is the JSON request accepted by -request. It mirrors the HTTP
+// evaluation body and adds inline subject/resource attributes.
+type requestFile struct {
+	RequestID          string                  `json:"request_id,omitempty"`
+	SubjectID          string                  `json:"subject_id"`
+	SubjectAttributes  map[string]interface{}  `json:"subject_attributes,omitempty"`
+	ResourceID         string                  `json:"resource_id"`
+	ResourceAttributes map[string]interface{}  `json:"resource_attributes,omitempty"`
+	Action             string                  `json:"action"`
+	Context            map[string]interface{}  `json:"context,omitempty"`
+	Environment        *models.EnvironmentInfo `json:"environment,omitempty"`
+	Timestamp          *time.Time              `json:"timestamp,omitempty"`
+	Session            *models.SessionInfo     `json:"session,omitempty"`
+}
+
+// attributeFlag collects repeated key=value flags. Values are parsed as JSON
+// literals when possible (9, true, ["a","b"]) and kept as strings otherwise.
+type attributeFlag map[string]interface{}
+
+func (a attributeFlag) String() string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func (a attributeFlag) Set(raw string) error {
+	key, value, err := parseAssignment(raw)
+	if err != nil {
+		return err
+	}
+	a[key] = value
+	return nil
+}
+
+// parseAssignment splits "key=value" and decodes the value
+func parseAssignment(raw string) (string, interface{}, error) {
+	key, value, found := strings.Cut(raw, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return "", nil, fmt.Errorf("expected key=value, got %q", raw)
+	}
+	return key, parseValue(strings.TrimSpace(value)), nil
+}
+
+// parseValue decodes a JSON literal, falling back to the raw string
+func parseValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err == nil {
+		return value
+	}
+	return raw
+}
+
+// loadRequestFile reads a request JSON file
+func loadRequestFile(filename string) (*requestFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var request requestFile
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("invalid request file %s: %w", filename, err)
+	}
+	return &request, nil
+}
+
+// loadPolicies reads policies from a JSON file containing {"policies": [...]},
+// a bare array of policies or a single policy document. Legacy policies (rules,
+// actions, resource_patterns) are converted to statements with policy.FromLegacy.
+func loadPolicies(filename string) ([]*models.Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	var documents []json.RawMessage
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		err = json.Unmarshal(data, &documents)
+	default:
+		var document struct {
+			Policies []json.RawMessage `json:"policies"`
+		}
+		if err = json.Unmarshal(data, &document); err == nil && document.Policies != nil {
+			documents = document.Policies
+			break
+		}
+		documents = []json.RawMessage{data}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+	}
+
+	policies := make([]*models.Policy, 0, len(documents))
+	for _, document := range documents {
+		decoded, err := policy.DecodePolicy(document)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+		}
+		if decoded.ID == "" {
+			return nil, fmt.Errorf("invalid policy file %s: policy without id", filename)
+		}
+		policies = append(policies, decoded)
+	}
+	return policies, nil
+}
+
+// fileSubject is a subject described by stored and command-line attributes
+type fileSubject struct {
+	id         string
+	attributes map[string]interface{}
+}
+
+func (s *fileSubject) GetID() string { return s.id }
+
+func (s *fileSubject) GetType() models.SubjectType {
+	if subjectType, ok := s.attributes["subject_type"].(string); ok && subjectType != "" {
+		return models.SubjectType(subjectType)
+	}
+	return models.SubjectTypeUser
+}
+
+func (s *fileSubject) GetAttributes() map[string]interface{} {
+	attributes := make(map[string]interface{}, len(s.attributes)+1)
+	for key, value := range s.attributes {
+		attributes[key] = value
+	}
+	attributes["id"] = s.id
+	return attributes
+}
+
+func (s *fileSubject) GetDisplayName() string { return s.id }
+
+func (s *fileSubject) IsActive() bool { return true }
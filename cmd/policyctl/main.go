@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// errNotPermitted is returned by commands whose evaluated decision was not a permit
+var errNotPermitted = errors.New("request not permitted")
+
+const usage = `policyctl - local policy debugging tool
+
+Usage:
+  policyctl <command> [flags]
+
+Commands:
+  evaluate   Evaluate a single request and print the decision with a statement trace
+  whatif     Interactively tweak attributes and see which conditions flip the decision
+  bundle     Generate keys, sign and verify signed policy bundles
+  reconcile  Diff a directory of manifests against storage (DB_DRIVER) and apply the drift
+  notation   Report deprecated condition notation (user:department keys, nonstandard operator names)
+  diff-decisions
+             Evaluate a request corpus against two policy directories and report changed decisions
+  encrypt-attributes
+             Re-encrypt stored attributes (DB_DRIVER) with the current ABAC_ATTRIBUTE_ENCRYPTION_KEYS key
+
+Run "policyctl <command> -h" for command flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run dispatches the subcommand and maps its outcome to an exit status:
+// 0 permitted, 1 error, 2 evaluated but not permitted (or, for reconcile, drift left unapplied;
+// for notation, deprecated keys or operators found; for diff-decisions, decisions that differ).
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 1
+	}
+
+	var err error
+	switch args[0] {
+	case "evaluate":
+		err = runEvaluate(args[1:], stdout)
+	case "whatif":
+		err = runWhatif(args[1:], stdin, stdout)
+	case "bundle":
+		err = runBundle(args[1:], stdout)
+	case "reconcile":
+		err = runReconcile(args[1:], stdout)
+	case "notation":
+		err = runNotation(args[1:], stdout)
+	case "diff-decisions":
+		err = runDiffDecisions(args[1:], stdout)
+	case "encrypt-attributes":
+		err = runEncryptAttributes(args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 1
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errNotPermitted), errors.Is(err, errDrift), errors.Is(err, errDeprecatedKeys), errors.Is(err, errDecisionsDiffer):
+		return 2
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	default:
+		fmt.Fprintf(stderr, "❌ %v\n", err)
+		return 1
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"abac_go_example/evaluator/path"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// errDeprecatedKeys is returned by "notation" when policies still use colon notation keys or
+// nonstandard operator spellings
+var errDeprecatedKeys = errors.New("policies use deprecated condition notation")
+
+// notationFinding is one policy statement with colon notation condition keys or nonstandard operators
+type notationFinding struct {
+	PolicyID  string                     `json:"policy_id"`
+	Statement int                        `json:"statement"`
+	Sid       string                     `json:"sid,omitempty"`
+	Keys      []path.DeprecatedKey       `json:"keys"`
+	Operators []path.NonstandardOperator `json:"operators,omitempty"`
+}
+
+// runNotation implements "policyctl notation": a compatibility report listing condition keys
+// written as "user:department" instead of "user.department" and operators spelled other than
+// their canonical name ("stringequals", "Boolean"), read from -policies or from the storage
+// selected by DB_DRIVER
+func runNotation(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("notation", flag.ContinueOnError)
+	policiesFile := fs.String("policies", "", "policy JSON file; storage (DB_DRIVER) is scanned when omitted")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var policies []*models.Policy
+	if *policiesFile != "" {
+		loaded, err := loadPolicies(*policiesFile)
+		if err != nil {
+			return err
+		}
+		policies = loaded
+	} else {
+		store, err := openStorage(os.Getenv("DB_DRIVER"))
+		if err != nil {
+			return fmt.Errorf("failed to open storage: %w", err)
+		}
+		defer store.Close()
+		if policies, err = allPolicies(store); err != nil {
+			return err
+		}
+	}
+
+	findings := notationFindings(policies)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return err
+		}
+	} else {
+		for _, finding := range findings {
+			label := fmt.Sprintf("%s statement[%d]", finding.PolicyID, finding.Statement)
+			if finding.Sid != "" {
+				label += " (" + finding.Sid + ")"
+			}
+			for _, key := range finding.Keys {
+				fmt.Fprintf(stdout, "%s: %s %q -> %q\n", label, key.Operator, key.Key, key.Preferred)
+			}
+			for _, operator := range finding.Operators {
+				fmt.Fprintf(stdout, "%s: operator %q -> %q\n", label, operator.Operator, operator.Canonical)
+			}
+		}
+		fmt.Fprintf(stdout, "%d of %d policies use deprecated notation.\n", countPolicies(findings), len(policies))
+	}
+
+	if len(findings) > 0 {
+		return errDeprecatedKeys
+	}
+	return nil
+}
+
+// notationFindings scans the statements of every policy, including disabled ones
+func notationFindings(policies []*models.Policy) []notationFinding {
+	findings := []notationFinding{}
+	for _, policy := range policies {
+		for i, statement := range policy.Statement {
+			keys := path.DeprecatedConditionKeys(statement.Condition)
+			operators := path.NonstandardOperators(statement.Condition)
+			if len(keys) > 0 || len(operators) > 0 {
+				findings = append(findings, notationFinding{
+					PolicyID: policy.ID, Statement: i, Sid: statement.Sid, Keys: keys, Operators: operators,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func countPolicies(findings []notationFinding) int {
+	seen := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		seen[finding.PolicyID] = true
+	}
+	return len(seen)
+}
+
+// allPolicies pages through every stored policy
+func allPolicies(store storage.Storage) ([]*models.Policy, error) {
+	var policies []*models.Policy
+	opts := storage.ListOptions{Limit: storage.MaxListLimit}
+	for {
+		page, info, err := store.ListPolicies(opts)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, page...)
+		if info.NextCursor == "" {
+			return policies, nil
+		}
+		opts.Cursor = info.NextCursor
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNotationCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"notation", "-policies", examplePolicies}, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit 2 for deprecated keys, got %d (stderr: %s)", code, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		`pol-001 statement[1] (DepartmentDocumentsRead): StringNotEquals "resource:Sensitivity" -> "resource.Sensitivity"`,
+		"5 of 6 policies use deprecated notation.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestNotationCommandClean(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.json")
+	policy := `{"id": "pol-dot", "policy_name": "Dot", "version": "1", "enabled": true, "statement": [{
+		"Effect": "Allow", "Action": "read", "Resource": "*",
+		"Condition": {"StringEquals": {"user.department": "Engineering"}, "ResourceTag": {"env:prod": "*"}}
+	}]}`
+	if err := os.WriteFile(file, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"notation", "-policies", file, "-json"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	var findings []notationFinding
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil || len(findings) != 0 {
+		t.Errorf("expected no findings, got %s (%v)", stdout.String(), err)
+	}
+}
+
+func TestNotationCommandOperators(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.json")
+	policy := `{"id": "pol-ops", "policy_name": "Ops", "version": "1", "enabled": true, "statement": [{
+		"Sid": "Lowercase", "Effect": "Allow", "Action": "read", "Resource": "*",
+		"Condition": {"stringequals": {"user.department": "Engineering"}}
+	}]}`
+	if err := os.WriteFile(file, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"notation", "-policies", file}, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit 2 for nonstandard operators, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := `pol-ops statement[0] (Lowercase): operator "stringequals" -> "StringEquals"`
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected output to contain %q, got:\n%s", want, stdout.String())
+	}
+}
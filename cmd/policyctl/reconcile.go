@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"abac_go_example/reconcile"
+	"abac_go_example/storage"
+)
+
+// errDrift is returned by "reconcile" when storage differs from the manifests and -apply was not set
+var errDrift = errors.New("storage differs from the manifests")
+
+// runReconcile implements "policyctl reconcile". It connects to the storage selected by DB_DRIVER
+// (with the service's database environment variables) and plans, or with -apply applies, the changes.
+func runReconcile(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	dir := fs.String("dir", "", "directory of JSON manifests")
+	apply := fs.Bool("apply", false, "apply the planned changes instead of only reporting drift")
+	prune := fs.Bool("prune", false, "delete entities of managed kinds missing from the manifests")
+	actor := fs.String("actor", reconcile.DefaultActor, "actor recorded in the policy change audit trail")
+	asJSON := fs.Bool("json", false, "print the plan and report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("-dir is required")
+	}
+
+	desired, err := reconcile.LoadDir(*dir)
+	if err != nil {
+		return err
+	}
+	store, err := openStorage(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer store.Close()
+
+	reconciler := reconcile.NewReconciler(store, *prune, *actor)
+	plan, err := reconciler.Plan(desired)
+	if err != nil {
+		return err
+	}
+	result := &reconcile.Result{Plan: plan}
+	if *apply && plan.HasDrift() {
+		result.Report = reconciler.Apply(plan)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printResult(stdout, result)
+	}
+
+	switch {
+	case result.Report != nil && result.Report.Failed > 0:
+		return fmt.Errorf("%d of %d changes failed", result.Report.Failed, len(plan.Changes))
+	case !*apply && plan.HasDrift():
+		return errDrift
+	}
+	return nil
+}
+
+// printResult writes the plan in a terraform-like format followed by the apply summary
+func printResult(w io.Writer, result *reconcile.Result) {
+	symbols := map[reconcile.Op]string{reconcile.OpCreate: "+", reconcile.OpUpdate: "~", reconcile.OpDelete: "-"}
+	for _, change := range result.Plan.Changes {
+		line := fmt.Sprintf("%s %s/%s", symbols[change.Op], change.Kind, change.ID)
+		if len(change.Fields) > 0 {
+			line += " (" + strings.Join(change.Fields, ", ") + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+	for _, ref := range result.Plan.Unmanaged {
+		fmt.Fprintf(w, "? %s/%s (unmanaged, kept without -prune)\n", ref.Kind, ref.ID)
+	}
+
+	if !result.Plan.HasDrift() {
+		fmt.Fprintln(w, "No changes. Storage matches the manifests.")
+		return
+	}
+	if result.Report == nil {
+		fmt.Fprintf(w, "Plan: %d changes. Run with -apply to apply them.\n", len(result.Plan.Changes))
+		return
+	}
+	fmt.Fprintf(w, "Applied: %d, failed: %d\n", result.Report.Applied, result.Report.Failed)
+	for _, changeErr := range result.Report.Errors {
+		fmt.Fprintf(w, "  ❌ %s %s/%s: %s\n", changeErr.Op, changeErr.Kind, changeErr.ID, changeErr.Error)
+	}
+}
+
+// openStorage opens the storage backend selected by DB_DRIVER ("postgres" or "sqlite"), like the service
+func openStorage(driver string) (storage.Storage, error) {
+	switch driver {
+	case "", "postgres":
+		postgresStorage, err := storage.NewPostgreSQLStorage(storage.DefaultDatabaseConfig())
+		if err != nil {
+			return nil, err
+		}
+		return postgresStorage, nil
+	case "sqlite":
+		sqliteStorage, err := storage.NewSQLiteStorage(storage.DefaultSQLiteConfig())
+		if err != nil {
+			return nil, err
+		}
+		return sqliteStorage, nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+}
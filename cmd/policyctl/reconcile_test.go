@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReconcileCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(dir, "abac.db"))
+
+	manifests := filepath.Join(dir, "manifests")
+	os.Mkdir(manifests, 0o755)
+	os.WriteFile(filepath.Join(manifests, "subjects.json"), []byte(`{
+  "subjects": [{"id": "sub-001", "subject_type": "user", "attributes": {"department": "engineering"}}]
+}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"reconcile", "-dir", manifests}, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit 2 for unapplied drift, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "+ subjects/sub-001") {
+		t.Errorf("expected the create in the plan, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"reconcile", "-dir", manifests, "-apply"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 after apply, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Applied: 1, failed: 0") {
+		t.Errorf("expected an apply summary, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"reconcile", "-dir", manifests}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 without drift, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No changes") {
+		t.Errorf("expected no changes, got:\n%s", stdout.String())
+	}
+}
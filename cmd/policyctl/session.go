@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// sourceOptions are the flags shared by commands that evaluate requests
+type sourceOptions struct {
+	policyFile  string
+	useDB       bool
+	requestFile string
+	subjectID   string
+	resourceID  string
+	action      string
+	subject     attributeFlag
+	resource    attributeFlag
+	context     attributeFlag
+	unredacted  bool
+	debug       bool
+}
+
+func newSourceOptions() *sourceOptions {
+	return &sourceOptions{
+		subject:  attributeFlag{},
+		resource: attributeFlag{},
+		context:  attributeFlag{},
+	}
+}
+
+// register adds the shared flags to a command's flag set
+func (o *sourceOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.policyFile, "policies", "", "policy JSON file ({\"policies\": [...]}, an array or a single policy)")
+	fs.BoolVar(&o.useDB, "db", false, "load policies, subject and resource from PostgreSQL (DB_* environment variables)")
+	fs.StringVar(&o.requestFile, "request", "", "request JSON file; flags override its values")
+	fs.StringVar(&o.subjectID, "subject", "", "subject ID")
+	fs.StringVar(&o.resourceID, "resource", "", "resource ID")
+	fs.StringVar(&o.action, "action", "", "action name")
+	fs.Var(o.subject, "subject-attr", "subject attribute key=value (repeatable)")
+	fs.Var(o.resource, "resource-attr", "resource attribute key=value (repeatable)")
+	fs.Var(o.context, "context", "request context key=value (repeatable)")
+	fs.BoolVar(&o.unredacted, "unredacted", false, "show raw attribute values in decision reasons")
+	fs.BoolVar(&o.debug, "debug", false, "show PDP debug logs")
+}
+
+// resolveRequest merges the request file (if any) with command-line flags
+func (o *sourceOptions) resolveRequest() (*requestFile, error) {
+	request := &requestFile{}
+	if o.requestFile != "" {
+		loaded, err := loadRequestFile(o.requestFile)
+		if err != nil {
+			return nil, err
+		}
+		request = loaded
+	}
+
+	if o.subjectID != "" {
+		request.SubjectID = o.subjectID
+	}
+	if o.resourceID != "" {
+		request.ResourceID = o.resourceID
+	}
+	if o.action != "" {
+		request.Action = o.action
+	}
+	request.SubjectAttributes = mergeMaps(request.SubjectAttributes, o.subject)
+	request.ResourceAttributes = mergeMaps(request.ResourceAttributes, o.resource)
+	request.Context = mergeMaps(request.Context, o.context)
+
+	if request.SubjectID == "" || request.ResourceID == "" || request.Action == "" {
+		return nil, errors.New("subject, resource and action are required (flags or -request file)")
+	}
+	return request, nil
+}
+
+// session evaluates requests locally against an in-memory copy of the policy set.
+// Subject and resource attributes are rebuilt from the stored base values plus the
+// request's inline attributes on every evaluation, so callers may edit the request.
+type session struct {
+	store        *storage.MockStorage
+	pdp          core.PolicyDecisionPointInterface
+	request      *requestFile
+	baseSubject  map[string]interface{}
+	baseResource map[string]interface{}
+	resourceType string
+}
+
+// newSession loads the policy set from the configured source and builds a PDP over it
+func newSession(o *sourceOptions) (*session, error) {
+	if o.useDB == (o.policyFile != "") {
+		return nil, errors.New("exactly one of -policies or -db is required")
+	}
+
+	if !o.debug {
+		log.SetOutput(io.Discard)
+	}
+
+	request, err := o.resolveRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{
+		store:   storage.NewMockStorage(),
+		request: request,
+	}
+
+	if o.useDB {
+		err = s.loadFromDatabase()
+	} else {
+		var policies []*models.Policy
+		policies, err = loadPolicies(o.policyFile)
+		s.store.SetPolicies(policies)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.pdp = newLocalPDP(s.store, o.unredacted)
+	return s, nil
+}
+
+// newLocalPDP builds a PDP over store without statistics, optionally showing raw attribute values in reasons
+func newLocalPDP(store storage.Storage, unredacted bool) core.PolicyDecisionPointInterface {
+	config := core.DefaultPDPConfig()
+	config.EnableStats = false
+	if unredacted {
+		config.Redactor = nil
+	}
+	return core.NewPolicyDecisionPointWithConfig(store, config)
+}
+
+// loadFromDatabase copies the policies, subject attributes and resource from PostgreSQL
+func (s *session) loadFromDatabase() error {
+	pgStorage, err := storage.NewPostgreSQLStorage(storage.DefaultDatabaseConfig())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pgStorage.Close()
+
+	policies, err := pgStorage.GetPolicies()
+	if err != nil {
+		return fmt.Errorf("failed to load policies: %w", err)
+	}
+	s.store.SetPolicies(policies)
+
+	factory := models.NewSubjectFactory(storage.NewStorageUserLoader(pgStorage), storage.NewStorageServiceLoader(pgStorage))
+	subject, err := factory.CreateFromSubjectID(s.request.SubjectID)
+	if err != nil {
+		return err
+	}
+	s.baseSubject = subject.GetAttributes()
+
+	if resource, err := pgStorage.GetResource(s.request.ResourceID); err == nil && resource != nil {
+		s.baseResource = resource.Attributes
+		s.resourceType = resource.ResourceType
+	}
+	return nil
+}
+
+// explain evaluates the current request and returns the decision with statement traces
+func (s *session) explain() (*models.Explanation, error) {
+	request, err := s.evaluationRequest()
+	if err != nil {
+		return nil, err
+	}
+	return s.pdp.Explain(request)
+}
+
+// evaluationRequest stores the current request's resource and action and builds its evaluation request
+func (s *session) evaluationRequest() (*models.EvaluationRequest, error) {
+	request := s.request
+
+	resourceAttrs := mergeMaps(s.baseResource, request.ResourceAttributes)
+	resourceType := s.resourceType
+	if value, ok := resourceAttrs["resource_type"].(string); ok {
+		resourceType = value
+	}
+	if err := s.store.CreateResource(&models.Resource{
+		ID:           request.ResourceID,
+		ResourceID:   request.ResourceID,
+		ResourceType: resourceType,
+		Attributes:   models.JSONMap(resourceAttrs),
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateAction(&models.Action{ID: request.Action, ActionName: request.Action}); err != nil {
+		return nil, err
+	}
+
+	requestID := request.RequestID
+	if requestID == "" {
+		requestID = "policyctl"
+	}
+	return &models.EvaluationRequest{
+		RequestID: requestID,
+		Subject: &fileSubject{
+			id:         request.SubjectID,
+			attributes: mergeMaps(s.baseSubject, request.SubjectAttributes),
+		},
+		ResourceID:  request.ResourceID,
+		Action:      request.Action,
+		Context:     mergeMaps(nil, request.Context),
+		Environment: request.Environment,
+		Timestamp:   request.Timestamp,
+		Session:     request.Session,
+	}, nil
+}
+
+// mergeMaps returns a new map holding base overlaid with overrides
+func mergeMaps(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
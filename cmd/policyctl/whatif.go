@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"abac_go_example/models"
+)
+
+const whatifHelp = `Commands:
+  set <target>=<value>   change an attribute and re-evaluate
+  unset <target>         remove an attribute and re-evaluate
+  show                   print the current request
+  trace                  print the full statement trace
+  reset                  restore the initial request
+  help                   show this help
+  quit                   leave the session
+
+Targets: user.<key> (or subject.<key>), resource.<key>, context.<key>, action, resource
+Values are parsed as JSON literals when possible (9, true, ["a","b"]).
+`
+
+// runWhatif implements "policyctl whatif": an interactive loop that re-evaluates
+// the request after every attribute change and reports which conditions flipped.
+func runWhatif(args []string, stdin io.Reader, stdout io.Writer) error {
+	options := newSourceOptions()
+	fs := flag.NewFlagSet("whatif", flag.ContinueOnError)
+	options.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := newSession(options)
+	if err != nil {
+		return err
+	}
+	initial := cloneRequest(s.request)
+
+	current, err := s.explain()
+	if err != nil {
+		return err
+	}
+	printExplanation(stdout, current, false)
+	fmt.Fprintln(stdout, `Type "help" for commands.`)
+
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			return scanner.Err()
+		}
+
+		command, argument, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		argument = strings.TrimSpace(argument)
+
+		var commandErr error
+		switch command {
+		case "":
+			continue
+		case "quit", "exit":
+			return nil
+		case "help":
+			fmt.Fprint(stdout, whatifHelp)
+			continue
+		case "show":
+			printRequest(stdout, s.request)
+			continue
+		case "trace":
+			printExplanation(stdout, current, true)
+			continue
+		case "set":
+			commandErr = applySet(s.request, argument)
+		case "unset":
+			commandErr = applyUnset(s.request, argument)
+		case "reset":
+			s.request = cloneRequest(initial)
+		default:
+			commandErr = fmt.Errorf("unknown command %q", command)
+		}
+		if commandErr != nil {
+			fmt.Fprintf(stdout, "❌ %v\n", commandErr)
+			continue
+		}
+
+		next, err := s.explain()
+		if err != nil {
+			fmt.Fprintf(stdout, "❌ %v\n", err)
+			continue
+		}
+		printFlips(stdout, current, next)
+		current = next
+	}
+}
+
+// applySet handles "set <target>=<value>"
+func applySet(request *requestFile, argument string) error {
+	target, value, err := parseAssignment(argument)
+	if err != nil {
+		return err
+	}
+
+	switch target {
+	case "action":
+		request.Action = fmt.Sprint(value)
+		return nil
+	case "resource":
+		request.ResourceID = fmt.Sprint(value)
+		return nil
+	}
+
+	attributes, key, err := resolveTarget(request, target)
+	if err != nil {
+		return err
+	}
+	attributes[key] = value
+	return nil
+}
+
+// applyUnset handles "unset <target>"
+func applyUnset(request *requestFile, target string) error {
+	attributes, key, err := resolveTarget(request, target)
+	if err != nil {
+		return err
+	}
+	delete(attributes, key)
+	return nil
+}
+
+// resolveTarget maps "scope.key" to the request attribute map it addresses
+func resolveTarget(request *requestFile, target string) (map[string]interface{}, string, error) {
+	scope, key, found := strings.Cut(target, ".")
+	if !found || key == "" {
+		return nil, "", fmt.Errorf("target must be <scope>.<key>, got %q", target)
+	}
+
+	switch scope {
+	case "user", "subject":
+		return request.SubjectAttributes, key, nil
+	case "resource":
+		return request.ResourceAttributes, key, nil
+	case "context":
+		return request.Context, key, nil
+	default:
+		return nil, "", fmt.Errorf("unknown scope %q (use user, resource or context)", scope)
+	}
+}
+
+// printFlips reports the decision change and every statement or condition whose outcome flipped
+func printFlips(w io.Writer, before, after *models.Explanation) {
+	if before.Decision.Result == after.Decision.Result {
+		fmt.Fprintf(w, "Decision: %s (unchanged)\n", strings.ToUpper(after.Decision.Result))
+	} else {
+		fmt.Fprintf(w, "Decision: %s → %s\n", strings.ToUpper(before.Decision.Result), strings.ToUpper(after.Decision.Result))
+	}
+	if after.Decision.Reason != "" {
+		fmt.Fprintf(w, "Reason:   %s\n", after.Decision.Reason)
+	}
+
+	previous := make(map[string]bool)
+	for _, trace := range before.Statements {
+		previous[statementKey(trace)] = trace.Matched
+		for _, condition := range trace.Conditions {
+			previous[conditionKey(trace, condition)] = condition.Matched
+		}
+	}
+
+	var flips []string
+	for _, trace := range after.Statements {
+		for _, condition := range trace.Conditions {
+			if was, ok := previous[conditionKey(trace, condition)]; ok && was != condition.Matched {
+				flips = append(flips, fmt.Sprintf("  condition %s %s %s: %s → %s",
+					statementKey(trace), condition.Operator, orDash(condition.Key), mark(was), mark(condition.Matched)))
+			}
+		}
+		if was, ok := previous[statementKey(trace)]; ok && was != trace.Matched {
+			flips = append(flips, fmt.Sprintf("  statement %s (%s): %s → %s",
+				statementKey(trace), trace.Effect, mark(was), mark(trace.Matched)))
+		}
+	}
+
+	if len(flips) == 0 {
+		fmt.Fprintln(w, "No conditions flipped")
+		return
+	}
+	fmt.Fprintln(w, "Flipped:")
+	for _, flip := range flips {
+		fmt.Fprintln(w, flip)
+	}
+}
+
+func statementKey(trace models.StatementTrace) string {
+	return trace.PolicyID + "/" + orDash(trace.Sid)
+}
+
+func conditionKey(trace models.StatementTrace, condition models.ConditionTrace) string {
+	return statementKey(trace) + "|" + condition.Operator + "|" + condition.Key
+}
+
+// printRequest writes the current request attributes in a stable order
+func printRequest(w io.Writer, request *requestFile) {
+	fmt.Fprintf(w, "subject:  %s\nresource: %s\naction:   %s\n", request.SubjectID, request.ResourceID, request.Action)
+	printAttributes(w, "user", request.SubjectAttributes)
+	printAttributes(w, "resource", request.ResourceAttributes)
+	printAttributes(w, "context", request.Context)
+}
+
+func printAttributes(w io.Writer, scope string, attributes map[string]interface{}) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s.%s = %v\n", scope, key, attributes[key])
+	}
+}
+
+// cloneRequest copies the request and its attribute maps
+func cloneRequest(request *requestFile) *requestFile {
+	clone := *request
+	clone.SubjectAttributes = mergeMaps(nil, request.SubjectAttributes)
+	clone.ResourceAttributes = mergeMaps(nil, request.ResourceAttributes)
+	clone.Context = mergeMaps(nil, request.Context)
+	return &clone
+}
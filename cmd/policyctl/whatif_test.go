@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWhatifCommand(t *testing.T) {
+	script := strings.Join([]string{
+		"set resource.Sensitivity=confidential",
+		"set action=document-service:file:delete",
+		"set planet.name=mars",
+		"reset",
+		"quit",
+	}, "\n")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"whatif", "-policies", examplePolicies,
+		"-subject", "user-1", "-subject-attr", "Department=engineering",
+		"-resource", "api:documents:dept-engineering", "-action", "document-service:file:read"}
+	if code := run(args, strings.NewReader(script), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	output := stdout.String()
+	expected := []string{
+		"condition pol-001/DepartmentDocumentsRead StringNotEquals resource:Sensitivity: ✓ → ✗",
+		"Decision: PERMIT → DENY",
+		"statement pol-001/DenyConfidentialDelete (Deny): ✗ → ✓",
+		`unknown scope "planet"`,
+		"Decision: DENY → PERMIT",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -28,21 +28,29 @@ const (
 	MaxContextMapSize     = 200 // Maximum allowed context map size
 )
 
-// Mobile user agent detection patterns
-var MobileUserAgentPatterns = []string{
-	"(?i)mobile",
-	"(?i)android",
-	"(?i)iphone",
-	"(?i)ipad",
-	"(?i)blackberry",
-	"(?i)windows phone",
-}
+// User agent attribute values produced by operators.ParseUserAgent
+const (
+	UserAgentUnknown = "unknown"
 
-// Browser detection patterns
-var BrowserPatterns = map[string]string{
-	"(?i)chrome":  "chrome",
-	"(?i)firefox": "firefox",
-	"(?i)safari":  "safari",
-	"(?i)edge":    "edge",
-	"(?i)opera":   "opera",
-}
+	DeviceTypeMobile  = "mobile"
+	DeviceTypeTablet  = "tablet"
+	DeviceTypeDesktop = "desktop"
+	DeviceTypeBot     = "bot"
+
+	OSIOS          = "ios"
+	OSAndroid      = "android"
+	OSWindows      = "windows"
+	OSWindowsPhone = "windows_phone"
+	OSMacOS        = "macos"
+	OSLinux        = "linux"
+	OSChromeOS     = "chromeos"
+	OSBlackBerry   = "blackberry"
+
+	BrowserChrome  = "chrome"
+	BrowserSafari  = "safari"
+	BrowserFirefox = "firefox"
+	BrowserEdge    = "edge"
+	BrowserOpera   = "opera"
+	BrowserSamsung = "samsung"
+	BrowserIE      = "ie"
+)
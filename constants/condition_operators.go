@@ -10,6 +10,14 @@ const (
 	ConditionStringLike      ConditionOperatorType = "StringLike"
 )
 
+// Condition operator constants for version operations
+const (
+	ConditionVersionLessThan          ConditionOperatorType = "VersionLessThan"
+	ConditionVersionLessThanEquals    ConditionOperatorType = "VersionLessThanEquals"
+	ConditionVersionGreaterThan       ConditionOperatorType = "VersionGreaterThan"
+	ConditionVersionGreaterThanEquals ConditionOperatorType = "VersionGreaterThanEquals"
+)
+
 // Condition operator constants for numeric operations
 const (
 	ConditionNumericLessThan          ConditionOperatorType = "NumericLessThan"
@@ -21,17 +29,43 @@ const (
 // Condition operator constants for boolean operations
 const (
 	ConditionBool ConditionOperatorType = "Bool"
+	ConditionNull ConditionOperatorType = "Null"
 )
 
 // Condition operator constants for network operations
 const (
+	ConditionIPInRange    ConditionOperatorType = "IPInRange"
+	ConditionIPNotInRange ConditionOperatorType = "IPNotInRange"
+
+	// Deprecated: IpAddress is not evaluated and fails validation; use IPInRange
 	ConditionIpAddress ConditionOperatorType = "IpAddress"
 )
 
+// Condition operator constants for client certificate operations
+const (
+	ConditionIssuedByCA ConditionOperatorType = "IssuedByCA"
+)
+
 // Condition operator constants for date/time operations
 const (
 	ConditionDateGreaterThan ConditionOperatorType = "DateGreaterThan"
 	ConditionDateLessThan    ConditionOperatorType = "DateLessThan"
+	ConditionAuthAgeLessThan ConditionOperatorType = "AuthAgeLessThan"
+	ConditionTimeBetween     ConditionOperatorType = "TimeBetween"
+	ConditionTimeOfDay       ConditionOperatorType = "TimeOfDay"
+	ConditionIsBusinessHours ConditionOperatorType = "IsBusinessHours"
+	ConditionIsHoliday       ConditionOperatorType = "IsHoliday"
+)
+
+// Condition operator constants for quota operations
+const (
+	ConditionRequestRateBelow ConditionOperatorType = "RequestRateBelow"
+	ConditionDailyQuotaBelow  ConditionOperatorType = "DailyQuotaBelow"
+)
+
+// Condition operator constants for resource tag operations
+const (
+	ConditionResourceTag ConditionOperatorType = "ResourceTag"
 )
 
 // Condition operator constants for logical operations
@@ -51,10 +85,25 @@ func AllConditionOperatorTypes() []ConditionOperatorType {
 		ConditionNumericLessThanEquals,
 		ConditionNumericGreaterThan,
 		ConditionNumericGreaterThanEquals,
+		ConditionVersionLessThan,
+		ConditionVersionLessThanEquals,
+		ConditionVersionGreaterThan,
+		ConditionVersionGreaterThanEquals,
 		ConditionBool,
-		ConditionIpAddress,
+		ConditionNull,
+		ConditionIPInRange,
+		ConditionIPNotInRange,
+		ConditionIssuedByCA,
 		ConditionDateGreaterThan,
 		ConditionDateLessThan,
+		ConditionAuthAgeLessThan,
+		ConditionTimeBetween,
+		ConditionTimeOfDay,
+		ConditionIsBusinessHours,
+		ConditionIsHoliday,
+		ConditionRequestRateBelow,
+		ConditionDailyQuotaBelow,
+		ConditionResourceTag,
 		ConditionAnd,
 		ConditionOr,
 		ConditionNot,
@@ -66,30 +115,42 @@ func (cot ConditionOperatorType) String() string {
 	return string(cot)
 }
 
-// IsValid checks if the condition operator type is valid
+// IsValid checks if the condition operator type is supported, in any spelling accepted by ResolveOperator
 func (cot ConditionOperatorType) IsValid() bool {
-	for _, validOp := range AllConditionOperatorTypes() {
-		if cot == validOp {
-			return true
-		}
-	}
-	return false
+	return ResolveOperator(string(cot)).Known
+}
+
+// Canonical returns the standard spelling of the operator, e.g. "StringEquals" for "stringequals"
+func (cot ConditionOperatorType) Canonical() ConditionOperatorType {
+	return ConditionOperatorType(ResolveOperator(string(cot)).Canonical)
 }
 
 // GetOperatorCategory returns the category of the operator (string, numeric, boolean, etc.)
 func (cot ConditionOperatorType) GetOperatorCategory() string {
-	switch cot {
+	switch cot.Canonical() {
 	case ConditionStringEquals, ConditionStringNotEquals, ConditionStringLike:
 		return "string"
 	case ConditionNumericLessThan, ConditionNumericLessThanEquals,
 		ConditionNumericGreaterThan, ConditionNumericGreaterThanEquals:
 		return "numeric"
-	case ConditionBool:
+	case ConditionVersionLessThan, ConditionVersionLessThanEquals,
+		ConditionVersionGreaterThan, ConditionVersionGreaterThanEquals:
+		return "version"
+	case ConditionBool, ConditionNull:
 		return "boolean"
-	case ConditionIpAddress:
+	case ConditionIsBusinessHours, ConditionIsHoliday:
+		return "date"
+	case ConditionIPInRange, ConditionIPNotInRange:
 		return "network"
-	case ConditionDateGreaterThan, ConditionDateLessThan:
+	case ConditionIssuedByCA:
+		return "certificate"
+	case ConditionDateGreaterThan, ConditionDateLessThan, ConditionAuthAgeLessThan,
+		ConditionTimeBetween, ConditionTimeOfDay:
 		return "date"
+	case ConditionRequestRateBelow, ConditionDailyQuotaBelow:
+		return "quota"
+	case ConditionResourceTag:
+		return "tag"
 	case ConditionAnd, ConditionOr, ConditionNot:
 		return "logical"
 	default:
@@ -2,18 +2,56 @@ package constants
 
 // Core context key constants
 const (
-	ContextKeyRequestUserID     = "request:UserId"
-	ContextKeyRequestAction     = "request:Action"
-	ContextKeyRequestResourceID = "request:ResourceId"
-	ContextKeyRequestTime       = "request:Time"
+	ContextKeyRequestUserID      = "request:UserId"
+	ContextKeyRequestAction      = "request:Action"
+	ContextKeyRequestResourceID  = "request:ResourceId"
+	ContextKeyRequestTime        = "request:Time"
+	ContextKeyResourceTags       = "resource:Tags"         // []string of "key=value" tags, read by ResourceTag conditions
+	ContextKeyResourceParams     = "resource:params"       // Path parameters captured by URL template Resource patterns (resource.params.id)
+	ContextKeyConditionMemo      = "_condition_memo"       // Request-scoped *conditions.ConditionMemo installed by the PDP
+	ContextKeyActionCategory     = "_action_category"      // Category of the stored action, read by default decision rules
+	ContextKeyPolicyWindowChange = "_policy_window_change" // time.Time of the next policy EffectiveFrom/ExpiresAt, read by decision TTLs
+	ContextKeyCanaryPolicies     = "_canary_policies"      // []*models.Policy held back by their rollout, evaluated in shadow
 )
 
 // Context key prefixes
 const (
-	ContextKeyUserPrefix        = "user:"
-	ContextKeyResourcePrefix    = "resource:"
-	ContextKeyEnvironmentPrefix = "environment:"
-	ContextKeyRequestPrefix     = "request:"
+	ContextKeyUserPrefix         = "user:"
+	ContextKeyResourcePrefix     = "resource:"
+	ContextKeyEnvironmentPrefix  = "environment:"
+	ContextKeyRequestPrefix      = "request:"
+	ContextKeySessionPrefix      = "session:"
+	ContextKeyRelationshipPrefix = "relationship:"
+	ContextKeyDevicePrefix       = "device:"
+)
+
+// Session context keys (structured under "session", flat under "session:")
+const (
+	ContextKeySession        = "session"
+	SessionKeyID             = "session_id"
+	SessionKeyAuthMethod     = "auth_method"
+	SessionKeyMFAVerified    = "mfa_verified"
+	SessionKeyAuthTime       = "auth_time"
+	SessionKeyAuthAgeSeconds = "auth_age_seconds"
+)
+
+// Relationship context keys (structured under "relationship", flat under "relationship:")
+const (
+	ContextKeyRelationship  = "relationship"
+	RelationshipKeyIsOwner  = "is_owner"
+	RelationshipKeySameTeam = "same_team"
+)
+
+// Device posture context keys (structured under "device", flat under "device:")
+const (
+	ContextKeyDevice            = "device"
+	DeviceKeyID                 = "device_id" // Also the request context/environment/subject attribute identifying the device
+	DeviceKeyManaged            = "managed"
+	DeviceKeyDiskEncrypted      = "disk_encrypted"
+	DeviceKeyOSPatchLevel       = "os_patch_level"
+	DeviceKeyPatchAgeDays       = "patch_age_days"
+	DeviceKeyCompliant          = "compliant"
+	DeviceKeyPostureUnavailable = "posture_unavailable"
 )
 
 // Enhanced context keys for improved features
@@ -24,6 +62,34 @@ const (
 	ContextKeyRegion    = "environment:region"
 	ContextKeyTimeOfDay = "environment:time_of_day"
 	ContextKeyDayOfWeek = "environment:day_of_week"
+	ContextKeyEvalTime  = "environment:timestamp" // RFC3339 evaluation time, read by clock windows with a tz
+)
+
+// User agent context keys, parsed from EnvironmentInfo.UserAgent
+const (
+	ContextKeyDeviceType     = "environment:device_type"
+	ContextKeyOS             = "environment:os"
+	ContextKeyOSVersion      = "environment:os_version"
+	ContextKeyBrowser        = "environment:browser"
+	ContextKeyBrowserVersion = "environment:browser_version"
+)
+
+// Client certificate context keys (fields of the structured "environment:client_cert" attribute)
+const (
+	ContextKeyClientCert        = "environment:client_cert"
+	ClientCertKeySubjectDN      = "subject_dn"
+	ClientCertKeyCommonName     = "common_name"
+	ClientCertKeyIssuerDN       = "issuer_dn"
+	ClientCertKeySerialNumber   = "serial_number"
+	ClientCertKeyFingerprint    = "fingerprint"
+	ClientCertKeyDNSNames       = "dns_names"
+	ClientCertKeyURIs           = "uris"
+	ClientCertKeyEmailAddresses = "email_addresses"
+	ClientCertKeyIPAddresses    = "ip_addresses"
+	ClientCertKeyNotBefore      = "not_before"
+	ClientCertKeyNotAfter       = "not_after"
+	ClientCertKeyVerified       = "verified"
+	ClientCertKeyIssuerChain    = "issuer_chain"
 )
 
 // Attribute resolver context keys
@@ -38,17 +104,37 @@ const (
 	ContextKeyDayOfWeekShort  = "day_of_week"
 	ContextKeyHour            = "hour"
 	ContextKeyIsBusinessHours = "is_business_hours"
+	ContextKeyIsHoliday       = "is_holiday"
+	ContextKeyHolidayName     = "holiday_name"
 	ContextKeyIsInternalIP    = "is_internal_ip"
 	ContextKeyIPSubnet        = "ip_subnet"
 
+	// Risk attributes contributed by a RiskProvider
+	ContextKeyRiskScore        = "risk_score"
+	ContextKeyImpossibleTravel = "impossible_travel"
+	ContextKeyNewDevice        = "new_device"
+	ContextKeyRiskFactors      = "risk_factors"
+	ContextKeyRiskUnavailable  = "risk_unavailable"
+
 	// Dynamic subject attributes
 	ContextKeyYearsOfService = "years_of_service"
 	ContextKeyCurrentHour    = "current_hour"
 	ContextKeyCurrentDay     = "current_day"
 
+	// Request-supplied attribute overrides
+	ContextKeySubjectAttributes  = "attributes"
+	ContextKeyResourceAttributes = "resource_attributes"
+
 	// Subject attribute keys
 	ContextKeyHireDate       = "hire_date"
 	ContextKeyDepartment     = "department"
 	ContextKeyRole           = "role"
 	ContextKeyClearanceLevel = "clearance_level"
+	ContextKeyTeamIDs        = "team_ids"
+	ContextKeyGroups         = "groups"
+	ContextKeyRoles          = "roles"
+
+	// Resource relationship attribute keys
+	ContextKeyOwnerID = "owner_id"
+	ContextKeyTeamID  = "team_id"
 )
@@ -13,6 +13,18 @@ const (
 	OpStringEndsWith   = "stringendswith"
 	OpStringRegex      = "stringregex"
 
+	// Accent-insensitive string operators ("Ky thuat" matches "Kỹ thuật")
+	OpStringEqualsIgnoreAccents     = "stringequalsignoreaccents"
+	OpStringNotEqualsIgnoreAccents  = "stringnotequalsignoreaccents"
+	OpStringContainsIgnoreAccents   = "stringcontainsignoreaccents"
+	OpStringStartsWithIgnoreAccents = "stringstartswithignoreaccents"
+
+	// Version operators (dotted numeric versions, e.g. environment.browser_version)
+	OpVersionLessThan          = "versionlessthan"
+	OpVersionLessThanEquals    = "versionlessthanequals"
+	OpVersionGreaterThan       = "versiongreaterthan"
+	OpVersionGreaterThanEquals = "versiongreaterthanequals"
+
 	// Numeric operators
 	OpNumericEquals            = "numericequals"
 	OpNumericNotEquals         = "numericnotequals"
@@ -36,6 +48,8 @@ const (
 	OpDayOfWeek             = "dayofweek"
 	OpTimeOfDay             = "timeofday"
 	OpIsBusinessHours       = "isbusinesshours"
+	OpIsHoliday             = "isholiday"
+	OpAuthAgeLessThan       = "authagelessthan"
 
 	// Array operators
 	OpArrayContains    = "arraycontains"
@@ -47,10 +61,23 @@ const (
 	OpIPNotInRange = "ipnotinrange"
 	OpIsInternalIP = "isinternalip"
 
+	// Certificate operators
+	OpIssuedByCA = "issuedbyca"
+
 	// Boolean operators
 	OpBool    = "bool"
 	OpBoolean = "boolean"
 
+	// Existence operator: {"attr": true} matches an absent attribute, {"attr": false} a present one
+	OpNull = "null"
+
+	// Quota operators
+	OpRequestRateBelow = "requestratebelow"
+	OpDailyQuotaBelow  = "dailyquotabelow"
+
+	// Resource tag operators
+	OpResourceTag = "resourcetag"
+
 	// Logical operators
 	OpAnd = "and"
 	OpOr  = "or"
@@ -120,6 +147,7 @@ const (
 const (
 	ContextKeyEnvironmentHour      = "environment.hour"
 	ContextKeyEnvironmentDayOfWeek = "environment.day_of_week"
+	ContextKeyEnvironmentIsHoliday = "environment.is_holiday"
 )
 
 // Default values
@@ -0,0 +1,75 @@
+package constants
+
+import "strings"
+
+// canonicalOperators lists the standard spelling of every condition operator the evaluator supports
+var canonicalOperators = []string{
+	"StringEquals", "StringNotEquals", "StringLike", "StringContains", "StringStartsWith", "StringEndsWith", "StringRegex",
+	"StringEqualsIgnoreAccents", "StringNotEqualsIgnoreAccents", "StringContainsIgnoreAccents", "StringStartsWithIgnoreAccents",
+	"VersionLessThan", "VersionLessThanEquals", "VersionGreaterThan", "VersionGreaterThanEquals",
+	"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals",
+	"NumericGreaterThan", "NumericGreaterThanEquals", "NumericBetween",
+	"DateLessThan", "TimeLessThan", "DateLessThanEquals", "TimeLessThanEquals",
+	"DateGreaterThan", "TimeGreaterThan", "DateGreaterThanEquals", "TimeGreaterThanEquals",
+	"DateBetween", "TimeBetween", "DayOfWeek", "TimeOfDay", "IsBusinessHours", "IsHoliday", "AuthAgeLessThan",
+	"ArrayContains", "ArrayNotContains", "ArraySize",
+	"IPInRange", "IPNotInRange", "IsInternalIP",
+	"IssuedByCA",
+	"Bool", "Null",
+	"RequestRateBelow", "DailyQuotaBelow",
+	"ResourceTag",
+	"And", "Or", "Not",
+}
+
+// OperatorAliases maps nonstandard operator names accepted for compatibility to their canonical name
+var OperatorAliases = map[string]string{
+	"Boolean": "Bool",
+}
+
+// OperatorName is a condition operator spelling resolved through the alias table
+type OperatorName struct {
+	Key         string // Lowercase key dispatched on by evaluators (the Op* constants)
+	Canonical   string // Standard spelling, e.g. "StringEquals"; the spelling itself when unknown
+	Nonstandard bool   // Spelled other than Canonical: a case variant or an alias
+	Known       bool   // Supported by the evaluator
+}
+
+var (
+	// exactOperators resolves canonical spellings and aliases without allocating
+	exactOperators = make(map[string]OperatorName)
+	// foldedOperators resolves any case variant by its lowercase spelling
+	foldedOperators = make(map[string]OperatorName)
+)
+
+func init() {
+	for _, canonical := range canonicalOperators {
+		name := OperatorName{Key: strings.ToLower(canonical), Canonical: canonical, Known: true}
+		exactOperators[canonical] = name
+		foldedOperators[name.Key] = name
+	}
+	for alias, canonical := range OperatorAliases {
+		name := exactOperators[canonical]
+		name.Nonstandard = true
+		exactOperators[alias] = name
+		foldedOperators[strings.ToLower(alias)] = name
+	}
+}
+
+// ResolveOperator canonicalizes a condition operator spelling. Matching is case-insensitive; any
+// spelling other than the canonical one is reported as Nonstandard.
+func ResolveOperator(operator string) OperatorName {
+	if name, ok := exactOperators[operator]; ok {
+		return name
+	}
+	key := strings.ToLower(operator)
+	if name, ok := foldedOperators[key]; ok {
+		name.Nonstandard = true
+		return name
+	}
+	return OperatorName{Key: key, Canonical: operator}
+}
+
+// OperatorKey returns the lowercase key evaluators dispatch a condition operator on
+func OperatorKey(operator string) string {
+	return ResolveOperator(operator).Key
+}
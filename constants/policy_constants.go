@@ -4,6 +4,7 @@ package constants
 const (
 	EffectAllow = "allow"
 	EffectDeny  = "deny"
+	EffectMask  = "mask" // Field-level statements only
 )
 
 // Decision result constants
@@ -12,17 +13,232 @@ const (
 	ResultDeny   = "deny"
 )
 
+// Statement obligations: instructions a decision carries to the components enforcing or recording it
+const (
+	ObligationAudit = "audit" // Audit verbosity of the request, one of the AuditLevel* values
+)
+
+// Audit verbosity levels of the audit obligation, from least to most verbose
+const (
+	AuditLevelMinimal  = "minimal"  // Decision and matched policies only
+	AuditLevelStandard = "standard" // The default audit entry
+	AuditLevelFull     = "full"     // Standard entry plus the enriched evaluation context snapshot
+)
+
 // Decision reason templates
 const (
 	ReasonDeniedByStatement   = "Denied by statement: %s"
 	ReasonAllowedByStatements = "Allowed by statements: %s"
 	ReasonImplicitDeny        = "No matching policies found (implicit deny)"
+	ReasonLockdown            = "Denied by lockdown (%s)"
+	ReasonAllowedByException  = "Allowed by exception %s: %s"
+	ReasonDeniedByException   = "Denied by exception %s: %s"
+	ReasonAccessRevoked       = "Access revoked by %s: %s"
+	ReasonDefaultPermit       = "No matching policies found (default permit by rule %s)"
+	ReasonReadOnlyPermit      = "Permit is read-only; %s is not allowed"
+	ReasonPolicyQuarantined   = "Indeterminate: quarantined policy %s may deny"
+)
+
+// Decision reason codes - stable identifiers for localized end-user messages
+const (
+	ReasonCodeDeniedByStatement   = "DENIED_BY_STATEMENT"
+	ReasonCodeAllowedByStatements = "ALLOWED_BY_STATEMENTS"
+	ReasonCodeImplicitDeny        = "IMPLICIT_DENY"
+	ReasonCodeInvalidRequest      = "INVALID_REQUEST"
+	ReasonCodeEvaluationError     = "EVALUATION_ERROR"
+	ReasonCodeLockdown            = "LOCKDOWN"
+	ReasonCodeAllowedByException  = "ALLOWED_BY_EXCEPTION"
+	ReasonCodeDeniedByException   = "DENIED_BY_EXCEPTION"
+	ReasonCodeAccessRevoked       = "ACCESS_REVOKED"
+	ReasonCodeDefaultPermit       = "DEFAULT_PERMIT"
+	ReasonCodeReadOnlyPermit      = "READ_ONLY_PERMIT" // Set by PEPs rejecting a mutating request under a read-only permit
+	ReasonCodePolicyQuarantined   = "POLICY_QUARANTINED"
+)
+
+// Reason detail keys carried in Decision.ReasonDetails
+const (
+	ReasonDetailStatement    = "statement"
+	ReasonDetailStatements   = "statements"
+	ReasonDetailError        = "error"
+	ReasonDetailLockdownMode = "lockdown_mode"
+	ReasonDetailException    = "exception"
+	ReasonDetailRevocation   = "revocation"
+	ReasonDetailExpiresAt    = "expires_at"
+	ReasonDetailDefaultRule  = "default_rule"
+	ReasonDetailPolicy       = "policy"
 )
 
 // Validation and performance constants
 const (
 	MaxConditionDepth      = 10   // Maximum depth for nested conditions
+	MaxStatementsPerPolicy = 100  // Maximum number of statements in a policy
+	MaxRegexLength         = 512  // Maximum length of a StringRegex pattern
+	MaxRegexProgramSize    = 1000 // Maximum compiled instructions of a StringRegex pattern, bounding counted repetitions like (\w{1,30}){1,30}
 	MaxConditionKeys       = 100  // Maximum number of condition keys
 	MaxEvaluationTimeMs    = 5000 // Maximum evaluation time in milliseconds
 	MinRequiredContextKeys = 3    // Minimum required context keys (action, resource, subject)
 )
+
+// Negative (deny) cache defaults and environment variables
+const (
+	DefaultDenyCacheTTLMs      = 2000                          // How long a deny is replayed for identical requests
+	DefaultDenyCacheMaxEntries = 10000                         // Maximum cached denies
+	EnvDenyCacheTTL            = "ABAC_DENY_CACHE_TTL"         // Duration, e.g. "2s"; unset or 0 disables the cache
+	EnvDenyCacheMaxEntries     = "ABAC_DENY_CACHE_MAX_ENTRIES" // Integer
+)
+
+// Attribute cache defaults and environment variables
+const (
+	DefaultAttributeCacheTTLMs      = 30000                               // How long looked-up subjects, resources and actions are reused
+	DefaultAttributeCacheMaxEntries = 10000                               // Maximum cached entities
+	EnvAttributeCacheTTL            = "ABAC_ATTRIBUTE_CACHE_TTL"          // Duration, e.g. "30s"; unset or 0 disables the cache
+	EnvAttributeCacheSubjectTTL     = "ABAC_ATTRIBUTE_CACHE_SUBJECT_TTL"  // Duration overriding the TTL for subjects; "0" disables subject caching
+	EnvAttributeCacheResourceTTL    = "ABAC_ATTRIBUTE_CACHE_RESOURCE_TTL" // Duration overriding the TTL for resources; "0" disables resource caching
+	EnvAttributeCacheActionTTL      = "ABAC_ATTRIBUTE_CACHE_ACTION_TTL"   // Duration overriding the TTL for actions; "0" disables action caching
+	EnvAttributeCacheMaxEntries     = "ABAC_ATTRIBUTE_CACHE_MAX_ENTRIES"  // Integer
+)
+
+// Prepared subject context defaults
+const (
+	DefaultPreparedSubjectTTLMs      = 900000 // How long a session's prepared subject context is reused
+	DefaultPreparedSubjectMaxEntries = 10000  // Maximum prepared sessions
+)
+
+// Action catalog environment variables
+const (
+	EnvActionCatalog = "ABAC_ACTION_CATALOG" // Path to a JSON action catalog of implied actions; unset disables implications
+)
+
+// Decision cache TTL hint defaults and environment variables
+const (
+	DefaultDecisionTTLSeconds = 300                 // CacheTTL of decisions that depend on no time-sensitive condition
+	EnvDecisionTTL            = "ABAC_DECISION_TTL" // Duration, e.g. "5m"; "0" disables the hint (CacheTTL 0)
+)
+
+// Default decision environment variables
+const (
+	EnvDefaultDecisions = "ABAC_DEFAULT_DECISIONS" // Path to a JSON list of default permit rules; unset keeps the implicit deny for every action
+)
+
+// Condition coverage environment variables
+const (
+	EnvConditionCoverage = "ABAC_CONDITION_COVERAGE" // "true" records which conditions live evaluations exercise
+)
+
+// Missing attribute telemetry environment variables
+const (
+	EnvMissingAttributeLogInterval = "ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL" // Duration, e.g. "1m"; "0" counts without warnings
+)
+
+// Policy quarantine environment variables
+const (
+	EnvPolicyQuarantineThreshold   = "ABAC_POLICY_QUARANTINE_THRESHOLD"   // Duration, e.g. "50ms"; evaluations of a policy taking longer are slow. Unset disables quarantine
+	EnvPolicyQuarantineConsecutive = "ABAC_POLICY_QUARANTINE_CONSECUTIVE" // Consecutive slow evaluations that quarantine a policy (default 5)
+	EnvPolicyQuarantineDuration    = "ABAC_POLICY_QUARANTINE_DURATION"    // How long a policy stays quarantined, e.g. "10m" (default); "0" until released via the API
+)
+
+// Policy compilation environment variables
+const (
+	EnvCompileMode = "ABAC_COMPILE_MODE" // "eager" compiles every policy at startup; unset or "lazy" compiles on first use
+)
+
+// Parallel policy evaluation defaults and environment variables
+const (
+	DefaultParallelMinPolicies = 64                                // Candidate policies below which evaluation stays sequential
+	EnvEvalParallelism         = "ABAC_EVAL_PARALLELISM"           // Worker count, e.g. "8"; unset, 0 or 1 evaluates sequentially
+	EnvEvalParallelMinPolicies = "ABAC_EVAL_PARALLEL_MIN_POLICIES" // Integer
+)
+
+// Policy namespace environment variables
+const (
+	EnvNamespace          = "ABAC_NAMESPACE" // Default evaluation namespace of this service; unset evaluates global policies only
+	MaxPolicyNamespaceLen = 100              // Maximum length of a policy namespace
+)
+
+// Holiday calendar environment variables
+const (
+	EnvHolidayCalendar        = "ABAC_HOLIDAY_CALENDAR"         // Path to a JSON holiday calendar; holidays are treated like weekends
+	EnvHolidayCalendarURL     = "ABAC_HOLIDAY_CALENDAR_URL"     // HTTP provider called with ?year=YYYY; unset (with no file) disables holidays
+	EnvHolidayCalendarRefresh = "ABAC_HOLIDAY_CALENDAR_REFRESH" // How long a fetched year is reused, e.g. "24h"
+)
+
+// Lockdown (incident response kill switch)
+const (
+	EnvLockdown            = "ABAC_LOCKDOWN"              // "deny_all" or "safelist"; unset starts without lockdown
+	EnvLockdownSafeActions = "ABAC_LOCKDOWN_SAFE_ACTIONS" // Comma-separated action patterns still evaluated in safelist mode
+	ActionLockdownManage   = "lockdown:manage"            // Action authorizing lockdown changes; never blocked by a lockdown
+)
+
+// PDP endpoints that need a permission of the calling PEP or service
+const (
+	ActionEvaluationSession = "pdp:session" // Prepare and release evaluation session contexts
+	ActionExplain           = "pdp:explain" // Explain evaluations; traces reveal policy conditions and subject attributes
+)
+
+// Signed policy bundle environment variables
+const (
+	EnvBundlePublicKey    = "ABAC_BUNDLE_PUBLIC_KEY"    // Path to a PEM Ed25519 public key; set to evaluate only policies of a verified bundle
+	EnvBundleSigningKey   = "ABAC_BUNDLE_SIGNING_KEY"   // Path to a PEM Ed25519 private key; enables bundle export
+	EnvPolicyBundle       = "ABAC_POLICY_BUNDLE"        // Path of the trusted bundle loaded at startup and rewritten by bundle import
+	EnvBundleURL          = "ABAC_BUNDLE_URL"           // http(s):// or s3://bucket/key polled for signed bundles; requires ABAC_BUNDLE_PUBLIC_KEY
+	EnvBundlePollInterval = "ABAC_BUNDLE_POLL_INTERVAL" // Duration between bundle downloads, e.g. "1m" (default 60s)
+)
+
+// Mutual TLS environment variables
+const (
+	EnvTLSCert       = "ABAC_TLS_CERT"        // Path to the PEM server certificate; set (with ABAC_TLS_KEY) to serve HTTPS
+	EnvTLSKey        = "ABAC_TLS_KEY"         // Path to the PEM server private key
+	EnvTLSClientCA   = "ABAC_TLS_CLIENT_CA"   // Path to PEM CA certificates trusted for client certificates; enables mutual TLS
+	EnvTLSClientAuth = "ABAC_TLS_CLIENT_AUTH" // "optional" (default) verifies certificates when presented; "require" rejects connections without one
+)
+
+// PEP request attribute environment variables
+const (
+	EnvPEPBodyFields      = "ABAC_PEP_BODY_FIELDS"      // Comma-separated JSON body paths exposed as request.body.*, e.g. "amount,payee.country"
+	EnvPEPHeaders         = "ABAC_PEP_HEADERS"          // Comma-separated header names exposed as request.header.*, e.g. "X-Channel"
+	EnvPEPMaxBodyBytes    = "ABAC_PEP_MAX_BODY_BYTES"   // Largest body inspected for body fields (default 65536); larger bodies are rejected
+	EnvPEPMaxHeaderBytes  = "ABAC_PEP_MAX_HEADER_BYTES" // Longest header value exposed (default 1024); longer values are left out
+	EnvPEPDecisionHeaders = "ABAC_PEP_DECISION_HEADERS" // "request", "response", "request,response" (default) or "none": where X-ABAC-* decision headers are set
+)
+
+// Token claims environment variables
+const (
+	EnvClaimsMapping = "ABAC_CLAIMS_MAPPING" // Path to a JSON claims-to-attributes mapping for JWT/OIDC subjects; unset uses claims as-is
+)
+
+// Subject type registry environment variables
+const (
+	EnvSubjectTypes = "ABAC_SUBJECT_TYPES" // "default" (user, service, device, anonymous) or a path to a JSON subject type registry; unset accepts any type
+)
+
+// Admin API authentication environment variables
+const (
+	EnvAdminTokens = "ABAC_ADMIN_TOKENS" // Path to a JSON file of admin bearer tokens and roles; unset leaves admin endpoints to ABACMiddleware alone
+)
+
+// Envoy external authorization environment variables
+const (
+	EnvExtAuthzAddr           = "ABAC_EXT_AUTHZ_ADDR"             // gRPC listen address of the Envoy ext_authz server, e.g. ":9191"; unset disables it
+	EnvExtAuthzTrustPeerCerts = "ABAC_EXT_AUTHZ_TRUST_PEER_CERTS" // "true" when Envoy verifies the client certificates it forwards
+)
+
+// Declarative manifest reconciliation environment variables
+const (
+	EnvReconcileDir      = "ABAC_RECONCILE_DIR"      // Directory of JSON manifests reconciled into storage; unset disables the loop
+	EnvReconcileInterval = "ABAC_RECONCILE_INTERVAL" // Duration between runs, e.g. "1m"
+	EnvReconcilePrune    = "ABAC_RECONCILE_PRUNE"    // "true" deletes stored entities of managed kinds missing from the manifests
+	EnvReconcileDryRun   = "ABAC_RECONCILE_DRY_RUN"  // "true" only reports drift without applying changes
+)
+
+// Attribute encryption at rest environment variables
+const (
+	EnvEncryptedAttributes     = "ABAC_ENCRYPTED_ATTRIBUTES"      // Comma-separated subject/resource attribute keys encrypted at rest, e.g. "ssn,email"; unset disables encryption
+	EnvAttributeEncryptionKeys = "ABAC_ATTRIBUTE_ENCRYPTION_KEYS" // Comma-separated "id=base64" AES keys (16, 24 or 32 bytes); the first encrypts, all decrypt
+)
+
+// Change event subscriber environment variables
+const (
+	EnvEventWebhookURL    = "ABAC_EVENT_WEBHOOK_URL"    // URL receiving subject/resource/policy change events as JSON POSTs; unset disables the webhook
+	EnvEventWebhookSecret = "ABAC_EVENT_WEBHOOK_SECRET" // Optional HMAC-SHA256 key signing webhook bodies (X-ABAC-Signature header)
+	EnvEventLog           = "ABAC_EVENT_LOG"            // File receiving change events as JSON lines ("-" for stdout); unset disables the event log
+)
@@ -0,0 +1,176 @@
+// Package cron parses standard five-field cron expressions ("minute hour day-of-month month
+// day-of-week") and computes their next activation, for recurring jobs such as scheduled
+// policy toggles:
+//
+//	spec, err := cron.Parse("0 18 * * FRI") // every Friday at 18:00
+//	next := spec.Next(time.Now().In(location))
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds Next for expressions that never match (e.g. "0 0 30 2 *")
+const maxSearchYears = 5
+
+// field describes the range and names of one cron field
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var fields = [5]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	// 7 is accepted as Sunday like in most cron implementations
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr                                string
+	minutes, hours, days, months, weeks uint64 // Bit sets of allowed values
+	// Like cron, when both day fields are restricted a day matches either of them
+	daysRestricted, weekdaysRestricted bool
+}
+
+// Parse parses a five-field cron expression. Fields accept "*", values, ranges ("1-5"),
+// steps ("*/15", "8-18/2"), comma-separated lists, and month/weekday names ("JAN", "MON").
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(parts))
+	}
+	var sets [5]uint64
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Sunday is both 0 and 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return &Schedule{
+		expr:               expr,
+		minutes:            sets[0],
+		hours:              sets[1],
+		days:               sets[2],
+		months:             sets[3],
+		weeks:              sets[4],
+		daysRestricted:     parts[2] != "*",
+		weekdaysRestricted: parts[4] != "*",
+	}, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first activation strictly after t, in t's location, or the zero time when
+// the expression matches no date within maxSearchYears
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.months, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hours, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay applies the day-of-month and day-of-week fields to t's date
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day, weekday := has(s.days, t.Day()), has(s.weeks, int(t.Weekday()))
+	if s.daysRestricted && s.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// parseField parses one comma-separated field into a bit set
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if slash := strings.Index(item, "/"); slash >= 0 {
+			rangeExpr = item[:slash]
+			n, err := strconv.Atoi(item[slash+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", item[slash+1:], f.name)
+			}
+			step = n
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		default:
+			value, err := parseValue(rangeExpr, f)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if step == 1 {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a number or name within the field's range
+func parseValue(expr string, f field) (int, error) {
+	if value, ok := f.names[strings.ToUpper(expr)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field (%d-%d)", expr, f.name, f.min, f.max)
+	}
+	return value, nil
+}
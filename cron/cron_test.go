@@ -0,0 +1,50 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	ict := time.FixedZone("ICT", 7*3600)
+	// Wednesday 2026-10-14 10:07 ICT
+	from := time.Date(2026, time.October, 14, 10, 7, 30, 0, ict)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 18 * * FRI", time.Date(2026, time.October, 16, 18, 0, 0, 0, ict)},
+		{"*/15 * * * *", time.Date(2026, time.October, 14, 10, 15, 0, 0, ict)},
+		{"0 9-17/4 * * 1-5", time.Date(2026, time.October, 14, 13, 0, 0, 0, ict)},
+		{"30 0 1 jan,jul *", time.Date(2027, time.January, 1, 0, 30, 0, 0, ict)},
+		{"0 0 * * 7", time.Date(2026, time.October, 18, 0, 0, 0, 0, ict)},
+		// Both day fields restricted: the 20th or any Monday
+		{"0 0 20 * MON", time.Date(2026, time.October, 19, 0, 0, 0, 0, ict)},
+		{"7 10 14 10 *", time.Date(2027, time.October, 14, 10, 7, 0, 0, ict)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			spec, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := spec.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", from, got, tt.want)
+			}
+		})
+	}
+
+	spec, _ := Parse("0 0 30 2 *")
+	if next := spec.Next(from); !next.IsZero() {
+		t.Errorf("Expected no activation on February 30th, got %v", next)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * * FUNDAY", "a * * * *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Expected %q to be rejected", expr)
+		}
+	}
+}
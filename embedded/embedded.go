@@ -0,0 +1,331 @@
+// Package embedded runs the policy evaluator inside a service. Policies are pulled from the central
+// PAP as a bundle at intervals and evaluated locally, removing the PDP round trip from every request;
+// until the first bundle is loaded (cold start) requests are sent to the remote evaluate endpoint.
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"abac_go_example/bundle"
+	"abac_go_example/client"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// DefaultSyncInterval is the bundle pull interval of configs without one
+const DefaultSyncInterval = 30 * time.Second
+
+// ErrNotSynced is returned by LocalEvaluate before the first bundle is loaded
+var ErrNotSynced = errors.New("policy bundle not synced yet")
+
+// Config configures an embedded PDP
+type Config struct {
+	// SyncInterval is the time between bundle pulls
+	SyncInterval time.Duration
+	// Verifier checks the signature of pulled bundles; nil trusts the transport
+	Verifier *bundle.Verifier
+	// PDP configures the local evaluator; nil uses core.DefaultPDPConfig
+	PDP *core.PDPConfig
+	// FallbackOnError sends requests the local evaluator fails on (e.g. a resource
+	// missing from the local store) to the remote PDP instead of returning the error
+	FallbackOnError bool
+}
+
+// DefaultConfig returns the default embedded PDP configuration
+func DefaultConfig() *Config {
+	return &Config{
+		SyncInterval:    DefaultSyncInterval,
+		FallbackOnError: true,
+	}
+}
+
+// Stats reports where decisions were made and the state of the local bundle
+type Stats struct {
+	Synced            bool      `json:"synced"`
+	BundleHash        string    `json:"bundle_hash,omitempty"`
+	Policies          int       `json:"policies"`
+	LastSync          time.Time `json:"last_sync"`            // Last successful pull, changed or not
+	LastError         string    `json:"last_error,omitempty"` // Error of the last failed pull
+	Syncs             int64     `json:"syncs"`                // Bundles loaded
+	SyncErrors        int64     `json:"sync_errors"`
+	LocalEvaluations  int64     `json:"local_evaluations"`
+	RemoteEvaluations int64     `json:"remote_evaluations"`
+}
+
+// PDP evaluates requests against a locally synced policy bundle and falls back to the remote PDP.
+// Subjects come with the request; resources and actions are read from the entity store of the
+// embedding service, so it must hold the resources and actions it asks about. It is safe for concurrent use.
+type PDP struct {
+	remote *client.Client
+	config Config
+	store  *policyStore
+	local  core.PolicyDecisionPointInterface
+
+	mu          sync.Mutex
+	stats       Stats
+	fingerprint string // ETag of the fingerprint the loaded bundle was pulled at
+}
+
+// New creates an embedded PDP that pulls bundles from remote and reads resources and actions from
+// entities. The remote client must be permitted "admin", which exporting bundles requires.
+func New(remote *client.Client, entities storage.Storage, config *Config) *PDP {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	pdpConfig := config.PDP
+	if pdpConfig == nil {
+		pdpConfig = core.DefaultPDPConfig()
+	}
+	store := &policyStore{Storage: entities}
+	return &PDP{
+		remote: remote,
+		config: *config,
+		store:  store,
+		local:  core.NewPolicyDecisionPointWithConfig(store, pdpConfig),
+	}
+}
+
+// Start pulls the bundle immediately and then every SyncInterval until ctx is cancelled.
+// Failed pulls are logged; the previous bundle stays in use.
+func (p *PDP) Start(ctx context.Context) {
+	interval := p.config.SyncInterval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to sync policy bundle: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync pulls the policy bundle when the remote policy fingerprint changed and loads it.
+// It reports whether a new bundle was loaded.
+func (p *PDP) Sync(ctx context.Context) (bool, error) {
+	loaded, err := p.sync(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.stats.SyncErrors++
+		p.stats.LastError = err.Error()
+		return false, err
+	}
+	p.stats.LastSync = time.Now()
+	p.stats.LastError = ""
+	return loaded, nil
+}
+
+func (p *PDP) sync(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	previous := p.fingerprint
+	p.mu.Unlock()
+
+	// The fingerprint is a cheap change check; 304 means the loaded bundle is current
+	fingerprint, err := p.remote.GetPolicyFingerprint(ctx, &client.GetPolicyFingerprintParams{
+		Namespace:   p.namespace(),
+		IfNoneMatch: previous,
+	})
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get policy fingerprint: %w", err)
+	}
+
+	exported, err := p.remote.ExportBundle(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to export policy bundle: %w", err)
+	}
+	var pulled bundle.Bundle
+	if err := convert(exported, &pulled); err != nil {
+		return false, fmt.Errorf("invalid policy bundle: %w", err)
+	}
+	if err := p.Load(&pulled); err != nil {
+		return false, err
+	}
+
+	p.mu.Lock()
+	p.fingerprint = `"` + fingerprint.Hash + `"`
+	p.mu.Unlock()
+	return true, nil
+}
+
+// Load verifies b when a Verifier is configured and makes its policies the local policy set
+func (p *PDP) Load(b *bundle.Bundle) error {
+	if p.config.Verifier != nil {
+		if _, err := p.config.Verifier.Verify(b); err != nil {
+			return fmt.Errorf("policy bundle verification failed: %w", err)
+		}
+	}
+	p.store.setPolicies(b.Policies)
+	p.local.PurgeDenyCache()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Synced = true
+	p.stats.BundleHash = b.Hash
+	p.stats.Policies = len(b.Policies)
+	p.stats.Syncs++
+	return nil
+}
+
+// Evaluate decides the request locally once a bundle is loaded and remotely before that
+// (or when the local evaluator fails and FallbackOnError is set)
+func (p *PDP) Evaluate(ctx context.Context, request *models.EvaluationRequest) (*models.Decision, error) {
+	decision, err := p.LocalEvaluate(request)
+	if err == nil {
+		return decision, nil
+	}
+	if !errors.Is(err, ErrNotSynced) && !p.config.FallbackOnError {
+		return nil, err
+	}
+	return p.RemoteEvaluate(ctx, request)
+}
+
+// LocalEvaluate decides the request with the local bundle; ErrNotSynced before one is loaded
+func (p *PDP) LocalEvaluate(request *models.EvaluationRequest) (*models.Decision, error) {
+	if !p.store.synced() {
+		return nil, ErrNotSynced
+	}
+	decision, err := p.local.Evaluate(request)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.stats.LocalEvaluations++
+	p.mu.Unlock()
+	return decision, nil
+}
+
+// RemoteEvaluate decides the request with the remote evaluate endpoint
+func (p *PDP) RemoteEvaluate(ctx context.Context, request *models.EvaluationRequest) (*models.Decision, error) {
+	if request == nil || request.Subject == nil {
+		return nil, fmt.Errorf("request subject is required")
+	}
+	var body client.EvaluateRequestBody
+	if err := convert(request, &body); err != nil {
+		return nil, fmt.Errorf("invalid evaluation request: %w", err)
+	}
+	body.SubjectID = request.Subject.GetID()
+
+	response, err := p.remote.Evaluate(ctx, &body)
+	if err != nil {
+		return nil, err
+	}
+	if response.Decision == nil {
+		return nil, fmt.Errorf("remote PDP returned no decision")
+	}
+	var decision models.Decision
+	if err := convert(response.Decision, &decision); err != nil {
+		return nil, fmt.Errorf("invalid remote decision: %w", err)
+	}
+
+	p.mu.Lock()
+	p.stats.RemoteEvaluations++
+	p.mu.Unlock()
+	return &decision, nil
+}
+
+// Local returns the local evaluator, e.g. to pass to a PEP once Synced
+func (p *PDP) Local() core.PolicyDecisionPointInterface {
+	return p.local
+}
+
+// Synced reports whether a bundle is loaded
+func (p *PDP) Synced() bool {
+	return p.store.synced()
+}
+
+// Stats returns a snapshot of the embedded PDP counters
+func (p *PDP) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// namespace is the evaluation namespace whose fingerprint is checked for changes
+func (p *PDP) namespace() string {
+	if p.config.PDP != nil {
+		return p.config.PDP.Namespace
+	}
+	return ""
+}
+
+// convert copies between the models and the generated client types, which share their JSON form
+func convert(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// policyStore serves the policies of the loaded bundle and everything else from the entity store.
+// Optional storage interfaces of the entity store (exceptions, attribute history, ...) are not exposed.
+type policyStore struct {
+	storage.Storage
+
+	mu       sync.RWMutex
+	loaded   bool
+	policies map[string]*models.Policy
+	enabled  []*models.Policy
+}
+
+func (s *policyStore) setPolicies(policies []*models.Policy) {
+	byID := make(map[string]*models.Policy, len(policies))
+	enabled := make([]*models.Policy, 0, len(policies))
+	for _, policy := range policies {
+		byID[policy.ID] = policy
+		if policy.Enabled {
+			enabled = append(enabled, policy)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaded = true
+	s.policies = byID
+	s.enabled = enabled
+}
+
+func (s *policyStore) synced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.loaded
+}
+
+// GetPolicies returns the enabled policies of the bundle
+func (s *policyStore) GetPolicies() ([]*models.Policy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*models.Policy(nil), s.enabled...), nil
+}
+
+// GetPolicy returns a policy of the bundle
+func (s *policyStore) GetPolicy(id string) (*models.Policy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	policy, exists := s.policies[id]
+	if !exists {
+		return nil, storage.ErrPolicyNotFound
+	}
+	return policy, nil
+}
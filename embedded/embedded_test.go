@@ -0,0 +1,218 @@
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"abac_go_example/bundle"
+	"abac_go_example/client"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// fakePAP serves the fingerprint, bundle export and evaluate endpoints of the central service
+type fakePAP struct {
+	mu        sync.Mutex
+	bundle    *bundle.Bundle
+	exports   int
+	evaluated []client.EvaluateRequestBody
+}
+
+func (f *fakePAP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api/v1/policies/fingerprint":
+		etag := `"` + f.bundle.Hash + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		json.NewEncoder(w).Encode(map[string]interface{}{"hash": f.bundle.Hash, "policies": len(f.bundle.Policies)})
+	case "/api/v1/bundles/export":
+		f.exports++
+		json.NewEncoder(w).Encode(f.bundle)
+	case "/api/v1/evaluate":
+		var body client.EvaluateRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		f.evaluated = append(f.evaluated, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"decision": map[string]interface{}{"result": "permit", "matched_policies": []string{"remote"}, "reason": "Remote decision"},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakePAP) setBundle(b *bundle.Bundle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bundle = b
+}
+
+func readPolicy(effect string) *models.Policy {
+	return &models.Policy{
+		ID:      "pol-read",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      "ReadDocuments",
+				Effect:   effect,
+				Action:   models.JSONActionResource{Single: "document:read"},
+				Resource: models.JSONActionResource{Single: "api:documents:*"},
+			},
+		},
+	}
+}
+
+func newTestPDP(t *testing.T, signer *bundle.Signer, config *Config) (*PDP, *fakePAP) {
+	t.Helper()
+	signed, err := signer.Sign([]*models.Policy{readPolicy("Allow")})
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	pap := &fakePAP{bundle: signed}
+	server := httptest.NewServer(pap)
+	t.Cleanup(server.Close)
+
+	entities := storage.NewMockStorage()
+	entities.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	entities.CreateResource(&models.Resource{ID: "api:documents:report.pdf", ResourceID: "api:documents:report.pdf"})
+	return New(client.New(server.URL), entities, config), pap
+}
+
+func newSigner(t *testing.T) (*bundle.Signer, *bundle.Verifier) {
+	t.Helper()
+	public, private, err := bundle.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	return bundle.NewSigner(private), bundle.NewVerifier(public)
+}
+
+func request(resourceID string) *models.EvaluationRequest {
+	return &models.EvaluationRequest{
+		RequestID:  "req-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-123", nil),
+		ResourceID: resourceID,
+		Action:     "document:read",
+	}
+}
+
+// TestEmbeddedPDP_ColdStartFallsBackToRemote tests that requests go to the remote PDP before the first sync
+func TestEmbeddedPDP_ColdStartFallsBackToRemote(t *testing.T) {
+	signer, _ := newSigner(t)
+	pdp, pap := newTestPDP(t, signer, nil)
+
+	if _, err := pdp.LocalEvaluate(request("api:documents:report.pdf")); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("Expected ErrNotSynced, got %v", err)
+	}
+	decision, err := pdp.Evaluate(context.Background(), request("api:documents:report.pdf"))
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if decision.Result != "permit" || decision.Reason != "Remote decision" {
+		t.Errorf("Expected the remote decision, got %+v", decision)
+	}
+	if len(pap.evaluated) != 1 || pap.evaluated[0].SubjectID != "user-123" || pap.evaluated[0].ResourceID != "api:documents:report.pdf" ||
+		pap.evaluated[0].Action != "document:read" || pap.evaluated[0].RequestID != "req-001" {
+		t.Errorf("Unexpected remote request %+v", pap.evaluated)
+	}
+	if stats := pdp.Stats(); stats.Synced || stats.RemoteEvaluations != 1 || stats.LocalEvaluations != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+// TestEmbeddedPDP_SyncEvaluatesLocally tests that a synced bundle is evaluated without the remote PDP
+func TestEmbeddedPDP_SyncEvaluatesLocally(t *testing.T) {
+	signer, verifier := newSigner(t)
+	pdp, pap := newTestPDP(t, signer, &Config{Verifier: verifier})
+
+	loaded, err := pdp.Sync(context.Background())
+	if err != nil || !loaded {
+		t.Fatalf("Expected the bundle to load, got %v %v", loaded, err)
+	}
+	decision, err := pdp.Evaluate(context.Background(), request("api:documents:report.pdf"))
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if decision.Result != "permit" || len(decision.MatchedPolicies) != 1 || decision.MatchedPolicies[0] != "pol-read" {
+		t.Errorf("Expected a local permit by pol-read, got %+v", decision)
+	}
+	if len(pap.evaluated) != 0 {
+		t.Errorf("Expected no remote evaluation, got %d", len(pap.evaluated))
+	}
+
+	// An unchanged fingerprint skips the export
+	if loaded, err := pdp.Sync(context.Background()); err != nil || loaded {
+		t.Errorf("Expected an unchanged fingerprint to skip the pull, got %v %v", loaded, err)
+	}
+	if pap.exports != 1 {
+		t.Errorf("Expected 1 export, got %d", pap.exports)
+	}
+
+	// A changed policy set is pulled and replaces the local policies
+	changed, err := signer.Sign([]*models.Policy{readPolicy("Deny")})
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	pap.setBundle(changed)
+	if loaded, err := pdp.Sync(context.Background()); err != nil || !loaded {
+		t.Fatalf("Expected the changed bundle to load, got %v %v", loaded, err)
+	}
+	decision, err = pdp.Evaluate(context.Background(), request("api:documents:report.pdf"))
+	if err != nil || decision.Result != "deny" {
+		t.Errorf("Expected a local deny after the change, got %+v %v", decision, err)
+	}
+
+	stats := pdp.Stats()
+	if !stats.Synced || stats.Syncs != 2 || stats.BundleHash != changed.Hash || stats.LocalEvaluations != 2 || stats.RemoteEvaluations != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+// TestEmbeddedPDP_RejectsUntrustedBundle tests that a bundle signed by another key is not loaded
+func TestEmbeddedPDP_RejectsUntrustedBundle(t *testing.T) {
+	signer, _ := newSigner(t)
+	_, verifier := newSigner(t)
+	pdp, _ := newTestPDP(t, signer, &Config{Verifier: verifier})
+
+	if _, err := pdp.Sync(context.Background()); !errors.Is(err, bundle.ErrUnknownKey) {
+		t.Fatalf("Expected ErrUnknownKey, got %v", err)
+	}
+	if stats := pdp.Stats(); stats.Synced || stats.SyncErrors != 1 || stats.LastError == "" {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Requests keep going to the remote PDP
+	decision, err := pdp.Evaluate(context.Background(), request("api:documents:report.pdf"))
+	if err != nil || decision.Reason != "Remote decision" {
+		t.Errorf("Expected the remote decision, got %+v %v", decision, err)
+	}
+}
+
+// TestEmbeddedPDP_FallbackOnError tests requests about resources missing from the local store
+func TestEmbeddedPDP_FallbackOnError(t *testing.T) {
+	signer, _ := newSigner(t)
+	pdp, pap := newTestPDP(t, signer, &Config{})
+	if _, err := pdp.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if _, err := pdp.Evaluate(context.Background(), request("api:documents:unknown.pdf")); err == nil {
+		t.Error("Expected the local error without FallbackOnError")
+	}
+
+	pdp.config.FallbackOnError = true
+	decision, err := pdp.Evaluate(context.Background(), request("api:documents:unknown.pdf"))
+	if err != nil || decision.Reason != "Remote decision" || len(pap.evaluated) != 1 {
+		t.Errorf("Expected the remote decision, got %+v %v", decision, err)
+	}
+}
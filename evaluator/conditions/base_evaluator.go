@@ -1,6 +1,7 @@
 package conditions
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	"abac_go_example/constants"
 	"abac_go_example/evaluator/path"
+	"abac_go_example/operators"
 )
 
 // BaseEvaluator provides common functionality for all evaluators
@@ -56,6 +58,76 @@ func (be *BaseEvaluator) EvaluateWithConditionMap(
 	return true
 }
 
+// EvaluateStringConditionMap evaluates each attribute/expected pair with the attribute resolved
+// as a string (path.ResolveString). A missing attribute satisfies a pair only when matchMissing
+// is set (negated operators); an attribute of another type never does.
+func (be *BaseEvaluator) EvaluateStringConditionMap(
+	conditions interface{},
+	context map[string]interface{},
+	matchMissing bool,
+	compare func(actual string, expected interface{}) bool,
+) bool {
+	condMap, ok := conditions.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for attributePath, expectedValue := range condMap {
+		actual, err := path.ResolveString(be.pathResolver, attributePath, context)
+		if err != nil {
+			if !(matchMissing && errors.Is(err, path.ErrPathNotFound)) {
+				return false
+			}
+			continue
+		}
+		if !compare(actual, expectedValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EvaluateNumericConditionMap evaluates each attribute/expected pair with the attribute resolved
+// as an exact number (operators.ParseNumeric), so decimal strings and large integers keep their
+// precision. Missing and non-numeric attributes are handled as in EvaluateStringConditionMap
+// instead of being read as 0.
+func (be *BaseEvaluator) EvaluateNumericConditionMap(
+	conditions interface{},
+	context map[string]interface{},
+	matchMissing bool,
+	compare func(actual operators.Numeric, expected interface{}) bool,
+) bool {
+	condMap, ok := conditions.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for attributePath, expectedValue := range condMap {
+		value, found := be.pathResolver.Resolve(attributePath, context)
+		if !found || value == nil {
+			if !matchMissing {
+				return false
+			}
+			continue
+		}
+		actual, ok := operators.ParseNumeric(value)
+		if !ok {
+			return false
+		}
+		if !compare(actual, expectedValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ResolveBool resolves an attribute path as a boolean (path.ResolveBool)
+func (be *BaseEvaluator) ResolveBool(attributePath string, context map[string]interface{}) (bool, error) {
+	return path.ResolveBool(be.pathResolver, attributePath, context)
+}
+
 // GetValueFromContext resolves attribute path from context
 func (be *BaseEvaluator) GetValueFromContext(attributePath string, context map[string]interface{}) interface{} {
 	value, _ := be.pathResolver.Resolve(attributePath, context)
@@ -0,0 +1,135 @@
+package conditions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClockWindow is an inclusive time-of-day window, optionally evaluated in a specific timezone.
+// When Start is after End the window wraps past midnight: ["22:00", "06:00"] covers 22:00-23:59 and 00:00-06:00.
+//
+// Condition forms: ["09:00", "17:00"], "09:00-17:00" or {"range": ["22:00", "06:00"], "tz": "Asia/Ho_Chi_Minh"}
+type ClockWindow struct {
+	Start    int            // seconds since midnight
+	End      int            // seconds since midnight
+	Location *time.Location // nil: compare the attribute's own clock time
+}
+
+// locationCache caches loaded timezones; time.LoadLocation reads the tz database on every call
+var locationCache sync.Map // name -> *time.Location
+
+// ParseClockWindow parses a clock window condition value. Bounds are "HH:MM" or "HH:MM:SS".
+func ParseClockWindow(value interface{}) (*ClockWindow, error) {
+	bounds := value
+	var tz string
+	if spec, ok := value.(map[string]interface{}); ok {
+		for key := range spec {
+			if key != "range" && key != "tz" {
+				return nil, fmt.Errorf("unknown time window field %q", key)
+			}
+		}
+		bounds = spec["range"]
+		if rawTZ, exists := spec["tz"]; exists {
+			if tz, ok = rawTZ.(string); !ok || tz == "" {
+				return nil, fmt.Errorf("tz must be a non-empty IANA timezone name")
+			}
+		}
+	}
+
+	var start, end string
+	switch v := bounds.(type) {
+	case string:
+		var found bool
+		if start, end, found = strings.Cut(v, "-"); !found {
+			return nil, fmt.Errorf("time window %q must be \"HH:MM-HH:MM\"", v)
+		}
+	case []interface{}:
+		if len(v) != 2 {
+			return nil, fmt.Errorf("time window range must have exactly two bounds")
+		}
+		start, _ = v[0].(string)
+		end, _ = v[1].(string)
+	case []string:
+		if len(v) != 2 {
+			return nil, fmt.Errorf("time window range must have exactly two bounds")
+		}
+		start, end = v[0], v[1]
+	default:
+		return nil, fmt.Errorf("time window range must be a [start, end] array or \"HH:MM-HH:MM\"")
+	}
+
+	window := &ClockWindow{}
+	var ok bool
+	if window.Start, ok = parseClock(start); !ok {
+		return nil, fmt.Errorf("invalid time window start %q, expected HH:MM", start)
+	}
+	if window.End, ok = parseClock(end); !ok {
+		return nil, fmt.Errorf("invalid time window end %q, expected HH:MM", end)
+	}
+
+	if tz != "" {
+		location, err := loadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time window tz %q: %w", tz, err)
+		}
+		window.Location = location
+	}
+	return window, nil
+}
+
+// Contains reports whether clock (seconds since midnight) falls inside the window
+func (w *ClockWindow) Contains(clock int) bool {
+	if w.Start <= w.End {
+		return clock >= w.Start && clock <= w.End
+	}
+	return clock >= w.Start || clock <= w.End
+}
+
+// ClockOf returns the seconds since midnight of t, in the window's timezone when it has one
+func (w *ClockWindow) ClockOf(t time.Time) int {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+// parseClock parses "HH:MM" or "HH:MM:SS" into seconds since midnight
+func parseClock(value string) (int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, false
+	}
+
+	limits := []int{24, 60, 60}
+	clock := 0
+	for i, part := range parts {
+		if len(part) != 2 {
+			return 0, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n >= limits[i] {
+			return 0, false
+		}
+		clock = clock*60 + n
+	}
+	if len(parts) == 2 {
+		clock *= 60
+	}
+	return clock, true
+}
+
+// loadLocation loads an IANA timezone, caching successful loads
+func loadLocation(name string) (*time.Location, error) {
+	if cached, ok := locationCache.Load(name); ok {
+		return cached.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locationCache.Store(name, location)
+	return location, nil
+}
@@ -0,0 +1,105 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClockWindow(t *testing.T) {
+	valid := []interface{}{
+		[]interface{}{"09:00", "17:00"},
+		[]string{"22:00", "06:00:30"},
+		"22:00-06:00",
+		map[string]interface{}{"range": "09:00-17:00", "tz": "UTC"},
+		map[string]interface{}{"range": []interface{}{"22:00", "06:00"}, "tz": "Asia/Ho_Chi_Minh"},
+		map[string]interface{}{"range": []interface{}{"00:00", "23:59"}},
+	}
+	for _, value := range valid {
+		if _, err := ParseClockWindow(value); err != nil {
+			t.Errorf("ParseClockWindow(%v) failed: %v", value, err)
+		}
+	}
+
+	invalid := []interface{}{
+		"09:00",
+		"09:00-",
+		[]interface{}{"09:00"},
+		[]interface{}{"9:00", "17:00"},
+		[]interface{}{"09:00", "24:00"},
+		[]interface{}{"2024-01-01", "2024-12-31"},
+		map[string]interface{}{"range": []interface{}{"22:00", "06:00"}, "tz": "Not/AZone"},
+		map[string]interface{}{"range": []interface{}{"22:00", "06:00"}, "tz": ""},
+		map[string]interface{}{"range": []interface{}{"22:00", "06:00"}, "timezone": "UTC"},
+	}
+	for _, value := range invalid {
+		if _, err := ParseClockWindow(value); err == nil {
+			t.Errorf("Expected ParseClockWindow(%v) to fail", value)
+		}
+	}
+}
+
+func TestTimeEvaluator_ClockWindows(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	// 16:30 UTC is 23:30 in Ho Chi Minh City (UTC+7)
+	evalTime := time.Date(2024, time.June, 3, 16, 30, 0, 0, time.UTC)
+	context := map[string]interface{}{
+		"environment:time_of_day": "23:15",
+		"environment:timestamp":   evalTime.Format(time.RFC3339),
+		"event": map[string]interface{}{
+			"at": "2024-06-03T05:00:00+07:00",
+		},
+	}
+
+	overnight := []interface{}{"22:00", "06:00"}
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{"overnight window before midnight", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"environment.time_of_day": overnight},
+		}, true},
+		{"daytime window excludes late evening", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"environment.time_of_day": []interface{}{"09:00", "17:00"}},
+		}, false},
+		{"overnight window after midnight via timestamp attribute", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"event.at": map[string]interface{}{"range": overnight, "tz": "Asia/Ho_Chi_Minh"}},
+		}, true},
+		{"timestamp converted to another timezone", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"event.at": map[string]interface{}{"range": overnight, "tz": "Asia/Tokyo"}},
+		}, false}, // 05:00+07:00 is 07:00 in Tokyo
+		{"bare clock time uses the evaluation time in tz", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"environment.time_of_day": map[string]interface{}{"range": overnight, "tz": "Asia/Ho_Chi_Minh"}},
+		}, true},
+		{"evaluation time outside window in UTC", map[string]interface{}{
+			"TimeBetween": map[string]interface{}{"environment.time_of_day": map[string]interface{}{"range": overnight, "tz": "UTC"}},
+		}, false},
+		{"TimeOfDay exact time", map[string]interface{}{
+			"TimeOfDay": map[string]interface{}{"environment.time_of_day": "23:15"},
+		}, true},
+		{"TimeOfDay overnight window", map[string]interface{}{
+			"TimeOfDay": map[string]interface{}{"environment.time_of_day": overnight},
+		}, true},
+		{"TimeOfDay range string", map[string]interface{}{
+			"TimeOfDay": map[string]interface{}{"environment.time_of_day": "09:00-17:00"},
+		}, false},
+		{"TimeOfDay overnight range string", map[string]interface{}{
+			"TimeOfDay": map[string]interface{}{"environment.time_of_day": "22:00-06:00"},
+		}, true},
+		{"invalid timezone never matches", map[string]interface{}{
+			"TimeOfDay": map[string]interface{}{"environment.time_of_day": map[string]interface{}{"range": overnight, "tz": "Not/AZone"}},
+		}, false},
+		{"absolute date range still supported", map[string]interface{}{
+			"DateBetween": map[string]interface{}{"event.at": []interface{}{"2024-06-01T00:00:00Z", "2024-06-30T00:00:00Z"}},
+		}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := evaluator.EvaluateConditions(test.conditions, context); result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
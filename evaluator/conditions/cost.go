@@ -0,0 +1,170 @@
+package conditions
+
+import (
+	"sort"
+
+	"abac_go_example/constants"
+)
+
+// Operator cost weights used to order conditions cheapest-first.
+// Costs are relative: a simple equality check is 1, a regex match is 20.
+const (
+	costTrivial   = 1
+	costCheap     = 2
+	costModerate  = 4
+	costTime      = 5
+	costPerCIDR   = 3
+	costRegex     = 20
+	costQuota     = 30 // Counter lookup, possibly remote
+	costUnknown   = 10
+	costLogicBase = 1
+)
+
+// operatorCosts maps lowercased operator names to their base cost
+var operatorCosts = map[string]int{
+	constants.OpBool:                          costTrivial,
+	constants.OpBoolean:                       costTrivial,
+	constants.OpStringEquals:                  costTrivial,
+	constants.OpStringNotEquals:               costTrivial,
+	constants.OpNumericEquals:                 costCheap,
+	constants.OpNumericNotEquals:              costCheap,
+	constants.OpNumericLessThan:               costCheap,
+	constants.OpNumericLessThanEquals:         costCheap,
+	constants.OpNumericGreaterThan:            costCheap,
+	constants.OpNumericGreaterThanEquals:      costCheap,
+	constants.OpNumericBetween:                costCheap,
+	constants.OpStringContains:                costCheap,
+	constants.OpStringStartsWith:              costCheap,
+	constants.OpStringEndsWith:                costCheap,
+	constants.OpVersionLessThan:               costCheap,
+	constants.OpVersionLessThanEquals:         costCheap,
+	constants.OpVersionGreaterThan:            costCheap,
+	constants.OpVersionGreaterThanEquals:      costCheap,
+	constants.OpArrayContains:                 costModerate,
+	constants.OpArrayNotContains:              costModerate,
+	constants.OpArraySize:                     costCheap,
+	constants.OpResourceTag:                   costModerate,
+	constants.OpIssuedByCA:                    costModerate,
+	constants.OpStringLike:                    costModerate,
+	constants.OpStringEqualsIgnoreAccents:     costModerate,
+	constants.OpStringNotEqualsIgnoreAccents:  costModerate,
+	constants.OpStringContainsIgnoreAccents:   costModerate,
+	constants.OpStringStartsWithIgnoreAccents: costModerate,
+	constants.OpDateLessThan:                  costTime,
+	constants.OpTimeLessThan:                  costTime,
+	constants.OpDateLessThanEquals:            costTime,
+	constants.OpTimeLessThanEquals:            costTime,
+	constants.OpDateGreaterThan:               costTime,
+	constants.OpTimeGreaterThan:               costTime,
+	constants.OpDateGreaterThanEquals:         costTime,
+	constants.OpTimeGreaterThanEquals:         costTime,
+	constants.OpDateBetween:                   costTime,
+	constants.OpTimeBetween:                   costTime,
+	constants.OpDayOfWeek:                     costTime,
+	constants.OpTimeOfDay:                     costTime,
+	constants.OpIsBusinessHours:               costTime,
+	constants.OpIsHoliday:                     costTime,
+	constants.OpAuthAgeLessThan:               costTime,
+	constants.OpIsInternalIP:                  costTime,
+	constants.OpIPInRange:                     costPerCIDR,
+	constants.OpIPNotInRange:                  costPerCIDR,
+	constants.OpStringRegex:                   costRegex,
+	constants.OpRequestRateBelow:              costQuota,
+	constants.OpDailyQuotaBelow:               costQuota,
+}
+
+// CompiledCondition is a single top-level condition operator with its precomputed cost
+type CompiledCondition struct {
+	Operator   string
+	Conditions interface{}
+	Cost       int
+}
+
+// CompileConditions splits a condition block into operators sorted by ascending cost,
+// so cheap checks run first and failing statements short-circuit early.
+// Ties are broken by operator name to keep evaluation order deterministic.
+func CompileConditions(conditions map[string]interface{}) []CompiledCondition {
+	compiled := make([]CompiledCondition, 0, len(conditions))
+	for operator, operatorConditions := range conditions {
+		compiled = append(compiled, CompiledCondition{
+			Operator:   operator,
+			Conditions: operatorConditions,
+			Cost:       OperatorCost(operator, operatorConditions),
+		})
+	}
+
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if compiled[i].Cost != compiled[j].Cost {
+			return compiled[i].Cost < compiled[j].Cost
+		}
+		return compiled[i].Operator < compiled[j].Operator
+	})
+
+	return compiled
+}
+
+// OperatorCost estimates the evaluation cost of an operator block.
+// Per-value operators scale with the number of expected values (e.g. CIDRs for IPInRange);
+// logical operators cost the sum of their nested blocks.
+func OperatorCost(operator string, operatorConditions interface{}) int {
+	op := constants.OperatorKey(operator)
+
+	switch op {
+	case constants.OpAnd, constants.OpOr:
+		cost := costLogicBase
+		if children, ok := operatorConditions.([]interface{}); ok {
+			for _, child := range children {
+				if childMap, ok := child.(map[string]interface{}); ok {
+					cost += conditionBlockCost(childMap)
+				}
+			}
+		}
+		return cost
+	case constants.OpNot:
+		if childMap, ok := operatorConditions.(map[string]interface{}); ok {
+			return costLogicBase + conditionBlockCost(childMap)
+		}
+		return costLogicBase
+	}
+
+	base, known := operatorCosts[op]
+	if !known {
+		base = costUnknown
+	}
+
+	return base * countExpectedValues(operatorConditions)
+}
+
+// conditionBlockCost sums the costs of all operators in a condition block
+func conditionBlockCost(conditions map[string]interface{}) int {
+	cost := 0
+	for operator, operatorConditions := range conditions {
+		cost += OperatorCost(operator, operatorConditions)
+	}
+	return cost
+}
+
+// countExpectedValues counts expected values in an operator block ({"attr": value | [values]}), minimum 1
+func countExpectedValues(operatorConditions interface{}) int {
+	condMap, ok := operatorConditions.(map[string]interface{})
+	if !ok {
+		return 1
+	}
+
+	count := 0
+	for _, expected := range condMap {
+		switch v := expected.(type) {
+		case []interface{}:
+			count += len(v)
+		case []string:
+			count += len(v)
+		default:
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 1
+	}
+	return count
+}
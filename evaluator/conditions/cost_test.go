@@ -0,0 +1,85 @@
+package conditions
+
+import "testing"
+
+func TestCompileConditionsOrdersByCost(t *testing.T) {
+	conditions := map[string]interface{}{
+		"StringRegex": map[string]interface{}{
+			"user.email": "^[a-z]+@company\\.com$",
+		},
+		"IPInRange": map[string]interface{}{
+			"environment.client_ip": []interface{}{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
+		},
+		"Bool": map[string]interface{}{
+			"user.mfa": true,
+		},
+		"StringEquals": map[string]interface{}{
+			"user.department": "Engineering",
+		},
+	}
+
+	compiled := CompileConditions(conditions)
+
+	expected := []string{"Bool", "StringEquals", "IPInRange", "StringRegex"}
+	if len(compiled) != len(expected) {
+		t.Fatalf("Expected %d compiled conditions, got %d", len(expected), len(compiled))
+	}
+	for i, op := range expected {
+		if compiled[i].Operator != op {
+			t.Errorf("Position %d: expected %s, got %s (cost %d)", i, op, compiled[i].Operator, compiled[i].Cost)
+		}
+	}
+}
+
+func TestOperatorCost(t *testing.T) {
+	oneCIDR := OperatorCost("IPInRange", map[string]interface{}{"ip": "10.0.0.0/8"})
+	manyCIDRs := OperatorCost("IPInRange", map[string]interface{}{
+		"ip": []interface{}{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10"},
+	})
+	if manyCIDRs != 4*oneCIDR {
+		t.Errorf("Expected IPInRange cost to scale with CIDR count: 1=%d, 4=%d", oneCIDR, manyCIDRs)
+	}
+
+	nested := OperatorCost("And", []interface{}{
+		map[string]interface{}{"StringEquals": map[string]interface{}{"a": "x"}},
+		map[string]interface{}{"StringRegex": map[string]interface{}{"b": ".*"}},
+	})
+	if nested <= OperatorCost("StringRegex", map[string]interface{}{"b": ".*"}) {
+		t.Errorf("Expected And cost to include nested costs, got %d", nested)
+	}
+
+	if OperatorCost("UnknownOp", map[string]interface{}{"a": "x"}) != costUnknown {
+		t.Error("Expected unknown operator to use default cost")
+	}
+}
+
+func TestEvaluateConditionsShortCircuitsOnCheapFailure(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"department": "Finance",
+			"email":      "alice@company.com",
+		},
+	}
+
+	conditions := map[string]interface{}{
+		"StringRegex": map[string]interface{}{
+			"user.email": "^[a-z]+@company\\.com$",
+		},
+		"StringEquals": map[string]interface{}{
+			"user.department": "Engineering",
+		},
+	}
+
+	// Result must not depend on ordering
+	for i := 0; i < 10; i++ {
+		if evaluator.EvaluateConditions(conditions, context) {
+			t.Fatal("Expected conditions to fail on department mismatch")
+		}
+	}
+
+	context["user"].(map[string]interface{})["department"] = "Engineering"
+	if !evaluator.EvaluateConditions(conditions, context) {
+		t.Error("Expected conditions to pass once department matches")
+	}
+}
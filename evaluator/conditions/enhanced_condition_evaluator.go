@@ -1,11 +1,14 @@
 package conditions
 
 import (
-	"strings"
 
+	"abac_go_example/clock"
 	"abac_go_example/constants"
 	"abac_go_example/evaluator/path"
+	"abac_go_example/holidays"
+	"abac_go_example/models"
 	"abac_go_example/operators"
+	"abac_go_example/quota"
 )
 
 // EnhancedConditionEvaluator provides advanced condition evaluation capabilities
@@ -17,6 +20,9 @@ type EnhancedConditionEvaluator struct {
 	arrayEvaluator   ArrayEvaluator
 	networkEvaluator NetworkEvaluator
 	logicalEvaluator LogicalEvaluator
+	quotaEvaluator   *QuotaConditionEvaluator
+	tagEvaluator     *TagConditionEvaluator
+	clock            clock.Clock
 }
 
 // NewEnhancedConditionEvaluator creates a new enhanced condition evaluator
@@ -33,6 +39,9 @@ func NewEnhancedConditionEvaluator() *EnhancedConditionEvaluator {
 		arrayEvaluator:   NewArrayEvaluator(pathResolver),
 		networkEvaluator: NewNetworkEvaluator(pathResolver, networkUtils),
 		logicalEvaluator: logicalEvaluator,
+		quotaEvaluator:   NewQuotaEvaluator(nil),
+		tagEvaluator:     NewTagEvaluator(),
+		clock:            clock.NewRealClock(),
 	}
 
 	// Set circular reference for logical evaluator
@@ -41,15 +50,48 @@ func NewEnhancedConditionEvaluator() *EnhancedConditionEvaluator {
 	return ece
 }
 
+// SetCounterProvider configures the counters used by quota operators (nil makes them fail closed)
+func (ece *EnhancedConditionEvaluator) SetCounterProvider(provider quota.CounterProvider) {
+	ece.quotaEvaluator = NewQuotaEvaluator(provider)
+	ece.quotaEvaluator.SetClock(ece.clock)
+}
+
+// SetClock configures the clock used by time-dependent operators (nil restores real time)
+func (ece *EnhancedConditionEvaluator) SetClock(c clock.Clock) {
+	ece.clock = clock.OrReal(c)
+	ece.quotaEvaluator.SetClock(ece.clock)
+}
+
+// SetHolidayCalendar configures the holidays excluded from business hours and matched by IsHoliday (nil: no holidays)
+func (ece *EnhancedConditionEvaluator) SetHolidayCalendar(calendar holidays.Calendar) {
+	ece.timeEvaluator.SetHolidayCalendar(calendar)
+}
+
+// ConsumeQuotas increments the quota counters of a permitted statement's conditions
+func (ece *EnhancedConditionEvaluator) ConsumeQuotas(conditions map[string]interface{}, context map[string]interface{}) {
+	ece.quotaEvaluator.Consume(conditions, context)
+}
+
 // EvaluateConditions evaluates conditions with enhanced operators and complex expressions
 func (ece *EnhancedConditionEvaluator) EvaluateConditions(conditions map[string]interface{}, context map[string]interface{}) bool {
 	if len(conditions) == 0 {
 		return true
 	}
 
-	// Evaluate each condition operator
-	for operator, operatorConditions := range conditions {
-		if !ece.evaluateOperator(operator, operatorConditions, context) {
+	// Single operator blocks need no ordering
+	if len(conditions) == 1 {
+		for operator, operatorConditions := range conditions {
+			return ece.evaluateOperator(operator, operatorConditions, context)
+		}
+	}
+
+	return ece.EvaluateCompiled(CompileConditions(conditions), context)
+}
+
+// EvaluateCompiled evaluates pre-ordered conditions, stopping at the first failing operator
+func (ece *EnhancedConditionEvaluator) EvaluateCompiled(compiled []CompiledCondition, context map[string]interface{}) bool {
+	for _, condition := range compiled {
+		if !ece.evaluateOperator(condition.Operator, condition.Conditions, context) {
 			return false
 		}
 	}
@@ -57,17 +99,36 @@ func (ece *EnhancedConditionEvaluator) EvaluateConditions(conditions map[string]
 	return true
 }
 
-// Evaluate implements ConditionEvaluator interface
+// Evaluate implements ConditionEvaluator interface: the single entry point for statement Condition
+// maps and, in compatibility mode, legacy BooleanExpression trees (translated by
+// LegacyExpressionConditions). Unsupported or untranslatable conditions never match.
 func (ece *EnhancedConditionEvaluator) Evaluate(conditions interface{}, context map[string]interface{}) bool {
-	if condMap, ok := conditions.(map[string]interface{}); ok {
-		return ece.EvaluateConditions(condMap, context)
+	switch typed := conditions.(type) {
+	case map[string]interface{}:
+		return ece.EvaluateConditions(typed, context)
+	case models.JSONMap:
+		return ece.EvaluateConditions(typed, context)
+	case *models.BooleanExpression:
+		translated, err := LegacyExpressionConditions(typed)
+		return err == nil && ece.EvaluateConditions(translated, context)
 	}
 	return false
 }
 
-// evaluateOperator evaluates a specific condition operator using specialized evaluators
+// evaluateOperator evaluates a specific condition operator, reusing results memoized for the request
 func (ece *EnhancedConditionEvaluator) evaluateOperator(operator string, operatorConditions interface{}, context map[string]interface{}) bool {
-	switch strings.ToLower(operator) {
+	operator = operatorKey(operator)
+	if memo := memoFromContext(context); memo != nil && isMemoizable(operator) {
+		if block, ok := operatorConditions.(map[string]interface{}); ok && len(block) > 0 {
+			return ece.evaluateMemoized(memo, operator, block, context)
+		}
+	}
+	return ece.dispatchOperator(operator, operatorConditions, context)
+}
+
+// dispatchOperator evaluates a condition operator by its key (see constants.OperatorKey) using specialized evaluators
+func (ece *EnhancedConditionEvaluator) dispatchOperator(operator string, operatorConditions interface{}, context map[string]interface{}) bool {
+	switch operator {
 	// String operators
 	case constants.OpStringEquals:
 		return ece.stringEvaluator.EvaluateEquals(operatorConditions, context)
@@ -83,6 +144,24 @@ func (ece *EnhancedConditionEvaluator) evaluateOperator(operator string, operato
 		return ece.stringEvaluator.EvaluateEndsWith(operatorConditions, context)
 	case constants.OpStringRegex:
 		return ece.stringEvaluator.EvaluateRegex(operatorConditions, context)
+	case constants.OpStringEqualsIgnoreAccents:
+		return ece.stringEvaluator.EvaluateEqualsIgnoreAccents(operatorConditions, context)
+	case constants.OpStringNotEqualsIgnoreAccents:
+		return ece.stringEvaluator.EvaluateNotEqualsIgnoreAccents(operatorConditions, context)
+	case constants.OpStringContainsIgnoreAccents:
+		return ece.stringEvaluator.EvaluateContainsIgnoreAccents(operatorConditions, context)
+	case constants.OpStringStartsWithIgnoreAccents:
+		return ece.stringEvaluator.EvaluateStartsWithIgnoreAccents(operatorConditions, context)
+
+	// Version operators
+	case constants.OpVersionLessThan:
+		return ece.stringEvaluator.EvaluateVersion(operatorConditions, context, func(c int) bool { return c < 0 })
+	case constants.OpVersionLessThanEquals:
+		return ece.stringEvaluator.EvaluateVersion(operatorConditions, context, func(c int) bool { return c <= 0 })
+	case constants.OpVersionGreaterThan:
+		return ece.stringEvaluator.EvaluateVersion(operatorConditions, context, func(c int) bool { return c > 0 })
+	case constants.OpVersionGreaterThanEquals:
+		return ece.stringEvaluator.EvaluateVersion(operatorConditions, context, func(c int) bool { return c >= 0 })
 
 	// Numeric operators
 	case constants.OpNumericEquals:
@@ -117,6 +196,10 @@ func (ece *EnhancedConditionEvaluator) evaluateOperator(operator string, operato
 		return ece.timeEvaluator.EvaluateTimeOfDay(operatorConditions, context)
 	case constants.OpIsBusinessHours:
 		return ece.timeEvaluator.EvaluateIsBusinessHours(operatorConditions, context)
+	case constants.OpIsHoliday:
+		return ece.timeEvaluator.EvaluateIsHoliday(operatorConditions, context)
+	case constants.OpAuthAgeLessThan:
+		return ece.timeEvaluator.EvaluateAuthAgeLessThan(operatorConditions, context)
 
 	// Array operators
 	case constants.OpArrayContains:
@@ -134,9 +217,25 @@ func (ece *EnhancedConditionEvaluator) evaluateOperator(operator string, operato
 	case constants.OpIsInternalIP:
 		return ece.networkEvaluator.EvaluateIsInternalIP(operatorConditions, context)
 
+	// Certificate operators
+	case constants.OpIssuedByCA:
+		return ece.networkEvaluator.EvaluateIssuedByCA(operatorConditions, context)
+
 	// Boolean operators
 	case constants.OpBool, constants.OpBoolean:
 		return ece.evaluateBoolean(operatorConditions, context)
+	case constants.OpNull:
+		return ece.evaluateNull(operatorConditions, context)
+
+	// Quota operators
+	case constants.OpRequestRateBelow:
+		return ece.quotaEvaluator.EvaluateRequestRateBelow(operatorConditions, context)
+	case constants.OpDailyQuotaBelow:
+		return ece.quotaEvaluator.EvaluateDailyQuotaBelow(operatorConditions, context)
+
+	// Resource tag operators
+	case constants.OpResourceTag:
+		return ece.tagEvaluator.EvaluateResourceTag(operatorConditions, context)
 
 	// Complex operators
 	case constants.OpAnd:
@@ -160,11 +259,9 @@ func (ece *EnhancedConditionEvaluator) evaluateBoolean(conditions interface{}, c
 	}
 
 	for attributePath, expectedValue := range condMap {
-		actualValue := ece.getValueFromContext(attributePath, context)
-		actualBool := ece.toBool(actualValue)
-		expectedBool := ece.toBool(expectedValue)
-
-		if actualBool != expectedBool {
+		// Missing or non-boolean attributes never match (they are not read as false)
+		actualBool, err := ece.stringEvaluator.(*StringConditionEvaluator).ResolveBool(attributePath, context)
+		if err != nil || actualBool != ece.toBool(expectedValue) {
 			return false
 		}
 	}
@@ -172,13 +269,16 @@ func (ece *EnhancedConditionEvaluator) evaluateBoolean(conditions interface{}, c
 	return true
 }
 
-// Helper methods for backward compatibility
-
-func (ece *EnhancedConditionEvaluator) getValueFromContext(attributePath string, context map[string]interface{}) interface{} {
-	// Delegate to string evaluator's base evaluator
-	return ece.stringEvaluator.(*StringConditionEvaluator).GetValueFromContext(attributePath, context)
+// evaluateNull matches attributes by presence: true requires the attribute to be absent (or null), false present
+func (ece *EnhancedConditionEvaluator) evaluateNull(conditions interface{}, context map[string]interface{}) bool {
+	resolver := ece.stringEvaluator.(*StringConditionEvaluator)
+	return resolver.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		return (evalCtx.ActualValue == nil) == resolver.ToBool(evalCtx.ExpectedValue)
+	})
 }
 
+// Helper methods for backward compatibility
+
 func (ece *EnhancedConditionEvaluator) toBool(value interface{}) bool {
 	// Delegate to string evaluator's base evaluator
 	return ece.stringEvaluator.(*StringConditionEvaluator).ToBool(value)
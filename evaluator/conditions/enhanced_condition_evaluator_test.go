@@ -1,8 +1,11 @@
 package conditions
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestEnhancedConditionEvaluator_StringOperators(t *testing.T) {
@@ -113,6 +116,52 @@ func TestEnhancedConditionEvaluator_StringOperators(t *testing.T) {
 	}
 }
 
+// TestEnhancedConditionEvaluator_UnicodeNormalization tests that NFC and NFD forms of Vietnamese
+// text compare equal and that the IgnoreAccents operators ignore diacritics
+func TestEnhancedConditionEvaluator_UnicodeNormalization(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	decomposed := norm.NFD.String("Kỹ thuật")
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"department": decomposed,
+			"name":       "Nguyễn Văn Đức",
+			"city":       "Đà Nẵng",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{"StringEquals - NFD attribute equals NFC policy value", map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Kỹ thuật"}}, true},
+		{"StringEquals - NFC attribute equals NFD policy value", map[string]interface{}{"StringEquals": map[string]interface{}{"user.name": norm.NFD.String("Nguyễn Văn Đức")}}, true},
+		{"StringEquals - accents still matter", map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Ky thuat"}}, false},
+		{"StringNotEquals - NFD form is not different", map[string]interface{}{"StringNotEquals": map[string]interface{}{"user.department": "Kỹ thuật"}}, false},
+		{"StringStartsWith - NFD attribute", map[string]interface{}{"StringStartsWith": map[string]interface{}{"user.department": "Kỹ"}}, true},
+		{"StringLike - NFD attribute", map[string]interface{}{"StringLike": map[string]interface{}{"user.department": "%thuật"}}, true},
+		{"StringRegex - NFD attribute", map[string]interface{}{"StringRegex": map[string]interface{}{"user.department": "^Kỹ thuật$"}}, true},
+		{"StringEqualsIgnoreAccents - match", map[string]interface{}{"StringEqualsIgnoreAccents": map[string]interface{}{"user.department": "Ky thuat"}}, true},
+		{"StringEqualsIgnoreAccents - d with stroke", map[string]interface{}{"StringEqualsIgnoreAccents": map[string]interface{}{"user.city": "Da Nang"}}, true},
+		{"StringEqualsIgnoreAccents - case still matters", map[string]interface{}{"StringEqualsIgnoreAccents": map[string]interface{}{"user.department": "ky thuat"}}, false},
+		{"StringNotEqualsIgnoreAccents - same letters", map[string]interface{}{"StringNotEqualsIgnoreAccents": map[string]interface{}{"user.city": "Da Nang"}}, false},
+		{"StringNotEqualsIgnoreAccents - missing attribute", map[string]interface{}{"StringNotEqualsIgnoreAccents": map[string]interface{}{"user.missing": "Da Nang"}}, true},
+		{"StringContainsIgnoreAccents - match", map[string]interface{}{"StringContainsIgnoreAccents": map[string]interface{}{"user.name": "Van Duc"}}, true},
+		{"StringStartsWithIgnoreAccents - match", map[string]interface{}{"StringStartsWithIgnoreAccents": map[string]interface{}{"user.name": "Nguyen"}}, true},
+		{"StringStartsWithIgnoreAccents - no match", map[string]interface{}{"StringStartsWithIgnoreAccents": map[string]interface{}{"user.name": "Tran"}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluator.EvaluateConditions(test.conditions, context)
+			if result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
 func TestEnhancedConditionEvaluator_NumericOperators(t *testing.T) {
 	evaluator := NewEnhancedConditionEvaluator()
 
@@ -202,6 +251,89 @@ func TestEnhancedConditionEvaluator_NumericOperators(t *testing.T) {
 	}
 }
 
+// TestEnhancedConditionEvaluator_TypedAttributes tests that missing or mistyped attributes are not
+// coerced to "", 0 or false
+func TestEnhancedConditionEvaluator_TypedAttributes(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"level":      "senior", // not a number
+			"clearance":  "3",      // numeric string
+			"department": []interface{}{"Engineering"},
+			"suspended":  "yes", // not a boolean
+			"mfa":        "true",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{"NumericLessThan - missing attribute is not 0", map[string]interface{}{"NumericLessThan": map[string]interface{}{"user.missing": 5}}, false},
+		{"NumericLessThan - non-numeric attribute is not 0", map[string]interface{}{"NumericLessThan": map[string]interface{}{"user.level": 5}}, false},
+		{"NumericNotEquals - non-numeric attribute fails closed", map[string]interface{}{"NumericNotEquals": map[string]interface{}{"user.level": 5}}, false},
+		{"NumericNotEquals - missing attribute is not equal", map[string]interface{}{"NumericNotEquals": map[string]interface{}{"user.missing": 5}}, true},
+		{"NumericGreaterThanEquals - numeric string", map[string]interface{}{"NumericGreaterThanEquals": map[string]interface{}{"user.clearance": 3}}, true},
+		{"StringEquals - missing attribute is not empty", map[string]interface{}{"StringEquals": map[string]interface{}{"user.missing": ""}}, false},
+		{"StringEquals - list is not formatted as a string", map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "[Engineering]"}}, false},
+		{"StringNotEquals - list fails closed", map[string]interface{}{"StringNotEquals": map[string]interface{}{"user.department": "Sales"}}, false},
+		{"StringNotEquals - missing attribute is not equal", map[string]interface{}{"StringNotEquals": map[string]interface{}{"user.missing": "Sales"}}, true},
+		{"Bool - missing attribute is not false", map[string]interface{}{"Bool": map[string]interface{}{"user.missing": false}}, false},
+		{"Bool - non-boolean attribute is not false", map[string]interface{}{"Bool": map[string]interface{}{"user.suspended": false}}, false},
+		{"Bool - boolean string", map[string]interface{}{"Bool": map[string]interface{}{"user.mfa": true}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluator.EvaluateConditions(test.conditions, context)
+			if result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+// TestEnhancedConditionEvaluator_DecimalPrecision tests monetary thresholds and integers beyond 2^53
+// that float64 comparison gets wrong
+func TestEnhancedConditionEvaluator_DecimalPrecision(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	context := map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"amount":     "1000000000000000.01",
+			"fee":        "19.99",
+			"ledger_id":  int64(9007199254740993),
+			"account_id": "9007199254740993",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{"NumericGreaterThan - one cent above the limit", map[string]interface{}{"NumericGreaterThan": map[string]interface{}{"transaction.amount": "1000000000000000.00"}}, true},
+		{"NumericLessThanEquals - one cent above the limit", map[string]interface{}{"NumericLessThanEquals": map[string]interface{}{"transaction.amount": "1000000000000000"}}, false},
+		{"NumericEquals - decimal string and float", map[string]interface{}{"NumericEquals": map[string]interface{}{"transaction.fee": 19.99}}, true},
+		{"NumericLessThan - fee below threshold", map[string]interface{}{"NumericLessThan": map[string]interface{}{"transaction.fee": "20.00"}}, true},
+		{"NumericNotEquals - int64 beyond float53", map[string]interface{}{"NumericNotEquals": map[string]interface{}{"transaction.ledger_id": int64(9007199254740992)}}, true},
+		{"NumericEquals - int64 beyond float53", map[string]interface{}{"NumericEquals": map[string]interface{}{"transaction.account_id": int64(9007199254740993)}}, true},
+		{"NumericBetween - exact bounds", map[string]interface{}{"NumericBetween": map[string]interface{}{"transaction.amount": []interface{}{"1000000000000000.01", "1000000000000000.02"}}}, true},
+		{"NumericBetween - just outside", map[string]interface{}{"NumericBetween": map[string]interface{}{"transaction.amount": []interface{}{"0", "1000000000000000"}}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluator.EvaluateConditions(test.conditions, context)
+			if result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
 func TestEnhancedConditionEvaluator_TimeOperators(t *testing.T) {
 	evaluator := NewEnhancedConditionEvaluator()
 
@@ -299,6 +431,124 @@ func TestEnhancedConditionEvaluator_TimeOperators(t *testing.T) {
 	}
 }
 
+func TestEnhancedConditionEvaluator_AuthAgeLessThan(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	requestTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	context := map[string]interface{}{
+		"request:Time": requestTime.Format(time.RFC3339),
+		"session": map[string]interface{}{
+			"auth_time":        requestTime.Add(-10 * time.Minute).Format(time.RFC3339),
+			"auth_age_seconds": 600,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{
+			name: "auth_time within duration",
+			conditions: map[string]interface{}{
+				"AuthAgeLessThan": map[string]interface{}{"session.auth_time": "15m"},
+			},
+			expected: true,
+		},
+		{
+			name: "auth_time too old",
+			conditions: map[string]interface{}{
+				"AuthAgeLessThan": map[string]interface{}{"session.auth_time": "5m"},
+			},
+			expected: false,
+		},
+		{
+			name: "age in seconds with numeric limit",
+			conditions: map[string]interface{}{
+				"AuthAgeLessThan": map[string]interface{}{"session.auth_age_seconds": float64(900)},
+			},
+			expected: true,
+		},
+		{
+			name: "missing attribute",
+			conditions: map[string]interface{}{
+				"AuthAgeLessThan": map[string]interface{}{"session.missing": "15m"},
+			},
+			expected: false,
+		},
+		{
+			name: "invalid limit",
+			conditions: map[string]interface{}{
+				"AuthAgeLessThan": map[string]interface{}{"session.auth_time": "soon"},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluator.EvaluateConditions(test.conditions, context)
+			if result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestEnhancedConditionEvaluator_ResourceTag(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+
+	context := map[string]interface{}{
+		"resource:Tags": []string{"env=prod", "legal-hold", "pii=true"},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{
+			name:       "boolean value matches tag",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"pii": true}},
+			expected:   true,
+		},
+		{
+			name:       "one of accepted values",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"env": []interface{}{"staging", "prod"}}},
+			expected:   true,
+		},
+		{
+			name:       "wildcard matches bare tag",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"legal-hold": "*"}},
+			expected:   true,
+		},
+		{
+			name:       "all keys required",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"pii": "true", "env": "staging"}},
+			expected:   false,
+		},
+		{
+			name:       "missing tag",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"team": "*"}},
+			expected:   false,
+		},
+		{
+			name:       "invalid value",
+			conditions: map[string]interface{}{"ResourceTag": map[string]interface{}{"pii": map[string]interface{}{}}},
+			expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := evaluator.EvaluateConditions(test.conditions, context)
+			if result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
 func TestEnhancedConditionEvaluator_NetworkOperators(t *testing.T) {
 	evaluator := NewEnhancedConditionEvaluator()
 
@@ -729,6 +979,24 @@ func TestEnhancedConditionEvaluator_ArrayAccess(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "ArrayContains - projection over array of objects",
+			conditions: map[string]interface{}{
+				"ArrayContains": map[string]interface{}{
+					"user.tags[*].name": "urgent",
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "ArrayNotContains - projection over array of objects",
+			conditions: map[string]interface{}{
+				"ArrayNotContains": map[string]interface{}{
+					"user.tags[*].name": "archived",
+				},
+			},
+			expected: true,
+		},
 		{
 			name: "StringEquals - array element mismatch",
 			conditions: map[string]interface{}{
@@ -759,3 +1027,141 @@ func TestEnhancedConditionEvaluator_ArrayAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestEnhancedConditionEvaluator_VersionOperators(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"environment": map[string]interface{}{
+			"browser_version": "124.0.6367.82",
+			"os_version":      "unknown",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{"greater than or equal across digit counts", map[string]interface{}{
+			"VersionGreaterThanEquals": map[string]interface{}{"environment.browser_version": "99"},
+		}, true},
+		{"less than", map[string]interface{}{
+			"VersionLessThan": map[string]interface{}{"environment.browser_version": "124.1"},
+		}, true},
+		{"greater than fails on equal prefix", map[string]interface{}{
+			"VersionGreaterThan": map[string]interface{}{"environment.browser_version": "124.0.6367.82"},
+		}, false},
+		{"less than or equal on equal version", map[string]interface{}{
+			"VersionLessThanEquals": map[string]interface{}{"environment.browser_version": "124.0.6367.82.0"},
+		}, true},
+		{"non-numeric attribute never matches", map[string]interface{}{
+			"VersionLessThan": map[string]interface{}{"environment.os_version": "99"},
+		}, false},
+		{"missing attribute never matches", map[string]interface{}{
+			"VersionLessThan": map[string]interface{}{"environment.app_version": "99"},
+		}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := evaluator.EvaluateConditions(test.conditions, context); result != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestEnhancedConditionEvaluator_IssuedByCA(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	intermediate := strings.Repeat("ab", 32)
+	root := strings.Repeat("cd", 32)
+	clientCert := func(verified bool) map[string]interface{} {
+		return map[string]interface{}{
+			"environment:client_cert": map[string]interface{}{
+				"subject_dn": "CN=payment-service,O=Acme",
+				"verified":   verified,
+				"issuer_chain": []interface{}{
+					map[string]interface{}{"subject_dn": "CN=Services Intermediate CA,O=Acme", "fingerprint": intermediate},
+					map[string]interface{}{"subject_dn": "CN=Acme Root CA,O=Acme", "fingerprint": root},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected interface{}
+		context  map[string]interface{}
+		want     bool
+	}{
+		{"root fingerprint", root, clientCert(true), true},
+		{"colon separated uppercase fingerprint", strings.ToUpper(strings.Repeat("ab:", 31) + "ab"), clientCert(true), true},
+		{"subject DN in list", []interface{}{"CN=Other CA", "CN=Services Intermediate CA,O=Acme"}, clientCert(true), true},
+		{"unknown CA", strings.Repeat("ef", 32), clientCert(true), false},
+		{"unverified chain never matches", root, clientCert(false), false},
+		{"no client certificate", root, map[string]interface{}{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions := map[string]interface{}{
+				"IssuedByCA": map[string]interface{}{"environment.client_cert": test.expected},
+			}
+			if result := evaluator.EvaluateConditions(conditions, test.context); result != test.want {
+				t.Errorf("Expected %v, got %v", test.want, result)
+			}
+		})
+	}
+}
+
+func TestEnhancedConditionEvaluator_AttributeTransforms(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"email": "John.Doe@Company.com",
+			"roles": []interface{}{"developer", "reviewer"},
+		},
+		"resource": map[string]interface{}{
+			"path": "/documents/project-alpha/specs.pdf",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   bool
+	}{
+		{
+			name:       "StringEquals on lower()",
+			conditions: map[string]interface{}{"StringEquals": map[string]interface{}{"lower(user.email)": "john.doe@company.com"}},
+			expected:   true,
+		},
+		{
+			name:       "NumericLessThanEquals on len()",
+			conditions: map[string]interface{}{"NumericLessThanEquals": map[string]interface{}{"len(user.roles)": 2}},
+			expected:   true,
+		},
+		{
+			name:       "StringEquals on substring()",
+			conditions: map[string]interface{}{"StringEquals": map[string]interface{}{"substring(resource.path, 0, 10)": "/documents"}},
+			expected:   true,
+		},
+		{
+			name:       "ArrayContains on split()",
+			conditions: map[string]interface{}{"ArrayContains": map[string]interface{}{"split(resource.path, '/')": "project-beta"}},
+			expected:   false,
+		},
+		{
+			name:       "Unknown function never matches",
+			conditions: map[string]interface{}{"StringEquals": map[string]interface{}{"reverse(user.email)": "moc.ynapmoC@eoD.nhoJ"}},
+			expected:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := evaluator.EvaluateConditions(tt.conditions, context); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package conditions
+
+import (
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// timeAttributeChanges maps attributes derived from the evaluation time to the boundary at which they change
+var timeAttributeChanges = map[string]func(time.Time) time.Time{
+	"time_of_day":       nextMinute,
+	"minute":            nextMinute,
+	"hour":              nextHour,
+	"current_hour":      nextHour,
+	"is_business_hours": nextHour,
+	"day_of_week":       nextMidnight,
+	"is_weekend":        nextMidnight,
+	"is_holiday":        nextMidnight,
+	"holiday_name":      nextMidnight,
+}
+
+// volatileAttributes change continuously with the evaluation time
+var volatileAttributes = map[string]bool{
+	"timestamp":        true,
+	"Time":             true,
+	"auth_age_seconds": true,
+}
+
+// volatileOperators depend on state that changes between requests (counters, authentication age)
+var volatileOperators = map[string]bool{
+	constants.OpRequestRateBelow: true,
+	constants.OpDailyQuotaBelow:  true,
+	constants.OpAuthAgeLessThan:  true,
+}
+
+// comparisonOperators compare an attribute with date or clock-time literals
+var comparisonOperators = map[string]bool{
+	constants.OpDateLessThan:          true,
+	constants.OpDateLessThanEquals:    true,
+	constants.OpDateGreaterThan:       true,
+	constants.OpDateGreaterThanEquals: true,
+	constants.OpDateBetween:           true,
+	constants.OpTimeLessThan:          true,
+	constants.OpTimeLessThanEquals:    true,
+	constants.OpTimeGreaterThan:       true,
+	constants.OpTimeGreaterThanEquals: true,
+	constants.OpTimeBetween:           true,
+	constants.OpTimeOfDay:             true,
+}
+
+// NextChange returns the earliest instant after at when the outcome of a condition block may change
+// only because time passes (e.g. the 17:00 end of a business-hours window). A zero instant means the
+// block does not depend on time. ok is false when the outcome may change at any moment: quota and
+// authentication age conditions, or conditions on the raw evaluation timestamp.
+// Boundaries are conservative: they may come earlier than the actual change, never later.
+func NextChange(conditions map[string]interface{}, at time.Time) (next time.Time, ok bool) {
+	change := &changeTracker{at: at, stable: true}
+	change.block(conditions)
+	return change.next, change.stable
+}
+
+// changeTracker accumulates the earliest time boundary of a condition block
+type changeTracker struct {
+	at     time.Time
+	next   time.Time
+	stable bool
+}
+
+// add records a boundary; boundaries at or before the evaluation time are ignored
+func (c *changeTracker) add(boundary time.Time) {
+	if !boundary.After(c.at) {
+		return
+	}
+	if c.next.IsZero() || boundary.Before(c.next) {
+		c.next = boundary
+	}
+}
+
+// block walks a condition block, including nested And/Or/Not blocks
+func (c *changeTracker) block(conditions map[string]interface{}) {
+	for operator, operands := range conditions {
+		op := constants.OperatorKey(operator)
+		switch {
+		case op == constants.OpAnd || op == constants.OpOr || op == constants.OpNot:
+			c.nested(operands)
+			continue
+		case volatileOperators[op]:
+			c.stable = false
+			continue
+		}
+
+		attributes, isMap := operands.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		for key, expected := range attributes {
+			switch {
+			case comparisonOperators[op]:
+				// The literals bound the outcome, even when comparing the raw evaluation timestamp
+				c.literals(expected)
+			case op == constants.OpDayOfWeek || op == constants.OpIsHoliday:
+				c.add(nextMidnight(c.at))
+			case op == constants.OpIsBusinessHours:
+				c.add(nextHour(c.at))
+			default:
+				c.attribute(key)
+			}
+		}
+	}
+}
+
+// nested walks the operands of a logical operator: a block or a list of blocks
+func (c *changeTracker) nested(operands interface{}) {
+	switch value := operands.(type) {
+	case map[string]interface{}:
+		c.block(value)
+	case []interface{}:
+		for _, item := range value {
+			if nested, ok := item.(map[string]interface{}); ok {
+				c.block(nested)
+			}
+		}
+	}
+}
+
+// attribute records the boundary of a time-derived attribute ("environment.hour", "user:current_hour")
+func (c *changeTracker) attribute(key string) {
+	name := key
+	if i := strings.LastIndexAny(key, ".:"); i >= 0 {
+		name = key[i+1:]
+	}
+	if volatileAttributes[name] {
+		c.stable = false
+		return
+	}
+	if change, ok := timeAttributeChanges[name]; ok {
+		c.add(change(c.at))
+	}
+}
+
+// literals records the boundaries of date and clock-time literals: a value, a [start, end] range or
+// a clock window. Attribute references ("${...}") may change at any moment.
+func (c *changeTracker) literals(expected interface{}) {
+	if window, err := ParseClockWindow(expected); err == nil {
+		location := window.Location
+		if location == nil {
+			location = c.at.Location()
+		}
+		c.add(nextClock(c.at, window.Start, location))
+		c.add(nextClock(c.at, window.End+1, location))
+		return
+	}
+
+	switch value := expected.(type) {
+	case []interface{}:
+		for _, item := range value {
+			c.literals(item)
+		}
+	case string:
+		if strings.Contains(value, "${") {
+			c.stable = false
+			return
+		}
+		if clock, isClock := parseClock(value); isClock {
+			c.add(nextClock(c.at, clock, c.at.Location()))
+			c.add(nextClock(c.at, clock+60, c.at.Location())) // "HH:MM" matches for a whole minute
+			return
+		}
+		for _, format := range constants.GetAllTimeFormats() {
+			if instant, err := time.Parse(format, value); err == nil {
+				c.add(instant)
+				return
+			}
+		}
+	}
+}
+
+// nextMinute returns the start of the minute after t
+func nextMinute(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+}
+
+// nextHour returns the start of the hour after t
+func nextHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+}
+
+// nextMidnight returns the start of the day after t
+func nextMidnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+// nextClock returns the first instant after t whose time of day in location is clock (seconds since midnight)
+func nextClock(t time.Time, clock int, location *time.Location) time.Time {
+	local := t.In(location)
+	candidate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, clock, 0, location)
+	if !candidate.After(t) {
+		candidate = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, clock, 0, location)
+	}
+	return candidate
+}
@@ -0,0 +1,54 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextChange(t *testing.T) {
+	at := time.Date(2024, time.June, 3, 10, 20, 30, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   time.Time
+		stable     bool
+	}{
+		{"static", map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Engineering"}}, time.Time{}, true},
+		{"business hours window", map[string]interface{}{"TimeBetween": map[string]interface{}{"environment.time_of_day": []interface{}{"09:00", "17:00"}}},
+			time.Date(2024, time.June, 3, 17, 0, 1, 0, time.UTC), true},
+		{"window in timezone", map[string]interface{}{"TimeOfDay": map[string]interface{}{"environment.time_of_day": map[string]interface{}{"range": "09:00-17:00", "tz": "Asia/Ho_Chi_Minh"}}},
+			time.Date(2024, time.June, 4, 2, 0, 0, 0, time.UTC), true},
+		{"exact time of day", map[string]interface{}{"TimeOfDay": map[string]interface{}{"environment.time_of_day": "10:00"}},
+			time.Date(2024, time.June, 4, 10, 0, 0, 0, time.UTC), true},
+		{"date literal", map[string]interface{}{"DateLessThan": map[string]interface{}{"environment.timestamp": "2024-06-30T00:00:00Z"}},
+			time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC), true},
+		{"past date literal", map[string]interface{}{"DateGreaterThan": map[string]interface{}{"environment.timestamp": "2024-01-01"}}, time.Time{}, true},
+		{"hour attribute", map[string]interface{}{"NumericLessThan": map[string]interface{}{"environment.hour": 18}},
+			time.Date(2024, time.June, 3, 11, 0, 0, 0, time.UTC), true},
+		{"business hours operator", map[string]interface{}{"IsBusinessHours": map[string]interface{}{"environment.is_business_hours": true}},
+			time.Date(2024, time.June, 3, 11, 0, 0, 0, time.UTC), true},
+		{"day of week", map[string]interface{}{"DayOfWeek": map[string]interface{}{"environment.day_of_week": []interface{}{"monday"}}},
+			time.Date(2024, time.June, 4, 0, 0, 0, 0, time.UTC), true},
+		{"nested earliest boundary", map[string]interface{}{"Or": []interface{}{
+			map[string]interface{}{"Bool": map[string]interface{}{"environment:is_weekend": true}},
+			map[string]interface{}{"Not": map[string]interface{}{"StringEquals": map[string]interface{}{"environment.time_of_day": "10:21"}}},
+		}}, time.Date(2024, time.June, 3, 10, 21, 0, 0, time.UTC), true},
+		{"quota", map[string]interface{}{"RequestRateBelow": map[string]interface{}{"user.id": 10}}, time.Time{}, false},
+		{"auth age", map[string]interface{}{"AuthAgeLessThan": map[string]interface{}{"session.auth_time": "15m"}}, time.Time{}, false},
+		{"raw timestamp", map[string]interface{}{"StringLike": map[string]interface{}{"request:Time": "2024-06-03*"}}, time.Time{}, false},
+		{"attribute reference", map[string]interface{}{"DateLessThan": map[string]interface{}{"environment.timestamp": "${resource.expires_at}"}}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, stable := NextChange(tt.conditions, at)
+			if stable != tt.stable {
+				t.Fatalf("expected stable=%v, got %v", tt.stable, stable)
+			}
+			if tt.stable && !next.Equal(tt.expected) {
+				t.Errorf("expected next change %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
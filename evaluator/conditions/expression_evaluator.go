@@ -1,51 +1,46 @@
 package conditions
 
 import (
-	"fmt"
-	"reflect"
-	"regexp"
-	"strconv"
 	"strings"
 
-	"abac_go_example/constants"
 	"abac_go_example/models"
 )
 
 // OperatorFunc represents a function that evaluates an operator
 type OperatorFunc func(left, right interface{}) bool
 
-// ExpressionEvaluator handles complex boolean expression evaluation
+// ExpressionEvaluator handles complex boolean expression evaluation. Legacy operators (eq, in,
+// contains, ...) are translated by LegacyCondition and evaluated by an EnhancedConditionEvaluator,
+// so expressions and statements share operator semantics; only registered operators run custom code.
 type ExpressionEvaluator struct {
-	operators map[string]OperatorFunc
+	conditions *EnhancedConditionEvaluator
+	operators  map[string]OperatorFunc
 }
 
-// NewExpressionEvaluator creates a new expression evaluator with default operators
+// NewExpressionEvaluator creates a new expression evaluator with the legacy operators
 func NewExpressionEvaluator() *ExpressionEvaluator {
+	return NewExpressionEvaluatorWith(NewEnhancedConditionEvaluator())
+}
+
+// NewExpressionEvaluatorWith creates an expression evaluator delegating to evaluator, e.g. the PDP's
+func NewExpressionEvaluatorWith(evaluator *EnhancedConditionEvaluator) *ExpressionEvaluator {
 	return &ExpressionEvaluator{
-		operators: map[string]OperatorFunc{
-			constants.SizeOpEquals:            equals,
-			"ne":                              notEquals,
-			constants.SizeOpGreaterThan:       greaterThan,
-			constants.SizeOpGreaterThanEquals: greaterThanEqual,
-			constants.SizeOpLessThan:          lessThan,
-			constants.SizeOpLessThanEquals:    lessThanEqual,
-			"in":                              inArray,
-			"nin":                             notInArray,
-			"contains":                        containsValue,
-			"regex":                           regexMatch,
-			"and":                             andOperator,
-			"or":                              orOperator,
-			"not":                             notOperator,
-			"exists":                          existsOperator,
-		},
+		conditions: evaluator,
+		operators:  make(map[string]OperatorFunc),
 	}
 }
 
-// RegisterOperator adds a custom operator
+// RegisterOperator adds a custom operator, taking precedence over a legacy operator of the same name
 func (ee *ExpressionEvaluator) RegisterOperator(name string, fn OperatorFunc) {
 	ee.operators[name] = fn
 }
 
+// Evaluate implements ConditionEvaluator for *models.BooleanExpression conditions
+func (ee *ExpressionEvaluator) Evaluate(conditions interface{}, context map[string]interface{}) bool {
+	expr, ok := conditions.(*models.BooleanExpression)
+	return ok && ee.EvaluateExpression(expr, context)
+}
+
 // EvaluateExpression evaluates a boolean expression against attributes
 func (ee *ExpressionEvaluator) EvaluateExpression(expr *models.BooleanExpression, attributes map[string]interface{}) bool {
 	if expr == nil {
@@ -67,18 +62,20 @@ func (ee *ExpressionEvaluator) evaluateSimpleCondition(condition *models.SimpleC
 	if condition == nil {
 		return true
 	}
+	return ee.evaluateComparison(condition.AttributePath, condition.Operator, condition.Value, attributes)
+}
 
-	// Get the actual value from attributes
-	actualValue := ee.getNestedValue(condition.AttributePath, attributes)
+// evaluateComparison runs a registered operator, or the statement operators a legacy one translates to
+func (ee *ExpressionEvaluator) evaluateComparison(attributePath, operator string, expected interface{}, attributes map[string]interface{}) bool {
+	if operatorFn, exists := ee.operators[operator]; exists {
+		return operatorFn(ee.getNestedValue(attributePath, attributes), expected)
+	}
 
-	// Get the operator function
-	operatorFn, exists := ee.operators[condition.Operator]
-	if !exists {
+	translated, err := LegacyCondition(attributePath, operator, expected)
+	if err != nil {
 		return false
 	}
-
-	// Evaluate the condition
-	return operatorFn(actualValue, condition.Value)
+	return ee.conditions.EvaluateConditions(translated, attributes)
 }
 
 // evaluateCompoundExpression evaluates a compound expression with logical operators
@@ -129,189 +126,6 @@ func (ee *ExpressionEvaluator) getNestedValue(path string, attributes map[string
 	return nil
 }
 
-// Operator implementations
-
-func equals(left, right interface{}) bool {
-	return reflect.DeepEqual(left, right)
-}
-
-func notEquals(left, right interface{}) bool {
-	return !reflect.DeepEqual(left, right)
-}
-
-func greaterThan(left, right interface{}) bool {
-	leftNum := toFloat64(left)
-	rightNum := toFloat64(right)
-	return leftNum > rightNum
-}
-
-func greaterThanEqual(left, right interface{}) bool {
-	leftNum := toFloat64(left)
-	rightNum := toFloat64(right)
-	return leftNum >= rightNum
-}
-
-func lessThan(left, right interface{}) bool {
-	leftNum := toFloat64(left)
-	rightNum := toFloat64(right)
-	return leftNum < rightNum
-}
-
-func lessThanEqual(left, right interface{}) bool {
-	leftNum := toFloat64(left)
-	rightNum := toFloat64(right)
-	return leftNum <= rightNum
-}
-
-func inArray(left, right interface{}) bool {
-	rightSlice := toInterfaceSlice(right)
-	if rightSlice == nil {
-		return false
-	}
-
-	for _, item := range rightSlice {
-		if reflect.DeepEqual(left, item) {
-			return true
-		}
-	}
-	return false
-}
-
-func notInArray(left, right interface{}) bool {
-	return !inArray(left, right)
-}
-
-func containsValue(left, right interface{}) bool {
-	leftSlice := toInterfaceSlice(left)
-	if leftSlice == nil {
-		// If left is not a slice, check if it's a string containing right
-		leftStr := toString(left)
-		rightStr := toString(right)
-		return strings.Contains(leftStr, rightStr)
-	}
-
-	for _, item := range leftSlice {
-		if reflect.DeepEqual(item, right) {
-			return true
-		}
-	}
-	return false
-}
-
-func regexMatch(left, right interface{}) bool {
-	leftStr := toString(left)
-	rightStr := toString(right)
-
-	if rightStr == "" {
-		return false
-	}
-
-	matched, err := regexp.MatchString(rightStr, leftStr)
-	if err != nil {
-		return false
-	}
-	return matched
-}
-
-func andOperator(left, right interface{}) bool {
-	leftBool := toBool(left)
-	rightBool := toBool(right)
-	return leftBool && rightBool
-}
-
-func orOperator(left, right interface{}) bool {
-	leftBool := toBool(left)
-	rightBool := toBool(right)
-	return leftBool || rightBool
-}
-
-func notOperator(left, right interface{}) bool {
-	leftBool := toBool(left)
-	return !leftBool
-}
-
-func existsOperator(left, right interface{}) bool {
-	return left != nil
-}
-
-// Helper functions
-
-func toFloat64(value interface{}) float64 {
-	if value == nil {
-		return 0
-	}
-
-	switch v := value.(type) {
-	case float64:
-		return v
-	case float32:
-		return float64(v)
-	case int:
-		return float64(v)
-	case int32:
-		return float64(v)
-	case int64:
-		return float64(v)
-	case string:
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
-			return f
-		}
-	}
-	return 0
-}
-
-func toString(value interface{}) string {
-	if value == nil {
-		return ""
-	}
-
-	switch v := value.(type) {
-	case string:
-		return v
-	case fmt.Stringer:
-		return v.String()
-	default:
-		return fmt.Sprintf("%v", v)
-	}
-}
-
-func toBool(value interface{}) bool {
-	if value == nil {
-		return false
-	}
-
-	switch v := value.(type) {
-	case bool:
-		return v
-	case string:
-		return strings.ToLower(v) == constants.BoolStringTrue
-	case int:
-		return v != 0
-	case int64:
-		return v != 0
-	case float64:
-		return v != 0
-	}
-	return false
-}
-
-func toInterfaceSlice(value interface{}) []interface{} {
-	if value == nil {
-		return nil
-	}
-
-	v := reflect.ValueOf(value)
-	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
-		return nil
-	}
-
-	result := make([]interface{}, v.Len())
-	for i := 0; i < v.Len(); i++ {
-		result[i] = v.Index(i).Interface()
-	}
-	return result
-}
-
 // EvaluateComplexExpression evaluates a complex expression with multiple conditions
 // Example: (user.department == "Engineering" AND user.level >= 5) OR
 //
@@ -348,15 +162,9 @@ func (ee *ExpressionEvaluator) evaluateOperatorConditions(operator string, opera
 		return false
 	}
 
-	operatorFn, exists := ee.operators[operator]
-	if !exists {
-		return false
-	}
-
 	// All conditions for this operator must pass
 	for attributePath, expectedValue := range conditionsMap {
-		actualValue := ee.getNestedValue(attributePath, attributes)
-		if !operatorFn(actualValue, expectedValue) {
+		if !ee.evaluateComparison(attributePath, operator, expectedValue, attributes) {
 			return false
 		}
 	}
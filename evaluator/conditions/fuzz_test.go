@@ -0,0 +1,74 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"abac_go_example/evaluator/path"
+)
+
+// FuzzLikePattern ensures StringLike patterns never panic and match literal text literally
+func FuzzLikePattern(f *testing.F) {
+	f.Add("%admin%")
+	f.Add("user_")
+	f.Add("a.b(c)[d]{e}|f^$+?\\")
+	f.Add("%%%_%%%")
+	f.Add("50\\%")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, pattern string) {
+		if !utf8.ValidString(pattern) {
+			t.Skip()
+		}
+
+		compiled := CompileLikePattern(pattern)
+
+		// Without wildcards or escapes the pattern must match exactly itself
+		if !strings.ContainsAny(pattern, "%_\\") {
+			if !compiled.Match(pattern) {
+				t.Errorf("Literal pattern %q does not match itself", pattern)
+			}
+			if compiled.Match(pattern + "x") {
+				t.Errorf("Literal pattern %q matches a longer string", pattern)
+			}
+		}
+
+		// Any text escaped for LIKE matches itself and nothing longer
+		escaped := CompileLikePattern(escapeLike(pattern))
+		if !escaped.Match(pattern) || escaped.Match(pattern+"x") {
+			t.Errorf("Escaped pattern for %q does not match it exactly", pattern)
+		}
+
+		// '%' wildcards must accept any text in their position
+		if strings.Count(pattern, "%") == 1 && !strings.ContainsAny(pattern, "_\\") {
+			candidate := strings.Replace(pattern, "%", "any\ntext.*", 1)
+			if !compiled.Match(candidate) {
+				t.Errorf("Pattern %q should match %q", pattern, candidate)
+			}
+		}
+	})
+}
+
+// escapeLike escapes the LIKE wildcards and backslashes in text
+func escapeLike(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(text)
+}
+
+// FuzzParseTime ensures time parsing never panics on arbitrary input
+func FuzzParseTime(f *testing.F) {
+	f.Add("2024-01-15T10:30:00Z")
+	f.Add("2024-01-15 10:30:00")
+	f.Add("10:30")
+	f.Add("2024-13-45")
+	f.Add("99999-99-99T99:99:99+99:99")
+	f.Add("")
+
+	evaluator := NewBaseEvaluator(path.NewCompositePathResolver())
+	f.Fuzz(func(t *testing.T, value string) {
+		parsed := evaluator.ParseTime(value)
+		if !parsed.IsZero() && parsed.Year() < 0 {
+			t.Errorf("ParseTime(%q) returned invalid year %d", value, parsed.Year())
+		}
+	})
+}
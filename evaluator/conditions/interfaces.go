@@ -1,6 +1,10 @@
 package conditions
 
-import "time"
+import (
+	"time"
+
+	"abac_go_example/holidays"
+)
 
 // ConditionEvaluator defines the interface for all condition evaluators
 type ConditionEvaluator interface {
@@ -17,6 +21,11 @@ type StringEvaluator interface {
 	EvaluateStartsWith(conditions interface{}, context map[string]interface{}) bool
 	EvaluateEndsWith(conditions interface{}, context map[string]interface{}) bool
 	EvaluateRegex(conditions interface{}, context map[string]interface{}) bool
+	EvaluateEqualsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool
+	EvaluateNotEqualsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool
+	EvaluateContainsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool
+	EvaluateStartsWithIgnoreAccents(conditions interface{}, context map[string]interface{}) bool
+	EvaluateVersion(conditions interface{}, context map[string]interface{}, accept func(comparison int) bool) bool
 }
 
 // NumericEvaluator handles numeric-based condition evaluations
@@ -42,6 +51,9 @@ type TimeEvaluator interface {
 	EvaluateDayOfWeek(conditions interface{}, context map[string]interface{}) bool
 	EvaluateTimeOfDay(conditions interface{}, context map[string]interface{}) bool
 	EvaluateIsBusinessHours(conditions interface{}, context map[string]interface{}) bool
+	EvaluateIsHoliday(conditions interface{}, context map[string]interface{}) bool
+	SetHolidayCalendar(calendar holidays.Calendar)
+	EvaluateAuthAgeLessThan(conditions interface{}, context map[string]interface{}) bool
 }
 
 // ArrayEvaluator handles array-based condition evaluations
@@ -58,6 +70,7 @@ type NetworkEvaluator interface {
 	EvaluateIPInRange(conditions interface{}, context map[string]interface{}) bool
 	EvaluateIPNotInRange(conditions interface{}, context map[string]interface{}) bool
 	EvaluateIsInternalIP(conditions interface{}, context map[string]interface{}) bool
+	EvaluateIssuedByCA(conditions interface{}, context map[string]interface{}) bool
 }
 
 // LogicalEvaluator handles logical operations (AND, OR, NOT)
@@ -0,0 +1,193 @@
+package conditions
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"abac_go_example/models"
+)
+
+// Legacy operators of PolicyRule and BooleanExpression conditions
+const (
+	LegacyOpEquals            = "eq"
+	LegacyOpNotEquals         = "ne"
+	LegacyOpGreaterThan       = "gt"
+	LegacyOpGreaterThanEquals = "gte"
+	LegacyOpLessThan          = "lt"
+	LegacyOpLessThanEquals    = "lte"
+	LegacyOpIn                = "in"
+	LegacyOpNotIn             = "nin"
+	LegacyOpContains          = "contains"
+	LegacyOpRegex             = "regex"
+	LegacyOpExists            = "exists"
+)
+
+// legacyNumericOperators maps legacy comparisons to their numeric statement operator
+var legacyNumericOperators = map[string]string{
+	LegacyOpGreaterThan:       "NumericGreaterThan",
+	LegacyOpGreaterThanEquals: "NumericGreaterThanEquals",
+	LegacyOpLessThan:          "NumericLessThan",
+	LegacyOpLessThanEquals:    "NumericLessThanEquals",
+}
+
+// IsLegacyOperator reports whether operator is one of the legacy comparison operators
+func IsLegacyOperator(operator string) bool {
+	switch strings.ToLower(operator) {
+	case LegacyOpEquals, LegacyOpNotEquals, LegacyOpIn, LegacyOpNotIn, LegacyOpContains, LegacyOpRegex, LegacyOpExists:
+		return true
+	}
+	_, numeric := legacyNumericOperators[strings.ToLower(operator)]
+	return numeric
+}
+
+// LegacyCondition translates one legacy comparison (attributePath operator value) into a statement
+// Condition block, so legacy policies run through the same operators as statements:
+//
+//	eq        StringEquals, NumericEquals or Bool by the type of value; Null for a nil value
+//	ne, nin   Not of eq / in
+//	gt … lte  NumericGreaterThan … NumericLessThanEquals
+//	in        Or of eq for every element of value
+//	contains  ArrayContains, or StringContains (substring) when the attribute is a string
+//	regex     StringRegex
+//	exists    Null false
+func LegacyCondition(attributePath, operator string, value interface{}) (map[string]interface{}, error) {
+	op := strings.ToLower(operator)
+	if statementOperator, ok := legacyNumericOperators[op]; ok {
+		if !isLegacyNumber(value) {
+			return nil, fmt.Errorf("operator %q on %s requires a number, got %T", operator, attributePath, value)
+		}
+		return block(statementOperator, attributePath, value), nil
+	}
+
+	switch op {
+	case LegacyOpEquals:
+		return legacyEquals(attributePath, value)
+	case LegacyOpNotEquals:
+		equals, err := legacyEquals(attributePath, value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Not": equals}, nil
+	case LegacyOpIn:
+		return legacyIn(attributePath, operator, value)
+	case LegacyOpNotIn:
+		in, err := legacyIn(attributePath, operator, value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Not": in}, nil
+	case LegacyOpContains:
+		if text, ok := value.(string); ok {
+			return map[string]interface{}{"Or": []interface{}{
+				block("ArrayContains", attributePath, text),
+				block("StringContains", attributePath, text),
+			}}, nil
+		}
+		return block("ArrayContains", attributePath, value), nil
+	case LegacyOpRegex:
+		pattern, ok := value.(string)
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("operator %q on %s requires a non-empty pattern", operator, attributePath)
+		}
+		return block("StringRegex", attributePath, pattern), nil
+	case LegacyOpExists:
+		return block("Null", attributePath, false), nil
+	default:
+		return nil, fmt.Errorf("unknown legacy operator %q", operator)
+	}
+}
+
+// LegacyExpressionConditions translates a BooleanExpression tree into a statement Condition block
+func LegacyExpressionConditions(expr *models.BooleanExpression) (map[string]interface{}, error) {
+	if expr == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	switch expr.Type {
+	case "simple":
+		if expr.Condition == nil {
+			return map[string]interface{}{}, nil
+		}
+		return LegacyCondition(expr.Condition.AttributePath, expr.Condition.Operator, expr.Condition.Value)
+	case "compound":
+		switch strings.ToLower(expr.Operator) {
+		case "and", "or":
+			if expr.Left == nil || expr.Right == nil {
+				return nil, fmt.Errorf("%q expression requires left and right operands", expr.Operator)
+			}
+			left, err := LegacyExpressionConditions(expr.Left)
+			if err != nil {
+				return nil, err
+			}
+			right, err := LegacyExpressionConditions(expr.Right)
+			if err != nil {
+				return nil, err
+			}
+			logical := "And"
+			if strings.ToLower(expr.Operator) == "or" {
+				logical = "Or"
+			}
+			return map[string]interface{}{logical: []interface{}{left, right}}, nil
+		case "not":
+			if expr.Left == nil {
+				return nil, fmt.Errorf("\"not\" expression requires a left operand")
+			}
+			operand, err := LegacyExpressionConditions(expr.Left)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Not": operand}, nil
+		}
+		return nil, fmt.Errorf("unknown compound operator %q", expr.Operator)
+	default:
+		return nil, fmt.Errorf("unknown expression type %q", expr.Type)
+	}
+}
+
+// legacyEquals picks the statement operator comparing like the legacy "eq" for the type of value
+func legacyEquals(attributePath string, value interface{}) (map[string]interface{}, error) {
+	switch {
+	case value == nil:
+		return block("Null", attributePath, true), nil
+	case isLegacyNumber(value):
+		return block("NumericEquals", attributePath, value), nil
+	}
+	switch v := value.(type) {
+	case string:
+		return block("StringEquals", attributePath, v), nil
+	case bool:
+		return block("Bool", attributePath, v), nil
+	}
+	return nil, fmt.Errorf("operator \"eq\" on %s does not support %T values", attributePath, value)
+}
+
+// legacyIn matches when the attribute equals any element of value
+func legacyIn(attributePath, operator string, value interface{}) (map[string]interface{}, error) {
+	items := reflect.ValueOf(value)
+	if value == nil || (items.Kind() != reflect.Slice && items.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("operator %q on %s requires an array, got %T", operator, attributePath, value)
+	}
+
+	anyOf := make([]interface{}, 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		equals, err := legacyEquals(attributePath, items.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		anyOf = append(anyOf, equals)
+	}
+	return map[string]interface{}{"Or": anyOf}, nil
+}
+
+func block(operator, attributePath string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{operator: map[string]interface{}{attributePath: value}}
+}
+
+func isLegacyNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
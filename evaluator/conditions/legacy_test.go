@@ -0,0 +1,126 @@
+package conditions
+
+import (
+	"reflect"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestLegacyCondition(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    interface{}
+		want     map[string]interface{}
+	}{
+		{"eq", "Engineering", map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Engineering"}}},
+		{"eq", 5, map[string]interface{}{"NumericEquals": map[string]interface{}{"user.department": 5}}},
+		{"eq", true, map[string]interface{}{"Bool": map[string]interface{}{"user.department": true}}},
+		{"gte", 3.5, map[string]interface{}{"NumericGreaterThanEquals": map[string]interface{}{"user.department": 3.5}}},
+		{"exists", nil, map[string]interface{}{"Null": map[string]interface{}{"user.department": false}}},
+		{"ne", "Sales", map[string]interface{}{"Not": map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Sales"}}}},
+	}
+	for _, tt := range tests {
+		got, err := LegacyCondition("user.department", tt.operator, tt.value)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LegacyCondition(%s, %v) = %v, %v; want %v", tt.operator, tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, invalid := range []struct {
+		operator string
+		value    interface{}
+	}{{"gt", "high"}, {"in", "Engineering"}, {"regex", ""}, {"between", 1}, {"eq", map[string]interface{}{}}} {
+		if _, err := LegacyCondition("user.level", invalid.operator, invalid.value); err == nil {
+			t.Errorf("Expected an error for %s %v", invalid.operator, invalid.value)
+		}
+	}
+}
+
+func TestExpressionEvaluatorLegacyOperators(t *testing.T) {
+	evaluator := NewExpressionEvaluator()
+	attributes := map[string]interface{}{
+		"user": map[string]interface{}{
+			"department": "Engineering",
+			"level":      5,
+			"roles":      []interface{}{"developer", "reviewer"},
+			"email":      "dev@example.com",
+		},
+	}
+
+	tests := []struct {
+		path     string
+		operator string
+		value    interface{}
+		want     bool
+	}{
+		{"user.department", "eq", "Engineering", true},
+		{"user.department", "ne", "Engineering", false},
+		{"user.level", "gt", 3, true},
+		{"user.level", "lte", 4, false},
+		{"user.department", "in", []interface{}{"Sales", "Engineering"}, true},
+		{"user.department", "nin", []interface{}{"Sales", "Engineering"}, false},
+		{"user.roles", "contains", "reviewer", true},
+		{"user.email", "contains", "@example", true},
+		{"user.email", "regex", `^dev@`, true},
+		{"user.department", "exists", nil, true},
+		{"user.manager", "exists", nil, false},
+		{"user.manager", "ne", "alice", true},
+		{"user.department", "unknown", "x", false},
+	}
+	for _, tt := range tests {
+		expr := &models.BooleanExpression{Type: "simple", Condition: &models.SimpleCondition{
+			AttributePath: tt.path, Operator: tt.operator, Value: tt.value,
+		}}
+		if got := evaluator.EvaluateExpression(expr, attributes); got != tt.want {
+			t.Errorf("%s %s %v = %v, want %v", tt.path, tt.operator, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionEvaluatorCustomOperator(t *testing.T) {
+	evaluator := NewExpressionEvaluator()
+	evaluator.RegisterOperator("eq", func(left, right interface{}) bool { return true })
+
+	expr := &models.BooleanExpression{Type: "simple", Condition: &models.SimpleCondition{
+		AttributePath: "user.department", Operator: "eq", Value: "Sales",
+	}}
+	if !evaluator.EvaluateExpression(expr, map[string]interface{}{}) {
+		t.Error("Expected the registered operator to take precedence")
+	}
+}
+
+func TestEvaluateBooleanExpression(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{"user": map[string]interface{}{"department": "Engineering", "level": 2}}
+
+	expr := &models.BooleanExpression{
+		Type:     "compound",
+		Operator: "or",
+		Left: &models.BooleanExpression{Type: "simple", Condition: &models.SimpleCondition{
+			AttributePath: "user.level", Operator: "gte", Value: 5,
+		}},
+		Right: &models.BooleanExpression{Type: "compound", Operator: "not", Left: &models.BooleanExpression{
+			Type: "simple", Condition: &models.SimpleCondition{AttributePath: "user.department", Operator: "eq", Value: "Sales"},
+		}},
+	}
+	if !evaluator.Evaluate(expr, context) {
+		t.Error("Expected legacy expression to match through the enhanced evaluator")
+	}
+	broken := &models.BooleanExpression{Type: "compound", Operator: "and", Left: expr}
+	if evaluator.Evaluate(broken, context) {
+		t.Error("Expected an incomplete expression not to match")
+	}
+}
+
+func TestNullOperator(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{"user": map[string]interface{}{"department": "Engineering"}}
+
+	if !evaluator.EvaluateConditions(map[string]interface{}{"Null": map[string]interface{}{"user.manager": true}}, context) {
+		t.Error("Expected Null true to match an absent attribute")
+	}
+	if evaluator.EvaluateConditions(map[string]interface{}{"Null": map[string]interface{}{"user.department": true}}, context) {
+		t.Error("Expected Null true not to match a present attribute")
+	}
+}
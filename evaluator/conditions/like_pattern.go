@@ -0,0 +1,131 @@
+package conditions
+
+import (
+	"sync"
+)
+
+// maxCompiledPatterns bounds the per-evaluator caches of compiled LIKE and regex patterns.
+// When the limit is reached the cache is cleared, so attacker-controlled patterns cannot grow it without bound.
+const maxCompiledPatterns = 1024
+
+// likeTokenKind identifies the kind of a compiled LIKE pattern token
+type likeTokenKind int
+
+const (
+	likeLiteral likeTokenKind = iota // one literal rune
+	likeAnyOne                       // '_': exactly one rune
+	likeAnySeq                       // '%': any sequence of runes, including none
+)
+
+// likeToken is one element of a compiled LIKE pattern
+type likeToken struct {
+	kind likeTokenKind
+	r    rune
+}
+
+// LikePattern is a compiled SQL LIKE pattern.
+// '%' matches any sequence and '_' any single character; every other character is matched literally.
+// A backslash escapes '%', '_' and '\' ("50\%" matches "50%"); before any other character it is a literal backslash.
+type LikePattern struct {
+	tokens []likeToken
+}
+
+// CompileLikePattern compiles a SQL LIKE pattern. It never fails: there is no invalid LIKE pattern.
+func CompileLikePattern(pattern string) *LikePattern {
+	runes := []rune(pattern)
+	tokens := make([]likeToken, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes) && (runes[i+1] == '%' || runes[i+1] == '_' || runes[i+1] == '\\'):
+			i++
+			tokens = append(tokens, likeToken{kind: likeLiteral, r: runes[i]})
+		case r == '%':
+			// Consecutive '%' are equivalent to one
+			if n := len(tokens); n > 0 && tokens[n-1].kind == likeAnySeq {
+				continue
+			}
+			tokens = append(tokens, likeToken{kind: likeAnySeq})
+		case r == '_':
+			tokens = append(tokens, likeToken{kind: likeAnyOne})
+		default:
+			tokens = append(tokens, likeToken{kind: likeLiteral, r: r})
+		}
+	}
+	return &LikePattern{tokens: tokens}
+}
+
+// Match reports whether value matches the whole pattern.
+// It backtracks only to the most recent '%', so matching is O(len(pattern) * len(value)) in the worst case.
+func (p *LikePattern) Match(value string) bool {
+	input := []rune(value)
+	tokenIdx, inputIdx := 0, 0
+	starIdx, starInput := -1, 0
+
+	for inputIdx < len(input) {
+		if tokenIdx < len(p.tokens) {
+			token := p.tokens[tokenIdx]
+			switch {
+			case token.kind == likeAnySeq:
+				starIdx, starInput = tokenIdx, inputIdx
+				tokenIdx++
+				continue
+			case token.kind == likeAnyOne || token.r == input[inputIdx]:
+				tokenIdx++
+				inputIdx++
+				continue
+			}
+		}
+		if starIdx < 0 {
+			return false
+		}
+		// Let the last '%' absorb one more rune and retry
+		starInput++
+		tokenIdx, inputIdx = starIdx+1, starInput
+	}
+
+	for tokenIdx < len(p.tokens) && p.tokens[tokenIdx].kind == likeAnySeq {
+		tokenIdx++
+	}
+	return tokenIdx == len(p.tokens)
+}
+
+// compiledCache is a bounded, concurrency-safe cache of compiled patterns keyed by their source
+type compiledCache[T any] struct {
+	mu      sync.RWMutex
+	entries map[string]T
+}
+
+func newCompiledCache[T any]() *compiledCache[T] {
+	return &compiledCache[T]{entries: make(map[string]T)}
+}
+
+// get returns the compiled form of pattern, compiling and caching it on a miss
+func (c *compiledCache[T]) get(pattern string, compile func(string) (T, error)) (T, error) {
+	c.mu.RLock()
+	compiled, exists := c.entries[pattern]
+	c.mu.RUnlock()
+	if exists {
+		return compiled, nil
+	}
+
+	compiled, err := compile(pattern)
+	if err != nil {
+		return compiled, err
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxCompiledPatterns {
+		c.entries = make(map[string]T)
+	}
+	c.entries[pattern] = compiled
+	c.mu.Unlock()
+	return compiled, nil
+}
+
+// len returns the number of cached patterns
+func (c *compiledCache[T]) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
@@ -0,0 +1,98 @@
+package conditions
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"abac_go_example/evaluator/path"
+)
+
+func TestLikePattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		expected       bool
+	}{
+		{"%@company.com", "alice@company.com", true},
+		{"%@company.com", "alice@companyXcom", false}, // '.' is literal
+		{"report (final).pdf", "report (final).pdf", true},
+		{"report (final).pdf", "report final.pdf", false},
+		{"[draft]%", "[draft] plan", true},
+		{"[draft]%", "d plan", false},
+		{"a+b*c?", "a+b*c?", true},
+		{"a+b*c?", "aab", false},
+		{"user_", "user1", true},
+		{"user_", "user", false},
+		{"user_", "user12", false},
+		{"_é_", "cés", true}, // '_' matches one character, not one byte
+		{"/documents/project-%/%.pdf", "/documents/project-alpha/spec.pdf", true},
+		{"%a%b%", "xxaxxbxx", true},
+		{"%a%b%", "xxbxxaxx", false},
+		{"%%", "", true},
+		{"", "", true},
+		{"", "x", false},
+		{"%\nline%", "first\nline two", true},
+		{`50\%`, "50%", true},
+		{`50\%`, "500", false},
+		{`snake\_case`, "snake_case", true},
+		{`snake\_case`, "snakeXcase", false},
+		{`C:\\tmp\\%`, `C:\tmp\file`, true},
+		{`C:\temp`, `C:\temp`, true}, // a backslash before an ordinary character is literal
+		{`trailing\`, `trailing\`, true},
+	}
+
+	for _, test := range tests {
+		if got := CompileLikePattern(test.pattern).Match(test.value); got != test.expected {
+			t.Errorf("LIKE %q on %q = %v, want %v", test.pattern, test.value, got, test.expected)
+		}
+	}
+}
+
+func TestStringEvaluator_PatternCaches(t *testing.T) {
+	evaluator := NewStringEvaluator(path.NewCompositePathResolver())
+	context := map[string]interface{}{
+		"user": map[string]interface{}{"email": "alice@company.com"},
+	}
+
+	// Concurrent evaluation shares the caches without races (run with -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if !evaluator.EvaluateLike(map[string]interface{}{"user.email": "%@company.com"}, context) {
+					t.Error("Expected LIKE to match")
+				}
+				if !evaluator.EvaluateRegex(map[string]interface{}{"user.email": `@company\.com$`}, context) {
+					t.Error("Expected regex to match")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if evaluator.likeCache.len() != 1 || evaluator.regexCache.len() != 1 {
+		t.Errorf("Expected one cached pattern each, got like=%d regex=%d", evaluator.likeCache.len(), evaluator.regexCache.len())
+	}
+
+	// Invalid regexes are rejected and not cached
+	if evaluator.EvaluateRegex(map[string]interface{}{"user.email": "("}, context) {
+		t.Error("Expected invalid regex not to match")
+	}
+	if evaluator.regexCache.len() != 1 {
+		t.Errorf("Expected invalid regex not to be cached, got %d entries", evaluator.regexCache.len())
+	}
+
+	// The cache is bounded
+	cache := newCompiledCache[*regexp.Regexp]()
+	for i := 0; i <= maxCompiledPatterns; i++ {
+		if _, err := cache.get(fmt.Sprintf("p%d", i), regexp.Compile); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.len() > maxCompiledPatterns {
+		t.Errorf("Expected at most %d cached patterns, got %d", maxCompiledPatterns, cache.len())
+	}
+}
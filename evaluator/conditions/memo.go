@@ -0,0 +1,96 @@
+package conditions
+
+import (
+	"fmt"
+	"sync"
+
+	"abac_go_example/constants"
+)
+
+// memoKey identifies one condition: an operator applied to an attribute path with an expected value
+type memoKey struct {
+	operator string
+	path     string
+	expected string
+}
+
+// ConditionMemo caches condition results for the duration of one evaluation request, so a
+// condition repeated across policies (e.g. IsInternalIP on environment.client_ip) is computed once.
+// It is safe for concurrent use.
+type ConditionMemo struct {
+	mu      sync.Mutex
+	results map[memoKey]bool
+	hits    int64
+	misses  int64
+}
+
+// NewConditionMemo creates an empty memo
+func NewConditionMemo() *ConditionMemo {
+	return &ConditionMemo{results: make(map[memoKey]bool)}
+}
+
+// EnableMemo installs a new memo in an evaluation context and returns it. The context must not
+// change afterwards: results are reused for the lifetime of the context.
+func EnableMemo(context map[string]interface{}) *ConditionMemo {
+	memo := NewConditionMemo()
+	context[constants.ContextKeyConditionMemo] = memo
+	return memo
+}
+
+// memoFromContext returns the memo installed by EnableMemo, or nil
+func memoFromContext(context map[string]interface{}) *ConditionMemo {
+	memo, _ := context[constants.ContextKeyConditionMemo].(*ConditionMemo)
+	return memo
+}
+
+// Stats returns how many condition results were reused and computed
+func (m *ConditionMemo) Stats() (hits, misses int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits, m.misses
+}
+
+// lookup returns the memoized result of key
+func (m *ConditionMemo) lookup(key memoKey) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, ok := m.results[key]
+	if ok {
+		m.hits++
+	} else {
+		m.misses++
+	}
+	return result, ok
+}
+
+// store records the result of key
+func (m *ConditionMemo) store(key memoKey, result bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[key] = result
+}
+
+// isMemoizable reports whether an operator (lowercased) is worth memoizing: building a memo key
+// costs more than a plain comparison, and quota operators read counters that change between requests
+// of the same subject, so only moderately expensive operators are memoized.
+func isMemoizable(operator string) bool {
+	cost := operatorCosts[operator]
+	return cost >= costPerCIDR && cost < costQuota
+}
+
+// evaluateMemoized evaluates each attribute of an operator block separately, reusing memoized results.
+// Operator blocks match when every attribute matches, so per-attribute results compose to the block result.
+func (ece *EnhancedConditionEvaluator) evaluateMemoized(memo *ConditionMemo, operator string, block map[string]interface{}, context map[string]interface{}) bool {
+	for attributePath, expected := range block {
+		key := memoKey{operator: operator, path: attributePath, expected: fmt.Sprintf("%#v", expected)}
+		result, ok := memo.lookup(key)
+		if !ok {
+			result = ece.dispatchOperator(operator, map[string]interface{}{attributePath: expected}, context)
+			memo.store(key, result)
+		}
+		if !result {
+			return false
+		}
+	}
+	return true
+}
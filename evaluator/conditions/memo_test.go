@@ -0,0 +1,97 @@
+package conditions
+
+import (
+	"testing"
+
+	"abac_go_example/quota"
+)
+
+func TestConditionMemo_ReusesResults(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"environment": map[string]interface{}{"client_ip": "10.1.2.3"},
+		"user":        map[string]interface{}{"roles": []interface{}{"analyst"}, "department": "finance"},
+	}
+	memo := EnableMemo(context)
+
+	// The same conditions repeated across three "policies", once nested in an Or
+	policies := []map[string]interface{}{
+		{
+			"IsInternalIP":  map[string]interface{}{"environment.client_ip": true},
+			"ArrayContains": map[string]interface{}{"user.roles": "analyst"},
+		},
+		{
+			"IsInternalIP": map[string]interface{}{"environment.client_ip": true},
+			"StringEquals": map[string]interface{}{"user.department": "finance"},
+		},
+		{
+			"Or": []interface{}{
+				map[string]interface{}{"ArrayContains": map[string]interface{}{"user.roles": "analyst"}},
+				map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "hr"}},
+			},
+		},
+	}
+	for i, conditions := range policies {
+		if !evaluator.EvaluateConditions(conditions, context) {
+			t.Errorf("Policy %d: expected conditions to match", i)
+		}
+	}
+
+	// StringEquals is cheaper than a memo lookup and is never memoized
+	if hits, misses := memo.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %d hits, %d misses", hits, misses)
+	}
+}
+
+func TestConditionMemo_PerAttributeResults(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"environment": map[string]interface{}{"client_ip": "10.1.2.3"},
+		"user":        map[string]interface{}{"roles": []interface{}{"analyst"}, "tags": []interface{}{"regular"}},
+	}
+	EnableMemo(context)
+
+	// One failing attribute fails the whole block, also when the other attribute is memoized as matching
+	block := map[string]interface{}{
+		"ArrayContains": map[string]interface{}{"user.roles": "analyst", "user.tags": "vip"},
+	}
+	if evaluator.EvaluateConditions(block, context) {
+		t.Error("Expected block with a failing attribute not to match")
+	}
+	if !evaluator.EvaluateConditions(map[string]interface{}{"ArrayContains": map[string]interface{}{"user.roles": "analyst"}}, context) {
+		t.Error("Expected memoized matching attribute to match")
+	}
+
+	// Expected values are part of the key: one malformed CIDR is not the same condition as two CIDRs
+	joined := map[string]interface{}{"IPInRange": map[string]interface{}{"environment.client_ip": []interface{}{"10.0.0.0/8 192.168.0.0/16"}}}
+	split := map[string]interface{}{"IPInRange": map[string]interface{}{"environment.client_ip": []interface{}{"10.0.0.0/8", "192.168.0.0/16"}}}
+	if evaluator.EvaluateConditions(joined, context) {
+		t.Error("Expected a malformed CIDR not to match")
+	}
+	if !evaluator.EvaluateConditions(split, context) {
+		t.Error("Expected the CIDR list to match")
+	}
+}
+
+func TestConditionMemo_QuotaNotMemoized(t *testing.T) {
+	provider := quota.NewMemoryCounterProvider()
+	evaluator := NewEnhancedConditionEvaluator()
+	evaluator.SetCounterProvider(provider)
+
+	conditions := map[string]interface{}{
+		"RequestRateBelow": map[string]interface{}{"user.id": map[string]interface{}{"limit": 1, "window": "1m"}},
+	}
+	context := map[string]interface{}{"user": map[string]interface{}{"id": "alice"}}
+	memo := EnableMemo(context)
+
+	if !evaluator.EvaluateConditions(conditions, context) {
+		t.Fatal("Expected first request to be under the rate limit")
+	}
+	evaluator.ConsumeQuotas(conditions, context)
+	if evaluator.EvaluateConditions(conditions, context) {
+		t.Error("Expected the consumed quota to be read again rather than memoized")
+	}
+	if hits, misses := memo.Stats(); hits != 0 || misses != 0 {
+		t.Errorf("Expected quota operators to bypass the memo, got %d hits, %d misses", hits, misses)
+	}
+}
@@ -2,7 +2,9 @@ package conditions
 
 import (
 	"net"
+	"strings"
 
+	"abac_go_example/constants"
 	"abac_go_example/evaluator/path"
 	"abac_go_example/operators"
 )
@@ -78,6 +80,56 @@ func (ne *NetworkConditionEvaluator) EvaluateIsInternalIP(conditions interface{}
 	})
 }
 
+// EvaluateIssuedByCA checks that a client certificate (environment.client_cert) chains to one of the
+// expected CAs, given by SHA-256 fingerprint or subject DN. Only verified chains match, so a
+// self-signed certificate naming a trusted CA as its issuer never does.
+func (ne *NetworkConditionEvaluator) EvaluateIssuedByCA(conditions interface{}, context map[string]interface{}) bool {
+	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		cert, ok := evalCtx.ActualValue.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if verified, _ := cert[constants.ClientCertKeyVerified].(bool); !verified {
+			return false
+		}
+
+		chain, _ := cert[constants.ClientCertKeyIssuerChain].([]interface{})
+		for _, expected := range ne.convertToRangeList(evalCtx.ExpectedValue) {
+			fingerprint, isFingerprint := NormalizeCertFingerprint(expected)
+			for _, entry := range chain {
+				issuer, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if isFingerprint && issuer[constants.ClientCertKeyFingerprint] == fingerprint {
+					return true
+				}
+				if !isFingerprint && issuer[constants.ClientCertKeySubjectDN] == expected {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// NormalizeCertFingerprint converts a SHA-256 fingerprint written as "AB:CD:...", "sha256:abcd..."
+// or plain hex to lowercase hex. It returns false when value is not a SHA-256 fingerprint.
+func NormalizeCertFingerprint(value string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.TrimPrefix(normalized, "sha256:")
+	normalized = strings.ReplaceAll(normalized, ":", "")
+	if len(normalized) != 64 {
+		return "", false
+	}
+	for _, c := range normalized {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return "", false
+		}
+	}
+	return normalized, true
+}
+
 // convertToRangeList converts ranges value to string slice
 func (ne *NetworkConditionEvaluator) convertToRangeList(ranges interface{}) []string {
 	var rangeList []string
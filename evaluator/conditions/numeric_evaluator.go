@@ -3,6 +3,7 @@ package conditions
 import (
 	"abac_go_example/constants"
 	"abac_go_example/evaluator/path"
+	"abac_go_example/operators"
 )
 
 // NumericConditionEvaluator handles all numeric-based condition evaluations
@@ -25,76 +26,63 @@ func (ne *NumericConditionEvaluator) Evaluate(conditions interface{}, context ma
 
 // EvaluateEquals checks if numeric values are equal
 func (ne *NumericConditionEvaluator) EvaluateEquals(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		expectedNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum == expectedNum
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) == 0
 	})
 }
 
-// EvaluateNotEquals checks if numeric values are not equal
+// EvaluateNotEquals checks if numeric values are not equal; a missing attribute is not equal
 func (ne *NumericConditionEvaluator) EvaluateNotEquals(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		expectedNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum != expectedNum
+	return ne.EvaluateNumericConditionMap(conditions, context, true, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) != 0
 	})
 }
 
 // EvaluateLessThan checks if actual value is less than threshold
 func (ne *NumericConditionEvaluator) EvaluateLessThan(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		thresholdNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum < thresholdNum
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) < 0
 	})
 }
 
 // EvaluateLessThanEquals checks if actual value is less than or equal to threshold
 func (ne *NumericConditionEvaluator) EvaluateLessThanEquals(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		thresholdNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum <= thresholdNum
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) <= 0
 	})
 }
 
 // EvaluateGreaterThan checks if actual value is greater than threshold
 func (ne *NumericConditionEvaluator) EvaluateGreaterThan(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		thresholdNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum > thresholdNum
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) > 0
 	})
 }
 
 // EvaluateGreaterThanEquals checks if actual value is greater than or equal to threshold
 func (ne *NumericConditionEvaluator) EvaluateGreaterThanEquals(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-		thresholdNum := ne.ToFloat64(evalCtx.ExpectedValue)
-		return actualNum >= thresholdNum
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
+		return actualNum.Cmp(operators.ToNumeric(expected)) >= 0
 	})
 }
 
 // EvaluateBetween checks if value is within a numeric range
 func (ne *NumericConditionEvaluator) EvaluateBetween(conditions interface{}, context map[string]interface{}) bool {
-	return ne.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualNum := ne.ToFloat64(evalCtx.ActualValue)
-
+	return ne.EvaluateNumericConditionMap(conditions, context, false, func(actualNum operators.Numeric, expected interface{}) bool {
 		// Range can be array [min, max] or map {constants.RangeKeyMin: x, constants.RangeKeyMax: y}
-		if rangeArray, ok := evalCtx.ExpectedValue.([]interface{}); ok && len(rangeArray) == 2 {
-			min := ne.ToFloat64(rangeArray[0])
-			max := ne.ToFloat64(rangeArray[1])
-			return actualNum >= min && actualNum <= max
+		if rangeArray, ok := expected.([]interface{}); ok && len(rangeArray) == 2 {
+			return inRange(actualNum, rangeArray[0], rangeArray[1])
 		}
 
-		if rangeMap, ok := evalCtx.ExpectedValue.(map[string]interface{}); ok {
-			min := ne.ToFloat64(rangeMap[constants.RangeKeyMin])
-			max := ne.ToFloat64(rangeMap[constants.RangeKeyMax])
-			return actualNum >= min && actualNum <= max
+		if rangeMap, ok := expected.(map[string]interface{}); ok {
+			return inRange(actualNum, rangeMap[constants.RangeKeyMin], rangeMap[constants.RangeKeyMax])
 		}
 
 		return false
 	})
 }
+
+// inRange reports whether min <= actual <= max
+func inRange(actual operators.Numeric, min, max interface{}) bool {
+	return actual.Cmp(operators.ToNumeric(min)) >= 0 && actual.Cmp(operators.ToNumeric(max)) <= 0
+}
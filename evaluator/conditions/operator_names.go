@@ -0,0 +1,23 @@
+package conditions
+
+import (
+	"log"
+	"sync"
+
+	"abac_go_example/constants"
+)
+
+// warnedOperators records the nonstandard operator spellings already logged, so each is warned once
+var warnedOperators sync.Map
+
+// operatorKey resolves an operator spelling to its dispatch key, logging a deprecation warning the
+// first time a nonstandard spelling (case variant or alias) is evaluated
+func operatorKey(operator string) string {
+	name := constants.ResolveOperator(operator)
+	if name.Nonstandard {
+		if _, warned := warnedOperators.LoadOrStore(operator, true); !warned {
+			log.Printf("Warning: condition operator %q is deprecated, use %q", operator, name.Canonical)
+		}
+	}
+	return name.Key
+}
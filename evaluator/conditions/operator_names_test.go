@@ -0,0 +1,49 @@
+package conditions
+
+import "testing"
+
+func TestOperatorSpellings(t *testing.T) {
+	evaluator := NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{"department": "Engineering", "mfa": true},
+	}
+
+	for _, operator := range []string{"StringEquals", "stringequals", "STRINGEQUALS", "stringEquals"} {
+		matching := map[string]interface{}{operator: map[string]interface{}{"user.department": "Engineering"}}
+		if !evaluator.EvaluateConditions(matching, context) {
+			t.Errorf("Expected %q to match", operator)
+		}
+		other := map[string]interface{}{operator: map[string]interface{}{"user.department": "Sales"}}
+		if evaluator.EvaluateConditions(other, context) {
+			t.Errorf("Expected %q not to match another department", operator)
+		}
+	}
+
+	// Aliases evaluate as their canonical operator, also nested in logical operators
+	nested := map[string]interface{}{
+		"and": []interface{}{
+			map[string]interface{}{"Boolean": map[string]interface{}{"user.mfa": false}},
+		},
+	}
+	if evaluator.EvaluateConditions(nested, context) {
+		t.Error("Expected Boolean alias to be evaluated as Bool")
+	}
+}
+
+func TestOperatorKey(t *testing.T) {
+	tests := []struct {
+		operator string
+		key      string
+	}{
+		{"StringEquals", "stringequals"},
+		{"numericLessThan", "numericlessthan"},
+		{"Boolean", "bool"},
+		{"boolean", "bool"},
+		{"Unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := operatorKey(tt.operator); got != tt.key {
+			t.Errorf("operatorKey(%q) = %q, want %q", tt.operator, got, tt.key)
+		}
+	}
+}
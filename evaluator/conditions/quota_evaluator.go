@@ -0,0 +1,115 @@
+package conditions
+
+import (
+	"log"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/quota"
+)
+
+// QuotaConditionEvaluator evaluates quota and rate conditions against a counter provider.
+// Evaluation only reads counters; Consume increments them once a request is permitted.
+// Without a provider, or when the provider fails, quota conditions fail closed.
+type QuotaConditionEvaluator struct {
+	provider quota.CounterProvider
+	clock    clock.Clock
+}
+
+// NewQuotaEvaluator creates a quota evaluator backed by provider (nil disables quota conditions)
+func NewQuotaEvaluator(provider quota.CounterProvider) *QuotaConditionEvaluator {
+	return &QuotaConditionEvaluator{
+		provider: provider,
+		clock:    clock.NewRealClock(),
+	}
+}
+
+// SetClock configures the clock that selects rate windows and daily buckets (nil restores real time)
+func (qe *QuotaConditionEvaluator) SetClock(c clock.Clock) {
+	qe.clock = clock.OrReal(c)
+}
+
+// EvaluateRequestRateBelow checks that every counter is below its limit in the current window
+func (qe *QuotaConditionEvaluator) EvaluateRequestRateBelow(conditions interface{}, context map[string]interface{}) bool {
+	return qe.evaluateBelow(quota.KindRate, conditions, context)
+}
+
+// EvaluateDailyQuotaBelow checks that every counter is below its limit for the current UTC day
+func (qe *QuotaConditionEvaluator) EvaluateDailyQuotaBelow(conditions interface{}, context map[string]interface{}) bool {
+	return qe.evaluateBelow(quota.KindDaily, conditions, context)
+}
+
+// Consume increments the counters referenced by the top-level quota operators of a condition block.
+// Quota operators nested in And/Or/Not are checked but never consumed.
+func (qe *QuotaConditionEvaluator) Consume(conditions map[string]interface{}, context map[string]interface{}) {
+	if qe.provider == nil {
+		return
+	}
+
+	for operator, operatorConditions := range conditions {
+		kind, ok := quotaKind(operator)
+		if !ok {
+			continue
+		}
+
+		limits, err := quota.ParseLimits(kind, operatorConditions)
+		if err != nil {
+			continue
+		}
+
+		subjectID, resourceID := quotaIdentifiers(context)
+		now := qe.clock.Now()
+		for _, limit := range limits {
+			if _, err := qe.provider.Increment(limit.Key(subjectID, resourceID, now), limit.TTL(now)); err != nil {
+				log.Printf("Warning: failed to consume quota %s: %v", limit.Counter, err)
+			}
+		}
+	}
+}
+
+// evaluateBelow reads every counter of the condition block and compares it with its limit
+func (qe *QuotaConditionEvaluator) evaluateBelow(kind quota.Kind, conditions interface{}, context map[string]interface{}) bool {
+	if qe.provider == nil {
+		return false
+	}
+
+	limits, err := quota.ParseLimits(kind, conditions)
+	if err != nil {
+		log.Printf("Warning: invalid quota condition: %v", err)
+		return false
+	}
+
+	subjectID, resourceID := quotaIdentifiers(context)
+	now := qe.clock.Now()
+	for _, limit := range limits {
+		count, err := qe.provider.Get(limit.Key(subjectID, resourceID, now))
+		if err != nil {
+			log.Printf("Warning: failed to read quota %s: %v", limit.Counter, err)
+			return false
+		}
+		if count >= limit.Max {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quotaKind maps a quota operator name to its counter kind
+func quotaKind(operator string) (quota.Kind, bool) {
+	switch constants.OperatorKey(operator) {
+	case constants.OpRequestRateBelow:
+		return quota.KindRate, true
+	case constants.OpDailyQuotaBelow:
+		return quota.KindDaily, true
+	default:
+		return "", false
+	}
+}
+
+// quotaIdentifiers extracts the subject and resource identifiers counters are keyed by
+func quotaIdentifiers(context map[string]interface{}) (string, string) {
+	subjectID, _ := context[constants.ContextKeyRequestUserID].(string)
+	resourceID, _ := context[constants.ContextKeyRequestResourceID].(string)
+	return subjectID, resourceID
+}
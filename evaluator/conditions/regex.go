@@ -0,0 +1,84 @@
+package conditions
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"unicode/utf8"
+
+	"abac_go_example/constants"
+	"abac_go_example/operators"
+)
+
+// CompileRegex compiles a StringRegex pattern within the PDP's safety limits. Go's regexp is RE2:
+// matching is linear in the input and backreferences and lookaround do not compile, so user-authored
+// patterns cannot backtrack catastrophically. What remains is size: patterns longer than
+// constants.MaxRegexLength or compiling to more than constants.MaxRegexProgramSize instructions
+// (e.g. nested counted repetitions) are rejected, since matching cost grows with the program.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	if length := utf8.RuneCountInString(pattern); length > constants.MaxRegexLength {
+		return nil, fmt.Errorf("pattern too long (%d characters, max %d)", length, constants.MaxRegexLength)
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	program, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	if size := len(program.Inst); size > constants.MaxRegexProgramSize {
+		return nil, fmt.Errorf("pattern too complex (%d instructions, max %d)", size, constants.MaxRegexProgramSize)
+	}
+	return regexp.Compile(pattern)
+}
+
+// InvalidRegex is a StringRegex pattern that does not compile or exceeds the regex limits
+type InvalidRegex struct {
+	Operator string `json:"operator"`
+	Key      string `json:"key"`
+	Pattern  string `json:"pattern"`
+	Message  string `json:"message"`
+}
+
+// InvalidRegexes lists the invalid StringRegex patterns of a statement Condition map, including
+// patterns nested in And/Or/Not. Patterns are checked as evaluated, after NFC normalization.
+func InvalidRegexes(conditions map[string]interface{}) []InvalidRegex {
+	var found []InvalidRegex
+	collectInvalidRegexes(conditions, &found)
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Key < found[j].Key
+	})
+	return found
+}
+
+func collectInvalidRegexes(conditions map[string]interface{}, found *[]InvalidRegex) {
+	for operator, operands := range conditions {
+		switch constants.OperatorKey(operator) {
+		case constants.OpAnd, constants.OpOr:
+			items, _ := operands.([]interface{})
+			for _, item := range items {
+				if nested, ok := item.(map[string]interface{}); ok {
+					collectInvalidRegexes(nested, found)
+				}
+			}
+		case constants.OpNot:
+			if nested, ok := operands.(map[string]interface{}); ok {
+				collectInvalidRegexes(nested, found)
+			}
+		case constants.OpStringRegex:
+			block, _ := operands.(map[string]interface{})
+			for key, value := range block {
+				pattern, ok := value.(string)
+				if !ok {
+					*found = append(*found, InvalidRegex{Operator: operator, Key: key, Pattern: fmt.Sprint(value), Message: "pattern must be a string"})
+					continue
+				}
+				if _, err := CompileRegex(operators.NormalizeString(pattern)); err != nil {
+					*found = append(*found, InvalidRegex{Operator: operator, Key: key, Pattern: pattern, Message: err.Error()})
+				}
+			}
+		}
+	}
+}
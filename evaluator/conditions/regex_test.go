@@ -0,0 +1,66 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/path"
+)
+
+func TestCompileRegex(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr string
+	}{
+		{"simple", `^[a-z0-9._%+-]+@company\.com$`, ""},
+		{"bounded repetition", `^\w{1,64}$`, ""},
+		{"syntax error", `(`, "missing closing )"},
+		{"backreference", `(a)\1`, "invalid escape sequence"},
+		{"lookahead", `(?=admin)`, "invalid or unsupported Perl syntax"},
+		{"too long", strings.Repeat("a", constants.MaxRegexLength+1), "pattern too long"},
+		{"nested counted repetition", `(\w{1,30}){1,30}`, "pattern too complex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := CompileRegex(tt.pattern)
+			if tt.wantErr == "" {
+				if err != nil || regex == nil {
+					t.Errorf("Expected %q to compile, got %v", tt.pattern, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestInvalidRegexes(t *testing.T) {
+	conditions := map[string]interface{}{
+		"StringRegex":  map[string]interface{}{"user.email": `@company\.com$`, "user.name": "(", "user.level": 5},
+		"StringEquals": map[string]interface{}{"user.department": "("},
+		"Not": map[string]interface{}{
+			"stringregex": map[string]interface{}{"resource.path": `(\w{1,30}){1,30}`},
+		},
+	}
+
+	invalid := InvalidRegexes(conditions)
+	keys := make([]string, len(invalid))
+	for i, regex := range invalid {
+		keys[i] = regex.Key
+	}
+	if strings.Join(keys, ",") != "resource.path,user.level,user.name" {
+		t.Errorf("Unexpected invalid regexes %+v", invalid)
+	}
+	if invalid[0].Operator != "stringregex" || !strings.Contains(invalid[0].Message, "too complex") {
+		t.Errorf("Expected the nested pattern to keep its operator and reason, got %+v", invalid[0])
+	}
+
+	evaluator := NewStringEvaluator(path.NewCompositePathResolver())
+	if evaluator.EvaluateRegex(map[string]interface{}{"user.name": `(\w{1,30}){1,30}`}, map[string]interface{}{"user": map[string]interface{}{"name": "a"}}) {
+		t.Error("Expected a pattern over the limits never to match")
+	}
+}
@@ -5,19 +5,22 @@ import (
 	"strings"
 
 	"abac_go_example/evaluator/path"
+	"abac_go_example/operators"
 )
 
 // StringConditionEvaluator handles all string-based condition evaluations
 type StringConditionEvaluator struct {
 	*BaseEvaluator
-	regexCache map[string]*regexp.Regexp
+	regexCache *compiledCache[*regexp.Regexp]
+	likeCache  *compiledCache[*LikePattern]
 }
 
 // NewStringEvaluator creates a new string evaluator
 func NewStringEvaluator(pathResolver path.PathResolver) *StringConditionEvaluator {
 	return &StringConditionEvaluator{
 		BaseEvaluator: NewBaseEvaluator(pathResolver),
-		regexCache:    make(map[string]*regexp.Regexp),
+		regexCache:    newCompiledCache[*regexp.Regexp](),
+		likeCache:     newCompiledCache[*LikePattern](),
 	}
 }
 
@@ -29,82 +32,98 @@ func (se *StringConditionEvaluator) Evaluate(conditions interface{}, context map
 
 // EvaluateEquals checks if string values are equal
 func (se *StringConditionEvaluator) EvaluateEquals(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		expectedStr := se.ToString(evalCtx.ExpectedValue)
-		return actualStr == expectedStr
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, func(actual, expected string) bool {
+		return actual == expected
 	})
 }
 
-// EvaluateNotEquals checks if string values are not equal
+// EvaluateNotEquals checks if string values are not equal; a missing attribute is not equal
 func (se *StringConditionEvaluator) EvaluateNotEquals(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		expectedStr := se.ToString(evalCtx.ExpectedValue)
-		return actualStr != expectedStr
+	return se.evaluateNormalized(conditions, context, true, operators.NormalizeString, func(actual, expected string) bool {
+		return actual != expected
 	})
 }
 
-// EvaluateLike checks if string matches SQL LIKE pattern
-func (se *StringConditionEvaluator) EvaluateLike(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		patternStr := se.ToString(evalCtx.ExpectedValue)
+// EvaluateEqualsIgnoreAccents checks if string values are equal ignoring diacritics ("Ky thuat" == "Kỹ thuật")
+func (se *StringConditionEvaluator) EvaluateEqualsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool {
+	return se.evaluateNormalized(conditions, context, false, operators.FoldAccents, func(actual, expected string) bool {
+		return actual == expected
+	})
+}
 
-		// Convert SQL LIKE pattern to regex
-		regexPattern := strings.ReplaceAll(patternStr, "%", ".*")
-		regexPattern = strings.ReplaceAll(regexPattern, "_", ".")
-		regexPattern = "^" + regexPattern + "$"
+// EvaluateNotEqualsIgnoreAccents checks if string values differ ignoring diacritics; a missing attribute is not equal
+func (se *StringConditionEvaluator) EvaluateNotEqualsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool {
+	return se.evaluateNormalized(conditions, context, true, operators.FoldAccents, func(actual, expected string) bool {
+		return actual != expected
+	})
+}
 
-		matched, err := regexp.MatchString(regexPattern, actualStr)
-		return err == nil && matched
+// EvaluateLike checks if string matches SQL LIKE pattern ('%' any sequence, '_' one character, backslash escapes)
+func (se *StringConditionEvaluator) EvaluateLike(conditions interface{}, context map[string]interface{}) bool {
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, func(actualStr, source string) bool {
+		pattern, _ := se.likeCache.get(source, func(source string) (*LikePattern, error) {
+			return CompileLikePattern(source), nil
+		})
+		return pattern.Match(actualStr)
 	})
 }
 
 // EvaluateContains checks if string contains substring
 func (se *StringConditionEvaluator) EvaluateContains(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		substringStr := se.ToString(evalCtx.ExpectedValue)
-		return strings.Contains(actualStr, substringStr)
-	})
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, strings.Contains)
+}
+
+// EvaluateContainsIgnoreAccents checks if string contains substring ignoring diacritics
+func (se *StringConditionEvaluator) EvaluateContainsIgnoreAccents(conditions interface{}, context map[string]interface{}) bool {
+	return se.evaluateNormalized(conditions, context, false, operators.FoldAccents, strings.Contains)
 }
 
 // EvaluateStartsWith checks if string starts with prefix
 func (se *StringConditionEvaluator) EvaluateStartsWith(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		prefixStr := se.ToString(evalCtx.ExpectedValue)
-		return strings.HasPrefix(actualStr, prefixStr)
-	})
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, strings.HasPrefix)
+}
+
+// EvaluateStartsWithIgnoreAccents checks if string starts with prefix ignoring diacritics
+func (se *StringConditionEvaluator) EvaluateStartsWithIgnoreAccents(conditions interface{}, context map[string]interface{}) bool {
+	return se.evaluateNormalized(conditions, context, false, operators.FoldAccents, strings.HasPrefix)
 }
 
 // EvaluateEndsWith checks if string ends with suffix
 func (se *StringConditionEvaluator) EvaluateEndsWith(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		suffixStr := se.ToString(evalCtx.ExpectedValue)
-		return strings.HasSuffix(actualStr, suffixStr)
-	})
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, strings.HasSuffix)
 }
 
 // EvaluateRegex checks if string matches regex pattern
 func (se *StringConditionEvaluator) EvaluateRegex(conditions interface{}, context map[string]interface{}) bool {
-	return se.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
-		actualStr := se.ToString(evalCtx.ActualValue)
-		patternStr := se.ToString(evalCtx.ExpectedValue)
-
-		// Use cached regex if available
-		regex, exists := se.regexCache[patternStr]
-		if !exists {
-			var err error
-			regex, err = regexp.Compile(patternStr)
-			if err != nil {
-				return false
-			}
-			se.regexCache[patternStr] = regex
+	return se.evaluateNormalized(conditions, context, false, operators.NormalizeString, func(actualStr, patternStr string) bool {
+		regex, err := se.regexCache.get(patternStr, CompileRegex)
+		if err != nil {
+			return false
 		}
 
 		return regex.MatchString(actualStr)
 	})
 }
+
+// evaluateNormalized applies normalize (NFC or accent folding) to the attribute and the expected
+// value before comparing them, so composed and decomposed Unicode forms compare equal
+func (se *StringConditionEvaluator) evaluateNormalized(
+	conditions interface{},
+	context map[string]interface{},
+	matchMissing bool,
+	normalize func(string) string,
+	compare func(actual, expected string) bool,
+) bool {
+	return se.EvaluateStringConditionMap(conditions, context, matchMissing, func(actual string, expected interface{}) bool {
+		return compare(normalize(actual), normalize(se.ToString(expected)))
+	})
+}
+
+// EvaluateVersion compares dotted versions ("124.0.6367.82" >= "120") with operators.CompareVersions;
+// accept receives -1, 0 or 1. Missing attributes and non-numeric versions never match.
+func (se *StringConditionEvaluator) EvaluateVersion(conditions interface{}, context map[string]interface{}, accept func(comparison int) bool) bool {
+	return se.EvaluateStringConditionMap(conditions, context, false, func(actualStr string, expected interface{}) bool {
+		comparison, ok := operators.CompareVersions(actualStr, se.ToString(expected))
+		return ok && accept(comparison)
+	})
+}
@@ -0,0 +1,127 @@
+package conditions
+
+import (
+	"fmt"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// TagConditionEvaluator evaluates ResourceTag conditions against the resource tags in the context
+type TagConditionEvaluator struct{}
+
+// NewTagEvaluator creates a new resource tag evaluator
+func NewTagEvaluator() *TagConditionEvaluator {
+	return &TagConditionEvaluator{}
+}
+
+// EvaluateResourceTag checks that the resource carries every required tag.
+// Each key maps to a value or a list of accepted values; "*" accepts any value, including bare tags:
+//
+//	"ResourceTag": {"pii": "true", "env": ["prod", "staging"], "owner": "*"}
+func (te *TagConditionEvaluator) EvaluateResourceTag(conditions interface{}, context map[string]interface{}) bool {
+	required, err := ParseTagConditions(conditions)
+	if err != nil {
+		return false
+	}
+	return MatchTags(required, tagsFromContext(context))
+}
+
+// ParseTagConditions parses a ResourceTag block into tag key -> accepted values
+func ParseTagConditions(conditions interface{}) (map[string][]string, error) {
+	condMap, ok := conditions.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ResourceTag conditions must be an object")
+	}
+
+	required := make(map[string][]string, len(condMap))
+	for key, value := range condMap {
+		if key == "" {
+			return nil, fmt.Errorf("ResourceTag key cannot be empty")
+		}
+
+		var accepted []string
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				tagValue, err := tagValueString(item)
+				if err != nil {
+					return nil, fmt.Errorf("ResourceTag %s: %w", key, err)
+				}
+				accepted = append(accepted, tagValue)
+			}
+		case []string:
+			accepted = append(accepted, v...)
+		default:
+			tagValue, err := tagValueString(v)
+			if err != nil {
+				return nil, fmt.Errorf("ResourceTag %s: %w", key, err)
+			}
+			accepted = []string{tagValue}
+		}
+
+		if len(accepted) == 0 {
+			return nil, fmt.Errorf("ResourceTag %s must accept at least one value", key)
+		}
+		required[key] = accepted
+	}
+	return required, nil
+}
+
+// MatchTags reports whether tags satisfy every required key
+func MatchTags(required map[string][]string, tags []string) bool {
+	for key, accepted := range required {
+		if !hasAcceptedTag(tags, key, accepted) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAcceptedTag reports whether tags contain key with one of the accepted values
+func hasAcceptedTag(tags []string, key string, accepted []string) bool {
+	for _, tag := range tags {
+		tagKey, tagValue := models.SplitResourceTag(tag)
+		if tagKey != key {
+			continue
+		}
+		for _, value := range accepted {
+			if value == "*" || value == tagValue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagValueString converts a scalar condition value to its tag form (true -> "true")
+func tagValueString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, float64, int, int64:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		return "", fmt.Errorf("value must be a string, number or boolean, got %T", value)
+	}
+}
+
+// tagsFromContext reads the resource tags placed in the context by the PDP
+func tagsFromContext(context map[string]interface{}) []string {
+	switch tags := context[constants.ContextKeyResourceTags].(type) {
+	case []string:
+		return tags
+	case models.JSONStringSlice:
+		return tags
+	case []interface{}:
+		converted := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				converted = append(converted, s)
+			}
+		}
+		return converted
+	default:
+		return nil
+	}
+}
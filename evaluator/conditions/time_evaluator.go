@@ -6,6 +6,7 @@ import (
 
 	"abac_go_example/constants"
 	"abac_go_example/evaluator/path"
+	"abac_go_example/holidays"
 	"abac_go_example/operators"
 )
 
@@ -13,6 +14,7 @@ import (
 type TimeConditionEvaluator struct {
 	*BaseEvaluator
 	networkUtils *operators.NetworkUtils
+	holidays     holidays.Calendar
 }
 
 // NewTimeEvaluator creates a new time evaluator
@@ -23,6 +25,11 @@ func NewTimeEvaluator(pathResolver path.PathResolver, networkUtils *operators.Ne
 	}
 }
 
+// SetHolidayCalendar configures the holidays treated like weekends (nil: no holidays)
+func (te *TimeConditionEvaluator) SetHolidayCalendar(calendar holidays.Calendar) {
+	te.holidays = calendar
+}
+
 // Evaluate delegates to the appropriate time evaluation method
 func (te *TimeConditionEvaluator) Evaluate(conditions interface{}, context map[string]interface{}) bool {
 	// This is a generic method - specific operations should use dedicated methods
@@ -65,9 +72,14 @@ func (te *TimeConditionEvaluator) EvaluateGreaterThanEquals(conditions interface
 	})
 }
 
-// EvaluateBetween checks if time is within a time range
+// EvaluateBetween checks if time is within a time range. Clock-time ranges ("HH:MM") wrap past midnight
+// and may name a timezone: {"range": ["22:00", "06:00"], "tz": "Asia/Ho_Chi_Minh"} (see ClockWindow).
 func (te *TimeConditionEvaluator) EvaluateBetween(conditions interface{}, context map[string]interface{}) bool {
 	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		if window, err := ParseClockWindow(evalCtx.ExpectedValue); err == nil {
+			return te.inClockWindow(window, evalCtx.ActualValue, context)
+		}
+
 		actualTime := te.ParseTime(evalCtx.ActualValue)
 
 		if rangeArray, ok := evalCtx.ExpectedValue.([]interface{}); ok && len(rangeArray) == 2 {
@@ -81,6 +93,34 @@ func (te *TimeConditionEvaluator) EvaluateBetween(conditions interface{}, contex
 	})
 }
 
+// inClockWindow reports whether the attribute's time of day falls inside window.
+// Without a timezone a clock-time attribute ("HH:MM") is compared as is. With one the attribute must identify
+// an instant: timestamps are converted to the timezone and a bare clock time is replaced by the evaluation time.
+func (te *TimeConditionEvaluator) inClockWindow(window *ClockWindow, value interface{}, context map[string]interface{}) bool {
+	if str, ok := value.(string); ok {
+		if clock, isClock := parseClock(str); isClock {
+			if window.Location == nil {
+				return window.Contains(clock)
+			}
+			value = te.evaluationTime(context)
+		}
+	}
+
+	instant := te.ParseTime(value)
+	if instant.IsZero() {
+		return false
+	}
+	return window.Contains(window.ClockOf(instant))
+}
+
+// evaluationTime returns the evaluation timestamp placed in the context by the PDP, or the request time
+func (te *TimeConditionEvaluator) evaluationTime(context map[string]interface{}) interface{} {
+	if timestamp, exists := context[constants.ContextKeyEvalTime]; exists {
+		return timestamp
+	}
+	return context[constants.ContextKeyRequestTime]
+}
+
 // EvaluateDayOfWeek checks if current day matches expected day(s)
 func (te *TimeConditionEvaluator) EvaluateDayOfWeek(conditions interface{}, context map[string]interface{}) bool {
 	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
@@ -101,9 +141,15 @@ func (te *TimeConditionEvaluator) EvaluateDayOfWeek(conditions interface{}, cont
 	})
 }
 
-// EvaluateTimeOfDay checks if current time matches expected time
+// EvaluateTimeOfDay checks if current time matches expected time ("HH:MM"),
+// or falls inside a clock window ("22:00-06:00", ["22:00", "06:00"] or {"range": [...], "tz": "..."})
 func (te *TimeConditionEvaluator) EvaluateTimeOfDay(conditions interface{}, context map[string]interface{}) bool {
 	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		if expected, isString := evalCtx.ExpectedValue.(string); !isString || strings.Contains(expected, "-") {
+			window, err := ParseClockWindow(evalCtx.ExpectedValue)
+			return err == nil && te.inClockWindow(window, evalCtx.ActualValue, context)
+		}
+
 		actualTimeStr := te.ToString(evalCtx.ActualValue)
 		expectedTimeStr := te.ToString(evalCtx.ExpectedValue)
 
@@ -119,7 +165,7 @@ func (te *TimeConditionEvaluator) EvaluateTimeOfDay(conditions interface{}, cont
 	})
 }
 
-// EvaluateIsBusinessHours checks if current time is within business hours
+// EvaluateIsBusinessHours checks if current time is within business hours; holidays are treated like weekends
 func (te *TimeConditionEvaluator) EvaluateIsBusinessHours(conditions interface{}, context map[string]interface{}) bool {
 	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
 		expectedBool := te.ToBool(evalCtx.ExpectedValue)
@@ -129,7 +175,7 @@ func (te *TimeConditionEvaluator) EvaluateIsBusinessHours(conditions interface{}
 		if timeValue, ok := evalCtx.ActualValue.(time.Time); ok {
 			hour := timeValue.Hour()
 			weekday := int(timeValue.Weekday())
-			isBusinessHours = te.networkUtils.IsBusinessHours(hour, weekday)
+			isBusinessHours = te.networkUtils.IsBusinessHours(hour, weekday) && !holidays.IsHoliday(te.holidays, timeValue)
 		} else if boolValue, ok := evalCtx.ActualValue.(bool); ok {
 			// If the value is already a boolean, use it directly
 			isBusinessHours = boolValue
@@ -143,9 +189,102 @@ func (te *TimeConditionEvaluator) EvaluateIsBusinessHours(conditions interface{}
 
 			// Convert day string to weekday number for consistency
 			weekday := constants.GetDayOfWeekNumber(strings.ToLower(dayStr))
-			isBusinessHours = te.networkUtils.IsBusinessHours(hour, weekday)
+			isHoliday, _ := te.GetValueFromContext(constants.ContextKeyEnvironmentIsHoliday, context).(bool)
+			isBusinessHours = te.networkUtils.IsBusinessHours(hour, weekday) && !isHoliday
 		}
 
 		return isBusinessHours == expectedBool
 	})
 }
+
+// EvaluateIsHoliday checks whether the evaluation date is a holiday of the configured calendar:
+//
+//	"IsHoliday": {"environment.is_holiday": false}
+//
+// A boolean attribute (set by the PDP) is used as is; a timestamp attribute is looked up in the calendar.
+// Any other value is not a holiday.
+func (te *TimeConditionEvaluator) EvaluateIsHoliday(conditions interface{}, context map[string]interface{}) bool {
+	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		expectedBool := te.ToBool(evalCtx.ExpectedValue)
+
+		var isHoliday bool
+		switch actual := evalCtx.ActualValue.(type) {
+		case bool:
+			isHoliday = actual
+		case nil:
+			isHoliday = false
+		default:
+			if date := te.ParseTime(actual); !date.IsZero() {
+				isHoliday = holidays.IsHoliday(te.holidays, date)
+			}
+		}
+
+		return isHoliday == expectedBool
+	})
+}
+
+// EvaluateAuthAgeLessThan checks that the subject authenticated less than the given duration before the request.
+// The attribute is either an auth timestamp (session.auth_time) or an age in seconds (session.auth_age_seconds);
+// the limit is a duration string ("15m") or a number of seconds. Missing or invalid values never match.
+func (te *TimeConditionEvaluator) EvaluateAuthAgeLessThan(conditions interface{}, context map[string]interface{}) bool {
+	return te.EvaluateWithConditionMap(conditions, context, func(evalCtx EvaluationContext) bool {
+		maxAge, ok := ParseAuthAgeLimit(evalCtx.ExpectedValue)
+		if !ok {
+			return false
+		}
+
+		age, ok := te.authAge(evalCtx.ActualValue, context)
+		if !ok {
+			return false
+		}
+
+		return age >= 0 && age < maxAge
+	})
+}
+
+// ParseAuthAgeLimit parses an AuthAgeLessThan limit: a duration string or a number of seconds
+func ParseAuthAgeLimit(value interface{}) (time.Duration, bool) {
+	var limit time.Duration
+	switch v := value.(type) {
+	case string:
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, false
+		}
+		limit = duration
+	case int:
+		limit = time.Duration(v) * time.Second
+	case int64:
+		limit = time.Duration(v) * time.Second
+	case float64:
+		limit = time.Duration(v * float64(time.Second))
+	default:
+		return 0, false
+	}
+	return limit, limit > 0
+}
+
+// authAge converts an auth timestamp or an age in seconds to the authentication age at request time
+func (te *TimeConditionEvaluator) authAge(value interface{}, context map[string]interface{}) (time.Duration, bool) {
+	switch v := value.(type) {
+	case nil:
+		return 0, false
+	case int:
+		return time.Duration(v) * time.Second, true
+	case int64:
+		return time.Duration(v) * time.Second, true
+	case float64:
+		return time.Duration(v * float64(time.Second)), true
+	}
+
+	authTime := te.ParseTime(value)
+	if authTime.IsZero() {
+		return 0, false
+	}
+
+	requestTime := te.ParseTime(context[constants.ContextKeyRequestTime])
+	if requestTime.IsZero() {
+		requestTime = time.Now()
+	}
+	return requestTime.Sub(authTime), true
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ImpliedActions tests that Allow statements cover implied actions while Deny statements stay literal
+func TestPDP_ImpliedActions(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	for _, action := range []string{"document-service:file:read", "document-service:file:write", "document-service:file:delete"} {
+		mockStorage.CreateAction(&models.Action{ID: action, ActionName: action})
+	}
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-writers",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "AllowWrite", Effect: "Allow", Action: models.JSONActionResource{Single: "document-service:file:write"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+				{Sid: "AllowDelete", Effect: "Allow", Action: models.JSONActionResource{Single: "document-service:file:delete"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+				{Sid: "DenyAdmin", Effect: "Deny", Action: models.JSONActionResource{Single: "document-service:file:admin"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+			},
+		},
+	})
+
+	catalog := matchers.NewActionCatalog()
+	catalog.AddImplication("document-service", "write", "read")
+	catalog.AddImplication("document-service", "admin", "*")
+
+	evaluate := func(pdp PolicyDecisionPointInterface, action string) string {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "implied-" + action,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     action,
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+
+	literal := NewPolicyDecisionPoint(mockStorage)
+	if result := evaluate(literal, "document-service:file:read"); result != constants.ResultDeny {
+		t.Errorf("Expected read to be denied without a catalog, got %s", result)
+	}
+
+	config := DefaultPDPConfig()
+	config.ActionCatalog = catalog
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	if result := evaluate(pdp, "document-service:file:read"); result != constants.ResultPermit {
+		t.Errorf("Expected write to imply read, got %s", result)
+	}
+	if result := evaluate(pdp, "document-service:file:write"); result != constants.ResultPermit {
+		t.Errorf("Expected write to be permitted, got %s", result)
+	}
+	// Deny on "admin" is not widened to the operations admin implies
+	if result := evaluate(pdp, "document-service:file:delete"); result != constants.ResultPermit {
+		t.Errorf("Expected delete to be permitted despite the admin deny, got %s", result)
+	}
+}
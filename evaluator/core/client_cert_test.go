@@ -0,0 +1,68 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ClientCertLargeSubject tests that certificate fields resolve in both notations from the structured
+// environment:client_cert attribute, without flat copies pushing a large subject over MaxConditionKeys
+func TestPDP_ClientCertLargeSubject(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "payment:create", ActionName: "payment:create"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:payments:batch-1", ResourceID: "api:payments:batch-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-mtls",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "PaymentServiceOnly",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "payment:create"},
+					Resource: models.JSONActionResource{Single: "api:payments:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"environment.client_cert.common_name": "payment-service",
+							"environment:client_cert.issuer_dn":   "CN=Services CA,O=Acme",
+							"user.custom_attr_43":                 "value-43",
+						},
+					},
+				},
+			},
+		},
+	})
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	attributes := make(map[string]interface{})
+	for i := 0; i < 44; i++ {
+		attributes[fmt.Sprintf("attr_%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("svc-payments", attributes),
+		ResourceID: "api:payments:batch-1",
+		Action:     "payment:create",
+		Environment: &models.EnvironmentInfo{
+			ClientIP: "10.0.0.7",
+			ClientCert: &models.ClientCertInfo{
+				SubjectDN:  "CN=payment-service,O=Acme",
+				CommonName: "payment-service",
+				IssuerDN:   "CN=Services CA,O=Acme",
+				DNSNames:   []string{"payments.acme.internal"},
+				Verified:   true,
+			},
+		},
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit for the mTLS caller, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
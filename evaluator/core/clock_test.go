@@ -0,0 +1,227 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/holidays"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ClockControlsEvaluationTime tests that weekend policies follow the injected clock
+func TestPDP_ClockControlsEvaluationTime(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-weekday",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "AllowRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+				},
+				{
+					Sid:      "DenyWeekend",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"Bool": map[string]interface{}{
+							"environment.is_weekend": true,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// Saturday 10:00 UTC
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "clock-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+		Context:    map[string]interface{}{},
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultDeny {
+		t.Errorf("Expected deny on Saturday, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// Monday 10:00 UTC
+	mockClock.Advance(48 * time.Hour)
+	decision, err = pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit on Monday, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// An explicit request timestamp still takes precedence over the clock
+	saturday := time.Date(2024, time.June, 8, 10, 0, 0, 0, time.UTC)
+	request.Timestamp = &saturday
+	decision, err = pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultDeny {
+		t.Errorf("Expected deny for Saturday request timestamp, got %s", decision.Result)
+	}
+}
+
+// TestPDP_OvernightTimeWindow tests a TimeBetween window that wraps past midnight in a named timezone
+func TestPDP_OvernightTimeWindow(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "batch:run", ActionName: "batch:run"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:jobs:nightly", ResourceID: "api:jobs:nightly"})
+	policy := &models.Policy{
+		ID:         "pol-night-batch",
+		PolicyName: "Night batch window",
+		Version:    "2012-10-17",
+		Enabled:    true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      "AllowOvernight",
+				Effect:   "Allow",
+				Action:   models.JSONActionResource{Single: "batch:run"},
+				Resource: models.JSONActionResource{Single: "api:jobs:*"},
+				Condition: map[string]interface{}{
+					"TimeBetween": map[string]interface{}{
+						"environment.time_of_day": map[string]interface{}{
+							"range": []interface{}{"22:00", "06:00"},
+							"tz":    "Asia/Ho_Chi_Minh",
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := NewPolicyValidator().ValidatePolicy(policy); err != nil {
+		t.Fatalf("Expected overnight window to validate, got %v", err)
+	}
+	mockStorage.SetPolicies([]*models.Policy{policy})
+
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "overnight-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:jobs:nightly",
+		Action:     "batch:run",
+		Context:    map[string]interface{}{},
+	}
+
+	tests := []struct {
+		utc      string
+		expected string
+	}{
+		{"10:00", constants.ResultDeny},   // 17:00 in Ho Chi Minh City
+		{"16:30", constants.ResultPermit}, // 23:30, before midnight
+		{"22:00", constants.ResultPermit}, // 05:00 the next day
+		{"23:30", constants.ResultDeny},   // 06:30 the next day
+	}
+	for _, test := range tests {
+		clockTime, _ := time.Parse("15:04", test.utc)
+		mockClock.Set(time.Date(2024, time.June, 3, clockTime.Hour(), clockTime.Minute(), 0, 0, time.UTC))
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != test.expected {
+			t.Errorf("At %s UTC expected %s, got %s (%s)", test.utc, test.expected, decision.Result, decision.Reason)
+		}
+	}
+
+	// Malformed windows are rejected at validation time
+	policy.Statement[0].Condition = map[string]interface{}{
+		"TimeBetween": map[string]interface{}{
+			"environment.time_of_day": map[string]interface{}{"range": []interface{}{"22:00", "25:00"}, "tz": "Mars/Olympus"},
+		},
+	}
+	if err := NewPolicyValidator().ValidatePolicy(policy); err == nil {
+		t.Error("Expected invalid window to be rejected")
+	}
+}
+
+// TestPDP_HolidayCalendar tests that holidays are treated like weekends and matched by IsHoliday
+func TestPDP_HolidayCalendar(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-business-hours",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "AllowBusinessHours",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"IsBusinessHours": map[string]interface{}{"environment.is_business_hours": true},
+						"IsHoliday":       map[string]interface{}{"environment.is_holiday": false},
+					},
+				},
+			},
+		},
+	})
+
+	calendar, err := holidays.NewStaticCalendar([]holidays.Holiday{{Date: "09-02", Name: "National Day"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tuesday 10:00 UTC, a public holiday
+	mockClock := clock.NewMockClock(time.Date(2025, time.September, 2, 10, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	config.Holidays = calendar
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "holiday-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+		Context:    map[string]interface{}{},
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultDeny {
+		t.Errorf("Expected deny on a holiday, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// Wednesday 10:00 UTC is an ordinary business day
+	mockClock.Advance(24 * time.Hour)
+	decision, err = pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit on a business day, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
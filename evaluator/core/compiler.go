@@ -0,0 +1,164 @@
+package core
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/models"
+)
+
+// CompiledStatement holds precomputed evaluation data for a policy statement
+type CompiledStatement struct {
+	Conditions    []conditions.CompiledCondition // Cost-ordered conditions
+	LimitExceeded bool                           // Statement violates PolicyLimits and must not grant access
+	RequiredTags  map[string][]string            // Top-level ResourceTag condition, used to pre-filter policies
+}
+
+// compiledPolicy holds precomputed evaluation data for a policy
+type compiledPolicy struct {
+	source     *models.Policy
+	updatedAt  time.Time
+	revision   int64
+	version    string
+	statements []CompiledStatement
+}
+
+// PolicyCompiler caches compiled statements per policy.
+// Entries are reused while the policy object, its UpdatedAt and Version are unchanged, and across
+// reloads of the policy from storage while its Revision and (non-zero) UpdatedAt are unchanged.
+type PolicyCompiler struct {
+	mu       sync.RWMutex
+	compiled map[string]*compiledPolicy
+	limits   *PolicyLimits
+
+	compilations  atomic.Int64
+	cacheHits     atomic.Int64
+	compileTimeNs atomic.Int64
+	lastCompileNs atomic.Int64
+}
+
+// CompilerStats reports policy compilation activity
+type CompilerStats struct {
+	Compilations      int64   `json:"compilations"` // Policies compiled (cache misses)
+	CacheHits         int64   `json:"cache_hits"`
+	CachedPolicies    int     `json:"cached_policies"`
+	AvgCompileTimeUs  float64 `json:"avg_compile_time_us"`
+	LastCompileTimeUs float64 `json:"last_compile_time_us"`
+}
+
+// NewPolicyCompiler creates an empty policy compiler enforcing the given limits (nil disables limits)
+func NewPolicyCompiler(limits *PolicyLimits) *PolicyCompiler {
+	return &PolicyCompiler{
+		compiled: make(map[string]*compiledPolicy),
+		limits:   limits,
+	}
+}
+
+// Compile returns the compiled form of each statement in policy
+func (pc *PolicyCompiler) Compile(policy *models.Policy) []CompiledStatement {
+	pc.mu.RLock()
+	entry, exists := pc.compiled[policy.ID]
+	pc.mu.RUnlock()
+
+	if exists && entry.isCurrent(policy) {
+		pc.cacheHits.Add(1)
+		return entry.statements
+	}
+
+	start := time.Now()
+	entry = &compiledPolicy{
+		source:     policy,
+		updatedAt:  policy.UpdatedAt,
+		revision:   policy.Revision,
+		version:    policy.Version,
+		statements: make([]CompiledStatement, len(policy.Statement)),
+	}
+
+	// A policy with too many statements is rejected as a whole
+	policyExceeded := pc.limits != nil && pc.limits.MaxStatementsPerPolicy > 0 &&
+		len(policy.Statement) > pc.limits.MaxStatementsPerPolicy
+	if policyExceeded {
+		log.Printf("Warning: policy %s exceeds statement limit (%d > %d)", policy.ID, len(policy.Statement), pc.limits.MaxStatementsPerPolicy)
+	}
+
+	for i, statement := range policy.Statement {
+		exceeded := policyExceeded
+		if violations := pc.limits.CheckStatement(statement, "statement"); len(violations) > 0 {
+			log.Printf("Warning: policy %s statement %d exceeds limits: %v", policy.ID, i, violations)
+			exceeded = true
+		}
+
+		entry.statements[i] = CompiledStatement{LimitExceeded: exceeded}
+		if !exceeded {
+			entry.statements[i].Conditions = conditions.CompileConditions(statement.Condition)
+			entry.statements[i].RequiredTags = requiredTags(statement.Condition)
+		}
+	}
+
+	pc.mu.Lock()
+	pc.compiled[policy.ID] = entry
+	pc.mu.Unlock()
+
+	elapsed := time.Since(start).Nanoseconds()
+	pc.compilations.Add(1)
+	pc.compileTimeNs.Add(elapsed)
+	pc.lastCompileNs.Store(elapsed)
+
+	return entry.statements
+}
+
+// Stats returns a snapshot of the compilation counters
+func (pc *PolicyCompiler) Stats() *CompilerStats {
+	pc.mu.RLock()
+	cached := len(pc.compiled)
+	pc.mu.RUnlock()
+
+	stats := &CompilerStats{
+		Compilations:      pc.compilations.Load(),
+		CacheHits:         pc.cacheHits.Load(),
+		CachedPolicies:    cached,
+		LastCompileTimeUs: float64(pc.lastCompileNs.Load()) / float64(time.Microsecond),
+	}
+	if stats.Compilations > 0 {
+		stats.AvgCompileTimeUs = float64(pc.compileTimeNs.Load()) / float64(stats.Compilations) / float64(time.Microsecond)
+	}
+	return stats
+}
+
+// requiredTags extracts a statement's top-level ResourceTag condition; nil when there is none
+// or it is malformed (the condition itself then fails during evaluation)
+func requiredTags(statementConditions map[string]interface{}) map[string][]string {
+	for operator, operatorConditions := range statementConditions {
+		if constants.OperatorKey(operator) != constants.OpResourceTag {
+			continue
+		}
+		if tags, err := conditions.ParseTagConditions(operatorConditions); err == nil {
+			return tags
+		}
+	}
+	return nil
+}
+
+// Invalidate drops the compiled form of a policy
+func (pc *PolicyCompiler) Invalidate(policyID string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	delete(pc.compiled, policyID)
+}
+
+// isCurrent reports whether the cached entry still matches policy. A different object (e.g. the
+// same policy reloaded from storage) matches only when it carries the same revision and a
+// non-zero UpdatedAt, since storage bumps both on every write.
+func (cp *compiledPolicy) isCurrent(policy *models.Policy) bool {
+	if cp.source != policy && (policy.UpdatedAt.IsZero() || cp.revision != policy.Revision) {
+		return false
+	}
+	return cp.updatedAt.Equal(policy.UpdatedAt) &&
+		cp.version == policy.Version &&
+		len(cp.statements) == len(policy.Statement)
+}
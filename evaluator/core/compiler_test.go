@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestPolicyCompilerCachesUntilPolicyChanges(t *testing.T) {
+	compiler := NewPolicyCompiler(DefaultPolicyLimits())
+	policy := &models.Policy{
+		ID:      "pol-001",
+		Version: "1",
+		Statement: []models.PolicyStatement{
+			{
+				Condition: map[string]interface{}{
+					"StringRegex":  map[string]interface{}{"user.email": ".*"},
+					"StringEquals": map[string]interface{}{"user.department": "Engineering"},
+				},
+			},
+		},
+	}
+
+	first := compiler.Compile(policy)
+	if len(first) != 1 || len(first[0].Conditions) != 2 || first[0].Conditions[0].Operator != "StringEquals" {
+		t.Fatalf("Unexpected compiled statements: %+v", first)
+	}
+
+	if second := compiler.Compile(policy); &second[0] != &first[0] {
+		t.Error("Expected cached compilation to be reused for unchanged policy")
+	}
+
+	policy.Version = "2"
+	if third := compiler.Compile(policy); &third[0] == &first[0] {
+		t.Error("Expected recompilation after version change")
+	}
+
+	replaced := *policy
+	replaced.Statement = []models.PolicyStatement{{}, {}}
+	if got := compiler.Compile(&replaced); len(got) != 2 {
+		t.Errorf("Expected recompilation for replaced policy, got %d statements", len(got))
+	}
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"time"
+
+	"abac_go_example/attributes"
+	"abac_go_example/bundle"
+	"abac_go_example/clock"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/holidays"
+	"abac_go_example/quota"
+	"abac_go_example/redaction"
+	"abac_go_example/sink"
+	"abac_go_example/storage"
+)
+
+// PDPConfig holds configuration for PolicyDecisionPoint
+type PDPConfig struct {
+	// Redactor masks sensitive attribute values in decision reasons and Explain output.
+	// Conditions are always evaluated against raw values. Nil disables redaction.
+	Redactor *redaction.Redactor `json:"-"`
+
+	// EnableStats records per-policy and per-statement hit counters and condition timings
+	EnableStats bool `json:"enable_stats"`
+
+	// ConditionCoverage records, per policy, which condition operators and attribute keys evaluations
+	// exercise and how often their attributes are missing
+	ConditionCoverage bool `json:"condition_coverage"`
+
+	// MissingAttributes counts condition attribute paths that resolve to nothing, labeled by policy,
+	// statement and path, and logs rate-limited warnings. Nil disables it.
+	MissingAttributes *MissingAttributeConfig `json:"missing_attributes,omitempty"`
+
+	// Quarantine skips policies whose evaluations consistently exceed a time threshold, alerting when
+	// one is quarantined. Skipped policies contribute Indeterminate. Nil disables it.
+	Quarantine *QuarantineConfig `json:"quarantine,omitempty"`
+
+	// Limits bounds policy size and complexity at evaluation time. Nil disables limits.
+	Limits *PolicyLimits `json:"limits,omitempty"`
+
+	// DecisionSink receives every decision made by Evaluate (e.g. live dashboards). Nil disables publishing.
+	DecisionSink sink.DecisionSink `json:"-"`
+
+	// CounterProvider backs RequestRateBelow/DailyQuotaBelow conditions; counters are incremented
+	// when Evaluate permits a request. Nil makes quota conditions fail closed.
+	CounterProvider quota.CounterProvider `json:"-"`
+
+	// RiskProvider contributes environment.risk_score and related attributes during enrichment. Nil disables it.
+	RiskProvider attributes.RiskProvider `json:"-"`
+
+	// DevicePosture contributes device.* attributes (managed, disk_encrypted, patch_age_days, ...)
+	// during enrichment. Nil disables them.
+	DevicePosture attributes.DevicePostureProvider `json:"-"`
+
+	// Holidays are treated like weekends: environment.is_business_hours is false on them and
+	// environment.is_holiday / IsHoliday conditions report them. Nil means no holidays.
+	Holidays holidays.Calendar `json:"-"`
+
+	// Clock supplies evaluation time when requests carry no timestamp. Nil uses the system clock.
+	Clock clock.Clock `json:"-"`
+
+	// DenyCache replays recent denies for identical subject/resource/action requests
+	// for a short TTL, absorbing client retry storms. Nil disables it.
+	DenyCache *DenyCacheConfig `json:"deny_cache,omitempty"`
+
+	// AttributeCache reuses subject group, resource and action lookups of context enrichment
+	// for per-entity TTLs. Nil disables it.
+	AttributeCache *attributes.AttributeCacheConfig `json:"attribute_cache,omitempty"`
+
+	// PreparedSubjects keeps subject contexts prepared at session start (see PrepareSubjectContext)
+	// and reuses them for evaluations of that session. Nil disables prepared contexts.
+	PreparedSubjects *attributes.PreparedSubjectConfig `json:"prepared_subjects,omitempty"`
+
+	// ActionCatalog declares implied actions (e.g. write implies read) honored by Allow statements.
+	// Nil matches actions literally.
+	ActionCatalog *matchers.ActionCatalog `json:"-"`
+
+	// Matchers holds custom action/resource matchers selected by pattern prefix (e.g. "arn:") for
+	// naming schemes beyond colon/glob patterns. Nil uses the built-in matchers only.
+	Matchers *matchers.MatcherRegistry `json:"-"`
+
+	// BundleVerifier enables signed policy bundles: only stored policies matching the last bundle
+	// passed to LoadBundle are evaluated, and none until one is loaded. Nil trusts storage as is.
+	BundleVerifier *bundle.Verifier `json:"-"`
+
+	// Namespace is the default evaluation namespace of requests without one: only global policies
+	// and policies of the namespace are evaluated. Empty evaluates global policies only.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Parallel evaluates large candidate policy sets with a worker pool, cancelling policies after
+	// the first deny. Decisions are identical to sequential evaluation. Nil evaluates sequentially.
+	Parallel *ParallelConfig `json:"parallel,omitempty"`
+
+	// Hooks are user-registered functions called before/after enrichment and before/after the decision,
+	// for custom enrichment, metric tagging or decision overrides. Nil registers none.
+	Hooks *Hooks `json:"-"`
+
+	// DecisionTTL sets the CacheTTL hint of decisions: Max for decisions without time-sensitive conditions,
+	// less when a condition or policy validity window changes sooner. Nil returns CacheTTL 0 (do not cache).
+	DecisionTTL *DecisionTTLConfig `json:"decision_ttl,omitempty"`
+
+	// DefaultDecisions permit requests no policy matches for selected actions (e.g. read on public
+	// resources) instead of the implicit deny; the rule is named in the decision reason. Nil denies them.
+	DefaultDecisions []DefaultDecisionRule `json:"default_decisions,omitempty"`
+
+	// RevocationRefresh is how long the revocation list is cached before it is reloaded from storage;
+	// changes published as storage events invalidate it immediately. 0 uses DefaultRevocationRefresh.
+	RevocationRefresh time.Duration `json:"revocation_refresh,omitempty"`
+
+	// CompileMode is CompileLazy (compile policies on first use, the default when empty) or
+	// CompileEager (the service calls WarmUp at startup). Compile counters are kept in both modes.
+	CompileMode string `json:"compile_mode,omitempty"`
+}
+
+// DefaultPDPConfig returns default configuration for PolicyDecisionPoint
+func DefaultPDPConfig() *PDPConfig {
+	return &PDPConfig{
+		Redactor:         redaction.DefaultRedactor(),
+		EnableStats:      true,
+		Limits:           DefaultPolicyLimits(),
+		CounterProvider:  quota.NewMemoryCounterProvider(),
+		DecisionTTL:      DefaultDecisionTTLConfig(),
+		PreparedSubjects: attributes.DefaultPreparedSubjectConfig(),
+	}
+}
+
+// NewPolicyDecisionPointWithConfig creates a new PDP instance with the given configuration
+func NewPolicyDecisionPointWithConfig(storage storage.Storage, config *PDPConfig) PolicyDecisionPointInterface {
+	if config == nil {
+		config = DefaultPDPConfig()
+	}
+
+	pdp := newPolicyDecisionPoint(storage)
+	pdp.config = config
+	pdp.compiler = NewPolicyCompiler(config.Limits)
+	pdp.enhancedConditionEvaluator.SetCounterProvider(config.CounterProvider)
+	pdp.attributeResolver.SetRiskProvider(config.RiskProvider)
+	pdp.attributeResolver.SetDevicePostureProvider(config.DevicePosture)
+	pdp.attributeResolver.SetClock(config.Clock)
+	pdp.actionMatcher.SetCatalog(config.ActionCatalog)
+	pdp.actionMatcher.SetRegistry(config.Matchers)
+	pdp.resourceMatcher.SetRegistry(config.Matchers)
+	pdp.enhancedConditionEvaluator.SetClock(config.Clock)
+	pdp.enhancedConditionEvaluator.SetHolidayCalendar(config.Holidays)
+	pdp.attributeResolver.SetHolidayCalendar(config.Holidays)
+	if config.EnableStats {
+		pdp.stats = NewStatsCollector()
+	}
+	if config.ConditionCoverage {
+		pdp.coverage = NewCoverageCollector()
+	}
+	if config.MissingAttributes != nil {
+		pdp.missingAttributes = NewMissingAttributeMonitor(config.MissingAttributes, config.Clock)
+	}
+	if config.Quarantine != nil {
+		pdp.quarantine = NewPolicyQuarantine(config.Quarantine, config.Clock)
+	}
+	if config.DenyCache != nil {
+		pdp.denyCache = NewDenyCache(config.DenyCache, config.Clock)
+	}
+	if config.AttributeCache != nil {
+		pdp.attributeResolver.SetAttributeCache(attributes.NewAttributeCache(config.AttributeCache, config.Clock))
+	}
+	if config.PreparedSubjects != nil {
+		pdp.attributeResolver.SetPreparedSubjects(attributes.NewPreparedSubjects(config.PreparedSubjects, config.Clock))
+	}
+	if config.BundleVerifier != nil {
+		pdp.integrity = newPolicyIntegrity(config.BundleVerifier)
+	}
+	return pdp
+}
@@ -0,0 +1,41 @@
+package core
+
+import "abac_go_example/models"
+
+// decisionConstraints merges the constraints of the Allow statements behind a permit. Each
+// statement grants access on its own, so the merge is the most permissive combination: any
+// unconstrained statement makes the permit unconstrained, fields are the union of the allowed
+// field patterns (unless one statement allows every field), and the permit is read-only only
+// when every statement is.
+func decisionConstraints(statements []models.PolicyStatement) *models.Constraints {
+	if len(statements) == 0 {
+		return nil
+	}
+	merged := &models.Constraints{ReadOnly: true}
+	allFields := false
+	seen := make(map[string]bool)
+	for _, statement := range statements {
+		constraints := statement.Constraints
+		if constraints.IsEmpty() {
+			return nil
+		}
+		merged.ReadOnly = merged.ReadOnly && constraints.ReadOnly
+		if len(constraints.Fields) == 0 {
+			allFields = true
+			continue
+		}
+		for _, field := range constraints.Fields {
+			if !seen[field] {
+				seen[field] = true
+				merged.Fields = append(merged.Fields, field)
+			}
+		}
+	}
+	if allFields {
+		merged.Fields = nil
+	}
+	if merged.IsEmpty() {
+		return nil
+	}
+	return merged
+}
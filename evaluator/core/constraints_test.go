@@ -0,0 +1,100 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestDecisionConstraints(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "report:read", ActionName: "report:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:reports:q1", ResourceID: "api:reports:q1"})
+	readStatement := func(sid, department string, constraints *models.Constraints) models.PolicyStatement {
+		return models.PolicyStatement{
+			Sid:         sid,
+			Effect:      "Allow",
+			Action:      models.JSONActionResource{Single: "report:read"},
+			Resource:    models.JSONActionResource{Single: "api:reports:*"},
+			Condition:   map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": department}},
+			Constraints: constraints,
+		}
+	}
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-reports",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			readStatement("SalesSummary", "Sales", &models.Constraints{Fields: []string{"region", "total"}, ReadOnly: true}),
+			readStatement("MarketingSummary", "Marketing", &models.Constraints{Fields: []string{"region", "campaign"}, ReadOnly: true}),
+			readStatement("MarketingAll", "Marketing", nil),
+			readStatement("AnalyticsSummary", "Analytics", &models.Constraints{Fields: []string{"total"}, ReadOnly: true}),
+			readStatement("AnalyticsReadOnly", "Analytics", &models.Constraints{ReadOnly: true}),
+			readStatement("FinanceSummary", "Finance", &models.Constraints{Fields: []string{"total"}}),
+			readStatement("FinanceRegions", "Finance", &models.Constraints{Fields: []string{"region", "total"}, ReadOnly: true}),
+		},
+	}})
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	evaluate := func(department string) *models.Decision {
+		t.Helper()
+		request := hooksTestRequest("user-1")
+		request.Subject = models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{"department": department})
+		request.ResourceID = "api:reports:q1"
+		request.Action = "report:read"
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		if decision.Result != constants.ResultPermit {
+			t.Fatalf("Expected %s to be permitted, got %s", department, decision.Result)
+		}
+		return decision
+	}
+
+	tests := []struct {
+		department string
+		expected   *models.Constraints
+	}{
+		{"Sales", &models.Constraints{Fields: []string{"region", "total"}, ReadOnly: true}},
+		// An unconstrained statement grants everything
+		{"Marketing", nil},
+		// One statement allows every field, both are read-only
+		{"Analytics", &models.Constraints{ReadOnly: true}},
+		// Fields are combined, read-only only when every statement is
+		{"Finance", &models.Constraints{Fields: []string{"total", "region"}}},
+	}
+	for _, tt := range tests {
+		if decision := evaluate(tt.department); !reflect.DeepEqual(decision.Constraints, tt.expected) {
+			t.Errorf("%s: expected constraints %+v, got %+v", tt.department, tt.expected, decision.Constraints)
+		}
+	}
+}
+
+func TestValidatePolicyConstraints(t *testing.T) {
+	policy := &models.Policy{
+		ID:         "pol-constraints",
+		PolicyName: "Constraints",
+		Version:    "1",
+		Statement: []models.PolicyStatement{{
+			Sid:         "Read",
+			Effect:      "Allow",
+			Action:      models.JSONActionResource{Single: "report:read"},
+			Resource:    models.JSONActionResource{Single: "api:reports:*"},
+			Constraints: &models.Constraints{Fields: []string{"region"}, ReadOnly: true},
+		}},
+	}
+	if err := NewPolicyValidator().ValidatePolicy(policy); err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	policy.Statement[0].Effect = "Deny"
+	policy.Statement[0].Constraints.Fields = []string{""}
+	err := NewPolicyValidator().ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "constraints require a resource-level Allow statement") || !strings.Contains(err.Error(), "value cannot be empty") {
+		t.Errorf("Expected constrained denies and empty fields to be rejected, got %v", err)
+	}
+}
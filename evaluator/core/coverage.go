@@ -0,0 +1,256 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/path"
+	"abac_go_example/models"
+)
+
+// PolicyCoverage reports which conditions of a policy live evaluations exercised
+type PolicyCoverage struct {
+	PolicyID    string              `json:"policy_id"`
+	Revision    int64               `json:"revision"`    // Policy revision the counters belong to
+	Evaluations int64               `json:"evaluations"` // Requests in which the policy was evaluated
+	Conditions  []ConditionCoverage `json:"conditions"`
+}
+
+// ConditionCoverage counts the evaluations of one operator/key pair of a statement condition.
+// Unused conditions were never evaluated (their statement never matched action and resource);
+// AlwaysMissing conditions were evaluated but their attribute was never in the context.
+type ConditionCoverage struct {
+	Statement     string `json:"statement"` // Statement Sid, or "#<index>" when Sid is empty
+	Operator      string `json:"operator"`
+	Key           string `json:"key"`
+	Evaluations   int64  `json:"evaluations"`
+	Missing       int64  `json:"missing"` // Evaluations without the attribute in the context
+	Unused        bool   `json:"unused"`
+	AlwaysMissing bool   `json:"always_missing"`
+}
+
+// conditionLeaf is an operator/key pair of a condition block
+type conditionLeaf struct {
+	operator string
+	key      string // Empty when the operator has no attribute block
+}
+
+// conditionCounters holds raw counters for a condition leaf of a statement
+type conditionCounters struct {
+	statement   string
+	leaf        conditionLeaf
+	evaluations int64
+	missing     int64
+}
+
+// policyCoverage holds raw counters for the conditions of a policy revision
+type policyCoverage struct {
+	revision    int64
+	evaluations int64
+	conditions  []*conditionCounters
+	index       map[string]*conditionCounters
+}
+
+// CoverageCollector records which condition operators and attribute keys are exercised by evaluations.
+// Counters of a policy restart when its revision changes. It is safe for concurrent use.
+type CoverageCollector struct {
+	mu       sync.Mutex
+	policies map[string]*policyCoverage
+	resolver path.PathResolver
+}
+
+// NewCoverageCollector creates an empty coverage collector
+func NewCoverageCollector() *CoverageCollector {
+	return &CoverageCollector{
+		policies: make(map[string]*policyCoverage),
+		resolver: path.NewCompositePathResolver(),
+	}
+}
+
+// ConditionCoverageFromEnv reports whether ABAC_CONDITION_COVERAGE enables condition coverage
+func ConditionCoverageFromEnv() (bool, error) {
+	value := os.Getenv(constants.EnvConditionCoverage)
+	if value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: expected true or false", constants.EnvConditionCoverage, value)
+	}
+	return enabled, nil
+}
+
+// RecordPolicy records one evaluation of policy: the conditions of statements in results whose
+// conditions were evaluated count as exercised, and as missing when their key does not resolve in context
+func (cc *CoverageCollector) RecordPolicy(policy *models.Policy, results []StatementResult, context map[string]interface{}) {
+	evaluated := make(map[int]bool, len(results))
+	for _, result := range results {
+		if result.ConditionsEvaluated {
+			evaluated[result.Index] = true
+		}
+	}
+
+	// Resolve keys before locking; the context belongs to this request only
+	type observation struct {
+		statement string
+		leaf      conditionLeaf
+		evaluated bool
+		missing   bool
+	}
+	var observations []observation
+	for i, statement := range policy.Statement {
+		if statement.IsFieldLevel() {
+			continue
+		}
+		sid := statement.Sid
+		if sid == "" {
+			sid = fmt.Sprintf("#%d", i)
+		}
+		for _, leaf := range conditionLeaves(statement.Condition) {
+			obs := observation{statement: sid, leaf: leaf, evaluated: evaluated[i]}
+			if obs.evaluated && leaf.key != "" {
+				_, found := cc.resolver.Resolve(leaf.key, context)
+				obs.missing = !found
+			}
+			observations = append(observations, obs)
+		}
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	coverage, exists := cc.policies[policy.ID]
+	if !exists || coverage.revision != policy.Revision {
+		coverage = &policyCoverage{revision: policy.Revision, index: make(map[string]*conditionCounters)}
+		cc.policies[policy.ID] = coverage
+	}
+	coverage.evaluations++
+
+	for _, obs := range observations {
+		id := obs.statement + "\x00" + obs.leaf.operator + "\x00" + obs.leaf.key
+		counters, exists := coverage.index[id]
+		if !exists {
+			counters = &conditionCounters{statement: obs.statement, leaf: obs.leaf}
+			coverage.index[id] = counters
+			coverage.conditions = append(coverage.conditions, counters)
+		}
+		if obs.evaluated {
+			counters.evaluations++
+			if obs.missing {
+				counters.missing++
+			}
+		}
+	}
+}
+
+// GetPolicyCoverage returns a snapshot of the condition coverage of a policy
+func (cc *CoverageCollector) GetPolicyCoverage(policyID string) (*PolicyCoverage, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	coverage, exists := cc.policies[policyID]
+	if !exists {
+		return nil, false
+	}
+	return coverage.snapshot(policyID), true
+}
+
+// GetAllPolicyCoverage returns snapshots of all recorded policies sorted by policy ID
+func (cc *CoverageCollector) GetAllPolicyCoverage() []*PolicyCoverage {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	result := make([]*PolicyCoverage, 0, len(cc.policies))
+	for policyID, coverage := range cc.policies {
+		result = append(result, coverage.snapshot(policyID))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PolicyID < result[j].PolicyID
+	})
+	return result
+}
+
+// Reset clears all recorded coverage
+func (cc *CoverageCollector) Reset() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.policies = make(map[string]*policyCoverage)
+}
+
+// snapshot converts raw counters into a PolicyCoverage value, in statement and condition order
+func (pc *policyCoverage) snapshot(policyID string) *PolicyCoverage {
+	coverage := &PolicyCoverage{
+		PolicyID:    policyID,
+		Revision:    pc.revision,
+		Evaluations: pc.evaluations,
+		Conditions:  make([]ConditionCoverage, 0, len(pc.conditions)),
+	}
+	for _, counters := range pc.conditions {
+		coverage.Conditions = append(coverage.Conditions, ConditionCoverage{
+			Statement:     counters.statement,
+			Operator:      counters.leaf.operator,
+			Key:           counters.leaf.key,
+			Evaluations:   counters.evaluations,
+			Missing:       counters.missing,
+			Unused:        counters.evaluations == 0,
+			AlwaysMissing: counters.evaluations > 0 && counters.missing == counters.evaluations,
+		})
+	}
+	return coverage
+}
+
+// conditionLeaves lists the operator/key pairs of a condition block in sorted order,
+// including those nested in And/Or/Not. Repeated pairs are listed once.
+func conditionLeaves(conditions map[string]interface{}) []conditionLeaf {
+	seen := make(map[conditionLeaf]bool)
+	var leaves []conditionLeaf
+	var walk func(block map[string]interface{})
+	walk = func(block map[string]interface{}) {
+		operators := make([]string, 0, len(block))
+		for operator := range block {
+			operators = append(operators, operator)
+		}
+		sort.Strings(operators)
+
+		for _, operator := range operators {
+			operands := block[operator]
+			if isLogicalOperator(operator) {
+				switch nested := operands.(type) {
+				case map[string]interface{}:
+					walk(nested)
+				case []interface{}:
+					for _, item := range nested {
+						if nestedBlock, ok := item.(map[string]interface{}); ok {
+							walk(nestedBlock)
+						}
+					}
+				}
+				continue
+			}
+
+			attributes, ok := operands.(map[string]interface{})
+			if !ok {
+				attributes = map[string]interface{}{"": nil}
+			}
+			keys := make([]string, 0, len(attributes))
+			for key := range attributes {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				leaf := conditionLeaf{operator: operator, key: key}
+				if !seen[leaf] {
+					seen[leaf] = true
+					leaves = append(leaves, leaf)
+				}
+			}
+		}
+	}
+	walk(conditions)
+	return leaves
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ConditionCoverage tests exercised, unused and always-missing condition counters
+func TestPDP_ConditionCoverage(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	policy := &models.Policy{
+		ID:       "pol-001",
+		Enabled:  true,
+		Revision: 1,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      "EngineeringRead",
+				Effect:   "Allow",
+				Action:   models.JSONActionResource{Single: "document:read"},
+				Resource: models.JSONActionResource{Single: "api:documents:*"},
+				Condition: map[string]interface{}{
+					"StringEquals": map[string]interface{}{"user.department": "Engineering"},
+					"Or": []interface{}{
+						map[string]interface{}{"StringEquals": map[string]interface{}{"user.clearance_badge": "gold"}},
+						map[string]interface{}{"Bool": map[string]interface{}{"user.department_head": true}},
+					},
+				},
+			},
+			{
+				Effect:   "Allow",
+				Action:   models.JSONActionResource{Single: "report:write"},
+				Resource: models.JSONActionResource{Single: "api:reports:*"},
+				Condition: map[string]interface{}{
+					"StringEquals": map[string]interface{}{"user.department": "Finance"},
+				},
+			},
+		},
+	}
+	mockStorage.SetPolicies([]*models.Policy{policy})
+
+	config := DefaultPDPConfig()
+	config.ConditionCoverage = true
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{"department": "Engineering"}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := pdp.Evaluate(request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	coverage, enabled := pdp.GetPolicyCoverage("pol-001")
+	if !enabled || coverage == nil {
+		t.Fatalf("Expected coverage for pol-001, got %v %v", coverage, enabled)
+	}
+	if coverage.Evaluations != 2 || coverage.Revision != 1 {
+		t.Errorf("Expected 2 evaluations of revision 1, got %+v", coverage)
+	}
+
+	byCondition := make(map[string]ConditionCoverage)
+	for _, condition := range coverage.Conditions {
+		byCondition[condition.Statement+" "+condition.Operator+" "+condition.Key] = condition
+	}
+	if len(byCondition) != 4 {
+		t.Fatalf("Expected 4 conditions, got %+v", coverage.Conditions)
+	}
+
+	department := byCondition["EngineeringRead StringEquals user.department"]
+	if department.Evaluations != 2 || department.Missing != 0 || department.Unused || department.AlwaysMissing {
+		t.Errorf("Unexpected department coverage: %+v", department)
+	}
+	badge := byCondition["EngineeringRead StringEquals user.clearance_badge"]
+	if badge.Evaluations != 2 || badge.Missing != 2 || !badge.AlwaysMissing {
+		t.Errorf("Expected always-missing badge condition: %+v", badge)
+	}
+	if _, ok := byCondition["EngineeringRead Bool user.department_head"]; !ok {
+		t.Errorf("Expected nested Bool condition to be listed")
+	}
+	unused := byCondition["#1 StringEquals user.department"]
+	if unused.Evaluations != 0 || !unused.Unused || unused.AlwaysMissing {
+		t.Errorf("Expected unused condition of statement #1: %+v", unused)
+	}
+
+	// A new revision restarts the counters
+	policy.Revision = 2
+	mockStorage.SetPolicies([]*models.Policy{policy})
+	if _, err := pdp.Evaluate(request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	coverage, _ = pdp.GetPolicyCoverage("pol-001")
+	if coverage.Evaluations != 1 || coverage.Revision != 2 {
+		t.Errorf("Expected counters of revision 2 only, got %+v", coverage)
+	}
+}
+
+// TestPDP_ConditionCoverageDisabled tests that coverage is off by default
+func TestPDP_ConditionCoverageDisabled(t *testing.T) {
+	pdp := NewPolicyDecisionPoint(storage.NewMockStorage())
+	if coverage, enabled := pdp.GetPolicyCoverage("pol-001"); enabled || coverage != nil {
+		t.Errorf("Expected coverage to be disabled, got %v %v", coverage, enabled)
+	}
+}
+
+func TestConditionCoverageFromEnv(t *testing.T) {
+	t.Setenv("ABAC_CONDITION_COVERAGE", "")
+	if enabled, err := ConditionCoverageFromEnv(); enabled || err != nil {
+		t.Errorf("Expected disabled by default, got %v %v", enabled, err)
+	}
+	t.Setenv("ABAC_CONDITION_COVERAGE", "true")
+	if enabled, err := ConditionCoverageFromEnv(); !enabled || err != nil {
+		t.Errorf("Expected enabled, got %v %v", enabled, err)
+	}
+	t.Setenv("ABAC_CONDITION_COVERAGE", "sometimes")
+	if _, err := ConditionCoverageFromEnv(); err == nil {
+		t.Error("Expected error for invalid value")
+	}
+}
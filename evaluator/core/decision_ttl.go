@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/models"
+)
+
+// DecisionTTLConfig configures the CacheTTL hint returned with decisions
+type DecisionTTLConfig struct {
+	// Max is the TTL of decisions that depend on no time-sensitive condition; time-sensitive
+	// decisions expire at the next boundary of their conditions (e.g. 17:00 for business hours)
+	Max time.Duration `json:"max"`
+}
+
+// DefaultDecisionTTLConfig returns the default decision TTL configuration
+func DefaultDecisionTTLConfig() *DecisionTTLConfig {
+	return &DecisionTTLConfig{Max: constants.DefaultDecisionTTLSeconds * time.Second}
+}
+
+// DecisionTTLConfigFromEnv reads ABAC_DECISION_TTL. It returns the default configuration when
+// the variable is unset and nil (decisions are not cacheable) when it is zero.
+func DecisionTTLConfigFromEnv() (*DecisionTTLConfig, error) {
+	value := os.Getenv(constants.EnvDecisionTTL)
+	if value == "" {
+		return DefaultDecisionTTLConfig(), nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return nil, fmt.Errorf("invalid %s %q: expected a duration such as \"5m\"", constants.EnvDecisionTTL, value)
+	}
+	if ttl == 0 {
+		return nil, nil
+	}
+	return &DecisionTTLConfig{Max: ttl}, nil
+}
+
+// decisionTTL returns the CacheTTL of a decision evaluated at `at`: Max, shortened to the next instant
+// at which a condition of a statement applying to the request, a default decision rule or a policy
+// validity window may change the outcome. Decisions depending on volatile conditions get 0.
+func (pdp *PolicyDecisionPoint) decisionTTL(policies []*models.Policy, context map[string]interface{}, at time.Time) int {
+	if pdp.config == nil || pdp.config.DecisionTTL == nil {
+		return 0
+	}
+
+	expiry := at.Add(pdp.config.DecisionTTL.Max)
+	shorten := func(next time.Time) {
+		if !next.IsZero() && next.Before(expiry) {
+			expiry = next
+		}
+	}
+	if next, ok := context[constants.ContextKeyPolicyWindowChange].(time.Time); ok {
+		shorten(next)
+	}
+
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		for _, statement := range policy.Statement {
+			if statement.IsFieldLevel() || len(statement.Condition) == 0 {
+				continue
+			}
+			if !pdp.isActionMatched(statement.Action, statement.Effect, context) || !pdp.isResourceMatched(statement, context) {
+				continue
+			}
+			next, stable := conditions.NextChange(statement.Condition, at)
+			if !stable {
+				return 0
+			}
+			shorten(next)
+		}
+	}
+
+	for _, rule := range pdp.config.DefaultDecisions {
+		if !pdp.defaultRuleCoversAction(rule, context) {
+			continue
+		}
+		next, stable := conditions.NextChange(rule.Condition, at)
+		if !stable {
+			return 0
+		}
+		shorten(next)
+	}
+
+	return ttlSeconds(expiry.Sub(at))
+}
+
+// exceptionTTL returns the CacheTTL of an access exception decision: Max, or less when the exception expires sooner
+func (pdp *PolicyDecisionPoint) exceptionTTL(decision *models.Decision) int {
+	if pdp.config == nil || pdp.config.DecisionTTL == nil {
+		return 0
+	}
+	ttl := pdp.config.DecisionTTL.Max
+	if expiresAt, err := time.Parse(time.RFC3339, decision.ReasonDetails[constants.ReasonDetailExpiresAt]); err == nil {
+		if remaining := expiresAt.Sub(pdp.now()); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	return ttlSeconds(ttl)
+}
+
+// nextPolicyWindowChange returns the next EffectiveFrom/ExpiresAt after t among enabled policies, zero if none
+func nextPolicyWindowChange(policies []*models.Policy, t time.Time) time.Time {
+	var next time.Time
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		for _, bound := range []*time.Time{policy.EffectiveFrom, policy.ExpiresAt} {
+			if bound != nil && bound.After(t) && (next.IsZero() || bound.Before(next)) {
+				next = *bound
+			}
+		}
+	}
+	return next
+}
+
+// ttlSeconds truncates a TTL to whole seconds, never below 0
+func ttlSeconds(ttl time.Duration) int {
+	if ttl <= 0 {
+		return 0
+	}
+	return int(ttl / time.Second)
+}
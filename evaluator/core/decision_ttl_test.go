@@ -0,0 +1,137 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_DecisionTTL tests that CacheTTL ends at the next boundary of the conditions applying to a request
+func TestPDP_DecisionTTL(t *testing.T) {
+	now := time.Date(2024, time.June, 3, 16, 30, 0, 0, time.UTC)
+	expiresAt := now.Add(90 * time.Second)
+
+	mockStorage := storage.NewMockStorage()
+	for _, action := range []string{"report:read", "report:export", "report:share", "report:archive", "report:delete"} {
+		mockStorage.CreateAction(&models.Action{ID: action, ActionName: action})
+	}
+	mockStorage.CreateResource(&models.Resource{ID: "api:reports:q3", ResourceID: "api:reports:q3"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-static",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "ReadAlways", Effect: "Allow", Action: models.JSONActionResource{Single: "report:read"}, Resource: models.JSONActionResource{Single: "api:reports:*"}},
+			},
+		},
+		{
+			ID:      "pol-hours",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid: "ExportInBusinessHours", Effect: "Allow",
+					Action: models.JSONActionResource{Single: "report:export"}, Resource: models.JSONActionResource{Single: "api:reports:*"},
+					Condition: map[string]interface{}{"TimeBetween": map[string]interface{}{"environment.time_of_day": []interface{}{"09:00", "17:00"}}},
+				},
+				{
+					Sid: "ShareWithinQuota", Effect: "Allow",
+					Action: models.JSONActionResource{Single: "report:share"}, Resource: models.JSONActionResource{Single: "api:reports:*"},
+					Condition: map[string]interface{}{"RequestRateBelow": map[string]interface{}{"request:UserId": map[string]interface{}{"limit": 10, "window": "1m"}}},
+				},
+			},
+		},
+		{
+			ID:        "pol-expiring",
+			Enabled:   true,
+			ExpiresAt: &expiresAt,
+			Statement: []models.PolicyStatement{
+				{Sid: "ArchiveUntilCutover", Effect: "Allow", Action: models.JSONActionResource{Single: "report:archive"}, Resource: models.JSONActionResource{Single: "api:reports:*"}},
+			},
+		},
+	})
+
+	config := DefaultPDPConfig()
+	config.Clock = clock.NewMockClock(now)
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	evaluate := func(pdp PolicyDecisionPointInterface, action string) *models.Decision {
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "ttl-001",
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:reports:q3",
+			Action:     action,
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Evaluate returned error: %v", err)
+		}
+		return decision
+	}
+
+	tests := []struct {
+		action   string
+		expected int
+	}{
+		{"report:read", 90},    // Static, but the expiring policy changes the candidate set in 90s
+		{"report:export", 90},  // Business hours end at 17:00:01, after the policy expiry
+		{"report:share", 0},    // Quota conditions change with every request
+		{"report:delete", 90},  // Implicit deny
+		{"report:archive", 90}, // Policy validity window
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if decision := evaluate(pdp, tt.action); decision.CacheTTL != tt.expected {
+				t.Errorf("expected CacheTTL %d, got %d (%s)", tt.expected, decision.CacheTTL, decision.Result)
+			}
+		})
+	}
+
+	t.Run("business hours boundary", func(t *testing.T) {
+		mockStorage.DeletePolicy("pol-expiring")
+		config := DefaultPDPConfig()
+		config.Clock = clock.NewMockClock(now)
+		config.DecisionTTL = &DecisionTTLConfig{Max: time.Hour}
+		pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+		if decision := evaluate(pdp, "report:export"); decision.Result != constants.ResultPermit || decision.CacheTTL != 1801 {
+			t.Errorf("expected a permit cacheable until 17:00:01, got %s for %ds", decision.Result, decision.CacheTTL)
+		}
+		if decision := evaluate(pdp, "report:read"); decision.CacheTTL != 3600 {
+			t.Errorf("expected the maximum TTL for a static decision, got %d", decision.CacheTTL)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := DefaultPDPConfig()
+		config.Clock = clock.NewMockClock(now)
+		config.DecisionTTL = nil
+		if decision := evaluate(NewPolicyDecisionPointWithConfig(mockStorage, config), "report:read"); decision.CacheTTL != 0 {
+			t.Errorf("expected CacheTTL 0 without a TTL configuration, got %d", decision.CacheTTL)
+		}
+	})
+}
+
+func TestDecisionTTLConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvDecisionTTL, "")
+	if config, err := DecisionTTLConfigFromEnv(); err != nil || config.Max != constants.DefaultDecisionTTLSeconds*time.Second {
+		t.Errorf("expected the default config when unset, got %+v (err=%v)", config, err)
+	}
+
+	t.Setenv(constants.EnvDecisionTTL, "1m")
+	if config, err := DecisionTTLConfigFromEnv(); err != nil || config.Max != time.Minute {
+		t.Errorf("expected a 1m TTL, got %+v (err=%v)", config, err)
+	}
+
+	t.Setenv(constants.EnvDecisionTTL, "0")
+	if config, err := DecisionTTLConfigFromEnv(); err != nil || config != nil {
+		t.Errorf("expected no TTL when zero, got %+v (err=%v)", config, err)
+	}
+
+	t.Setenv(constants.EnvDecisionTTL, "soon")
+	if _, err := DecisionTTLConfigFromEnv(); err == nil {
+		t.Error("expected an invalid TTL to fail")
+	}
+}
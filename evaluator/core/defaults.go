@@ -0,0 +1,108 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// DefaultDecisionRule permits requests that no policy matches, instead of the global implicit deny,
+// when the action matches one of Actions or ActionCategories and the optional Condition holds.
+// Explicit Deny statements still win: rules only apply when no statement matched.
+//
+// JSON form: {"name": "public-read", "action_categories": ["read"],
+// "condition": {"StringEquals": {"resource.classification": "public"}}}
+type DefaultDecisionRule struct {
+	Name             string                 `json:"name"`
+	Actions          []string               `json:"actions,omitempty"`           // Action patterns, e.g. "*:read"
+	ActionCategories []string               `json:"action_categories,omitempty"` // Stored action categories, e.g. "read"
+	Condition        map[string]interface{} `json:"condition,omitempty"`         // Policy condition block; empty always holds
+}
+
+// ParseDefaultDecisionRules parses a JSON list of default decision rules
+func ParseDefaultDecisionRules(data []byte) ([]DefaultDecisionRule, error) {
+	var rules []DefaultDecisionRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid default decision rules: %w", err)
+	}
+
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("default decision rule %d has no name", i)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate default decision rule %q", rule.Name)
+		}
+		if len(rule.Actions) == 0 && len(rule.ActionCategories) == 0 {
+			return nil, fmt.Errorf("default decision rule %q matches no actions", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return rules, nil
+}
+
+// LoadDefaultDecisionRules reads JSON default decision rules from path
+func LoadDefaultDecisionRules(path string) ([]DefaultDecisionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default decision rules: %w", err)
+	}
+	return ParseDefaultDecisionRules(data)
+}
+
+// DefaultDecisionRulesFromEnv loads the rules named by ABAC_DEFAULT_DECISIONS.
+// It returns nil (implicit deny for every action) when the variable is unset.
+func DefaultDecisionRulesFromEnv() ([]DefaultDecisionRule, error) {
+	path := os.Getenv(constants.EnvDefaultDecisions)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadDefaultDecisionRules(path)
+}
+
+// defaultDecision returns the default permit of the first rule matching the request, if any
+func (pdp *PolicyDecisionPoint) defaultDecision(context map[string]interface{}) (*models.Decision, bool) {
+	for _, rule := range pdp.config.DefaultDecisions {
+		if !pdp.defaultRuleMatches(rule, context) {
+			continue
+		}
+		return &models.Decision{
+			Result:          constants.ResultPermit,
+			MatchedPolicies: []string{},
+			Reason:          fmt.Sprintf(constants.ReasonDefaultPermit, rule.Name),
+			ReasonCode:      constants.ReasonCodeDefaultPermit,
+			ReasonDetails:   map[string]string{constants.ReasonDetailDefaultRule: rule.Name},
+		}, true
+	}
+	return nil, false
+}
+
+// defaultRuleMatches reports whether the action of context is covered by rule and its condition holds
+func (pdp *PolicyDecisionPoint) defaultRuleMatches(rule DefaultDecisionRule, context map[string]interface{}) bool {
+	if !pdp.defaultRuleCoversAction(rule, context) {
+		return false
+	}
+	return len(rule.Condition) == 0 || pdp.enhancedConditionEvaluator.EvaluateConditions(rule.Condition, context)
+}
+
+// defaultRuleCoversAction reports whether the requested action matches an action pattern or category of rule
+func (pdp *PolicyDecisionPoint) defaultRuleCoversAction(rule DefaultDecisionRule, context map[string]interface{}) bool {
+	action, _ := context[constants.ContextKeyRequestAction].(string)
+	for _, pattern := range rule.Actions {
+		if pdp.actionMatcher.Match(pattern, action) {
+			return true
+		}
+	}
+
+	category, _ := context[constants.ContextKeyActionCategory].(string)
+	for _, ruleCategory := range rule.ActionCategories {
+		if category != "" && ruleCategory == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_DefaultDecisions tests that default permit rules apply only when no statement matches
+func TestPDP_DefaultDecisions(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document-service:file:read", ActionName: "document-service:file:read", ActionCategory: "read"})
+	mockStorage.CreateAction(&models.Action{ID: "document-service:file:write", ActionName: "document-service:file:write", ActionCategory: "write"})
+	mockStorage.CreateAction(&models.Action{ID: "status:health:get", ActionName: "status:health:get"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-public", ResourceID: "api:documents:doc-public", Attributes: models.JSONMap{"classification": "public"}})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-secret", ResourceID: "api:documents:doc-secret", Attributes: models.JSONMap{"classification": "confidential"}})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-blocked", ResourceID: "api:documents:doc-blocked", Attributes: models.JSONMap{"classification": "public"}})
+	mockStorage.CreateResource(&models.Resource{ID: "api:health", ResourceID: "api:health"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-block",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "DenyBlocked", Effect: "Deny", Action: models.JSONActionResource{Single: "*"}, Resource: models.JSONActionResource{Single: "api:documents:doc-blocked"}},
+			},
+		},
+	})
+
+	config := DefaultPDPConfig()
+	config.DefaultDecisions = []DefaultDecisionRule{
+		{
+			Name:             "public-read",
+			ActionCategories: []string{"read"},
+			Condition:        map[string]interface{}{"StringEquals": map[string]interface{}{"resource.classification": "public"}},
+		},
+		{Name: "health-checks", Actions: []string{"status:*:get"}},
+	}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	tests := []struct {
+		name       string
+		resourceID string
+		action     string
+		expected   string
+		reasonCode string
+	}{
+		{"read public document", "api:documents:doc-public", "document-service:file:read", constants.ResultPermit, constants.ReasonCodeDefaultPermit},
+		{"read confidential document", "api:documents:doc-secret", "document-service:file:read", constants.ResultDeny, constants.ReasonCodeImplicitDeny},
+		{"write public document", "api:documents:doc-public", "document-service:file:write", constants.ResultDeny, constants.ReasonCodeImplicitDeny},
+		{"explicit deny wins", "api:documents:doc-blocked", "document-service:file:read", constants.ResultDeny, constants.ReasonCodeDeniedByStatement},
+		{"action pattern", "api:health", "status:health:get", constants.ResultPermit, constants.ReasonCodeDefaultPermit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := pdp.Evaluate(&models.EvaluationRequest{
+				RequestID:  "default-001",
+				Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+				ResourceID: tt.resourceID,
+				Action:     tt.action,
+				Context:    map[string]interface{}{},
+			})
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if decision.Result != tt.expected || decision.ReasonCode != tt.reasonCode {
+				t.Errorf("expected %s (%s), got %s (%s: %s)", tt.expected, tt.reasonCode, decision.Result, decision.ReasonCode, decision.Reason)
+			}
+		})
+	}
+
+	decision, _ := pdp.Evaluate(&models.EvaluationRequest{
+		RequestID:  "default-002",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:doc-public",
+		Action:     "document-service:file:read",
+		Context:    map[string]interface{}{},
+	})
+	if decision.Reason != "No matching policies found (default permit by rule public-read)" ||
+		decision.ReasonDetails[constants.ReasonDetailDefaultRule] != "public-read" {
+		t.Errorf("expected the default rule in the decision reason, got %q %v", decision.Reason, decision.ReasonDetails)
+	}
+}
+
+func TestParseDefaultDecisionRules(t *testing.T) {
+	rules, err := ParseDefaultDecisionRules([]byte(`[{"name": "public-read", "action_categories": ["read"],
+		"condition": {"StringEquals": {"resource.classification": "public"}}}]`))
+	if err != nil || len(rules) != 1 || rules[0].ActionCategories[0] != "read" || rules[0].Condition == nil {
+		t.Fatalf("unexpected rules %+v (err=%v)", rules, err)
+	}
+
+	invalid := map[string]string{
+		"not json":   `{`,
+		"no name":    `[{"actions": ["*:read"]}]`,
+		"no actions": `[{"name": "empty"}]`,
+		"duplicate":  `[{"name": "a", "actions": ["read"]}, {"name": "a", "actions": ["list"]}]`,
+	}
+	for name, data := range invalid {
+		if _, err := ParseDefaultDecisionRules([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDefaultDecisionRulesFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvDefaultDecisions, "")
+	if rules, err := DefaultDecisionRulesFromEnv(); rules != nil || err != nil {
+		t.Errorf("expected no rules when unset, got %v (err=%v)", rules, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "default_decisions.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "health-checks", "actions": ["status:*:get"]}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(constants.EnvDefaultDecisions, path)
+	if rules, err := DefaultDecisionRulesFromEnv(); err != nil || len(rules) != 1 || rules[0].Name != "health-checks" {
+		t.Errorf("unexpected rules %v (err=%v)", rules, err)
+	}
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// DenyCacheConfig configures the negative cache that replays recent denies for identical
+// subject/resource/action requests, absorbing retry storms from misbehaving clients.
+type DenyCacheConfig struct {
+	TTL        time.Duration `json:"ttl"`
+	MaxEntries int           `json:"max_entries"`
+}
+
+// DefaultDenyCacheConfig returns the default negative cache configuration
+func DefaultDenyCacheConfig() *DenyCacheConfig {
+	return &DenyCacheConfig{
+		TTL:        constants.DefaultDenyCacheTTLMs * time.Millisecond,
+		MaxEntries: constants.DefaultDenyCacheMaxEntries,
+	}
+}
+
+// DenyCacheConfigFromEnv reads ABAC_DENY_CACHE_TTL and ABAC_DENY_CACHE_MAX_ENTRIES.
+// It returns nil (cache disabled) when the TTL is unset, zero or invalid.
+func DenyCacheConfigFromEnv() *DenyCacheConfig {
+	ttl, err := time.ParseDuration(os.Getenv(constants.EnvDenyCacheTTL))
+	if err != nil || ttl <= 0 {
+		return nil
+	}
+
+	config := DefaultDenyCacheConfig()
+	config.TTL = ttl
+	if maxEntries, err := strconv.Atoi(os.Getenv(constants.EnvDenyCacheMaxEntries)); err == nil && maxEntries > 0 {
+		config.MaxEntries = maxEntries
+	}
+	return config
+}
+
+// DenyCacheStats reports negative cache activity
+type DenyCacheStats struct {
+	Suppressed int64 `json:"suppressed"` // Evaluations answered from the cache
+	Misses     int64 `json:"misses"`
+	Stored     int64 `json:"stored"`
+	Evictions  int64 `json:"evictions"`
+	Size       int   `json:"size"`
+}
+
+// denyCacheEntry is a cached deny and its expiry
+type denyCacheEntry struct {
+	decision  models.Decision
+	expiresAt time.Time
+}
+
+// DenyCache is a small TTL cache of deny decisions keyed by subject/resource/action.
+// It is safe for concurrent use.
+type DenyCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	clock      clock.Clock
+	entries    map[string]denyCacheEntry
+	stats      DenyCacheStats
+}
+
+// NewDenyCache creates a negative cache; c supplies the time used for expiry (nil = system clock)
+func NewDenyCache(config *DenyCacheConfig, c clock.Clock) *DenyCache {
+	if config == nil {
+		config = DefaultDenyCacheConfig()
+	}
+	return &DenyCache{
+		ttl:        config.TTL,
+		maxEntries: config.MaxEntries,
+		clock:      clock.OrReal(c),
+		entries:    make(map[string]denyCacheEntry),
+	}
+}
+
+// denyCacheKey identifies a request evaluated in namespace for the negative cache
+func denyCacheKey(request *models.EvaluationRequest, namespace string) string {
+	return request.Subject.GetID() + "\x00" + request.ResourceID + "\x00" + request.Action + "\x00" + namespace
+}
+
+// Get returns a copy of the cached deny for key, if it has not expired
+func (c *DenyCache) Get(key string) (*models.Decision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok && !c.clock.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.stats.Suppressed++
+	decision := entry.decision
+	decision.MatchedPolicies = append([]string(nil), entry.decision.MatchedPolicies...)
+	decision.EvaluationTimeMs = 0
+	return &decision, true
+}
+
+// Store caches a deny decision for key. When the cache is full, expired entries are
+// dropped first and then the entry closest to expiry is evicted.
+func (c *DenyCache) Store(key string, decision *models.Decision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+
+	cached := *decision
+	cached.MatchedPolicies = append([]string(nil), decision.MatchedPolicies...)
+	c.entries[key] = denyCacheEntry{decision: cached, expiresAt: now.Add(c.ttl)}
+	c.stats.Stored++
+}
+
+// evict makes room for one entry; callers must hold the lock
+func (c *DenyCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			c.stats.Evictions++
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	delete(c.entries, oldestKey)
+	c.stats.Evictions++
+}
+
+// Purge drops every cached deny, e.g. after policies change
+func (c *DenyCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]denyCacheEntry)
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *DenyCache) Stats() DenyCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func denyCacheTestPolicies(effect string) []*models.Policy {
+	return []*models.Policy{
+		{
+			ID:      "pol-delete",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "DeleteDocuments",
+					Effect:   effect,
+					Action:   models.JSONActionResource{Single: "document:delete"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+				},
+			},
+		},
+	}
+}
+
+// TestPDP_DenyCache tests that repeated identical denies are replayed until the TTL expires
+func TestPDP_DenyCache(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies(denyCacheTestPolicies("Deny"))
+
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	config.DenyCache = &DenyCacheConfig{TTL: time.Second, MaxEntries: 10}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "deny-cache-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:delete",
+		Context:    map[string]interface{}{},
+	}
+
+	evaluate := func(expected string) {
+		t.Helper()
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != expected {
+			t.Fatalf("Expected %s, got %s (%s)", expected, decision.Result, decision.Reason)
+		}
+	}
+
+	evaluate(constants.ResultDeny)
+
+	// The policy now allows, but the cached deny is replayed within the TTL
+	mockStorage.SetPolicies(denyCacheTestPolicies("Allow"))
+	evaluate(constants.ResultDeny)
+
+	stats, enabled := pdp.GetDenyCacheStats()
+	if !enabled {
+		t.Fatal("Expected deny cache to be enabled")
+	}
+	if stats.Suppressed != 1 || stats.Stored != 1 || stats.Size != 1 {
+		t.Errorf("Unexpected stats after replay: %+v", stats)
+	}
+
+	// After the TTL the request is evaluated again; permits are never cached
+	mockClock.Advance(time.Second)
+	evaluate(constants.ResultPermit)
+	evaluate(constants.ResultPermit)
+
+	stats, _ = pdp.GetDenyCacheStats()
+	if stats.Suppressed != 1 || stats.Size != 0 {
+		t.Errorf("Unexpected stats after expiry: %+v", stats)
+	}
+
+	// Purge drops cached denies immediately
+	mockStorage.SetPolicies(denyCacheTestPolicies("Deny"))
+	evaluate(constants.ResultDeny)
+	mockStorage.SetPolicies(denyCacheTestPolicies("Allow"))
+	pdp.PurgeDenyCache()
+	evaluate(constants.ResultPermit)
+}
+
+// TestPDP_DenyCacheDisabledByDefault tests that the default configuration does not cache denies
+func TestPDP_DenyCacheDisabledByDefault(t *testing.T) {
+	pdp := NewPolicyDecisionPoint(storage.NewMockStorage())
+	if _, enabled := pdp.GetDenyCacheStats(); enabled {
+		t.Error("Expected deny cache to be disabled by default")
+	}
+}
+
+func TestDenyCache_Eviction(t *testing.T) {
+	mockClock := clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+	cache := NewDenyCache(&DenyCacheConfig{TTL: time.Minute, MaxEntries: 2}, mockClock)
+	deny := &models.Decision{Result: constants.ResultDeny, MatchedPolicies: []string{"pol-1"}}
+
+	cache.Store("a", deny)
+	mockClock.Advance(time.Second)
+	cache.Store("b", deny)
+	mockClock.Advance(time.Second)
+	cache.Store("c", deny)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the entry closest to expiry to be evicted")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("Expected the newest entry to be cached")
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 1 || stats.Size != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Returned decisions are copies
+	cached, _ := cache.Get("b")
+	cached.MatchedPolicies[0] = "mutated"
+	if again, _ := cache.Get("b"); again.MatchedPolicies[0] != "pol-1" {
+		t.Error("Expected cached decision to be isolated from callers")
+	}
+}
+
+func TestDenyCacheConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvDenyCacheTTL, "")
+	if config := DenyCacheConfigFromEnv(); config != nil {
+		t.Errorf("Expected nil config when TTL is unset, got %+v", config)
+	}
+
+	t.Setenv(constants.EnvDenyCacheTTL, "500ms")
+	t.Setenv(constants.EnvDenyCacheMaxEntries, "42")
+	config := DenyCacheConfigFromEnv()
+	if config == nil || config.TTL != 500*time.Millisecond || config.MaxEntries != 42 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	t.Setenv(constants.EnvDenyCacheTTL, "0")
+	if config := DenyCacheConfigFromEnv(); config != nil {
+		t.Errorf("Expected nil config for zero TTL, got %+v", config)
+	}
+}
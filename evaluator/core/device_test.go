@@ -0,0 +1,82 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/attributes"
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_DeviceTrust tests a zero-trust policy requiring a managed, encrypted and patched device
+func TestPDP_DeviceTrust(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-device",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ReadFromTrustedDevice",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"Bool": map[string]interface{}{
+							"device.managed":        true,
+							"device:disk_encrypted": true,
+						},
+						"NumericLessThanEquals": map[string]interface{}{
+							"device.patch_age_days": 30,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	inventory := attributes.StaticDevicePostureProvider{
+		"trusted":   {Managed: true, DiskEncrypted: true, OSPatchLevel: "2024-05-20"},
+		"stale":     {Managed: true, DiskEncrypted: true, OSPatchLevel: "2024-01-01"},
+		"plaintext": {Managed: true, DiskEncrypted: false, OSPatchLevel: "2024-05-20"},
+	}
+
+	tests := []struct {
+		name     string
+		deviceID string
+		expected string
+	}{
+		{"trusted device", "trusted", constants.ResultPermit},
+		{"outdated patches", "stale", constants.ResultDeny},
+		{"unencrypted disk", "plaintext", constants.ResultDeny},
+		{"unknown device", "byod", constants.ResultDeny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultPDPConfig()
+			config.Clock = clock.NewMockClock(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC))
+			config.DevicePosture = inventory
+			pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+			decision, err := pdp.Evaluate(&models.EvaluationRequest{
+				RequestID:  "device-001",
+				Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+				ResourceID: "api:documents:doc-1",
+				Action:     "document:read",
+				Context:    map[string]interface{}{constants.DeviceKeyID: tt.deviceID},
+			})
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if decision.Result != tt.expected {
+				t.Errorf("expected %s, got %s (%s)", tt.expected, decision.Result, decision.Reason)
+			}
+		})
+	}
+}
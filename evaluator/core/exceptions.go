@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// exceptionDecision returns the decision of an active access exception for the exact subject,
+// resource and action of the request. A deny exception wins over allow exceptions. Storages
+// without an ExceptionStore never return exception decisions.
+func (pdp *PolicyDecisionPoint) exceptionDecision(request *models.EvaluationRequest) (*models.Decision, bool, error) {
+	exceptionStore, ok := pdp.storage.(storage.ExceptionStore)
+	if !ok || request == nil || request.Subject == nil {
+		return nil, false, nil
+	}
+
+	// Point-in-time requests see the exceptions active at that time
+	at := pdp.now()
+	if request.AsOf != nil {
+		at = *request.AsOf
+	}
+	exceptions, err := exceptionStore.FindActiveExceptions(request.Subject.GetID(), request.ResourceID, request.Action, at)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up access exceptions: %w", err)
+	}
+	if len(exceptions) == 0 {
+		return nil, false, nil
+	}
+
+	applied := exceptions[0]
+	for _, exception := range exceptions {
+		if exception.Effect == constants.EffectDeny {
+			applied = exception
+			break
+		}
+	}
+
+	decision := &models.Decision{
+		Result:          constants.ResultPermit,
+		MatchedPolicies: []string{},
+		Reason:          fmt.Sprintf(constants.ReasonAllowedByException, applied.ID, applied.Justification),
+		ReasonCode:      constants.ReasonCodeAllowedByException,
+		ReasonDetails: map[string]string{
+			constants.ReasonDetailException: applied.ID,
+			constants.ReasonDetailExpiresAt: applied.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	}
+	if applied.Effect == constants.EffectDeny {
+		decision.Result = constants.ResultDeny
+		decision.Reason = fmt.Sprintf(constants.ReasonDeniedByException, applied.ID, applied.Justification)
+		decision.ReasonCode = constants.ReasonCodeDeniedByException
+	}
+	return decision, true, nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// TestPDP_AccessExceptions tests that active exceptions override policy evaluation until they expire
+func TestPDP_AccessExceptions(t *testing.T) {
+	mockStorage := hooksTestStorage()
+	mockClock := clock.NewMockClock(time.Now())
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	config.DenyCache = &DenyCacheConfig{TTL: time.Hour, MaxEntries: 10}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	// alice satisfies the policy, bob does not
+	request := func(subjectID string) *models.EvaluationRequest {
+		request := hooksTestRequest(subjectID)
+		if subjectID == "alice" {
+			request.Context = map[string]interface{}{"region": "eu", "ticket": "approved"}
+		}
+		return request
+	}
+	evaluate := func(subjectID string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(request(subjectID))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+
+	// Cached before the exception exists: exceptions are checked ahead of the deny cache
+	if decision := evaluate("bob"); decision.Result != constants.ResultDeny {
+		t.Fatalf("Expected implicit deny for bob, got %s", decision.Result)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	for _, exception := range []*models.AccessException{
+		{ID: "exc-bob", SubjectID: "bob", ResourceID: "api:documents:plan.pdf", Action: "document:read", Effect: constants.EffectAllow, Justification: "Audit INC-42", ExpiresAt: expiresAt},
+		{ID: "exc-alice-allow", SubjectID: "alice", ResourceID: "api:documents:plan.pdf", Action: "document:read", Effect: constants.EffectAllow, Justification: "Project Q", ExpiresAt: expiresAt},
+		{ID: "exc-alice-deny", SubjectID: "alice", ResourceID: "api:documents:plan.pdf", Action: "document:read", Effect: constants.EffectDeny, Justification: "Legal hold", ExpiresAt: expiresAt},
+		{ID: "exc-bob-write", SubjectID: "bob", ResourceID: "api:documents:plan.pdf", Action: "document:write", Effect: constants.EffectAllow, Justification: "Other action", ExpiresAt: expiresAt},
+	} {
+		if err := mockStorage.CreateException(exception); err != nil {
+			t.Fatalf("CreateException failed: %v", err)
+		}
+	}
+
+	decision := evaluate("bob")
+	if decision.Result != constants.ResultPermit || decision.Reason != "Allowed by exception exc-bob: Audit INC-42" ||
+		decision.ReasonCode != constants.ReasonCodeAllowedByException || decision.ReasonDetails[constants.ReasonDetailException] != "exc-bob" {
+		t.Errorf("Expected permit by exception, got %s (%s, %s, %v)", decision.Result, decision.Reason, decision.ReasonCode, decision.ReasonDetails)
+	}
+
+	// A deny exception wins over an allow exception and over permitting policies
+	decision = evaluate("alice")
+	if decision.Result != constants.ResultDeny || decision.ReasonCode != constants.ReasonCodeDeniedByException ||
+		decision.ReasonDetails[constants.ReasonDetailException] != "exc-alice-deny" {
+		t.Errorf("Expected deny by exception, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// Expired exceptions no longer apply; alice's policy permit and bob's cached deny are back
+	mockClock.Advance(2 * time.Hour)
+	if decision := evaluate("alice"); decision.Result != constants.ResultPermit || decision.ReasonCode != constants.ReasonCodeAllowedByStatements {
+		t.Errorf("Expected policy permit after expiry, got %s (%s)", decision.Result, decision.Reason)
+	}
+	if decision := evaluate("bob"); decision.Result != constants.ResultDeny || decision.ReasonCode == constants.ReasonCodeAllowedByException {
+		t.Errorf("Expected deny after expiry, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
+
+// TestPDP_AccessExceptionsAllEntryPoints tests that a deny exception applies to Evaluate, EvaluateFields and Explain alike
+func TestPDP_AccessExceptionsAllEntryPoints(t *testing.T) {
+	mockStorage := hooksTestStorage()
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	// alice satisfies the policy
+	request := hooksTestRequest("alice")
+	request.Context = map[string]interface{}{"region": "eu", "ticket": "approved"}
+	if decision, err := pdp.Evaluate(request); err != nil || decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected policy permit before the exception, got %+v, %v", decision, err)
+	}
+
+	exception := &models.AccessException{
+		ID: "exc-alice-deny", SubjectID: "alice", ResourceID: "api:documents:plan.pdf", Action: "document:read",
+		Effect: constants.EffectDeny, Justification: "Legal hold", ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := mockStorage.CreateException(exception); err != nil {
+		t.Fatalf("CreateException failed: %v", err)
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil || decision.ReasonCode != constants.ReasonCodeDeniedByException {
+		t.Errorf("Evaluate: expected deny by exception, got %+v, %v", decision, err)
+	}
+
+	fieldDecision, err := pdp.EvaluateFields(request, []string{"title", "salary"})
+	if err != nil {
+		t.Fatalf("EvaluateFields failed: %v", err)
+	}
+	if fieldDecision.Decision.ReasonCode != constants.ReasonCodeDeniedByException {
+		t.Errorf("EvaluateFields: expected deny by exception, got %+v", fieldDecision.Decision)
+	}
+	for field, directive := range fieldDecision.Fields {
+		if directive.Effect != constants.EffectDeny {
+			t.Errorf("EvaluateFields: expected %s denied, got %s", field, directive.Effect)
+		}
+	}
+
+	explanation, err := pdp.Explain(request)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if explanation.Decision.ReasonCode != constants.ReasonCodeDeniedByException || len(explanation.Statements) != 0 {
+		t.Errorf("Explain: expected deny by exception without statement traces, got %+v", explanation)
+	}
+}
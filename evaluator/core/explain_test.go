@@ -0,0 +1,164 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/redaction"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ExplainReportsStatementsAndConflicts tests Explain output
+func TestPDP_ExplainReportsStatementsAndConflicts(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:test.pdf",
+		ResourceID: "api:documents:test.pdf",
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-001",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department": "Engineering",
+						},
+					},
+				},
+				{
+					Sid:      "ReportWrite",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "report:write"},
+					Resource: models.JSONActionResource{Single: "api:reports:*"},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	request := &models.EvaluationRequest{
+		RequestID: "explain-001",
+		Subject: models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{
+			"department": "Engineering",
+		}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+		Context: map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"department": "Finance",
+			},
+		},
+	}
+
+	explanation, err := pdp.Explain(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if explanation.Decision.Result != "permit" {
+		t.Errorf("Expected permit (stored department wins), got %s", explanation.Decision.Result)
+	}
+	if explanation.Decision.ReasonCode != constants.ReasonCodeAllowedByStatements ||
+		explanation.Decision.ReasonDetails[constants.ReasonDetailStatements] != "EngineeringRead" {
+		t.Errorf("Unexpected reason code/details: %s %v", explanation.Decision.ReasonCode, explanation.Decision.ReasonDetails)
+	}
+
+	if len(explanation.Statements) != 2 {
+		t.Fatalf("Expected 2 statement traces, got %d", len(explanation.Statements))
+	}
+	if !explanation.Statements[0].Matched {
+		t.Errorf("Expected EngineeringRead to match: %+v", explanation.Statements[0])
+	}
+	if explanation.Statements[1].Matched || explanation.Statements[1].ActionMatched {
+		t.Errorf("Expected ReportWrite not to match: %+v", explanation.Statements[1])
+	}
+
+	conditions := explanation.Statements[0].Conditions
+	if len(conditions) != 1 || conditions[0].Operator != "StringEquals" ||
+		conditions[0].Key != "user.department" || !conditions[0].Matched {
+		t.Errorf("Unexpected condition traces: %+v", conditions)
+	}
+	if len(explanation.Statements[1].Conditions) != 0 {
+		t.Errorf("Expected no condition traces for unconditional statement: %+v", explanation.Statements[1].Conditions)
+	}
+
+	if len(explanation.AttributeConflicts) != 1 {
+		t.Fatalf("Expected 1 attribute conflict, got %+v", explanation.AttributeConflicts)
+	}
+	conflict := explanation.AttributeConflicts[0]
+	if conflict.Key != "department" || conflict.StoredValue != "Engineering" || conflict.RequestValue != "Finance" {
+		t.Errorf("Unexpected conflict: %+v", conflict)
+	}
+}
+
+// TestPDP_ExplainRedactsSensitiveConflicts tests that Explain masks sensitive values
+// while conditions still evaluate against raw attributes
+func TestPDP_ExplainRedactsSensitiveConflicts(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:test.pdf",
+		ResourceID: "api:documents:test.pdf",
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-001",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department": "Engineering",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, &PDPConfig{
+		Redactor: redaction.NewRedactor("department"),
+	})
+	request := &models.EvaluationRequest{
+		RequestID: "explain-002",
+		Subject: models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{
+			"department": "Engineering",
+		}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+		Context: map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"department": "Finance",
+			},
+		},
+	}
+
+	explanation, err := pdp.Explain(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if explanation.Decision.Result != "permit" {
+		t.Errorf("Expected permit from raw department value, got %s", explanation.Decision.Result)
+	}
+
+	if len(explanation.AttributeConflicts) != 1 {
+		t.Fatalf("Expected 1 attribute conflict, got %+v", explanation.AttributeConflicts)
+	}
+	conflict := explanation.AttributeConflicts[0]
+	if conflict.StoredValue != redaction.DefaultMask || conflict.RequestValue != redaction.DefaultMask {
+		t.Errorf("Expected conflict values to be redacted: %+v", conflict)
+	}
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/path"
+	"abac_go_example/models"
+)
+
+// explainTree builds the decision tree of Explain: one node per enabled policy, statement, action/resource
+// target and condition. Conditions are evaluated on their own, so a statement whose action did not match
+// still shows which conditions would have passed.
+func (pdp *PolicyDecisionPoint) explainTree(decision *models.Decision, policies []*models.Policy, context map[string]interface{}) *models.ExplainNode {
+	root := &models.ExplainNode{
+		Kind:   models.ExplainNodeDecision,
+		Label:  fmt.Sprintf("%s: %s", decision.Result, decision.Reason),
+		Passed: decision.Result == constants.ResultPermit,
+	}
+
+	resolver := path.NewCompositePathResolver()
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+
+		policyNode := &models.ExplainNode{Kind: models.ExplainNodePolicy, Label: policyLabel(policy)}
+		for _, statement := range policy.Statement {
+			if statement.IsFieldLevel() {
+				continue
+			}
+			statementNode := pdp.statementNode(statement, context, resolver)
+			policyNode.Passed = policyNode.Passed || statementNode.Passed
+			policyNode.Children = append(policyNode.Children, statementNode)
+		}
+		root.Children = append(root.Children, policyNode)
+	}
+	return root
+}
+
+// policyLabel names a policy by ID and, when set, its name
+func policyLabel(policy *models.Policy) string {
+	if policy.PolicyName == "" {
+		return "Policy " + policy.ID
+	}
+	return fmt.Sprintf("Policy %s (%s)", policy.ID, policy.PolicyName)
+}
+
+// statementNode traces the action, resource and conditions of a statement
+func (pdp *PolicyDecisionPoint) statementNode(statement models.PolicyStatement, context map[string]interface{}, resolver path.PathResolver) *models.ExplainNode {
+	label := fmt.Sprintf("Statement %s", statement.Effect)
+	if statement.Sid != "" {
+		label = fmt.Sprintf("Statement %s (%s)", statement.Sid, statement.Effect)
+	}
+
+	actionNode := &models.ExplainNode{
+		Kind:     models.ExplainNodeTarget,
+		Label:    "Action",
+		Passed:   pdp.isActionMatched(statement.Action, statement.Effect, context),
+		Actual:   context[constants.ContextKeyRequestAction],
+		Expected: statement.Action.GetValues(),
+	}
+	resourceNode := &models.ExplainNode{
+		Kind:     models.ExplainNodeTarget,
+		Label:    "Resource",
+		Passed:   pdp.isResourceMatched(statement, context),
+		Actual:   context[constants.ContextKeyRequestResourceID],
+		Expected: statement.Resource.GetValues(),
+	}
+	if notResource := statement.NotResource.GetValues(); len(notResource) > 0 {
+		resourceNode.Label = "Resource (NotResource " + strings.Join(notResource, ", ") + ")"
+	}
+
+	node := &models.ExplainNode{
+		Kind:     models.ExplainNodeStatement,
+		Label:    label,
+		Children: []*models.ExplainNode{actionNode, resourceNode},
+	}
+	node.Children = append(node.Children, pdp.conditionNodes(statement.Condition, context, resolver)...)
+	node.Passed = actionNode.Passed && resourceNode.Passed && pdp.areConditionsSatisfied(statement.Condition, context)
+	return node
+}
+
+// conditionNodes traces each operator/key pair of a condition block in sorted order.
+// Logical operators get one node whose children trace their nested blocks.
+func (pdp *PolicyDecisionPoint) conditionNodes(conditions map[string]interface{}, context map[string]interface{}, resolver path.PathResolver) []*models.ExplainNode {
+	operators := make([]string, 0, len(conditions))
+	for operator := range conditions {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+
+	var nodes []*models.ExplainNode
+	for _, operator := range operators {
+		operands := conditions[operator]
+		if isLogicalOperator(operator) {
+			nodes = append(nodes, &models.ExplainNode{
+				Kind:     models.ExplainNodeCondition,
+				Label:    operator,
+				Passed:   pdp.enhancedConditionEvaluator.EvaluateConditions(map[string]interface{}{operator: operands}, context),
+				Children: pdp.nestedConditionNodes(operands, context, resolver),
+			})
+			continue
+		}
+
+		block, ok := operands.(map[string]interface{})
+		if !ok {
+			nodes = append(nodes, &models.ExplainNode{
+				Kind:   models.ExplainNodeCondition,
+				Label:  operator,
+				Passed: pdp.enhancedConditionEvaluator.EvaluateConditions(map[string]interface{}{operator: operands}, context),
+			})
+			continue
+		}
+
+		keys := make([]string, 0, len(block))
+		for key := range block {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			actual, _ := resolver.Resolve(key, context)
+			nodes = append(nodes, &models.ExplainNode{
+				Kind:     models.ExplainNodeCondition,
+				Label:    operator + " " + key,
+				Passed:   pdp.enhancedConditionEvaluator.EvaluateConditions(map[string]interface{}{operator: map[string]interface{}{key: block[key]}}, context),
+				Actual:   pdp.config.Redactor.MaskValue(key, actual),
+				Expected: block[key],
+			})
+		}
+	}
+	return nodes
+}
+
+// nestedConditionNodes traces the operands of a logical operator: a block or a list of blocks.
+// A listed block with several conditions is grouped under an "All" node.
+func (pdp *PolicyDecisionPoint) nestedConditionNodes(operands interface{}, context map[string]interface{}, resolver path.PathResolver) []*models.ExplainNode {
+	switch value := operands.(type) {
+	case map[string]interface{}:
+		return pdp.conditionNodes(value, context, resolver)
+	case []interface{}:
+		var nodes []*models.ExplainNode
+		for _, item := range value {
+			block, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			children := pdp.conditionNodes(block, context, resolver)
+			if len(children) == 1 {
+				nodes = append(nodes, children[0])
+				continue
+			}
+			nodes = append(nodes, &models.ExplainNode{
+				Kind:     models.ExplainNodeCondition,
+				Label:    "All",
+				Passed:   pdp.enhancedConditionEvaluator.EvaluateConditions(block, context),
+				Children: children,
+			})
+		}
+		return nodes
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ExplainTree tests the policy → statement → condition tree returned by Explain
+func TestPDP_ExplainTree(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:         "pol-001",
+			PolicyName: "Engineering read",
+			Enabled:    true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{"user.department": "Engineering"},
+						"Or": []interface{}{
+							map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Sales"}},
+							map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Finance"}},
+						},
+					},
+				},
+			},
+		},
+		{ID: "pol-disabled", Enabled: false},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	explanation, err := pdp.Explain(&models.EvaluationRequest{
+		RequestID: "explain-tree-001",
+		Subject: models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{
+			"department": "Engineering",
+		}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tree := explanation.Tree
+	if tree == nil || tree.Kind != models.ExplainNodeDecision || tree.Passed {
+		t.Fatalf("Expected a failed decision root, got %+v", tree)
+	}
+	if len(tree.Children) != 1 {
+		t.Fatalf("Expected only the enabled policy, got %d children", len(tree.Children))
+	}
+
+	policy := tree.Children[0]
+	if policy.Label != "Policy pol-001 (Engineering read)" || policy.Passed || len(policy.Children) != 1 {
+		t.Fatalf("Unexpected policy node: %+v", policy)
+	}
+
+	statement := policy.Children[0]
+	if statement.Label != "Statement EngineeringRead (Allow)" || statement.Passed {
+		t.Fatalf("Unexpected statement node: %+v", statement)
+	}
+	// Action, Resource, then conditions in operator order (Or before StringEquals)
+	if len(statement.Children) != 4 {
+		t.Fatalf("Expected 4 statement children, got %d", len(statement.Children))
+	}
+	action, resource, or, department := statement.Children[0], statement.Children[1], statement.Children[2], statement.Children[3]
+	if !action.Passed || action.Actual != "document:read" {
+		t.Errorf("Unexpected action node: %+v", action)
+	}
+	if !resource.Passed || resource.Actual != "api:documents:test.pdf" {
+		t.Errorf("Unexpected resource node: %+v", resource)
+	}
+	if !department.Passed || department.Label != "StringEquals user.department" ||
+		department.Actual != "Engineering" || department.Expected != "Engineering" {
+		t.Errorf("Unexpected department node: %+v", department)
+	}
+	if or.Label != "Or" || or.Passed || len(or.Children) != 2 {
+		t.Fatalf("Unexpected Or node: %+v", or)
+	}
+	if or.Children[0].Actual != "Engineering" || or.Children[0].Expected != "Sales" || or.Children[0].Passed {
+		t.Errorf("Unexpected nested condition node: %+v", or.Children[0])
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/models"
+)
+
+// fieldEffectPriority orders field effects; the strongest matching effect wins (Deny > Mask > Allow)
+var fieldEffectPriority = map[string]int{
+	constants.EffectAllow: 1,
+	constants.EffectMask:  2,
+	constants.EffectDeny:  3,
+}
+
+// EvaluateFields evaluates the request at resource level and returns a directive for each field.
+// Field-level statements (with Fields) decide allow/deny/mask per field; fields not covered by any
+// matching field-level statement inherit the resource-level decision.
+func (pdp *PolicyDecisionPoint) EvaluateFields(request *models.EvaluationRequest, fields []string) (*models.FieldDecision, error) {
+	startTime := time.Now()
+
+	gated, err := pdp.runGates(request, false)
+	if err != nil {
+		return nil, err
+	}
+	if !gated.enriched() {
+		// Revoked, locked down or decided by an exception: every field follows the decision
+		effect := constants.EffectDeny
+		if gated.decision.Result == constants.ResultPermit {
+			effect = constants.EffectAllow
+		}
+		directives := make(map[string]models.FieldDirective, len(fields))
+		for _, field := range fields {
+			directives[field] = models.FieldDirective{Field: field, Effect: effect}
+		}
+		return &models.FieldDecision{Decision: gated.decision, Fields: directives}, nil
+	}
+	allPolicies, evalContext := gated.policies, gated.evalContext
+
+	decision := gated.decision
+	if decision == nil {
+		decision = pdp.evaluateNewPolicies(allPolicies, evalContext)
+	}
+	decision.EvaluationTimeMs = int(time.Since(startTime).Milliseconds())
+	decision.Reason = pdp.config.Redactor.RedactText(decision.Reason, evalContext)
+
+	defaultEffect := constants.EffectDeny
+	if decision.Result == constants.ResultPermit {
+		defaultEffect = constants.EffectAllow
+	}
+
+	directives := make(map[string]models.FieldDirective, len(fields))
+	for _, field := range fields {
+		directive := pdp.evaluateField(allPolicies, field, evalContext)
+		if directive.Effect == "" {
+			directive.Effect = defaultEffect
+		}
+		// Field-level Allow/Mask cannot grant access to a resource that is denied as a whole
+		if decision.Result != constants.ResultPermit && directive.Effect != constants.EffectDeny {
+			directive = models.FieldDirective{Field: field, Effect: constants.EffectDeny}
+		}
+		directives[field] = directive
+	}
+
+	return &models.FieldDecision{
+		Decision: decision,
+		Fields:   directives,
+	}, nil
+}
+
+// evaluateField returns the strongest field-level directive matching field, or an empty effect when none match
+func (pdp *PolicyDecisionPoint) evaluateField(policies []*models.Policy, field string, context map[string]interface{}) models.FieldDirective {
+	directive := models.FieldDirective{Field: field}
+
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+
+		compiled := pdp.compiler.Compile(policy)
+		for i, statement := range policy.Statement {
+			if !statement.IsFieldLevel() || !matchesField(statement.Fields, field) {
+				continue
+			}
+
+			effect := strings.ToLower(statement.Effect)
+			if fieldEffectPriority[effect] <= fieldEffectPriority[directive.Effect] {
+				continue
+			}
+
+			if pdp.matchStatement(statement, &compiled[i], context).Matched {
+				directive.Effect = effect
+				directive.PolicyID = policy.ID
+				directive.Sid = statement.Sid
+			}
+		}
+	}
+
+	return directive
+}
+
+// matchesField reports whether field matches any of the statement's field patterns
+func matchesField(patterns models.JSONActionResource, field string) bool {
+	for _, pattern := range patterns.GetValues() {
+		if matchers.WildcardMatch(pattern, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// newFieldTestPDP creates a PDP with an employee record policy: everyone may read,
+// salary is visible only to HR, email is masked and national_id is never returned
+func newFieldTestPDP() (PolicyDecisionPointInterface, *storage.MockStorage) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "employee:read", ActionName: "employee:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:employees:emp-001",
+		ResourceID: "api:employees:emp-001",
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-fields",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ReadEmployees",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "employee:read"},
+					Resource: models.JSONActionResource{Single: "api:employees:*"},
+				},
+				{
+					Sid:      "DenySalaryOutsideHR",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "employee:read"},
+					Resource: models.JSONActionResource{Single: "api:employees:*"},
+					Fields:   models.JSONActionResource{Single: "salary"},
+					Condition: map[string]interface{}{
+						"StringNotEquals": map[string]interface{}{
+							"user.department": "HR",
+						},
+					},
+				},
+				{
+					Sid:      "MaskContact",
+					Effect:   "Mask",
+					Action:   models.JSONActionResource{Single: "employee:read"},
+					Resource: models.JSONActionResource{Single: "api:employees:*"},
+					Fields:   models.JSONActionResource{Multiple: []string{"email", "contact.*"}},
+				},
+				{
+					Sid:      "DenyNationalID",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "employee:read"},
+					Resource: models.JSONActionResource{Single: "api:employees:*"},
+					Fields:   models.JSONActionResource{Multiple: []string{"national_id", "email"}},
+				},
+			},
+		},
+	})
+
+	return NewPolicyDecisionPoint(mockStorage), mockStorage
+}
+
+func fieldRequest(department string) *models.EvaluationRequest {
+	return &models.EvaluationRequest{
+		RequestID: "fields-001",
+		Subject: models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{
+			"department": department,
+		}),
+		ResourceID: "api:employees:emp-001",
+		Action:     "employee:read",
+	}
+}
+
+// TestPDP_EvaluateFields tests per-field directives and their precedence
+func TestPDP_EvaluateFields(t *testing.T) {
+	pdp, _ := newFieldTestPDP()
+	fields := []string{"name", "salary", "email", "contact.phone", "national_id"}
+
+	tests := []struct {
+		department string
+		expected   map[string]string
+	}{
+		{
+			department: "Engineering",
+			expected: map[string]string{
+				"name":          constants.EffectAllow,
+				"salary":        constants.EffectDeny,
+				"email":         constants.EffectDeny, // Deny wins over Mask
+				"contact.phone": constants.EffectMask,
+				"national_id":   constants.EffectDeny,
+			},
+		},
+		{
+			department: "HR",
+			expected: map[string]string{
+				"name":          constants.EffectAllow,
+				"salary":        constants.EffectAllow,
+				"email":         constants.EffectDeny,
+				"contact.phone": constants.EffectMask,
+				"national_id":   constants.EffectDeny,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.department, func(t *testing.T) {
+			result, err := pdp.EvaluateFields(fieldRequest(tt.department), fields)
+			if err != nil {
+				t.Fatalf("EvaluateFields returned error: %v", err)
+			}
+			if result.Decision.Result != constants.ResultPermit {
+				t.Fatalf("expected resource-level permit, got %s", result.Decision.Result)
+			}
+			for field, effect := range tt.expected {
+				if got := result.Fields[field].Effect; got != effect {
+					t.Errorf("field %s: expected %s, got %s", field, effect, got)
+				}
+			}
+			if result.Fields["contact.phone"].Sid != "MaskContact" {
+				t.Errorf("expected directive to reference MaskContact, got %q", result.Fields["contact.phone"].Sid)
+			}
+		})
+	}
+}
+
+// TestPDP_FieldStatementsIgnoredByEvaluate tests that field-level statements do not affect resource decisions
+func TestPDP_FieldStatementsIgnoredByEvaluate(t *testing.T) {
+	pdp, _ := newFieldTestPDP()
+
+	decision, err := pdp.Evaluate(fieldRequest("Engineering"))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("expected permit despite field-level deny statements, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
+
+// TestPDP_EvaluateFieldsDeniedResource tests that fields are denied when the resource is denied
+func TestPDP_EvaluateFieldsDeniedResource(t *testing.T) {
+	pdp, mockStorage := newFieldTestPDP()
+	mockStorage.CreateAction(&models.Action{ID: "employee:delete", ActionName: "employee:delete"})
+
+	request := fieldRequest("HR")
+	request.Action = "employee:delete"
+
+	result, err := pdp.EvaluateFields(request, []string{"name", "email"})
+	if err != nil {
+		t.Fatalf("EvaluateFields returned error: %v", err)
+	}
+	if result.Decision.Result != constants.ResultDeny {
+		t.Fatalf("expected deny, got %s", result.Decision.Result)
+	}
+	for field, directive := range result.Fields {
+		if directive.Effect != constants.EffectDeny {
+			t.Errorf("field %s: expected deny, got %s", field, directive.Effect)
+		}
+	}
+}
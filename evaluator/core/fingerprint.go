@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/bundle"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// PolicyFingerprint identifies the policy set a PDP evaluates, so PEPs and decision caches can
+// cheaply detect that their copy or cached decisions are stale
+type PolicyFingerprint struct {
+	Hash       string    `json:"hash"`                // Hex SHA-256 over the policy digests, as in a bundle Hash
+	Namespace  string    `json:"namespace,omitempty"` // Evaluation namespace; empty for global policies only
+	Policies   int       `json:"policies"`            // Policies in the set, including disabled ones
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// GetPolicyFingerprint returns the fingerprint of the policies evaluated in namespace (empty uses the
+// configured default): global and namespace policies, restricted to the trusted bundle when one is required
+func (pdp *PolicyDecisionPoint) GetPolicyFingerprint(namespace string) (*PolicyFingerprint, error) {
+	namespace = pdp.evaluationNamespace(&models.EvaluationRequest{Namespace: namespace})
+	policies, err := storage.GetNamespacePolicies(pdp.storage, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	policies = pdp.trustedPolicies(policies)
+
+	hash, err := bundle.PolicySetHash(policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash policies: %w", err)
+	}
+	return &PolicyFingerprint{
+		Hash:       hash,
+		Namespace:  namespace,
+		Policies:   len(policies),
+		ComputedAt: pdp.now(),
+	}, nil
+}
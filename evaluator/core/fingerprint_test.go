@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PolicyFingerprint tests that the fingerprint tracks the evaluated policy set
+func TestPDP_PolicyFingerprint(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	global := &models.Policy{
+		ID:      "pol-global",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "*"}},
+		},
+	}
+	billing := &models.Policy{
+		ID:        "pol-billing",
+		Namespace: "billing",
+		Enabled:   true,
+		Statement: []models.PolicyStatement{
+			{Sid: "Pay", Effect: "Allow", Action: models.JSONActionResource{Single: "invoice:pay"}, Resource: models.JSONActionResource{Single: "*"}},
+		},
+	}
+	mockStorage.SetPolicies([]*models.Policy{global, billing})
+	pdp := NewPolicyDecisionPoint(mockStorage)
+
+	first, err := pdp.GetPolicyFingerprint("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.Hash == "" || first.Policies != 1 || first.Namespace != "" {
+		t.Fatalf("Expected a fingerprint of the global policy only, got %+v", first)
+	}
+
+	scoped, err := pdp.GetPolicyFingerprint("billing")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if scoped.Policies != 2 || scoped.Namespace != "billing" || scoped.Hash == first.Hash {
+		t.Errorf("Expected the billing set to differ, got %+v", scoped)
+	}
+
+	// Re-saving the same content keeps the hash
+	again, _ := pdp.GetPolicyFingerprint("")
+	if again.Hash != first.Hash {
+		t.Errorf("Expected a stable hash, got %s then %s", first.Hash, again.Hash)
+	}
+
+	// Editing a policy changes it
+	global.Statement[0].Effect = "Deny"
+	mockStorage.SetPolicies([]*models.Policy{global, billing})
+	edited, _ := pdp.GetPolicyFingerprint("")
+	if edited.Hash == first.Hash {
+		t.Error("Expected the hash to change after a policy edit")
+	}
+}
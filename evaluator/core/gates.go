@@ -0,0 +1,87 @@
+package core
+
+import (
+	"abac_go_example/models"
+)
+
+// Gates that decide a request before its policies are combined, in the order they run
+const (
+	gateRevocation = "revocation"
+	gateLockdown   = "lockdown"
+	gateException  = "exception"
+	gateDenyCache  = "deny_cache"
+	gateHook       = "hook"
+)
+
+// gatedRequest is a request that passed through the pre-evaluation gates. When gate is set, decision
+// is the decision of that gate and policies must not be combined. The candidate policies and the
+// enriched context are only present when the request reached the hook gate.
+type gatedRequest struct {
+	gate        string
+	decision    *models.Decision
+	policies    []*models.Policy
+	evalContext map[string]interface{}
+	context     *models.EvaluationContext
+}
+
+// enriched reports whether the request context was enriched before a gate decided it
+func (g *gatedRequest) enriched() bool {
+	return g.evalContext != nil
+}
+
+// runGates runs the gates applied by Evaluate, EvaluateFields and Explain before policies are combined:
+// revocations, lockdown, access exceptions, the negative cache (only when useDenyCache) and, once the
+// context is enriched and tag-filtered policies are loaded, the BeforeDecision hooks.
+func (pdp *PolicyDecisionPoint) runGates(request *models.EvaluationRequest, useDenyCache bool) (*gatedRequest, error) {
+	// Deny revoked subjects and sessions, whatever lockdown, exceptions and policies say
+	decision, ok, err := pdp.revocationDecision(request)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return &gatedRequest{gate: gateRevocation, decision: decision}, nil
+	}
+
+	// Deny everything not exempt from an active lockdown, before any evaluation
+	if decision, ok := pdp.lockdownDecision(request); ok {
+		return &gatedRequest{gate: gateLockdown, decision: decision}, nil
+	}
+
+	// Apply an explicit access exception for the subject/resource/action instead of policies
+	decision, ok, err = pdp.exceptionDecision(request)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		decision.CacheTTL = pdp.exceptionTTL(decision)
+		return &gatedRequest{gate: gateException, decision: decision}, nil
+	}
+
+	// Replay a recent deny for an identical subject/resource/action
+	if useDenyCache {
+		if decision, ok := pdp.cachedDeny(request); ok {
+			return &gatedRequest{gate: gateDenyCache, decision: decision}, nil
+		}
+	}
+
+	policies, evalContext, context, err := pdp.prepareEvaluation(request)
+	if err != nil {
+		return nil, err
+	}
+	gated := &gatedRequest{
+		// Skip policies that require resource tags the resource does not carry
+		policies:    pdp.tagFilteredPolicies(policies, evalContext),
+		evalContext: evalContext,
+		context:     context,
+	}
+
+	decision, err = pdp.hooks().runBeforeDecision(request, evalContext)
+	if err != nil {
+		return nil, err
+	}
+	if decision != nil {
+		gated.gate = gateHook
+		gated.decision = decision
+	}
+	return gated, nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_GroupMembership tests ArrayContains on user.groups resolved from nested group membership
+func TestPDP_GroupMembership(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "invoice:approve", ActionName: "invoice:approve"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:invoices:inv-1", ResourceID: "api:invoices:inv-1"})
+	mockStorage.CreateGroup(&models.Group{ID: "finance-approvers"})
+	mockStorage.CreateGroup(&models.Group{ID: "controllers"})
+	mockStorage.AddGroupMember("finance-approvers", models.GroupMemberGroup, "controllers")
+	mockStorage.AddGroupMember("controllers", models.GroupMemberSubject, "user-1")
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-approvers",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:       "FinanceApprovers",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "invoice:approve"},
+					Resource:  models.JSONActionResource{Single: "api:invoices:*"},
+					Condition: map[string]interface{}{"ArrayContains": map[string]interface{}{"user.groups": "finance-approvers"}},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	for subjectID, expected := range map[string]string{"user-1": constants.ResultPermit, "user-2": constants.ResultDeny} {
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "groups-" + subjectID,
+			Subject:    models.NewMockUserSubject(subjectID, subjectID),
+			ResourceID: "api:invoices:inv-1",
+			Action:     "invoice:approve",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != expected {
+			t.Errorf("%s: expected %s, got %s", subjectID, expected, decision.Result)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"abac_go_example/models"
+)
+
+// BeforeEnrichHook runs before attribute enrichment and may adjust the request, e.g. add
+// request.Context values looked up from another system. An error fails the evaluation.
+type BeforeEnrichHook func(request *models.EvaluationRequest) error
+
+// AfterEnrichHook runs once the evaluation context is built and may add or replace context
+// attributes read by policy conditions. An error fails the evaluation.
+type AfterEnrichHook func(request *models.EvaluationRequest, context map[string]interface{}) error
+
+// BeforeDecisionHook runs before policies are evaluated. Returning a decision skips policy
+// evaluation and uses it instead; returning nil continues normally. An error fails the evaluation.
+type BeforeDecisionHook func(request *models.EvaluationRequest, context map[string]interface{}) (*models.Decision, error)
+
+// AfterDecisionHook runs on the decision before it is returned and may change it (override the
+// result, add ReasonDetails for metric tagging). An error fails the evaluation.
+type AfterDecisionHook func(request *models.EvaluationRequest, context map[string]interface{}, decision *models.Decision) error
+
+// Hooks holds user-registered functions called at fixed points of the evaluation pipeline,
+// in registration order. Enrich hooks run for Evaluate, Explain and EvaluateFields; decision
+// hooks run for Evaluate only. Lockdown decisions, access exceptions and replayed cached denies
+// bypass all hooks.
+// Hooks may be registered at any time and must be safe for concurrent use.
+type Hooks struct {
+	mu             sync.RWMutex
+	beforeEnrich   []BeforeEnrichHook
+	afterEnrich    []AfterEnrichHook
+	beforeDecision []BeforeDecisionHook
+	afterDecision  []AfterDecisionHook
+}
+
+// NewHooks creates an empty hook registry
+func NewHooks() *Hooks {
+	return &Hooks{}
+}
+
+// BeforeEnrich registers a hook called before attribute enrichment
+func (h *Hooks) BeforeEnrich(hook BeforeEnrichHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeEnrich = append(h.beforeEnrich, hook)
+}
+
+// AfterEnrich registers a hook called after the evaluation context is built
+func (h *Hooks) AfterEnrich(hook AfterEnrichHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.afterEnrich = append(h.afterEnrich, hook)
+}
+
+// BeforeDecision registers a hook called before policies are evaluated
+func (h *Hooks) BeforeDecision(hook BeforeDecisionHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeDecision = append(h.beforeDecision, hook)
+}
+
+// AfterDecision registers a hook called on every evaluated decision
+func (h *Hooks) AfterDecision(hook AfterDecisionHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.afterDecision = append(h.afterDecision, hook)
+}
+
+// runBeforeEnrich calls the BeforeEnrich hooks; a nil registry has no hooks
+func (h *Hooks) runBeforeEnrich(request *models.EvaluationRequest) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	hooks := h.beforeEnrich
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(request); err != nil {
+			return fmt.Errorf("before enrich hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// runAfterEnrich calls the AfterEnrich hooks
+func (h *Hooks) runAfterEnrich(request *models.EvaluationRequest, context map[string]interface{}) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	hooks := h.afterEnrich
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(request, context); err != nil {
+			return fmt.Errorf("after enrich hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// runBeforeDecision calls the BeforeDecision hooks until one returns a decision
+func (h *Hooks) runBeforeDecision(request *models.EvaluationRequest, context map[string]interface{}) (*models.Decision, error) {
+	if h == nil {
+		return nil, nil
+	}
+	h.mu.RLock()
+	hooks := h.beforeDecision
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		decision, err := hook(request, context)
+		if err != nil {
+			return nil, fmt.Errorf("before decision hook: %w", err)
+		}
+		if decision != nil {
+			return decision, nil
+		}
+	}
+	return nil, nil
+}
+
+// runAfterDecision calls the AfterDecision hooks
+func (h *Hooks) runAfterDecision(request *models.EvaluationRequest, context map[string]interface{}, decision *models.Decision) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	hooks := h.afterDecision
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(request, context, decision); err != nil {
+			return fmt.Errorf("after decision hook: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func hooksTestStorage() *storage.MockStorage {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:plan.pdf", ResourceID: "api:documents:plan.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-region",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "AllowEURegionWithTicket",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"request.region": "eu",
+							"request.ticket": "approved",
+						},
+					},
+				},
+			},
+		},
+	})
+	return mockStorage
+}
+
+func hooksTestRequest(subjectID string) *models.EvaluationRequest {
+	return &models.EvaluationRequest{
+		RequestID:  "hooks-001",
+		Subject:    models.CreateMockSubjectWithAttributes(subjectID, map[string]interface{}{}),
+		ResourceID: "api:documents:plan.pdf",
+		Action:     "document:read",
+		Context:    map[string]interface{}{},
+	}
+}
+
+// TestPDP_Hooks tests custom enrichment, decision overrides and tagging through pipeline hooks
+func TestPDP_Hooks(t *testing.T) {
+	hooks := NewHooks()
+	var order []string
+
+	// Custom enrichment: the region comes from the request, the ticket status from the context
+	hooks.BeforeEnrich(func(request *models.EvaluationRequest) error {
+		order = append(order, "before_enrich")
+		request.Context["region"] = "eu"
+		return nil
+	})
+	hooks.AfterEnrich(func(request *models.EvaluationRequest, context map[string]interface{}) error {
+		order = append(order, "after_enrich")
+		if request.Subject.GetID() != "bob" {
+			context[constants.ContextKeyRequestPrefix+"ticket"] = "approved"
+		}
+		return nil
+	})
+	// Break-glass account decided without evaluating policies
+	hooks.BeforeDecision(func(request *models.EvaluationRequest, context map[string]interface{}) (*models.Decision, error) {
+		order = append(order, "before_decision")
+		if request.Subject.GetID() == "break-glass" {
+			return &models.Decision{Result: constants.ResultPermit, Reason: "Break-glass access", MatchedPolicies: []string{}}, nil
+		}
+		return nil, nil
+	})
+	// Metric tagging
+	hooks.AfterDecision(func(request *models.EvaluationRequest, context map[string]interface{}, decision *models.Decision) error {
+		order = append(order, "after_decision")
+		if decision.ReasonDetails == nil {
+			decision.ReasonDetails = map[string]string{}
+		}
+		decision.ReasonDetails["region"] = context[constants.ContextKeyRequestPrefix+"region"].(string)
+		return nil
+	})
+
+	config := DefaultPDPConfig()
+	config.Hooks = hooks
+	pdp := NewPolicyDecisionPointWithConfig(hooksTestStorage(), config)
+
+	tests := []struct {
+		subject  string
+		expected string
+		reason   string
+	}{
+		{"alice", constants.ResultPermit, "Allowed by statements: AllowEURegionWithTicket"},
+		{"bob", constants.ResultDeny, constants.ReasonImplicitDeny},
+		{"break-glass", constants.ResultPermit, "Break-glass access"},
+	}
+	for _, test := range tests {
+		order = nil
+		decision, err := pdp.Evaluate(hooksTestRequest(test.subject))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.subject, err)
+		}
+		if decision.Result != test.expected || decision.Reason != test.reason {
+			t.Errorf("%s: expected %s (%s), got %s (%s)", test.subject, test.expected, test.reason, decision.Result, decision.Reason)
+		}
+		if decision.ReasonDetails["region"] != "eu" {
+			t.Errorf("%s: expected region tag, got %v", test.subject, decision.ReasonDetails)
+		}
+		if len(order) != 4 || order[0] != "before_enrich" || order[3] != "after_decision" {
+			t.Errorf("%s: unexpected hook order %v", test.subject, order)
+		}
+	}
+
+	// Explain runs the enrich and BeforeDecision hooks, explaining a hook decision like Evaluate returns it
+	order = nil
+	explanation, err := pdp.Explain(hooksTestRequest("break-glass"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(order) != 3 || order[1] != "after_enrich" || order[2] != "before_decision" {
+		t.Errorf("Expected enrich and before decision hooks for Explain, got %v", order)
+	}
+	if explanation.Decision.Result != constants.ResultPermit || explanation.Decision.Reason != "Break-glass access" {
+		t.Errorf("Expected the break-glass hook decision, got %+v", explanation.Decision)
+	}
+}
+
+// TestPDP_HookErrors tests that hook errors fail the evaluation and lockdowns bypass hooks
+func TestPDP_HookErrors(t *testing.T) {
+	hooks := NewHooks()
+	called := false
+	hooks.AfterDecision(func(request *models.EvaluationRequest, context map[string]interface{}, decision *models.Decision) error {
+		called = true
+		decision.Result = constants.ResultPermit
+		return nil
+	})
+	config := DefaultPDPConfig()
+	config.Hooks = hooks
+	pdp := NewPolicyDecisionPointWithConfig(hooksTestStorage(), config)
+
+	// A lockdown cannot be overridden by a hook
+	if err := pdp.SetLockdown(&Lockdown{Mode: LockdownDenyAll, Reason: "incident"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decision, err := pdp.Evaluate(hooksTestRequest("alice"))
+	if err != nil || decision.Result != constants.ResultDeny || called {
+		t.Errorf("Expected lockdown deny without hooks, got %v (%v), hook called=%v", decision, err, called)
+	}
+	if err := pdp.SetLockdown(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hooks.BeforeEnrich(func(request *models.EvaluationRequest) error {
+		return errors.New("directory unavailable")
+	})
+	if _, err := pdp.Evaluate(hooksTestRequest("alice")); err == nil {
+		t.Error("Expected the hook error to fail the evaluation")
+	}
+}
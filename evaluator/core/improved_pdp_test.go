@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"abac_go_example/constants"
 	"abac_go_example/models"
 	"abac_go_example/storage"
 )
@@ -492,3 +493,34 @@ func TestImprovedPDP_IntegrationWithAllFeatures(t *testing.T) {
 	// The decision result shows that all enhanced features work together
 	t.Logf("Comprehensive evaluation completed with result: %s", decision.Result)
 }
+
+// TestImprovedPDP_UserAgentAttributes tests the device, OS and browser attributes parsed from the user agent
+func TestImprovedPDP_UserAgentAttributes(t *testing.T) {
+	pdp := NewPolicyDecisionPoint(storage.NewMockStorage()).(*PolicyDecisionPoint)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "ua-test-001",
+		Subject:    models.NewMockUserSubject("user-456", "user-456"),
+		ResourceID: "/api/financial/reports",
+		Action:     "read",
+		Environment: &models.EnvironmentInfo{
+			UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1",
+		},
+	}
+
+	context := pdp.BuildEnhancedEvaluationContext(request, &models.EvaluationContext{Timestamp: time.Now()})
+
+	expected := map[string]interface{}{
+		constants.ContextKeyDeviceType:                      constants.DeviceTypeMobile,
+		constants.ContextKeyOS:                              constants.OSIOS,
+		constants.ContextKeyOSVersion:                       "17.4.1",
+		constants.ContextKeyBrowser:                         constants.BrowserSafari,
+		constants.ContextKeyBrowserVersion:                  "17.4.1",
+		constants.ContextKeyEnvironmentPrefix + "is_mobile": true,
+	}
+	for key, value := range expected {
+		if context[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, context[key])
+		}
+	}
+}
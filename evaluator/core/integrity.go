@@ -0,0 +1,193 @@
+package core
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"abac_go_example/bundle"
+	"abac_go_example/models"
+)
+
+// ErrIntegrityDisabled is returned by LoadBundle when the PDP has no BundleVerifier
+var ErrIntegrityDisabled = errors.New("policy bundle verification is not enabled")
+
+// IntegrityStatus reports the trusted bundle and the stored policies rejected against it
+type IntegrityStatus struct {
+	BundleHash       string     `json:"bundle_hash,omitempty"`
+	KeyID            string     `json:"key_id,omitempty"`
+	LoadedAt         *time.Time `json:"loaded_at,omitempty"`
+	Policies         int        `json:"policies"`          // Policies in the trusted bundle
+	Rejections       int64      `json:"rejections"`        // Stored policy versions dropped, each counted once
+	RejectedPolicies []string   `json:"rejected_policies"` // IDs dropped by the latest load
+}
+
+// policyIntegrity restricts evaluation to policies whose content digest matches a verified signed
+// bundle. Until a bundle is loaded no policy is trusted, so evaluation fails closed to deny. The enabled
+// flag is not compared, so approvers can still enable and disable bundle policies.
+type policyIntegrity struct {
+	verifier *bundle.Verifier
+
+	mu      sync.RWMutex
+	digests map[string]string // Policy ID -> content digest from the trusted bundle
+	// stored caches the content digests of stored policies by revision and update time, like the
+	// policy compiler, so evaluations do not re-canonicalize every candidate policy
+	stored   map[string]storedDigest
+	hash     string
+	keyID    string
+	loadedAt time.Time
+	// rejected holds the digest of every dropped policy, so each tampered version is logged once
+	rejected   map[string]string
+	lastDrop   []string
+	rejections int64
+}
+
+// storedDigest is the content digest of a stored policy revision
+type storedDigest struct {
+	revision  int64
+	updatedAt time.Time
+	digest    string
+}
+
+func newPolicyIntegrity(verifier *bundle.Verifier) *policyIntegrity {
+	return &policyIntegrity{verifier: verifier, stored: make(map[string]storedDigest), rejected: make(map[string]string)}
+}
+
+// load verifies b and makes it the trusted bundle
+func (pi *policyIntegrity) load(b *bundle.Bundle, now time.Time) error {
+	if _, err := pi.verifier.Verify(b); err != nil {
+		return err
+	}
+	digests := make(map[string]string, len(b.Policies))
+	for _, policy := range b.Policies {
+		digest, err := bundle.PolicyContentDigest(policy)
+		if err != nil {
+			return err
+		}
+		digests[policy.ID] = digest
+	}
+
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	pi.digests = digests
+	pi.hash = b.Hash
+	pi.keyID = b.KeyID
+	pi.loadedAt = now
+	pi.stored = make(map[string]storedDigest)
+	pi.rejected = make(map[string]string)
+	pi.lastDrop = nil
+	return nil
+}
+
+// digest returns the content digest of a stored policy, computed once per revision and update time.
+// Policies without an update time are digested on every call.
+func (pi *policyIntegrity) digest(policy *models.Policy) (string, error) {
+	pi.mu.RLock()
+	cached, exists := pi.stored[policy.ID]
+	pi.mu.RUnlock()
+	if exists && !policy.UpdatedAt.IsZero() && cached.revision == policy.Revision && cached.updatedAt.Equal(policy.UpdatedAt) {
+		return cached.digest, nil
+	}
+
+	digest, err := bundle.PolicyContentDigest(policy)
+	if err != nil {
+		return "", err
+	}
+	pi.mu.Lock()
+	pi.stored[policy.ID] = storedDigest{revision: policy.Revision, updatedAt: policy.UpdatedAt, digest: digest}
+	pi.mu.Unlock()
+	return digest, nil
+}
+
+// filter drops policies that are not in the trusted bundle or whose content changed since signing
+func (pi *policyIntegrity) filter(policies []*models.Policy) []*models.Policy {
+	pi.mu.RLock()
+	digests := pi.digests
+	pi.mu.RUnlock()
+
+	trusted := make([]*models.Policy, 0, len(policies))
+	var dropped []string
+	for _, policy := range policies {
+		digest, err := pi.digest(policy)
+		if err == nil && digests[policy.ID] == digest {
+			trusted = append(trusted, policy)
+			continue
+		}
+		dropped = append(dropped, policy.ID)
+		pi.logRejection(policy.ID, digest, digests[policy.ID] != "")
+	}
+	sort.Strings(dropped)
+
+	pi.mu.Lock()
+	pi.lastDrop = dropped
+	pi.mu.Unlock()
+	return trusted
+}
+
+// logRejection logs and counts a dropped policy the first time its current digest is seen
+func (pi *policyIntegrity) logRejection(policyID, digest string, inBundle bool) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	if seen, exists := pi.rejected[policyID]; exists && seen == digest {
+		return
+	}
+	pi.rejected[policyID] = digest
+	pi.rejections++
+
+	if inBundle {
+		log.Printf("Policy integrity: policy %s does not match the signed bundle (digest %s), ignoring it", policyID, digest)
+	} else {
+		log.Printf("Policy integrity: policy %s is not in the signed bundle, ignoring it", policyID)
+	}
+}
+
+// status returns a snapshot of the integrity state
+func (pi *policyIntegrity) status() *IntegrityStatus {
+	pi.mu.RLock()
+	defer pi.mu.RUnlock()
+
+	status := &IntegrityStatus{
+		BundleHash:       pi.hash,
+		KeyID:            pi.keyID,
+		Policies:         len(pi.digests),
+		Rejections:       pi.rejections,
+		RejectedPolicies: append([]string{}, pi.lastDrop...),
+	}
+	if !pi.loadedAt.IsZero() {
+		loadedAt := pi.loadedAt
+		status.LoadedAt = &loadedAt
+	}
+	return status
+}
+
+// LoadBundle verifies a signed bundle and, when valid, restricts evaluation to its policies.
+// Stored policies missing from the bundle or changed since signing are ignored.
+func (pdp *PolicyDecisionPoint) LoadBundle(b *bundle.Bundle) error {
+	if pdp.integrity == nil {
+		return ErrIntegrityDisabled
+	}
+	if err := pdp.integrity.load(b, pdp.now()); err != nil {
+		return err
+	}
+	// Cached denies were decided against the previous policy set
+	pdp.PurgeDenyCache()
+	return nil
+}
+
+// GetIntegrityStatus returns the trusted bundle state; false when bundle verification is disabled
+func (pdp *PolicyDecisionPoint) GetIntegrityStatus() (*IntegrityStatus, bool) {
+	if pdp.integrity == nil {
+		return nil, false
+	}
+	return pdp.integrity.status(), true
+}
+
+// trustedPolicies applies bundle verification to loaded policies when it is enabled
+func (pdp *PolicyDecisionPoint) trustedPolicies(policies []*models.Policy) []*models.Policy {
+	if pdp.integrity == nil {
+		return policies
+	}
+	return pdp.integrity.filter(policies)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/bundle"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PolicyIntegrity tests that only policies of the verified bundle are evaluated
+func TestPDP_PolicyIntegrity(t *testing.T) {
+	readPolicy := &models.Policy{
+		ID:      "pol-read",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		},
+	}
+
+	// Signed disabled, like a lockdown policy enabled later by a bulk toggle or schedule
+	archivePolicy := &models.Policy{
+		ID:      "pol-archive",
+		Enabled: false,
+		Statement: []models.PolicyStatement{
+			{Sid: "Archive", Effect: "Allow", Action: models.JSONActionResource{Single: "document:archive"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		},
+	}
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateAction(&models.Action{ID: "document:archive", ActionName: "document:archive"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	mockStorage.CreatePolicy(readPolicy)
+	mockStorage.CreatePolicy(archivePolicy)
+
+	publicKey, privateKey, _ := bundle.GenerateKey()
+	signed, err := bundle.NewSigner(privateKey).Sign([]*models.Policy{readPolicy, archivePolicy})
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	config := DefaultPDPConfig()
+	config.BundleVerifier = bundle.NewVerifier(publicKey)
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	evaluate := func(action string) string {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "integrity-" + action,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     action,
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+
+	// Nothing is trusted before a bundle is loaded
+	if result := evaluate("document:read"); result != constants.ResultDeny {
+		t.Errorf("Expected deny without a bundle, got %s", result)
+	}
+
+	if err := pdp.LoadBundle(signed); err != nil {
+		t.Fatalf("LoadBundle failed: %v", err)
+	}
+	if result := evaluate("document:read"); result != constants.ResultPermit {
+		t.Errorf("Expected permit from the signed policy, got %s", result)
+	}
+
+	// Enabling a signed policy keeps it trusted
+	if result := evaluate("document:archive"); result != constants.ResultDeny {
+		t.Errorf("Expected deny while the signed policy is disabled, got %s", result)
+	}
+	toggled, _ := mockStorage.GetPolicy("pol-archive")
+	toggled.Enabled = true
+	if err := mockStorage.UpdatePolicy(toggled); err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+	if result := evaluate("document:archive"); result != constants.ResultPermit {
+		t.Errorf("Expected permit from the enabled signed policy, got %s", result)
+	}
+
+	// A permit-all row injected into the database is ignored
+	mockStorage.CreatePolicy(&models.Policy{
+		ID:      "pol-injected",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{Sid: "All", Effect: "Allow", Action: models.JSONActionResource{Single: "*"}, Resource: models.JSONActionResource{Single: "*"}},
+		},
+	})
+	if result := evaluate("document:delete"); result != constants.ResultDeny {
+		t.Errorf("Expected the injected policy to be ignored, got %s", result)
+	}
+
+	// A signed policy widened in place no longer matches its digest and is dropped
+	tampered, _ := mockStorage.GetPolicy("pol-read")
+	tampered.Statement[0].Action = models.JSONActionResource{Single: "*"}
+	if err := mockStorage.UpdatePolicy(tampered); err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+	if result := evaluate("document:read"); result != constants.ResultDeny {
+		t.Errorf("Expected the tampered policy to be ignored, got %s", result)
+	}
+
+	status, enabled := pdp.GetIntegrityStatus()
+	if !enabled || status.BundleHash != signed.Hash || status.Policies != 2 || status.LoadedAt == nil {
+		t.Fatalf("Unexpected integrity status %+v", status)
+	}
+	// Each rejected policy version is counted once, however often it is evaluated: both signed policies
+	// before the bundle was loaded, then the injected and the tampered policy
+	if len(status.RejectedPolicies) != 2 || status.Rejections != 4 {
+		t.Errorf("Unexpected rejections %+v", status)
+	}
+
+	// A bundle that fails verification keeps the previous trusted set
+	signed.Policies[0].Statement[0].Effect = "Deny"
+	if err := pdp.LoadBundle(signed); err == nil {
+		t.Error("Expected a modified bundle to be rejected")
+	}
+	if status, _ := pdp.GetIntegrityStatus(); status.Policies != 2 {
+		t.Errorf("Expected the trusted bundle to be kept, got %+v", status)
+	}
+
+	if err := NewPolicyDecisionPoint(mockStorage).LoadBundle(signed); err != ErrIntegrityDisabled {
+		t.Errorf("Expected ErrIntegrityDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// PolicyLimits bounds the size and complexity of policies to protect the PDP
+// from pathological policies. A zero value disables the corresponding check.
+type PolicyLimits struct {
+	MaxStatementsPerPolicy int `json:"max_statements_per_policy"`
+	MaxConditionDepth      int `json:"max_condition_depth"` // Nesting depth of And/Or/Not blocks
+	MaxRegexLength         int `json:"max_regex_length"`
+	MaxConditionKeys       int `json:"max_condition_keys"` // Attribute keys across all condition blocks of a statement
+}
+
+// DefaultPolicyLimits returns the default policy limits
+func DefaultPolicyLimits() *PolicyLimits {
+	return &PolicyLimits{
+		MaxStatementsPerPolicy: constants.MaxStatementsPerPolicy,
+		MaxConditionDepth:      constants.MaxConditionDepth,
+		MaxRegexLength:         constants.MaxRegexLength,
+		MaxConditionKeys:       constants.MaxConditionKeys,
+	}
+}
+
+// conditionMetrics describes the complexity of a condition block
+type conditionMetrics struct {
+	depth       int
+	keys        int
+	regexLength int
+}
+
+// Check returns the limit violations of policy, keyed by field path
+func (l *PolicyLimits) Check(policy *models.Policy) []ValidationError {
+	if l == nil || policy == nil {
+		return nil
+	}
+
+	var violations []ValidationError
+
+	if l.MaxStatementsPerPolicy > 0 && len(policy.Statement) > l.MaxStatementsPerPolicy {
+		violations = append(violations, ValidationError{
+			Field:   "statement",
+			Message: fmt.Sprintf("too many statements (max %d)", l.MaxStatementsPerPolicy),
+			Value:   len(policy.Statement),
+		})
+	}
+
+	for i, statement := range policy.Statement {
+		violations = append(violations, l.CheckStatement(statement, fmt.Sprintf("statement[%d]", i))...)
+	}
+
+	return violations
+}
+
+// CheckStatement returns the condition limit violations of a single statement
+func (l *PolicyLimits) CheckStatement(statement models.PolicyStatement, fieldPrefix string) []ValidationError {
+	if l == nil || len(statement.Condition) == 0 {
+		return nil
+	}
+
+	var violations []ValidationError
+	metrics := measureConditions(statement.Condition)
+	field := fieldPrefix + ".condition"
+
+	if l.MaxConditionDepth > 0 && metrics.depth > l.MaxConditionDepth {
+		violations = append(violations, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("condition nesting too deep (max %d)", l.MaxConditionDepth),
+			Value:   metrics.depth,
+		})
+	}
+
+	if l.MaxConditionKeys > 0 && metrics.keys > l.MaxConditionKeys {
+		violations = append(violations, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("too many condition keys (max %d)", l.MaxConditionKeys),
+			Value:   metrics.keys,
+		})
+	}
+
+	if l.MaxRegexLength > 0 && metrics.regexLength > l.MaxRegexLength {
+		violations = append(violations, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("regex pattern too long (max %d)", l.MaxRegexLength),
+			Value:   metrics.regexLength,
+		})
+	}
+
+	return violations
+}
+
+// measureConditions computes nesting depth, key count and longest regex of a condition block
+func measureConditions(conditions map[string]interface{}) conditionMetrics {
+	metrics := conditionMetrics{depth: 1}
+
+	for operator, operatorConditions := range conditions {
+		switch constants.OperatorKey(operator) {
+		case constants.OpAnd, constants.OpOr, constants.OpNot:
+			for _, child := range nestedConditionBlocks(operatorConditions) {
+				childMetrics := measureConditions(child)
+				metrics.depth = max(metrics.depth, childMetrics.depth+1)
+				metrics.keys += childMetrics.keys
+				metrics.regexLength = max(metrics.regexLength, childMetrics.regexLength)
+			}
+		default:
+			condMap, ok := operatorConditions.(map[string]interface{})
+			if !ok {
+				metrics.keys++
+				continue
+			}
+			metrics.keys += len(condMap)
+
+			if constants.OperatorKey(operator) == constants.OpStringRegex {
+				for _, pattern := range condMap {
+					metrics.regexLength = max(metrics.regexLength, len(fmt.Sprintf("%v", pattern)))
+				}
+			}
+		}
+	}
+
+	return metrics
+}
+
+// nestedConditionBlocks returns the condition maps nested under a logical operator
+func nestedConditionBlocks(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		blocks := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if block, ok := item.(map[string]interface{}); ok {
+				blocks = append(blocks, block)
+			}
+		}
+		return blocks
+	}
+	return nil
+}
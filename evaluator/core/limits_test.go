@@ -0,0 +1,184 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// nestedAnd builds a condition block nested depth levels deep with And operators
+func nestedAnd(depth int) map[string]interface{} {
+	block := map[string]interface{}{
+		"StringEquals": map[string]interface{}{"user.department": "Engineering"},
+	}
+	for i := 1; i < depth; i++ {
+		block = map[string]interface{}{"And": []interface{}{block}}
+	}
+	return block
+}
+
+func TestPolicyLimitsCheck(t *testing.T) {
+	limits := &PolicyLimits{
+		MaxStatementsPerPolicy: 2,
+		MaxConditionDepth:      3,
+		MaxRegexLength:         10,
+		MaxConditionKeys:       2,
+	}
+
+	tests := []struct {
+		name      string
+		policy    *models.Policy
+		violation string
+	}{
+		{
+			name: "within limits",
+			policy: &models.Policy{Statement: []models.PolicyStatement{
+				{Condition: nestedAnd(3)},
+			}},
+		},
+		{
+			name: "too many statements",
+			policy: &models.Policy{Statement: []models.PolicyStatement{
+				{}, {}, {},
+			}},
+			violation: "too many statements",
+		},
+		{
+			name: "too deep",
+			policy: &models.Policy{Statement: []models.PolicyStatement{
+				{Condition: nestedAnd(4)},
+			}},
+			violation: "nesting too deep",
+		},
+		{
+			name: "too many keys",
+			policy: &models.Policy{Statement: []models.PolicyStatement{
+				{Condition: map[string]interface{}{
+					"StringEquals": map[string]interface{}{"a": "1", "b": "2"},
+					"Bool":         map[string]interface{}{"c": true},
+				}},
+			}},
+			violation: "too many condition keys",
+		},
+		{
+			name: "regex too long",
+			policy: &models.Policy{Statement: []models.PolicyStatement{
+				{Condition: map[string]interface{}{
+					"StringRegex": map[string]interface{}{"user.email": "^(a+)+@example\\.com$"},
+				}},
+			}},
+			violation: "regex pattern too long",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := limits.Check(tt.policy)
+			if tt.violation == "" {
+				if len(violations) != 0 {
+					t.Errorf("Expected no violations, got %v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || !strings.Contains(violations[0].Message, tt.violation) {
+				t.Errorf("Expected violation %q, got %v", tt.violation, violations)
+			}
+		})
+	}
+}
+
+func TestPolicyValidatorEnforcesLimits(t *testing.T) {
+	validator := NewPolicyValidator()
+	validator.SetLimits(&PolicyLimits{MaxStatementsPerPolicy: 1})
+
+	statement := models.PolicyStatement{
+		Effect:   "Allow",
+		Action:   models.JSONActionResource{Single: "document:read"},
+		Resource: models.JSONActionResource{Single: "api:documents:*"},
+	}
+	policy := &models.Policy{
+		ID:         "pol-001",
+		PolicyName: "Too many statements",
+		Version:    "2024-10-21",
+		Statement:  []models.PolicyStatement{statement, statement},
+	}
+
+	err := validator.ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "too many statements") {
+		t.Errorf("Expected statement limit error, got %v", err)
+	}
+
+	validator.SetLimits(nil)
+	if err := validator.ValidatePolicy(policy); err != nil {
+		t.Errorf("Expected no error with limits disabled, got %v", err)
+	}
+
+	// Regex patterns must compile within the regex limits
+	statement.Condition = map[string]interface{}{"StringRegex": map[string]interface{}{"user.email": `(\w{1,30}){1,30}`}}
+	policy.Statement = []models.PolicyStatement{statement}
+	err = validator.ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "invalid regex: pattern too complex") {
+		t.Errorf("Expected regex complexity error, got %v", err)
+	}
+}
+
+// TestPDP_LimitExceededStatementsFailClosed tests that over-limit statements never grant access but still deny
+func TestPDP_LimitExceededStatementsFailClosed(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:test.pdf",
+		ResourceID: "api:documents:test.pdf",
+	})
+
+	allow := models.PolicyStatement{
+		Sid:       "DeepAllow",
+		Effect:    "Allow",
+		Action:    models.JSONActionResource{Single: "document:read"},
+		Resource:  models.JSONActionResource{Single: "api:documents:*"},
+		Condition: nestedAnd(5),
+	}
+	mockStorage.SetPolicies([]*models.Policy{{ID: "pol-allow", Enabled: true, Statement: []models.PolicyStatement{allow}}})
+
+	config := DefaultPDPConfig()
+	config.Limits = &PolicyLimits{MaxConditionDepth: 3}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID: "limits-001",
+		Subject: models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{
+			"department": "Engineering",
+		}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != "deny" {
+		t.Errorf("Expected over-limit Allow statement not to grant access, got %s", decision.Result)
+	}
+
+	deny := allow
+	deny.Sid = "DeepDeny"
+	deny.Effect = "Deny"
+	simpleAllow := allow
+	simpleAllow.Sid = "SimpleAllow"
+	simpleAllow.Condition = nil
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-allow", Enabled: true, Statement: []models.PolicyStatement{simpleAllow}},
+		{ID: "pol-deny", Enabled: true, Statement: []models.PolicyStatement{deny}},
+	})
+
+	decision, err = pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != "deny" || decision.ReasonDetails["statement"] != "DeepDeny" {
+		t.Errorf("Expected over-limit Deny statement to deny, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
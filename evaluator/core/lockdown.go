@@ -0,0 +1,122 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// Lockdown modes
+const (
+	LockdownDenyAll  = "deny_all" // Every request is denied
+	LockdownSafelist = "safelist" // Only SafeActions are evaluated; everything else is denied
+)
+
+// Lockdown is the operational kill switch for incident response. While set, requests are
+// denied before policy evaluation, except constants.ActionLockdownManage (so the lockdown
+// can be lifted) and, in safelist mode, actions matching SafeActions.
+type Lockdown struct {
+	Mode        string    `json:"mode"`
+	SafeActions []string  `json:"safe_actions,omitempty"` // Action patterns, e.g. "document-service:*:read"
+	Reason      string    `json:"reason,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+	Since       time.Time `json:"since"`
+}
+
+// Validate checks the mode and safelist
+func (l *Lockdown) Validate() error {
+	switch l.Mode {
+	case LockdownDenyAll:
+		if len(l.SafeActions) > 0 {
+			return fmt.Errorf("safe_actions are only allowed in %s mode", LockdownSafelist)
+		}
+	case LockdownSafelist:
+		if len(l.SafeActions) == 0 {
+			return fmt.Errorf("%s mode requires safe_actions", LockdownSafelist)
+		}
+		for _, action := range l.SafeActions {
+			if strings.TrimSpace(action) == "" {
+				return fmt.Errorf("safe_actions cannot contain empty actions")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown lockdown mode %q (expected %s or %s)", l.Mode, LockdownDenyAll, LockdownSafelist)
+	}
+	return nil
+}
+
+// LockdownFromEnv reads ABAC_LOCKDOWN ("deny_all" or "safelist") and ABAC_LOCKDOWN_SAFE_ACTIONS
+// (comma-separated action patterns). It returns nil (no lockdown) when ABAC_LOCKDOWN is unset.
+func LockdownFromEnv() (*Lockdown, error) {
+	mode := strings.TrimSpace(os.Getenv(constants.EnvLockdown))
+	if mode == "" {
+		return nil, nil
+	}
+
+	lockdown := &Lockdown{Mode: mode, Reason: "configured by " + constants.EnvLockdown, Actor: "config", Since: time.Now().UTC()}
+	for _, action := range strings.Split(os.Getenv(constants.EnvLockdownSafeActions), ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			lockdown.SafeActions = append(lockdown.SafeActions, action)
+		}
+	}
+	if err := lockdown.Validate(); err != nil {
+		return nil, err
+	}
+	return lockdown, nil
+}
+
+// SetLockdown puts the PDP into lockdown immediately; nil lifts it
+func (pdp *PolicyDecisionPoint) SetLockdown(lockdown *Lockdown) error {
+	if lockdown == nil {
+		pdp.lockdown.Store(nil)
+		return nil
+	}
+	if err := lockdown.Validate(); err != nil {
+		return err
+	}
+
+	state := *lockdown
+	state.SafeActions = append([]string(nil), lockdown.SafeActions...)
+	if state.Since.IsZero() {
+		state.Since = pdp.now()
+	}
+	pdp.lockdown.Store(&state)
+	return nil
+}
+
+// GetLockdown returns the active lockdown, or nil
+func (pdp *PolicyDecisionPoint) GetLockdown() *Lockdown {
+	lockdown := pdp.lockdown.Load()
+	if lockdown == nil {
+		return nil
+	}
+	state := *lockdown
+	return &state
+}
+
+// lockdownDecision returns the deny for a request blocked by the active lockdown
+func (pdp *PolicyDecisionPoint) lockdownDecision(request *models.EvaluationRequest) (*models.Decision, bool) {
+	lockdown := pdp.lockdown.Load()
+	if lockdown == nil || request == nil || request.Action == constants.ActionLockdownManage {
+		return nil, false
+	}
+	if lockdown.Mode == LockdownSafelist {
+		for _, pattern := range lockdown.SafeActions {
+			if pdp.actionMatcher.Match(pattern, request.Action) {
+				return nil, false
+			}
+		}
+	}
+
+	return &models.Decision{
+		Result:          constants.ResultDeny,
+		MatchedPolicies: []string{},
+		Reason:          fmt.Sprintf(constants.ReasonLockdown, lockdown.Mode),
+		ReasonCode:      constants.ReasonCodeLockdown,
+		ReasonDetails:   map[string]string{constants.ReasonDetailLockdownMode: lockdown.Mode},
+	}, true
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_Lockdown tests that a lockdown denies requests before evaluation, except safelisted actions
+func TestPDP_Lockdown(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	for _, action := range []string{"document:read", "document:delete", constants.ActionLockdownManage} {
+		mockStorage.CreateAction(&models.Action{ID: action, ActionName: action})
+	}
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-all",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "AllowAll", Effect: "Allow", Action: models.JSONActionResource{Single: "*"}, Resource: models.JSONActionResource{Single: "*"}},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	evaluate := func(action string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "lockdown-" + action,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     action,
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+
+	if err := pdp.SetLockdown(&Lockdown{Mode: LockdownDenyAll, Reason: "incident-42", Actor: "oncall"}); err != nil {
+		t.Fatalf("SetLockdown failed: %v", err)
+	}
+	decision := evaluate("document:read")
+	if decision.Result != constants.ResultDeny || decision.ReasonCode != constants.ReasonCodeLockdown ||
+		decision.ReasonDetails[constants.ReasonDetailLockdownMode] != LockdownDenyAll {
+		t.Errorf("Expected a lockdown deny, got %+v", decision)
+	}
+	// Lockdown management stays reachable so the lockdown can be lifted
+	if result := evaluate(constants.ActionLockdownManage).Result; result != constants.ResultPermit {
+		t.Errorf("Expected %s to be evaluated normally, got %s", constants.ActionLockdownManage, result)
+	}
+	if lockdown := pdp.GetLockdown(); lockdown == nil || lockdown.Since.IsZero() || lockdown.Actor != "oncall" {
+		t.Errorf("Unexpected lockdown state %+v", lockdown)
+	}
+
+	// Safelisted actions are evaluated, everything else is denied
+	pdp.SetLockdown(&Lockdown{Mode: LockdownSafelist, SafeActions: []string{"document:read"}})
+	if result := evaluate("document:read").Result; result != constants.ResultPermit {
+		t.Errorf("Expected safelisted read to be permitted, got %s", result)
+	}
+	if decision := evaluate("document:delete"); decision.ReasonCode != constants.ReasonCodeLockdown {
+		t.Errorf("Expected delete to be denied by lockdown, got %+v", decision)
+	}
+
+	explanation, err := pdp.Explain(&models.EvaluationRequest{
+		RequestID:  "lockdown-explain",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:doc-1",
+		Action:     "document:delete",
+	})
+	if err != nil || explanation.Decision.ReasonCode != constants.ReasonCodeLockdown || len(explanation.Statements) != 0 {
+		t.Errorf("Expected Explain to report the lockdown, got %+v (err=%v)", explanation, err)
+	}
+
+	pdp.SetLockdown(nil)
+	if result := evaluate("document:delete").Result; result != constants.ResultPermit || pdp.GetLockdown() != nil {
+		t.Errorf("Expected normal evaluation after lifting, got %s", result)
+	}
+}
+
+func TestLockdown_Validate(t *testing.T) {
+	invalid := []*Lockdown{
+		{Mode: "panic"},
+		{Mode: LockdownSafelist},
+		{Mode: LockdownSafelist, SafeActions: []string{" "}},
+		{Mode: LockdownDenyAll, SafeActions: []string{"document:read"}},
+	}
+	for _, lockdown := range invalid {
+		if err := lockdown.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", lockdown)
+		}
+	}
+}
+
+func TestLockdownFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvLockdown, "")
+	if lockdown, err := LockdownFromEnv(); lockdown != nil || err != nil {
+		t.Errorf("Expected no lockdown when unset, got %+v (err=%v)", lockdown, err)
+	}
+
+	t.Setenv(constants.EnvLockdown, LockdownSafelist)
+	t.Setenv(constants.EnvLockdownSafeActions, "document:read, health:check")
+	lockdown, err := LockdownFromEnv()
+	if err != nil || lockdown.Mode != LockdownSafelist || len(lockdown.SafeActions) != 2 || lockdown.SafeActions[1] != "health:check" {
+		t.Errorf("Unexpected lockdown %+v (err=%v)", lockdown, err)
+	}
+
+	t.Setenv(constants.EnvLockdownSafeActions, "")
+	if _, err := LockdownFromEnv(); err == nil {
+		t.Error("Expected safelist mode without actions to be rejected")
+	}
+}
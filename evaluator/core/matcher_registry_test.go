@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_CustomMatchers tests that registered matchers evaluate resource naming schemes beyond colon/glob patterns
+func TestPDP_CustomMatchers(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "s3:GetObject", ActionName: "s3:GetObject"})
+	for _, resource := range []string{"arn:aws:s3:::reports/2024/q1.csv", "arn:aws:s3:::invoices/q1.csv"} {
+		mockStorage.CreateResource(&models.Resource{ID: resource, ResourceID: resource})
+	}
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-reports",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "ReadReports", Effect: "Allow", Action: models.JSONActionResource{Single: "s3:GetObject"}, Resource: models.JSONActionResource{Single: "arn:aws:s3:::reports/*"}},
+			},
+		},
+	})
+
+	evaluate := func(pdp PolicyDecisionPointInterface, resource string) string {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "arn-" + resource,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: resource,
+			Action:     "s3:GetObject",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+
+	// The built-in matcher rejects ARNs: their empty region/account segments are not a valid resource format
+	if result := evaluate(NewPolicyDecisionPoint(mockStorage), "arn:aws:s3:::reports/2024/q1.csv"); result != constants.ResultDeny {
+		t.Errorf("Expected the built-in matcher to deny, got %s", result)
+	}
+
+	config := DefaultPDPConfig()
+	config.Matchers = matchers.NewMatcherRegistry()
+	config.Matchers.RegisterResourceMatcher("arn:", matchers.NewARNMatcher())
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+	if result := evaluate(pdp, "arn:aws:s3:::reports/2024/q1.csv"); result != constants.ResultPermit {
+		t.Errorf("Expected the ARN matcher to permit, got %s", result)
+	}
+	if result := evaluate(pdp, "arn:aws:s3:::invoices/q1.csv"); result != constants.ResultDeny {
+		t.Errorf("Expected other buckets to be denied, got %s", result)
+	}
+}
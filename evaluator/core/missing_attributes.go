@@ -0,0 +1,168 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/path"
+	"abac_go_example/models"
+)
+
+// MissingAttributeConfig configures missing attribute telemetry
+type MissingAttributeConfig struct {
+	// LogInterval rate-limits the warning logged for each policy/statement/path: at most one per interval,
+	// reporting the occurrences since the previous one. Zero only counts.
+	LogInterval time.Duration `json:"log_interval"`
+}
+
+// MissingAttributeConfigFromEnv reads ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL. It returns nil (telemetry disabled)
+// when the variable is unset; "0" counts missing attributes without logging warnings.
+func MissingAttributeConfigFromEnv() (*MissingAttributeConfig, error) {
+	value := os.Getenv(constants.EnvMissingAttributeLogInterval)
+	if value == "" {
+		return nil, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return nil, fmt.Errorf("invalid %s %q: expected a duration such as \"1m\"", constants.EnvMissingAttributeLogInterval, value)
+	}
+	return &MissingAttributeConfig{LogInterval: interval}, nil
+}
+
+// MissingAttributeLabels identifies a condition attribute path of a policy statement
+type MissingAttributeLabels struct {
+	PolicyID  string `json:"policy_id"`
+	Statement string `json:"statement"` // Statement Sid, or "#<index>" when Sid is empty
+	Path      string `json:"path"`
+}
+
+// MissingAttributeCount is the number of evaluations in which a condition path resolved to nothing
+type MissingAttributeCount struct {
+	MissingAttributeLabels
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// missingAttributeEntry holds the counter and warning state of a label set
+type missingAttributeEntry struct {
+	count      int64
+	lastSeen   time.Time
+	lastLogged time.Time
+	unlogged   int64 // Occurrences since the last warning
+}
+
+// MissingAttributeMonitor counts condition attribute paths that resolve to nothing in the evaluation
+// context and logs rate-limited warnings, surfacing misconfigured policies and broken enrichment.
+// It is safe for concurrent use.
+type MissingAttributeMonitor struct {
+	mu       sync.Mutex
+	config   MissingAttributeConfig
+	clock    clock.Clock
+	resolver path.PathResolver
+	entries  map[MissingAttributeLabels]*missingAttributeEntry
+}
+
+// NewMissingAttributeMonitor creates a monitor; a nil clock uses the system clock
+func NewMissingAttributeMonitor(config *MissingAttributeConfig, c clock.Clock) *MissingAttributeMonitor {
+	monitor := &MissingAttributeMonitor{
+		clock:    clock.OrReal(c),
+		resolver: path.NewCompositePathResolver(),
+		entries:  make(map[MissingAttributeLabels]*missingAttributeEntry),
+	}
+	if config != nil {
+		monitor.config = *config
+	}
+	return monitor
+}
+
+// RecordPolicy checks the condition paths of the statements of policy whose conditions were evaluated
+func (m *MissingAttributeMonitor) RecordPolicy(policy *models.Policy, results []StatementResult, context map[string]interface{}) {
+	var missing []MissingAttributeLabels
+	for _, result := range results {
+		if !result.ConditionsEvaluated || result.Index >= len(policy.Statement) {
+			continue
+		}
+		statement := policy.Statement[result.Index]
+		sid := statement.Sid
+		if sid == "" {
+			sid = fmt.Sprintf("#%d", result.Index)
+		}
+		for _, leaf := range conditionLeaves(statement.Condition) {
+			if leaf.key == "" {
+				continue
+			}
+			if _, found := m.resolver.Resolve(leaf.key, context); !found {
+				missing = append(missing, MissingAttributeLabels{PolicyID: policy.ID, Statement: sid, Path: leaf.key})
+			}
+		}
+	}
+	for _, labels := range missing {
+		m.record(labels)
+	}
+}
+
+// record increments the counter of labels and logs a warning when the interval has elapsed
+func (m *MissingAttributeMonitor) record(labels MissingAttributeLabels) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	entry, exists := m.entries[labels]
+	if !exists {
+		entry = &missingAttributeEntry{}
+		m.entries[labels] = entry
+	}
+	entry.count++
+	entry.unlogged++
+	entry.lastSeen = now
+
+	shouldLog := m.config.LogInterval > 0 && (entry.lastLogged.IsZero() || now.Sub(entry.lastLogged) >= m.config.LogInterval)
+	occurrences, total := entry.unlogged, entry.count
+	if shouldLog {
+		entry.lastLogged = now
+		entry.unlogged = 0
+	}
+	m.mu.Unlock()
+
+	if shouldLog {
+		log.Printf("Warning: missing attribute policy_id=%s statement=%s path=%s occurrences=%d total=%d",
+			labels.PolicyID, labels.Statement, labels.Path, occurrences, total)
+	}
+}
+
+// Counts returns the missing attribute counters, highest count first
+func (m *MissingAttributeMonitor) Counts() []MissingAttributeCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make([]MissingAttributeCount, 0, len(m.entries))
+	for labels, entry := range m.entries {
+		counts = append(counts, MissingAttributeCount{MissingAttributeLabels: labels, Count: entry.count, LastSeen: entry.lastSeen})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].PolicyID != counts[j].PolicyID {
+			return counts[i].PolicyID < counts[j].PolicyID
+		}
+		if counts[i].Statement != counts[j].Statement {
+			return counts[i].Statement < counts[j].Statement
+		}
+		return counts[i].Path < counts[j].Path
+	})
+	return counts
+}
+
+// Reset clears all counters
+func (m *MissingAttributeMonitor) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[MissingAttributeLabels]*missingAttributeEntry)
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_MissingAttributes tests labeled counters and rate-limited warnings for unresolved condition paths
+func TestPDP_MissingAttributes(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-001",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department":  "Engineering",
+							"user.cost_centre": "CC-42",
+						},
+					},
+				},
+				{
+					// Never reaches condition evaluation: its missing path is not counted
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "report:write"},
+					Resource:  models.JSONActionResource{Single: "api:reports:*"},
+					Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.region": "EU"}},
+				},
+			},
+		},
+	})
+
+	mockClock := clock.NewMockClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	config.MissingAttributes = &MissingAttributeConfig{LogInterval: time.Minute}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	var logs bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(original)
+
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{"department": "Engineering"}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	}
+	evaluate := func() {
+		t.Helper()
+		if _, err := pdp.Evaluate(request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	evaluate()
+	evaluate()
+	mockClock.Advance(time.Minute)
+	evaluate()
+
+	counts, enabled := pdp.GetMissingAttributes()
+	if !enabled {
+		t.Fatal("Expected missing attribute telemetry to be enabled")
+	}
+	if len(counts) != 1 {
+		t.Fatalf("Expected only user.cost_centre to be missing, got %+v", counts)
+	}
+	expected := MissingAttributeLabels{PolicyID: "pol-001", Statement: "EngineeringRead", Path: "user.cost_centre"}
+	if counts[0].MissingAttributeLabels != expected || counts[0].Count != 3 {
+		t.Errorf("Unexpected counter: %+v", counts[0])
+	}
+
+	warnings := strings.Count(logs.String(), "missing attribute policy_id=pol-001 statement=EngineeringRead path=user.cost_centre")
+	if warnings != 2 {
+		t.Errorf("Expected one warning per interval (2), got %d:\n%s", warnings, logs.String())
+	}
+	if !strings.Contains(logs.String(), "occurrences=2 total=3") {
+		t.Errorf("Expected the second warning to report suppressed occurrences:\n%s", logs.String())
+	}
+}
+
+func TestMissingAttributeConfigFromEnv(t *testing.T) {
+	t.Setenv("ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL", "")
+	if config, err := MissingAttributeConfigFromEnv(); config != nil || err != nil {
+		t.Errorf("Expected disabled by default, got %+v %v", config, err)
+	}
+	t.Setenv("ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL", "0")
+	if config, err := MissingAttributeConfigFromEnv(); err != nil || config == nil || config.LogInterval != 0 {
+		t.Errorf("Expected counters without warnings, got %+v %v", config, err)
+	}
+	t.Setenv("ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL", "soon")
+	if _, err := MissingAttributeConfigFromEnv(); err == nil {
+		t.Error("Expected error for invalid interval")
+	}
+}
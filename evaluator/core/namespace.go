@@ -0,0 +1,23 @@
+package core
+
+import (
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// evaluationNamespace returns the namespace a request is evaluated in: its own, else the configured default.
+// Only global policies and policies of that namespace are evaluated; an empty namespace evaluates global ones only.
+func (pdp *PolicyDecisionPoint) evaluationNamespace(request *models.EvaluationRequest) string {
+	if request.Namespace != "" {
+		return request.Namespace
+	}
+	if pdp.config != nil {
+		return pdp.config.Namespace
+	}
+	return ""
+}
+
+// namespacePolicies loads the stored policies that apply to the namespace of request
+func (pdp *PolicyDecisionPoint) namespacePolicies(request *models.EvaluationRequest) ([]*models.Policy, error) {
+	return storage.GetNamespacePolicies(pdp.storage, pdp.evaluationNamespace(request))
+}
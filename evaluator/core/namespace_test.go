@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_Namespaces tests that only global policies and those of the evaluation namespace are evaluated
+func TestPDP_Namespaces(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	allowRead := []models.PolicyStatement{
+		{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+	}
+	denyRead := []models.PolicyStatement{
+		{Sid: "DenyRead", Effect: "Deny", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+	}
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-documents-allow", Namespace: "document-service", Enabled: true, Statement: allowRead},
+		{ID: "pol-payments-deny", Namespace: "payment-service", Enabled: true, Statement: denyRead},
+	})
+
+	config := DefaultPDPConfig()
+	config.DenyCache = DefaultDenyCacheConfig()
+	config.Namespace = "document-service"
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	evaluate := func(namespace string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "namespace-" + namespace,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+			Namespace:  namespace,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+
+	// The configured namespace applies; the payment-service deny is never evaluated
+	if decision := evaluate(""); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit in document-service, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// The request namespace overrides the default; the cached deny is not replayed to other namespaces
+	decision := evaluate("payment-service")
+	if decision.Result != constants.ResultDeny || len(decision.MatchedPolicies) != 1 || decision.MatchedPolicies[0] != "pol-payments-deny" {
+		t.Errorf("Expected explicit deny in payment-service, got %s (%v)", decision.Result, decision.MatchedPolicies)
+	}
+	if decision := evaluate("document-service"); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit in document-service after a payment-service deny, got %s", decision.Result)
+	}
+
+	// Global policies apply in every namespace
+	mockStorage.CreatePolicy(&models.Policy{ID: "pol-global-deny", Enabled: true, Statement: denyRead})
+	if decision := evaluate("document-service"); decision.Result != constants.ResultDeny {
+		t.Errorf("Expected the global deny to apply in document-service, got %s", decision.Result)
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// auditLevelRank orders audit levels by verbosity
+var auditLevelRank = map[string]int{
+	constants.AuditLevelMinimal:  1,
+	constants.AuditLevelStandard: 2,
+	constants.AuditLevelFull:     3,
+}
+
+// knownObligations maps every supported obligation to its allowed values
+var knownObligations = map[string]map[string]int{
+	constants.ObligationAudit: auditLevelRank,
+}
+
+// decisionObligations merges the obligations of the statements that decided a result. When
+// statements disagree on the audit level the most verbose one wins, so forensic needs of one
+// policy are never silenced by another; for other obligations the first statement wins.
+func decisionObligations(statements []models.PolicyStatement) map[string]string {
+	var obligations map[string]string
+	for _, statement := range statements {
+		for name, value := range statement.Obligations {
+			if obligations == nil {
+				obligations = make(map[string]string)
+			}
+			current, exists := obligations[name]
+			switch {
+			case !exists:
+				obligations[name] = value
+			case name == constants.ObligationAudit && auditLevelRank[value] > auditLevelRank[current]:
+				obligations[name] = value
+			}
+		}
+	}
+	return obligations
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestDecisionObligations(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:plan.pdf", ResourceID: "api:documents:plan.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-read",
+			Enabled: true,
+			Statement: []models.PolicyStatement{{
+				Sid:         "ReadDocuments",
+				Effect:      "Allow",
+				Action:      models.JSONActionResource{Single: "document:read"},
+				Resource:    models.JSONActionResource{Single: "api:documents:*"},
+				Obligations: map[string]string{constants.ObligationAudit: constants.AuditLevelMinimal},
+			}},
+		},
+		{
+			ID:      "pol-confidential",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:         "ReadConfidential",
+					Effect:      "Allow",
+					Action:      models.JSONActionResource{Single: "document:read"},
+					Resource:    models.JSONActionResource{Single: "api:documents:*"},
+					Condition:   map[string]interface{}{"StringEquals": map[string]interface{}{"resource.classification": "confidential"}},
+					Obligations: map[string]string{constants.ObligationAudit: constants.AuditLevelFull},
+				},
+				{
+					Sid:         "NoDeletes",
+					Effect:      "Deny",
+					Action:      models.JSONActionResource{Single: "document:delete"},
+					Resource:    models.JSONActionResource{Single: "api:documents:*"},
+					Obligations: map[string]string{constants.ObligationAudit: constants.AuditLevelStandard},
+				},
+			},
+		},
+	})
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	evaluate := func(action, classification string) *models.Decision {
+		t.Helper()
+		request := hooksTestRequest("user-1")
+		request.Action = action
+		request.Context = map[string]interface{}{
+			constants.ContextKeyResourceAttributes: map[string]interface{}{"classification": classification},
+		}
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		return decision
+	}
+
+	if decision := evaluate("document:read", "public"); decision.Obligations[constants.ObligationAudit] != constants.AuditLevelMinimal {
+		t.Errorf("Expected minimal auditing of public reads, got %v", decision.Obligations)
+	}
+	// Both Allow statements match; the most verbose audit level wins
+	if decision := evaluate("document:read", "confidential"); decision.Obligations[constants.ObligationAudit] != constants.AuditLevelFull {
+		t.Errorf("Expected full auditing of confidential reads, got %v", decision.Obligations)
+	}
+	if decision := evaluate("document:delete", "public"); decision.Result != constants.ResultDeny ||
+		decision.Obligations[constants.ObligationAudit] != constants.AuditLevelStandard {
+		t.Errorf("Expected the deny statement's obligation, got %v", decision)
+	}
+}
+
+func TestValidatePolicyObligations(t *testing.T) {
+	policy := &models.Policy{
+		ID:         "pol-obligations",
+		PolicyName: "Obligations",
+		Version:    "1",
+		Statement: []models.PolicyStatement{{
+			Sid:         "Read",
+			Effect:      "Allow",
+			Action:      models.JSONActionResource{Single: "document:read"},
+			Resource:    models.JSONActionResource{Single: "api:documents:*"},
+			Obligations: map[string]string{constants.ObligationAudit: constants.AuditLevelFull},
+		}},
+	}
+	if err := NewPolicyValidator().ValidatePolicy(policy); err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	policy.Statement[0].Obligations = map[string]string{constants.ObligationAudit: "verbose", "notify": "secops"}
+	err := NewPolicyValidator().ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "unsupported obligation value") || !strings.Contains(err.Error(), "unknown obligation") {
+		t.Errorf("Expected unknown obligations and levels to be rejected, got %v", err)
+	}
+}
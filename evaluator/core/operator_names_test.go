@@ -0,0 +1,45 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestPolicyValidatorOperatorSpellings(t *testing.T) {
+	validator := NewPolicyValidator()
+	policyWith := func(condition map[string]interface{}) *models.Policy {
+		return &models.Policy{
+			ID:         "pol-001",
+			PolicyName: "Operators",
+			Version:    "2024-10-21",
+			Statement: []models.PolicyStatement{{
+				Effect:    "Allow",
+				Action:    models.JSONActionResource{Single: "document:read"},
+				Resource:  models.JSONActionResource{Single: "*"},
+				Condition: condition,
+			}},
+		}
+	}
+
+	// Case variants and evaluator-only operators are accepted and validated like the canonical name
+	valid := policyWith(map[string]interface{}{
+		"stringequals":  map[string]interface{}{"user.department": "Engineering"},
+		"ArrayContains": map[string]interface{}{"user.roles": "admin"},
+		"IPInRange":     map[string]interface{}{"environment.client_ip": []interface{}{"10.0.0.0/8"}},
+	})
+	if err := validator.ValidatePolicy(valid); err != nil {
+		t.Errorf("Expected valid policy, got %v", err)
+	}
+	invalid := policyWith(map[string]interface{}{"numericlessthan": map[string]interface{}{"user.level": "high"}})
+	if err := validator.ValidatePolicy(invalid); err == nil || !strings.Contains(err.Error(), "numeric") {
+		t.Errorf("Expected numeric validation error for a lowercase operator, got %v", err)
+	}
+
+	// IpAddress is not evaluated, so accepting it would silently ignore the condition
+	ignored := policyWith(map[string]interface{}{"IpAddress": map[string]interface{}{"request:SourceIp": "10.0.0.0/8"}})
+	if err := validator.ValidatePolicy(ignored); err == nil || !strings.Contains(err.Error(), "unknown condition operator") {
+		t.Errorf("Expected unknown operator error for IpAddress, got %v", err)
+	}
+}
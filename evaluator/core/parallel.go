@@ -0,0 +1,109 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// ParallelConfig configures concurrent evaluation of large candidate policy sets
+type ParallelConfig struct {
+	Workers     int `json:"workers"`      // Policies evaluated concurrently; 1 or less evaluates sequentially
+	MinPolicies int `json:"min_policies"` // Candidate policies (after pre-filtering) below which evaluation stays sequential
+}
+
+// DefaultParallelConfig returns one worker per CPU for candidate sets of DefaultParallelMinPolicies or more
+func DefaultParallelConfig() *ParallelConfig {
+	return &ParallelConfig{
+		Workers:     runtime.GOMAXPROCS(0),
+		MinPolicies: constants.DefaultParallelMinPolicies,
+	}
+}
+
+// ParallelConfigFromEnv reads ABAC_EVAL_PARALLELISM and ABAC_EVAL_PARALLEL_MIN_POLICIES.
+// It returns nil (sequential evaluation) when the parallelism is unset, invalid or 1 or less.
+func ParallelConfigFromEnv() *ParallelConfig {
+	workers, err := strconv.Atoi(os.Getenv(constants.EnvEvalParallelism))
+	if err != nil || workers <= 1 {
+		return nil
+	}
+
+	config := DefaultParallelConfig()
+	config.Workers = workers
+	if minPolicies, err := strconv.Atoi(os.Getenv(constants.EnvEvalParallelMinPolicies)); err == nil && minPolicies > 0 {
+		config.MinPolicies = minPolicies
+	}
+	return config
+}
+
+// parallelEnabled reports whether a candidate set of count policies is evaluated concurrently
+func (pdp *PolicyDecisionPoint) parallelEnabled(count int) bool {
+	if pdp.config == nil || pdp.config.Parallel == nil {
+		return false
+	}
+	parallel := pdp.config.Parallel
+	return parallel.Workers > 1 && count >= parallel.MinPolicies && count > 1
+}
+
+// evaluatePoliciesParallel evaluates policies with a pool of workers taking policies in order.
+// When a policy denies, policies after it are cancelled (skipped, or stopped between statements)
+// while policies before it still complete, so the outcomes up to the first deny are exactly those
+// of sequential evaluation and deny-override picks the same statement.
+func (pdp *PolicyDecisionPoint) evaluatePoliciesParallel(policies []*models.Policy, context map[string]interface{}) []policyOutcome {
+	outcomes := make([]policyOutcome, len(policies))
+	total := int64(len(policies))
+
+	var next atomic.Int64
+	var firstDeny atomic.Int64
+	firstDeny.Store(total)
+
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicValue interface{}
+	for w := 0; w < min(pdp.config.Parallel.Workers, len(policies)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Re-raised on the calling goroutine, where it surfaces like a sequential evaluation panic
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicValue = r })
+					firstDeny.Store(-1)
+				}
+			}()
+
+			for {
+				i := next.Add(1) - 1
+				if i >= total || i > firstDeny.Load() {
+					return
+				}
+				outcome := pdp.evaluatePolicy(policies[i], context, func() bool { return i > firstDeny.Load() })
+				outcomes[i] = outcome
+				if outcome.denied {
+					lowerDenyIndex(&firstDeny, i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if panicValue != nil {
+		panic(panicValue)
+	}
+	return outcomes
+}
+
+// lowerDenyIndex records i as the first denying policy unless an earlier one already denied
+func lowerDenyIndex(firstDeny *atomic.Int64, i int64) {
+	for {
+		current := firstDeny.Load()
+		if i >= current || firstDeny.CompareAndSwap(current, i) {
+			return
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// parallelTestPolicies builds a large policy set: allows gated by clearance level and three
+// deny policies at different positions, so several denies can match one request
+func parallelTestPolicies() []*models.Policy {
+	denies := map[int]map[string]interface{}{
+		40:  {"StringEquals": map[string]interface{}{"user.department": "interns"}},
+		120: {"ArrayContains": map[string]interface{}{"user.custom_roles": "suspended"}},
+		180: {"IsInternalIP": map[string]interface{}{"environment.client_ip": false}},
+	}
+
+	policies := make([]*models.Policy, 0, 200)
+	for i := 0; i < 200; i++ {
+		statement := models.PolicyStatement{
+			Sid:      fmt.Sprintf("AllowLevel%d", i),
+			Effect:   "Allow",
+			Action:   models.JSONActionResource{Single: "document:read"},
+			Resource: models.JSONActionResource{Single: "api:documents:*"},
+			Condition: map[string]interface{}{
+				"NumericGreaterThanEquals": map[string]interface{}{"user.access_level": i % 10},
+				"IsInternalIP":             map[string]interface{}{"environment.client_ip": true},
+			},
+		}
+		if condition, ok := denies[i]; ok {
+			statement.Sid = fmt.Sprintf("Deny%d", i)
+			statement.Effect = "Deny"
+			statement.Condition = condition
+		}
+		policies = append(policies, &models.Policy{ID: fmt.Sprintf("pol-%03d", i), Enabled: i%7 != 3, Statement: []models.PolicyStatement{statement}})
+	}
+	return policies
+}
+
+// TestPDP_ParallelEvaluation tests that parallel evaluation makes the same decisions as sequential evaluation
+func TestPDP_ParallelEvaluation(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:report.pdf", ResourceID: "api:documents:report.pdf"})
+	mockStorage.SetPolicies(parallelTestPolicies())
+
+	sequential := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+	config := DefaultPDPConfig()
+	config.Parallel = &ParallelConfig{Workers: 8, MinPolicies: 10}
+	parallel := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		clientIP   string
+		expected   string
+		reason     string
+	}{
+		{"allowed by many policies", map[string]interface{}{"level": 5, "department": "finance", "roles": []interface{}{"analyst"}}, "10.0.0.1", constants.ResultPermit, ""},
+		{"first of three denies", map[string]interface{}{"level": 9, "department": "interns", "roles": []interface{}{"suspended"}}, "203.0.113.9", constants.ResultDeny, "Denied by statement: Deny40"},
+		{"later deny", map[string]interface{}{"level": 9, "department": "finance", "roles": []interface{}{"suspended"}}, "10.0.0.1", constants.ResultDeny, "Denied by statement: Deny120"},
+		{"last deny", map[string]interface{}{"level": 2, "department": "finance", "roles": []interface{}{}}, "203.0.113.9", constants.ResultDeny, "Denied by statement: Deny180"},
+		{"implicit deny", map[string]interface{}{"level": -1, "department": "finance", "roles": []interface{}{}}, "10.0.0.1", constants.ResultDeny, constants.ReasonImplicitDeny},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := &models.EvaluationRequest{
+				RequestID:   "parallel-001",
+				Subject:     models.CreateMockSubjectWithAttributes("user-1", test.attributes),
+				ResourceID:  "api:documents:report.pdf",
+				Action:      "document:read",
+				Environment: &models.EnvironmentInfo{ClientIP: test.clientIP},
+			}
+
+			expected, err := sequential.Evaluate(request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if expected.Result != test.expected || (test.reason != "" && expected.Reason != test.reason) {
+				t.Fatalf("Sequential: expected %s (%s), got %s (%s)", test.expected, test.reason, expected.Result, expected.Reason)
+			}
+
+			// Repeat to exercise different worker interleavings
+			for run := 0; run < 20; run++ {
+				decision, err := parallel.Evaluate(request)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if decision.Result != expected.Result || decision.Reason != expected.Reason ||
+					!reflect.DeepEqual(decision.MatchedPolicies, expected.MatchedPolicies) {
+					t.Fatalf("Run %d: parallel decision %s (%s, %v) differs from sequential %s (%s, %v)", run,
+						decision.Result, decision.Reason, decision.MatchedPolicies,
+						expected.Result, expected.Reason, expected.MatchedPolicies)
+				}
+			}
+		})
+	}
+}
+
+// TestPDP_ParallelEvaluationThreshold tests that small candidate sets stay sequential
+func TestPDP_ParallelEvaluationThreshold(t *testing.T) {
+	pdp := newPolicyDecisionPoint(storage.NewMockStorage())
+	pdp.config = DefaultPDPConfig()
+	if pdp.parallelEnabled(1000) {
+		t.Error("Expected sequential evaluation without a parallel config")
+	}
+
+	pdp.config.Parallel = &ParallelConfig{Workers: 4, MinPolicies: 64}
+	if pdp.parallelEnabled(63) || !pdp.parallelEnabled(64) {
+		t.Error("Expected parallel evaluation from MinPolicies candidates")
+	}
+	pdp.config.Parallel.Workers = 1
+	if pdp.parallelEnabled(1000) {
+		t.Error("Expected one worker to evaluate sequentially")
+	}
+}
+
+func TestParallelConfigFromEnv(t *testing.T) {
+	for _, value := range []string{"", "1", "many"} {
+		t.Setenv(constants.EnvEvalParallelism, value)
+		if config := ParallelConfigFromEnv(); config != nil {
+			t.Errorf("%q: expected sequential evaluation, got %+v", value, config)
+		}
+	}
+
+	t.Setenv(constants.EnvEvalParallelism, "8")
+	t.Setenv(constants.EnvEvalParallelMinPolicies, "200")
+	config := ParallelConfigFromEnv()
+	if config == nil || config.Workers != 8 || config.MinPolicies != 200 {
+		t.Errorf("Unexpected config %+v", config)
+	}
+}
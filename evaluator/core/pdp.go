@@ -3,21 +3,51 @@ package core
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"abac_go_example/attributes"
+	"abac_go_example/bundle"
+	"abac_go_example/clock"
 	"abac_go_example/constants"
 	"abac_go_example/evaluator/conditions"
 	"abac_go_example/evaluator/matchers"
+	"abac_go_example/evaluator/path"
 	"abac_go_example/models"
 	"abac_go_example/operators"
+	"abac_go_example/sink"
 	"abac_go_example/storage"
 )
 
 // PolicyDecisionPointInterface defines the interface for policy evaluation
 type PolicyDecisionPointInterface interface {
 	Evaluate(request *models.EvaluationRequest) (*models.Decision, error)
+	Explain(request *models.EvaluationRequest) (*models.Explanation, error)
+	EvaluateFields(request *models.EvaluationRequest, fields []string) (*models.FieldDecision, error)
+	GetPolicyStats(policyID string) (*PolicyStats, bool)
+	GetAllPolicyStats() []*PolicyStats
+	GetPolicyCoverage(policyID string) (*PolicyCoverage, bool)
+	GetAllPolicyCoverage() []*PolicyCoverage
+	GetMissingAttributes() ([]MissingAttributeCount, bool)
+	GetQuarantinedPolicies() ([]QuarantinedPolicy, bool)
+	ReleaseQuarantine(policyID string) bool
+	GetPolicyFingerprint(namespace string) (*PolicyFingerprint, error)
+	GetDenyCacheStats() (*DenyCacheStats, bool)
+	PurgeDenyCache()
+	GetAttributeCacheStats() (*attributes.AttributeCacheStats, bool)
+	InvalidateAttributeCache(entityType, entityID string)
+	PrepareSubjectContext(sessionID string, subject models.SubjectInterface) (*attributes.PreparedSubject, error)
+	GetPreparedSubjectContext(sessionID, subjectID string) (*attributes.PreparedSubject, bool)
+	ReleaseSubjectContext(sessionID string) bool
+	LoadBundle(b *bundle.Bundle) error
+	GetIntegrityStatus() (*IntegrityStatus, bool)
+	SetLockdown(lockdown *Lockdown) error
+	GetLockdown() *Lockdown
+	WarmUp() (*WarmUpStats, error)
+	GetCompileStats() *CompileStats
+	InvalidateRevocations()
 }
 
 // PolicyDecisionPoint (PDP) is the main evaluation engine
@@ -28,10 +58,26 @@ type PolicyDecisionPoint struct {
 	resourceMatcher            *matchers.ResourceMatcher
 	enhancedConditionEvaluator *conditions.EnhancedConditionEvaluator
 	networkUtils               *operators.NetworkUtils
+	config                     *PDPConfig
+	stats                      *StatsCollector
+	coverage                   *CoverageCollector
+	missingAttributes          *MissingAttributeMonitor
+	quarantine                 *PolicyQuarantine
+	compiler                   *PolicyCompiler
+	denyCache                  *DenyCache
+	integrity                  *policyIntegrity
+	lockdown                   atomic.Pointer[Lockdown]
+	warmUp                     atomic.Pointer[WarmUpStats]
+	revocations                revocationCache
 }
 
 // NewPolicyDecisionPoint creates a new PDP instance and returns the interface
 func NewPolicyDecisionPoint(storage storage.Storage) PolicyDecisionPointInterface {
+	return NewPolicyDecisionPointWithConfig(storage, DefaultPDPConfig())
+}
+
+// newPolicyDecisionPoint wires the PDP components without applying configuration
+func newPolicyDecisionPoint(storage storage.Storage) *PolicyDecisionPoint {
 	return &PolicyDecisionPoint{
 		storage:                    storage,
 		attributeResolver:          attributes.NewAttributeResolver(storage),
@@ -46,42 +92,304 @@ func NewPolicyDecisionPoint(storage storage.Storage) PolicyDecisionPointInterfac
 func (pdp *PolicyDecisionPoint) Evaluate(request *models.EvaluationRequest) (*models.Decision, error) {
 	startTime := time.Now()
 
+	// Step 0: Revocations, lockdown, access exceptions and the negative cache decide before any evaluation;
+	// BeforeDecision hooks decide after enrichment (Step 1-3b: enrich context, load and pre-filter policies)
+	gated, err := pdp.runGates(request, true)
+	if err != nil {
+		return nil, err
+	}
+	if !gated.enriched() {
+		decision := gated.decision
+		if gated.gate == gateRevocation || gated.gate == gateException {
+			decision.EvaluationTimeMs = int(time.Since(startTime).Milliseconds())
+		}
+		pdp.publishDecision(request, decision)
+		return decision, nil
+	}
+	allPolicies, evalContext, context := gated.policies, gated.evalContext, gated.context
+
+	// Step 4: Evaluate all policies with Deny-Override algorithm, unless a hook decided first
+	decision := gated.decision
+	var allowStatements []models.PolicyStatement
+	if decision == nil {
+		decision, allowStatements = pdp.evaluatePolicies(allPolicies, evalContext)
+
+		// Step 4a: Tell PEPs how long the decision stays valid; decisions of hooks and about the past are not cacheable
+		if !isPointInTime(request) {
+			decision.CacheTTL = pdp.decisionTTL(allPolicies, evalContext, evaluationTime(request, context))
+		}
+
+		// Evaluate canary policies held back by their rollout in shadow
+		decision.Shadow = pdp.shadowDecision(request, evalContext, decision)
+	}
+	if err := pdp.hooks().runAfterDecision(request, evalContext, decision); err != nil {
+		return nil, err
+	}
+
+	// Step 4b: Count the permitted request against quota conditions
+	if decision.Result == constants.ResultPermit {
+		pdp.consumeQuotas(allowStatements, evalContext)
+	}
+
+	// Step 5: Calculate evaluation time
+	evaluationTime := int(time.Since(startTime).Milliseconds())
+	decision.EvaluationTimeMs = evaluationTime
+
+	// Step 6: Mask sensitive attribute values that leaked into the reason
+	decision.Reason = pdp.config.Redactor.RedactText(decision.Reason, evalContext)
+
+	// Step 7: Remember denies so retry storms are answered without re-evaluation
+	if pdp.denyCache != nil && decision.Result == constants.ResultDeny && !isPointInTime(request) {
+		pdp.denyCache.Store(denyCacheKey(request, pdp.evaluationNamespace(request)), decision)
+	}
+
+	// Step 8: Publish the decision to the configured sink
+	pdp.publishDecision(request, decision)
+
+	return decision, nil
+}
+
+// hooks returns the registered pipeline hooks, nil when none are configured
+func (pdp *PolicyDecisionPoint) hooks() *Hooks {
+	if pdp.config == nil {
+		return nil
+	}
+	return pdp.config.Hooks
+}
+
+// cachedDeny returns the cached deny for the request when the negative cache is enabled
+func (pdp *PolicyDecisionPoint) cachedDeny(request *models.EvaluationRequest) (*models.Decision, bool) {
+	if pdp.denyCache == nil || request == nil || request.Subject == nil || isPointInTime(request) {
+		return nil, false
+	}
+	return pdp.denyCache.Get(denyCacheKey(request, pdp.evaluationNamespace(request)))
+}
+
+// isPointInTime reports whether the request evaluates historical or supplied attributes;
+// such decisions are never served from or stored in the deny cache
+func isPointInTime(request *models.EvaluationRequest) bool {
+	return request.AsOf != nil || request.Snapshots != nil
+}
+
+// publishDecision sends the decision to the configured sink
+func (pdp *PolicyDecisionPoint) publishDecision(request *models.EvaluationRequest, decision *models.Decision) {
+	if pdp.config.DecisionSink != nil {
+		pdp.config.DecisionSink.Publish(sink.NewDecisionEvent(request, decision, pdp.now()))
+	}
+}
+
+// now returns the current time from the configured clock
+func (pdp *PolicyDecisionPoint) now() time.Time {
+	if pdp.config == nil {
+		return time.Now()
+	}
+	return clock.OrReal(pdp.config.Clock).Now()
+}
+
+// GetDenyCacheStats returns negative cache counters; false when the cache is disabled
+func (pdp *PolicyDecisionPoint) GetDenyCacheStats() (*DenyCacheStats, bool) {
+	if pdp.denyCache == nil {
+		return nil, false
+	}
+	stats := pdp.denyCache.Stats()
+	return &stats, true
+}
+
+// PurgeDenyCache drops all cached denies; call it after policies change
+func (pdp *PolicyDecisionPoint) PurgeDenyCache() {
+	if pdp.denyCache != nil {
+		pdp.denyCache.Purge()
+	}
+}
+
+// GetAttributeCacheStats returns attribute cache counters; false when the cache is disabled
+func (pdp *PolicyDecisionPoint) GetAttributeCacheStats() (*attributes.AttributeCacheStats, bool) {
+	cache := pdp.attributeResolver.GetAttributeCache()
+	if cache == nil {
+		return nil, false
+	}
+	stats := cache.Stats()
+	return &stats, true
+}
+
+// InvalidateAttributeCache drops cached lookups of an entity (see AttributeCache.Invalidate);
+// call it after subjects, groups, resources or actions change. Subject changes also drop the
+// subject's prepared contexts.
+func (pdp *PolicyDecisionPoint) InvalidateAttributeCache(entityType, entityID string) {
+	if cache := pdp.attributeResolver.GetAttributeCache(); cache != nil {
+		cache.Invalidate(entityType, entityID)
+	}
+	if prepared := pdp.attributeResolver.GetPreparedSubjects(); prepared != nil && (entityType == "" || entityType == attributes.EntitySubject) {
+		prepared.InvalidateSubject(entityID)
+	}
+}
+
+// Explain evaluates the request and reports how every statement contributed to the decision,
+// together with any attribute conflicts resolved during context enrichment
+func (pdp *PolicyDecisionPoint) Explain(request *models.EvaluationRequest) (*models.Explanation, error) {
+	startTime := time.Now()
+
+	// No statement is evaluated while a revocation, lockdown or access exception decides the request
+	gated, err := pdp.runGates(request, false)
+	if err != nil {
+		return nil, err
+	}
+	if !gated.enriched() {
+		return &models.Explanation{Decision: gated.decision, Statements: []models.StatementTrace{}}, nil
+	}
+	allPolicies, evalContext, context := gated.policies, gated.evalContext, gated.context
+
+	decision := gated.decision
+	if decision == nil {
+		decision = pdp.evaluateNewPolicies(allPolicies, evalContext)
+	}
+	decision.EvaluationTimeMs = int(time.Since(startTime).Milliseconds())
+	decision.Reason = pdp.config.Redactor.RedactText(decision.Reason, evalContext)
+
+	return &models.Explanation{
+		Decision:           decision,
+		Statements:         pdp.traceStatements(allPolicies, evalContext),
+		AttributeConflicts: pdp.config.Redactor.RedactConflicts(context.Conflicts),
+		AsOf:               request.AsOf,
+		AttributeSources:   context.AttributeSources,
+		Relationships:      context.Relationships,
+		Device:             context.Device,
+		Elevations:         context.Elevations,
+		Tree:               pdp.explainTree(decision, allPolicies, evalContext),
+	}, nil
+}
+
+// prepareEvaluation validates the request, enriches its context and loads candidate policies
+func (pdp *PolicyDecisionPoint) prepareEvaluation(request *models.EvaluationRequest) ([]*models.Policy, map[string]interface{}, *models.EvaluationContext, error) {
 	// Input validation
 	if request == nil {
-		return nil, fmt.Errorf("evaluation request cannot be nil")
+		return nil, nil, nil, fmt.Errorf("evaluation request cannot be nil")
 	}
 
 	if request.Subject == nil {
-		return nil, fmt.Errorf("subject is required")
+		return nil, nil, nil, fmt.Errorf("subject is required")
 	}
 
 	if request.ResourceID == "" || request.Action == "" {
-		return nil, fmt.Errorf("invalid request: missing required fields (ResourceID, Action)")
+		return nil, nil, nil, fmt.Errorf("invalid request: missing required fields (ResourceID, Action)")
+	}
+
+	// Step 0: Let registered hooks adjust the request
+	if err := pdp.hooks().runBeforeEnrich(request); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// Step 1: Enrich context with all necessary attributes
 	context, err := pdp.attributeResolver.EnrichContext(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to enrich context: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to enrich context: %w", err)
 	}
 
-	// Step 2: Get applicable policies with pre-filtering
-	allPolicies, err := pdp.storage.GetPolicies()
+	// Step 2: Get applicable policies (global plus the request namespace) with pre-filtering
+	allPolicies, err := pdp.namespacePolicies(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get policies: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to get policies: %w", err)
 	}
+	at := evaluationTime(request, context)
+	allPolicies = pdp.trustedPolicies(allPolicies)
+	windowChange := nextPolicyWindowChange(allPolicies, at)
+	allPolicies = effectivePolicies(allPolicies, at)
+	allPolicies, canary := rolloutPolicies(allPolicies, request.Subject.GetID())
 
 	// Step 3: Build enhanced evaluation context with time-based and environmental attributes
 	evalContext := pdp.BuildEnhancedEvaluationContext(request, context)
+	if !windowChange.IsZero() {
+		evalContext[constants.ContextKeyPolicyWindowChange] = windowChange
+	}
+	if len(canary) > 0 {
+		evalContext[constants.ContextKeyCanaryPolicies] = canary
+	}
+	pdp.addResourceParams(allPolicies, evalContext)
+	if err := pdp.hooks().runAfterEnrich(request, evalContext); err != nil {
+		return nil, nil, nil, err
+	}
 
-	// Step 4: Evaluate all policies with Deny-Override algorithm
-	decision := pdp.evaluateNewPolicies(allPolicies, evalContext)
+	// Step 4: Share condition results across the policies of this request
+	conditions.EnableMemo(evalContext)
 
-	// Step 5: Calculate evaluation time
-	evaluationTime := int(time.Since(startTime).Milliseconds())
-	decision.EvaluationTimeMs = evaluationTime
+	return allPolicies, evalContext, context, nil
+}
 
-	return decision, nil
+// traceStatements evaluates every statement of every enabled policy without short-circuiting
+func (pdp *PolicyDecisionPoint) traceStatements(policies []*models.Policy, context map[string]interface{}) []models.StatementTrace {
+	traces := make([]models.StatementTrace, 0, len(policies))
+
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+
+		for _, statement := range policy.Statement {
+			trace := models.StatementTrace{
+				PolicyID: policy.ID,
+				Sid:      statement.Sid,
+				Effect:   statement.Effect,
+			}
+			trace.ActionMatched = pdp.isActionMatched(statement.Action, statement.Effect, context)
+			trace.ResourceMatched = pdp.isResourceMatched(statement, context)
+			trace.ConditionsMatched = pdp.areConditionsSatisfied(statement.Condition, context)
+			trace.Matched = trace.ActionMatched && trace.ResourceMatched && trace.ConditionsMatched
+			trace.Conditions = pdp.traceConditions(statement.Condition, context)
+			traces = append(traces, trace)
+		}
+	}
+
+	return traces
+}
+
+// traceConditions evaluates each operator/key pair of a condition block in isolation, in sorted order
+func (pdp *PolicyDecisionPoint) traceConditions(conditions map[string]interface{}, context map[string]interface{}) []models.ConditionTrace {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	operators := make([]string, 0, len(conditions))
+	for operator := range conditions {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+
+	traces := make([]models.ConditionTrace, 0, len(conditions))
+	for _, operator := range operators {
+		block, ok := conditions[operator].(map[string]interface{})
+		if !ok || isLogicalOperator(operator) {
+			traces = append(traces, models.ConditionTrace{
+				Operator: operator,
+				Matched:  pdp.enhancedConditionEvaluator.EvaluateConditions(map[string]interface{}{operator: conditions[operator]}, context),
+			})
+			continue
+		}
+
+		keys := make([]string, 0, len(block))
+		for key := range block {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			single := map[string]interface{}{operator: map[string]interface{}{key: block[key]}}
+			traces = append(traces, models.ConditionTrace{
+				Operator: operator,
+				Key:      key,
+				Matched:  pdp.enhancedConditionEvaluator.EvaluateConditions(single, context),
+			})
+		}
+	}
+	return traces
+}
+
+// isLogicalOperator reports whether the operator combines nested conditions
+func isLogicalOperator(operator string) bool {
+	switch constants.OperatorKey(operator) {
+	case constants.OpAnd, constants.OpOr, constants.OpNot:
+		return true
+	}
+	return false
 }
 
 // BuildEnhancedEvaluationContext builds enhanced context map with structured attributes
@@ -100,12 +408,21 @@ func (pdp *PolicyDecisionPoint) BuildEnhancedEvaluationContext(request *models.E
 	// Enhanced environmental context
 	pdp.addEnvironmentalContext(evalContext, request)
 
+	// Standardized session and MFA attributes
+	pdp.addSessionContext(evalContext, request, context)
+
 	// Structured subject attributes
 	pdp.addStructuredSubjectAttributes(evalContext, context)
 
 	// Structured resource attributes
 	pdp.addStructuredResourceAttributes(evalContext, context)
 
+	// Subject/resource relationships (relationship.is_owner, relationship.same_team)
+	pdp.addRelationshipContext(evalContext, context)
+
+	// Device posture (device.managed, device.patch_age_days)
+	pdp.addDeviceContext(evalContext, context)
+
 	// Add custom context from request
 	for key, value := range request.Context {
 		evalContext[constants.ContextKeyRequestPrefix+key] = value
@@ -116,23 +433,71 @@ func (pdp *PolicyDecisionPoint) BuildEnhancedEvaluationContext(request *models.E
 		evalContext[constants.ContextKeyEnvironmentPrefix+key] = value
 	}
 
+	// Action category for default decision rules
+	if context.Action != nil {
+		evalContext[constants.ContextKeyActionCategory] = context.Action.ActionCategory
+	}
+
+	// Expose every attribute in both flat (user:department) and dot (user.department) notation
+	path.BridgeNotations(evalContext)
+
 	return evalContext
 }
 
+// evaluationTime returns the request timestamp, or the enrichment time when the request carries none
+func evaluationTime(request *models.EvaluationRequest, context *models.EvaluationContext) time.Time {
+	if request.Timestamp != nil {
+		return *request.Timestamp
+	}
+	return context.Timestamp
+}
+
+// tagFilteredPolicies drops policies whose every statement has a top-level ResourceTag condition
+// the resource fails, and disabled policies (which are never evaluated, so they are not compiled).
+// Such statements can never match, so skipping them does not change decisions;
+// Explain keeps tracing them so operators can see why they did not apply.
+func (pdp *PolicyDecisionPoint) tagFilteredPolicies(policies []*models.Policy, context map[string]interface{}) []*models.Policy {
+	tags, _ := context[constants.ContextKeyResourceTags].([]string)
+	filtered := make([]*models.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		compiled := pdp.compiler.Compile(policy)
+		applicable := len(compiled) == 0
+		for _, statement := range compiled {
+			if statement.RequiredTags == nil || conditions.MatchTags(statement.RequiredTags, tags) {
+				applicable = true
+				break
+			}
+		}
+		if applicable {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
+
+// effectivePolicies drops policies outside their EffectiveFrom/ExpiresAt window at t
+func effectivePolicies(policies []*models.Policy, t time.Time) []*models.Policy {
+	effective := make([]*models.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.IsEffectiveAt(t) {
+			effective = append(effective, policy)
+		}
+	}
+	return effective
+}
+
 // addTimeBasedAttributes adds time-based attributes (improvement #4)
 func (pdp *PolicyDecisionPoint) addTimeBasedAttributes(evalContext map[string]interface{}, request *models.EvaluationRequest, context *models.EvaluationContext) {
-	var timestamp time.Time
-
 	// Use provided timestamp or current time
-	if request.Timestamp != nil {
-		timestamp = *request.Timestamp
-	} else {
-		timestamp = context.Timestamp
-	}
+	timestamp := evaluationTime(request, context)
 
 	// Add time of day (HH:MM format)
 	timeOfDay := timestamp.Format("15:04")
 	evalContext[constants.ContextKeyTimeOfDay] = timeOfDay
+	evalContext[constants.ContextKeyEvalTime] = timestamp.Format(time.RFC3339)
 
 	// Add day of week
 	dayOfWeek := timestamp.Weekday().String()
@@ -142,7 +507,17 @@ func (pdp *PolicyDecisionPoint) addTimeBasedAttributes(evalContext map[string]in
 	evalContext[constants.ContextKeyEnvironmentPrefix+"hour"] = timestamp.Hour()
 	evalContext[constants.ContextKeyEnvironmentPrefix+"minute"] = timestamp.Minute()
 	evalContext[constants.ContextKeyEnvironmentPrefix+"is_weekend"] = timestamp.Weekday() == time.Saturday || timestamp.Weekday() == time.Sunday
-	evalContext[constants.ContextKeyEnvironmentPrefix+"is_business_hours"] = pdp.networkUtils.IsBusinessHours(timestamp.Hour(), int(timestamp.Weekday()))
+
+	// Holidays are treated like weekends
+	isHoliday := false
+	if pdp.config != nil && pdp.config.Holidays != nil {
+		var name string
+		if name, isHoliday = pdp.config.Holidays.Holiday(timestamp); isHoliday {
+			evalContext[constants.ContextKeyEnvironmentPrefix+constants.ContextKeyHolidayName] = name
+		}
+	}
+	evalContext[constants.ContextKeyEnvironmentPrefix+constants.ContextKeyIsHoliday] = isHoliday
+	evalContext[constants.ContextKeyEnvironmentPrefix+"is_business_hours"] = pdp.networkUtils.IsBusinessHours(timestamp.Hour(), int(timestamp.Weekday())) && !isHoliday
 }
 
 // addEnvironmentalContext adds environmental context (improvement #5)
@@ -163,8 +538,13 @@ func (pdp *PolicyDecisionPoint) addEnvironmentalContext(evalContext map[string]i
 	// User Agent and device detection
 	if env.UserAgent != "" {
 		evalContext[constants.ContextKeyUserAgent] = env.UserAgent
-		evalContext[constants.ContextKeyEnvironmentPrefix+"is_mobile"] = pdp.networkUtils.IsMobileUserAgent(env.UserAgent)
-		evalContext[constants.ContextKeyEnvironmentPrefix+"browser"] = pdp.networkUtils.GetBrowserFromUserAgent(env.UserAgent)
+		userAgent := operators.ParseUserAgent(env.UserAgent)
+		evalContext[constants.ContextKeyEnvironmentPrefix+"is_mobile"] = userAgent.IsMobile()
+		evalContext[constants.ContextKeyDeviceType] = userAgent.DeviceType
+		evalContext[constants.ContextKeyOS] = userAgent.OS
+		evalContext[constants.ContextKeyOSVersion] = userAgent.OSVersion
+		evalContext[constants.ContextKeyBrowser] = userAgent.Browser
+		evalContext[constants.ContextKeyBrowserVersion] = userAgent.BrowserVersion
 	}
 
 	// Location attributes
@@ -175,12 +555,119 @@ func (pdp *PolicyDecisionPoint) addEnvironmentalContext(evalContext map[string]i
 		evalContext[constants.ContextKeyRegion] = env.Region
 	}
 
+	// Mutual TLS client certificate
+	if env.ClientCert != nil {
+		addClientCertContext(evalContext, env.ClientCert)
+	}
+
 	// Custom environment attributes
 	for key, value := range env.Attributes {
 		evalContext[constants.ContextKeyEnvironmentPrefix+key] = value
 	}
 }
 
+// addClientCertContext exposes a client certificate as the structured environment:client_cert attribute
+// (read by IssuedByCA); conditions reach its fields through the path resolver (environment.client_cert.subject_dn)
+func addClientCertContext(evalContext map[string]interface{}, cert *models.ClientCertInfo) {
+	issuerChain := make([]interface{}, 0, len(cert.IssuerChain))
+	if cert.Verified {
+		for _, issuer := range cert.IssuerChain {
+			issuerChain = append(issuerChain, map[string]interface{}{
+				constants.ClientCertKeySubjectDN:   issuer.SubjectDN,
+				constants.ClientCertKeyFingerprint: strings.ToLower(issuer.Fingerprint),
+			})
+		}
+	}
+
+	certContext := map[string]interface{}{
+		constants.ClientCertKeySubjectDN:      cert.SubjectDN,
+		constants.ClientCertKeyCommonName:     cert.CommonName,
+		constants.ClientCertKeyIssuerDN:       cert.IssuerDN,
+		constants.ClientCertKeySerialNumber:   cert.SerialNumber,
+		constants.ClientCertKeyFingerprint:    strings.ToLower(cert.Fingerprint),
+		constants.ClientCertKeyDNSNames:       stringsToInterfaces(cert.DNSNames),
+		constants.ClientCertKeyURIs:           stringsToInterfaces(cert.URIs),
+		constants.ClientCertKeyEmailAddresses: stringsToInterfaces(cert.EmailAddresses),
+		constants.ClientCertKeyIPAddresses:    stringsToInterfaces(cert.IPAddresses),
+		constants.ClientCertKeyVerified:       cert.Verified,
+		constants.ClientCertKeyIssuerChain:    issuerChain,
+	}
+	if !cert.NotBefore.IsZero() {
+		certContext[constants.ClientCertKeyNotBefore] = cert.NotBefore.UTC().Format(time.RFC3339)
+	}
+	if !cert.NotAfter.IsZero() {
+		certContext[constants.ClientCertKeyNotAfter] = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	evalContext[constants.ContextKeyClientCert] = certContext
+}
+
+// stringsToInterfaces converts a string slice to the []interface{} form used by array conditions
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// addSessionContext exposes request.Session as structured (session.mfa_verified) and flat (session:mfa_verified) attributes
+func (pdp *PolicyDecisionPoint) addSessionContext(evalContext map[string]interface{}, request *models.EvaluationRequest, context *models.EvaluationContext) {
+	if request.Session == nil {
+		return
+	}
+
+	session := request.Session
+	sessionContext := map[string]interface{}{
+		constants.SessionKeyID:          session.SessionID,
+		constants.SessionKeyAuthMethod:  session.AuthMethod,
+		constants.SessionKeyMFAVerified: session.MFAVerified,
+	}
+
+	if session.AuthTime != nil {
+		// Age is measured against the same timestamp as the time-based attributes
+		evaluationTime := context.Timestamp
+		if request.Timestamp != nil {
+			evaluationTime = *request.Timestamp
+		}
+		sessionContext[constants.SessionKeyAuthTime] = session.AuthTime.Format(time.RFC3339)
+		sessionContext[constants.SessionKeyAuthAgeSeconds] = int(evaluationTime.Sub(*session.AuthTime).Seconds())
+	}
+
+	for key, value := range sessionContext {
+		evalContext[constants.ContextKeySessionPrefix+key] = value
+	}
+	evalContext[constants.ContextKeySession] = sessionContext
+}
+
+// addRelationshipContext exposes context.Relationships as structured (relationship.is_owner) and flat (relationship:is_owner) attributes
+func (pdp *PolicyDecisionPoint) addRelationshipContext(evalContext map[string]interface{}, context *models.EvaluationContext) {
+	if context.Relationships == nil {
+		return
+	}
+
+	relationshipContext := make(map[string]interface{}, len(context.Relationships))
+	for key, value := range context.Relationships {
+		relationshipContext[key] = value
+		evalContext[constants.ContextKeyRelationshipPrefix+key] = value
+	}
+	evalContext[constants.ContextKeyRelationship] = relationshipContext
+}
+
+// addDeviceContext exposes context.Device as structured (device.managed) and flat (device:managed) attributes
+func (pdp *PolicyDecisionPoint) addDeviceContext(evalContext map[string]interface{}, context *models.EvaluationContext) {
+	if context.Device == nil {
+		return
+	}
+
+	deviceContext := make(map[string]interface{}, len(context.Device))
+	for key, value := range context.Device {
+		deviceContext[key] = value
+		evalContext[constants.ContextKeyDevicePrefix+key] = value
+	}
+	evalContext[constants.ContextKeyDevice] = deviceContext
+}
+
 // addStructuredSubjectAttributes adds structured subject attributes (improvement #6)
 func (pdp *PolicyDecisionPoint) addStructuredSubjectAttributes(evalContext map[string]interface{}, context *models.EvaluationContext) {
 	// Support both legacy Subject and new SubjectInterface
@@ -221,12 +708,14 @@ func (pdp *PolicyDecisionPoint) addStructuredResourceAttributes(evalContext map[
 	}
 	evalContext[constants.ContextKeyResourcePrefix+"ResourceType"] = context.Resource.ResourceType
 	evalContext[constants.ContextKeyResourcePrefix+"ResourceId"] = context.Resource.ResourceID
+	evalContext[constants.ContextKeyResourceTags] = []string(models.NormalizeTags(context.Resource.Tags))
 
 	// Structured attributes for enhanced access
 	resourceContext := map[string]interface{}{
 		"resource_type": context.Resource.ResourceType,
 		"resource_id":   context.Resource.ResourceID,
 		"attributes":    map[string]interface{}(context.Resource.Attributes),
+		"tags":          evalContext[constants.ContextKeyResourceTags],
 	}
 
 	// Also add flat attributes directly to resource context for easier access
@@ -240,31 +729,61 @@ func (pdp *PolicyDecisionPoint) addStructuredResourceAttributes(evalContext map[
 
 // evaluateNewPolicies evaluates policies using the new format with Deny-Override
 func (pdp *PolicyDecisionPoint) evaluateNewPolicies(policies []*models.Policy, context map[string]interface{}) *models.Decision {
-	var matchedPolicies []string
-	var matchedStatements []string
+	decision, _ := pdp.evaluatePolicies(policies, context)
+	return decision
+}
 
-	// Step 1: Collect all matching statements
-	for _, policy := range policies {
-		if !policy.Enabled {
-			continue
+// evaluatePolicies implements evaluateNewPolicies and also returns the matched Allow statements
+// of a permit decision, whose quota conditions are consumed by Evaluate.
+// Quarantined policies are skipped and contribute Indeterminate (see quarantineDecision).
+func (pdp *PolicyDecisionPoint) evaluatePolicies(policies []*models.Policy, context map[string]interface{}) (*models.Decision, []models.PolicyStatement) {
+	policies, indeterminate, mayDeny := pdp.quarantinedPolicies(policies, context)
+	decision, allowStatements := pdp.combinePolicies(policies, context)
+	if len(indeterminate) == 0 {
+		return decision, allowStatements
+	}
+	return quarantineDecision(decision, allowStatements, indeterminate, mayDeny)
+}
+
+// combinePolicies evaluates policies with the Deny-Override algorithm
+func (pdp *PolicyDecisionPoint) combinePolicies(policies []*models.Policy, context map[string]interface{}) (*models.Decision, []models.PolicyStatement) {
+	// Step 1: Collect all matching statements, stopping at the first policy with a matching Deny
+	var outcomes []policyOutcome
+	if pdp.parallelEnabled(len(policies)) {
+		outcomes = pdp.evaluatePoliciesParallel(policies, context)
+	} else {
+		outcomes = make([]policyOutcome, 0, len(policies))
+		for _, policy := range policies {
+			outcome := pdp.evaluatePolicy(policy, context, nil)
+			outcomes = append(outcomes, outcome)
+			if outcome.denied {
+				break
+			}
 		}
+	}
 
-		for _, statement := range policy.Statement {
-			if pdp.evaluateStatement(statement, context) {
-				matchedPolicies = append(matchedPolicies, policy.ID)
-				if statement.Sid != "" {
-					matchedStatements = append(matchedStatements, statement.Sid)
-				}
-
-				// Step 2: Apply Deny-Override - if any statement denies, return deny immediately
-				if strings.ToLower(statement.Effect) == constants.EffectDeny {
-					return &models.Decision{
-						Result:          constants.ResultDeny,
-						MatchedPolicies: matchedPolicies,
-						Reason:          fmt.Sprintf(constants.ReasonDeniedByStatement, statement.Sid),
-					}
-				}
+	var matchedPolicies []string
+	var matchedStatements []string
+	var allowStatements []models.PolicyStatement
+	for i, outcome := range outcomes {
+		for _, statement := range outcome.matched {
+			matchedPolicies = append(matchedPolicies, policies[i].ID)
+			if statement.Sid != "" {
+				matchedStatements = append(matchedStatements, statement.Sid)
 			}
+
+			// Step 2: Apply Deny-Override - if any statement denies, return deny immediately
+			if strings.ToLower(statement.Effect) == constants.EffectDeny {
+				return &models.Decision{
+					Result:          constants.ResultDeny,
+					MatchedPolicies: matchedPolicies,
+					Reason:          fmt.Sprintf(constants.ReasonDeniedByStatement, statement.Sid),
+					ReasonCode:      constants.ReasonCodeDeniedByStatement,
+					ReasonDetails:   map[string]string{constants.ReasonDetailStatement: statement.Sid},
+					Obligations:     decisionObligations([]models.PolicyStatement{statement}),
+				}, nil
+			}
+			allowStatements = append(allowStatements, statement)
 		}
 	}
 
@@ -274,14 +793,83 @@ func (pdp *PolicyDecisionPoint) evaluateNewPolicies(policies []*models.Policy, c
 			Result:          constants.ResultPermit,
 			MatchedPolicies: matchedPolicies,
 			Reason:          fmt.Sprintf(constants.ReasonAllowedByStatements, strings.Join(matchedStatements, ", ")),
-		}
+			ReasonCode:      constants.ReasonCodeAllowedByStatements,
+			ReasonDetails:   map[string]string{constants.ReasonDetailStatements: strings.Join(matchedStatements, ", ")},
+			Obligations:     decisionObligations(allowStatements),
+			Constraints:     decisionConstraints(allowStatements),
+		}, allowStatements
 	}
 
-	// Step 4: Default deny (no matching policies)
+	// Step 4: Configured default permits, then default deny (no matching policies)
+	if decision, ok := pdp.defaultDecision(context); ok {
+		return decision, nil
+	}
 	return &models.Decision{
 		Result:          constants.ResultDeny,
 		MatchedPolicies: []string{},
 		Reason:          constants.ReasonImplicitDeny,
+		ReasonCode:      constants.ReasonCodeImplicitDeny,
+	}, nil
+}
+
+// policyOutcome is the result of evaluating the statements of one policy
+type policyOutcome struct {
+	matched []models.PolicyStatement // Matching statements in order; a matching Deny is always last
+	denied  bool                     // A Deny statement matched
+}
+
+// evaluatePolicy evaluates the statements of one policy in order, stopping at the first matching Deny
+// or when cancelled reports true (nil never cancels). Statement results are recorded in the policy stats
+// and the evaluation time in the policy quarantine.
+func (pdp *PolicyDecisionPoint) evaluatePolicy(policy *models.Policy, context map[string]interface{}, cancelled func() bool) policyOutcome {
+	var outcome policyOutcome
+	if !policy.Enabled {
+		return outcome
+	}
+	if pdp.quarantine != nil {
+		start := time.Now()
+		defer func() { pdp.quarantine.Record(policy, time.Since(start)) }()
+	}
+
+	compiled := pdp.compiler.Compile(policy)
+	var results []StatementResult
+	for i, statement := range policy.Statement {
+		// Field-level statements are evaluated by EvaluateFields only
+		if statement.IsFieldLevel() {
+			continue
+		}
+		if cancelled != nil && cancelled() {
+			break
+		}
+
+		result := pdp.matchStatement(statement, &compiled[i], context)
+		result.Index = i
+		result.Sid = statement.Sid
+		result.Deny = strings.ToLower(statement.Effect) == constants.EffectDeny
+		results = append(results, result)
+
+		if result.Matched {
+			outcome.matched = append(outcome.matched, statement)
+			if result.Deny {
+				outcome.denied = true
+				break
+			}
+		}
+	}
+	pdp.recordPolicyStats(policy.ID, results)
+	if pdp.coverage != nil {
+		pdp.coverage.RecordPolicy(policy, results, context)
+	}
+	if pdp.missingAttributes != nil {
+		pdp.missingAttributes.RecordPolicy(policy, results, context)
+	}
+	return outcome
+}
+
+// consumeQuotas increments the quota counters of the Allow statements behind a permit decision
+func (pdp *PolicyDecisionPoint) consumeQuotas(statements []models.PolicyStatement, context map[string]interface{}) {
+	for _, statement := range statements {
+		pdp.enhancedConditionEvaluator.ConsumeQuotas(statement.Condition, context)
 	}
 }
 
@@ -289,22 +877,110 @@ func (pdp *PolicyDecisionPoint) evaluateNewPolicies(policies []*models.Policy, c
 // It performs three main checks: action matching, resource matching, and condition evaluation.
 // Returns true if all checks pass, false otherwise.
 func (pdp *PolicyDecisionPoint) evaluateStatement(statement models.PolicyStatement, context map[string]interface{}) bool {
+	return pdp.matchStatement(statement, nil, context).Matched
+}
+
+// matchStatement evaluates a statement like evaluateStatement and also reports
+// whether conditions were evaluated and how long they took.
+// compiled holds the precompiled statement; nil orders conditions on the fly.
+// Statements exceeding policy limits fail closed: Deny still applies, Allow never matches.
+func (pdp *PolicyDecisionPoint) matchStatement(statement models.PolicyStatement, compiled *CompiledStatement, context map[string]interface{}) StatementResult {
 	// Validate input parameters
 	if !pdp.isValidEvaluationContext(context) {
 		log.Printf("Error: Invalid evaluation context provided")
-		return false
+		return StatementResult{}
 	}
 
 	// Early return pattern for better readability
-	if !pdp.isActionMatched(statement.Action, context) {
-		return false
+	if !pdp.isActionMatched(statement.Action, statement.Effect, context) {
+		return StatementResult{}
 	}
 
 	if !pdp.isResourceMatched(statement, context) {
+		return StatementResult{}
+	}
+
+	if compiled != nil && compiled.LimitExceeded {
+		return StatementResult{Matched: strings.ToLower(statement.Effect) == constants.EffectDeny}
+	}
+
+	conditionStart := time.Now()
+	var matched bool
+	if compiled != nil {
+		matched = pdp.enhancedConditionEvaluator.EvaluateCompiled(compiled.Conditions, context)
+	} else {
+		matched = pdp.areConditionsSatisfied(statement.Condition, context)
+	}
+	return StatementResult{
+		Matched:             matched,
+		ConditionsEvaluated: len(statement.Condition) > 0,
+		ConditionTime:       time.Since(conditionStart),
+	}
+}
+
+// recordPolicyStats records statement results when statistics are enabled
+func (pdp *PolicyDecisionPoint) recordPolicyStats(policyID string, results []StatementResult) {
+	if pdp.stats != nil {
+		pdp.stats.RecordPolicy(policyID, results)
+	}
+}
+
+// GetPolicyStats returns evaluation statistics for a policy
+func (pdp *PolicyDecisionPoint) GetPolicyStats(policyID string) (*PolicyStats, bool) {
+	if pdp.stats == nil {
+		return nil, false
+	}
+	return pdp.stats.GetPolicyStats(policyID)
+}
+
+// GetAllPolicyStats returns evaluation statistics for every evaluated policy
+func (pdp *PolicyDecisionPoint) GetAllPolicyStats() []*PolicyStats {
+	if pdp.stats == nil {
+		return nil
+	}
+	return pdp.stats.GetAllPolicyStats()
+}
+
+// GetPolicyCoverage returns the condition coverage of a policy, nil when it was never evaluated.
+// It returns false when condition coverage is disabled.
+func (pdp *PolicyDecisionPoint) GetPolicyCoverage(policyID string) (*PolicyCoverage, bool) {
+	if pdp.coverage == nil {
+		return nil, false
+	}
+	coverage, _ := pdp.coverage.GetPolicyCoverage(policyID)
+	return coverage, true
+}
+
+// GetAllPolicyCoverage returns the condition coverage of every evaluated policy
+func (pdp *PolicyDecisionPoint) GetAllPolicyCoverage() []*PolicyCoverage {
+	if pdp.coverage == nil {
+		return nil
+	}
+	return pdp.coverage.GetAllPolicyCoverage()
+}
+
+// GetQuarantinedPolicies returns the quarantined policies, most recent first; false when quarantine is disabled
+func (pdp *PolicyDecisionPoint) GetQuarantinedPolicies() ([]QuarantinedPolicy, bool) {
+	if pdp.quarantine == nil {
+		return nil, false
+	}
+	return pdp.quarantine.Quarantined(), true
+}
+
+// ReleaseQuarantine evaluates a quarantined policy again; false when it is not quarantined
+func (pdp *PolicyDecisionPoint) ReleaseQuarantine(policyID string) bool {
+	if pdp.quarantine == nil {
 		return false
 	}
+	return pdp.quarantine.Release(policyID)
+}
 
-	return pdp.areConditionsSatisfied(statement.Condition, context)
+// GetMissingAttributes returns the missing attribute counters, highest first; false when telemetry is disabled
+func (pdp *PolicyDecisionPoint) GetMissingAttributes() ([]MissingAttributeCount, bool) {
+	if pdp.missingAttributes == nil {
+		return nil, false
+	}
+	return pdp.missingAttributes.Counts(), true
 }
 
 // isValidEvaluationContext validates that the evaluation context contains required keys
@@ -342,7 +1018,9 @@ func (pdp *PolicyDecisionPoint) isValidEvaluationContext(context map[string]inte
 }
 
 // isActionMatched checks if the requested action matches the statement's action specification.
-func (pdp *PolicyDecisionPoint) isActionMatched(actionSpec models.JSONActionResource, context map[string]interface{}) bool {
+// Allow statements also match actions implied by the granted ones (see PDPConfig.ActionCatalog);
+// Deny and Mask statements only match the actions they name.
+func (pdp *PolicyDecisionPoint) isActionMatched(actionSpec models.JSONActionResource, effect string, context map[string]interface{}) bool {
 	requestedAction, ok := context[constants.ContextKeyRequestAction].(string)
 	if !ok {
 		log.Printf("Warning: Missing or invalid action in context: %v", context[constants.ContextKeyRequestAction])
@@ -365,7 +1043,11 @@ func (pdp *PolicyDecisionPoint) isActionMatched(actionSpec models.JSONActionReso
 			log.Printf("Warning: Empty action pattern found in policy statement")
 			continue
 		}
-		if pdp.actionMatcher.Match(actionPattern, requestedAction) {
+		if strings.ToLower(effect) == constants.EffectAllow {
+			if pdp.actionMatcher.MatchImplied(actionPattern, requestedAction) {
+				return true
+			}
+		} else if pdp.actionMatcher.Match(actionPattern, requestedAction) {
 			return true
 		}
 	}
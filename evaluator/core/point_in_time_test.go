@@ -0,0 +1,139 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PointInTimeEvaluation tests that AsOf and request snapshots replace current attributes
+func TestPDP_PointInTimeEvaluation(t *testing.T) {
+	transferDate := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	decisionTime := transferDate.Add(-24 * time.Hour)
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:plan.pdf",
+		ResourceID: "api:documents:plan.pdf",
+		Attributes: models.JSONMap{"classification": "confidential"},
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-engineering-internal",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringReadInternal",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department":         "Engineering",
+							"resource.classification": "internal",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// The subject moved from Engineering to Finance and the document was reclassified on transferDate
+	mockStorage.RecordAttributeSnapshot(&models.AttributeSnapshot{
+		EntityType: models.SnapshotEntitySubject,
+		EntityID:   "user-1",
+		Attributes: models.JSONMap{"department": "Engineering"},
+		ValidFrom:  transferDate.AddDate(0, -6, 0),
+	})
+	mockStorage.RecordAttributeSnapshot(&models.AttributeSnapshot{
+		EntityType: models.SnapshotEntityResource,
+		EntityID:   "api:documents:plan.pdf",
+		Attributes: models.JSONMap{"classification": "internal"},
+		ValidFrom:  transferDate.AddDate(0, -6, 0),
+	})
+
+	config := DefaultPDPConfig()
+	config.DenyCache = DefaultDenyCacheConfig()
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	newRequest := func() *models.EvaluationRequest {
+		return &models.EvaluationRequest{
+			RequestID:  "as-of-001",
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{"department": "Finance"}),
+			ResourceID: "api:documents:plan.pdf",
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+		}
+	}
+
+	// Current attributes deny (and the deny is cached)
+	decision, err := pdp.Evaluate(newRequest())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultDeny {
+		t.Fatalf("Expected deny with current attributes, got %s", decision.Result)
+	}
+
+	// As of the original decision the subject and resource attributes permit; the cached deny is bypassed
+	asOfRequest := newRequest()
+	asOfRequest.AsOf = &decisionTime
+	decision, err = pdp.Evaluate(asOfRequest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected permit as of %s, got %s (%s)", decisionTime, decision.Result, decision.Reason)
+	}
+
+	explanation, err := pdp.Explain(asOfRequest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if explanation.AsOf == nil || !explanation.AsOf.Equal(decisionTime) {
+		t.Errorf("Expected explanation as_of %s, got %v", decisionTime, explanation.AsOf)
+	}
+	if explanation.AttributeSources["subject"] != models.AttributeSourceHistory ||
+		explanation.AttributeSources["resource"] != models.AttributeSourceHistory {
+		t.Errorf("Expected history attribute sources, got %v", explanation.AttributeSources)
+	}
+
+	// Before any snapshot exists the current attributes are used
+	beforeHistory := transferDate.AddDate(-1, 0, 0)
+	asOfRequest.AsOf = &beforeHistory
+	explanation, err = pdp.Explain(asOfRequest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if explanation.Decision.Result != constants.ResultDeny || explanation.AttributeSources["subject"] != models.AttributeSourceCurrent {
+		t.Errorf("Expected deny from current attributes, got %s with sources %v", explanation.Decision.Result, explanation.AttributeSources)
+	}
+
+	// Request-supplied snapshots take precedence over history
+	snapshotRequest := newRequest()
+	snapshotRequest.AsOf = &decisionTime
+	snapshotRequest.Snapshots = &models.AttributeSnapshots{
+		Subject: map[string]interface{}{"department": "Sales"},
+	}
+	explanation, err = pdp.Explain(snapshotRequest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if explanation.Decision.Result != constants.ResultDeny {
+		t.Errorf("Expected deny for supplied Sales snapshot, got %s", explanation.Decision.Result)
+	}
+	if explanation.AttributeSources["subject"] != models.AttributeSourceRequest ||
+		explanation.AttributeSources["resource"] != models.AttributeSourceHistory {
+		t.Errorf("Unexpected attribute sources: %v", explanation.AttributeSources)
+	}
+
+	// The stored resource is never rewritten by point-in-time evaluation
+	resource, _ := mockStorage.GetResource("api:documents:plan.pdf")
+	if resource.Attributes["classification"] != "confidential" {
+		t.Errorf("Stored resource was mutated: %v", resource.Attributes)
+	}
+}
@@ -2,19 +2,23 @@ package core
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"time"
 
 	"abac_go_example/constants"
+	"abac_go_example/evaluator/conditions"
 	"abac_go_example/models"
+	"abac_go_example/operators"
+	"abac_go_example/quota"
 )
 
 // PolicyValidator validates policies against schema and business rules
 type PolicyValidator struct {
-	timeZones        map[string]bool
-	allowedEffects   map[string]bool
-	allowedOperators map[string]bool
+	timeZones      map[string]bool
+	allowedEffects map[string]bool
+	limits         *PolicyLimits
 }
 
 // NewPolicyValidator creates a new policy validator
@@ -31,23 +35,17 @@ func NewPolicyValidator() *PolicyValidator {
 		allowedEffects: map[string]bool{
 			"Allow": true,
 			"Deny":  true,
+			"Mask":  true,
 		},
-		allowedOperators: map[string]bool{
-			"eq":       true,
-			"ne":       true,
-			"gt":       true,
-			"gte":      true,
-			"lt":       true,
-			"lte":      true,
-			"in":       true,
-			"nin":      true,
-			"contains": true,
-			"regex":    true,
-			"exists":   true,
-		},
+		limits: DefaultPolicyLimits(),
 	}
 }
 
+// SetLimits replaces the policy limits enforced by ValidatePolicy (nil disables limit checks)
+func (pv *PolicyValidator) SetLimits(limits *PolicyLimits) {
+	pv.limits = limits
+}
+
 // ValidationError represents a policy validation error
 type ValidationError struct {
 	Field   string      `json:"field"`
@@ -78,6 +76,11 @@ func (pv *PolicyValidator) ValidatePolicy(policy *models.Policy) error {
 	// Statement validation
 	pv.validateStatements(policy.Statement, result)
 
+	// Size and complexity limits
+	for _, violation := range pv.limits.Check(policy) {
+		pv.addError(result, violation.Field, violation.Message, violation.Value)
+	}
+
 	if !result.Valid {
 		return fmt.Errorf("policy validation failed: %v", result.Errors)
 	}
@@ -125,6 +128,18 @@ func (pv *PolicyValidator) validateBasicFields(policy *models.Policy, result *Va
 	if len(policy.Statement) == 0 {
 		pv.addError(result, "statement", "at least one statement is required", len(policy.Statement))
 	}
+
+	if policy.EffectiveFrom != nil && policy.ExpiresAt != nil && !policy.ExpiresAt.After(*policy.EffectiveFrom) {
+		pv.addError(result, "expires_at", "expires_at must be after effective_from", policy.ExpiresAt)
+	}
+
+	if policy.RolloutPercent != nil && (*policy.RolloutPercent < 0 || *policy.RolloutPercent > 100) {
+		pv.addError(result, "rollout_percent", "rollout_percent must be between 0 and 100", *policy.RolloutPercent)
+	}
+
+	if len(policy.Namespace) > constants.MaxPolicyNamespaceLen || strings.TrimSpace(policy.Namespace) != policy.Namespace {
+		pv.addError(result, "namespace", fmt.Sprintf("namespace must be at most %d characters without surrounding spaces", constants.MaxPolicyNamespaceLen), policy.Namespace)
+	}
 }
 
 // validateStatements validates policy statements
@@ -134,7 +149,12 @@ func (pv *PolicyValidator) validateStatements(statements []models.PolicyStatemen
 
 		// Validate effect
 		if !pv.allowedEffects[stmt.Effect] {
-			pv.addError(result, fieldPrefix+".effect", "invalid effect, must be 'Allow' or 'Deny'", stmt.Effect)
+			pv.addError(result, fieldPrefix+".effect", "invalid effect, must be 'Allow', 'Deny' or 'Mask'", stmt.Effect)
+		}
+
+		// Mask only makes sense per field
+		if stmt.Effect == "Mask" && !stmt.IsFieldLevel() {
+			pv.addError(result, fieldPrefix+".fields", "Mask effect requires Fields", stmt.Effect)
 		}
 
 		// Validate action
@@ -145,6 +165,31 @@ func (pv *PolicyValidator) validateStatements(statements []models.PolicyStatemen
 
 		// Validate conditions
 		pv.validateConditions(stmt.Condition, fieldPrefix+".condition", result)
+		for _, invalid := range conditions.InvalidRegexes(stmt.Condition) {
+			pv.addError(result, fieldPrefix+".condition."+invalid.Operator+"."+invalid.Key, "invalid regex: "+invalid.Message, invalid.Pattern)
+		}
+
+		// Validate obligations
+		for name, value := range stmt.Obligations {
+			values, known := knownObligations[name]
+			if !known {
+				pv.addError(result, fieldPrefix+".obligations."+name, "unknown obligation", name)
+			} else if _, ok := values[value]; !ok {
+				pv.addError(result, fieldPrefix+".obligations."+name, "unsupported obligation value", value)
+			}
+		}
+
+		// Validate constraints; they only narrow resource-level permits
+		if stmt.Constraints != nil {
+			if stmt.Effect != "Allow" || stmt.IsFieldLevel() {
+				pv.addError(result, fieldPrefix+".constraints", "constraints require a resource-level Allow statement", stmt.Effect)
+			}
+			for j, field := range stmt.Constraints.Fields {
+				if field == "" {
+					pv.addError(result, fmt.Sprintf("%s.constraints.fields[%d]", fieldPrefix, j), "value cannot be empty", field)
+				}
+			}
+		}
 	}
 }
 
@@ -194,7 +239,7 @@ func (pv *PolicyValidator) validateOperatorConditions(operator string, condition
 		fieldName := fieldPrefix + "." + key
 
 		// Validate based on operator type
-		switch constants.ConditionOperatorType(operator) {
+		switch constants.ConditionOperatorType(operator).Canonical() {
 		case constants.ConditionStringEquals, constants.ConditionStringNotEquals, constants.ConditionStringLike:
 			if _, ok := value.(string); !ok {
 				pv.addError(result, fieldName, "value must be a string for string operators", value)
@@ -203,24 +248,63 @@ func (pv *PolicyValidator) validateOperatorConditions(operator string, condition
 			if !pv.isNumeric(value) {
 				pv.addError(result, fieldName, "value must be numeric for numeric operators", value)
 			}
-		case constants.ConditionBool:
+		case constants.ConditionVersionLessThan, constants.ConditionVersionLessThanEquals,
+			constants.ConditionVersionGreaterThan, constants.ConditionVersionGreaterThanEquals:
+			if version, ok := value.(string); !ok {
+				pv.addError(result, fieldName, "value must be a version string for version operators", value)
+			} else if _, valid := operators.CompareVersions(version, version); !valid {
+				pv.addError(result, fieldName, "invalid version, expected dotted numbers (e.g. \"120.0\")", value)
+			}
+		case constants.ConditionBool, constants.ConditionNull:
+			if _, ok := value.(bool); !ok {
+				pv.addError(result, fieldName, "value must be boolean for "+operator+" operator", value)
+			}
+		case constants.ConditionIsBusinessHours, constants.ConditionIsHoliday:
 			if _, ok := value.(bool); !ok {
-				pv.addError(result, fieldName, "value must be boolean for Bool operator", value)
+				pv.addError(result, fieldName, "value must be boolean for "+operator+" operator", value)
 			}
-		case constants.ConditionIpAddress:
+		case constants.ConditionIPInRange, constants.ConditionIPNotInRange:
 			if !pv.isValidIPOrCIDR(value) {
-				pv.addError(result, fieldName, "value must be valid IP address or CIDR", value)
+				pv.addError(result, fieldName, "value must be valid IP address or CIDR, or an array of them", value)
+			}
+		case constants.ConditionIssuedByCA:
+			if !pv.isValidCAList(value) {
+				pv.addError(result, fieldName, "value must be a CA fingerprint or subject DN, or an array of them", value)
 			}
 		case constants.ConditionDateGreaterThan, constants.ConditionDateLessThan:
 			if !pv.isValidDateString(value) {
 				pv.addError(result, fieldName, "value must be valid date string", value)
 			}
+		case constants.ConditionTimeBetween:
+			if err := pv.validateTimeRange(value); err != nil {
+				pv.addError(result, fieldName, err.Error(), value)
+			}
+		case constants.ConditionTimeOfDay:
+			if err := pv.validateTimeOfDay(value); err != nil {
+				pv.addError(result, fieldName, err.Error(), value)
+			}
+		case constants.ConditionAuthAgeLessThan:
+			if !pv.isValidAuthAge(value) {
+				pv.addError(result, fieldName, "value must be a positive duration (e.g. \"15m\") or number of seconds", value)
+			}
 		case constants.ConditionAnd, constants.ConditionOr:
 			// For logical operators, validate the nested conditions
 			pv.validateLogicalOperatorConditions(operator, value, fieldName, result)
 		case constants.ConditionNot:
 			// For NOT operator, validate the single nested condition
 			pv.validateNotOperatorCondition(value, fieldName, result)
+		case constants.ConditionRequestRateBelow:
+			if _, err := quota.ParseLimits(quota.KindRate, map[string]interface{}{key: value}); err != nil {
+				pv.addError(result, fieldName, err.Error(), value)
+			}
+		case constants.ConditionDailyQuotaBelow:
+			if _, err := quota.ParseLimits(quota.KindDaily, map[string]interface{}{key: value}); err != nil {
+				pv.addError(result, fieldName, err.Error(), value)
+			}
+		case constants.ConditionResourceTag:
+			if err := validateResourceTagCondition(key, value); err != nil {
+				pv.addError(result, fieldName, err.Error(), value)
+			}
 		}
 	}
 }
@@ -244,7 +328,7 @@ func (pv *PolicyValidator) validateRuleFields(rule *models.PolicyRule, result *V
 		pv.addError(result, "operator", "operator is required", rule.Operator)
 	}
 
-	if !pv.allowedOperators[rule.Operator] {
+	if !conditions.IsLegacyOperator(rule.Operator) {
 		pv.addError(result, "operator", "invalid operator", rule.Operator)
 	}
 }
@@ -382,6 +466,53 @@ func (pv *PolicyValidator) isNumeric(value interface{}) bool {
 	return false
 }
 
+// validateResourceTagCondition checks one ResourceTag key and its accepted value(s)
+func validateResourceTagCondition(key string, value interface{}) error {
+	_, err := conditions.ParseTagConditions(map[string]interface{}{key: value})
+	return err
+}
+
+// validateTimeRange checks a TimeBetween value: a clock window or an absolute [start, end] range
+func (pv *PolicyValidator) validateTimeRange(value interface{}) error {
+	_, err := conditions.ParseClockWindow(value)
+	if err == nil {
+		return nil
+	}
+	if bounds, ok := value.([]interface{}); ok && len(bounds) == 2 && pv.isValidDateString(bounds[0]) && pv.isValidDateString(bounds[1]) {
+		return nil
+	}
+	return err
+}
+
+// validateTimeOfDay checks a TimeOfDay value: an exact "HH:MM" time or a clock window
+func (pv *PolicyValidator) validateTimeOfDay(value interface{}) error {
+	if str, ok := value.(string); ok && !strings.Contains(str, "-") {
+		if !pv.isValidTimeFormat(str) {
+			return fmt.Errorf("invalid time format, expected HH:MM")
+		}
+		return nil
+	}
+	_, err := conditions.ParseClockWindow(value)
+	return err
+}
+
+// isValidAuthAge checks an AuthAgeLessThan limit: a positive duration string or number of seconds
+func (pv *PolicyValidator) isValidAuthAge(value interface{}) bool {
+	if str, ok := value.(string); ok {
+		duration, err := time.ParseDuration(str)
+		return err == nil && duration > 0
+	}
+	switch v := value.(type) {
+	case int:
+		return v > 0
+	case int64:
+		return v > 0
+	case float64:
+		return v > 0
+	}
+	return false
+}
+
 func (pv *PolicyValidator) isValidIPOrCIDR(value interface{}) bool {
 	switch v := value.(type) {
 	case string:
@@ -394,6 +525,36 @@ func (pv *PolicyValidator) isValidIPOrCIDR(value interface{}) bool {
 
 		// Single IP
 		return parseIP(v) != nil
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		for _, item := range v {
+			if !pv.isValidIPOrCIDR(item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidCAList accepts a non-empty CA fingerprint or subject DN, or a non-empty array of them
+func (pv *PolicyValidator) isValidCAList(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		for _, item := range v {
+			if ca, ok := item.(string); !ok || strings.TrimSpace(ca) == "" {
+				return false
+			}
+		}
+		return true
 	default:
 		return false
 	}
@@ -446,12 +607,10 @@ func parseFloat(s string) (float64, error) {
 	return 0, fmt.Errorf("not implemented")
 }
 
-func parseIP(s string) interface{} {
-	// This would use net.ParseIP in real implementation
-	return nil
+func parseIP(s string) net.IP {
+	return net.ParseIP(s)
 }
 
-func parseIPNet(s string) (interface{}, interface{}, error) {
-	// This would use net.ParseCIDR in real implementation
-	return nil, nil, fmt.Errorf("not implemented")
+func parseIPNet(s string) (net.IP, *net.IPNet, error) {
+	return net.ParseCIDR(s)
 }
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PolicyValidityWindow tests that policies are only evaluated between EffectiveFrom and ExpiresAt
+func TestPDP_PolicyValidityWindow(t *testing.T) {
+	lockdownStart := time.Date(2024, time.December, 24, 0, 0, 0, 0, time.UTC)
+	lockdownEnd := time.Date(2024, time.December, 27, 0, 0, 0, 0, time.UTC)
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-allow-read",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+			},
+		},
+		{
+			ID:            "pol-holiday-lockdown",
+			Enabled:       true,
+			EffectiveFrom: &lockdownStart,
+			ExpiresAt:     &lockdownEnd,
+			Statement: []models.PolicyStatement{
+				{Sid: "HolidayLockdown", Effect: "Deny", Action: models.JSONActionResource{Single: "*"}, Resource: models.JSONActionResource{Single: "*"}},
+			},
+		},
+	})
+
+	mockClock := clock.NewMockClock(lockdownStart.Add(-time.Hour))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := &models.EvaluationRequest{
+		RequestID:  "window-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+		Context:    map[string]interface{}{},
+	}
+
+	evaluate := func(expected string) {
+		t.Helper()
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != expected {
+			t.Fatalf("At %s expected %s, got %s (%s)", mockClock.Now(), expected, decision.Result, decision.Reason)
+		}
+	}
+
+	evaluate(constants.ResultPermit)
+
+	mockClock.Set(lockdownStart)
+	evaluate(constants.ResultDeny)
+
+	mockClock.Set(lockdownEnd)
+	evaluate(constants.ResultPermit)
+
+	// An explicit request timestamp takes precedence over the clock
+	inLockdown := lockdownStart.Add(time.Hour)
+	request.Timestamp = &inLockdown
+	evaluate(constants.ResultDeny)
+
+	// Explain only traces policies inside their window
+	request.Timestamp = nil
+	explanation, err := pdp.Explain(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, trace := range explanation.Statements {
+		if trace.PolicyID == "pol-holiday-lockdown" {
+			t.Error("Expected expired policy to be excluded from the explanation")
+		}
+	}
+}
+
+func TestPolicyValidatorValidityWindow(t *testing.T) {
+	from := time.Date(2024, time.December, 24, 0, 0, 0, 0, time.UTC)
+	policy := &models.Policy{
+		ID:            "pol-001",
+		PolicyName:    "Inverted window",
+		Version:       "2024-10-21",
+		EffectiveFrom: &from,
+		ExpiresAt:     &from,
+		Statement: []models.PolicyStatement{
+			{Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "*"}},
+		},
+	}
+
+	err := NewPolicyValidator().ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "expires_at") {
+		t.Errorf("Expected validity window error, got %v", err)
+	}
+}
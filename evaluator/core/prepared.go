@@ -0,0 +1,27 @@
+package core
+
+import (
+	"abac_go_example/attributes"
+	"abac_go_example/models"
+)
+
+// PrepareSubjectContext enriches the subject once at session start and keeps the result for the
+// session: evaluations carrying that session ID reuse it instead of enriching the subject again
+func (pdp *PolicyDecisionPoint) PrepareSubjectContext(sessionID string, subject models.SubjectInterface) (*attributes.PreparedSubject, error) {
+	return pdp.attributeResolver.PrepareSubject(sessionID, subject)
+}
+
+// GetPreparedSubjectContext returns the prepared context of a session, when it was prepared for subjectID and has not expired
+func (pdp *PolicyDecisionPoint) GetPreparedSubjectContext(sessionID, subjectID string) (*attributes.PreparedSubject, bool) {
+	prepared := pdp.attributeResolver.GetPreparedSubjects()
+	if prepared == nil || sessionID == "" {
+		return nil, false
+	}
+	return prepared.Get(sessionID, subjectID)
+}
+
+// ReleaseSubjectContext drops the prepared context of a session; false when there was none
+func (pdp *PolicyDecisionPoint) ReleaseSubjectContext(sessionID string) bool {
+	prepared := pdp.attributeResolver.GetPreparedSubjects()
+	return prepared != nil && prepared.Release(sessionID)
+}
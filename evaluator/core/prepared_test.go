@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/attributes"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func TestPDP_PreparedSubjectContext(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "invoice:approve", ActionName: "invoice:approve"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:invoices:inv-1", ResourceID: "api:invoices:inv-1"})
+	mockStorage.CreateGroup(&models.Group{ID: "finance-approvers"})
+	mockStorage.AddGroupMember("finance-approvers", models.GroupMemberSubject, "user-1")
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-approvers",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:       "FinanceApprovers",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "invoice:approve"},
+					Resource:  models.JSONActionResource{Single: "api:invoices:*"},
+					Condition: map[string]interface{}{"ArrayContains": map[string]interface{}{"user.groups": "finance-approvers"}},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	subject := models.NewMockUserSubject("user-1", "user-1")
+	if _, err := pdp.PrepareSubjectContext("sess-1", subject); err != nil {
+		t.Fatalf("Failed to prepare subject context: %v", err)
+	}
+
+	// Membership removed after the session started is not seen until the subject is invalidated
+	mockStorage.RemoveGroupMember("finance-approvers", models.GroupMemberSubject, "user-1")
+	evaluate := func() string {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "prepared-1",
+			Subject:    subject,
+			ResourceID: "api:invoices:inv-1",
+			Action:     "invoice:approve",
+			Context:    map[string]interface{}{},
+			Session:    &models.SessionInfo{SessionID: "sess-1"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+	if result := evaluate(); result != constants.ResultPermit {
+		t.Errorf("Expected the prepared groups to permit, got %s", result)
+	}
+
+	pdp.InvalidateAttributeCache(attributes.EntitySubject, "user-1")
+	if _, ok := pdp.GetPreparedSubjectContext("sess-1", "user-1"); ok {
+		t.Error("Expected subject invalidation to drop the prepared context")
+	}
+	if result := evaluate(); result != constants.ResultDeny {
+		t.Errorf("Expected current groups after invalidation, got %s", result)
+	}
+
+	pdp.PrepareSubjectContext("sess-1", subject)
+	if !pdp.ReleaseSubjectContext("sess-1") || pdp.ReleaseSubjectContext("sess-1") {
+		t.Error("Expected ReleaseSubjectContext to report whether the session was prepared")
+	}
+}
@@ -0,0 +1,274 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// DefaultQuarantineConsecutive is how many consecutive slow evaluations quarantine a policy
+const DefaultQuarantineConsecutive = 5
+
+// DefaultQuarantineDuration is how long a policy stays quarantined before it is evaluated again
+const DefaultQuarantineDuration = 10 * time.Minute
+
+// QuarantineConfig configures policy quarantine: a policy whose evaluations consistently take longer
+// than Threshold is skipped, contributing Indeterminate, so one bad policy cannot slow down every request
+type QuarantineConfig struct {
+	Threshold   time.Duration `json:"threshold"`   // Evaluation time of one policy counted as slow
+	Consecutive int           `json:"consecutive"` // Consecutive slow evaluations that quarantine a policy; 0 or less uses 1
+	// Duration is how long a policy stays quarantined; 0 keeps it quarantined until released via the API.
+	// Updating the policy (a new revision) always releases it.
+	Duration time.Duration `json:"duration"`
+	// Alert is called when a policy is quarantined, e.g. to page on-call. Nil only logs the alert.
+	Alert func(QuarantinedPolicy) `json:"-"`
+}
+
+// QuarantineConfigFromEnv reads ABAC_POLICY_QUARANTINE_THRESHOLD, ABAC_POLICY_QUARANTINE_CONSECUTIVE and
+// ABAC_POLICY_QUARANTINE_DURATION. It returns nil (quarantine disabled) when the threshold is unset.
+func QuarantineConfigFromEnv() (*QuarantineConfig, error) {
+	value := os.Getenv(constants.EnvPolicyQuarantineThreshold)
+	if value == "" {
+		return nil, nil
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		return nil, fmt.Errorf("invalid %s %q: expected a duration such as \"50ms\"", constants.EnvPolicyQuarantineThreshold, value)
+	}
+	config := &QuarantineConfig{
+		Threshold:   threshold,
+		Consecutive: DefaultQuarantineConsecutive,
+		Duration:    DefaultQuarantineDuration,
+	}
+
+	if value := os.Getenv(constants.EnvPolicyQuarantineConsecutive); value != "" {
+		consecutive, err := strconv.Atoi(value)
+		if err != nil || consecutive <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive integer", constants.EnvPolicyQuarantineConsecutive, value)
+		}
+		config.Consecutive = consecutive
+	}
+	if value := os.Getenv(constants.EnvPolicyQuarantineDuration); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a duration such as \"10m\"", constants.EnvPolicyQuarantineDuration, value)
+		}
+		config.Duration = duration
+	}
+	return config, nil
+}
+
+// QuarantinedPolicy describes a policy skipped by evaluation because it was consistently slow
+type QuarantinedPolicy struct {
+	PolicyID         string     `json:"policy_id"`
+	Revision         int64      `json:"revision"` // Policy revision quarantined; a new revision is evaluated again
+	QuarantinedAt    time.Time  `json:"quarantined_at"`
+	ReleaseAt        *time.Time `json:"release_at,omitempty"` // Nil when the policy stays quarantined until released via the API
+	SlowEvaluations  int        `json:"slow_evaluations"`     // Consecutive evaluations over the threshold
+	LastEvaluationMs float64    `json:"last_evaluation_ms"`
+}
+
+// quarantineEntry holds the slow evaluation streak and quarantine state of a policy
+type quarantineEntry struct {
+	slow        int
+	revision    int64
+	last        time.Duration
+	quarantined *QuarantinedPolicy
+}
+
+// PolicyQuarantine tracks per-policy evaluation times and quarantines policies that exceed the
+// threshold in consecutive evaluations. It is safe for concurrent use.
+type PolicyQuarantine struct {
+	mu      sync.Mutex
+	config  QuarantineConfig
+	clock   clock.Clock
+	entries map[string]*quarantineEntry
+}
+
+// NewPolicyQuarantine creates a quarantine tracker; a nil clock uses the system clock
+func NewPolicyQuarantine(config *QuarantineConfig, c clock.Clock) *PolicyQuarantine {
+	quarantine := &PolicyQuarantine{
+		clock:   clock.OrReal(c),
+		entries: make(map[string]*quarantineEntry),
+	}
+	if config != nil {
+		quarantine.config = *config
+	}
+	if quarantine.config.Consecutive <= 0 {
+		quarantine.config.Consecutive = 1
+	}
+	return quarantine
+}
+
+// Record records one evaluation of policy taking elapsed, quarantining the policy when this
+// evaluation completes a streak of slow evaluations
+func (q *PolicyQuarantine) Record(policy *models.Policy, elapsed time.Duration) {
+	if q.config.Threshold <= 0 {
+		return
+	}
+	now := q.clock.Now()
+
+	q.mu.Lock()
+	entry, exists := q.entries[policy.ID]
+	if !exists || entry.revision != policy.Revision {
+		entry = &quarantineEntry{revision: policy.Revision}
+		q.entries[policy.ID] = entry
+	}
+	entry.last = elapsed
+	if elapsed <= q.config.Threshold {
+		entry.slow = 0
+		q.mu.Unlock()
+		return
+	}
+	entry.slow++
+	if entry.quarantined != nil || entry.slow < q.config.Consecutive {
+		q.mu.Unlock()
+		return
+	}
+
+	quarantined := &QuarantinedPolicy{
+		PolicyID:         policy.ID,
+		Revision:         policy.Revision,
+		QuarantinedAt:    now,
+		SlowEvaluations:  entry.slow,
+		LastEvaluationMs: durationMilliseconds(elapsed),
+	}
+	if q.config.Duration > 0 {
+		releaseAt := now.Add(q.config.Duration)
+		quarantined.ReleaseAt = &releaseAt
+	}
+	entry.quarantined = quarantined
+	alert := *quarantined
+	q.mu.Unlock()
+
+	log.Printf("🚨 Alert: policy quarantined policy_id=%s revision=%d slow_evaluations=%d last_evaluation_ms=%.3f threshold=%s",
+		alert.PolicyID, alert.Revision, alert.SlowEvaluations, alert.LastEvaluationMs, q.config.Threshold)
+	if q.config.Alert != nil {
+		q.config.Alert(alert)
+	}
+}
+
+// IsQuarantined reports whether policy is skipped by evaluation. Quarantines of earlier
+// revisions and quarantines past their release time end here.
+func (q *PolicyQuarantine) IsQuarantined(policy *models.Policy) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, exists := q.entries[policy.ID]
+	if !exists || entry.quarantined == nil {
+		return false
+	}
+	if entry.revision != policy.Revision {
+		delete(q.entries, policy.ID)
+		log.Printf("Policy quarantine released policy_id=%s reason=revision %d replaced %d", policy.ID, policy.Revision, entry.revision)
+		return false
+	}
+	if releaseAt := entry.quarantined.ReleaseAt; releaseAt != nil && !q.clock.Now().Before(*releaseAt) {
+		delete(q.entries, policy.ID)
+		log.Printf("Policy quarantine released policy_id=%s reason=expired", policy.ID)
+		return false
+	}
+	return true
+}
+
+// Release ends the quarantine of policyID and its slow evaluation streak.
+// It returns false when the policy is not quarantined.
+func (q *PolicyQuarantine) Release(policyID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, exists := q.entries[policyID]
+	if !exists || entry.quarantined == nil {
+		return false
+	}
+	delete(q.entries, policyID)
+	return true
+}
+
+// Quarantined returns the quarantined policies, most recently quarantined first
+func (q *PolicyQuarantine) Quarantined() []QuarantinedPolicy {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	policies := make([]QuarantinedPolicy, 0)
+	for _, entry := range q.entries {
+		if entry.quarantined != nil {
+			policies = append(policies, *entry.quarantined)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if !policies[i].QuarantinedAt.Equal(policies[j].QuarantinedAt) {
+			return policies[i].QuarantinedAt.After(policies[j].QuarantinedAt)
+		}
+		return policies[i].PolicyID < policies[j].PolicyID
+	})
+	return policies
+}
+
+// quarantinedPolicies splits policies into those to evaluate and the IDs of quarantined policies
+// that apply to the request. A quarantined policy applies when one of its statements matches the
+// requested action and resource; mayDeny reports whether one of those is a Deny statement.
+func (pdp *PolicyDecisionPoint) quarantinedPolicies(policies []*models.Policy, context map[string]interface{}) (active []*models.Policy, indeterminate []string, mayDeny bool) {
+	if pdp.quarantine == nil {
+		return policies, nil, false
+	}
+
+	active = policies[:0:0]
+	for _, policy := range policies {
+		if !policy.Enabled || !pdp.quarantine.IsQuarantined(policy) {
+			active = append(active, policy)
+			continue
+		}
+
+		applies := false
+		for _, statement := range policy.Statement {
+			if statement.IsFieldLevel() ||
+				!pdp.isActionMatched(statement.Action, statement.Effect, context) ||
+				!pdp.isResourceMatched(statement, context) {
+				continue
+			}
+			applies = true
+			if strings.ToLower(statement.Effect) == constants.EffectDeny {
+				mayDeny = true
+			}
+		}
+		if applies {
+			indeterminate = append(indeterminate, policy.ID)
+		}
+	}
+	return active, indeterminate, mayDeny
+}
+
+// quarantineDecision applies the Indeterminate contribution of quarantined policies to decision:
+// under deny-override a quarantined policy that may deny turns a permit into a deny (fail closed),
+// otherwise the decision of the evaluated policies stands. Both name the skipped policies.
+func quarantineDecision(decision *models.Decision, allowStatements []models.PolicyStatement, indeterminate []string, mayDeny bool) (*models.Decision, []models.PolicyStatement) {
+	decision.QuarantinedPolicies = indeterminate
+	if !mayDeny || decision.Result != constants.ResultPermit {
+		return decision, allowStatements
+	}
+
+	policies := strings.Join(indeterminate, ", ")
+	return &models.Decision{
+		Result:              constants.ResultDeny,
+		MatchedPolicies:     []string{},
+		Reason:              fmt.Sprintf(constants.ReasonPolicyQuarantined, policies),
+		ReasonCode:          constants.ReasonCodePolicyQuarantined,
+		ReasonDetails:       map[string]string{constants.ReasonDetailPolicy: policies},
+		QuarantinedPolicies: indeterminate,
+	}, nil
+}
+
+// durationMilliseconds converts d to fractional milliseconds
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
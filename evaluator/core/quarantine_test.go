@@ -0,0 +1,214 @@
+package core
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPolicyQuarantine_Record tests slow evaluation streaks, alerts, expiry, revisions and release
+func TestPolicyQuarantine_Record(t *testing.T) {
+	mockClock := clock.NewMockClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	var alerts []QuarantinedPolicy
+	quarantine := NewPolicyQuarantine(&QuarantineConfig{
+		Threshold:   10 * time.Millisecond,
+		Consecutive: 3,
+		Duration:    10 * time.Minute,
+		Alert:       func(policy QuarantinedPolicy) { alerts = append(alerts, policy) },
+	}, mockClock)
+
+	var logs bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(original)
+
+	policy := &models.Policy{ID: "pol-slow", Revision: 1}
+
+	// A fast evaluation breaks the streak
+	quarantine.Record(policy, 20*time.Millisecond)
+	quarantine.Record(policy, 20*time.Millisecond)
+	quarantine.Record(policy, 5*time.Millisecond)
+	quarantine.Record(policy, 20*time.Millisecond)
+	quarantine.Record(policy, 20*time.Millisecond)
+	if quarantine.IsQuarantined(policy) {
+		t.Fatal("Expected no quarantine before 3 consecutive slow evaluations")
+	}
+
+	quarantine.Record(policy, 25*time.Millisecond)
+	quarantine.Record(policy, 25*time.Millisecond)
+	if !quarantine.IsQuarantined(policy) {
+		t.Fatal("Expected quarantine after 3 consecutive slow evaluations")
+	}
+	if len(alerts) != 1 || alerts[0].PolicyID != "pol-slow" || alerts[0].SlowEvaluations != 3 || alerts[0].LastEvaluationMs != 25 {
+		t.Errorf("Expected one alert for pol-slow, got %+v", alerts)
+	}
+	if !strings.Contains(logs.String(), "policy quarantined policy_id=pol-slow") {
+		t.Errorf("Expected an alert log line, got %q", logs.String())
+	}
+	listed := quarantine.Quarantined()
+	if len(listed) != 1 || listed[0].ReleaseAt == nil || !listed[0].ReleaseAt.Equal(mockClock.Now().Add(10*time.Minute)) {
+		t.Errorf("Unexpected quarantined policies: %+v", listed)
+	}
+
+	// Released once the quarantine duration has elapsed
+	mockClock.Advance(10 * time.Minute)
+	if quarantine.IsQuarantined(policy) {
+		t.Error("Expected the quarantine to expire")
+	}
+
+	// A new revision is evaluated again
+	for i := 0; i < 3; i++ {
+		quarantine.Record(policy, time.Second)
+	}
+	if !quarantine.IsQuarantined(policy) {
+		t.Fatal("Expected quarantine again")
+	}
+	if quarantine.IsQuarantined(&models.Policy{ID: "pol-slow", Revision: 2}) {
+		t.Error("Expected an updated policy to be released")
+	}
+
+	// Released via the API
+	for i := 0; i < 3; i++ {
+		quarantine.Record(policy, time.Second)
+	}
+	if !quarantine.Release("pol-slow") || quarantine.IsQuarantined(policy) {
+		t.Error("Expected Release to end the quarantine")
+	}
+	if quarantine.Release("pol-slow") {
+		t.Error("Expected Release of a policy that is not quarantined to return false")
+	}
+}
+
+// TestPDP_Quarantine tests that quarantined policies are skipped with an Indeterminate contribution
+func TestPDP_Quarantine(t *testing.T) {
+	allow := &models.Policy{
+		ID:      "pol-allow",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      "ReadDocuments",
+				Effect:   "Allow",
+				Action:   models.JSONActionResource{Single: "document:read"},
+				Resource: models.JSONActionResource{Single: "api:documents:*"},
+			},
+		},
+	}
+	deny := &models.Policy{
+		ID:      "pol-deny",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:       "DenyContractors",
+				Effect:    "Deny",
+				Action:    models.JSONActionResource{Single: "document:read"},
+				Resource:  models.JSONActionResource{Single: "api:documents:*"},
+				Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.employment_type": "contractor"}},
+			},
+		},
+	}
+	extraAllow := &models.Policy{
+		ID:      "pol-reports",
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      "ReadReports",
+				Effect:   "Allow",
+				Action:   models.JSONActionResource{Single: "report:read"},
+				Resource: models.JSONActionResource{Single: "api:reports:*"},
+			},
+		},
+	}
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{allow, deny, extraAllow})
+
+	config := DefaultPDPConfig()
+	config.Quarantine = &QuarantineConfig{Threshold: time.Second, Consecutive: 1}
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config).(*PolicyDecisionPoint)
+
+	var logs bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(original)
+
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("user-123", map[string]interface{}{"employment_type": "employee"}),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	}
+	evaluate := func() *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		return decision
+	}
+
+	if decision := evaluate(); decision.Result != constants.ResultPermit || len(decision.QuarantinedPolicies) != 0 {
+		t.Fatalf("Expected a permit without quarantined policies, got %+v", decision)
+	}
+
+	// A quarantined policy that cannot deny leaves the decision to the other policies;
+	// a quarantined policy that does not apply to the request is not reported
+	pdp.quarantine.Record(extraAllow, 2*time.Second)
+	if decision := evaluate(); decision.Result != constants.ResultPermit || len(decision.QuarantinedPolicies) != 0 {
+		t.Errorf("Expected a permit, got %+v", decision)
+	}
+
+	// A quarantined policy that may deny turns the permit into a deny (fail closed)
+	pdp.quarantine.Record(deny, 2*time.Second)
+	decision := evaluate()
+	if decision.Result != constants.ResultDeny || decision.ReasonCode != constants.ReasonCodePolicyQuarantined ||
+		len(decision.QuarantinedPolicies) != 1 || decision.QuarantinedPolicies[0] != "pol-deny" {
+		t.Errorf("Expected an indeterminate deny naming pol-deny, got %+v", decision)
+	}
+
+	// Without the allow policy only the quarantined Allow remains: it cannot permit
+	pdp.ReleaseQuarantine("pol-deny")
+	pdp.quarantine.Record(allow, 2*time.Second)
+	decision = evaluate()
+	if decision.Result != constants.ResultDeny || decision.ReasonCode != constants.ReasonCodeImplicitDeny ||
+		len(decision.QuarantinedPolicies) != 1 || decision.QuarantinedPolicies[0] != "pol-allow" {
+		t.Errorf("Expected an implicit deny naming pol-allow, got %+v", decision)
+	}
+
+	policies, enabled := pdp.GetQuarantinedPolicies()
+	if !enabled || len(policies) != 2 {
+		t.Errorf("Expected 2 quarantined policies, got %+v", policies)
+	}
+}
+
+// TestQuarantineConfigFromEnv tests quarantine environment variables
+func TestQuarantineConfigFromEnv(t *testing.T) {
+	if config, err := QuarantineConfigFromEnv(); err != nil || config != nil {
+		t.Errorf("Expected quarantine disabled without a threshold, got %+v, %v", config, err)
+	}
+
+	t.Setenv(constants.EnvPolicyQuarantineThreshold, "50ms")
+	config, err := QuarantineConfigFromEnv()
+	if err != nil || config.Threshold != 50*time.Millisecond ||
+		config.Consecutive != DefaultQuarantineConsecutive || config.Duration != DefaultQuarantineDuration {
+		t.Errorf("Unexpected default quarantine config: %+v, %v", config, err)
+	}
+
+	t.Setenv(constants.EnvPolicyQuarantineConsecutive, "3")
+	t.Setenv(constants.EnvPolicyQuarantineDuration, "0")
+	if config, err := QuarantineConfigFromEnv(); err != nil || config.Consecutive != 3 || config.Duration != 0 {
+		t.Errorf("Unexpected quarantine config: %+v, %v", config, err)
+	}
+
+	t.Setenv(constants.EnvPolicyQuarantineConsecutive, "zero")
+	if _, err := QuarantineConfigFromEnv(); err == nil {
+		t.Error("Expected an error for an invalid consecutive count")
+	}
+}
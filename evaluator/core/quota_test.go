@@ -0,0 +1,116 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_DailyQuota tests that a daily quota permits up to its limit and counts only permitted requests
+func TestPDP_DailyQuota(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "report:export", ActionName: "report:export"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:reports:monthly",
+		ResourceID: "api:reports:monthly",
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-quota",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ExportTwicePerDay",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "report:export"},
+					Resource: models.JSONActionResource{Single: "api:reports:*"},
+					Condition: map[string]interface{}{
+						"DailyQuotaBelow": map[string]interface{}{
+							"exports": float64(2),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	request := func(subjectID string) *models.EvaluationRequest {
+		return &models.EvaluationRequest{
+			RequestID:  "quota-001",
+			Subject:    models.CreateMockSubjectWithAttributes(subjectID, map[string]interface{}{}),
+			ResourceID: "api:reports:monthly",
+			Action:     "report:export",
+		}
+	}
+
+	// Explain is a dry run and must not consume the quota
+	if _, err := pdp.Explain(request("user-1")); err != nil {
+		t.Fatalf("Explain returned error: %v", err)
+	}
+
+	expected := []string{constants.ResultPermit, constants.ResultPermit, constants.ResultDeny, constants.ResultDeny}
+	for i, want := range expected {
+		decision, err := pdp.Evaluate(request("user-1"))
+		if err != nil {
+			t.Fatalf("request %d: Evaluate returned error: %v", i+1, err)
+		}
+		if decision.Result != want {
+			t.Errorf("request %d: expected %s, got %s", i+1, want, decision.Result)
+		}
+	}
+
+	// Counters are keyed by subject
+	decision, err := pdp.Evaluate(request("user-2"))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("expected another subject to have its own quota, got %s", decision.Result)
+	}
+}
+
+// TestPDP_QuotaWithoutProvider tests that quota conditions fail closed without a counter provider
+func TestPDP_QuotaWithoutProvider(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "api:call", ActionName: "api:call"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:orders", ResourceID: "api:orders"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-rate",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "RateLimited",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "api:call"},
+					Resource: models.JSONActionResource{Single: "api:*"},
+					Condition: map[string]interface{}{
+						"RequestRateBelow": map[string]interface{}{
+							"calls": map[string]interface{}{"limit": float64(10), "window": "1m"},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	config := DefaultPDPConfig()
+	config.CounterProvider = nil
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	decision, err := pdp.Evaluate(&models.EvaluationRequest{
+		RequestID:  "rate-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "api:orders",
+		Action:     "api:call",
+	})
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if decision.Result != constants.ResultDeny {
+		t.Errorf("expected deny without counter provider, got %s", decision.Result)
+	}
+}
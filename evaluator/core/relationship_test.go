@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_RelationshipAttributes tests owner/team relationships resolved during enrichment
+func TestPDP_RelationshipAttributes(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	for _, action := range []string{"document:read", "document:delete"} {
+		mockStorage.CreateAction(&models.Action{ID: action, ActionName: action})
+	}
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:doc-1",
+		ResourceID: "api:documents:doc-1",
+		Attributes: models.JSONMap{"owner_id": "user-1", "team_id": "team-a"},
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-relationships",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:       "TeamRead",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "document:read"},
+					Resource:  models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{"Bool": map[string]interface{}{"relationship.same_team": true}},
+				},
+				{
+					Sid:       "OwnerDelete",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "document:delete"},
+					Resource:  models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{"Bool": map[string]interface{}{"relationship:is_owner": true}},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	evaluate := func(subjectID string, teams []interface{}, action string) string {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "rel-" + subjectID + "-" + action,
+			Subject:    models.NewMockUserSubject(subjectID, subjectID),
+			ResourceID: "api:documents:doc-1",
+			Action:     action,
+			Context: map[string]interface{}{
+				constants.ContextKeySubjectAttributes: map[string]interface{}{"team_ids": teams},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+
+	tests := []struct {
+		subjectID string
+		teams     []interface{}
+		action    string
+		expected  string
+	}{
+		{"user-1", []interface{}{"team-a"}, "document:delete", constants.ResultPermit},
+		{"user-2", []interface{}{"team-b", "team-a"}, "document:read", constants.ResultPermit},
+		{"user-2", []interface{}{"team-a"}, "document:delete", constants.ResultDeny},
+		{"user-3", []interface{}{"team-b"}, "document:read", constants.ResultDeny},
+	}
+
+	for _, tt := range tests {
+		if result := evaluate(tt.subjectID, tt.teams, tt.action); result != tt.expected {
+			t.Errorf("%s %s (teams %v): expected %s, got %s", tt.subjectID, tt.action, tt.teams, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// addResourceParams exposes the path parameters captured by URL template Resource patterns
+// ("/api/v1/users/{id}") matching the requested resource as resource.params, so conditions can
+// compare them. Parameters are captured before evaluation because the context must not change
+// afterwards; when templates capture different values for a name, the first policy in order wins.
+func (pdp *PolicyDecisionPoint) addResourceParams(policies []*models.Policy, evalContext map[string]interface{}) {
+	resourceID, _ := evalContext[constants.ContextKeyRequestResourceID].(string)
+	if resourceID == "" {
+		return
+	}
+
+	params := map[string]interface{}{}
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		for _, statement := range policy.Statement {
+			for _, pattern := range statement.Resource.GetValues() {
+				captured, ok := pdp.resourceMatcher.CaptureParams(pattern, resourceID, evalContext)
+				if !ok {
+					continue
+				}
+				for name, value := range captured {
+					if _, exists := params[name]; !exists {
+						params[name] = value
+					}
+				}
+			}
+		}
+	}
+	if len(params) == 0 {
+		return
+	}
+
+	evalContext[constants.ContextKeyResourceParams] = params
+	if resource, ok := evalContext["resource"].(map[string]interface{}); ok {
+		resource["params"] = params
+	}
+}
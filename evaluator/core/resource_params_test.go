@@ -0,0 +1,61 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_URLTemplateParams tests conditions on path parameters captured by URL template Resource patterns
+func TestPDP_URLTemplateParams(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	for _, resource := range []string{"/api/v1/users/42/documents/doc-7", "/api/v1/users/0/documents/doc-7", "/api/v1/users/42/documents/doc-8"} {
+		mockStorage.CreateResource(&models.Resource{ID: resource, ResourceID: resource})
+	}
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-user-documents",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:       "ReadSharedDocument",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "document:read"},
+					Resource:  models.JSONActionResource{Single: "/api/v1/users/{id}/documents/{docId}"},
+					Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"resource.params.docId": "doc-7"}},
+				},
+				{
+					Sid:       "DenySystemUser",
+					Effect:    "Deny",
+					Action:    models.JSONActionResource{Single: "document:read"},
+					Resource:  models.JSONActionResource{Single: "/api/v1/users/{id}/*/*"},
+					Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"resource.params.id": "0"}},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	for resource, expected := range map[string]string{
+		"/api/v1/users/42/documents/doc-7": constants.ResultPermit,
+		"/api/v1/users/42/documents/doc-8": constants.ResultDeny, // Condition on docId fails
+		"/api/v1/users/0/documents/doc-7":  constants.ResultDeny, // Explicit deny on id
+	} {
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "params-" + resource,
+			Subject:    models.NewMockUserSubject("user-1", "user-1"),
+			ResourceID: resource,
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != expected {
+			t.Errorf("%s: expected %s, got %s (%s)", resource, expected, decision.Result, decision.Reason)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_ResourceTagPolicies tests ResourceTag conditions and the tag pre-filter
+func TestPDP_ResourceTagPolicies(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:salaries", ResourceID: "api:documents:salaries", Tags: models.JSONStringSlice{"pii=true"}})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:handbook", ResourceID: "api:documents:handbook"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-allow-read",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+			},
+		},
+		{
+			ID:      "pol-pii",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "DenyPIIOutsideHR",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "*"},
+					Resource: models.JSONActionResource{Single: "*"},
+					Condition: map[string]interface{}{
+						"ResourceTag":     map[string]interface{}{"pii": true},
+						"StringNotEquals": map[string]interface{}{"user.department": "HR"},
+					},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	evaluate := func(resourceID, department string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "tag-" + resourceID,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{"department": department}),
+			ResourceID: resourceID,
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+
+	if decision := evaluate("api:documents:salaries", "Engineering"); decision.Result != constants.ResultDeny ||
+		!strings.Contains(decision.Reason, "DenyPIIOutsideHR") {
+		t.Errorf("Expected PII deny for Engineering, got %s (%s)", decision.Result, decision.Reason)
+	}
+	if decision := evaluate("api:documents:salaries", "HR"); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit for HR, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// The untagged resource never reaches the PII policy
+	before, _ := pdp.GetPolicyStats("pol-pii")
+	if decision := evaluate("api:documents:handbook", "Engineering"); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit for untagged resource, got %s (%s)", decision.Result, decision.Reason)
+	}
+	after, _ := pdp.GetPolicyStats("pol-pii")
+	if after.Evaluations != before.Evaluations {
+		t.Errorf("Expected pol-pii to be pre-filtered, evaluations went from %d to %d", before.Evaluations, after.Evaluations)
+	}
+}
+
+func TestPolicyValidatorResourceTag(t *testing.T) {
+	policy := &models.Policy{
+		ID:         "pol-001",
+		PolicyName: "Invalid tag",
+		Version:    "2024-10-21",
+		Statement: []models.PolicyStatement{
+			{
+				Effect:    "Allow",
+				Action:    models.JSONActionResource{Single: "document:read"},
+				Resource:  models.JSONActionResource{Single: "*"},
+				Condition: map[string]interface{}{"ResourceTag": map[string]interface{}{"pii": []interface{}{}}},
+			},
+		},
+	}
+
+	err := NewPolicyValidator().ValidatePolicy(policy)
+	if err == nil || !strings.Contains(err.Error(), "ResourceTag") {
+		t.Errorf("Expected ResourceTag validation error, got %v", err)
+	}
+}
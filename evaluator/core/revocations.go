@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// DefaultRevocationRefresh is how long the in-memory revocation list is used before it is reloaded
+// from storage, bounding how long other instances sharing the database take to see a change
+const DefaultRevocationRefresh = 10 * time.Second
+
+// revocationList is the revocation list cached by the PDP, indexed by subject and session
+type revocationList struct {
+	subjects map[string][]*models.Revocation
+	sessions map[string][]*models.Revocation
+	loadedAt time.Time
+}
+
+// revocationCache loads the revocation list lazily and reloads it once it is older than the
+// refresh interval or has been invalidated
+type revocationCache struct {
+	mu   sync.Mutex
+	list *revocationList
+}
+
+// InvalidateRevocations drops the cached revocation list, so the next evaluation reloads it. The
+// storage event subscriber calls it on every revocation change for immediate effect.
+func (pdp *PolicyDecisionPoint) InvalidateRevocations() {
+	pdp.revocations.mu.Lock()
+	pdp.revocations.list = nil
+	pdp.revocations.mu.Unlock()
+}
+
+// revocationRefresh returns the configured refresh interval
+func (pdp *PolicyDecisionPoint) revocationRefresh() time.Duration {
+	if pdp.config != nil && pdp.config.RevocationRefresh > 0 {
+		return pdp.config.RevocationRefresh
+	}
+	return DefaultRevocationRefresh
+}
+
+// currentRevocations returns the cached revocation list, reloading it when stale
+func (pdp *PolicyDecisionPoint) currentRevocations(revocationStore storage.RevocationStore) (*revocationList, error) {
+	pdp.revocations.mu.Lock()
+	defer pdp.revocations.mu.Unlock()
+
+	now := pdp.now()
+	if list := pdp.revocations.list; list != nil && now.Sub(list.loadedAt) < pdp.revocationRefresh() {
+		return list, nil
+	}
+	revocations, err := revocationStore.ListRevocations()
+	if err != nil {
+		return nil, err
+	}
+	list := &revocationList{
+		subjects: make(map[string][]*models.Revocation),
+		sessions: make(map[string][]*models.Revocation),
+		loadedAt: now,
+	}
+	for _, revocation := range revocations {
+		if revocation.SubjectID != "" {
+			list.subjects[revocation.SubjectID] = append(list.subjects[revocation.SubjectID], revocation)
+		}
+		if revocation.SessionID != "" {
+			list.sessions[revocation.SessionID] = append(list.sessions[revocation.SessionID], revocation)
+		}
+	}
+	pdp.revocations.list = list
+	return list, nil
+}
+
+// revocationDecision returns the deny for a request whose subject or session is revoked. It runs
+// before lockdown, exceptions and policies, so nothing grants a revoked subject access. Storages
+// without a RevocationStore never revoke; a failing lookup fails closed.
+func (pdp *PolicyDecisionPoint) revocationDecision(request *models.EvaluationRequest) (*models.Decision, bool, error) {
+	revocationStore, ok := pdp.storage.(storage.RevocationStore)
+	if !ok || request == nil || request.Subject == nil {
+		return nil, false, nil
+	}
+	list, err := pdp.currentRevocations(revocationStore)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load revocation list: %w", err)
+	}
+
+	// Point-in-time requests see the revocations active at that time
+	at := pdp.now()
+	if request.AsOf != nil {
+		at = *request.AsOf
+	}
+	candidates := list.subjects[request.Subject.GetID()]
+	if request.Session != nil && request.Session.SessionID != "" {
+		candidates = append(candidates[:len(candidates):len(candidates)], list.sessions[request.Session.SessionID]...)
+	}
+	for _, revocation := range candidates {
+		if !revocation.ActiveAt(at) {
+			continue
+		}
+		details := map[string]string{constants.ReasonDetailRevocation: revocation.ID}
+		if revocation.ExpiresAt != nil {
+			details[constants.ReasonDetailExpiresAt] = revocation.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		return &models.Decision{
+			Result:          constants.ResultDeny,
+			MatchedPolicies: []string{},
+			Reason:          fmt.Sprintf(constants.ReasonAccessRevoked, revocation.ID, revocation.Reason),
+			ReasonCode:      constants.ReasonCodeAccessRevoked,
+			ReasonDetails:   details,
+		}, true, nil
+	}
+	return nil, false, nil
+}
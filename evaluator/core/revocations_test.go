@@ -0,0 +1,163 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/events"
+	"abac_go_example/models"
+)
+
+// TestPDP_Revocations tests that revoked subjects and sessions are denied ahead of exceptions and policies
+func TestPDP_Revocations(t *testing.T) {
+	mockStorage := hooksTestStorage()
+	mockClock := clock.NewMockClock(time.Now())
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	// alice satisfies the policy; bob is allowed by an exception
+	request := func(subjectID, sessionID string) *models.EvaluationRequest {
+		request := hooksTestRequest(subjectID)
+		request.Context = map[string]interface{}{"region": "eu", "ticket": "approved"}
+		if sessionID != "" {
+			request.Session = &models.SessionInfo{SessionID: sessionID}
+		}
+		return request
+	}
+	evaluate := func(subjectID, sessionID string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(request(subjectID, sessionID))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+	if err := mockStorage.CreateException(&models.AccessException{ID: "exc-bob", SubjectID: "bob", ResourceID: "api:documents:plan.pdf",
+		Action: "document:read", Effect: constants.EffectAllow, Justification: "Audit", ExpiresAt: time.Now().Add(24 * time.Hour)}); err != nil {
+		t.Fatalf("CreateException failed: %v", err)
+	}
+	if decision := evaluate("alice", ""); decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected permit before revocation, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	for _, revocation := range []*models.Revocation{
+		{ID: "rev-alice", SubjectID: "alice", Reason: "Compromised account INC-7", ExpiresAt: &expiresAt},
+		{ID: "rev-session", SessionID: "sess-stolen", Reason: "Stolen token"},
+	} {
+		if err := mockStorage.CreateRevocation(revocation); err != nil {
+			t.Fatalf("CreateRevocation failed: %v", err)
+		}
+	}
+
+	// The cached list is used until it is invalidated or refreshed
+	if decision := evaluate("alice", ""); decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected the cached revocation list until invalidation, got %s", decision.Result)
+	}
+	pdp.InvalidateRevocations()
+
+	decision := evaluate("alice", "")
+	if decision.Result != constants.ResultDeny || decision.Reason != "Access revoked by rev-alice: Compromised account INC-7" ||
+		decision.ReasonCode != constants.ReasonCodeAccessRevoked || decision.ReasonDetails[constants.ReasonDetailRevocation] != "rev-alice" ||
+		decision.ReasonDetails[constants.ReasonDetailExpiresAt] == "" {
+		t.Errorf("Expected deny by revocation, got %s (%s, %s, %v)", decision.Result, decision.Reason, decision.ReasonCode, decision.ReasonDetails)
+	}
+
+	// A revoked session is denied even though an exception allows its subject
+	if decision := evaluate("bob", ""); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected bob's other sessions to stay allowed, got %s", decision.Result)
+	}
+	if decision := evaluate("bob", "sess-stolen"); decision.Result != constants.ResultDeny || decision.ReasonDetails[constants.ReasonDetailRevocation] != "rev-session" {
+		t.Errorf("Expected the revoked session to be denied, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	// Explain and EvaluateFields report the revocation without evaluating statements
+	explanation, err := pdp.Explain(request("alice", ""))
+	if err != nil || explanation.Decision.ReasonCode != constants.ReasonCodeAccessRevoked || len(explanation.Statements) != 0 {
+		t.Errorf("Expected Explain to report the revocation, got %+v (%v)", explanation, err)
+	}
+	fields, err := pdp.EvaluateFields(request("alice", ""), []string{"salary"})
+	if err != nil || fields.Decision.ReasonCode != constants.ReasonCodeAccessRevoked || fields.Fields["salary"].Effect != constants.EffectDeny {
+		t.Errorf("Expected EvaluateFields to deny every field, got %+v (%v)", fields, err)
+	}
+
+	// Expired revocations no longer apply
+	mockClock.Advance(2 * time.Hour)
+	if decision := evaluate("alice", ""); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit after the revocation expired, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
+
+// TestPDP_RevocationRefresh tests that the cached revocation list is reloaded once the refresh
+// interval has passed on the PDP clock
+func TestPDP_RevocationRefresh(t *testing.T) {
+	mockStorage := hooksTestStorage()
+	mockClock := clock.NewMockClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.Clock = mockClock
+	config.RevocationRefresh = time.Minute
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	request := hooksTestRequest("alice")
+	request.Context = map[string]interface{}{"region": "eu", "ticket": "approved"}
+	evaluate := func() *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+	if decision := evaluate(); decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected permit before revocation, got %s (%s)", decision.Result, decision.Reason)
+	}
+	if err := mockStorage.CreateRevocation(&models.Revocation{ID: "rev-alice", SubjectID: "alice", Reason: "Compromised account"}); err != nil {
+		t.Fatalf("CreateRevocation failed: %v", err)
+	}
+
+	mockClock.Advance(59 * time.Second)
+	if decision := evaluate(); decision.Result != constants.ResultPermit {
+		t.Errorf("Expected the cached revocation list within the refresh interval, got %s", decision.Result)
+	}
+	mockClock.Advance(time.Second)
+	if decision := evaluate(); decision.Result != constants.ResultDeny || decision.ReasonCode != constants.ReasonCodeAccessRevoked {
+		t.Errorf("Expected the reloaded revocation list to deny, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
+
+// TestPDP_RevocationEvents tests that revocation changes published by storage take effect immediately
+func TestPDP_RevocationEvents(t *testing.T) {
+	mockStorage := hooksTestStorage()
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+	bus := events.NewBus()
+	bus.Subscribe("cache", events.NewCacheInvalidationSubscriber(pdp))
+	mockStorage.SetEventPublisher(bus)
+
+	request := hooksTestRequest("alice")
+	request.Context = map[string]interface{}{"region": "eu", "ticket": "approved"}
+	evaluate := func() string {
+		t.Helper()
+		decision, err := pdp.Evaluate(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision.Result
+	}
+
+	if result := evaluate(); result != constants.ResultPermit {
+		t.Fatalf("Expected permit, got %s", result)
+	}
+	if err := mockStorage.CreateRevocation(&models.Revocation{ID: "rev-alice", SubjectID: "alice", Reason: "Offboarded"}); err != nil {
+		t.Fatalf("CreateRevocation failed: %v", err)
+	}
+	if result := evaluate(); result != constants.ResultDeny {
+		t.Errorf("Expected the revocation to apply immediately, got %s", result)
+	}
+	mockStorage.DeleteRevocation("rev-alice")
+	if result := evaluate(); result != constants.ResultPermit {
+		t.Errorf("Expected permit once the revocation is lifted, got %s", result)
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/attributes"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_RiskBasedAccess tests an adaptive policy driven by environment.risk_score
+func TestPDP_RiskBasedAccess(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "payment:approve", ActionName: "payment:approve"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:payments:tx-1", ResourceID: "api:payments:tx-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-risk",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ApproveWhenLowRisk",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "payment:approve"},
+					Resource: models.JSONActionResource{Single: "api:payments:*"},
+					Condition: map[string]interface{}{
+						"NumericLessThan": map[string]interface{}{
+							"environment.risk_score": 50,
+						},
+					},
+				},
+				{
+					Sid:      "DenyImpossibleTravel",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "*"},
+					Resource: models.JSONActionResource{Single: "*"},
+					Condition: map[string]interface{}{
+						"Bool": map[string]interface{}{
+							"environment.impossible_travel": true,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		assessment *attributes.RiskAssessment
+		expected   string
+	}{
+		{"low risk", &attributes.RiskAssessment{Score: 10}, constants.ResultPermit},
+		{"high risk", &attributes.RiskAssessment{Score: 80, NewDevice: true}, constants.ResultDeny},
+		{"impossible travel", &attributes.RiskAssessment{Score: 20, ImpossibleTravel: true}, constants.ResultDeny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultPDPConfig()
+			config.RiskProvider = attributes.RiskProviderFunc(func(request *models.EvaluationRequest, environment map[string]interface{}) (*attributes.RiskAssessment, error) {
+				return tt.assessment, nil
+			})
+			pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+			decision, err := pdp.Evaluate(&models.EvaluationRequest{
+				RequestID:  "risk-001",
+				Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+				ResourceID: "api:payments:tx-1",
+				Action:     "payment:approve",
+			})
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if decision.Result != tt.expected {
+				t.Errorf("expected %s, got %s (%s)", tt.expected, decision.Result, decision.Reason)
+			}
+		})
+	}
+}
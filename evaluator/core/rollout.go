@@ -0,0 +1,94 @@
+package core
+
+import (
+	"hash/fnv"
+	"log"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// RolloutBucket returns the deterministic bucket (0-99) of a subject for a canary policy. Buckets are
+// salted with the policy ID, so concurrent canaries do not all land on the same subjects.
+func RolloutBucket(policyID, subjectID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(policyID))
+	h.Write([]byte{0})
+	h.Write([]byte(subjectID))
+	return int(h.Sum32() % 100)
+}
+
+// rolloutPolicies splits policies into those enforced for subjectID and the canary policies
+// whose rollout does not include the subject yet
+func rolloutPolicies(policies []*models.Policy, subjectID string) (enforced, canary []*models.Policy) {
+	enforced = policies
+	for i, policy := range policies {
+		if policy.InRollout(RolloutBucket(policy.ID, subjectID)) {
+			if canary != nil {
+				enforced = append(enforced, policy)
+			}
+			continue
+		}
+		if canary == nil {
+			enforced = append(make([]*models.Policy, 0, len(policies)), policies[:i]...)
+		}
+		canary = append(canary, policy)
+	}
+	return enforced, canary
+}
+
+// shadowDecision evaluates the request as if the canary policies held back from it were enforced.
+// Only the canary policies are evaluated; their outcome is combined with the enforced decision, so
+// enforced policies are not counted twice in stats, coverage and quarantine timing. Shadow results
+// that differ from the enforced decision are logged, so risky deny rules can be checked before their
+// rollout grows.
+func (pdp *PolicyDecisionPoint) shadowDecision(request *models.EvaluationRequest, evalContext map[string]interface{},
+	decision *models.Decision) *models.ShadowDecision {
+	canary, _ := evalContext[constants.ContextKeyCanaryPolicies].([]*models.Policy)
+	canary = pdp.tagFilteredPolicies(canary, evalContext)
+	if len(canary) == 0 {
+		return nil
+	}
+
+	active, _, mayDeny := pdp.quarantinedPolicies(canary, evalContext)
+	canaryDecision, _ := pdp.combinePolicies(active, evalContext)
+	result := combineShadow(decision, canaryDecision, mayDeny)
+	result.CanaryPolicies = make([]string, len(canary))
+	for i, policy := range canary {
+		result.CanaryPolicies[i] = policy.ID
+	}
+	if result.Result != decision.Result {
+		log.Printf("Canary rollout: policies %v would change the decision of subject %s on %s %s from %s to %s",
+			result.CanaryPolicies, request.Subject.GetID(), request.Action, request.ResourceID, decision.Result, result.Result)
+	}
+	return result
+}
+
+// combineShadow applies Deny-Override to the enforced decision and the decision of the canary
+// policies alone, giving the decision of evaluating both policy sets together. mayDeny reports a
+// quarantined canary that may deny, which turns a permit into a deny like quarantineDecision.
+func combineShadow(enforced, canary *models.Decision, mayDeny bool) *models.ShadowDecision {
+	// An enforced Deny statement stops evaluation before the canary policies
+	if enforced.ReasonCode == constants.ReasonCodeDeniedByStatement {
+		return &models.ShadowDecision{Result: enforced.Result, MatchedPolicies: enforced.MatchedPolicies}
+	}
+
+	var matched []string
+	if enforced.ReasonCode == constants.ReasonCodeAllowedByStatements {
+		matched = append(matched, enforced.MatchedPolicies...)
+	}
+	shadow := &models.ShadowDecision{Result: enforced.Result, MatchedPolicies: enforced.MatchedPolicies}
+	switch canary.ReasonCode {
+	case constants.ReasonCodeDeniedByStatement:
+		shadow = &models.ShadowDecision{Result: constants.ResultDeny, MatchedPolicies: append(matched, canary.MatchedPolicies...)}
+	case constants.ReasonCodeAllowedByStatements:
+		// Canary Allow statements permit what the enforced policies left unmatched
+		if enforced.Result == constants.ResultPermit || enforced.ReasonCode == constants.ReasonCodeImplicitDeny {
+			shadow = &models.ShadowDecision{Result: constants.ResultPermit, MatchedPolicies: append(matched, canary.MatchedPolicies...)}
+		}
+	}
+	if mayDeny && shadow.Result == constants.ResultPermit {
+		shadow.Result = constants.ResultDeny
+	}
+	return shadow
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_CanaryRollout tests that a canary deny is enforced for its share of subjects only and
+// evaluated in shadow for the others
+func TestPDP_CanaryRollout(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	percent := 30
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-allow", Enabled: true, Statement: []models.PolicyStatement{
+			{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		}},
+		{ID: "pol-canary-deny", Enabled: true, RolloutPercent: &percent, Statement: []models.PolicyStatement{
+			{Sid: "DenyRead", Effect: "Deny", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		}},
+	})
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	evaluate := func(subjectID string) *models.Decision {
+		t.Helper()
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "rollout-" + subjectID,
+			Subject:    models.CreateMockSubjectWithAttributes(subjectID, map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return decision
+	}
+
+	denied := 0
+	for i := 0; i < 1000; i++ {
+		subjectID := fmt.Sprintf("user-%d", i)
+		decision := evaluate(subjectID)
+		inRollout := RolloutBucket("pol-canary-deny", subjectID) < percent
+
+		switch {
+		case inRollout:
+			denied++
+			if decision.Result != constants.ResultDeny || decision.Shadow != nil {
+				t.Fatalf("Expected %s in the rollout to be denied without shadow, got %s (%+v)", subjectID, decision.Result, decision.Shadow)
+			}
+		default:
+			if decision.Result != constants.ResultPermit {
+				t.Fatalf("Expected %s outside the rollout to be permitted, got %s", subjectID, decision.Result)
+			}
+			if decision.Shadow == nil || decision.Shadow.Result != constants.ResultDeny ||
+				len(decision.Shadow.CanaryPolicies) != 1 || decision.Shadow.CanaryPolicies[0] != "pol-canary-deny" {
+				t.Fatalf("Expected the shadow deny of the canary for %s, got %+v", subjectID, decision.Shadow)
+			}
+		}
+
+		// Bucketing is deterministic per subject
+		if again := evaluate(subjectID); again.Result != decision.Result {
+			t.Fatalf("Expected a stable decision for %s, got %s then %s", subjectID, decision.Result, again.Result)
+		}
+	}
+	if denied < 250 || denied > 350 {
+		t.Errorf("Expected about 30%% of subjects in the rollout, got %d of 1000", denied)
+	}
+}
+
+// TestPDP_CanaryShadowStats tests that the shadow pass evaluates only the canary policies, so enforced
+// policies are counted once per request
+func TestPDP_CanaryShadowStats(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	zero := 0
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-allow", Enabled: true, Statement: []models.PolicyStatement{
+			{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		}},
+		{ID: "pol-canary-deny", Enabled: true, RolloutPercent: &zero, Statement: []models.PolicyStatement{
+			{Sid: "DenyRead", Effect: "Deny", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		}},
+	})
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, DefaultPDPConfig())
+
+	for i := 0; i < 5; i++ {
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  fmt.Sprintf("shadow-%d", i),
+			Subject:    models.CreateMockSubjectWithAttributes(fmt.Sprintf("user-%d", i), map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     "document:read",
+			Context:    map[string]interface{}{},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decision.Result != constants.ResultPermit || decision.Shadow == nil || decision.Shadow.Result != constants.ResultDeny {
+			t.Fatalf("Expected a permit with a shadow deny, got %s (%+v)", decision.Result, decision.Shadow)
+		}
+		if matched := decision.Shadow.MatchedPolicies; len(matched) != 2 || matched[0] != "pol-allow" || matched[1] != "pol-canary-deny" {
+			t.Errorf("Expected the shadow to match pol-allow and pol-canary-deny, got %v", matched)
+		}
+	}
+
+	for _, policyID := range []string{"pol-allow", "pol-canary-deny"} {
+		stats, ok := pdp.GetPolicyStats(policyID)
+		if !ok || stats.Evaluations != 5 {
+			t.Errorf("Expected %s to be evaluated once per request, got %+v", policyID, stats)
+		}
+	}
+}
+
+// TestCombineShadow tests that combining the enforced decision with the canary decision gives the
+// decision of evaluating both policy sets together
+func TestCombineShadow(t *testing.T) {
+	permit := &models.Decision{Result: constants.ResultPermit, ReasonCode: constants.ReasonCodeAllowedByStatements, MatchedPolicies: []string{"pol-a"}}
+	deny := &models.Decision{Result: constants.ResultDeny, ReasonCode: constants.ReasonCodeDeniedByStatement, MatchedPolicies: []string{"pol-d"}}
+	implicit := &models.Decision{Result: constants.ResultDeny, ReasonCode: constants.ReasonCodeImplicitDeny}
+	quarantined := &models.Decision{Result: constants.ResultDeny, ReasonCode: constants.ReasonCodePolicyQuarantined}
+	canaryPermit := &models.Decision{Result: constants.ResultPermit, ReasonCode: constants.ReasonCodeAllowedByStatements, MatchedPolicies: []string{"pol-c"}}
+	canaryDeny := &models.Decision{Result: constants.ResultDeny, ReasonCode: constants.ReasonCodeDeniedByStatement, MatchedPolicies: []string{"pol-c"}}
+
+	tests := []struct {
+		name     string
+		enforced *models.Decision
+		canary   *models.Decision
+		mayDeny  bool
+		expected string
+		matched  int
+	}{
+		{"canary deny overrides a permit", permit, canaryDeny, false, constants.ResultDeny, 2},
+		{"canary allow adds to a permit", permit, canaryPermit, false, constants.ResultPermit, 2},
+		{"canary allow fills an implicit deny", implicit, canaryPermit, false, constants.ResultPermit, 1},
+		{"enforced deny stops evaluation", deny, canaryPermit, false, constants.ResultDeny, 1},
+		{"canary allow cannot lift a quarantine deny", quarantined, canaryPermit, false, constants.ResultDeny, 0},
+		{"unmatched canary keeps the decision", permit, implicit, false, constants.ResultPermit, 1},
+		{"quarantined canary that may deny fails closed", permit, implicit, true, constants.ResultDeny, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shadow := combineShadow(tt.enforced, tt.canary, tt.mayDeny)
+			if shadow.Result != tt.expected || len(shadow.MatchedPolicies) != tt.matched {
+				t.Errorf("Expected %s matching %d policies, got %+v", tt.expected, tt.matched, shadow)
+			}
+		})
+	}
+}
+
+func TestRolloutPolicies(t *testing.T) {
+	zero, full := 0, 100
+	policies := []*models.Policy{{ID: "pol-a"}, {ID: "pol-b", RolloutPercent: &zero}, {ID: "pol-c", RolloutPercent: &full}}
+
+	enforced, canary := rolloutPolicies(policies, "user-1")
+	if len(enforced) != 2 || enforced[0].ID != "pol-a" || enforced[1].ID != "pol-c" {
+		t.Errorf("Expected pol-a and pol-c enforced, got %v", enforced)
+	}
+	if len(canary) != 1 || canary[0].ID != "pol-b" {
+		t.Errorf("Expected pol-b held back at 0%%, got %v", canary)
+	}
+
+	if enforced, canary := rolloutPolicies(policies[:1], "user-1"); len(enforced) != 1 || canary != nil {
+		t.Errorf("Expected policies without rollout to be enforced as is, got %v and %v", enforced, canary)
+	}
+}
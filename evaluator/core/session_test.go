@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_SessionContext tests MFA and auth-age conditions driven by request.Session
+func TestPDP_SessionContext(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-mfa",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "DeleteWithRecentMFA",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:delete"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"Bool": map[string]interface{}{
+							"session.mfa_verified": true,
+						},
+						"AuthAgeLessThan": map[string]interface{}{
+							"session.auth_time": "15m",
+						},
+					},
+				},
+			},
+		},
+	})
+	pdp := NewPolicyDecisionPoint(mockStorage)
+
+	now := time.Now()
+	recent := now.Add(-5 * time.Minute)
+	stale := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name     string
+		session  *models.SessionInfo
+		expected string
+	}{
+		{"recent MFA", &models.SessionInfo{SessionID: "s-1", AuthMethod: "webauthn", MFAVerified: true, AuthTime: &recent}, constants.ResultPermit},
+		{"stale authentication", &models.SessionInfo{SessionID: "s-2", MFAVerified: true, AuthTime: &stale}, constants.ResultDeny},
+		{"no MFA", &models.SessionInfo{SessionID: "s-3", MFAVerified: false, AuthTime: &recent}, constants.ResultDeny},
+		{"no session", nil, constants.ResultDeny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := pdp.Evaluate(&models.EvaluationRequest{
+				RequestID:  "session-001",
+				Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+				ResourceID: "api:documents:test.pdf",
+				Action:     "document:delete",
+				Timestamp:  &now,
+				Session:    tt.session,
+			})
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if decision.Result != tt.expected {
+				t.Errorf("expected %s, got %s (%s)", tt.expected, decision.Result, decision.Reason)
+			}
+		})
+	}
+}
+
+// TestBuildEnhancedEvaluationContext_Session tests the standardized session attributes
+func TestBuildEnhancedEvaluationContext_Session(t *testing.T) {
+	pdp := newPolicyDecisionPoint(storage.NewMockStorage())
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	authTime := now.Add(-90 * time.Second)
+
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+		ResourceID: "res-1",
+		Action:     "read",
+		Timestamp:  &now,
+		Session:    &models.SessionInfo{SessionID: "s-1", AuthMethod: "sso", MFAVerified: true, AuthTime: &authTime},
+	}
+	evalContext := pdp.BuildEnhancedEvaluationContext(request, &models.EvaluationContext{Timestamp: now})
+
+	session, ok := evalContext[constants.ContextKeySession].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected structured session context, got %v", evalContext[constants.ContextKeySession])
+	}
+	if session[constants.SessionKeyAuthAgeSeconds] != 90 || session[constants.SessionKeyMFAVerified] != true {
+		t.Errorf("unexpected session context: %v", session)
+	}
+	if evalContext["session:auth_method"] != "sso" {
+		t.Errorf("expected flat session:auth_method, got %v", evalContext["session:auth_method"])
+	}
+}
+
+// TestPDP_LargeContextNotations tests that a large subject with a client certificate, session and request
+// context stays under MaxConditionKeys and resolves attributes in both notations without materialised keys
+func TestPDP_LargeContextNotations(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "payment:approve", ActionName: "payment:approve"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:payments:batch-1", ResourceID: "api:payments:batch-1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-approve",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ApproveFromPaymentService",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "payment:approve"},
+					Resource: models.JSONActionResource{Single: "api:payments:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.custom_attr_49":                 "value-49",
+							"user:custom_attr_0":                  "value-0",
+							"environment.client_cert.common_name": "payment-service",
+							"session:auth_method":                 "webauthn",
+							"request.channel":                     "batch",
+							"request:ticket.id":                   "CHG-7",
+						},
+						"Bool": map[string]interface{}{"session.mfa_verified": true},
+					},
+				},
+			},
+		},
+	})
+	pdp := NewPolicyDecisionPoint(mockStorage)
+
+	attributes := make(map[string]interface{})
+	for i := 0; i < 50; i++ {
+		attributes[fmt.Sprintf("attr_%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	request := &models.EvaluationRequest{
+		Subject:    models.CreateMockSubjectWithAttributes("svc-payments", attributes),
+		ResourceID: "api:payments:batch-1",
+		Action:     "payment:approve",
+		Context:    map[string]interface{}{"channel": "batch", "ticket": map[string]interface{}{"id": "CHG-7"}},
+		Session:    &models.SessionInfo{SessionID: "s-1", AuthMethod: "webauthn", MFAVerified: true},
+		Environment: &models.EnvironmentInfo{
+			ClientCert: &models.ClientCertInfo{CommonName: "payment-service", IssuerDN: "CN=Services CA,O=Acme", Verified: true},
+		},
+	}
+
+	decision, err := pdp.Evaluate(request)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Errorf("Expected permit, got %s (%s)", decision.Result, decision.Reason)
+	}
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+	"abac_go_example/sink"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PublishesDecisionEvents tests that Evaluate publishes decisions to the configured sink
+func TestPDP_PublishesDecisionEvents(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+
+	broadcaster := sink.NewBroadcaster()
+	subscription := broadcaster.Subscribe(sink.EventFilter{Result: "deny"}, 10)
+	defer subscription.Unsubscribe()
+
+	mockClock := clock.NewMockClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	config := DefaultPDPConfig()
+	config.DecisionSink = broadcaster
+	config.Clock = mockClock
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	_, err := pdp.Evaluate(&models.EvaluationRequest{
+		RequestID:  "sink-001",
+		Subject:    models.NewMockUserSubject("user-123", "user-123"),
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case event := <-subscription.Events():
+		if event.RequestID != "sink-001" || event.SubjectID != "user-123" || event.ReasonCode == "" ||
+			!event.Timestamp.Equal(mockClock.Now()) {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("Expected a deny event to be published")
+	}
+}
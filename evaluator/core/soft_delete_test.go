@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_SoftDeletedPolicies tests that soft-deleted policies are never evaluated until restored
+func TestPDP_SoftDeletedPolicies(t *testing.T) {
+	type softDeleteStorage interface {
+		storage.Storage
+		storage.SoftDeleteStore
+	}
+	stores := map[string]softDeleteStorage{"mock": storage.NewMockStorage(), "sqlite": storage.NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			store.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+			store.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceType: "document", ResourceID: "api:documents:doc-1"})
+			for _, policy := range []*models.Policy{
+				{ID: "pol-allow", PolicyName: "Allow Read", Version: "1", Enabled: true, Statement: []models.PolicyStatement{
+					{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+				}},
+				{ID: "pol-deny", PolicyName: "Deny Read", Version: "1", Enabled: true, Statement: []models.PolicyStatement{
+					{Sid: "DenyRead", Effect: "Deny", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+				}},
+			} {
+				if err := store.CreatePolicy(policy); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pdp := NewPolicyDecisionPoint(store)
+
+			evaluate := func() *models.Decision {
+				t.Helper()
+				decision, err := pdp.Evaluate(&models.EvaluationRequest{
+					RequestID:  "soft-delete",
+					Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+					ResourceID: "api:documents:doc-1",
+					Action:     "document:read",
+					Context:    map[string]interface{}{},
+				})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return decision
+			}
+
+			if decision := evaluate(); decision.Result != constants.ResultDeny {
+				t.Fatalf("Expected the deny policy to apply, got %s", decision.Result)
+			}
+			if err := store.DeletePolicy("pol-deny"); err != nil {
+				t.Fatal(err)
+			}
+			if decision := evaluate(); decision.Result != constants.ResultPermit {
+				t.Errorf("Expected permit once the deny policy is soft-deleted, got %s (%v)", decision.Result, decision.MatchedPolicies)
+			}
+			if err := store.RestorePolicy("pol-deny"); err != nil {
+				t.Fatal(err)
+			}
+			if decision := evaluate(); decision.Result != constants.ResultDeny {
+				t.Errorf("Expected the restored deny policy to apply again, got %s", decision.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PolicyStats summarizes how often a policy was evaluated and matched
+type PolicyStats struct {
+	PolicyID             string           `json:"policy_id"`
+	Evaluations          int64            `json:"evaluations"` // Requests in which the policy was evaluated
+	Matches              int64            `json:"matches"`     // Statements of the policy that matched
+	Denies               int64            `json:"denies"`      // Matched Deny statements
+	ConditionEvaluations int64            `json:"condition_evaluations"`
+	AvgConditionTimeUs   float64          `json:"avg_condition_time_us"`
+	LastMatchedAt        *time.Time       `json:"last_matched_at,omitempty"`
+	Statements           []StatementStats `json:"statements"`
+}
+
+// StatementStats summarizes evaluation counters of a single policy statement
+type StatementStats struct {
+	Sid                  string  `json:"sid"` // Statement Sid, or "#<index>" when Sid is empty
+	Evaluations          int64   `json:"evaluations"`
+	Matches              int64   `json:"matches"`
+	Denies               int64   `json:"denies"`
+	ConditionEvaluations int64   `json:"condition_evaluations"`
+	AvgConditionTimeUs   float64 `json:"avg_condition_time_us"`
+}
+
+// statementCounters holds raw counters for a statement
+type statementCounters struct {
+	sid                  string
+	evaluations          int64
+	matches              int64
+	denies               int64
+	conditionEvaluations int64
+	conditionTime        time.Duration
+}
+
+// policyCounters holds raw counters for a policy
+type policyCounters struct {
+	evaluations   int64
+	lastMatchedAt time.Time
+	statements    []*statementCounters
+}
+
+// StatsCollector records per-policy and per-statement evaluation statistics.
+// It is safe for concurrent use.
+type StatsCollector struct {
+	mu       sync.RWMutex
+	policies map[string]*policyCounters
+}
+
+// NewStatsCollector creates an empty statistics collector
+func NewStatsCollector() *StatsCollector {
+	return &StatsCollector{
+		policies: make(map[string]*policyCounters),
+	}
+}
+
+// StatementResult is the outcome of evaluating one statement, as recorded by StatsCollector
+type StatementResult struct {
+	Index               int
+	Sid                 string
+	Matched             bool
+	Deny                bool
+	ConditionsEvaluated bool
+	ConditionTime       time.Duration
+}
+
+// RecordPolicy records one evaluation of a policy and the outcome of each evaluated statement
+func (sc *StatsCollector) RecordPolicy(policyID string, results []StatementResult) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	policy, exists := sc.policies[policyID]
+	if !exists {
+		policy = &policyCounters{}
+		sc.policies[policyID] = policy
+	}
+	policy.evaluations++
+
+	for _, result := range results {
+		statement := policy.statement(result.Index, result.Sid)
+		statement.evaluations++
+
+		if result.ConditionsEvaluated {
+			statement.conditionEvaluations++
+			statement.conditionTime += result.ConditionTime
+		}
+
+		if result.Matched {
+			statement.matches++
+			policy.lastMatchedAt = time.Now()
+			if result.Deny {
+				statement.denies++
+			}
+		}
+	}
+}
+
+// GetPolicyStats returns a snapshot of the statistics of a policy
+func (sc *StatsCollector) GetPolicyStats(policyID string) (*PolicyStats, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	policy, exists := sc.policies[policyID]
+	if !exists {
+		return nil, false
+	}
+	return policy.snapshot(policyID), true
+}
+
+// GetAllPolicyStats returns snapshots of all recorded policies sorted by policy ID
+func (sc *StatsCollector) GetAllPolicyStats() []*PolicyStats {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	result := make([]*PolicyStats, 0, len(sc.policies))
+	for policyID, policy := range sc.policies {
+		result = append(result, policy.snapshot(policyID))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PolicyID < result[j].PolicyID
+	})
+	return result
+}
+
+// Reset clears all recorded statistics
+func (sc *StatsCollector) Reset() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.policies = make(map[string]*policyCounters)
+}
+
+// statement returns the counters for the statement at index, growing the slice as needed
+func (pc *policyCounters) statement(index int, sid string) *statementCounters {
+	for len(pc.statements) <= index {
+		pc.statements = append(pc.statements, nil)
+	}
+
+	if pc.statements[index] == nil {
+		if sid == "" {
+			sid = fmt.Sprintf("#%d", index)
+		}
+		pc.statements[index] = &statementCounters{sid: sid}
+	}
+	return pc.statements[index]
+}
+
+// snapshot converts raw counters into a PolicyStats value
+func (pc *policyCounters) snapshot(policyID string) *PolicyStats {
+	stats := &PolicyStats{
+		PolicyID:    policyID,
+		Evaluations: pc.evaluations,
+		Statements:  make([]StatementStats, 0, len(pc.statements)),
+	}
+
+	var totalConditionTime time.Duration
+	for _, statement := range pc.statements {
+		if statement == nil {
+			continue
+		}
+
+		stats.Matches += statement.matches
+		stats.Denies += statement.denies
+		stats.ConditionEvaluations += statement.conditionEvaluations
+		totalConditionTime += statement.conditionTime
+
+		stats.Statements = append(stats.Statements, StatementStats{
+			Sid:                  statement.sid,
+			Evaluations:          statement.evaluations,
+			Matches:              statement.matches,
+			Denies:               statement.denies,
+			ConditionEvaluations: statement.conditionEvaluations,
+			AvgConditionTimeUs:   averageMicroseconds(statement.conditionTime, statement.conditionEvaluations),
+		})
+	}
+
+	stats.AvgConditionTimeUs = averageMicroseconds(totalConditionTime, stats.ConditionEvaluations)
+	if !pc.lastMatchedAt.IsZero() {
+		lastMatchedAt := pc.lastMatchedAt
+		stats.LastMatchedAt = &lastMatchedAt
+	}
+
+	return stats
+}
+
+// averageMicroseconds returns total/count in microseconds, or 0 when count is 0
+func averageMicroseconds(total time.Duration, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(time.Microsecond) / float64(count)
+}
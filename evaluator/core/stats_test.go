@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_PolicyStats tests per-policy and per-statement hit counters
+func TestPDP_PolicyStats(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{
+		ID:         "api:documents:test.pdf",
+		ResourceID: "api:documents:test.pdf",
+	})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-allow",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "EngineeringRead",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department": "Engineering",
+						},
+					},
+				},
+				{
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "report:write"},
+					Resource: models.JSONActionResource{Single: "api:reports:*"},
+				},
+			},
+		},
+		{
+			ID:      "pol-deny",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "DenyFinance",
+					Effect:   "Deny",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{
+						"StringEquals": map[string]interface{}{
+							"user.department": "Finance",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	pdp := NewPolicyDecisionPoint(mockStorage)
+	for i, department := range []string{"Engineering", "Engineering", "Finance"} {
+		_, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID: fmt.Sprintf("stats-%d", i),
+			Subject: models.CreateMockSubjectWithAttributes(fmt.Sprintf("user-%d", i), map[string]interface{}{
+				"department": department,
+			}),
+			ResourceID: "api:documents:test.pdf",
+			Action:     "document:read",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	allowStats, ok := pdp.GetPolicyStats("pol-allow")
+	if !ok {
+		t.Fatal("Expected stats for pol-allow")
+	}
+	if allowStats.Evaluations != 3 || allowStats.Matches != 2 || allowStats.Denies != 0 {
+		t.Errorf("Unexpected pol-allow stats: %+v", allowStats)
+	}
+	if len(allowStats.Statements) != 2 {
+		t.Fatalf("Expected 2 statement stats, got %+v", allowStats.Statements)
+	}
+	if allowStats.Statements[0].Sid != "EngineeringRead" || allowStats.Statements[0].ConditionEvaluations != 3 {
+		t.Errorf("Unexpected EngineeringRead stats: %+v", allowStats.Statements[0])
+	}
+	if allowStats.Statements[1].Sid != "#1" || allowStats.Statements[1].Matches != 0 {
+		t.Errorf("Expected unnamed dead statement stats, got %+v", allowStats.Statements[1])
+	}
+	if allowStats.LastMatchedAt == nil {
+		t.Error("Expected LastMatchedAt to be set")
+	}
+
+	denyStats, ok := pdp.GetPolicyStats("pol-deny")
+	if !ok {
+		t.Fatal("Expected stats for pol-deny")
+	}
+	if denyStats.Evaluations != 3 || denyStats.Matches != 1 || denyStats.Denies != 1 {
+		t.Errorf("Unexpected pol-deny stats: %+v", denyStats)
+	}
+
+	if all := pdp.GetAllPolicyStats(); len(all) != 2 || all[0].PolicyID != "pol-allow" {
+		t.Errorf("Unexpected GetAllPolicyStats result: %+v", all)
+	}
+
+	disabled := NewPolicyDecisionPointWithConfig(mockStorage, &PDPConfig{EnableStats: false})
+	if _, ok := disabled.GetPolicyStats("pol-allow"); ok {
+		t.Error("Expected no stats when EnableStats is false")
+	}
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_StorageFaults tests that storage failures fail closed and are not remembered afterwards
+func TestPDP_StorageFaults(t *testing.T) {
+	store := storage.NewMockStorage()
+	store.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	store.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceType: "document", ResourceID: "api:documents:doc-1"})
+	store.CreatePolicy(&models.Policy{ID: "pol-allow", PolicyName: "Allow Read", Version: "1", Enabled: true, Statement: []models.PolicyStatement{
+		{Sid: "AllowRead", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+	}})
+	pdp := NewPolicyDecisionPoint(store)
+
+	// Every second policy load fails
+	store.InjectFault("GetPolicies", storage.Fault{ErrorRate: 0.5})
+	for call := 1; call <= 4; call++ {
+		decision, err := pdp.Evaluate(&models.EvaluationRequest{
+			RequestID:  "fault",
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:doc-1",
+			Action:     "document:read",
+		})
+		if call%2 == 0 {
+			if !errors.Is(err, storage.ErrInjectedFault) {
+				t.Errorf("call %d: expected the storage fault, got %v", call, err)
+			}
+			if decision != nil && decision.Result == constants.ResultPermit {
+				t.Errorf("call %d: expected no permit when policies cannot be loaded", call)
+			}
+			continue
+		}
+		if err != nil || decision.Result != constants.ResultPermit {
+			t.Errorf("call %d: expected permit after the failed load, got %+v (%v)", call, decision, err)
+		}
+	}
+	if stats := store.FaultStats("GetPolicies"); stats.Failed != 2 {
+		t.Errorf("expected 2 failed policy loads, got %+v", stats)
+	}
+}
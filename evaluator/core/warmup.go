@@ -0,0 +1,106 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/storage"
+)
+
+// Policy compilation modes
+const (
+	CompileLazy  = "lazy"  // Policies are compiled on first use (default)
+	CompileEager = "eager" // WarmUp compiles every stored policy before the service takes traffic
+)
+
+// warmUpProgressInterval is the number of compiled policies between WarmUp progress log lines
+const warmUpProgressInterval = 100
+
+// WarmUpStats describes the last WarmUp run
+type WarmUpStats struct {
+	Policies    int       `json:"policies"`
+	DurationMs  float64   `json:"duration_ms"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// CompileStats reports the compilation mode, compiler counters and the last warm-up
+type CompileStats struct {
+	Mode string `json:"mode"`
+	CompilerStats
+	WarmUp *WarmUpStats `json:"warm_up,omitempty"`
+}
+
+// CompileModeFromEnv reads ABAC_COMPILE_MODE ("eager" or "lazy"); unset means lazy
+func CompileModeFromEnv() (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv(constants.EnvCompileMode)))
+	switch mode {
+	case "", CompileLazy:
+		return CompileLazy, nil
+	case CompileEager:
+		return CompileEager, nil
+	default:
+		return "", fmt.Errorf("unknown %s %q (expected %s or %s)", constants.EnvCompileMode, mode, CompileEager, CompileLazy)
+	}
+}
+
+// compileMode returns the configured compilation mode, lazy by default
+func (pdp *PolicyDecisionPoint) compileMode() string {
+	if pdp.config == nil || pdp.config.CompileMode == "" {
+		return CompileLazy
+	}
+	return pdp.config.CompileMode
+}
+
+// WarmUp compiles every enabled policy this PDP evaluates (its namespace, trusted by the loaded
+// bundle) so the first requests do not pay for compilation. Progress is logged for large policy sets.
+// It can be called in any mode; eager mode only means the service calls it at startup.
+func (pdp *PolicyDecisionPoint) WarmUp() (*WarmUpStats, error) {
+	start := time.Now()
+
+	namespace := ""
+	if pdp.config != nil {
+		namespace = pdp.config.Namespace
+	}
+	policies, err := storage.GetNamespacePolicies(pdp.storage, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies for warm-up: %w", err)
+	}
+	policies = pdp.trustedPolicies(policies)
+
+	compiled := 0
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		pdp.compiler.Compile(policy)
+		compiled++
+		if compiled%warmUpProgressInterval == 0 {
+			log.Printf("Policy warm-up: compiled %d/%d policies", compiled, len(policies))
+		}
+	}
+
+	stats := &WarmUpStats{
+		Policies:    compiled,
+		DurationMs:  float64(time.Since(start).Microseconds()) / 1000,
+		CompletedAt: time.Now().UTC(),
+	}
+	pdp.warmUp.Store(stats)
+	log.Printf("Policy warm-up: compiled %d policies in %.1fms", stats.Policies, stats.DurationMs)
+
+	copied := *stats
+	return &copied, nil
+}
+
+// GetCompileStats returns the compilation mode, compile counters and durations, and the last warm-up
+func (pdp *PolicyDecisionPoint) GetCompileStats() *CompileStats {
+	stats := &CompileStats{Mode: pdp.compileMode(), CompilerStats: *pdp.compiler.Stats()}
+	if warmUp := pdp.warmUp.Load(); warmUp != nil {
+		copied := *warmUp
+		stats.WarmUp = &copied
+	}
+	return stats
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// TestPDP_WarmUp tests that WarmUp compiles policies ahead of evaluation and reports compile metrics
+func TestPDP_WarmUp(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:doc-1", ResourceID: "api:documents:doc-1"})
+	allowRead := []models.PolicyStatement{
+		{
+			Sid:       "AllowEngineering",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: "document:read"},
+			Resource:  models.JSONActionResource{Single: "api:documents:*"},
+			Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Engineering"}},
+		},
+	}
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-read", Enabled: true, Statement: allowRead},
+		{ID: "pol-disabled", Enabled: false, Statement: allowRead},
+		{ID: "pol-other-namespace", Namespace: "payment-service", Enabled: true, Statement: allowRead},
+	})
+
+	config := DefaultPDPConfig()
+	config.CompileMode = CompileEager
+	pdp := NewPolicyDecisionPointWithConfig(mockStorage, config)
+
+	warmUp, err := pdp.WarmUp()
+	if err != nil {
+		t.Fatalf("WarmUp failed: %v", err)
+	}
+	if warmUp.Policies != 1 {
+		t.Errorf("Expected 1 warmed-up policy (enabled, in namespace), got %d", warmUp.Policies)
+	}
+
+	decision, err := pdp.Evaluate(&models.EvaluationRequest{
+		RequestID:  "warm-up-001",
+		Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{"department": "Engineering"}),
+		ResourceID: "api:documents:doc-1",
+		Action:     "document:read",
+		Context:    map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision.Result != constants.ResultPermit {
+		t.Fatalf("Expected permit, got %s (%s)", decision.Result, decision.Reason)
+	}
+
+	stats := pdp.GetCompileStats()
+	if stats.Mode != CompileEager || stats.Compilations != 1 || stats.CacheHits == 0 || stats.CachedPolicies != 1 {
+		t.Errorf("Expected evaluation to reuse the warmed-up policy, got %+v", stats)
+	}
+	if stats.WarmUp == nil || stats.WarmUp.Policies != 1 || stats.WarmUp.CompletedAt.IsZero() {
+		t.Errorf("Unexpected warm-up stats: %+v", stats.WarmUp)
+	}
+
+	if lazy := NewPolicyDecisionPoint(mockStorage).GetCompileStats(); lazy.Mode != CompileLazy || lazy.WarmUp != nil {
+		t.Errorf("Expected lazy mode without warm-up by default, got %+v", lazy)
+	}
+}
+
+// TestPolicyCompiler_ReloadedPolicies tests that compiled statements survive reloading an unchanged policy
+func TestPolicyCompiler_ReloadedPolicies(t *testing.T) {
+	compiler := NewPolicyCompiler(nil)
+	updatedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	load := func(revision int64, updatedAt time.Time) *models.Policy {
+		return &models.Policy{
+			ID:        "pol-1",
+			Version:   "2012-10-17",
+			Revision:  revision,
+			UpdatedAt: updatedAt,
+			Statement: []models.PolicyStatement{{Sid: "Allow", Effect: "Allow"}},
+		}
+	}
+
+	compiler.Compile(load(1, updatedAt))
+	compiler.Compile(load(1, updatedAt))
+	if stats := compiler.Stats(); stats.Compilations != 1 || stats.CacheHits != 1 {
+		t.Errorf("Expected the reloaded policy to hit the cache, got %+v", stats)
+	}
+
+	compiler.Compile(load(2, updatedAt.Add(time.Second)))
+	compiler.Compile(load(2, time.Time{}))
+	if stats := compiler.Stats(); stats.Compilations != 3 {
+		t.Errorf("Expected updated and unversioned policies to be recompiled, got %+v", stats)
+	}
+}
+
+func TestCompileModeFromEnv(t *testing.T) {
+	for value, expected := range map[string]string{"": CompileLazy, "lazy": CompileLazy, " Eager ": CompileEager} {
+		t.Setenv(constants.EnvCompileMode, value)
+		if mode, err := CompileModeFromEnv(); err != nil || mode != expected {
+			t.Errorf("CompileModeFromEnv(%q) = %q, %v; want %q", value, mode, err, expected)
+		}
+	}
+
+	t.Setenv(constants.EnvCompileMode, "sometimes")
+	if _, err := CompileModeFromEnv(); err == nil {
+		t.Error("Expected an unknown compile mode to be rejected")
+	}
+}
@@ -0,0 +1,113 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// ActionCatalog declares implied actions per system (the first action segment).
+// An operation implies other operations of the same system, e.g. in "document-service"
+// "write" implies "read" and "admin" implies "*" (every operation). Implications are transitive.
+//
+// JSON form: {"document-service": {"write": ["read"], "admin": ["*"]}}
+type ActionCatalog struct {
+	systems map[string]map[string][]string // system -> operation -> implied operations
+}
+
+// NewActionCatalog creates an empty action catalog
+func NewActionCatalog() *ActionCatalog {
+	return &ActionCatalog{systems: make(map[string]map[string][]string)}
+}
+
+// AddImplication records that operation implies the given operations within system
+func (c *ActionCatalog) AddImplication(system, operation string, implied ...string) {
+	operations, exists := c.systems[system]
+	if !exists {
+		operations = make(map[string][]string)
+		c.systems[system] = operations
+	}
+	operations[operation] = append(operations[operation], implied...)
+}
+
+// ParseActionCatalog parses a catalog from its JSON form
+func ParseActionCatalog(data []byte) (*ActionCatalog, error) {
+	var systems map[string]map[string][]string
+	if err := json.Unmarshal(data, &systems); err != nil {
+		return nil, fmt.Errorf("invalid action catalog: %w", err)
+	}
+
+	catalog := NewActionCatalog()
+	for system, operations := range systems {
+		if system == "" || strings.Contains(system, ":") {
+			return nil, fmt.Errorf("invalid action catalog system %q", system)
+		}
+		for operation, implied := range operations {
+			if operation == "" || operation == "*" || strings.Contains(operation, ":") {
+				return nil, fmt.Errorf("invalid action catalog operation %q in system %q", operation, system)
+			}
+			catalog.AddImplication(system, operation, implied...)
+		}
+	}
+	return catalog, nil
+}
+
+// LoadActionCatalog reads a JSON catalog from path
+func LoadActionCatalog(path string) (*ActionCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read action catalog: %w", err)
+	}
+	return ParseActionCatalog(data)
+}
+
+// ActionCatalogFromEnv loads the catalog named by ABAC_ACTION_CATALOG.
+// It returns nil (no implied actions) when the variable is unset.
+func ActionCatalogFromEnv() (*ActionCatalog, error) {
+	path := os.Getenv(constants.EnvActionCatalog)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadActionCatalog(path)
+}
+
+// Implies reports whether operation grants target within system, directly or transitively
+func (c *ActionCatalog) Implies(system, operation, target string) bool {
+	if operation == target {
+		return true
+	}
+
+	operations := c.systems[system]
+	visited := map[string]bool{operation: true}
+	pending := []string{operation}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, implied := range operations[current] {
+			if implied == target || implied == "*" {
+				return true
+			}
+			if !visited[implied] {
+				visited[implied] = true
+				pending = append(pending, implied)
+			}
+		}
+	}
+	return false
+}
+
+// ImplyingOperations returns the operations of system that imply target (excluding target), sorted
+func (c *ActionCatalog) ImplyingOperations(system, target string) []string {
+	var implying []string
+	for operation := range c.systems[system] {
+		if operation != target && c.Implies(system, operation, target) {
+			implying = append(implying, operation)
+		}
+	}
+	sort.Strings(implying)
+	return implying
+}
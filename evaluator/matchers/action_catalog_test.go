@@ -0,0 +1,113 @@
+package matchers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func TestActionCatalog_Implies(t *testing.T) {
+	catalog, err := ParseActionCatalog([]byte(`{
+		"document-service": {"admin": ["*"], "write": ["read"], "publish": ["write"]},
+		"billing": {"approve": ["view"]}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseActionCatalog failed: %v", err)
+	}
+
+	tests := []struct {
+		system, operation, target string
+		expected                  bool
+	}{
+		{"document-service", "read", "read", true},
+		{"document-service", "write", "read", true},
+		{"document-service", "publish", "read", true}, // transitive
+		{"document-service", "admin", "delete", true}, // wildcard
+		{"document-service", "read", "write", false},
+		{"billing", "write", "read", false}, // implications are per system
+		{"billing", "approve", "view", true},
+	}
+
+	for _, test := range tests {
+		if got := catalog.Implies(test.system, test.operation, test.target); got != test.expected {
+			t.Errorf("Implies(%q, %q, %q) = %v, want %v", test.system, test.operation, test.target, got, test.expected)
+		}
+	}
+
+	if implying := catalog.ImplyingOperations("document-service", "read"); len(implying) != 3 ||
+		implying[0] != "admin" || implying[1] != "publish" || implying[2] != "write" {
+		t.Errorf("Unexpected implying operations: %v", implying)
+	}
+}
+
+func TestActionCatalog_Cycles(t *testing.T) {
+	catalog := NewActionCatalog()
+	catalog.AddImplication("svc", "a", "b")
+	catalog.AddImplication("svc", "b", "a")
+
+	if !catalog.Implies("svc", "a", "b") || catalog.Implies("svc", "a", "c") {
+		t.Error("Unexpected result for cyclic implications")
+	}
+}
+
+func TestParseActionCatalog_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`not json`,
+		`{"svc:file": {"write": ["read"]}}`,
+		`{"svc": {"*": ["read"]}}`,
+		`{"svc": {"": ["read"]}}`,
+	} {
+		if _, err := ParseActionCatalog([]byte(data)); err == nil {
+			t.Errorf("Expected %s to be rejected", data)
+		}
+	}
+}
+
+func TestActionMatcher_MatchImplied(t *testing.T) {
+	matcher := NewActionMatcher()
+	if matcher.MatchImplied("document-service:file:write", "document-service:file:read") {
+		t.Error("Expected no implications without a catalog")
+	}
+
+	catalog := NewActionCatalog()
+	catalog.AddImplication("document-service", "write", "read")
+	catalog.AddImplication("document-service", "admin", "*")
+	matcher.SetCatalog(catalog)
+
+	tests := []struct {
+		pattern, action string
+		expected        bool
+	}{
+		{"document-service:file:write", "document-service:file:read", true},
+		{"document-service:*:write", "document-service:folder:read", true},
+		{"document-service:file:admin", "document-service:file:delete", true},
+		{"document-service:file:write", "document-service:folder:read", false},
+		{"document-service:file:read", "document-service:file:write", false},
+		{"payment-service:file:write", "payment-service:file:read", false},
+	}
+
+	for _, test := range tests {
+		if got := matcher.MatchImplied(test.pattern, test.action); got != test.expected {
+			t.Errorf("MatchImplied(%q, %q) = %v, want %v", test.pattern, test.action, got, test.expected)
+		}
+	}
+}
+
+func TestActionCatalogFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvActionCatalog, "")
+	if catalog, err := ActionCatalogFromEnv(); catalog != nil || err != nil {
+		t.Errorf("Expected nil catalog when unset, got %v (err=%v)", catalog, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "action_catalog.json")
+	if err := os.WriteFile(path, []byte(`{"document-service": {"write": ["read"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(constants.EnvActionCatalog, path)
+	catalog, err := ActionCatalogFromEnv()
+	if err != nil || catalog == nil || !catalog.Implies("document-service", "write", "read") {
+		t.Errorf("Unexpected catalog %v (err=%v)", catalog, err)
+	}
+}
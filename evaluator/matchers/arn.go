@@ -0,0 +1,31 @@
+package matchers
+
+import "strings"
+
+// arnFields is the number of fields of an ARN: arn:partition:service:region:account-id:resource
+const arnFields = 6
+
+// ARNMatcher matches AWS-style ARN resource patterns, e.g. "arn:aws:s3:::reports/*". Register it
+// for the "arn:" prefix. Wildcards of the partition, service, region and account fields stay within
+// their field; the resource field may contain ':' and '/' and its '*' spans them.
+type ARNMatcher struct{}
+
+// NewARNMatcher creates an ARN matcher
+func NewARNMatcher() *ARNMatcher {
+	return &ARNMatcher{}
+}
+
+// Match checks if an ARN matches an ARN pattern; malformed ARNs never match
+func (m *ARNMatcher) Match(pattern, resource string, context map[string]interface{}) bool {
+	patternFields := strings.SplitN(pattern, ":", arnFields)
+	resourceFields := strings.SplitN(resource, ":", arnFields)
+	if len(patternFields) != arnFields || len(resourceFields) != arnFields {
+		return false
+	}
+	for i := range patternFields {
+		if !fastPatternMatch(patternFields[i], resourceFields[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -3,21 +3,68 @@ package matchers
 import (
 	"regexp"
 	"strings"
+
+	"abac_go_example/evaluator/path"
 )
 
+// variableResolver resolves ${...} resource pattern variables in either notation (${user:department}, ${session.id})
+var variableResolver = path.NewCompositePathResolver()
+
 // ActionMatcher handles action pattern matching
-type ActionMatcher struct{}
+type ActionMatcher struct {
+	catalog  *ActionCatalog
+	registry *MatcherRegistry
+}
 
 // NewActionMatcher creates a new action matcher
 func NewActionMatcher() *ActionMatcher {
 	return &ActionMatcher{}
 }
 
+// SetCatalog configures the implied actions used by MatchImplied (nil disables implications)
+func (am *ActionMatcher) SetCatalog(catalog *ActionCatalog) {
+	am.catalog = catalog
+}
+
+// SetRegistry configures the custom matchers consulted before the built-in matching (nil disables them)
+func (am *ActionMatcher) SetRegistry(registry *MatcherRegistry) {
+	am.registry = registry
+}
+
+// MatchImplied is Match extended with the action catalog: the pattern also matches when it
+// matches the action with its operation replaced by one that implies it, so a grant on
+// "document-service:file:write" covers "document-service:file:read" when write implies read.
+func (am *ActionMatcher) MatchImplied(pattern, action string) bool {
+	if am.Match(pattern, action) {
+		return true
+	}
+	if am.catalog == nil {
+		return false
+	}
+
+	parts := strings.Split(action, ":")
+	if len(parts) < 2 {
+		return false
+	}
+	last := len(parts) - 1
+	for _, operation := range am.catalog.ImplyingOperations(parts[0], parts[last]) {
+		parts[last] = operation
+		if am.Match(pattern, strings.Join(parts, ":")) {
+			return true
+		}
+	}
+	return false
+}
+
 // Match checks if an action matches a pattern
 // Pattern format: <service>:<resource-type>:<operation>
 // Supports wildcards: *, prefix-*, *-suffix, *-middle-*
 // Trailing wildcard (*) matches remaining action segments
+// Patterns with a prefix registered in the MatcherRegistry are matched by the custom matcher.
 func (am *ActionMatcher) Match(pattern, action string) bool {
+	if custom, ok := am.registry.ActionMatcherFor(pattern); ok {
+		return custom.Match(pattern, action)
+	}
 	if pattern == "*" {
 		return true
 	}
@@ -64,36 +111,43 @@ func (am *ActionMatcher) matchSegment(pattern, value string) bool {
 	return am.matchWildcard(pattern, value)
 }
 
-// matchWildcard converts wildcard pattern to regex and matches
+// matchWildcard matches a wildcard pattern; characters other than '*' match literally
 func (am *ActionMatcher) matchWildcard(pattern, value string) bool {
-	// Convert wildcard pattern to regex
-	regexPattern := strings.ReplaceAll(pattern, "*", ".*")
-	regexPattern = "^" + regexPattern + "$"
-
-	regex, err := regexp.Compile(regexPattern)
-	if err != nil {
-		return false
-	}
-
-	return regex.MatchString(value)
+	return fastPatternMatch(pattern, value)
 }
 
 // ResourceMatcher handles resource pattern matching
-type ResourceMatcher struct{}
+type ResourceMatcher struct {
+	registry *MatcherRegistry
+}
 
 // NewResourceMatcher creates a new resource matcher
 func NewResourceMatcher() *ResourceMatcher {
 	return &ResourceMatcher{}
 }
 
+// SetRegistry configures the custom matchers consulted before the built-in matching (nil disables them)
+func (rm *ResourceMatcher) SetRegistry(registry *MatcherRegistry) {
+	rm.registry = registry
+}
+
 // Match checks if a resource matches a pattern
 // Pattern format: <service>:<resource-type>:<resource-id>
 // Hierarchical: <service>:<parent-type>:<parent-id>/<child-type>:<child-id>
 // Supports wildcards and variable substitution
+// URL path templates ("/api/v1/users/{id}") match URL path resources (see MatchURLTemplate)
+// Patterns with a prefix registered in the MatcherRegistry are matched by the custom matcher.
 func (rm *ResourceMatcher) Match(pattern, resource string, context map[string]interface{}) bool {
+	if custom, ok := rm.registry.ResourceMatcherFor(pattern); ok {
+		return custom.Match(pattern, resource, context)
+	}
 	if pattern == "*" {
 		return true
 	}
+	if IsURLTemplate(pattern) {
+		_, ok := MatchURLTemplate(rm.substituteVariables(pattern, context), resource)
+		return ok
+	}
 
 	// Validate resource format before matching
 	if !rm.validateResourceFormat(resource) {
@@ -117,6 +171,15 @@ func (rm *ResourceMatcher) Match(pattern, resource string, context map[string]in
 	return rm.matchSimple(expandedPattern, resource)
 }
 
+// CaptureParams returns the path parameters a URL template pattern captures from resource; false
+// when the pattern is not a built-in URL template or does not match
+func (rm *ResourceMatcher) CaptureParams(pattern, resource string, context map[string]interface{}) (map[string]string, bool) {
+	if _, custom := rm.registry.ResourceMatcherFor(pattern); custom || !IsURLTemplate(pattern) {
+		return nil, false
+	}
+	return MatchURLTemplate(rm.substituteVariables(pattern, context), resource)
+}
+
 // matchSimple handles simple resource pattern matching
 func (rm *ResourceMatcher) matchSimple(pattern, resource string) bool {
 	patternParts := strings.Split(pattern, ":")
@@ -168,18 +231,9 @@ func (rm *ResourceMatcher) matchSegment(pattern, value string) bool {
 	return rm.matchWildcard(pattern, value)
 }
 
-// matchWildcard converts wildcard pattern to regex and matches
+// matchWildcard matches a wildcard pattern; characters other than '*' match literally
 func (rm *ResourceMatcher) matchWildcard(pattern, value string) bool {
-	// Convert wildcard pattern to regex
-	regexPattern := strings.ReplaceAll(pattern, "*", ".*")
-	regexPattern = "^" + regexPattern + "$"
-
-	regex, err := regexp.Compile(regexPattern)
-	if err != nil {
-		return false
-	}
-
-	return regex.MatchString(value)
+	return fastPatternMatch(pattern, value)
 }
 
 // hasVariables checks if a string contains variable substitutions
@@ -248,7 +302,7 @@ func (rm *ResourceMatcher) substituteVariables(pattern string, context map[strin
 	for _, match := range matches {
 		if len(match) >= 2 {
 			varName := match[1]
-			if value, exists := context[varName]; exists {
+			if value, exists := variableResolver.Resolve(varName, context); exists {
 				if strValue, ok := value.(string); ok {
 					result = strings.ReplaceAll(result, match[0], strValue)
 				}
@@ -258,3 +312,9 @@ func (rm *ResourceMatcher) substituteVariables(pattern string, context map[strin
 
 	return result
 }
+
+// Built-in matchers satisfy the matcher interfaces
+var (
+	_ ActionMatcherInterface   = (*ActionMatcher)(nil)
+	_ ResourceMatcherInterface = (*ResourceMatcher)(nil)
+)
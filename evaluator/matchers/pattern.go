@@ -0,0 +1,42 @@
+package matchers
+
+// WildcardMatch reports whether value matches a glob pattern where '*' matches any
+// (possibly empty) sequence of characters. All other characters match literally.
+func WildcardMatch(pattern, value string) bool {
+	return fastPatternMatch(pattern, value)
+}
+
+// fastPatternMatch matches '*' wildcards without regular expressions.
+// It runs in O(len(pattern) * len(value)) worst case with no recursion or allocation,
+// so user-supplied patterns cannot trigger catastrophic backtracking.
+func fastPatternMatch(pattern, value string) bool {
+	p, v := 0, 0
+	starIdx, matchIdx := -1, 0
+
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			// Remember the star position and try matching zero characters first
+			starIdx = p
+			matchIdx = v
+			p++
+		case p < len(pattern) && pattern[p] == value[v]:
+			p++
+			v++
+		case starIdx >= 0:
+			// Backtrack: let the last star absorb one more character
+			p = starIdx + 1
+			matchIdx++
+			v = matchIdx
+		default:
+			return false
+		}
+	}
+
+	// Remaining pattern must consist only of stars
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
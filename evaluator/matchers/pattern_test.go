@@ -0,0 +1,62 @@
+package matchers
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFastPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"document:*", "document:read", true},
+		{"*:read", "document:read", true},
+		{"doc*:re*d", "document:read", true},
+		{"a*b*c", "a-x-c", false},
+		{"ab*ba", "aba", false},
+		{"doc.*", "docs", false},
+		{"doc.*", "doc.pdf", true},
+		{"a(b*", "a(bc", true},
+		{"exact", "exact", true},
+		{"exact", "exacter", false},
+		{"**a**", "xxaxx", true},
+	}
+
+	for _, tt := range tests {
+		if got := fastPatternMatch(tt.pattern, tt.value); got != tt.expected {
+			t.Errorf("fastPatternMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.expected)
+		}
+	}
+}
+
+// FuzzFastPatternMatch compares fastPatternMatch against an escaped regex reference implementation
+func FuzzFastPatternMatch(f *testing.F) {
+	f.Add("document:*", "document:read")
+	f.Add("*:*:*", "api:documents:1")
+	f.Add("a*b*c", "a-x-c")
+	f.Add("(a+)+*", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaa!")
+	f.Add("[*]", "[x]")
+	f.Add("***", "")
+
+	f.Fuzz(func(t *testing.T, pattern, value string) {
+		if !utf8.ValidString(pattern) || !utf8.ValidString(value) {
+			t.Skip()
+		}
+
+		parts := strings.Split(pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		reference := regexp.MustCompile("(?s)^" + strings.Join(parts, ".*") + "$")
+
+		if got, want := fastPatternMatch(pattern, value), reference.MatchString(value); got != want {
+			t.Errorf("fastPatternMatch(%q, %q) = %v, reference = %v", pattern, value, got, want)
+		}
+	})
+}
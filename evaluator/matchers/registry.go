@@ -0,0 +1,100 @@
+package matchers
+
+import (
+	"strings"
+	"sync"
+)
+
+// ActionMatcherInterface matches an action pattern of a policy statement against a requested action
+type ActionMatcherInterface interface {
+	Match(pattern, action string) bool
+}
+
+// ResourceMatcherInterface matches a resource pattern of a policy statement against a requested
+// resource; context carries the evaluation context for ${...} variables
+type ResourceMatcherInterface interface {
+	Match(pattern, resource string, context map[string]interface{}) bool
+}
+
+// ActionMatcherFunc adapts a function to ActionMatcherInterface
+type ActionMatcherFunc func(pattern, action string) bool
+
+// Match calls f(pattern, action)
+func (f ActionMatcherFunc) Match(pattern, action string) bool {
+	return f(pattern, action)
+}
+
+// ResourceMatcherFunc adapts a function to ResourceMatcherInterface
+type ResourceMatcherFunc func(pattern, resource string, context map[string]interface{}) bool
+
+// Match calls f(pattern, resource, context)
+func (f ResourceMatcherFunc) Match(pattern, resource string, context map[string]interface{}) bool {
+	return f(pattern, resource, context)
+}
+
+// MatcherRegistry holds custom matchers for naming schemes beyond colon/glob patterns (e.g. ARNs
+// or URL templates), selected by pattern prefix: the matcher with the longest prefix of a pattern
+// matches it, and patterns without a registered prefix use the built-in matchers. An empty prefix
+// replaces the built-in matcher for every pattern.
+// Matchers may be registered at any time and must be safe for concurrent use.
+type MatcherRegistry struct {
+	mu        sync.RWMutex
+	actions   map[string]ActionMatcherInterface
+	resources map[string]ResourceMatcherInterface
+}
+
+// NewMatcherRegistry creates an empty matcher registry
+func NewMatcherRegistry() *MatcherRegistry {
+	return &MatcherRegistry{
+		actions:   make(map[string]ActionMatcherInterface),
+		resources: make(map[string]ResourceMatcherInterface),
+	}
+}
+
+// RegisterActionMatcher matches action patterns starting with prefix with matcher, replacing a
+// matcher registered for the same prefix
+func (r *MatcherRegistry) RegisterActionMatcher(prefix string, matcher ActionMatcherInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions[prefix] = matcher
+}
+
+// RegisterResourceMatcher matches resource patterns starting with prefix with matcher, replacing a
+// matcher registered for the same prefix
+func (r *MatcherRegistry) RegisterResourceMatcher(prefix string, matcher ResourceMatcherInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resources[prefix] = matcher
+}
+
+// ActionMatcherFor returns the custom matcher of an action pattern; false when the built-in matcher applies
+func (r *MatcherRegistry) ActionMatcherFor(pattern string) (ActionMatcherInterface, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return longestPrefix(r.actions, pattern)
+}
+
+// ResourceMatcherFor returns the custom matcher of a resource pattern; false when the built-in matcher applies
+func (r *MatcherRegistry) ResourceMatcherFor(pattern string) (ResourceMatcherInterface, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return longestPrefix(r.resources, pattern)
+}
+
+// longestPrefix returns the value registered for the longest prefix of pattern
+func longestPrefix[M any](registered map[string]M, pattern string) (M, bool) {
+	var found M
+	best := -1
+	for prefix, matcher := range registered {
+		if len(prefix) > best && strings.HasPrefix(pattern, prefix) {
+			found, best = matcher, len(prefix)
+		}
+	}
+	return found, best >= 0
+}
@@ -0,0 +1,56 @@
+package matchers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatcherRegistry(t *testing.T) {
+	registry := NewMatcherRegistry()
+	registry.RegisterResourceMatcher("arn:", NewARNMatcher())
+	registry.RegisterResourceMatcher("arn:legacy:", ResourceMatcherFunc(func(pattern, resource string, _ map[string]interface{}) bool {
+		return strings.EqualFold(pattern, resource)
+	}))
+	registry.RegisterActionMatcher("s3:", ActionMatcherFunc(func(pattern, action string) bool {
+		return strings.EqualFold(pattern, action) // IAM-style case-insensitive actions
+	}))
+
+	resources := NewResourceMatcher()
+	resources.SetRegistry(registry)
+	tests := []struct {
+		pattern, resource string
+		expected          bool
+	}{
+		{"arn:aws:s3:::reports/*", "arn:aws:s3:::reports/2024/q1.csv", true},
+		{"arn:aws:s3:::reports/*", "arn:aws:s3:::invoices/q1.csv", false},
+		{"arn:aws:dynamodb:*:123456789012:table/orders", "arn:aws:dynamodb:eu-west-1:123456789012:table/orders", true},
+		{"arn:aws:dynamodb:*:123456789012:table/orders", "arn:aws:dynamodb:eu-west-1:999999999999:table/orders", false},
+		{"arn:aws:*:us-east-1::*", "arn:aws:s3:eu:west:1::x", false}, // Field wildcards stop at ':'
+		{"arn:aws:s3:::reports/*", "api:documents:doc-1", false},
+		{"arn:legacy:X", "ARN:LEGACY:x", true},           // Longest prefix wins
+		{"api:documents:*", "api:documents:doc-1", true}, // No registered prefix: built-in matching
+	}
+	for _, tt := range tests {
+		if got := resources.Match(tt.pattern, tt.resource, nil); got != tt.expected {
+			t.Errorf("Match(%q, %q) = %v, expected %v", tt.pattern, tt.resource, got, tt.expected)
+		}
+	}
+
+	actions := NewActionMatcher()
+	actions.SetRegistry(registry)
+	if !actions.Match("s3:GetObject", "s3:getobject") || !actions.MatchImplied("s3:GetObject", "S3:GETOBJECT") {
+		t.Error("Expected the custom action matcher for s3: patterns")
+	}
+	if actions.Match("document:READ", "document:read") {
+		t.Error("Expected built-in case-sensitive matching without a registered prefix")
+	}
+
+	// An empty prefix replaces the built-in matcher
+	registry.RegisterResourceMatcher("", ResourceMatcherFunc(func(pattern, resource string, _ map[string]interface{}) bool { return false }))
+	if resources.Match("*", "api:documents:doc-1", nil) {
+		t.Error("Expected the empty prefix to replace the built-in matcher")
+	}
+	if !resources.Match("arn:aws:s3:::reports/*", "arn:aws:s3:::reports/a", nil) {
+		t.Error("Expected longer prefixes to win over the empty prefix")
+	}
+}
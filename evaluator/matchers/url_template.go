@@ -0,0 +1,69 @@
+package matchers
+
+import (
+	"net/url"
+	"strings"
+)
+
+// IsURLTemplate reports whether a resource pattern is a URL path template, e.g.
+// "/api/v1/users/{id}/documents/{docId}"
+func IsURLTemplate(pattern string) bool {
+	return strings.HasPrefix(pattern, "/")
+}
+
+// MatchURLTemplate matches a URL path against a template and returns the captured path parameters.
+// A "{name}" segment captures one non-empty path segment (URL-unescaped), a segment containing '*'
+// is a wildcard within that segment, and any other segment matches literally. The query string
+// and a trailing slash of the path are ignored; a parameter used twice must capture the same value.
+func MatchURLTemplate(template, path string) (map[string]string, bool) {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	templateSegments := splitURLPath(template)
+	pathSegments := splitURLPath(path)
+	if len(templateSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, segment := range templateSegments {
+		value := pathSegments[i]
+		name, isParam := urlTemplateParam(segment)
+		switch {
+		case isParam:
+			if value == "" {
+				return nil, false
+			}
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			if previous, seen := params[name]; seen && previous != value {
+				return nil, false
+			}
+			params[name] = value
+		case strings.Contains(segment, "*"):
+			if !fastPatternMatch(segment, value) {
+				return nil, false
+			}
+		case segment != value:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// splitURLPath splits a path into its segments, ignoring a trailing slash
+func splitURLPath(path string) []string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return strings.Split(path, "/")
+}
+
+// urlTemplateParam returns the parameter name of a "{name}" template segment
+func urlTemplateParam(segment string) (string, bool) {
+	if len(segment) < 3 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+		return "", false
+	}
+	return segment[1 : len(segment)-1], true
+}
@@ -0,0 +1,52 @@
+package matchers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchURLTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		params   map[string]string // nil: no match
+	}{
+		{"parameters", "/api/v1/users/{id}/documents/{docId}", "/api/v1/users/42/documents/doc-7", map[string]string{"id": "42", "docId": "doc-7"}},
+		{"literal", "/api/v1/health", "/api/v1/health", map[string]string{}},
+		{"query and trailing slash ignored", "/api/v1/users/{id}", "/api/v1/users/42/?fields=name", map[string]string{"id": "42"}},
+		{"unescaped", "/files/{name}", "/files/q1%20report.pdf", map[string]string{"name": "q1 report.pdf"}},
+		{"segment wildcard", "/api/*/users/{id}", "/api/v2/users/42", map[string]string{"id": "42"}},
+		{"parameter does not span segments", "/api/v1/users/{id}", "/api/v1/users/42/documents", nil},
+		{"empty parameter", "/api/v1/users/{id}/documents", "/api/v1/users//documents", nil},
+		{"literal mismatch", "/api/v1/users/{id}", "/api/v1/groups/42", nil},
+		{"repeated parameter must agree", "/orgs/{org}/mirror/{org}", "/orgs/a/mirror/b", nil},
+		{"repeated parameter", "/orgs/{org}/mirror/{org}", "/orgs/a/mirror/a", map[string]string{"org": "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, ok := MatchURLTemplate(tt.template, tt.path)
+			if ok != (tt.params != nil) || (ok && !reflect.DeepEqual(params, tt.params)) {
+				t.Errorf("MatchURLTemplate(%q, %q) = %v, %v; expected %v", tt.template, tt.path, params, ok, tt.params)
+			}
+		})
+	}
+}
+
+func TestResourceMatcher_URLTemplates(t *testing.T) {
+	matcher := NewResourceMatcher()
+	context := map[string]interface{}{"user:tenant": "acme"}
+
+	if !matcher.Match("/tenants/${user:tenant}/users/{id}", "/tenants/acme/users/42", context) {
+		t.Error("Expected variables to be substituted in URL templates")
+	}
+	if matcher.Match("/tenants/${user:tenant}/users/{id}", "/tenants/other/users/42", context) {
+		t.Error("Expected another tenant not to match")
+	}
+	if params, ok := matcher.CaptureParams("/tenants/${user:tenant}/users/{id}", "/tenants/acme/users/42", context); !ok || params["id"] != "42" {
+		t.Errorf("Expected id to be captured, got %v (%v)", params, ok)
+	}
+	if _, ok := matcher.CaptureParams("api:users:*", "api:users:42", context); ok {
+		t.Error("Expected colon patterns to capture nothing")
+	}
+}
@@ -0,0 +1,184 @@
+package path
+
+import (
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// NotationNamespaces are the context namespaces exposed both as flat keys ("user:department")
+// and as structured maps ("user" → {"department": ...})
+var NotationNamespaces = []string{
+	strings.TrimSuffix(constants.ContextKeyUserPrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeyResourcePrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeyEnvironmentPrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeyRequestPrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeySessionPrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeyRelationshipPrefix, ":"),
+	strings.TrimSuffix(constants.ContextKeyDevicePrefix, ":"),
+}
+
+// legacyKeyAliases maps flat keys whose structured attribute has another name
+var legacyKeyAliases = map[string]string{
+	constants.ContextKeyUserPrefix + "SubjectType":      "user.subject_type",
+	constants.ContextKeyResourcePrefix + "ResourceType": "resource.resource_type",
+	constants.ContextKeyResourcePrefix + "ResourceId":   "resource.resource_id",
+	constants.ContextKeyResourceTags:                    "resource.tags",
+}
+
+// BridgeNotations adds flat attributes of NotationNamespaces to their structured maps, so that
+// flat "environment:client_ip" is also reachable as "environment" → {"client_ip": ...}. The reverse
+// direction is not materialised: CompositePathResolver resolves colon paths such as
+// "user:attributes.level" on the structured maps (see StructuredNotationResolver), keeping the
+// context small. Existing entries are never overwritten, flat keys containing dots stay flat only,
+// and a namespace whose top-level key holds something other than a map is left alone.
+func BridgeNotations(context map[string]interface{}) {
+	flat := make(map[string]map[string]interface{}, len(NotationNamespaces))
+	for key, value := range context {
+		namespace, name, found := strings.Cut(key, ":")
+		if !found || name == "" || strings.Contains(name, ".") || !isNotationNamespace(namespace) {
+			continue
+		}
+		if flat[namespace] == nil {
+			flat[namespace] = make(map[string]interface{})
+		}
+		flat[namespace][name] = value
+	}
+
+	for _, namespace := range NotationNamespaces {
+		existing, exists := context[namespace]
+		structured, isMap := existing.(map[string]interface{})
+		if exists && !isMap {
+			continue
+		}
+
+		// Copy instead of extending the structured map, which may be shared (e.g. session context)
+		merged := make(map[string]interface{}, len(structured)+len(flat[namespace]))
+		for name, value := range structured {
+			merged[name] = value
+		}
+		for name, value := range flat[namespace] {
+			if _, exists := merged[name]; !exists {
+				merged[name] = value
+			}
+		}
+		if len(merged) > 0 {
+			context[namespace] = merged
+		}
+	}
+}
+
+// PreferredKey returns the dot notation form of a flat condition key ("user:department" →
+// "user.department") and whether key used the deprecated colon form
+func PreferredKey(key string) (string, bool) {
+	if alias, ok := legacyKeyAliases[key]; ok {
+		return alias, true
+	}
+	namespace, name, found := strings.Cut(key, ":")
+	if !found || name == "" || !isNotationNamespace(namespace) {
+		return key, false
+	}
+	return namespace + "." + name, true
+}
+
+// DeprecatedKey is a condition key written in colon notation
+type DeprecatedKey struct {
+	Operator  string `json:"operator"`
+	Key       string `json:"key"`
+	Preferred string `json:"preferred"`
+}
+
+// DeprecatedConditionKeys lists the colon notation keys of a statement Condition map, including
+// keys nested in And/Or/Not. ResourceTag and quota operators are skipped: their keys are tag and
+// counter names, not attribute paths.
+func DeprecatedConditionKeys(conditions map[string]interface{}) []DeprecatedKey {
+	var found []DeprecatedKey
+	collectDeprecatedKeys(conditions, &found)
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Key != found[j].Key {
+			return found[i].Key < found[j].Key
+		}
+		return found[i].Operator < found[j].Operator
+	})
+	return found
+}
+
+func collectDeprecatedKeys(conditions map[string]interface{}, found *[]DeprecatedKey) {
+	for operator, operands := range conditions {
+		switch constants.OperatorKey(operator) {
+		case constants.OpAnd, constants.OpOr:
+			items, _ := operands.([]interface{})
+			for _, item := range items {
+				if nested, ok := item.(map[string]interface{}); ok {
+					collectDeprecatedKeys(nested, found)
+				}
+			}
+			continue
+		case constants.OpNot:
+			if nested, ok := operands.(map[string]interface{}); ok {
+				collectDeprecatedKeys(nested, found)
+			}
+			continue
+		case constants.OpResourceTag, constants.OpDailyQuotaBelow, constants.OpRequestRateBelow:
+			continue
+		}
+
+		block, ok := operands.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range block {
+			if preferred, deprecated := PreferredKey(key); deprecated {
+				*found = append(*found, DeprecatedKey{Operator: operator, Key: key, Preferred: preferred})
+			}
+		}
+	}
+}
+
+// NonstandardOperator is a condition operator spelled other than its canonical name
+type NonstandardOperator struct {
+	Operator  string `json:"operator"`
+	Canonical string `json:"canonical"`
+}
+
+// NonstandardOperators lists the operators of a statement Condition map, including those nested in
+// And/Or/Not, written as a case variant ("stringequals") or alias ("Boolean") of their canonical name
+func NonstandardOperators(conditions map[string]interface{}) []NonstandardOperator {
+	var found []NonstandardOperator
+	collectNonstandardOperators(conditions, &found)
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Operator < found[j].Operator })
+	return found
+}
+
+func collectNonstandardOperators(conditions map[string]interface{}, found *[]NonstandardOperator) {
+	for operator, operands := range conditions {
+		name := constants.ResolveOperator(operator)
+		if name.Nonstandard {
+			*found = append(*found, NonstandardOperator{Operator: operator, Canonical: name.Canonical})
+		}
+
+		switch name.Key {
+		case constants.OpAnd, constants.OpOr:
+			items, _ := operands.([]interface{})
+			for _, item := range items {
+				if nested, ok := item.(map[string]interface{}); ok {
+					collectNonstandardOperators(nested, found)
+				}
+			}
+		case constants.OpNot:
+			if nested, ok := operands.(map[string]interface{}); ok {
+				collectNonstandardOperators(nested, found)
+			}
+		}
+	}
+}
+
+func isNotationNamespace(namespace string) bool {
+	for _, candidate := range NotationNamespaces {
+		if namespace == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBridgeNotations(t *testing.T) {
+	session := map[string]interface{}{"mfa_verified": true}
+	context := map[string]interface{}{
+		"user:department":                     "Engineering",
+		"user":                                map[string]interface{}{"department": "Engineering", "attributes": map[string]interface{}{"level": 5}},
+		"environment:client_ip":               "10.0.0.1",
+		"environment:client_cert":             map[string]interface{}{"common_name": "svc"},
+		"environment:client_cert.common_name": "svc",
+		"device":                              map[string]interface{}{"posture": map[string]interface{}{"managed": true}},
+		"request:custom":                      map[string]interface{}{"nested": "value"},
+		"session":                             session,
+		"relationship":                        "not a map",
+		"relationship:is_owner":               true,
+	}
+	BridgeNotations(context)
+
+	resolver := NewCompositePathResolver()
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"user:department", "Engineering"},
+		{"user.department", "Engineering"},
+		{"user:attributes.level", 5},
+		{"environment.client_ip", "10.0.0.1"},
+		{"environment.client_cert.common_name", "svc"},
+		{"request.custom.nested", "value"},
+		{"session:mfa_verified", true},
+		{"relationship:is_owner", true},
+		{"device:posture.managed", true},
+	}
+	for _, tt := range tests {
+		if value, found := resolver.Resolve(tt.path, context); !found || !reflect.DeepEqual(value, tt.want) {
+			t.Errorf("Resolve(%q) = %v, %v; want %v", tt.path, value, found, tt.want)
+		}
+	}
+
+	if _, exists := context["user:attributes"]; exists {
+		t.Error("expected structured attributes not to be materialised as flat keys")
+	}
+	if _, exists := context["environment"].(map[string]interface{})["client_cert.common_name"]; exists {
+		t.Error("expected flat keys containing dots to stay flat")
+	}
+	if context["relationship"] != "not a map" {
+		t.Error("expected a non-map namespace value to be left alone")
+	}
+	if len(session) != 1 {
+		t.Errorf("expected the structured session map to stay unchanged, got %v", session)
+	}
+}
+
+func TestPreferredKey(t *testing.T) {
+	tests := []struct {
+		key        string
+		preferred  string
+		deprecated bool
+	}{
+		{"user:department", "user.department", true},
+		{"environment:client_cert.issuer_dn", "environment.client_cert.issuer_dn", true},
+		{"resource:ResourceType", "resource.resource_type", true},
+		{"user.department", "user.department", false},
+		{"custom:key", "custom:key", false},
+	}
+	for _, tt := range tests {
+		if preferred, deprecated := PreferredKey(tt.key); preferred != tt.preferred || deprecated != tt.deprecated {
+			t.Errorf("PreferredKey(%q) = %q, %v", tt.key, preferred, deprecated)
+		}
+	}
+}
+
+func TestDeprecatedConditionKeys(t *testing.T) {
+	conditions := map[string]interface{}{
+		"StringEquals": map[string]interface{}{"user:role": "admin", "user.department": "Engineering"},
+		"Or": []interface{}{
+			map[string]interface{}{"Bool": map[string]interface{}{"session:mfa_verified": true}},
+			map[string]interface{}{"Not": map[string]interface{}{"IPInRange": map[string]interface{}{"environment:client_ip": []interface{}{"10.0.0.0/8"}}}},
+		},
+		"ResourceTag":     map[string]interface{}{"env:prod": "*"},
+		"DailyQuotaBelow": map[string]interface{}{"user:exports": 10},
+	}
+
+	want := []DeprecatedKey{
+		{Operator: "IPInRange", Key: "environment:client_ip", Preferred: "environment.client_ip"},
+		{Operator: "Bool", Key: "session:mfa_verified", Preferred: "session.mfa_verified"},
+		{Operator: "StringEquals", Key: "user:role", Preferred: "user.role"},
+	}
+	if got := DeprecatedConditionKeys(conditions); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedConditionKeys = %+v, want %+v", got, want)
+	}
+}
+
+func TestNonstandardOperators(t *testing.T) {
+	conditions := map[string]interface{}{
+		"StringEquals": map[string]interface{}{"user.role": "admin"},
+		"or": []interface{}{
+			map[string]interface{}{"Boolean": map[string]interface{}{"session.mfa_verified": true}},
+			map[string]interface{}{"Not": map[string]interface{}{"ipinrange": map[string]interface{}{"environment.client_ip": "10.0.0.0/8"}}},
+		},
+		"CustomOperator": map[string]interface{}{"user.role": "admin"},
+	}
+
+	want := []NonstandardOperator{
+		{Operator: "Boolean", Canonical: "Bool"},
+		{Operator: "ipinrange", Canonical: "IPInRange"},
+		{Operator: "or", Canonical: "Or"},
+	}
+	if got := NonstandardOperators(conditions); !reflect.DeepEqual(got, want) {
+		t.Errorf("NonstandardOperators = %+v, want %+v", got, want)
+	}
+}
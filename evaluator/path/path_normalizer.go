@@ -7,13 +7,20 @@ import (
 	"strings"
 )
 
+// WildcardIndex is the ArrayIndices value of a [*] projection: the rest of the path is
+// resolved on every array element and the results are returned as a slice
+const WildcardIndex = -1
+
+// noArrayIndex is returned by parseArrayAccess for parts without array access
+const noArrayIndex = -2
+
 // PathInfo contains parsed and normalized path information
 type PathInfo struct {
 	// Original raw path
 	Raw string
 	// Normalized path parts (without array indices)
 	Parts []string
-	// Map of part index to array index (e.g., parts[2] accesses array[5])
+	// Map of part index to array index (e.g., parts[2] accesses array[5]); WildcardIndex for [*]
 	ArrayIndices map[int]int
 	// Whether this path contains array access
 	HasArrayAccess bool
@@ -32,7 +39,9 @@ func NewPathNormalizer() *PathNormalizer {
 	}
 }
 
-// NormalizePath validates and normalizes a path string
+// NormalizePath validates and normalizes a path string.
+// Keys that are not identifiers (e.g. containing dots) can be escaped with a backslash
+// (user.kubernetes\.io/role) or quoted in brackets (user.attributes["kubernetes.io/role"]).
 func (pn *PathNormalizer) NormalizePath(path string) (*PathInfo, error) {
 	if path == "" {
 		return nil, fmt.Errorf("path cannot be empty")
@@ -45,6 +54,10 @@ func (pn *PathNormalizer) NormalizePath(path string) (*PathInfo, error) {
 		return nil, fmt.Errorf("path cannot be empty after trimming")
 	}
 
+	if HasEscapes(path) {
+		return pn.normalizeEscapedPath(path)
+	}
+
 	// Split by dots
 	rawParts := strings.Split(path, ".")
 
@@ -73,7 +86,7 @@ func (pn *PathNormalizer) NormalizePath(path string) (*PathInfo, error) {
 				info.Parts = append(info.Parts, fieldName)
 			}
 
-			if arrayIndex >= 0 {
+			if arrayIndex != noArrayIndex {
 				// Record array index for this position
 				info.ArrayIndices[len(info.Parts)-1] = arrayIndex
 				info.HasArrayAccess = true
@@ -94,14 +107,58 @@ func (pn *PathNormalizer) NormalizePath(path string) (*PathInfo, error) {
 	return info, nil
 }
 
+// normalizeEscapedPath normalizes a path containing escaped or quoted keys.
+// Escaped and quoted keys are taken literally: they are neither validated as identifiers
+// nor read as numeric indices.
+func (pn *PathNormalizer) normalizeEscapedPath(path string) (*PathInfo, error) {
+	tokens, err := tokenizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PathInfo{
+		Raw:          path,
+		Parts:        make([]string, 0, len(tokens)),
+		ArrayIndices: make(map[int]int),
+	}
+
+	for _, token := range tokens {
+		switch {
+		case token.isIndex:
+			if len(info.Parts) == 0 {
+				return nil, fmt.Errorf("array access must follow a field")
+			}
+			info.ArrayIndices[len(info.Parts)-1] = token.index
+			info.HasArrayAccess = true
+		case token.literal:
+			info.Parts = append(info.Parts, token.key)
+		case pn.isNumericPart(token.key, len(info.Parts) > 0):
+			index, _ := strconv.Atoi(token.key)
+			info.ArrayIndices[len(info.Parts)-1] = index
+			info.HasArrayAccess = true
+		default:
+			if !pn.isValidIdentifier(token.key) {
+				return nil, fmt.Errorf("invalid identifier: '%s'", token.key)
+			}
+			info.Parts = append(info.Parts, token.key)
+		}
+	}
+
+	if len(info.Parts) == 0 {
+		return nil, fmt.Errorf("path must contain at least one valid part")
+	}
+
+	return info, nil
+}
+
 // parseArrayAccess parses array access notation
-// Supports: field[0], field.0 (when isFollowing is true)
+// Supports: field[0], field[*], field.0 (when isFollowing is true)
 // Returns: fieldName, arrayIndex, error
 func (pn *PathNormalizer) parseArrayAccess(part string, isFollowing bool) (string, int, error) {
 	// Try bracket notation: field[0]
 	if idx := strings.Index(part, "["); idx >= 0 {
 		if !strings.HasSuffix(part, "]") {
-			return "", -1, fmt.Errorf("unclosed bracket in '%s'", part)
+			return "", noArrayIndex, fmt.Errorf("unclosed bracket in '%s'", part)
 		}
 
 		fieldName := part[:idx]
@@ -109,13 +166,17 @@ func (pn *PathNormalizer) parseArrayAccess(part string, isFollowing bool) (strin
 
 		// Validate field name if it exists
 		if fieldName != "" && !pn.isValidIdentifier(fieldName) {
-			return "", -1, fmt.Errorf("invalid field name '%s'", fieldName)
+			return "", noArrayIndex, fmt.Errorf("invalid field name '%s'", fieldName)
+		}
+
+		if indexStr == "*" {
+			return fieldName, WildcardIndex, nil
 		}
 
 		// Parse index
 		arrayIndex, err := strconv.Atoi(indexStr)
 		if err != nil || arrayIndex < 0 {
-			return "", -1, fmt.Errorf("invalid array index '%s'", indexStr)
+			return "", noArrayIndex, fmt.Errorf("invalid array index '%s'", indexStr)
 		}
 
 		return fieldName, arrayIndex, nil
@@ -126,12 +187,12 @@ func (pn *PathNormalizer) parseArrayAccess(part string, isFollowing bool) (strin
 	if isFollowing && pn.isNumericPart(part, true) {
 		arrayIndex, err := strconv.Atoi(part)
 		if err != nil || arrayIndex < 0 {
-			return "", -1, fmt.Errorf("invalid array index '%s'", part)
+			return "", noArrayIndex, fmt.Errorf("invalid array index '%s'", part)
 		}
 		return "", arrayIndex, nil
 	}
 
-	return part, -1, nil
+	return part, noArrayIndex, nil
 }
 
 // isNumericPart checks if a part is purely numeric
@@ -165,6 +226,132 @@ func (pn *PathNormalizer) isValidIdentifier(s string) bool {
 	return true
 }
 
+// pathToken is a key or an array index of an escaped path
+type pathToken struct {
+	key     string
+	index   int
+	isIndex bool
+	literal bool // The key was escaped or quoted
+}
+
+// HasEscapes reports whether path uses backslash escapes or quoted bracket keys
+func HasEscapes(path string) bool {
+	return strings.Contains(path, "\\") || strings.Contains(path, `["`) || strings.Contains(path, "['")
+}
+
+// SplitPath splits a path into its keys, honoring backslash escapes and quoted bracket keys:
+//
+//	user.kubernetes\.io/role            -> [user kubernetes.io/role]
+//	user.attributes["kubernetes.io/role"] -> [user attributes kubernetes.io/role]
+//
+// Keys are not validated. Array indices are rejected; use NormalizePath for them.
+func SplitPath(path string) ([]string, error) {
+	if !HasEscapes(path) {
+		return strings.Split(path, "."), nil
+	}
+
+	tokens, err := tokenizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.isIndex {
+			return nil, fmt.Errorf("array access is not supported here")
+		}
+		keys = append(keys, token.key)
+	}
+	return keys, nil
+}
+
+// tokenizePath splits an escaped path into keys and array indices.
+// A backslash makes the next character literal; ["key"] or ['key'] is a literal key
+// (a backslash escapes the quote inside it); [n] is an array index and [*] a projection.
+func tokenizePath(path string) ([]pathToken, error) {
+	var tokens []pathToken
+	var key strings.Builder
+	literal, pending := false, false
+
+	flush := func() {
+		if pending {
+			tokens = append(tokens, pathToken{key: key.String(), literal: literal})
+		}
+		key.Reset()
+		literal, pending = false, false
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i+1 >= len(path) {
+				return nil, fmt.Errorf("dangling escape at end of '%s'", path)
+			}
+			i++
+			key.WriteByte(path[i])
+			literal, pending = true, true
+		case '.':
+			flush()
+		case '[':
+			flush()
+			token, next, err := parseBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+			i = next
+			if i+1 < len(path) && path[i+1] != '.' && path[i+1] != '[' {
+				return nil, fmt.Errorf("unexpected '%c' after ']' in '%s'", path[i+1], path)
+			}
+		default:
+			key.WriteByte(c)
+			pending = true
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// parseBracket parses the bracket starting at path[open] and returns its token and the index of the closing ']'
+func parseBracket(path string, open int) (pathToken, int, error) {
+	if open+1 < len(path) && (path[open+1] == '"' || path[open+1] == '\'') {
+		quote := path[open+1]
+		var key strings.Builder
+		for i := open + 2; i < len(path); i++ {
+			switch path[i] {
+			case '\\':
+				if i+1 < len(path) {
+					i++
+					key.WriteByte(path[i])
+				}
+			case quote:
+				if i+1 >= len(path) || path[i+1] != ']' {
+					return pathToken{}, 0, fmt.Errorf("expected ']' after quoted key in '%s'", path)
+				}
+				return pathToken{key: key.String(), literal: true}, i + 1, nil
+			default:
+				key.WriteByte(path[i])
+			}
+		}
+		return pathToken{}, 0, fmt.Errorf("unterminated quoted key in '%s'", path)
+	}
+
+	closing := strings.IndexByte(path[open:], ']')
+	if closing < 0 {
+		return pathToken{}, 0, fmt.Errorf("unclosed bracket in '%s'", path)
+	}
+	indexStr := path[open+1 : open+closing]
+	if indexStr == "*" {
+		return pathToken{index: WildcardIndex, isIndex: true}, open + closing, nil
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil || index < 0 {
+		return pathToken{}, 0, fmt.Errorf("invalid array index '%s'", indexStr)
+	}
+	return pathToken{index: index, isIndex: true}, open + closing, nil
+}
+
 // ParsePath is a convenience function to parse a path
 func ParsePath(path string) (*PathInfo, error) {
 	normalizer := NewPathNormalizer()
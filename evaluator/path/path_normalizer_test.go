@@ -1,6 +1,7 @@
 package path
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -303,3 +304,63 @@ func TestParsePath_Convenience(t *testing.T) {
 		t.Errorf("Expected raw path to be preserved")
 	}
 }
+
+func TestPathNormalizer_EscapedKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expected     []string
+		arrayIndices map[int]int
+	}{
+		{"Backslash-escaped dot", `user.kubernetes\.io/role`, []string{"user", "kubernetes.io/role"}, map[int]int{}},
+		{"Double-quoted bracket key", `user.attributes["kubernetes.io/role"]`, []string{"user", "attributes", "kubernetes.io/role"}, map[int]int{}},
+		{"Single-quoted bracket key", `user['app.kubernetes.io/name']`, []string{"user", "app.kubernetes.io/name"}, map[int]int{}},
+		{"Escaped quote inside key", `user["say \"hi\""]`, []string{"user", `say "hi"`}, map[int]int{}},
+		{"Escaped backslash", `user.a\\b`, []string{"user", `a\b`}, map[int]int{}},
+		{"Quoted key with array index", `user["kubernetes.io/groups"][1].name`, []string{"user", "kubernetes.io/groups", "name"}, map[int]int{1: 1}},
+		{"Quoted numeric key is not an index", `user.scores["0"]`, []string{"user", "scores", "0"}, map[int]int{}},
+		{"Quoted key with projection", `user["kubernetes.io/groups"][*]`, []string{"user", "kubernetes.io/groups"}, map[int]int{1: WildcardIndex}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := ParsePath(test.path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(info.Parts, test.expected) {
+				t.Errorf("Expected parts %q, got %q", test.expected, info.Parts)
+			}
+			if !reflect.DeepEqual(info.ArrayIndices, test.arrayIndices) {
+				t.Errorf("Expected array indices %v, got %v", test.arrayIndices, info.ArrayIndices)
+			}
+		})
+	}
+
+	for _, invalid := range []string{
+		`user["unterminated`,
+		`user["key"x]`,
+		`user["key"]x`,
+		`user.key\`,
+		`user[abc]`,
+		`["key"][-1]`,
+	} {
+		if _, err := ParsePath(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestPathNormalizer_Wildcard(t *testing.T) {
+	info, err := ParsePath("user.roles[*].name")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(info.Parts, []string{"user", "roles", "name"}) || info.ArrayIndices[1] != WildcardIndex || !info.HasArrayAccess {
+		t.Errorf("Unexpected path info: %+v", info)
+	}
+
+	if _, err := ParsePath("user.roles[**]"); err == nil {
+		t.Error("Expected [**] to be rejected")
+	}
+}
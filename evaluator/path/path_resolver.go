@@ -37,15 +37,17 @@ func NewCompositePathResolver() *CompositePathResolver {
 
 // NewCompositePathResolverWithShortcuts creates a new composite resolver with custom shortcut configs
 func NewCompositePathResolverWithShortcuts(shortcuts []ShortcutConfig) *CompositePathResolver {
-	return &CompositePathResolver{
-		resolvers: []PathResolver{
-			&DirectPathResolver{},
-			NewArrayAccessResolver(), // High priority for array access
-			&DotNotationResolver{},
-			&ColonFallbackResolver{},
-			NewShortcutResolver(shortcuts),
-		},
+	cpr := &CompositePathResolver{}
+	cpr.resolvers = []PathResolver{
+		&DirectPathResolver{},
+		NewTransformResolver(cpr), // Transform keys such as lower(user.email) resolve their path with this resolver
+		NewArrayAccessResolver(),  // High priority for array access
+		&DotNotationResolver{},
+		&ColonFallbackResolver{},
+		&StructuredNotationResolver{},
+		NewShortcutResolver(shortcuts),
 	}
+	return cpr
 }
 
 // Resolve tries each resolver in order until one succeeds
@@ -80,7 +82,10 @@ func (dnr *DotNotationResolver) Resolve(path string, context map[string]interfac
 		return nil, false
 	}
 
-	parts := strings.Split(path, ".")
+	parts, err := SplitPath(path)
+	if err != nil {
+		return nil, false
+	}
 	return navigateNestedMap(parts, context)
 }
 
@@ -96,8 +101,51 @@ func (cfr *ColonFallbackResolver) Resolve(path string, context map[string]interf
 
 	// Convert first dot to colon: "user.department" -> "user:department"
 	flatPath := strings.Replace(path, ".", ":", 1)
-	value, exists := context[flatPath]
-	return value, exists
+	if HasEscapes(path) {
+		// "user.kubernetes\.io/role" -> "user:kubernetes.io/role"
+		parts, err := SplitPath(path)
+		if err != nil || len(parts) < 2 {
+			return nil, false
+		}
+		flatPath = parts[0] + ":" + strings.Join(parts[1:], ".")
+	}
+	if value, exists := context[flatPath]; exists {
+		return value, true
+	}
+
+	// Navigate into a structured flat attribute: "environment.client_cert.subject_dn" ->
+	// "environment:client_cert" → {"subject_dn": ...}
+	parts, err := SplitPath(path)
+	if err != nil || len(parts) < 3 {
+		return nil, false
+	}
+	nested, ok := context[parts[0]+":"+parts[1]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return navigateNestedMap(parts[2:], nested)
+}
+
+// StructuredNotationResolver resolves colon notation on the structured namespace maps:
+// "user:department" -> "user" → {"department": ...} and "user:attributes.level" ->
+// "user" → {"attributes": {"level": ...}}. Only NotationNamespaces are resolved this way.
+type StructuredNotationResolver struct{}
+
+func (snr *StructuredNotationResolver) Resolve(path string, context map[string]interface{}) (interface{}, bool) {
+	namespace, name, found := strings.Cut(path, ":")
+	if !found || name == "" || !isNotationNamespace(namespace) {
+		return nil, false
+	}
+	structured, ok := context[namespace].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	parts, err := SplitPath(name)
+	if err != nil {
+		return nil, false
+	}
+	return navigateNestedMap(parts, structured)
 }
 
 // ShortcutResolver handles configurable shortcuts for common patterns
@@ -123,8 +171,8 @@ func (sr *ShortcutResolver) Resolve(path string, context map[string]interface{})
 		return nil, false
 	}
 
-	parts := strings.Split(path, ".")
-	if len(parts) < 2 {
+	parts, err := SplitPath(path)
+	if err != nil || len(parts) < 2 {
 		return nil, false
 	}
 
@@ -169,7 +217,8 @@ func (sr *ShortcutResolver) Resolve(path string, context map[string]interface{})
 }
 
 // ArrayAccessResolver handles array index access in paths
-// Supports: user.roles[0], user.roles.0
+// Supports: user.roles[0], user.roles.0, user.attributes["kubernetes.io/groups"][0],
+// and projections: user.roles[*].name returns the name of every role as a []interface{}
 type ArrayAccessResolver struct {
 	normalizer *PathNormalizer
 }
@@ -213,72 +262,85 @@ func (aar *ArrayAccessResolver) hasNumericPart(path string) bool {
 	return false
 }
 
-// navigateWithArrayAccess navigates through nested maps and arrays
+// navigateWithArrayAccess navigates through nested maps and arrays.
+// A WildcardIndex projects the rest of the path over every element (see projectArray).
 func navigateWithArrayAccess(parts []string, arrayIndices map[int]int, startMap map[string]interface{}) (interface{}, bool) {
-	var current interface{} = startMap
-
-	for i, part := range parts {
-		// Check if we need to access an array at this position
-		if arrayIndex, hasArrayAccess := arrayIndices[i]; hasArrayAccess {
-			// First, navigate to the field (which should be an array)
-			currentMap, ok := current.(map[string]interface{})
-			if !ok {
-				return nil, false
-			}
-
-			arrayField, exists := currentMap[part]
-			if !exists {
-				return nil, false
-			}
-
-			// Now access the array
-			currentArray, ok := arrayField.([]interface{})
-			if !ok {
-				return nil, false
-			}
-
-			// Check bounds
-			if arrayIndex < 0 || arrayIndex >= len(currentArray) {
-				return nil, false
-			}
+	value, found, _ := navigateParts(parts, arrayIndices, 0, startMap)
+	return value, found
+}
 
-			// Access array element
-			current = currentArray[arrayIndex]
+// navigateParts resolves parts[from:] starting at current; projected reports that the value
+// is the slice produced by a [*] projection
+func navigateParts(parts []string, arrayIndices map[int]int, from int, current interface{}) (interface{}, bool, bool) {
+	for i := from; i < len(parts); i++ {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, false
+		}
 
-			// If this is the last part, return the value
-			if i == len(parts)-1 {
-				return current, true
-			}
+		next, exists := currentMap[parts[i]]
+		if !exists {
+			return nil, false, false
+		}
 
-			// Otherwise, current should be a map for next iteration
-			// (continue will handle the type check in next iteration)
+		arrayIndex, hasArrayAccess := arrayIndices[i]
+		if !hasArrayAccess {
+			current = next
 			continue
 		}
 
-		// Regular map navigation
-		currentMap, ok := current.(map[string]interface{})
+		// The field must be an array
+		currentArray, ok := toArray(next)
 		if !ok {
-			return nil, false
+			return nil, false, false
 		}
 
-		// Last part - return the value
-		if i == len(parts)-1 {
-			if value, exists := currentMap[part]; exists {
-				return value, true
-			}
-			return nil, false
+		if arrayIndex == WildcardIndex {
+			return projectArray(parts, arrayIndices, i+1, currentArray), true, true
 		}
 
-		// Navigate deeper
-		nextLevel, exists := currentMap[part]
-		if !exists {
-			return nil, false
+		// Check bounds
+		if arrayIndex < 0 || arrayIndex >= len(currentArray) {
+			return nil, false, false
 		}
+		current = currentArray[arrayIndex]
+	}
+
+	return current, true, false
+}
 
-		current = nextLevel
+// projectArray resolves parts[from:] on every element and collects the values that were found.
+// Nested projections are flattened, so teams[*].members[*].name is a flat list of names.
+func projectArray(parts []string, arrayIndices map[int]int, from int, elements []interface{}) []interface{} {
+	projected := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		value, found, nested := navigateParts(parts, arrayIndices, from, element)
+		if !found {
+			continue
+		}
+		if nested {
+			projected = append(projected, value.([]interface{})...)
+		} else {
+			projected = append(projected, value)
+		}
 	}
+	return projected
+}
 
-	return nil, false
+// toArray returns the elements of an array value decoded from JSON or built in Go
+func toArray(value interface{}) ([]interface{}, bool) {
+	switch array := value.(type) {
+	case []interface{}:
+		return array, true
+	case []map[string]interface{}:
+		elements := make([]interface{}, len(array))
+		for i, element := range array {
+			elements[i] = element
+		}
+		return elements, true
+	default:
+		return nil, false
+	}
 }
 
 // navigateNestedMap is a unified function to navigate through nested maps
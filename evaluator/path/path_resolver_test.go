@@ -1,6 +1,7 @@
 package path
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -204,6 +205,15 @@ func TestColonFallbackResolver(t *testing.T) {
 			expected: "Engineering",
 			found:    true,
 		},
+		{
+			name: "Multiple dots - navigate into structured flat attribute",
+			path: "environment.client_cert.common_name",
+			context: map[string]interface{}{
+				"environment:client_cert": map[string]interface{}{"common_name": "svc"},
+			},
+			expected: "svc",
+			found:    true,
+		},
 	}
 
 	for _, test := range tests {
@@ -219,6 +229,34 @@ func TestColonFallbackResolver(t *testing.T) {
 	}
 }
 
+func TestStructuredNotationResolver(t *testing.T) {
+	resolver := &StructuredNotationResolver{}
+	context := map[string]interface{}{
+		"user":    map[string]interface{}{"department": "Engineering", "attributes": map[string]interface{}{"level": 5}},
+		"session": "not a map",
+		"custom":  map[string]interface{}{"key": "value"},
+	}
+
+	tests := []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{"user:department", "Engineering", true},
+		{"user:attributes.level", 5, true},
+		{"user:missing", nil, false},
+		{"session:mfa_verified", nil, false}, // Namespace value is not a map
+		{"custom:key", nil, false},           // Not a notation namespace
+		{"user.department", nil, false},      // Dot notation is left to the other resolvers
+	}
+	for _, test := range tests {
+		value, found := resolver.Resolve(test.path, context)
+		if found != test.found || value != test.expected {
+			t.Errorf("Resolve(%q) = %v, %v; want %v, %v", test.path, value, found, test.expected, test.found)
+		}
+	}
+}
+
 func TestShortcutResolver(t *testing.T) {
 	// Use default shortcuts config
 	resolver := NewShortcutResolver(DefaultShortcuts())
@@ -797,3 +835,92 @@ func TestCompositePathResolver_WithArrayAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositePathResolver_EscapedKeys(t *testing.T) {
+	resolver := NewCompositePathResolver()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"kubernetes.io/role":   "node-admin",
+				"kubernetes.io/groups": []interface{}{"system:masters", "developers"},
+			},
+		},
+		"resource:app.kubernetes.io/name": "billing",
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{"Quoted key", `user.attributes["kubernetes.io/role"]`, "node-admin", true},
+		{"Escaped key", `user.attributes.kubernetes\.io/role`, "node-admin", true},
+		{"Escaped key via shortcut", `user.kubernetes\.io/role`, "node-admin", true},
+		{"Quoted key with array index", `user.attributes["kubernetes.io/groups"][1]`, "developers", true},
+		{"Escaped key via colon fallback", `resource.app\.kubernetes\.io/name`, "billing", true},
+		{"Unescaped dots still split", "user.attributes.kubernetes.io/role", nil, false},
+		{"Malformed path", `user.attributes["kubernetes.io/role`, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, found := resolver.Resolve(test.path, context)
+			if found != test.found {
+				t.Errorf("Expected found=%v, got found=%v", test.found, found)
+			}
+			if value != test.expected {
+				t.Errorf("Expected value=%v, got value=%v", test.expected, value)
+			}
+		})
+	}
+}
+
+func TestArrayAccessResolver_Projection(t *testing.T) {
+	resolver := NewCompositePathResolver()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"roles": []interface{}{
+				map[string]interface{}{"name": "admin", "scopes": []interface{}{"read", "write"}},
+				map[string]interface{}{"name": "auditor", "scopes": []interface{}{"read"}},
+				map[string]interface{}{"scopes": []interface{}{}}, // no name
+			},
+			"teams": []map[string]interface{}{
+				{"members": []interface{}{map[string]interface{}{"id": "u1"}, map[string]interface{}{"id": "u2"}}},
+				{"members": []interface{}{map[string]interface{}{"id": "u3"}}},
+			},
+			"department": "Engineering",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected []interface{}
+		found    bool
+	}{
+		{"Project a field", "user.roles[*].name", []interface{}{"admin", "auditor"}, true},
+		{"Index inside a projection", "user.roles[*].scopes[0]", []interface{}{"read", "read"}, true},
+		{"Nested projections are flattened", "user.roles[*].scopes[*]", []interface{}{"read", "write", "read"}, true},
+		{"Typed slice of maps", "user.teams[*].members[*].id", []interface{}{"u1", "u2", "u3"}, true},
+		{"Quoted key projection", `user["roles"][*].name`, []interface{}{"admin", "auditor"}, true},
+		{"Field missing everywhere", "user.roles[*].missing", []interface{}{}, true},
+		{"Not an array", "user.department[*].name", nil, false},
+		{"Missing array", "user.groups[*].name", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, found := resolver.Resolve(test.path, context)
+			if found != test.found {
+				t.Fatalf("Expected found=%v, got found=%v", test.found, found)
+			}
+			if !found {
+				return
+			}
+			if !reflect.DeepEqual(value, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"abac_go_example/constants"
+)
+
+// transformFunction is a whitelisted function of condition keys. Its first argument is an
+// attribute path (or another transform); params are the literal arguments that follow it.
+type transformFunction struct {
+	minParams, maxParams int
+	apply                func(value interface{}, params []interface{}) (interface{}, bool)
+}
+
+// transformFunctions is the whitelist of functions allowed in condition keys
+var transformFunctions = map[string]transformFunction{
+	"lower":     {apply: stringTransform(strings.ToLower)},
+	"upper":     {apply: stringTransform(strings.ToUpper)},
+	"trim":      {apply: stringTransform(strings.TrimSpace)},
+	"len":       {apply: lengthOf},
+	"substring": {minParams: 1, maxParams: 2, apply: substring},
+	"split":     {minParams: 1, maxParams: 1, apply: split},
+}
+
+// TransformFunctions lists the function names allowed in condition keys
+func TransformFunctions() []string {
+	names := make([]string, 0, len(transformFunctions))
+	for name := range transformFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Transform is a parsed condition key of the form fn(path, literal...), e.g. "lower(user.email)"
+type Transform struct {
+	Function string
+	Path     string        // Attribute path or nested transform the function is applied to
+	Params   []interface{} // Literal arguments: int64 or string
+}
+
+// ParseTransform parses key as a transform. It returns nil without error when key is a plain
+// attribute path, and an error when key calls an unknown function or has malformed arguments.
+func ParseTransform(key string) (*Transform, error) {
+	open := strings.IndexByte(key, '(')
+	if open <= 0 || !strings.HasSuffix(key, ")") || !isIdentifier(key[:open]) {
+		return nil, nil
+	}
+	name := key[:open]
+	function, ok := transformFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q in %q (allowed: %s)", name, key, strings.Join(TransformFunctions(), ", "))
+	}
+
+	args, err := splitArguments(key[open+1 : len(key)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments in %q: %w", key, err)
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, fmt.Errorf("%s in %q requires an attribute path", name, key)
+	}
+	params := args[1:]
+	if len(params) < function.minParams || len(params) > function.maxParams {
+		return nil, fmt.Errorf("%s in %q takes %s", name, key, arity(function))
+	}
+
+	transform := &Transform{Function: name, Path: args[0], Params: make([]interface{}, 0, len(params))}
+	if _, err := ParseTransform(transform.Path); err != nil {
+		return nil, err
+	}
+	for _, param := range params {
+		literal, err := parseLiteral(param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q in %q: %w", param, key, err)
+		}
+		transform.Params = append(transform.Params, literal)
+	}
+	return transform, nil
+}
+
+// TransformResolver resolves transform keys by resolving their path with the inner resolver
+// and applying the function. Values the function does not accept resolve to nothing.
+type TransformResolver struct {
+	inner  PathResolver
+	parsed sync.Map // key -> *Transform, or nil for keys that are not valid transforms
+}
+
+// NewTransformResolver creates a transform resolver resolving paths with inner
+func NewTransformResolver(inner PathResolver) *TransformResolver {
+	return &TransformResolver{inner: inner}
+}
+
+func (tr *TransformResolver) Resolve(path string, context map[string]interface{}) (interface{}, bool) {
+	if !strings.HasSuffix(path, ")") {
+		return nil, false
+	}
+	transform := tr.parse(path)
+	if transform == nil {
+		return nil, false
+	}
+	value, found := tr.inner.Resolve(transform.Path, context)
+	if !found || value == nil {
+		return nil, false
+	}
+	return transformFunctions[transform.Function].apply(value, transform.Params)
+}
+
+// parse caches parsed keys; condition keys come from a bounded set of policies
+func (tr *TransformResolver) parse(key string) *Transform {
+	if cached, ok := tr.parsed.Load(key); ok {
+		transform, _ := cached.(*Transform)
+		return transform
+	}
+	transform, err := ParseTransform(key)
+	if err != nil {
+		transform = nil
+	}
+	tr.parsed.Store(key, transform)
+	return transform
+}
+
+// InvalidTransform is a condition key calling an unknown function or with malformed arguments
+type InvalidTransform struct {
+	Operator string `json:"operator"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+}
+
+// InvalidTransformKeys lists the invalid transform keys of a statement Condition map, including
+// keys nested in And/Or/Not. ResourceTag and quota operators are skipped like in DeprecatedConditionKeys.
+func InvalidTransformKeys(conditions map[string]interface{}) []InvalidTransform {
+	var found []InvalidTransform
+	collectInvalidTransforms(conditions, &found)
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Key != found[j].Key {
+			return found[i].Key < found[j].Key
+		}
+		return found[i].Operator < found[j].Operator
+	})
+	return found
+}
+
+func collectInvalidTransforms(conditions map[string]interface{}, found *[]InvalidTransform) {
+	for operator, operands := range conditions {
+		switch constants.OperatorKey(operator) {
+		case constants.OpAnd, constants.OpOr:
+			items, _ := operands.([]interface{})
+			for _, item := range items {
+				if nested, ok := item.(map[string]interface{}); ok {
+					collectInvalidTransforms(nested, found)
+				}
+			}
+			continue
+		case constants.OpNot:
+			if nested, ok := operands.(map[string]interface{}); ok {
+				collectInvalidTransforms(nested, found)
+			}
+			continue
+		case constants.OpResourceTag, constants.OpDailyQuotaBelow, constants.OpRequestRateBelow:
+			continue
+		}
+
+		block, ok := operands.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range block {
+			if _, err := ParseTransform(key); err != nil {
+				*found = append(*found, InvalidTransform{Operator: operator, Key: key, Message: err.Error()})
+			}
+		}
+	}
+}
+
+// splitArguments splits a comma-separated argument list, keeping commas inside quotes and parentheses
+func splitArguments(list string) ([]string, error) {
+	var args []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case r == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	if quote != 0 || depth != 0 {
+		return nil, fmt.Errorf("unterminated quote or parenthesis")
+	}
+	return append(args, strings.TrimSpace(list[start:])), nil
+}
+
+// parseLiteral parses an integer or a single/double quoted string
+func parseLiteral(literal string) (interface{}, error) {
+	if len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0] {
+		return literal[1 : len(literal)-1], nil
+	}
+	n, err := strconv.ParseInt(literal, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected an integer or quoted string")
+	}
+	return n, nil
+}
+
+func isIdentifier(name string) bool {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func arity(function transformFunction) string {
+	if function.maxParams == 0 {
+		return "only an attribute path"
+	}
+	if function.minParams == function.maxParams {
+		return fmt.Sprintf("an attribute path and %d argument(s)", function.minParams)
+	}
+	return fmt.Sprintf("an attribute path and %d to %d arguments", function.minParams, function.maxParams)
+}
+
+func stringTransform(fn func(string) string) func(interface{}, []interface{}) (interface{}, bool) {
+	return func(value interface{}, _ []interface{}) (interface{}, bool) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		return fn(s), true
+	}
+}
+
+// lengthOf counts the characters of a string or the elements of a slice, array or map
+func lengthOf(value interface{}, _ []interface{}) (interface{}, bool) {
+	if s, ok := value.(string); ok {
+		return len([]rune(s)), true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return reflect.ValueOf(value).Len(), true
+	}
+	return nil, false
+}
+
+// substring returns the characters [start, end) of a string; bounds are clamped to the string
+func substring(value interface{}, params []interface{}) (interface{}, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	runes := []rune(s)
+	start, ok := params[0].(int64)
+	if !ok {
+		return nil, false
+	}
+	end := int64(len(runes))
+	if len(params) > 1 {
+		if end, ok = params[1].(int64); !ok {
+			return nil, false
+		}
+	}
+	start, end = clamp(start, int64(len(runes))), clamp(end, int64(len(runes)))
+	if start >= end {
+		return "", true
+	}
+	return string(runes[start:end]), true
+}
+
+// split splits a string by a separator into a list usable with array operators
+func split(value interface{}, params []interface{}) (interface{}, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	separator, ok := params[0].(string)
+	if !ok {
+		return nil, false
+	}
+	parts := strings.Split(s, separator)
+	result := make([]interface{}, len(parts))
+	for i, part := range parts {
+		result[i] = part
+	}
+	return result, true
+}
+
+func clamp(n, max int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
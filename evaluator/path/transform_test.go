@@ -0,0 +1,100 @@
+package path
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransformResolver(t *testing.T) {
+	resolver := NewCompositePathResolver()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"email": "  John.Doe@Company.COM ",
+				"roles": []interface{}{"developer", "reviewer"},
+			},
+		},
+		"resource": map[string]interface{}{
+			"path": "/documents/project-alpha/specs.pdf",
+			"name": "Tài liệu",
+		},
+		"user:level": 5,
+	}
+
+	tests := []struct {
+		key      string
+		expected interface{}
+	}{
+		{"lower(user.email)", "  john.doe@company.com "},
+		{"upper(user.attributes.email)", "  JOHN.DOE@COMPANY.COM "},
+		{"lower(trim(user.email))", "john.doe@company.com"},
+		{"len(user.roles)", 2},
+		{"len(resource.name)", 8},
+		{"substring(resource.path, 0, 10)", "/documents"},
+		{"substring(resource.path, 11)", "project-alpha/specs.pdf"},
+		{"substring(resource.path, 20, 500)", "lpha/specs.pdf"},
+		{"substring(resource.path, 10, 2)", ""},
+		{"split(resource.path, '/')", []interface{}{"", "documents", "project-alpha", "specs.pdf"}},
+		{"len(split(resource.path, \"/\"))", 4},
+	}
+	for _, tt := range tests {
+		value, found := resolver.Resolve(tt.key, context)
+		if !found || !reflect.DeepEqual(value, tt.expected) {
+			t.Errorf("%s: expected %#v, got %#v (found=%v)", tt.key, tt.expected, value, found)
+		}
+	}
+
+	// Missing attributes, unsupported types and unknown functions resolve to nothing
+	for _, key := range []string{"lower(user.phone)", "lower(user:level)", "len(user:level)", "reverse(user.email)", "lower(user.email"} {
+		if value, found := resolver.Resolve(key, context); found {
+			t.Errorf("%s: expected no value, got %#v", key, value)
+		}
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	transform, err := ParseTransform("substring(resource.path, 0, 'a,b')")
+	if err != nil {
+		t.Fatalf("ParseTransform failed: %v", err)
+	}
+	if transform.Function != "substring" || transform.Path != "resource.path" || !reflect.DeepEqual(transform.Params, []interface{}{int64(0), "a,b"}) {
+		t.Errorf("Unexpected transform %+v", transform)
+	}
+
+	for _, key := range []string{"user.email", "user:department", "environment:client_cert.subject_dn", "(user.email)"} {
+		if transform, err := ParseTransform(key); transform != nil || err != nil {
+			t.Errorf("%s: expected a plain path, got %+v %v", key, transform, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"reverse(user.email)":         "unknown function",
+		"lower(user.email, 1)":        "only an attribute path",
+		"substring(resource.path)":    "1 to 2 arguments",
+		"split(resource.path, x)":     "invalid argument",
+		"len()":                       "requires an attribute path",
+		"lower(upper(user.email, 1))": "only an attribute path",
+		"lower('user.email)":          "unterminated",
+	}
+	for key, message := range invalid {
+		if _, err := ParseTransform(key); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("%s: expected error containing %q, got %v", key, message, err)
+		}
+	}
+}
+
+func TestInvalidTransformKeys(t *testing.T) {
+	conditions := map[string]interface{}{
+		"StringEquals": map[string]interface{}{"lower(user.email)": "john@company.com", "reverse(user.name)": "nhoj"},
+		"Or": []interface{}{
+			map[string]interface{}{"NumericLessThan": map[string]interface{}{"length(user.roles)": 3}},
+		},
+		"ResourceTag": map[string]interface{}{"team(x)": "platform"},
+	}
+
+	invalid := InvalidTransformKeys(conditions)
+	if len(invalid) != 2 || invalid[0].Key != "length(user.roles)" || invalid[1].Key != "reverse(user.name)" || invalid[1].Operator != "StringEquals" {
+		t.Errorf("Unexpected invalid keys %+v", invalid)
+	}
+}
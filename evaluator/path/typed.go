@@ -0,0 +1,190 @@
+package path
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned by the typed Resolve helpers when the path does not resolve
+var ErrPathNotFound = errors.New("attribute path not found")
+
+// TypeError is returned by the typed Resolve helpers when the value has another type
+type TypeError struct {
+	Path     string
+	Expected string
+	Value    interface{}
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("attribute %s: expected %s, got %T", e.Path, e.Expected, e.Value)
+}
+
+// ResolveString resolves path to a string. Only string values are accepted.
+func ResolveString(resolver PathResolver, path string, context map[string]interface{}) (string, error) {
+	value, err := resolveValue(resolver, path, context)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return "", &TypeError{Path: path, Expected: "string", Value: value}
+}
+
+// ResolveInt resolves path to an integer. Integer kinds, integral floats (JSON numbers)
+// and integer strings are accepted.
+func ResolveInt(resolver PathResolver, path string, context map[string]interface{}) (int64, error) {
+	value, err := resolveValue(resolver, path, context)
+	if err != nil {
+		return 0, err
+	}
+	if s, ok := value.(string); ok {
+		if i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+			return i, nil
+		}
+		return 0, &TypeError{Path: path, Expected: "integer", Value: value}
+	}
+	f, ok := toNumber(value)
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, &TypeError{Path: path, Expected: "integer", Value: value}
+	}
+	return int64(f), nil
+}
+
+// ResolveFloat resolves path to a number. Numeric kinds and numeric strings are accepted.
+func ResolveFloat(resolver PathResolver, path string, context map[string]interface{}) (float64, error) {
+	value, err := resolveValue(resolver, path, context)
+	if err != nil {
+		return 0, err
+	}
+	if s, ok := value.(string); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && !math.IsNaN(f) {
+			return f, nil
+		}
+		return 0, &TypeError{Path: path, Expected: "number", Value: value}
+	}
+	if f, ok := toNumber(value); ok {
+		return f, nil
+	}
+	return 0, &TypeError{Path: path, Expected: "number", Value: value}
+}
+
+// ResolveBool resolves path to a boolean. Booleans and the strings "true"/"false" are accepted.
+func ResolveBool(resolver PathResolver, path string, context map[string]interface{}) (bool, error) {
+	value, err := resolveValue(resolver, path, context)
+	if err != nil {
+		return false, err
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, &TypeError{Path: path, Expected: "boolean", Value: value}
+}
+
+// ResolveStringSlice resolves path to a list of strings. Any slice whose elements are all
+// strings is accepted (e.g. []string, []interface{} decoded from JSON, models.JSONStringSlice).
+func ResolveStringSlice(resolver PathResolver, path string, context map[string]interface{}) ([]string, error) {
+	value, err := resolveValue(resolver, path, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if values, ok := value.([]string); ok {
+		return values, nil
+	}
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice {
+		return nil, &TypeError{Path: path, Expected: "list of strings", Value: value}
+	}
+	result := make([]string, list.Len())
+	for i := range result {
+		s, ok := list.Index(i).Interface().(string)
+		if !ok {
+			return nil, &TypeError{Path: path, Expected: "list of strings", Value: value}
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+// ResolveString resolves path to a string (see the package-level ResolveString)
+func (cpr *CompositePathResolver) ResolveString(path string, context map[string]interface{}) (string, error) {
+	return ResolveString(cpr, path, context)
+}
+
+// ResolveInt resolves path to an integer (see the package-level ResolveInt)
+func (cpr *CompositePathResolver) ResolveInt(path string, context map[string]interface{}) (int64, error) {
+	return ResolveInt(cpr, path, context)
+}
+
+// ResolveFloat resolves path to a number (see the package-level ResolveFloat)
+func (cpr *CompositePathResolver) ResolveFloat(path string, context map[string]interface{}) (float64, error) {
+	return ResolveFloat(cpr, path, context)
+}
+
+// ResolveBool resolves path to a boolean (see the package-level ResolveBool)
+func (cpr *CompositePathResolver) ResolveBool(path string, context map[string]interface{}) (bool, error) {
+	return ResolveBool(cpr, path, context)
+}
+
+// ResolveStringSlice resolves path to a list of strings (see the package-level ResolveStringSlice)
+func (cpr *CompositePathResolver) ResolveStringSlice(path string, context map[string]interface{}) ([]string, error) {
+	return ResolveStringSlice(cpr, path, context)
+}
+
+// resolveValue resolves path, reporting missing paths and nil values as ErrPathNotFound
+func resolveValue(resolver PathResolver, path string, context map[string]interface{}) (interface{}, error) {
+	value, found := resolver.Resolve(path, context)
+	if !found || value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+	return value, nil
+}
+
+// toNumber converts numeric kinds to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, !math.IsNaN(v)
+	case float32:
+		return float64(v), !math.IsNaN(float64(v))
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
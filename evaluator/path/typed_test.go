@@ -0,0 +1,81 @@
+package path
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypedResolve(t *testing.T) {
+	resolver := NewCompositePathResolver()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":      "alice",
+			"level":     float64(5), // JSON number
+			"ratio":     0.75,
+			"count":     json.Number("12"),
+			"clearance": "3",
+			"active":    true,
+			"mfa":       "false",
+			"roles":     []interface{}{"admin", "auditor"},
+			"groups":    []string{"eng"},
+			"mixed":     []interface{}{"a", 1},
+			"nothing":   nil,
+		},
+	}
+
+	if s, err := resolver.ResolveString("user.name", context); err != nil || s != "alice" {
+		t.Errorf("ResolveString = %q, %v", s, err)
+	}
+	if i, err := resolver.ResolveInt("user.level", context); err != nil || i != 5 {
+		t.Errorf("ResolveInt(level) = %d, %v", i, err)
+	}
+	if i, err := resolver.ResolveInt("user.count", context); err != nil || i != 12 {
+		t.Errorf("ResolveInt(count) = %d, %v", i, err)
+	}
+	if i, err := resolver.ResolveInt("user.clearance", context); err != nil || i != 3 {
+		t.Errorf("ResolveInt(clearance) = %d, %v", i, err)
+	}
+	if f, err := resolver.ResolveFloat("user.ratio", context); err != nil || f != 0.75 {
+		t.Errorf("ResolveFloat = %v, %v", f, err)
+	}
+	if b, err := resolver.ResolveBool("user.active", context); err != nil || !b {
+		t.Errorf("ResolveBool(active) = %v, %v", b, err)
+	}
+	if b, err := resolver.ResolveBool("user.mfa", context); err != nil || b {
+		t.Errorf("ResolveBool(mfa) = %v, %v", b, err)
+	}
+	if roles, err := resolver.ResolveStringSlice("user.roles", context); err != nil || !reflect.DeepEqual(roles, []string{"admin", "auditor"}) {
+		t.Errorf("ResolveStringSlice(roles) = %v, %v", roles, err)
+	}
+	if groups, err := resolver.ResolveStringSlice("user.groups", context); err != nil || !reflect.DeepEqual(groups, []string{"eng"}) {
+		t.Errorf("ResolveStringSlice(groups) = %v, %v", groups, err)
+	}
+
+	var typeErr *TypeError
+	for name, err := range map[string]error{
+		"string from number":    second(resolver.ResolveString("user.level", context)),
+		"int from fraction":     second(resolver.ResolveInt("user.ratio", context)),
+		"int from word":         second(resolver.ResolveInt("user.name", context)),
+		"float from word":       second(resolver.ResolveFloat("user.name", context)),
+		"bool from number":      second(resolver.ResolveBool("user.level", context)),
+		"slice with non-string": second(resolver.ResolveStringSlice("user.mixed", context)),
+		"slice from string":     second(resolver.ResolveStringSlice("user.name", context)),
+	} {
+		if !errors.As(err, &typeErr) {
+			t.Errorf("%s: expected *TypeError, got %v", name, err)
+		}
+	}
+
+	for _, missing := range []string{"user.missing", "user.nothing"} {
+		if _, err := resolver.ResolveString(missing, context); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("ResolveString(%s): expected ErrPathNotFound, got %v", missing, err)
+		}
+	}
+}
+
+// second returns the error of a typed Resolve call
+func second[T any](_ T, err error) error {
+	return err
+}
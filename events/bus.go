@@ -0,0 +1,135 @@
+// Package events carries change notifications of subjects, resources and policies from storage
+// to pluggable subscribers (cache invalidation, webhooks, audit), so storage does not depend on
+// the caches and sinks reacting to its writes.
+package events
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Changed entities
+const (
+	EntitySubject        = "subject"
+	EntityResource       = "resource"
+	EntityPolicy         = "policy"
+	EntityRevocation     = "revocation" // Event ID is the revocation ID, not the revoked subject
+	EntityElevation      = "elevation"  // Event ID is the elevation ID, not the elevated subject
+	EntityPolicySchedule = "policy_schedule"
+)
+
+// Change types
+const (
+	Created  = "created"
+	Updated  = "updated"
+	Deleted  = "deleted"
+	Restored = "restored"
+)
+
+// Event is one committed write of a subject, resource or policy
+type Event struct {
+	Type     string    `json:"type"`
+	Entity   string    `json:"entity"`
+	ID       string    `json:"id"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Subscriber reacts to events. It is called synchronously by Publish, so slow work (e.g. HTTP
+// calls) must be handed off to a goroutine.
+type Subscriber interface {
+	HandleEvent(event Event) error
+}
+
+// SubscriberFunc adapts a function to Subscriber
+type SubscriberFunc func(event Event) error
+
+// HandleEvent calls f
+func (f SubscriberFunc) HandleEvent(event Event) error {
+	return f(event)
+}
+
+// SubscriberStats counts the deliveries of one subscriber
+type SubscriberStats struct {
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`
+}
+
+type namedSubscriber struct {
+	name              string
+	subscriber        Subscriber
+	delivered, failed atomic.Int64
+}
+
+// Bus delivers published events to every subscriber in subscription order. A failing or
+// panicking subscriber is logged and counted; it never affects the write or other subscribers.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers []*namedSubscriber
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers subscriber under a unique name
+func (b *Bus) Subscribe(name string, subscriber Subscriber) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, existing := range b.subscribers {
+		if existing.name == name {
+			return fmt.Errorf("event subscriber %q already registered", name)
+		}
+	}
+	// Copy on write: Publish may be iterating over the current slice
+	subscribers := make([]*namedSubscriber, len(b.subscribers), len(b.subscribers)+1)
+	copy(subscribers, b.subscribers)
+	b.subscribers = append(subscribers, &namedSubscriber{name: name, subscriber: subscriber})
+	return nil
+}
+
+// Publish delivers event to all subscribers; Time defaults to now
+func (b *Bus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	// Delivered without holding the lock so subscribers may publish or subscribe themselves
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.mu.Unlock()
+	for _, s := range subscribers {
+		if err := deliver(s.subscriber, event); err != nil {
+			s.failed.Add(1)
+			log.Printf("Event subscriber %s failed on %s %s %s: %v", s.name, event.Entity, event.ID, event.Type, err)
+			continue
+		}
+		s.delivered.Add(1)
+	}
+}
+
+// Stats returns the delivery counters of each subscriber by name
+func (b *Bus) Stats() map[string]SubscriberStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make(map[string]SubscriberStats, len(b.subscribers))
+	for _, s := range b.subscribers {
+		stats[s.name] = SubscriberStats{Delivered: s.delivered.Load(), Failed: s.failed.Load()}
+	}
+	return stats
+}
+
+func deliver(subscriber Subscriber, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return subscriber.HandleEvent(event)
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBusDeliversInOrderAndIsolatesFailures(t *testing.T) {
+	bus := NewBus()
+	var received []string
+	bus.Subscribe("first", SubscriberFunc(func(event Event) error {
+		received = append(received, "first:"+event.ID)
+		return nil
+	}))
+	bus.Subscribe("failing", SubscriberFunc(func(Event) error { return errors.New("unavailable") }))
+	bus.Subscribe("panicking", SubscriberFunc(func(Event) error { panic("boom") }))
+	bus.Subscribe("last", SubscriberFunc(func(event Event) error {
+		received = append(received, "last:"+event.ID)
+		if event.Time.IsZero() {
+			t.Error("expected Publish to set the event time")
+		}
+		return nil
+	}))
+	if err := bus.Subscribe("first", SubscriberFunc(func(Event) error { return nil })); err == nil {
+		t.Error("expected duplicate subscriber names to be rejected")
+	}
+
+	bus.Publish(Event{Type: Created, Entity: EntitySubject, ID: "sub-001"})
+	bus.Publish(Event{Type: Deleted, Entity: EntityPolicy, ID: "pol-001"})
+
+	expected := []string{"first:sub-001", "last:sub-001", "first:pol-001", "last:pol-001"}
+	if len(received) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("delivery %d: expected %s, got %s", i, expected[i], received[i])
+		}
+	}
+
+	stats := bus.Stats()
+	if stats["first"].Delivered != 2 || stats["failing"].Failed != 2 || stats["panicking"].Failed != 2 || stats["last"].Delivered != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+type fakeCaches struct {
+	invalidated []string
+	purges      int
+	revocations int
+}
+
+func (f *fakeCaches) InvalidateAttributeCache(entityType, entityID string) {
+	f.invalidated = append(f.invalidated, entityType+":"+entityID)
+}
+
+func (f *fakeCaches) PurgeDenyCache() { f.purges++ }
+
+func (f *fakeCaches) InvalidateRevocations() { f.revocations++ }
+
+func TestCacheInvalidationSubscriber(t *testing.T) {
+	caches := &fakeCaches{}
+	subscriber := NewCacheInvalidationSubscriber(caches)
+	subscriber.HandleEvent(Event{Type: Updated, Entity: EntitySubject, ID: "sub-001"})
+	subscriber.HandleEvent(Event{Type: Deleted, Entity: EntityResource, ID: "res-001"})
+	subscriber.HandleEvent(Event{Type: Created, Entity: EntityPolicy, ID: "pol-001"})
+	subscriber.HandleEvent(Event{Type: Created, Entity: EntityRevocation, ID: "rev-001"})
+
+	if len(caches.invalidated) != 2 || caches.invalidated[0] != "subject:sub-001" || caches.invalidated[1] != "resource:res-001" {
+		t.Errorf("unexpected attribute cache invalidations %v", caches.invalidated)
+	}
+	if caches.purges != 4 {
+		t.Errorf("expected every change to purge the deny cache, got %d purges", caches.purges)
+	}
+	if caches.revocations != 1 {
+		t.Errorf("expected the revocation change to reload the revocation list, got %d reloads", caches.revocations)
+	}
+}
+
+func TestLogSubscriber(t *testing.T) {
+	var buf bytes.Buffer
+	subscriber := NewLogSubscriber(&buf)
+	subscriber.HandleEvent(Event{Type: Restored, Entity: EntityPolicy, ID: "pol-001", TenantID: "acme"})
+
+	var logged Event
+	if err := json.Unmarshal(buf.Bytes(), &logged); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %v", buf.String(), err)
+	}
+	if logged.Type != Restored || logged.ID != "pol-001" || logged.TenantID != "acme" {
+		t.Errorf("unexpected logged event %+v", logged)
+	}
+}
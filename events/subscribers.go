@@ -0,0 +1,80 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"abac_go_example/constants"
+)
+
+// CacheInvalidator is the part of the PDP whose caches depend on stored entities
+type CacheInvalidator interface {
+	InvalidateAttributeCache(entityType, entityID string)
+	PurgeDenyCache()
+	InvalidateRevocations()
+}
+
+// NewCacheInvalidationSubscriber keeps the PDP caches consistent with storage: a changed subject
+// or resource is dropped from the attribute cache, a revocation change reloads the revocation list,
+// and any change purges the deny cache since a cached deny may no longer hold.
+func NewCacheInvalidationSubscriber(cache CacheInvalidator) Subscriber {
+	return SubscriberFunc(func(event Event) error {
+		switch event.Entity {
+		case EntitySubject, EntityResource:
+			cache.InvalidateAttributeCache(event.Entity, event.ID)
+		case EntityRevocation:
+			cache.InvalidateRevocations()
+		}
+		cache.PurgeDenyCache()
+		return nil
+	})
+}
+
+// LogSubscriber writes every event as a JSON line, an append-only audit of entity changes
+type LogSubscriber struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewLogSubscriber writes events to w
+func NewLogSubscriber(w io.Writer) *LogSubscriber {
+	return &LogSubscriber{encoder: json.NewEncoder(w)}
+}
+
+// LogSubscriberFromEnv appends events to the file in ABAC_EVENT_LOG ("-" for stdout).
+// It returns nil when the variable is unset.
+func LogSubscriberFromEnv() (*LogSubscriber, error) {
+	path := os.Getenv(constants.EnvEventLog)
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		return NewLogSubscriber(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	subscriber := NewLogSubscriber(file)
+	subscriber.closer = file
+	return subscriber, nil
+}
+
+// HandleEvent writes the event
+func (s *LogSubscriber) HandleEvent(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(event)
+}
+
+// Close closes the event log file opened by LogSubscriberFromEnv
+func (s *LogSubscriber) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// SignatureHeader carries "sha256=<hex HMAC of the body>" when a webhook secret is configured
+const SignatureHeader = "X-ABAC-Signature"
+
+// WebhookConfig configures a WebhookSubscriber
+type WebhookConfig struct {
+	URL     string
+	Secret  string        // Optional HMAC-SHA256 key signing each body
+	Timeout time.Duration // Per request, default 5s
+	Buffer  int           // Queued events before new ones are dropped, default 256
+}
+
+// WebhookSubscriber POSTs each event as JSON to a URL. Events are queued and sent by a background
+// goroutine in order, so writes never wait for the receiver; events are dropped when the queue
+// is full and failed deliveries are logged, not retried.
+type WebhookSubscriber struct {
+	config  WebhookConfig
+	client  *http.Client
+	queue   chan Event
+	done    chan struct{}
+	once    sync.Once
+	sent    atomic.Int64
+	failed  atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewWebhookSubscriber starts the delivery goroutine; Close stops it
+func NewWebhookSubscriber(config WebhookConfig) *WebhookSubscriber {
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = 256
+	}
+	w := &WebhookSubscriber{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		queue:  make(chan Event, config.Buffer),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// WebhookSubscriberFromEnv builds a webhook from ABAC_EVENT_WEBHOOK_URL and ABAC_EVENT_WEBHOOK_SECRET.
+// It returns nil when no URL is configured.
+func WebhookSubscriberFromEnv() *WebhookSubscriber {
+	url := os.Getenv(constants.EnvEventWebhookURL)
+	if url == "" {
+		return nil
+	}
+	return NewWebhookSubscriber(WebhookConfig{URL: url, Secret: os.Getenv(constants.EnvEventWebhookSecret)})
+}
+
+// HandleEvent queues the event for delivery
+func (w *WebhookSubscriber) HandleEvent(event Event) error {
+	select {
+	case w.queue <- event:
+		return nil
+	default:
+		w.dropped.Add(1)
+		return fmt.Errorf("webhook queue full, event dropped")
+	}
+}
+
+// Close delivers the queued events and stops the delivery goroutine
+func (w *WebhookSubscriber) Close() error {
+	w.once.Do(func() { close(w.queue) })
+	<-w.done
+	return nil
+}
+
+// Stats returns the sent, failed and dropped event counts
+func (w *WebhookSubscriber) Stats() (sent, failed, dropped int64) {
+	return w.sent.Load(), w.failed.Load(), w.dropped.Load()
+}
+
+func (w *WebhookSubscriber) run() {
+	defer close(w.done)
+	for event := range w.queue {
+		if err := w.send(event); err != nil {
+			w.failed.Add(1)
+			log.Printf("Failed to deliver %s %s %s event to webhook: %v", event.Entity, event.ID, event.Type, err)
+			continue
+		}
+		w.sent.Add(1)
+	}
+}
+
+func (w *WebhookSubscriber) send(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.config.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.config.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value of body; receivers recompute it to verify deliveries
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
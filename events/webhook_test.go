@@ -0,0 +1,71 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestWebhookSubscriber(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(SignatureHeader); got != Sign("s3cret", body) {
+			t.Errorf("unexpected signature %q", got)
+		}
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("invalid body %q: %v", body, err)
+		}
+		if event.ID == "pol-fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	webhook := NewWebhookSubscriber(WebhookConfig{URL: server.URL, Secret: "s3cret"})
+	for _, id := range []string{"pol-001", "pol-fail", "pol-002"} {
+		if err := webhook.HandleEvent(Event{Type: Updated, Entity: EntityPolicy, ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	webhook.Close() // Delivers the queue before returning
+
+	if len(received) != 2 || received[0].ID != "pol-001" || received[1].ID != "pol-002" {
+		t.Errorf("expected pol-001 and pol-002 in order, got %+v", received)
+	}
+	if sent, failed, dropped := webhook.Stats(); sent != 2 || failed != 1 || dropped != 0 {
+		t.Errorf("expected 2 sent and 1 failed, got sent=%d failed=%d dropped=%d", sent, failed, dropped)
+	}
+}
+
+func TestWebhookSubscriberDropsWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	webhook := NewWebhookSubscriber(WebhookConfig{URL: server.URL, Buffer: 1})
+	errs := 0
+	for i := 0; i < 5; i++ {
+		if webhook.HandleEvent(Event{Type: Created, Entity: EntitySubject, ID: "sub"}) != nil {
+			errs++
+		}
+	}
+	close(release)
+	webhook.Close()
+
+	// One event is in flight and one queued at most, so at least 3 are dropped
+	if _, _, dropped := webhook.Stats(); dropped < 3 || dropped != int64(errs) {
+		t.Errorf("expected at least 3 dropped events reported as errors, got dropped=%d errors=%d", dropped, errs)
+	}
+}
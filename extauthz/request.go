@@ -0,0 +1,140 @@
+package extauthz
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/pep"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+)
+
+// Context extensions set per route in the Envoy ext_authz filter config
+// (typed_per_filter_config.envoy.filters.http.ext_authz.check_settings.context_extensions)
+const (
+	ExtensionAction    = "abac_action"    // Action evaluated instead of the method's default
+	ExtensionResource  = "abac_resource"  // Registered resource ID evaluated instead of the request path
+	ExtensionNamespace = "abac_namespace" // Policy namespace of the protected service
+)
+
+// httpRequest rebuilds the headers of the checked request so subjects are identified exactly
+// like requests to ABACMiddleware (X-User-ID, X-Subject-ID, Bearer token, service token, API key)
+func httpRequest(attrs *authv3.AttributeContext_HttpRequest) *http.Request {
+	header := make(http.Header, len(attrs.GetHeaders()))
+	for name, value := range attrs.GetHeaders() {
+		header.Set(name, value)
+	}
+	return &http.Request{
+		Method: attrs.GetMethod(),
+		URL:    &url.URL{Path: requestPath(attrs), RawQuery: attrs.GetQuery()},
+		Host:   attrs.GetHost(),
+		Header: header,
+	}
+}
+
+// requestPath returns the path of the checked request without its query string.
+// Envoy sends the path with the query; Query is only set by newer versions.
+func requestPath(attrs *authv3.AttributeContext_HttpRequest) string {
+	path, _, _ := strings.Cut(attrs.GetPath(), "?")
+	return path
+}
+
+// evaluationRequest maps the attributes of an Envoy check to a PDP request for subject
+func (s *Server) evaluationRequest(attrs *authv3.AttributeContext, subject models.SubjectInterface) *models.EvaluationRequest {
+	httpAttrs := attrs.GetRequest().GetHttp()
+	extensions := attrs.GetContextExtensions()
+
+	requestID := httpAttrs.GetId()
+	if requestID == "" {
+		requestID = fmt.Sprintf("extauthz_%d", time.Now().UnixNano())
+	}
+	action := extensions[ExtensionAction]
+	if action == "" {
+		action = s.config.MethodAction(httpAttrs.GetMethod())
+	}
+	resourceID := extensions[ExtensionResource]
+	if resourceID == "" {
+		resourceID = requestPath(httpAttrs)
+	}
+
+	timestamp := time.Now().UTC()
+	if requestTime := attrs.GetRequest().GetTime(); requestTime != nil {
+		timestamp = requestTime.AsTime().UTC()
+	}
+	clientIP := peerIP(attrs.GetSource())
+
+	context := map[string]interface{}{
+		"method":    httpAttrs.GetMethod(),
+		"path":      requestPath(httpAttrs),
+		"host":      httpAttrs.GetHost(),
+		"scheme":    httpAttrs.GetScheme(),
+		"protocol":  httpAttrs.GetProtocol(),
+		"timestamp": timestamp.Format(time.RFC3339),
+		"user_ip":   clientIP,
+	}
+	if query := httpAttrs.GetQuery(); query != "" {
+		context["query"] = query
+	} else if _, query, ok := strings.Cut(httpAttrs.GetPath(), "?"); ok {
+		context["query"] = query
+	}
+	if principal := attrs.GetSource().GetPrincipal(); principal != "" {
+		context["source_principal"] = principal // e.g. the SPIFFE ID of the calling workload
+	}
+	if service := attrs.GetDestination().GetService(); service != "" {
+		context["destination_service"] = service
+	}
+
+	environment := &models.EnvironmentInfo{
+		ClientIP:   clientIP,
+		UserAgent:  httpAttrs.GetHeaders()["user-agent"],
+		ClientCert: s.peerCertificate(attrs.GetSource()),
+	}
+
+	return &models.EvaluationRequest{
+		RequestID:   requestID,
+		Subject:     subject,
+		ResourceID:  resourceID,
+		Action:      action,
+		Context:     context,
+		Environment: environment,
+		Timestamp:   &timestamp,
+		Namespace:   extensions[ExtensionNamespace],
+	}
+}
+
+// peerIP returns the socket address of a peer, or "" for pipes and missing addresses
+func peerIP(peer *authv3.AttributeContext_Peer) string {
+	return peer.GetAddress().GetSocketAddress().GetAddress()
+}
+
+// peerCertificate parses the URL-encoded PEM certificate Envoy forwards when the listener has
+// include_peer_certificate set. It is marked verified only when Envoy is trusted to have
+// validated it (Config.TrustPeerCertificates); unparsable certificates are ignored.
+func (s *Server) peerCertificate(peer *authv3.AttributeContext_Peer) *models.ClientCertInfo {
+	encoded := peer.GetCertificate()
+	if encoded == "" {
+		return nil
+	}
+	decoded, err := url.QueryUnescape(encoded)
+	if err != nil {
+		return nil
+	}
+	block, _ := pem.Decode([]byte(decoded))
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	info := pep.ClientCertFromCertificate(cert, nil)
+	info.Verified = s.config.TrustPeerCertificates
+	return info
+}
@@ -0,0 +1,230 @@
+// Package extauthz implements the Envoy external authorization gRPC API (envoy.service.auth.v3)
+// on top of the PDP, so any service behind Envoy or Istio is protected without application changes.
+package extauthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/pep"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Headers added to requests Envoy forwards upstream after a permit
+const (
+	HeaderDecision  = "x-abac-decision"   // "permit"; pep.DecisionHeaders also adds x-abac-policies and x-abac-obligation-*
+	HeaderRequestID = "x-abac-request-id" // Evaluation request ID, for correlating audit logs
+	HeaderSubjectID = "x-abac-subject-id" // Authorized subject
+)
+
+// Config holds the ext_authz server settings
+type Config struct {
+	// MethodActions maps HTTP methods to the action evaluated when a route sets no abac_action;
+	// methods missing from the map use DefaultAction
+	MethodActions map[string]string
+	DefaultAction string
+	// TrustPeerCertificates marks client certificates forwarded by Envoy as verified.
+	// Only enable it when Envoy validates client certificates against a trusted CA.
+	TrustPeerCertificates bool
+	// RecordDecision, when set, persists every decision, e.g. to the service audit logs
+	RecordDecision func(request *models.EvaluationRequest, decision *models.Decision)
+}
+
+// DefaultConfig evaluates "read" for safe methods and "write" for everything else,
+// matching the actions of ABACMiddleware
+func DefaultConfig() *Config {
+	return &Config{
+		MethodActions: map[string]string{
+			http.MethodGet:     "read",
+			http.MethodHead:    "read",
+			http.MethodOptions: "read",
+		},
+		DefaultAction: "write",
+	}
+}
+
+// ConfigFromEnv returns the default configuration with ABAC_EXT_AUTHZ_TRUST_PEER_CERTS applied
+func ConfigFromEnv() *Config {
+	config := DefaultConfig()
+	config.TrustPeerCertificates = strings.EqualFold(os.Getenv(constants.EnvExtAuthzTrustPeerCerts), "true")
+	return config
+}
+
+// MethodAction returns the action evaluated for requests with the HTTP method
+func (c *Config) MethodAction(method string) string {
+	if action, ok := c.MethodActions[strings.ToUpper(method)]; ok {
+		return action
+	}
+	return c.DefaultAction
+}
+
+// SubjectResolver identifies the caller of a checked request from its headers
+type SubjectResolver interface {
+	CreateFromRequest(r *http.Request) (models.SubjectInterface, error)
+}
+
+// Server answers Envoy Check calls with PDP decisions
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+
+	pdp      core.PolicyDecisionPointInterface
+	subjects SubjectResolver
+	config   *Config
+}
+
+// NewServer creates an ext_authz server; a nil config uses DefaultConfig()
+func NewServer(pdp core.PolicyDecisionPointInterface, subjects SubjectResolver, config *Config) *Server {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Server{pdp: pdp, subjects: subjects, config: config}
+}
+
+// Register adds the Authorization service to a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	authv3.RegisterAuthorizationServer(registrar, s)
+}
+
+// ListenAndServe serves the Authorization service on addr until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer()
+	s.Register(grpcServer)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	if err := grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// deniedBody is the JSON body Envoy returns to the client of a denied request
+type deniedBody struct {
+	Error      string `json:"error"`
+	Reason     string `json:"reason,omitempty"`
+	ReasonCode string `json:"reason_code,omitempty"`
+	Details    string `json:"details,omitempty"`
+}
+
+// Check implements authv3.AuthorizationServer. Requests without a known subject are denied
+// with 401, policy denials with 403. Evaluation errors are returned as gRPC errors so Envoy
+// applies its failure_mode_allow setting.
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	attrs := req.GetAttributes()
+	httpAttrs := attrs.GetRequest().GetHttp()
+	if httpAttrs == nil {
+		return nil, status.Error(codes.InvalidArgument, "check request has no HTTP attributes")
+	}
+
+	subject, err := s.subjects.CreateFromRequest(httpRequest(httpAttrs))
+	if err != nil {
+		return denied(codes.Unauthenticated, typev3.StatusCode_Unauthorized, deniedBody{
+			Error:   "Authentication required",
+			Details: err.Error(),
+		}), nil
+	}
+
+	request := s.evaluationRequest(attrs, subject)
+	decision, err := s.pdp.Evaluate(request)
+	if err != nil {
+		log.Printf("ext_authz evaluation error: %v", err)
+		return nil, status.Error(codes.Internal, "authorization error")
+	}
+	if s.config.RecordDecision != nil {
+		s.config.RecordDecision(request, decision)
+	}
+
+	log.Printf("ext_authz Decision: %s - Subject: %s, Resource: %s, Action: %s, Reason: %s",
+		decision.Result, subject.GetID(), request.ResourceID, request.Action, decision.Reason)
+
+	if decision.Result != constants.ResultPermit {
+		return denied(codes.PermissionDenied, typev3.StatusCode_Forbidden, deniedBody{
+			Error:      "Access denied",
+			Reason:     decision.Reason,
+			ReasonCode: decision.ReasonCode,
+		}), nil
+	}
+
+	headers := pep.DecisionHeaders(request.RequestID, decision)
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{
+			Headers:         headerOptions(headers, subject.GetID()),
+			HeadersToRemove: forgedHeaders(httpAttrs.GetHeaders(), headers),
+		}},
+	}, nil
+}
+
+// headerOptions returns the pep decision headers (result, matched policies, request ID and
+// obligations) plus the authorized subject, lowercased as Envoy expects
+func headerOptions(headers http.Header, subjectID string) []*corev3.HeaderValueOption {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	options := make([]*corev3.HeaderValueOption, 0, len(names)+1)
+	for _, name := range names {
+		options = append(options, header(strings.ToLower(name), headers.Get(name)))
+	}
+	return append(options, header(HeaderSubjectID, subjectID))
+}
+
+// forgedHeaders lists the x-abac-* headers of the incoming request that the decision headers do
+// not overwrite, so clients cannot pass policies or obligations of their own upstream
+func forgedHeaders(incoming map[string]string, headers http.Header) []string {
+	var forged []string
+	for name := range incoming {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-abac-") && name != HeaderSubjectID && headers.Get(name) == "" {
+			forged = append(forged, name)
+		}
+	}
+	sort.Strings(forged)
+	return forged
+}
+
+// denied builds a check response rejecting the request with an HTTP status and JSON body
+func denied(code codes.Code, httpStatus typev3.StatusCode, body deniedBody) *authv3.CheckResponse {
+	encoded, _ := json.Marshal(body)
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: body.Error},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+			Status:  &typev3.HttpStatus{Code: httpStatus},
+			Headers: []*corev3.HeaderValueOption{header("content-type", "application/json")},
+			Body:    string(encoded),
+		}},
+	}
+}
+
+// header replaces a header of the request or response
+func header(name, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:       &corev3.HeaderValue{Key: name, Value: value},
+		AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
@@ -0,0 +1,212 @@
+package extauthz
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestServer(t *testing.T, config *Config) *Server {
+	t.Helper()
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	mockStorage.CreateAction(&models.Action{ID: "write", ActionName: "write"})
+	mockStorage.CreateAction(&models.Action{ID: "orders:approve", ActionName: "orders:approve"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:orders:42", ResourceID: "api:orders:42"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:invoices:7", ResourceID: "api:invoices:7"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-orders",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ReadOrders",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Multiple: []string{"read", "orders:approve"}},
+					Resource: models.JSONActionResource{Single: "api:orders:*"},
+				},
+			},
+		},
+	})
+
+	factory := models.NewSubjectFactory(storage.NewStorageUserLoader(mockStorage), storage.NewStorageServiceLoader(mockStorage))
+	return NewServer(core.NewPolicyDecisionPoint(mockStorage), factory, config)
+}
+
+func checkRequest(method, path string, headers, extensions map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{
+		Source: &authv3.AttributeContext_Peer{
+			Address: &corev3.Address{Address: &corev3.Address_SocketAddress{
+				SocketAddress: &corev3.SocketAddress{Address: "10.0.0.5"},
+			}},
+			Principal: "spiffe://cluster.local/ns/shop/sa/frontend",
+		},
+		Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+			Id:      "envoy-req-1",
+			Method:  method,
+			Path:    path,
+			Host:    "orders.shop.svc",
+			Scheme:  "https",
+			Headers: headers,
+		}},
+		ContextExtensions: extensions,
+	}}
+}
+
+func TestCheck(t *testing.T) {
+	recorded := []string{}
+	config := DefaultConfig()
+	config.RecordDecision = func(request *models.EvaluationRequest, decision *models.Decision) {
+		recorded = append(recorded, decision.Result)
+	}
+	server := newTestServer(t, config)
+	user := map[string]string{"x-user-id": "user-001", "user-agent": "curl/8.0", "x-abac-obligation-audit": "minimal"}
+	orders := map[string]string{ExtensionResource: "api:orders:42"}
+
+	tests := []struct {
+		name       string
+		request    *authv3.CheckRequest
+		code       codes.Code
+		httpStatus typev3.StatusCode
+	}{
+		{"GET maps to read", checkRequest("GET", "/orders/42?expand=items", user, orders), codes.OK, 0},
+		{"POST maps to write", checkRequest("POST", "/orders/42", user, orders), codes.PermissionDenied, typev3.StatusCode_Forbidden},
+		{"route action", checkRequest("POST", "/orders/42/approve", user, map[string]string{ExtensionResource: "api:orders:42", ExtensionAction: "orders:approve"}), codes.OK, 0},
+		{"other resources", checkRequest("GET", "/invoices/7", user, map[string]string{ExtensionResource: "api:invoices:7"}), codes.PermissionDenied, typev3.StatusCode_Forbidden},
+		{"unauthenticated", checkRequest("GET", "/orders/42", map[string]string{}, orders), codes.Unauthenticated, typev3.StatusCode_Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := server.Check(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+			if codes.Code(response.GetStatus().GetCode()) != tt.code {
+				t.Fatalf("Expected %s, got %s", tt.code, codes.Code(response.GetStatus().GetCode()))
+			}
+
+			if tt.code == codes.OK {
+				headers := make(map[string]string)
+				for _, option := range response.GetOkResponse().GetHeaders() {
+					headers[option.GetHeader().GetKey()] = option.GetHeader().GetValue()
+				}
+				if headers[HeaderDecision] != "permit" || headers[HeaderSubjectID] != "user-001" || headers[HeaderRequestID] != "envoy-req-1" || headers["x-abac-policies"] == "" {
+					t.Errorf("Unexpected upstream headers %v", headers)
+				}
+				if removed := response.GetOkResponse().GetHeadersToRemove(); len(removed) != 1 || removed[0] != "x-abac-obligation-audit" {
+					t.Errorf("Expected the client's obligation header to be removed, got %v", removed)
+				}
+				return
+			}
+
+			denied := response.GetDeniedResponse()
+			if denied.GetStatus().GetCode() != tt.httpStatus {
+				t.Errorf("Expected HTTP %s, got %s", tt.httpStatus, denied.GetStatus().GetCode())
+			}
+			var body deniedBody
+			if err := json.Unmarshal([]byte(denied.GetBody()), &body); err != nil || body.Error == "" {
+				t.Errorf("Expected JSON error body, got %q", denied.GetBody())
+			}
+		})
+	}
+
+	if _, err := server.Check(context.Background(), &authv3.CheckRequest{}); err == nil {
+		t.Error("Expected an error for a check without HTTP attributes")
+	}
+	// Every evaluated check is recorded; the unauthenticated one never reached the PDP
+	if len(recorded) != 4 || recorded[0] != "permit" || recorded[1] != "deny" {
+		t.Errorf("Expected 4 recorded decisions, got %v", recorded)
+	}
+}
+
+func TestEvaluationRequest(t *testing.T) {
+	server := newTestServer(t, &Config{DefaultAction: "write", TrustPeerCertificates: true})
+	check := checkRequest("DELETE", "/orders/42?force=true", map[string]string{"user-agent": "curl/8.0"}, map[string]string{ExtensionNamespace: "shop"})
+	check.Attributes.Source.Certificate = url.QueryEscape(string(testCertificatePEM(t)))
+
+	request := server.evaluationRequest(check.Attributes, models.NewUserSubject(&models.User{ID: "user-001"}, nil, nil))
+	if request.ResourceID != "/orders/42" || request.Action != "write" || request.Namespace != "shop" {
+		t.Errorf("Unexpected request %s %s in %q", request.Action, request.ResourceID, request.Namespace)
+	}
+	if request.Context["query"] != "force=true" || request.Context["host"] != "orders.shop.svc" || request.Context["user_ip"] != "10.0.0.5" {
+		t.Errorf("Unexpected context %v", request.Context)
+	}
+	if request.Context["source_principal"] != "spiffe://cluster.local/ns/shop/sa/frontend" {
+		t.Errorf("Expected source principal, got %v", request.Context["source_principal"])
+	}
+	if request.Environment.ClientIP != "10.0.0.5" || request.Environment.UserAgent != "curl/8.0" {
+		t.Errorf("Unexpected environment %+v", request.Environment)
+	}
+	if cert := request.Environment.ClientCert; cert == nil || cert.CommonName != "frontend" || !cert.Verified {
+		t.Errorf("Expected verified peer certificate, got %+v", cert)
+	}
+}
+
+func TestCheckOverGRPC(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	newTestServer(t, nil).Register(grpcServer)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	response, err := authv3.NewAuthorizationClient(conn).Check(context.Background(),
+		checkRequest("GET", "/orders/42", map[string]string{"x-user-id": "user-001"}, map[string]string{ExtensionResource: "api:orders:42"}))
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if response.GetOkResponse() == nil {
+		t.Errorf("Expected an OK response, got %v", response)
+	}
+}
+
+// testCertificatePEM returns a self-signed client certificate for CN=frontend
+func testCertificatePEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "frontend"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
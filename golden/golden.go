@@ -0,0 +1,242 @@
+// Package golden records (request, decision) pairs into versioned golden files and replays them
+// against the current engine, so evaluator refactors cannot change decisions unnoticed.
+package golden
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// FormatVersion is the golden file format written by Save; Load rejects other versions
+const FormatVersion = 1
+
+// Request is a self-contained evaluation request: subject and resource are described by their
+// attributes, so replays need no database
+type Request struct {
+	SubjectID          string                  `json:"subject_id"`
+	SubjectAttributes  map[string]interface{}  `json:"subject_attributes,omitempty"`
+	ResourceID         string                  `json:"resource_id"`
+	ResourceType       string                  `json:"resource_type,omitempty"`
+	ResourceAttributes map[string]interface{}  `json:"resource_attributes,omitempty"`
+	Action             string                  `json:"action"`
+	Context            map[string]interface{}  `json:"context,omitempty"`
+	Environment        *models.EnvironmentInfo `json:"environment,omitempty"`
+	Session            *models.SessionInfo     `json:"session,omitempty"`
+	// Timestamp pins time-based conditions; Record sets it to the recording time when empty
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// Decision is the recorded part of a decision. Reasons and timings are not compared.
+type Decision struct {
+	Result          string   `json:"result"`
+	MatchedPolicies []string `json:"matched_policies,omitempty"` // Sorted
+	ReasonCode      string   `json:"reason_code,omitempty"`
+}
+
+// Case is one (request, decision) pair
+type Case struct {
+	Name     string   `json:"name"`
+	Request  Request  `json:"request"`
+	Decision Decision `json:"decision"`
+}
+
+// File is a golden file. It embeds its policy set so that only engine changes cause drift.
+type File struct {
+	Version     int              `json:"version"`
+	Description string           `json:"description,omitempty"`
+	RecordedAt  time.Time        `json:"recorded_at"`
+	Policies    []*models.Policy `json:"policies"`
+	Cases       []Case           `json:"cases"`
+}
+
+// Drift is a case whose current decision differs from the recorded one
+type Drift struct {
+	Case string   `json:"case"`
+	Want Decision `json:"want"`
+	Got  Decision `json:"got"`
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s: recorded %s, got %s", d.Case, d.Want, d.Got)
+}
+
+func (d Decision) String() string {
+	s := d.Result
+	if d.ReasonCode != "" {
+		s += " (" + d.ReasonCode + ")"
+	}
+	if len(d.MatchedPolicies) > 0 {
+		s += " [" + strings.Join(d.MatchedPolicies, ", ") + "]"
+	}
+	return s
+}
+
+// Load reads a golden file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid golden file %s: %w", path, err)
+	}
+	if f.Version != FormatVersion {
+		return nil, fmt.Errorf("golden file %s has format version %d, expected %d", path, f.Version, FormatVersion)
+	}
+	return &f, nil
+}
+
+// Save writes the file as indented JSON, stable across runs so diffs show only real changes
+func (f *File) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Record evaluates every case against policies and stores the decisions in a new file
+func Record(description string, policies []*models.Policy, cases []Case) (*File, error) {
+	f := &File{
+		Version:     FormatVersion,
+		Description: description,
+		RecordedAt:  time.Now().UTC().Truncate(time.Second),
+		Policies:    policies,
+		Cases:       make([]Case, len(cases)),
+	}
+	names := make(map[string]bool, len(cases))
+	for i, c := range cases {
+		if c.Name == "" || names[c.Name] {
+			return nil, fmt.Errorf("case %d: name missing or duplicated: %q", i, c.Name)
+		}
+		names[c.Name] = true
+		if c.Request.Timestamp == nil {
+			recordedAt := f.RecordedAt
+			c.Request.Timestamp = &recordedAt
+		}
+		decision, err := evaluate(policies, &c.Request)
+		if err != nil {
+			return nil, fmt.Errorf("case %s: %w", c.Name, err)
+		}
+		c.Decision = decision
+		f.Cases[i] = c
+	}
+	return f, nil
+}
+
+// Rerecord replaces the recorded decisions with those of the current engine, keeping the cases
+func (f *File) Rerecord() (*File, error) {
+	return Record(f.Description, f.Policies, f.Cases)
+}
+
+// Replay re-evaluates every case and returns those whose decision drifted
+func (f *File) Replay() ([]Drift, error) {
+	var drifts []Drift
+	for _, c := range f.Cases {
+		got, err := evaluate(f.Policies, &c.Request)
+		if err != nil {
+			return nil, fmt.Errorf("case %s: %w", c.Name, err)
+		}
+		if !equal(c.Decision, got) {
+			drifts = append(drifts, Drift{Case: c.Name, Want: c.Decision, Got: got})
+		}
+	}
+	return drifts, nil
+}
+
+// evaluate runs one request against a fresh PDP, so no cache or counter carries over between cases
+func evaluate(policies []*models.Policy, request *Request) (Decision, error) {
+	if request.SubjectID == "" || request.ResourceID == "" || request.Action == "" {
+		return Decision{}, errors.New("subject_id, resource_id and action are required")
+	}
+
+	store := storage.NewMockStorage()
+	store.SetPolicies(policies)
+	if err := store.CreateResource(&models.Resource{
+		ID:           request.ResourceID,
+		ResourceID:   request.ResourceID,
+		ResourceType: request.ResourceType,
+		Attributes:   models.JSONMap(copyMap(request.ResourceAttributes)),
+	}); err != nil {
+		return Decision{}, err
+	}
+	if err := store.CreateAction(&models.Action{ID: request.Action, ActionName: request.Action}); err != nil {
+		return Decision{}, err
+	}
+
+	config := core.DefaultPDPConfig()
+	config.EnableStats = false
+	decision, err := core.NewPolicyDecisionPointWithConfig(store, config).Evaluate(&models.EvaluationRequest{
+		RequestID:   "golden",
+		Subject:     &caseSubject{id: request.SubjectID, attributes: request.SubjectAttributes},
+		ResourceID:  request.ResourceID,
+		Action:      request.Action,
+		Context:     copyMap(request.Context),
+		Environment: request.Environment,
+		Timestamp:   request.Timestamp,
+		Session:     request.Session,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	matched := append([]string(nil), decision.MatchedPolicies...)
+	sort.Strings(matched)
+	return Decision{Result: decision.Result, MatchedPolicies: matched, ReasonCode: decision.ReasonCode}, nil
+}
+
+func equal(a, b Decision) bool {
+	if a.Result != b.Result || a.ReasonCode != b.ReasonCode || len(a.MatchedPolicies) != len(b.MatchedPolicies) {
+		return false
+	}
+	for i := range a.MatchedPolicies {
+		if a.MatchedPolicies[i] != b.MatchedPolicies[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// caseSubject is a subject described only by the attributes of a case
+type caseSubject struct {
+	id         string
+	attributes map[string]interface{}
+}
+
+func (s *caseSubject) GetID() string { return s.id }
+
+func (s *caseSubject) GetType() models.SubjectType {
+	if subjectType, ok := s.attributes["subject_type"].(string); ok && subjectType != "" {
+		return models.SubjectType(subjectType)
+	}
+	return models.SubjectTypeUser
+}
+
+func (s *caseSubject) GetAttributes() map[string]interface{} {
+	attributes := copyMap(s.attributes)
+	attributes["id"] = s.id
+	return attributes
+}
+
+func (s *caseSubject) GetDisplayName() string { return s.id }
+
+func (s *caseSubject) IsActive() bool { return true }
+
+// copyMap keeps the PDP from mutating the maps of a case
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m)+1)
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,107 @@
+package golden
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+var update = flag.Bool("update", false, "re-record the decisions of golden files that drifted")
+
+// TestGoldenCorpus replays every testdata/*.golden.json file against the current engine.
+// An intended behavior change is accepted with: go test ./golden -run TestGoldenCorpus -update
+func TestGoldenCorpus(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.golden.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no golden files found (%v)", err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			drifts, err := f.Replay()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(drifts) == 0 {
+				return
+			}
+			if *update {
+				rerecorded, err := f.Rerecord()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := rerecorded.Save(path); err != nil {
+					t.Fatal(err)
+				}
+				t.Logf("re-recorded %d drifted decisions", len(drifts))
+				return
+			}
+			for _, drift := range drifts {
+				t.Errorf("decision drift: %s", drift)
+			}
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	policies := []*models.Policy{{
+		ID: "pol-read", PolicyName: "Read", Version: "1", Enabled: true,
+		Statement: []models.PolicyStatement{{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "doc:read"},
+			Resource: models.JSONActionResource{Single: "api:docs:*"}}},
+	}}
+	f, err := Record("test", policies, []Case{
+		{Name: "read", Request: Request{SubjectID: "user-1", ResourceID: "api:docs:1", Action: "doc:read"}},
+		{Name: "write", Request: Request{SubjectID: "user-1", ResourceID: "api:docs:1", Action: "doc:write"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Cases[0].Decision.Result != "permit" || f.Cases[1].Decision.Result != "deny" {
+		t.Fatalf("unexpected recorded decisions %+v", f.Cases)
+	}
+	if f.Cases[0].Request.Timestamp == nil || !f.Cases[0].Request.Timestamp.Equal(f.RecordedAt) {
+		t.Error("expected the request time to be pinned to the recording time")
+	}
+
+	path := filepath.Join(t.TempDir(), "test.golden.json")
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drifts, err := loaded.Replay(); err != nil || len(drifts) != 0 {
+		t.Fatalf("expected no drift, got %v (%v)", drifts, err)
+	}
+
+	// A changed engine (simulated by disabling the policy) is reported as drift
+	loaded.Policies[0].Enabled = false
+	drifts, err := loaded.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 1 || drifts[0].Case != "read" || drifts[0].Got.Result == "permit" {
+		t.Fatalf("expected the read case to drift, got %v", drifts)
+	}
+	if description := drifts[0].String(); !strings.Contains(description, "recorded permit") || !strings.Contains(description, "[pol-read]") {
+		t.Errorf("unexpected drift description %q", drifts[0])
+	}
+
+	if _, err := Record("test", policies, []Case{{Name: "a"}, {Name: "a"}}); err == nil {
+		t.Error("expected duplicate case names to fail")
+	}
+	f.Version = FormatVersion + 1
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an unknown format version to be rejected")
+	}
+}
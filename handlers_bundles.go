@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"abac_go_example/bundle"
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadTrustedBundle verifies the bundle file at path and loads it into the PDP. Without a file
+// no stored policy is trusted (every request is denied) until a bundle is imported; a file that
+// fails verification is an error so a tampered bundle never starts serving. It returns the loaded
+// bundle, or nil when there is none.
+func loadTrustedBundle(pdp core.PolicyDecisionPointInterface, path string) (*bundle.Bundle, error) {
+	if path == "" {
+		log.Printf("Policy integrity: no %s configured, denying all requests until a bundle is imported", constants.EnvPolicyBundle)
+		return nil, nil
+	}
+	signed, err := bundle.LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Policy integrity: bundle %s not found, denying all requests until a bundle is imported", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := pdp.LoadBundle(signed); err != nil {
+		return nil, fmt.Errorf("bundle %s: %w", path, err)
+	}
+	log.Printf("Policy integrity: loaded bundle %s (%d policies, key %s)", signed.ShortHash(), len(signed.Policies), signed.KeyID)
+	return signed, nil
+}
+
+// BundleImportResponse counts the bundle policies created, updated and left unchanged in storage
+type BundleImportResponse struct {
+	BundleHash string `json:"bundle_hash"`
+	KeyID      string `json:"key_id"`
+	Created    int    `json:"created"`
+	Updated    int    `json:"updated"`
+	Unchanged  int    `json:"unchanged"`
+}
+
+// BundleStatusResponse reports the trusted bundle; Status is nil when bundle verification is disabled
+type BundleStatusResponse struct {
+	Enabled bool                  `json:"enabled"`
+	Status  *core.IntegrityStatus `json:"status,omitempty"`
+	Fetcher *bundle.FetcherStatus `json:"fetcher,omitempty"` // Downloads from ABAC_BUNDLE_URL, when polling
+}
+
+// bundleFetcherActor is recorded as the actor of policy changes applied by the bundle fetcher
+const bundleFetcherActor = "bundle-fetcher"
+
+// handleExportBundle signs the enabled policies into a bundle (requires ABAC_BUNDLE_SIGNING_KEY)
+func (service *ABACService) handleExportBundle(c *gin.Context) {
+	if service.bundleSigner == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Bundle signing key is not configured"})
+		return
+	}
+
+	policies, err := service.storage.GetPolicies()
+	if err != nil {
+		log.Printf("Failed to load policies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policies"})
+		return
+	}
+
+	signed, err := service.bundleSigner.Sign(policies)
+	if err != nil {
+		log.Printf("Failed to sign policy bundle: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign policy bundle"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="policies.bundle.json"`)
+	c.JSON(http.StatusOK, signed)
+}
+
+// handleImportBundle verifies a signed bundle, makes it the PDP's trusted policy set and
+// writes its policies to storage. Policies already stored with the same content are left untouched.
+func (service *ABACService) handleImportBundle(c *gin.Context) {
+	signed, err := bundle.Read(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bundle", "details": err.Error()})
+		return
+	}
+
+	// Verification happens before anything is stored
+	if err := service.pdp.LoadBundle(signed); err != nil {
+		if errors.Is(err, core.ErrIntegrityDisabled) {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Bundle verification key is not configured"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bundle verification failed", "details": err.Error()})
+		return
+	}
+
+	response, err := service.storeBundle(signed, requestActor(c))
+	if err != nil {
+		log.Printf("Failed to store policy bundle: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store policy bundle", "details": err.Error()})
+		return
+	}
+	// The fetcher does not replace an imported bundle with an older download
+	if service.bundleFetcher != nil {
+		service.bundleFetcher.SetCurrent(signed)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// applyFetchedBundle hot-swaps a bundle downloaded by the bundle fetcher, like a bundle import
+func (service *ABACService) applyFetchedBundle(signed *bundle.Bundle) error {
+	if err := service.pdp.LoadBundle(signed); err != nil {
+		return err
+	}
+	_, err := service.storeBundle(signed, bundleFetcherActor)
+	return err
+}
+
+// storeBundle writes the policies of a loaded bundle to storage and persists the trusted bundle file.
+// Policies already stored with the same content are left untouched.
+func (service *ABACService) storeBundle(signed *bundle.Bundle, actor string) (*BundleImportResponse, error) {
+	response := &BundleImportResponse{BundleHash: signed.Hash, KeyID: signed.KeyID}
+	for _, policy := range signed.Policies {
+		changed, isNew, err := service.storeBundlePolicy(policy, actor)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", policy.ID, err)
+		}
+		switch {
+		case isNew:
+			response.Created++
+		case changed:
+			response.Updated++
+		default:
+			response.Unchanged++
+		}
+	}
+
+	if service.bundlePath != "" {
+		if err := signed.WriteFile(service.bundlePath); err != nil {
+			return nil, fmt.Errorf("failed to persist trusted bundle: %w", err)
+		}
+	}
+	log.Printf("Policy integrity: loaded bundle %s (%d policies, key %s)", signed.ShortHash(), len(signed.Policies), signed.KeyID)
+	return response, nil
+}
+
+// storeBundlePolicy creates or replaces a stored policy with its bundle version
+func (service *ABACService) storeBundlePolicy(policy *models.Policy, actor string) (changed, isNew bool, err error) {
+	existing, err := service.storage.GetPolicy(policy.ID)
+	if errors.Is(err, storage.ErrPolicyNotFound) {
+		if err := service.storage.CreatePolicy(policy); err != nil {
+			return false, false, err
+		}
+		service.recordPolicyChangeAs(actor, models.PolicyChangeCreate, nil, policy)
+		return true, true, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	existingDigest, err := bundle.PolicyDigest(existing)
+	if err != nil {
+		return false, false, err
+	}
+	digest, err := bundle.PolicyDigest(policy)
+	if err != nil {
+		return false, false, err
+	}
+	if existingDigest == digest {
+		return false, false, nil
+	}
+
+	replacement := *policy
+	replacement.Revision = existing.Revision
+	replacement.CreatedAt = existing.CreatedAt
+	if err := service.storage.UpdatePolicy(&replacement); err != nil {
+		return false, false, err
+	}
+	service.recordPolicyChangeAs(actor, models.PolicyChangeUpdate, existing, &replacement)
+	return true, false, nil
+}
+
+// handleBundleStatus reports the trusted bundle and stored policies rejected against it
+func (service *ABACService) handleBundleStatus(c *gin.Context) {
+	status, enabled := service.pdp.GetIntegrityStatus()
+	if !enabled {
+		c.JSON(http.StatusOK, BundleStatusResponse{Enabled: false})
+		return
+	}
+
+	response := BundleStatusResponse{
+		Enabled: true,
+		Status:  status,
+	}
+	if service.bundleFetcher != nil {
+		fetcherStatus := service.bundleFetcher.Status()
+		response.Fetcher = &fetcherStatus
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"abac_go_example/sink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// decisionStreamHeartbeat keeps idle SSE connections alive through proxies
+const decisionStreamHeartbeat = 15 * time.Second
+
+// handleDecisionStream streams live decision events as Server-Sent Events.
+// Query parameters subject, resource (both support '*') and result filter the stream.
+func (service *ABACService) handleDecisionStream(c *gin.Context) {
+	if service.decisions == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Decision stream is not enabled"})
+		return
+	}
+
+	filter := sink.EventFilter{
+		SubjectID:  c.Query("subject"),
+		ResourceID: c.Query("resource"),
+		Result:     c.Query("result"),
+	}
+
+	subscription := service.decisions.Subscribe(filter, sink.DefaultSubscriberBuffer)
+	defer subscription.Unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// Send headers right away so clients see the stream as open before the first event
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(decisionStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent("decision", event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"dropped": subscription.Dropped()})
+			return true
+		}
+	})
+}
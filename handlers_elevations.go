@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log identifiers of elevation changes
+const (
+	elevationAuditCreate = "elevation:create"
+	elevationAuditDelete = "elevation:delete"
+)
+
+// ElevationRequestBody grants a subject roles and attributes until expires_at
+type ElevationRequestBody struct {
+	ID            string                 `json:"id"` // Generated when empty
+	SubjectID     string                 `json:"subject_id" binding:"required"`
+	Roles         []string               `json:"roles,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Justification string                 `json:"justification" binding:"required"`
+	ExpiresAt     time.Time              `json:"expires_at" binding:"required"` // At most storage.MaxElevationDuration ahead
+}
+
+// ElevationListResponse lists elevations
+type ElevationListResponse struct {
+	Count      int                 `json:"count"`
+	Elevations []*models.Elevation `json:"elevations"`
+}
+
+// elevationStore returns the storage's ElevationStore, answering 501 when it has none
+func (service *ABACService) elevationStore(c *gin.Context) (storage.ElevationStore, bool) {
+	elevationStore, ok := service.storage.(storage.ElevationStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support elevations"})
+	}
+	return elevationStore, ok
+}
+
+// handleListElevations lists elevations, expired ones included (?subject_id= filters by subject)
+func (service *ABACService) handleListElevations(c *gin.Context) {
+	elevationStore, ok := service.elevationStore(c)
+	if !ok {
+		return
+	}
+
+	elevations, err := elevationStore.ListElevations(c.Query("subject_id"))
+	if err != nil {
+		log.Printf("Failed to list elevations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list elevations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ElevationListResponse{
+		Count:      len(elevations),
+		Elevations: elevations,
+	})
+}
+
+// handleCreateElevation grants a subject just-in-time roles and attributes ("sudo mode")
+func (service *ABACService) handleCreateElevation(c *gin.Context) {
+	elevationStore, ok := service.elevationStore(c)
+	if !ok {
+		return
+	}
+
+	var body ElevationRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	elevation := &models.Elevation{
+		ID:            body.ID,
+		SubjectID:     body.SubjectID,
+		Roles:         body.Roles,
+		Attributes:    body.Attributes,
+		Justification: body.Justification,
+		CreatedBy:     requestActor(c),
+		ExpiresAt:     body.ExpiresAt,
+	}
+	if elevation.ID == "" {
+		elevation.ID = fmt.Sprintf("elev_%d", time.Now().UnixNano())
+	} else if _, err := elevationStore.GetElevation(elevation.ID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Elevation already exists", "elevation_id": elevation.ID})
+		return
+	}
+
+	if err := elevationStore.CreateElevation(elevation); err != nil {
+		if errors.Is(err, storage.ErrInvalidElevation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid elevation", "details": err.Error()})
+			return
+		}
+		log.Printf("Failed to create elevation %s: %v", elevation.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create elevation"})
+		return
+	}
+	// A cached deny may predate the elevation
+	service.pdp.PurgeDenyCache()
+	service.recordElevation(elevationAuditCreate, elevation.CreatedBy, elevation)
+	log.Printf("Elevation %s created by %s: subject=%s roles=%v until %s (%q)", elevation.ID, elevation.CreatedBy,
+		elevation.SubjectID, elevation.Roles, elevation.ExpiresAt.Format(time.RFC3339), elevation.Justification)
+
+	c.JSON(http.StatusCreated, elevation)
+}
+
+// handleDeleteElevation ends an elevation before it expires
+func (service *ABACService) handleDeleteElevation(c *gin.Context) {
+	elevationStore, ok := service.elevationStore(c)
+	if !ok {
+		return
+	}
+
+	elevation, err := elevationStore.GetElevation(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Elevation not found", "elevation_id": c.Param("id")})
+		return
+	}
+	if err := elevationStore.DeleteElevation(elevation.ID); err != nil {
+		log.Printf("Failed to delete elevation %s: %v", elevation.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete elevation"})
+		return
+	}
+	service.recordElevation(elevationAuditDelete, requestActor(c), elevation)
+
+	c.Status(http.StatusNoContent)
+}
+
+// recordElevation writes an elevation change to the audit log. The change has already taken
+// effect, so a storage failure is logged rather than undoing it.
+func (service *ABACService) recordElevation(action, actor string, elevation *models.Elevation) {
+	auditLog := &models.AuditLog{
+		RequestID:  fmt.Sprintf("elevation_%d", time.Now().UnixNano()),
+		SubjectID:  actor,
+		ResourceID: elevation.ID,
+		ActionID:   action,
+		Decision:   constants.ResultPermit,
+		Context: models.JSONMap{
+			"subject_id":    elevation.SubjectID,
+			"roles":         []string(elevation.Roles),
+			"attributes":    map[string]interface{}(elevation.Attributes),
+			"justification": elevation.Justification,
+			"expires_at":    elevation.ExpiresAt.Format(time.RFC3339),
+		},
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", action, err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EvaluateRequestBody is the JSON body accepted by the evaluation API.
+// The subject is referenced by ID and resolved through the SubjectFactory.
+type EvaluateRequestBody struct {
+	RequestID   string                  `json:"request_id"`
+	SubjectID   string                  `json:"subject_id" binding:"required"`
+	ResourceID  string                  `json:"resource_id" binding:"required"`
+	Action      string                  `json:"action" binding:"required"`
+	Context     map[string]interface{}  `json:"context"`
+	Environment *models.EnvironmentInfo `json:"environment,omitempty"`
+	Timestamp   *time.Time              `json:"timestamp,omitempty"`
+	Session     *models.SessionInfo     `json:"session,omitempty"`
+	Fields      []string                `json:"fields,omitempty"` // Optional field-level directives
+	// Point-in-time evaluation: attributes as of AsOf, or supplied snapshots
+	AsOf      *time.Time                 `json:"as_of,omitempty"`
+	Snapshots *models.AttributeSnapshots `json:"snapshots,omitempty"`
+	Namespace string                     `json:"namespace,omitempty"` // Overrides the service's ABAC_NAMESPACE
+}
+
+// EvaluateResponse is the decision of an evaluation request; Fields is set when fields were requested
+type EvaluateResponse struct {
+	RequestID string                           `json:"request_id"`
+	Decision  *models.Decision                 `json:"decision"`
+	Fields    map[string]models.FieldDirective `json:"fields,omitempty"`
+}
+
+// ExplainResponse is the decision of an evaluation request with statement traces
+type ExplainResponse struct {
+	RequestID   string              `json:"request_id"`
+	Explanation *models.Explanation `json:"explanation"`
+}
+
+// handleEvaluate evaluates a request and returns the decision (central PDP mode)
+// When fields are given, per-field allow/deny/mask directives are returned as well.
+func (service *ABACService) handleEvaluate(c *gin.Context) {
+	request, fields, ok := service.bindEvaluationRequest(c)
+	if !ok {
+		return
+	}
+
+	if len(fields) > 0 {
+		fieldDecision, err := service.pdp.EvaluateFields(request, fields)
+		if err != nil {
+			log.Printf("ABAC evaluation error: %v", err)
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Evaluation failed", "details": err.Error()})
+			return
+		}
+		service.recordDecision(request, fieldDecision.Decision)
+
+		c.JSON(http.StatusOK, EvaluateResponse{
+			RequestID: request.RequestID,
+			Decision:  fieldDecision.Decision,
+			Fields:    fieldDecision.Fields,
+		})
+		return
+	}
+
+	decision, err := service.pdp.Evaluate(request)
+	if err != nil {
+		log.Printf("ABAC evaluation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Evaluation failed", "details": err.Error()})
+		return
+	}
+	service.recordDecision(request, decision)
+
+	c.JSON(http.StatusOK, EvaluateResponse{
+		RequestID: request.RequestID,
+		Decision:  decision,
+	})
+}
+
+// handleExplain evaluates a request and returns the decision with statement traces.
+// With ?format=dot it returns the decision tree as Graphviz DOT instead.
+func (service *ABACService) handleExplain(c *gin.Context) {
+	request, _, ok := service.bindEvaluationRequest(c)
+	if !ok {
+		return
+	}
+
+	explanation, err := service.pdp.Explain(request)
+	if err != nil {
+		log.Printf("ABAC explain error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Evaluation failed", "details": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "dot" && explanation.Tree != nil {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(explanation.Tree.ToDOT()))
+		return
+	}
+
+	c.JSON(http.StatusOK, ExplainResponse{
+		RequestID:   request.RequestID,
+		Explanation: explanation,
+	})
+}
+
+// handlePolicyFingerprint returns the hash of the evaluated policy set, also sent as ETag.
+// A request whose If-None-Match carries the current hash gets 304 Not Modified.
+func (service *ABACService) handlePolicyFingerprint(c *gin.Context) {
+	fingerprint, err := service.pdp.GetPolicyFingerprint(c.Query("namespace"))
+	if err != nil {
+		log.Printf("Failed to compute policy fingerprint: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute policy fingerprint"})
+		return
+	}
+
+	etag := `"` + fingerprint.Hash + `"`
+	c.Header("ETag", etag)
+	if strings.TrimPrefix(c.GetHeader("If-None-Match"), "W/") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, fingerprint)
+}
+
+// bindEvaluationRequest parses the request body and resolves the subject, returning the requested fields too.
+// It writes an error response and returns false when the request cannot be built.
+func (service *ABACService) bindEvaluationRequest(c *gin.Context) (*models.EvaluationRequest, []string, bool) {
+	var body EvaluateRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return nil, nil, false
+	}
+
+	request, err := service.buildEvaluationRequest(&body)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subject not found", "subject_id": body.SubjectID})
+		return nil, nil, false
+	}
+
+	return request, body.Fields, true
+}
+
+// buildEvaluationRequest resolves the subject of a request body and builds the EvaluationRequest
+// Requests of a prepared session skip the subject lookup (see handlePrepareSession).
+func (service *ABACService) buildEvaluationRequest(body *EvaluateRequestBody) (*models.EvaluationRequest, error) {
+	subject, err := service.sessionSubject(body)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID := body.RequestID
+	if requestID == "" {
+		requestID = fmt.Sprintf("req_%d", time.Now().UnixNano())
+	}
+
+	return &models.EvaluationRequest{
+		RequestID:   requestID,
+		Subject:     subject,
+		ResourceID:  body.ResourceID,
+		Action:      body.Action,
+		Context:     body.Context,
+		Environment: body.Environment,
+		Timestamp:   body.Timestamp,
+		Session:     body.Session,
+		AsOf:        body.AsOf,
+		Snapshots:   body.Snapshots,
+		Namespace:   body.Namespace,
+	}, nil
+}
+
+// sessionSubject returns the prepared subject of the request's session when the PDP will reuse it,
+// otherwise it loads the subject through the SubjectFactory
+func (service *ABACService) sessionSubject(body *EvaluateRequestBody) (models.SubjectInterface, error) {
+	if body.Session != nil && body.AsOf == nil && (body.Snapshots == nil || body.Snapshots.Subject == nil) {
+		if prepared, ok := service.pdp.GetPreparedSubjectContext(body.Session.SessionID, body.SubjectID); ok {
+			return prepared, nil
+		}
+	}
+	return service.subjectFactory.CreateFromSubjectID(body.SubjectID)
+}
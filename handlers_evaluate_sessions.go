@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrepareSessionRequestBody starts an evaluation session of a subject
+type PrepareSessionRequestBody struct {
+	SessionID string `json:"session_id" binding:"required"`
+	SubjectID string `json:"subject_id" binding:"required"`
+}
+
+// handlePrepareSession enriches a subject once at session start. Evaluations whose
+// session.session_id matches reuse the prepared subject instead of loading it again.
+func (service *ABACService) handlePrepareSession(c *gin.Context) {
+	var body PrepareSessionRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	subject, err := service.subjectFactory.CreateFromSubjectID(body.SubjectID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subject not found", "subject_id": body.SubjectID})
+		return
+	}
+
+	prepared, err := service.pdp.PrepareSubjectContext(body.SessionID, subject)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to prepare subject context", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, prepared)
+}
+
+// handleReleaseSession drops the prepared subject of a session, e.g. at logout
+func (service *ABACService) handleReleaseSession(c *gin.Context) {
+	if !service.pdp.ReleaseSubjectContext(c.Param("id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log identifiers of access exception changes
+const (
+	exceptionAuditCreate = "exception:create"
+	exceptionAuditDelete = "exception:delete"
+)
+
+// ExceptionRequestBody creates an access exception
+type ExceptionRequestBody struct {
+	ID            string    `json:"id"` // Generated when empty
+	SubjectID     string    `json:"subject_id" binding:"required"`
+	ResourceID    string    `json:"resource_id" binding:"required"`
+	Action        string    `json:"action" binding:"required"`
+	Effect        string    `json:"effect" binding:"required"` // "allow" or "deny"
+	Justification string    `json:"justification" binding:"required"`
+	ExpiresAt     time.Time `json:"expires_at" binding:"required"`
+}
+
+// ExceptionListResponse lists access exceptions
+type ExceptionListResponse struct {
+	Count      int                       `json:"count"`
+	Exceptions []*models.AccessException `json:"exceptions"`
+}
+
+// exceptionStore returns the storage's ExceptionStore, answering 501 when it has none
+func (service *ABACService) exceptionStore(c *gin.Context) (storage.ExceptionStore, bool) {
+	exceptionStore, ok := service.storage.(storage.ExceptionStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support access exceptions"})
+	}
+	return exceptionStore, ok
+}
+
+// handleListExceptions lists access exceptions, expired ones included (?subject_id= filters by subject)
+func (service *ABACService) handleListExceptions(c *gin.Context) {
+	exceptionStore, ok := service.exceptionStore(c)
+	if !ok {
+		return
+	}
+
+	exceptions, err := exceptionStore.ListExceptions(c.Query("subject_id"))
+	if err != nil {
+		log.Printf("Failed to list access exceptions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list access exceptions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExceptionListResponse{
+		Count:      len(exceptions),
+		Exceptions: exceptions,
+	})
+}
+
+// handleCreateException grants or revokes access to one subject/resource/action until expires_at
+func (service *ABACService) handleCreateException(c *gin.Context) {
+	exceptionStore, ok := service.exceptionStore(c)
+	if !ok {
+		return
+	}
+
+	var body ExceptionRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	exception := &models.AccessException{
+		ID:            body.ID,
+		SubjectID:     body.SubjectID,
+		ResourceID:    body.ResourceID,
+		Action:        body.Action,
+		Effect:        body.Effect,
+		Justification: body.Justification,
+		CreatedBy:     requestActor(c),
+		ExpiresAt:     body.ExpiresAt,
+	}
+	if exception.ID == "" {
+		exception.ID = fmt.Sprintf("exc_%d", time.Now().UnixNano())
+	} else if _, err := exceptionStore.GetException(exception.ID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Access exception already exists", "exception_id": exception.ID})
+		return
+	}
+
+	if err := exceptionStore.CreateException(exception); err != nil {
+		if errors.Is(err, storage.ErrInvalidException) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid access exception", "details": err.Error()})
+			return
+		}
+		log.Printf("Failed to create access exception %s: %v", exception.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create access exception"})
+		return
+	}
+	service.recordException(exceptionAuditCreate, exception.CreatedBy, exception)
+	log.Printf("Access exception %s created by %s: %s %s on %s for %s until %s (%q)", exception.ID, exception.CreatedBy,
+		exception.Effect, exception.Action, exception.ResourceID, exception.SubjectID, exception.ExpiresAt.Format(time.RFC3339), exception.Justification)
+
+	c.JSON(http.StatusCreated, exception)
+}
+
+// handleDeleteException revokes an access exception before it expires
+func (service *ABACService) handleDeleteException(c *gin.Context) {
+	exceptionStore, ok := service.exceptionStore(c)
+	if !ok {
+		return
+	}
+
+	exception, err := exceptionStore.GetException(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access exception not found", "exception_id": c.Param("id")})
+		return
+	}
+	if err := exceptionStore.DeleteException(exception.ID); err != nil {
+		log.Printf("Failed to delete access exception %s: %v", exception.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete access exception"})
+		return
+	}
+	service.recordException(exceptionAuditDelete, requestActor(c), exception)
+
+	c.Status(http.StatusNoContent)
+}
+
+// recordException writes an access exception change to the audit log. The change has already
+// taken effect, so a storage failure is logged rather than undoing it.
+func (service *ABACService) recordException(action, actor string, exception *models.AccessException) {
+	auditLog := &models.AuditLog{
+		RequestID:  fmt.Sprintf("exception_%d", time.Now().UnixNano()),
+		SubjectID:  actor,
+		ResourceID: exception.ID,
+		ActionID:   action,
+		Decision:   constants.ResultPermit,
+		Context: models.JSONMap{
+			"subject_id":    exception.SubjectID,
+			"resource_id":   exception.ResourceID,
+			"action":        exception.Action,
+			"effect":        exception.Effect,
+			"justification": exception.Justification,
+			"expires_at":    exception.ExpiresAt.Format(time.RFC3339),
+		},
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", action, err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"abac_go_example/attributes"
+	"abac_go_example/importer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportResponse reports the records imported and rejected from an NDJSON stream
+type ImportResponse struct {
+	Report *importer.Report `json:"report"`
+}
+
+// handleImport streams an NDJSON body (one subject, resource or policy per line) into storage.
+// Invalid records do not abort the import; they are listed in the report with their line numbers.
+func (service *ABACService) handleImport(c *gin.Context) {
+	kind, err := importer.ParseKind(c.Param("kind"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	im := importer.NewImporter(service.storage)
+	im.SetActor(requestActor(c))
+	report, err := im.Import(kind, c.Request.Body)
+	if report != nil && report.Imported > 0 && kind == importer.KindPolicies {
+		// Cached denies may no longer hold under the new policy set
+		service.pdp.PurgeDenyCache()
+	}
+	if report != nil && report.Imported > 0 {
+		// Imported subjects and resources replace cached lookups
+		switch kind {
+		case importer.KindSubjects:
+			service.pdp.InvalidateAttributeCache(attributes.EntitySubject, "")
+		case importer.KindResources:
+			service.pdp.InvalidateAttributeCache(attributes.EntityResource, "")
+		}
+	}
+	if err != nil {
+		log.Printf("Import of %s stopped: %v", kind, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read import stream", "details": err.Error(), "report": report})
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportResponse{Report: report})
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseListOptions reads ?limit=&offset=&cursor=&type=&enabled=&namespace=&updated_since=&sort= into storage.ListOptions
+func parseListOptions(c *gin.Context) (storage.ListOptions, error) {
+	opts := storage.ListOptions{
+		Cursor: c.Query("cursor"),
+		Type:   c.Query("type"),
+		Sort:   c.Query("sort"),
+		Tag:    c.Query("tag"),
+		Owner:  c.Query("owner"),
+	}
+
+	var err error
+	if value := c.Query("limit"); value != "" {
+		if opts.Limit, err = strconv.Atoi(value); err != nil {
+			return opts, fmt.Errorf("limit must be an integer")
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if opts.Offset, err = strconv.Atoi(value); err != nil {
+			return opts, fmt.Errorf("offset must be an integer")
+		}
+	}
+	if value := c.Query("enabled"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("enabled must be true or false")
+		}
+		opts.Enabled = &enabled
+	}
+	if namespace, present := c.GetQuery("namespace"); present {
+		opts.Namespace = &namespace
+	}
+	if value := c.Query("updated_since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, fmt.Errorf("updated_since must be an RFC 3339 timestamp")
+		}
+		opts.UpdatedSince = &since
+	}
+	return opts, nil
+}
+
+// ListPage is the paging metadata of list responses
+type ListPage struct {
+	Count      int    `json:"count"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor"` // Empty on the last page
+}
+
+// newListPage builds the paging metadata of a page of count items
+func newListPage(count int, info *storage.PageInfo) ListPage {
+	return ListPage{Count: count, Total: info.Total, NextCursor: info.NextCursor}
+}
+
+// SubjectListResponse is a page of subjects
+type SubjectListResponse struct {
+	Subjects []*models.Subject `json:"subjects"`
+	ListPage
+}
+
+// ResourceListResponse is a page of resources
+type ResourceListResponse struct {
+	Resources []*models.Resource `json:"resources"`
+	ListPage
+}
+
+// ActionListResponse is a page of actions
+type ActionListResponse struct {
+	Actions []*models.Action `json:"actions"`
+	ListPage
+}
+
+// PolicyListResponse is a page of policies
+type PolicyListResponse struct {
+	Policies []*models.Policy `json:"policies"`
+	ListPage
+}
+
+// handleList serves a paged list endpoint; list loads one page and returns its response
+func handleList(c *gin.Context, entity string, list func(storage.ListOptions) (interface{}, error)) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list parameters", "details": err.Error()})
+		return
+	}
+
+	response, err := list(opts)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidListOptions) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list parameters", "details": err.Error()})
+			return
+		}
+		log.Printf("Failed to list %s: %v", entity, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list " + entity})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleListSubjects lists subjects page by page, e.g. ?type=user&sort=-updated_at&limit=50
+func (service *ABACService) handleListSubjects(c *gin.Context) {
+	handleList(c, "subjects", func(opts storage.ListOptions) (interface{}, error) {
+		subjects, info, err := service.storage.ListSubjects(opts)
+		if err != nil {
+			return nil, err
+		}
+		return SubjectListResponse{Subjects: subjects, ListPage: newListPage(len(subjects), info)}, nil
+	})
+}
+
+// handleListResources lists resources page by page, e.g. ?type=document
+func (service *ABACService) handleListResources(c *gin.Context) {
+	handleList(c, "resources", func(opts storage.ListOptions) (interface{}, error) {
+		resources, info, err := service.storage.ListResources(opts)
+		if err != nil {
+			return nil, err
+		}
+		return ResourceListResponse{Resources: resources, ListPage: newListPage(len(resources), info)}, nil
+	})
+}
+
+// handleListActions lists actions page by page, e.g. ?type=write (action category)
+func (service *ABACService) handleListActions(c *gin.Context) {
+	handleList(c, "actions", func(opts storage.ListOptions) (interface{}, error) {
+		actions, info, err := service.storage.ListActions(opts)
+		if err != nil {
+			return nil, err
+		}
+		return ActionListResponse{Actions: actions, ListPage: newListPage(len(actions), info)}, nil
+	})
+}
+
+// handleListPolicies lists policies (including disabled ones) page by page, e.g. ?enabled=true
+func (service *ABACService) handleListPolicies(c *gin.Context) {
+	handleList(c, "policies", func(opts storage.ListOptions) (interface{}, error) {
+		policies, info, err := service.storage.ListPolicies(opts)
+		if err != nil {
+			return nil, err
+		}
+		return PolicyListResponse{Policies: policies, ListPage: newListPage(len(policies), info)}, nil
+	})
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/impact"
+	"abac_go_example/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ownershipTransferAuditAction is the audit log identifier of a committed ownership transfer
+const ownershipTransferAuditAction = "resource:transfer-ownership"
+
+// maxOwnershipTransferResources bounds the resources a single transfer may touch
+const maxOwnershipTransferResources = 1000
+
+// ownershipAttributes are the resource attributes a transfer may select on and set: the
+// relationship attributes that attributes.ResolveRelationships reads
+var ownershipAttributes = map[string]bool{
+	constants.ContextKeyOwnerID: true,
+	constants.ContextKeyTeamID:  true,
+}
+
+// OwnershipTransferRequestBody moves resources to a new owner or team, e.g. after a team reorg.
+// Resources are selected by ID and/or by their current ownership attributes. Without commit the
+// transfer is only previewed.
+type OwnershipTransferRequestBody struct {
+	ResourceIDs      []string              `json:"resource_ids,omitempty"`
+	From             map[string]string     `json:"from,omitempty"`        // Current ownership, e.g. {"team_id": "team-a"}
+	To               map[string]string     `json:"to" binding:"required"` // New ownership, e.g. {"team_id": "team-b"}
+	Requests         []EvaluateRequestBody `json:"requests,omitempty"`    // Optional request corpus, default sampled audit logs
+	SampleSize       int                   `json:"sample_size"`           // Audit logs to sample (default impact.DefaultSampleSize)
+	Commit           bool                  `json:"commit"`                // Apply the transfer; false only previews it
+	AcceptAccessLoss bool                  `json:"accept_access_loss"`    // Commit even when subjects lose access
+}
+
+// OwnershipTransferResponse reports the selected resources and who would lose access to them
+type OwnershipTransferResponse struct {
+	Resources    []string          `json:"resources"`
+	To           map[string]string `json:"to"`
+	Source       string            `json:"source"` // "requests" or "audit_logs"
+	Report       *impact.Report    `json:"report"`
+	LosingAccess []string          `json:"losing_access"` // Subjects with a permit that turns into a deny
+	Committed    bool              `json:"committed"`
+}
+
+// handleTransferResourceOwnership previews or commits a bulk ownership transfer. Committing a
+// transfer that revokes access answers 409 with the report unless accept_access_loss is set.
+func (service *ABACService) handleTransferResourceOwnership(c *gin.Context) {
+	var body OwnershipTransferRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	if err := validateOwnershipAttributes("to", body.To); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(body.ResourceIDs) == 0 && len(body.From) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resource_ids or from are required"})
+		return
+	}
+	if len(body.From) > 0 {
+		if err := validateOwnershipAttributes("from", body.From); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resources, ok := service.ownershipTransferResources(c, &body)
+	if !ok {
+		return
+	}
+	ids := make([]string, 0, len(resources))
+	change := &impact.Change{Resources: make(map[string]models.JSONMap, len(resources))}
+	for _, resource := range resources {
+		ids = append(ids, resource.ID)
+		updates := models.JSONMap{}
+		for key, value := range body.To {
+			updates[key] = value
+		}
+		change.Resources[resource.ID] = updates
+	}
+
+	requests, failures, err := service.impactCorpus(body.Requests, body.SampleSize)
+	if err != nil {
+		log.Printf("Failed to build impact corpus: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load requests"})
+		return
+	}
+	report, err := impact.NewAnalyzer(service.storage, nil).Analyze(change, impact.RequestsOnResources(requests, ids))
+	if err != nil {
+		log.Printf("Ownership transfer impact analysis failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Impact analysis failed"})
+		return
+	}
+	report.Failures = append(failures, report.Failures...)
+
+	response := OwnershipTransferResponse{
+		Resources:    ids,
+		To:           body.To,
+		Source:       "audit_logs",
+		Report:       report,
+		LosingAccess: subjectsLosingAccess(report),
+	}
+	if len(body.Requests) > 0 {
+		response.Source = "requests"
+	}
+	if !body.Commit {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if report.PermitToDeny > 0 && !body.AcceptAccessLoss {
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	actor := requestActor(c)
+	transferred, err := service.applyOwnershipTransfer(resources, change.Resources)
+	if len(transferred) > 0 {
+		// Resources moved before a failure stay moved, so the caches and audit log must see them
+		service.pdp.PurgeDenyCache()
+		service.recordOwnershipTransfer(actor, transferred, &response)
+	}
+	if err != nil {
+		log.Printf("Failed to transfer resource ownership: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer resource ownership", "transferred": transferred})
+		return
+	}
+
+	response.Committed = true
+	c.JSON(http.StatusOK, response)
+}
+
+// validateOwnershipAttributes checks that attrs only names ownership attributes with non-empty values
+func validateOwnershipAttributes(field string, attrs map[string]string) error {
+	if len(attrs) == 0 {
+		return fmt.Errorf("%s is required", field)
+	}
+	for key, value := range attrs {
+		if !ownershipAttributes[key] {
+			return fmt.Errorf("%s: %q is not an ownership attribute (owner_id, team_id)", field, key)
+		}
+		if value == "" {
+			return fmt.Errorf("%s: %s must not be empty", field, key)
+		}
+	}
+	return nil
+}
+
+// ownershipTransferResources resolves the resources a transfer selects, sorted by ID: the listed
+// resources still matching from, or every resource matching from. It answers the
+// request itself when a resource is missing, the lookup fails or too many are selected.
+func (service *ABACService) ownershipTransferResources(c *gin.Context, body *OwnershipTransferRequestBody) ([]*models.Resource, bool) {
+	selected := map[string]*models.Resource{}
+	for _, id := range body.ResourceIDs {
+		resource, err := service.storage.GetResource(id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found", "resource_id": id})
+			return nil, false
+		}
+		if matchesOwnership(resource, body.From) {
+			selected[resource.ID] = resource
+		}
+	}
+
+	if len(body.ResourceIDs) == 0 {
+		keys := make([]string, 0, len(body.From))
+		for key := range body.From {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		candidates, err := service.storage.FindResourcesByAttribute(keys[0], body.From[keys[0]])
+		if err != nil {
+			log.Printf("Failed to find resources by %s: %v", keys[0], err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find resources"})
+			return nil, false
+		}
+		for _, resource := range candidates {
+			if matchesOwnership(resource, body.From) {
+				selected[resource.ID] = resource
+			}
+		}
+	}
+
+	if len(selected) > maxOwnershipTransferResources {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many resources selected", "count": len(selected), "max": maxOwnershipTransferResources})
+		return nil, false
+	}
+	resources := make([]*models.Resource, 0, len(selected))
+	for _, resource := range selected {
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	return resources, true
+}
+
+// matchesOwnership reports whether resource currently has every from attribute
+func matchesOwnership(resource *models.Resource, from map[string]string) bool {
+	for key, value := range from {
+		if fmt.Sprint(resource.Attributes[key]) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// subjectsLosingAccess lists, once each, the subjects of permit-to-deny flips
+func subjectsLosingAccess(report *impact.Report) []string {
+	seen := map[string]bool{}
+	subjects := []string{}
+	for _, flip := range report.Flips {
+		if flip.Before != constants.ResultPermit || seen[flip.SubjectID] {
+			continue
+		}
+		seen[flip.SubjectID] = true
+		subjects = append(subjects, flip.SubjectID)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// applyOwnershipTransfer merges updates into each resource's attributes and returns the IDs
+// transferred before any failure
+func (service *ABACService) applyOwnershipTransfer(resources []*models.Resource, updates map[string]models.JSONMap) ([]string, error) {
+	transferred := []string{}
+	for _, resource := range resources {
+		updated := *resource
+		updated.Attributes = models.JSONMap{}
+		for key, value := range resource.Attributes {
+			updated.Attributes[key] = value
+		}
+		for key, value := range updates[resource.ID] {
+			updated.Attributes[key] = value
+		}
+		if err := service.storage.UpdateResource(&updated); err != nil {
+			return transferred, fmt.Errorf("failed to update resource %s: %w", resource.ID, err)
+		}
+		transferred = append(transferred, resource.ID)
+	}
+	return transferred, nil
+}
+
+// recordOwnershipTransfer writes a committed transfer to the audit log
+func (service *ABACService) recordOwnershipTransfer(actor string, transferred []string, response *OwnershipTransferResponse) {
+	auditLog := &models.AuditLog{
+		RequestID: fmt.Sprintf("ownership_transfer_%d", time.Now().UnixNano()),
+		SubjectID: actor,
+		ActionID:  ownershipTransferAuditAction,
+		Decision:  constants.ResultPermit,
+		Context: models.JSONMap{
+			"resources":      transferred,
+			"to":             response.To,
+			"permit_to_deny": response.Report.PermitToDeny,
+			"losing_access":  response.LosingAccess,
+		},
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", ownershipTransferAuditAction, err)
+	}
+}
@@ -0,0 +1,731 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"abac_go_example/attributes"
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/evaluator/path"
+	"abac_go_example/impact"
+	"abac_go_example/models"
+	"abac_go_example/schema"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyResponse wraps a policy; its revision is also sent as ETag
+type PolicyResponse struct {
+	Policy *models.Policy `json:"policy"`
+}
+
+// PolicyStatsResponse holds the evaluation statistics of a policy
+type PolicyStatsResponse struct {
+	PolicyID string            `json:"policy_id"`
+	Stats    *core.PolicyStats `json:"stats"`
+}
+
+// PolicyCoverageResponse holds the condition coverage of a policy; Coverage is nil when coverage is disabled
+type PolicyCoverageResponse struct {
+	PolicyID string               `json:"policy_id"`
+	Enabled  bool                 `json:"enabled"`
+	Coverage *core.PolicyCoverage `json:"coverage,omitempty"`
+}
+
+// MissingAttributesResponse reports missing attribute counters; Attributes is empty when telemetry is disabled
+type MissingAttributesResponse struct {
+	Enabled    bool                         `json:"enabled"`
+	Attributes []core.MissingAttributeCount `json:"attributes"`
+}
+
+// PolicyQuarantineResponse lists quarantined policies; Policies is empty when quarantine is disabled
+type PolicyQuarantineResponse struct {
+	Enabled  bool                     `json:"enabled"`
+	Policies []core.QuarantinedPolicy `json:"policies"`
+}
+
+// DenyCacheStatsResponse reports negative cache counters; Stats is nil when the cache is disabled
+type DenyCacheStatsResponse struct {
+	Enabled bool                 `json:"enabled"`
+	Stats   *core.DenyCacheStats `json:"stats,omitempty"`
+}
+
+// AttributeCacheStatsResponse reports attribute cache counters; Stats is nil when the cache is disabled
+type AttributeCacheStatsResponse struct {
+	Enabled bool                            `json:"enabled"`
+	Stats   *attributes.AttributeCacheStats `json:"stats,omitempty"`
+}
+
+// InvalidateAttributeCacheRequestBody selects the cached lookups to drop; empty fields match everything
+type InvalidateAttributeCacheRequestBody struct {
+	EntityType string `json:"entity_type"` // "subject", "resource" or "action"
+	ID         string `json:"id"`
+}
+
+// InvalidateAttributeCacheResponse echoes the invalidated entries
+type InvalidateAttributeCacheResponse struct {
+	Invalidated bool   `json:"invalidated"`
+	EntityType  string `json:"entity_type"`
+	ID          string `json:"id"`
+}
+
+// PolicyChangesResponse lists the change audit trail of a policy, newest first
+type PolicyChangesResponse struct {
+	PolicyID string                 `json:"policy_id"`
+	Count    int                    `json:"count"`
+	Changes  []*models.PolicyChange `json:"changes"`
+}
+
+// DeletedPoliciesResponse lists soft-deleted policies, most recently deleted first
+type DeletedPoliciesResponse struct {
+	Count    int              `json:"count"`
+	Policies []*models.Policy `json:"policies"`
+}
+
+// RegexReportResponse lists the stored policies with StringRegex patterns the PDP refuses to compile;
+// their conditions never match until the patterns are fixed
+type RegexReportResponse struct {
+	Checked  int                 `json:"checked"` // Policies inspected, including disabled ones
+	Policies []PolicyRegexReport `json:"policies"`
+}
+
+// PolicyRegexReport is the invalid regex patterns of one policy
+type PolicyRegexReport struct {
+	PolicyID string             `json:"policy_id"`
+	Enabled  bool               `json:"enabled"`
+	Errors   []PolicyRegexError `json:"errors"`
+}
+
+// PolicyImpactResponse reports the decision flips of a proposed change; Source is "requests" or "audit_logs"
+type PolicyImpactResponse struct {
+	Source string         `json:"source"`
+	Report *impact.Report `json:"report"`
+}
+
+// handlePolicyStats returns evaluation statistics for a single policy
+func (service *ABACService) handlePolicyStats(c *gin.Context) {
+	policyID := c.Param("id")
+	if _, ok := service.lookupPolicy(c, policyID); !ok {
+		return
+	}
+
+	// Policies that were never evaluated report zero counters (dead policy candidates)
+	stats, ok := service.pdp.GetPolicyStats(policyID)
+	if !ok {
+		stats = &core.PolicyStats{PolicyID: policyID, Statements: []core.StatementStats{}}
+	}
+
+	c.JSON(http.StatusOK, PolicyStatsResponse{
+		PolicyID: policyID,
+		Stats:    stats,
+	})
+}
+
+// handlePolicyCoverage returns the condition coverage of a single policy
+func (service *ABACService) handlePolicyCoverage(c *gin.Context) {
+	policyID := c.Param("id")
+	policy, ok := service.lookupPolicy(c, policyID)
+	if !ok {
+		return
+	}
+
+	coverage, enabled := service.pdp.GetPolicyCoverage(policyID)
+	if !enabled {
+		c.JSON(http.StatusOK, PolicyCoverageResponse{PolicyID: policyID, Enabled: false})
+		return
+	}
+	if coverage == nil {
+		// Never evaluated since startup: no condition was exercised
+		coverage = &core.PolicyCoverage{PolicyID: policyID, Revision: policy.Revision, Conditions: []core.ConditionCoverage{}}
+	}
+
+	c.JSON(http.StatusOK, PolicyCoverageResponse{
+		PolicyID: policyID,
+		Enabled:  true,
+		Coverage: coverage,
+	})
+}
+
+// handleMissingAttributes returns the condition attribute paths that resolved to nothing, highest count first
+func (service *ABACService) handleMissingAttributes(c *gin.Context) {
+	counts, enabled := service.pdp.GetMissingAttributes()
+	if counts == nil {
+		counts = []core.MissingAttributeCount{}
+	}
+	c.JSON(http.StatusOK, MissingAttributesResponse{
+		Enabled:    enabled,
+		Attributes: counts,
+	})
+}
+
+// handlePolicyQuarantine returns the policies skipped by evaluation as too slow, most recent first
+func (service *ABACService) handlePolicyQuarantine(c *gin.Context) {
+	policies, enabled := service.pdp.GetQuarantinedPolicies()
+	if policies == nil {
+		policies = []core.QuarantinedPolicy{}
+	}
+	c.JSON(http.StatusOK, PolicyQuarantineResponse{
+		Enabled:  enabled,
+		Policies: policies,
+	})
+}
+
+// handleReleaseQuarantine evaluates a quarantined policy again
+func (service *ABACService) handleReleaseQuarantine(c *gin.Context) {
+	policyID := c.Param("id")
+	if !service.pdp.ReleaseQuarantine(policyID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy is not quarantined", "policy_id": policyID})
+		return
+	}
+	log.Printf("Policy quarantine released policy_id=%s reason=api", policyID)
+	c.Status(http.StatusNoContent)
+}
+
+// lookupPolicy returns the stored policy with policyID, writing an error response when it cannot be found
+func (service *ABACService) lookupPolicy(c *gin.Context, policyID string) (*models.Policy, bool) {
+	policies, err := service.storage.GetPolicies()
+	if err != nil {
+		log.Printf("Failed to load policies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policies"})
+		return nil, false
+	}
+
+	for _, policy := range policies {
+		if policy.ID == policyID {
+			return policy, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found", "policy_id": policyID})
+	return nil, false
+}
+
+// handleDenyCacheStats returns negative cache counters (suppressed evaluations, size, evictions)
+func (service *ABACService) handleDenyCacheStats(c *gin.Context) {
+	stats, enabled := service.pdp.GetDenyCacheStats()
+	if !enabled {
+		c.JSON(http.StatusOK, DenyCacheStatsResponse{Enabled: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, DenyCacheStatsResponse{
+		Enabled: true,
+		Stats:   stats,
+	})
+}
+
+// handleAttributeCacheStats returns attribute cache counters (hits, misses, invalidations, size)
+func (service *ABACService) handleAttributeCacheStats(c *gin.Context) {
+	stats, enabled := service.pdp.GetAttributeCacheStats()
+	if !enabled {
+		c.JSON(http.StatusOK, AttributeCacheStatsResponse{Enabled: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, AttributeCacheStatsResponse{
+		Enabled: true,
+		Stats:   stats,
+	})
+}
+
+// handleInvalidateAttributeCache drops cached subject, resource or action lookups.
+// The body selects {"entity_type": "subject|resource|action", "id": "..."}; omitted fields match everything.
+func (service *ABACService) handleInvalidateAttributeCache(c *gin.Context) {
+	var body InvalidateAttributeCacheRequestBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+	switch body.EntityType {
+	case "", attributes.EntitySubject, attributes.EntityResource, attributes.EntityAction:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_type must be subject, resource or action"})
+		return
+	}
+
+	service.pdp.InvalidateAttributeCache(body.EntityType, body.ID)
+	c.JSON(http.StatusOK, InvalidateAttributeCacheResponse{Invalidated: true, EntityType: body.EntityType, ID: body.ID})
+}
+
+// handleCompileStats returns the policy compilation mode, compile counters and durations, and the last warm-up
+func (service *ABACService) handleCompileStats(c *gin.Context) {
+	c.JSON(http.StatusOK, service.pdp.GetCompileStats())
+}
+
+// handlePolicySchema publishes the JSON Schema of the policy document format for external tooling
+func (service *ABACService) handlePolicySchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", schema.PolicySchema())
+}
+
+// handleCreatePolicy validates an incoming policy document against the policy schema and stores it
+func (service *ABACService) handleCreatePolicy(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	if errs := schema.ValidatePolicy(body); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy does not match schema", "errors": errs})
+		return
+	}
+
+	var policy models.Policy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy", "details": err.Error()})
+		return
+	}
+	if errs := service.subjectTypeErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy compares user.subject_type with unknown subject types", "errors": errs})
+		return
+	}
+	if errs := transformErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy uses invalid attribute transforms", "errors": errs})
+		return
+	}
+	if errs := regexErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy has invalid or too complex regular expressions", "errors": errs})
+		return
+	}
+
+	policies, err := service.storage.GetPolicies()
+	if err != nil {
+		log.Printf("Failed to load policies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policies"})
+		return
+	}
+	for _, existing := range policies {
+		if existing.ID == policy.ID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Policy already exists", "policy_id": policy.ID})
+			return
+		}
+	}
+	if !requireApproval(c, policy.ID, policy.Enabled) {
+		return
+	}
+
+	if err := service.storage.CreatePolicy(&policy); err != nil {
+		log.Printf("Failed to create policy %s: %v", policy.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create policy"})
+		return
+	}
+	service.recordPolicyChange(c, models.PolicyChangeCreate, nil, &policy)
+
+	// Cached denies may no longer hold under the new policy set
+	service.pdp.PurgeDenyCache()
+
+	c.Header("ETag", policyETag(policy.Revision))
+	c.JSON(http.StatusCreated, PolicyResponse{Policy: &policy})
+}
+
+// PolicyRegexError is a StringRegex pattern of a statement that does not compile or exceeds the regex limits
+type PolicyRegexError struct {
+	Statement int    `json:"statement"`
+	Sid       string `json:"sid,omitempty"`
+	conditions.InvalidRegex
+}
+
+// regexErrors returns the StringRegex patterns of policy that the PDP would refuse to compile
+func regexErrors(policy *models.Policy) []PolicyRegexError {
+	var invalid []PolicyRegexError
+	for i, statement := range policy.Statement {
+		for _, regex := range conditions.InvalidRegexes(statement.Condition) {
+			invalid = append(invalid, PolicyRegexError{Statement: i, Sid: statement.Sid, InvalidRegex: regex})
+		}
+	}
+	return invalid
+}
+
+// transformErrors returns the condition keys of policy calling unknown functions or with malformed arguments
+func transformErrors(policy *models.Policy) []path.InvalidTransform {
+	var invalid []path.InvalidTransform
+	for _, statement := range policy.Statement {
+		invalid = append(invalid, path.InvalidTransformKeys(statement.Condition)...)
+	}
+	return invalid
+}
+
+// policyETag returns the entity tag of a policy revision
+func policyETag(revision int64) string {
+	return `"` + strconv.FormatInt(revision, 10) + `"`
+}
+
+// parseIfMatch reads the revision from an If-Match header produced by policyETag (weak tags accepted)
+func parseIfMatch(header string) (int64, error) {
+	tag := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fmt.Errorf("If-Match must be a single policy ETag such as \"3\"")
+	}
+	revision, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("If-Match must be a single policy ETag such as \"3\"")
+	}
+	return revision, nil
+}
+
+// handleGetPolicy returns a policy with its revision as ETag, for use in If-Match on update
+func (service *ABACService) handleGetPolicy(c *gin.Context) {
+	policy, err := service.storage.GetPolicy(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found", "policy_id": c.Param("id")})
+			return
+		}
+		log.Printf("Failed to load policy %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policy"})
+		return
+	}
+
+	c.Header("ETag", policyETag(policy.Revision))
+	c.JSON(http.StatusOK, PolicyResponse{Policy: policy})
+}
+
+// handleUpdatePolicy replaces a policy. The revision the edit is based on must be sent as If-Match
+// (or "revision" in the body); a stale revision is rejected with 412 so concurrent edits are not lost.
+func (service *ABACService) handleUpdatePolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	if errs := schema.ValidatePolicy(body); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy does not match schema", "errors": errs})
+		return
+	}
+
+	var policy models.Policy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy", "details": err.Error()})
+		return
+	}
+	if errs := service.subjectTypeErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy compares user.subject_type with unknown subject types", "errors": errs})
+		return
+	}
+	if errs := transformErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy uses invalid attribute transforms", "errors": errs})
+		return
+	}
+	if errs := regexErrors(&policy); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy has invalid or too complex regular expressions", "errors": errs})
+		return
+	}
+	if policy.ID != policyID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Policy id does not match the URL", "policy_id": policyID})
+		return
+	}
+
+	if header := c.GetHeader("If-Match"); header != "" {
+		revision, err := parseIfMatch(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header", "details": err.Error()})
+			return
+		}
+		if policy.Revision != 0 && policy.Revision != revision {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match and body revision disagree"})
+			return
+		}
+		policy.Revision = revision
+	}
+	if policy.Revision == 0 {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Policy updates require If-Match or revision"})
+		return
+	}
+
+	existing, err := service.storage.GetPolicy(policyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found", "policy_id": policyID})
+			return
+		}
+		log.Printf("Failed to load policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policy"})
+		return
+	}
+	if !requireApproval(c, policyID, existing.Enabled || policy.Enabled) {
+		return
+	}
+	policy.CreatedAt = existing.CreatedAt
+
+	if err := service.storage.UpdatePolicy(&policy); err != nil {
+		var conflict *storage.RevisionConflictError
+		if errors.As(err, &conflict) {
+			c.Header("ETag", policyETag(conflict.Current))
+			c.JSON(http.StatusPreconditionFailed, gin.H{
+				"error":            "Policy was modified by someone else",
+				"policy_id":        policyID,
+				"current_revision": conflict.Current,
+			})
+			return
+		}
+		log.Printf("Failed to update policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update policy"})
+		return
+	}
+	service.recordPolicyChange(c, models.PolicyChangeUpdate, existing, &policy)
+
+	// Cached denies may no longer hold under the new policy set
+	service.pdp.PurgeDenyCache()
+
+	c.Header("ETag", policyETag(policy.Revision))
+	c.JSON(http.StatusOK, PolicyResponse{Policy: &policy})
+}
+
+// handleDeletePolicy removes a policy; storages implementing SoftDeleteStore keep it restorable
+func (service *ABACService) handleDeletePolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	existing, err := service.storage.GetPolicy(policyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found", "policy_id": policyID})
+			return
+		}
+		log.Printf("Failed to load policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policy"})
+		return
+	}
+	if !requireApproval(c, policyID, existing.Enabled) {
+		return
+	}
+
+	if err := service.storage.DeletePolicy(policyID); err != nil {
+		log.Printf("Failed to delete policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete policy"})
+		return
+	}
+	service.recordPolicyChange(c, models.PolicyChangeDelete, existing, nil)
+
+	// A removed Deny statement can turn cached denies into permits
+	service.pdp.PurgeDenyCache()
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleRegexReport reports the StringRegex compile errors of stored policies, e.g. policies saved
+// before pattern validation or loaded from bundles and manifests
+func (service *ABACService) handleRegexReport(c *gin.Context) {
+	policies, err := service.storage.GetPolicies()
+	if err != nil {
+		log.Printf("Failed to load policies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policies"})
+		return
+	}
+
+	response := RegexReportResponse{Checked: len(policies), Policies: []PolicyRegexReport{}}
+	for _, policy := range policies {
+		if errs := regexErrors(policy); len(errs) > 0 {
+			response.Policies = append(response.Policies, PolicyRegexReport{PolicyID: policy.ID, Enabled: policy.Enabled, Errors: errs})
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// handleListDeletedPolicies lists soft-deleted policies that can be restored
+func (service *ABACService) handleListDeletedPolicies(c *gin.Context) {
+	softDeleteStore, ok := service.storage.(storage.SoftDeleteStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not keep deleted policies"})
+		return
+	}
+
+	policies, err := softDeleteStore.ListDeletedPolicies()
+	if err != nil {
+		log.Printf("Failed to list deleted policies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list deleted policies"})
+		return
+	}
+	c.JSON(http.StatusOK, DeletedPoliciesResponse{Count: len(policies), Policies: policies})
+}
+
+// handleRestorePolicy undeletes a soft-deleted policy so the PDP evaluates it again
+func (service *ABACService) handleRestorePolicy(c *gin.Context) {
+	softDeleteStore, ok := service.storage.(storage.SoftDeleteStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not keep deleted policies"})
+		return
+	}
+
+	policyID := c.Param("id")
+	if err := softDeleteStore.RestorePolicy(policyID); err != nil {
+		if errors.Is(err, storage.ErrNotDeleted) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No deleted policy with this ID", "policy_id": policyID})
+			return
+		}
+		log.Printf("Failed to restore policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore policy"})
+		return
+	}
+	policy, err := service.storage.GetPolicy(policyID)
+	if err != nil {
+		log.Printf("Failed to load restored policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policy"})
+		return
+	}
+	service.recordPolicyChange(c, models.PolicyChangeRestore, nil, policy)
+
+	// The restored policy can turn cached denies into permits
+	service.pdp.PurgeDenyCache()
+
+	c.Header("ETag", policyETag(policy.Revision))
+	c.JSON(http.StatusOK, PolicyResponse{Policy: policy})
+}
+
+// handlePolicyChanges lists the change audit trail of a policy, newest first (?limit=, default 100)
+func (service *ABACService) handlePolicyChanges(c *gin.Context) {
+	changeStore, ok := service.storage.(storage.PolicyChangeStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not record policy changes"})
+		return
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	policyID := c.Param("id")
+	changes, err := changeStore.GetPolicyChanges(policyID, limit)
+	if err != nil {
+		log.Printf("Failed to load changes of policy %s: %v", policyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load policy changes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PolicyChangesResponse{
+		PolicyID: policyID,
+		Count:    len(changes),
+		Changes:  changes,
+	})
+}
+
+// actorContextKey is the gin context key holding the subject ID authorized by ABACMiddleware
+const actorContextKey = "abac_actor"
+
+// constraintsContextKey is the gin context key holding the *models.Constraints of a filtered permit;
+// handlers pass them to pep.ApplyConstraints
+const constraintsContextKey = "abac_constraints"
+
+// requestConstraints returns the constraints of the filtered permit ABACMiddleware granted, or nil
+func requestConstraints(c *gin.Context) *models.Constraints {
+	constraints, _ := c.Value(constraintsContextKey).(*models.Constraints)
+	return constraints
+}
+
+// requestActor returns who is making an admin request: the authorized subject, else the X-User-ID header
+func requestActor(c *gin.Context) string {
+	if actor := c.GetString(actorContextKey); actor != "" {
+		return actor
+	}
+	if actor := c.GetHeader("X-User-ID"); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
+
+// recordPolicyChange appends a policy change to the audit trail. The policy write already
+// succeeded, so a failure is logged rather than returned to the client.
+func (service *ABACService) recordPolicyChange(c *gin.Context, action string, before, after *models.Policy) {
+	service.recordPolicyChangeAs(requestActor(c), action, before, after)
+}
+
+// recordPolicyChangeAs appends a policy change made by actor outside of a request to the audit trail
+func (service *ABACService) recordPolicyChangeAs(actor, action string, before, after *models.Policy) {
+	if err := storage.RecordPolicyChange(service.storage, action, actor, before, after); err != nil {
+		log.Printf("Failed to record %s of policy: %v", action, err)
+	}
+}
+
+// PolicyImpactRequestBody is a proposed policy change and the requests to replay against it.
+// Without requests, the most recent audit-log decisions are sampled.
+type PolicyImpactRequestBody struct {
+	Policies   []json.RawMessage     `json:"policies"`    // Policies to create or replace, validated against the schema
+	Delete     []string              `json:"delete"`      // IDs of policies to remove
+	Requests   []EvaluateRequestBody `json:"requests"`    // Optional request corpus
+	SampleSize int                   `json:"sample_size"` // Audit logs to sample (default impact.DefaultSampleSize)
+}
+
+// handlePolicyImpact reports decision flips a policy change would cause before it is saved
+func (service *ABACService) handlePolicyImpact(c *gin.Context) {
+	var body PolicyImpactRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	change := &impact.Change{Delete: body.Delete}
+	for i, raw := range body.Policies {
+		if errs := schema.ValidatePolicy(raw); len(errs) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Policy does not match schema", "index": i, "errors": errs})
+			return
+		}
+		var policy models.Policy
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy", "index": i, "details": err.Error()})
+			return
+		}
+		change.Upsert = append(change.Upsert, &policy)
+	}
+
+	requests, failures, err := service.impactCorpus(body.Requests, body.SampleSize)
+	if err != nil {
+		log.Printf("Failed to build impact corpus: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load requests"})
+		return
+	}
+
+	report, err := impact.NewAnalyzer(service.storage, nil).Analyze(change, requests)
+	if err != nil {
+		log.Printf("Policy impact analysis failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Impact analysis failed"})
+		return
+	}
+	report.Failures = append(failures, report.Failures...)
+
+	source := "audit_logs"
+	if len(body.Requests) > 0 {
+		source = "requests"
+	}
+	c.JSON(http.StatusOK, PolicyImpactResponse{
+		Source: source,
+		Report: report,
+	})
+}
+
+// impactCorpus builds the requests replayed by an impact analysis: bodies when given, otherwise
+// up to sampleSize recent audit-log decisions
+func (service *ABACService) impactCorpus(bodies []EvaluateRequestBody, sampleSize int) ([]*models.EvaluationRequest, []impact.Failure, error) {
+	if len(bodies) == 0 {
+		return impact.RequestsFromAuditLogs(service.storage, service.subjectFactory.CreateFromSubjectID, sampleSize)
+	}
+
+	requests := make([]*models.EvaluationRequest, 0, len(bodies))
+	failures := []impact.Failure{}
+	for i := range bodies {
+		request, err := service.buildEvaluationRequest(&bodies[i])
+		if err != nil {
+			failures = append(failures, impact.Failure{
+				RequestID: bodies[i].RequestID,
+				Error:     fmt.Sprintf("subject %s: %v", bodies[i].SubjectID, err),
+			})
+			continue
+		}
+		requests = append(requests, request)
+	}
+	return requests, failures, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log identifiers of policy schedule changes
+const (
+	policyScheduleAuditCreate = "policy-schedule:create"
+	policyScheduleAuditDelete = "policy-schedule:delete"
+)
+
+// PolicyToggleRequestBody enables or disables the policies selected by ID or tag
+type PolicyToggleRequestBody struct {
+	PolicyIDs []string `json:"policy_ids,omitempty"`
+	Tags      []string `json:"tags,omitempty"` // Policies carrying any of the tags
+	Enabled   bool     `json:"enabled"`
+}
+
+// PolicyToggleResponse reports a bulk enable or disable
+type PolicyToggleResponse struct {
+	Enabled bool     `json:"enabled"`
+	Matched []string `json:"matched"`
+	Changed []string `json:"changed"` // Matched policies that were not already in the requested state
+}
+
+// PolicyScheduleRequestBody plans a bulk enable or disable, once at run_at or whenever cron fires
+type PolicyScheduleRequestBody struct {
+	ID          string     `json:"id"` // Generated when empty
+	Description string     `json:"description,omitempty"`
+	PolicyIDs   []string   `json:"policy_ids,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Enabled     bool       `json:"enabled"`
+	Cron        string     `json:"cron,omitempty"` // Five-field cron expression, e.g. "0 18 * * FRI"
+	RunAt       *time.Time `json:"run_at,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"` // IANA location of cron; empty is UTC
+}
+
+// PolicyScheduleListResponse lists policy schedules
+type PolicyScheduleListResponse struct {
+	Count     int                      `json:"count"`
+	Schedules []*models.PolicySchedule `json:"schedules"`
+}
+
+// policyScheduleStore returns the storage's PolicyScheduleStore, answering 501 when it has none
+func (service *ABACService) policyScheduleStore(c *gin.Context) (storage.PolicyScheduleStore, bool) {
+	scheduleStore, ok := service.storage.(storage.PolicyScheduleStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support policy schedules"})
+	}
+	return scheduleStore, ok
+}
+
+// handleTogglePolicies enables or disables every policy selected by ID or tag at once
+func (service *ABACService) handleTogglePolicies(c *gin.Context) {
+	var body PolicyToggleRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	selector := models.PolicySelector{PolicyIDs: body.PolicyIDs, Tags: body.Tags}
+	if selector.IsEmpty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "policy_ids or tags are required"})
+		return
+	}
+
+	actor := requestActor(c)
+	result, err := storage.SetPoliciesEnabled(service.storage, selector, body.Enabled, actor)
+	if result != nil && len(result.Changed) > 0 {
+		// Policies changed before a failure stay changed, so the caches and audit log must see them
+		service.pdp.PurgeDenyCache()
+		service.recordPolicyToggle(actor, selector, body.Enabled, result)
+	}
+	if err != nil {
+		log.Printf("Failed to toggle policies: %v", err)
+		response := gin.H{"error": "Failed to toggle policies"}
+		if result != nil {
+			response["changed"] = result.Changed
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, PolicyToggleResponse{
+		Enabled: body.Enabled,
+		Matched: result.Matched,
+		Changed: result.Changed,
+	})
+}
+
+// handleListPolicySchedules lists policy schedules with their last and next runs
+func (service *ABACService) handleListPolicySchedules(c *gin.Context) {
+	scheduleStore, ok := service.policyScheduleStore(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := scheduleStore.ListPolicySchedules()
+	if err != nil {
+		log.Printf("Failed to list policy schedules: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list policy schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PolicyScheduleListResponse{
+		Count:     len(schedules),
+		Schedules: schedules,
+	})
+}
+
+// handleCreatePolicySchedule plans a bulk enable or disable, applied by the policy schedule job
+func (service *ABACService) handleCreatePolicySchedule(c *gin.Context) {
+	scheduleStore, ok := service.policyScheduleStore(c)
+	if !ok {
+		return
+	}
+
+	var body PolicyScheduleRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	schedule := &models.PolicySchedule{
+		ID:          body.ID,
+		Description: body.Description,
+		PolicyIDs:   body.PolicyIDs,
+		Tags:        body.Tags,
+		Enabled:     body.Enabled,
+		Cron:        body.Cron,
+		RunAt:       body.RunAt,
+		Timezone:    body.Timezone,
+		CreatedBy:   requestActor(c),
+	}
+	if schedule.ID == "" {
+		schedule.ID = fmt.Sprintf("sched_%d", time.Now().UnixNano())
+	} else if _, err := scheduleStore.GetPolicySchedule(schedule.ID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Policy schedule already exists", "schedule_id": schedule.ID})
+		return
+	}
+
+	if err := scheduleStore.CreatePolicySchedule(schedule); err != nil {
+		if errors.Is(err, storage.ErrInvalidPolicySchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy schedule", "details": err.Error()})
+			return
+		}
+		log.Printf("Failed to create policy schedule %s: %v", schedule.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create policy schedule"})
+		return
+	}
+	service.recordPolicySchedule(policyScheduleAuditCreate, schedule.CreatedBy, schedule)
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// handleDeletePolicySchedule cancels a policy schedule; policies it already toggled stay as they are
+func (service *ABACService) handleDeletePolicySchedule(c *gin.Context) {
+	scheduleStore, ok := service.policyScheduleStore(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := scheduleStore.GetPolicySchedule(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy schedule not found", "schedule_id": c.Param("id")})
+		return
+	}
+	if err := scheduleStore.DeletePolicySchedule(schedule.ID); err != nil {
+		log.Printf("Failed to delete policy schedule %s: %v", schedule.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete policy schedule"})
+		return
+	}
+	service.recordPolicySchedule(policyScheduleAuditDelete, requestActor(c), schedule)
+
+	c.Status(http.StatusNoContent)
+}
+
+// recordPolicyToggle writes a bulk enable or disable to the audit log under the action the
+// policy schedule job uses, so manual and scheduled toggles read alike
+func (service *ABACService) recordPolicyToggle(actor string, selector models.PolicySelector, enabled bool, result *storage.PolicyToggleResult) {
+	action := storage.PolicyAuditBulkDisable
+	if enabled {
+		action = storage.PolicyAuditBulkEnable
+	}
+	auditLog := &models.AuditLog{
+		RequestID: fmt.Sprintf("policy_toggle_%d", time.Now().UnixNano()),
+		SubjectID: actor,
+		ActionID:  action,
+		Decision:  constants.ResultPermit,
+		Context: models.JSONMap{
+			"policy_ids": selector.PolicyIDs,
+			"tags":       selector.Tags,
+			"matched":    result.Matched,
+			"changed":    result.Changed,
+		},
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", action, err)
+	}
+}
+
+// recordPolicySchedule writes a policy schedule change to the audit log
+func (service *ABACService) recordPolicySchedule(action, actor string, schedule *models.PolicySchedule) {
+	auditLog := &models.AuditLog{
+		RequestID:  fmt.Sprintf("policy_schedule_%d", time.Now().UnixNano()),
+		SubjectID:  actor,
+		ResourceID: schedule.ID,
+		ActionID:   action,
+		Decision:   constants.ResultPermit,
+		Context: models.JSONMap{
+			"policy_ids": []string(schedule.PolicyIDs),
+			"tags":       []string(schedule.Tags),
+			"enabled":    schedule.Enabled,
+			"cron":       schedule.Cron,
+			"timezone":   schedule.Timezone,
+		},
+	}
+	if schedule.RunAt != nil {
+		auditLog.Context["run_at"] = schedule.RunAt.Format(time.RFC3339)
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", action, err)
+	}
+}
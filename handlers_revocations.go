@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log identifiers of revocation list changes
+const (
+	revocationAuditCreate = "revocation:create"
+	revocationAuditDelete = "revocation:delete"
+)
+
+// RevocationRequestBody suspends the access of a subject or a session
+type RevocationRequestBody struct {
+	ID        string     `json:"id"` // Generated when empty
+	SubjectID string     `json:"subject_id,omitempty"`
+	SessionID string     `json:"session_id,omitempty"`
+	Reason    string     `json:"reason" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Omitted keeps the revocation until it is lifted
+}
+
+// RevocationListResponse lists the revocation list
+type RevocationListResponse struct {
+	Count       int                  `json:"count"`
+	Revocations []*models.Revocation `json:"revocations"`
+}
+
+// revocationStore returns the storage's RevocationStore, answering 501 when it has none
+func (service *ABACService) revocationStore(c *gin.Context) (storage.RevocationStore, bool) {
+	revocationStore, ok := service.storage.(storage.RevocationStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support revocations"})
+	}
+	return revocationStore, ok
+}
+
+// handleListRevocations lists the revocation list, expired entries included
+func (service *ABACService) handleListRevocations(c *gin.Context) {
+	revocationStore, ok := service.revocationStore(c)
+	if !ok {
+		return
+	}
+
+	revocations, err := revocationStore.ListRevocations()
+	if err != nil {
+		log.Printf("Failed to list revocations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list revocations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, RevocationListResponse{
+		Count:       len(revocations),
+		Revocations: revocations,
+	})
+}
+
+// handleCreateRevocation cuts a subject or session off immediately, regardless of policies
+func (service *ABACService) handleCreateRevocation(c *gin.Context) {
+	revocationStore, ok := service.revocationStore(c)
+	if !ok {
+		return
+	}
+
+	var body RevocationRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	revocation := &models.Revocation{
+		ID:        body.ID,
+		SubjectID: body.SubjectID,
+		SessionID: body.SessionID,
+		Reason:    body.Reason,
+		CreatedBy: requestActor(c),
+		ExpiresAt: body.ExpiresAt,
+	}
+	if revocation.ID == "" {
+		revocation.ID = fmt.Sprintf("rev_%d", time.Now().UnixNano())
+	} else if _, err := revocationStore.GetRevocation(revocation.ID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Revocation already exists", "revocation_id": revocation.ID})
+		return
+	}
+
+	if err := revocationStore.CreateRevocation(revocation); err != nil {
+		if errors.Is(err, storage.ErrInvalidRevocation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revocation", "details": err.Error()})
+			return
+		}
+		log.Printf("Failed to create revocation %s: %v", revocation.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create revocation"})
+		return
+	}
+	// Storage events also invalidate the list, but only when an event bus is attached
+	service.pdp.InvalidateRevocations()
+	service.recordRevocation(revocationAuditCreate, revocation.CreatedBy, revocation)
+	log.Printf("Revocation %s created by %s: subject=%q session=%q (%q)", revocation.ID, revocation.CreatedBy,
+		revocation.SubjectID, revocation.SessionID, revocation.Reason)
+
+	c.JSON(http.StatusCreated, revocation)
+}
+
+// handleDeleteRevocation lifts a revocation
+func (service *ABACService) handleDeleteRevocation(c *gin.Context) {
+	revocationStore, ok := service.revocationStore(c)
+	if !ok {
+		return
+	}
+
+	revocation, err := revocationStore.GetRevocation(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revocation not found", "revocation_id": c.Param("id")})
+		return
+	}
+	if err := revocationStore.DeleteRevocation(revocation.ID); err != nil {
+		log.Printf("Failed to delete revocation %s: %v", revocation.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete revocation"})
+		return
+	}
+	service.pdp.InvalidateRevocations()
+	service.recordRevocation(revocationAuditDelete, requestActor(c), revocation)
+
+	c.Status(http.StatusNoContent)
+}
+
+// recordRevocation writes a revocation list change to the audit log. The change has already
+// taken effect, so a storage failure is logged rather than undoing it.
+func (service *ABACService) recordRevocation(action, actor string, revocation *models.Revocation) {
+	context := models.JSONMap{
+		"subject_id": revocation.SubjectID,
+		"session_id": revocation.SessionID,
+		"reason":     revocation.Reason,
+	}
+	if revocation.ExpiresAt != nil {
+		context["expires_at"] = revocation.ExpiresAt.Format(time.RFC3339)
+	}
+	auditLog := &models.AuditLog{
+		RequestID:  fmt.Sprintf("revocation_%d", time.Now().UnixNano()),
+		SubjectID:  actor,
+		ResourceID: revocation.ID,
+		ActionID:   action,
+		Decision:   constants.ResultPermit,
+		Context:    context,
+	}
+	if err := service.audit.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit %s: %v", action, err)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubjectSearchResponse lists the subjects matching an attribute query
+type SubjectSearchResponse struct {
+	Key      string            `json:"key"`
+	Value    interface{}       `json:"value"`
+	Count    int               `json:"count"`
+	Subjects []*models.Subject `json:"subjects"`
+}
+
+// ResourceSearchResponse lists the resources matching an attribute query (Key, Value) or a tag
+type ResourceSearchResponse struct {
+	Key       string             `json:"key,omitempty"`
+	Value     interface{}        `json:"value,omitempty"`
+	Tag       string             `json:"tag,omitempty"`
+	Count     int                `json:"count"`
+	Resources []*models.Resource `json:"resources"`
+}
+
+// parseAttributeQuery reads ?key=&value= from an attribute search request.
+// The value is parsed as a JSON literal (3, true, "3") and falls back to a plain string.
+func parseAttributeQuery(c *gin.Context) (string, interface{}, bool) {
+	key := c.Query("key")
+	rawValue, hasValue := c.GetQuery("value")
+	if key == "" || !hasValue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameters 'key' and 'value' are required"})
+		return "", nil, false
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+		value = rawValue
+	}
+	return key, value, true
+}
+
+// handleSearchSubjects lists subjects whose attribute matches the query, e.g. ?key=clearance&value=confidential
+func (service *ABACService) handleSearchSubjects(c *gin.Context) {
+	key, value, ok := parseAttributeQuery(c)
+	if !ok {
+		return
+	}
+
+	subjects, err := service.storage.FindSubjectsByAttribute(key, value)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidAttributeKey) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute key", "key": key})
+			return
+		}
+		log.Printf("Failed to search subjects by %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search subjects"})
+		return
+	}
+
+	c.JSON(http.StatusOK, SubjectSearchResponse{
+		Key:      key,
+		Value:    value,
+		Count:    len(subjects),
+		Subjects: subjects,
+	})
+}
+
+// handleSearchResources lists resources whose attribute matches the query,
+// or resources carrying a tag when ?tag=pii=true is given
+func (service *ABACService) handleSearchResources(c *gin.Context) {
+	if tag, hasTag := c.GetQuery("tag"); hasTag {
+		service.searchResourcesByTag(c, tag)
+		return
+	}
+
+	key, value, ok := parseAttributeQuery(c)
+	if !ok {
+		return
+	}
+
+	resources, err := service.storage.FindResourcesByAttribute(key, value)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidAttributeKey) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute key", "key": key})
+			return
+		}
+		log.Printf("Failed to search resources by %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResourceSearchResponse{
+		Key:       key,
+		Value:     value,
+		Count:     len(resources),
+		Resources: resources,
+	})
+}
+
+// searchResourcesByTag lists resources carrying tag
+func (service *ABACService) searchResourcesByTag(c *gin.Context, tag string) {
+	resources, err := service.storage.FindResourcesByTag(tag)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource tag", "tag": tag})
+			return
+		}
+		log.Printf("Failed to search resources by tag %s: %v", tag, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResourceSearchResponse{
+		Tag:       tag,
+		Count:     len(resources),
+		Resources: resources,
+	})
+}
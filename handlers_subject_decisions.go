@@ -0,0 +1,87 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults of GET /api/v1/subjects/:id/decisions
+const (
+	defaultSubjectDecisionWindow = 7 * 24 * time.Hour
+	defaultTopDeniedResources    = 5
+)
+
+// SubjectDecisionsResponse is a subject's recent decisions with their aggregation
+type SubjectDecisionsResponse struct {
+	SubjectID string                          `json:"subject_id"`
+	Since     time.Time                       `json:"since"`
+	Summary   *storage.SubjectDecisionSummary `json:"summary"` // Over the returned decisions
+	Decisions []*models.AuditLog              `json:"decisions"`
+}
+
+// handleSubjectDecisions returns a subject's recent decisions from the audit logs with its deny
+// rate and most denied resources, for support when a user reports "I can't access X"
+func (service *ABACService) handleSubjectDecisions(c *gin.Context) {
+	decisionStore, ok := service.storage.(storage.SubjectDecisionStore)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support decision history"})
+		return
+	}
+
+	limit, ok := queryPositiveInt(c, "limit", storage.DefaultSubjectDecisionLimit)
+	if !ok {
+		return
+	}
+	if limit > storage.MaxSubjectDecisionLimit {
+		limit = storage.MaxSubjectDecisionLimit
+	}
+	top, ok := queryPositiveInt(c, "top", defaultTopDeniedResources)
+	if !ok {
+		return
+	}
+	since := time.Now().Add(-defaultSubjectDecisionWindow)
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 time"})
+			return
+		}
+		since = parsed
+	}
+
+	subjectID := c.Param("id")
+	decisions, err := decisionStore.SubjectDecisions(subjectID, since, limit)
+	if err != nil {
+		log.Printf("Failed to load decisions of subject %s: %v", subjectID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load decisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, SubjectDecisionsResponse{
+		SubjectID: subjectID,
+		Since:     since.UTC(),
+		Summary:   storage.SummarizeSubjectDecisions(decisions, top),
+		Decisions: decisions,
+	})
+}
+
+// queryPositiveInt reads an optional positive integer query parameter, answering 400 when it is invalid
+func queryPositiveInt(c *gin.Context, name string, fallback int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a positive integer"})
+		return 0, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+
+	"abac_go_example/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubjectTypesResponse lists the registered subject types and the attribute enums they define.
+// Enforced is false when no registry is configured and any subject type is accepted.
+type SubjectTypesResponse struct {
+	Enforced bool                           `json:"enforced"`
+	Types    []models.SubjectTypeDefinition `json:"types"`
+	Enums    map[string][]string            `json:"enums"` // e.g. {"user.subject_type": ["anonymous", "device", "service", "user"]}
+}
+
+// handleSubjectTypes publishes the subject type registry for policy authoring tools
+func (service *ABACService) handleSubjectTypes(c *gin.Context) {
+	response := SubjectTypesResponse{Types: []models.SubjectTypeDefinition{}, Enums: map[string][]string{}}
+	if service.subjectTypes != nil {
+		response.Enforced = true
+		response.Types = service.subjectTypes.Definitions()
+		response.Enums[models.SubjectTypeAttribute] = service.subjectTypes.Enum()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// subjectTypeErrors returns the conditions of policy comparing user.subject_type with unregistered types
+func (service *ABACService) subjectTypeErrors(policy *models.Policy) []string {
+	if service.subjectTypes == nil {
+		return nil
+	}
+	var messages []string
+	for _, err := range service.subjectTypes.ValidatePolicy(policy) {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
@@ -0,0 +1,2177 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/adminauth"
+	"abac_go_example/attributes"
+	"abac_go_example/bundle"
+	"abac_go_example/client"
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/importer"
+	"abac_go_example/models"
+	"abac_go_example/openapi"
+	"abac_go_example/pep"
+	"abac_go_example/sink"
+	"abac_go_example/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter builds a router with the evaluation API backed by MockStorage
+func newTestRouter(t *testing.T) (*gin.Engine, *storage.MockStorage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-001",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "ReadDocuments",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "document:read"},
+					Resource: models.JSONActionResource{Single: "api:documents:*"},
+				},
+			},
+		},
+	})
+
+	decisions := sink.NewBroadcaster()
+	config := core.DefaultPDPConfig()
+	config.DecisionSink = decisions
+	config.DenyCache = core.DefaultDenyCacheConfig()
+	config.ConditionCoverage = true
+	config.MissingAttributes = &core.MissingAttributeConfig{}
+	config.Quarantine = &core.QuarantineConfig{Threshold: time.Hour}
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, config))
+	service.decisions = decisions
+
+	router := gin.New()
+	apiV1 := router.Group("/api/v1")
+	apiV1.POST("/evaluate", service.handleEvaluate)
+	apiV1.POST("/explain", service.handleExplain)
+	apiV1.POST("/evaluate/sessions", service.handlePrepareSession)
+	apiV1.DELETE("/evaluate/sessions/:id", service.handleReleaseSession)
+	apiV1.GET("/decisions/stream", service.handleDecisionStream)
+	apiV1.POST("/policies", service.handleCreatePolicy)
+	apiV1.GET("/policies/:id", service.handleGetPolicy)
+	apiV1.PUT("/policies/:id", service.handleUpdatePolicy)
+	apiV1.DELETE("/policies/:id", service.handleDeletePolicy)
+	apiV1.GET("/policies/:id/coverage", service.handlePolicyCoverage)
+	apiV1.GET("/attributes/missing", service.handleMissingAttributes)
+	apiV1.GET("/policies/quarantine", service.handlePolicyQuarantine)
+	apiV1.DELETE("/policies/:id/quarantine", service.handleReleaseQuarantine)
+	apiV1.GET("/policies/fingerprint", service.handlePolicyFingerprint)
+	apiV1.GET("/policies/:id/changes", service.handlePolicyChanges)
+	apiV1.GET("/policies/deleted", service.handleListDeletedPolicies)
+	apiV1.GET("/policies/regex-report", service.handleRegexReport)
+	apiV1.POST("/policies/:id/restore", service.handleRestorePolicy)
+	apiV1.POST("/policies/impact", service.handlePolicyImpact)
+	apiV1.GET("/schema/policy", service.handlePolicySchema)
+	apiV1.GET("/deny-cache/stats", service.handleDenyCacheStats)
+	apiV1.GET("/compile/stats", service.handleCompileStats)
+	apiV1.GET("/subjects/search", service.handleSearchSubjects)
+	apiV1.GET("/subjects/:id/decisions", service.handleSubjectDecisions)
+	apiV1.GET("/resources/search", service.handleSearchResources)
+	apiV1.POST("/resources/transfer-ownership", service.handleTransferResourceOwnership)
+	apiV1.POST("/import/:kind", service.handleImport)
+	apiV1.GET("/lockdown", service.handleGetLockdown)
+	apiV1.PUT("/lockdown", service.handleSetLockdown)
+	apiV1.DELETE("/lockdown", service.handleLiftLockdown)
+	apiV1.GET("/exceptions", service.handleListExceptions)
+	apiV1.POST("/exceptions", service.handleCreateException)
+	apiV1.DELETE("/exceptions/:id", service.handleDeleteException)
+	apiV1.GET("/revocations", service.handleListRevocations)
+	apiV1.POST("/revocations", service.handleCreateRevocation)
+	apiV1.DELETE("/revocations/:id", service.handleDeleteRevocation)
+	apiV1.GET("/elevations", service.handleListElevations)
+	apiV1.POST("/elevations", service.handleCreateElevation)
+	apiV1.DELETE("/elevations/:id", service.handleDeleteElevation)
+	apiV1.POST("/policies/bulk-toggle", service.handleTogglePolicies)
+	apiV1.GET("/policy-schedules", service.handleListPolicySchedules)
+	apiV1.POST("/policy-schedules", service.handleCreatePolicySchedule)
+	apiV1.DELETE("/policy-schedules/:id", service.handleDeletePolicySchedule)
+	apiV1.GET("/subjects", service.handleListSubjects)
+	apiV1.GET("/policies", service.handleListPolicies)
+
+	return router, mockStorage
+}
+
+// postJSON sends body as JSON to path and returns the recorder
+func postJSON(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleEvaluate(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+		expectedResult string
+	}{
+		{
+			name:           "permit",
+			body:           map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read"},
+			expectedStatus: http.StatusOK,
+			expectedResult: "permit",
+		},
+		{
+			name:           "deny",
+			body:           map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:delete"},
+			expectedStatus: http.StatusOK,
+			expectedResult: "deny",
+		},
+		{
+			name:           "missing action",
+			body:           map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown subject",
+			body:           map[string]interface{}{"subject_id": "nobody", "resource_id": "api:documents:test.pdf", "action": "document:read"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(router, "/api/v1/evaluate", tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedResult == "" {
+				return
+			}
+
+			var response struct {
+				Decision models.Decision `json:"decision"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid response JSON: %v", err)
+			}
+			if response.Decision.Result != tt.expectedResult {
+				t.Errorf("Expected %s, got %s", tt.expectedResult, response.Decision.Result)
+			}
+		})
+	}
+}
+
+func TestHandleExplain(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	w := postJSON(router, "/api/v1/explain", map[string]interface{}{
+		"request_id":  "explain-http-001",
+		"subject_id":  "user-001",
+		"resource_id": "api:documents:test.pdf",
+		"action":      "document:read",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		RequestID   string             `json:"request_id"`
+		Explanation models.Explanation `json:"explanation"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if response.RequestID != "explain-http-001" {
+		t.Errorf("Expected request ID to be echoed, got %q", response.RequestID)
+	}
+	if len(response.Explanation.Statements) != 1 || !response.Explanation.Statements[0].Matched {
+		t.Errorf("Expected matched statement trace, got %+v", response.Explanation.Statements)
+	}
+}
+
+func TestHandleExplainDOT(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	w := postJSON(router, "/api/v1/explain?format=dot", map[string]interface{}{
+		"subject_id":  "user-001",
+		"resource_id": "api:documents:test.pdf",
+		"action":      "document:read",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/vnd.graphviz") {
+		t.Errorf("Expected Graphviz content type, got %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(w.Body.String(), "digraph explain {") {
+		t.Errorf("Expected DOT body, got %s", w.Body.String())
+	}
+}
+
+func TestHandlePolicyCoverage(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/v1/policies/pol-001/coverage")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response PolicyCoverageResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if !response.Enabled || response.Coverage == nil || response.Coverage.Evaluations != 0 {
+		t.Errorf("Expected empty coverage before any evaluation, got %+v", response)
+	}
+
+	postJSON(router, "/api/v1/evaluate", map[string]interface{}{
+		"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read",
+	})
+	w = get("/api/v1/policies/pol-001/coverage")
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if response.Coverage == nil || response.Coverage.Evaluations != 1 {
+		t.Errorf("Expected 1 recorded evaluation, got %+v", response.Coverage)
+	}
+
+	if w := get("/api/v1/policies/missing/coverage"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown policy, got %d", w.Code)
+	}
+}
+
+func TestHandleMissingAttributes(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-001",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:       "ReadDocuments",
+					Effect:    "Allow",
+					Action:    models.JSONActionResource{Single: "document:read"},
+					Resource:  models.JSONActionResource{Single: "api:documents:*"},
+					Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.cost_centre": "CC-42"}},
+				},
+			},
+		},
+	})
+
+	postJSON(router, "/api/v1/evaluate", map[string]interface{}{
+		"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read",
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/attributes/missing", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response MissingAttributesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if !response.Enabled || len(response.Attributes) != 1 ||
+		response.Attributes[0].Path != "user.cost_centre" || response.Attributes[0].Statement != "ReadDocuments" {
+		t.Errorf("Unexpected missing attributes: %+v", response)
+	}
+}
+
+func TestHandlePolicyQuarantine(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/policies/quarantine", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response PolicyQuarantineResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if !response.Enabled || response.Policies == nil || len(response.Policies) != 0 {
+		t.Errorf("Expected no quarantined policies, got %+v", response)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/policies/pol-001/quarantine", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 releasing a policy that is not quarantined, got %d", w.Code)
+	}
+}
+
+func TestHandlePolicyFingerprint(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/policies/fingerprint", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var fingerprint core.PolicyFingerprint
+	if err := json.Unmarshal(w.Body.Bytes(), &fingerprint); err != nil {
+		t.Fatalf("Invalid response JSON: %v", err)
+	}
+	if fingerprint.Hash == "" || fingerprint.Policies != 1 {
+		t.Fatalf("Unexpected fingerprint: %+v", fingerprint)
+	}
+	etag := w.Header().Get("ETag")
+	if etag != `"`+fingerprint.Hash+`"` {
+		t.Errorf("Expected the hash as ETag, got %q", etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/policies/fingerprint", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for the current ETag, got %d", w.Code)
+	}
+}
+
+func TestHandleDecisionStream(t *testing.T) {
+	router, _ := newTestRouter(t)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/decisions/stream?result=deny", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("Expected text/event-stream, got %q", resp.Header.Get("Content-Type"))
+	}
+
+	// The permit is filtered out; the deny is streamed
+	for _, action := range []string{"document:read", "document:delete"} {
+		postJSON(router, "/api/v1/evaluate", map[string]interface{}{
+			"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": action,
+		})
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		var event sink.DecisionEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event); err != nil {
+			t.Fatalf("Invalid event payload %q: %v", line, err)
+		}
+		if event.Result != "deny" || event.Action != "document:delete" {
+			t.Errorf("Unexpected streamed event: %+v", event)
+		}
+		return
+	}
+	t.Fatalf("Stream ended without events: %v", scanner.Err())
+}
+
+func TestHandleDenyCacheStats(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	body := map[string]interface{}{
+		"subject_id":  "user-001",
+		"resource_id": "api:documents:test.pdf",
+		"action":      "document:delete",
+	}
+	for i := 0; i < 3; i++ {
+		if w := postJSON(router, "/api/v1/evaluate", body); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/deny-cache/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response struct {
+		Enabled bool                `json:"enabled"`
+		Stats   core.DenyCacheStats `json:"stats"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if !response.Enabled || response.Stats.Suppressed != 2 || response.Stats.Size != 1 {
+		t.Errorf("Unexpected deny cache stats: %s", w.Body.String())
+	}
+}
+
+func TestHandleCompileStats(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	body := map[string]interface{}{
+		"subject_id":  "user-001",
+		"resource_id": "api:documents:test.pdf",
+		"action":      "document:read",
+	}
+	if w := postJSON(router, "/api/v1/evaluate", body); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/compile/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var stats core.CompileStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if stats.Mode != core.CompileLazy || stats.Compilations == 0 {
+		t.Errorf("Unexpected compile stats: %s", w.Body.String())
+	}
+}
+
+func TestHandlePolicySchema(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schema/policy", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/schema+json") {
+		t.Errorf("Unexpected content type %q", w.Header().Get("Content-Type"))
+	}
+	var schemaDoc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &schemaDoc); err != nil || schemaDoc["title"] == nil {
+		t.Errorf("Expected a JSON Schema document, got %s", w.Body.String())
+	}
+}
+
+func TestHandleCreatePolicy(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	validPolicy := map[string]interface{}{
+		"id":          "pol-new",
+		"policy_name": "New Policy",
+		"version":     "2024-10-21",
+		"enabled":     true,
+		"statement": []interface{}{
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+
+	w := postJSON(router, "/api/v1/policies", validPolicy)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := mockStorage.GetPolicy("pol-new"); err != nil {
+		t.Errorf("Expected policy to be stored: %v", err)
+	}
+
+	if w := postJSON(router, "/api/v1/policies", validPolicy); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate policy, got %d", w.Code)
+	}
+
+	invalidPolicy := map[string]interface{}{
+		"id":          "pol-invalid",
+		"policy_name": "Invalid",
+		"version":     "1",
+		"statement": []interface{}{
+			map[string]interface{}{"Effect": "Permit", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	w = postJSON(router, "/api/v1/policies", invalidPolicy)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for schema violation, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "/statement/0/Effect") {
+		t.Errorf("Expected error path in response, got %s", w.Body.String())
+	}
+
+	unknownTransform := map[string]interface{}{
+		"id":          "pol-transform",
+		"policy_name": "Transform",
+		"version":     "2024-10-21",
+		"enabled":     true,
+		"statement": []interface{}{
+			map[string]interface{}{
+				"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*",
+				"Condition": map[string]interface{}{"StringEquals": map[string]interface{}{"reverse(user.email)": "x"}},
+			},
+		},
+	}
+	w = postJSON(router, "/api/v1/policies", unknownTransform)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `unknown function \"reverse\"`) {
+		t.Errorf("Expected 400 for an unknown transform function, got %d: %s", w.Code, w.Body.String())
+	}
+
+	pathologicalRegex := map[string]interface{}{
+		"id":          "pol-regex",
+		"policy_name": "Regex",
+		"version":     "2024-10-21",
+		"enabled":     true,
+		"statement": []interface{}{
+			map[string]interface{}{
+				"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*",
+				"Condition": map[string]interface{}{"Or": []interface{}{
+					map[string]interface{}{"StringRegex": map[string]interface{}{"user.email": "(\\w{1,30}){1,30}"}},
+					map[string]interface{}{"StringRegex": map[string]interface{}{"user.name": `(\w+)\1`}},
+				}},
+			},
+		},
+	}
+	w = postJSON(router, "/api/v1/policies", pathologicalRegex)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "pattern too complex") || !strings.Contains(w.Body.String(), "invalid escape sequence") {
+		t.Errorf("Expected 400 with a compile-error report, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleRegexReport(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	mockStorage.CreatePolicy(&models.Policy{
+		ID:         "pol-legacy-regex",
+		PolicyName: "Legacy Regex",
+		Version:    "1",
+		Statement: []models.PolicyStatement{{
+			Sid:       "Pattern",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: "document:read"},
+			Resource:  models.JSONActionResource{Single: "api:documents:*"},
+			Condition: map[string]interface{}{"StringRegex": map[string]interface{}{"user.email": "(?=admin)"}},
+		}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/policies/regex-report", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report RegexReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Policies) != 1 || report.Policies[0].PolicyID != "pol-legacy-regex" || report.Policies[0].Errors[0].Sid != "Pattern" ||
+		report.Policies[0].Errors[0].Key != "user.email" || report.Checked < 2 {
+		t.Errorf("Unexpected regex report %+v", report)
+	}
+}
+
+func TestHandleSubjectTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	service := newABACService(mockStorage, core.NewPolicyDecisionPoint(mockStorage))
+	router := gin.New()
+	router.GET("/api/v1/subject-types", service.handleSubjectTypes)
+	router.POST("/api/v1/policies", service.handleCreatePolicy)
+
+	getTypes := func() SubjectTypesResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/subject-types", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response SubjectTypesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 with types, got %d: %s", w.Code, w.Body.String())
+		}
+		return response
+	}
+	if response := getTypes(); response.Enforced || len(response.Types) != 0 {
+		t.Errorf("Expected no registry by default, got %+v", response)
+	}
+
+	service.subjectTypes = models.DefaultSubjectTypeRegistry()
+	response := getTypes()
+	if !response.Enforced || len(response.Types) != 4 ||
+		strings.Join(response.Enums[models.SubjectTypeAttribute], ",") != "anonymous,device,service,user" {
+		t.Errorf("Expected the default registry, got %+v", response)
+	}
+
+	policy := func(id, subjectType string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "policy_name": id, "version": "1", "enabled": true,
+			"statement": []interface{}{map[string]interface{}{
+				"Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*",
+				"Condition": map[string]interface{}{"StringEquals": map[string]interface{}{"user.subject_type": subjectType}},
+			}},
+		}
+	}
+	if w := postJSON(router, "/api/v1/policies", policy("pol-device", "device")); w.Code != http.StatusCreated {
+		t.Errorf("Expected a registered subject type to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	w := postJSON(router, "/api/v1/policies", policy("pol-robot", "robot"))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `unknown subject type \"robot\"`) {
+		t.Errorf("Expected an unregistered subject type to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleAttributeSearch(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	mockStorage.CreateSubject(&models.Subject{ID: "sub-1", Attributes: models.JSONMap{"clearance": "confidential", "level": 3}})
+	mockStorage.CreateSubject(&models.Subject{ID: "sub-2", Attributes: models.JSONMap{"clearance": "public", "level": 1}})
+	mockStorage.CreateResource(&models.Resource{ID: "res-1", Attributes: models.JSONMap{"classification": "confidential"}, Tags: models.JSONStringSlice{"pii=true"}})
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedCode  int
+		expectedCount int
+	}{
+		{"subjects by string", "/api/v1/subjects/search?key=clearance&value=confidential", http.StatusOK, 1},
+		{"subjects by number", "/api/v1/subjects/search?key=level&value=3", http.StatusOK, 1},
+		{"number quoted as string", "/api/v1/subjects/search?key=level&value=%223%22", http.StatusOK, 0},
+		{"resources", "/api/v1/resources/search?key=classification&value=confidential", http.StatusOK, 1},
+		{"missing value", "/api/v1/subjects/search?key=clearance", http.StatusBadRequest, 0},
+		{"invalid key", "/api/v1/resources/search?key=%22&value=x", http.StatusBadRequest, 0},
+		{"resources by tag", "/api/v1/resources/search?tag=pii%3Dtrue", http.StatusOK, 1},
+		{"resources by missing tag", "/api/v1/resources/search?tag=pii%3Dfalse", http.StatusOK, 0},
+		{"empty tag", "/api/v1/resources/search?tag=", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Count int `json:"count"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid response: %v", err)
+			}
+			if response.Count != tt.expectedCount {
+				t.Errorf("Expected %d results, got %d: %s", tt.expectedCount, response.Count, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlePolicyImpact(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	denyRead := map[string]interface{}{
+		"id":          "pol-deny-read",
+		"policy_name": "Deny Read",
+		"version":     "2024-10-21",
+		"enabled":     true,
+		"statement": []map[string]interface{}{
+			{"Sid": "DenyRead", "Effect": "Deny", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	corpus := []map[string]interface{}{
+		{"request_id": "req-read", "subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read"},
+		{"request_id": "req-unknown", "subject_id": "nobody", "resource_id": "api:documents:test.pdf", "action": "document:read"},
+	}
+
+	type impactResponse struct {
+		Source string `json:"source"`
+		Report struct {
+			Evaluated    int `json:"evaluated"`
+			PermitToDeny int `json:"permit_to_deny"`
+			Failures     []struct {
+				RequestID string `json:"request_id"`
+			} `json:"failures"`
+		} `json:"report"`
+	}
+
+	w := postJSON(router, "/api/v1/policies/impact", map[string]interface{}{
+		"policies": []interface{}{denyRead},
+		"requests": corpus,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response impactResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if response.Source != "requests" || response.Report.Evaluated != 1 || response.Report.PermitToDeny != 1 {
+		t.Errorf("Unexpected report: %s", w.Body.String())
+	}
+	if len(response.Report.Failures) != 1 || response.Report.Failures[0].RequestID != "req-unknown" {
+		t.Errorf("Expected unknown subject to be reported as a failure: %s", w.Body.String())
+	}
+
+	// Without a corpus, recent audit-log decisions are replayed
+	mockStorage.LogAudit(&models.AuditLog{RequestID: "audit-1", SubjectID: "user-001", ResourceID: "api:documents:test.pdf", ActionID: "document:read", Decision: "permit"})
+	w = postJSON(router, "/api/v1/policies/impact", map[string]interface{}{"delete": []string{"pol-001"}})
+	response = impactResponse{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || response.Source != "audit_logs" || response.Report.PermitToDeny != 1 {
+		t.Errorf("Unexpected audit replay response %d: %s", w.Code, w.Body.String())
+	}
+
+	// Proposed policies are validated against the schema
+	w = postJSON(router, "/api/v1/policies/impact", map[string]interface{}{"policies": []interface{}{map[string]interface{}{"id": "bad"}}})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid policy, got %d", w.Code)
+	}
+
+	// Nothing was saved
+	if policies, _ := mockStorage.GetPolicies(); len(policies) != 1 || policies[0].ID != "pol-001" {
+		t.Errorf("Impact analysis must not modify policies, got %v", policies)
+	}
+}
+
+func TestHandleTransferResourceOwnership(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	for _, id := range []string{"api:documents:plan.pdf", "api:documents:budget.pdf"} {
+		mockStorage.CreateResource(&models.Resource{ID: id, ResourceID: id, ResourceType: "document",
+			Attributes: models.JSONMap{"team_id": "team-a"}})
+	}
+	teamRead := &models.Policy{
+		ID:         "pol-team-read",
+		PolicyName: "Team A Read",
+		Version:    "2024-10-21",
+		Enabled:    true,
+		Statement: models.JSONStatements{{
+			Sid:       "TeamARead",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: "document:read"},
+			Resource:  models.JSONActionResource{Single: "api:documents:*"},
+			Condition: models.JSONMap{"StringEquals": map[string]interface{}{"resource.team_id": "team-a"}},
+		}},
+	}
+	mockStorage.SetPolicies([]*models.Policy{teamRead})
+
+	type transferResponse struct {
+		Resources    []string `json:"resources"`
+		LosingAccess []string `json:"losing_access"`
+		Committed    bool     `json:"committed"`
+		Report       struct {
+			PermitToDeny int `json:"permit_to_deny"`
+		} `json:"report"`
+	}
+	transfer := func(extra map[string]interface{}) (int, transferResponse) {
+		body := map[string]interface{}{
+			"from": map[string]string{"team_id": "team-a"},
+			"to":   map[string]string{"team_id": "team-b"},
+			"requests": []map[string]interface{}{
+				{"request_id": "req-read", "subject_id": "user-001", "resource_id": "api:documents:plan.pdf", "action": "document:read"},
+				{"request_id": "req-other", "subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read"},
+			},
+		}
+		for key, value := range extra {
+			body[key] = value
+		}
+		w := postJSON(router, "/api/v1/resources/transfer-ownership", body)
+		var response transferResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	// The preview selects both team-a resources and reports the lost read
+	code, response := transfer(nil)
+	if code != http.StatusOK || len(response.Resources) != 2 || response.Report.PermitToDeny != 1 || response.Committed {
+		t.Fatalf("Unexpected preview %d: %+v", code, response)
+	}
+	if len(response.LosingAccess) != 1 || response.LosingAccess[0] != "user-001" {
+		t.Errorf("Expected user-001 to lose access, got %v", response.LosingAccess)
+	}
+
+	// Committing an access loss needs accept_access_loss
+	if code, _ := transfer(map[string]interface{}{"commit": true}); code != http.StatusConflict {
+		t.Errorf("Expected 409 without accept_access_loss, got %d", code)
+	}
+	if resource, _ := mockStorage.GetResource("api:documents:plan.pdf"); resource.Attributes["team_id"] != "team-a" {
+		t.Fatalf("Rejected transfer modified the resource: %v", resource.Attributes)
+	}
+
+	code, response = transfer(map[string]interface{}{"commit": true, "accept_access_loss": true})
+	if code != http.StatusOK || !response.Committed {
+		t.Fatalf("Expected committed transfer, got %d: %+v", code, response)
+	}
+	for _, id := range []string{"api:documents:plan.pdf", "api:documents:budget.pdf"} {
+		if resource, _ := mockStorage.GetResource(id); resource.Attributes["team_id"] != "team-b" {
+			t.Errorf("Expected %s moved to team-b, got %v", id, resource.Attributes)
+		}
+	}
+
+	for name, body := range map[string]map[string]interface{}{
+		"no selection":  {"to": map[string]string{"team_id": "team-b"}},
+		"not ownership": {"resource_ids": []string{"api:documents:plan.pdf"}, "to": map[string]string{"classification": "public"}},
+		"empty owner":   {"resource_ids": []string{"api:documents:plan.pdf"}, "to": map[string]string{"owner_id": ""}},
+		"missing to":    {"resource_ids": []string{"api:documents:plan.pdf"}},
+	} {
+		if w := postJSON(router, "/api/v1/resources/transfer-ownership", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, w.Code)
+		}
+	}
+	w := postJSON(router, "/api/v1/resources/transfer-ownership", map[string]interface{}{
+		"resource_ids": []string{"missing"}, "to": map[string]string{"team_id": "team-b"}})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown resource, got %d", w.Code)
+	}
+}
+
+func TestHandleImport(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	body := strings.Join([]string{
+		`{"id": "res-import-1", "resource_type": "document", "tags": ["pii=true"]}`,
+		``,
+		`{"id": "res-import-2", "resource_type": "document"}`,
+		`{"id": "res-import-3"}`,
+		`not json`,
+	}, "\n")
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/import/resources", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Report importer.Report `json:"report"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if response.Report.Imported != 2 || response.Report.Failed != 2 {
+		t.Fatalf("Expected 2 imported and 2 failed, got %+v", response.Report)
+	}
+	if response.Report.Errors[0].Line != 4 || response.Report.Errors[0].ID != "res-import-3" || response.Report.Errors[1].Line != 5 {
+		t.Errorf("Unexpected record errors: %+v", response.Report.Errors)
+	}
+	if _, err := mockStorage.GetResource("res-import-1"); err != nil {
+		t.Errorf("Expected res-import-1 to be stored: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/import/actions", strings.NewReader("{}")))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown import kind, got %d", w.Code)
+	}
+}
+
+func TestHandleList(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	for i := 1; i <= 3; i++ {
+		mockStorage.CreateSubject(&models.Subject{ID: fmt.Sprintf("sub-%d", i), SubjectType: "user"})
+	}
+	mockStorage.CreatePolicy(&models.Policy{ID: "pol-pci", Tags: models.JSONStringSlice{"domain=pci"}, Owner: "team-payments"})
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedCode  int
+		expectedCount int
+		hasNext       bool
+	}{
+		{"first page", "/api/v1/subjects?limit=2", http.StatusOK, 2, true},
+		{"filtered", "/api/v1/subjects?type=service", http.StatusOK, 0, false},
+		{"policies by enabled", "/api/v1/policies?enabled=true", http.StatusOK, 1, false},
+		{"invalid limit", "/api/v1/subjects?limit=ten", http.StatusBadRequest, 0, false},
+		{"invalid sort", "/api/v1/subjects?sort=attributes", http.StatusBadRequest, 0, false},
+		{"unsupported filter", "/api/v1/subjects?enabled=true", http.StatusBadRequest, 0, false},
+		{"invalid updated_since", "/api/v1/policies?updated_since=yesterday", http.StatusBadRequest, 0, false},
+		{"policies by tag", "/api/v1/policies?tag=domain%3Dpci", http.StatusOK, 1, false},
+		{"policies by owner", "/api/v1/policies?owner=team-payments&tag=sox", http.StatusOK, 0, false},
+		{"blank tag", "/api/v1/policies?tag=%20", http.StatusBadRequest, 0, false},
+		{"tag on subjects", "/api/v1/subjects?tag=pci", http.StatusBadRequest, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Count      int    `json:"count"`
+				NextCursor string `json:"next_cursor"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid response: %v", err)
+			}
+			if response.Count != tt.expectedCount || (response.NextCursor != "") != tt.hasNext {
+				t.Errorf("Expected count %d (next=%v), got %s", tt.expectedCount, tt.hasNext, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleUpdatePolicy(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	policy := map[string]interface{}{
+		"id":          "pol-edit",
+		"policy_name": "Editable",
+		"version":     "2024-10-21",
+		"statement": []interface{}{
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	w := postJSON(router, "/api/v1/policies", policy)
+	if w.Code != http.StatusCreated || w.Header().Get("ETag") != `"1"` {
+		t.Fatalf("Expected 201 with ETag \"1\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	put := func(ifMatch string, body map[string]interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/policies/pol-edit", bytes.NewReader(payload))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Two admins load revision 1; the first update wins, the second is rejected
+	policy["description"] = "first admin"
+	if w := put(`"1"`, policy); w.Code != http.StatusOK || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("Expected 200 with ETag \"2\", got %d %q: %s", w.Code, w.Header().Get("ETag"), w.Body.String())
+	}
+	policy["description"] = "second admin"
+	if w := put(`"1"`, policy); w.Code != http.StatusPreconditionFailed || w.Header().Get("ETag") != `"2"` {
+		t.Errorf("Expected 412 with current ETag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+	if stored, _ := mockStorage.GetPolicy("pol-edit"); stored.Description != "first admin" {
+		t.Errorf("Expected first update to be kept, got %q", stored.Description)
+	}
+
+	// Revision in the payload works like If-Match
+	policy["revision"] = 2
+	if w := put("", policy); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for body revision, got %d: %s", w.Code, w.Body.String())
+	}
+	delete(policy, "revision")
+
+	tests := []struct {
+		name         string
+		ifMatch      string
+		expectedCode int
+	}{
+		{"missing precondition", "", http.StatusPreconditionRequired},
+		{"malformed If-Match", "3", http.StatusBadRequest},
+		{"weak ETag", `W/"3"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if w := put(tt.ifMatch, policy); w.Code != tt.expectedCode {
+			t.Errorf("%s: expected %d, got %d: %s", tt.name, tt.expectedCode, w.Code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/policies/pol-edit", nil))
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"4"` {
+		t.Errorf("Expected GET to return ETag \"4\", got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestHandleSubjectDecisions(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	// Evaluations are audited as decisions of their subject
+	for i, action := range []string{"document:read", "document:delete", "document:delete"} {
+		w := postJSON(router, "/api/v1/evaluate", map[string]interface{}{"request_id": fmt.Sprintf("req-%d", i),
+			"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": action})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	mockStorage.LogAudit(&models.AuditLog{RequestID: "req-other", SubjectID: "user-002", ResourceID: "api:documents:test.pdf",
+		ActionID: "document:read", Decision: "deny", Source: models.AuditSourceEvaluation})
+	// Administrative actions of user-001 are not its decisions
+	mockStorage.LogAudit(&models.AuditLog{RequestID: "elevation_1", SubjectID: "user-001", ResourceID: "elev-1",
+		ActionID: "elevation:create", Decision: "permit"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subjects/user-001/decisions", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response SubjectDecisionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Decisions) != 3 || response.Decisions[0].RequestID != "req-2" {
+		t.Errorf("Expected the 3 decisions of user-001, newest first, got %+v", response.Decisions)
+	}
+	summary := response.Summary
+	if summary.Denies != 2 || len(summary.TopDeniedResources) != 1 || summary.TopDeniedResources[0].ResourceID != "api:documents:test.pdf" {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+
+	for _, query := range []string{"limit=0", "top=x", "since=yesterday"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subjects/user-001/decisions?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subjects/user-001/decisions?since=2999-01-01T00:00:00Z", nil))
+	response = SubjectDecisionsResponse{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Decisions) != 0 || response.Summary.Total != 0 {
+		t.Errorf("Expected no decisions after since, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandlePrepareSession(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	w := postJSON(router, "/api/v1/evaluate/sessions", PrepareSessionRequestBody{SessionID: "sess-1", SubjectID: "user-001"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var prepared attributes.PreparedSubject
+	if err := json.Unmarshal(w.Body.Bytes(), &prepared); err != nil || prepared.SubjectID != "user-001" || prepared.ExpiresAt.IsZero() {
+		t.Errorf("Unexpected prepared subject %+v (%v)", prepared, err)
+	}
+	if w := postJSON(router, "/api/v1/evaluate/sessions", PrepareSessionRequestBody{SessionID: "sess-2", SubjectID: "nobody"}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown subject, got %d", w.Code)
+	}
+
+	// Evaluations of the session no longer load the subject
+	mockStorage.DeleteUser("user-001")
+	body := EvaluateRequestBody{SubjectID: "user-001", ResourceID: "api:documents:test.pdf", Action: "document:read",
+		Session: &models.SessionInfo{SessionID: "sess-1"}}
+	w = postJSON(router, "/api/v1/evaluate", body)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"result":"permit"`) {
+		t.Errorf("Expected the prepared session to be evaluated, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/evaluate/sessions/sess-1", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", w.Code)
+	}
+	if w := postJSON(router, "/api/v1/evaluate", body); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the subject lookup after release, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/evaluate/sessions/sess-1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a released session, got %d", w.Code)
+	}
+}
+
+// TestEvaluationSessionAuthorization tests that only PEPs permitted pdp:session prepare and release
+// session contexts, also when admin tokens are configured
+func TestEvaluationSessionAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateUser(&models.User{ID: "pep-gateway", Username: "gateway", Email: "gateway@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: constants.ActionEvaluationSession, ActionName: constants.ActionEvaluationSession})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/evaluate/sessions", ResourceID: "/api/v1/evaluate/sessions"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/evaluate/sessions/sess-1", ResourceID: "/api/v1/evaluate/sessions/sess-1"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-pep-sessions",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:       "GatewayManagesSessions",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: constants.ActionEvaluationSession},
+			Resource:  models.JSONActionResource{Single: "*"},
+			Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.email": "gateway@company.com"}},
+		}},
+	}})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	var err error
+	service.adminAuth, err = adminauth.NewAuthenticator([]adminauth.TokenEntry{
+		{Name: "ops", Token: "admin-token", Roles: []adminauth.Role{adminauth.RoleAdmin}},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	router := gin.New()
+	service.registerRoutes(router)
+
+	send := func(method, path, userID string) *httptest.ResponseRecorder {
+		var payload []byte
+		if method == http.MethodPost {
+			payload, _ = json.Marshal(PrepareSessionRequestBody{SessionID: "sess-1", SubjectID: "user-001"})
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		userID   string
+		expected int
+	}{
+		{"anonymous caller cannot prepare", http.MethodPost, "/api/v1/evaluate/sessions", "", http.StatusUnauthorized},
+		{"subject without pdp:session cannot prepare", http.MethodPost, "/api/v1/evaluate/sessions", "user-001", http.StatusForbidden},
+		{"PEP prepares a session", http.MethodPost, "/api/v1/evaluate/sessions", "pep-gateway", http.StatusCreated},
+		{"anonymous caller cannot release", http.MethodDelete, "/api/v1/evaluate/sessions/sess-1", "", http.StatusUnauthorized},
+		{"subject without pdp:session cannot release", http.MethodDelete, "/api/v1/evaluate/sessions/sess-1", "user-001", http.StatusForbidden},
+		{"PEP releases the session", http.MethodDelete, "/api/v1/evaluate/sessions/sess-1", "pep-gateway", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := send(tt.method, tt.path, tt.userID); w.Code != tt.expected {
+				t.Errorf("Expected %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestExplainAuthorization tests that explain traces are only returned to callers permitted pdp:explain,
+// while plain evaluation stays open
+func TestExplainAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateUser(&models.User{ID: "policy-debugger", Username: "debugger", Email: "debugger@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateAction(&models.Action{ID: constants.ActionExplain, ActionName: constants.ActionExplain})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/explain", ResourceID: "/api/v1/explain"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-explain",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:       "DebuggerExplains",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: constants.ActionExplain},
+			Resource:  models.JSONActionResource{Single: "*"},
+			Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.email": "debugger@company.com"}},
+		}},
+	}})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	router := gin.New()
+	service.registerRoutes(router)
+
+	body := EvaluateRequestBody{SubjectID: "user-001", ResourceID: "api:documents:test.pdf", Action: "document:read"}
+	send := func(path, userID string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		userID   string
+		expected int
+	}{
+		{"anonymous caller cannot explain", "/api/v1/explain", "", http.StatusUnauthorized},
+		{"subject without pdp:explain cannot explain", "/api/v1/explain", "user-001", http.StatusForbidden},
+		{"permitted caller explains", "/api/v1/explain", "policy-debugger", http.StatusOK},
+		{"evaluation stays open", "/api/v1/evaluate", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.path, tt.userID)
+			if w.Code != tt.expected {
+				t.Errorf("Expected %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK && strings.Contains(w.Body.String(), "statements") {
+				t.Errorf("Expected no statement traces, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlePolicyChanges(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	send := func(method, path, ifMatch string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("X-User-ID", "admin-1")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	policy := map[string]interface{}{
+		"id":          "pol-audited",
+		"policy_name": "Audited",
+		"version":     "2024-10-21",
+		"statement": []interface{}{
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	if w := send(http.MethodPost, "/api/v1/policies", "", policy); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	policy["statement"].([]interface{})[0].(map[string]interface{})["Effect"] = "Deny"
+	if w := send(http.MethodPut, "/api/v1/policies/pol-audited", `"1"`, policy); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodDelete, "/api/v1/policies/pol-audited", "", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodDelete, "/api/v1/policies/pol-audited", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted policy, got %d", w.Code)
+	}
+
+	// Changes stay listable after the policy is deleted
+	w := send(http.MethodGet, "/api/v1/policies/pol-audited/changes", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Changes []models.PolicyChange `json:"changes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	actions := []string{models.PolicyChangeDelete, models.PolicyChangeUpdate, models.PolicyChangeCreate}
+	if len(response.Changes) != len(actions) {
+		t.Fatalf("Expected %d changes, got %+v", len(actions), response.Changes)
+	}
+	for i, change := range response.Changes {
+		if change.Action != actions[i] || change.Actor != "admin-1" {
+			t.Errorf("Change %d: expected %s by admin-1, got %s by %s", i, actions[i], change.Action, change.Actor)
+		}
+	}
+	update := response.Changes[1].Diff
+	if len(update) != 1 || update[0].Path != "/statement/0/Effect" || update[0].Old != "Allow" || update[0].New != "Deny" {
+		t.Errorf("Unexpected update diff %+v", update)
+	}
+
+	if w := send(http.MethodGet, "/api/v1/policies/pol-audited/changes?limit=0", "", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid limit, got %d", w.Code)
+	}
+}
+
+func TestHandleRestorePolicy(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	send := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("X-User-ID", "admin-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	listDeleted := func() DeletedPoliciesResponse {
+		w := send(http.MethodGet, "/api/v1/policies/deleted", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response DeletedPoliciesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	policy := map[string]interface{}{
+		"id":          "pol-recoverable",
+		"policy_name": "Recoverable",
+		"version":     "2024-10-21",
+		"statement": []interface{}{
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	if w := send(http.MethodPost, "/api/v1/policies", policy); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodDelete, "/api/v1/policies/pol-recoverable", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/policies/pol-recoverable", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a soft-deleted policy, got %d", w.Code)
+	}
+
+	deleted := listDeleted()
+	if deleted.Count != 1 || deleted.Policies[0].ID != "pol-recoverable" || !deleted.Policies[0].DeletedAt.Valid {
+		t.Fatalf("Expected the deleted policy to be listed, got %+v", deleted)
+	}
+
+	w := send(http.MethodPost, "/api/v1/policies/pol-recoverable/restore", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("Expected ETag \"1\", got %s", etag)
+	}
+	if strings.Contains(w.Body.String(), "deleted_at") {
+		t.Errorf("Restored policy should not carry deleted_at: %s", w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/policies/pol-recoverable", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected the restored policy to be readable, got %d", w.Code)
+	}
+	if deleted := listDeleted(); deleted.Count != 0 {
+		t.Errorf("Expected no deleted policies after restore, got %+v", deleted)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/policies/pol-recoverable/restore", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when restoring a live policy, got %d", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/policies/pol-missing/restore", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown policy, got %d", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/policies/pol-recoverable/changes", nil)
+	var changes PolicyChangesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &changes); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(changes.Changes) != 3 || changes.Changes[0].Action != models.PolicyChangeRestore {
+		t.Errorf("Expected the restore to be audited, got %+v", changes.Changes)
+	}
+}
+
+func TestHandleBundles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreatePolicy(&models.Policy{ID: "pol-unsigned", PolicyName: "Unsigned", Enabled: true})
+
+	publicKey, privateKey, _ := bundle.GenerateKey()
+	config := core.DefaultPDPConfig()
+	config.BundleVerifier = bundle.NewVerifier(publicKey)
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, config))
+	service.bundleSigner = bundle.NewSigner(privateKey)
+	service.bundlePath = filepath.Join(t.TempDir(), "policies.bundle.json")
+
+	router := gin.New()
+	router.GET("/api/v1/bundles/export", service.handleExportBundle)
+	router.POST("/api/v1/bundles/import", service.handleImportBundle)
+	router.GET("/api/v1/bundles/status", service.handleBundleStatus)
+	router.POST("/api/v1/evaluate", service.handleEvaluate)
+
+	signed, _ := bundle.NewSigner(privateKey).Sign([]*models.Policy{{
+		ID: "pol-read", PolicyName: "Read", Version: "2024-10-21", Enabled: true,
+		Statement: []models.PolicyStatement{
+			{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		},
+	}})
+	payload, _ := json.Marshal(signed)
+
+	w := postJSON(router, "/api/v1/bundles/import", json.RawMessage(payload))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result struct {
+		Created int `json:"created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if result.Created != 1 {
+		t.Errorf("Expected 1 created policy, got %s", w.Body.String())
+	}
+	if _, err := bundle.LoadFile(service.bundlePath); err != nil {
+		t.Errorf("Expected the trusted bundle to be persisted: %v", err)
+	}
+	if changes, _ := mockStorage.GetPolicyChanges("pol-read", 0); len(changes) != 1 {
+		t.Errorf("Expected the bundle import to be recorded, got %d changes", len(changes))
+	}
+
+	w = postJSON(router, "/api/v1/evaluate", EvaluateRequestBody{SubjectID: "user-001", ResourceID: "api:documents:test.pdf", Action: "document:read"})
+	if !strings.Contains(w.Body.String(), `"permit"`) {
+		t.Errorf("Expected the imported policy to permit, got %s", w.Body.String())
+	}
+
+	// Tampered bundles are rejected before anything is stored
+	signed.Policies[0].Statement[0].Resource = models.JSONActionResource{Single: "*"}
+	payload, _ = json.Marshal(signed)
+	if w := postJSON(router, "/api/v1/bundles/import", json.RawMessage(payload)); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a tampered bundle, got %d", w.Code)
+	}
+	if stored, _ := mockStorage.GetPolicy("pol-read"); stored.Statement[0].Resource.Single != "api:documents:*" {
+		t.Errorf("Expected the stored policy to be unchanged, got %v", stored.Statement[0].Resource)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bundles/status", nil))
+	if !strings.Contains(w.Body.String(), `"rejected_policies":["pol-unsigned"]`) {
+		t.Errorf("Expected the unsigned policy to be reported, got %s", w.Body.String())
+	}
+
+	// Export signs the enabled policies, including ones outside the trusted bundle
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bundles/export", nil))
+	exported, err := bundle.Read(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode exported bundle: %v", err)
+	}
+	if _, err := bundle.NewVerifier(publicKey).Verify(exported); err != nil || len(exported.Policies) != 2 {
+		t.Errorf("Expected a verifiable bundle of 2 policies, got %d (err=%v)", len(exported.Policies), err)
+	}
+}
+
+func TestBundleFetcherHotSwap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+
+	publicKey, privateKey, _ := bundle.GenerateKey()
+	config := core.DefaultPDPConfig()
+	config.BundleVerifier = bundle.NewVerifier(publicKey)
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, config))
+
+	signed, _ := bundle.NewSigner(privateKey).Sign([]*models.Policy{{
+		ID: "pol-read", PolicyName: "Read", Version: "2024-10-21", Enabled: true,
+		Statement: []models.PolicyStatement{
+			{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}},
+		},
+	}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(signed)
+	}))
+	defer server.Close()
+
+	var err error
+	service.bundleFetcher, err = bundle.NewFetcher(&bundle.FetcherConfig{URL: server.URL + "/policies.bundle.json"}, config.BundleVerifier, service.applyFetchedBundle)
+	if err != nil {
+		t.Fatalf("NewFetcher failed: %v", err)
+	}
+	if applied, err := service.bundleFetcher.Fetch(context.Background()); err != nil || !applied {
+		t.Fatalf("Expected the bundle to be applied, got %v %v", applied, err)
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/bundles/status", service.handleBundleStatus)
+	router.POST("/api/v1/evaluate", service.handleEvaluate)
+
+	w := postJSON(router, "/api/v1/evaluate", EvaluateRequestBody{SubjectID: "user-001", ResourceID: "api:documents:test.pdf", Action: "document:read"})
+	if !strings.Contains(w.Body.String(), `"permit"`) {
+		t.Errorf("Expected the fetched policy to permit, got %s", w.Body.String())
+	}
+	if changes, _ := mockStorage.GetPolicyChanges("pol-read", 0); len(changes) != 1 || changes[0].Actor != "bundle-fetcher" {
+		t.Errorf("Expected the fetched policy to be recorded by bundle-fetcher, got %+v", changes)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bundles/status", nil))
+	if !strings.Contains(w.Body.String(), `"fetcher":{"url":"`+server.URL) || !strings.Contains(w.Body.String(), `"updates":1`) {
+		t.Errorf("Expected the fetcher status, got %s", w.Body.String())
+	}
+}
+
+func TestHandleLockdown(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+
+	send := func(method string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, "/api/v1/lockdown", bytes.NewReader(payload))
+		req.Header.Set("X-User-ID", "oncall-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	evaluate := func() string {
+		w := postJSON(router, "/api/v1/evaluate", EvaluateRequestBody{SubjectID: "user-001", ResourceID: "api:documents:test.pdf", Action: "document:read"})
+		var response struct {
+			Decision models.Decision `json:"decision"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Decision.ReasonCode
+	}
+
+	if w := send(http.MethodPut, map[string]interface{}{"mode": "safelist"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for safelist without actions, got %d", w.Code)
+	}
+	if w := send(http.MethodPut, map[string]interface{}{"mode": "deny_all", "reason": "credential leak"}); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if code := evaluate(); code != constants.ReasonCodeLockdown {
+		t.Errorf("Expected a lockdown deny, got reason code %q", code)
+	}
+	if w := send(http.MethodGet, nil); !strings.Contains(w.Body.String(), `"active":true`) || !strings.Contains(w.Body.String(), `"actor":"oncall-1"`) {
+		t.Errorf("Expected an active lockdown by oncall-1, got %s", w.Body.String())
+	}
+
+	if w := send(http.MethodDelete, nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if code := evaluate(); code == constants.ReasonCodeLockdown {
+		t.Error("Expected normal evaluation after lifting the lockdown")
+	}
+
+	logs, _ := mockStorage.GetAuditLogs(10, 0)
+	actions := map[string]bool{}
+	for _, entry := range logs {
+		if entry.ResourceID == lockdownAuditResource && entry.SubjectID == "oncall-1" {
+			actions[entry.ActionID] = true
+		}
+	}
+	if !actions[lockdownAuditEnable] || !actions[lockdownAuditDisable] {
+		t.Errorf("Expected enable and disable to be audited, got %v", actions)
+	}
+}
+
+func TestAdminTokenAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "admin", ActionName: "admin"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/policies", ResourceID: "/api/v1/policies"})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	var err error
+	service.adminAuth, err = adminauth.NewAuthenticator([]adminauth.TokenEntry{
+		{Name: "alice", Token: "author-token", Roles: []adminauth.Role{adminauth.RolePolicyAuthor}},
+		{Name: "bob", Token: "approver-token", Roles: []adminauth.Role{adminauth.RolePolicyApprover}},
+		{Name: "carol", Token: "auditor-token", Roles: []adminauth.Role{adminauth.RoleAuditor}},
+		// Also authorized by the PDP as user-001, which no policy allows to administer
+		{Name: "dave", Token: "subject-token", Roles: []adminauth.Role{adminauth.RoleAdmin}, SubjectID: "user-001"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	router := gin.New()
+	service.registerRoutes(router)
+
+	send := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	policy := map[string]interface{}{
+		"id":          "pol-authored",
+		"policy_name": "Authored",
+		"version":     "2024-10-21",
+		"statement": []interface{}{
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"},
+		},
+	}
+	enabledPolicy := map[string]interface{}{"enabled": true}
+	for key, value := range policy {
+		enabledPolicy[key] = value
+	}
+	enabledPolicy["id"] = "pol-live"
+
+	w := send(http.MethodGet, "/api/v1/policies", "", nil)
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected 401 with a challenge without token, got %d", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/policies", "guessed", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown token, got %d", w.Code)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		body     interface{}
+		expected int
+	}{
+		{"auditor reads policies", http.MethodGet, "/api/v1/policies", "auditor-token", nil, http.StatusOK},
+		{"auditor cannot author", http.MethodPost, "/api/v1/policies", "auditor-token", policy, http.StatusForbidden},
+		{"approver cannot author", http.MethodPost, "/api/v1/policies", "approver-token", policy, http.StatusForbidden},
+		{"author creates a policy", http.MethodPost, "/api/v1/policies", "author-token", policy, http.StatusCreated},
+		{"author cannot create an enabled policy", http.MethodPost, "/api/v1/policies", "author-token", enabledPolicy, http.StatusForbidden},
+		{"author cannot enable the draft", http.MethodPost, "/api/v1/policies/bulk-toggle", "author-token", PolicyToggleRequestBody{PolicyIDs: []string{"pol-authored"}, Enabled: true}, http.StatusForbidden},
+		{"approver enables the draft", http.MethodPost, "/api/v1/policies/bulk-toggle", "approver-token", PolicyToggleRequestBody{PolicyIDs: []string{"pol-authored"}, Enabled: true}, http.StatusOK},
+		{"author cannot delete an enabled policy", http.MethodDelete, "/api/v1/policies/pol-authored", "author-token", nil, http.StatusForbidden},
+		{"author cannot bulk import", http.MethodPost, "/api/v1/import/policies", "author-token", nil, http.StatusForbidden},
+		{"author cannot release a quarantine", http.MethodDelete, "/api/v1/policies/pol-authored/quarantine", "author-token", nil, http.StatusForbidden},
+		{"author cannot read the audit trail", http.MethodGet, "/api/v1/policies/pol-authored/changes", "author-token", nil, http.StatusForbidden},
+		{"auditor reads the audit trail", http.MethodGet, "/api/v1/policies/pol-authored/changes", "auditor-token", nil, http.StatusOK},
+		{"author cannot approve a lockdown", http.MethodPut, "/api/v1/lockdown", "author-token", map[string]interface{}{"mode": "deny_all"}, http.StatusForbidden},
+		{"approver reads the lockdown", http.MethodGet, "/api/v1/lockdown", "approver-token", nil, http.StatusOK},
+		{"subject token is denied by the PDP", http.MethodGet, "/api/v1/policies", "subject-token", nil, http.StatusForbidden},
+		{"evaluation API stays public", http.MethodGet, "/api/v1/schema/policy", "", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := send(tt.method, tt.path, tt.token, tt.body); w.Code != tt.expected {
+				t.Errorf("Expected %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// The token principal is the actor of the audit trail: alice drafted the policy, bob enabled it
+	changes, _ := mockStorage.GetPolicyChanges("pol-authored", 0)
+	if len(changes) != 2 || changes[0].Actor != "bob" || changes[1].Actor != "alice" {
+		t.Errorf("Expected a change by alice then one by bob, got %+v", changes)
+	}
+}
+
+func TestCORSPreflightAllowsAdminHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(corsMiddleware())
+	router.PUT("/api/v1/policies/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/policies/pol-001", nil)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, if-match")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	allowed := w.Header().Get("Access-Control-Allow-Headers")
+	if w.Code != http.StatusOK || !strings.Contains(allowed, "Authorization") || !strings.Contains(allowed, "If-Match") {
+		t.Errorf("Expected a preflight allowing Authorization and If-Match, got %d %q", w.Code, allowed)
+	}
+	if exposed := w.Header().Get("Access-Control-Expose-Headers"); exposed != "ETag" {
+		t.Errorf("Expected ETag to be exposed, got %q", exposed)
+	}
+}
+
+func TestABACMiddlewareRequestAttributes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "payment:create", ActionName: "payment:create"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/payments", ResourceID: "/api/v1/payments"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-payments",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:      "SmallWebPayments",
+			Effect:   "Allow",
+			Action:   models.JSONActionResource{Single: "payment:create"},
+			Resource: models.JSONActionResource{Single: "*"},
+			Condition: map[string]interface{}{
+				"NumericLessThanEquals": map[string]interface{}{"request.body.amount": "10000.00"},
+				"StringEquals":          map[string]interface{}{"request.header.x-channel": "web"},
+			},
+		}},
+	}})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	service.requestAttrs = &pep.RequestAttributeConfig{BodyFields: []string{"amount"}, Headers: []string{"X-Channel"}, MaxBodyBytes: 256}
+	service.decisionHdrs = &pep.DecisionHeaderConfig{Request: true, Response: true}
+	router := gin.New()
+	router.POST("/api/v1/payments", service.ABACMiddleware("payment:create"), func(c *gin.Context) {
+		c.Header("X-Upstream-Decision", c.GetHeader(pep.HeaderDecision))
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, body)
+	})
+
+	send := func(body, channel string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/payments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user-001")
+		req.Header.Set("X-ABAC-Decision", "forged")
+		if channel != "" {
+			req.Header.Set("X-Channel", channel)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"amount": 9999.99, "payee": "ACME"}`, "web")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"payee":"ACME"`) {
+		t.Errorf("Expected a permitted payment with the body passed on, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Upstream-Decision") != "permit" || w.Header().Get(pep.HeaderPolicies) != "pol-payments" || w.Header().Get(pep.HeaderRequestID) == "" {
+		t.Errorf("Expected decision headers on the request and response, got %v", w.Header())
+	}
+	if w := send(`{"amount": 10000.01}`, "web"); w.Code != http.StatusForbidden || w.Header().Get(pep.HeaderDecision) != "deny" {
+		t.Errorf("Expected a payment above the threshold to be denied with a decision header, got %d %v", w.Code, w.Header())
+	}
+	if w := send(`{"amount": 50}`, "branch"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected another channel to be denied, got %d", w.Code)
+	}
+	if w := send(`{"payee": "ACME"}`, "web"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a missing amount to be denied, got %d", w.Code)
+	}
+	if w := send(`{"amount": 1, "memo": "`+strings.Repeat("x", 300)+`"}`, "web"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized body, got %d", w.Code)
+	}
+}
+
+func TestABACMiddlewareConstraints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	mockStorage.CreateAction(&models.Action{ID: "write", ActionName: "write"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/financial", ResourceID: "/api/v1/financial"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-financial-summary",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:         "FinancialSummary",
+			Effect:      "Allow",
+			Action:      models.JSONActionResource{Multiple: []string{"read", "write"}},
+			Resource:    models.JSONActionResource{Single: "*"},
+			Constraints: &models.Constraints{Fields: []string{"quarter", "profit"}, ReadOnly: true},
+		}},
+	}})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	router := gin.New()
+	router.GET("/api/v1/financial", service.ABACMiddleware("read"), service.handleFinancialData)
+	router.POST("/api/v1/financial", service.ABACMiddleware("write"), service.handleFinancialData)
+
+	send := func(method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/financial", nil)
+		req.Header.Set("X-User-ID", "user-001")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodGet)
+	var response struct {
+		FinancialData map[string]interface{} `json:"financial_data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.FinancialData) != 2 || response.FinancialData["profit"] == nil {
+		t.Errorf("Expected only the permitted fields, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost); w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), constants.ReasonCodeReadOnlyPermit) {
+		t.Errorf("Expected a write under a read-only permit to be denied, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestABACMiddlewareAuditsDecisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateUser(&models.User{ID: "user-001", Username: "john", Email: "john@company.com", Status: "active"})
+	mockStorage.CreateAction(&models.Action{ID: "read", ActionName: "read"})
+	mockStorage.CreateAction(&models.Action{ID: "write", ActionName: "write"})
+	mockStorage.CreateResource(&models.Resource{ID: "/api/v1/financial", ResourceID: "/api/v1/financial"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:        "pol-financial-read",
+		Enabled:   true,
+		Statement: []models.PolicyStatement{{Sid: "FinancialRead", Effect: "Allow", Action: models.JSONActionResource{Single: "read"}, Resource: models.JSONActionResource{Single: "*"}}},
+	}})
+
+	service := newABACService(mockStorage, core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()))
+	spool, err := storage.NewStoreAndForwardAuditWriter(mockStorage, &storage.AuditSpoolConfig{Dir: t.TempDir(), MaxSegmentBytes: 1 << 20, MaxSegments: 4, ReplayInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("NewStoreAndForwardAuditWriter failed: %v", err)
+	}
+	service.audit = spool
+	router := gin.New()
+	router.GET("/api/v1/financial", service.ABACMiddleware("read"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/financial", service.ABACMiddleware("write"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method string) int {
+		req := httptest.NewRequest(method, "/api/v1/financial", nil)
+		req.Header.Set("X-User-ID", "user-001")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(http.MethodGet); code != http.StatusOK {
+		t.Fatalf("Expected a permit, got %d", code)
+	}
+	// A deny during an audit storage outage is spooled and replayed
+	mockStorage.InjectFault("LogAudit", storage.Fault{ErrorRate: 1})
+	if code := send(http.MethodPost); code != http.StatusForbidden {
+		t.Fatalf("Expected a deny, got %d", code)
+	}
+	if stats := spool.Stats(); stats.Pending != 1 {
+		t.Fatalf("Expected the deny to be spooled, got %+v", stats)
+	}
+	mockStorage.ClearFaults()
+	if _, err := spool.Replay(context.Background()); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	decisions, _ := mockStorage.SubjectDecisions("user-001", time.Now().Add(-time.Minute), 10)
+	if len(decisions) != 2 || decisions[0].Decision != "deny" || decisions[0].ActionID != "write" || decisions[1].Decision != "permit" {
+		t.Fatalf("Expected the deny and the permit of user-001, got %+v", decisions)
+	}
+	if decisions[0].Source != models.AuditSourceEvaluation || decisions[0].ResourceID != "/api/v1/financial" || decisions[1].Context["matched_policies"] == nil {
+		t.Errorf("Unexpected decision audit entries %+v", decisions)
+	}
+}
+
+func TestHandleExceptions(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	evaluate := func() map[string]interface{} {
+		t.Helper()
+		w := postJSON(router, "/api/v1/evaluate", map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read"})
+		var response struct {
+			Decision map[string]interface{} `json:"decision"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Decision
+	}
+
+	exception := map[string]interface{}{
+		"id":            "exc-hold",
+		"subject_id":    "user-001",
+		"resource_id":   "api:documents:test.pdf",
+		"action":        "document:read",
+		"effect":        "deny",
+		"justification": "Legal hold LH-7",
+		"expires_at":    time.Now().Add(time.Hour).Format(time.RFC3339),
+	}
+	if w := postJSON(router, "/api/v1/exceptions", exception); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(router, "/api/v1/exceptions", exception); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate exception, got %d", w.Code)
+	}
+
+	response := evaluate()
+	if response["result"] != "deny" || !strings.Contains(response["reason"].(string), "exception exc-hold: Legal hold LH-7") {
+		t.Errorf("Expected deny by exception, got %v", response)
+	}
+
+	expired := map[string]interface{}{}
+	for k, v := range exception {
+		expired[k] = v
+	}
+	expired["id"] = "exc-old"
+	expired["expires_at"] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	if w := postJSON(router, "/api/v1/exceptions", expired); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for expired exception, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/exceptions?subject_id=user-001", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/exceptions/exc-hold", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if response := evaluate(); response["result"] != "permit" {
+		t.Errorf("Expected policy permit after revoking the exception, got %v", response)
+	}
+	if logs, _ := mockStorage.GetAuditLogs(100, 0); len(logs) == 0 {
+		t.Error("Expected exception changes to be audited")
+	}
+}
+
+func TestHandleRevocations(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	evaluate := func() map[string]interface{} {
+		t.Helper()
+		w := postJSON(router, "/api/v1/evaluate", map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:read"})
+		var response struct {
+			Decision map[string]interface{} `json:"decision"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Decision
+	}
+	if response := evaluate(); response["result"] != "permit" {
+		t.Fatalf("Expected policy permit, got %v", response)
+	}
+
+	revocation := map[string]interface{}{"id": "rev-john", "subject_id": "user-001", "reason": "Compromised account INC-9"}
+	if w := postJSON(router, "/api/v1/revocations", revocation); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(router, "/api/v1/revocations", revocation); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate revocation, got %d", w.Code)
+	}
+	if w := postJSON(router, "/api/v1/revocations", map[string]interface{}{"reason": "Nobody"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without subject or session, got %d", w.Code)
+	}
+
+	// The revocation list cached by the first evaluation is invalidated by the write
+	response := evaluate()
+	if response["result"] != "deny" || response["reason_code"] != constants.ReasonCodeAccessRevoked ||
+		!strings.Contains(response["reason"].(string), "rev-john: Compromised account INC-9") {
+		t.Errorf("Expected deny by revocation, got %v", response)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/revocations", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/revocations/rev-john", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if response := evaluate(); response["result"] != "permit" {
+		t.Errorf("Expected policy permit after lifting the revocation, got %v", response)
+	}
+	if logs, _ := mockStorage.GetAuditLogs(100, 0); len(logs) == 0 {
+		t.Error("Expected revocation changes to be audited")
+	}
+}
+
+func TestHandleElevations(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	mockStorage.CreatePolicy(&models.Policy{
+		ID:      "pol-responder-delete",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:       "RespondersDelete",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: "document:delete"},
+			Resource:  models.JSONActionResource{Single: "api:documents:*"},
+			Condition: map[string]interface{}{"ArrayContains": map[string]interface{}{"user.roles": "incident-responder"}},
+		}},
+	})
+	evaluate := func() interface{} {
+		t.Helper()
+		w := postJSON(router, "/api/v1/evaluate", map[string]interface{}{"subject_id": "user-001", "resource_id": "api:documents:test.pdf", "action": "document:delete"})
+		var response struct {
+			Decision map[string]interface{} `json:"decision"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Decision["result"]
+	}
+	if result := evaluate(); result != "deny" {
+		t.Fatalf("Expected deny without the role, got %v", result)
+	}
+
+	elevation := map[string]interface{}{
+		"id": "elev-john", "subject_id": "user-001", "roles": []string{"incident-responder"},
+		"justification": "INC-9 cleanup", "expires_at": time.Now().Add(time.Hour),
+	}
+	if w := postJSON(router, "/api/v1/elevations", elevation); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(router, "/api/v1/elevations", elevation); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate elevation, got %d", w.Code)
+	}
+	tooLong := map[string]interface{}{"subject_id": "user-001", "roles": []string{"admin"}, "justification": "Forever", "expires_at": time.Now().AddDate(1, 0, 0)}
+	if w := postJSON(router, "/api/v1/elevations", tooLong); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 beyond the maximum duration, got %d", w.Code)
+	}
+
+	// The deny cached by the first evaluation is purged by the write
+	if result := evaluate(); result != "permit" {
+		t.Errorf("Expected permit while elevated, got %v", result)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/elevations?subject_id=user-001", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/elevations/elev-john", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if result := evaluate(); result != "deny" {
+		t.Errorf("Expected deny after ending the elevation, got %v", result)
+	}
+	if logs, _ := mockStorage.GetAuditLogs(100, 0); len(logs) == 0 {
+		t.Error("Expected elevation changes to be audited")
+	}
+}
+
+func TestHandlePolicySchedules(t *testing.T) {
+	router, mockStorage := newTestRouter(t)
+	for _, policy := range []*models.Policy{
+		{ID: "pol-lockdown-a", Enabled: false, Tags: models.JSONStringSlice{"lockdown"}},
+		{ID: "pol-lockdown-b", Enabled: false, Tags: models.JSONStringSlice{"lockdown"}},
+		{ID: "pol-regular", Enabled: true},
+	} {
+		mockStorage.CreatePolicy(policy)
+	}
+
+	w := postJSON(router, "/api/v1/policies/bulk-toggle", map[string]interface{}{"tags": []string{"lockdown"}, "enabled": true})
+	var toggled PolicyToggleResponse
+	json.Unmarshal(w.Body.Bytes(), &toggled)
+	if w.Code != http.StatusOK || len(toggled.Changed) != 2 || !toggled.Enabled {
+		t.Fatalf("Unexpected toggle response %d: %s", w.Code, w.Body.String())
+	}
+	if policy, _ := mockStorage.GetPolicy("pol-lockdown-b"); !policy.Enabled {
+		t.Error("Expected the tagged policies to be enabled")
+	}
+	if w := postJSON(router, "/api/v1/policies/bulk-toggle", map[string]interface{}{"enabled": true}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a selector, got %d", w.Code)
+	}
+
+	schedule := map[string]interface{}{"id": "sched-friday", "tags": []string{"lockdown"}, "enabled": true, "cron": "0 18 * * FRI"}
+	if w := postJSON(router, "/api/v1/policy-schedules", schedule); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(router, "/api/v1/policy-schedules", schedule); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate schedule, got %d", w.Code)
+	}
+	badCron := map[string]interface{}{"tags": []string{"lockdown"}, "cron": "every friday"}
+	if w := postJSON(router, "/api/v1/policy-schedules", badCron); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid cron expression, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/policy-schedules", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":1`) || !strings.Contains(w.Body.String(), `"next_run_at"`) {
+		t.Errorf("Unexpected list response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/policy-schedules/sched-friday", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	logs, _ := mockStorage.GetAuditLogs(100, 0)
+	actions := map[string]bool{}
+	for _, auditLog := range logs {
+		actions[auditLog.ActionID] = true
+	}
+	if !actions[storage.PolicyAuditBulkEnable] || !actions[policyScheduleAuditCreate] || !actions[policyScheduleAuditDelete] {
+		t.Errorf("Expected toggles and schedule changes to be audited, got %v", actions)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	router, _ := newTestRouter(t)
+	service := &ABACService{}
+	router.GET("/openapi.json", service.handleOpenAPI)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version || doc.Info.Title != apiInfo.Title {
+		t.Errorf("Unexpected document header %s %+v", doc.OpenAPI, doc.Info)
+	}
+
+	evaluate := doc.Paths["/api/v1/evaluate"]["post"]
+	if evaluate == nil || evaluate.RequestBody.Content[openapi.ContentTypeJSON].Schema.RefName() != "EvaluateRequestBody" {
+		t.Fatalf("Expected evaluate operation with EvaluateRequestBody, got %+v", evaluate)
+	}
+	if update := doc.Paths["/api/v1/policies/{id}"]["put"]; update == nil || update.Permission != "admin" {
+		t.Errorf("Expected admin-protected policy update, got %+v", update)
+	}
+	action := doc.Components.Schemas["PolicyStatement"].Properties["Action"]
+	if action == nil || len(action.OneOf) != 2 {
+		t.Errorf("Expected Action to be a string or an array, got %+v", action)
+	}
+
+	// Every registered route is documented once, under a unique operationId
+	registered := gin.New()
+	service.registerRoutes(registered)
+	operationIDs := make(map[string]bool)
+	for _, ref := range doc.Operations() {
+		if operationIDs[ref.Operation.OperationID] {
+			t.Errorf("Duplicate operationId %s", ref.Operation.OperationID)
+		}
+		operationIDs[ref.Operation.OperationID] = true
+	}
+	if routes := registered.Routes(); len(routes) != len(operationIDs) {
+		t.Errorf("Expected %d documented operations, got %d", len(routes), len(operationIDs))
+	}
+}
+
+func TestGeneratedClientIsCurrent(t *testing.T) {
+	source, err := generateClient()
+	if err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	committed, err := os.ReadFile(filepath.Join("client", "client_gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read client: %v", err)
+	}
+	if !bytes.Equal(source, committed) {
+		t.Error("client/client_gen.go is out of date, run `make client`")
+	}
+}
+
+func TestClientAgainstService(t *testing.T) {
+	router, _ := newTestRouter(t)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	c := client.New(server.URL)
+	response, err := c.Evaluate(context.Background(), &client.EvaluateRequestBody{
+		SubjectID:  "user-001",
+		ResourceID: "api:documents:test.pdf",
+		Action:     "document:read",
+	})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if response.Decision == nil || response.Decision.Result != "permit" {
+		t.Errorf("Expected permit, got %+v", response.Decision)
+	}
+
+	_, err = c.GetPolicy(context.Background(), "missing")
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 client error, got %v", err)
+	}
+}
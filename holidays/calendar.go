@@ -0,0 +1,146 @@
+package holidays
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// Calendar reports public holidays. Business-hours checks treat holidays like weekends,
+// so a holiday calendar keeps policies unchanged from year to year.
+type Calendar interface {
+	// Holiday returns the holiday name when date (in its own location) is a holiday
+	Holiday(date time.Time) (name string, ok bool)
+}
+
+// IsHoliday reports whether date is a holiday in calendar; a nil calendar has no holidays
+func IsHoliday(calendar Calendar, date time.Time) bool {
+	if calendar == nil {
+		return false
+	}
+	_, ok := calendar.Holiday(date)
+	return ok
+}
+
+// Holiday is one calendar entry. Date is "YYYY-MM-DD" for a single day or "MM-DD" for a day every year.
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name,omitempty"`
+}
+
+// File is the JSON form of a holiday calendar, shared by config files and HTTP providers:
+//
+//	{"holidays": [{"date": "2025-04-30", "name": "Reunification Day"}, {"date": "09-02", "name": "National Day"}]}
+type File struct {
+	Holidays []Holiday `json:"holidays"`
+}
+
+// StaticCalendar is a fixed set of dated and annual holidays
+type StaticCalendar struct {
+	dates  map[string]string // "YYYY-MM-DD" -> name
+	annual map[string]string // "MM-DD" -> name
+}
+
+// NewStaticCalendar builds a calendar from holiday entries
+func NewStaticCalendar(holidays []Holiday) (*StaticCalendar, error) {
+	calendar := &StaticCalendar{
+		dates:  make(map[string]string),
+		annual: make(map[string]string),
+	}
+	for i, holiday := range holidays {
+		if _, err := time.Parse(constants.TimeFormatDate, holiday.Date); err == nil {
+			calendar.dates[holiday.Date] = holiday.Name
+			continue
+		}
+		// Annual dates are validated against a leap year so "02-29" is accepted
+		if _, err := time.Parse(constants.TimeFormatDate, "2024-"+holiday.Date); len(holiday.Date) == 5 && err == nil {
+			calendar.annual[holiday.Date] = holiday.Name
+			continue
+		}
+		return nil, fmt.Errorf("holiday %d: invalid date %q, expected YYYY-MM-DD or MM-DD", i, holiday.Date)
+	}
+	return calendar, nil
+}
+
+// ParseCalendar parses a calendar from its JSON form
+func ParseCalendar(data []byte) (*StaticCalendar, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid holiday calendar: %w", err)
+	}
+	return NewStaticCalendar(file.Holidays)
+}
+
+// LoadCalendar reads a JSON calendar from path
+func LoadCalendar(path string) (*StaticCalendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday calendar: %w", err)
+	}
+	return ParseCalendar(data)
+}
+
+// Holiday implements Calendar
+func (c *StaticCalendar) Holiday(date time.Time) (string, bool) {
+	day := date.Format(constants.TimeFormatDate)
+	if name, ok := c.dates[day]; ok {
+		return name, true
+	}
+	name, ok := c.annual[day[5:]]
+	return name, ok
+}
+
+// Len returns the number of configured holidays
+func (c *StaticCalendar) Len() int {
+	return len(c.dates) + len(c.annual)
+}
+
+// MultiCalendar combines calendars: a date is a holiday when any calendar says so
+type MultiCalendar []Calendar
+
+// Holiday implements Calendar, returning the first calendar's name for the date
+func (m MultiCalendar) Holiday(date time.Time) (string, bool) {
+	for _, calendar := range m {
+		if name, ok := calendar.Holiday(date); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// CalendarFromEnv builds the calendar configured by ABAC_HOLIDAY_CALENDAR (a JSON file) and
+// ABAC_HOLIDAY_CALENDAR_URL (an HTTP provider). It returns nil (no holidays) when neither is set.
+func CalendarFromEnv() (Calendar, error) {
+	var calendars MultiCalendar
+	if path := os.Getenv(constants.EnvHolidayCalendar); path != "" {
+		static, err := LoadCalendar(path)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, static)
+	}
+
+	if url := os.Getenv(constants.EnvHolidayCalendarURL); url != "" {
+		refresh := DefaultRefreshInterval
+		if value := os.Getenv(constants.EnvHolidayCalendarRefresh); value != "" {
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("invalid %s %q", constants.EnvHolidayCalendarRefresh, value)
+			}
+			refresh = interval
+		}
+		calendars = append(calendars, NewHTTPCalendar(url, refresh))
+	}
+
+	switch len(calendars) {
+	case 0:
+		return nil, nil
+	case 1:
+		return calendars[0], nil
+	default:
+		return calendars, nil
+	}
+}
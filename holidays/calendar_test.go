@@ -0,0 +1,139 @@
+package holidays
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+)
+
+func TestStaticCalendar(t *testing.T) {
+	calendar, err := ParseCalendar([]byte(`{"holidays": [
+		{"date": "2025-04-30", "name": "Reunification Day"},
+		{"date": "09-02", "name": "National Day"},
+		{"date": "02-29", "name": "Leap Day"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseCalendar failed: %v", err)
+	}
+
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2025, time.April, 30, 10, 0, 0, 0, time.UTC), "Reunification Day"},
+		{time.Date(2026, time.April, 30, 10, 0, 0, 0, time.UTC), ""}, // dated holidays do not recur
+		{time.Date(2025, time.September, 2, 10, 0, 0, 0, time.UTC), "National Day"},
+		{time.Date(2031, time.September, 2, 10, 0, 0, 0, time.UTC), "National Day"},
+		{time.Date(2028, time.February, 29, 10, 0, 0, 0, time.UTC), "Leap Day"},
+		{time.Date(2025, time.September, 3, 10, 0, 0, 0, time.UTC), ""},
+	}
+	for _, test := range tests {
+		name, ok := calendar.Holiday(test.date)
+		if name != test.expected || ok != (test.expected != "") {
+			t.Errorf("Holiday(%s) = %q, %v; want %q", test.date.Format(constants.TimeFormatDate), name, ok, test.expected)
+		}
+	}
+
+	// The date is taken in the timestamp's own location
+	local := time.FixedZone("ICT", 7*3600)
+	if !IsHoliday(calendar, time.Date(2025, time.September, 1, 18, 0, 0, 0, time.UTC).In(local)) {
+		t.Error("Expected 01:00 ICT on September 2 to be a holiday")
+	}
+	if IsHoliday(nil, time.Now()) {
+		t.Error("Expected a nil calendar to have no holidays")
+	}
+
+	for _, data := range []string{`not json`, `{"holidays": [{"date": "2025-13-01"}]}`, `{"holidays": [{"date": "9-2"}]}`} {
+		if _, err := ParseCalendar([]byte(data)); err == nil {
+			t.Errorf("Expected %s to be rejected", data)
+		}
+	}
+}
+
+func TestHTTPCalendar(t *testing.T) {
+	var requests atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("year") != "2025" || r.URL.Query().Get("country") != "VN" {
+			w.Write([]byte(`{"holidays": []}`))
+			return
+		}
+		w.Write([]byte(`{"holidays": [{"date": "2025-01-01", "name": "New Year"}]}`))
+	}))
+	defer server.Close()
+
+	mockClock := clock.NewMockClock(time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC))
+	calendar := NewHTTPCalendar(server.URL+"?country=VN", time.Hour)
+	calendar.SetClock(mockClock)
+
+	newYear := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+	if name, ok := calendar.Holiday(newYear); !ok || name != "New Year" {
+		t.Fatalf("Expected New Year from provider, got %q, %v", name, ok)
+	}
+	if IsHoliday(calendar, newYear.AddDate(0, 0, 1)) {
+		t.Error("Expected January 2 not to be a holiday")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected one request for a cached year, got %d", requests.Load())
+	}
+
+	// After the refresh interval a failing provider keeps serving the last fetched holidays
+	failing.Store(true)
+	mockClock.Advance(2 * time.Hour)
+	if !IsHoliday(calendar, newYear) {
+		t.Error("Expected stale holidays to be used while the provider fails")
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected a refresh attempt, got %d requests", requests.Load())
+	}
+
+	// A year that was never fetched has no holidays, and failures are not retried immediately
+	if IsHoliday(calendar, newYear.AddDate(1, 0, 0)) || IsHoliday(calendar, newYear.AddDate(1, 0, 0)) {
+		t.Error("Expected no holidays for an unavailable year")
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected failed fetches to be throttled, got %d requests", requests.Load())
+	}
+}
+
+func TestCalendarFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvHolidayCalendar, "")
+	t.Setenv(constants.EnvHolidayCalendarURL, "")
+	if calendar, err := CalendarFromEnv(); calendar != nil || err != nil {
+		t.Errorf("Expected nil calendar when unset, got %v (err=%v)", calendar, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if err := os.WriteFile(path, []byte(`{"holidays": [{"date": "01-01", "name": "New Year"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(constants.EnvHolidayCalendar, path)
+	calendar, err := CalendarFromEnv()
+	if err != nil || !IsHoliday(calendar, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected calendar %v (err=%v)", calendar, err)
+	}
+
+	t.Setenv(constants.EnvHolidayCalendarURL, "http://holidays.invalid/api")
+	t.Setenv(constants.EnvHolidayCalendarRefresh, "12h")
+	calendar, err = CalendarFromEnv()
+	if multi, ok := calendar.(MultiCalendar); err != nil || !ok || len(multi) != 2 {
+		t.Errorf("Expected file and HTTP calendars, got %T (err=%v)", calendar, err)
+	}
+
+	t.Setenv(constants.EnvHolidayCalendarRefresh, "soon")
+	if _, err := CalendarFromEnv(); err == nil {
+		t.Error("Expected an invalid refresh interval to be rejected")
+	}
+}
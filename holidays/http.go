@@ -0,0 +1,132 @@
+package holidays
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"abac_go_example/clock"
+)
+
+// DefaultRefreshInterval is how long a year fetched from an HTTP provider is reused
+const DefaultRefreshInterval = 24 * time.Hour
+
+// failedFetchRetry is how long a failed fetch is not retried, so an outage does not add latency to every evaluation
+const failedFetchRetry = time.Minute
+
+// maxCalendarResponseBytes bounds the body read from an HTTP provider
+const maxCalendarResponseBytes = 1 << 20
+
+// HTTPCalendar fetches holidays per year from an HTTP provider: GET <url>?year=2025 returning
+// the File JSON form. Years are cached for the refresh interval. When a refresh fails the last
+// fetched holidays keep being used; a year that was never fetched has no holidays until a fetch succeeds.
+type HTTPCalendar struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+	clock   clock.Clock
+
+	mu    sync.Mutex
+	years map[int]*cachedYear
+}
+
+// cachedYear is one fetched year of an HTTP calendar
+type cachedYear struct {
+	calendar  *StaticCalendar // nil until a fetch succeeds
+	expiresAt time.Time
+}
+
+// NewHTTPCalendar creates a calendar backed by the provider at providerURL
+func NewHTTPCalendar(providerURL string, refresh time.Duration) *HTTPCalendar {
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	return &HTTPCalendar{
+		url:     providerURL,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		clock:   clock.NewRealClock(),
+		years:   make(map[int]*cachedYear),
+	}
+}
+
+// SetHTTPClient replaces the client used to call the provider (nil restores the default)
+func (c *HTTPCalendar) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	c.client = client
+}
+
+// SetClock configures the clock used to expire fetched years (nil restores real time)
+func (c *HTTPCalendar) SetClock(cl clock.Clock) {
+	c.clock = clock.OrReal(cl)
+}
+
+// Holiday implements Calendar
+func (c *HTTPCalendar) Holiday(date time.Time) (string, bool) {
+	calendar := c.yearCalendar(date.Year())
+	if calendar == nil {
+		return "", false
+	}
+	return calendar.Holiday(date)
+}
+
+// yearCalendar returns the holidays of year, fetching them when missing or expired.
+// The lock is held during the fetch so concurrent evaluations trigger a single request.
+func (c *HTTPCalendar) yearCalendar(year int) *StaticCalendar {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	cached := c.years[year]
+	if cached != nil && now.Before(cached.expiresAt) {
+		return cached.calendar
+	}
+
+	calendar, err := c.fetch(year)
+	if err != nil {
+		log.Printf("Warning: holiday calendar fetch for %d failed: %v", year, err)
+		if cached == nil {
+			cached = &cachedYear{}
+			c.years[year] = cached
+		}
+		cached.expiresAt = now.Add(failedFetchRetry)
+		return cached.calendar
+	}
+
+	c.years[year] = &cachedYear{calendar: calendar, expiresAt: now.Add(c.refresh)}
+	return calendar
+}
+
+// fetch requests one year of holidays from the provider
+func (c *HTTPCalendar) fetch(year int) (*StaticCalendar, error) {
+	requestURL, err := url.Parse(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider URL: %w", err)
+	}
+	query := requestURL.Query()
+	query.Set("year", strconv.Itoa(year))
+	requestURL.RawQuery = query.Encode()
+
+	resp, err := c.client.Get(requestURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("provider returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	return ParseCalendar(body)
+}
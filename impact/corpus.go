@@ -0,0 +1,104 @@
+package impact
+
+import (
+	"fmt"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// DefaultSampleSize is the number of recent audit logs replayed when no corpus is given
+const DefaultSampleSize = 500
+
+// SubjectResolver resolves the subject of a replayed request (e.g. SubjectFactory.CreateFromSubjectID)
+type SubjectResolver func(subjectID string) (models.SubjectInterface, error)
+
+// RequestsFromAuditLogs rebuilds evaluation requests from the most recent audit logs.
+// Only evaluation entries are used and each is replayed at its original time.
+// Logs whose subject can no longer be resolved are reported as failures.
+func RequestsFromAuditLogs(store storage.Storage, resolve SubjectResolver, sampleSize int) ([]*models.EvaluationRequest, []Failure, error) {
+	if sampleSize <= 0 {
+		sampleSize = DefaultSampleSize
+	}
+
+	auditLogs, err := store.GetAuditLogs(sampleSize, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load audit logs: %w", err)
+	}
+
+	requests := make([]*models.EvaluationRequest, 0, len(auditLogs))
+	failures := []Failure{}
+	for _, auditLog := range auditLogs {
+		if !isEvaluationLog(auditLog) {
+			continue
+		}
+
+		subject, err := resolve(auditLog.SubjectID)
+		if err != nil {
+			failures = append(failures, Failure{RequestID: auditLog.RequestID, Error: fmt.Sprintf("subject %s: %v", auditLog.SubjectID, err)})
+			continue
+		}
+
+		timestamp := auditLog.CreatedAt
+		requests = append(requests, &models.EvaluationRequest{
+			RequestID:   auditLog.RequestID,
+			Subject:     subject,
+			ResourceID:  auditLog.ResourceID,
+			Action:      auditLog.ActionID,
+			Context:     map[string]interface{}{},
+			Environment: environmentFromAudit(auditLog.Context),
+			Timestamp:   &timestamp,
+			AsOf:        &timestamp, // Replay against the attributes in effect when the decision was logged
+		})
+	}
+
+	return requests, failures, nil
+}
+
+// isEvaluationLog reports whether an audit entry records a policy decision
+func isEvaluationLog(auditLog *models.AuditLog) bool {
+	if auditLog.SubjectID == "" || auditLog.ResourceID == "" || auditLog.ActionID == "" {
+		return false
+	}
+	return auditLog.Decision == constants.ResultPermit || auditLog.Decision == constants.ResultDeny
+}
+
+// environmentFromAudit restores the client environment recorded by AuditLogger.LogEvaluation
+func environmentFromAudit(auditContext models.JSONMap) *models.EnvironmentInfo {
+	sourceIP, _ := auditContext["source_ip"].(string)
+	userAgent, _ := auditContext["user_agent"].(string)
+	if sourceIP == "" && userAgent == "" {
+		return nil
+	}
+	return &models.EnvironmentInfo{ClientIP: sourceIP, UserAgent: userAgent}
+}
+
+// RequestsOnResources keeps the requests on resourceIDs, once per subject/resource/action, and
+// clears their Timestamp and AsOf so they are evaluated now: resource changes (Change.Resources)
+// apply to current attributes, and the question is who would lose the access they have today.
+func RequestsOnResources(requests []*models.EvaluationRequest, resourceIDs []string) []*models.EvaluationRequest {
+	wanted := make(map[string]bool, len(resourceIDs))
+	for _, id := range resourceIDs {
+		wanted[id] = true
+	}
+
+	seen := make(map[string]bool)
+	kept := []*models.EvaluationRequest{}
+	for _, request := range requests {
+		if !wanted[request.ResourceID] || request.Subject == nil {
+			continue
+		}
+		key := request.Subject.GetID() + "\x00" + request.ResourceID + "\x00" + request.Action
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		current := *request
+		current.Timestamp = nil
+		current.AsOf = nil
+		kept = append(kept, &current)
+	}
+	return kept
+}
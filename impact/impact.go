@@ -0,0 +1,196 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/quota"
+	"abac_go_example/storage"
+)
+
+// Change is a proposed modification of the active policy set and of resource attributes
+type Change struct {
+	Upsert []*models.Policy `json:"upsert,omitempty"` // New policies or replacements (matched by ID)
+	Delete []string         `json:"delete,omitempty"` // IDs of policies to remove
+	// Resources are attribute updates merged over the stored attributes, by resource ID
+	// (e.g. a new owner_id). They apply to current attributes, not to point-in-time replays.
+	Resources map[string]models.JSONMap `json:"resources,omitempty"`
+}
+
+// Flip is a request whose decision differs under the proposed change
+type Flip struct {
+	RequestID      string   `json:"request_id"`
+	SubjectID      string   `json:"subject_id"`
+	ResourceID     string   `json:"resource_id"`
+	Action         string   `json:"action"`
+	Before         string   `json:"before"`
+	After          string   `json:"after"`
+	BeforePolicies []string `json:"before_policies"`
+	AfterPolicies  []string `json:"after_policies"`
+}
+
+// Failure is a request that could not be evaluated (e.g. its resource no longer exists)
+type Failure struct {
+	RequestID string `json:"request_id"`
+	Error     string `json:"error"`
+}
+
+// Report summarizes the blast radius of a change over a request corpus
+type Report struct {
+	Evaluated    int       `json:"evaluated"`
+	Unchanged    int       `json:"unchanged"`
+	PermitToDeny int       `json:"permit_to_deny"`
+	DenyToPermit int       `json:"deny_to_permit"`
+	Flips        []Flip    `json:"flips"`
+	Failures     []Failure `json:"failures"`
+}
+
+// Analyzer re-evaluates requests against the current and a proposed policy set
+type Analyzer struct {
+	storage storage.Storage
+	config  *core.PDPConfig
+}
+
+// NewAnalyzer creates an analyzer reading current policies and entities from storage.
+// A nil config uses core.DefaultPDPConfig.
+func NewAnalyzer(store storage.Storage, config *core.PDPConfig) *Analyzer {
+	if config == nil {
+		config = core.DefaultPDPConfig()
+	}
+	return &Analyzer{storage: store, config: config}
+}
+
+// Analyze evaluates every request under the current policies and under the change.
+// Nothing is written: both PDPs are private to the call and publish no decisions.
+func (a *Analyzer) Analyze(change *Change, requests []*models.EvaluationRequest) (*Report, error) {
+	if change == nil {
+		change = &Change{}
+	}
+
+	current, err := a.storage.GetPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	before := core.NewPolicyDecisionPointWithConfig(a.storage, a.replayConfig())
+	after := core.NewPolicyDecisionPointWithConfig(&changeOverlay{
+		Storage:   a.storage,
+		policies:  applyChange(current, change),
+		resources: change.Resources,
+	}, a.replayConfig())
+
+	report := &Report{Flips: []Flip{}, Failures: []Failure{}}
+	for _, request := range requests {
+		beforeDecision, err := before.Evaluate(request)
+		if err == nil {
+			var afterDecision *models.Decision
+			afterDecision, err = after.Evaluate(request)
+			if err == nil {
+				report.Record(request, beforeDecision, afterDecision)
+				continue
+			}
+		}
+		report.Failures = append(report.Failures, Failure{RequestID: request.RequestID, Error: err.Error()})
+	}
+
+	return report, nil
+}
+
+// replayConfig copies the analyzer config without side effects on live state:
+// no decision publishing, no deny cache and private quota counters.
+func (a *Analyzer) replayConfig() *core.PDPConfig {
+	config := *a.config
+	config.DecisionSink = nil
+	config.DenyCache = nil
+	config.EnableStats = false
+	config.CounterProvider = quota.NewMemoryCounterProvider()
+	return &config
+}
+
+// Record compares the decisions of one request before and after a change
+func (r *Report) Record(request *models.EvaluationRequest, before, after *models.Decision) {
+	r.Evaluated++
+	if before.Result == after.Result {
+		r.Unchanged++
+		return
+	}
+
+	switch {
+	case before.Result == constants.ResultPermit:
+		r.PermitToDeny++
+	case after.Result == constants.ResultPermit:
+		r.DenyToPermit++
+	}
+
+	subjectID := ""
+	if request.Subject != nil {
+		subjectID = request.Subject.GetID()
+	}
+	r.Flips = append(r.Flips, Flip{
+		RequestID:      request.RequestID,
+		SubjectID:      subjectID,
+		ResourceID:     request.ResourceID,
+		Action:         request.Action,
+		Before:         before.Result,
+		After:          after.Result,
+		BeforePolicies: before.MatchedPolicies,
+		AfterPolicies:  after.MatchedPolicies,
+	})
+}
+
+// applyChange returns the enabled policies that would be active after the change
+func applyChange(current []*models.Policy, change *Change) []*models.Policy {
+	byID := make(map[string]*models.Policy, len(current)+len(change.Upsert))
+	for _, policy := range current {
+		byID[policy.ID] = policy
+	}
+	for _, policy := range change.Upsert {
+		byID[policy.ID] = policy
+	}
+	for _, id := range change.Delete {
+		delete(byID, id)
+	}
+
+	policies := make([]*models.Policy, 0, len(byID))
+	for _, policy := range byID {
+		if policy.Enabled {
+			policies = append(policies, policy)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return policies
+}
+
+// changeOverlay serves the proposed policy set and resource attributes on top of an existing storage
+type changeOverlay struct {
+	storage.Storage
+	policies  []*models.Policy
+	resources map[string]models.JSONMap
+}
+
+// GetPolicies returns the proposed policies instead of the stored ones
+func (o *changeOverlay) GetPolicies() ([]*models.Policy, error) {
+	return o.policies, nil
+}
+
+// GetResource returns a copy of the stored resource with the proposed attribute updates merged in
+func (o *changeOverlay) GetResource(id string) (*models.Resource, error) {
+	resource, err := o.Storage.GetResource(id)
+	updates, changed := o.resources[id]
+	if err != nil || !changed {
+		return resource, err
+	}
+
+	copied := *resource
+	copied.Attributes = make(models.JSONMap, len(resource.Attributes)+len(updates))
+	for key, value := range resource.Attributes {
+		copied.Attributes[key] = value
+	}
+	for key, value := range updates {
+		copied.Attributes[key] = value
+	}
+	return &copied, nil
+}
@@ -0,0 +1,178 @@
+package impact
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+func statementPolicy(id, effect, action string) *models.Policy {
+	return &models.Policy{
+		ID:      id,
+		Enabled: true,
+		Statement: []models.PolicyStatement{
+			{
+				Sid:      id,
+				Effect:   effect,
+				Action:   models.JSONActionResource{Single: action},
+				Resource: models.JSONActionResource{Single: "api:documents:*"},
+			},
+		},
+	}
+}
+
+func newImpactStorage() *storage.MockStorage {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateAction(&models.Action{ID: "document:delete", ActionName: "document:delete"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:test.pdf", ResourceID: "api:documents:test.pdf"})
+	mockStorage.SetPolicies([]*models.Policy{statementPolicy("pol-read", "Allow", "document:read")})
+	return mockStorage
+}
+
+func resolveMockSubject(subjectID string) (models.SubjectInterface, error) {
+	if subjectID == "ghost" {
+		return nil, fmt.Errorf("subject not found")
+	}
+	return models.CreateMockSubjectWithAttributes(subjectID, map[string]interface{}{}), nil
+}
+
+func corpus() []*models.EvaluationRequest {
+	request := func(id, action string) *models.EvaluationRequest {
+		return &models.EvaluationRequest{
+			RequestID:  id,
+			Subject:    models.CreateMockSubjectWithAttributes("user-1", map[string]interface{}{}),
+			ResourceID: "api:documents:test.pdf",
+			Action:     action,
+			Context:    map[string]interface{}{},
+		}
+	}
+	return []*models.EvaluationRequest{request("req-read", "document:read"), request("req-delete", "document:delete")}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name         string
+		change       *Change
+		permitToDeny int
+		denyToPermit int
+		flipped      string
+	}{
+		{"no change", &Change{}, 0, 0, ""},
+		{"new deny", &Change{Upsert: []*models.Policy{statementPolicy("pol-deny-read", "Deny", "document:read")}}, 1, 0, "req-read"},
+		{"new allow", &Change{Upsert: []*models.Policy{statementPolicy("pol-delete", "Allow", "document:delete")}}, 0, 1, "req-delete"},
+		{"delete policy", &Change{Delete: []string{"pol-read"}}, 1, 0, "req-read"},
+		{"replace policy", &Change{Upsert: []*models.Policy{statementPolicy("pol-read", "Allow", "document:delete")}}, 1, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := newImpactStorage()
+			report, err := NewAnalyzer(mockStorage, nil).Analyze(tt.change, corpus())
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+
+			if report.Evaluated != 2 || report.PermitToDeny != tt.permitToDeny || report.DenyToPermit != tt.denyToPermit {
+				t.Errorf("Unexpected report: %+v", report)
+			}
+			if tt.flipped != "" && (len(report.Flips) != 1 || report.Flips[0].RequestID != tt.flipped) {
+				t.Errorf("Expected %s to flip, got %+v", tt.flipped, report.Flips)
+			}
+
+			// The stored policy set is never modified
+			if policies, _ := mockStorage.GetPolicies(); len(policies) != 1 || policies[0].Statement[0].Action.Single != "document:read" {
+				t.Errorf("Stored policies were modified: %+v", policies)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDisabledUpsert(t *testing.T) {
+	deny := statementPolicy("pol-deny-read", "Deny", "document:read")
+	deny.Enabled = false
+
+	report, err := NewAnalyzer(newImpactStorage(), nil).Analyze(&Change{Upsert: []*models.Policy{deny}}, corpus())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(report.Flips) != 0 {
+		t.Errorf("Disabled policies must not affect decisions, got %+v", report.Flips)
+	}
+}
+
+func TestRequestsFromAuditLogs(t *testing.T) {
+	mockStorage := newImpactStorage()
+	entries := []*models.AuditLog{
+		{RequestID: "req-1", SubjectID: "user-1", ResourceID: "api:documents:test.pdf", ActionID: "document:read", Decision: constants.ResultPermit,
+			Context: models.JSONMap{"source_ip": "10.0.0.1"}},
+		{RequestID: "req-2", SubjectID: "ghost", ResourceID: "api:documents:test.pdf", ActionID: "document:read", Decision: constants.ResultDeny},
+		{RequestID: "evt-1", SubjectID: "user-1", ActionID: "security_event", Decision: "logged"},
+	}
+	for _, entry := range entries {
+		mockStorage.LogAudit(entry)
+	}
+
+	requests, failures, err := RequestsFromAuditLogs(mockStorage, resolveMockSubject, 10)
+	if err != nil {
+		t.Fatalf("RequestsFromAuditLogs failed: %v", err)
+	}
+	if len(requests) != 1 || requests[0].RequestID != "req-1" || requests[0].Timestamp == nil {
+		t.Fatalf("Unexpected requests: %+v", requests)
+	}
+	if requests[0].Environment == nil || requests[0].Environment.ClientIP != "10.0.0.1" {
+		t.Errorf("Expected client IP to be restored, got %+v", requests[0].Environment)
+	}
+	if len(failures) != 1 || failures[0].RequestID != "req-2" {
+		t.Errorf("Expected unresolvable subject to be reported, got %+v", failures)
+	}
+
+	report, err := NewAnalyzer(mockStorage, nil).Analyze(&Change{Delete: []string{"pol-read"}}, requests)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.PermitToDeny != 1 {
+		t.Errorf("Expected the replayed permit to flip, got %+v", report)
+	}
+}
+
+func TestAnalyzeResourceChange(t *testing.T) {
+	mockStorage := newImpactStorage()
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:plan.pdf", ResourceID: "api:documents:plan.pdf",
+		Attributes: models.JSONMap{"team_id": "team-a", "classification": "internal"}})
+	teamRead := statementPolicy("pol-read", "Allow", "document:read")
+	teamRead.Statement[0].Condition = map[string]interface{}{"StringEquals": map[string]interface{}{"resource.team_id": "team-a"}}
+	mockStorage.SetPolicies([]*models.Policy{teamRead})
+
+	requests := RequestsOnResources(append(corpus(), corpus()...), []string{"api:documents:plan.pdf"})
+	if len(requests) != 0 {
+		t.Fatalf("Expected no request on plan.pdf, got %+v", requests)
+	}
+	replayed := corpus()
+	for _, request := range replayed {
+		request.ResourceID = "api:documents:plan.pdf"
+		request.AsOf = &time.Time{}
+	}
+	requests = RequestsOnResources(append(replayed, corpus()...), []string{"api:documents:plan.pdf"})
+	if len(requests) != 2 || requests[0].AsOf != nil || replayed[0].AsOf == nil {
+		t.Fatalf("Expected the plan.pdf requests evaluated now, got %+v", requests)
+	}
+
+	change := &Change{Resources: map[string]models.JSONMap{"api:documents:plan.pdf": {"team_id": "team-b"}}}
+	report, err := NewAnalyzer(mockStorage, nil).Analyze(change, requests)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.PermitToDeny != 1 || len(report.Flips) != 1 || report.Flips[0].Action != "document:read" {
+		t.Errorf("Expected the team-a read to be lost, got %+v", report)
+	}
+
+	// The stored resource is never modified
+	if resource, _ := mockStorage.GetResource("api:documents:plan.pdf"); resource.Attributes["team_id"] != "team-a" {
+		t.Errorf("Stored resource was modified: %+v", resource.Attributes)
+	}
+}
@@ -0,0 +1,278 @@
+// Package importer loads subjects, resources and policies from NDJSON streams in batches,
+// reporting failures per record instead of aborting the whole import.
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/models"
+	"abac_go_example/schema"
+	"abac_go_example/storage"
+)
+
+// Kind selects the entity type of an import stream
+type Kind string
+
+const (
+	KindSubjects  Kind = "subjects"
+	KindResources Kind = "resources"
+	KindPolicies  Kind = "policies"
+)
+
+const (
+	// MaxRecordSize is the longest accepted NDJSON line
+	MaxRecordSize = 4 * 1024 * 1024
+	// DefaultActor is recorded in the policy change audit trail when SetActor was not called
+	DefaultActor = "importer"
+	// MaxReportedErrors caps Report.Errors; Report.Failed still counts every failure
+	MaxReportedErrors = 100
+)
+
+// RecordError describes a record that was not imported
+type RecordError struct {
+	Line  int    `json:"line"` // 1-based line number in the stream
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// Report summarizes an import
+type Report struct {
+	Kind     Kind          `json:"kind"`
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []RecordError `json:"errors"`
+}
+
+// record is a decoded line waiting in the current batch
+type record struct {
+	line   int
+	id     string
+	entity interface{} // *models.Subject, *models.Resource or *models.Policy
+}
+
+// Importer streams NDJSON records into storage using bulk inserts
+type Importer struct {
+	store     storage.Storage
+	batchSize int
+	actor     string
+}
+
+// NewImporter creates an importer writing to store
+func NewImporter(store storage.Storage) *Importer {
+	return &Importer{store: store, batchSize: storage.BulkInsertBatchSize, actor: DefaultActor}
+}
+
+// SetBatchSize sets the number of records written per bulk insert
+func (im *Importer) SetBatchSize(size int) {
+	if size > 0 {
+		im.batchSize = size
+	}
+}
+
+// SetActor sets who is recorded in the policy change audit trail for imported policies
+func (im *Importer) SetActor(actor string) {
+	im.actor = actor
+}
+
+// ParseKind validates an import kind
+func ParseKind(value string) (Kind, error) {
+	switch kind := Kind(value); kind {
+	case KindSubjects, KindResources, KindPolicies:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown import kind %q (expected subjects, resources or policies)", value)
+	}
+}
+
+// Import reads one JSON document per line from r. Blank lines are skipped. Invalid records and records
+// rejected by storage are reported in the returned Report; a batch that fails as a whole is retried one
+// record at a time so each failure is attributed to its line. The error is non-nil only when the stream
+// cannot be read, in which case the report covers the records read so far.
+func (im *Importer) Import(kind Kind, r io.Reader) (*Report, error) {
+	if _, err := ParseKind(string(kind)); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Kind: kind, Errors: []RecordError{}}
+	seen := make(map[string]int)
+	batch := make([]record, 0, im.batchSize)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxRecordSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(strings.TrimSpace(string(raw))) == 0 {
+			continue
+		}
+
+		rec, err := decodeRecord(kind, raw)
+		if err != nil {
+			report.fail(line, rec.id, err)
+			continue
+		}
+		rec.line = line
+		if previous, duplicate := seen[rec.id]; duplicate {
+			report.fail(line, rec.id, fmt.Errorf("duplicate id, first seen on line %d", previous))
+			continue
+		}
+		seen[rec.id] = line
+
+		batch = append(batch, rec)
+		if len(batch) >= im.batchSize {
+			im.flush(kind, batch, report)
+			batch = batch[:0]
+		}
+	}
+	im.flush(kind, batch, report)
+
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Line < report.Errors[j].Line })
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("failed to read import stream after line %d: %w", line, err)
+	}
+	return report, nil
+}
+
+// Decode parses and validates one record of kind with the rules Import applies to each line.
+// The returned entity is a *models.Subject, *models.Resource or *models.Policy.
+func Decode(kind Kind, raw []byte) (string, interface{}, error) {
+	rec, err := decodeRecord(kind, raw)
+	return rec.id, rec.entity, err
+}
+
+// decodeRecord parses and validates one line; the returned record carries the ID even on validation errors
+func decodeRecord(kind Kind, raw []byte) (record, error) {
+	switch kind {
+	case KindSubjects:
+		var subject models.Subject
+		if err := json.Unmarshal(raw, &subject); err != nil {
+			return record{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+		rec := record{id: subject.ID, entity: &subject}
+		if subject.ID == "" || subject.SubjectType == "" {
+			return rec, fmt.Errorf("id and subject_type are required")
+		}
+		return rec, nil
+
+	case KindResources:
+		var resource models.Resource
+		if err := json.Unmarshal(raw, &resource); err != nil {
+			return record{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+		rec := record{id: resource.ID, entity: &resource}
+		if resource.ID == "" || resource.ResourceType == "" {
+			return rec, fmt.Errorf("id and resource_type are required")
+		}
+		return rec, nil
+
+	default:
+		var policy models.Policy
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return record{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+		rec := record{id: policy.ID, entity: &policy}
+		if errs := schema.ValidatePolicy(raw); len(errs) > 0 {
+			messages := make([]string, len(errs))
+			for i, validationErr := range errs {
+				messages[i] = validationErr.Error()
+			}
+			return rec, fmt.Errorf("policy does not match schema: %s", strings.Join(messages, "; "))
+		}
+		var invalid []string
+		for i, statement := range policy.Statement {
+			for _, regex := range conditions.InvalidRegexes(statement.Condition) {
+				invalid = append(invalid, fmt.Sprintf("statement[%d] %s %s: %s", i, regex.Operator, regex.Key, regex.Message))
+			}
+		}
+		if len(invalid) > 0 {
+			return rec, fmt.Errorf("policy has invalid regular expressions: %s", strings.Join(invalid, "; "))
+		}
+		return rec, nil
+	}
+}
+
+// flush bulk-inserts batch, falling back to one insert per record when the batch is rejected
+func (im *Importer) flush(kind Kind, batch []record, report *Report) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := im.bulkCreate(kind, batch); err == nil {
+		report.Imported += len(batch)
+		for _, rec := range batch {
+			im.recordCreated(rec)
+		}
+		return
+	}
+
+	for _, rec := range batch {
+		if err := im.create(rec.entity); err != nil {
+			report.fail(rec.line, rec.id, err)
+			continue
+		}
+		report.Imported++
+		im.recordCreated(rec)
+	}
+}
+
+// recordCreated adds an imported policy to the policy change audit trail
+func (im *Importer) recordCreated(rec record) {
+	policy, ok := rec.entity.(*models.Policy)
+	if !ok {
+		return
+	}
+	if err := storage.RecordPolicyChange(im.store, models.PolicyChangeCreate, im.actor, nil, policy); err != nil {
+		log.Printf("Failed to record import of policy %s: %v", policy.ID, err)
+	}
+}
+
+// bulkCreate writes a batch with the storage bulk method for kind
+func (im *Importer) bulkCreate(kind Kind, batch []record) error {
+	switch kind {
+	case KindSubjects:
+		subjects := make([]*models.Subject, len(batch))
+		for i, rec := range batch {
+			subjects[i] = rec.entity.(*models.Subject)
+		}
+		return im.store.BulkCreateSubjects(subjects)
+	case KindResources:
+		resources := make([]*models.Resource, len(batch))
+		for i, rec := range batch {
+			resources[i] = rec.entity.(*models.Resource)
+		}
+		return im.store.BulkCreateResources(resources)
+	default:
+		policies := make([]*models.Policy, len(batch))
+		for i, rec := range batch {
+			policies[i] = rec.entity.(*models.Policy)
+		}
+		return im.store.BulkCreatePolicies(policies)
+	}
+}
+
+// create writes a single record
+func (im *Importer) create(entity interface{}) error {
+	switch e := entity.(type) {
+	case *models.Subject:
+		return im.store.CreateSubject(e)
+	case *models.Resource:
+		return im.store.CreateResource(e)
+	default:
+		return im.store.CreatePolicy(entity.(*models.Policy))
+	}
+}
+
+// fail records a failed record, keeping at most MaxReportedErrors details
+func (report *Report) fail(line int, id string, err error) {
+	report.Failed++
+	if len(report.Errors) < MaxReportedErrors {
+		report.Errors = append(report.Errors, RecordError{Line: line, ID: id, Error: err.Error()})
+	}
+}
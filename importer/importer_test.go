@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"abac_go_example/storage"
+)
+
+func TestImport_BatchFallbackReportsConflicts(t *testing.T) {
+	store := storage.NewSQLiteTestStorage(t)
+
+	var lines []string
+	for i := 1; i <= 5; i++ {
+		lines = append(lines, fmt.Sprintf(`{"id": "sub-%d", "subject_type": "user", "attributes": {"level": %d}}`, i, i))
+	}
+	im := NewImporter(store)
+	im.SetBatchSize(2)
+	report, err := im.Import(KindSubjects, strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil || report.Imported != 5 || report.Failed != 0 {
+		t.Fatalf("Expected 5 imported, got %+v (err=%v)", report, err)
+	}
+
+	// sub-2 already exists: its batch is retried record by record, so only line 2 fails
+	report, err = im.Import(KindSubjects, strings.NewReader(strings.Join([]string{
+		`{"id": "sub-6", "subject_type": "user"}`,
+		`{"id": "sub-2", "subject_type": "user"}`,
+		`{"id": "sub-7", "subject_type": "user"}`,
+		`{"id": "sub-7", "subject_type": "user"}`,
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if report.Imported != 2 || report.Failed != 2 {
+		t.Fatalf("Expected 2 imported and 2 failed, got %+v", report)
+	}
+	if report.Errors[0].Line != 2 || report.Errors[0].ID != "sub-2" || report.Errors[1].Line != 4 {
+		t.Errorf("Unexpected record errors: %+v", report.Errors)
+	}
+
+	subjects, _ := store.GetAllSubjects()
+	if len(subjects) != 7 {
+		t.Errorf("Expected 7 subjects, got %d", len(subjects))
+	}
+}
+
+func TestImport_Policies(t *testing.T) {
+	store := storage.NewMockStorage()
+	report, err := NewImporter(store).Import(KindPolicies, strings.NewReader(strings.Join([]string{
+		`{"id": "pol-1", "policy_name": "Read", "version": "2024-10-21", "statement": [{"Effect": "Allow", "Action": "document:read", "Resource": "*"}]}`,
+		`{"id": "pol-2", "policy_name": "Broken", "version": "2024-10-21", "statement": [{"Effect": "Maybe", "Action": "document:read", "Resource": "*"}]}`,
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if report.Imported != 1 || report.Failed != 1 || report.Errors[0].ID != "pol-2" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if changes, _ := store.GetPolicyChanges("pol-1", 0); len(changes) != 1 || changes[0].Actor != DefaultActor {
+		t.Errorf("Expected the import to be recorded, got %+v", changes)
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	if _, err := ParseKind("actions"); err == nil {
+		t.Error("Expected unknown kind to be rejected")
+	}
+
+	// A line longer than MaxRecordSize stops the import with an error and a partial report
+	stream := `{"id": "res-1", "resource_type": "document"}` + "\n" + strings.Repeat("x", MaxRecordSize+1)
+	report, err := NewImporter(storage.NewMockStorage()).Import(KindResources, strings.NewReader(stream))
+	if err == nil || report == nil || report.Imported != 1 {
+		t.Errorf("Expected read error with 1 imported record, got %+v (err=%v)", report, err)
+	}
+
+	var lines []string
+	for i := 0; i < MaxReportedErrors+10; i++ {
+		lines = append(lines, `{}`)
+	}
+	report, _ = NewImporter(storage.NewMockStorage()).Import(KindResources, strings.NewReader(strings.Join(lines, "\n")))
+	if report.Failed != MaxReportedErrors+10 || len(report.Errors) != MaxReportedErrors {
+		t.Errorf("Expected capped error list, got %d failed with %d errors", report.Failed, len(report.Errors))
+	}
+}
@@ -0,0 +1,141 @@
+package localization
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// Supported languages
+const (
+	LanguageEnglish    = "en"
+	LanguageVietnamese = "vi"
+)
+
+// Catalog maps decision reason codes to end-user messages per language.
+// Templates reference ReasonDetails entries with {key} placeholders, e.g. "Denied by {statement}".
+type Catalog struct {
+	messages map[string]map[string]string
+	fallback string
+}
+
+// NewCatalog creates an empty catalog that falls back to the given language
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{
+		messages: make(map[string]map[string]string),
+		fallback: normalizeLanguage(fallback),
+	}
+}
+
+// DefaultCatalog returns a catalog with English and Vietnamese messages for the built-in reason codes
+func DefaultCatalog() *Catalog {
+	c := NewCatalog(LanguageEnglish)
+
+	c.Register(LanguageEnglish, constants.ReasonCodeDeniedByStatement, "Access denied by policy rule {statement}.")
+	c.Register(LanguageEnglish, constants.ReasonCodeAllowedByStatements, "Access granted.")
+	c.Register(LanguageEnglish, constants.ReasonCodeImplicitDeny, "You do not have permission to perform this action.")
+	c.Register(LanguageEnglish, constants.ReasonCodeInvalidRequest, "The request is invalid and could not be authorized.")
+	c.Register(LanguageEnglish, constants.ReasonCodeEvaluationError, "Authorization could not be completed. Please try again later.")
+	c.Register(LanguageEnglish, constants.ReasonCodeLockdown, "Access is temporarily suspended. Please try again later.")
+	c.Register(LanguageEnglish, constants.ReasonCodeAllowedByException, "Access granted by exception {exception}.")
+	c.Register(LanguageEnglish, constants.ReasonCodeDeniedByException, "Access denied by exception {exception}.")
+	c.Register(LanguageEnglish, constants.ReasonCodeDefaultPermit, "Access granted.")
+	c.Register(LanguageEnglish, constants.ReasonCodeReadOnlyPermit, "You have read-only access to this resource.")
+	c.Register(LanguageEnglish, constants.ReasonCodePolicyQuarantined, "Authorization could not be completed. Please try again later.")
+
+	c.Register(LanguageVietnamese, constants.ReasonCodeDeniedByStatement, "Truy cập bị từ chối bởi quy tắc chính sách {statement}.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeAllowedByStatements, "Truy cập được cho phép.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeImplicitDeny, "Bạn không có quyền thực hiện thao tác này.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeInvalidRequest, "Yêu cầu không hợp lệ và không thể được cấp quyền.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeEvaluationError, "Không thể hoàn tất việc kiểm tra quyền. Vui lòng thử lại sau.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeLockdown, "Quyền truy cập tạm thời bị đình chỉ. Vui lòng thử lại sau.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeAllowedByException, "Truy cập được cho phép theo ngoại lệ {exception}.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeDeniedByException, "Truy cập bị từ chối theo ngoại lệ {exception}.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeDefaultPermit, "Truy cập được cho phép.")
+	c.Register(LanguageVietnamese, constants.ReasonCodeReadOnlyPermit, "Bạn chỉ có quyền đọc tài nguyên này.")
+	c.Register(LanguageVietnamese, constants.ReasonCodePolicyQuarantined, "Không thể hoàn tất việc kiểm tra quyền. Vui lòng thử lại sau.")
+
+	return c
+}
+
+// Register adds or replaces the message template for a reason code in a language
+func (c *Catalog) Register(lang, code, template string) {
+	lang = normalizeLanguage(lang)
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[string]string)
+	}
+	c.messages[lang][code] = template
+}
+
+// Languages returns the languages that have at least one message, sorted
+func (c *Catalog) Languages() []string {
+	languages := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Message renders the message for code in lang, falling back to the catalog's fallback language.
+// Unknown codes return an empty string so callers can keep the raw reason.
+func (c *Catalog) Message(lang, code string, details map[string]string) string {
+	template, ok := c.messages[normalizeLanguage(lang)][code]
+	if !ok {
+		template, ok = c.messages[c.fallback][code]
+		if !ok {
+			return ""
+		}
+	}
+
+	for key, value := range details {
+		template = strings.ReplaceAll(template, "{"+key+"}", value)
+	}
+	return template
+}
+
+// NegotiateLanguage picks the best supported language from an Accept-Language header value.
+// Regional variants match their primary language ("vi-VN" -> "vi"); the fallback is returned when nothing matches.
+func (c *Catalog) NegotiateLanguage(acceptLanguage string) string {
+	best := c.fallback
+	bestQuality := 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := normalizeLanguage(fields[0])
+		if lang == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if _, supported := c.messages[lang]; supported && quality > bestQuality {
+			best = lang
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// normalizeLanguage reduces a language tag to its lowercase primary subtag
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "-_"); idx >= 0 {
+		lang = lang[:idx]
+	}
+	if lang == "*" {
+		return ""
+	}
+	return lang
+}
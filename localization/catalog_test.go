@@ -0,0 +1,53 @@
+package localization
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func TestCatalogNegotiateLanguage(t *testing.T) {
+	c := DefaultCatalog()
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", LanguageEnglish},
+		{"vi", LanguageVietnamese},
+		{"vi-VN,vi;q=0.9,en;q=0.8", LanguageVietnamese},
+		{"en-US,en;q=0.9,vi;q=0.8", LanguageEnglish},
+		{"fr-FR,vi;q=0.5", LanguageVietnamese},
+		{"fr-FR,de;q=0.7", LanguageEnglish},
+		{"*", LanguageEnglish},
+		{"en;q=0.3,vi;q=0.7", LanguageVietnamese},
+	}
+
+	for _, tt := range tests {
+		if got := c.NegotiateLanguage(tt.header); got != tt.expected {
+			t.Errorf("NegotiateLanguage(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestCatalogMessage(t *testing.T) {
+	c := DefaultCatalog()
+	details := map[string]string{constants.ReasonDetailStatement: "DenyProbation"}
+
+	if got := c.Message(LanguageEnglish, constants.ReasonCodeDeniedByStatement, details); got != "Access denied by policy rule DenyProbation." {
+		t.Errorf("Unexpected English message: %q", got)
+	}
+	if got := c.Message(LanguageVietnamese, constants.ReasonCodeDeniedByStatement, details); got != "Truy cập bị từ chối bởi quy tắc chính sách DenyProbation." {
+		t.Errorf("Unexpected Vietnamese message: %q", got)
+	}
+
+	// Unknown language falls back to English
+	if got := c.Message("fr", constants.ReasonCodeImplicitDeny, nil); got != "You do not have permission to perform this action." {
+		t.Errorf("Expected English fallback, got %q", got)
+	}
+
+	// Unknown code yields empty message
+	if got := c.Message(LanguageEnglish, "UNKNOWN_CODE", nil); got != "" {
+		t.Errorf("Expected empty message for unknown code, got %q", got)
+	}
+}
@@ -11,37 +11,259 @@ import (
 	"syscall"
 	"time"
 
+	"abac_go_example/adminauth"
+	"abac_go_example/attrcrypt"
+	"abac_go_example/attributes"
+	"abac_go_example/audit"
+	"abac_go_example/bundle"
+	"abac_go_example/constants"
 	"abac_go_example/evaluator/core"
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/events"
+	"abac_go_example/extauthz"
+	"abac_go_example/holidays"
+	"abac_go_example/localization"
 	"abac_go_example/models"
+	"abac_go_example/pep"
+	"abac_go_example/reconcile"
+	"abac_go_example/redaction"
+	"abac_go_example/sink"
 	"abac_go_example/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// `go run . gen-client client/client_gen.go` regenerates the typed client instead of serving
+	if len(os.Args) == 3 && os.Args[1] == "gen-client" {
+		if err := writeClient(os.Args[2]); err != nil {
+			log.Fatalf("Failed to generate client: %v", err)
+		}
+		return
+	}
+
 	fmt.Println("🚀 Starting ABAC HTTP Service with Gin...")
 
-	// Khởi tạo PostgreSQL storage
-	dbConfig := storage.DefaultDatabaseConfig()
-	storageInstance, err := storage.NewPostgreSQLStorage(dbConfig)
+	// Khởi tạo storage (PostgreSQL mặc định, DB_DRIVER=sqlite cho embedded deployments)
+	storageInstance, err := newStorage(os.Getenv("DB_DRIVER"))
 	if err != nil {
-		log.Fatalf("Failed to initialize PostgreSQL storage: %v", err)
+		log.Fatalf("Failed to initialize storage: %v", err)
 	}
 	defer storageInstance.Close()
 
-	// Khởi tạo PDP
-	pdp := core.NewPolicyDecisionPoint(storageInstance)
+	// Mã hóa PII attributes at rest (ABAC_ENCRYPTED_ATTRIBUTES + ABAC_ATTRIBUTE_ENCRYPTION_KEYS)
+	attributeCipher, err := attrcrypt.CipherFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure attribute encryption: %v", err)
+	}
+	if attributeCipher != nil {
+		if err := storageInstance.EnableAttributeEncryption(attributeCipher); err != nil {
+			log.Fatalf("Failed to enable attribute encryption: %v", err)
+		}
+		log.Printf("Attribute encryption enabled for %v", attributeCipher.SensitiveKeys())
+	}
+
+	// Background jobs: audit partition maintenance & retention (bật khi có AUDIT_RETENTION_DAYS)
+	retentionCtx, stopRetention := context.WithCancel(context.Background())
+	defer stopRetention()
+	if retentionConfig := storage.AuditRetentionConfigFromEnv(); retentionConfig != nil {
+		archiveDir := os.Getenv("AUDIT_ARCHIVE_DIR")
+		if archiveDir == "" {
+			archiveDir = "audit-archive"
+		}
+		retentionJob := storage.NewAuditRetentionJob(storageInstance, storage.NewFileAuditArchiver(archiveDir), retentionConfig)
+		go retentionJob.Start(retentionCtx)
+	}
 
-	// Khởi tạo SubjectFactory với loaders
-	userLoader := storage.NewStorageUserLoader(storageInstance)
-	serviceLoader := storage.NewStorageServiceLoader(storageInstance)
-	subjectFactory := models.NewSubjectFactory(userLoader, serviceLoader)
+	// Store-and-forward audit logs: buffer to local disk while the audit DB is unavailable (AUDIT_SPOOL_DIR)
+	var auditWriter storage.AuditWriter = storageInstance
+	if spoolConfig := storage.AuditSpoolConfigFromEnv(); spoolConfig != nil {
+		spool, err := storage.NewStoreAndForwardAuditWriter(storageInstance, spoolConfig)
+		if err != nil {
+			log.Fatalf("Failed to open audit spool: %v", err)
+		}
+		go spool.Start(retentionCtx)
+		auditWriter = spool
+	}
+
+	// Disable policies past their expires_at (POLICY_EXPIRY_INTERVAL, default 1m)
+	go storage.NewPolicyExpiryJob(storageInstance, storage.PolicyExpiryIntervalFromEnv()).Start(retentionCtx)
+	// Apply due policy schedules (POLICY_SCHEDULE_INTERVAL, default 1m)
+	go storage.NewPolicyScheduleJob(storageInstance, storage.PolicyScheduleIntervalFromEnv()).Start(retentionCtx)
+
+	// Khởi tạo PDP với decision stream cho admin dashboards
+	decisions := sink.NewBroadcaster()
+	pdpConfig := core.DefaultPDPConfig()
+	pdpConfig.DecisionSink = decisions
+	pdpConfig.DenyCache = core.DenyCacheConfigFromEnv()                 // ABAC_DENY_CACHE_TTL, e.g. "2s"
+	pdpConfig.AttributeCache = attributes.AttributeCacheConfigFromEnv() // ABAC_ATTRIBUTE_CACHE_TTL, e.g. "30s"
+	pdpConfig.Parallel = core.ParallelConfigFromEnv()                   // ABAC_EVAL_PARALLELISM, e.g. "8"
+	pdpConfig.Namespace = os.Getenv(constants.EnvNamespace)             // ABAC_NAMESPACE, e.g. "document-service"
+	pdpConfig.ActionCatalog, err = matchers.ActionCatalogFromEnv()      // ABAC_ACTION_CATALOG, e.g. "action_catalog.json"
+	if err != nil {
+		log.Fatalf("Failed to load action catalog: %v", err)
+	}
+	pdpConfig.Holidays, err = holidays.CalendarFromEnv() // ABAC_HOLIDAY_CALENDAR, e.g. "holidays.json", and/or ABAC_HOLIDAY_CALENDAR_URL
+	if err != nil {
+		log.Fatalf("Failed to load holiday calendar: %v", err)
+	}
+	pdpConfig.CompileMode, err = core.CompileModeFromEnv() // ABAC_COMPILE_MODE=eager|lazy
+	if err != nil {
+		log.Fatalf("Invalid compile mode: %v", err)
+	}
+	pdpConfig.DecisionTTL, err = core.DecisionTTLConfigFromEnv() // ABAC_DECISION_TTL, e.g. "5m"
+	if err != nil {
+		log.Fatalf("Invalid decision TTL: %v", err)
+	}
+	pdpConfig.DefaultDecisions, err = core.DefaultDecisionRulesFromEnv() // ABAC_DEFAULT_DECISIONS, e.g. "default_decisions.json"
+	if err != nil {
+		log.Fatalf("Failed to load default decision rules: %v", err)
+	}
+	pdpConfig.ConditionCoverage, err = core.ConditionCoverageFromEnv() // ABAC_CONDITION_COVERAGE=true
+	if err != nil {
+		log.Fatalf("Invalid condition coverage setting: %v", err)
+	}
+	pdpConfig.MissingAttributes, err = core.MissingAttributeConfigFromEnv() // ABAC_MISSING_ATTRIBUTE_LOG_INTERVAL, e.g. "1m"
+	if err != nil {
+		log.Fatalf("Invalid missing attribute telemetry setting: %v", err)
+	}
+	pdpConfig.Quarantine, err = core.QuarantineConfigFromEnv() // ABAC_POLICY_QUARANTINE_THRESHOLD, e.g. "50ms"
+	if err != nil {
+		log.Fatalf("Invalid policy quarantine setting: %v", err)
+	}
+	pdpConfig.BundleVerifier, err = bundle.VerifierFromEnv() // ABAC_BUNDLE_PUBLIC_KEY, e.g. "bundle_public.pem"
+	if err != nil {
+		log.Fatalf("Failed to load bundle verification key: %v", err)
+	}
+	pdp := core.NewPolicyDecisionPointWithConfig(storageInstance, pdpConfig)
+
+	// Incident response kill switch (ABAC_LOCKDOWN=deny_all|safelist); also toggled via /api/v1/lockdown
+	lockdown, err := core.LockdownFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid lockdown configuration: %v", err)
+	}
+	if lockdown != nil {
+		pdp.SetLockdown(lockdown)
+		if err := auditWriter.LogAudit(lockdownAuditLog(lockdownAuditEnable, lockdown)); err != nil {
+			log.Printf("Failed to audit %s: %v", lockdownAuditEnable, err)
+		}
+		log.Printf("🚨 Starting in lockdown: mode=%s safe_actions=%v", lockdown.Mode, lockdown.SafeActions)
+	}
+
+	// Change events of subjects, resources and policies: cache invalidation, optional event log and webhook
+	changeEvents := events.NewBus()
+	changeEvents.Subscribe("cache", events.NewCacheInvalidationSubscriber(pdp))
+	eventLog, err := events.LogSubscriberFromEnv() // ABAC_EVENT_LOG, e.g. "events.jsonl"
+	if err != nil {
+		log.Fatalf("Failed to open event log: %v", err)
+	}
+	if eventLog != nil {
+		defer eventLog.Close()
+		changeEvents.Subscribe("log", eventLog)
+	}
+	if webhook := events.WebhookSubscriberFromEnv(); webhook != nil { // ABAC_EVENT_WEBHOOK_URL
+		defer webhook.Close()
+		changeEvents.Subscribe("webhook", webhook)
+	}
+	storageInstance.SetEventPublisher(changeEvents)
 
 	// Khởi tạo service
-	service := &ABACService{
-		pdp:            pdp,
-		storage:        storageInstance,
-		subjectFactory: subjectFactory,
+	service := newABACService(storageInstance, pdp)
+	service.decisions = decisions
+	service.audit = auditWriter
+	claimsMapping, err := models.ClaimsMappingFromEnv() // ABAC_CLAIMS_MAPPING, e.g. "claims_mapping.json"
+	if err != nil {
+		log.Fatalf("Failed to load claims mapping: %v", err)
+	}
+	service.subjectFactory.SetClaimsMapping(claimsMapping)
+	service.subjectTypes, err = models.SubjectTypeRegistryFromEnv() // ABAC_SUBJECT_TYPES=default or "subject_types.json"
+	if err != nil {
+		log.Fatalf("Failed to load subject type registry: %v", err)
+	}
+	if service.subjectTypes != nil {
+		storageInstance.SetSubjectTypes(service.subjectTypes)
+		log.Printf("Subject types restricted to %v", service.subjectTypes.Enum())
+	}
+	service.requestAttrs, err = pep.RequestAttributeConfigFromEnv() // ABAC_PEP_BODY_FIELDS, e.g. "amount"; ABAC_PEP_HEADERS, e.g. "X-Channel"
+	if err != nil {
+		log.Fatalf("Invalid PEP request attribute configuration: %v", err)
+	}
+	service.decisionHdrs, err = pep.DecisionHeaderConfigFromEnv() // ABAC_PEP_DECISION_HEADERS, e.g. "request"
+	if err != nil {
+		log.Fatalf("Invalid PEP decision header configuration: %v", err)
+	}
+	service.adminAuth, err = adminauth.AuthenticatorFromEnv() // ABAC_ADMIN_TOKENS, e.g. "admin_tokens.json"
+	if err != nil {
+		log.Fatalf("Failed to load admin tokens: %v", err)
+	}
+	if service.adminAuth != nil {
+		log.Printf("Admin endpoints require bearer tokens from %s", os.Getenv(constants.EnvAdminTokens))
+	}
+	service.bundleSigner, err = bundle.SignerFromEnv() // ABAC_BUNDLE_SIGNING_KEY
+	if err != nil {
+		log.Fatalf("Failed to load bundle signing key: %v", err)
+	}
+	var trustedBundle *bundle.Bundle
+	if pdpConfig.BundleVerifier != nil {
+		// Only policies of the verified bundle are evaluated (ABAC_POLICY_BUNDLE, e.g. "policies.bundle.json")
+		service.bundlePath = os.Getenv(constants.EnvPolicyBundle)
+		if trustedBundle, err = loadTrustedBundle(pdp, service.bundlePath); err != nil {
+			log.Fatalf("Failed to load trusted policy bundle: %v", err)
+		}
+	}
+
+	// Signed bundles polled from HTTP or S3 are hot-swapped into the PDP (ABAC_BUNDLE_URL, e.g. "s3://policies/prod.bundle.json")
+	fetcherConfig, err := bundle.FetcherConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid bundle fetcher configuration: %v", err)
+	}
+	if fetcherConfig != nil {
+		if pdpConfig.BundleVerifier == nil {
+			log.Fatalf("%s requires %s", constants.EnvBundleURL, constants.EnvBundlePublicKey)
+		}
+		service.bundleFetcher, err = bundle.NewFetcher(fetcherConfig, pdpConfig.BundleVerifier, service.applyFetchedBundle)
+		if err != nil {
+			log.Fatalf("Failed to create bundle fetcher: %v", err)
+		}
+		// Downloads older than the bundle loaded at startup are rejected as replays
+		if trustedBundle != nil {
+			service.bundleFetcher.SetCurrent(trustedBundle)
+		}
+		go service.bundleFetcher.Start(retentionCtx)
+		log.Printf("Polling policy bundles from %s every %s", fetcherConfig.URL, fetcherConfig.Interval)
+	}
+
+	// Eager mode compiles every policy before taking traffic; lazy compilation remains the fallback
+	if pdpConfig.CompileMode == core.CompileEager {
+		if _, err := pdp.WarmUp(); err != nil {
+			log.Printf("Policy warm-up failed, compiling lazily: %v", err)
+		}
+	}
+
+	// Declarative manifests reconciled into storage (ABAC_RECONCILE_DIR, e.g. "manifests")
+	reconcileConfig, err := reconcile.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid reconciliation configuration: %v", err)
+	}
+	if reconcileConfig != nil {
+		// Reconciled writes invalidate the PDP caches through the change event bus
+		reconcileJob := reconcile.NewJob(storageInstance, *reconcileConfig)
+		go reconcileJob.Start(retentionCtx)
+		log.Printf("Reconciling manifests in %s every %s (prune=%v, dry_run=%v)",
+			reconcileConfig.Dir, reconcileConfig.Interval, reconcileConfig.Prune, reconcileConfig.DryRun)
+	}
+
+	// Envoy/Istio external authorization over gRPC (ABAC_EXT_AUTHZ_ADDR, e.g. ":9191")
+	if extAuthzAddr := os.Getenv(constants.EnvExtAuthzAddr); extAuthzAddr != "" {
+		extAuthzConfig := extauthz.ConfigFromEnv()
+		extAuthzConfig.RecordDecision = service.recordDecision
+		extAuthz := extauthz.NewServer(pdp, service.subjectFactory, extAuthzConfig)
+		go func() {
+			if err := extAuthz.ListenAndServe(retentionCtx, extAuthzAddr); err != nil {
+				log.Fatalf("Envoy ext_authz server failed: %v", err)
+			}
+		}()
+		log.Printf("Envoy ext_authz gRPC server listening on %s", extAuthzAddr)
 	}
 
 	// Setup Gin router
@@ -50,17 +272,8 @@ func main() {
 	// CORS middleware
 	router.Use(corsMiddleware())
 
-	// Health check (không cần authorization)
-	router.GET("/health", handleHealth)
-
-	// Protected endpoints với ABAC middleware
-	apiV1 := router.Group("/api/v1")
-	{
-		apiV1.GET("/users", service.ABACMiddleware("read"), service.handleUsers)
-		apiV1.POST("/users/create", service.ABACMiddleware("write"), service.handleCreateUser)
-		apiV1.GET("/financial", service.ABACMiddleware("read"), service.handleFinancialData)
-		apiV1.GET("/admin", service.ABACMiddleware("admin"), service.handleAdminPanel)
-	}
+	// Health check, protected endpoints (ABACMiddleware), evaluation API and /openapi.json
+	service.registerRoutes(router)
 
 	// Debug: List all routes (Gin does this automatically in debug mode)
 	// You can add a custom one if needed
@@ -72,10 +285,15 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"routes": routes})
 	})
 
-	// HTTP server
+	// HTTP server; HTTPS with optional mutual TLS when ABAC_TLS_CERT/ABAC_TLS_KEY (and ABAC_TLS_CLIENT_CA) are set
+	tlsConfig, err := pep.TLSConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
 	server := &http.Server{
-		Addr:    ":8081",
-		Handler: router,
+		Addr:      ":8081",
+		Handler:   router,
+		TLSConfig: tlsConfig,
 	}
 
 	// Graceful shutdown
@@ -101,6 +319,46 @@ func main() {
 	fmt.Println("  POST /api/v1/users/create       - Create user (write permission)")
 	fmt.Println("  GET  /api/v1/financial          - Financial data (read permission)")
 	fmt.Println("  GET  /api/v1/admin              - Admin panel (admin permission)")
+	fmt.Println("  GET  /api/v1/{subjects,resources,actions,policies} - Paged lists ?limit=&cursor=&type=&sort= (admin permission)")
+	fmt.Println("  POST /api/v1/policies           - Create a schema-validated policy (admin permission)")
+	fmt.Println("  PUT  /api/v1/policies/:id       - Update a policy, requires If-Match revision (admin permission)")
+	fmt.Println("  DELETE /api/v1/policies/:id     - Delete a policy (admin permission)")
+	fmt.Println("  GET  /api/v1/policies/:id/changes - Policy change audit trail with diffs (admin permission)")
+	fmt.Println("  POST /api/v1/policies/impact    - Decision flips of a proposed policy change (admin permission)")
+	fmt.Println("  POST /api/v1/policies/bulk-toggle - Enable/disable policies by ID or tag (admin permission)")
+	fmt.Println("  GET|POST /api/v1/policy-schedules - Scheduled bulk enable/disable, run_at or cron (admin permission)")
+	fmt.Println("  DELETE /api/v1/policy-schedules/:id - Cancel a policy schedule (admin permission)")
+	fmt.Println("  GET  /api/v1/policies/:id/stats - Policy evaluation stats (admin permission)")
+	fmt.Println("  GET  /api/v1/policies/:id/coverage - Condition coverage of live traffic (admin permission)")
+	fmt.Println("  GET  /api/v1/attributes/missing - Condition paths resolving to nothing (admin permission)")
+	fmt.Println("  GET  /api/v1/deny-cache/stats   - Negative cache stats (admin permission)")
+	fmt.Println("  GET  /api/v1/compile/stats      - Policy compile mode, durations, warm-up (admin permission)")
+	fmt.Println("  GET  /api/v1/subjects/search    - Subjects by attribute ?key=&value= (admin permission)")
+	fmt.Println("  GET  /api/v1/subjects/:id/decisions - Recent decisions, deny rate and top denied resources (admin permission)")
+	fmt.Println("  GET  /api/v1/resources/search   - Resources by attribute ?key=&value= (admin permission)")
+	fmt.Println("  POST /api/v1/resources/transfer-ownership - Bulk owner/team transfer with access impact (admin permission)")
+	fmt.Println("  GET|PUT|DELETE /api/v1/lockdown - Deny-all / safelist kill switch (lockdown:manage permission)")
+	fmt.Println("  GET  /api/v1/exceptions         - Access exceptions ?subject_id= (admin permission)")
+	fmt.Println("  POST /api/v1/exceptions         - Allow/deny one subject/resource/action until expires_at (admin permission)")
+	fmt.Println("  DELETE /api/v1/exceptions/:id   - Revoke an access exception (admin permission)")
+	fmt.Println("  GET  /api/v1/revocations        - Revoked subjects and sessions (admin permission)")
+	fmt.Println("  POST /api/v1/revocations        - Suspend all access of a subject or session (admin permission)")
+	fmt.Println("  DELETE /api/v1/revocations/:id  - Lift a revocation (admin permission)")
+	fmt.Println("  GET  /api/v1/elevations         - Time-limited role/attribute elevations ?subject_id= (admin permission)")
+	fmt.Println("  POST /api/v1/elevations         - Grant roles/attributes until expires_at, \"sudo mode\" (admin permission)")
+	fmt.Println("  DELETE /api/v1/elevations/:id   - End an elevation (admin permission)")
+	fmt.Println("  GET  /api/v1/bundles/export     - Export enabled policies as a signed bundle (admin permission)")
+	fmt.Println("  POST /api/v1/bundles/import     - Verify and load a signed policy bundle (admin permission)")
+	fmt.Println("  GET  /api/v1/bundles/status     - Trusted bundle and rejected policies (admin permission)")
+	fmt.Println("  POST /api/v1/import/:kind      - NDJSON bulk import of subjects/resources/policies (admin permission)")
+	fmt.Println("  GET  /api/v1/schema/policy      - Policy document JSON Schema (no auth)")
+	fmt.Println("  GET  /api/v1/subject-types      - Registered subject types and user.subject_type enum (no auth)")
+	fmt.Println("  POST /api/v1/evaluate           - Evaluate an EvaluationRequest (central PDP)")
+	fmt.Println("  POST /api/v1/evaluate/sessions  - Prepare a session's subject context for reuse by its evaluations")
+	fmt.Println("  DELETE /api/v1/evaluate/sessions/:id - Release a prepared session context")
+	fmt.Println("  POST /api/v1/explain            - Evaluate and explain statement matching")
+	fmt.Println("  GET  /api/v1/decisions/stream   - Live decision events via SSE (admin permission)")
+	fmt.Println("  GET  /openapi.json              - OpenAPI 3 document of all endpoints (no auth)")
 	fmt.Println("\n💡 Usage examples:")
 	fmt.Println("  curl http://localhost:8081/health")
 	fmt.Println("  curl -H 'X-Subject-ID: sub-001' http://localhost:8081/api/v1/users")
@@ -112,7 +370,12 @@ func main() {
 	fmt.Println("  sub-003: Payment Service - Service account")
 	fmt.Println("  sub-004: Bob Wilson (On probation) - Limited access")
 
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "") // Certificates come from tlsConfig
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 
@@ -124,13 +387,75 @@ type ABACService struct {
 	pdp            core.PolicyDecisionPointInterface
 	storage        storage.Storage
 	subjectFactory *models.SubjectFactory
+	messages       *localization.Catalog
+	decisions      *sink.Broadcaster
+	bundleSigner   *bundle.Signer              // Signs exported policy bundles; nil disables export
+	bundlePath     string                      // Trusted bundle file rewritten by bundle import; empty keeps it in memory only
+	subjectTypes   *models.SubjectTypeRegistry // Allowed subject types; nil accepts any type
+	audit          storage.AuditWriter         // Audit log writer: storage, or a store-and-forward spool in front of it
+	auditRedactor  *redaction.Redactor         // Masks sensitive attribute values in the reasons of audited decisions
+	bundleFetcher  *bundle.Fetcher             // Polls ABAC_BUNDLE_URL for signed bundles; nil when unset
+	adminAuth      *adminauth.Authenticator    // Admin bearer tokens and roles (ABAC_ADMIN_TOKENS); nil leaves admin routes to ABACMiddleware
+	requestAttrs   *pep.RequestAttributeConfig // Body fields and headers ABACMiddleware exposes as request.body.*/request.header.*; nil disables
+	decisionHdrs   *pep.DecisionHeaderConfig   // Where ABACMiddleware sets X-ABAC-* decision headers; nil disables
+}
+
+// appStorage is the storage used by the HTTP service, including audit retention, attribute encryption, change event and subject type registry support
+type appStorage interface {
+	storage.Storage
+	storage.AuditRetentionStore
+	storage.AttributeEncryptionStore
+	storage.EventStore
+	storage.SubjectTypeStore
+}
+
+// newStorage opens the storage backend selected by DB_DRIVER ("postgres" or "sqlite")
+func newStorage(driver string) (appStorage, error) {
+	switch driver {
+	case "", "postgres":
+		postgresStorage, err := storage.NewPostgreSQLStorage(storage.DefaultDatabaseConfig())
+		if err != nil {
+			return nil, err
+		}
+		return postgresStorage, nil
+	case "sqlite":
+		sqliteStorage, err := storage.NewSQLiteStorage(storage.DefaultSQLiteConfig())
+		if err != nil {
+			return nil, err
+		}
+		return sqliteStorage, nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+}
+
+// newABACService wires the HTTP service with a SubjectFactory backed by storage
+func newABACService(storageInstance storage.Storage, pdp core.PolicyDecisionPointInterface) *ABACService {
+	userLoader := storage.NewStorageUserLoader(storageInstance)
+	serviceLoader := storage.NewStorageServiceLoader(storageInstance)
+
+	return &ABACService{
+		pdp:            pdp,
+		storage:        storageInstance,
+		audit:          storageInstance,
+		auditRedactor:  audit.DefaultAuditConfig().Redactor(),
+		subjectFactory: models.NewSubjectFactory(userLoader, serviceLoader),
+		messages:       localization.DefaultCatalog(),
+	}
 }
 
 // ABACMiddleware - Middleware để check ABAC permissions
 func (service *ABACService) ABACMiddleware(requiredAction string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Create Subject from request using SubjectFactory
-		subject, err := service.subjectFactory.CreateFromRequest(c.Request)
+		// Admin tokens without an ABAC subject are authorized by their roles alone
+		principal := adminPrincipal(c)
+		if principal != nil && principal.SubjectID == "" {
+			c.Next()
+			return
+		}
+
+		// Create Subject from request using SubjectFactory; an admin token's subject replaces the headers
+		subject, err := service.subjectFromRequest(c, principal)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "Authentication required",
@@ -153,6 +478,23 @@ func (service *ABACService) ABACMiddleware(requiredAction string) gin.HandlerFun
 				"user_ip":   c.ClientIP(),
 			},
 		}
+		// Selected body fields and headers for transaction-level policies (request.body.amount, request.header.x-channel)
+		attributes, err := pep.ExtractRequestAttributes(c.Request, service.requestAttrs)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, pep.ErrBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": "Request attributes unavailable", "details": err.Error()})
+			return
+		}
+		for key, value := range attributes {
+			request.Context[key] = value
+		}
+		// Mutual TLS callers are identified by their certificate (environment.client_cert.*)
+		if clientCert := pep.ClientCertFromTLS(c.Request.TLS); clientCert != nil {
+			request.Environment = &models.EnvironmentInfo{ClientCert: clientCert}
+		}
 
 		// Evaluate with PDP
 		decision, err := service.pdp.Evaluate(request)
@@ -162,11 +504,40 @@ func (service *ABACService) ABACMiddleware(requiredAction string) gin.HandlerFun
 			c.Abort()
 			return
 		}
+		service.recordDecision(request, decision)
+
+		// Downstream handlers and gateways see the outcome as X-ABAC-* headers
+		if service.decisionHdrs != nil {
+			headers := pep.DecisionHeaders(request.RequestID, decision)
+			if service.decisionHdrs.Request {
+				pep.ApplyDecisionHeaders(c.Request.Header, headers)
+			}
+			if service.decisionHdrs.Response {
+				pep.ApplyDecisionHeaders(c.Writer.Header(), headers)
+			}
+		}
 
 		service.handleDecision(c, decision, subject.GetID(), c.Request.URL.Path, requiredAction)
 	}
 }
 
+// subjectFromRequest resolves the subject ABACMiddleware authorizes: the subject named by the admin
+// token when there is one, else the request's authentication headers
+func (service *ABACService) subjectFromRequest(c *gin.Context, principal *adminauth.Principal) (models.SubjectInterface, error) {
+	if principal != nil {
+		return service.subjectFactory.CreateFromSubjectID(principal.SubjectID)
+	}
+	return service.subjectFactory.CreateFromRequest(c.Request)
+}
+
+// recordDecision persists a PDP decision to the audit logs through the audit writer, so decisions made
+// during a storage outage are spooled and replayed. A failed write is logged; the decision stands.
+func (service *ABACService) recordDecision(request *models.EvaluationRequest, decision *models.Decision) {
+	if err := service.audit.LogAudit(audit.DecisionAuditLog(request, decision, service.auditRedactor)); err != nil {
+		log.Printf("Failed to audit decision %s: %v", request.RequestID, err)
+	}
+}
+
 // handleDecision processes the PDP decision
 func (service *ABACService) handleDecision(c *gin.Context, decision *models.Decision, subjectID, resource, action string) {
 	// Log decision
@@ -175,18 +546,47 @@ func (service *ABACService) handleDecision(c *gin.Context, decision *models.Deci
 
 	// Check result
 	if decision.Result != "permit" {
+		// Localized end-user message based on Accept-Language
+		lang := service.messages.NegotiateLanguage(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", lang)
+
 		c.JSON(http.StatusForbidden, gin.H{
-			"error":    "Access denied",
-			"reason":   decision.Reason,
-			"subject":  subjectID,
-			"resource": resource,
-			"action":   action,
+			"error":       "Access denied",
+			"reason":      decision.Reason,
+			"reason_code": decision.ReasonCode,
+			"message":     service.messages.Message(lang, decision.ReasonCode, decision.ReasonDetails),
+			"subject":     subjectID,
+			"resource":    resource,
+			"action":      action,
 		})
 		c.Abort()
 		return
 	}
 
-	// Allow request to continue
+	// A read-only permit does not cover mutating methods, whatever action the route evaluates
+	if !pep.PermitsMethod(decision.Constraints, c.Request.Method) {
+		lang := service.messages.NegotiateLanguage(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", lang)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       "Access denied",
+			"reason":      fmt.Sprintf(constants.ReasonReadOnlyPermit, c.Request.Method),
+			"reason_code": constants.ReasonCodeReadOnlyPermit,
+			"message":     service.messages.Message(lang, constants.ReasonCodeReadOnlyPermit, nil),
+			"subject":     subjectID,
+			"resource":    resource,
+			"action":      action,
+		})
+		c.Abort()
+		return
+	}
+
+	// Allow request to continue; handlers read the actor for audit trails (the admin principal when authenticated by token)
+	if c.GetString(actorContextKey) == "" {
+		c.Set(actorContextKey, subjectID)
+	}
+	if decision.Constraints != nil {
+		c.Set(constraintsContextKey, decision.Constraints)
+	}
 	c.Next()
 }
 
@@ -218,7 +618,7 @@ func (service *ABACService) handleFinancialData(c *gin.Context) {
 		"quarter":  "Q1 2024",
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"financial_data": data,
+		"financial_data": pep.ApplyConstraints(data, requestConstraints(c)),
 		"message":        "Financial data retrieved successfully",
 	})
 }
@@ -244,7 +644,9 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Subject-ID")
+		// Authorization carries admin bearer tokens; policy edits read the ETag and send it back as If-Match
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Subject-ID, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusOK)
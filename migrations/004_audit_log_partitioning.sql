@@ -0,0 +1,62 @@
+-- Migration 004: Audit Log Partitioning
+-- Converts audit_logs into a table range-partitioned by month on created_at.
+-- Monthly partitions are named audit_logs_yYYYYmMM; rows outside every monthly
+-- partition land in audit_logs_default. Future partitions and retention are
+-- maintained by storage.AuditRetentionJob (AUDIT_RETENTION_DAYS).
+-- Created: 2025-11-20
+
+BEGIN;
+
+ALTER TABLE audit_logs RENAME TO audit_logs_unpartitioned;
+ALTER INDEX IF EXISTS audit_logs_pkey RENAME TO audit_logs_unpartitioned_pkey;
+
+-- The primary key must include the partition key
+CREATE TABLE audit_logs (
+    id BIGINT NOT NULL DEFAULT nextval('audit_logs_id_seq'),
+    request_id VARCHAR(255) NOT NULL,
+    subject_id VARCHAR(255) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    action_id VARCHAR(255) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    evaluation_ms BIGINT NOT NULL,
+    context JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE audit_logs_id_seq OWNED BY audit_logs.id;
+
+CREATE TABLE audit_logs_default PARTITION OF audit_logs DEFAULT;
+
+-- Monthly partitions from the oldest existing row up to two months ahead
+DO $$
+DECLARE
+    month_start DATE := date_trunc('month', COALESCE((SELECT MIN(created_at) FROM audit_logs_unpartitioned), now()) AT TIME ZONE 'UTC');
+    last_month DATE := date_trunc('month', now() AT TIME ZONE 'UTC') + INTERVAL '2 months';
+BEGIN
+    WHILE month_start <= last_month LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF audit_logs FOR VALUES FROM (%L) TO (%L)',
+            'audit_logs_y' || to_char(month_start, 'YYYY') || 'm' || to_char(month_start, 'MM'),
+            month_start::timestamp AT TIME ZONE 'UTC',
+            (month_start + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC'
+        );
+        month_start := month_start + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO audit_logs (id, request_id, subject_id, resource_id, action_id, decision, evaluation_ms, context, created_at)
+SELECT id, request_id, subject_id, resource_id, action_id, decision, evaluation_ms, context, created_at
+FROM audit_logs_unpartitioned;
+
+DROP TABLE audit_logs_unpartitioned;
+
+-- Same index names as GORM AutoMigrate so it does not recreate them
+CREATE INDEX idx_audit_logs_request_id ON audit_logs(request_id);
+CREATE INDEX idx_audit_logs_subject_id ON audit_logs(subject_id);
+CREATE INDEX idx_audit_logs_resource_id ON audit_logs(resource_id);
+CREATE INDEX idx_audit_logs_action_id ON audit_logs(action_id);
+CREATE INDEX idx_audit_logs_decision ON audit_logs(decision);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+COMMIT;
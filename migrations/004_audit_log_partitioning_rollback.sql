@@ -0,0 +1,38 @@
+-- Rollback Migration 004: Audit Log Partitioning
+-- Converts audit_logs back into a plain table, keeping all rows
+-- Created: 2025-11-20
+
+BEGIN;
+
+ALTER TABLE audit_logs RENAME TO audit_logs_partitioned;
+ALTER INDEX IF EXISTS audit_logs_pkey RENAME TO audit_logs_partitioned_pkey;
+
+CREATE TABLE audit_logs (
+    id BIGINT PRIMARY KEY DEFAULT nextval('audit_logs_id_seq'),
+    request_id VARCHAR(255) NOT NULL,
+    subject_id VARCHAR(255) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    action_id VARCHAR(255) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    evaluation_ms BIGINT NOT NULL,
+    context JSONB,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+ALTER SEQUENCE audit_logs_id_seq OWNED BY audit_logs.id;
+
+INSERT INTO audit_logs (id, request_id, subject_id, resource_id, action_id, decision, evaluation_ms, context, created_at)
+SELECT id, request_id, subject_id, resource_id, action_id, decision, evaluation_ms, context, created_at
+FROM audit_logs_partitioned;
+
+-- Dropping the parent drops every partition
+DROP TABLE audit_logs_partitioned;
+
+CREATE INDEX idx_audit_logs_request_id ON audit_logs(request_id);
+CREATE INDEX idx_audit_logs_subject_id ON audit_logs(subject_id);
+CREATE INDEX idx_audit_logs_resource_id ON audit_logs(resource_id);
+CREATE INDEX idx_audit_logs_action_id ON audit_logs(action_id);
+CREATE INDEX idx_audit_logs_decision ON audit_logs(decision);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- Migration 005: Attribute Search Indexes
+-- GIN indexes backing FindSubjectsByAttribute / FindResourcesByAttribute (jsonb containment @>)
+-- Created: 2025-11-24
+
+CREATE INDEX IF NOT EXISTS idx_subjects_attributes_gin ON subjects USING GIN (attributes jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_resources_attributes_gin ON resources USING GIN (attributes jsonb_path_ops);
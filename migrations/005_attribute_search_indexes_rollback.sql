@@ -0,0 +1,5 @@
+-- Rollback Migration 005: Attribute Search Indexes
+-- Created: 2025-11-24
+
+DROP INDEX IF EXISTS idx_subjects_attributes_gin;
+DROP INDEX IF EXISTS idx_resources_attributes_gin;
@@ -0,0 +1,6 @@
+-- Migration 006: Resource Tags
+-- Tag set on resources for ResourceTag conditions, with a GIN index backing FindResourcesByTag (jsonb containment @>)
+-- Created: 2025-11-26
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS tags JSONB;
+CREATE INDEX IF NOT EXISTS idx_resources_tags_gin ON resources USING GIN (tags jsonb_path_ops);
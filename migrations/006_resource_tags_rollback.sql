@@ -0,0 +1,5 @@
+-- Rollback Migration 006: Resource Tags
+-- Created: 2025-11-26
+
+DROP INDEX IF EXISTS idx_resources_tags_gin;
+ALTER TABLE resources DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,21 @@
+-- Migration 007: Groups
+-- Group entities and subject/group memberships; nested groups are expanded into user.groups during enrichment
+-- Created: 2025-11-27
+
+CREATE TABLE IF NOT EXISTS groups (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255),
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS group_memberships (
+    group_id VARCHAR(255) NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    member_type VARCHAR(20) NOT NULL CHECK (member_type IN ('subject', 'group')),
+    member_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (group_id, member_type, member_id)
+);
+
+-- GetDirectGroups looks memberships up by member
+CREATE INDEX IF NOT EXISTS idx_group_memberships_member ON group_memberships (member_id);
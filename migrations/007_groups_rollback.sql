@@ -0,0 +1,5 @@
+-- Rollback Migration 007: Groups
+-- Created: 2025-11-27
+
+DROP TABLE IF EXISTS group_memberships;
+DROP TABLE IF EXISTS groups;
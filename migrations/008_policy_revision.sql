@@ -0,0 +1,5 @@
+-- Migration 008: Policy Revision
+-- Revision counter for optimistic concurrency on policy updates (If-Match / ETag)
+-- Created: 2025-11-28
+
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Rollback Migration 008: Policy Revision
+-- Created: 2025-11-28
+
+ALTER TABLE policies DROP COLUMN IF EXISTS revision;
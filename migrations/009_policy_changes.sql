@@ -0,0 +1,19 @@
+-- Migration 009: Policy Changes
+-- Audit trail of policy create/update/delete with actor and JSON diff
+-- Created: 2025-11-29
+
+CREATE TABLE IF NOT EXISTS policy_changes (
+    id BIGSERIAL PRIMARY KEY,
+    policy_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    actor VARCHAR(255) NOT NULL,
+    revision BIGINT,
+    diff JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- No foreign key: changes outlive deleted policies
+-- GET /api/v1/policies/:id/changes lists a policy's changes newest first
+CREATE INDEX IF NOT EXISTS idx_policy_changes_policy ON policy_changes (policy_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_policy_changes_actor ON policy_changes (actor);
+CREATE INDEX IF NOT EXISTS idx_policy_changes_created_at ON policy_changes (created_at);
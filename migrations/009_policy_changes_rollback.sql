@@ -0,0 +1,4 @@
+-- Rollback Migration 009: Policy Changes
+-- Created: 2025-11-29
+
+DROP TABLE IF EXISTS policy_changes;
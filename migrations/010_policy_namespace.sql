@@ -0,0 +1,7 @@
+-- Migration 010: Policy Namespace
+-- Scopes policies to a service namespace; empty namespace keeps a policy global
+-- Created: 2025-11-30
+
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS namespace VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_policies_namespace ON policies(namespace);
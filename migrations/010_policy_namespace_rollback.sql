@@ -0,0 +1,5 @@
+-- Rollback Migration 010: Policy Namespace
+-- Created: 2025-11-30
+
+DROP INDEX IF EXISTS idx_policies_namespace;
+ALTER TABLE policies DROP COLUMN IF EXISTS namespace;
@@ -0,0 +1,18 @@
+-- Migration 011: Access Exceptions
+-- Explicit subject/resource/action allows or denies with expiry and justification, applied before policy evaluation
+-- Created: 2025-12-01
+
+CREATE TABLE IF NOT EXISTS access_exceptions (
+    id VARCHAR(255) PRIMARY KEY,
+    subject_id VARCHAR(255) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    effect VARCHAR(10) NOT NULL CHECK (effect IN ('allow', 'deny')),
+    justification TEXT NOT NULL,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- FindActiveExceptions looks exceptions up by exact subject, resource and action on every evaluation
+CREATE INDEX IF NOT EXISTS idx_access_exceptions_lookup ON access_exceptions (subject_id, resource_id, action);
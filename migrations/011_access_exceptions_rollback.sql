@@ -0,0 +1,4 @@
+-- Rollback Migration 011: Access Exceptions
+-- Created: 2025-12-01
+
+DROP TABLE IF EXISTS access_exceptions;
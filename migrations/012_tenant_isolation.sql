@@ -0,0 +1,41 @@
+-- Migration 012: Tenant Isolation
+-- Adds tenant_id to tenant-owned tables and row-level security policies as a second line of defense
+-- behind storage.TenantStore: database roles without BYPASSRLS only see rows of the tenant in the
+-- abac.tenant_id setting, e.g. ALTER ROLE abac_acme SET abac.tenant_id = 'acme'.
+-- Table owners (the service's migration role) bypass RLS, so existing connections keep working.
+-- Created: 2025-12-03
+
+ALTER TABLE subjects ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE attribute_snapshots ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_subjects_tenant_id ON subjects(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_resources_tenant_id ON resources(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_policies_tenant_id ON policies(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_attribute_snapshots_tenant_id ON attribute_snapshots(tenant_id);
+
+-- current_setting(..., true) is NULL when unset, which matches no row
+ALTER TABLE subjects ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON subjects;
+CREATE POLICY tenant_isolation ON subjects
+    USING (tenant_id = current_setting('abac.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('abac.tenant_id', true));
+
+ALTER TABLE resources ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON resources;
+CREATE POLICY tenant_isolation ON resources
+    USING (tenant_id = current_setting('abac.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('abac.tenant_id', true));
+
+ALTER TABLE policies ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON policies;
+CREATE POLICY tenant_isolation ON policies
+    USING (tenant_id = current_setting('abac.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('abac.tenant_id', true));
+
+ALTER TABLE attribute_snapshots ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON attribute_snapshots;
+CREATE POLICY tenant_isolation ON attribute_snapshots
+    USING (tenant_id = current_setting('abac.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('abac.tenant_id', true));
@@ -0,0 +1,22 @@
+-- Rollback Migration 012: Tenant Isolation
+-- Created: 2025-12-03
+
+DROP POLICY IF EXISTS tenant_isolation ON attribute_snapshots;
+DROP POLICY IF EXISTS tenant_isolation ON policies;
+DROP POLICY IF EXISTS tenant_isolation ON resources;
+DROP POLICY IF EXISTS tenant_isolation ON subjects;
+
+ALTER TABLE attribute_snapshots DISABLE ROW LEVEL SECURITY;
+ALTER TABLE policies DISABLE ROW LEVEL SECURITY;
+ALTER TABLE resources DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subjects DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_attribute_snapshots_tenant_id;
+DROP INDEX IF EXISTS idx_policies_tenant_id;
+DROP INDEX IF EXISTS idx_resources_tenant_id;
+DROP INDEX IF EXISTS idx_subjects_tenant_id;
+
+ALTER TABLE attribute_snapshots DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE policies DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE resources DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subjects DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,16 @@
+-- Migration 013: Soft Delete
+-- Deleting a subject, resource, action or policy now only sets deleted_at, so accidental
+-- deletions can be restored. Storage hides soft-deleted rows and the PDP never evaluates
+-- soft-deleted policies. Soft-deleted rows keep their ID and unique names (action_name,
+-- policy_name) until they are restored or purged manually.
+-- Created: 2025-12-10
+
+ALTER TABLE subjects ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE actions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_subjects_deleted_at ON subjects(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_resources_deleted_at ON resources(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_actions_deleted_at ON actions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_policies_deleted_at ON policies(deleted_at);
@@ -0,0 +1,18 @@
+-- Rollback Migration 013: Soft Delete
+-- Soft-deleted rows would become live again once the column is gone, so they are removed first.
+-- Created: 2025-12-10
+
+DELETE FROM policies WHERE deleted_at IS NOT NULL;
+DELETE FROM actions WHERE deleted_at IS NOT NULL;
+DELETE FROM resources WHERE deleted_at IS NOT NULL;
+DELETE FROM subjects WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_policies_deleted_at;
+DROP INDEX IF EXISTS idx_actions_deleted_at;
+DROP INDEX IF EXISTS idx_resources_deleted_at;
+DROP INDEX IF EXISTS idx_subjects_deleted_at;
+
+ALTER TABLE policies DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE actions DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE resources DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE subjects DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+-- Migration 014: Policy Rollout
+-- Canary rollouts: a policy with rollout_percent is enforced for that share of subjects
+-- (bucketed by subject ID) and evaluated in shadow for the others. NULL enforces it for everyone.
+-- Created: 2026-10-17
+
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS rollout_percent INTEGER
+    CHECK (rollout_percent IS NULL OR rollout_percent BETWEEN 0 AND 100);
@@ -0,0 +1,4 @@
+-- Rollback Migration 014: Policy Rollout
+-- Created: 2026-10-17
+
+ALTER TABLE policies DROP COLUMN IF EXISTS rollout_percent;
@@ -0,0 +1,19 @@
+-- Migration 015: Revocation List
+-- Subjects and sessions whose access is suspended (compromised accounts, stolen tokens),
+-- checked by the PDP before lockdown, exceptions and policies
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS revocations (
+    id VARCHAR(255) PRIMARY KEY,
+    subject_id VARCHAR(255),
+    session_id VARCHAR(255),
+    reason TEXT NOT NULL,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((subject_id IS NULL OR subject_id = '') <> (session_id IS NULL OR session_id = ''))
+);
+
+CREATE INDEX IF NOT EXISTS idx_revocations_subject_id ON revocations (subject_id);
+CREATE INDEX IF NOT EXISTS idx_revocations_session_id ON revocations (session_id);
+CREATE INDEX IF NOT EXISTS idx_revocations_expires_at ON revocations (expires_at);
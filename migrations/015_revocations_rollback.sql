@@ -0,0 +1,4 @@
+-- Rollback Migration 015: Revocation List
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS revocations;
@@ -0,0 +1,19 @@
+-- Migration 016: Time-Limited Elevations
+-- Roles and attributes granted to a subject until expires_at ("sudo mode"), merged into
+-- user.roles and the subject attributes by the attribute resolver only while active
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS elevations (
+    id VARCHAR(255) PRIMARY KEY,
+    subject_id VARCHAR(255) NOT NULL,
+    roles JSONB,
+    attributes JSONB,
+    justification TEXT NOT NULL,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (expires_at > created_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_elevations_subject_id ON elevations (subject_id);
+CREATE INDEX IF NOT EXISTS idx_elevations_expires_at ON elevations (expires_at);
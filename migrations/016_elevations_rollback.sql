@@ -0,0 +1,4 @@
+-- Rollback Migration 016: Time-Limited Elevations
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS elevations;
@@ -0,0 +1,25 @@
+-- Migration 017: Policy Tags and Schedules
+-- Free-form policy tags used to select policies in bulk, and schedules that enable or disable
+-- the selected policies once (run_at) or whenever a cron expression fires (e.g. "0 18 * * FRI")
+-- Created: 2026-10-17
+
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS tags JSONB;
+CREATE INDEX IF NOT EXISTS idx_policies_tags_gin ON policies USING GIN (tags jsonb_path_ops);
+
+CREATE TABLE IF NOT EXISTS policy_schedules (
+    id VARCHAR(255) PRIMARY KEY,
+    description TEXT,
+    policy_ids JSONB,
+    tags JSONB,
+    enabled BOOLEAN NOT NULL,
+    cron VARCHAR(100),
+    run_at TIMESTAMP WITH TIME ZONE,
+    timezone VARCHAR(64),
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((cron IS NULL OR cron = '') <> (run_at IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_schedules_next_run_at ON policy_schedules (next_run_at);
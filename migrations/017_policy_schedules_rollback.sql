@@ -0,0 +1,6 @@
+-- Rollback Migration 017: Policy Tags and Schedules
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS policy_schedules;
+DROP INDEX IF EXISTS idx_policies_tags_gin;
+ALTER TABLE policies DROP COLUMN IF EXISTS tags;
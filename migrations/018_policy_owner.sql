@@ -0,0 +1,7 @@
+-- Migration 018: Policy Owner
+-- Team accountable for a policy; with policies.tags (017) lets the PAP list policies per owner
+-- or compliance domain (GET /api/v1/policies?owner=team-payments&tag=domain=pci)
+-- Created: 2026-10-17
+
+ALTER TABLE policies ADD COLUMN IF NOT EXISTS owner VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_policies_owner ON policies (owner);
@@ -0,0 +1,5 @@
+-- Rollback Migration 018: Policy Owner
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_policies_owner;
+ALTER TABLE policies DROP COLUMN IF EXISTS owner;
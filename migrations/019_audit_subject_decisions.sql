@@ -0,0 +1,9 @@
+-- Migration 019: Subject Decision History
+-- Marks audit entries that record a PDP decision (source = 'evaluation') and indexes them for
+-- GET /api/v1/subjects/{id}/decisions: a subject's most recent decisions without scanning every
+-- audit log of the subject or counting administrative entries
+-- Created: 2026-10-17
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_subject_source_created ON audit_logs (subject_id, source, created_at DESC);
@@ -0,0 +1,6 @@
+-- Rollback Migration 019: Subject Decision History
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_audit_logs_subject_source_created;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS source;
@@ -0,0 +1,304 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// defaultSubjectClaim is the standard JWT subject claim
+const defaultSubjectClaim = "sub"
+
+// ClaimsMapping maps token claims to subject attributes, so tokens of different identity providers
+// (Keycloak realm_access.roles, Azure AD groups, custom namespaced claims) feed the same user.* attributes:
+//
+//	{
+//	  "subject_claim": "preferred_username",
+//	  "claims": [
+//	    {"claim": "realm_access.roles", "attribute": "roles", "transforms": ["lowercase"]},
+//	    {"claim": "https://acme.com/department", "attribute": "department", "default": "unknown"},
+//	    {"claim": "scope", "attribute": "scopes", "transforms": ["split"]}
+//	  ]
+//	}
+type ClaimsMapping struct {
+	SubjectClaim string         `json:"subject_claim,omitempty"` // Claim holding the subject ID; default "sub"
+	Claims       []ClaimMapping `json:"claims"`
+}
+
+// ClaimMapping maps one claim to one subject attribute
+type ClaimMapping struct {
+	Claim      string      `json:"claim"`                // Claim name, or a dotted path into nested claims ("realm_access.roles")
+	Attribute  string      `json:"attribute"`            // Subject attribute path, e.g. "department" or "user.org.unit"
+	Transforms []string    `json:"transforms,omitempty"` // Applied in order, see claimTransforms
+	Default    interface{} `json:"default,omitempty"`    // Value used when the claim is absent
+	Required   bool        `json:"required,omitempty"`   // Reject tokens without the claim
+}
+
+// claimTransforms are the supported transforms. String transforms apply to each element of an array claim.
+//
+//	lowercase, uppercase, trim  - change a string
+//	split, split:<sep>          - split a string on whitespace (e.g. the OAuth "scope" claim) or on sep into an array
+//	strip_prefix:<prefix>       - remove a prefix, e.g. "strip_prefix:/" for Keycloak group paths
+//	first                       - take the first element of an array
+var claimTransforms = map[string]bool{
+	"lowercase": true, "uppercase": true, "trim": true, "split": true, "strip_prefix": true, "first": true,
+}
+
+// ParseClaimsMapping parses and validates a JSON claims mapping
+func ParseClaimsMapping(data []byte) (*ClaimsMapping, error) {
+	var mapping ClaimsMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid claims mapping: %w", err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// LoadClaimsMapping reads a JSON claims mapping from path
+func LoadClaimsMapping(path string) (*ClaimsMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claims mapping: %w", err)
+	}
+	return ParseClaimsMapping(data)
+}
+
+// ClaimsMappingFromEnv loads the mapping configured by ABAC_CLAIMS_MAPPING.
+// It returns nil (claims are used as-is) when the variable is unset.
+func ClaimsMappingFromEnv() (*ClaimsMapping, error) {
+	path := os.Getenv(constants.EnvClaimsMapping)
+	if path == "" {
+		return nil, nil
+	}
+	return LoadClaimsMapping(path)
+}
+
+// Validate checks that every mapping names a claim, an attribute and known transforms
+func (m *ClaimsMapping) Validate() error {
+	for i, claim := range m.Claims {
+		if claim.Claim == "" {
+			return fmt.Errorf("claims[%d]: claim is required", i)
+		}
+		if claimAttributePath(claim.Attribute) == nil {
+			return fmt.Errorf("claims[%d]: attribute is required", i)
+		}
+		for _, transform := range claim.Transforms {
+			name, _, _ := strings.Cut(transform, ":")
+			if !claimTransforms[name] {
+				return fmt.Errorf("claims[%d]: unknown transform %q", i, transform)
+			}
+			if name == "strip_prefix" && !strings.Contains(transform, ":") {
+				return fmt.Errorf("claims[%d]: strip_prefix requires a prefix (strip_prefix:<prefix>)", i)
+			}
+		}
+	}
+	return nil
+}
+
+// SubjectID returns the subject ID claim, or "" when it is missing or not a string
+func (m *ClaimsMapping) SubjectID(claims map[string]interface{}) string {
+	claim := m.SubjectClaim
+	if claim == "" {
+		claim = defaultSubjectClaim
+	}
+	value, _ := lookupClaim(claims, claim)
+	id, _ := value.(string)
+	return id
+}
+
+// Apply maps claims to subject attributes. Absent optional claims without a default are skipped.
+func (m *ClaimsMapping) Apply(claims map[string]interface{}) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{}, len(m.Claims))
+	for _, mapping := range m.Claims {
+		value, found := lookupClaim(claims, mapping.Claim)
+		if !found {
+			if mapping.Required {
+				return nil, fmt.Errorf("required claim %q is missing", mapping.Claim)
+			}
+			if mapping.Default == nil {
+				continue
+			}
+			value = mapping.Default
+		}
+
+		for _, transform := range mapping.Transforms {
+			var err error
+			if value, err = applyClaimTransform(transform, value); err != nil {
+				return nil, fmt.Errorf("claim %q: %w", mapping.Claim, err)
+			}
+		}
+		setAttributePath(attributes, claimAttributePath(mapping.Attribute), value)
+	}
+	return attributes, nil
+}
+
+// lookupClaim finds a claim by its exact name first, so namespaced claims such as
+// "https://acme.com/roles" work, then as a dotted path into nested claim objects
+func lookupClaim(claims map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := claims[name]; ok {
+		return value, true
+	}
+
+	var current interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// applyClaimTransform applies one transform to a claim value
+func applyClaimTransform(transform string, value interface{}) (interface{}, error) {
+	name, arg, _ := strings.Cut(transform, ":")
+	switch name {
+	case "first":
+		values, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("first: empty array")
+		}
+		return values[0], nil
+	case "split":
+		parts := []interface{}{}
+		err := eachClaimString(value, func(s string) {
+			var fields []string
+			if arg == "" {
+				fields = strings.Fields(s)
+			} else {
+				fields = strings.Split(s, arg)
+			}
+			for _, field := range fields {
+				if field = strings.TrimSpace(field); field != "" {
+					parts = append(parts, field)
+				}
+			}
+		})
+		return parts, err
+	}
+
+	var convert func(string) string
+	switch name {
+	case "lowercase":
+		convert = strings.ToLower
+	case "uppercase":
+		convert = strings.ToUpper
+	case "trim":
+		convert = strings.TrimSpace
+	case "strip_prefix":
+		convert = func(s string) string { return strings.TrimPrefix(s, arg) }
+	default:
+		return nil, fmt.Errorf("unknown transform %q", transform)
+	}
+
+	if values, ok := value.([]interface{}); ok {
+		converted := make([]interface{}, len(values))
+		for i, element := range values {
+			s, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: array element %v is not a string", name, element)
+			}
+			converted[i] = convert(s)
+		}
+		return converted, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s: value %v is not a string", name, value)
+	}
+	return convert(s), nil
+}
+
+// eachClaimString calls fn for a string value or each string of an array value
+func eachClaimString(value interface{}, fn func(string)) error {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case []interface{}:
+		for _, element := range v {
+			s, ok := element.(string)
+			if !ok {
+				return fmt.Errorf("array element %v is not a string", element)
+			}
+			fn(s)
+		}
+	default:
+		return fmt.Errorf("value %v is not a string", value)
+	}
+	return nil
+}
+
+// claimAttributePath splits a target attribute path, accepting an optional "user." prefix
+func claimAttributePath(attribute string) []string {
+	attribute = strings.TrimPrefix(strings.TrimSpace(attribute), "user.")
+	if attribute == "" {
+		return nil
+	}
+	return strings.Split(attribute, ".")
+}
+
+// setAttributePath sets a nested attribute, creating intermediate objects
+func setAttributePath(attributes map[string]interface{}, path []string, value interface{}) {
+	current := attributes
+	for _, part := range path[:len(path)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[path[len(path)-1]] = value
+}
+
+// ClaimsSubject is a user described by mapped token claims, merged over the stored user when one exists.
+// Federated users without a local record are authorized from their claims alone.
+type ClaimsSubject struct {
+	id         string
+	attributes map[string]interface{}
+}
+
+// NewClaimsSubject creates a subject with the given attributes
+func NewClaimsSubject(id string, attributes map[string]interface{}) *ClaimsSubject {
+	return &ClaimsSubject{id: id, attributes: attributes}
+}
+
+// GetID returns the subject ID claim
+func (cs *ClaimsSubject) GetID() string {
+	return cs.id
+}
+
+// GetType returns SubjectTypeUser
+func (cs *ClaimsSubject) GetType() SubjectType {
+	return SubjectTypeUser
+}
+
+// GetAttributes returns the merged attributes
+func (cs *ClaimsSubject) GetAttributes() map[string]interface{} {
+	return cs.attributes
+}
+
+// GetDisplayName returns the full_name attribute, falling back to the ID
+func (cs *ClaimsSubject) GetDisplayName() string {
+	if name, ok := cs.attributes["full_name"].(string); ok && name != "" {
+		return name
+	}
+	return cs.id
+}
+
+// IsActive reports false only for subjects whose status attribute is set and not active
+func (cs *ClaimsSubject) IsActive() bool {
+	status, ok := cs.attributes["status"].(string)
+	return !ok || strings.EqualFold(status, "active")
+}
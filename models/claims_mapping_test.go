@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// keycloakClaims is a Keycloak-shaped access token
+var keycloakClaims = map[string]interface{}{
+	"sub":                         "f3a1-42",
+	"preferred_username":          "alice",
+	"scope":                       "openid profile  documents:read",
+	"realm_access":                map[string]interface{}{"roles": []interface{}{"Finance-Analyst", "Reader"}},
+	"groups":                      []interface{}{"/finance", "/finance/reporting"},
+	"https://acme.com/department": " FIN ",
+}
+
+func TestClaimsMapping_Apply(t *testing.T) {
+	mapping, err := ParseClaimsMapping([]byte(`{
+		"subject_claim": "preferred_username",
+		"claims": [
+			{"claim": "realm_access.roles", "attribute": "roles", "transforms": ["lowercase"]},
+			{"claim": "https://acme.com/department", "attribute": "user.department", "transforms": ["trim", "lowercase"]},
+			{"claim": "scope", "attribute": "scopes", "transforms": ["split"]},
+			{"claim": "groups", "attribute": "org.unit", "transforms": ["strip_prefix:/", "first"]},
+			{"claim": "clearance", "attribute": "clearance_level", "default": "public"},
+			{"claim": "email", "attribute": "email"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if id := mapping.SubjectID(keycloakClaims); id != "alice" {
+		t.Errorf("Expected subject alice, got %q", id)
+	}
+
+	attributes, err := mapping.Apply(keycloakClaims)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"roles":           []interface{}{"finance-analyst", "reader"},
+		"department":      "fin",
+		"scopes":          []interface{}{"openid", "profile", "documents:read"},
+		"org":             map[string]interface{}{"unit": "finance"},
+		"clearance_level": "public",
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("Expected %v, got %v", expected, attributes)
+	}
+}
+
+func TestClaimsMapping_Errors(t *testing.T) {
+	invalid := []string{
+		`{"claims": [{"claim": "", "attribute": "roles"}]}`,
+		`{"claims": [{"claim": "roles", "attribute": "user."}]}`,
+		`{"claims": [{"claim": "roles", "attribute": "roles", "transforms": ["reverse"]}]}`,
+		`{"claims": [{"claim": "roles", "attribute": "roles", "transforms": ["strip_prefix"]}]}`,
+		`{"claims": "roles"}`,
+	}
+	for _, document := range invalid {
+		if _, err := ParseClaimsMapping([]byte(document)); err == nil {
+			t.Errorf("Expected error for %s", document)
+		}
+	}
+
+	required := &ClaimsMapping{Claims: []ClaimMapping{{Claim: "tenant", Attribute: "tenant", Required: true}}}
+	if _, err := required.Apply(keycloakClaims); err == nil {
+		t.Error("Expected error for missing required claim")
+	}
+
+	wrongType := &ClaimsMapping{Claims: []ClaimMapping{{Claim: "realm_access", Attribute: "roles", Transforms: []string{"lowercase"}}}}
+	if _, err := wrongType.Apply(keycloakClaims); err == nil {
+		t.Error("Expected error for a string transform on an object claim")
+	}
+}
+
+// stubUserLoader returns a fixed user, or an error for other IDs
+type stubUserLoader struct {
+	user *User
+}
+
+func (l *stubUserLoader) LoadUser(userID string) (*User, *UserProfile, []Role, error) {
+	if l.user == nil || l.user.ID != userID {
+		return nil, nil, nil, errors.New("user not found")
+	}
+	return l.user, nil, nil, nil
+}
+
+func TestSubjectFactory_CreateFromMappedClaims(t *testing.T) {
+	loader := &stubUserLoader{user: &User{ID: "alice", Username: "alice", Status: "active", EmployeeID: "E-1"}}
+	factory := NewSubjectFactory(loader, nil)
+	factory.SetClaimsMapping(&ClaimsMapping{
+		SubjectClaim: "preferred_username",
+		Claims: []ClaimMapping{
+			{Claim: "realm_access.roles", Attribute: "roles", Transforms: []string{"lowercase"}},
+			{Claim: "https://acme.com/department", Attribute: "department", Transforms: []string{"trim"}},
+		},
+	})
+
+	subject, err := factory.CreateFromClaims(keycloakClaims)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	attributes := subject.GetAttributes()
+	if subject.GetID() != "alice" || subject.GetType() != SubjectTypeUser {
+		t.Errorf("Unexpected subject %s (%s)", subject.GetID(), subject.GetType())
+	}
+	if attributes["employee_id"] != "E-1" {
+		t.Errorf("Expected stored attributes to be kept, got %v", attributes)
+	}
+	if attributes["department"] != "FIN" || !reflect.DeepEqual(attributes["roles"], []interface{}{"finance-analyst", "reader"}) {
+		t.Errorf("Expected mapped attributes, got %v", attributes)
+	}
+
+	// Federated user without a local record
+	federated := map[string]interface{}{"preferred_username": "bob", "realm_access": map[string]interface{}{"roles": []interface{}{"Reader"}}}
+	subject, err = factory.CreateFromClaims(federated)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if subject.GetID() != "bob" || !subject.IsActive() || subject.GetAttributes()["user_id"] != "bob" {
+		t.Errorf("Unexpected federated subject %s: %v", subject.GetID(), subject.GetAttributes())
+	}
+
+	if _, err := factory.CreateFromClaims(map[string]interface{}{"sub": "no-username"}); err == nil {
+		t.Error("Expected error when the subject claim is missing")
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Explain tree node kinds
+const (
+	ExplainNodeDecision  = "decision"
+	ExplainNodePolicy    = "policy"
+	ExplainNodeStatement = "statement"
+	ExplainNodeTarget    = "target"    // Action or resource matching of a statement
+	ExplainNodeCondition = "condition" // Operator/key condition or logical operator
+)
+
+// ExplainNode is a node of the decision tree built by Explain: decision → policy → statement →
+// target/condition, each with its pass/fail outcome. Leaf conditions carry the actual context value
+// (masked when sensitive) and the expected policy value.
+type ExplainNode struct {
+	Kind     string         `json:"kind"`
+	Label    string         `json:"label"`
+	Passed   bool           `json:"passed"`
+	Actual   interface{}    `json:"actual,omitempty"`
+	Expected interface{}    `json:"expected,omitempty"`
+	Children []*ExplainNode `json:"children,omitempty"`
+}
+
+// ToDOT renders the tree in Graphviz DOT format (e.g. `dot -Tsvg`); passed nodes are green, failed ones red
+func (n *ExplainNode) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph explain {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	next := 0
+	n.writeDOT(&b, &next)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeDOT writes the node and its subtree, returning the DOT identifier of the node
+func (n *ExplainNode) writeDOT(b *strings.Builder, next *int) string {
+	id := fmt.Sprintf("n%d", *next)
+	*next++
+
+	color := "#f8d7da"
+	if n.Passed {
+		color = "#d4edda"
+	}
+	label := n.Label
+	if n.Actual != nil || n.Expected != nil {
+		label += fmt.Sprintf("\nactual: %s\nexpected: %s", dotValue(n.Actual), dotValue(n.Expected))
+	}
+	fmt.Fprintf(b, "  %s [label=%q, fillcolor=%q];\n", id, label, color)
+
+	for _, child := range n.Children {
+		childID := child.writeDOT(b, next)
+		fmt.Fprintf(b, "  %s -> %s;\n", id, childID)
+	}
+	return id
+}
+
+// dotValue formats a node value compactly for a DOT label
+func dotValue(value interface{}) string {
+	if value == nil {
+		return "<missing>"
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
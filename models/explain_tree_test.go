@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainNodeToDOT(t *testing.T) {
+	tree := &ExplainNode{
+		Kind:  ExplainNodeDecision,
+		Label: "deny: no match",
+		Children: []*ExplainNode{
+			{
+				Kind:   ExplainNodePolicy,
+				Label:  "Policy pol-001",
+				Passed: false,
+				Children: []*ExplainNode{
+					{Kind: ExplainNodeCondition, Label: "StringEquals user.department", Expected: "Engineering"},
+					{Kind: ExplainNodeCondition, Label: "Bool user.mfa", Passed: true, Actual: true, Expected: true},
+				},
+			},
+		},
+	}
+
+	dot := tree.ToDOT()
+	for _, want := range []string{
+		"digraph explain {",
+		`n0 [label="deny: no match", fillcolor="#f8d7da"];`,
+		`n2 [label="StringEquals user.department\nactual: <missing>\nexpected: \"Engineering\"", fillcolor="#f8d7da"];`,
+		`n3 [label="Bool user.mfa\nactual: true\nexpected: true", fillcolor="#d4edda"];`,
+		"n0 -> n1;",
+		"n1 -> n2;",
+		"n1 -> n3;",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected DOT to contain %q:\n%s", want, dot)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("Expected DOT to be closed:\n%s", dot)
+	}
+}
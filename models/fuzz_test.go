@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// FuzzJSONActionResourceUnmarshal ensures arbitrary JSON never panics and accepted values round-trip
+func FuzzJSONActionResourceUnmarshal(f *testing.F) {
+	f.Add([]byte(`"document:read"`))
+	f.Add([]byte(`["document:read","document:write"]`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`[1,2]`))
+	f.Add([]byte(`{"a":"b"}`))
+	f.Add([]byte(`"\u0000"`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var first JSONActionResource
+		if err := json.Unmarshal(data, &first); err != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(first)
+		if err != nil {
+			t.Fatalf("Marshal after successful Unmarshal failed: %v", err)
+		}
+
+		var second JSONActionResource
+		if err := json.Unmarshal(encoded, &second); err != nil {
+			t.Fatalf("Unmarshal of re-encoded value %s failed: %v", encoded, err)
+		}
+
+		if len(first.GetValues()) == 0 && len(second.GetValues()) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(first.GetValues(), second.GetValues()) {
+			t.Errorf("Round trip mismatch: %q -> %s -> %q", first.GetValues(), encoded, second.GetValues())
+		}
+	})
+}
@@ -29,6 +29,7 @@ const (
 type SubjectFactory struct {
 	userLoader    UserLoader
 	serviceLoader ServiceLoader
+	claimsMapping *ClaimsMapping // Maps token claims to subject attributes; nil uses the user_id/sub claims only
 }
 
 // UserLoader defines the interface for loading user data
@@ -49,6 +50,11 @@ func NewSubjectFactory(userLoader UserLoader, serviceLoader ServiceLoader) *Subj
 	}
 }
 
+// SetClaimsMapping configures how CreateFromClaims maps token claims to subject attributes (nil disables mapping)
+func (sf *SubjectFactory) SetClaimsMapping(mapping *ClaimsMapping) {
+	sf.claimsMapping = mapping
+}
+
 // CreateFromRequest creates a Subject from an HTTP request
 // It detects the authentication type and delegates to appropriate creation method
 func (sf *SubjectFactory) CreateFromRequest(r *http.Request) (SubjectInterface, error) {
@@ -155,6 +161,10 @@ func (sf *SubjectFactory) CreateFromAPIKey(apiKey string) (SubjectInterface, err
 // CreateFromClaims creates a Subject from JWT claims
 // This helper method processes already-validated JWT claims
 func (sf *SubjectFactory) CreateFromClaims(claims map[string]interface{}) (SubjectInterface, error) {
+	if sf.claimsMapping != nil {
+		return sf.createFromMappedClaims(claims)
+	}
+
 	// Check for user_id in claims
 	if userID, ok := claims["user_id"].(string); ok && userID != "" {
 		return sf.CreateFromUserID(userID)
@@ -175,6 +185,34 @@ func (sf *SubjectFactory) CreateFromClaims(claims map[string]interface{}) (Subje
 	return nil, fmt.Errorf("cannot determine subject from claims")
 }
 
+// createFromMappedClaims builds a ClaimsSubject from mapped claims. Mapped attributes override
+// those of the stored user with the same ID; users without a local record get the mapped attributes only.
+func (sf *SubjectFactory) createFromMappedClaims(claims map[string]interface{}) (SubjectInterface, error) {
+	subjectID := sf.claimsMapping.SubjectID(claims)
+	if subjectID == "" {
+		return nil, fmt.Errorf("cannot determine subject from claims")
+	}
+
+	mapped, err := sf.claimsMapping.Apply(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map claims: %w", err)
+	}
+
+	attributes := map[string]interface{}{
+		"user_id":      subjectID,
+		"subject_type": string(SubjectTypeUser),
+	}
+	if sf.userLoader != nil {
+		if user, profile, roles, err := sf.userLoader.LoadUser(subjectID); err == nil && user != nil {
+			attributes = NewUserSubject(user, profile, roles).GetAttributes()
+		}
+	}
+	for key, value := range mapped {
+		attributes[key] = value
+	}
+	return NewClaimsSubject(subjectID, attributes), nil
+}
+
 // DetectAuthenticationType detects the type of authentication from an HTTP request
 func DetectAuthenticationType(r *http.Request) string {
 	if r.Header.Get(headerUserID) != "" {
@@ -10,6 +10,10 @@ const (
 	SubjectTypeService SubjectType = "service"
 	// SubjectTypeAPIKey represents an API key authentication
 	SubjectTypeAPIKey SubjectType = "api_key"
+	// SubjectTypeDevice represents a device or workload identity
+	SubjectTypeDevice SubjectType = "device"
+	// SubjectTypeAnonymous represents an unauthenticated caller
+	SubjectTypeAnonymous SubjectType = "anonymous"
 	// SubjectTypeLegacy represents legacy subject from subjects table (for backward compatibility)
 	SubjectTypeLegacy SubjectType = "legacy"
 )
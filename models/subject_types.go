@@ -0,0 +1,211 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// DefaultSubjectTypes selects the built-in registry in ABAC_SUBJECT_TYPES instead of a file
+const DefaultSubjectTypes = "default"
+
+// SubjectTypeAttribute is the attribute path policies use to match the subject type
+const SubjectTypeAttribute = "user.subject_type"
+
+// Subject type validation errors
+var (
+	ErrUnknownSubjectType      = errors.New("unknown subject type")
+	ErrMissingSubjectAttribute = errors.New("missing required subject attribute")
+)
+
+// SubjectTypeDefinition is an allowed subject type and the attributes every subject of it must have
+type SubjectTypeDefinition struct {
+	Type               SubjectType `json:"type"`
+	Description        string      `json:"description,omitempty"`
+	RequiredAttributes []string    `json:"required_attributes,omitempty"`
+}
+
+// SubjectTypeRegistry is the controlled set of subject types. Storages enforce it on subject writes
+// and the policy API rejects conditions on user.subject_type naming other types.
+//
+// JSON form: {"device": {"description": "Managed endpoint", "required_attributes": ["device_id"]}}
+type SubjectTypeRegistry struct {
+	types map[SubjectType]SubjectTypeDefinition
+}
+
+// NewSubjectTypeRegistry creates a registry of the given types
+func NewSubjectTypeRegistry(definitions ...SubjectTypeDefinition) *SubjectTypeRegistry {
+	registry := &SubjectTypeRegistry{types: make(map[SubjectType]SubjectTypeDefinition, len(definitions))}
+	for _, definition := range definitions {
+		registry.types[definition.Type] = definition
+	}
+	return registry
+}
+
+// DefaultSubjectTypeRegistry allows users, services, devices and anonymous callers
+func DefaultSubjectTypeRegistry() *SubjectTypeRegistry {
+	return NewSubjectTypeRegistry(
+		SubjectTypeDefinition{Type: SubjectTypeUser, Description: "Human user"},
+		SubjectTypeDefinition{Type: SubjectTypeService, Description: "Service account or application", RequiredAttributes: []string{"service_name"}},
+		SubjectTypeDefinition{Type: SubjectTypeDevice, Description: "Device or workload identity", RequiredAttributes: []string{"device_id"}},
+		SubjectTypeDefinition{Type: SubjectTypeAnonymous, Description: "Unauthenticated caller"},
+	)
+}
+
+// ParseSubjectTypeRegistry parses a registry from its JSON form
+func ParseSubjectTypeRegistry(data []byte) (*SubjectTypeRegistry, error) {
+	var types map[string]SubjectTypeDefinition
+	if err := json.Unmarshal(data, &types); err != nil {
+		return nil, fmt.Errorf("invalid subject type registry: %w", err)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("invalid subject type registry: no subject types")
+	}
+
+	registry := NewSubjectTypeRegistry()
+	for name, definition := range types {
+		if name == "" || strings.TrimSpace(name) != name {
+			return nil, fmt.Errorf("invalid subject type %q", name)
+		}
+		for _, attribute := range definition.RequiredAttributes {
+			if attribute == "" {
+				return nil, fmt.Errorf("subject type %q has an empty required attribute", name)
+			}
+		}
+		definition.Type = SubjectType(name)
+		registry.types[definition.Type] = definition
+	}
+	return registry, nil
+}
+
+// LoadSubjectTypeRegistry reads a JSON registry from path
+func LoadSubjectTypeRegistry(path string) (*SubjectTypeRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subject type registry: %w", err)
+	}
+	return ParseSubjectTypeRegistry(data)
+}
+
+// SubjectTypeRegistryFromEnv loads the registry configured by ABAC_SUBJECT_TYPES ("default" or a path).
+// It returns nil (any subject type is accepted) when the variable is unset.
+func SubjectTypeRegistryFromEnv() (*SubjectTypeRegistry, error) {
+	switch value := os.Getenv(constants.EnvSubjectTypes); value {
+	case "":
+		return nil, nil
+	case DefaultSubjectTypes:
+		return DefaultSubjectTypeRegistry(), nil
+	default:
+		return LoadSubjectTypeRegistry(value)
+	}
+}
+
+// Definitions returns the allowed types sorted by name
+func (r *SubjectTypeRegistry) Definitions() []SubjectTypeDefinition {
+	definitions := make([]SubjectTypeDefinition, 0, len(r.types))
+	for _, definition := range r.types {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Type < definitions[j].Type })
+	return definitions
+}
+
+// Enum returns the allowed values of user.subject_type, sorted
+func (r *SubjectTypeRegistry) Enum() []string {
+	values := make([]string, 0, len(r.types))
+	for subjectType := range r.types {
+		values = append(values, string(subjectType))
+	}
+	sort.Strings(values)
+	return values
+}
+
+// Allows reports whether subjectType is registered
+func (r *SubjectTypeRegistry) Allows(subjectType string) bool {
+	_, ok := r.types[SubjectType(subjectType)]
+	return ok
+}
+
+// ValidateSubject checks that the subject has a registered type and all attributes required by it
+func (r *SubjectTypeRegistry) ValidateSubject(subject *Subject) error {
+	definition, ok := r.types[SubjectType(subject.SubjectType)]
+	if !ok {
+		return fmt.Errorf("%w %q for subject %s (allowed: %s)", ErrUnknownSubjectType, subject.SubjectType, subject.ID, strings.Join(r.Enum(), ", "))
+	}
+	for _, attribute := range definition.RequiredAttributes {
+		if value, exists := subject.Attributes[attribute]; !exists || value == nil {
+			return fmt.Errorf("%w %q for %s subject %s", ErrMissingSubjectAttribute, attribute, subject.SubjectType, subject.ID)
+		}
+	}
+	return nil
+}
+
+// subjectTypeOperators are the condition operators whose values must be registered subject types
+var subjectTypeOperators = map[string]bool{
+	constants.OpStringEquals:    true,
+	constants.OpStringNotEquals: true,
+}
+
+// ValidatePolicy returns an error for every StringEquals/StringNotEquals condition on
+// user.subject_type naming an unregistered type, so such conditions cannot silently never match
+func (r *SubjectTypeRegistry) ValidatePolicy(policy *Policy) []error {
+	var errs []error
+	for i, statement := range policy.Statement {
+		r.checkConditions(statement.Condition, fmt.Sprintf("statement %d", i), &errs)
+	}
+	return errs
+}
+
+// checkConditions walks a condition block, including nested And/Or/Not blocks
+func (r *SubjectTypeRegistry) checkConditions(conditions map[string]interface{}, location string, errs *[]error) {
+	for operator, operands := range conditions {
+		switch value := operands.(type) {
+		case map[string]interface{}:
+			if !subjectTypeOperators[constants.OperatorKey(operator)] {
+				r.checkConditions(value, location, errs)
+				continue
+			}
+			for attribute, expected := range value {
+				if attribute != SubjectTypeAttribute {
+					continue
+				}
+				for _, subjectType := range subjectTypeValues(expected) {
+					if !r.Allows(subjectType) {
+						*errs = append(*errs, fmt.Errorf("%s: %s %s compares with %w %q (allowed: %s)",
+							location, operator, SubjectTypeAttribute, ErrUnknownSubjectType, subjectType, strings.Join(r.Enum(), ", ")))
+					}
+				}
+			}
+		case []interface{}:
+			for _, item := range value {
+				if nested, ok := item.(map[string]interface{}); ok {
+					r.checkConditions(nested, location, errs)
+				}
+			}
+		}
+	}
+}
+
+// subjectTypeValues returns the string values of a condition operand (a string or a list of strings)
+func subjectTypeValues(expected interface{}) []string {
+	switch value := expected.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubjectTypeRegistry_ValidateSubject(t *testing.T) {
+	registry := DefaultSubjectTypeRegistry()
+
+	tests := []struct {
+		name     string
+		subject  *Subject
+		expected error
+	}{
+		{"user", &Subject{ID: "sub-1", SubjectType: "user"}, nil},
+		{"service with name", &Subject{ID: "svc-1", SubjectType: "service", Attributes: JSONMap{"service_name": "billing"}}, nil},
+		{"service without name", &Subject{ID: "svc-2", SubjectType: "service"}, ErrMissingSubjectAttribute},
+		{"device with null id", &Subject{ID: "dev-1", SubjectType: "device", Attributes: JSONMap{"device_id": nil}}, ErrMissingSubjectAttribute},
+		{"unknown type", &Subject{ID: "sub-2", SubjectType: "employee"}, ErrUnknownSubjectType},
+		{"empty type", &Subject{ID: "sub-3"}, ErrUnknownSubjectType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := registry.ValidateSubject(tt.subject); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseSubjectTypeRegistry(t *testing.T) {
+	registry, err := ParseSubjectTypeRegistry([]byte(`{
+		"employee": {"description": "Staff", "required_attributes": ["department"]},
+		"contractor": {}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if enum := strings.Join(registry.Enum(), ","); enum != "contractor,employee" {
+		t.Errorf("Expected contractor,employee, got %s", enum)
+	}
+	if definitions := registry.Definitions(); definitions[1].Type != "employee" || definitions[1].RequiredAttributes[0] != "department" {
+		t.Errorf("Unexpected definitions %+v", definitions)
+	}
+
+	for _, invalid := range []string{`[]`, `{}`, `{"": {}}`, `{"user": {"required_attributes": [""]}}`} {
+		if _, err := ParseSubjectTypeRegistry([]byte(invalid)); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestSubjectTypeRegistry_ValidatePolicy(t *testing.T) {
+	registry := DefaultSubjectTypeRegistry()
+	policy := &Policy{ID: "pol-1", Statement: JSONStatements{
+		{Condition: map[string]interface{}{
+			"StringEquals":    map[string]interface{}{"user.subject_type": "device"},
+			"StringNotEquals": map[string]interface{}{"user.subject_type": []interface{}{"anonymous", "bot"}},
+			"StringLike":      map[string]interface{}{"user.subject_type": "serv*"},
+		}},
+		{Condition: map[string]interface{}{
+			"Or": []interface{}{
+				map[string]interface{}{"StringEquals": map[string]interface{}{"user.subject_type": "robot"}},
+				map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "robot"}},
+			},
+		}},
+	}}
+
+	errs := registry.ValidatePolicy(policy)
+	if len(errs) != 2 {
+		t.Fatalf("Expected errors for bot and robot, got %v", errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, ErrUnknownSubjectType) {
+			t.Errorf("Expected ErrUnknownSubjectType, got %v", err)
+		}
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // JSONMap is a custom type for handling map[string]interface{} in GORM
@@ -224,8 +228,11 @@ type Subject struct {
 	SubjectType string    `json:"subject_type" gorm:"size:100;not null;index"`
 	Metadata    JSONMap   `json:"metadata" gorm:"type:jsonb"`
 	Attributes  JSONMap   `json:"attributes" gorm:"type:jsonb"`
+	TenantID    string    `json:"tenant_id,omitempty" gorm:"size:100;not null;default:'';index"` // Owning tenant, see storage.TenantStore
 	CreatedAt   time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty" gorm:"autoUpdateTime"`
+	// DeletedAt is set by DeleteSubject; soft-deleted subjects are hidden until restored
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"`
 }
 
 // TableName specifies the table name for Subject
@@ -235,14 +242,19 @@ func (Subject) TableName() string {
 
 // Resource represents an API, document, or data object
 type Resource struct {
-	ID           string    `json:"id" gorm:"primaryKey;size:255"`
-	ResourceType string    `json:"resource_type" gorm:"size:100;not null;index"`
-	ResourceID   string    `json:"resource_id" gorm:"size:255;index"`
-	Path         string    `json:"path" gorm:"size:500"`
-	ParentID     string    `json:"parent_id,omitempty" gorm:"size:255;index"`
-	Metadata     JSONMap   `json:"metadata" gorm:"type:jsonb"`
-	Attributes   JSONMap   `json:"attributes" gorm:"type:jsonb"`
-	CreatedAt    time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	ID           string          `json:"id" gorm:"primaryKey;size:255"`
+	ResourceType string          `json:"resource_type" gorm:"size:100;not null;index"`
+	ResourceID   string          `json:"resource_id" gorm:"size:255;index"`
+	Path         string          `json:"path" gorm:"size:500"`
+	ParentID     string          `json:"parent_id,omitempty" gorm:"size:255;index"`
+	Metadata     JSONMap         `json:"metadata" gorm:"type:jsonb"`
+	Attributes   JSONMap         `json:"attributes" gorm:"type:jsonb"`
+	Tags         JSONStringSlice `json:"tags,omitempty" gorm:"type:jsonb"` // "key=value" or bare "key" labels, see ResourceTag conditions
+	// TenantID is the owning tenant, see storage.TenantStore
+	TenantID  string    `json:"tenant_id,omitempty" gorm:"size:100;not null;default:'';index"`
+	CreatedAt time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	// DeletedAt is set by DeleteResource; soft-deleted resources are hidden until restored
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"`
 }
 
 // TableName specifies the table name for Resource
@@ -250,6 +262,50 @@ func (Resource) TableName() string {
 	return "resources"
 }
 
+// ResourceTag formats a tag as "key=value", or "key" when value is empty
+func ResourceTag(key, value string) string {
+	if value == "" {
+		return key
+	}
+	return key + "=" + value
+}
+
+// SplitResourceTag splits a tag into its key and value
+func SplitResourceTag(tag string) (key, value string) {
+	key, value, _ = strings.Cut(tag, "=")
+	return key, value
+}
+
+// NormalizeTags trims, de-duplicates and sorts tags so they behave as a set
+func NormalizeTags(tags []string) JSONStringSlice {
+	if tags == nil {
+		return nil
+	}
+	normalized := make(JSONStringSlice, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		key, value := SplitResourceTag(strings.TrimSpace(tag))
+		tag = ResourceTag(strings.TrimSpace(key), strings.TrimSpace(value))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTag reports whether the resource carries tag
+func (r *Resource) HasTag(tag string) bool {
+	for _, existing := range r.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // Action represents an operation that can be performed
 type Action struct {
 	ID             string `json:"id" gorm:"primaryKey;size:255"`
@@ -257,6 +313,8 @@ type Action struct {
 	ActionCategory string `json:"action_category" gorm:"size:100;index"`
 	Description    string `json:"description" gorm:"type:text"`
 	IsSystem       bool   `json:"is_system" gorm:"default:false;index"`
+	// DeletedAt is set by DeleteAction; soft-deleted actions are hidden until restored
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"`
 }
 
 // TableName specifies the table name for Action
@@ -273,8 +331,26 @@ type Policy struct {
 	Version     string         `json:"version" gorm:"size:50;not null"`
 	Statement   JSONStatements `json:"statement" gorm:"type:jsonb"`
 	Enabled     bool           `json:"enabled" gorm:"default:true;index"`
-	CreatedAt   time.Time      `json:"created_at,omitempty" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at,omitempty" gorm:"autoUpdateTime"`
+	// Namespace scopes the policy to one service's evaluations; empty makes it global
+	Namespace string `json:"namespace,omitempty" gorm:"size:100;index"`
+	// TenantID is the owning tenant; tenant-scoped storages (storage.TenantStore) only see their own policies
+	TenantID string `json:"tenant_id,omitempty" gorm:"size:100;not null;default:'';index"`
+	// Revision increases on every update; updates must carry the revision they were based on
+	Revision int64 `json:"revision" gorm:"not null;default:1"`
+	// EffectiveFrom/ExpiresAt bound the validity window; nil means unbounded
+	EffectiveFrom *time.Time `json:"effective_from,omitempty" gorm:"index"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	// RolloutPercent enforces the policy for that share of subjects (0-100) and evaluates it in shadow
+	// for the others; nil enforces it for everyone
+	RolloutPercent *int `json:"rollout_percent,omitempty"`
+	// Tags are free-form labels ("lockdown", "domain=pci") used to select, search and audit policies in bulk
+	Tags JSONStringSlice `json:"tags,omitempty" gorm:"type:jsonb"`
+	// Owner is the team accountable for the policy (e.g. "team-payments")
+	Owner     string    `json:"owner,omitempty" gorm:"size:255;index"`
+	CreatedAt time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" gorm:"autoUpdateTime"`
+	// DeletedAt is set by DeletePolicy; soft-deleted policies are never evaluated until restored
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"`
 }
 
 // TableName specifies the table name for Policy
@@ -282,6 +358,94 @@ func (Policy) TableName() string {
 	return "policies"
 }
 
+// InNamespace reports whether the policy applies to evaluations in namespace: global policies apply everywhere
+func (p *Policy) InNamespace(namespace string) bool {
+	return p.Namespace == "" || p.Namespace == namespace
+}
+
+// InRollout reports whether the policy is enforced for a subject in bucket (0-99)
+func (p *Policy) InRollout(bucket int) bool {
+	return p.RolloutPercent == nil || bucket < *p.RolloutPercent
+}
+
+// HasTag reports whether the policy carries tag
+func (p *Policy) HasTag(tag string) bool {
+	for _, existing := range p.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicySelector selects policies by ID or by tag; a policy matching either is selected
+type PolicySelector struct {
+	PolicyIDs []string `json:"policy_ids,omitempty"`
+	Tags      []string `json:"tags,omitempty"` // Policies carrying any of the tags
+}
+
+// IsEmpty reports whether the selector names no policy and no tag
+func (s PolicySelector) IsEmpty() bool {
+	return len(s.PolicyIDs) == 0 && len(s.Tags) == 0
+}
+
+// Matches reports whether the selector selects policy
+func (s PolicySelector) Matches(policy *Policy) bool {
+	for _, id := range s.PolicyIDs {
+		if id == policy.ID {
+			return true
+		}
+	}
+	for _, tag := range NormalizeTags(s.Tags) {
+		if policy.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicySchedule enables or disables the selected policies at RunAt, or every time Cron fires
+// (e.g. "0 18 * * FRI" to enable lockdown policies every Friday at 18:00 in Timezone)
+type PolicySchedule struct {
+	ID          string          `json:"id" gorm:"primaryKey;size:255"`
+	Description string          `json:"description,omitempty" gorm:"type:text"`
+	PolicyIDs   JSONStringSlice `json:"policy_ids,omitempty" gorm:"type:jsonb"`
+	Tags        JSONStringSlice `json:"tags,omitempty" gorm:"type:jsonb"`
+	Enabled     bool            `json:"enabled"` // State the selected policies are set to
+	// Exactly one of Cron (recurring, five-field cron expression) and RunAt (one-shot) is set
+	Cron      string     `json:"cron,omitempty" gorm:"size:100"`
+	RunAt     *time.Time `json:"run_at,omitempty"`
+	Timezone  string     `json:"timezone,omitempty" gorm:"size:64"` // IANA location of Cron; empty is UTC
+	CreatedBy string     `json:"created_by,omitempty" gorm:"size:255"`
+	CreatedAt time.Time  `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// NextRunAt is when the schedule is due next; nil once a one-shot schedule has run
+	NextRunAt *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+}
+
+// TableName specifies the table name for PolicySchedule
+func (PolicySchedule) TableName() string {
+	return "policy_schedules"
+}
+
+// Selector returns the policies the schedule toggles
+func (s *PolicySchedule) Selector() PolicySelector {
+	return PolicySelector{PolicyIDs: s.PolicyIDs, Tags: s.Tags}
+}
+
+// IsEffectiveAt reports whether t falls inside the policy validity window [EffectiveFrom, ExpiresAt)
+func (p *Policy) IsEffectiveAt(t time.Time) bool {
+	if p.EffectiveFrom != nil && t.Before(*p.EffectiveFrom) {
+		return false
+	}
+	return !p.IsExpiredAt(t)
+}
+
+// IsExpiredAt reports whether the policy has expired at t
+func (p *Policy) IsExpiredAt(t time.Time) bool {
+	return p.ExpiresAt != nil && !t.Before(*p.ExpiresAt)
+}
+
 // PolicyRule represents a single rule within a policy (legacy format)
 type PolicyRule struct {
 	ID            string             `json:"id,omitempty"`
@@ -303,6 +467,14 @@ type PolicyStatement struct {
 	Resource    JSONActionResource `json:"Resource"`              // string or []string
 	NotResource JSONActionResource `json:"NotResource,omitempty"` // Exclusion patterns
 	Condition   JSONMap            `json:"Condition,omitempty"`   // Runtime conditions
+	Fields      JSONActionResource `json:"Fields,omitempty"`      // Field patterns for field-level statements ("Allow", "Deny" or "Mask")
+	Obligations map[string]string  `json:"Obligations,omitempty"` // Carried by decisions this statement contributes to, e.g. {"audit": "full"}
+	Constraints *Constraints       `json:"Constraints,omitempty"` // Narrows the permits of an Allow statement, e.g. {"read_only": true}
+}
+
+// IsFieldLevel reports whether the statement applies to individual fields rather than the whole resource
+func (s PolicyStatement) IsFieldLevel() bool {
+	return len(s.Fields.GetValues()) > 0
 }
 
 // PolicyDocument represents the complete policy document
@@ -321,6 +493,28 @@ type EvaluationRequest struct {
 	// Enhanced fields for improved PDP
 	Environment *EnvironmentInfo `json:"environment,omitempty"`
 	Timestamp   *time.Time       `json:"timestamp,omitempty"`
+	Session     *SessionInfo     `json:"session,omitempty"`
+	// AsOf evaluates the request with subject/resource attributes as they were at that time
+	AsOf *time.Time `json:"as_of,omitempty"`
+	// Snapshots supplies point-in-time attributes that replace the stored ones
+	Snapshots *AttributeSnapshots `json:"snapshots,omitempty"`
+	// Namespace limits evaluation to global policies plus those of the namespace; empty uses the PDP default
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AttributeSnapshots carries request-supplied point-in-time attributes (nil entries are looked up)
+type AttributeSnapshots struct {
+	Subject  map[string]interface{} `json:"subject,omitempty"`
+	Resource map[string]interface{} `json:"resource,omitempty"`
+}
+
+// SessionInfo describes the authenticated session behind a request.
+// It is exposed to conditions as session.* attributes (e.g. session.mfa_verified, session.auth_age_seconds).
+type SessionInfo struct {
+	SessionID   string     `json:"session_id,omitempty"`
+	AuthMethod  string     `json:"auth_method,omitempty"` // e.g. "password", "sso", "webauthn"
+	MFAVerified bool       `json:"mfa_verified"`
+	AuthTime    *time.Time `json:"auth_time,omitempty"` // When the subject last authenticated
 }
 
 // EnvironmentInfo represents environmental context for basic PDP
@@ -332,6 +526,34 @@ type EnvironmentInfo struct {
 	TimeOfDay  string                 `json:"time_of_day,omitempty"` // "14:30"
 	DayOfWeek  string                 `json:"day_of_week,omitempty"` // "Monday"
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// ClientCert is the mutual TLS client certificate presented by the caller (see pep.ClientCertFromTLS)
+	ClientCert *ClientCertInfo `json:"client_cert,omitempty"`
+}
+
+// ClientCertInfo describes a TLS client certificate.
+// It is exposed to conditions as environment.client_cert.* attributes (e.g. environment.client_cert.subject_dn).
+type ClientCertInfo struct {
+	SubjectDN      string    `json:"subject_dn"`                // e.g. "CN=payment-service,O=Acme"
+	CommonName     string    `json:"common_name,omitempty"`     // Subject CN
+	IssuerDN       string    `json:"issuer_dn"`                 // DN of the issuing CA
+	SerialNumber   string    `json:"serial_number,omitempty"`   // Decimal serial number
+	Fingerprint    string    `json:"fingerprint"`               // SHA-256 of the DER certificate, lowercase hex
+	DNSNames       []string  `json:"dns_names,omitempty"`       // DNS SANs
+	URIs           []string  `json:"uris,omitempty"`            // URI SANs, e.g. SPIFFE IDs
+	EmailAddresses []string  `json:"email_addresses,omitempty"` // Email SANs
+	IPAddresses    []string  `json:"ip_addresses,omitempty"`    // IP SANs
+	NotBefore      time.Time `json:"not_before"`
+	NotAfter       time.Time `json:"not_after"`
+	// Verified is true only when the certificate chained to a trusted client CA during the TLS handshake
+	Verified bool `json:"verified"`
+	// IssuerChain lists the CAs of the verified chain, immediate issuer first and root last; empty when not verified
+	IssuerChain []CertIssuer `json:"issuer_chain,omitempty"`
+}
+
+// CertIssuer is one CA certificate of a verified client certificate chain
+type CertIssuer struct {
+	SubjectDN   string `json:"subject_dn"`
+	Fingerprint string `json:"fingerprint"` // SHA-256 of the DER certificate, lowercase hex
 }
 
 // EvaluationContext contains all the context needed for evaluation
@@ -341,6 +563,24 @@ type EvaluationContext struct {
 	Action      *Action
 	Environment map[string]interface{}
 	Timestamp   time.Time
+	Conflicts   []AttributeConflict
+	// AttributeSources records where entity attributes came from ("subject"/"resource" -> AttributeSource*)
+	AttributeSources map[string]string
+	// Relationships holds subject/resource relationships derived during enrichment (e.g. "is_owner")
+	Relationships map[string]bool
+	// Device holds device posture attributes contributed by a DevicePostureProvider (nil when none is configured)
+	Device map[string]interface{}
+	// Elevations lists the IDs of the elevations whose roles and attributes were granted to the subject
+	Elevations []string
+}
+
+// AttributeConflict records a key supplied both by storage and by the request
+type AttributeConflict struct {
+	Entity       string      `json:"entity"` // "subject" or "resource"
+	Key          string      `json:"key"`
+	StoredValue  interface{} `json:"stored_value"`
+	RequestValue interface{} `json:"request_value"`
+	Resolution   string      `json:"resolution"` // "request" or "stored"
 }
 
 // Decision represents the result of a policy evaluation
@@ -349,6 +589,87 @@ type Decision struct {
 	MatchedPolicies  []string `json:"matched_policies"`
 	EvaluationTimeMs int      `json:"evaluation_time_ms"`
 	Reason           string   `json:"reason,omitempty"`
+	// ReasonCode and ReasonDetails describe the reason in structured form for localization
+	ReasonCode    string            `json:"reason_code,omitempty"`
+	ReasonDetails map[string]string `json:"reason_details,omitempty"`
+	// CacheTTL is how many seconds a PEP may cache the decision; 0 means it must not be cached
+	CacheTTL int `json:"cache_ttl"`
+	// Shadow is the decision with every canary policy enforced, set when a rollout held one back
+	Shadow *ShadowDecision `json:"shadow,omitempty"`
+	// Obligations merged from the statements that decided the result (constants.Obligation* keys)
+	Obligations map[string]string `json:"obligations,omitempty"`
+	// Constraints limit a permit to part of what the action allows; nil is an unconstrained permit
+	Constraints *Constraints `json:"constraints,omitempty"`
+	// QuarantinedPolicies applied to the request but were skipped as too slow (Indeterminate contribution)
+	QuarantinedPolicies []string `json:"quarantined_policies,omitempty"`
+}
+
+// Constraints turn a permit into a filtered permit, e.g. "permit but only columns A,B" or
+// "permit read-only". The PDP only reports them; PEPs enforce them (see pep.ApplyConstraints).
+type Constraints struct {
+	Fields   []string `json:"fields,omitempty"`    // Field patterns the subject may see; empty allows every field
+	ReadOnly bool     `json:"read_only,omitempty"` // Only non-mutating operations are permitted
+}
+
+// IsEmpty reports whether c constrains nothing
+func (c *Constraints) IsEmpty() bool {
+	return c == nil || len(c.Fields) == 0 && !c.ReadOnly
+}
+
+// ShadowDecision is the not-enforced result of canary policies whose rollout excludes the subject
+type ShadowDecision struct {
+	Result          string   `json:"result"`
+	MatchedPolicies []string `json:"matched_policies"`
+	CanaryPolicies  []string `json:"canary_policies"`
+}
+
+// FieldDirective is the field-level authorization result for a single field
+type FieldDirective struct {
+	Field    string `json:"field"`
+	Effect   string `json:"effect"` // "allow", "deny" or "mask"
+	PolicyID string `json:"policy_id,omitempty"`
+	Sid      string `json:"sid,omitempty"`
+}
+
+// FieldDecision combines the resource-level decision with per-field directives
+type FieldDecision struct {
+	Decision *Decision                 `json:"decision"`
+	Fields   map[string]FieldDirective `json:"fields"`
+}
+
+// Explanation describes how a decision was reached
+type Explanation struct {
+	Decision           *Decision              `json:"decision"`
+	Statements         []StatementTrace       `json:"statements"`
+	AttributeConflicts []AttributeConflict    `json:"attribute_conflicts,omitempty"`
+	AsOf               *time.Time             `json:"as_of,omitempty"`
+	AttributeSources   map[string]string      `json:"attribute_sources,omitempty"`
+	Relationships      map[string]bool        `json:"relationships,omitempty"`
+	Device             map[string]interface{} `json:"device,omitempty"`
+	Elevations         []string               `json:"elevations,omitempty"`
+	// Tree is the decision → policy → statement → condition tree, renderable with ExplainNode.ToDOT
+	Tree *ExplainNode `json:"tree,omitempty"`
+}
+
+// StatementTrace records the evaluation outcome of a single policy statement
+type StatementTrace struct {
+	PolicyID          string `json:"policy_id"`
+	Sid               string `json:"sid,omitempty"`
+	Effect            string `json:"effect"`
+	ActionMatched     bool   `json:"action_matched"`
+	ResourceMatched   bool   `json:"resource_matched"`
+	ConditionsMatched bool   `json:"conditions_matched"`
+	Matched           bool   `json:"matched"`
+	// Conditions holds the outcome of each condition block evaluated on its own
+	Conditions []ConditionTrace `json:"conditions,omitempty"`
+}
+
+// ConditionTrace records the outcome of a single operator/key condition.
+// Logical operators (And, Or, Not) are traced as one unit with an empty Key.
+type ConditionTrace struct {
+	Operator string `json:"operator"`
+	Key      string `json:"key,omitempty"`
+	Matched  bool   `json:"matched"`
 }
 
 // Enhanced decision types for improved PDP
@@ -462,6 +783,205 @@ type SimpleCondition struct {
 	Value         interface{} `json:"value"`
 }
 
+// Entity types of attribute snapshots
+const (
+	SnapshotEntitySubject  = "subject"
+	SnapshotEntityResource = "resource"
+)
+
+// Attribute sources reported for point-in-time evaluation
+const (
+	AttributeSourceCurrent  = "current"  // Stored attributes at evaluation time
+	AttributeSourceHistory  = "history"  // Latest AttributeSnapshot at or before AsOf
+	AttributeSourceRequest  = "request"  // EvaluationRequest.Snapshots
+	AttributeSourcePrepared = "prepared" // Prepared subject context of the request's session
+)
+
+// AttributeSnapshot records the attributes of a subject or resource from ValidFrom onwards
+type AttributeSnapshot struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	EntityType string    `json:"entity_type" gorm:"size:20;not null;index:idx_attribute_snapshots_entity"` // "subject" or "resource"
+	EntityID   string    `json:"entity_id" gorm:"size:255;not null;index:idx_attribute_snapshots_entity"`
+	Attributes JSONMap   `json:"attributes" gorm:"type:jsonb"`
+	TenantID   string    `json:"tenant_id,omitempty" gorm:"size:100;not null;default:'';index"` // Tenant of the entity
+	ValidFrom  time.Time `json:"valid_from" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for AttributeSnapshot
+func (AttributeSnapshot) TableName() string {
+	return "attribute_snapshots"
+}
+
+// Group is a named set of subjects and other groups, exposed to policies as user.groups
+type Group struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:255"`
+	Name        string    `json:"name" gorm:"size:255"`
+	Description string    `json:"description,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for Group
+func (Group) TableName() string {
+	return "groups"
+}
+
+// Member types of group memberships
+const (
+	GroupMemberSubject = "subject"
+	GroupMemberGroup   = "group" // Nested group: members of MemberID are members of GroupID
+)
+
+// GroupMembership records that a subject or a group is a direct member of GroupID
+type GroupMembership struct {
+	GroupID    string    `json:"group_id" gorm:"primaryKey;size:255"`
+	MemberType string    `json:"member_type" gorm:"primaryKey;size:20"` // GroupMemberSubject or GroupMemberGroup
+	MemberID   string    `json:"member_id" gorm:"primaryKey;size:255;index:idx_group_memberships_member"`
+	CreatedAt  time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GroupMembership
+func (GroupMembership) TableName() string {
+	return "group_memberships"
+}
+
+// AccessException explicitly allows or denies one subject one action on one resource until
+// ExpiresAt, overriding policy evaluation for one-off business exceptions
+type AccessException struct {
+	ID            string    `json:"id" gorm:"primaryKey;size:255"`
+	SubjectID     string    `json:"subject_id" gorm:"size:255;not null;index:idx_access_exceptions_lookup"`
+	ResourceID    string    `json:"resource_id" gorm:"size:255;not null;index:idx_access_exceptions_lookup"`
+	Action        string    `json:"action" gorm:"size:255;not null;index:idx_access_exceptions_lookup"`
+	Effect        string    `json:"effect" gorm:"size:10;not null"` // constants.EffectAllow or constants.EffectDeny
+	Justification string    `json:"justification" gorm:"type:text;not null"`
+	CreatedBy     string    `json:"created_by,omitempty" gorm:"size:255"`
+	CreatedAt     time.Time `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for AccessException
+func (AccessException) TableName() string {
+	return "access_exceptions"
+}
+
+// ActiveAt reports whether the exception has not expired at t
+func (e *AccessException) ActiveAt(t time.Time) bool {
+	return t.Before(e.ExpiresAt)
+}
+
+// Revocation suspends all access of a subject or of one session (e.g. a compromised account or
+// stolen token) until it is lifted or ExpiresAt passes, regardless of policies and exceptions
+type Revocation struct {
+	ID        string     `json:"id" gorm:"primaryKey;size:255"`
+	SubjectID string     `json:"subject_id,omitempty" gorm:"size:255;index"`
+	SessionID string     `json:"session_id,omitempty" gorm:"size:255;index"`
+	Reason    string     `json:"reason" gorm:"type:text;not null"`
+	CreatedBy string     `json:"created_by,omitempty" gorm:"size:255"`
+	CreatedAt time.Time  `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"` // nil keeps the revocation until it is deleted
+}
+
+// TableName specifies the table name for Revocation
+func (Revocation) TableName() string {
+	return "revocations"
+}
+
+// ActiveAt reports whether the revocation has not expired at t
+func (r *Revocation) ActiveAt(t time.Time) bool {
+	return r.ExpiresAt == nil || t.Before(*r.ExpiresAt)
+}
+
+// Elevation grants a subject extra roles and attributes until ExpiresAt ("sudo mode"): the
+// attribute resolver merges them into user.roles and the subject attributes only while active,
+// enabling just-in-time privilege workflows without editing the stored subject
+type Elevation struct {
+	ID            string          `json:"id" gorm:"primaryKey;size:255"`
+	SubjectID     string          `json:"subject_id" gorm:"size:255;not null;index"`
+	Roles         JSONStringSlice `json:"roles,omitempty" gorm:"type:jsonb"`
+	Attributes    JSONMap         `json:"attributes,omitempty" gorm:"type:jsonb"`
+	Justification string          `json:"justification" gorm:"type:text;not null"`
+	CreatedBy     string          `json:"created_by,omitempty" gorm:"size:255"`
+	CreatedAt     time.Time       `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	ExpiresAt     time.Time       `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for Elevation
+func (Elevation) TableName() string {
+	return "elevations"
+}
+
+// ActiveAt reports whether the elevation has started and not expired at t
+func (e *Elevation) ActiveAt(t time.Time) bool {
+	return !t.Before(e.CreatedAt) && t.Before(e.ExpiresAt)
+}
+
+// Policy change actions
+const (
+	PolicyChangeCreate  = "create"
+	PolicyChangeUpdate  = "update"
+	PolicyChangeDelete  = "delete"
+	PolicyChangeRestore = "restore"
+)
+
+// PolicyDiffOp is one difference between two policy documents. Path is a JSON Pointer into the
+// policy JSON (e.g. "/statement/0/Effect"); Op is "add", "remove" or "replace".
+type PolicyDiffOp struct {
+	Op   string      `json:"op"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// JSONPolicyDiff is a custom type for handling []PolicyDiffOp in GORM
+type JSONPolicyDiff []PolicyDiffOp
+
+// Value implements the driver.Valuer interface for GORM
+func (j JSONPolicyDiff) Value() (driver.Value, error) {
+	if j == nil {
+		return nil, nil
+	}
+	return json.Marshal(j)
+}
+
+// Scan implements the sql.Scanner interface for GORM
+func (j *JSONPolicyDiff) Scan(value interface{}) error {
+	if value == nil {
+		*j = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONPolicyDiff", value)
+	}
+
+	return json.Unmarshal(bytes, j)
+}
+
+// PolicyChange records who created, updated or deleted a policy and what changed
+type PolicyChange struct {
+	ID        int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	PolicyID  string         `json:"policy_id" gorm:"size:255;not null;index:idx_policy_changes_policy"`
+	Action    string         `json:"action" gorm:"size:20;not null"` // PolicyChangeCreate, PolicyChangeUpdate, PolicyChangeDelete or PolicyChangeRestore
+	Actor     string         `json:"actor" gorm:"size:255;not null;index"`
+	Revision  int64          `json:"revision"` // Policy revision after the change (before it, for deletes)
+	Diff      JSONPolicyDiff `json:"diff" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"created_at" gorm:"not null;index:idx_policy_changes_policy"`
+}
+
+// TableName specifies the table name for PolicyChange
+func (PolicyChange) TableName() string {
+	return "policy_changes"
+}
+
+// AuditSourceEvaluation marks audit entries that record a PDP decision, as opposed to
+// administrative actions, whose Source is empty
+const AuditSourceEvaluation = "evaluation"
+
 // AuditLog represents an audit log entry
 type AuditLog struct {
 	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
@@ -470,6 +990,7 @@ type AuditLog struct {
 	ResourceID   string    `json:"resource_id" gorm:"size:255;not null;index"`
 	ActionID     string    `json:"action_id" gorm:"size:255;not null;index"`
 	Decision     string    `json:"decision" gorm:"size:20;not null;index"`
+	Source       string    `json:"source,omitempty" gorm:"size:20;not null;default:''"` // AuditSourceEvaluation for PDP decisions
 	EvaluationMs int       `json:"evaluation_ms" gorm:"not null"`
 	Context      JSONMap   `json:"context" gorm:"type:jsonb"`
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index"`
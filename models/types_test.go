@@ -418,3 +418,52 @@ func TestEnhancedEvaluationRequest(t *testing.T) {
 		t.Errorf("Expected ClientIP %s, got %s", request.Environment.ClientIP, unmarshaled.Environment.ClientIP)
 	}
 }
+
+func TestPolicyIsEffectiveAt(t *testing.T) {
+	from := time.Date(2024, time.December, 24, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.December, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		at       time.Time
+		expected bool
+	}{
+		{"unbounded", Policy{}, from, true},
+		{"before effective_from", Policy{EffectiveFrom: &from}, from.Add(-time.Second), false},
+		{"at effective_from", Policy{EffectiveFrom: &from}, from, true},
+		{"before expires_at", Policy{ExpiresAt: &until}, until.Add(-time.Second), true},
+		{"at expires_at", Policy{ExpiresAt: &until}, until, false},
+		{"inside window", Policy{EffectiveFrom: &from, ExpiresAt: &until}, from.Add(time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsEffectiveAt(tt.at); got != tt.expected {
+				t.Errorf("IsEffectiveAt(%s) = %v, expected %v", tt.at, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResourceTags(t *testing.T) {
+	tags := NormalizeTags(JSONStringSlice{" pii=true", "env=prod", "pii=true", "", "archived"})
+	if len(tags) != 3 || tags[0] != "archived" || tags[1] != "env=prod" || tags[2] != "pii=true" {
+		t.Errorf("Unexpected normalized tags: %v", tags)
+	}
+	if NormalizeTags(nil) != nil {
+		t.Error("Expected nil tags to stay nil")
+	}
+
+	resource := &Resource{Tags: tags}
+	if !resource.HasTag(ResourceTag("pii", "true")) || !resource.HasTag(ResourceTag("archived", "")) {
+		t.Errorf("Expected resource to carry pii=true and archived, got %v", resource.Tags)
+	}
+	if resource.HasTag("pii=false") {
+		t.Error("Expected pii=false to be absent")
+	}
+
+	if key, value := SplitResourceTag("owner=team=a"); key != "owner" || value != "team=a" {
+		t.Errorf("SplitResourceTag = %q, %q", key, value)
+	}
+}
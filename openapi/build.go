@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Content types of request and response bodies
+const (
+	ContentTypeJSON = "application/json"
+)
+
+// Security scheme names of protected operations
+const (
+	SecurityUserID = "userId"
+	SecurityBearer = "bearerAuth"
+)
+
+// Route annotates one HTTP endpoint for the document. Path uses gin syntax (/policies/:id).
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string // lowerCamelCase, also the generated client method name
+	Summary     string
+	Description string
+	Tag         string
+	Permission  string      // ABAC action checked by the middleware; empty for public endpoints
+	Query       []Parameter // Query and header parameters; path parameters are derived from Path
+	Request     interface{} // Sample of the JSON body type, nil without a body
+	RequestType string      // Content type of a non-JSON body, e.g. "application/x-ndjson"
+	Response    interface{} // Sample of the JSON response type, nil for a free-form object
+	Status      int         // Success status, http.StatusOK when zero
+	// ResponseType is the content type of a non-JSON success response, e.g. "text/event-stream"
+	ResponseType string
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details,omitempty"`
+}
+
+// QueryParam is a shorthand for an optional query parameter
+func QueryParam(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// HeaderParam is a shorthand for an optional header parameter
+func HeaderParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// Build generates the document of routes. Schemas come from generator, which may carry
+// overrides for types with a custom JSON encoding; a nil generator uses NewGenerator().
+func Build(info Info, routes []Route, generator *Generator) *Document {
+	if generator == nil {
+		generator = NewGenerator()
+	}
+	errorSchema := generator.SchemaFor(ErrorResponse{})
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				SecurityUserID: {Type: "apiKey", In: "header", Name: "X-User-ID", Description: "Subject ID of the caller"},
+				SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "JWT/OIDC token whose claims are mapped to subject attributes"},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		path, pathParams := openAPIPath(route.Path)
+		operation := &Operation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Description: route.Description,
+			Parameters:  append(pathParams, route.Query...),
+			Responses:   make(map[string]*Response),
+			Permission:  route.Permission,
+		}
+		if route.Tag != "" {
+			operation.Tags = []string{route.Tag}
+		}
+
+		if route.Request != nil || route.RequestType != "" {
+			contentType, schema := ContentTypeJSON, generator.SchemaFor(route.Request)
+			if route.RequestType != "" {
+				contentType, schema = route.RequestType, &Schema{Type: "string", Format: "binary"}
+			}
+			operation.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{contentType: {Schema: schema}}}
+			operation.Responses["400"] = errorResponse("Invalid request", errorSchema)
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &Response{Description: http.StatusText(status)}
+		switch {
+		case route.ResponseType != "":
+			success.Content = map[string]MediaType{route.ResponseType: {Schema: &Schema{Type: "string"}}}
+		case status != http.StatusNoContent:
+			schema := generator.SchemaFor(route.Response)
+			if schema == nil {
+				schema = &Schema{Type: "object", AdditionalProperties: &Schema{}}
+			}
+			success.Content = map[string]MediaType{ContentTypeJSON: {Schema: schema}}
+		}
+		operation.Responses[strconv.Itoa(status)] = success
+
+		if route.Permission != "" {
+			operation.Security = []map[string][]string{{SecurityUserID: {}}, {SecurityBearer: {}}}
+			operation.Responses["401"] = errorResponse("Authentication required", errorSchema)
+			operation.Responses["403"] = errorResponse("Access denied by policy", errorSchema)
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	doc.Components.Schemas = generator.Schemas()
+	return doc
+}
+
+// Operations returns the operations of the document sorted by path, then method
+func (d *Document) Operations() []OperationRef {
+	var refs []OperationRef
+	for path, item := range d.Paths {
+		for method, operation := range item {
+			refs = append(refs, OperationRef{Method: strings.ToUpper(method), Path: path, Operation: operation})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Path != refs[j].Path {
+			return refs[i].Path < refs[j].Path
+		}
+		return refs[i].Method < refs[j].Method
+	})
+	return refs
+}
+
+// OperationRef is an operation with its method and OpenAPI path
+type OperationRef struct {
+	Method    string
+	Path      string
+	Operation *Operation
+}
+
+// openAPIPath converts a gin path to OpenAPI syntax (/policies/:id -> /policies/{id}) and
+// returns its path parameters
+func openAPIPath(ginPath string) (string, []Parameter) {
+	segments := strings.Split(ginPath, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func errorResponse(description string, schema *Schema) *Response {
+	return &Response{Description: description, Content: map[string]MediaType{ContentTypeJSON: {Schema: schema}}}
+}
+
+// GoName converts a snake_case, kebab-case or lowerCamel identifier to an exported Go name,
+// upper-casing common initialisms (request_id -> RequestID)
+func GoName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// initialisms are written in upper case in Go names
+var initialisms = map[string]bool{
+	"ID": true, "IP": true, "URL": true, "URI": true, "TTL": true, "JSON": true, "HTTP": true,
+	"API": true, "CIDR": true, "JWT": true, "TLS": true, "SSE": true, "UUID": true,
+}
+
+// splitWords splits an identifier at separators and lower-to-upper case changes
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
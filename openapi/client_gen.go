@@ -0,0 +1,388 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateClient writes Go source for a typed client of doc into package pkg: one struct per
+// component schema and one method per operation on *Client. The generated file relies on the
+// hand-written Client type of the package, which provides:
+//
+//	do(ctx context.Context, method, path string, query url.Values, header http.Header, contentType string, body, out interface{}) error
+//	stream(ctx context.Context, method, path string, query url.Values) (io.ReadCloser, error)
+//
+// do JSON-encodes body unless it is an io.Reader sent as contentType, and decodes JSON into out when non-nil.
+func GenerateClient(doc *Document, pkg string) ([]byte, error) {
+	g := &clientGenerator{doc: doc}
+	g.printf("// Code generated by openapi.GenerateClient from %s %s. DO NOT EDIT.\n\n", doc.Info.Title, doc.Info.Version)
+	g.printf("package %s\n\n", pkg)
+
+	var body bytes.Buffer
+	g.out = &body
+	g.components()
+	for _, ref := range doc.Operations() {
+		if err := g.operation(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	var file bytes.Buffer
+	file.Write(g.header.Bytes())
+	file.WriteString("import (\n\"context\"\n")
+	if g.usesIO {
+		file.WriteString("\"io\"\n")
+	}
+	if g.usesHTTP {
+		file.WriteString("\"net/http\"\n")
+	}
+	if g.usesURL {
+		file.WriteString("\"net/url\"\n")
+	}
+	if g.usesStrconv {
+		file.WriteString("\"strconv\"\n")
+	}
+	if g.usesTime {
+		file.WriteString("\"time\"\n")
+	}
+	file.WriteString(")\n\n")
+	file.Write(body.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated client does not compile: %w", err)
+	}
+	return source, nil
+}
+
+type clientGenerator struct {
+	doc         *Document
+	header      bytes.Buffer
+	out         *bytes.Buffer
+	usesIO      bool
+	usesHTTP    bool
+	usesURL     bool
+	usesTime    bool
+	usesStrconv bool
+}
+
+func (g *clientGenerator) printf(format string, args ...interface{}) {
+	if g.out == nil {
+		fmt.Fprintf(&g.header, format, args...)
+		return
+	}
+	fmt.Fprintf(g.out, format, args...)
+}
+
+// components emits a type per component schema
+func (g *clientGenerator) components() {
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := g.doc.Components.Schemas[name]
+		g.printf("// %s mirrors the %s schema\n", GoName(name), name)
+		if schema.Type == "object" && len(schema.Properties) > 0 {
+			g.printf("type %s %s\n\n", GoName(name), g.structType(schema))
+			continue
+		}
+		g.printf("type %s %s\n\n", GoName(name), g.goType(schema))
+	}
+}
+
+// structType renders the fields of an object schema, sorted by JSON name
+func (g *clientGenerator) structType(schema *Schema) string {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		field := GoName(name)
+		if field == "" || used[field] {
+			field = "Field" + strconv.Itoa(len(used))
+		}
+		used[field] = true
+
+		property := schema.Properties[name]
+		tag := name
+		if !required[name] && (omitEmpty(property) || property.Nullable) {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", field, g.fieldType(property, required[name]), tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// fieldType is the Go type of a struct field: optional objects, times and nullable scalars are pointers
+func (g *clientGenerator) fieldType(schema *Schema, required bool) string {
+	if !required && (schema.RefName() != "" || (schema.Type == "string" && schema.Format == "date-time") || schema.Nullable) {
+		return "*" + g.goType(schema)
+	}
+	return g.goType(schema)
+}
+
+// omitEmpty reports whether a field may be left out when zero; booleans and numbers are always sent
+func omitEmpty(schema *Schema) bool {
+	return schema.Type != "boolean" && schema.Type != "integer" && schema.Type != "number"
+}
+
+// goType is the Go type of values of schema
+func (g *clientGenerator) goType(schema *Schema) string {
+	if schema == nil {
+		return "interface{}"
+	}
+	if name := schema.RefName(); name != "" {
+		return GoName(name)
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			g.usesTime = true
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if schema.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items)
+	case "object":
+		if len(schema.Properties) > 0 {
+			return g.structType(schema)
+		}
+		return "map[string]" + g.goType(schema.AdditionalProperties)
+	}
+	return "interface{}"
+}
+
+// operation emits the client method of one operation
+func (g *clientGenerator) operation(ref OperationRef) error {
+	op := ref.Operation
+	if op.OperationID == "" {
+		return fmt.Errorf("%s %s has no operationId", ref.Method, ref.Path)
+	}
+	name := GoName(op.OperationID)
+
+	args := []string{"ctx context.Context"}
+	var pathParams, optionalParams []Parameter
+	query, header := "nil", "nil"
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, param)
+			args = append(args, argName(param.Name)+" string")
+		case "query":
+			optionalParams = append(optionalParams, param)
+			query = "params.values()"
+		case "header":
+			optionalParams = append(optionalParams, param)
+			header = "params.header()"
+		}
+	}
+	if len(optionalParams) > 0 {
+		g.requestParams(name+"Params", optionalParams)
+		args = append(args, "params *"+name+"Params")
+	}
+
+	contentType, body := `""`, "nil"
+	if op.RequestBody != nil {
+		for mediaType, content := range op.RequestBody.Content {
+			if mediaType == ContentTypeJSON {
+				args = append(args, "body *"+g.goType(content.Schema))
+			} else {
+				g.usesIO = true
+				args = append(args, "body io.Reader")
+				contentType = strconv.Quote(mediaType)
+			}
+		}
+		body = "body"
+	}
+
+	success := successResponse(op)
+	returnType := ""
+	streamed := false
+	if success != nil && success.Content != nil {
+		if content, ok := success.Content[ContentTypeJSON]; ok {
+			returnType = g.goType(content.Schema)
+			if content.Schema.RefName() != "" {
+				returnType = "*" + returnType
+			}
+		} else {
+			g.usesIO = true
+			returnType = "io.ReadCloser"
+			streamed = true
+		}
+	}
+
+	g.printf("// %s calls %s %s", name, ref.Method, ref.Path)
+	if op.Summary != "" {
+		g.printf(": %s", op.Summary)
+	}
+	g.printf("\n")
+	if op.Permission != "" {
+		g.printf("// The caller must be permitted %q.\n", op.Permission)
+	}
+	if streamed {
+		g.printf("// The caller must close the returned body.\n")
+	}
+
+	path := pathExpression(ref.Path, pathParams)
+	g.usesURL = g.usesURL || len(pathParams) > 0
+	switch {
+	case streamed:
+		g.printf("func (c *Client) %s(%s) (io.ReadCloser, error) {\n", name, strings.Join(args, ", "))
+		g.printf("return c.stream(ctx, %q, %s, %s)\n}\n\n", ref.Method, path, query)
+	case returnType == "":
+		g.printf("func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+		g.printf("return c.do(ctx, %q, %s, %s, %s, %s, %s, nil)\n}\n\n", ref.Method, path, query, header, contentType, body)
+	default:
+		g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), returnType)
+		g.printf("var out %s\n", strings.TrimPrefix(returnType, "*"))
+		g.printf("if err := c.do(ctx, %q, %s, %s, %s, %s, %s, &out); err != nil {\nreturn nil, err\n}\n", ref.Method, path, query, header, contentType, body)
+		if strings.HasPrefix(returnType, "*") {
+			g.printf("return &out, nil\n}\n\n")
+		} else {
+			g.printf("return out, nil\n}\n\n")
+		}
+	}
+	return nil
+}
+
+// requestParams emits the parameter struct of an operation with its url.Values and http.Header
+// conversions. Zero values are not sent; booleans are pointers so false can be sent.
+func (g *clientGenerator) requestParams(typeName string, params []Parameter) {
+	g.printf("// %s holds the optional parameters of %s\n", typeName, strings.TrimSuffix(typeName, "Params"))
+	g.printf("type %s struct {\n", typeName)
+	for _, param := range params {
+		if param.Description != "" {
+			g.printf("// %s\n", param.Description)
+		}
+		g.printf("%s %s\n", GoName(param.Name), queryGoType(param.Schema))
+	}
+	g.printf("}\n\n")
+
+	g.paramsMethod(typeName, "values", "url.Values", "query", params)
+	g.paramsMethod(typeName, "header", "http.Header", "header", params)
+}
+
+// paramsMethod emits the conversion of the parameters located in "in", if there are any
+func (g *clientGenerator) paramsMethod(typeName, method, resultType, in string, params []Parameter) {
+	var located []Parameter
+	for _, param := range params {
+		if param.In == in {
+			located = append(located, param)
+		}
+	}
+	if len(located) == 0 {
+		return
+	}
+	if in == "header" {
+		g.usesHTTP = true
+	} else {
+		g.usesURL = true
+	}
+
+	g.printf("func (p *%s) %s() %s {\n", typeName, method, resultType)
+	g.printf("values := %s{}\nif p == nil {\nreturn values\n}\n", resultType)
+	for _, param := range located {
+		field := "p." + GoName(param.Name)
+		switch queryGoType(param.Schema) {
+		case "*bool":
+			g.usesStrconv = true
+			g.printf("if %s != nil {\nvalues.Set(%q, strconv.FormatBool(*%s))\n}\n", field, param.Name, field)
+		case "int":
+			g.usesStrconv = true
+			g.printf("if %s != 0 {\nvalues.Set(%q, strconv.Itoa(%s))\n}\n", field, param.Name, field)
+		default:
+			g.printf("if %s != \"\" {\nvalues.Set(%q, %s)\n}\n", field, param.Name, field)
+		}
+	}
+	g.printf("return values\n}\n\n")
+}
+
+func queryGoType(schema *Schema) string {
+	switch schema.Type {
+	case "boolean":
+		return "*bool"
+	case "integer":
+		return "int"
+	}
+	return "string"
+}
+
+// successResponse returns the 2xx response of an operation
+func successResponse(op *Operation) *Response {
+	for code, response := range op.Responses {
+		if status, err := strconv.Atoi(code); err == nil && status >= http.StatusOK && status < http.StatusMultipleChoices {
+			return response
+		}
+	}
+	return nil
+}
+
+// pathExpression renders a Go expression building path with escaped parameter arguments
+func pathExpression(path string, params []Parameter) string {
+	if len(params) == 0 {
+		return strconv.Quote(path)
+	}
+	var parts []string
+	rest := path
+	for _, param := range params {
+		placeholder := "{" + param.Name + "}"
+		before, after, _ := strings.Cut(rest, placeholder)
+		if before != "" {
+			parts = append(parts, strconv.Quote(before))
+		}
+		parts = append(parts, "url.PathEscape("+argName(param.Name)+")")
+		rest = after
+	}
+	if rest != "" {
+		parts = append(parts, strconv.Quote(rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// argName is the Go parameter name of a path parameter
+func argName(name string) string {
+	// The first word is lower-cased whole so initialisms stay readable (id -> id, not iD)
+	words := splitWords(name)
+	arg := strings.ToLower(words[0]) + GoName(strings.Join(words[1:], "_"))
+	if goKeywords[arg] || arg == "ctx" || arg == "params" || arg == "body" {
+		arg += "Param"
+	}
+	return arg
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
@@ -0,0 +1,105 @@
+package openapi
+
+// Version is the OpenAPI specification version of generated documents
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document. Maps marshal with sorted keys, so a document
+// built from the same routes always serializes to the same bytes.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations of one path, keyed by lowercase HTTP method
+type PathItem map[string]*Operation
+
+// Operation is one endpoint
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	// Permission is the ABAC action the caller must be permitted, enforced by ABACMiddleware
+	Permission string `json:"x-abac-permission,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // "path", "query" or "header"
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response status of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components holds the named schemas referenced with $ref and the security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how callers authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"` // "apiKey" or "http"
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"` // Header name of apiKey schemes
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"` // "bearer" for http schemes
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema object produced from Go types.
+// An empty schema accepts any value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	// Nullable marks optional scalars (Go pointers to booleans and numbers) whose zero value is meaningful
+	Nullable bool `json:"nullable,omitempty"`
+}
+
+// RefName returns the component name a $ref schema points to, or "" for inline schemas
+func (s *Schema) RefName() string {
+	const prefix = "#/components/schemas/"
+	if s == nil || len(s.Ref) <= len(prefix) || s.Ref[:len(prefix)] != prefix {
+		return ""
+	}
+	return s.Ref[len(prefix):]
+}
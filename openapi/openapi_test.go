@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testTag struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type testItem struct {
+	ID        string            `json:"id" binding:"required"`
+	Count     int64             `json:"count"`
+	Limit     *int              `json:"limit,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Tags      []testTag         `json:"tags"`
+	Parent    *testItem         `json:"parent,omitempty"`
+	Extra     interface{}       `json:"extra"`
+	secret    string
+	Ignored   string `json:"-"`
+}
+
+type testPage struct {
+	Items []testItem `json:"items"`
+	testPaging
+}
+
+type testPaging struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	generator := NewGenerator()
+	ref := generator.SchemaFor(testPage{})
+	if ref.RefName() != "testPage" {
+		t.Fatalf("expected $ref to testPage, got %+v", ref)
+	}
+
+	schemas := generator.Schemas()
+	page := schemas["testPage"]
+	if page.Properties["next_cursor"] == nil {
+		t.Error("expected embedded struct fields to be flattened")
+	}
+
+	item := schemas["testItem"]
+	if item == nil {
+		t.Fatal("expected testItem component")
+	}
+	if len(item.Required) != 1 || item.Required[0] != "id" {
+		t.Errorf("expected id to be required, got %v", item.Required)
+	}
+	if item.Properties["parent"].RefName() != "testItem" {
+		t.Errorf("expected recursive $ref, got %+v", item.Properties["parent"])
+	}
+	if created := item.Properties["created_at"]; created.Type != "string" || created.Format != "date-time" {
+		t.Errorf("expected date-time string, got %+v", created)
+	}
+	if count := item.Properties["count"]; count.Type != "integer" || count.Format != "int64" {
+		t.Errorf("expected int64 integer, got %+v", count)
+	}
+	if limit := item.Properties["limit"]; limit.Type != "integer" || !limit.Nullable || item.Properties["count"].Nullable {
+		t.Errorf("expected only the pointer integer to be nullable, got %+v", limit)
+	}
+	if labels := item.Properties["labels"]; labels.AdditionalProperties == nil || labels.AdditionalProperties.Type != "string" {
+		t.Errorf("expected string map, got %+v", labels)
+	}
+	if tags := item.Properties["tags"]; tags.Type != "array" || tags.Items.RefName() != "testTag" {
+		t.Errorf("expected array of testTag, got %+v", tags)
+	}
+	for _, name := range []string{"secret", "Ignored"} {
+		if _, ok := item.Properties[name]; ok {
+			t.Errorf("expected %s to be skipped", name)
+		}
+	}
+}
+
+func TestSchemaForOverride(t *testing.T) {
+	generator := NewGenerator()
+	generator.Override(testTag{}, &Schema{Type: "string"})
+	if schema := generator.SchemaFor([]testTag{}); schema.Items.Type != "string" {
+		t.Errorf("expected override to apply, got %+v", schema.Items)
+	}
+	if _, ok := generator.Schemas()["testTag"]; ok {
+		t.Error("overridden types should not become components")
+	}
+}
+
+func testRoutes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Path: "/health", OperationID: "health"},
+		{Method: http.MethodGet, Path: "/items", OperationID: "listItems", Permission: "admin", Response: testPage{},
+			Query: []Parameter{QueryParam("limit", "integer", "Page size"), QueryParam("enabled", "boolean", "")}},
+		{Method: http.MethodPut, Path: "/items/:id", OperationID: "updateItem", Permission: "admin", Request: testItem{}, Response: testItem{},
+			Query: []Parameter{HeaderParam("If-Match", "Revision")}},
+		{Method: http.MethodDelete, Path: "/items/:id", OperationID: "deleteItem", Permission: "admin", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/import", OperationID: "importItems", RequestType: "application/x-ndjson", Response: testPage{}},
+		{Method: http.MethodGet, Path: "/events", OperationID: "streamEvents", ResponseType: "text/event-stream"},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, testRoutes(), nil)
+
+	if doc.OpenAPI != Version {
+		t.Errorf("expected openapi %s, got %s", Version, doc.OpenAPI)
+	}
+	update := doc.Paths["/items/{id}"]["put"]
+	if update == nil {
+		t.Fatalf("expected gin path to be converted, got paths %v", doc.Paths)
+	}
+	if len(update.Parameters) != 2 || update.Parameters[0].In != "path" || update.Parameters[1].In != "header" {
+		t.Errorf("expected path and header parameters, got %+v", update.Parameters)
+	}
+	if update.RequestBody == nil || update.RequestBody.Content[ContentTypeJSON].Schema.RefName() != "testItem" {
+		t.Errorf("expected JSON request body, got %+v", update.RequestBody)
+	}
+	for _, status := range []string{"200", "400", "401", "403"} {
+		if update.Responses[status] == nil {
+			t.Errorf("expected %s response on a protected operation with a body", status)
+		}
+	}
+	if update.Permission != "admin" || len(update.Security) != 2 {
+		t.Errorf("expected permission and security, got %q %v", update.Permission, update.Security)
+	}
+
+	if deleted := doc.Paths["/items/{id}"]["delete"].Responses["204"]; deleted == nil || deleted.Content != nil {
+		t.Errorf("expected a 204 response without content, got %+v", deleted)
+	}
+	health := doc.Paths["/health"]["get"]
+	if health.Security != nil || health.Responses["401"] != nil {
+		t.Error("public operations should not require authentication")
+	}
+	if schema := health.Responses["200"].Content[ContentTypeJSON].Schema; schema.Type != "object" {
+		t.Errorf("expected free-form object response, got %+v", schema)
+	}
+	if body := doc.Paths["/import"]["post"].RequestBody; body.Content["application/x-ndjson"].Schema.Format != "binary" {
+		t.Errorf("expected binary NDJSON body, got %+v", body)
+	}
+	if doc.Components.Schemas["ErrorResponse"] == nil {
+		t.Error("expected ErrorResponse component")
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("document does not marshal: %v", err)
+	}
+
+	refs := doc.Operations()
+	if len(refs) != len(testRoutes()) || refs[0].Path != "/events" || refs[1].Path != "/health" {
+		t.Errorf("expected operations sorted by path, got %+v", refs)
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, testRoutes(), nil)
+	source, err := GenerateClient(doc, "testclient")
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	// Compare with whitespace collapsed, gofmt aligns struct fields
+	code := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"package testclient",
+		"type TestItem struct",
+		"ID string `json:\"id\"`",
+		"CreatedAt *time.Time `json:\"created_at,omitempty\"`",
+		"Limit *int `json:\"limit,omitempty\"`",
+		"Count int64 `json:\"count\"`",
+		"func (c *Client) ListItems(ctx context.Context, params *ListItemsParams) (*TestPage, error)",
+		"func (c *Client) UpdateItem(ctx context.Context, id string, params *UpdateItemParams, body *TestItem) (*TestItem, error)",
+		"func (p *UpdateItemParams) header() http.Header",
+		"Enabled *bool",
+		"func (c *Client) DeleteItem(ctx context.Context, id string) error",
+		"func (c *Client) ImportItems(ctx context.Context, body io.Reader) (*TestPage, error)",
+		"func (c *Client) StreamEvents(ctx context.Context) (io.ReadCloser, error)",
+		`"/items/"+url.PathEscape(id)`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated client is missing %q", want)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"request_id":    "RequestID",
+		"next_cursor":   "NextCursor",
+		"If-Match":      "IfMatch",
+		"listPolicies":  "ListPolicies",
+		"client_cert":   "ClientCert",
+		"updated_since": "UpdatedSince",
+	}
+	for input, want := range tests {
+		if got := GoName(input); got != want {
+			t.Errorf("GoName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
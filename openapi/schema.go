@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	rawJSONType   = reflect.TypeOf(json.RawMessage(nil))
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Generator derives schemas from Go types using their json tags. Named struct types become
+// components referenced with $ref; fields tagged `binding:"required"` are required.
+// Types with a custom MarshalJSON are described as any value unless overridden.
+type Generator struct {
+	schemas   map[string]*Schema
+	names     map[reflect.Type]string
+	overrides map[reflect.Type]*Schema
+}
+
+// NewGenerator creates a generator with no components
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas:   make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+		overrides: make(map[reflect.Type]*Schema),
+	}
+}
+
+// Override describes values of the type of sample with schema instead of reflecting it,
+// e.g. for types with a custom JSON encoding
+func (g *Generator) Override(sample interface{}, schema *Schema) {
+	g.overrides[reflect.TypeOf(sample)] = schema
+}
+
+// SchemaFor returns the schema of the type of sample; nil describes no body
+func (g *Generator) SchemaFor(sample interface{}) *Schema {
+	if sample == nil {
+		return nil
+	}
+	return g.schemaOf(reflect.TypeOf(sample))
+}
+
+// Schemas returns the components collected so far
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	if schema, ok := g.overrides[t]; ok {
+		return schema
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}
+	case rawJSONType:
+		return &Schema{}
+	}
+	if t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(marshalerType) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := g.schemaOf(t.Elem())
+		if !omitEmpty(schema) {
+			nullable := *schema
+			nullable.Nullable = true
+			return &nullable
+		}
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.componentRef(t)
+	default:
+		// Interfaces (and kinds without a JSON form) accept any value
+		return &Schema{}
+	}
+}
+
+// componentRef registers a named struct as a component on first use and returns a $ref to it
+func (g *Generator) componentRef(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		// Registered before the fields are reflected so recursive types terminate
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names a component after its Go type, prefixed with the package on collisions
+func (g *Generator) componentName(t reflect.Type) string {
+	name := sanitizeName(t.Name())
+	if _, taken := g.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	base := sanitizeName(GoName(pkg) + t.Name())
+	name = base
+	for i := 2; ; i++ {
+		if _, taken := g.schemas[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+// structSchema describes the JSON object encoding a struct, flattening embedded structs
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+	return schema
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schemaOf(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
+
+// sanitizeName keeps the letters and digits of a Go type name (generic instantiations include brackets)
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+}
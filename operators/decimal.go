@@ -0,0 +1,152 @@
+package operators
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxSafeInteger is the largest integer magnitude float64 represents exactly (2^53)
+const maxSafeInteger = 1 << 53
+
+// maxDecimalExponent bounds the exponent of decimal strings compared exactly; larger
+// exponents fall back to float64 instead of building huge rationals
+const maxDecimalExponent = 400
+
+// decimalPattern matches plain decimal numbers with an optional exponent, e.g. "1999.99" or "-1.5e3"
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// Numeric is a number compared without losing precision. Values float64 holds exactly enough
+// (numbers and integers up to 2^53) are compared as float64; decimal strings such as "1000000.01"
+// and integers beyond 2^53 are compared as exact rationals.
+type Numeric struct {
+	f     float64
+	exact *big.Rat // Set when the value is compared exactly
+}
+
+// ParseNumeric converts numeric kinds, json.Number and numeric strings; false for anything else
+func ParseNumeric(value interface{}) (Numeric, bool) {
+	switch v := value.(type) {
+	case float64:
+		return Numeric{f: v}, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case float32:
+		// Shortest float32 form, so float32(0.1) compares equal to "0.1"
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+		return Numeric{f: f}, !math.IsNaN(f) && !math.IsInf(f, 0)
+	case int:
+		return fromInt64(int64(v)), true
+	case int8:
+		return fromInt64(int64(v)), true
+	case int16:
+		return fromInt64(int64(v)), true
+	case int32:
+		return fromInt64(int64(v)), true
+	case int64:
+		return fromInt64(v), true
+	case uint:
+		return fromUint64(uint64(v)), true
+	case uint8:
+		return fromUint64(uint64(v)), true
+	case uint16:
+		return fromUint64(uint64(v)), true
+	case uint32:
+		return fromUint64(uint64(v)), true
+	case uint64:
+		return fromUint64(v), true
+	case json.Number:
+		return parseNumericString(string(v))
+	case string:
+		return parseNumericString(v)
+	}
+	return Numeric{}, false
+}
+
+// ToNumeric converts value like ParseNumeric, returning zero for non-numeric values
+func ToNumeric(value interface{}) Numeric {
+	n, _ := ParseNumeric(value)
+	return n
+}
+
+// Cmp compares n and other: -1 when n < other, 0 when equal, +1 when n > other
+func (n Numeric) Cmp(other Numeric) int {
+	if n.exact == nil && other.exact == nil {
+		switch {
+		case n.f < other.f:
+			return -1
+		case n.f > other.f:
+			return 1
+		}
+		return 0
+	}
+	return n.rat().Cmp(other.rat())
+}
+
+// Float64 returns the nearest float64
+func (n Numeric) Float64() float64 {
+	if n.exact != nil {
+		f, _ := n.exact.Float64()
+		return f
+	}
+	return n.f
+}
+
+// rat returns the exact value; a float64 is taken at its shortest decimal form, so 0.1 equals "0.1"
+func (n Numeric) rat() *big.Rat {
+	if n.exact != nil {
+		return n.exact
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(n.f, 'g', -1, 64))
+	return r
+}
+
+func fromInt64(v int64) Numeric {
+	if v > maxSafeInteger || v < -maxSafeInteger {
+		return Numeric{f: float64(v), exact: new(big.Rat).SetInt64(v)}
+	}
+	return Numeric{f: float64(v)}
+}
+
+func fromUint64(v uint64) Numeric {
+	if v > maxSafeInteger {
+		return Numeric{f: float64(v), exact: new(big.Rat).SetUint64(v)}
+	}
+	return Numeric{f: float64(v)}
+}
+
+// parseNumericString parses integers exactly and decimal strings as exact rationals
+func parseNumericString(s string) (Numeric, bool) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return fromInt64(i), true
+	}
+	if decimalPattern.MatchString(s) && decimalExponent(s) <= maxDecimalExponent {
+		if r, ok := new(big.Rat).SetString(s); ok {
+			f, _ := r.Float64()
+			return Numeric{f: f, exact: r}, true
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return Numeric{}, false
+	}
+	return Numeric{f: f}, true
+}
+
+// decimalExponent returns the magnitude of the exponent of a decimal string (0 without one)
+func decimalExponent(s string) int {
+	index := strings.IndexAny(s, "eE")
+	if index < 0 {
+		return 0
+	}
+	exponent, err := strconv.Atoi(s[index+1:])
+	if err != nil {
+		return math.MaxInt
+	}
+	if exponent < 0 {
+		return -exponent
+	}
+	return exponent
+}
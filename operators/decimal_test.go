@@ -0,0 +1,69 @@
+package operators
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseNumeric(t *testing.T) {
+	valid := []interface{}{1, int8(1), int64(9007199254740993), uint64(18446744073709551615), 1.5, float32(0.1), "42", " 1999.99 ", "-0.5", ".5", "1e3", json.Number("12.30")}
+	for _, value := range valid {
+		if _, ok := ParseNumeric(value); !ok {
+			t.Errorf("Expected %#v to be numeric", value)
+		}
+	}
+	invalid := []interface{}{nil, true, "", "abc", "1/3", "NaN", "Inf", []int{1}}
+	for _, value := range invalid {
+		if _, ok := ParseNumeric(value); ok {
+			t.Errorf("Expected %#v not to be numeric", value)
+		}
+	}
+}
+
+func TestNumericCmp(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right interface{}
+		expected    int
+	}{
+		// Monetary thresholds: float64 cannot tell these apart
+		{"cents above a large limit", "1000000000000000.01", "1000000000000000.00", 1},
+		{"cents below a large limit", "9999999999999999.99", "10000000000000000", -1},
+		{"decimal string equals float", "0.1", 0.1, 0},
+		{"decimal vs float64 sum", "0.3", 0.30000000000000004, -1}, // float64(0.1) + float64(0.2)
+		{"trailing zeros", "12.30", json.Number("12.3"), 0},
+		{"float32 shortest form", float32(0.1), "0.1", 0},
+
+		// Integers beyond 2^53
+		{"int64 beyond float53", int64(9007199254740993), int64(9007199254740992), 1},
+		{"int64 string beyond float53", "9007199254740993", 9007199254740992.0, 1},
+		{"uint64 max", uint64(18446744073709551615), "18446744073709551614", 1},
+		{"large negative", int64(-9007199254740993), "-9007199254740992", -1},
+
+		// Fast path
+		{"small ints", 5, 3, 1},
+		{"int and float", 2, 2.0, 0},
+		{"numeric string and int", "10", 9, 1},
+
+		// Non-numeric values count as zero
+		{"invalid counts as zero", "abc", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ToNumeric(tt.left).Cmp(ToNumeric(tt.right)); result != tt.expected {
+				t.Errorf("Cmp(%#v, %#v) = %d, expected %d", tt.left, tt.right, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNumericHugeExponent(t *testing.T) {
+	// Exponents beyond maxDecimalExponent are not expanded into huge rationals
+	n, ok := ParseNumeric("1e1000000000")
+	if ok {
+		t.Errorf("Expected an out-of-range exponent to be rejected, got %+v", n)
+	}
+	if n, ok := ParseNumeric("1e-1000000000"); !ok || n.exact != nil || n.Float64() != 0 {
+		t.Errorf("Expected a tiny value to fall back to float64, got %+v %v", n, ok)
+	}
+}
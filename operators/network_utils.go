@@ -2,7 +2,6 @@ package operators
 
 import (
 	"net"
-	"regexp"
 
 	"abac_go_example/constants"
 )
@@ -52,26 +51,14 @@ func (nu *NetworkUtils) GetIPClass(ipStr string) string {
 	return "ipv6"
 }
 
-// IsMobileUserAgent detects if user agent is from mobile device
+// IsMobileUserAgent detects if user agent is from a phone or tablet
 func (nu *NetworkUtils) IsMobileUserAgent(userAgent string) bool {
-	for _, pattern := range constants.MobileUserAgentPatterns {
-		matched, _ := regexp.MatchString(pattern, userAgent)
-		if matched {
-			return true
-		}
-	}
-	return false
+	return ParseUserAgent(userAgent).IsMobile()
 }
 
 // GetBrowserFromUserAgent extracts browser name from user agent
 func (nu *NetworkUtils) GetBrowserFromUserAgent(userAgent string) string {
-	for pattern, browser := range constants.BrowserPatterns {
-		matched, _ := regexp.MatchString(pattern, userAgent)
-		if matched {
-			return browser
-		}
-	}
-	return "unknown"
+	return ParseUserAgent(userAgent).Browser
 }
 
 // IsBusinessHours checks if the given hour and weekday are within business hours
@@ -237,11 +237,8 @@ func (o *BetweenOperator) Evaluate(actual, expected interface{}) bool {
 	}
 
 	// For numeric comparisons
-	actualNum := toFloat64(actual)
-	lowerNum := toFloat64(expectedSlice[0])
-	upperNum := toFloat64(expectedSlice[1])
-
-	return actualNum >= lowerNum && actualNum <= upperNum
+	actualNum := ToNumeric(actual)
+	return actualNum.Cmp(ToNumeric(expectedSlice[0])) >= 0 && actualNum.Cmp(ToNumeric(expectedSlice[1])) <= 0
 }
 
 // ExistsOperator checks if a value exists (is not nil)
@@ -285,40 +282,14 @@ func toString(value interface{}) string {
 	}
 }
 
+// toFloat64 converts numeric values to the nearest float64; non-numeric values are 0
 func toFloat64(value interface{}) float64 {
-	if value == nil {
-		return 0
-	}
-
-	switch v := value.(type) {
-	case int:
-		return float64(v)
-	case int32:
-		return float64(v)
-	case int64:
-		return float64(v)
-	case float32:
-		return float64(v)
-	case float64:
-		return v
-	case string:
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
-			return f
-		}
-	}
-	return 0
+	return ToNumeric(value).Float64()
 }
 
+// compareNumbers compares numbers without losing precision; non-numeric values count as zero
 func compareNumbers(actual, expected interface{}) int {
-	actualNum := toFloat64(actual)
-	expectedNum := toFloat64(expected)
-
-	if actualNum > expectedNum {
-		return 1
-	} else if actualNum < expectedNum {
-		return -1
-	}
-	return 0
+	return ToNumeric(actual).Cmp(ToNumeric(expected))
 }
 
 func isTimeBetween(timeStr, startStr, endStr string) bool {
@@ -348,5 +319,9 @@ func isTimeBetween(timeStr, startStr, endStr string) bool {
 	startMinutes := startHour*60 + startMin
 	endMinutes := endHour*60 + endMin
 
+	// A window whose start is after its end wraps past midnight ("22:00"-"06:00")
+	if startMinutes > endMinutes {
+		return timeMinutes >= startMinutes || timeMinutes <= endMinutes
+	}
 	return timeMinutes >= startMinutes && timeMinutes <= endMinutes
 }
@@ -281,6 +281,9 @@ func TestTimeBetween(t *testing.T) {
 		{"invalid", "08:00", "18:00", false}, // Invalid time format
 		{"10:30", "invalid", "18:00", false}, // Invalid start format
 		{"10:30", "08:00", "invalid", false}, // Invalid end format
+		{"23:15", "22:00", "06:00", true},    // Overnight window before midnight
+		{"05:59", "22:00", "06:00", true},    // Overnight window after midnight
+		{"12:00", "22:00", "06:00", false},   // Outside overnight window
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,47 @@
+package operators
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// accentReplacer maps letters whose diacritics are not combining marks, so they survive NFD
+var accentReplacer = strings.NewReplacer("đ", "d", "Đ", "D", "ø", "o", "Ø", "O", "ł", "l", "Ł", "L")
+
+// NormalizeString returns s in Unicode NFC, so precomposed "Kỹ thuật" and its decomposed form
+// (e.g. from macOS file names or some IMEs) compare equal. ASCII strings are returned as is.
+func NormalizeString(s string) string {
+	if isASCII(s) || norm.NFC.IsNormalString(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// FoldAccents removes diacritics for accent-insensitive comparison: "Kỹ thuật" becomes "Ky thuat"
+// and "Đà Nẵng" becomes "Da Nang". Case is preserved.
+func FoldAccents(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	decomposed := norm.NFD.String(accentReplacer.Replace(s))
+	var b strings.Builder
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
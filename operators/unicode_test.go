@@ -0,0 +1,52 @@
+package operators
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestNormalizeString(t *testing.T) {
+	precomposed := "Kỹ thuật"
+	decomposed := norm.NFD.String(precomposed)
+	if decomposed == precomposed {
+		t.Fatal("Expected the NFD form to differ")
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{precomposed, precomposed},
+		{decomposed, precomposed},
+		{norm.NFD.String("Nguyễn Văn Đức"), "Nguyễn Văn Đức"},
+		{"Engineering", "Engineering"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if result := NormalizeString(tt.input); result != tt.expected {
+			t.Errorf("NormalizeString(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFoldAccents(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Kỹ thuật", "Ky thuat"},
+		{norm.NFD.String("Kỹ thuật"), "Ky thuat"},
+		{"Đà Nẵng", "Da Nang"},
+		{"Phòng Kế toán", "Phong Ke toan"},
+		{"Hồ Chí Minh", "Ho Chi Minh"},
+		{"Crème brûlée", "Creme brulee"},
+		{"Ky thuat", "Ky thuat"},
+		{"日本語", "日本語"},
+	}
+	for _, tt := range tests {
+		if result := FoldAccents(tt.input); result != tt.expected {
+			t.Errorf("FoldAccents(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package operators
+
+import (
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+// UserAgent holds the attributes parsed from a User-Agent header.
+// Unrecognized parts are constants.UserAgentUnknown; versions are empty when absent.
+type UserAgent struct {
+	DeviceType     string // constants.DeviceType*
+	OS             string // constants.OS*
+	OSVersion      string // e.g. "17.4" (iOS, Android, Windows NT, macOS)
+	Browser        string // constants.Browser*
+	BrowserVersion string // e.g. "124.0.6367.82"
+}
+
+// IsMobile reports whether the device is a phone or a tablet
+func (ua UserAgent) IsMobile() bool {
+	return ua.DeviceType == constants.DeviceTypeMobile || ua.DeviceType == constants.DeviceTypeTablet
+}
+
+// browserRule maps a product token to a browser. Rules are checked in order because most
+// browsers also advertise the engines they are built on (Edge sends "Chrome/" and "Safari/").
+type browserRule struct {
+	token   string // product token prefix, matched case-insensitively, e.g. "edg/"
+	browser string
+}
+
+var browserRules = []browserRule{
+	{"edg/", constants.BrowserEdge},
+	{"edga/", constants.BrowserEdge},
+	{"edgios/", constants.BrowserEdge},
+	{"edge/", constants.BrowserEdge},
+	{"opr/", constants.BrowserOpera},
+	{"opios/", constants.BrowserOpera},
+	{"samsungbrowser/", constants.BrowserSamsung},
+	{"firefox/", constants.BrowserFirefox},
+	{"fxios/", constants.BrowserFirefox},
+	{"crios/", constants.BrowserChrome},
+	{"chrome/", constants.BrowserChrome},
+	{"chromium/", constants.BrowserChrome},
+}
+
+// botMarkers identify crawlers and automated clients
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests/"}
+
+// ParseUserAgent parses a User-Agent header into device, OS and browser attributes.
+// Parsing is deterministic and never fails; unrecognized values are reported as unknown.
+func ParseUserAgent(userAgent string) UserAgent {
+	ua := UserAgent{
+		DeviceType: constants.UserAgentUnknown,
+		OS:         constants.UserAgentUnknown,
+		Browser:    constants.UserAgentUnknown,
+	}
+	lower := strings.ToLower(userAgent)
+	if strings.TrimSpace(lower) == "" {
+		return ua
+	}
+
+	parseOS(lower, &ua)
+	parseBrowser(lower, &ua)
+
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			ua.DeviceType = constants.DeviceTypeBot
+			break
+		}
+	}
+	return ua
+}
+
+// parseOS detects the operating system and the device type it implies
+func parseOS(lower string, ua *UserAgent) {
+	switch {
+	case strings.Contains(lower, "windows phone"):
+		ua.OS, ua.DeviceType = constants.OSWindowsPhone, constants.DeviceTypeMobile
+		ua.OSVersion = versionAfter(lower, "windows phone ")
+	case strings.Contains(lower, "iphone") || strings.Contains(lower, "ipod"):
+		ua.OS, ua.DeviceType = constants.OSIOS, constants.DeviceTypeMobile
+		ua.OSVersion = iosVersion(lower)
+	case strings.Contains(lower, "ipad"):
+		ua.OS, ua.DeviceType = constants.OSIOS, constants.DeviceTypeTablet
+		ua.OSVersion = iosVersion(lower)
+	case strings.Contains(lower, "android"):
+		ua.OS = constants.OSAndroid
+		ua.OSVersion = versionAfter(lower, "android ")
+		// Android tablets omit the "Mobile" token
+		if strings.Contains(lower, "mobile") {
+			ua.DeviceType = constants.DeviceTypeMobile
+		} else {
+			ua.DeviceType = constants.DeviceTypeTablet
+		}
+	case strings.Contains(lower, "blackberry") || strings.Contains(lower, "bb10"):
+		ua.OS, ua.DeviceType = constants.OSBlackBerry, constants.DeviceTypeMobile
+	case strings.Contains(lower, "windows nt"):
+		ua.OS, ua.DeviceType = constants.OSWindows, constants.DeviceTypeDesktop
+		ua.OSVersion = versionAfter(lower, "windows nt ")
+	case strings.Contains(lower, "cros "):
+		ua.OS, ua.DeviceType = constants.OSChromeOS, constants.DeviceTypeDesktop
+	case strings.Contains(lower, "mac os x") || strings.Contains(lower, "macintosh"):
+		ua.OS, ua.DeviceType = constants.OSMacOS, constants.DeviceTypeDesktop
+		ua.OSVersion = strings.ReplaceAll(versionAfter(lower, "mac os x "), "_", ".")
+	case strings.Contains(lower, "linux"):
+		ua.OS, ua.DeviceType = constants.OSLinux, constants.DeviceTypeDesktop
+	}
+
+	// Generic mobile marker for devices without a recognized OS
+	if ua.DeviceType == constants.UserAgentUnknown && strings.Contains(lower, "mobile") {
+		ua.DeviceType = constants.DeviceTypeMobile
+	}
+}
+
+// parseBrowser detects the browser and its version
+func parseBrowser(lower string, ua *UserAgent) {
+	for _, rule := range browserRules {
+		if version, ok := productVersion(lower, rule.token); ok {
+			ua.Browser, ua.BrowserVersion = rule.browser, version
+			return
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "safari/") && strings.Contains(lower, "version/"):
+		// Safari reports its own version in "Version/"; "Safari/" carries the WebKit build
+		ua.Browser = constants.BrowserSafari
+		ua.BrowserVersion, _ = productVersion(lower, "version/")
+	case strings.Contains(lower, "msie "):
+		ua.Browser = constants.BrowserIE
+		ua.BrowserVersion = versionAfter(lower, "msie ")
+	case strings.Contains(lower, "trident/"):
+		ua.Browser = constants.BrowserIE
+		ua.BrowserVersion = versionAfter(lower, "rv:")
+	case strings.HasPrefix(lower, "opera/"):
+		ua.Browser = constants.BrowserOpera
+		ua.BrowserVersion, _ = productVersion(lower, "version/")
+	}
+}
+
+// productVersion returns the version following a product token ("chrome/" in "Chrome/124.0") at a word boundary
+func productVersion(lower, token string) (string, bool) {
+	for offset := 0; ; {
+		index := strings.Index(lower[offset:], token)
+		if index < 0 {
+			return "", false
+		}
+		index += offset
+		if index == 0 || !isTokenChar(lower[index-1]) {
+			return readVersion(lower[index+len(token):]), true
+		}
+		offset = index + len(token)
+	}
+}
+
+// versionAfter returns the version immediately following marker, or "" when marker is absent
+func versionAfter(lower, marker string) string {
+	index := strings.Index(lower, marker)
+	if index < 0 {
+		return ""
+	}
+	return readVersion(lower[index+len(marker):])
+}
+
+// iosVersion reads "OS 17_4_1" from iPhone/iPad user agents
+func iosVersion(lower string) string {
+	for _, marker := range []string{"iphone os ", "cpu os "} {
+		if version := versionAfter(lower, marker); version != "" {
+			return strings.ReplaceAll(version, "_", ".")
+		}
+	}
+	return ""
+}
+
+// readVersion reads a leading version made of digits, dots and underscores
+func readVersion(s string) string {
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || s[end] == '_') {
+		end++
+	}
+	return strings.TrimRight(s[:end], "._")
+}
+
+// isTokenChar reports whether c can be part of a product name
+func isTokenChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// CompareVersions compares dotted versions component by component ("120.0.1" > "99.9").
+// Missing components count as zero, so "17" equals "17.0". It returns false when either
+// version is empty or has a non-numeric component.
+func CompareVersions(a, b string) (int, bool) {
+	left, ok := versionComponents(a)
+	if !ok {
+		return 0, false
+	}
+	right, ok := versionComponents(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		if l != r {
+			if l < r {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// versionComponents splits a dotted (or underscored) numeric version
+func versionComponents(version string) ([]int, bool) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil, false
+	}
+	parts := strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' })
+	components := make([]int, len(parts))
+	for i, part := range parts {
+		n := 0
+		for _, c := range part {
+			if c < '0' || c > '9' || n > 1e8 {
+				return nil, false
+			}
+			n = n*10 + int(c-'0')
+		}
+		components[i] = n
+	}
+	return components, len(components) > 0
+}
@@ -0,0 +1,142 @@
+package operators
+
+import (
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  UserAgent
+	}{
+		{
+			name:      "iPhone Safari",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1",
+			expected:  UserAgent{constants.DeviceTypeMobile, constants.OSIOS, "17.4.1", constants.BrowserSafari, "17.4.1"},
+		},
+		{
+			name:      "iPhone Chrome",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1",
+			expected:  UserAgent{constants.DeviceTypeMobile, constants.OSIOS, "17.4", constants.BrowserChrome, "124.0.6367.88"},
+		},
+		{
+			name:      "iPad Safari",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			expected:  UserAgent{constants.DeviceTypeTablet, constants.OSIOS, "16.6", constants.BrowserSafari, "16.6"},
+		},
+		{
+			name:      "Android Chrome phone",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.82 Mobile Safari/537.36",
+			expected:  UserAgent{constants.DeviceTypeMobile, constants.OSAndroid, "14", constants.BrowserChrome, "124.0.6367.82"},
+		},
+		{
+			name:      "Android Samsung tablet",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Safari/537.36",
+			expected:  UserAgent{constants.DeviceTypeTablet, constants.OSAndroid, "13", constants.BrowserSamsung, "24.0"},
+		},
+		{
+			name:      "Windows Edge",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSWindows, "10.0", constants.BrowserEdge, "124.0.2478.80"},
+		},
+		{
+			name:      "Windows Chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSWindows, "10.0", constants.BrowserChrome, "124.0.0.0"},
+		},
+		{
+			name:      "macOS Safari",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSMacOS, "10.15.7", constants.BrowserSafari, "17.4"},
+		},
+		{
+			name:      "Linux Firefox",
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSLinux, "", constants.BrowserFirefox, "125.0"},
+		},
+		{
+			name:      "Opera",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36 OPR/109.0.0.0",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSWindows, "10.0", constants.BrowserOpera, "109.0.0.0"},
+		},
+		{
+			name:      "ChromeOS",
+			userAgent: "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSChromeOS, "", constants.BrowserChrome, "124.0.0.0"},
+		},
+		{
+			name:      "Internet Explorer 11",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko",
+			expected:  UserAgent{constants.DeviceTypeDesktop, constants.OSWindows, "6.1", constants.BrowserIE, "11.0"},
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected:  UserAgent{constants.DeviceTypeBot, constants.UserAgentUnknown, "", constants.UserAgentUnknown, ""},
+		},
+		{
+			name:      "curl",
+			userAgent: "curl/8.5.0",
+			expected:  UserAgent{constants.DeviceTypeBot, constants.UserAgentUnknown, "", constants.UserAgentUnknown, ""},
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			expected:  UserAgent{constants.UserAgentUnknown, constants.UserAgentUnknown, "", constants.UserAgentUnknown, ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseUserAgent(test.userAgent); got != test.expected {
+				t.Errorf("ParseUserAgent() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestNetworkUtils_UserAgentHelpers(t *testing.T) {
+	nu := NewNetworkUtils()
+	iphone := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1"
+	if !nu.IsMobileUserAgent(iphone) || nu.GetBrowserFromUserAgent(iphone) != constants.BrowserSafari {
+		t.Errorf("Expected iPhone Safari to be a mobile safari user agent")
+	}
+
+	// Chrome also advertises Safari; the result must not depend on map iteration order
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	for i := 0; i < 20; i++ {
+		if browser := nu.GetBrowserFromUserAgent(chrome); browser != constants.BrowserChrome {
+			t.Fatalf("Expected chrome, got %s", browser)
+		}
+	}
+	if nu.IsMobileUserAgent(chrome) {
+		t.Error("Expected desktop Chrome not to be mobile")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+		valid    bool
+	}{
+		{"124.0.6367.82", "120", 1, true},
+		{"99.9", "120.0.1", -1, true},
+		{"17", "17.0.0", 0, true},
+		{"17_4_1", "17.4.1", 0, true},
+		{"10.15.7", "10.15.10", -1, true},
+		{"", "1.0", 0, false},
+		{"1.0-beta", "1.0", 0, false},
+		{"unknown", "1", 0, false},
+	}
+
+	for _, test := range tests {
+		got, ok := CompareVersions(test.a, test.b)
+		if got != test.expected || ok != test.valid {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v; want %d, %v", test.a, test.b, got, ok, test.expected, test.valid)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package pep
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// ClientCertFromTLS extracts the client certificate of a mutual TLS connection for
+// EnvironmentInfo.ClientCert. It returns nil when the connection is not TLS or the client
+// presented no certificate. The certificate is marked verified, with its issuer chain, only
+// when the handshake verified it against the server's client CAs.
+func ClientCertFromTLS(state *tls.ConnectionState) *models.ClientCertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	if len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		chain := state.VerifiedChains[0]
+		return ClientCertFromCertificate(chain[0], chain[1:])
+	}
+
+	info := ClientCertFromCertificate(state.PeerCertificates[0], nil)
+	info.Verified = false
+	return info
+}
+
+// ClientCertFromCertificate describes cert as verified by issuers (immediate issuer first).
+// Callers must only pass certificates whose chain has already been verified.
+func ClientCertFromCertificate(cert *x509.Certificate, issuers []*x509.Certificate) *models.ClientCertInfo {
+	info := &models.ClientCertInfo{
+		SubjectDN:      cert.Subject.String(),
+		CommonName:     cert.Subject.CommonName,
+		IssuerDN:       cert.Issuer.String(),
+		Fingerprint:    CertFingerprint(cert),
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		NotBefore:      cert.NotBefore,
+		NotAfter:       cert.NotAfter,
+		Verified:       true,
+	}
+	if cert.SerialNumber != nil {
+		info.SerialNumber = cert.SerialNumber.String()
+	}
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, issuer := range issuers {
+		info.IssuerChain = append(info.IssuerChain, models.CertIssuer{
+			SubjectDN:   issuer.Subject.String(),
+			Fingerprint: CertFingerprint(issuer),
+		})
+	}
+	return info
+}
+
+// CertFingerprint returns the SHA-256 fingerprint of a certificate as lowercase hex
+func CertFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// TLSConfigFromEnv builds the server TLS configuration from ABAC_TLS_CERT/ABAC_TLS_KEY.
+// ABAC_TLS_CLIENT_CA enables mutual TLS: client certificates are verified against those CAs
+// and required when ABAC_TLS_CLIENT_AUTH=require. It returns nil when ABAC_TLS_CERT is unset.
+func TLSConfigFromEnv() (*tls.Config, error) {
+	certFile := os.Getenv(constants.EnvTLSCert)
+	if certFile == "" {
+		return nil, nil
+	}
+
+	serverCert, err := tls.LoadX509KeyPair(certFile, os.Getenv(constants.EnvTLSKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	caFile := os.Getenv(constants.EnvTLSClientCA)
+	if caFile == "" {
+		return config, nil
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA certificates: %w", err)
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+
+	switch mode := strings.ToLower(os.Getenv(constants.EnvTLSClientAuth)); mode {
+	case "", "optional":
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid %s %q, expected optional or require", constants.EnvTLSClientAuth, mode)
+	}
+	return config, nil
+}
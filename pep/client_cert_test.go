@@ -0,0 +1,261 @@
+package pep
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// testCertificate is a generated certificate with its key
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate issues a certificate from template, self-signed when parent is nil
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key}
+}
+
+func newTestCA(t *testing.T, name string) *testCertificate {
+	return newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"Acme"}},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+}
+
+func newTestClientCert(t *testing.T, ca *testCertificate) *testCertificate {
+	spiffeID, _ := url.Parse("spiffe://acme.internal/ns/payments/sa/payment-service")
+	return newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4242),
+		Subject:      pkix.Name{CommonName: "payment-service", Organization: []string{"Acme"}},
+		DNSNames:     []string{"payment-service.internal"},
+		URIs:         []*url.URL{spiffeID},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+}
+
+// handshakeClientCert performs a mutual TLS request and returns the certificate seen by the server
+func handshakeClientCert(t *testing.T, trustedCA *testCertificate, client *testCertificate) *models.ClientCertInfo {
+	t.Helper()
+	var seen *models.ClientCertInfo
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = ClientCertFromTLS(r.TLS)
+	}))
+	server.TLS = &tls.Config{ClientCAs: x509.NewCertPool(), ClientAuth: tls.VerifyClientCertIfGiven}
+	server.TLS.ClientCAs.AddCert(trustedCA.cert)
+	server.StartTLS()
+	defer server.Close()
+
+	httpClient := server.Client()
+	transport := httpClient.Transport.(*http.Transport)
+	if client != nil {
+		transport.TLSClientConfig.Certificates = []tls.Certificate{{
+			Certificate: [][]byte{client.cert.Raw},
+			PrivateKey:  client.key,
+		}}
+	}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("mutual TLS request failed: %v", err)
+	}
+	resp.Body.Close()
+	return seen
+}
+
+func TestClientCertFromTLS(t *testing.T) {
+	ca := newTestCA(t, "Services CA")
+	client := newTestClientCert(t, ca)
+
+	info := handshakeClientCert(t, ca, client)
+	if info == nil {
+		t.Fatal("Expected client certificate")
+	}
+	if info.SubjectDN != "CN=payment-service,O=Acme" || info.CommonName != "payment-service" {
+		t.Errorf("Unexpected subject: %q / %q", info.SubjectDN, info.CommonName)
+	}
+	if info.IssuerDN != "CN=Services CA,O=Acme" || info.SerialNumber != "4242" {
+		t.Errorf("Unexpected issuer or serial: %q / %q", info.IssuerDN, info.SerialNumber)
+	}
+	if info.Fingerprint != CertFingerprint(client.cert) || len(info.Fingerprint) != 64 {
+		t.Errorf("Unexpected fingerprint %q", info.Fingerprint)
+	}
+	if len(info.URIs) != 1 || info.URIs[0] != "spiffe://acme.internal/ns/payments/sa/payment-service" {
+		t.Errorf("Unexpected URI SANs %v", info.URIs)
+	}
+	if len(info.DNSNames) != 1 || info.DNSNames[0] != "payment-service.internal" {
+		t.Errorf("Unexpected DNS SANs %v", info.DNSNames)
+	}
+	if !info.Verified || len(info.IssuerChain) != 1 || info.IssuerChain[0].Fingerprint != CertFingerprint(ca.cert) {
+		t.Errorf("Expected a verified chain to the CA, got verified=%v chain=%v", info.Verified, info.IssuerChain)
+	}
+
+	if info := handshakeClientCert(t, ca, nil); info != nil {
+		t.Errorf("Expected no client certificate, got %+v", info)
+	}
+	if ClientCertFromTLS(nil) != nil {
+		t.Error("Expected nil for a plain HTTP request")
+	}
+}
+
+func TestClientCertFromTLS_Unverified(t *testing.T) {
+	ca := newTestCA(t, "Services CA")
+	client := newTestClientCert(t, ca)
+
+	// Presented but not verified, e.g. tls.RequestClientCert
+	info := ClientCertFromTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{client.cert, ca.cert}})
+	if info == nil || info.Verified || len(info.IssuerChain) != 0 {
+		t.Errorf("Expected an unverified certificate without issuer chain, got %+v", info)
+	}
+}
+
+func TestClientCert_IssuedByCAPolicy(t *testing.T) {
+	trusted := newTestCA(t, "Services CA")
+	rogue := newTestCA(t, "Services CA") // Same name, different key
+
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "payment:capture", ActionName: "payment:capture"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:payments:1", ResourceID: "api:payments:1"})
+	mockStorage.SetPolicies([]*models.Policy{
+		{
+			ID:      "pol-mtls-services",
+			Enabled: true,
+			Statement: []models.PolicyStatement{
+				{
+					Sid:      "AllowPaymentService",
+					Effect:   "Allow",
+					Action:   models.JSONActionResource{Single: "payment:capture"},
+					Resource: models.JSONActionResource{Single: "api:payments:*"},
+					Condition: map[string]interface{}{
+						"IssuedByCA": map[string]interface{}{"environment.client_cert": CertFingerprint(trusted.cert)},
+						"StringLike": map[string]interface{}{"environment.client_cert.common_name": "payment-%"},
+					},
+				},
+			},
+		},
+	})
+	pdp := core.NewPolicyDecisionPoint(mockStorage)
+
+	// The server trusts the issuing CA in both cases, so only the policy tells them apart
+	tests := []struct {
+		name     string
+		issuer   *testCertificate
+		expected string
+	}{
+		{"certificate from the trusted CA", trusted, constants.ResultPermit},
+		{"certificate from a CA with the same name", rogue, constants.ResultDeny},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientCert := handshakeClientCert(t, test.issuer, newTestClientCert(t, test.issuer))
+			request := &models.EvaluationRequest{
+				RequestID:   "mtls-001",
+				Subject:     models.NewMockUserSubject("payment-service", "payment-service"),
+				ResourceID:  "api:payments:1",
+				Action:      "payment:capture",
+				Environment: &models.EnvironmentInfo{ClientCert: clientCert},
+			}
+
+			decision, err := pdp.Evaluate(request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if decision.Result != test.expected {
+				t.Errorf("Expected %s, got %s (%s)", test.expected, decision.Result, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestTLSConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvTLSCert, "")
+	if config, err := TLSConfigFromEnv(); config != nil || err != nil {
+		t.Fatalf("Expected TLS disabled, got %v, %v", config, err)
+	}
+
+	dir := t.TempDir()
+	ca := newTestCA(t, "Services CA")
+	server := newTestCertificate(t, &x509.Certificate{SerialNumber: big.NewInt(7), DNSNames: []string{"localhost"}}, ca)
+	keyDER, err := x509.MarshalECPrivateKey(server.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	t.Setenv(constants.EnvTLSCert, writePEM("server.pem", "CERTIFICATE", server.cert.Raw))
+	t.Setenv(constants.EnvTLSKey, writePEM("server-key.pem", "EC PRIVATE KEY", keyDER))
+	t.Setenv(constants.EnvTLSClientCA, writePEM("ca.pem", "CERTIFICATE", ca.cert.Raw))
+
+	tests := []struct {
+		mode     string
+		expected tls.ClientAuthType
+		wantErr  bool
+	}{
+		{"", tls.VerifyClientCertIfGiven, false},
+		{"require", tls.RequireAndVerifyClientCert, false},
+		{"always", 0, true},
+	}
+	for _, test := range tests {
+		t.Setenv(constants.EnvTLSClientAuth, test.mode)
+		config, err := TLSConfigFromEnv()
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("mode %q: expected error", test.mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("mode %q: unexpected error: %v", test.mode, err)
+		}
+		if config.ClientAuth != test.expected || config.ClientCAs == nil || len(config.Certificates) != 1 {
+			t.Errorf("mode %q: unexpected config %+v", test.mode, config)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package pep
 
-import "time"
+import (
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/redaction"
+)
 
 // PEPConfig holds basic configuration for SimplePEP
 type PEPConfig struct {
@@ -11,6 +16,9 @@ type PEPConfig struct {
 
 	// Performance settings
 	EvaluationTimeout time.Duration `json:"evaluation_timeout"`
+
+	// Privacy settings
+	Redactor *redaction.Redactor `json:"-"` // Masks sensitive attributes in audit records (nil disables)
 }
 
 // DefaultPEPConfig returns default configuration for SimplePEP
@@ -20,6 +28,7 @@ func DefaultPEPConfig() *PEPConfig {
 		StrictValidation:  true,
 		AuditEnabled:      true,
 		EvaluationTimeout: time.Millisecond * 100,
+		Redactor:          redaction.DefaultRedactor(),
 	}
 }
 
@@ -28,10 +37,14 @@ type EnforcementResult struct {
 	Allowed          bool                   `json:"allowed"`
 	Decision         string                 `json:"decision"` // "permit" or "deny"
 	Reason           string                 `json:"reason"`
+	ReasonCode       string                 `json:"reason_code,omitempty"`
+	ReasonDetails    map[string]string      `json:"reason_details,omitempty"`
 	MatchedPolicies  []string               `json:"matched_policies,omitempty"`
 	EvaluationTime   time.Duration          `json:"evaluation_time"`
 	EvaluationTimeMs int                    `json:"evaluation_time_ms"`
 	CacheHit         bool                   `json:"cache_hit"`
 	Timestamp        time.Time              `json:"timestamp"`
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	Obligations      map[string]string      `json:"obligations,omitempty"` // From the decision, e.g. {"audit": "full"}
+	Constraints      *models.Constraints    `json:"constraints,omitempty"` // Limits of a filtered permit, see ApplyConstraints
 }
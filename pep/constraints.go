@@ -0,0 +1,72 @@
+package pep
+
+import (
+	"net/http"
+	"strings"
+
+	"abac_go_example/evaluator/matchers"
+	"abac_go_example/models"
+)
+
+// PermitsMethod reports whether a permit with constraints allows an HTTP method: read-only
+// permits only allow GET, HEAD and OPTIONS
+func PermitsMethod(constraints *models.Constraints, method string) bool {
+	if constraints == nil || !constraints.ReadOnly {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// ApplyConstraints returns a copy of payload holding only the fields a permit with constraints
+// allows. Fields are dotted paths into nested objects matched against the constraint patterns
+// ("*" wildcards allowed); an object is descended into when a pattern names one of its fields.
+// Constraints without fields return the payload unchanged.
+func ApplyConstraints(payload map[string]interface{}, constraints *models.Constraints) map[string]interface{} {
+	if constraints == nil || len(constraints.Fields) == 0 {
+		return payload
+	}
+	return keepFields(payload, "", constraints.Fields)
+}
+
+// keepFields copies the fields of m under prefix that match or lead to a pattern
+func keepFields(m map[string]interface{}, prefix string, patterns []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range m {
+		path := prefix + key
+		if matchesAny(patterns, path) {
+			result[key] = value
+			continue
+		}
+		child, ok := value.(map[string]interface{})
+		if !ok || !leadsToPattern(patterns, path+".") {
+			continue
+		}
+		if kept := keepFields(child, path+".", patterns); len(kept) > 0 {
+			result[key] = kept
+		}
+	}
+	return result
+}
+
+func matchesAny(patterns []string, field string) bool {
+	for _, pattern := range patterns {
+		if matchers.WildcardMatch(pattern, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// leadsToPattern reports whether a pattern names a field below prefix
+func leadsToPattern(patterns []string, prefix string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, prefix) || strings.HasPrefix(pattern, "*") {
+			return true
+		}
+	}
+	return false
+}
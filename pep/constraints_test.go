@@ -0,0 +1,58 @@
+package pep
+
+import (
+	"reflect"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestApplyConstraints(t *testing.T) {
+	payload := map[string]interface{}{
+		"id":      "emp-1",
+		"name":    "John",
+		"salary":  1000,
+		"profile": map[string]interface{}{"team": "core", "ssn": "123", "address": map[string]interface{}{"city": "Hanoi"}},
+		"manager": map[string]interface{}{"id": "emp-9"},
+	}
+	constraints := &models.Constraints{Fields: []string{"id", "name", "profile.team", "profile.address.*"}}
+
+	expected := map[string]interface{}{
+		"id":      "emp-1",
+		"name":    "John",
+		"profile": map[string]interface{}{"team": "core", "address": map[string]interface{}{"city": "Hanoi"}},
+	}
+	if got := ApplyConstraints(payload, constraints); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected constrained payload %v", got)
+	}
+	if _, ok := payload["salary"]; !ok {
+		t.Error("Expected the original payload to be unchanged")
+	}
+	if got := ApplyConstraints(payload, &models.Constraints{ReadOnly: true}); !reflect.DeepEqual(got, payload) {
+		t.Errorf("Expected constraints without fields to keep every field, got %v", got)
+	}
+	if got := ApplyConstraints(payload, nil); !reflect.DeepEqual(got, payload) {
+		t.Errorf("Expected an unconstrained permit to keep every field, got %v", got)
+	}
+}
+
+func TestPermitsMethod(t *testing.T) {
+	readOnly := &models.Constraints{ReadOnly: true}
+	tests := []struct {
+		constraints *models.Constraints
+		method      string
+		expected    bool
+	}{
+		{nil, "DELETE", true},
+		{&models.Constraints{Fields: []string{"id"}}, "POST", true},
+		{readOnly, "GET", true},
+		{readOnly, "HEAD", true},
+		{readOnly, "POST", false},
+		{readOnly, "PATCH", false},
+	}
+	for _, tt := range tests {
+		if got := PermitsMethod(tt.constraints, tt.method); got != tt.expected {
+			t.Errorf("PermitsMethod(%+v, %s) = %v, want %v", tt.constraints, tt.method, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package pep
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// Headers describing the authorization outcome to downstream services and gateways
+const (
+	HeaderDecision         = "X-ABAC-Decision"    // "permit", "deny" or "not_applicable"
+	HeaderPolicies         = "X-ABAC-Policies"    // Comma-separated matched policy IDs
+	HeaderRequestID        = "X-ABAC-Request-ID"  // Evaluation request ID, for correlating audit logs
+	HeaderObligationPrefix = "X-ABAC-Obligation-" // Followed by the obligation key, e.g. X-ABAC-Obligation-Audit
+	HeaderConstraints      = "X-ABAC-Constraints" // JSON constraints of a filtered permit, e.g. {"read_only":true}
+)
+
+// headerPrefix is shared by every decision header; incoming headers with it are dropped so
+// clients cannot forge an outcome
+const headerPrefix = "X-Abac-"
+
+// DecisionHeaderConfig selects where a PEP sets decision headers
+type DecisionHeaderConfig struct {
+	Request  bool `json:"request"`  // On the request passed to handlers and proxied upstream
+	Response bool `json:"response"` // On the response returned to the caller
+}
+
+// DecisionHeaderConfigFromEnv reads ABAC_PEP_DECISION_HEADERS. Unset sets headers on both the
+// request and the response; "none" returns nil (no decision headers).
+func DecisionHeaderConfigFromEnv() (*DecisionHeaderConfig, error) {
+	value := os.Getenv(constants.EnvPEPDecisionHeaders)
+	if value == "" {
+		return &DecisionHeaderConfig{Request: true, Response: true}, nil
+	}
+	if strings.TrimSpace(value) == "none" {
+		return nil, nil
+	}
+	config := &DecisionHeaderConfig{}
+	for _, target := range splitList(value) {
+		switch target {
+		case "request":
+			config.Request = true
+		case "response":
+			config.Response = true
+		default:
+			return nil, fmt.Errorf("invalid %s target %q: expected request, response or none", constants.EnvPEPDecisionHeaders, target)
+		}
+	}
+	return config, nil
+}
+
+// DecisionHeaders returns the headers describing decision: the result, the matched policies, the
+// request ID, one X-ABAC-Obligation-<key> header per obligation and the constraints of a filtered
+// permit. Empty values are left out.
+func DecisionHeaders(requestID string, decision *models.Decision) http.Header {
+	header := http.Header{}
+	header.Set(HeaderDecision, decision.Result)
+	if requestID != "" {
+		header.Set(HeaderRequestID, headerValue(requestID))
+	}
+	if len(decision.MatchedPolicies) > 0 {
+		header.Set(HeaderPolicies, headerValue(strings.Join(decision.MatchedPolicies, ",")))
+	}
+	keys := make([]string, 0, len(decision.Obligations))
+	for key := range decision.Obligations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if name := obligationHeader(key); name != "" {
+			header.Set(name, headerValue(decision.Obligations[key]))
+		}
+	}
+	if !decision.Constraints.IsEmpty() {
+		if encoded, err := json.Marshal(decision.Constraints); err == nil {
+			header.Set(HeaderConstraints, string(encoded))
+		}
+	}
+	return header
+}
+
+// ApplyDecisionHeaders replaces every X-ABAC-* header of target with decisionHeaders
+func ApplyDecisionHeaders(target, decisionHeaders http.Header) {
+	for name := range target {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), headerPrefix) {
+			target.Del(name)
+		}
+	}
+	for name, values := range decisionHeaders {
+		target[name] = append([]string(nil), values...)
+	}
+}
+
+// obligationHeader names the header of an obligation key; characters not allowed in header
+// names become '-'. Keys without any allowed character have no header.
+func obligationHeader(key string) string {
+	var name strings.Builder
+	valid := false
+	for _, r := range key {
+		if r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			name.WriteRune(r)
+			valid = true
+		} else {
+			name.WriteByte('-')
+		}
+	}
+	if !valid {
+		return ""
+	}
+	return http.CanonicalHeaderKey(HeaderObligationPrefix + name.String())
+}
+
+// headerValue replaces control characters, so values taken from policies cannot split headers
+func headerValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, value)
+}
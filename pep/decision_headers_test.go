@@ -0,0 +1,83 @@
+package pep
+
+import (
+	"net/http"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+func TestDecisionHeaders(t *testing.T) {
+	decision := &models.Decision{
+		Result:          "permit",
+		MatchedPolicies: []string{"pol-001", "pol-002"},
+		Obligations:     map[string]string{"audit": "full", "log_level": "debug\r\nX-Injected: 1", "???": "dropped"},
+		Constraints:     &models.Constraints{Fields: []string{"id", "title"}, ReadOnly: true},
+	}
+	headers := DecisionHeaders("req-1", decision)
+
+	expected := map[string]string{
+		HeaderDecision:                "permit",
+		HeaderRequestID:               "req-1",
+		HeaderPolicies:                "pol-001,pol-002",
+		"X-Abac-Obligation-Audit":     "full",
+		"X-Abac-Obligation-Log-Level": "debug  X-Injected: 1",
+		HeaderConstraints:             `{"fields":["id","title"],"read_only":true}`,
+	}
+	for name, value := range expected {
+		if got := headers.Get(name); got != value {
+			t.Errorf("Expected %s %q, got %q", name, value, got)
+		}
+	}
+	if len(headers) != len(expected) {
+		t.Errorf("Unexpected headers %v", headers)
+	}
+
+	if headers := DecisionHeaders("", &models.Decision{Result: "deny"}); len(headers) != 1 || headers.Get(HeaderDecision) != "deny" {
+		t.Errorf("Expected only the decision header for an unmatched deny, got %v", headers)
+	}
+}
+
+func TestApplyDecisionHeaders(t *testing.T) {
+	target := http.Header{}
+	target.Set("X-ABAC-Obligation-Redact", "none")
+	target.Set("x-abac-decision", "permit")
+	target.Set("Content-Type", "application/json")
+
+	ApplyDecisionHeaders(target, DecisionHeaders("req-2", &models.Decision{Result: "deny"}))
+	if target.Get(HeaderDecision) != "deny" || target.Get(HeaderRequestID) != "req-2" {
+		t.Errorf("Expected the decision headers to be set, got %v", target)
+	}
+	if target.Get("X-ABAC-Obligation-Redact") != "" {
+		t.Error("Expected forged decision headers to be removed")
+	}
+	if target.Get("Content-Type") != "application/json" {
+		t.Error("Expected other headers to be kept")
+	}
+}
+
+func TestDecisionHeaderConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected *DecisionHeaderConfig
+		wantErr  bool
+	}{
+		{"", &DecisionHeaderConfig{Request: true, Response: true}, false},
+		{"none", nil, false},
+		{"request", &DecisionHeaderConfig{Request: true}, false},
+		{"response, request", &DecisionHeaderConfig{Request: true, Response: true}, false},
+		{"upstream", nil, true},
+	}
+	for _, tt := range tests {
+		t.Setenv(constants.EnvPEPDecisionHeaders, tt.value)
+		config, err := DecisionHeaderConfigFromEnv()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error %v", tt.value, err)
+			continue
+		}
+		if (config == nil) != (tt.expected == nil) || config != nil && *config != *tt.expected {
+			t.Errorf("%q: expected %+v, got %+v", tt.value, tt.expected, config)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package pep
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/redaction"
+)
+
+// ApplyFieldMask returns a copy of payload with field directives applied:
+// denied fields are removed and masked fields are replaced with redaction.DefaultMask.
+// Fields are dotted paths into nested objects (e.g. "profile.salary"); fields without a
+// directive are kept unchanged. A nil decision fails closed and returns an empty payload.
+func ApplyFieldMask(payload map[string]interface{}, decision *models.FieldDecision) map[string]interface{} {
+	if decision == nil {
+		return map[string]interface{}{}
+	}
+
+	result := copyPayload(payload)
+	for field, directive := range decision.Fields {
+		switch directive.Effect {
+		case constants.EffectDeny:
+			applyToPath(result, strings.Split(field, "."), func(m map[string]interface{}, key string) {
+				delete(m, key)
+			})
+		case constants.EffectMask:
+			applyToPath(result, strings.Split(field, "."), func(m map[string]interface{}, key string) {
+				m[key] = redaction.DefaultMask
+			})
+		}
+	}
+	return result
+}
+
+// ApplyFieldMaskJSON applies field directives to a JSON object payload
+func ApplyFieldMaskJSON(data []byte, decision *models.FieldDecision) ([]byte, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("payload must be a JSON object: %w", err)
+	}
+	return json.Marshal(ApplyFieldMask(payload, decision))
+}
+
+// applyToPath walks path through nested objects and calls apply on the parent of the last segment
+// when that key exists
+func applyToPath(m map[string]interface{}, path []string, apply func(map[string]interface{}, string)) {
+	if len(path) == 1 {
+		if _, ok := m[path[0]]; ok {
+			apply(m, path[0])
+		}
+		return
+	}
+
+	if child, ok := m[path[0]].(map[string]interface{}); ok {
+		applyToPath(child, path[1:], apply)
+	}
+}
+
+// copyPayload deep-copies nested objects so the caller's payload is never modified
+func copyPayload(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if child, ok := value.(map[string]interface{}); ok {
+			result[key] = copyPayload(child)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package pep
+
+import (
+	"encoding/json"
+	"testing"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/redaction"
+)
+
+func TestApplyFieldMask(t *testing.T) {
+	payload := map[string]interface{}{
+		"name":   "Nguyen Van A",
+		"salary": 50000,
+		"email":  "a@company.com",
+		"contact": map[string]interface{}{
+			"phone":   "0901234567",
+			"address": "Hanoi",
+		},
+	}
+	decision := &models.FieldDecision{
+		Fields: map[string]models.FieldDirective{
+			"name":          {Field: "name", Effect: constants.EffectAllow},
+			"salary":        {Field: "salary", Effect: constants.EffectDeny},
+			"email":         {Field: "email", Effect: constants.EffectMask},
+			"contact.phone": {Field: "contact.phone", Effect: constants.EffectMask},
+			"missing.field": {Field: "missing.field", Effect: constants.EffectDeny},
+		},
+	}
+
+	result := ApplyFieldMask(payload, decision)
+
+	if result["name"] != "Nguyen Van A" {
+		t.Errorf("expected name to be kept, got %v", result["name"])
+	}
+	if _, ok := result["salary"]; ok {
+		t.Error("expected salary to be removed")
+	}
+	if result["email"] != redaction.DefaultMask {
+		t.Errorf("expected email to be masked, got %v", result["email"])
+	}
+	contact := result["contact"].(map[string]interface{})
+	if contact["phone"] != redaction.DefaultMask {
+		t.Errorf("expected contact.phone to be masked, got %v", contact["phone"])
+	}
+	if contact["address"] != "Hanoi" {
+		t.Errorf("expected contact.address to be kept, got %v", contact["address"])
+	}
+
+	// The original payload must not be modified
+	if payload["salary"] != 50000 || payload["contact"].(map[string]interface{})["phone"] != "0901234567" {
+		t.Error("original payload was modified")
+	}
+
+	if len(ApplyFieldMask(payload, nil)) != 0 {
+		t.Error("expected nil decision to return an empty payload")
+	}
+}
+
+func TestApplyFieldMaskJSON(t *testing.T) {
+	decision := &models.FieldDecision{
+		Fields: map[string]models.FieldDirective{
+			"salary": {Field: "salary", Effect: constants.EffectDeny},
+		},
+	}
+
+	data, err := ApplyFieldMaskJSON([]byte(`{"name":"A","salary":1000}`), decision)
+	if err != nil {
+		t.Fatalf("ApplyFieldMaskJSON returned error: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if _, ok := result["salary"]; ok || result["name"] != "A" {
+		t.Errorf("unexpected result: %s", data)
+	}
+
+	if _, err := ApplyFieldMaskJSON([]byte(`[1,2]`), decision); err == nil {
+		t.Error("expected error for non-object payload")
+	}
+}
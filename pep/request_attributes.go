@@ -0,0 +1,182 @@
+package pep
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"abac_go_example/constants"
+)
+
+const (
+	// DefaultMaxBodyBytes bounds the request body read to extract body fields
+	DefaultMaxBodyBytes = 64 << 10
+	// DefaultMaxHeaderBytes bounds the header values exposed as attributes
+	DefaultMaxHeaderBytes = 1024
+)
+
+// ErrBodyTooLarge is returned when body fields are configured and the request body exceeds MaxBodyBytes.
+// The request cannot be evaluated without its fields, so PEPs reject it (413) instead of guessing.
+var ErrBodyTooLarge = errors.New("request body exceeds the attribute extraction limit")
+
+// RequestAttributeConfig selects request body fields and headers exposed to policies as
+// request.body.<path> and request.header.<lowercase name>
+type RequestAttributeConfig struct {
+	BodyFields     []string `json:"body_fields"` // Dotted JSON paths, e.g. "amount" or "payee.country"
+	Headers        []string `json:"headers"`
+	MaxBodyBytes   int64    `json:"max_body_bytes"`
+	MaxHeaderBytes int      `json:"max_header_bytes"`
+}
+
+// RequestAttributeConfigFromEnv reads ABAC_PEP_BODY_FIELDS, ABAC_PEP_HEADERS, ABAC_PEP_MAX_BODY_BYTES
+// and ABAC_PEP_MAX_HEADER_BYTES. It returns nil (no request attributes) when neither fields nor headers are set.
+func RequestAttributeConfigFromEnv() (*RequestAttributeConfig, error) {
+	config := &RequestAttributeConfig{
+		BodyFields:     splitList(os.Getenv(constants.EnvPEPBodyFields)),
+		Headers:        splitList(os.Getenv(constants.EnvPEPHeaders)),
+		MaxBodyBytes:   DefaultMaxBodyBytes,
+		MaxHeaderBytes: DefaultMaxHeaderBytes,
+	}
+	if len(config.BodyFields) == 0 && len(config.Headers) == 0 {
+		return nil, nil
+	}
+	if value := os.Getenv(constants.EnvPEPMaxBodyBytes); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive integer", constants.EnvPEPMaxBodyBytes, value)
+		}
+		config.MaxBodyBytes = n
+	}
+	if value := os.Getenv(constants.EnvPEPMaxHeaderBytes); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive integer", constants.EnvPEPMaxHeaderBytes, value)
+		}
+		config.MaxHeaderBytes = n
+	}
+	return config, nil
+}
+
+// ExtractRequestAttributes returns the configured attributes of r as request context entries:
+// {"body": {...}, "header": {...}}. The body is restored so handlers can read it again. Only JSON
+// bodies are inspected; fields missing from the body are left out, so conditions on them do not match.
+// Numbers are kept as json.Number, so numeric conditions compare amounts exactly.
+func ExtractRequestAttributes(r *http.Request, config *RequestAttributeConfig) (map[string]interface{}, error) {
+	attributes := map[string]interface{}{}
+	if config == nil {
+		return attributes, nil
+	}
+
+	if headers := extractHeaders(r.Header, config); len(headers) > 0 {
+		attributes["header"] = headers
+	}
+
+	if len(config.BodyFields) == 0 || r.Body == nil || r.Body == http.NoBody || !isJSON(r.Header.Get("Content-Type")) {
+		return attributes, nil
+	}
+	limit := config.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrBodyTooLarge
+	}
+
+	var body interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		// Malformed bodies expose no fields; the handler reports the parse error
+		return attributes, nil
+	}
+	if fields := extractBodyFields(body, config.BodyFields); len(fields) > 0 {
+		attributes["body"] = fields
+	}
+	return attributes, nil
+}
+
+// extractHeaders returns the configured headers keyed by lowercase name; values longer than
+// MaxHeaderBytes are left out rather than truncated, so prefix matches cannot be forged
+func extractHeaders(header http.Header, config *RequestAttributeConfig) map[string]interface{} {
+	limit := config.MaxHeaderBytes
+	if limit <= 0 {
+		limit = DefaultMaxHeaderBytes
+	}
+	headers := map[string]interface{}{}
+	for _, name := range config.Headers {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if len(value) > limit {
+			continue
+		}
+		headers[strings.ToLower(name)] = value
+	}
+	return headers
+}
+
+// extractBodyFields copies the values at paths into a nested map mirroring the body structure
+func extractBodyFields(body interface{}, paths []string) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		value, ok := lookup(body, segments)
+		if !ok {
+			continue
+		}
+		target := fields
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := target[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				target[segment] = child
+			}
+			target = child
+		}
+		target[segments[len(segments)-1]] = value
+	}
+	return fields
+}
+
+func lookup(value interface{}, segments []string) (interface{}, bool) {
+	for _, segment := range segments {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
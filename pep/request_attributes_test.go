@@ -0,0 +1,92 @@
+package pep
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"abac_go_example/constants"
+)
+
+func TestExtractRequestAttributes(t *testing.T) {
+	config := &RequestAttributeConfig{
+		BodyFields: []string{"amount", "payee.country", "missing", "memo.text"},
+		Headers:    []string{"X-Channel", "X-Missing", "X-Long"},
+	}
+	body := `{"amount": 15000.50, "currency": "VND", "payee": {"country": "VN", "iban": "secret"}, "memo": "not an object"}`
+	req := httptest.NewRequest("POST", "/api/v1/payments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-Channel", "mobile")
+	req.Header.Set("X-Long", strings.Repeat("a", DefaultMaxHeaderBytes+1))
+
+	attributes, err := ExtractRequestAttributes(req, config)
+	if err != nil {
+		t.Fatalf("ExtractRequestAttributes failed: %v", err)
+	}
+	expected := map[string]interface{}{
+		"body": map[string]interface{}{
+			"amount": json.Number("15000.50"),
+			"payee":  map[string]interface{}{"country": "VN"},
+		},
+		"header": map[string]interface{}{"x-channel": "mobile"},
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("Unexpected attributes %#v", attributes)
+	}
+
+	// Handlers still read the whole body
+	if restored, _ := io.ReadAll(req.Body); string(restored) != body {
+		t.Errorf("Expected the body to be restored, got %q", restored)
+	}
+}
+
+func TestExtractRequestAttributesLimits(t *testing.T) {
+	config := &RequestAttributeConfig{BodyFields: []string{"amount"}, MaxBodyBytes: 16}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"amount": 1, "padding": "xxxxxxxx"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := ExtractRequestAttributes(req, config); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge, got %v", err)
+	}
+
+	// Non-JSON and malformed bodies expose no fields
+	for _, tt := range []struct{ contentType, body string }{
+		{"text/plain", `{"amount": 1}`},
+		{"application/json", `{"amount":`},
+	} {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		if attributes, err := ExtractRequestAttributes(req, config); err != nil || len(attributes) != 0 {
+			t.Errorf("%s %q: expected no attributes, got %v %v", tt.contentType, tt.body, attributes, err)
+		}
+	}
+
+	if attributes, err := ExtractRequestAttributes(httptest.NewRequest("GET", "/", nil), nil); err != nil || len(attributes) != 0 {
+		t.Errorf("Expected no attributes without config, got %v %v", attributes, err)
+	}
+}
+
+func TestRequestAttributeConfigFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvPEPBodyFields, "")
+	t.Setenv(constants.EnvPEPHeaders, "")
+	if config, err := RequestAttributeConfigFromEnv(); config != nil || err != nil {
+		t.Errorf("Expected no config, got %+v %v", config, err)
+	}
+
+	t.Setenv(constants.EnvPEPBodyFields, "amount, payee.country")
+	t.Setenv(constants.EnvPEPHeaders, "X-Channel")
+	t.Setenv(constants.EnvPEPMaxBodyBytes, "1024")
+	config, err := RequestAttributeConfigFromEnv()
+	if err != nil || !reflect.DeepEqual(config.BodyFields, []string{"amount", "payee.country"}) || config.MaxBodyBytes != 1024 || config.MaxHeaderBytes != DefaultMaxHeaderBytes {
+		t.Errorf("Unexpected config %+v %v", config, err)
+	}
+
+	t.Setenv(constants.EnvPEPMaxBodyBytes, "-1")
+	if _, err := RequestAttributeConfigFromEnv(); err == nil {
+		t.Error("Expected an invalid limit to be rejected")
+	}
+}
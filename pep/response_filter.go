@@ -0,0 +1,229 @@
+package pep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+)
+
+const (
+	// DefaultFilterWorkers bounds the concurrent evaluations of one filtered response
+	DefaultFilterWorkers = 8
+	// DefaultFilterTimeout bounds the time spent filtering one response
+	DefaultFilterTimeout = 200 * time.Millisecond
+)
+
+// ItemResource describes a list item as the resource a filter evaluates. ID must name a stored
+// resource (e.g. "api:documents:doc-1"); Attributes taken from the item are merged over the stored
+// ones as request overrides, so policies see the values the response is about to expose.
+type ItemResource struct {
+	ID         string
+	Attributes map[string]interface{}
+}
+
+// ResponseFilterConfig configures post-filtering of list responses
+type ResponseFilterConfig struct {
+	Action  string        `json:"action"`  // Policy-defined filter action, e.g. "document:list-item"
+	Workers int           `json:"workers"` // Concurrent evaluations; 0 uses DefaultFilterWorkers
+	Timeout time.Duration `json:"timeout"` // Overall filtering budget; 0 uses DefaultFilterTimeout
+}
+
+// FilterStats summarizes one filtered response
+type FilterStats struct {
+	Items       int           `json:"items"`
+	Allowed     int           `json:"allowed"`
+	Removed     int           `json:"removed"`
+	Evaluations int           `json:"evaluations"` // Distinct resources evaluated; duplicate items share a decision
+	Errors      int           `json:"errors"`
+	TimedOut    int           `json:"timed_out"` // Resources without a decision when the budget ran out
+	Duration    time.Duration `json:"duration"`
+}
+
+// FilterItems returns the items subject may see under config.Action, in their original order.
+// base supplies the request fields shared by every item (subject, context, environment, session);
+// its ResourceID and Action are ignored. Identical resources are evaluated once and distinct ones
+// concurrently within config.Timeout. The filter fails closed: items whose evaluation errors or
+// does not finish in time are removed.
+func FilterItems[T any](ctx context.Context, pdp core.PolicyDecisionPointInterface, base *models.EvaluationRequest,
+	items []T, resourceOf func(T) ItemResource, config ResponseFilterConfig) ([]T, *FilterStats, error) {
+	startTime := time.Now()
+	if base == nil || base.Subject == nil {
+		return nil, nil, fmt.Errorf("response filter requires a subject")
+	}
+	if config.Action == "" {
+		return nil, nil, fmt.Errorf("response filter requires an action")
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = DefaultFilterWorkers
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultFilterTimeout
+	}
+
+	// Deduplicate items into batch entries
+	resources := make([]ItemResource, 0, len(items))
+	itemEntry := make([]int, len(items))
+	entryByKey := make(map[string]int, len(items))
+	for i, item := range items {
+		resource := resourceOf(item)
+		key, err := resourceKey(resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		entry, ok := entryByKey[key]
+		if !ok {
+			entry = len(resources)
+			entryByKey[key] = entry
+			resources = append(resources, resource)
+		}
+		itemEntry[i] = entry
+	}
+
+	decisions := evaluateBatch(ctx, pdp, base, config.Action, resources, workers, timeout)
+
+	stats := &FilterStats{Items: len(items), Evaluations: len(resources)}
+	for _, outcome := range decisions {
+		switch {
+		case outcome.timedOut:
+			stats.TimedOut++
+		case outcome.err != nil:
+			stats.Errors++
+		}
+	}
+	allowed := make([]T, 0, len(items))
+	for i, item := range items {
+		if decisions[itemEntry[i]].permitted() {
+			allowed = append(allowed, item)
+		}
+	}
+	stats.Allowed = len(allowed)
+	stats.Removed = len(items) - len(allowed)
+	stats.Duration = time.Since(startTime)
+	return allowed, stats, nil
+}
+
+// MapItemResource maps JSON-like items to resources: the ID is prefix followed by the item's
+// idField, and the item's fields (except nested objects and lists) are the resource attributes
+func MapItemResource(prefix, idField string) func(map[string]interface{}) ItemResource {
+	return func(item map[string]interface{}) ItemResource {
+		attributes := make(map[string]interface{}, len(item))
+		for key, value := range item {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				continue
+			}
+			attributes[key] = value
+		}
+		return ItemResource{ID: prefix + fmt.Sprint(item[idField]), Attributes: attributes}
+	}
+}
+
+// batchOutcome is the decision of one distinct resource
+type batchOutcome struct {
+	decision *models.Decision
+	err      error
+	timedOut bool
+}
+
+func (o batchOutcome) permitted() bool {
+	return !o.timedOut && o.err == nil && o.decision != nil && o.decision.Result == constants.ResultPermit
+}
+
+// evaluateBatch evaluates action on resources with a bounded worker pool. It returns when every
+// resource is decided or the timeout expires; evaluations still in flight then finish in the
+// background and their results are discarded.
+func evaluateBatch(ctx context.Context, pdp core.PolicyDecisionPointInterface, base *models.EvaluationRequest,
+	action string, resources []ItemResource, workers int, timeout time.Duration) []batchOutcome {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	outcomes := make([]batchOutcome, len(resources))
+	decided := make([]bool, len(resources))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(resources)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				decision, err := pdp.Evaluate(itemRequest(base, action, resources[i], i))
+				mu.Lock()
+				if ctx.Err() == nil {
+					outcomes[i] = batchOutcome{decision: decision, err: err}
+					decided[i] = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer wg.Wait()
+		defer close(jobs)
+		for i := range resources {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]batchOutcome, len(resources))
+	for i := range resources {
+		if decided[i] {
+			result[i] = outcomes[i]
+		} else {
+			result[i] = batchOutcome{timedOut: true}
+		}
+	}
+	return result
+}
+
+// itemRequest derives the evaluation request of one resource from the shared base request
+func itemRequest(base *models.EvaluationRequest, action string, resource ItemResource, index int) *models.EvaluationRequest {
+	request := *base
+	request.RequestID = fmt.Sprintf("%s-filter-%d", base.RequestID, index)
+	request.ResourceID = resource.ID
+	request.Action = action
+
+	request.Context = make(map[string]interface{}, len(base.Context)+1)
+	for key, value := range base.Context {
+		request.Context[key] = value
+	}
+	if len(resource.Attributes) > 0 {
+		request.Context[constants.ContextKeyResourceAttributes] = resource.Attributes
+	}
+	return &request
+}
+
+// resourceKey identifies a resource for deduplication; encoding/json sorts map keys
+func resourceKey(resource ItemResource) (string, error) {
+	if resource.ID == "" {
+		return "", fmt.Errorf("resource ID is required")
+	}
+	attributes, err := json.Marshal(resource.Attributes)
+	if err != nil {
+		return "", fmt.Errorf("resource %s: attributes are not serializable: %w", resource.ID, err)
+	}
+	return resource.ID + "\x00" + string(attributes), nil
+}
@@ -0,0 +1,107 @@
+package pep
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// countingPDP counts evaluations and delays them
+type countingPDP struct {
+	core.PolicyDecisionPointInterface
+	calls atomic.Int64
+	delay time.Duration
+}
+
+func (p *countingPDP) Evaluate(request *models.EvaluationRequest) (*models.Decision, error) {
+	p.calls.Add(1)
+	time.Sleep(p.delay)
+	return p.PolicyDecisionPointInterface.Evaluate(request)
+}
+
+func newFilterPDP(t *testing.T) core.PolicyDecisionPointInterface {
+	t.Helper()
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:list-item", ActionName: "document:list-item"})
+	for _, id := range []string{"doc-1", "doc-2", "doc-3"} {
+		mockStorage.CreateResource(&models.Resource{ID: "api:documents:" + id, ResourceID: "api:documents:" + id, ResourceType: "document"})
+	}
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-list-documents",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:       "ListOwnOrPublic",
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Single: "document:list-item"},
+			Resource:  models.JSONActionResource{Single: "api:documents:*"},
+			Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"resource.classification": "public"}},
+		}},
+	}})
+	return core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig())
+}
+
+func TestFilterItems(t *testing.T) {
+	pdp := &countingPDP{PolicyDecisionPointInterface: newFilterPDP(t)}
+	base := &models.EvaluationRequest{RequestID: "list-1", Subject: models.NewMockUserSubject("user-1", "user-1"), Context: map[string]interface{}{}}
+	items := []map[string]interface{}{
+		{"id": "doc-1", "classification": "public"},
+		{"id": "doc-2", "classification": "confidential"},
+		{"id": "doc-3", "classification": "public", "owner": map[string]interface{}{"id": "user-2"}},
+		{"id": "doc-1", "classification": "public"},
+		{"id": "doc-404", "classification": "public"},
+	}
+
+	allowed, stats, err := FilterItems(context.Background(), pdp, base, items,
+		MapItemResource("api:documents:", "id"), ResponseFilterConfig{Action: "document:list-item"})
+	if err != nil {
+		t.Fatalf("FilterItems failed: %v", err)
+	}
+
+	var ids []string
+	for _, item := range allowed {
+		ids = append(ids, item["id"].(string))
+	}
+	if len(ids) != 3 || ids[0] != "doc-1" || ids[1] != "doc-3" || ids[2] != "doc-1" {
+		t.Errorf("Expected public documents in order [doc-1 doc-3 doc-1], got %v", ids)
+	}
+	if stats.Items != 5 || stats.Allowed != 3 || stats.Removed != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.Evaluations != 4 || pdp.calls.Load() != 4 {
+		t.Errorf("Expected duplicate items to share one evaluation, got %d evaluations and %d calls", stats.Evaluations, pdp.calls.Load())
+	}
+	if stats.Errors != 1 {
+		t.Errorf("Expected the unknown resource to fail closed as an error, got %+v", stats)
+	}
+}
+
+func TestFilterItemsTimeout(t *testing.T) {
+	pdp := &countingPDP{PolicyDecisionPointInterface: newFilterPDP(t), delay: 100 * time.Millisecond}
+	base := &models.EvaluationRequest{RequestID: "list-2", Subject: models.NewMockUserSubject("user-1", "user-1"), Context: map[string]interface{}{}}
+	items := []string{"doc-1", "doc-2", "doc-3"}
+	resourceOf := func(id string) ItemResource {
+		return ItemResource{ID: "api:documents:" + id, Attributes: map[string]interface{}{"classification": "public"}}
+	}
+
+	start := time.Now()
+	allowed, stats, err := FilterItems(context.Background(), pdp, base, items, resourceOf,
+		ResponseFilterConfig{Action: "document:list-item", Workers: 1, Timeout: 150 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("FilterItems failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 280*time.Millisecond {
+		t.Errorf("Expected filtering to stop at the timeout, took %v", elapsed)
+	}
+	if len(allowed) != 1 || allowed[0] != "doc-1" || stats.TimedOut != 2 || stats.Removed != 2 {
+		t.Errorf("Expected undecided items to be removed, got %v and %+v", allowed, stats)
+	}
+
+	if _, _, err := FilterItems(context.Background(), pdp, base, items, resourceOf, ResponseFilterConfig{}); err == nil {
+		t.Error("Expected an error without a filter action")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"abac_go_example/audit"
+	"abac_go_example/constants"
 	"abac_go_example/evaluator/core"
 	"abac_go_example/models"
+	"abac_go_example/redaction"
 )
 
 // AuditLogger interface for audit logging
@@ -39,6 +42,7 @@ func NewSimplePolicyEnforcementPoint(pdp core.PolicyDecisionPointInterface, audi
 			StrictValidation:  true,
 			AuditEnabled:      true,
 			EvaluationTimeout: time.Millisecond * 100,
+			Redactor:          redaction.DefaultRedactor(),
 		}
 	}
 
@@ -60,7 +64,7 @@ func (spep *SimplePolicyEnforcementPoint) EnforceRequest(ctx context.Context, re
 	// Input validation
 	if err := spep.validateRequest(request); err != nil {
 		spep.metrics.ValidationErrors++
-		return spep.createDenyResult("Invalid request: "+err.Error(), startTime), nil
+		return spep.createDenyResult("Invalid request: "+err.Error(), constants.ReasonCodeInvalidRequest, err, startTime), nil
 	}
 
 	// Create context with timeout
@@ -74,7 +78,7 @@ func (spep *SimplePolicyEnforcementPoint) EnforceRequest(ctx context.Context, re
 
 		// Fail-safe mode: deny on error
 		if spep.config.FailSafeMode {
-			result := spep.createDenyResult("Evaluation error: "+err.Error(), startTime)
+			result := spep.createDenyResult("Evaluation error: "+err.Error(), constants.ReasonCodeEvaluationError, err, startTime)
 			spep.auditDecision(request, result)
 			return result, nil
 		}
@@ -86,10 +90,14 @@ func (spep *SimplePolicyEnforcementPoint) EnforceRequest(ctx context.Context, re
 		Decision:         decision.Result,
 		Allowed:          decision.Result == "permit",
 		Reason:           decision.Reason,
+		ReasonCode:       decision.ReasonCode,
+		ReasonDetails:    decision.ReasonDetails,
 		MatchedPolicies:  decision.MatchedPolicies,
 		EvaluationTimeMs: int(time.Since(startTime).Milliseconds()),
 		CacheHit:         false,
 		Timestamp:        time.Now(),
+		Obligations:      decision.Obligations,
+		Constraints:      decision.Constraints,
 	}
 
 	// Update metrics based on decision
@@ -171,11 +179,13 @@ func (spep *SimplePolicyEnforcementPoint) validateRequest(request *models.Evalua
 }
 
 // createDenyResult creates a deny result for error cases
-func (spep *SimplePolicyEnforcementPoint) createDenyResult(reason string, startTime time.Time) *EnforcementResult {
+func (spep *SimplePolicyEnforcementPoint) createDenyResult(reason, reasonCode string, cause error, startTime time.Time) *EnforcementResult {
 	return &EnforcementResult{
 		Decision:         "deny",
 		Allowed:          false,
 		Reason:           reason,
+		ReasonCode:       reasonCode,
+		ReasonDetails:    map[string]string{constants.ReasonDetailError: cause.Error()},
 		MatchedPolicies:  []string{},
 		EvaluationTimeMs: int(time.Since(startTime).Milliseconds()),
 		CacheHit:         false,
@@ -183,7 +193,8 @@ func (spep *SimplePolicyEnforcementPoint) createDenyResult(reason string, startT
 	}
 }
 
-// auditDecision logs the decision for audit purposes
+// auditDecision logs the decision for audit purposes. A "minimal" audit obligation drops the
+// request context from the entry.
 func (spep *SimplePolicyEnforcementPoint) auditDecision(request *models.EvaluationRequest, result *EnforcementResult) {
 	if spep.auditLogger == nil {
 		return
@@ -204,7 +215,13 @@ func (spep *SimplePolicyEnforcementPoint) auditDecision(request *models.Evaluati
 		"allowed":          result.Allowed,
 		"evaluation_ms":    result.EvaluationTimeMs,
 		"matched_policies": result.MatchedPolicies,
-		"context":          request.Context,
+	}
+	level := audit.AuditLevel(result.Obligations)
+	if level != constants.AuditLevelMinimal {
+		auditData["context"] = spep.config.Redactor.RedactMap(request.Context)
+	}
+	if level != constants.AuditLevelStandard {
+		auditData["audit_level"] = level
 	}
 
 	spep.auditLogger.LogDecision(auditData)
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"abac_go_example/constants"
 	"abac_go_example/evaluator/core"
 	"abac_go_example/models"
 	"abac_go_example/storage"
@@ -271,3 +272,52 @@ func TestSimplePolicyEnforcementPoint_Timeout(t *testing.T) {
 func BenchmarkSimplePolicyEnforcementPoint_EnforceRequest(b *testing.B) {
 	b.Skip("Skipping benchmark - requires database setup")
 }
+
+// recordingAuditLogger keeps the logged audit records
+type recordingAuditLogger struct {
+	records []map[string]interface{}
+}
+
+func (r *recordingAuditLogger) LogDecision(data map[string]interface{}) {
+	r.records = append(r.records, data)
+}
+
+func TestSimplePolicyEnforcementPoint_AuditObligation(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	mockStorage.CreateAction(&models.Action{ID: "document:read", ActionName: "document:read"})
+	mockStorage.CreateResource(&models.Resource{ID: "api:documents:faq", ResourceID: "api:documents:faq"})
+	mockStorage.SetPolicies([]*models.Policy{{
+		ID:      "pol-public-read",
+		Enabled: true,
+		Statement: []models.PolicyStatement{{
+			Sid:         "PublicRead",
+			Effect:      "Allow",
+			Action:      models.JSONActionResource{Single: "document:read"},
+			Resource:    models.JSONActionResource{Single: "api:documents:*"},
+			Obligations: map[string]string{constants.ObligationAudit: constants.AuditLevelMinimal},
+		}},
+	}})
+	auditLogger := &recordingAuditLogger{}
+	pep := NewSimplePolicyEnforcementPoint(core.NewPolicyDecisionPointWithConfig(mockStorage, core.DefaultPDPConfig()), auditLogger, nil)
+
+	result, err := pep.EnforceRequest(context.Background(), &models.EvaluationRequest{
+		RequestID:  "obligation-001",
+		Subject:    models.NewMockUserSubject("user-1", "user-1"),
+		ResourceID: "api:documents:faq",
+		Action:     "document:read",
+		Context:    map[string]interface{}{"source_ip": "10.0.0.1"},
+	})
+	if err != nil || !result.Allowed {
+		t.Fatalf("Expected permit, got %v (%v)", result, err)
+	}
+	if result.Obligations[constants.ObligationAudit] != constants.AuditLevelMinimal {
+		t.Errorf("Expected the audit obligation on the result, got %v", result.Obligations)
+	}
+	if len(auditLogger.records) != 1 {
+		t.Fatalf("Expected one audit record, got %d", len(auditLogger.records))
+	}
+	record := auditLogger.records[0]
+	if _, ok := record["context"]; ok || record["audit_level"] != constants.AuditLevelMinimal {
+		t.Errorf("Expected a minimal record without request context, got %v", record)
+	}
+}
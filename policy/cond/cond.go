@@ -0,0 +1,269 @@
+// Package cond builds policy statement conditions in the JSON shape the condition evaluator reads,
+// e.g. cond.StringEquals("user.department", "Engineering") is {"StringEquals": {"user.department": "Engineering"}}.
+package cond
+
+import (
+	"time"
+
+	"abac_go_example/constants"
+)
+
+// Condition is one operator block of a statement's Condition map
+type Condition map[string]interface{}
+
+// Map returns the block as a plain map, the form nested And/Or/Not conditions must take
+func (c Condition) Map() map[string]interface{} {
+	return map[string]interface{}(c)
+}
+
+// leaf builds {operator: {key: value}}
+func leaf(operator constants.ConditionOperatorType, key string, value interface{}) Condition {
+	return Condition{string(operator): map[string]interface{}{key: value}}
+}
+
+// list converts values to the []interface{} form JSON decoding produces
+func list(values []string) []interface{} {
+	items := make([]interface{}, len(values))
+	for i, value := range values {
+		items[i] = value
+	}
+	return items
+}
+
+// oneOrList keeps a single value unwrapped
+func oneOrList(values []string) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return list(values)
+}
+
+// String conditions
+
+func StringEquals(key, value string) Condition {
+	return leaf(constants.ConditionStringEquals, key, value)
+}
+
+// StringNotEquals also matches when the attribute is missing
+func StringNotEquals(key, value string) Condition {
+	return leaf(constants.ConditionStringNotEquals, key, value)
+}
+
+// StringLike matches a SQL LIKE pattern ('%' any sequence, '_' one character)
+func StringLike(key, pattern string) Condition {
+	return leaf(constants.ConditionStringLike, key, pattern)
+}
+
+func StringContains(key, substring string) Condition {
+	return leaf("StringContains", key, substring)
+}
+
+func StringStartsWith(key, prefix string) Condition {
+	return leaf("StringStartsWith", key, prefix)
+}
+
+func StringEndsWith(key, suffix string) Condition {
+	return leaf("StringEndsWith", key, suffix)
+}
+
+func StringRegex(key, pattern string) Condition {
+	return leaf("StringRegex", key, pattern)
+}
+
+// Accent-insensitive string conditions: "Ky thuat" matches "Kỹ thuật"
+
+func StringEqualsIgnoreAccents(key, value string) Condition {
+	return leaf("StringEqualsIgnoreAccents", key, value)
+}
+
+// StringNotEqualsIgnoreAccents also matches when the attribute is missing
+func StringNotEqualsIgnoreAccents(key, value string) Condition {
+	return leaf("StringNotEqualsIgnoreAccents", key, value)
+}
+
+func StringContainsIgnoreAccents(key, substring string) Condition {
+	return leaf("StringContainsIgnoreAccents", key, substring)
+}
+
+func StringStartsWithIgnoreAccents(key, prefix string) Condition {
+	return leaf("StringStartsWithIgnoreAccents", key, prefix)
+}
+
+// Version conditions compare dotted numbers (e.g. "120.0")
+
+func VersionLessThan(key, version string) Condition {
+	return leaf(constants.ConditionVersionLessThan, key, version)
+}
+
+func VersionLessThanEquals(key, version string) Condition {
+	return leaf(constants.ConditionVersionLessThanEquals, key, version)
+}
+
+func VersionGreaterThan(key, version string) Condition {
+	return leaf(constants.ConditionVersionGreaterThan, key, version)
+}
+
+func VersionGreaterThanEquals(key, version string) Condition {
+	return leaf(constants.ConditionVersionGreaterThanEquals, key, version)
+}
+
+// Numeric conditions
+
+func NumericEquals(key string, value float64) Condition {
+	return leaf("NumericEquals", key, value)
+}
+
+func NumericNotEquals(key string, value float64) Condition {
+	return leaf("NumericNotEquals", key, value)
+}
+
+func NumericLessThan(key string, value float64) Condition {
+	return leaf(constants.ConditionNumericLessThan, key, value)
+}
+
+func NumericLessThanEquals(key string, value float64) Condition {
+	return leaf(constants.ConditionNumericLessThanEquals, key, value)
+}
+
+func NumericGreaterThan(key string, value float64) Condition {
+	return leaf(constants.ConditionNumericGreaterThan, key, value)
+}
+
+func NumericGreaterThanEquals(key string, value float64) Condition {
+	return leaf(constants.ConditionNumericGreaterThanEquals, key, value)
+}
+
+// NumericBetween matches min <= value <= max
+func NumericBetween(key string, min, max float64) Condition {
+	return leaf("NumericBetween", key, []interface{}{min, max})
+}
+
+func Bool(key string, value bool) Condition {
+	return leaf(constants.ConditionBool, key, value)
+}
+
+// Date and time conditions
+
+func DateLessThan(key string, t time.Time) Condition {
+	return leaf(constants.ConditionDateLessThan, key, t.Format(time.RFC3339))
+}
+
+func DateGreaterThan(key string, t time.Time) Condition {
+	return leaf(constants.ConditionDateGreaterThan, key, t.Format(time.RFC3339))
+}
+
+// TimeBetween matches a clock window of "HH:MM" bounds; windows may cross midnight ("22:00", "06:00")
+func TimeBetween(key, start, end string) Condition {
+	return leaf(constants.ConditionTimeBetween, key, []interface{}{start, end})
+}
+
+// TimeBetweenIn is TimeBetween evaluated in an IANA timezone
+func TimeBetweenIn(key, start, end, tz string) Condition {
+	return leaf(constants.ConditionTimeBetween, key, map[string]interface{}{"range": []interface{}{start, end}, "tz": tz})
+}
+
+// TimeOfDay matches an exact "HH:MM" time
+func TimeOfDay(key, hourMinute string) Condition {
+	return leaf(constants.ConditionTimeOfDay, key, hourMinute)
+}
+
+func DayOfWeek(key string, days ...time.Weekday) Condition {
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = day.String()
+	}
+	return leaf("DayOfWeek", key, list(names))
+}
+
+func IsBusinessHours(key string, value bool) Condition {
+	return leaf(constants.ConditionIsBusinessHours, key, value)
+}
+
+func IsHoliday(key string, value bool) Condition {
+	return leaf(constants.ConditionIsHoliday, key, value)
+}
+
+// AuthAgeLessThan requires the subject to have authenticated less than maxAge before the request
+func AuthAgeLessThan(key string, maxAge time.Duration) Condition {
+	return leaf(constants.ConditionAuthAgeLessThan, key, maxAge.String())
+}
+
+// Array conditions
+
+func ArrayContains(key string, value interface{}) Condition {
+	return leaf("ArrayContains", key, value)
+}
+
+func ArrayNotContains(key string, value interface{}) Condition {
+	return leaf("ArrayNotContains", key, value)
+}
+
+// ArraySize matches arrays with exactly size elements
+func ArraySize(key string, size int) Condition {
+	return leaf("ArraySize", key, size)
+}
+
+// Network and certificate conditions
+
+// IPInRange matches IP addresses inside any of the CIDRs
+func IPInRange(key string, cidrs ...string) Condition {
+	return leaf("IPInRange", key, list(cidrs))
+}
+
+func IPNotInRange(key string, cidrs ...string) Condition {
+	return leaf("IPNotInRange", key, list(cidrs))
+}
+
+func IsInternalIP(key string, value bool) Condition {
+	return leaf("IsInternalIP", key, value)
+}
+
+// IssuedByCA matches client certificates issued by any of the CA fingerprints or subject DNs
+func IssuedByCA(key string, cas ...string) Condition {
+	return leaf(constants.ConditionIssuedByCA, key, oneOrList(cas))
+}
+
+// ResourceTag requires the resource to carry tag with one of values; no values accepts any value
+func ResourceTag(tag string, values ...string) Condition {
+	if len(values) == 0 {
+		return leaf(constants.ConditionResourceTag, tag, "*")
+	}
+	return leaf(constants.ConditionResourceTag, tag, oneOrList(values))
+}
+
+// Quota conditions
+
+// DailyQuotaBelow permits at most limit requests per subject and day on counter
+func DailyQuotaBelow(counter string, limit int) Condition {
+	return leaf(constants.ConditionDailyQuotaBelow, counter, limit)
+}
+
+// RequestRateBelow permits at most limit requests per subject within window on counter
+func RequestRateBelow(counter string, limit int, window time.Duration) Condition {
+	return leaf(constants.ConditionRequestRateBelow, counter, map[string]interface{}{"limit": limit, "window": window.String()})
+}
+
+// Logical conditions
+
+// And matches when every condition matches
+func And(conditions ...Condition) Condition {
+	return Condition{string(constants.ConditionAnd): blocks(conditions)}
+}
+
+// Or matches when any condition matches
+func Or(conditions ...Condition) Condition {
+	return Condition{string(constants.ConditionOr): blocks(conditions)}
+}
+
+// Not matches when condition does not
+func Not(condition Condition) Condition {
+	return Condition{string(constants.ConditionNot): condition.Map()}
+}
+
+func blocks(conditions []Condition) []interface{} {
+	items := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		items[i] = condition.Map()
+	}
+	return items
+}
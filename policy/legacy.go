@@ -0,0 +1,272 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/models"
+	"abac_go_example/policy/cond"
+)
+
+// LegacyPriorityTagPrefix tags converted policies with their legacy priority ("legacy-priority=10").
+// Statements are combined deny-overrides, so the priority no longer orders evaluation.
+const LegacyPriorityTagPrefix = "legacy-priority="
+
+// LegacyPolicy is a policy in the pre-statement format: one effect, rules that must all match,
+// and action/resource pattern lists
+type LegacyPolicy struct {
+	ID               string                 `json:"id"`
+	PolicyName       string                 `json:"policy_name"`
+	Description      string                 `json:"description"`
+	Effect           string                 `json:"effect"` // "permit" or "deny"
+	Enabled          bool                   `json:"enabled"`
+	Version          interface{}            `json:"version"` // Legacy versions are numbers
+	Priority         int                    `json:"priority"`
+	Conditions       map[string]interface{} `json:"conditions"` // Statement Condition block, ANDed with the rules
+	Rules            []models.PolicyRule    `json:"rules"`
+	Actions          []string               `json:"actions"`
+	ResourcePatterns []string               `json:"resource_patterns"`
+}
+
+// legacyTargetPrefixes maps rule target types to the evaluation context namespace of their attributes
+var legacyTargetPrefixes = map[string]string{
+	"subject":     "user.",
+	"resource":    "resource.",
+	"environment": "environment.",
+}
+
+// legacyActionPaths maps the attribute paths of action rules to their evaluation context key
+var legacyActionPaths = map[string]string{
+	"name":            constants.ContextKeyRequestAction,
+	"action_name":     constants.ContextKeyRequestAction,
+	"category":        constants.ContextKeyActionCategory,
+	"action_category": constants.ContextKeyActionCategory,
+}
+
+// IsLegacyDocument reports whether a policy JSON object uses the legacy format: rules, actions or
+// resource_patterns and no statement
+func IsLegacyDocument(data []byte) bool {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return false
+	}
+	if _, hasStatement := keys["statement"]; hasStatement {
+		return false
+	}
+	for _, key := range []string{"rules", "actions", "resource_patterns"} {
+		if _, ok := keys[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodePolicy decodes a policy JSON object in the statement format, converting legacy policies
+func DecodePolicy(data []byte) (*models.Policy, error) {
+	if IsLegacyDocument(data) {
+		var legacy LegacyPolicy
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		return FromLegacy(&legacy)
+	}
+
+	var policy models.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FromLegacy converts a legacy policy to a single-statement policy. Rules (in RuleOrder) and
+// Conditions are ANDed into the statement Condition with the operator semantics of
+// conditions.LegacyCondition; rules that cannot be translated (e.g. geo-fencing) fail the
+// conversion rather than being dropped, since dropping a rule would widen access.
+func FromLegacy(legacy *LegacyPolicy) (*models.Policy, error) {
+	statement := NewStatement().Sid(legacySid(legacy.ID)).
+		Actions(legacy.Actions...).
+		Resources(legacy.ResourcePatterns...)
+
+	switch strings.ToLower(legacy.Effect) {
+	case "permit", "allow", "":
+		statement.Allow()
+	case "deny":
+		statement.Deny()
+	default:
+		return nil, fmt.Errorf("legacy policy %s: unknown effect %q", legacy.ID, legacy.Effect)
+	}
+
+	if len(legacy.Conditions) > 0 {
+		for operator := range legacy.Conditions {
+			if !constants.ResolveOperator(operator).Known {
+				return nil, fmt.Errorf("legacy policy %s: unknown condition operator %q", legacy.ID, operator)
+			}
+		}
+		statement.Where(cond.Condition(legacy.Conditions))
+	}
+
+	rules := append([]models.PolicyRule(nil), legacy.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].RuleOrder < rules[j].RuleOrder })
+	var problems []error
+	for i, rule := range rules {
+		ruleConditions, err := LegacyRuleConditions(rule)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("rule %s: %w", legacyRuleName(rule, i), err))
+			continue
+		}
+		statement.Where(ruleConditions...)
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, fmt.Errorf("legacy policy %s: %w", legacy.ID, err)
+	}
+
+	builder := New(legacy.ID, legacy.PolicyName).
+		Version(legacyVersion(legacy.Version)).
+		Description(legacy.Description).
+		Tags(fmt.Sprintf("%s%d", LegacyPriorityTagPrefix, legacy.Priority)).
+		Statements(statement)
+	if !legacy.Enabled {
+		builder.Disabled()
+	}
+	return builder.Build()
+}
+
+// LegacyRuleConditions translates one legacy rule, including its time windows and location
+// restrictions, into statement conditions that must all match
+func LegacyRuleConditions(rule models.PolicyRule) ([]cond.Condition, error) {
+	attributePath, err := legacyAttributePath(rule)
+	if err != nil {
+		return nil, err
+	}
+	comparison, err := conditions.LegacyCondition(attributePath, rule.Operator, rule.ExpectedValue)
+	if err != nil {
+		return nil, err
+	}
+	if rule.IsNegative {
+		comparison = cond.Not(cond.Condition(comparison))
+	}
+	translated := []cond.Condition{cond.Condition(comparison)}
+
+	if len(rule.TimeWindows) > 0 {
+		windows := make([]cond.Condition, len(rule.TimeWindows))
+		for i, window := range rule.TimeWindows {
+			windows[i] = legacyTimeWindow(window)
+		}
+		translated = append(translated, cond.Or(windows...))
+	}
+	if rule.Location != nil {
+		location, err := legacyLocation(rule.Location)
+		if err != nil {
+			return nil, err
+		}
+		translated = append(translated, location...)
+	}
+	return translated, nil
+}
+
+// legacyAttributePath qualifies a rule's attribute path with the namespace of its target type
+func legacyAttributePath(rule models.PolicyRule) (string, error) {
+	if rule.TargetType == "action" {
+		if key, ok := legacyActionPaths[rule.AttributePath]; ok {
+			return key, nil
+		}
+		return "", fmt.Errorf("unsupported action attribute %q", rule.AttributePath)
+	}
+	prefix, ok := legacyTargetPrefixes[rule.TargetType]
+	if !ok {
+		return "", fmt.Errorf("unknown target type %q", rule.TargetType)
+	}
+	if rule.AttributePath == "" {
+		return "", fmt.Errorf("attribute path is required")
+	}
+	return prefix + rule.AttributePath, nil
+}
+
+// legacyTimeWindow matches the clock range (in the window's timezone), the weekdays and none of
+// the excluded dates. Weekdays and dates are those of the evaluation time, not converted to the timezone.
+func legacyTimeWindow(window models.TimeWindow) cond.Condition {
+	var parts []cond.Condition
+	if window.StartTime != "" && window.EndTime != "" {
+		if window.Timezone != "" {
+			parts = append(parts, cond.TimeBetweenIn("environment.time_of_day", window.StartTime, window.EndTime, window.Timezone))
+		} else {
+			parts = append(parts, cond.TimeBetween("environment.time_of_day", window.StartTime, window.EndTime))
+		}
+	}
+	if len(window.DaysOfWeek) > 0 {
+		days := make([]interface{}, len(window.DaysOfWeek))
+		for i, day := range window.DaysOfWeek {
+			days[i] = day
+		}
+		parts = append(parts, cond.Condition{"DayOfWeek": map[string]interface{}{"environment.day_of_week": days}})
+	}
+	if len(window.ExcludeDates) > 0 {
+		excluded := make([]cond.Condition, len(window.ExcludeDates))
+		for i, date := range window.ExcludeDates {
+			excluded[i] = cond.StringLike("environment.timestamp", date+"%")
+		}
+		parts = append(parts, cond.Not(cond.Or(excluded...)))
+	}
+	return cond.And(parts...)
+}
+
+// legacyLocation translates allowed countries, regions and IP ranges
+func legacyLocation(location *models.LocationCondition) ([]cond.Condition, error) {
+	if location.GeoFencing != nil {
+		return nil, fmt.Errorf("geo-fencing has no statement equivalent")
+	}
+
+	var translated []cond.Condition
+	for key, allowed := range map[string][]string{
+		"environment.country": location.AllowedCountries,
+		"environment.region":  location.AllowedRegions,
+	} {
+		if len(allowed) == 0 {
+			continue
+		}
+		anyOf := make([]cond.Condition, len(allowed))
+		for i, value := range allowed {
+			anyOf[i] = cond.StringEquals(key, value)
+		}
+		translated = append(translated, cond.Or(anyOf...))
+	}
+	if len(location.IPRanges) > 0 {
+		translated = append(translated, cond.IPInRange("environment.client_ip", location.IPRanges...))
+	}
+	return translated, nil
+}
+
+func legacySid(id string) string {
+	sid := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, id)
+	return "Legacy" + sid
+}
+
+func legacyRuleName(rule models.PolicyRule, index int) string {
+	if rule.ID != "" {
+		return rule.ID
+	}
+	return fmt.Sprintf("[%d]", index)
+}
+
+func legacyVersion(version interface{}) string {
+	switch v := version.(type) {
+	case nil:
+		return "1"
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/models"
+)
+
+const legacyDocument = `{
+	"id": "pol-legacy-1",
+	"policy_name": "Engineering read (legacy)",
+	"effect": "permit",
+	"enabled": true,
+	"version": 3,
+	"priority": 10,
+	"actions": ["document:read"],
+	"resource_patterns": ["api:documents:*"],
+	"rules": [
+		{"target_type": "subject", "attribute_path": "attributes.level", "operator": "gte", "expected_value": 3, "rule_order": 2},
+		{"target_type": "subject", "attribute_path": "department", "operator": "eq", "expected_value": "Engineering", "rule_order": 1},
+		{"target_type": "resource", "attribute_path": "classification", "operator": "eq", "expected_value": "secret", "is_negative": true,
+		 "time_windows": [{"start_time": "09:00", "end_time": "17:00", "days_of_week": ["monday", "friday"]}],
+		 "location": {"ip_ranges": ["10.0.0.0/8"]}}
+	]
+}`
+
+func TestDecodeLegacyPolicy(t *testing.T) {
+	if !IsLegacyDocument([]byte(legacyDocument)) || IsLegacyDocument([]byte(`{"id": "p", "statement": []}`)) {
+		t.Fatal("Expected legacy detection by rules/actions/resource_patterns without statement")
+	}
+
+	converted, err := DecodePolicy([]byte(legacyDocument))
+	if err != nil {
+		t.Fatalf("DecodePolicy: %v", err)
+	}
+	if converted.Version != "3" || !converted.Enabled || len(converted.Statement) != 1 {
+		t.Fatalf("Unexpected policy %+v", converted)
+	}
+	if len(converted.Tags) != 1 || converted.Tags[0] != LegacyPriorityTagPrefix+"10" {
+		t.Errorf("Expected priority tag, got %v", converted.Tags)
+	}
+	statement := converted.Statement[0]
+	if statement.Effect != EffectAllow || statement.Sid != "Legacypollegacy1" || statement.Action.Single != "document:read" {
+		t.Errorf("Unexpected statement %+v", statement)
+	}
+
+	evaluator := conditions.NewEnhancedConditionEvaluator()
+	context := func(level int, classification, day, clientIP string) map[string]interface{} {
+		return map[string]interface{}{
+			"user":        map[string]interface{}{"department": "Engineering", "attributes": map[string]interface{}{"level": level}},
+			"resource":    map[string]interface{}{"classification": classification},
+			"environment": map[string]interface{}{"time_of_day": "10:30", "day_of_week": day, "client_ip": clientIP},
+		}
+	}
+	tests := []struct {
+		name    string
+		context map[string]interface{}
+		want    bool
+	}{
+		{"all rules match", context(5, "internal", "Monday", "10.1.2.3"), true},
+		{"level too low", context(2, "internal", "Monday", "10.1.2.3"), false},
+		{"negated rule", context(5, "secret", "Monday", "10.1.2.3"), false},
+		{"outside time window", context(5, "internal", "Sunday", "10.1.2.3"), false},
+		{"outside ip range", context(5, "internal", "Friday", "192.168.1.1"), false},
+	}
+	for _, tt := range tests {
+		if got := evaluator.EvaluateConditions(statement.Condition, tt.context); got != tt.want {
+			t.Errorf("%s: expected %v for %v", tt.name, tt.want, statement.Condition)
+		}
+	}
+}
+
+func TestFromLegacyErrors(t *testing.T) {
+	base := func() *LegacyPolicy {
+		return &LegacyPolicy{ID: "pol-1", PolicyName: "Legacy", Effect: "deny", Enabled: true,
+			Actions: []string{"read"}, ResourcePatterns: []string{"*"}}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*LegacyPolicy)
+		want   string
+	}{
+		{"effect", func(p *LegacyPolicy) { p.Effect = "maybe" }, "unknown effect"},
+		{"no actions", func(p *LegacyPolicy) { p.Actions = nil }, "Action"},
+		{"target type", func(p *LegacyPolicy) {
+			p.Rules = []models.PolicyRule{{ID: "r1", TargetType: "tenant", AttributePath: "id", Operator: "eq", ExpectedValue: "a"}}
+		}, "rule r1: unknown target type"},
+		{"operator", func(p *LegacyPolicy) {
+			p.Rules = []models.PolicyRule{{TargetType: "subject", AttributePath: "level", Operator: "between", ExpectedValue: 1}}
+		}, "unknown legacy operator"},
+		{"geo-fencing", func(p *LegacyPolicy) {
+			p.Rules = []models.PolicyRule{{TargetType: "subject", AttributePath: "level", Operator: "exists",
+				Location: &models.LocationCondition{GeoFencing: &models.GeoFenceCondition{}}}}
+		}, "geo-fencing"},
+		{"conditions", func(p *LegacyPolicy) { p.Conditions = map[string]interface{}{"Matches": map[string]interface{}{}} }, "unknown condition operator"},
+	}
+	for _, tt := range tests {
+		legacy := base()
+		tt.modify(legacy)
+		if _, err := FromLegacy(legacy); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	converted, err := FromLegacy(base())
+	if err != nil || converted.Statement[0].Effect != EffectDeny || converted.Version != "1" {
+		t.Errorf("Expected a deny statement with default version, got %+v, %v", converted, err)
+	}
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+	"abac_go_example/schema"
+)
+
+// Builder assembles a models.Policy from statement builders
+type Builder struct {
+	policy     models.Policy
+	statements []*StatementBuilder
+}
+
+// New starts an enabled policy; a version and at least one statement are required
+func New(id, name string) *Builder {
+	return &Builder{policy: models.Policy{ID: id, PolicyName: name, Enabled: true}}
+}
+
+func (b *Builder) Version(version string) *Builder {
+	b.policy.Version = version
+	return b
+}
+
+func (b *Builder) Description(description string) *Builder {
+	b.policy.Description = description
+	return b
+}
+
+// Namespace scopes the policy to one service's evaluations
+func (b *Builder) Namespace(namespace string) *Builder {
+	b.policy.Namespace = namespace
+	return b
+}
+
+// Tags labels the policy for bulk selection and search
+func (b *Builder) Tags(tags ...string) *Builder {
+	b.policy.Tags = append(b.policy.Tags, tags...)
+	return b
+}
+
+// Owner names the team accountable for the policy
+func (b *Builder) Owner(owner string) *Builder {
+	b.policy.Owner = owner
+	return b
+}
+
+func (b *Builder) Disabled() *Builder {
+	b.policy.Enabled = false
+	return b
+}
+
+// ValidBetween bounds the validity window; a zero time leaves that side unbounded
+func (b *Builder) ValidBetween(from, until time.Time) *Builder {
+	b.policy.EffectiveFrom, b.policy.ExpiresAt = nil, nil
+	if !from.IsZero() {
+		b.policy.EffectiveFrom = &from
+	}
+	if !until.IsZero() {
+		b.policy.ExpiresAt = &until
+	}
+	return b
+}
+
+// Rollout enforces the policy for percent of subjects and evaluates it in shadow for the others
+func (b *Builder) Rollout(percent int) *Builder {
+	b.policy.RolloutPercent = &percent
+	return b
+}
+
+// Statements appends statements in evaluation order
+func (b *Builder) Statements(statements ...*StatementBuilder) *Builder {
+	b.statements = append(b.statements, statements...)
+	return b
+}
+
+// Build builds every statement and validates the policy against the policy JSON Schema,
+// the same check the HTTP API and importer apply
+func (b *Builder) Build() (*models.Policy, error) {
+	policy := b.policy
+	policy.Statement = make(models.JSONStatements, 0, len(b.statements))
+	for i, statementBuilder := range b.statements {
+		statement, err := statementBuilder.Build()
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: statement[%d]: %w", policy.ID, i, err)
+		}
+		policy.Statement = append(policy.Statement, statement)
+	}
+
+	document, err := json.Marshal(&policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy %s: %w", policy.ID, err)
+	}
+	if errs := schema.ValidatePolicy(document); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, validationErr := range errs {
+			messages[i] = validationErr.Error()
+		}
+		return nil, fmt.Errorf("policy %s does not match schema: %s", policy.ID, strings.Join(messages, "; "))
+	}
+	if policy.EffectiveFrom != nil && policy.ExpiresAt != nil && !policy.ExpiresAt.After(*policy.EffectiveFrom) {
+		return nil, fmt.Errorf("policy %s: expires_at must be after effective_from", policy.ID)
+	}
+	if policy.RolloutPercent != nil && (*policy.RolloutPercent < 0 || *policy.RolloutPercent > 100) {
+		return nil, fmt.Errorf("policy %s: rollout_percent must be between 0 and 100", policy.ID)
+	}
+	return &policy, nil
+}
+
+// MustBuild is Build for policies known to be valid, e.g. in tests; it panics on errors
+func (b *Builder) MustBuild() *models.Policy {
+	policy, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
@@ -0,0 +1,168 @@
+package policy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/evaluator/conditions"
+	"abac_go_example/policy/cond"
+)
+
+func TestStatementBuilder(t *testing.T) {
+	statement := NewStatement().Sid("EngineeringRead").Allow().
+		Actions("document:read").
+		Resources("api:documents:*", "api:reports:*").
+		Where(cond.StringEquals("user.department", "Engineering"), cond.NumericGreaterThanEquals("user.level", 3)).
+		Obligation("audit", "full").
+		ConstrainFields("title", "summary").
+		ReadOnly().
+		MustBuild()
+
+	data, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	json.Unmarshal(data, &got)
+	json.Unmarshal([]byte(`{
+		"Sid": "EngineeringRead",
+		"Effect": "Allow",
+		"Action": "document:read",
+		"Resource": ["api:documents:*", "api:reports:*"],
+		"NotResource": null,
+		"Fields": null,
+		"Condition": {
+			"StringEquals": {"user.department": "Engineering"},
+			"NumericGreaterThanEquals": {"user.level": 3}
+		},
+		"Obligations": {"audit": "full"},
+		"Constraints": {"fields": ["title", "summary"], "read_only": true}
+	}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected statement JSON %s", data)
+	}
+}
+
+func TestStatementBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *StatementBuilder
+		want    string
+	}{
+		{"no effect", NewStatement().Actions("read").Resources("*"), "effect is required"},
+		{"no actions", NewStatement().Allow().Resources("*"), "Action requires at least one pattern"},
+		{"empty resource", NewStatement().Deny().Actions("read").Resources(""), "Resource[0] is empty"},
+		{"mask without fields", NewStatement().Sid("M").Mask().Actions("read").Resources("*"), "statement M: Mask effect requires Fields"},
+		{"constrained deny", NewStatement().Deny().Actions("read").Resources("*").ReadOnly(), "Constraints require a resource-level Allow statement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestMergeConditions(t *testing.T) {
+	same := NewStatement().Allow().Actions("read").Resources("*").
+		Where(cond.StringEquals("user.department", "Engineering")).
+		Where(cond.StringEquals("resource.owner", "user-001")).
+		MustBuild()
+	if len(same.Condition) != 1 || len(same.Condition["StringEquals"].(map[string]interface{})) != 2 {
+		t.Errorf("expected both attributes under one StringEquals, got %v", same.Condition)
+	}
+
+	repeated := NewStatement().Allow().Actions("read").Resources("*").
+		Where(cond.StringNotEquals("user.status", "suspended"), cond.StringNotEquals("user.status", "locked")).
+		MustBuild()
+	and, ok := repeated.Condition["And"].([]interface{})
+	if !ok || len(and) != 2 {
+		t.Errorf("expected repeated attributes in an And list, got %v", repeated.Condition)
+	}
+
+	// Merging must not modify the conditions passed in
+	shared := cond.StringEquals("user.department", "Engineering")
+	NewStatement().Allow().Actions("read").Resources("*").Where(shared, cond.StringEquals("user.role", "admin")).MustBuild()
+	if len(shared["StringEquals"].(map[string]interface{})) != 1 {
+		t.Errorf("expected the shared condition to stay unchanged, got %v", shared)
+	}
+}
+
+func TestConditionsEvaluate(t *testing.T) {
+	evaluator := conditions.NewEnhancedConditionEvaluator()
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"department": "Engineering",
+			"level":      5,
+			"roles":      []interface{}{"developer", "reviewer"},
+			"status":     "active",
+		},
+		"environment": map[string]interface{}{"client_ip": "10.1.2.3"},
+	}
+
+	tests := []struct {
+		name      string
+		condition cond.Condition
+		want      bool
+	}{
+		{"string equals", cond.StringEquals("user.department", "Engineering"), true},
+		{"string like", cond.StringLike("user.department", "Eng%"), true},
+		{"numeric between", cond.NumericBetween("user.level", 1, 4), false},
+		{"array contains", cond.ArrayContains("user.roles", "reviewer"), true},
+		{"ip in range", cond.IPInRange("environment.client_ip", "192.168.0.0/16", "10.0.0.0/8"), true},
+		{"and", cond.And(cond.StringEquals("user.department", "Engineering"), cond.NumericGreaterThan("user.level", 3)), true},
+		{"or", cond.Or(cond.StringEquals("user.department", "Finance"), cond.ArrayContains("user.roles", "admin")), false},
+		{"not", cond.Not(cond.StringEquals("user.status", "suspended")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement := NewStatement().Allow().Actions("read").Resources("*").Where(tt.condition).MustBuild()
+			if got := evaluator.EvaluateConditions(statement.Condition, context); got != tt.want {
+				t.Errorf("expected %v for %v", tt.want, statement.Condition)
+			}
+		})
+	}
+}
+
+func TestPolicyBuilder(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy, err := New("pol-builder", "Engineering Documents").
+		Version("2024-10-21").
+		Namespace("document-service").
+		Tags("domain=engineering", "sox").
+		Owner("team-docs").
+		ValidBetween(from, time.Time{}).
+		Statements(
+			NewStatement().Sid("Read").Allow().Actions("document:read").Resources("api:documents:*").
+				Where(cond.StringEquals("user.department", "Engineering")),
+			NewStatement().Sid("MaskSalary").Mask("salary").Actions("document:read").Resources("api:documents:*"),
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if !policy.Enabled || len(policy.Statement) != 2 || policy.EffectiveFrom == nil || policy.ExpiresAt != nil ||
+		len(policy.Tags) != 2 || policy.Owner != "team-docs" {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+	if policy.Statement[1].Effect != EffectMask || !policy.Statement[1].IsFieldLevel() {
+		t.Errorf("expected a field-level Mask statement, got %+v", policy.Statement[1])
+	}
+
+	if _, err := New("pol-x", "No Version").Statements(NewStatement().Allow().Actions("read").Resources("*")).Build(); err == nil ||
+		!strings.Contains(err.Error(), "does not match schema") {
+		t.Errorf("expected a schema error for a missing version, got %v", err)
+	}
+	if _, err := New("pol-x", "Bad").Version("1").Statements(NewStatement().Actions("read")).Build(); err == nil ||
+		!strings.Contains(err.Error(), "statement[0]") {
+		t.Errorf("expected the statement error, got %v", err)
+	}
+	if _, err := New("pol-x", "Window").Version("1").ValidBetween(from, from).
+		Statements(NewStatement().Allow().Actions("read").Resources("*")).Build(); err == nil {
+		t.Error("expected an empty validity window to fail")
+	}
+}
@@ -0,0 +1,258 @@
+// Package policy provides fluent builders for policy statements and documents, so programmatic
+// policy creation does not hand-assemble nested condition maps:
+//
+//	statement := policy.NewStatement().Allow().
+//		Actions("document:read").
+//		Resources("api:documents:*").
+//		Where(cond.StringEquals("user.department", "Engineering")).
+//		MustBuild()
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+	"abac_go_example/policy/cond"
+)
+
+// Statement effects
+const (
+	EffectAllow = "Allow"
+	EffectDeny  = "Deny"
+	EffectMask  = "Mask"
+)
+
+// StatementBuilder assembles a models.PolicyStatement
+type StatementBuilder struct {
+	sid          string
+	effect       string
+	actions      []string
+	resources    []string
+	notResources []string
+	fields       []string
+	conditions   []cond.Condition
+	obligations  map[string]string
+	constraints  *models.Constraints
+}
+
+// NewStatement starts an empty statement; an effect, actions and resources are required
+func NewStatement() *StatementBuilder {
+	return &StatementBuilder{}
+}
+
+// Sid sets the statement ID reported in traces and decisions
+func (b *StatementBuilder) Sid(sid string) *StatementBuilder {
+	b.sid = sid
+	return b
+}
+
+func (b *StatementBuilder) Allow() *StatementBuilder {
+	b.effect = EffectAllow
+	return b
+}
+
+func (b *StatementBuilder) Deny() *StatementBuilder {
+	b.effect = EffectDeny
+	return b
+}
+
+// Mask redacts the given field patterns instead of denying the resource
+func (b *StatementBuilder) Mask(fields ...string) *StatementBuilder {
+	b.effect = EffectMask
+	return b.Fields(fields...)
+}
+
+// Actions appends action patterns (e.g. "document:read", "document:*")
+func (b *StatementBuilder) Actions(actions ...string) *StatementBuilder {
+	b.actions = append(b.actions, actions...)
+	return b
+}
+
+// Resources appends resource patterns (e.g. "api:documents:*")
+func (b *StatementBuilder) Resources(resources ...string) *StatementBuilder {
+	b.resources = append(b.resources, resources...)
+	return b
+}
+
+// NotResources appends resource patterns excluded from the statement
+func (b *StatementBuilder) NotResources(resources ...string) *StatementBuilder {
+	b.notResources = append(b.notResources, resources...)
+	return b
+}
+
+// Fields appends field patterns, making the statement field-level
+func (b *StatementBuilder) Fields(fields ...string) *StatementBuilder {
+	b.fields = append(b.fields, fields...)
+	return b
+}
+
+// Where adds conditions; all conditions of a statement must match
+func (b *StatementBuilder) Where(conditions ...cond.Condition) *StatementBuilder {
+	b.conditions = append(b.conditions, conditions...)
+	return b
+}
+
+// Obligation attaches an obligation to the decisions the statement contributes to,
+// e.g. Obligation(constants.ObligationAudit, constants.AuditLevelFull)
+func (b *StatementBuilder) Obligation(name, value string) *StatementBuilder {
+	if b.obligations == nil {
+		b.obligations = make(map[string]string)
+	}
+	b.obligations[name] = value
+	return b
+}
+
+// ConstrainFields limits the permits of an Allow statement to the given field patterns,
+// e.g. ConstrainFields("id", "title") for "permit but only columns id, title"
+func (b *StatementBuilder) ConstrainFields(fields ...string) *StatementBuilder {
+	b.constraint().Fields = append(b.constraint().Fields, fields...)
+	return b
+}
+
+// ReadOnly limits the permits of an Allow statement to non-mutating operations
+func (b *StatementBuilder) ReadOnly() *StatementBuilder {
+	b.constraint().ReadOnly = true
+	return b
+}
+
+func (b *StatementBuilder) constraint() *models.Constraints {
+	if b.constraints == nil {
+		b.constraints = &models.Constraints{}
+	}
+	return b.constraints
+}
+
+// Build validates and returns the statement
+func (b *StatementBuilder) Build() (models.PolicyStatement, error) {
+	var problems []error
+	switch b.effect {
+	case EffectAllow, EffectDeny:
+	case EffectMask:
+		if len(b.fields) == 0 {
+			problems = append(problems, fmt.Errorf("Mask effect requires Fields"))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("effect is required (Allow, Deny or Mask)"))
+	}
+	problems = append(problems, checkPatterns("Action", b.actions, true), checkPatterns("Resource", b.resources, true),
+		checkPatterns("NotResource", b.notResources, false), checkPatterns("Fields", b.fields, false))
+	if b.constraints != nil {
+		if b.effect != EffectAllow || len(b.fields) > 0 {
+			problems = append(problems, fmt.Errorf("Constraints require a resource-level Allow statement"))
+		}
+		problems = append(problems, checkPatterns("Constraints.Fields", b.constraints.Fields, false))
+	}
+	if err := errors.Join(problems...); err != nil {
+		if b.sid != "" {
+			return models.PolicyStatement{}, fmt.Errorf("statement %s: %w", b.sid, err)
+		}
+		return models.PolicyStatement{}, err
+	}
+
+	return models.PolicyStatement{
+		Sid:         b.sid,
+		Effect:      b.effect,
+		Action:      patterns(b.actions),
+		Resource:    patterns(b.resources),
+		NotResource: patterns(b.notResources),
+		Condition:   mergeConditions(b.conditions),
+		Fields:      patterns(b.fields),
+		Obligations: b.obligations,
+		Constraints: b.constraints,
+	}, nil
+}
+
+// MustBuild is Build for statements known to be valid, e.g. in tests; it panics on errors
+func (b *StatementBuilder) MustBuild() models.PolicyStatement {
+	statement, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return statement
+}
+
+// checkPatterns rejects empty patterns and, when required, an empty list
+func checkPatterns(field string, values []string, required bool) error {
+	if required && len(values) == 0 {
+		return fmt.Errorf("%s requires at least one pattern", field)
+	}
+	for i, value := range values {
+		if value == "" {
+			return fmt.Errorf("%s[%d] is empty", field, i)
+		}
+	}
+	return nil
+}
+
+// patterns stores one pattern as a string and several as an array, like hand-written policies
+func patterns(values []string) models.JSONActionResource {
+	switch len(values) {
+	case 0:
+		return models.JSONActionResource{}
+	case 1:
+		return models.JSONActionResource{Single: values[0]}
+	default:
+		return models.JSONActionResource{Multiple: append([]string(nil), values...)}
+	}
+}
+
+// mergeConditions combines condition blocks into one Condition map. Blocks with distinct operators,
+// or the same operator on distinct attributes, share the top level ({"StringEquals": {a, b}});
+// anything else (repeated attributes, several And/Or/Not blocks) is wrapped in an And list.
+func mergeConditions(conditions []cond.Condition) models.JSONMap {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	merged := models.JSONMap{}
+	for _, condition := range conditions {
+		for operator, operands := range condition {
+			existing, exists := merged[operator]
+			if !exists {
+				merged[operator] = copyOperands(operands)
+				continue
+			}
+			existingMap, ok1 := existing.(map[string]interface{})
+			operandMap, ok2 := operands.(map[string]interface{})
+			if !ok1 || !ok2 || isLogical(operator) || overlaps(existingMap, operandMap) {
+				return models.JSONMap(cond.And(conditions...))
+			}
+			for key, value := range operandMap {
+				existingMap[key] = value
+			}
+		}
+	}
+	return merged
+}
+
+// copyOperands copies attribute maps so merging never modifies the caller's conditions
+func copyOperands(operands interface{}) interface{} {
+	operandMap, ok := operands.(map[string]interface{})
+	if !ok {
+		return operands
+	}
+	copied := make(map[string]interface{}, len(operandMap))
+	for key, value := range operandMap {
+		copied[key] = value
+	}
+	return copied
+}
+
+func isLogical(operator string) bool {
+	switch constants.ConditionOperatorType(operator).Canonical() {
+	case constants.ConditionAnd, constants.ConditionOr, constants.ConditionNot:
+		return true
+	}
+	return false
+}
+
+func overlaps(a, b map[string]interface{}) bool {
+	for key := range b {
+		if _, exists := a[key]; exists {
+			return true
+		}
+	}
+	return false
+}
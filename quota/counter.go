@@ -0,0 +1,84 @@
+package quota
+
+import (
+	"sync"
+	"time"
+)
+
+// CounterProvider stores the counters behind quota and rate conditions.
+// Keys already include the time window, so implementations only need expiring counters.
+type CounterProvider interface {
+	// Get returns the current counter value, 0 when the counter does not exist or has expired
+	Get(key string) (int64, error)
+	// Increment adds one to the counter, creating it with the given TTL when missing, and returns the new value
+	Increment(key string, ttl time.Duration) (int64, error)
+}
+
+// counterEntry is a single in-memory counter
+type counterEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// MemoryCounterProvider keeps counters in process memory.
+// Counters are not shared between instances; use RedisCounterProvider for multi-instance deployments.
+type MemoryCounterProvider struct {
+	mu       sync.Mutex
+	counters map[string]*counterEntry
+	now      func() time.Time
+}
+
+// NewMemoryCounterProvider creates an empty in-memory counter provider
+func NewMemoryCounterProvider() *MemoryCounterProvider {
+	return &MemoryCounterProvider{
+		counters: make(map[string]*counterEntry),
+		now:      time.Now,
+	}
+}
+
+// Get returns the current value of key
+func (p *MemoryCounterProvider) Get(key string) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.counters[key]
+	if !ok {
+		return 0, nil
+	}
+	if !p.now().Before(entry.expiresAt) {
+		delete(p.counters, key)
+		return 0, nil
+	}
+	return entry.value, nil
+}
+
+// Increment adds one to key and returns the new value
+func (p *MemoryCounterProvider) Increment(key string, ttl time.Duration) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	entry, ok := p.counters[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		entry = &counterEntry{expiresAt: now.Add(ttl)}
+		p.counters[key] = entry
+	}
+	entry.value++
+	return entry.value, nil
+}
+
+// Purge removes expired counters and returns how many were removed
+func (p *MemoryCounterProvider) Purge() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	removed := 0
+	for key, entry := range p.counters {
+		if !now.Before(entry.expiresAt) {
+			delete(p.counters, key)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,171 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Kind distinguishes sliding request rates from calendar-day quotas
+type Kind string
+
+const (
+	// KindRate limits requests per fixed window (RequestRateBelow)
+	KindRate Kind = "rate"
+	// KindDaily limits requests per UTC calendar day (DailyQuotaBelow)
+	KindDaily Kind = "daily"
+)
+
+// Scope selects which request identifiers a counter is keyed by
+type Scope string
+
+const (
+	ScopeSubject         Scope = "subject"
+	ScopeResource        Scope = "resource"
+	ScopeSubjectResource Scope = "subject_resource"
+)
+
+// DefaultRateWindow is used by RequestRateBelow when no window is given
+const DefaultRateWindow = time.Minute
+
+// Limit is one parsed quota or rate condition
+type Limit struct {
+	Kind    Kind
+	Counter string
+	Max     int64
+	Window  time.Duration // KindRate only
+	Scope   Scope
+}
+
+// ParseLimits parses the condition block of a quota operator.
+// Each entry maps a counter name to either a number (the limit) or an object:
+//
+//	{"exports": 100}
+//	{"exports": {"limit": 100, "scope": "subject_resource"}}
+//	{"api_calls": {"limit": 10, "window": "1m"}}
+func ParseLimits(kind Kind, conditions interface{}) ([]Limit, error) {
+	conditionMap, ok := conditions.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("quota conditions must be an object")
+	}
+
+	limits := make([]Limit, 0, len(conditionMap))
+	for counter, spec := range conditionMap {
+		limit, err := parseLimit(kind, counter, spec)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+
+	// Stable order keeps evaluation deterministic
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Counter < limits[j].Counter })
+	return limits, nil
+}
+
+// parseLimit parses a single counter specification
+func parseLimit(kind Kind, counter string, spec interface{}) (Limit, error) {
+	limit := Limit{Kind: kind, Counter: counter, Scope: ScopeSubject}
+	if kind == KindRate {
+		limit.Window = DefaultRateWindow
+	}
+
+	options, isObject := spec.(map[string]interface{})
+	if !isObject {
+		max, err := toInt64(spec)
+		if err != nil {
+			return limit, fmt.Errorf("counter %s: %w", counter, err)
+		}
+		limit.Max = max
+		return limit, validateLimit(limit)
+	}
+
+	max, err := toInt64(options["limit"])
+	if err != nil {
+		return limit, fmt.Errorf("counter %s: limit: %w", counter, err)
+	}
+	limit.Max = max
+
+	if scope, ok := options["scope"].(string); ok {
+		limit.Scope = Scope(scope)
+	}
+
+	if window, ok := options["window"].(string); ok && kind == KindRate {
+		duration, err := time.ParseDuration(window)
+		if err != nil {
+			return limit, fmt.Errorf("counter %s: invalid window %q: %w", counter, window, err)
+		}
+		limit.Window = duration
+	}
+
+	return limit, validateLimit(limit)
+}
+
+// validateLimit checks limit values that cannot be expressed by the parser alone
+func validateLimit(limit Limit) error {
+	if limit.Max <= 0 {
+		return fmt.Errorf("counter %s: limit must be positive", limit.Counter)
+	}
+	if limit.Kind == KindRate && limit.Window <= 0 {
+		return fmt.Errorf("counter %s: window must be positive", limit.Counter)
+	}
+	switch limit.Scope {
+	case ScopeSubject, ScopeResource, ScopeSubjectResource:
+		return nil
+	default:
+		return fmt.Errorf("counter %s: unknown scope %q", limit.Counter, limit.Scope)
+	}
+}
+
+// Key builds the counter key for the window containing now
+func (l Limit) Key(subjectID, resourceID string, now time.Time) string {
+	var scopeKey string
+	switch l.Scope {
+	case ScopeResource:
+		scopeKey = "r=" + resourceID
+	case ScopeSubjectResource:
+		scopeKey = "s=" + subjectID + "|r=" + resourceID
+	default:
+		scopeKey = "s=" + subjectID
+	}
+
+	return fmt.Sprintf("quota:%s:%s:%s:%s", l.Kind, l.Counter, scopeKey, l.bucket(now))
+}
+
+// TTL returns how long a counter created at now must live to cover its window
+func (l Limit) TTL(now time.Time) time.Duration {
+	if l.Kind == KindDaily {
+		day := now.UTC().Truncate(24 * time.Hour)
+		return day.Add(24 * time.Hour).Sub(now)
+	}
+	return l.Window
+}
+
+// bucket identifies the window containing now
+func (l Limit) bucket(now time.Time) string {
+	if l.Kind == KindDaily {
+		return now.UTC().Format("2006-01-02")
+	}
+	return strconv.FormatInt(now.Truncate(l.Window).Unix(), 10)
+}
+
+// toInt64 converts JSON numbers and numeric strings to int64
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("limit must be a number")
+	}
+}
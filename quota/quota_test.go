@@ -0,0 +1,141 @@
+package quota
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCounterProvider(t *testing.T) {
+	provider := NewMemoryCounterProvider()
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	for i := 1; i <= 3; i++ {
+		value, err := provider.Increment("k", time.Minute)
+		if err != nil || value != int64(i) {
+			t.Fatalf("increment %d: got %d, %v", i, value, err)
+		}
+	}
+	if value, _ := provider.Get("k"); value != 3 {
+		t.Errorf("expected 3, got %d", value)
+	}
+	if value, _ := provider.Get("missing"); value != 0 {
+		t.Errorf("expected 0 for missing key, got %d", value)
+	}
+
+	now = now.Add(time.Minute)
+	if value, _ := provider.Get("k"); value != 0 {
+		t.Errorf("expected expired counter to read 0, got %d", value)
+	}
+	if value, _ := provider.Increment("k", time.Minute); value != 1 {
+		t.Errorf("expected expired counter to restart at 1, got %d", value)
+	}
+
+	provider.Increment("other", time.Second)
+	now = now.Add(time.Second)
+	if removed := provider.Purge(); removed != 1 {
+		t.Errorf("expected 1 purged counter, got %d", removed)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(KindRate, map[string]interface{}{
+		"exports":   float64(100),
+		"api_calls": map[string]interface{}{"limit": float64(10), "window": "30s", "scope": "subject_resource"},
+	})
+	if err != nil {
+		t.Fatalf("ParseLimits returned error: %v", err)
+	}
+	if len(limits) != 2 {
+		t.Fatalf("expected 2 limits, got %d", len(limits))
+	}
+
+	apiCalls, exports := limits[0], limits[1]
+	if apiCalls.Max != 10 || apiCalls.Window != 30*time.Second || apiCalls.Scope != ScopeSubjectResource {
+		t.Errorf("unexpected api_calls limit: %+v", apiCalls)
+	}
+	if exports.Max != 100 || exports.Window != DefaultRateWindow || exports.Scope != ScopeSubject {
+		t.Errorf("unexpected exports limit: %+v", exports)
+	}
+
+	invalid := []interface{}{
+		"not-a-number",
+		float64(0),
+		map[string]interface{}{"limit": float64(5), "window": "soon"},
+		map[string]interface{}{"limit": float64(5), "scope": "tenant"},
+	}
+	for _, spec := range invalid {
+		if _, err := ParseLimits(KindRate, map[string]interface{}{"c": spec}); err == nil {
+			t.Errorf("expected error for spec %v", spec)
+		}
+	}
+}
+
+func TestLimitKeyAndTTL(t *testing.T) {
+	now := time.Date(2024, 1, 15, 18, 0, 30, 0, time.UTC)
+
+	daily := Limit{Kind: KindDaily, Counter: "exports", Max: 100, Scope: ScopeSubject}
+	if key := daily.Key("user-1", "doc-1", now); key != "quota:daily:exports:s=user-1:2024-01-15" {
+		t.Errorf("unexpected daily key: %s", key)
+	}
+	if ttl := daily.TTL(now); ttl != 6*time.Hour-30*time.Second {
+		t.Errorf("unexpected daily TTL: %v", ttl)
+	}
+
+	rate := Limit{Kind: KindRate, Counter: "api", Max: 10, Window: time.Minute, Scope: ScopeResource}
+	first := rate.Key("user-1", "doc-1", now)
+	if !strings.Contains(first, ":r=doc-1:") {
+		t.Errorf("expected resource scoped key, got %s", first)
+	}
+	if rate.Key("user-2", "doc-1", now.Add(20*time.Second)) != first {
+		t.Error("expected same key within the window for resource scope")
+	}
+	if rate.Key("user-1", "doc-1", now.Add(time.Minute)) == first {
+		t.Error("expected a new key in the next window")
+	}
+}
+
+// fakeRedis implements RedisClient in memory
+type fakeRedis struct {
+	values map[string]int64
+	ttls   map[string]time.Duration
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return "", false, nil
+	}
+	return strconv.FormatInt(value, 10), true, nil
+}
+
+func (f *fakeRedis) Incr(ctx context.Context, key string) (int64, error) {
+	f.values[key]++
+	return f.values[key], nil
+}
+
+func (f *fakeRedis) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	f.ttls[key] = ttl
+	return nil
+}
+
+func TestRedisCounterProvider(t *testing.T) {
+	client := &fakeRedis{values: map[string]int64{}, ttls: map[string]time.Duration{}}
+	provider := NewRedisCounterProvider(client, "abac:", time.Second)
+
+	provider.Increment("k", time.Hour)
+	provider.Increment("k", time.Hour)
+
+	if value, err := provider.Get("k"); err != nil || value != 2 {
+		t.Errorf("expected 2, got %d (%v)", value, err)
+	}
+	if client.ttls["abac:k"] != time.Hour {
+		t.Errorf("expected TTL to be set on first increment, got %v", client.ttls["abac:k"])
+	}
+	if value, _ := provider.Get("missing"); value != 0 {
+		t.Errorf("expected 0 for missing key, got %d", value)
+	}
+}
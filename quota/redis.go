@@ -0,0 +1,75 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedisClient is the subset of Redis commands used by RedisCounterProvider.
+// It keeps this package free of a Redis driver dependency; wrapping a go-redis client takes a few lines:
+//
+//	func (a adapter) Incr(ctx context.Context, key string) (int64, error) { return a.c.Incr(ctx, key).Result() }
+type RedisClient interface {
+	// Get returns the raw value of key; found is false when the key does not exist
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	// Incr increments key by one and returns the new value
+	Incr(ctx context.Context, key string) (int64, error)
+	// Expire sets the TTL of key
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// RedisCounterProvider stores counters in Redis so limits are shared by every PDP instance
+type RedisCounterProvider struct {
+	client  RedisClient
+	prefix  string
+	timeout time.Duration
+}
+
+// NewRedisCounterProvider creates a Redis-backed counter provider.
+// prefix namespaces the keys (e.g. "abac:"); timeout bounds each Redis call.
+func NewRedisCounterProvider(client RedisClient, prefix string, timeout time.Duration) *RedisCounterProvider {
+	return &RedisCounterProvider{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+// Get returns the current value of key
+func (p *RedisCounterProvider) Get(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	raw, found, err := p.client.Get(ctx, p.prefix+key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read counter %s: %w", key, err)
+	}
+	if !found {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid counter value for %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Increment adds one to key and sets its TTL when the counter was just created
+func (p *RedisCounterProvider) Increment(key string, ttl time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	value, err := p.client.Incr(ctx, p.prefix+key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment counter %s: %w", key, err)
+	}
+	if value == 1 {
+		if err := p.client.Expire(ctx, p.prefix+key, ttl); err != nil {
+			return value, fmt.Errorf("failed to set TTL for counter %s: %w", key, err)
+		}
+	}
+	return value, nil
+}
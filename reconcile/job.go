@@ -0,0 +1,125 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/storage"
+)
+
+// DefaultInterval is how often Job reconciles when Config.Interval is not positive
+const DefaultInterval = time.Minute
+
+// Config configures the reconciliation loop
+type Config struct {
+	Dir      string        // Manifest directory
+	Interval time.Duration // Time between runs
+	Prune    bool          // Delete entities of managed kinds missing from the manifests
+	DryRun   bool          // Only report drift
+	Actor    string        // Recorded in the policy change audit trail (DefaultActor when empty)
+}
+
+// ConfigFromEnv reads ABAC_RECONCILE_DIR, ABAC_RECONCILE_INTERVAL, ABAC_RECONCILE_PRUNE and
+// ABAC_RECONCILE_DRY_RUN. It returns nil when no manifest directory is set.
+func ConfigFromEnv() (*Config, error) {
+	dir := os.Getenv(constants.EnvReconcileDir)
+	if dir == "" {
+		return nil, nil
+	}
+
+	config := &Config{
+		Dir:      dir,
+		Interval: DefaultInterval,
+		Prune:    strings.EqualFold(os.Getenv(constants.EnvReconcilePrune), "true"),
+		DryRun:   strings.EqualFold(os.Getenv(constants.EnvReconcileDryRun), "true"),
+	}
+	if raw := os.Getenv(constants.EnvReconcileInterval); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: expected a positive duration", constants.EnvReconcileInterval, raw)
+		}
+		config.Interval = interval
+	}
+	return config, nil
+}
+
+// Result is the outcome of one reconciliation run
+type Result struct {
+	Plan   *Plan   `json:"plan"`
+	Report *Report `json:"report,omitempty"` // nil for dry runs and runs without drift
+}
+
+// Job periodically reconciles a manifest directory into storage
+type Job struct {
+	reconciler *Reconciler
+	config     Config
+	onApplied  func(*Result)
+}
+
+// NewJob creates a reconciliation job; a non-positive interval uses DefaultInterval
+func NewJob(store storage.Storage, config Config) *Job {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Job{reconciler: NewReconciler(store, config.Prune, config.Actor), config: config}
+}
+
+// OnApplied registers a callback run after a run changed storage, e.g. to purge PDP caches
+func (j *Job) OnApplied(fn func(*Result)) {
+	j.onApplied = fn
+}
+
+// RunOnce loads the manifests, plans and, unless DryRun is set, applies the drift.
+// Invalid manifests abort the run without touching storage.
+func (j *Job) RunOnce(ctx context.Context) (*Result, error) {
+	desired, err := LoadDir(j.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	plan, err := j.reconciler.Plan(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Plan: plan}
+	if j.config.DryRun || !plan.HasDrift() {
+		return result, nil
+	}
+	result.Report = j.reconciler.Apply(plan)
+	if result.Report.Applied > 0 && j.onApplied != nil {
+		j.onApplied(result)
+	}
+	return result, nil
+}
+
+// Start reconciles immediately and then every interval until ctx is cancelled
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := j.RunOnce(ctx)
+		switch {
+		case err != nil:
+			log.Printf("Reconciliation of %s failed: %v", j.config.Dir, err)
+		case result.Report != nil:
+			log.Printf("Reconciliation: applied %d changes, %d failed %v", result.Report.Applied, result.Report.Failed, result.Report.Errors)
+		case result.Plan.HasDrift():
+			log.Printf("Reconciliation drift (dry run): %d changes pending", len(result.Plan.Changes))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"abac_go_example/importer"
+	"abac_go_example/models"
+)
+
+// Manifest is the JSON document of one manifest file. A kind whose key is present (even as an
+// empty array) is managed by the manifests; absent kinds are never pruned.
+type Manifest struct {
+	Subjects  []json.RawMessage `json:"subjects,omitempty"`
+	Resources []json.RawMessage `json:"resources,omitempty"`
+	Policies  []json.RawMessage `json:"policies,omitempty"`
+}
+
+// State is the desired state declared by a set of manifests
+type State struct {
+	Subjects  map[string]*models.Subject
+	Resources map[string]*models.Resource
+	Policies  map[string]*models.Policy
+
+	managed map[importer.Kind]bool
+	sources map[string]string // kind/id -> file declaring it, for duplicate errors
+}
+
+// NewState returns an empty desired state managing no kinds
+func NewState() *State {
+	return &State{
+		Subjects:  make(map[string]*models.Subject),
+		Resources: make(map[string]*models.Resource),
+		Policies:  make(map[string]*models.Policy),
+		managed:   make(map[importer.Kind]bool),
+		sources:   make(map[string]string),
+	}
+}
+
+// Manages reports whether the manifests declare kind
+func (s *State) Manages(kind importer.Kind) bool {
+	return s.managed[kind]
+}
+
+// LoadDir reads every *.json file below dir in lexical order. Entries are validated like
+// NDJSON imports; all invalid entries and duplicate IDs are reported in one error.
+func LoadDir(dir string) (*State, error) {
+	state := NewState()
+	problems := []string{}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(dir, path)
+		problems = append(problems, state.Add(name, data)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests in %s: %w", dir, err)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid manifests:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return state, nil
+}
+
+// Add merges one manifest document named source into the state and returns its problems
+func (s *State) Add(source string, data []byte) []string {
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return []string{fmt.Sprintf("%s: invalid JSON: %v", source, err)}
+	}
+
+	problems := []string{}
+	sections := []struct {
+		kind    importer.Kind
+		entries []json.RawMessage
+	}{
+		{importer.KindSubjects, manifest.Subjects},
+		{importer.KindResources, manifest.Resources},
+		{importer.KindPolicies, manifest.Policies},
+	}
+	for _, section := range sections {
+		if section.entries == nil {
+			continue
+		}
+		s.managed[section.kind] = true
+		for i, raw := range section.entries {
+			if err := s.addEntry(source, section.kind, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s[%d]: %v", source, section.kind, i, err))
+			}
+		}
+	}
+	return problems
+}
+
+// addEntry validates one entry and records it under its ID
+func (s *State) addEntry(source string, kind importer.Kind, raw json.RawMessage) error {
+	id, entity, err := importer.Decode(kind, raw)
+	if err != nil {
+		return err
+	}
+	key := string(kind) + "/" + id
+	if previous, duplicate := s.sources[key]; duplicate {
+		return fmt.Errorf("duplicate id %q, first declared in %s", id, previous)
+	}
+	s.sources[key] = source
+
+	switch e := entity.(type) {
+	case *models.Subject:
+		s.Subjects[id] = e
+	case *models.Resource:
+		s.Resources[id] = e
+	case *models.Policy:
+		s.Policies[id] = e
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+// Package reconcile diffs declarative subject, resource and policy manifests against storage
+// and applies the differences idempotently, so infrastructure-as-code workflows can manage ABAC data.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"abac_go_example/importer"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+// DefaultActor is recorded in the policy change audit trail when no actor is given
+const DefaultActor = "reconciler"
+
+// Op is the operation a change applies to storage
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Ref identifies a stored entity
+type Ref struct {
+	Kind importer.Kind `json:"kind"`
+	ID   string        `json:"id"`
+}
+
+// Change is one difference between the manifests and storage
+type Change struct {
+	Ref
+	Op Op `json:"op"`
+	// Fields lists the top-level JSON fields an update changes
+	Fields []string `json:"fields,omitempty"`
+
+	desired interface{} // entity to create or update with
+	current interface{} // stored entity being updated or deleted
+}
+
+// Plan lists the changes that bring storage in line with the manifests
+type Plan struct {
+	Changes []Change `json:"changes"`
+	// Unmanaged lists stored entities of managed kinds missing from the manifests; they
+	// become deletes when pruning is enabled
+	Unmanaged []Ref `json:"unmanaged"`
+}
+
+// HasDrift reports whether storage differs from the manifests
+func (p *Plan) HasDrift() bool {
+	return len(p.Changes) > 0
+}
+
+// ChangeError describes a change storage rejected
+type ChangeError struct {
+	Ref
+	Op    Op     `json:"op"`
+	Error string `json:"error"`
+}
+
+// Report summarizes an applied plan
+type Report struct {
+	Applied int           `json:"applied"`
+	Failed  int           `json:"failed"`
+	Errors  []ChangeError `json:"errors"`
+}
+
+// Reconciler plans and applies manifest changes against a storage
+type Reconciler struct {
+	store storage.Storage
+	prune bool
+	actor string
+}
+
+// NewReconciler creates a reconciler for store. Without pruning, entities missing from the
+// manifests are only reported as unmanaged.
+func NewReconciler(store storage.Storage, prune bool, actor string) *Reconciler {
+	if actor == "" {
+		actor = DefaultActor
+	}
+	return &Reconciler{store: store, prune: prune, actor: actor}
+}
+
+// Plan diffs the desired state against storage. Changes are ordered subjects, resources,
+// policies, with deletes last; within a kind they are sorted by ID.
+func (r *Reconciler) Plan(desired *State) (*Plan, error) {
+	plan := &Plan{Changes: []Change{}, Unmanaged: []Ref{}}
+	deletes := []Change{}
+
+	subjects, err := r.store.GetAllSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %w", err)
+	}
+	current := make(map[string]interface{}, len(subjects))
+	for _, subject := range subjects {
+		current[subject.ID] = subject
+	}
+	wanted := make(map[string]interface{}, len(desired.Subjects))
+	for id, subject := range desired.Subjects {
+		wanted[id] = subject
+	}
+	if deletes, err = r.diff(plan, deletes, importer.KindSubjects, desired.Manages(importer.KindSubjects), wanted, current); err != nil {
+		return nil, err
+	}
+
+	resources, err := r.store.GetAllResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources: %w", err)
+	}
+	current = make(map[string]interface{}, len(resources))
+	for _, resource := range resources {
+		current[resource.ID] = resource
+	}
+	wanted = make(map[string]interface{}, len(desired.Resources))
+	for id, resource := range desired.Resources {
+		wanted[id] = resource
+	}
+	if deletes, err = r.diff(plan, deletes, importer.KindResources, desired.Manages(importer.KindResources), wanted, current); err != nil {
+		return nil, err
+	}
+
+	policies, err := r.allPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	current = make(map[string]interface{}, len(policies))
+	for _, policy := range policies {
+		current[policy.ID] = policy
+	}
+	wanted = make(map[string]interface{}, len(desired.Policies))
+	for id, policy := range desired.Policies {
+		wanted[id] = policy
+	}
+	if deletes, err = r.diff(plan, deletes, importer.KindPolicies, desired.Manages(importer.KindPolicies), wanted, current); err != nil {
+		return nil, err
+	}
+
+	// Policies go first so no policy is left referencing a deleted subject or resource
+	for i := len(deletes) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, deletes[i])
+	}
+	return plan, nil
+}
+
+// allPolicies pages through ListPolicies, which unlike GetPolicies includes disabled policies
+func (r *Reconciler) allPolicies() ([]*models.Policy, error) {
+	all := []*models.Policy{}
+	opts := storage.ListOptions{Limit: storage.MaxListLimit}
+	for {
+		page, info, err := r.store.ListPolicies(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if info.NextCursor == "" {
+			return all, nil
+		}
+		opts.Cursor = info.NextCursor
+	}
+}
+
+// diff appends the creates and updates of one kind to plan and returns deletes extended with its deletes
+func (r *Reconciler) diff(plan *Plan, deletes []Change, kind importer.Kind, managed bool, wanted, current map[string]interface{}) ([]Change, error) {
+	for _, id := range sortedKeys(wanted) {
+		desired := wanted[id]
+		stored, exists := current[id]
+		if !exists {
+			plan.Changes = append(plan.Changes, Change{Ref: Ref{Kind: kind, ID: id}, Op: OpCreate, desired: desired})
+			continue
+		}
+		fields, err := changedFields(stored, desired)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			plan.Changes = append(plan.Changes, Change{Ref: Ref{Kind: kind, ID: id}, Op: OpUpdate, Fields: fields, desired: desired, current: stored})
+		}
+	}
+
+	if !managed {
+		return deletes, nil
+	}
+	kindDeletes := []Change{}
+	for _, id := range sortedKeys(current) {
+		if _, declared := wanted[id]; declared {
+			continue
+		}
+		ref := Ref{Kind: kind, ID: id}
+		if !r.prune {
+			plan.Unmanaged = append(plan.Unmanaged, ref)
+			continue
+		}
+		kindDeletes = append(kindDeletes, Change{Ref: ref, Op: OpDelete, current: current[id]})
+	}
+	// Reversed again by Plan, which keeps each kind's deletes sorted by ID
+	for i := len(kindDeletes) - 1; i >= 0; i-- {
+		deletes = append(deletes, kindDeletes[i])
+	}
+	return deletes, nil
+}
+
+// Apply executes the plan's changes in order. Changes rejected by storage are reported and the
+// remaining changes still applied, so a later run retries only what failed.
+func (r *Reconciler) Apply(plan *Plan) *Report {
+	report := &Report{Errors: []ChangeError{}}
+	for _, change := range plan.Changes {
+		if err := r.apply(change); err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, ChangeError{Ref: change.Ref, Op: change.Op, Error: err.Error()})
+			continue
+		}
+		report.Applied++
+	}
+	return report
+}
+
+// apply writes one change, carrying over the stored bookkeeping fields on updates
+func (r *Reconciler) apply(change Change) error {
+	switch change.Op {
+	case OpCreate:
+		switch desired := change.desired.(type) {
+		case *models.Subject:
+			return r.store.CreateSubject(desired)
+		case *models.Resource:
+			return r.store.CreateResource(desired)
+		case *models.Policy:
+			if err := r.store.CreatePolicy(desired); err != nil {
+				return err
+			}
+			r.recordPolicyChange(models.PolicyChangeCreate, nil, desired)
+			return nil
+		}
+
+	case OpUpdate:
+		switch desired := change.desired.(type) {
+		case *models.Subject:
+			desired.CreatedAt = change.current.(*models.Subject).CreatedAt
+			return r.store.UpdateSubject(desired)
+		case *models.Resource:
+			desired.CreatedAt = change.current.(*models.Resource).CreatedAt
+			return r.store.UpdateResource(desired)
+		case *models.Policy:
+			current := change.current.(*models.Policy)
+			desired.Revision = current.Revision
+			desired.CreatedAt = current.CreatedAt
+			if err := r.store.UpdatePolicy(desired); err != nil {
+				return err
+			}
+			r.recordPolicyChange(models.PolicyChangeUpdate, current, desired)
+			return nil
+		}
+
+	case OpDelete:
+		switch current := change.current.(type) {
+		case *models.Subject:
+			return r.store.DeleteSubject(current.ID)
+		case *models.Resource:
+			return r.store.DeleteResource(current.ID)
+		case *models.Policy:
+			if err := r.store.DeletePolicy(current.ID); err != nil {
+				return err
+			}
+			r.recordPolicyChange(models.PolicyChangeDelete, current, nil)
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported %s of %s", change.Op, change.Kind)
+}
+
+// recordPolicyChange adds an applied policy change to the audit trail
+func (r *Reconciler) recordPolicyChange(action string, before, after *models.Policy) {
+	if err := storage.RecordPolicyChange(r.store, action, r.actor, before, after); err != nil {
+		subject := after
+		if subject == nil {
+			subject = before
+		}
+		log.Printf("Failed to record reconciled %s of policy %s: %v", action, subject.ID, err)
+	}
+}
+
+// bookkeepingFields are set by storage and never declared in manifests
+var bookkeepingFields = []string{"revision", "created_at", "updated_at", "deleted_at"}
+
+// changedFields returns the sorted top-level JSON fields that differ between two entities.
+// Null, empty objects and empty arrays are equivalent, and policy validity times are
+// compared in UTC, so storage round trips do not show up as drift.
+func changedFields(stored, desired interface{}) ([]string, error) {
+	storedDoc, err := document(stored)
+	if err != nil {
+		return nil, err
+	}
+	desiredDoc, err := document(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+	for key, value := range storedDoc {
+		if !reflect.DeepEqual(value, desiredDoc[key]) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range desiredDoc {
+		if _, exists := storedDoc[key]; !exists {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// document converts an entity to its comparable JSON form
+func document(entity interface{}) (map[string]interface{}, error) {
+	switch e := entity.(type) {
+	case *models.Resource:
+		copied := *e
+		copied.Tags = models.NormalizeTags(copied.Tags)
+		entity = &copied
+	case *models.Policy:
+		copied := *e
+		if copied.EffectiveFrom != nil {
+			utc := copied.EffectiveFrom.UTC()
+			copied.EffectiveFrom = &utc
+		}
+		if copied.ExpiresAt != nil {
+			utc := copied.ExpiresAt.UTC()
+			copied.ExpiresAt = &utc
+		}
+		entity = &copied
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %T: %w", entity, err)
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %T: %w", entity, err)
+	}
+	for _, field := range bookkeepingFields {
+		delete(doc, field)
+	}
+	for key, value := range doc {
+		if isEmpty(value) {
+			delete(doc, key)
+		}
+	}
+	return doc, nil
+}
+
+// isEmpty reports whether a decoded JSON value is null, an empty object or an empty array
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func sortedKeys(entities map[string]interface{}) []string {
+	keys := make([]string, 0, len(entities))
+	for key := range entities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
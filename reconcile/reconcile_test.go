@@ -0,0 +1,266 @@
+package reconcile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"abac_go_example/importer"
+	"abac_go_example/models"
+	"abac_go_example/storage"
+)
+
+const subjectsManifest = `{
+  "subjects": [
+    {"id": "sub-001", "subject_type": "user", "attributes": {"department": "engineering", "level": 5}},
+    {"id": "sub-002", "subject_type": "user", "attributes": {"department": "finance"}}
+  ]
+}`
+
+const policiesManifest = `{
+  "resources": [
+    {"id": "res-001", "resource_type": "document", "resource_id": "doc-1", "tags": ["env=prod"]}
+  ],
+  "policies": [
+    {
+      "id": "pol-001",
+      "policy_name": "Engineering Read",
+      "version": "2024-10-21",
+      "enabled": true,
+      "statement": [{"Sid": "Read", "Effect": "Allow", "Action": "document:read", "Resource": "api:documents:*"}]
+    }
+  ]
+}`
+
+func writeManifests(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func opsOf(plan *Plan) []string {
+	ops := []string{}
+	for _, change := range plan.Changes {
+		ops = append(ops, string(change.Op)+" "+string(change.Kind)+"/"+change.ID)
+	}
+	return ops
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := writeManifests(t, map[string]string{
+		"subjects.json":      subjectsManifest,
+		"team/policies.json": policiesManifest,
+		"notes.txt":          "ignored",
+	})
+
+	state, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(state.Subjects) != 2 || len(state.Resources) != 1 || len(state.Policies) != 1 {
+		t.Errorf("unexpected state: %d subjects, %d resources, %d policies", len(state.Subjects), len(state.Resources), len(state.Policies))
+	}
+	for _, kind := range []importer.Kind{importer.KindSubjects, importer.KindResources, importer.KindPolicies} {
+		if !state.Manages(kind) {
+			t.Errorf("expected %s to be managed", kind)
+		}
+	}
+
+	dir = writeManifests(t, map[string]string{
+		"a.json": subjectsManifest,
+		"b.json": `{"subjects": [{"id": "sub-001", "subject_type": "user"}, {"id": "sub-003"}], "policies": [{"id": "pol-x"}]}`,
+	})
+	_, err = LoadDir(dir)
+	if err == nil {
+		t.Fatal("expected invalid manifests to fail")
+	}
+	for _, want := range []string{`b.json: subjects[0]: duplicate id "sub-001", first declared in a.json`, "b.json: subjects[1]: id and subject_type are required", "b.json: policies[0]: policy does not match schema"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	store := storage.NewMockStorage()
+	store.CreateSubject(&models.Subject{ID: "sub-001", SubjectType: "user", Attributes: models.JSONMap{"department": "sales", "level": 5}})
+	store.CreateSubject(&models.Subject{ID: "sub-legacy", SubjectType: "user"})
+	store.CreateResource(&models.Resource{ID: "res-001", ResourceType: "document", ResourceID: "doc-1", Tags: models.JSONStringSlice{"env=prod"}})
+
+	state := NewState()
+	if problems := state.Add("subjects.json", []byte(subjectsManifest)); len(problems) > 0 {
+		t.Fatal(problems)
+	}
+	if problems := state.Add("policies.json", []byte(policiesManifest)); len(problems) > 0 {
+		t.Fatal(problems)
+	}
+
+	reconciler := NewReconciler(store, false, "")
+	plan, err := reconciler.Plan(state)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	want := []string{"update subjects/sub-001", "create subjects/sub-002", "create policies/pol-001"}
+	if got := opsOf(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(plan.Changes[0].Fields, []string{"attributes"}) {
+		t.Errorf("expected attributes to change, got %v", plan.Changes[0].Fields)
+	}
+	if !reflect.DeepEqual(plan.Unmanaged, []Ref{{Kind: importer.KindSubjects, ID: "sub-legacy"}}) {
+		t.Errorf("unexpected unmanaged entities %v", plan.Unmanaged)
+	}
+
+	report := reconciler.Apply(plan)
+	if report.Applied != 3 || report.Failed != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	subject, _ := store.GetSubject("sub-001")
+	if subject.Attributes["department"] != "engineering" {
+		t.Errorf("expected updated department, got %v", subject.Attributes)
+	}
+	changes, _ := store.GetPolicyChanges("pol-001", 0)
+	if len(changes) != 1 || changes[0].Actor != DefaultActor || changes[0].Action != models.PolicyChangeCreate {
+		t.Errorf("expected the create in the audit trail, got %+v", changes)
+	}
+
+	// A second run against fresh manifests finds nothing to do
+	reloaded := NewState()
+	reloaded.Add("subjects.json", []byte(subjectsManifest))
+	reloaded.Add("policies.json", []byte(policiesManifest))
+	plan, err = reconciler.Plan(reloaded)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if plan.HasDrift() {
+		t.Errorf("expected no drift after apply, got %v", opsOf(plan))
+	}
+}
+
+func TestPlanPrune(t *testing.T) {
+	store := storage.NewMockStorage()
+	store.CreateSubject(&models.Subject{ID: "sub-b", SubjectType: "user"})
+	store.CreateSubject(&models.Subject{ID: "sub-a", SubjectType: "user"})
+	store.CreateResource(&models.Resource{ID: "res-unmanaged", ResourceType: "document"})
+	store.CreatePolicy(&models.Policy{ID: "pol-old", PolicyName: "Old", Version: "1"})
+
+	state := NewState()
+	state.Add("empty.json", []byte(`{"subjects": [], "policies": []}`))
+
+	reconciler := NewReconciler(store, true, "ci")
+	plan, err := reconciler.Plan(state)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	// Resources are not declared by any manifest, so they are left alone
+	want := []string{"delete policies/pol-old", "delete subjects/sub-a", "delete subjects/sub-b"}
+	if got := opsOf(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if report := reconciler.Apply(plan); report.Applied != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if _, err := store.GetResource("res-unmanaged"); err != nil {
+		t.Errorf("expected unmanaged resource to survive: %v", err)
+	}
+	changes, _ := store.GetPolicyChanges("pol-old", 0)
+	if len(changes) != 1 || changes[0].Actor != "ci" || changes[0].Action != models.PolicyChangeDelete {
+		t.Errorf("expected the delete in the audit trail, got %+v", changes)
+	}
+}
+
+func TestPlanPolicyUpdate(t *testing.T) {
+	store := storage.NewMockStorage()
+	store.CreatePolicy(&models.Policy{ID: "pol-001", PolicyName: "Engineering Read", Version: "2024-10-21", Enabled: false,
+		Statement: []models.PolicyStatement{{Sid: "Read", Effect: "Allow",
+			Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}}}})
+	store.UpdatePolicy(&models.Policy{ID: "pol-001", PolicyName: "Engineering Read", Version: "2024-10-21", Revision: 1,
+		Statement: []models.PolicyStatement{{Sid: "Read", Effect: "Allow",
+			Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "api:documents:*"}}}})
+
+	state := NewState()
+	state.Add("policies.json", []byte(policiesManifest))
+	reconciler := NewReconciler(store, false, "")
+	plan, err := reconciler.Plan(state)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	// The disabled policy is found even though GetPolicies only returns enabled ones
+	if got := opsOf(plan); !reflect.DeepEqual(got, []string{"create resources/res-001", "update policies/pol-001"}) {
+		t.Fatalf("unexpected plan %v", got)
+	}
+	if !reflect.DeepEqual(plan.Changes[1].Fields, []string{"enabled"}) {
+		t.Errorf("expected only enabled to change, got %v", plan.Changes[1].Fields)
+	}
+
+	if report := reconciler.Apply(plan); report.Failed != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	policy, _ := store.GetPolicy("pol-001")
+	if !policy.Enabled || policy.Revision != 3 {
+		t.Errorf("expected enabled policy at revision 3, got enabled=%v revision=%d", policy.Enabled, policy.Revision)
+	}
+}
+
+func TestJobRunOnce(t *testing.T) {
+	dir := writeManifests(t, map[string]string{"subjects.json": subjectsManifest})
+	store := storage.NewMockStorage()
+
+	dryRun := NewJob(store, Config{Dir: dir, DryRun: true})
+	result, err := dryRun.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if !result.Plan.HasDrift() || result.Report != nil {
+		t.Fatalf("expected drift to be reported only, got %+v", result)
+	}
+	if subjects, _ := store.GetAllSubjects(); len(subjects) != 0 {
+		t.Fatalf("dry run changed storage: %d subjects", len(subjects))
+	}
+
+	applied := 0
+	job := NewJob(store, Config{Dir: dir})
+	job.OnApplied(func(result *Result) { applied += result.Report.Applied })
+	if _, err := job.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if _, err := job.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("expected 2 changes applied once, got %d", applied)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("ABAC_RECONCILE_DIR", "")
+	if config, err := ConfigFromEnv(); config != nil || err != nil {
+		t.Errorf("expected no config without a directory, got %+v, %v", config, err)
+	}
+
+	t.Setenv("ABAC_RECONCILE_DIR", "manifests")
+	t.Setenv("ABAC_RECONCILE_INTERVAL", "30s")
+	t.Setenv("ABAC_RECONCILE_PRUNE", "true")
+	config, err := ConfigFromEnv()
+	if err != nil || config.Dir != "manifests" || config.Interval.String() != "30s" || !config.Prune || config.DryRun {
+		t.Errorf("unexpected config %+v, %v", config, err)
+	}
+
+	t.Setenv("ABAC_RECONCILE_INTERVAL", "soon")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("expected an invalid interval to fail")
+	}
+}
@@ -0,0 +1,195 @@
+package redaction
+
+import (
+	"fmt"
+	"strings"
+
+	"abac_go_example/models"
+)
+
+// DefaultMask replaces the value of fully redacted attributes
+const DefaultMask = "[REDACTED]"
+
+// MaskStrategy defines how a sensitive value is masked
+type MaskStrategy string
+
+const (
+	// MaskFull replaces the whole value with the mask
+	MaskFull MaskStrategy = "full"
+	// MaskPartial keeps the last 4 characters (e.g. "***6789")
+	MaskPartial MaskStrategy = "partial"
+	// MaskEmail keeps the first character of the local part and the domain (e.g. "j***@company.com")
+	MaskEmail MaskStrategy = "email"
+)
+
+// partialVisibleChars is the number of trailing characters kept by MaskPartial
+const partialVisibleChars = 4
+
+// minTextValueLength skips very short values in RedactText so unrelated text is not mangled
+const minTextValueLength = 3
+
+// Redactor masks sensitive attributes before they leave the authorization engine
+// (audit logs, Explain output, decision reasons). Evaluation itself always sees raw values.
+// A nil *Redactor is valid and performs no redaction.
+type Redactor struct {
+	rules map[string]MaskStrategy
+	mask  string
+}
+
+// NewRedactor creates a redactor that fully masks the given attribute names (case-insensitive)
+func NewRedactor(keys ...string) *Redactor {
+	r := &Redactor{
+		rules: make(map[string]MaskStrategy, len(keys)),
+		mask:  DefaultMask,
+	}
+	for _, key := range keys {
+		r.AddRule(key, MaskFull)
+	}
+	return r
+}
+
+// DefaultRedactor returns a redactor covering common PII attributes
+func DefaultRedactor() *Redactor {
+	r := NewRedactor("salary", "password", "national_id")
+	r.AddRule("ssn", MaskPartial)
+	r.AddRule("phone", MaskPartial)
+	r.AddRule("email", MaskEmail)
+	return r
+}
+
+// AddRule registers an attribute name with the given mask strategy
+func (r *Redactor) AddRule(key string, strategy MaskStrategy) {
+	r.rules[strings.ToLower(key)] = strategy
+}
+
+// IsSensitive reports whether the attribute is covered by a rule.
+// Flat context keys ("user:salary") and dotted paths ("user.salary") are matched by their last segment.
+func (r *Redactor) IsSensitive(key string) bool {
+	_, ok := r.ruleFor(key)
+	return ok
+}
+
+// MaskValue returns the masked form of value when key is sensitive, otherwise value unchanged
+func (r *Redactor) MaskValue(key string, value interface{}) interface{} {
+	strategy, ok := r.ruleFor(key)
+	if !ok || value == nil {
+		return value
+	}
+	return r.applyStrategy(strategy, fmt.Sprintf("%v", value))
+}
+
+// RedactMap returns a deep copy of m with sensitive values masked
+func (r *Redactor) RedactMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if r.IsSensitive(key) {
+			result[key] = r.MaskValue(key, value)
+			continue
+		}
+
+		result[key] = r.redactValue(value)
+	}
+	return result
+}
+
+// redactValue returns a deep copy of value with sensitive keys of nested maps masked,
+// including maps inside arrays such as certificate chains and relationship lists
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return r.RedactMap(v)
+	case models.JSONMap:
+		return r.RedactMap(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, element := range v {
+			result[i] = r.redactValue(element)
+		}
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, len(v))
+		for i, element := range v {
+			result[i] = r.RedactMap(element)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// RedactConflicts masks the stored and requested values of sensitive attribute conflicts
+func (r *Redactor) RedactConflicts(conflicts []models.AttributeConflict) []models.AttributeConflict {
+	if r == nil || len(conflicts) == 0 {
+		return conflicts
+	}
+
+	result := make([]models.AttributeConflict, len(conflicts))
+	for i, conflict := range conflicts {
+		conflict.StoredValue = r.MaskValue(conflict.Key, conflict.StoredValue)
+		conflict.RequestValue = r.MaskValue(conflict.Key, conflict.RequestValue)
+		result[i] = conflict
+	}
+	return result
+}
+
+// RedactText replaces occurrences of sensitive attribute values from attrs inside free text,
+// such as decision reasons built from attribute values
+func (r *Redactor) RedactText(text string, attrs map[string]interface{}) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	for key, value := range attrs {
+		if value == nil || !r.IsSensitive(key) {
+			continue
+		}
+		raw := fmt.Sprintf("%v", value)
+		if len(raw) < minTextValueLength {
+			continue
+		}
+		text = strings.ReplaceAll(text, raw, fmt.Sprintf("%v", r.MaskValue(key, value)))
+	}
+	return text
+}
+
+// ruleFor finds the strategy for key, trying the full key first and then its last path segment
+func (r *Redactor) ruleFor(key string) (MaskStrategy, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	lower := strings.ToLower(key)
+	if strategy, ok := r.rules[lower]; ok {
+		return strategy, true
+	}
+
+	if idx := strings.LastIndexAny(lower, ".:"); idx >= 0 {
+		strategy, ok := r.rules[lower[idx+1:]]
+		return strategy, ok
+	}
+
+	return "", false
+}
+
+// applyStrategy masks raw according to strategy
+func (r *Redactor) applyStrategy(strategy MaskStrategy, raw string) string {
+	switch strategy {
+	case MaskPartial:
+		if len(raw) <= partialVisibleChars {
+			return r.mask
+		}
+		return "***" + raw[len(raw)-partialVisibleChars:]
+	case MaskEmail:
+		at := strings.LastIndex(raw, "@")
+		if at < 1 {
+			return r.mask
+		}
+		return raw[:1] + "***" + raw[at:]
+	default:
+		return r.mask
+	}
+}
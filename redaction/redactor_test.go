@@ -0,0 +1,142 @@
+package redaction
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestRedactorMaskValue(t *testing.T) {
+	r := DefaultRedactor()
+
+	tests := []struct {
+		name     string
+		key      string
+		value    interface{}
+		expected interface{}
+	}{
+		{"full mask", "salary", 85000, DefaultMask},
+		{"case insensitive", "Salary", 85000, DefaultMask},
+		{"flat context key", "user:salary", 85000, DefaultMask},
+		{"dotted path", "user.salary", 85000, DefaultMask},
+		{"partial mask", "ssn", "123-45-6789", "***6789"},
+		{"partial mask short value", "ssn", "12", DefaultMask},
+		{"email mask", "email", "john.doe@company.com", "j***@company.com"},
+		{"invalid email", "email", "not-an-email", DefaultMask},
+		{"non sensitive", "department", "Engineering", "Engineering"},
+		{"nil value", "salary", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.MaskValue(tt.key, tt.value); got != tt.expected {
+				t.Errorf("MaskValue(%q, %v) = %v, want %v", tt.key, tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedactorRedactMap(t *testing.T) {
+	r := NewRedactor("salary")
+	original := map[string]interface{}{
+		"department": "Engineering",
+		"salary":     85000,
+		"attributes": map[string]interface{}{
+			"salary": 90000,
+			"level":  5,
+		},
+	}
+
+	redacted := r.RedactMap(original)
+
+	if redacted["salary"] != DefaultMask {
+		t.Errorf("Expected top-level salary to be redacted, got %v", redacted["salary"])
+	}
+	if redacted["department"] != "Engineering" {
+		t.Errorf("Expected department to be kept, got %v", redacted["department"])
+	}
+	nested := redacted["attributes"].(map[string]interface{})
+	if nested["salary"] != DefaultMask || nested["level"] != 5 {
+		t.Errorf("Unexpected nested attributes: %v", nested)
+	}
+
+	// Original values must stay available for condition evaluation
+	if original["salary"] != 85000 || original["attributes"].(map[string]interface{})["salary"] != 90000 {
+		t.Errorf("RedactMap must not mutate the input: %v", original)
+	}
+}
+
+func TestRedactorRedactMapArrays(t *testing.T) {
+	r := NewRedactor("salary", "serial_number")
+	original := map[string]interface{}{
+		"issuer_chain": []interface{}{
+			map[string]interface{}{"common_name": "Issuing CA", "serial_number": "01:ab"},
+			"intermediate",
+			[]interface{}{map[string]interface{}{"serial_number": "02:cd"}},
+		},
+		"relationships": []map[string]interface{}{
+			{"type": "manager_of", "salary": 85000},
+		},
+	}
+
+	redacted := r.RedactMap(original)
+
+	chain := redacted["issuer_chain"].([]interface{})
+	issuer := chain[0].(map[string]interface{})
+	if issuer["serial_number"] != DefaultMask || issuer["common_name"] != "Issuing CA" {
+		t.Errorf("Expected the serial number inside the array to be redacted: %v", issuer)
+	}
+	if chain[1] != "intermediate" {
+		t.Errorf("Expected scalar array elements to be kept, got %v", chain[1])
+	}
+	if nested := chain[2].([]interface{})[0].(map[string]interface{}); nested["serial_number"] != DefaultMask {
+		t.Errorf("Expected nested arrays to be redacted: %v", nested)
+	}
+	if relationship := redacted["relationships"].([]map[string]interface{})[0]; relationship["salary"] != DefaultMask || relationship["type"] != "manager_of" {
+		t.Errorf("Expected the salary inside the relationship list to be redacted: %v", relationship)
+	}
+
+	if original["issuer_chain"].([]interface{})[0].(map[string]interface{})["serial_number"] != "01:ab" {
+		t.Errorf("RedactMap must not mutate arrays of the input: %v", original)
+	}
+}
+
+func TestRedactorRedactConflictsAndText(t *testing.T) {
+	r := DefaultRedactor()
+
+	conflicts := r.RedactConflicts([]models.AttributeConflict{
+		{Entity: "subject", Key: "salary", StoredValue: 85000, RequestValue: 120000},
+		{Entity: "subject", Key: "department", StoredValue: "Engineering", RequestValue: "Finance"},
+	})
+	if conflicts[0].StoredValue != DefaultMask || conflicts[0].RequestValue != DefaultMask {
+		t.Errorf("Expected salary conflict to be redacted: %+v", conflicts[0])
+	}
+	if conflicts[1].StoredValue != "Engineering" {
+		t.Errorf("Expected department conflict to be kept: %+v", conflicts[1])
+	}
+
+	text := r.RedactText("Denied: salary 85000 exceeds limit for john.doe@company.com", map[string]interface{}{
+		"user:salary": 85000,
+		"user:email":  "john.doe@company.com",
+		"user:level":  5,
+	})
+	expected := "Denied: salary [REDACTED] exceeds limit for j***@company.com"
+	if text != expected {
+		t.Errorf("RedactText = %q, want %q", text, expected)
+	}
+}
+
+func TestNilRedactorIsNoop(t *testing.T) {
+	var r *Redactor
+	m := map[string]interface{}{"salary": 85000}
+
+	if r.IsSensitive("salary") {
+		t.Error("Nil redactor should not report sensitive keys")
+	}
+	if got := r.RedactMap(m); got["salary"] != 85000 {
+		t.Errorf("Nil redactor should keep values, got %v", got)
+	}
+	if got := r.RedactText("salary 85000", m); got != "salary 85000" {
+		t.Errorf("Nil redactor should keep text, got %q", got)
+	}
+}
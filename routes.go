@@ -0,0 +1,184 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"abac_go_example/attributes"
+	"abac_go_example/bundle"
+	"abac_go_example/constants"
+	"abac_go_example/evaluator/core"
+	"abac_go_example/models"
+	"abac_go_example/openapi"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// apiInfo describes the service in the OpenAPI document
+var apiInfo = openapi.Info{
+	Title:       "ABAC Authorization Service",
+	Description: "Policy decision point (evaluate, explain), policy administration and audit endpoints.",
+	Version:     "1.0.0",
+}
+
+// apiRoute is an HTTP endpoint with the annotation it is documented by
+type apiRoute struct {
+	openapi.Route
+	handler gin.HandlerFunc
+}
+
+// listQuery are the paging parameters of the list endpoints (see parseListOptions)
+var listQuery = []openapi.Parameter{
+	openapi.QueryParam("limit", "integer", "Page size"),
+	openapi.QueryParam("offset", "integer", "Items to skip, instead of a cursor"),
+	openapi.QueryParam("cursor", "string", "next_cursor of the previous page"),
+	openapi.QueryParam("type", "string", "Filter by type"),
+	openapi.QueryParam("sort", "string", "Field name, \"-\" prefix for descending (e.g. \"-updated_at\")"),
+	openapi.QueryParam("enabled", "boolean", "Filter policies by enabled flag"),
+	openapi.QueryParam("updated_since", "string", "RFC 3339 timestamp"),
+	openapi.QueryParam("tag", "string", "Filter policies by tag (e.g. \"pci\" or \"domain=pci\")"),
+	openapi.QueryParam("owner", "string", "Filter policies by owning team"),
+}
+
+// attributeQuery are the parameters of the attribute search endpoints (see parseAttributeQuery)
+var attributeQuery = []openapi.Parameter{
+	openapi.QueryParam("key", "string", "Attribute name"),
+	openapi.QueryParam("value", "string", "Attribute value, parsed as a JSON literal when possible"),
+}
+
+// routes lists every endpoint of the service. Routes are registered and documented from
+// this table, so /openapi.json and the generated client cannot drift from the router.
+func (service *ABACService) routes() []apiRoute {
+	return []apiRoute{
+		{openapi.Route{Method: http.MethodGet, Path: "/health", OperationID: "health", Summary: "Health check", Tag: "service"}, handleHealth},
+
+		// Demo endpoints protected by ABACMiddleware
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/users", OperationID: "listUsers", Summary: "List users", Tag: "demo", Permission: "read"}, service.handleUsers},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/users/create", OperationID: "createUser", Summary: "Create user", Tag: "demo", Permission: "write"}, service.handleCreateUser},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/financial", OperationID: "getFinancialData", Summary: "Financial data", Tag: "demo", Permission: "read"}, service.handleFinancialData},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/admin", OperationID: "getAdminPanel", Summary: "Admin panel", Tag: "demo", Permission: "admin"}, service.handleAdminPanel},
+
+		// Policy administration (PAP)
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/subjects", OperationID: "listSubjects", Summary: "Page of subjects", Tag: "pap", Permission: "admin", Query: listQuery, Response: SubjectListResponse{}}, service.handleListSubjects},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/resources", OperationID: "listResources", Summary: "Page of resources", Tag: "pap", Permission: "admin", Query: listQuery, Response: ResourceListResponse{}}, service.handleListResources},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/actions", OperationID: "listActions", Summary: "Page of actions", Tag: "pap", Permission: "admin", Query: listQuery, Response: ActionListResponse{}}, service.handleListActions},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies", OperationID: "listPolicies", Summary: "Page of policies, including disabled ones", Tag: "pap", Permission: "admin", Query: listQuery, Response: PolicyListResponse{}}, service.handleListPolicies},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/policies", OperationID: "createPolicy", Summary: "Create a schema-validated policy", Tag: "pap", Permission: "admin", Request: models.Policy{}, Response: PolicyResponse{}, Status: http.StatusCreated}, service.handleCreatePolicy},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/:id", OperationID: "getPolicy", Summary: "Get a policy; its revision is sent as ETag", Tag: "pap", Permission: "admin", Response: PolicyResponse{}}, service.handleGetPolicy},
+		{openapi.Route{Method: http.MethodPut, Path: "/api/v1/policies/:id", OperationID: "updatePolicy", Summary: "Replace a policy", Description: "The revision the edit is based on is sent as If-Match (or revision in the body); stale revisions are rejected with 412.", Tag: "pap", Permission: "admin", Query: []openapi.Parameter{openapi.HeaderParam("If-Match", "ETag of the revision being replaced")}, Request: models.Policy{}, Response: PolicyResponse{}}, service.handleUpdatePolicy},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/policies/:id", OperationID: "deletePolicy", Summary: "Delete a policy", Tag: "pap", Permission: "admin", Status: http.StatusNoContent}, service.handleDeletePolicy},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/regex-report", OperationID: "getPolicyRegexReport", Summary: "StringRegex patterns of stored policies that do not compile or exceed the regex limits", Tag: "pap", Permission: "admin", Response: RegexReportResponse{}}, service.handleRegexReport},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/deleted", OperationID: "listDeletedPolicies", Summary: "Soft-deleted policies, most recently deleted first", Tag: "pap", Permission: "admin", Response: DeletedPoliciesResponse{}}, service.handleListDeletedPolicies},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/policies/:id/restore", OperationID: "restorePolicy", Summary: "Restore a soft-deleted policy", Tag: "pap", Permission: "admin", Response: PolicyResponse{}}, service.handleRestorePolicy},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/:id/changes", OperationID: "listPolicyChanges", Summary: "Policy change audit trail with diffs, newest first", Tag: "audit", Permission: "admin", Query: []openapi.Parameter{openapi.QueryParam("limit", "integer", "Maximum changes, default 100")}, Response: PolicyChangesResponse{}}, service.handlePolicyChanges},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/policies/impact", OperationID: "policyImpact", Summary: "Decision flips of a proposed policy change", Tag: "pap", Permission: "admin", Request: PolicyImpactRequestBody{}, Response: PolicyImpactResponse{}}, service.handlePolicyImpact},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/policies/bulk-toggle", OperationID: "togglePolicies", Summary: "Enable or disable the policies selected by ID or tag", Tag: "pap", Permission: "admin", Request: PolicyToggleRequestBody{}, Response: PolicyToggleResponse{}}, service.handleTogglePolicies},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policy-schedules", OperationID: "listPolicySchedules", Summary: "Scheduled bulk policy enables and disables", Tag: "pap", Permission: "admin", Response: PolicyScheduleListResponse{}}, service.handleListPolicySchedules},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/policy-schedules", OperationID: "createPolicySchedule", Summary: "Enable or disable selected policies at run_at or whenever cron fires", Description: "Applied by the policy schedule job (POLICY_SCHEDULE_INTERVAL).", Tag: "pap", Permission: "admin", Request: PolicyScheduleRequestBody{}, Response: models.PolicySchedule{}, Status: http.StatusCreated}, service.handleCreatePolicySchedule},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/policy-schedules/:id", OperationID: "deletePolicySchedule", Summary: "Cancel a policy schedule", Tag: "pap", Permission: "admin", Status: http.StatusNoContent}, service.handleDeletePolicySchedule},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/:id/stats", OperationID: "getPolicyStats", Summary: "Policy evaluation statistics", Tag: "stats", Permission: "admin", Response: PolicyStatsResponse{}}, service.handlePolicyStats},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/:id/coverage", OperationID: "getPolicyCoverage", Summary: "Condition operators and attribute keys exercised by live evaluations", Tag: "stats", Permission: "admin", Response: PolicyCoverageResponse{}}, service.handlePolicyCoverage},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/attributes/missing", OperationID: "getMissingAttributes", Summary: "Condition attribute paths that resolved to nothing, by policy and statement", Tag: "stats", Permission: "admin", Response: MissingAttributesResponse{}}, service.handleMissingAttributes},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/quarantine", OperationID: "listQuarantinedPolicies", Summary: "Policies skipped by evaluation because they were consistently slow", Description: "Quarantined policies contribute Indeterminate: a permit is denied when one of them may deny.", Tag: "stats", Permission: "admin", Response: PolicyQuarantineResponse{}}, service.handlePolicyQuarantine},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/policies/:id/quarantine", OperationID: "releasePolicyQuarantine", Summary: "Evaluate a quarantined policy again", Tag: "stats", Permission: "admin", Status: http.StatusNoContent}, service.handleReleaseQuarantine},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/deny-cache/stats", OperationID: "getDenyCacheStats", Summary: "Negative cache counters", Tag: "stats", Permission: "admin", Response: DenyCacheStatsResponse{}}, service.handleDenyCacheStats},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/attribute-cache/stats", OperationID: "getAttributeCacheStats", Summary: "Attribute cache counters", Tag: "stats", Permission: "admin", Response: AttributeCacheStatsResponse{}}, service.handleAttributeCacheStats},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/attribute-cache/invalidate", OperationID: "invalidateAttributeCache", Summary: "Drop cached subject, resource or action lookups", Tag: "stats", Permission: "admin", Request: InvalidateAttributeCacheRequestBody{}, Response: InvalidateAttributeCacheResponse{}}, service.handleInvalidateAttributeCache},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/compile/stats", OperationID: "getCompileStats", Summary: "Policy compile mode, durations and last warm-up", Tag: "stats", Permission: "admin", Response: core.CompileStats{}}, service.handleCompileStats},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/subjects/search", OperationID: "searchSubjects", Summary: "Subjects by attribute", Tag: "pap", Permission: "admin", Query: attributeQuery, Response: SubjectSearchResponse{}}, service.handleSearchSubjects},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/subjects/:id/decisions", OperationID: "listSubjectDecisions", Summary: "Recent decisions of a subject with deny rate and top denied resources", Tag: "audit", Permission: "admin", Query: []openapi.Parameter{openapi.QueryParam("since", "string", "RFC 3339 start, default 7 days ago"), openapi.QueryParam("limit", "integer", "Maximum decisions, default 100, at most 1000"), openapi.QueryParam("top", "integer", "Top denied resources, default 5")}, Response: SubjectDecisionsResponse{}}, service.handleSubjectDecisions},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/resources/search", OperationID: "searchResources", Summary: "Resources by attribute or tag", Tag: "pap", Permission: "admin", Query: append(attributeQuery, openapi.QueryParam("tag", "string", "Resource tag, instead of key and value")), Response: ResourceSearchResponse{}}, service.handleSearchResources},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/resources/transfer-ownership", OperationID: "transferResourceOwnership", Summary: "Preview or commit a bulk resource ownership transfer with its access impact", Tag: "pap", Permission: "admin", Request: OwnershipTransferRequestBody{}, Response: OwnershipTransferResponse{}}, service.handleTransferResourceOwnership},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/lockdown", OperationID: "getLockdown", Summary: "Whether a lockdown is active", Tag: "lockdown", Permission: constants.ActionLockdownManage, Response: LockdownResponse{}}, service.handleGetLockdown},
+		{openapi.Route{Method: http.MethodPut, Path: "/api/v1/lockdown", OperationID: "setLockdown", Summary: "Enable the deny-all / safelist kill switch", Tag: "lockdown", Permission: constants.ActionLockdownManage, Request: LockdownRequestBody{}, Response: LockdownResponse{}}, service.handleSetLockdown},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/lockdown", OperationID: "liftLockdown", Summary: "Return to normal evaluation", Tag: "lockdown", Permission: constants.ActionLockdownManage, Response: LockdownResponse{}}, service.handleLiftLockdown},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/exceptions", OperationID: "listExceptions", Summary: "Access exceptions", Tag: "pap", Permission: "admin", Query: []openapi.Parameter{openapi.QueryParam("subject_id", "string", "Only exceptions of this subject")}, Response: ExceptionListResponse{}}, service.handleListExceptions},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/exceptions", OperationID: "createException", Summary: "Allow or deny one subject/resource/action until expires_at", Tag: "pap", Permission: "admin", Request: ExceptionRequestBody{}, Response: models.AccessException{}, Status: http.StatusCreated}, service.handleCreateException},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/exceptions/:id", OperationID: "deleteException", Summary: "Revoke an access exception", Tag: "pap", Permission: "admin", Status: http.StatusNoContent}, service.handleDeleteException},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/revocations", OperationID: "listRevocations", Summary: "Revoked subjects and sessions", Tag: "pap", Permission: "admin", Response: RevocationListResponse{}}, service.handleListRevocations},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/revocations", OperationID: "createRevocation", Summary: "Suspend all access of a subject or session", Description: "Takes effect before lockdown, exceptions and policies.", Tag: "pap", Permission: "admin", Request: RevocationRequestBody{}, Response: models.Revocation{}, Status: http.StatusCreated}, service.handleCreateRevocation},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/revocations/:id", OperationID: "deleteRevocation", Summary: "Lift a revocation", Tag: "pap", Permission: "admin", Status: http.StatusNoContent}, service.handleDeleteRevocation},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/elevations", OperationID: "listElevations", Summary: "Time-limited role and attribute elevations", Tag: "pap", Permission: "admin", Query: []openapi.Parameter{openapi.QueryParam("subject_id", "string", "Only elevations of this subject")}, Response: ElevationListResponse{}}, service.handleListElevations},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/elevations", OperationID: "createElevation", Summary: "Grant a subject roles and attributes until expires_at", Description: "Merged into user.roles and the subject attributes only while active.", Tag: "pap", Permission: "admin", Request: ElevationRequestBody{}, Response: models.Elevation{}, Status: http.StatusCreated}, service.handleCreateElevation},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/elevations/:id", OperationID: "deleteElevation", Summary: "End an elevation", Tag: "pap", Permission: "admin", Status: http.StatusNoContent}, service.handleDeleteElevation},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/bundles/export", OperationID: "exportBundle", Summary: "Export enabled policies as a signed bundle", Tag: "bundles", Permission: "admin", Response: bundle.Bundle{}}, service.handleExportBundle},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/bundles/import", OperationID: "importBundle", Summary: "Verify and load a signed policy bundle", Tag: "bundles", Permission: "admin", Request: bundle.Bundle{}, Response: BundleImportResponse{}}, service.handleImportBundle},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/bundles/status", OperationID: "getBundleStatus", Summary: "Trusted bundle and rejected policies", Tag: "bundles", Permission: "admin", Response: BundleStatusResponse{}}, service.handleBundleStatus},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/import/:kind", OperationID: "importRecords", Summary: "NDJSON bulk import of subjects, resources or policies", Tag: "pap", Permission: "admin", RequestType: "application/x-ndjson", Response: ImportResponse{}}, service.handleImport},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/schema/policy", OperationID: "getPolicySchema", Summary: "Policy document JSON Schema", Tag: "pap", ResponseType: "application/schema+json"}, service.handlePolicySchema},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/subject-types", OperationID: "listSubjectTypes", Summary: "Registered subject types and the user.subject_type enum", Tag: "pap", Response: SubjectTypesResponse{}}, service.handleSubjectTypes},
+
+		// Read-only evaluation API (central PDP mode)
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/evaluate", OperationID: "evaluate", Summary: "Evaluate a request", Description: "When fields are given, per-field allow/deny/mask directives are returned as well.", Tag: "pdp", Request: EvaluateRequestBody{}, Response: EvaluateResponse{}}, service.handleEvaluate},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/evaluate/sessions", OperationID: "prepareEvaluationSession", Summary: "Prepare the subject context of an evaluation session", Description: "Evaluations whose session.session_id matches reuse the enriched subject instead of loading it again; elevations are still resolved per evaluation.", Tag: "pdp", Permission: constants.ActionEvaluationSession, Status: http.StatusCreated, Request: PrepareSessionRequestBody{}, Response: attributes.PreparedSubject{}}, service.handlePrepareSession},
+		{openapi.Route{Method: http.MethodDelete, Path: "/api/v1/evaluate/sessions/:id", OperationID: "releaseEvaluationSession", Summary: "Release the prepared subject context of a session", Tag: "pdp", Permission: constants.ActionEvaluationSession, Status: http.StatusNoContent}, service.handleReleaseSession},
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/policies/fingerprint", OperationID: "getPolicyFingerprint", Summary: "Hash of the evaluated policy set for PEP cache synchronization", Description: "The hash is also sent as ETag; If-None-Match with the current hash returns 304.", Tag: "pdp", Query: []openapi.Parameter{
+			openapi.QueryParam("namespace", "string", "Evaluation namespace, default the PDP namespace"),
+			openapi.HeaderParam("If-None-Match", "ETag of a previously fetched fingerprint"),
+		}, Response: core.PolicyFingerprint{}}, service.handlePolicyFingerprint},
+		{openapi.Route{Method: http.MethodPost, Path: "/api/v1/explain", OperationID: "explain", Summary: "Evaluate a request and explain statement matching", Tag: "pdp", Permission: constants.ActionExplain, Request: EvaluateRequestBody{}, Response: ExplainResponse{}}, service.handleExplain},
+
+		// Live decision stream (SSE) for admin dashboards
+		{openapi.Route{Method: http.MethodGet, Path: "/api/v1/decisions/stream", OperationID: "streamDecisions", Summary: "Live decision events as Server-Sent Events", Tag: "audit", Permission: "admin", Query: []openapi.Parameter{
+			openapi.QueryParam("subject", "string", "Subject ID filter, supports '*'"),
+			openapi.QueryParam("resource", "string", "Resource ID filter, supports '*'"),
+			openapi.QueryParam("result", "string", "permit or deny"),
+		}, ResponseType: "text/event-stream"}, service.handleDecisionStream},
+
+		{openapi.Route{Method: http.MethodGet, Path: "/openapi.json", OperationID: "getOpenAPI", Summary: "This OpenAPI document", Tag: "service"}, service.handleOpenAPI},
+	}
+}
+
+// registerRoutes adds the routes to router, behind ABACMiddleware when they require a permission.
+// With admin tokens configured, admin routes first require a token whose roles grant the route's scope.
+func (service *ABACService) registerRoutes(router gin.IRoutes) {
+	for _, route := range service.routes() {
+		if service.adminAuth != nil && isAdminRoute(route.Route) {
+			router.Handle(route.Method, route.Path, service.AdminAuthMiddleware(adminScope(route.Route)), service.ABACMiddleware(route.Permission), route.handler)
+			continue
+		}
+		if route.Permission != "" {
+			router.Handle(route.Method, route.Path, service.ABACMiddleware(route.Permission), route.handler)
+			continue
+		}
+		router.Handle(route.Method, route.Path, route.handler)
+	}
+}
+
+// openAPIDocument builds the OpenAPI document of routes
+func openAPIDocument(routes []apiRoute) *openapi.Document {
+	annotations := make([]openapi.Route, len(routes))
+	for i, route := range routes {
+		annotations[i] = route.Route
+	}
+
+	// JSONActionResource marshals as a single string or an array of strings
+	generator := openapi.NewGenerator()
+	generator.Override(models.JSONActionResource{}, &openapi.Schema{OneOf: []*openapi.Schema{
+		{Type: "string"},
+		{Type: "array", Items: &openapi.Schema{Type: "string"}},
+	}})
+	// DeletedAt marshals as a timestamp or null
+	generator.Override(gorm.DeletedAt{}, &openapi.Schema{Type: "string", Format: "date-time"})
+	return openapi.Build(apiInfo, annotations, generator)
+}
+
+// generateClient renders the Go source of the client package from the OpenAPI document
+func generateClient() ([]byte, error) {
+	return openapi.GenerateClient(openAPIDocument((&ABACService{}).routes()), "client")
+}
+
+// writeClient writes the generated client to path
+func writeClient(path string) error {
+	source, err := generateClient()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, source, 0o644)
+}
+
+// handleOpenAPI serves the OpenAPI 3 document of the service
+func (service *ABACService) handleOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, openAPIDocument(service.routes()))
+}
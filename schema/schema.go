@@ -0,0 +1,49 @@
+// Package schema publishes the JSON Schema of the policy document format and validates documents against it.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+//go:embed policy.schema.json
+var policySchema []byte
+
+var (
+	compiledOnce sync.Once
+	compiled     map[string]interface{}
+	compileErr   error
+)
+
+// PolicySchema returns the raw JSON Schema (draft 2020-12) of a policy document
+func PolicySchema() []byte {
+	return policySchema
+}
+
+// ValidatePolicy validates a raw policy document against the policy schema.
+// It returns nil when the document is valid.
+func ValidatePolicy(document []byte) []ValidationError {
+	root, err := policySchemaRoot()
+	if err != nil {
+		return []ValidationError{{Path: "", Message: err.Error()}}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return []ValidationError{{Path: "", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+
+	v := &validator{root: root}
+	v.validate(root, value, "")
+	return v.errors
+}
+
+// policySchemaRoot parses the embedded schema once
+func policySchemaRoot() (map[string]interface{}, error) {
+	compiledOnce.Do(func() {
+		compileErr = json.Unmarshal(policySchema, &compiled)
+	})
+	return compiled, compileErr
+}
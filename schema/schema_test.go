@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestPolicySchemaIsValidJSON(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(PolicySchema(), &schema); err != nil {
+		t.Fatalf("embedded schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] == nil || schema["$defs"] == nil {
+		t.Error("expected $schema and $defs in policy schema")
+	}
+}
+
+func TestValidatePolicy_ExamplePolicies(t *testing.T) {
+	data, err := os.ReadFile("../policy_examples_corrected.json")
+	if err != nil {
+		t.Fatalf("failed to read example policies: %v", err)
+	}
+
+	var examples struct {
+		Policies []json.RawMessage `json:"policies"`
+	}
+	if err := json.Unmarshal(data, &examples); err != nil {
+		t.Fatalf("failed to parse example policies: %v", err)
+	}
+
+	for i, policy := range examples.Policies {
+		if errs := ValidatePolicy(policy); len(errs) > 0 {
+			t.Errorf("example policy %d should be valid, got %v", i, errs)
+		}
+	}
+}
+
+func TestValidatePolicy_MarshaledModel(t *testing.T) {
+	expiresAt := time.Date(2024, time.December, 27, 0, 0, 0, 0, time.UTC)
+	policy := models.Policy{
+		ID:         "pol-001",
+		PolicyName: "Test",
+		Version:    "2024-10-21",
+		Enabled:    true,
+		ExpiresAt:  &expiresAt,
+		Statement: []models.PolicyStatement{
+			{
+				Effect:      "Allow",
+				Action:      models.JSONActionResource{Single: "document:read"},
+				Resource:    models.JSONActionResource{Multiple: []string{"api:documents:*"}},
+				Obligations: map[string]string{"audit": "full"},
+				Constraints: &models.Constraints{Fields: []string{"title", "summary"}, ReadOnly: true},
+			},
+		},
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatalf("failed to marshal policy: %v", err)
+	}
+	if errs := ValidatePolicy(data); len(errs) > 0 {
+		t.Errorf("marshaled policy should be valid, got %v", errs)
+	}
+}
+
+func TestValidatePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		path     string
+	}{
+		{"not JSON", `{`, ""},
+		{"missing id", `{"policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r"}]}`, "/id"},
+		{"empty statements", `{"id":"p","policy_name":"p","version":"1","statement":[]}`, "/statement"},
+		{"invalid effect", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Permit","Action":"a","Resource":"r"}]}`, "/statement/0/Effect"},
+		{"empty action list", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":[],"Resource":"r"}]}`, "/statement/0/Action"},
+		{"numeric resource", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":5}]}`, "/statement/0/Resource"},
+		{"unknown statement property", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r","Principal":"x"}]}`, "/statement/0/Principal"},
+		{"unknown audit level", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r","Obligations":{"audit":"verbose"}}]}`, "/statement/0/Obligations/audit"},
+		{"unknown obligation", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r","Obligations":{"notify":"secops"}}]}`, "/statement/0/Obligations/notify"},
+		{"unknown constraint", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r","Constraints":{"max_rows":10}}]}`, "/statement/0/Constraints/max_rows"},
+		{"scalar condition", `{"id":"p","policy_name":"p","version":"1","statement":[{"Effect":"Allow","Action":"a","Resource":"r","Condition":{"Bool":true}}]}`, "/statement/0/Condition/Bool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidatePolicy([]byte(tt.document))
+			if len(errs) == 0 {
+				t.Fatal("expected validation errors")
+			}
+			found := false
+			for _, err := range errs {
+				if err.Path == tt.path {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected an error at %q, got %v", tt.path, errs)
+			}
+			if tt.path != "" && !strings.HasPrefix(errs[0].Error(), "/") {
+				t.Errorf("expected error message to include the path, got %q", errs[0].Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a schema violation at a JSON pointer-like path (e.g. "/statement/0/Effect")
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// validator implements the subset of JSON Schema used by policy.schema.json:
+// type, enum, required, properties, additionalProperties, items, minItems, minLength and local $ref.
+type validator struct {
+	root   map[string]interface{}
+	errors []ValidationError
+}
+
+func (v *validator) addError(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks value against schema and records every violation
+func (v *validator) validate(schema map[string]interface{}, value interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolveRef(ref)
+		if err != nil {
+			v.addError(path, "%v", err)
+			return
+		}
+		schema = resolved
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		v.addError(path, "expected %s, got %s", describeTypes(types), jsonType(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		v.addError(path, "must be one of %v", enum)
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && utf8.RuneCountInString(typed) < int(min) {
+			v.addError(path, "must be at least %d characters", int(min))
+		}
+	case []interface{}:
+		v.validateArray(schema, typed, path)
+	case map[string]interface{}:
+		v.validateObject(schema, typed, path)
+	}
+}
+
+// validateArray applies minItems and items
+func (v *validator) validateArray(schema map[string]interface{}, values []interface{}, path string) {
+	if min, ok := schema["minItems"].(float64); ok && len(values) < int(min) {
+		v.addError(path, "must contain at least %d items", int(min))
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range values {
+			v.validate(items, item, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+// validateObject applies required, properties and additionalProperties
+func (v *validator) validateObject(schema map[string]interface{}, object map[string]interface{}, path string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, exists := object[key]; !exists {
+				v.addError(path+"/"+key, "is required")
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Sorted keys keep error order deterministic
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + key
+		if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+			v.validate(propertySchema, object[key], childPath)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.addError(childPath, "unknown property")
+			}
+		case map[string]interface{}:
+			v.validate(additional, object[key], childPath)
+		}
+	}
+}
+
+// resolveRef resolves local references of the form "#/$defs/name"
+func (v *validator) resolveRef(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+
+	var current interface{} = v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		current = node[part]
+	}
+
+	resolved, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return resolved, nil
+}
+
+// matchesType reports whether value matches a "type" keyword (string or list of strings)
+func matchesType(types interface{}, value interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return typeMatches(t, value)
+	case []interface{}:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && typeMatches(name, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeMatches(name string, value interface{}) bool {
+	actual := jsonType(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func describeTypes(types interface{}) string {
+	if list, ok := types.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, name := range list {
+			names = append(names, fmt.Sprintf("%v", name))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprintf("%v", types)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		return false // Enums only hold scalars
+	}
+	for _, candidate := range enum {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
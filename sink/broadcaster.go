@@ -0,0 +1,130 @@
+package sink
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"abac_go_example/evaluator/matchers"
+)
+
+// DefaultSubscriberBuffer is the channel buffer size of a subscription
+const DefaultSubscriberBuffer = 64
+
+// EventFilter selects which decision events a subscriber receives.
+// Empty fields match everything; SubjectID and ResourceID support '*' wildcards.
+type EventFilter struct {
+	SubjectID  string `json:"subject_id,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	Result     string `json:"result,omitempty"`
+}
+
+// Matches reports whether the event passes the filter
+func (f EventFilter) Matches(event DecisionEvent) bool {
+	if f.SubjectID != "" && !matchers.WildcardMatch(f.SubjectID, event.SubjectID) {
+		return false
+	}
+	if f.ResourceID != "" && !matchers.WildcardMatch(f.ResourceID, event.ResourceID) {
+		return false
+	}
+	if f.Result != "" && f.Result != event.Result {
+		return false
+	}
+	return true
+}
+
+// Subscription receives filtered decision events from a Broadcaster
+type Subscription struct {
+	id          uint64
+	filter      EventFilter
+	events      chan DecisionEvent
+	dropped     atomic.Int64
+	broadcaster *Broadcaster
+}
+
+// Events returns the channel of matching events; it is closed on Unsubscribe
+func (s *Subscription) Events() <-chan DecisionEvent {
+	return s.events
+}
+
+// Dropped returns how many events were dropped because the subscriber was too slow
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops delivery and closes the events channel
+func (s *Subscription) Unsubscribe() {
+	s.broadcaster.remove(s.id)
+}
+
+// Broadcaster is a DecisionSink that fans events out to live subscribers (e.g. dashboards).
+// Publishing never blocks: events are dropped for subscribers whose buffer is full.
+type Broadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[uint64]*Subscription
+	nextID      uint64
+}
+
+// NewBroadcaster creates a broadcaster with no subscribers
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[uint64]*Subscription),
+	}
+}
+
+// Subscribe registers a subscriber receiving events that match filter.
+// A non-positive buffer uses DefaultSubscriberBuffer.
+func (b *Broadcaster) Subscribe(filter EventFilter, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	subscription := &Subscription{
+		id:          b.nextID,
+		filter:      filter,
+		events:      make(chan DecisionEvent, buffer),
+		broadcaster: b,
+	}
+	b.subscribers[subscription.id] = subscription
+	return subscription
+}
+
+// Publish implements DecisionSink
+func (b *Broadcaster) Publish(event DecisionEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, subscription := range b.subscribers {
+		if !subscription.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.dropped.Add(1)
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Broadcaster) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers)
+}
+
+// remove unregisters a subscriber and closes its channel
+func (b *Broadcaster) remove(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if subscription, exists := b.subscribers[id]; exists {
+		delete(b.subscribers, id)
+		close(subscription.events)
+	}
+}
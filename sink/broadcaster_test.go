@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcasterFiltersEvents(t *testing.T) {
+	b := NewBroadcaster()
+	denies := b.Subscribe(EventFilter{Result: "deny"}, 10)
+	docs := b.Subscribe(EventFilter{ResourceID: "api:documents:*", SubjectID: "user-*"}, 10)
+	defer denies.Unsubscribe()
+	defer docs.Unsubscribe()
+
+	b.Publish(DecisionEvent{RequestID: "1", SubjectID: "user-1", ResourceID: "api:documents:a", Result: "permit"})
+	b.Publish(DecisionEvent{RequestID: "2", SubjectID: "svc-1", ResourceID: "api:documents:b", Result: "deny"})
+	b.Publish(DecisionEvent{RequestID: "3", SubjectID: "user-2", ResourceID: "api:reports:c", Result: "deny"})
+
+	if got := drain(denies); len(got) != 2 || got[0].RequestID != "2" || got[1].RequestID != "3" {
+		t.Errorf("Unexpected deny events: %+v", got)
+	}
+	if got := drain(docs); len(got) != 1 || got[0].RequestID != "1" {
+		t.Errorf("Unexpected document events: %+v", got)
+	}
+}
+
+func TestBroadcasterDropsForSlowSubscribers(t *testing.T) {
+	b := NewBroadcaster()
+	subscription := b.Subscribe(EventFilter{}, 1)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			b.Publish(DecisionEvent{Result: "deny"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow subscriber")
+	}
+
+	if subscription.Dropped() != 4 {
+		t.Errorf("Expected 4 dropped events, got %d", subscription.Dropped())
+	}
+
+	subscription.Unsubscribe()
+	if b.SubscriberCount() != 0 {
+		t.Errorf("Expected no subscribers after Unsubscribe, got %d", b.SubscriberCount())
+	}
+	if _, open := <-subscription.Events(); open {
+		// Buffered event is still delivered before close
+		if _, open := <-subscription.Events(); open {
+			t.Error("Expected events channel to be closed after Unsubscribe")
+		}
+	}
+}
+
+// drain reads all currently buffered events
+func drain(subscription *Subscription) []DecisionEvent {
+	var events []DecisionEvent
+	for {
+		select {
+		case event := <-subscription.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
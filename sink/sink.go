@@ -0,0 +1,57 @@
+package sink
+
+import (
+	"time"
+
+	"abac_go_example/models"
+)
+
+// DecisionEvent is a single PDP decision published to decision sinks
+type DecisionEvent struct {
+	RequestID        string    `json:"request_id"`
+	SubjectID        string    `json:"subject_id"`
+	ResourceID       string    `json:"resource_id"`
+	Action           string    `json:"action"`
+	Result           string    `json:"result"`
+	Reason           string    `json:"reason,omitempty"`
+	ReasonCode       string    `json:"reason_code,omitempty"`
+	MatchedPolicies  []string  `json:"matched_policies,omitempty"`
+	EvaluationTimeMs int       `json:"evaluation_time_ms"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// DecisionSink receives decisions made by the PDP.
+// Implementations must not block: Publish is called on the evaluation path.
+type DecisionSink interface {
+	Publish(event DecisionEvent)
+}
+
+// NewDecisionEvent builds an event from an evaluation request and its decision, made at the given
+// time of the PDP clock so stream events agree with audit rows
+func NewDecisionEvent(request *models.EvaluationRequest, decision *models.Decision, at time.Time) DecisionEvent {
+	event := DecisionEvent{
+		RequestID:        request.RequestID,
+		ResourceID:       request.ResourceID,
+		Action:           request.Action,
+		Result:           decision.Result,
+		Reason:           decision.Reason,
+		ReasonCode:       decision.ReasonCode,
+		MatchedPolicies:  decision.MatchedPolicies,
+		EvaluationTimeMs: decision.EvaluationTimeMs,
+		Timestamp:        at,
+	}
+	if request.Subject != nil {
+		event.SubjectID = request.Subject.GetID()
+	}
+	return event
+}
+
+// MultiSink fans an event out to several sinks
+type MultiSink []DecisionSink
+
+// Publish forwards the event to every sink
+func (m MultiSink) Publish(event DecisionEvent) {
+	for _, s := range m {
+		s.Publish(event)
+	}
+}
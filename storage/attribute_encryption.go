@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// Names of the GORM callbacks and the statement setting used by attribute encryption
+const (
+	encryptAttributesCallback = "abac:encrypt_attributes"
+	restoreAttributesCallback = "abac:restore_attributes"
+	decryptAttributesCallback = "abac:decrypt_attributes"
+	plaintextAttributesKey    = "abac:plaintext_attributes"
+)
+
+// reencryptBatchSize is the number of rows ReencryptAttributes loads at a time
+const reencryptBatchSize = 100
+
+// ErrAttributeEncryptionEnabled is returned when attribute encryption is enabled twice
+var ErrAttributeEncryptionEnabled = errors.New("attribute encryption is already enabled")
+
+// AttributeCipher encrypts and decrypts subject/resource attribute maps (attrcrypt.Cipher)
+type AttributeCipher interface {
+	EncryptAttributes(attrs map[string]interface{}) (map[string]interface{}, error)
+	DecryptAttributes(attrs map[string]interface{}) (map[string]interface{}, error)
+}
+
+// AttributeEncryptionStore is implemented by storages that can encrypt attributes at rest
+type AttributeEncryptionStore interface {
+	// EnableAttributeEncryption encrypts the attributes of subjects, resources and attribute
+	// history on every write and decrypts them on every read
+	EnableAttributeEncryption(cipher AttributeCipher) error
+	// ReencryptAttributes rewrites all stored attributes with the current key, encrypting
+	// plaintext values of sensitive keys; it returns the number of rows rewritten
+	ReencryptAttributes() (int, error)
+}
+
+// plaintextAttributes remembers the attribute maps replaced by their encrypted copies
+type plaintextAttributes struct {
+	target *models.JSONMap
+	value  models.JSONMap
+}
+
+// EnableAttributeEncryption registers GORM callbacks so encryption is transparent to every query
+// of this storage, including bulk inserts and point-in-time history. Attribute search
+// (FindSubjectsByAttribute) cannot match encrypted values.
+func (s *PostgreSQLStorage) EnableAttributeEncryption(cipher AttributeCipher) error {
+	if s.attributeCipher != nil {
+		return ErrAttributeEncryptionEnabled
+	}
+
+	encrypt := func(db *gorm.DB) {
+		var replaced []plaintextAttributes
+		err := eachAttributeMap(db, func(attrs *models.JSONMap) error {
+			encrypted, err := cipher.EncryptAttributes(*attrs)
+			if err != nil {
+				return err
+			}
+			replaced = append(replaced, plaintextAttributes{target: attrs, value: *attrs})
+			*attrs = encrypted
+			return nil
+		})
+		if err != nil {
+			db.AddError(fmt.Errorf("failed to encrypt attributes: %w", err))
+		}
+		db.InstanceSet(plaintextAttributesKey, replaced)
+	}
+	// Callers keep working with plaintext after a write
+	restore := func(db *gorm.DB) {
+		if replaced, ok := db.InstanceGet(plaintextAttributesKey); ok {
+			for _, original := range replaced.([]plaintextAttributes) {
+				*original.target = original.value
+			}
+		}
+	}
+	decrypt := func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		err := eachAttributeMap(db, func(attrs *models.JSONMap) error {
+			decrypted, err := cipher.DecryptAttributes(*attrs)
+			if err != nil {
+				return err
+			}
+			*attrs = decrypted
+			return nil
+		})
+		if err != nil {
+			db.AddError(fmt.Errorf("failed to decrypt attributes: %w", err))
+		}
+	}
+
+	callbacks := s.db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register(encryptAttributesCallback, encrypt),
+		callbacks.Create().After("gorm:create").Register(restoreAttributesCallback, restore),
+		callbacks.Update().Before("gorm:update").Register(encryptAttributesCallback, encrypt),
+		callbacks.Update().After("gorm:update").Register(restoreAttributesCallback, restore),
+		callbacks.Query().After("gorm:query").Register(decryptAttributesCallback, decrypt),
+	}
+	if err := errors.Join(registrations...); err != nil {
+		return fmt.Errorf("failed to register attribute encryption: %w", err)
+	}
+	s.attributeCipher = cipher
+	return nil
+}
+
+// ReencryptAttributes rewrites the attributes column of every subject, resource and attribute
+// snapshot without touching updated_at or recording history
+func (s *PostgreSQLStorage) ReencryptAttributes() (int, error) {
+	if s.attributeCipher == nil {
+		return 0, fmt.Errorf("attribute encryption is not enabled")
+	}
+
+	rewritten := 0
+	rewrite := func(model interface{}, attrs models.JSONMap) error {
+		encrypted, err := s.attributeCipher.EncryptAttributes(attrs)
+		if err != nil {
+			return err
+		}
+		if err := s.db.Model(model).UpdateColumn("attributes", models.JSONMap(encrypted)).Error; err != nil {
+			return err
+		}
+		rewritten++
+		return nil
+	}
+
+	var subjects []*models.Subject
+	err := s.db.FindInBatches(&subjects, reencryptBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, subject := range subjects {
+			if err := rewrite(subject, subject.Attributes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return rewritten, fmt.Errorf("failed to re-encrypt subjects: %w", err)
+	}
+
+	var resources []*models.Resource
+	err = s.db.FindInBatches(&resources, reencryptBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, resource := range resources {
+			if err := rewrite(resource, resource.Attributes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return rewritten, fmt.Errorf("failed to re-encrypt resources: %w", err)
+	}
+
+	var snapshots []*models.AttributeSnapshot
+	err = s.db.FindInBatches(&snapshots, reencryptBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, snapshot := range snapshots {
+			if err := rewrite(snapshot, snapshot.Attributes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return rewritten, fmt.Errorf("failed to re-encrypt attribute history: %w", err)
+	}
+	return rewritten, nil
+}
+
+// eachAttributeMap calls fn with the attributes of every subject, resource and attribute
+// snapshot the statement reads or writes
+func eachAttributeMap(db *gorm.DB, fn func(attrs *models.JSONMap) error) error {
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := visitAttributeMap(reflect.Indirect(value.Index(i)), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		return visitAttributeMap(value, fn)
+	}
+	return nil
+}
+
+func visitAttributeMap(value reflect.Value, fn func(attrs *models.JSONMap) error) error {
+	if !value.IsValid() || !value.CanAddr() {
+		return nil
+	}
+	switch entity := value.Addr().Interface().(type) {
+	case *models.Subject:
+		return fn(&entity.Attributes)
+	case *models.Resource:
+		return fn(&entity.Attributes)
+	case *models.AttributeSnapshot:
+		return fn(&entity.Attributes)
+	}
+	return nil
+}
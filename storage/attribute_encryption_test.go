@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"abac_go_example/attrcrypt"
+	"abac_go_example/models"
+)
+
+// rotatingKeys is a KeyProvider whose current key can be switched
+type rotatingKeys struct {
+	current string
+	keys    map[string]attrcrypt.Key
+}
+
+func (r *rotatingKeys) CurrentKey() (attrcrypt.Key, error) { return r.keys[r.current], nil }
+
+func (r *rotatingKeys) Key(id string) (attrcrypt.Key, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return attrcrypt.Key{}, attrcrypt.ErrUnknownKey
+	}
+	return key, nil
+}
+
+// storedAttributes reads the raw attributes column, bypassing decryption
+func storedAttributes(t *testing.T, s *PostgreSQLStorage, table, id string) string {
+	var raw string
+	if err := s.db.Raw("SELECT attributes FROM "+table+" WHERE id = ?", id).Scan(&raw).Error; err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestAttributeEncryption(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	keys := &rotatingKeys{current: "k1", keys: map[string]attrcrypt.Key{
+		"k1": {ID: "k1", Material: []byte(strings.Repeat("a", 32))},
+		"k2": {ID: "k2", Material: []byte(strings.Repeat("b", 32))},
+	}}
+	cipher := attrcrypt.NewCipher(keys, []string{"ssn", "email"})
+	if err := sqliteStorage.EnableAttributeEncryption(cipher); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteStorage.EnableAttributeEncryption(cipher); !errors.Is(err, ErrAttributeEncryptionEnabled) {
+		t.Errorf("expected ErrAttributeEncryptionEnabled, got %v", err)
+	}
+
+	subject := &models.Subject{ID: "sub-pii", SubjectType: "user", Attributes: models.JSONMap{"ssn": "123-45-6789", "department": "engineering"}}
+	if err := sqliteStorage.CreateSubject(subject); err != nil {
+		t.Fatal(err)
+	}
+	if subject.Attributes["ssn"] != "123-45-6789" {
+		t.Errorf("expected the caller's subject to keep plaintext, got %v", subject.Attributes)
+	}
+	raw := storedAttributes(t, sqliteStorage.PostgreSQLStorage, "subjects", "sub-pii")
+	if strings.Contains(raw, "123-45-6789") || !strings.Contains(raw, "enc:v1:k1:") || !strings.Contains(raw, "engineering") {
+		t.Errorf("expected only ssn encrypted at rest, got %s", raw)
+	}
+
+	stored, err := sqliteStorage.GetSubject("sub-pii")
+	if err != nil || stored.Attributes["ssn"] != "123-45-6789" {
+		t.Fatalf("expected decrypted attributes, got %v (%v)", stored, err)
+	}
+	snapshot, err := sqliteStorage.GetAttributeSnapshot(models.SnapshotEntitySubject, "sub-pii", time.Now().Add(time.Minute))
+	if err != nil || snapshot.Attributes["ssn"] != "123-45-6789" {
+		t.Errorf("expected decrypted attribute history, got %v (%v)", snapshot, err)
+	}
+
+	stored.Attributes["email"] = "pii@example.com"
+	if err := sqliteStorage.UpdateSubject(stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteStorage.BulkCreateResources([]*models.Resource{{ID: "res-pii", ResourceType: "document", Attributes: models.JSONMap{"email": "owner@example.com"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if raw := storedAttributes(t, sqliteStorage.PostgreSQLStorage, "resources", "res-pii"); strings.Contains(raw, "owner@example.com") {
+		t.Errorf("expected bulk inserted attributes to be encrypted, got %s", raw)
+	}
+	subjects, err := sqliteStorage.GetAllSubjects()
+	if err != nil || len(subjects) != 1 || subjects[0].Attributes["email"] != "pii@example.com" {
+		t.Fatalf("expected decrypted subjects, got %v (%v)", subjects, err)
+	}
+
+	// Rotate: new writes use k2, ReencryptAttributes rewrites rows still encrypted with k1
+	keys.current = "k2"
+	rewritten, err := sqliteStorage.ReencryptAttributes()
+	if err != nil || rewritten != 5 { // 1 subject, 1 resource, 3 attribute snapshots
+		t.Fatalf("ReencryptAttributes = %d, %v", rewritten, err)
+	}
+	if raw := storedAttributes(t, sqliteStorage.PostgreSQLStorage, "subjects", "sub-pii"); strings.Contains(raw, "enc:v1:k1:") || !strings.Contains(raw, "enc:v1:k2:") {
+		t.Errorf("expected attributes re-encrypted with k2, got %s", raw)
+	}
+	delete(keys.keys, "k1")
+	if resource, err := sqliteStorage.GetResource("res-pii"); err != nil || resource.Attributes["email"] != "owner@example.com" {
+		t.Errorf("expected resources readable without k1, got %v (%v)", resource, err)
+	}
+
+	// Values encrypted with a key the provider no longer has fail the read instead of leaking ciphertext
+	delete(keys.keys, "k2")
+	if _, err := sqliteStorage.GetSubject("sub-pii"); err == nil {
+		t.Error("expected reading with a missing key to fail")
+	}
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"time"
+
+	"abac_go_example/models"
+)
+
+// AttributeHistoryStore is implemented by storages that keep point-in-time attribute snapshots.
+// PostgreSQLStorage and MockStorage record a snapshot whenever a subject or resource is created
+// or updated; callers with computed attributes (e.g. user subjects) record them explicitly.
+type AttributeHistoryStore interface {
+	// RecordAttributeSnapshot stores attributes valid from snapshot.ValidFrom (now when zero)
+	RecordAttributeSnapshot(snapshot *models.AttributeSnapshot) error
+	// GetAttributeSnapshot returns the latest snapshot at or before asOf, or nil when there is none
+	GetAttributeSnapshot(entityType, entityID string, asOf time.Time) (*models.AttributeSnapshot, error)
+}
+
+// newAttributeSnapshot builds a snapshot of attrs valid from now
+func newAttributeSnapshot(entityType, entityID string, attrs models.JSONMap) *models.AttributeSnapshot {
+	return &models.AttributeSnapshot{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Attributes: copyJSONMap(attrs),
+		ValidFrom:  time.Now().UTC(),
+	}
+}
+
+// copyJSONMap returns a shallow copy so later mutations of the entity do not rewrite history
+func copyJSONMap(attrs models.JSONMap) models.JSONMap {
+	if attrs == nil {
+		return nil
+	}
+	copied := make(models.JSONMap, len(attrs))
+	for k, v := range attrs {
+		copied[k] = v
+	}
+	return copied
+}
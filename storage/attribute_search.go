@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidAttributeKey is returned by attribute search for empty or malformed keys
+var ErrInvalidAttributeKey = errors.New("invalid attribute key")
+
+// validateAttributeKey rejects keys that cannot be used as a top-level attribute name
+func validateAttributeKey(key string) error {
+	if key == "" || strings.ContainsAny(key, `"\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidAttributeKey, key)
+	}
+	return nil
+}
+
+// attributeContainment returns the jsonb documents matched by FindXByAttribute:
+// the attribute equals value, or the attribute is an array containing value
+func attributeContainment(key string, value interface{}) (string, string, error) {
+	scalar, err := json.Marshal(map[string]interface{}{key: value})
+	if err != nil {
+		return "", "", fmt.Errorf("invalid attribute value: %w", err)
+	}
+	element, err := json.Marshal(map[string]interface{}{key: []interface{}{value}})
+	if err != nil {
+		return "", "", fmt.Errorf("invalid attribute value: %w", err)
+	}
+	return string(scalar), string(element), nil
+}
+
+// normalizeJSONValue converts a value to its JSON-decoded form so that e.g. int and float64 compare equal
+func normalizeJSONValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// attributeMatches applies the FindXByAttribute semantics to an in-memory attribute map
+func attributeMatches(attributes map[string]interface{}, key string, value interface{}) bool {
+	actual, exists := attributes[key]
+	if !exists {
+		return false
+	}
+
+	actual = normalizeJSONValue(actual)
+	expected := normalizeJSONValue(value)
+	if reflect.DeepEqual(actual, expected) {
+		return true
+	}
+
+	if elements, ok := actual.([]interface{}); ok {
+		for _, element := range elements {
+			if reflect.DeepEqual(element, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+// attributeSearchFixtures returns subjects covering scalar, numeric and array attributes
+func attributeSearchFixtures() []*models.Subject {
+	return []*models.Subject{
+		{ID: "sub-1", SubjectType: "user", Attributes: models.JSONMap{"clearance": "confidential", "level": 3, "roles": []string{"admin", "dev"}}},
+		{ID: "sub-2", SubjectType: "user", Attributes: models.JSONMap{"clearance": "public", "level": 1, "roles": []string{"dev"}}},
+		{ID: "sub-3", SubjectType: "user", Attributes: models.JSONMap{"clearance": "confidential"}},
+		{ID: "sub-4", SubjectType: "service", Attributes: nil},
+	}
+}
+
+// assertSubjectIDs checks the returned subjects against the expected IDs in order
+func assertSubjectIDs(t *testing.T, subjects []*models.Subject, expected ...string) {
+	t.Helper()
+	if len(subjects) != len(expected) {
+		t.Fatalf("Expected subjects %v, got %d subjects", expected, len(subjects))
+	}
+	for i, subject := range subjects {
+		if subject.ID != expected[i] {
+			t.Errorf("Expected subject %s at %d, got %s", expected[i], i, subject.ID)
+		}
+	}
+}
+
+// testFindSubjectsByAttribute runs the shared search expectations against a storage
+func testFindSubjectsByAttribute(t *testing.T, store Storage) {
+	for _, subject := range attributeSearchFixtures() {
+		if err := store.CreateSubject(subject); err != nil {
+			t.Fatalf("CreateSubject failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		value    interface{}
+		expected []string
+	}{
+		{"string equality", "clearance", "confidential", []string{"sub-1", "sub-3"}},
+		{"numeric equality", "level", 3, []string{"sub-1"}},
+		{"numeric from JSON", "level", float64(1), []string{"sub-2"}},
+		{"array membership", "roles", "dev", []string{"sub-1", "sub-2"}},
+		{"no match", "clearance", "secret", []string{}},
+		{"missing attribute", "team", "platform", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subjects, err := store.FindSubjectsByAttribute(tt.key, tt.value)
+			if err != nil {
+				t.Fatalf("FindSubjectsByAttribute failed: %v", err)
+			}
+			assertSubjectIDs(t, subjects, tt.expected...)
+		})
+	}
+
+	if _, err := store.FindSubjectsByAttribute(`bad"key`, "x"); err == nil {
+		t.Error("Expected error for invalid attribute key")
+	}
+}
+
+func TestMockStorage_FindSubjectsByAttribute(t *testing.T) {
+	testFindSubjectsByAttribute(t, NewMockStorage())
+}
+
+func TestSQLiteStorage_FindSubjectsByAttribute(t *testing.T) {
+	testFindSubjectsByAttribute(t, NewSQLiteTestStorage(t))
+}
+
+func TestFindResourcesByAttribute(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			store.CreateResource(&models.Resource{ID: "res-1", ResourceType: "document", Attributes: models.JSONMap{"classification": "confidential"}})
+			store.CreateResource(&models.Resource{ID: "res-2", ResourceType: "document", Attributes: models.JSONMap{"classification": "public"}})
+
+			resources, err := store.FindResourcesByAttribute("classification", "confidential")
+			if err != nil {
+				t.Fatalf("FindResourcesByAttribute failed: %v", err)
+			}
+			if len(resources) != 1 || resources[0].ID != "res-1" {
+				t.Errorf("Expected [res-1], got %v", resources)
+			}
+		})
+	}
+}
+
+func TestFindResourcesByTag(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			store.CreateResource(&models.Resource{ID: "res-1", ResourceType: "document", Tags: models.JSONStringSlice{" pii = true ", "team=payments", "pii=true"}})
+			store.CreateResource(&models.Resource{ID: "res-2", ResourceType: "document", Tags: models.JSONStringSlice{"pii=false"}})
+			store.CreateResource(&models.Resource{ID: "res-3", ResourceType: "document"})
+
+			stored, err := store.GetResource("res-1")
+			if err != nil || len(stored.Tags) != 2 || stored.Tags[0] != "pii=true" || stored.Tags[1] != "team=payments" {
+				t.Fatalf("Expected normalized tag set, got %v (err=%v)", stored.Tags, err)
+			}
+
+			resources, err := store.FindResourcesByTag("pii=true")
+			if err != nil {
+				t.Fatalf("FindResourcesByTag failed: %v", err)
+			}
+			if len(resources) != 1 || resources[0].ID != "res-1" {
+				t.Errorf("Expected [res-1], got %v", resources)
+			}
+
+			if _, err := store.FindResourcesByTag(" "); !errors.Is(err, ErrInvalidTag) {
+				t.Errorf("Expected ErrInvalidTag, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"abac_go_example/models"
+)
+
+// AuditArchiver stores expired audit logs before they are removed from the database
+type AuditArchiver interface {
+	Archive(ctx context.Context, name string, logs []*models.AuditLog) error
+}
+
+// ObjectStore is the minimal object storage API used for archival (e.g. an S3 bucket adapter)
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// encodeAuditArchive encodes logs as gzip-compressed newline-delimited JSON
+func encodeAuditArchive(logs []*models.AuditLog) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	encoder := json.NewEncoder(writer)
+	for _, auditLog := range logs {
+		if err := encoder.Encode(auditLog); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// maxArchiveAttempts bounds the numeric suffixes tried for an archive file name
+const maxArchiveAttempts = 100
+
+// FileAuditArchiver writes each archive to <dir>/<name>.ndjson.gz
+type FileAuditArchiver struct {
+	dir string
+}
+
+// NewFileAuditArchiver creates an archiver writing into dir
+func NewFileAuditArchiver(dir string) *FileAuditArchiver {
+	return &FileAuditArchiver{dir: dir}
+}
+
+// Archive writes the logs to a new file. Existing archives are never overwritten:
+// a numeric suffix is added when the name is already taken (e.g. after a retried run).
+func (a *FileAuditArchiver) Archive(ctx context.Context, name string, logs []*models.AuditLog) error {
+	data, err := encodeAuditArchive(logs)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return err
+	}
+
+	path := filepath.Join(a.dir, name+".ndjson.gz")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	for attempt := 2; errors.Is(err, fs.ErrExist) && attempt <= maxArchiveAttempts; attempt++ {
+		path = filepath.Join(a.dir, fmt.Sprintf("%s-%d.ndjson.gz", name, attempt))
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create archive %s: %w", path, err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ObjectStoreAuditArchiver uploads each archive as <prefix><name>.ndjson.gz
+type ObjectStoreAuditArchiver struct {
+	store  ObjectStore
+	prefix string
+}
+
+// NewObjectStoreAuditArchiver creates an archiver uploading to store under prefix (e.g. "audit/")
+func NewObjectStoreAuditArchiver(store ObjectStore, prefix string) *ObjectStoreAuditArchiver {
+	return &ObjectStoreAuditArchiver{store: store, prefix: prefix}
+}
+
+// Archive uploads the logs as a single object
+func (a *ObjectStoreAuditArchiver) Archive(ctx context.Context, name string, logs []*models.AuditLog) error {
+	data, err := encodeAuditArchive(logs)
+	if err != nil {
+		return err
+	}
+	return a.store.PutObject(ctx, a.prefix+name+".ndjson.gz", data, "application/gzip")
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+// auditPartitionPrefix prefixes monthly audit_logs partitions, e.g. audit_logs_y2024m06
+const auditPartitionPrefix = "audit_logs_y"
+
+// AuditPartition is one monthly range of the audit_logs table
+type AuditPartition struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"` // Inclusive
+	End   time.Time `json:"end"`   // Exclusive
+}
+
+// AuditRetentionStore is implemented by storages that support audit partitioning and retention
+type AuditRetentionStore interface {
+	// EnsureAuditPartitions creates the partitions covering from's month and the following months
+	EnsureAuditPartitions(ctx context.Context, from time.Time, months int) error
+	// ListAuditPartitions returns existing monthly partitions ordered by start time
+	ListAuditPartitions(ctx context.Context) ([]AuditPartition, error)
+	// AuditLogsBetween returns the audit logs created in [from, to) ordered by ID
+	AuditLogsBetween(ctx context.Context, from, to time.Time) ([]*models.AuditLog, error)
+	// DropAuditPartition removes a partition and all of its rows
+	DropAuditPartition(ctx context.Context, name string) error
+	// DeleteAuditLogsBefore deletes audit logs created before cutoff and returns the count
+	DeleteAuditLogsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// AuditRetentionConfig configures audit partition maintenance and retention
+type AuditRetentionConfig struct {
+	RetentionDays   int           `json:"retention_days"`   // Audit logs older than this are archived and removed
+	PrecreateMonths int           `json:"precreate_months"` // Future monthly partitions created ahead of time
+	Interval        time.Duration `json:"interval"`         // How often Start runs the job
+}
+
+// DefaultAuditRetentionConfig returns the default audit retention configuration
+func DefaultAuditRetentionConfig() *AuditRetentionConfig {
+	return &AuditRetentionConfig{
+		RetentionDays:   90,
+		PrecreateMonths: 2,
+		Interval:        24 * time.Hour,
+	}
+}
+
+// AuditRetentionConfigFromEnv reads AUDIT_RETENTION_DAYS, AUDIT_PARTITION_PRECREATE_MONTHS and
+// AUDIT_RETENTION_INTERVAL. It returns nil (retention disabled) when AUDIT_RETENTION_DAYS is unset.
+func AuditRetentionConfigFromEnv() *AuditRetentionConfig {
+	days, err := strconv.Atoi(getEnv("AUDIT_RETENTION_DAYS", ""))
+	if err != nil || days <= 0 {
+		return nil
+	}
+
+	config := DefaultAuditRetentionConfig()
+	config.RetentionDays = days
+	config.PrecreateMonths = getEnvAsInt("AUDIT_PARTITION_PRECREATE_MONTHS", config.PrecreateMonths)
+	if interval, err := time.ParseDuration(getEnv("AUDIT_RETENTION_INTERVAL", "")); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	return config
+}
+
+// AuditRetentionResult summarizes one retention run
+type AuditRetentionResult struct {
+	Cutoff            time.Time `json:"cutoff"`
+	DroppedPartitions []string  `json:"dropped_partitions"`
+	ArchivedLogs      int       `json:"archived_logs"`
+	DeletedLogs       int64     `json:"deleted_logs"`
+}
+
+// AuditRetentionJob keeps future audit partitions in place and archives then removes
+// audit logs older than the retention period. Expired partitions are dropped whole;
+// remaining expired rows (e.g. in the default partition) are deleted.
+type AuditRetentionJob struct {
+	store    AuditRetentionStore
+	archiver AuditArchiver
+	config   *AuditRetentionConfig
+	clock    clock.Clock
+}
+
+// NewAuditRetentionJob creates a retention job. A nil archiver deletes without archiving.
+func NewAuditRetentionJob(store AuditRetentionStore, archiver AuditArchiver, config *AuditRetentionConfig) *AuditRetentionJob {
+	if config == nil {
+		config = DefaultAuditRetentionConfig()
+	}
+	return &AuditRetentionJob{
+		store:    store,
+		archiver: archiver,
+		config:   config,
+		clock:    clock.NewRealClock(),
+	}
+}
+
+// SetClock configures the clock used to compute the retention cutoff (nil restores real time)
+func (j *AuditRetentionJob) SetClock(c clock.Clock) {
+	j.clock = clock.OrReal(c)
+}
+
+// RunOnce performs a single maintenance run
+func (j *AuditRetentionJob) RunOnce(ctx context.Context) (*AuditRetentionResult, error) {
+	now := j.clock.Now().UTC()
+	result := &AuditRetentionResult{
+		Cutoff:            now.AddDate(0, 0, -j.config.RetentionDays),
+		DroppedPartitions: []string{},
+	}
+
+	if err := j.store.EnsureAuditPartitions(ctx, now, j.config.PrecreateMonths); err != nil {
+		return result, fmt.Errorf("failed to create audit partitions: %w", err)
+	}
+
+	partitions, err := j.store.ListAuditPartitions(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to list audit partitions: %w", err)
+	}
+
+	for _, partition := range partitions {
+		if partition.End.After(result.Cutoff) {
+			continue
+		}
+
+		archived, err := j.archive(ctx, partition.Name, partition.Start, partition.End)
+		if err != nil {
+			return result, err
+		}
+		if err := j.store.DropAuditPartition(ctx, partition.Name); err != nil {
+			return result, fmt.Errorf("failed to drop audit partition %s: %w", partition.Name, err)
+		}
+		result.ArchivedLogs += archived
+		result.DroppedPartitions = append(result.DroppedPartitions, partition.Name)
+	}
+
+	name := "audit_logs_before_" + result.Cutoff.Format("20060102T150405Z")
+	archived, err := j.archive(ctx, name, time.Time{}, result.Cutoff)
+	if err != nil {
+		return result, err
+	}
+	result.ArchivedLogs += archived
+
+	deleted, err := j.store.DeleteAuditLogsBefore(ctx, result.Cutoff)
+	if err != nil {
+		return result, fmt.Errorf("failed to delete expired audit logs: %w", err)
+	}
+	result.DeletedLogs = deleted
+
+	return result, nil
+}
+
+// archive hands the logs in [from, to) to the archiver and returns how many were archived
+func (j *AuditRetentionJob) archive(ctx context.Context, name string, from, to time.Time) (int, error) {
+	if j.archiver == nil {
+		return 0, nil
+	}
+
+	logs, err := j.store.AuditLogsBetween(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audit logs for %s: %w", name, err)
+	}
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	if err := j.archiver.Archive(ctx, name, logs); err != nil {
+		return 0, fmt.Errorf("failed to archive %s: %w", name, err)
+	}
+	return len(logs), nil
+}
+
+// Start runs the job immediately and then every configured interval until ctx is cancelled
+func (j *AuditRetentionJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Audit retention run failed: %v", err)
+		} else if len(result.DroppedPartitions) > 0 || result.DeletedLogs > 0 {
+			log.Printf("Audit retention: dropped %d partitions, archived %d logs, deleted %d logs older than %s",
+				len(result.DroppedPartitions), result.ArchivedLogs, result.DeletedLogs, result.Cutoff.Format(time.RFC3339))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// auditMonthStart returns the first instant of t's month in UTC
+func auditMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// auditPartitionFor returns the monthly partition containing t
+func auditPartitionFor(t time.Time) AuditPartition {
+	start := auditMonthStart(t)
+	return AuditPartition{
+		Name:  fmt.Sprintf("%s%04dm%02d", auditPartitionPrefix, start.Year(), int(start.Month())),
+		Start: start,
+		End:   start.AddDate(0, 1, 0),
+	}
+}
+
+// parseAuditPartitionName parses names produced by auditPartitionFor
+func parseAuditPartitionName(name string) (AuditPartition, bool) {
+	var year, month int
+	if _, err := fmt.Sscanf(name, auditPartitionPrefix+"%04dm%02d", &year, &month); err != nil || month < 1 || month > 12 {
+		return AuditPartition{}, false
+	}
+
+	partition := auditPartitionFor(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC))
+	if partition.Name != name {
+		return AuditPartition{}, false
+	}
+	return partition, true
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+// seedAuditLogs appends audit logs with fixed creation times
+func seedAuditLogs(m *MockStorage, times ...time.Time) {
+	for i, createdAt := range times {
+		m.auditLogs = append(m.auditLogs, &models.AuditLog{
+			ID:        int64(i + 1),
+			RequestID: "req-" + createdAt.Format("20060102"),
+			Decision:  "permit",
+			CreatedAt: createdAt,
+		})
+	}
+}
+
+// countArchiveLines returns the number of NDJSON records in a gzip archive
+func countArchiveLines(t *testing.T, path string) int {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Invalid gzip archive: %v", err)
+	}
+	scanner := bufio.NewScanner(reader)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
+
+func TestAuditRetentionJob(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 12, 0, 0, 0, time.UTC) }
+
+	mockStorage := NewMockStorage()
+	seedAuditLogs(mockStorage,
+		day(time.January, 5), day(time.January, 20),
+		day(time.February, 10),
+		day(time.March, 1), day(time.March, 20),
+		day(time.April, 10),
+	)
+
+	archiveDir := t.TempDir()
+	job := NewAuditRetentionJob(mockStorage, NewFileAuditArchiver(archiveDir), &AuditRetentionConfig{RetentionDays: 30, PrecreateMonths: 2})
+	job.SetClock(clock.NewMockClock(day(time.April, 15)))
+
+	result, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	// Cutoff 2024-03-16: January and February partitions expire whole, 1 March row is deleted
+	if len(result.DroppedPartitions) != 2 || result.DroppedPartitions[0] != "audit_logs_y2024m01" || result.DroppedPartitions[1] != "audit_logs_y2024m02" {
+		t.Errorf("Unexpected dropped partitions: %v", result.DroppedPartitions)
+	}
+	if result.ArchivedLogs != 4 || result.DeletedLogs != 1 {
+		t.Errorf("Expected 4 archived and 1 deleted log, got %+v", result)
+	}
+
+	remaining, _ := mockStorage.GetAuditLogs(100, 0)
+	if len(remaining) != 2 {
+		t.Fatalf("Expected 2 remaining logs, got %d", len(remaining))
+	}
+	for _, auditLog := range remaining {
+		if auditLog.CreatedAt.Before(result.Cutoff) {
+			t.Errorf("Log %d older than cutoff was kept", auditLog.ID)
+		}
+	}
+
+	if lines := countArchiveLines(t, filepath.Join(archiveDir, "audit_logs_y2024m01.ndjson.gz")); lines != 2 {
+		t.Errorf("Expected 2 archived January logs, got %d", lines)
+	}
+	if lines := countArchiveLines(t, filepath.Join(archiveDir, "audit_logs_before_20240316T120000Z.ndjson.gz")); lines != 1 {
+		t.Errorf("Expected 1 archived remainder log, got %d", lines)
+	}
+
+	// A second run has nothing left to do
+	result, err = job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("Second RunOnce failed: %v", err)
+	}
+	if len(result.DroppedPartitions) != 0 || result.ArchivedLogs != 0 || result.DeletedLogs != 0 {
+		t.Errorf("Expected an empty second run, got %+v", result)
+	}
+}
+
+// memoryObjectStore is an in-memory ObjectStore
+type memoryObjectStore map[string][]byte
+
+func (s memoryObjectStore) PutObject(ctx context.Context, key string, body []byte, contentType string) error {
+	s[key] = body
+	return nil
+}
+
+func TestObjectStoreAuditArchiver(t *testing.T) {
+	store := memoryObjectStore{}
+	archiver := NewObjectStoreAuditArchiver(store, "audit/")
+
+	logs := []*models.AuditLog{{ID: 1, RequestID: "req-1"}}
+	if err := archiver.Archive(context.Background(), "audit_logs_y2024m01", logs); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if len(store["audit/audit_logs_y2024m01.ndjson.gz"]) == 0 {
+		t.Errorf("Expected archive object to be uploaded, got keys %v", store)
+	}
+}
+
+func TestFileAuditArchiverNeverOverwrites(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewFileAuditArchiver(dir)
+	logs := []*models.AuditLog{{ID: 1, RequestID: "req-1"}}
+
+	for i := 0; i < 2; i++ {
+		if err := archiver.Archive(context.Background(), "audit_logs_y2024m01", logs); err != nil {
+			t.Fatalf("Archive %d failed: %v", i, err)
+		}
+	}
+
+	for _, name := range []string{"audit_logs_y2024m01.ndjson.gz", "audit_logs_y2024m01-2.ndjson.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected archive %s: %v", name, err)
+		}
+	}
+}
+
+func TestParseAuditPartitionName(t *testing.T) {
+	partition, ok := parseAuditPartitionName("audit_logs_y2024m12")
+	if !ok || !partition.Start.Equal(time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)) ||
+		!partition.End.Equal(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected partition: %+v (ok=%v)", partition, ok)
+	}
+
+	for _, name := range []string{"audit_logs_default", "audit_logs_y2024m13", "audit_logs_y2024m01; DROP TABLE users"} {
+		if _, ok := parseAuditPartitionName(name); ok {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestAuditRetentionConfigFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_RETENTION_DAYS", "")
+	if config := AuditRetentionConfigFromEnv(); config != nil {
+		t.Errorf("Expected nil config when AUDIT_RETENTION_DAYS is unset, got %+v", config)
+	}
+
+	t.Setenv("AUDIT_RETENTION_DAYS", "30")
+	t.Setenv("AUDIT_RETENTION_INTERVAL", "1h")
+	config := AuditRetentionConfigFromEnv()
+	if config == nil || config.RetentionDays != 30 || config.Interval != time.Hour {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
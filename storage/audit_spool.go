@@ -0,0 +1,353 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"abac_go_example/models"
+)
+
+// AuditWriter persists audit log entries; every Storage is one
+type AuditWriter interface {
+	LogAudit(auditLog *models.AuditLog) error
+}
+
+// auditSpoolPrefix and auditSpoolSuffix name spool segments, e.g. audit-00000000000000000003.ndjson
+const (
+	auditSpoolPrefix = "audit-"
+	auditSpoolSuffix = ".ndjson"
+)
+
+// AuditSpoolConfig configures the local disk buffer of StoreAndForwardAuditWriter
+type AuditSpoolConfig struct {
+	Dir             string        `json:"dir"`               // Spool directory, created when missing
+	MaxSegmentBytes int64         `json:"max_segment_bytes"` // A new segment is started once the current one reaches this size
+	MaxSegments     int           `json:"max_segments"`      // Oldest segments are dropped beyond this count
+	ReplayInterval  time.Duration `json:"replay_interval"`   // How often Start replays spooled entries
+}
+
+// DefaultAuditSpoolConfig returns the default spool configuration (64 segments of 4 MiB)
+func DefaultAuditSpoolConfig() *AuditSpoolConfig {
+	return &AuditSpoolConfig{
+		Dir:             "audit-spool",
+		MaxSegmentBytes: 4 << 20,
+		MaxSegments:     64,
+		ReplayInterval:  30 * time.Second,
+	}
+}
+
+// AuditSpoolConfigFromEnv reads AUDIT_SPOOL_DIR, AUDIT_SPOOL_MAX_SEGMENT_BYTES, AUDIT_SPOOL_MAX_SEGMENTS
+// and AUDIT_SPOOL_REPLAY_INTERVAL. It returns nil (audit writes fail during outages) when AUDIT_SPOOL_DIR is unset.
+func AuditSpoolConfigFromEnv() *AuditSpoolConfig {
+	dir := getEnv("AUDIT_SPOOL_DIR", "")
+	if dir == "" {
+		return nil
+	}
+
+	config := DefaultAuditSpoolConfig()
+	config.Dir = dir
+	if size, err := strconv.ParseInt(getEnv("AUDIT_SPOOL_MAX_SEGMENT_BYTES", ""), 10, 64); err == nil && size > 0 {
+		config.MaxSegmentBytes = size
+	}
+	if segments := getEnvAsInt("AUDIT_SPOOL_MAX_SEGMENTS", config.MaxSegments); segments > 0 {
+		config.MaxSegments = segments
+	}
+	if interval, err := time.ParseDuration(getEnv("AUDIT_SPOOL_REPLAY_INTERVAL", "")); err == nil && interval > 0 {
+		config.ReplayInterval = interval
+	}
+	return config
+}
+
+// AuditSpoolStats counts spooled, replayed and dropped audit entries
+type AuditSpoolStats struct {
+	Pending  int64 `json:"pending"`  // Entries on disk waiting for replay
+	Spooled  int64 `json:"spooled"`  // Entries buffered because the target failed
+	Replayed int64 `json:"replayed"` // Spooled entries written to the target
+	Dropped  int64 `json:"dropped"`  // Entries lost to the segment bound or unreadable lines
+}
+
+// StoreAndForwardAuditWriter writes audit entries to a target (the audit database) and, when the target
+// fails, buffers them in newline-delimited JSON segments on local disk. Replay writes them back in order
+// once the target is reachable again, so decision evidence survives storage incidents. The spool is
+// bounded: beyond MaxSegments the oldest segment is dropped. It is safe for concurrent use.
+type StoreAndForwardAuditWriter struct {
+	target AuditWriter
+	config AuditSpoolConfig
+
+	mu       sync.Mutex
+	segments []int64 // Sequence numbers of segments on disk, oldest first
+	sizes    map[int64]int64
+	lines    map[int64]int64
+	stats    AuditSpoolStats
+	outage   bool // Entries were spooled since the last successful replay
+}
+
+// NewStoreAndForwardAuditWriter creates a writer spooling into config.Dir. Segments left by a previous
+// process are kept and replayed.
+func NewStoreAndForwardAuditWriter(target AuditWriter, config *AuditSpoolConfig) (*StoreAndForwardAuditWriter, error) {
+	if config == nil {
+		config = DefaultAuditSpoolConfig()
+	}
+	if err := os.MkdirAll(config.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit spool directory: %w", err)
+	}
+
+	writer := &StoreAndForwardAuditWriter{
+		target: target,
+		config: *config,
+		sizes:  make(map[int64]int64),
+		lines:  make(map[int64]int64),
+	}
+	if err := writer.loadSegments(); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// LogAudit writes the entry to the target, spooling it when the target fails.
+// It only returns an error when the entry could be written neither to the target nor to the spool.
+func (w *StoreAndForwardAuditWriter) LogAudit(auditLog *models.AuditLog) error {
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = time.Now() // Keep the original time when the entry is replayed later
+	}
+	targetErr := w.target.LogAudit(auditLog)
+	if targetErr == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.spool(auditLog); err != nil {
+		return fmt.Errorf("audit write failed (%v) and could not be spooled: %w", targetErr, err)
+	}
+	if !w.outage {
+		w.outage = true
+		log.Printf("Warning: audit storage unavailable, spooling audit logs to %s: %v", w.config.Dir, targetErr)
+	}
+	return nil
+}
+
+// Replay writes spooled entries to the target in order and removes them from disk. It stops at the
+// first failure, keeping the remaining entries, and returns the number of entries replayed.
+func (w *StoreAndForwardAuditWriter) Replay(ctx context.Context) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	replayed := 0
+	for len(w.segments) > 0 {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+		seq := w.segments[0]
+		count, err := w.replaySegment(seq)
+		replayed += count
+		if err != nil {
+			return replayed, err
+		}
+		w.removeSegment(seq)
+	}
+	if w.outage {
+		w.outage = false
+		log.Printf("Audit storage restored, spool drained")
+	}
+	return replayed, nil
+}
+
+// Stats returns a snapshot of the spool counters
+func (w *StoreAndForwardAuditWriter) Stats() AuditSpoolStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// Start replays spooled entries every ReplayInterval until ctx is cancelled
+func (w *StoreAndForwardAuditWriter) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		if w.Stats().Pending > 0 {
+			replayed, err := w.Replay(ctx)
+			if replayed > 0 {
+				log.Printf("Audit spool: replayed %d audit logs", replayed)
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Audit spool replay paused: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// spool appends the entry to the newest segment, rotating and enforcing the segment bound
+func (w *StoreAndForwardAuditWriter) spool(auditLog *models.AuditLog) error {
+	entry := *auditLog
+	entry.ID = 0 // Assigned by the target on replay
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit log: %w", err)
+	}
+	data = append(data, '\n')
+
+	if len(w.segments) == 0 || w.sizes[w.segments[len(w.segments)-1]] >= w.config.MaxSegmentBytes {
+		next := int64(1)
+		if len(w.segments) > 0 {
+			next = w.segments[len(w.segments)-1] + 1
+		}
+		w.segments = append(w.segments, next)
+	}
+	seq := w.segments[len(w.segments)-1]
+
+	file, err := os.OpenFile(w.segmentPath(seq), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		if w.sizes[seq] == 0 {
+			w.removeSegment(seq)
+		}
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	w.sizes[seq] += int64(len(data))
+	w.lines[seq]++
+	w.stats.Pending++
+	w.stats.Spooled++
+
+	for len(w.segments) > w.config.MaxSegments {
+		oldest := w.segments[0]
+		dropped := w.lines[oldest]
+		w.removeSegment(oldest)
+		w.stats.Dropped += dropped
+		log.Printf("Warning: audit spool full, dropped %d audit logs of segment %d", dropped, oldest)
+	}
+	return nil
+}
+
+// replaySegment writes the entries of a segment to the target. On failure the segment is rewritten
+// with the entries not yet replayed.
+func (w *StoreAndForwardAuditWriter) replaySegment(seq int64) (int, error) {
+	path := w.segmentPath(seq)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audit spool segment: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	replayed := 0
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		var auditLog models.AuditLog
+		if err := json.Unmarshal(line, &auditLog); err != nil {
+			log.Printf("Warning: dropping unreadable audit spool entry in %s: %v", path, err)
+			w.stats.Dropped++
+			w.stats.Pending--
+			w.lines[seq]--
+			continue
+		}
+		if err := w.target.LogAudit(&auditLog); err != nil {
+			remaining := append(bytes.Join(lines[i:], []byte("\n")), '\n')
+			if writeErr := os.WriteFile(path, remaining, 0o640); writeErr != nil {
+				return replayed, fmt.Errorf("audit replay failed (%v) and segment could not be rewritten: %w", err, writeErr)
+			}
+			w.sizes[seq] = int64(len(remaining))
+			return replayed, fmt.Errorf("failed to replay audit log %s: %w", auditLog.RequestID, err)
+		}
+		replayed++
+		w.stats.Replayed++
+		w.stats.Pending--
+		w.lines[seq]--
+	}
+	return replayed, nil
+}
+
+// removeSegment deletes a segment file and forgets it
+func (w *StoreAndForwardAuditWriter) removeSegment(seq int64) {
+	if err := os.Remove(w.segmentPath(seq)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to remove audit spool segment %d: %v", seq, err)
+	}
+	for i, existing := range w.segments {
+		if existing == seq {
+			w.segments = append(w.segments[:i], w.segments[i+1:]...)
+			break
+		}
+	}
+	w.stats.Pending -= w.lines[seq]
+	delete(w.sizes, seq)
+	delete(w.lines, seq)
+}
+
+// loadSegments finds the segments left in the spool directory and counts their entries
+func (w *StoreAndForwardAuditWriter) loadSegments() error {
+	entries, err := os.ReadDir(w.config.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read audit spool directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, auditSpoolPrefix) || !strings.HasSuffix(name, auditSpoolSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, auditSpoolPrefix), auditSpoolSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		file, err := os.Open(filepath.Join(w.config.Dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to open audit spool segment: %w", err)
+		}
+		var size, lines int64
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		for scanner.Scan() {
+			size += int64(len(scanner.Bytes())) + 1
+			if len(scanner.Bytes()) > 0 {
+				lines++
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read audit spool segment %s: %w", name, err)
+		}
+
+		w.segments = append(w.segments, seq)
+		w.sizes[seq] = size
+		w.lines[seq] = lines
+		w.stats.Pending += lines
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i] < w.segments[j] })
+	if w.stats.Pending > 0 {
+		w.outage = true
+		log.Printf("Audit spool: %d audit logs pending replay from a previous run", w.stats.Pending)
+	}
+	return nil
+}
+
+// segmentPath returns the file of a segment
+func (w *StoreAndForwardAuditWriter) segmentPath(seq int64) string {
+	return filepath.Join(w.config.Dir, fmt.Sprintf("%s%020d%s", auditSpoolPrefix, seq, auditSpoolSuffix))
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+// flakyAuditWriter fails while down is set, or once it has accepted failAfter entries (when > 0)
+type flakyAuditWriter struct {
+	down      bool
+	failAfter int
+	logs      []*models.AuditLog
+}
+
+func (f *flakyAuditWriter) LogAudit(auditLog *models.AuditLog) error {
+	if f.down || (f.failAfter > 0 && len(f.logs) >= f.failAfter) {
+		return errors.New("database unavailable")
+	}
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+func spoolTestConfig(t *testing.T) *AuditSpoolConfig {
+	config := DefaultAuditSpoolConfig()
+	config.Dir = t.TempDir()
+	return config
+}
+
+func TestStoreAndForwardAuditWriter_SpoolsAndReplays(t *testing.T) {
+	target := &flakyAuditWriter{down: true}
+	writer, err := NewStoreAndForwardAuditWriter(target, spoolTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+
+	createdAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"req-1", "req-2", "req-3"} {
+		if err := writer.LogAudit(&models.AuditLog{RequestID: id, Decision: "deny", CreatedAt: createdAt}); err != nil {
+			t.Fatalf("Expected entry to be spooled, got %v", err)
+		}
+	}
+	if stats := writer.Stats(); stats.Pending != 3 || stats.Spooled != 3 {
+		t.Fatalf("Expected 3 pending entries, got %+v", stats)
+	}
+
+	// Still down: nothing is lost
+	if replayed, err := writer.Replay(context.Background()); err == nil || replayed != 0 {
+		t.Fatalf("Expected replay to fail while the target is down, got %d %v", replayed, err)
+	}
+
+	target.down = false
+	replayed, err := writer.Replay(context.Background())
+	if err != nil || replayed != 3 {
+		t.Fatalf("Expected 3 replayed entries, got %d %v", replayed, err)
+	}
+	if len(target.logs) != 3 || target.logs[0].RequestID != "req-1" || target.logs[2].RequestID != "req-3" {
+		t.Fatalf("Expected entries replayed in order, got %+v", target.logs)
+	}
+	if !target.logs[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected original CreatedAt to be kept, got %v", target.logs[0].CreatedAt)
+	}
+	if stats := writer.Stats(); stats.Pending != 0 || stats.Replayed != 3 {
+		t.Errorf("Unexpected stats after replay: %+v", stats)
+	}
+
+	files, _ := os.ReadDir(writer.config.Dir)
+	if len(files) != 0 {
+		t.Errorf("Expected spool directory to be empty, got %d files", len(files))
+	}
+}
+
+func TestStoreAndForwardAuditWriter_PartialReplayKeepsRemainder(t *testing.T) {
+	target := &flakyAuditWriter{down: true}
+	config := spoolTestConfig(t)
+	writer, err := NewStoreAndForwardAuditWriter(target, config)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for _, id := range []string{"req-1", "req-2", "req-3"} {
+		writer.LogAudit(&models.AuditLog{RequestID: id})
+	}
+
+	// The target fails again after one entry
+	target.down = false
+	target.failAfter = 1
+	if replayed, err := writer.Replay(context.Background()); err == nil || replayed != 1 {
+		t.Fatalf("Expected 1 entry replayed before failure, got %d %v", replayed, err)
+	}
+	if stats := writer.Stats(); stats.Pending != 2 {
+		t.Fatalf("Expected 2 pending entries, got %+v", stats)
+	}
+
+	// A restarted process picks up the remaining entries
+	restarted, err := NewStoreAndForwardAuditWriter(target, config)
+	if err != nil {
+		t.Fatalf("Failed to reopen spool: %v", err)
+	}
+	if stats := restarted.Stats(); stats.Pending != 2 {
+		t.Fatalf("Expected 2 pending entries after restart, got %+v", stats)
+	}
+	target.failAfter = 0
+	if replayed, err := restarted.Replay(context.Background()); err != nil || replayed != 2 {
+		t.Fatalf("Expected 2 replayed entries, got %d %v", replayed, err)
+	}
+	if len(target.logs) != 3 || target.logs[1].RequestID != "req-2" || target.logs[2].RequestID != "req-3" {
+		t.Errorf("Unexpected replayed entries: %+v", target.logs)
+	}
+}
+
+func TestStoreAndForwardAuditWriter_BoundDropsOldestSegment(t *testing.T) {
+	target := &flakyAuditWriter{down: true}
+	config := spoolTestConfig(t)
+	config.MaxSegmentBytes = 1 // One entry per segment
+	config.MaxSegments = 2
+	writer, err := NewStoreAndForwardAuditWriter(target, config)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for _, id := range []string{"req-1", "req-2", "req-3"} {
+		writer.LogAudit(&models.AuditLog{RequestID: id})
+	}
+
+	if stats := writer.Stats(); stats.Pending != 2 || stats.Dropped != 1 {
+		t.Fatalf("Expected the oldest entry to be dropped, got %+v", stats)
+	}
+
+	target.down = false
+	if _, err := writer.Replay(context.Background()); err != nil {
+		t.Fatalf("Unexpected replay error: %v", err)
+	}
+	if len(target.logs) != 2 || target.logs[0].RequestID != "req-2" {
+		t.Errorf("Expected req-2 and req-3 to survive, got %+v", target.logs)
+	}
+}
+
+func TestStoreAndForwardAuditWriter_WritesThroughWhenAvailable(t *testing.T) {
+	mockStorage := NewMockStorage()
+	writer, err := NewStoreAndForwardAuditWriter(mockStorage, spoolTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	if err := writer.LogAudit(&models.AuditLog{RequestID: "req-1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if logs, _ := mockStorage.GetAuditLogs(10, 0); len(logs) != 1 {
+		t.Errorf("Expected the entry in storage, got %d", len(logs))
+	}
+	if stats := writer.Stats(); stats.Spooled != 0 {
+		t.Errorf("Expected nothing spooled, got %+v", stats)
+	}
+}
+
+func TestAuditSpoolConfigFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_SPOOL_DIR", "")
+	if config := AuditSpoolConfigFromEnv(); config != nil {
+		t.Errorf("Expected nil without AUDIT_SPOOL_DIR, got %+v", config)
+	}
+
+	t.Setenv("AUDIT_SPOOL_DIR", "/var/spool/abac")
+	t.Setenv("AUDIT_SPOOL_MAX_SEGMENTS", "8")
+	t.Setenv("AUDIT_SPOOL_REPLAY_INTERVAL", "10s")
+	config := AuditSpoolConfigFromEnv()
+	if config == nil || config.Dir != "/var/spool/abac" || config.MaxSegments != 8 ||
+		config.ReplayInterval != 10*time.Second || config.MaxSegmentBytes != DefaultAuditSpoolConfig().MaxSegmentBytes {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
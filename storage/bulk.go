@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// BulkInsertBatchSize is the number of rows per multi-row INSERT used by bulk methods
+const BulkInsertBatchSize = 500
+
+// BulkCreateSubjects inserts subjects (and their attribute snapshots) in one transaction.
+// Either every subject is created or none is; callers needing per-record errors retry with CreateSubject.
+func (s *PostgreSQLStorage) BulkCreateSubjects(subjects []*models.Subject) error {
+	if len(subjects) == 0 {
+		return nil
+	}
+	if err := validateSubjects(s.subjectTypes, subjects...); err != nil {
+		return err
+	}
+
+	snapshots := make([]*models.AttributeSnapshot, len(subjects))
+	for i, subject := range subjects {
+		snapshots[i] = newAttributeSnapshot(models.SnapshotEntitySubject, subject.ID, subject.Attributes)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(subjects, BulkInsertBatchSize).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(snapshots, BulkInsertBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bulk create subjects: %w", err)
+	}
+	for _, subject := range subjects {
+		s.publish(events.Created, events.EntitySubject, subject.ID, subject.TenantID)
+	}
+	return nil
+}
+
+// BulkCreateResources inserts resources (and their attribute snapshots) in one transaction
+func (s *PostgreSQLStorage) BulkCreateResources(resources []*models.Resource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	snapshots := make([]*models.AttributeSnapshot, len(resources))
+	for i, resource := range resources {
+		resource.Tags = models.NormalizeTags(resource.Tags)
+		snapshots[i] = newAttributeSnapshot(models.SnapshotEntityResource, resource.ID, resource.Attributes)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(resources, BulkInsertBatchSize).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(snapshots, BulkInsertBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bulk create resources: %w", err)
+	}
+	for _, resource := range resources {
+		s.publish(events.Created, events.EntityResource, resource.ID, resource.TenantID)
+	}
+	return nil
+}
+
+// BulkCreatePolicies inserts policies in one transaction
+func (s *PostgreSQLStorage) BulkCreatePolicies(policies []*models.Policy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	for _, policy := range policies {
+		policy.Revision = initialPolicyRevision
+		policy.Tags = models.NormalizeTags(policy.Tags)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(policies, BulkInsertBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bulk create policies: %w", err)
+	}
+	for _, policy := range policies {
+		s.publish(events.Created, events.EntityPolicy, policy.ID, policy.TenantID)
+	}
+	return nil
+}
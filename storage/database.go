@@ -47,19 +47,8 @@ func NewDatabaseConnection(config *DatabaseConfig) (*gorm.DB, error) {
 		config = DefaultDatabaseConfig()
 	}
 
-	// Configure GORM logger
-	gormLogger := logger.Default.LogMode(logger.Info)
-	if getEnv("DB_LOG_LEVEL", "info") == "silent" {
-		gormLogger = logger.Default.LogMode(logger.Silent)
-	}
-
 	// Open database connection
-	db, err := gorm.Open(postgres.Open(config.DSN()), &gorm.Config{
-		Logger: gormLogger,
-		NowFunc: func() time.Time {
-			return time.Now().UTC()
-		},
-	})
+	db, err := gorm.Open(postgres.Open(config.DSN()), newGormConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -78,6 +67,22 @@ func NewDatabaseConnection(config *DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// newGormConfig returns the GORM configuration shared by all database backends
+func newGormConfig() *gorm.Config {
+	// Configure GORM logger
+	gormLogger := logger.Default.LogMode(logger.Info)
+	if getEnv("DB_LOG_LEVEL", "info") == "silent" {
+		gormLogger = logger.Default.LogMode(logger.Silent)
+	}
+
+	return &gorm.Config{
+		Logger: gormLogger,
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
 // Helper functions for environment variables
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// MaxElevationDuration bounds how long an elevation may last; longer grants belong on the subject
+const MaxElevationDuration = 24 * time.Hour
+
+// ErrInvalidElevation is returned when creating an elevation without a subject, without roles or
+// attributes, without a justification, or with an expiry outside (now, now+MaxElevationDuration]
+var ErrInvalidElevation = errors.New("invalid elevation")
+
+// ElevationStore is implemented by storages that keep time-limited elevations: roles and
+// attributes the attribute resolver grants a subject until they expire. Writes publish
+// events.EntityElevation.
+type ElevationStore interface {
+	// CreateElevation validates and stores an elevation (CreatedAt defaults to now)
+	CreateElevation(elevation *models.Elevation) error
+	GetElevation(id string) (*models.Elevation, error)
+	// DeleteElevation ends an elevation before it expires
+	DeleteElevation(id string) error
+	// ListElevations returns the elevations of subjectID (all subjects when empty), expired ones included, by ID
+	ListElevations(subjectID string) ([]*models.Elevation, error)
+	// FindActiveElevations returns the elevations of subjectID active at t, by ID
+	FindActiveElevations(subjectID string, at time.Time) ([]*models.Elevation, error)
+}
+
+// ValidateElevation checks that an elevation names a subject, grants roles or attributes, carries
+// a justification and expires after now but within MaxElevationDuration. Roles are granted through
+// Roles only, never through an attributes entry.
+func ValidateElevation(elevation *models.Elevation, now time.Time) error {
+	switch {
+	case elevation.ID == "":
+		return fmt.Errorf("%w: id is required", ErrInvalidElevation)
+	case elevation.SubjectID == "":
+		return fmt.Errorf("%w: subject_id is required", ErrInvalidElevation)
+	case len(elevation.Roles) == 0 && len(elevation.Attributes) == 0:
+		return fmt.Errorf("%w: roles or attributes are required", ErrInvalidElevation)
+	case strings.TrimSpace(elevation.Justification) == "":
+		return fmt.Errorf("%w: justification is required", ErrInvalidElevation)
+	case !elevation.ExpiresAt.After(now):
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidElevation)
+	case elevation.ExpiresAt.Sub(now) > MaxElevationDuration:
+		return fmt.Errorf("%w: expires_at must be within %s", ErrInvalidElevation, MaxElevationDuration)
+	}
+	if _, ok := elevation.Attributes[constants.ContextKeyRoles]; ok {
+		return fmt.Errorf("%w: grant roles through roles, not attributes", ErrInvalidElevation)
+	}
+	for _, role := range elevation.Roles {
+		if strings.TrimSpace(role) == "" {
+			return fmt.Errorf("%w: roles must not be blank", ErrInvalidElevation)
+		}
+	}
+	return nil
+}
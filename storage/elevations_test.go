@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestElevationStore(t *testing.T) {
+	stores := map[string]interface {
+		ElevationStore
+		EventStore
+	}{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			store.SetEventPublisher(publisher)
+
+			expiresAt := time.Now().Add(time.Hour).In(time.FixedZone("ICT", 7*3600))
+			for _, elevation := range []*models.Elevation{
+				{ID: "elev-2", SubjectID: "sub-2", Roles: models.JSONStringSlice{"dba"}, Justification: "Migration", ExpiresAt: expiresAt},
+				{ID: "elev-1", SubjectID: "sub-1", Roles: models.JSONStringSlice{"incident-responder"},
+					Attributes: models.JSONMap{"clearance_level": float64(5)}, Justification: "INC-42", CreatedBy: "oncall", ExpiresAt: expiresAt},
+			} {
+				if err := store.CreateElevation(elevation); err != nil {
+					t.Fatalf("CreateElevation(%s) failed: %v", elevation.ID, err)
+				}
+			}
+
+			listed, err := store.ListElevations("")
+			if err != nil || len(listed) != 2 || listed[0].ID != "elev-1" || listed[1].ID != "elev-2" {
+				t.Fatalf("Expected elev-1 and elev-2 by ID, got %v (%v)", listed, err)
+			}
+			if listed, _ := store.ListElevations("sub-2"); len(listed) != 1 || listed[0].ID != "elev-2" {
+				t.Errorf("Expected only elev-2 for sub-2, got %v", listed)
+			}
+			elevation, err := store.GetElevation("elev-1")
+			if err != nil || len(elevation.Roles) != 1 || elevation.Roles[0] != "incident-responder" ||
+				elevation.Attributes["clearance_level"] != float64(5) || !elevation.ExpiresAt.Equal(expiresAt) {
+				t.Errorf("Unexpected elevation %+v (%v)", elevation, err)
+			}
+
+			active, err := store.FindActiveElevations("sub-1", time.Now())
+			if err != nil || len(active) != 1 || active[0].ID != "elev-1" {
+				t.Errorf("Expected elev-1 to be active, got %v (%v)", active, err)
+			}
+			for _, at := range []time.Time{time.Now().Add(-time.Hour), expiresAt.Add(time.Second)} {
+				if active, _ := store.FindActiveElevations("sub-1", at); len(active) != 0 {
+					t.Errorf("Expected no elevation active at %v, got %v", at, active)
+				}
+			}
+
+			if err := store.DeleteElevation("elev-2"); err != nil {
+				t.Fatalf("DeleteElevation failed: %v", err)
+			}
+			if _, err := store.GetElevation("elev-2"); err == nil {
+				t.Error("Expected the ended elevation to be gone")
+			}
+			if want := "deleted elevation elev-2 "; len(*publisher) != 3 || (*publisher)[2] != want {
+				t.Errorf("Expected two created and one deleted elevation events, got %v", *publisher)
+			}
+
+			soon := time.Now().Add(time.Hour)
+			invalid := []*models.Elevation{
+				{ID: "bad-1", Roles: models.JSONStringSlice{"admin"}, Justification: "No subject", ExpiresAt: soon},
+				{ID: "bad-2", SubjectID: "sub-1", Justification: "Nothing granted", ExpiresAt: soon},
+				{ID: "bad-3", SubjectID: "sub-1", Roles: models.JSONStringSlice{"admin"}, Justification: " ", ExpiresAt: soon},
+				{ID: "bad-4", SubjectID: "sub-1", Roles: models.JSONStringSlice{"admin"}, Justification: "Expired", ExpiresAt: time.Now().Add(-time.Minute)},
+				{ID: "bad-5", SubjectID: "sub-1", Roles: models.JSONStringSlice{"admin"}, Justification: "Too long", ExpiresAt: time.Now().Add(2 * MaxElevationDuration)},
+				{ID: "bad-6", SubjectID: "sub-1", Attributes: models.JSONMap{"roles": []string{"admin"}}, Justification: "Roles as attribute", ExpiresAt: soon},
+				{ID: "bad-7", SubjectID: "sub-1", Roles: models.JSONStringSlice{""}, Justification: "Blank role", ExpiresAt: soon},
+			}
+			for _, elevation := range invalid {
+				if err := store.CreateElevation(elevation); !errors.Is(err, ErrInvalidElevation) {
+					t.Errorf("%s: expected ErrInvalidElevation, got %v", elevation.ID, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package storage
+
+import "abac_go_example/events"
+
+// EventPublisher receives the change events of a storage (events.Bus)
+type EventPublisher interface {
+	Publish(event events.Event)
+}
+
+// EventStore is implemented by storages that publish an event after every committed write of a
+// subject, resource or policy, including bulk inserts, soft deletes and restores
+type EventStore interface {
+	SetEventPublisher(publisher EventPublisher)
+}
+
+// SetEventPublisher publishes the changes of this storage and of tenant views created afterwards
+func (s *PostgreSQLStorage) SetEventPublisher(publisher EventPublisher) {
+	s.events = publisher
+}
+
+// publish emits a change event when a publisher is set; tenantID defaults to the view's tenant
+func (s *PostgreSQLStorage) publish(changeType, entity, id, tenantID string) {
+	if s.events == nil {
+		return
+	}
+	if tenantID == "" {
+		tenantID = s.tenantID
+	}
+	s.events.Publish(events.Event{Type: changeType, Entity: entity, ID: id, TenantID: tenantID})
+}
+
+// SetEventPublisher publishes the changes of the mock storage
+func (m *MockStorage) SetEventPublisher(publisher EventPublisher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = publisher
+}
+
+// publish queues a change event; it is published by unlock once the write lock is released
+func (m *MockStorage) publish(changeType, entity, id, tenantID string) {
+	if m.events != nil {
+		m.pending = append(m.pending, events.Event{Type: changeType, Entity: entity, ID: id, TenantID: tenantID})
+	}
+}
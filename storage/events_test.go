@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"testing"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+)
+
+// recordingPublisher collects published events as "type entity id tenant"
+type recordingPublisher []string
+
+func (r *recordingPublisher) Publish(event events.Event) {
+	*r = append(*r, event.Type+" "+event.Entity+" "+event.ID+" "+event.TenantID)
+}
+
+func TestChangeEvents(t *testing.T) {
+	type eventStorage interface {
+		Storage
+		EventStore
+		SoftDeleteStore
+	}
+	stores := map[string]eventStorage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			var published recordingPublisher
+			store.SetEventPublisher(&published)
+
+			subject := &models.Subject{ID: "sub-001", SubjectType: "user"}
+			policy := &models.Policy{ID: "pol-001", PolicyName: "Policy", Version: "1", Enabled: true}
+			steps := []error{
+				store.CreateSubject(subject),
+				store.UpdateSubject(subject),
+				store.BulkCreateResources([]*models.Resource{{ID: "res-001", ResourceType: "document"}, {ID: "res-002", ResourceType: "document"}}),
+				store.DeleteResource("res-002"),
+				store.CreatePolicy(policy),
+				store.UpdatePolicy(policy),
+				store.DeletePolicy("pol-001"),
+				store.RestorePolicy("pol-001"),
+				store.CreateAction(&models.Action{ID: "act-001", ActionName: "document:read"}),
+			}
+			for i, err := range steps {
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+			}
+
+			expected := []string{
+				"created subject sub-001 ",
+				"updated subject sub-001 ",
+				"created resource res-001 ",
+				"created resource res-002 ",
+				"deleted resource res-002 ",
+				"created policy pol-001 ",
+				"updated policy pol-001 ",
+				"deleted policy pol-001 ",
+				"restored policy pol-001 ",
+			}
+			if len(published) != len(expected) {
+				t.Fatalf("expected %d events, got %q", len(expected), published)
+			}
+			for i := range expected {
+				if published[i] != expected[i] {
+					t.Errorf("event %d: expected %q, got %q", i, expected[i], published[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChangeEventsOfTenantViews(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	var published recordingPublisher
+	sqliteStorage.SetEventPublisher(&published)
+	acme, err := sqliteStorage.ForTenant("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := acme.CreateSubject(&models.Subject{ID: "sub-acme", SubjectType: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting another tenant's (here: a missing) subject matches no row and publishes nothing
+	if err := acme.DeleteSubject("sub-other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := acme.DeleteSubject("sub-acme"); err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 2 || published[0] != "created subject sub-acme acme" || published[1] != "deleted subject sub-acme acme" {
+		t.Errorf("expected tenant-tagged create and delete events, got %q", published)
+	}
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// ErrInvalidException is returned when creating an exception with missing fields, an unknown effect
+// or an expiry that is not in the future
+var ErrInvalidException = errors.New("invalid access exception")
+
+// ExceptionStore is implemented by storages that keep access exceptions: explicit allows or
+// denies of a subject/resource/action that the PDP applies before policy evaluation
+type ExceptionStore interface {
+	// CreateException validates and stores an exception (CreatedAt defaults to now)
+	CreateException(exception *models.AccessException) error
+	GetException(id string) (*models.AccessException, error)
+	DeleteException(id string) error
+	// ListExceptions returns the exceptions of subjectID (all subjects when empty), expired ones included, by ID
+	ListExceptions(subjectID string) ([]*models.AccessException, error)
+	// FindActiveExceptions returns the exceptions of the exact subject, resource and action not expired at t
+	FindActiveExceptions(subjectID, resourceID, action string, at time.Time) ([]*models.AccessException, error)
+}
+
+// ValidateException checks that an exception names a subject, resource and action, carries a
+// justification and an allow or deny effect, and expires after now
+func ValidateException(exception *models.AccessException, now time.Time) error {
+	switch {
+	case exception.ID == "":
+		return fmt.Errorf("%w: id is required", ErrInvalidException)
+	case exception.SubjectID == "" || exception.ResourceID == "" || exception.Action == "":
+		return fmt.Errorf("%w: subject_id, resource_id and action are required", ErrInvalidException)
+	case strings.TrimSpace(exception.Justification) == "":
+		return fmt.Errorf("%w: justification is required", ErrInvalidException)
+	case exception.Effect != constants.EffectAllow && exception.Effect != constants.EffectDeny:
+		return fmt.Errorf("%w: effect must be %q or %q", ErrInvalidException, constants.EffectAllow, constants.EffectDeny)
+	case !exception.ActiveAt(now):
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidException)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+func TestExceptionStore(t *testing.T) {
+	stores := map[string]ExceptionStore{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			newException := func(id, subjectID, action string, expiresAt time.Time) *models.AccessException {
+				return &models.AccessException{
+					ID: id, SubjectID: subjectID, ResourceID: "doc-1", Action: action,
+					Effect: constants.EffectAllow, Justification: "Quarter close", CreatedBy: "admin", ExpiresAt: expiresAt,
+				}
+			}
+			for _, exception := range []*models.AccessException{
+				newException("exc-1", "sub-1", "read", now.Add(time.Hour)),
+				newException("exc-2", "sub-1", "read", now.Add(24*time.Hour).In(time.FixedZone("ICT", 7*3600))),
+				newException("exc-3", "sub-1", "write", now.Add(time.Hour)),
+				newException("exc-4", "sub-2", "read", now.Add(time.Hour)),
+			} {
+				if err := store.CreateException(exception); err != nil {
+					t.Fatalf("CreateException(%s) failed: %v", exception.ID, err)
+				}
+			}
+
+			active, err := store.FindActiveExceptions("sub-1", "doc-1", "read", now)
+			if err != nil || len(active) != 2 || active[0].ID != "exc-1" || active[1].ID != "exc-2" {
+				t.Fatalf("Expected exc-1 and exc-2, got %v (%v)", active, err)
+			}
+			// exc-1 expired two hours later; exc-2 still active
+			active, err = store.FindActiveExceptions("sub-1", "doc-1", "read", now.Add(2*time.Hour))
+			if err != nil || len(active) != 1 || active[0].ID != "exc-2" {
+				t.Errorf("Expected only exc-2 after expiry, got %v (%v)", active, err)
+			}
+
+			listed, err := store.ListExceptions("sub-1")
+			if err != nil || len(listed) != 3 {
+				t.Errorf("Expected 3 exceptions of sub-1, got %d (%v)", len(listed), err)
+			}
+			if all, _ := store.ListExceptions(""); len(all) != 4 {
+				t.Errorf("Expected 4 exceptions, got %d", len(all))
+			}
+
+			if err := store.DeleteException("exc-2"); err != nil {
+				t.Fatalf("DeleteException failed: %v", err)
+			}
+			if _, err := store.GetException("exc-2"); err == nil {
+				t.Error("Expected deleted exception to be gone")
+			}
+			exception, err := store.GetException("exc-1")
+			if err != nil || exception.Justification != "Quarter close" {
+				t.Errorf("Unexpected exception %v (%v)", exception, err)
+			}
+
+			invalid := []*models.AccessException{
+				newException("bad-1", "sub-1", "read", now.Add(-time.Minute)),
+				{ID: "bad-2", SubjectID: "sub-1", ResourceID: "doc-1", Action: "read", Effect: "maybe", Justification: "x", ExpiresAt: now.Add(time.Hour)},
+				{ID: "bad-3", SubjectID: "sub-1", ResourceID: "doc-1", Action: "read", Effect: constants.EffectDeny, Justification: " ", ExpiresAt: now.Add(time.Hour)},
+				{ID: "bad-4", SubjectID: "sub-1", Action: "read", Effect: constants.EffectDeny, Justification: "x", ExpiresAt: now.Add(time.Hour)},
+			}
+			for _, exception := range invalid {
+				if err := store.CreateException(exception); !errors.Is(err, ErrInvalidException) {
+					t.Errorf("%s: expected ErrInvalidException, got %v", exception.ID, err)
+				}
+			}
+		})
+	}
+}
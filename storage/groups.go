@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"abac_go_example/models"
+)
+
+var (
+	// ErrGroupCycle is returned when adding a group member would make a group contain itself
+	ErrGroupCycle = errors.New("group membership cycle")
+	// ErrInvalidGroupMember is returned for unknown member types or empty IDs
+	ErrInvalidGroupMember = errors.New("invalid group member")
+)
+
+// GroupStore is implemented by storages that keep groups and subject/group memberships.
+// Groups can contain other groups; ResolveGroups expands nested membership.
+type GroupStore interface {
+	CreateGroup(group *models.Group) error
+	GetGroup(id string) (*models.Group, error)
+	// DeleteGroup removes the group and every membership that references it
+	DeleteGroup(id string) error
+	// AddGroupMember adds a subject or a nested group to groupID, rejecting cycles with ErrGroupCycle
+	AddGroupMember(groupID, memberType, memberID string) error
+	RemoveGroupMember(groupID, memberType, memberID string) error
+	// GetDirectGroups returns the IDs of the groups memberID is a direct member of
+	GetDirectGroups(memberType, memberID string) ([]string, error)
+}
+
+// ResolveGroups returns every group memberID belongs to, directly or through nested groups, sorted.
+// Cycles already present in storage are tolerated: each group is visited once.
+func ResolveGroups(store GroupStore, memberType, memberID string) ([]string, error) {
+	direct, err := store.GetDirectGroups(memberType, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := make(map[string]bool, len(direct))
+	pending := append([]string(nil), direct...)
+	for len(pending) > 0 {
+		groupID := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[groupID] {
+			continue
+		}
+		visited[groupID] = true
+
+		parents, err := store.GetDirectGroups(models.GroupMemberGroup, groupID)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, parents...)
+	}
+
+	groups := make([]string, 0, len(visited))
+	for groupID := range visited {
+		groups = append(groups, groupID)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// validateGroupMember checks the member type and, for nested groups, that groupID is not
+// already (transitively) a member of memberID, which would create a cycle
+func validateGroupMember(store GroupStore, groupID, memberType, memberID string) error {
+	if groupID == "" || memberID == "" {
+		return fmt.Errorf("%w: group and member IDs are required", ErrInvalidGroupMember)
+	}
+
+	switch memberType {
+	case models.GroupMemberSubject:
+		return nil
+	case models.GroupMemberGroup:
+		if groupID == memberID {
+			return fmt.Errorf("%w: group %s cannot contain itself", ErrGroupCycle, groupID)
+		}
+		ancestors, err := ResolveGroups(store, models.GroupMemberGroup, groupID)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor == memberID {
+				return fmt.Errorf("%w: group %s already contains group %s", ErrGroupCycle, memberID, groupID)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown member type %q", ErrInvalidGroupMember, memberType)
+	}
+}
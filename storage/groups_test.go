@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestGroupStore(t *testing.T) {
+	stores := map[string]GroupStore{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, id := range []string{"finance", "finance-approvers", "approvers", "all-staff"} {
+				if err := store.CreateGroup(&models.Group{ID: id, Name: id}); err != nil {
+					t.Fatalf("CreateGroup failed: %v", err)
+				}
+			}
+
+			// sub-1 -> finance-approvers -> {finance, approvers}; approvers -> all-staff
+			mustAdd := func(groupID, memberType, memberID string) {
+				t.Helper()
+				if err := store.AddGroupMember(groupID, memberType, memberID); err != nil {
+					t.Fatalf("AddGroupMember(%s, %s, %s) failed: %v", groupID, memberType, memberID, err)
+				}
+			}
+			mustAdd("finance-approvers", models.GroupMemberSubject, "sub-1")
+			mustAdd("finance", models.GroupMemberGroup, "finance-approvers")
+			mustAdd("approvers", models.GroupMemberGroup, "finance-approvers")
+			mustAdd("all-staff", models.GroupMemberGroup, "approvers")
+			mustAdd("finance-approvers", models.GroupMemberSubject, "sub-1") // idempotent
+
+			groups, err := ResolveGroups(store, models.GroupMemberSubject, "sub-1")
+			if err != nil {
+				t.Fatalf("ResolveGroups failed: %v", err)
+			}
+			expected := []string{"all-staff", "approvers", "finance", "finance-approvers"}
+			if len(groups) != len(expected) {
+				t.Fatalf("Expected %v, got %v", expected, groups)
+			}
+			for i := range expected {
+				if groups[i] != expected[i] {
+					t.Fatalf("Expected %v, got %v", expected, groups)
+				}
+			}
+
+			if err := store.AddGroupMember("finance-approvers", models.GroupMemberGroup, "all-staff"); !errors.Is(err, ErrGroupCycle) {
+				t.Errorf("Expected ErrGroupCycle for indirect cycle, got %v", err)
+			}
+			if err := store.AddGroupMember("finance", models.GroupMemberGroup, "finance"); !errors.Is(err, ErrGroupCycle) {
+				t.Errorf("Expected ErrGroupCycle for self membership, got %v", err)
+			}
+			if err := store.AddGroupMember("finance", "role", "admin"); !errors.Is(err, ErrInvalidGroupMember) {
+				t.Errorf("Expected ErrInvalidGroupMember, got %v", err)
+			}
+
+			if err := store.DeleteGroup("approvers"); err != nil {
+				t.Fatalf("DeleteGroup failed: %v", err)
+			}
+			if groups, _ := ResolveGroups(store, models.GroupMemberSubject, "sub-1"); len(groups) != 2 {
+				t.Errorf("Expected [finance finance-approvers] after delete, got %v", groups)
+			}
+		})
+	}
+}
+
+func TestResolveGroups_ExistingCycle(t *testing.T) {
+	store := NewMockStorage()
+	// Cycles written directly to storage bypass AddGroupMember validation
+	store.memberships[groupMembershipKey{"a", models.GroupMemberSubject, "sub-1"}] = true
+	store.memberships[groupMembershipKey{"b", models.GroupMemberGroup, "a"}] = true
+	store.memberships[groupMembershipKey{"a", models.GroupMemberGroup, "b"}] = true
+
+	groups, err := ResolveGroups(store, models.GroupMemberSubject, "sub-1")
+	if err != nil || len(groups) != 2 || groups[0] != "a" || groups[1] != "b" {
+		t.Errorf("Expected [a b], got %v (err=%v)", groups, err)
+	}
+}
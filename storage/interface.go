@@ -9,6 +9,8 @@ type Storage interface {
 	GetResource(id string) (*models.Resource, error)
 	GetAction(name string) (*models.Action, error)
 	GetPolicies() ([]*models.Policy, error)
+	// GetPolicy returns a policy whether enabled or not (ErrPolicyNotFound when missing)
+	GetPolicy(id string) (*models.Policy, error)
 
 	// User-based ABAC operations (new)
 	GetUser(id string) (*models.User, error)
@@ -24,6 +26,19 @@ type Storage interface {
 	GetAllActions() ([]*models.Action, error)
 	GetAllUsers(status string, limit, offset int) ([]*models.User, error)
 
+	// Paged listing for admin tooling; options an entity does not support return ErrInvalidListOptions
+	ListSubjects(opts ListOptions) ([]*models.Subject, *PageInfo, error)
+	ListResources(opts ListOptions) ([]*models.Resource, *PageInfo, error)
+	ListActions(opts ListOptions) ([]*models.Action, *PageInfo, error)
+	// ListPolicies includes disabled policies unless opts.Enabled is set (GetPolicies is for evaluation)
+	ListPolicies(opts ListOptions) ([]*models.Policy, *PageInfo, error)
+
+	// Attribute search: the attribute equals value, or is an array containing value
+	FindSubjectsByAttribute(key string, value interface{}) ([]*models.Subject, error)
+	FindResourcesByAttribute(key string, value interface{}) ([]*models.Resource, error)
+	// FindResourcesByTag returns resources carrying tag ("key=value" or bare "key")
+	FindResourcesByTag(tag string) ([]*models.Resource, error)
+
 	// CRUD operations
 	CreateSubject(subject *models.Subject) error
 	CreateResource(resource *models.Resource) error
@@ -35,6 +50,8 @@ type Storage interface {
 	UpdateSubject(subject *models.Subject) error
 	UpdateResource(resource *models.Resource) error
 	UpdateAction(action *models.Action) error
+	// UpdatePolicy requires policy.Revision to equal the stored revision (ErrRevisionConflict otherwise)
+	// and increments it on success
 	UpdatePolicy(policy *models.Policy) error
 	UpdateUser(user *models.User) error
 	UpdateUserProfile(profile *models.UserProfile) error
@@ -45,6 +62,11 @@ type Storage interface {
 	DeletePolicy(id string) error
 	DeleteUser(id string) error
 
+	// Bulk inserts: all records are created in one transaction, or none is
+	BulkCreateSubjects(subjects []*models.Subject) error
+	BulkCreateResources(resources []*models.Resource) error
+	BulkCreatePolicies(policies []*models.Policy) error
+
 	// Role operations
 	AssignRole(userID, roleID, assignedBy string) error
 	RevokeRole(userID, roleID string) error
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultListLimit is the page size used when ListOptions.Limit is zero
+	DefaultListLimit = 100
+	// MaxListLimit is the largest accepted page size
+	MaxListLimit = 1000
+)
+
+// ErrInvalidListOptions is returned for unknown sort fields, filters an entity does not support,
+// negative limits/offsets and malformed cursors
+var ErrInvalidListOptions = errors.New("invalid list options")
+
+// ListOptions controls paging, filtering and sorting of the List* storage methods
+type ListOptions struct {
+	Limit        int        // Page size (DefaultListLimit when 0, at most MaxListLimit)
+	Offset       int        // Records to skip; ignored when Cursor is set
+	Cursor       string     // Opaque PageInfo.NextCursor of the previous page
+	Type         string     // subject_type, resource_type or action_category
+	Enabled      *bool      // Policies only
+	Namespace    *string    // Policies only; exact namespace, "" selects global policies
+	UpdatedSince *time.Time // Subjects and policies only (entities with updated_at)
+	Tag          string     // Policies only; policies carrying the tag ("pci", "domain=pci")
+	Owner        string     // Policies only; exact owner
+	Sort         string     // Field name, "-" prefix for descending (e.g. "-updated_at"); default "id"
+}
+
+// PageInfo describes the position of a page in the full result
+type PageInfo struct {
+	Total      int64  `json:"total"`                 // Records matching the filters
+	NextCursor string `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// listSpec declares which options an entity supports and the columns behind them
+type listSpec struct {
+	entity        string
+	typeColumn    string // "" when the entity has no type filter
+	updatedColumn string // "" when the entity has no updated_at
+	hasEnabled    bool
+	hasNamespace  bool
+	hasLabels     bool // Tag and owner filters
+	sortFields    []string
+}
+
+var (
+	subjectListSpec  = listSpec{entity: "subjects", typeColumn: "subject_type", updatedColumn: "updated_at", sortFields: []string{"id", "subject_type", "created_at", "updated_at"}}
+	resourceListSpec = listSpec{entity: "resources", typeColumn: "resource_type", sortFields: []string{"id", "resource_type", "created_at"}}
+	actionListSpec   = listSpec{entity: "actions", typeColumn: "action_category", sortFields: []string{"id", "action_name", "action_category"}}
+	policyListSpec   = listSpec{entity: "policies", updatedColumn: "updated_at", hasEnabled: true, hasNamespace: true, hasLabels: true, sortFields: []string{"id", "policy_name", "owner", "created_at", "updated_at"}}
+)
+
+// listQuery is a validated ListOptions
+type listQuery struct {
+	limit, offset int
+	sortField     string
+	descending    bool
+}
+
+// resolve validates opts against spec and decodes the cursor
+func (opts ListOptions) resolve(spec listSpec) (listQuery, error) {
+	query := listQuery{limit: opts.Limit, offset: opts.Offset, sortField: "id"}
+
+	switch {
+	case query.limit < 0 || query.offset < 0:
+		return query, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidListOptions)
+	case query.limit == 0:
+		query.limit = DefaultListLimit
+	case query.limit > MaxListLimit:
+		query.limit = MaxListLimit
+	}
+
+	if opts.Cursor != "" {
+		offset, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return query, err
+		}
+		query.offset = offset
+	}
+
+	if opts.Type != "" && spec.typeColumn == "" {
+		return query, fmt.Errorf("%w: %s cannot be filtered by type", ErrInvalidListOptions, spec.entity)
+	}
+	if opts.Enabled != nil && !spec.hasEnabled {
+		return query, fmt.Errorf("%w: %s cannot be filtered by enabled", ErrInvalidListOptions, spec.entity)
+	}
+	if opts.Namespace != nil && !spec.hasNamespace {
+		return query, fmt.Errorf("%w: %s cannot be filtered by namespace", ErrInvalidListOptions, spec.entity)
+	}
+	if (opts.Tag != "" || opts.Owner != "") && !spec.hasLabels {
+		return query, fmt.Errorf("%w: %s cannot be filtered by tag or owner", ErrInvalidListOptions, spec.entity)
+	}
+	if opts.Tag != "" {
+		if _, err := normalizeSearchTag(opts.Tag); err != nil {
+			return query, fmt.Errorf("%w: %v", ErrInvalidListOptions, err)
+		}
+	}
+	if opts.UpdatedSince != nil && spec.updatedColumn == "" {
+		return query, fmt.Errorf("%w: %s cannot be filtered by updated_since", ErrInvalidListOptions, spec.entity)
+	}
+
+	if opts.Sort != "" {
+		query.sortField = strings.TrimPrefix(opts.Sort, "-")
+		query.descending = strings.HasPrefix(opts.Sort, "-")
+		if !spec.sorts(query.sortField) {
+			return query, fmt.Errorf("%w: %s cannot be sorted by %q (allowed: %s)",
+				ErrInvalidListOptions, spec.entity, query.sortField, strings.Join(spec.sortFields, ", "))
+		}
+	}
+	return query, nil
+}
+
+// sorts reports whether field is a sort field of the entity
+func (spec listSpec) sorts(field string) bool {
+	for _, allowed := range spec.sortFields {
+		if allowed == field {
+			return true
+		}
+	}
+	return false
+}
+
+// orderClause returns the SQL ORDER BY of query; id breaks ties so pages are stable
+func (query listQuery) orderClause() string {
+	direction := "ASC"
+	if query.descending {
+		direction = "DESC"
+	}
+	if query.sortField == "id" {
+		return "id " + direction
+	}
+	return query.sortField + " " + direction + ", id " + direction
+}
+
+// pageInfo builds the PageInfo of a page of returned records out of total
+func (query listQuery) pageInfo(total int64, returned int) *PageInfo {
+	info := &PageInfo{Total: total}
+	if next := query.offset + returned; returned > 0 && int64(next) < total {
+		info.NextCursor = encodeCursor(next)
+	}
+	return info
+}
+
+// bounds returns the slice bounds of the page within total in-memory records
+func (query listQuery) bounds(total int) (int, int) {
+	start := query.offset
+	if start > total {
+		start = total
+	}
+	end := start + query.limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// encodeCursor returns the opaque cursor of the page starting at offset
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		if value, found := strings.CutPrefix(string(data), "offset:"); found {
+			if offset, convErr := strconv.Atoi(value); convErr == nil && offset >= 0 {
+				return offset, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: malformed cursor", ErrInvalidListOptions)
+}
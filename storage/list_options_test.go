@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestListSubjects(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i := 1; i <= 5; i++ {
+				subjectType := "user"
+				if i%2 == 0 {
+					subjectType = "service"
+				}
+				if err := store.CreateSubject(&models.Subject{ID: fmt.Sprintf("sub-%d", i), SubjectType: subjectType}); err != nil {
+					t.Fatalf("CreateSubject failed: %v", err)
+				}
+			}
+
+			// Walk all users two at a time with cursors, newest ID first
+			var ids []string
+			opts := ListOptions{Limit: 2, Type: "user", Sort: "-id"}
+			for page := 0; page < 5; page++ {
+				subjects, info, err := store.ListSubjects(opts)
+				if err != nil {
+					t.Fatalf("ListSubjects failed: %v", err)
+				}
+				if info.Total != 3 {
+					t.Fatalf("Expected total 3, got %d", info.Total)
+				}
+				for _, subject := range subjects {
+					ids = append(ids, subject.ID)
+				}
+				if info.NextCursor == "" {
+					break
+				}
+				opts.Cursor = info.NextCursor
+			}
+			if fmt.Sprint(ids) != "[sub-5 sub-3 sub-1]" {
+				t.Errorf("Unexpected page walk: %v", ids)
+			}
+
+			subjects, info, err := store.ListSubjects(ListOptions{Offset: 3, Sort: "subject_type"})
+			if err != nil || len(subjects) != 2 || info.NextCursor != "" || subjects[0].ID != "sub-3" {
+				t.Errorf("Unexpected offset page %v %+v (err=%v)", subjects, info, err)
+			}
+
+			future := time.Now().Add(time.Hour)
+			if subjects, info, _ := store.ListSubjects(ListOptions{UpdatedSince: &future}); len(subjects) != 0 || info.Total != 0 {
+				t.Errorf("Expected no subjects updated in the future, got %v", subjects)
+			}
+		})
+	}
+}
+
+func TestListPolicies(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			store.CreatePolicy(&models.Policy{ID: "pol-b", PolicyName: "B", Version: "1", Enabled: true,
+				Tags: models.JSONStringSlice{"domain=pci", "sox"}, Owner: "team-payments"})
+			store.CreatePolicy(&models.Policy{ID: "pol-a", PolicyName: "A", Version: "1", Enabled: true, Tags: models.JSONStringSlice{"domain = pci"}})
+			disabled := &models.Policy{ID: "pol-c", PolicyName: "C", Version: "1", Enabled: true, Owner: "team-payments"}
+			store.CreatePolicy(disabled)
+			disabled.Enabled = false
+			store.UpdatePolicy(disabled)
+
+			policies, info, err := store.ListPolicies(ListOptions{Sort: "-policy_name"})
+			if err != nil || info.Total != 3 || policies[0].ID != "pol-c" || policies[2].ID != "pol-a" {
+				t.Fatalf("Unexpected policies %v %+v (err=%v)", policies, info, err)
+			}
+
+			enabled := false
+			if policies, _, _ := store.ListPolicies(ListOptions{Enabled: &enabled}); len(policies) != 1 || policies[0].ID != "pol-c" {
+				t.Errorf("Expected only pol-c to be disabled, got %v", policies)
+			}
+
+			if policies, info, _ := store.ListPolicies(ListOptions{Tag: " domain=pci "}); info.Total != 2 || policies[0].ID != "pol-a" || policies[1].ID != "pol-b" {
+				t.Errorf("Expected pol-a and pol-b tagged domain=pci, got %v", policies)
+			}
+			if policies, _, _ := store.ListPolicies(ListOptions{Owner: "team-payments", Sort: "-id"}); len(policies) != 2 || policies[0].ID != "pol-c" {
+				t.Errorf("Expected the team-payments policies, got %v", policies)
+			}
+			if policies, _, _ := store.ListPolicies(ListOptions{Owner: "team-payments", Tag: "sox"}); len(policies) != 1 || policies[0].ID != "pol-b" {
+				t.Errorf("Expected tag and owner to combine, got %v", policies)
+			}
+		})
+	}
+}
+
+func TestListOptions_Invalid(t *testing.T) {
+	store := NewMockStorage()
+	enabled := true
+	since := time.Now()
+
+	invalid := map[string]func() error{
+		"unknown sort":        func() error { _, _, err := store.ListSubjects(ListOptions{Sort: "attributes"}); return err },
+		"negative limit":      func() error { _, _, err := store.ListActions(ListOptions{Limit: -1}); return err },
+		"bad cursor":          func() error { _, _, err := store.ListResources(ListOptions{Cursor: "not-a-cursor"}); return err },
+		"enabled on subjects": func() error { _, _, err := store.ListSubjects(ListOptions{Enabled: &enabled}); return err },
+		"type on policies":    func() error { _, _, err := store.ListPolicies(ListOptions{Type: "x"}); return err },
+		"tag on resources":    func() error { _, _, err := store.ListResources(ListOptions{Tag: "pci"}); return err },
+		"blank tag":           func() error { _, _, err := store.ListPolicies(ListOptions{Tag: " = "}); return err },
+		"updated_since on resources": func() error {
+			_, _, err := store.ListResources(ListOptions{UpdatedSince: &since})
+			return err
+		},
+	}
+	for name, call := range invalid {
+		if err := call(); !errors.Is(err, ErrInvalidListOptions) {
+			t.Errorf("%s: expected ErrInvalidListOptions, got %v", name, err)
+		}
+	}
+
+	if _, info, err := store.ListActions(ListOptions{Limit: MaxListLimit + 1}); err != nil || info.Total != 0 {
+		t.Errorf("Expected oversized limit to be clamped, got %+v (err=%v)", info, err)
+	}
+}
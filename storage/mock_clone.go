@@ -0,0 +1,222 @@
+package storage
+
+import "abac_go_example/models"
+
+// MockSnapshot is a consistent deep copy of the MockStorage contents, taken under one lock so
+// tests can assert on it while other goroutines keep writing
+type MockSnapshot struct {
+	Subjects  map[string]*models.Subject
+	Resources map[string]*models.Resource
+	Actions   map[string]*models.Action
+	Policies  map[string]*models.Policy
+
+	DeletedSubjects  map[string]*models.Subject
+	DeletedResources map[string]*models.Resource
+	DeletedActions   map[string]*models.Action
+	DeletedPolicies  map[string]*models.Policy
+
+	Users       map[string]*models.User
+	Groups      map[string]*models.Group
+	Exceptions  map[string]*models.AccessException
+	Revocations map[string]*models.Revocation
+	Elevations  map[string]*models.Elevation
+	Schedules   map[string]*models.PolicySchedule
+	AuditLogs   []*models.AuditLog
+}
+
+// Snapshot returns a deep copy of the storage contents
+func (m *MockStorage) Snapshot() *MockSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := make(map[string]*models.User, len(m.users))
+	for id, user := range m.users {
+		users[id] = m.userWithRelations(user)
+	}
+	auditLogs := make([]*models.AuditLog, len(m.auditLogs))
+	for i, auditLog := range m.auditLogs {
+		auditLogs[i] = cloneAuditLog(auditLog)
+	}
+	return &MockSnapshot{
+		Subjects:         cloneMap(m.subjects, cloneSubject),
+		Resources:        cloneMap(m.resources, cloneResource),
+		Actions:          cloneMap(m.actions, cloneAction),
+		Policies:         cloneMap(m.policies, clonePolicy),
+		DeletedSubjects:  cloneMap(m.deletedSubjects, cloneSubject),
+		DeletedResources: cloneMap(m.deletedResources, cloneResource),
+		DeletedActions:   cloneMap(m.deletedActions, cloneAction),
+		DeletedPolicies:  cloneMap(m.deletedPolicies, clonePolicy),
+		Users:            users,
+		Groups:           cloneMap(m.groups, shallowClone[models.Group]),
+		Exceptions:       cloneMap(m.exceptions, shallowClone[models.AccessException]),
+		Revocations:      cloneMap(m.revocations, cloneRevocation),
+		Elevations:       cloneMap(m.elevations, cloneElevation),
+		Schedules:        cloneMap(m.schedules, clonePolicySchedule),
+		AuditLogs:        auditLogs,
+	}
+}
+
+// unlock releases the write lock, then publishes the events queued while holding it so
+// subscribers may call back into the storage
+func (m *MockStorage) unlock() {
+	pending, publisher := m.pending, m.events
+	m.pending = nil
+	m.mu.Unlock()
+	if publisher == nil {
+		return
+	}
+	for _, event := range pending {
+		publisher.Publish(event)
+	}
+}
+
+// Copies of stored entities. MockStorage stores copies of what it is given and returns copies of
+// what it holds, so callers never share memory with the storage or with each other.
+
+func cloneSubject(subject *models.Subject) *models.Subject {
+	copied := *subject
+	copied.Metadata = cloneJSONMap(subject.Metadata)
+	copied.Attributes = cloneJSONMap(subject.Attributes)
+	return &copied
+}
+
+func cloneResource(resource *models.Resource) *models.Resource {
+	copied := *resource
+	copied.Metadata = cloneJSONMap(resource.Metadata)
+	copied.Attributes = cloneJSONMap(resource.Attributes)
+	copied.Tags = cloneSlice(resource.Tags)
+	return &copied
+}
+
+func cloneAction(action *models.Action) *models.Action {
+	copied := *action
+	return &copied
+}
+
+func clonePolicy(policy *models.Policy) *models.Policy {
+	copied := *policy
+	if policy.Statement != nil {
+		copied.Statement = make(models.JSONStatements, len(policy.Statement))
+		for i, statement := range policy.Statement {
+			statement.Action.Multiple = cloneSlice(statement.Action.Multiple)
+			statement.Resource.Multiple = cloneSlice(statement.Resource.Multiple)
+			statement.NotResource.Multiple = cloneSlice(statement.NotResource.Multiple)
+			statement.Fields.Multiple = cloneSlice(statement.Fields.Multiple)
+			statement.Condition = cloneJSONMap(statement.Condition)
+			copied.Statement[i] = statement
+		}
+	}
+	copied.EffectiveFrom = clonePointer(policy.EffectiveFrom)
+	copied.ExpiresAt = clonePointer(policy.ExpiresAt)
+	copied.RolloutPercent = clonePointer(policy.RolloutPercent)
+	copied.Tags = cloneSlice(policy.Tags)
+	return &copied
+}
+
+func cloneUser(user *models.User) *models.User {
+	copied := *user
+	copied.Metadata = cloneJSONMap(user.Metadata)
+	copied.HireDate = clonePointer(user.HireDate)
+	copied.TerminationDate = clonePointer(user.TerminationDate)
+	copied.Roles = cloneSlice(user.Roles)
+	if user.Profile != nil {
+		profile := cloneUserProfile(*user.Profile)
+		copied.Profile = &profile
+	}
+	return &copied
+}
+
+func cloneUserProfile(profile models.UserProfile) models.UserProfile {
+	profile.Attributes = cloneJSONMap(profile.Attributes)
+	profile.EmergencyContact = cloneJSONMap(profile.EmergencyContact)
+	profile.ManagerID = clonePointer(profile.ManagerID)
+	return profile
+}
+
+func cloneAuditLog(auditLog *models.AuditLog) *models.AuditLog {
+	copied := *auditLog
+	copied.Context = cloneJSONMap(auditLog.Context)
+	return &copied
+}
+
+func cloneRevocation(revocation *models.Revocation) *models.Revocation {
+	copied := *revocation
+	copied.ExpiresAt = clonePointer(revocation.ExpiresAt)
+	return &copied
+}
+
+func cloneElevation(elevation *models.Elevation) *models.Elevation {
+	copied := *elevation
+	copied.Roles = cloneSlice(elevation.Roles)
+	copied.Attributes = cloneJSONMap(elevation.Attributes)
+	return &copied
+}
+
+func clonePolicySchedule(schedule *models.PolicySchedule) *models.PolicySchedule {
+	copied := *schedule
+	copied.PolicyIDs = cloneSlice(schedule.PolicyIDs)
+	copied.Tags = cloneSlice(schedule.Tags)
+	copied.RunAt = clonePointer(schedule.RunAt)
+	copied.LastRunAt = clonePointer(schedule.LastRunAt)
+	copied.NextRunAt = clonePointer(schedule.NextRunAt)
+	return &copied
+}
+
+func shallowClone[T any](value *T) *T {
+	copied := *value
+	return &copied
+}
+
+func cloneMap[T any](entities map[string]*T, clone func(*T) *T) map[string]*T {
+	copied := make(map[string]*T, len(entities))
+	for id, entity := range entities {
+		copied[id] = clone(entity)
+	}
+	return copied
+}
+
+func cloneSlice[S ~[]E, E any](values S) S {
+	if values == nil {
+		return nil
+	}
+	return append(S(nil), values...)
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// cloneJSONMap deep-copies nested JSON objects and arrays, unlike the shallow copyJSONMap
+func cloneJSONMap(attrs models.JSONMap) models.JSONMap {
+	if attrs == nil {
+		return nil
+	}
+	copied := make(models.JSONMap, len(attrs))
+	for key, value := range attrs {
+		copied[key] = cloneJSONValue(value)
+	}
+	return copied
+}
+
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return map[string]interface{}(cloneJSONMap(v))
+	case models.JSONMap:
+		return cloneJSONMap(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = cloneJSONValue(item)
+		}
+		return copied
+	case []string:
+		return cloneSlice(v)
+	default:
+		return value
+	}
+}
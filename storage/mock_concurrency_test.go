@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+)
+
+func TestMockStorageConcurrency(t *testing.T) {
+	t.Run("concurrent writers and readers", func(t *testing.T) {
+		store := NewMockStorage()
+		store.SetEventPublisher(events.NewBus())
+
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					id := fmt.Sprintf("sub-%d-%d", worker, i)
+					if err := store.CreateSubject(&models.Subject{ID: id, Attributes: models.JSONMap{"n": i}}); err != nil {
+						t.Error(err)
+						return
+					}
+					subject, err := store.GetSubject(id)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					subject.Attributes["n"] = -1
+					if err := store.UpdateSubject(subject); err != nil {
+						t.Error(err)
+						return
+					}
+					store.CreatePolicy(&models.Policy{ID: id, Enabled: true})
+					store.GetPolicies()
+					store.ListSubjects(ListOptions{})
+					store.LogAudit(&models.AuditLog{RequestID: id})
+				}
+			}(worker)
+		}
+		wg.Wait()
+
+		snapshot := store.Snapshot()
+		if len(snapshot.Subjects) != 400 || len(snapshot.Policies) != 400 || len(snapshot.AuditLogs) != 400 {
+			t.Errorf("expected 400 subjects, policies and audit logs, got %d, %d and %d",
+				len(snapshot.Subjects), len(snapshot.Policies), len(snapshot.AuditLogs))
+		}
+	})
+
+	t.Run("reads and writes are isolated copies", func(t *testing.T) {
+		store := NewMockStorage()
+		subject := &models.Subject{ID: "sub-001", Attributes: models.JSONMap{"department": "Engineering"}}
+		if err := store.CreateSubject(subject); err != nil {
+			t.Fatal(err)
+		}
+		if subject.CreatedAt.IsZero() {
+			t.Error("expected CreateSubject to stamp the caller's subject")
+		}
+
+		subject.Attributes["department"] = "Finance"
+		read, _ := store.GetSubject("sub-001")
+		if read.Attributes["department"] != "Engineering" {
+			t.Errorf("expected the stored subject to be unaffected by the caller, got %v", read.Attributes["department"])
+		}
+		read.Attributes["department"] = "Sales"
+		again, _ := store.GetSubject("sub-001")
+		if again.Attributes["department"] != "Engineering" {
+			t.Errorf("expected reads to return copies, got %v", again.Attributes["department"])
+		}
+
+		policy := &models.Policy{ID: "pol-001", Statement: models.JSONStatements{{
+			Effect:    "Allow",
+			Action:    models.JSONActionResource{Multiple: []string{"read"}},
+			Condition: map[string]interface{}{"StringEquals": map[string]interface{}{"user.department": "Engineering"}},
+		}}}
+		store.CreatePolicy(policy)
+		policies, _ := store.GetPolicies()
+		policies[0].Statement[0].Action.Multiple[0] = "delete"
+		policies[0].Statement[0].Condition["StringEquals"].(map[string]interface{})["user.department"] = "Sales"
+		stored, _ := store.GetPolicy("pol-001")
+		if stored.Statement[0].Action.Multiple[0] != "read" ||
+			stored.Statement[0].Condition["StringEquals"].(map[string]interface{})["user.department"] != "Engineering" {
+			t.Errorf("expected nested policy fields to be copied, got %+v", stored.Statement[0])
+		}
+	})
+
+	t.Run("snapshot is a consistent deep copy", func(t *testing.T) {
+		store := NewMockStorage()
+		store.SeedTestData()
+		store.DeleteResource("res-456")
+		store.CreateUser(&models.User{ID: "user-1", Status: "active"})
+		store.CreateUserProfile(&models.UserProfile{UserID: "user-1", Attributes: models.JSONMap{"level": 3}})
+
+		snapshot := store.Snapshot()
+		if len(snapshot.Subjects) != 3 || len(snapshot.Resources) != 1 || len(snapshot.DeletedResources) != 1 || len(snapshot.Actions) != 3 {
+			t.Errorf("unexpected snapshot counts: %d subjects, %d resources, %d deleted resources, %d actions",
+				len(snapshot.Subjects), len(snapshot.Resources), len(snapshot.DeletedResources), len(snapshot.Actions))
+		}
+		if user := snapshot.Users["user-1"]; user == nil || user.Profile == nil || user.Profile.Attributes["level"] != 3 {
+			t.Errorf("expected the user with its profile, got %+v", user)
+		}
+
+		snapshot.Subjects["user-123"].Attributes["department"] = "Sales"
+		store.DeleteSubject("user-456")
+		if subject, _ := store.GetSubject("user-123"); subject.Attributes["department"] != "Engineering" {
+			t.Errorf("expected the snapshot to be detached from the storage, got %v", subject.Attributes["department"])
+		}
+		if _, ok := snapshot.Subjects["user-456"]; !ok {
+			t.Error("expected the snapshot to keep the state at the time it was taken")
+		}
+	})
+
+	t.Run("subscribers may call back into the storage", func(t *testing.T) {
+		store := NewMockStorage()
+		bus := events.NewBus()
+		store.SetEventPublisher(bus)
+		reads := make(chan error, 1)
+		bus.Subscribe("reader", events.SubscriberFunc(func(event events.Event) error {
+			_, err := store.GetSubject(event.ID)
+			reads <- err
+			return nil
+		}))
+
+		if err := store.CreateSubject(&models.Subject{ID: "sub-001"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-reads; err != nil {
+			t.Errorf("expected the subscriber to read the new subject, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is returned by MockStorage calls failed by an injected Fault without Err
+var ErrInjectedFault = errors.New("injected storage fault")
+
+// AllMethods configures the fault of every MockStorage method without a fault of its own
+const AllMethods = "*"
+
+// Fault makes MockStorage calls slow or failing, to test circuit breakers, retries and fallbacks.
+// Rates are applied deterministically by call count rather than randomly: with ErrorRate 0.1
+// exactly the 10th, 20th, ... calls fail, so tests need no seeds or retries.
+type Fault struct {
+	Latency   time.Duration // Added before every call
+	ErrorRate float64       // Fraction of calls returning Err, 0..1
+	Err       error         // Returned by failed calls, default ErrInjectedFault
+	// PartialRate is the fraction of calls that only partly succeed: list reads (GetPolicies,
+	// GetAll*) silently return the first half of their rows, bulk creates write the first half of
+	// the records and then return Err. Other methods ignore it.
+	PartialRate float64
+}
+
+// FaultStats counts the calls of one method while a fault was injected
+type FaultStats struct {
+	Calls   int `json:"calls"`
+	Failed  int `json:"failed"`
+	Partial int `json:"partial"`
+}
+
+// mockFaults holds the injected faults of a MockStorage. It has its own lock so faults can be
+// changed while other goroutines use the storage.
+type mockFaults struct {
+	mu     sync.Mutex
+	faults map[string]Fault
+	stats  map[string]*FaultStats
+}
+
+// faultOutcome is the effect of an injected fault on one call
+type faultOutcome struct {
+	err     error
+	partial bool
+}
+
+// InjectFault sets the fault of method (e.g. "GetPolicies"), or of all methods with AllMethods,
+// and resets its call count
+func (m *MockStorage) InjectFault(method string, fault Fault) {
+	m.faults.mu.Lock()
+	defer m.faults.mu.Unlock()
+	if m.faults.faults == nil {
+		m.faults.faults = make(map[string]Fault)
+		m.faults.stats = make(map[string]*FaultStats)
+	}
+	if fault.Err == nil {
+		fault.Err = ErrInjectedFault
+	}
+	m.faults.faults[method] = fault
+	m.faults.stats[method] = &FaultStats{}
+}
+
+// ClearFaults removes every injected fault and its statistics
+func (m *MockStorage) ClearFaults() {
+	m.faults.mu.Lock()
+	defer m.faults.mu.Unlock()
+	m.faults.faults = nil
+	m.faults.stats = nil
+}
+
+// FaultStats returns the call counts of method (or AllMethods) since its fault was injected
+func (m *MockStorage) FaultStats(method string) FaultStats {
+	m.faults.mu.Lock()
+	defer m.faults.mu.Unlock()
+	if stats, ok := m.faults.stats[method]; ok {
+		return *stats
+	}
+	return FaultStats{}
+}
+
+// fault applies the injected fault of method to a call and returns the error to fail it with.
+// Methods calling it have no partial outcome, so partial calls succeed.
+func (m *MockStorage) fault(method string) error {
+	outcome := m.nextFault(method)
+	if outcome.partial {
+		return nil
+	}
+	return outcome.err
+}
+
+// nextFault counts the call, sleeps for the fault's latency and decides the call's outcome
+func (m *MockStorage) nextFault(method string) faultOutcome {
+	m.faults.mu.Lock()
+	key := method
+	fault, ok := m.faults.faults[key]
+	if !ok {
+		key = AllMethods
+		fault, ok = m.faults.faults[key]
+	}
+	if !ok {
+		m.faults.mu.Unlock()
+		return faultOutcome{}
+	}
+	stats := m.faults.stats[key]
+	stats.Calls++
+	var outcome faultOutcome
+	switch {
+	case scheduled(stats.Calls, fault.ErrorRate):
+		stats.Failed++
+		outcome.err = fault.Err
+	case scheduled(stats.Calls, fault.PartialRate):
+		stats.Partial++
+		outcome.partial = true
+		outcome.err = fault.Err
+	}
+	m.faults.mu.Unlock()
+
+	// Sleep without the lock so concurrent calls are delayed in parallel
+	if fault.Latency > 0 {
+		time.Sleep(fault.Latency)
+	}
+	return outcome
+}
+
+// scheduled reports whether call n (1-based) is one of the rate*n calls spread evenly over n
+func scheduled(n int, rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return math.Floor(float64(n)*rate) > math.Floor(float64(n-1)*rate)
+}
+
+// partialRead truncates the rows of a list read hit by a partial fault
+func partialRead[T any](m *MockStorage, method string, rows []T) ([]T, error) {
+	outcome := m.nextFault(method)
+	if outcome.partial {
+		return rows[:len(rows)/2], nil
+	}
+	if outcome.err != nil {
+		return nil, outcome.err
+	}
+	return rows, nil
+}
+
+// partialWrite returns the records a bulk create hit by a partial fault writes before failing
+func partialWrite[T any](outcome faultOutcome, records []T) []T {
+	if outcome.partial {
+		return records[:len(records)/2]
+	}
+	return records
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestMockStorageFaults(t *testing.T) {
+	t.Run("error rate is applied by call count", func(t *testing.T) {
+		store := NewMockStorage()
+		store.SeedTestData()
+		store.InjectFault("GetSubject", Fault{ErrorRate: 0.25})
+
+		var failed []int
+		for call := 1; call <= 8; call++ {
+			if _, err := store.GetSubject("user-123"); errors.Is(err, ErrInjectedFault) {
+				failed = append(failed, call)
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(failed) != 2 || failed[0] != 4 || failed[1] != 8 {
+			t.Errorf("expected calls 4 and 8 to fail, got %v", failed)
+		}
+		if stats := store.FaultStats("GetSubject"); stats.Calls != 8 || stats.Failed != 2 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		// Other methods are unaffected
+		if _, err := store.GetResource("res-123"); err != nil {
+			t.Errorf("expected GetResource to succeed: %v", err)
+		}
+	})
+
+	t.Run("custom error and all methods", func(t *testing.T) {
+		store := NewMockStorage()
+		unavailable := errors.New("connection refused")
+		store.InjectFault(AllMethods, Fault{ErrorRate: 1, Err: unavailable})
+		store.InjectFault("GetPolicies", Fault{})
+
+		if err := store.CreateSubject(&models.Subject{ID: "sub-001"}); !errors.Is(err, unavailable) {
+			t.Errorf("expected the custom error, got %v", err)
+		}
+		if _, _, err := store.ListPolicies(ListOptions{}); !errors.Is(err, unavailable) {
+			t.Errorf("expected the custom error, got %v", err)
+		}
+		if _, err := store.GetPolicies(); err != nil {
+			t.Errorf("expected the method fault to override AllMethods, got %v", err)
+		}
+
+		store.ClearFaults()
+		if err := store.CreateSubject(&models.Subject{ID: "sub-001"}); err != nil {
+			t.Errorf("expected no fault after ClearFaults, got %v", err)
+		}
+	})
+
+	t.Run("partial reads and bulk writes", func(t *testing.T) {
+		store := NewMockStorage()
+		store.InjectFault("BulkCreatePolicies", Fault{PartialRate: 1})
+		policies := []*models.Policy{{ID: "pol-1"}, {ID: "pol-2"}, {ID: "pol-3"}, {ID: "pol-4"}}
+		if err := store.BulkCreatePolicies(policies); !errors.Is(err, ErrInjectedFault) {
+			t.Fatalf("expected the partial bulk create to fail, got %v", err)
+		}
+		if stored, _ := store.GetPolicies(); len(stored) != 2 {
+			t.Fatalf("expected the first 2 policies to be written, got %d", len(stored))
+		}
+
+		store.InjectFault("GetPolicies", Fault{PartialRate: 0.5})
+		first, err1 := store.GetPolicies()
+		second, err2 := store.GetPolicies()
+		if err1 != nil || err2 != nil || len(first) != 2 || len(second) != 1 {
+			t.Errorf("expected a full then a silently truncated read, got %d (%v) and %d (%v)", len(first), err1, len(second), err2)
+		}
+		// Methods without a partial outcome succeed on partial calls
+		store.InjectFault("GetPolicy", Fault{PartialRate: 1})
+		if _, err := store.GetPolicy("pol-1"); err != nil {
+			t.Errorf("expected GetPolicy to ignore PartialRate, got %v", err)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		store := NewMockStorage()
+		store.InjectFault("GetPolicies", Fault{Latency: 50 * time.Millisecond})
+
+		// Concurrent calls are delayed in parallel, not serialized by the fault lock
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				store.GetPolicies()
+			}()
+		}
+		wg.Wait()
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed >= 150*time.Millisecond {
+			t.Errorf("expected about 50ms for 4 concurrent calls, took %v", elapsed)
+		}
+		if stats := store.FaultStats("GetPolicies"); stats.Calls != 4 {
+			t.Errorf("expected 4 calls, got %+v", stats)
+		}
+	})
+}
@@ -1,14 +1,25 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"abac_go_example/events"
 	"abac_go_example/models"
+
+	"gorm.io/gorm"
 )
 
-// MockStorage implements Storage interface for testing
+// MockStorage implements Storage interface for testing. It is safe for concurrent use: methods
+// lock the storage, store copies of the entities they are given and return copies of what they
+// hold, so callers may mutate results without affecting the storage or each other.
 type MockStorage struct {
+	mu sync.RWMutex
+
 	subjects     map[string]*models.Subject
 	resources    map[string]*models.Resource
 	actions      map[string]*models.Action
@@ -18,6 +29,31 @@ type MockStorage struct {
 	userProfiles map[string]models.UserProfile // Store value, not pointer
 	roles        map[string]*models.Role
 	userRoles    map[string][]string // userID -> []roleIDs
+	snapshots    []*models.AttributeSnapshot
+	groups       map[string]*models.Group
+	memberships  map[groupMembershipKey]bool
+	changes      []*models.PolicyChange
+	exceptions   map[string]*models.AccessException
+	revocations  map[string]*models.Revocation
+	elevations   map[string]*models.Elevation
+	schedules    map[string]*models.PolicySchedule
+
+	// Soft-deleted entities, kept out of the maps above so reads never see them
+	deletedSubjects  map[string]*models.Subject
+	deletedResources map[string]*models.Resource
+	deletedActions   map[string]*models.Action
+	deletedPolicies  map[string]*models.Policy
+
+	events  EventPublisher // Set by SetEventPublisher
+	pending []events.Event // Published by unlock once the write lock is released
+	faults  mockFaults     // Set by InjectFault
+
+	subjectTypes *models.SubjectTypeRegistry // Set by SetSubjectTypes
+}
+
+// groupMembershipKey identifies a direct group membership in MockStorage
+type groupMembershipKey struct {
+	groupID, memberType, memberID string
 }
 
 // NewMockStorage creates a new mock storage instance
@@ -32,229 +68,610 @@ func NewMockStorage() *MockStorage {
 		userProfiles: make(map[string]models.UserProfile),
 		roles:        make(map[string]*models.Role),
 		userRoles:    make(map[string][]string),
+		groups:       make(map[string]*models.Group),
+		memberships:  make(map[groupMembershipKey]bool),
+		exceptions:   make(map[string]*models.AccessException),
+		revocations:  make(map[string]*models.Revocation),
+		elevations:   make(map[string]*models.Elevation),
+		schedules:    make(map[string]*models.PolicySchedule),
+
+		deletedSubjects:  make(map[string]*models.Subject),
+		deletedResources: make(map[string]*models.Resource),
+		deletedActions:   make(map[string]*models.Action),
+		deletedPolicies:  make(map[string]*models.Policy),
 	}
 }
 
-// SetPolicies sets the policies for testing
+// SetPolicies sets the policies for testing. Policies without UpdatedAt are stamped like
+// CreatePolicy does, so compiled forms stay cached across the copies returned by reads.
 func (m *MockStorage) SetPolicies(policies []*models.Policy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
 	m.policies = make(map[string]*models.Policy)
 	for _, policy := range policies {
-		m.policies[policy.ID] = policy
+		stored := clonePolicy(policy)
+		if stored.UpdatedAt.IsZero() {
+			stored.UpdatedAt = now
+		}
+		m.policies[policy.ID] = stored
 	}
 }
 
 // Subject operations
 func (m *MockStorage) CreateSubject(subject *models.Subject) error {
+	if err := m.fault("CreateSubject"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	return m.createSubject(subject)
+}
+
+func (m *MockStorage) createSubject(subject *models.Subject) error {
 	if subject.ID == "" {
 		return fmt.Errorf("subject ID cannot be empty")
 	}
+	if err := validateSubjects(m.subjectTypes, subject); err != nil {
+		return err
+	}
+	if _, deleted := m.deletedSubjects[subject.ID]; deleted {
+		return softDeletedConflict("subject", subject.ID)
+	}
 	subject.CreatedAt = time.Now()
 	subject.UpdatedAt = time.Now()
-	m.subjects[subject.ID] = subject
+	m.subjects[subject.ID] = cloneSubject(subject)
+	m.recordAttributeSnapshot(newAttributeSnapshot(models.SnapshotEntitySubject, subject.ID, subject.Attributes))
+	m.publish(events.Created, events.EntitySubject, subject.ID, subject.TenantID)
 	return nil
 }
 
 func (m *MockStorage) GetSubject(id string) (*models.Subject, error) {
+	if err := m.fault("GetSubject"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	subject, exists := m.subjects[id]
 	if !exists {
 		return nil, fmt.Errorf("subject not found: %s", id)
 	}
-	return subject, nil
+	return cloneSubject(subject), nil
 }
 
 func (m *MockStorage) UpdateSubject(subject *models.Subject) error {
+	if err := m.fault("UpdateSubject"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.subjects[subject.ID]; !exists {
 		return fmt.Errorf("subject not found: %s", subject.ID)
 	}
+	if err := validateSubjects(m.subjectTypes, subject); err != nil {
+		return err
+	}
 	subject.UpdatedAt = time.Now()
-	m.subjects[subject.ID] = subject
+	m.subjects[subject.ID] = cloneSubject(subject)
+	m.recordAttributeSnapshot(newAttributeSnapshot(models.SnapshotEntitySubject, subject.ID, subject.Attributes))
+	m.publish(events.Updated, events.EntitySubject, subject.ID, subject.TenantID)
 	return nil
 }
 
 func (m *MockStorage) DeleteSubject(id string) error {
-	if _, exists := m.subjects[id]; !exists {
+	if err := m.fault("DeleteSubject"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	subject, exists := m.subjects[id]
+	if !exists {
 		return fmt.Errorf("subject not found: %s", id)
 	}
+	subject.DeletedAt = softDeletedNow()
+	m.deletedSubjects[id] = subject
 	delete(m.subjects, id)
+	m.publish(events.Deleted, events.EntitySubject, id, subject.TenantID)
 	return nil
 }
 
-func (m *MockStorage) ListSubjects() ([]*models.Subject, error) {
-	subjects := make([]*models.Subject, 0, len(m.subjects))
+func (m *MockStorage) ListSubjects(opts ListOptions) ([]*models.Subject, *PageInfo, error) {
+	if err := m.fault("ListSubjects"); err != nil {
+		return nil, nil, err
+	}
+	query, err := opts.resolve(subjectListSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subjects := []*models.Subject{}
 	for _, subject := range m.subjects {
-		subjects = append(subjects, subject)
+		if (opts.Type == "" || subject.SubjectType == opts.Type) &&
+			(opts.UpdatedSince == nil || !subject.UpdatedAt.Before(*opts.UpdatedSince)) {
+			subjects = append(subjects, subject)
+		}
 	}
-	return subjects, nil
+	sortMockList(len(subjects), query, func(i int) string { return subjects[i].ID }, func(i int, field string) interface{} {
+		switch field {
+		case "subject_type":
+			return subjects[i].SubjectType
+		case "created_at":
+			return subjects[i].CreatedAt
+		default:
+			return subjects[i].UpdatedAt
+		}
+	}, func(i, j int) { subjects[i], subjects[j] = subjects[j], subjects[i] })
+
+	start, end := query.bounds(len(subjects))
+	return cloneAll(subjects[start:end], cloneSubject), query.pageInfo(int64(len(subjects)), end-start), nil
 }
 
 func (m *MockStorage) GetAllSubjects() ([]*models.Subject, error) {
-	return m.ListSubjects()
+	m.mu.RLock()
+	subjects := make([]*models.Subject, 0, len(m.subjects))
+	for _, subject := range m.subjects {
+		subjects = append(subjects, cloneSubject(subject))
+	}
+	m.mu.RUnlock()
+	return partialRead(m, "GetAllSubjects", subjects)
+}
+
+func (m *MockStorage) FindSubjectsByAttribute(key string, value interface{}) ([]*models.Subject, error) {
+	if err := m.fault("FindSubjectsByAttribute"); err != nil {
+		return nil, err
+	}
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subjects := []*models.Subject{}
+	for _, subject := range m.subjects {
+		if attributeMatches(subject.Attributes, key, value) {
+			subjects = append(subjects, cloneSubject(subject))
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].ID < subjects[j].ID })
+	return subjects, nil
 }
 
 // Resource operations
 func (m *MockStorage) CreateResource(resource *models.Resource) error {
+	if err := m.fault("CreateResource"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	return m.createResource(resource)
+}
+
+func (m *MockStorage) createResource(resource *models.Resource) error {
 	if resource.ID == "" {
 		return fmt.Errorf("resource ID cannot be empty")
 	}
-	m.resources[resource.ID] = resource
+	if _, deleted := m.deletedResources[resource.ID]; deleted {
+		return softDeletedConflict("resource", resource.ID)
+	}
+	resource.Tags = models.NormalizeTags(resource.Tags)
+	m.resources[resource.ID] = cloneResource(resource)
+	m.recordAttributeSnapshot(newAttributeSnapshot(models.SnapshotEntityResource, resource.ID, resource.Attributes))
+	m.publish(events.Created, events.EntityResource, resource.ID, resource.TenantID)
 	return nil
 }
 
 func (m *MockStorage) GetResource(id string) (*models.Resource, error) {
+	if err := m.fault("GetResource"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	resource, exists := m.resources[id]
 	if !exists {
 		return nil, fmt.Errorf("resource not found: %s", id)
 	}
-	return resource, nil
+	return cloneResource(resource), nil
 }
 
 func (m *MockStorage) UpdateResource(resource *models.Resource) error {
+	if err := m.fault("UpdateResource"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.resources[resource.ID]; !exists {
 		return fmt.Errorf("resource not found: %s", resource.ID)
 	}
-	m.resources[resource.ID] = resource
+	resource.Tags = models.NormalizeTags(resource.Tags)
+	m.resources[resource.ID] = cloneResource(resource)
+	m.recordAttributeSnapshot(newAttributeSnapshot(models.SnapshotEntityResource, resource.ID, resource.Attributes))
+	m.publish(events.Updated, events.EntityResource, resource.ID, resource.TenantID)
 	return nil
 }
 
 func (m *MockStorage) DeleteResource(id string) error {
-	if _, exists := m.resources[id]; !exists {
+	if err := m.fault("DeleteResource"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	resource, exists := m.resources[id]
+	if !exists {
 		return fmt.Errorf("resource not found: %s", id)
 	}
+	resource.DeletedAt = softDeletedNow()
+	m.deletedResources[id] = resource
 	delete(m.resources, id)
+	m.publish(events.Deleted, events.EntityResource, id, resource.TenantID)
 	return nil
 }
 
-func (m *MockStorage) ListResources() ([]*models.Resource, error) {
+func (m *MockStorage) ListResources(opts ListOptions) ([]*models.Resource, *PageInfo, error) {
+	if err := m.fault("ListResources"); err != nil {
+		return nil, nil, err
+	}
+	query, err := opts.resolve(resourceListSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resources := []*models.Resource{}
+	for _, resource := range m.resources {
+		if opts.Type == "" || resource.ResourceType == opts.Type {
+			resources = append(resources, resource)
+		}
+	}
+	sortMockList(len(resources), query, func(i int) string { return resources[i].ID }, func(i int, field string) interface{} {
+		if field == "resource_type" {
+			return resources[i].ResourceType
+		}
+		return resources[i].CreatedAt
+	}, func(i, j int) { resources[i], resources[j] = resources[j], resources[i] })
+
+	start, end := query.bounds(len(resources))
+	return cloneAll(resources[start:end], cloneResource), query.pageInfo(int64(len(resources)), end-start), nil
+}
+
+func (m *MockStorage) GetAllResources() ([]*models.Resource, error) {
+	m.mu.RLock()
 	resources := make([]*models.Resource, 0, len(m.resources))
 	for _, resource := range m.resources {
-		resources = append(resources, resource)
+		resources = append(resources, cloneResource(resource))
 	}
+	m.mu.RUnlock()
+	return partialRead(m, "GetAllResources", resources)
+}
+
+func (m *MockStorage) FindResourcesByAttribute(key string, value interface{}) ([]*models.Resource, error) {
+	if err := m.fault("FindResourcesByAttribute"); err != nil {
+		return nil, err
+	}
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resources := []*models.Resource{}
+	for _, resource := range m.resources {
+		if attributeMatches(resource.Attributes, key, value) {
+			resources = append(resources, cloneResource(resource))
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
 	return resources, nil
 }
 
-func (m *MockStorage) GetAllResources() ([]*models.Resource, error) {
-	return m.ListResources()
+func (m *MockStorage) FindResourcesByTag(tag string) ([]*models.Resource, error) {
+	if err := m.fault("FindResourcesByTag"); err != nil {
+		return nil, err
+	}
+	tag, err := normalizeSearchTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resources := []*models.Resource{}
+	for _, resource := range m.resources {
+		if resource.HasTag(tag) {
+			resources = append(resources, cloneResource(resource))
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	return resources, nil
 }
 
 // Action operations
 func (m *MockStorage) CreateAction(action *models.Action) error {
+	if err := m.fault("CreateAction"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if action.ID == "" {
 		return fmt.Errorf("action ID cannot be empty")
 	}
-	m.actions[action.ID] = action
+	if _, deleted := m.deletedActions[action.ID]; deleted {
+		return softDeletedConflict("action", action.ID)
+	}
+	m.actions[action.ID] = cloneAction(action)
 	return nil
 }
 
 func (m *MockStorage) GetAction(name string) (*models.Action, error) {
+	if err := m.fault("GetAction"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	// Search by action name instead of ID
 	for _, action := range m.actions {
 		if action.ActionName == name {
-			return action, nil
+			return cloneAction(action), nil
 		}
 	}
 	return nil, fmt.Errorf("action not found: %s", name)
 }
 
 func (m *MockStorage) GetActionByID(id string) (*models.Action, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	action, exists := m.actions[id]
 	if !exists {
 		return nil, fmt.Errorf("action not found: %s", id)
 	}
-	return action, nil
+	return cloneAction(action), nil
 }
 
 func (m *MockStorage) UpdateAction(action *models.Action) error {
+	if err := m.fault("UpdateAction"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.actions[action.ID]; !exists {
 		return fmt.Errorf("action not found: %s", action.ID)
 	}
-	m.actions[action.ID] = action
+	m.actions[action.ID] = cloneAction(action)
 	return nil
 }
 
 func (m *MockStorage) DeleteAction(id string) error {
-	if _, exists := m.actions[id]; !exists {
+	if err := m.fault("DeleteAction"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	action, exists := m.actions[id]
+	if !exists {
 		return fmt.Errorf("action not found: %s", id)
 	}
+	action.DeletedAt = softDeletedNow()
+	m.deletedActions[id] = action
 	delete(m.actions, id)
 	return nil
 }
 
-func (m *MockStorage) ListActions() ([]*models.Action, error) {
-	actions := make([]*models.Action, 0, len(m.actions))
+func (m *MockStorage) ListActions(opts ListOptions) ([]*models.Action, *PageInfo, error) {
+	if err := m.fault("ListActions"); err != nil {
+		return nil, nil, err
+	}
+	query, err := opts.resolve(actionListSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	actions := []*models.Action{}
 	for _, action := range m.actions {
-		actions = append(actions, action)
+		if opts.Type == "" || action.ActionCategory == opts.Type {
+			actions = append(actions, action)
+		}
 	}
-	return actions, nil
+	sortMockList(len(actions), query, func(i int) string { return actions[i].ID }, func(i int, field string) interface{} {
+		if field == "action_name" {
+			return actions[i].ActionName
+		}
+		return actions[i].ActionCategory
+	}, func(i, j int) { actions[i], actions[j] = actions[j], actions[i] })
+
+	start, end := query.bounds(len(actions))
+	return cloneAll(actions[start:end], cloneAction), query.pageInfo(int64(len(actions)), end-start), nil
 }
 
 func (m *MockStorage) GetAllActions() ([]*models.Action, error) {
-	return m.ListActions()
+	m.mu.RLock()
+	actions := make([]*models.Action, 0, len(m.actions))
+	for _, action := range m.actions {
+		actions = append(actions, cloneAction(action))
+	}
+	m.mu.RUnlock()
+	return partialRead(m, "GetAllActions", actions)
 }
 
 // Policy operations
 func (m *MockStorage) CreatePolicy(policy *models.Policy) error {
+	if err := m.fault("CreatePolicy"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	return m.createPolicy(policy)
+}
+
+func (m *MockStorage) createPolicy(policy *models.Policy) error {
 	if policy.ID == "" {
 		return fmt.Errorf("policy ID cannot be empty")
 	}
+	if _, deleted := m.deletedPolicies[policy.ID]; deleted {
+		return softDeletedConflict("policy", policy.ID)
+	}
 	policy.CreatedAt = time.Now()
 	policy.UpdatedAt = time.Now()
-	m.policies[policy.ID] = policy
+	policy.Revision = initialPolicyRevision
+	policy.DeletedAt = gorm.DeletedAt{}
+	policy.Tags = models.NormalizeTags(policy.Tags)
+	m.policies[policy.ID] = clonePolicy(policy)
+	m.publish(events.Created, events.EntityPolicy, policy.ID, policy.TenantID)
 	return nil
 }
 
 func (m *MockStorage) GetPolicy(id string) (*models.Policy, error) {
+	if err := m.fault("GetPolicy"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	policy, exists := m.policies[id]
 	if !exists {
-		return nil, fmt.Errorf("policy not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrPolicyNotFound, id)
 	}
-	return policy, nil
+	// A copy, so callers holding a stale revision do not see later updates
+	return clonePolicy(policy), nil
 }
 
 func (m *MockStorage) UpdatePolicy(policy *models.Policy) error {
-	if _, exists := m.policies[policy.ID]; !exists {
-		return fmt.Errorf("policy not found: %s", policy.ID)
+	if err := m.fault("UpdatePolicy"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	current, exists := m.policies[policy.ID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPolicyNotFound, policy.ID)
+	}
+	if current.Revision != policy.Revision {
+		return revisionConflict(policy.ID, policy.Revision, current.Revision)
 	}
+	policy.Revision++
 	policy.UpdatedAt = time.Now()
-	m.policies[policy.ID] = policy
+	policy.DeletedAt = gorm.DeletedAt{}
+	policy.Tags = models.NormalizeTags(policy.Tags)
+	m.policies[policy.ID] = clonePolicy(policy)
+	m.publish(events.Updated, events.EntityPolicy, policy.ID, policy.TenantID)
 	return nil
 }
 
 func (m *MockStorage) DeletePolicy(id string) error {
-	if _, exists := m.policies[id]; !exists {
+	if err := m.fault("DeletePolicy"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	policy, exists := m.policies[id]
+	if !exists {
 		return fmt.Errorf("policy not found: %s", id)
 	}
+	policy.DeletedAt = softDeletedNow()
+	m.deletedPolicies[id] = policy
 	delete(m.policies, id)
+	m.publish(events.Deleted, events.EntityPolicy, id, policy.TenantID)
 	return nil
 }
 
 func (m *MockStorage) GetPolicies() ([]*models.Policy, error) {
+	m.mu.RLock()
 	policies := make([]*models.Policy, 0, len(m.policies))
 	for _, policy := range m.policies {
-		policies = append(policies, policy)
+		policies = append(policies, clonePolicy(policy))
 	}
-	return policies, nil
+	m.mu.RUnlock()
+	// Stable order keeps evaluation (and deny short-circuiting) deterministic in tests
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return partialRead(m, "GetPolicies", policies)
+}
+
+// GetNamespacePolicies returns global policies plus those of namespace, in the order of GetPolicies
+func (m *MockStorage) GetNamespacePolicies(namespace string) ([]*models.Policy, error) {
+	policies, err := m.GetPolicies()
+	if err != nil {
+		return nil, err
+	}
+	return filterNamespace(policies, namespace), nil
 }
 
-func (m *MockStorage) ListPolicies() ([]*models.Policy, error) {
-	return m.GetPolicies()
+func (m *MockStorage) ListPolicies(opts ListOptions) ([]*models.Policy, *PageInfo, error) {
+	if err := m.fault("ListPolicies"); err != nil {
+		return nil, nil, err
+	}
+	query, err := opts.resolve(policyListSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tag, _ := normalizeSearchTag(opts.Tag)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policies := []*models.Policy{}
+	for _, policy := range m.policies {
+		if (opts.Enabled == nil || policy.Enabled == *opts.Enabled) &&
+			(opts.Namespace == nil || policy.Namespace == *opts.Namespace) &&
+			(opts.UpdatedSince == nil || !policy.UpdatedAt.Before(*opts.UpdatedSince)) &&
+			(opts.Owner == "" || policy.Owner == opts.Owner) &&
+			(opts.Tag == "" || policy.HasTag(tag)) {
+			policies = append(policies, policy)
+		}
+	}
+	sortMockList(len(policies), query, func(i int) string { return policies[i].ID }, func(i int, field string) interface{} {
+		switch field {
+		case "policy_name":
+			return policies[i].PolicyName
+		case "owner":
+			return policies[i].Owner
+		case "created_at":
+			return policies[i].CreatedAt
+		default:
+			return policies[i].UpdatedAt
+		}
+	}, func(i, j int) { policies[i], policies[j] = policies[j], policies[i] })
+
+	start, end := query.bounds(len(policies))
+	return cloneAll(policies[start:end], clonePolicy), query.pageInfo(int64(len(policies)), end-start), nil
 }
 
 // Audit operations
 func (m *MockStorage) CreateAuditLog(auditLog *models.AuditLog) error {
+	m.mu.Lock()
+	defer m.unlock()
+	return m.createAuditLog(auditLog)
+}
+
+func (m *MockStorage) createAuditLog(auditLog *models.AuditLog) error {
 	if auditLog.RequestID == "" {
 		return fmt.Errorf("audit log request ID cannot be empty")
 	}
 	auditLog.ID = int64(len(m.auditLogs) + 1)
-	auditLog.CreatedAt = time.Now()
-	m.auditLogs = append(m.auditLogs, auditLog)
+	// Like autoCreateTime, an explicit CreatedAt is kept
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = time.Now()
+	}
+	m.auditLogs = append(m.auditLogs, cloneAuditLog(auditLog))
 	return nil
 }
 
 func (m *MockStorage) LogAudit(auditLog *models.AuditLog) error {
-	return m.CreateAuditLog(auditLog)
+	if err := m.fault("LogAudit"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	return m.createAuditLog(auditLog)
 }
 
 func (m *MockStorage) GetAuditLogs(limit, offset int) ([]*models.AuditLog, error) {
+	if err := m.fault("GetAuditLogs"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if offset >= len(m.auditLogs) {
 		return []*models.AuditLog{}, nil
 	}
@@ -264,7 +681,703 @@ func (m *MockStorage) GetAuditLogs(limit, offset int) ([]*models.AuditLog, error
 		end = len(m.auditLogs)
 	}
 
-	return m.auditLogs[offset:end], nil
+	return cloneAll(m.auditLogs[offset:end], cloneAuditLog), nil
+}
+
+func (m *MockStorage) SubjectDecisions(subjectID string, since time.Time, limit int) ([]*models.AuditLog, error) {
+	if err := m.fault("SubjectDecisions"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	decisions := []*models.AuditLog{}
+	// Logs are appended in order, so walking backwards yields the newest first
+	for i := len(m.auditLogs) - 1; i >= 0 && len(decisions) < limit; i-- {
+		auditLog := m.auditLogs[i]
+		if auditLog.SubjectID == subjectID && !auditLog.CreatedAt.Before(since) && isDecisionLog(auditLog) {
+			decisions = append(decisions, cloneAuditLog(auditLog))
+		}
+	}
+	return decisions, nil
+}
+
+// Audit retention operations (partitions are derived from log timestamps)
+func (m *MockStorage) EnsureAuditPartitions(ctx context.Context, from time.Time, months int) error {
+	return nil
+}
+
+func (m *MockStorage) ListAuditPartitions(ctx context.Context) ([]AuditPartition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := make(map[string]bool)
+	partitions := make([]AuditPartition, 0)
+	for _, auditLog := range m.auditLogs {
+		partition := auditPartitionFor(auditLog.CreatedAt)
+		if !seen[partition.Name] {
+			seen[partition.Name] = true
+			partitions = append(partitions, partition)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Start.Before(partitions[j].Start) })
+	return partitions, nil
+}
+
+func (m *MockStorage) AuditLogsBetween(ctx context.Context, from, to time.Time) ([]*models.AuditLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	logs := make([]*models.AuditLog, 0)
+	for _, auditLog := range m.auditLogs {
+		if auditLog.CreatedAt.Before(to) && !auditLog.CreatedAt.Before(from) {
+			logs = append(logs, cloneAuditLog(auditLog))
+		}
+	}
+	return logs, nil
+}
+
+func (m *MockStorage) DropAuditPartition(ctx context.Context, name string) error {
+	partition, ok := parseAuditPartitionName(name)
+	if !ok {
+		return fmt.Errorf("invalid audit partition name %q", name)
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	m.removeAuditLogs(func(auditLog *models.AuditLog) bool {
+		return !auditLog.CreatedAt.Before(partition.Start) && auditLog.CreatedAt.Before(partition.End)
+	})
+	return nil
+}
+
+func (m *MockStorage) DeleteAuditLogsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.unlock()
+	return m.removeAuditLogs(func(auditLog *models.AuditLog) bool {
+		return auditLog.CreatedAt.Before(cutoff)
+	}), nil
+}
+
+// removeAuditLogs drops the audit logs matching remove and returns how many were dropped
+func (m *MockStorage) removeAuditLogs(remove func(*models.AuditLog) bool) int64 {
+	kept := make([]*models.AuditLog, 0, len(m.auditLogs))
+	for _, auditLog := range m.auditLogs {
+		if !remove(auditLog) {
+			kept = append(kept, auditLog)
+		}
+	}
+	removed := int64(len(m.auditLogs) - len(kept))
+	m.auditLogs = kept
+	return removed
+}
+
+// Bulk operations (all-or-nothing like the database implementations)
+func (m *MockStorage) BulkCreateSubjects(subjects []*models.Subject) error {
+	outcome := m.nextFault("BulkCreateSubjects")
+	if outcome.err != nil && !outcome.partial {
+		return outcome.err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	for _, subject := range subjects {
+		if subject.ID == "" {
+			return fmt.Errorf("subject ID cannot be empty")
+		}
+		if _, deleted := m.deletedSubjects[subject.ID]; deleted {
+			return softDeletedConflict("subject", subject.ID)
+		}
+	}
+	if err := validateSubjects(m.subjectTypes, subjects...); err != nil {
+		return err
+	}
+	for _, subject := range partialWrite(outcome, subjects) {
+		m.createSubject(subject)
+	}
+	return outcome.err
+}
+
+func (m *MockStorage) BulkCreateResources(resources []*models.Resource) error {
+	outcome := m.nextFault("BulkCreateResources")
+	if outcome.err != nil && !outcome.partial {
+		return outcome.err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	for _, resource := range resources {
+		if resource.ID == "" {
+			return fmt.Errorf("resource ID cannot be empty")
+		}
+		if _, deleted := m.deletedResources[resource.ID]; deleted {
+			return softDeletedConflict("resource", resource.ID)
+		}
+	}
+	for _, resource := range partialWrite(outcome, resources) {
+		m.createResource(resource)
+	}
+	return outcome.err
+}
+
+func (m *MockStorage) BulkCreatePolicies(policies []*models.Policy) error {
+	outcome := m.nextFault("BulkCreatePolicies")
+	if outcome.err != nil && !outcome.partial {
+		return outcome.err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	for _, policy := range policies {
+		if policy.ID == "" {
+			return fmt.Errorf("policy ID cannot be empty")
+		}
+		if _, deleted := m.deletedPolicies[policy.ID]; deleted {
+			return softDeletedConflict("policy", policy.ID)
+		}
+	}
+	for _, policy := range partialWrite(outcome, policies) {
+		m.createPolicy(policy)
+	}
+	return outcome.err
+}
+
+// Attribute history operations
+func (m *MockStorage) RecordAttributeSnapshot(snapshot *models.AttributeSnapshot) error {
+	m.mu.Lock()
+	defer m.unlock()
+	return m.recordAttributeSnapshot(snapshot)
+}
+
+func (m *MockStorage) recordAttributeSnapshot(snapshot *models.AttributeSnapshot) error {
+	if snapshot.ValidFrom.IsZero() {
+		snapshot.ValidFrom = time.Now().UTC()
+	}
+	snapshot.ID = int64(len(m.snapshots) + 1)
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+func (m *MockStorage) GetAttributeSnapshot(entityType, entityID string, asOf time.Time) (*models.AttributeSnapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var latest *models.AttributeSnapshot
+	for _, snapshot := range m.snapshots {
+		if snapshot.EntityType != entityType || snapshot.EntityID != entityID || snapshot.ValidFrom.After(asOf) {
+			continue
+		}
+		// Later records win ties, matching the PostgreSQL "valid_from DESC, id DESC" ordering
+		if latest == nil || !snapshot.ValidFrom.Before(latest.ValidFrom) {
+			latest = snapshot
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	copied.Attributes = cloneJSONMap(latest.Attributes)
+	return &copied, nil
+}
+
+// Policy change operations
+func (m *MockStorage) RecordPolicyChange(change *models.PolicyChange) error {
+	m.mu.Lock()
+	defer m.unlock()
+	stampPolicyChange(change)
+	change.ID = int64(len(m.changes) + 1)
+	m.changes = append(m.changes, shallowClone(change))
+	return nil
+}
+
+func (m *MockStorage) GetPolicyChanges(policyID string, limit int) ([]*models.PolicyChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	limit = policyChangeLimit(limit)
+	changes := []*models.PolicyChange{}
+	// Newest first; records are appended in order, matching "created_at DESC, id DESC"
+	for i := len(m.changes) - 1; i >= 0 && len(changes) < limit; i-- {
+		if m.changes[i].PolicyID == policyID {
+			changes = append(changes, shallowClone(m.changes[i]))
+		}
+	}
+	return changes, nil
+}
+
+// Group operations
+func (m *MockStorage) CreateGroup(group *models.Group) error {
+	if group.ID == "" {
+		return fmt.Errorf("group ID cannot be empty")
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	group.CreatedAt = time.Now()
+	m.groups[group.ID] = shallowClone(group)
+	return nil
+}
+
+func (m *MockStorage) GetGroup(id string) (*models.Group, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	group, exists := m.groups[id]
+	if !exists {
+		return nil, fmt.Errorf("group not found: %s", id)
+	}
+	return shallowClone(group), nil
+}
+
+func (m *MockStorage) DeleteGroup(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.groups, id)
+	for membership := range m.memberships {
+		if membership.groupID == id || (membership.memberType == models.GroupMemberGroup && membership.memberID == id) {
+			delete(m.memberships, membership)
+		}
+	}
+	return nil
+}
+
+func (m *MockStorage) AddGroupMember(groupID, memberType, memberID string) error {
+	// Validated before locking: cycle detection reads memberships through GetDirectGroups
+	if err := validateGroupMember(m, groupID, memberType, memberID); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	m.memberships[groupMembershipKey{groupID, memberType, memberID}] = true
+	return nil
+}
+
+func (m *MockStorage) RemoveGroupMember(groupID, memberType, memberID string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.memberships, groupMembershipKey{groupID, memberType, memberID})
+	return nil
+}
+
+func (m *MockStorage) GetDirectGroups(memberType, memberID string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var groupIDs []string
+	for membership := range m.memberships {
+		if membership.memberType == memberType && membership.memberID == memberID {
+			groupIDs = append(groupIDs, membership.groupID)
+		}
+	}
+	sort.Strings(groupIDs)
+	return groupIDs, nil
+}
+
+// Access exception operations
+func (m *MockStorage) CreateException(exception *models.AccessException) error {
+	if err := ValidateException(exception, time.Now()); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	if _, exists := m.exceptions[exception.ID]; exists {
+		return fmt.Errorf("access exception already exists: %s", exception.ID)
+	}
+	exception.CreatedAt = time.Now()
+	m.exceptions[exception.ID] = shallowClone(exception)
+	return nil
+}
+
+func (m *MockStorage) GetException(id string) (*models.AccessException, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	exception, exists := m.exceptions[id]
+	if !exists {
+		return nil, fmt.Errorf("access exception not found: %s", id)
+	}
+	return shallowClone(exception), nil
+}
+
+func (m *MockStorage) DeleteException(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.exceptions, id)
+	return nil
+}
+
+func (m *MockStorage) ListExceptions(subjectID string) ([]*models.AccessException, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	exceptions := []*models.AccessException{}
+	for _, exception := range m.exceptions {
+		if subjectID == "" || exception.SubjectID == subjectID {
+			exceptions = append(exceptions, shallowClone(exception))
+		}
+	}
+	sort.Slice(exceptions, func(i, j int) bool { return exceptions[i].ID < exceptions[j].ID })
+	return exceptions, nil
+}
+
+func (m *MockStorage) FindActiveExceptions(subjectID, resourceID, action string, at time.Time) ([]*models.AccessException, error) {
+	exceptions, _ := m.ListExceptions(subjectID)
+	active := []*models.AccessException{}
+	for _, exception := range exceptions {
+		if exception.ResourceID == resourceID && exception.Action == action && exception.ActiveAt(at) {
+			active = append(active, exception)
+		}
+	}
+	return active, nil
+}
+
+// Revocation operations
+func (m *MockStorage) CreateRevocation(revocation *models.Revocation) error {
+	if err := ValidateRevocation(revocation, time.Now()); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	if _, exists := m.revocations[revocation.ID]; exists {
+		return fmt.Errorf("revocation already exists: %s", revocation.ID)
+	}
+	revocation.CreatedAt = time.Now()
+	m.revocations[revocation.ID] = cloneRevocation(revocation)
+	m.publish(events.Created, events.EntityRevocation, revocation.ID, "")
+	return nil
+}
+
+func (m *MockStorage) GetRevocation(id string) (*models.Revocation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	revocation, exists := m.revocations[id]
+	if !exists {
+		return nil, fmt.Errorf("revocation not found: %s", id)
+	}
+	return cloneRevocation(revocation), nil
+}
+
+func (m *MockStorage) DeleteRevocation(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.revocations, id)
+	m.publish(events.Deleted, events.EntityRevocation, id, "")
+	return nil
+}
+
+func (m *MockStorage) ListRevocations() ([]*models.Revocation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	revocations := []*models.Revocation{}
+	for _, revocation := range m.revocations {
+		revocations = append(revocations, cloneRevocation(revocation))
+	}
+	sort.Slice(revocations, func(i, j int) bool { return revocations[i].ID < revocations[j].ID })
+	return revocations, nil
+}
+
+// Elevation operations
+func (m *MockStorage) CreateElevation(elevation *models.Elevation) error {
+	now := time.Now()
+	if err := ValidateElevation(elevation, now); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	if _, exists := m.elevations[elevation.ID]; exists {
+		return fmt.Errorf("elevation already exists: %s", elevation.ID)
+	}
+	elevation.CreatedAt = now
+	m.elevations[elevation.ID] = cloneElevation(elevation)
+	m.publish(events.Created, events.EntityElevation, elevation.ID, "")
+	return nil
+}
+
+func (m *MockStorage) GetElevation(id string) (*models.Elevation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	elevation, exists := m.elevations[id]
+	if !exists {
+		return nil, fmt.Errorf("elevation not found: %s", id)
+	}
+	return cloneElevation(elevation), nil
+}
+
+func (m *MockStorage) DeleteElevation(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.elevations, id)
+	m.publish(events.Deleted, events.EntityElevation, id, "")
+	return nil
+}
+
+func (m *MockStorage) ListElevations(subjectID string) ([]*models.Elevation, error) {
+	return m.filterElevations(func(elevation *models.Elevation) bool {
+		return subjectID == "" || elevation.SubjectID == subjectID
+	}), nil
+}
+
+func (m *MockStorage) FindActiveElevations(subjectID string, at time.Time) ([]*models.Elevation, error) {
+	return m.filterElevations(func(elevation *models.Elevation) bool {
+		return elevation.SubjectID == subjectID && elevation.ActiveAt(at)
+	}), nil
+}
+
+// filterElevations returns copies of the elevations matching keep, by ID
+func (m *MockStorage) filterElevations(keep func(*models.Elevation) bool) []*models.Elevation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	elevations := []*models.Elevation{}
+	for _, elevation := range m.elevations {
+		if keep(elevation) {
+			elevations = append(elevations, cloneElevation(elevation))
+		}
+	}
+	sort.Slice(elevations, func(i, j int) bool { return elevations[i].ID < elevations[j].ID })
+	return elevations
+}
+
+// Policy schedule operations
+func (m *MockStorage) CreatePolicySchedule(schedule *models.PolicySchedule) error {
+	now := time.Now()
+	if err := ValidatePolicySchedule(schedule, now); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
+	if _, exists := m.schedules[schedule.ID]; exists {
+		return fmt.Errorf("policy schedule already exists: %s", schedule.ID)
+	}
+	schedule.CreatedAt = now
+	schedule.Tags = models.NormalizeTags(schedule.Tags)
+	m.schedules[schedule.ID] = clonePolicySchedule(schedule)
+	m.publish(events.Created, events.EntityPolicySchedule, schedule.ID, "")
+	return nil
+}
+
+func (m *MockStorage) GetPolicySchedule(id string) (*models.PolicySchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	schedule, exists := m.schedules[id]
+	if !exists {
+		return nil, fmt.Errorf("policy schedule not found: %s", id)
+	}
+	return clonePolicySchedule(schedule), nil
+}
+
+func (m *MockStorage) DeletePolicySchedule(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	delete(m.schedules, id)
+	m.publish(events.Deleted, events.EntityPolicySchedule, id, "")
+	return nil
+}
+
+func (m *MockStorage) ListPolicySchedules() ([]*models.PolicySchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	schedules := []*models.PolicySchedule{}
+	for _, schedule := range m.schedules {
+		schedules = append(schedules, clonePolicySchedule(schedule))
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules, nil
+}
+
+func (m *MockStorage) DuePolicySchedules(at time.Time) ([]*models.PolicySchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	schedules := []*models.PolicySchedule{}
+	for _, schedule := range m.schedules {
+		if schedule.NextRunAt != nil && !schedule.NextRunAt.After(at) {
+			schedules = append(schedules, clonePolicySchedule(schedule))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].NextRunAt.Equal(*schedules[j].NextRunAt) {
+			return schedules[i].NextRunAt.Before(*schedules[j].NextRunAt)
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+	return schedules, nil
+}
+
+func (m *MockStorage) ClaimPolicyScheduleRun(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.unlock()
+	schedule, exists := m.schedules[id]
+	if !exists || schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(dueAt) {
+		return false, nil
+	}
+	schedule.NextRunAt = &leaseUntil
+	return true, nil
+}
+
+func (m *MockStorage) CompletePolicyScheduleRun(id string, ranAt time.Time, next *time.Time) error {
+	m.mu.Lock()
+	defer m.unlock()
+	schedule, exists := m.schedules[id]
+	if !exists {
+		return fmt.Errorf("policy schedule not found: %s", id)
+	}
+	schedule.LastRunAt = &ranAt
+	schedule.NextRunAt = clonePointer(next)
+	m.publish(events.Updated, events.EntityPolicySchedule, id, "")
+	return nil
+}
+
+// sortMockList sorts n records in place like the SQL ORDER BY of query: by the sort field, then by ID.
+// field returns a string or time.Time; swap exchanges two records.
+func sortMockList(n int, query listQuery, id func(i int) string, field func(i int, name string) interface{}, swap func(i, j int)) {
+	sort.Sort(mockListSorter{n: n, query: query, id: id, field: field, swap: swap})
+}
+
+// mockListSorter adapts sortMockList to sort.Interface
+type mockListSorter struct {
+	n     int
+	query listQuery
+	id    func(i int) string
+	field func(i int, name string) interface{}
+	swap  func(i, j int)
+}
+
+func (s mockListSorter) Len() int      { return s.n }
+func (s mockListSorter) Swap(i, j int) { s.swap(i, j) }
+func (s mockListSorter) Less(i, j int) bool {
+	cmp := 0
+	if s.query.sortField != "id" {
+		switch a := s.field(i, s.query.sortField).(type) {
+		case time.Time:
+			cmp = a.Compare(s.field(j, s.query.sortField).(time.Time))
+		case string:
+			cmp = strings.Compare(a, s.field(j, s.query.sortField).(string))
+		}
+	}
+	if cmp == 0 {
+		cmp = strings.Compare(s.id(i), s.id(j))
+	}
+	if s.query.descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// cloneAll copies each entity of a result page
+func cloneAll[T any](entities []*T, clone func(*T) *T) []*T {
+	copied := make([]*T, len(entities))
+	for i, entity := range entities {
+		copied[i] = clone(entity)
+	}
+	return copied
+}
+
+// Soft delete operations
+func (m *MockStorage) ListDeletedSubjects() ([]*models.Subject, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subjects := make([]*models.Subject, 0, len(m.deletedSubjects))
+	for _, subject := range m.deletedSubjects {
+		subjects = append(subjects, cloneSubject(subject))
+	}
+	sortDeleted(subjects, func(subject *models.Subject) (gorm.DeletedAt, string) { return subject.DeletedAt, subject.ID })
+	return subjects, nil
+}
+
+func (m *MockStorage) ListDeletedResources() ([]*models.Resource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resources := make([]*models.Resource, 0, len(m.deletedResources))
+	for _, resource := range m.deletedResources {
+		resources = append(resources, cloneResource(resource))
+	}
+	sortDeleted(resources, func(resource *models.Resource) (gorm.DeletedAt, string) { return resource.DeletedAt, resource.ID })
+	return resources, nil
+}
+
+func (m *MockStorage) ListDeletedActions() ([]*models.Action, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	actions := make([]*models.Action, 0, len(m.deletedActions))
+	for _, action := range m.deletedActions {
+		actions = append(actions, cloneAction(action))
+	}
+	sortDeleted(actions, func(action *models.Action) (gorm.DeletedAt, string) { return action.DeletedAt, action.ID })
+	return actions, nil
+}
+
+func (m *MockStorage) ListDeletedPolicies() ([]*models.Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policies := make([]*models.Policy, 0, len(m.deletedPolicies))
+	for _, policy := range m.deletedPolicies {
+		policies = append(policies, clonePolicy(policy))
+	}
+	sortDeleted(policies, func(policy *models.Policy) (gorm.DeletedAt, string) { return policy.DeletedAt, policy.ID })
+	return policies, nil
+}
+
+func (m *MockStorage) RestoreSubject(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	subject, deleted := m.deletedSubjects[id]
+	if !deleted {
+		return fmt.Errorf("%w subject: %s", ErrNotDeleted, id)
+	}
+	subject.DeletedAt = gorm.DeletedAt{}
+	m.subjects[id] = subject
+	delete(m.deletedSubjects, id)
+	m.publish(events.Restored, events.EntitySubject, id, subject.TenantID)
+	return nil
+}
+
+func (m *MockStorage) RestoreResource(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	resource, deleted := m.deletedResources[id]
+	if !deleted {
+		return fmt.Errorf("%w resource: %s", ErrNotDeleted, id)
+	}
+	resource.DeletedAt = gorm.DeletedAt{}
+	m.resources[id] = resource
+	delete(m.deletedResources, id)
+	m.publish(events.Restored, events.EntityResource, id, resource.TenantID)
+	return nil
+}
+
+func (m *MockStorage) RestoreAction(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	action, deleted := m.deletedActions[id]
+	if !deleted {
+		return fmt.Errorf("%w action: %s", ErrNotDeleted, id)
+	}
+	action.DeletedAt = gorm.DeletedAt{}
+	m.actions[id] = action
+	delete(m.deletedActions, id)
+	return nil
+}
+
+func (m *MockStorage) RestorePolicy(id string) error {
+	m.mu.Lock()
+	defer m.unlock()
+	policy, deleted := m.deletedPolicies[id]
+	if !deleted {
+		return fmt.Errorf("%w policy: %s", ErrNotDeleted, id)
+	}
+	policy.DeletedAt = gorm.DeletedAt{}
+	m.policies[id] = policy
+	delete(m.deletedPolicies, id)
+	m.publish(events.Restored, events.EntityPolicy, id, policy.TenantID)
+	return nil
+}
+
+// softDeletedNow is the DeletedAt of an entity deleted now
+func softDeletedNow() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+// softDeletedConflict mirrors the primary key violation a database reports when an ID is
+// re-created while its soft-deleted row still exists
+func softDeletedConflict(kind, id string) error {
+	return fmt.Errorf("%s %s is soft-deleted and must be restored instead of re-created", kind, id)
+}
+
+// sortDeleted orders soft-deleted entities like the database: most recently deleted first, then by ID
+func sortDeleted[T any](entities []T, key func(T) (gorm.DeletedAt, string)) {
+	sort.Slice(entities, func(i, j int) bool {
+		deletedI, idI := key(entities[i])
+		deletedJ, idJ := key(entities[j])
+		if !deletedI.Time.Equal(deletedJ.Time) {
+			return deletedI.Time.After(deletedJ.Time)
+		}
+		return idI < idJ
+	})
 }
 
 // Health check
@@ -281,11 +1394,25 @@ func (m *MockStorage) Close() error {
 
 // Clear clears all data
 func (m *MockStorage) Clear() {
+	m.mu.Lock()
+	defer m.unlock()
 	m.subjects = make(map[string]*models.Subject)
 	m.resources = make(map[string]*models.Resource)
 	m.actions = make(map[string]*models.Action)
 	m.policies = make(map[string]*models.Policy)
 	m.auditLogs = make([]*models.AuditLog, 0)
+	m.snapshots = nil
+	m.groups = make(map[string]*models.Group)
+	m.memberships = make(map[groupMembershipKey]bool)
+	m.changes = nil
+	m.exceptions = make(map[string]*models.AccessException)
+	m.revocations = make(map[string]*models.Revocation)
+	m.elevations = make(map[string]*models.Elevation)
+	m.schedules = make(map[string]*models.PolicySchedule)
+	m.deletedSubjects = make(map[string]*models.Subject)
+	m.deletedResources = make(map[string]*models.Resource)
+	m.deletedActions = make(map[string]*models.Action)
+	m.deletedPolicies = make(map[string]*models.Policy)
 }
 
 // SeedTestData seeds mock storage with test data
@@ -386,49 +1513,77 @@ func (m *MockStorage) SeedTestData() {
 
 // GetUser retrieves a user by ID
 func (m *MockStorage) GetUser(id string) (*models.User, error) {
+	if err := m.fault("GetUser"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	user, exists := m.users[id]
 	if !exists {
 		return nil, fmt.Errorf("user not found: %s", id)
 	}
-	return user, nil
+	return cloneUser(user), nil
 }
 
 // GetUserWithRelations retrieves a user with all relations
 func (m *MockStorage) GetUserWithRelations(id string) (*models.User, error) {
-	user, err := m.GetUser(id)
-	if err != nil {
+	if err := m.fault("GetUserWithRelations"); err != nil {
 		return nil, err
 	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	user, exists := m.users[id]
+	if !exists {
+		return nil, fmt.Errorf("user not found: %s", id)
+	}
+	return m.userWithRelations(user), nil
+}
+
+// userWithRelations returns a copy of user with its profile and roles loaded
+func (m *MockStorage) userWithRelations(user *models.User) *models.User {
+	copied := cloneUser(user)
 
 	// Load profile
-	if profile, exists := m.userProfiles[id]; exists {
-		user.Profile = &profile
+	if profile, exists := m.userProfiles[user.ID]; exists {
+		profile = cloneUserProfile(profile)
+		copied.Profile = &profile
 	}
 
 	// Load roles
-	if roleIDs, exists := m.userRoles[id]; exists {
-		user.Roles = make([]models.Role, 0, len(roleIDs))
+	if roleIDs, exists := m.userRoles[user.ID]; exists {
+		copied.Roles = make([]models.Role, 0, len(roleIDs))
 		for _, roleID := range roleIDs {
 			if role, exists := m.roles[roleID]; exists {
-				user.Roles = append(user.Roles, *role)
+				copied.Roles = append(copied.Roles, *role)
 			}
 		}
 	}
 
-	return user, nil
+	return copied
 }
 
 // GetUserProfile retrieves a user profile
 func (m *MockStorage) GetUserProfile(userID string) (*models.UserProfile, error) {
+	if err := m.fault("GetUserProfile"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	profile, exists := m.userProfiles[userID]
 	if !exists {
 		return nil, fmt.Errorf("user profile not found for user: %s", userID)
 	}
+	profile = cloneUserProfile(profile)
 	return &profile, nil
 }
 
 // GetUserRoles retrieves user roles
 func (m *MockStorage) GetUserRoles(userID string) ([]models.Role, error) {
+	if err := m.fault("GetUserRoles"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	roleIDs, exists := m.userRoles[userID]
 	if !exists {
 		return []models.Role{}, nil
@@ -445,6 +1600,9 @@ func (m *MockStorage) GetUserRoles(userID string) ([]models.Role, error) {
 
 // GetUserAttributes builds ABAC attributes from user data
 func (m *MockStorage) GetUserAttributes(userID string) (map[string]interface{}, error) {
+	if err := m.fault("GetUserAttributes"); err != nil {
+		return nil, err
+	}
 	user, err := m.GetUserWithRelations(userID)
 	if err != nil {
 		return nil, err
@@ -465,6 +1623,9 @@ func (m *MockStorage) GetUserAttributes(userID string) (map[string]interface{},
 
 // BuildSubjectFromUser creates a SubjectInterface from user ID
 func (m *MockStorage) BuildSubjectFromUser(userID string) (models.SubjectInterface, error) {
+	if err := m.fault("BuildSubjectFromUser"); err != nil {
+		return nil, err
+	}
 	user, err := m.GetUserWithRelations(userID)
 	if err != nil {
 		return nil, err
@@ -480,60 +1641,90 @@ func (m *MockStorage) BuildSubjectFromUser(userID string) (models.SubjectInterfa
 
 // GetAllUsers retrieves all users
 func (m *MockStorage) GetAllUsers(status string, limit, offset int) ([]*models.User, error) {
+	if err := m.fault("GetAllUsers"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	users := make([]*models.User, 0, len(m.users))
 	for _, user := range m.users {
 		if status != "" && user.Status != status {
 			continue
 		}
-		users = append(users, user)
+		users = append(users, cloneUser(user))
 	}
 	return users, nil
 }
 
 // CreateUser creates a new user
 func (m *MockStorage) CreateUser(user *models.User) error {
+	if err := m.fault("CreateUser"); err != nil {
+		return err
+	}
 	if user.ID == "" {
 		return fmt.Errorf("user ID cannot be empty")
 	}
+	m.mu.Lock()
+	defer m.unlock()
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	m.users[user.ID] = user
+	m.users[user.ID] = cloneUser(user)
 	return nil
 }
 
 // CreateUserProfile creates a new user profile
 func (m *MockStorage) CreateUserProfile(profile *models.UserProfile) error {
+	if err := m.fault("CreateUserProfile"); err != nil {
+		return err
+	}
 	if profile.UserID == "" {
 		return fmt.Errorf("user ID cannot be empty")
 	}
+	m.mu.Lock()
+	defer m.unlock()
 	profile.CreatedAt = time.Now()
 	profile.UpdatedAt = time.Now()
-	m.userProfiles[profile.UserID] = *profile
+	m.userProfiles[profile.UserID] = cloneUserProfile(*profile)
 	return nil
 }
 
 // UpdateUser updates a user
 func (m *MockStorage) UpdateUser(user *models.User) error {
+	if err := m.fault("UpdateUser"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.users[user.ID]; !exists {
 		return fmt.Errorf("user not found: %s", user.ID)
 	}
 	user.UpdatedAt = time.Now()
-	m.users[user.ID] = user
+	m.users[user.ID] = cloneUser(user)
 	return nil
 }
 
 // UpdateUserProfile updates a user profile
 func (m *MockStorage) UpdateUserProfile(profile *models.UserProfile) error {
+	if err := m.fault("UpdateUserProfile"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.userProfiles[profile.UserID]; !exists {
 		return fmt.Errorf("user profile not found for user: %s", profile.UserID)
 	}
 	profile.UpdatedAt = time.Now()
-	m.userProfiles[profile.UserID] = *profile
+	m.userProfiles[profile.UserID] = cloneUserProfile(*profile)
 	return nil
 }
 
 // DeleteUser deletes a user
 func (m *MockStorage) DeleteUser(id string) error {
+	if err := m.fault("DeleteUser"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.users[id]; !exists {
 		return fmt.Errorf("user not found: %s", id)
 	}
@@ -545,6 +1736,11 @@ func (m *MockStorage) DeleteUser(id string) error {
 
 // AssignRole assigns a role to a user
 func (m *MockStorage) AssignRole(userID, roleID, assignedBy string) error {
+	if err := m.fault("AssignRole"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if _, exists := m.users[userID]; !exists {
 		return fmt.Errorf("user not found: %s", userID)
 	}
@@ -569,6 +1765,11 @@ func (m *MockStorage) AssignRole(userID, roleID, assignedBy string) error {
 
 // RevokeRole revokes a role from a user
 func (m *MockStorage) RevokeRole(userID, roleID string) error {
+	if err := m.fault("RevokeRole"); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.unlock()
 	if roleIDs, exists := m.userRoles[userID]; exists {
 		newRoles := make([]string, 0, len(roleIDs))
 		for _, rid := range roleIDs {
@@ -583,9 +1784,15 @@ func (m *MockStorage) RevokeRole(userID, roleID string) error {
 
 // GetRoleByCode retrieves a role by code
 func (m *MockStorage) GetRoleByCode(code string) (*models.Role, error) {
+	if err := m.fault("GetRoleByCode"); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	for _, role := range m.roles {
 		if role.RoleCode == code {
-			return role, nil
+			copied := *role
+			return &copied, nil
 		}
 	}
 	return nil, fmt.Errorf("role not found: %s", code)
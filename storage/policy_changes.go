@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+)
+
+// DefaultPolicyChangeLimit is the number of changes GetPolicyChanges returns when limit is not positive
+const DefaultPolicyChangeLimit = 100
+
+// PolicyChangeStore is implemented by storages that keep the policy change audit trail
+type PolicyChangeStore interface {
+	// RecordPolicyChange appends a change (CreatedAt defaults to now)
+	RecordPolicyChange(change *models.PolicyChange) error
+	// GetPolicyChanges returns up to limit changes of a policy, newest first
+	GetPolicyChanges(policyID string, limit int) ([]*models.PolicyChange, error)
+}
+
+// untrackedPolicyFields are bookkeeping fields left out of policy diffs
+var untrackedPolicyFields = []string{"revision", "created_at", "updated_at", "deleted_at"}
+
+// RecordPolicyChange diffs before and after and appends the change to store's audit trail.
+// before is nil for creates and after is nil for deletes. Storages without a
+// PolicyChangeStore are skipped.
+func RecordPolicyChange(store Storage, action, actor string, before, after *models.Policy) error {
+	changeStore, ok := store.(PolicyChangeStore)
+	if !ok {
+		return nil
+	}
+	change, err := NewPolicyChange(action, actor, before, after)
+	if err != nil {
+		return err
+	}
+	return changeStore.RecordPolicyChange(change)
+}
+
+// NewPolicyChange builds the change record for a policy create, update or delete
+func NewPolicyChange(action, actor string, before, after *models.Policy) (*models.PolicyChange, error) {
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+	if subject == nil {
+		return nil, fmt.Errorf("policy change needs a policy")
+	}
+
+	diff, err := DiffPolicies(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PolicyChange{
+		PolicyID: subject.ID,
+		Action:   action,
+		Actor:    actor,
+		Revision: subject.Revision,
+		Diff:     diff,
+	}, nil
+}
+
+// DiffPolicies returns the JSON differences between two policy documents, ordered by path.
+// A nil policy is an empty document, so creates list every field as "add" and deletes as "remove".
+func DiffPolicies(before, after *models.Policy) (models.JSONPolicyDiff, error) {
+	beforeDoc, err := policyDocument(before)
+	if err != nil {
+		return nil, err
+	}
+	afterDoc, err := policyDocument(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := models.JSONPolicyDiff{}
+	diffJSON("", beforeDoc, afterDoc, &diff)
+	return diff, nil
+}
+
+// policyDocument converts a policy to its generic JSON form without bookkeeping fields
+func policyDocument(policy *models.Policy) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	if policy == nil {
+		return doc, nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy %s: %w", policy.ID, err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode policy %s: %w", policy.ID, err)
+	}
+	for _, field := range untrackedPolicyFields {
+		delete(doc, field)
+	}
+	return doc, nil
+}
+
+// diffJSON appends the operations turning before into after at path
+func diffJSON(path string, before, after interface{}, diff *models.JSONPolicyDiff) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			diffObjects(path, b, a, diff)
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			diffArrays(path, b, a, diff)
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*diff = append(*diff, models.PolicyDiffOp{Op: "replace", Path: path, Old: before, New: after})
+	}
+}
+
+func diffObjects(path string, before, after map[string]interface{}, diff *models.JSONPolicyDiff) {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, exists := before[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := path + "/" + escapeJSONPointer(key)
+		oldValue, inBefore := before[key]
+		newValue, inAfter := after[key]
+		switch {
+		case !inBefore:
+			*diff = append(*diff, models.PolicyDiffOp{Op: "add", Path: child, New: newValue})
+		case !inAfter:
+			*diff = append(*diff, models.PolicyDiffOp{Op: "remove", Path: child, Old: oldValue})
+		default:
+			diffJSON(child, oldValue, newValue, diff)
+		}
+	}
+}
+
+// diffArrays compares elements by index; statements are ordered, so a reorder shows as replacements
+func diffArrays(path string, before, after []interface{}, diff *models.JSONPolicyDiff) {
+	for i := 0; i < len(before) || i < len(after); i++ {
+		child := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(before):
+			*diff = append(*diff, models.PolicyDiffOp{Op: "add", Path: child, New: after[i]})
+		case i >= len(after):
+			*diff = append(*diff, models.PolicyDiffOp{Op: "remove", Path: child, Old: before[i]})
+		default:
+			diffJSON(child, before[i], after[i], diff)
+		}
+	}
+}
+
+// escapeJSONPointer escapes a key as a JSON Pointer reference token (RFC 6901)
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// policyChangeLimit applies DefaultPolicyChangeLimit to a non-positive limit
+func policyChangeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPolicyChangeLimit
+	}
+	return limit
+}
+
+// stampPolicyChange defaults CreatedAt to now
+func stampPolicyChange(change *models.PolicyChange) {
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now().UTC()
+	}
+}
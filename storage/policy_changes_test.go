@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestDiffPolicies(t *testing.T) {
+	before := &models.Policy{
+		ID:         "pol-1",
+		PolicyName: "Read",
+		Version:    "2024-10-21",
+		Enabled:    true,
+		Revision:   1,
+		Statement: []models.PolicyStatement{
+			{Sid: "Read", Effect: "Allow", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "*"}},
+		},
+	}
+	after := *before
+	after.Revision = 2
+	after.Statement = []models.PolicyStatement{
+		{Sid: "Read", Effect: "Deny", Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "*"}},
+		{Sid: "List", Effect: "Allow", Action: models.JSONActionResource{Single: "document:list"}, Resource: models.JSONActionResource{Single: "*"}},
+	}
+
+	diff, err := DiffPolicies(before, &after)
+	if err != nil {
+		t.Fatalf("DiffPolicies failed: %v", err)
+	}
+	// The revision bump is bookkeeping and not part of the diff
+	if len(diff) != 2 {
+		t.Fatalf("Expected 2 operations, got %+v", diff)
+	}
+	if op := diff[0]; op.Op != "replace" || op.Path != "/statement/0/Effect" || op.Old != "Allow" || op.New != "Deny" {
+		t.Errorf("Unexpected first operation %+v", op)
+	}
+	if op := diff[1]; op.Op != "add" || op.Path != "/statement/1" {
+		t.Errorf("Unexpected second operation %+v", op)
+	}
+
+	created, _ := DiffPolicies(nil, before)
+	for _, op := range created {
+		if op.Op != "add" || op.Path == "/revision" {
+			t.Errorf("Unexpected create operation %+v", op)
+		}
+	}
+	if unchanged, _ := DiffPolicies(before, before); len(unchanged) != 0 {
+		t.Errorf("Expected no differences, got %+v", unchanged)
+	}
+}
+
+func TestPolicyChangeStore(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			policy := &models.Policy{ID: "pol-1", PolicyName: "Original", Version: "2024-10-21", Enabled: true}
+			if err := store.CreatePolicy(policy); err != nil {
+				t.Fatalf("CreatePolicy failed: %v", err)
+			}
+			if err := RecordPolicyChange(store, models.PolicyChangeCreate, "alice", nil, policy); err != nil {
+				t.Fatalf("RecordPolicyChange failed: %v", err)
+			}
+
+			before, _ := store.GetPolicy("pol-1")
+			updated := *before
+			updated.Description = "tightened"
+			if err := store.UpdatePolicy(&updated); err != nil {
+				t.Fatalf("UpdatePolicy failed: %v", err)
+			}
+			if err := RecordPolicyChange(store, models.PolicyChangeUpdate, "bob", before, &updated); err != nil {
+				t.Fatalf("RecordPolicyChange failed: %v", err)
+			}
+			RecordPolicyChange(store, models.PolicyChangeCreate, "alice", nil, &models.Policy{ID: "pol-other"})
+
+			changes, err := store.(PolicyChangeStore).GetPolicyChanges("pol-1", 0)
+			if err != nil {
+				t.Fatalf("GetPolicyChanges failed: %v", err)
+			}
+			if len(changes) != 2 {
+				t.Fatalf("Expected 2 changes, got %d", len(changes))
+			}
+			latest := changes[0]
+			if latest.Action != models.PolicyChangeUpdate || latest.Actor != "bob" || latest.Revision != 2 || latest.CreatedAt.IsZero() {
+				t.Errorf("Unexpected latest change %+v", latest)
+			}
+			if len(latest.Diff) != 1 || latest.Diff[0].Path != "/description" || latest.Diff[0].New != "tightened" {
+				t.Errorf("Unexpected diff %+v", latest.Diff)
+			}
+			if changes[1].Action != models.PolicyChangeCreate || changes[1].Actor != "alice" {
+				t.Errorf("Unexpected first change %+v", changes[1])
+			}
+
+			if limited, _ := store.(PolicyChangeStore).GetPolicyChanges("pol-1", 1); len(limited) != 1 || limited[0].Actor != "bob" {
+				t.Errorf("Expected only the latest change, got %+v", limited)
+			}
+		})
+	}
+}
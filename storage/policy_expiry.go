@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+// DefaultPolicyExpiryInterval is how often PolicyExpiryJob checks for expired policies
+const DefaultPolicyExpiryInterval = time.Minute
+
+// PolicyExpiryActor is the actor recorded in policy_changes when the job disables a policy
+const PolicyExpiryActor = "system:policy-expiry"
+
+// PolicyExpiryJob disables enabled policies whose ExpiresAt has passed. The PDP already
+// ignores expired policies; the job keeps the stored state in line so temporary rules
+// (e.g. holiday lockdowns) need no manual cleanup.
+type PolicyExpiryJob struct {
+	storage  Storage
+	interval time.Duration
+	clock    clock.Clock
+}
+
+// NewPolicyExpiryJob creates an expiry job; a non-positive interval uses DefaultPolicyExpiryInterval
+func NewPolicyExpiryJob(storage Storage, interval time.Duration) *PolicyExpiryJob {
+	if interval <= 0 {
+		interval = DefaultPolicyExpiryInterval
+	}
+	return &PolicyExpiryJob{
+		storage:  storage,
+		interval: interval,
+		clock:    clock.NewRealClock(),
+	}
+}
+
+// PolicyExpiryIntervalFromEnv reads POLICY_EXPIRY_INTERVAL (e.g. "30s"), falling back to the default
+func PolicyExpiryIntervalFromEnv() time.Duration {
+	if interval, err := time.ParseDuration(getEnv("POLICY_EXPIRY_INTERVAL", "")); err == nil && interval > 0 {
+		return interval
+	}
+	return DefaultPolicyExpiryInterval
+}
+
+// SetClock configures the clock used to decide expiry (nil restores real time)
+func (j *PolicyExpiryJob) SetClock(c clock.Clock) {
+	j.clock = clock.OrReal(c)
+}
+
+// RunOnce disables every expired policy and returns their IDs
+func (j *PolicyExpiryJob) RunOnce(ctx context.Context) ([]string, error) {
+	policies, err := j.storage.GetPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	now := j.clock.Now()
+	expired := []string{}
+	for _, policy := range policies {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		if !policy.Enabled || !policy.IsExpiredAt(now) {
+			continue
+		}
+
+		updated := *policy
+		updated.Enabled = false
+		if err := j.storage.UpdatePolicy(&updated); err != nil {
+			return expired, fmt.Errorf("failed to disable expired policy %s: %w", policy.ID, err)
+		}
+		if err := RecordPolicyChange(j.storage, models.PolicyChangeUpdate, PolicyExpiryActor, policy, &updated); err != nil {
+			log.Printf("Failed to record expiry of policy %s: %v", policy.ID, err)
+		}
+		expired = append(expired, policy.ID)
+	}
+
+	return expired, nil
+}
+
+// Start runs the job immediately and then every interval until ctx is cancelled
+func (j *PolicyExpiryJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if expired, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Policy expiry run failed: %v", err)
+		} else if len(expired) > 0 {
+			log.Printf("Policy expiry: disabled %d expired policies %v", len(expired), expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
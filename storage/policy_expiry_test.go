@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+func TestPolicyExpiryJob(t *testing.T) {
+	now := time.Date(2024, time.December, 27, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	mockStorage := NewMockStorage()
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-expired", Enabled: true, ExpiresAt: &past},
+		{ID: "pol-expires-now", Enabled: true, ExpiresAt: &now},
+		{ID: "pol-active", Enabled: true, ExpiresAt: &future},
+		{ID: "pol-unbounded", Enabled: true},
+		{ID: "pol-disabled", Enabled: false, ExpiresAt: &past},
+	})
+
+	job := NewPolicyExpiryJob(mockStorage, 0)
+	job.SetClock(clock.NewMockClock(now))
+
+	expired, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if len(expired) != 2 || expired[0] != "pol-expired" || expired[1] != "pol-expires-now" {
+		t.Errorf("Unexpected expired policies: %v", expired)
+	}
+
+	for id, enabled := range map[string]bool{"pol-expired": false, "pol-expires-now": false, "pol-active": true, "pol-unbounded": true} {
+		policy, _ := mockStorage.GetPolicy(id)
+		if policy.Enabled != enabled {
+			t.Errorf("Expected %s enabled=%v", id, enabled)
+		}
+	}
+
+	changes, _ := mockStorage.GetPolicyChanges("pol-expired", 0)
+	if len(changes) != 1 || changes[0].Actor != PolicyExpiryActor || len(changes[0].Diff) != 1 || changes[0].Diff[0].Path != "/enabled" {
+		t.Errorf("Expected the expiry to be recorded, got %+v", changes)
+	}
+
+	// Already disabled policies are not reported again
+	if expired, _ := job.RunOnce(context.Background()); len(expired) != 0 {
+		t.Errorf("Expected no policies on second run, got %v", expired)
+	}
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"abac_go_example/models"
+)
+
+// PolicyNamespaceStore is implemented by storages that can load the policies of one namespace
+// without reading every other service's policies. PostgreSQLStorage and MockStorage implement it.
+type PolicyNamespaceStore interface {
+	// GetNamespacePolicies returns the enabled global policies plus those of namespace
+	GetNamespacePolicies(namespace string) ([]*models.Policy, error)
+}
+
+// GetNamespacePolicies returns the enabled policies that apply to namespace: global ones plus
+// those of namespace. Storages without PolicyNamespaceStore are filtered in memory.
+func GetNamespacePolicies(store Storage, namespace string) ([]*models.Policy, error) {
+	if namespaced, ok := store.(PolicyNamespaceStore); ok {
+		return namespaced.GetNamespacePolicies(namespace)
+	}
+
+	policies, err := store.GetPolicies()
+	if err != nil {
+		return nil, err
+	}
+	return filterNamespace(policies, namespace), nil
+}
+
+// filterNamespace keeps the policies that apply to namespace
+func filterNamespace(policies []*models.Policy, namespace string) []*models.Policy {
+	scoped := make([]*models.Policy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.InNamespace(namespace) {
+			scoped = append(scoped, policy)
+		}
+	}
+	return scoped
+}
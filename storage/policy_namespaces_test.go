@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestGetNamespacePolicies(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, policy := range []*models.Policy{
+				{ID: "pol-global", PolicyName: "Global", Enabled: true},
+				{ID: "pol-documents", PolicyName: "Documents", Namespace: "document-service", Enabled: true},
+				{ID: "pol-payments", PolicyName: "Payments", Namespace: "payment-service", Enabled: true},
+			} {
+				if err := store.CreatePolicy(policy); err != nil {
+					t.Fatalf("CreatePolicy failed: %v", err)
+				}
+			}
+
+			tests := []struct {
+				namespace string
+				expected  []string
+			}{
+				{"document-service", []string{"pol-documents", "pol-global"}},
+				{"payment-service", []string{"pol-global", "pol-payments"}},
+				{"", []string{"pol-global"}},
+				{"unknown-service", []string{"pol-global"}},
+			}
+			for _, test := range tests {
+				policies, err := GetNamespacePolicies(store, test.namespace)
+				if err != nil {
+					t.Fatalf("GetNamespacePolicies(%q) failed: %v", test.namespace, err)
+				}
+				if ids := policyIDs(policies); !slices.Equal(ids, test.expected) {
+					t.Errorf("GetNamespacePolicies(%q) = %v, want %v", test.namespace, ids, test.expected)
+				}
+			}
+
+			global := ""
+			page, _, err := store.ListPolicies(ListOptions{Namespace: &global})
+			if err != nil {
+				t.Fatalf("ListPolicies failed: %v", err)
+			}
+			if ids := policyIDs(page); !slices.Equal(ids, []string{"pol-global"}) {
+				t.Errorf("Expected only the global policy when listing namespace \"\", got %v", ids)
+			}
+		})
+	}
+}
+
+// policyIDs returns the sorted IDs of policies
+func policyIDs(policies []*models.Policy) []string {
+	ids := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		ids = append(ids, policy.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
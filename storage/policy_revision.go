@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// initialPolicyRevision is the revision of a newly created policy
+const initialPolicyRevision = 1
+
+var (
+	// ErrPolicyNotFound is returned when a policy ID does not exist
+	ErrPolicyNotFound = errors.New("policy not found")
+	// ErrRevisionConflict is returned when an update is based on a stale policy revision
+	ErrRevisionConflict = errors.New("policy revision conflict")
+)
+
+// RevisionConflictError reports the revision an update expected and the revision actually stored
+type RevisionConflictError struct {
+	PolicyID string
+	Expected int64
+	Current  int64
+}
+
+func (e *RevisionConflictError) Error() string {
+	return fmt.Sprintf("%v: policy %s is at revision %d, update was based on revision %d",
+		ErrRevisionConflict, e.PolicyID, e.Current, e.Expected)
+}
+
+// Unwrap lets errors.Is(err, ErrRevisionConflict) match
+func (e *RevisionConflictError) Unwrap() error {
+	return ErrRevisionConflict
+}
+
+// revisionConflict builds the error returned by UpdatePolicy for a stale revision
+func revisionConflict(policyID string, expected, current int64) error {
+	return &RevisionConflictError{PolicyID: policyID, Expected: expected, Current: current}
+}
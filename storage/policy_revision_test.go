@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestUpdatePolicy_Revision(t *testing.T) {
+	stores := map[string]Storage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := store.CreatePolicy(&models.Policy{ID: "pol-1", PolicyName: "Original", Enabled: true}); err != nil {
+				t.Fatalf("CreatePolicy failed: %v", err)
+			}
+
+			// Two admins load the same revision
+			first, err := store.GetPolicy("pol-1")
+			if err != nil {
+				t.Fatalf("GetPolicy failed: %v", err)
+			}
+			second, _ := store.GetPolicy("pol-1")
+			if first.Revision != 1 {
+				t.Fatalf("Expected revision 1 after create, got %d", first.Revision)
+			}
+
+			first.PolicyName = "First"
+			if err := store.UpdatePolicy(first); err != nil {
+				t.Fatalf("UpdatePolicy failed: %v", err)
+			}
+			if first.Revision != 2 {
+				t.Errorf("Expected revision 2 after update, got %d", first.Revision)
+			}
+
+			second.PolicyName = "Second"
+			err = store.UpdatePolicy(second)
+			var conflict *RevisionConflictError
+			if !errors.Is(err, ErrRevisionConflict) || !errors.As(err, &conflict) {
+				t.Fatalf("Expected revision conflict, got %v", err)
+			}
+			if conflict.Expected != 1 || conflict.Current != 2 || second.Revision != 1 {
+				t.Errorf("Unexpected conflict %+v (caller revision %d)", conflict, second.Revision)
+			}
+
+			stored, _ := store.GetPolicy("pol-1")
+			if stored.PolicyName != "First" || stored.Revision != 2 {
+				t.Errorf("Expected first update to be kept, got %q at revision %d", stored.PolicyName, stored.Revision)
+			}
+
+			if err := store.UpdatePolicy(&models.Policy{ID: "missing", Revision: 1}); !errors.Is(err, ErrPolicyNotFound) {
+				t.Errorf("Expected ErrPolicyNotFound, got %v", err)
+			}
+		})
+	}
+}
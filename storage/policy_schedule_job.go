@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// DefaultPolicyScheduleInterval is how often PolicyScheduleJob checks for due schedules
+const DefaultPolicyScheduleInterval = time.Minute
+
+// PolicyScheduleLease is how long a replica holds a claimed schedule run; a run that failed or whose
+// replica stopped before completing it becomes due again afterwards
+const PolicyScheduleLease = 5 * time.Minute
+
+// PolicyScheduleActor is the actor recorded in policy_changes and audit_logs for scheduled toggles
+const PolicyScheduleActor = "system:policy-schedule"
+
+// Audit log identifiers of bulk policy toggles
+const (
+	PolicyAuditBulkEnable  = "policy:bulk-enable"
+	PolicyAuditBulkDisable = "policy:bulk-disable"
+)
+
+// PolicyScheduleJob applies due policy schedules: it enables or disables the selected policies,
+// writes an audit record and moves each schedule to its next run. A run missed while the service
+// was down is applied once; recurring schedules then continue from the current time. Every replica
+// may run the job: a run is claimed atomically before it is applied, so it is applied once.
+type PolicyScheduleJob struct {
+	storage  Storage
+	interval time.Duration
+	clock    clock.Clock
+}
+
+// NewPolicyScheduleJob creates a schedule job; a non-positive interval uses DefaultPolicyScheduleInterval
+func NewPolicyScheduleJob(storage Storage, interval time.Duration) *PolicyScheduleJob {
+	if interval <= 0 {
+		interval = DefaultPolicyScheduleInterval
+	}
+	return &PolicyScheduleJob{
+		storage:  storage,
+		interval: interval,
+		clock:    clock.NewRealClock(),
+	}
+}
+
+// PolicyScheduleIntervalFromEnv reads POLICY_SCHEDULE_INTERVAL (e.g. "30s"), falling back to the default
+func PolicyScheduleIntervalFromEnv() time.Duration {
+	if interval, err := time.ParseDuration(getEnv("POLICY_SCHEDULE_INTERVAL", "")); err == nil && interval > 0 {
+		return interval
+	}
+	return DefaultPolicyScheduleInterval
+}
+
+// SetClock configures the clock used to decide which schedules are due (nil restores real time)
+func (j *PolicyScheduleJob) SetClock(c clock.Clock) {
+	j.clock = clock.OrReal(c)
+}
+
+// RunOnce applies every due schedule and returns their IDs. Storages without a
+// PolicyScheduleStore have nothing to run.
+func (j *PolicyScheduleJob) RunOnce(ctx context.Context) ([]string, error) {
+	scheduleStore, ok := j.storage.(PolicyScheduleStore)
+	if !ok {
+		return nil, nil
+	}
+
+	now := j.clock.Now()
+	due, err := scheduleStore.DuePolicySchedules(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due policy schedules: %w", err)
+	}
+
+	ran := []string{}
+	for _, schedule := range due {
+		if err := ctx.Err(); err != nil {
+			return ran, err
+		}
+
+		claimed, err := scheduleStore.ClaimPolicyScheduleRun(schedule.ID, *schedule.NextRunAt, now.Add(PolicyScheduleLease))
+		if err != nil {
+			return ran, fmt.Errorf("failed to claim policy schedule %s: %w", schedule.ID, err)
+		}
+		if !claimed {
+			// Another replica applies this run
+			continue
+		}
+
+		result, err := SetPoliciesEnabled(j.storage, schedule.Selector(), schedule.Enabled, PolicyScheduleActor)
+		if err != nil {
+			// The schedule is due again once the lease expires, so a later run retries it
+			log.Printf("Policy schedule %s failed: %v", schedule.ID, err)
+			continue
+		}
+		j.audit(schedule, result, now)
+
+		schedule.LastRunAt = &now
+		next, err := NextPolicyScheduleRun(schedule, now)
+		if err != nil {
+			log.Printf("Policy schedule %s has no next run: %v", schedule.ID, err)
+		}
+		if err := scheduleStore.CompletePolicyScheduleRun(schedule.ID, now, next); err != nil {
+			return ran, fmt.Errorf("failed to complete policy schedule %s: %w", schedule.ID, err)
+		}
+		ran = append(ran, schedule.ID)
+	}
+
+	return ran, nil
+}
+
+// audit records a scheduled toggle; the policies have already changed, so failures are only logged
+func (j *PolicyScheduleJob) audit(schedule *models.PolicySchedule, result *PolicyToggleResult, now time.Time) {
+	action := PolicyAuditBulkDisable
+	if schedule.Enabled {
+		action = PolicyAuditBulkEnable
+	}
+	auditLog := &models.AuditLog{
+		RequestID:  fmt.Sprintf("policy_schedule_%s_%d", schedule.ID, now.UnixNano()),
+		SubjectID:  PolicyScheduleActor,
+		ResourceID: schedule.ID,
+		ActionID:   action,
+		Decision:   constants.ResultPermit,
+		Context: models.JSONMap{
+			"schedule_id": schedule.ID,
+			"policy_ids":  []string(schedule.PolicyIDs),
+			"tags":        []string(schedule.Tags),
+			"matched":     result.Matched,
+			"changed":     result.Changed,
+		},
+		CreatedAt: now,
+	}
+	if err := j.storage.LogAudit(auditLog); err != nil {
+		log.Printf("Failed to audit policy schedule %s: %v", schedule.ID, err)
+	}
+}
+
+// Start runs the job immediately and then every interval until ctx is cancelled
+func (j *PolicyScheduleJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if ran, err := j.RunOnce(ctx); err != nil {
+			log.Printf("Policy schedule run failed: %v", err)
+		} else if len(ran) > 0 {
+			log.Printf("Policy schedule: applied %d schedules %v", len(ran), ran)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+func TestPolicyScheduleJob(t *testing.T) {
+	mockStorage := NewMockStorage()
+	mockStorage.SetPolicies([]*models.Policy{
+		{ID: "pol-lockdown", Enabled: false, Tags: models.JSONStringSlice{"lockdown"}},
+		{ID: "pol-regular", Enabled: true},
+	})
+
+	runAt := time.Now().Add(time.Hour)
+	for _, schedule := range []*models.PolicySchedule{
+		{ID: "sched-friday", Tags: models.JSONStringSlice{"lockdown"}, Enabled: true, Cron: "0 18 * * FRI"},
+		{ID: "sched-once", PolicyIDs: models.JSONStringSlice{"pol-regular"}, Enabled: false, RunAt: &runAt},
+	} {
+		if err := mockStorage.CreatePolicySchedule(schedule); err != nil {
+			t.Fatalf("CreatePolicySchedule(%s) failed: %v", schedule.ID, err)
+		}
+	}
+	friday, _ := mockStorage.GetPolicySchedule("sched-friday")
+	now := friday.NextRunAt.Add(time.Minute)
+	// What a second replica saw due before this one applied the schedules
+	staleDue, _ := mockStorage.DuePolicySchedules(now)
+
+	job := NewPolicyScheduleJob(mockStorage, 0)
+	job.SetClock(clock.NewMockClock(now))
+
+	ran, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if len(ran) != 2 || ran[0] != "sched-once" || ran[1] != "sched-friday" {
+		t.Errorf("Expected both schedules to run in due order, got %v", ran)
+	}
+	for id, enabled := range map[string]bool{"pol-lockdown": true, "pol-regular": false} {
+		if policy, _ := mockStorage.GetPolicy(id); policy.Enabled != enabled {
+			t.Errorf("Expected %s enabled=%v", id, enabled)
+		}
+	}
+
+	changes, _ := mockStorage.GetPolicyChanges("pol-lockdown", 0)
+	if len(changes) != 1 || changes[0].Actor != PolicyScheduleActor {
+		t.Errorf("Expected the scheduled toggle to be recorded, got %+v", changes)
+	}
+	auditLogs := mockStorage.Snapshot().AuditLogs
+	if len(auditLogs) != 2 || auditLogs[1].ActionID != PolicyAuditBulkEnable || auditLogs[1].ResourceID != "sched-friday" ||
+		auditLogs[0].ActionID != PolicyAuditBulkDisable || auditLogs[0].SubjectID != PolicyScheduleActor {
+		t.Errorf("Expected an audit record per run, got %+v", auditLogs)
+	}
+	for _, auditLog := range auditLogs {
+		if !auditLog.CreatedAt.Equal(now) || auditLog.RequestID != fmt.Sprintf("policy_schedule_%s_%d", auditLog.ResourceID, now.UnixNano()) {
+			t.Errorf("Expected the audit record to use the job clock, got %+v", auditLog)
+		}
+	}
+
+	// A replica that saw the same schedules due cannot claim them again
+	replica := NewPolicyScheduleJob(staleDueStorage{MockStorage: mockStorage, due: staleDue}, 0)
+	replica.SetClock(clock.NewMockClock(now))
+	if ran, err := replica.RunOnce(context.Background()); err != nil || len(ran) != 0 {
+		t.Errorf("Expected the replica to skip claimed schedules, got %v (%v)", ran, err)
+	}
+	if auditLogs := mockStorage.Snapshot().AuditLogs; len(auditLogs) != 2 {
+		t.Errorf("Expected no duplicate audit records, got %d", len(auditLogs))
+	}
+
+	// The recurring schedule moves to the next Friday, the one-shot schedule is done
+	if friday, _ := mockStorage.GetPolicySchedule("sched-friday"); friday.NextRunAt == nil ||
+		!friday.NextRunAt.Equal(now.Add(7*24*time.Hour-time.Minute)) {
+		t.Errorf("Expected sched-friday due a week later, got %v", friday.NextRunAt)
+	}
+	if once, _ := mockStorage.GetPolicySchedule("sched-once"); once.NextRunAt != nil || once.LastRunAt == nil {
+		t.Errorf("Expected sched-once to be done, got %+v", once)
+	}
+	if ran, _ := job.RunOnce(context.Background()); len(ran) != 0 {
+		t.Errorf("Expected nothing due on second run, got %v", ran)
+	}
+}
+
+// staleDueStorage returns due schedules read before another replica ran them
+type staleDueStorage struct {
+	*MockStorage
+	due []*models.PolicySchedule
+}
+
+func (s staleDueStorage) DuePolicySchedules(time.Time) ([]*models.PolicySchedule, error) {
+	return s.due, nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"abac_go_example/cron"
+	"abac_go_example/models"
+)
+
+// ErrInvalidPolicySchedule is returned when creating a schedule without a selector, without
+// exactly one of cron and run_at, with an unparsable cron expression or timezone, or with a
+// run_at in the past
+var ErrInvalidPolicySchedule = errors.New("invalid policy schedule")
+
+// PolicyScheduleStore is implemented by storages that keep policy schedules: planned bulk
+// enables and disables that PolicyScheduleJob applies when they are due. Writes publish
+// events.EntityPolicySchedule.
+type PolicyScheduleStore interface {
+	// CreatePolicySchedule validates and stores a schedule, computing its NextRunAt
+	CreatePolicySchedule(schedule *models.PolicySchedule) error
+	GetPolicySchedule(id string) (*models.PolicySchedule, error)
+	DeletePolicySchedule(id string) error
+	// ListPolicySchedules returns every schedule, by ID
+	ListPolicySchedules() ([]*models.PolicySchedule, error)
+	// DuePolicySchedules returns the schedules whose NextRunAt is at or before at, by NextRunAt then ID
+	DuePolicySchedules(at time.Time) ([]*models.PolicySchedule, error)
+	// ClaimPolicyScheduleRun atomically moves a schedule still due at dueAt to leaseUntil, so only one
+	// replica applies the run. It returns false when another replica claimed or completed it first.
+	ClaimPolicyScheduleRun(id string, dueAt, leaseUntil time.Time) (bool, error)
+	// CompletePolicyScheduleRun records a run at ranAt and the next due time (nil when done)
+	CompletePolicyScheduleRun(id string, ranAt time.Time, next *time.Time) error
+}
+
+// ValidatePolicySchedule checks a schedule and sets its NextRunAt after now
+func ValidatePolicySchedule(schedule *models.PolicySchedule, now time.Time) error {
+	switch {
+	case schedule.ID == "":
+		return fmt.Errorf("%w: id is required", ErrInvalidPolicySchedule)
+	case schedule.Selector().IsEmpty():
+		return fmt.Errorf("%w: policy_ids or tags are required", ErrInvalidPolicySchedule)
+	case (schedule.Cron == "") == (schedule.RunAt == nil):
+		return fmt.Errorf("%w: exactly one of cron and run_at is required", ErrInvalidPolicySchedule)
+	case schedule.RunAt != nil && !schedule.RunAt.After(now):
+		return fmt.Errorf("%w: run_at must be in the future", ErrInvalidPolicySchedule)
+	}
+	next, err := NextPolicyScheduleRun(schedule, now)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPolicySchedule, err)
+	}
+	if next == nil {
+		return fmt.Errorf("%w: schedule never runs", ErrInvalidPolicySchedule)
+	}
+	schedule.NextRunAt = next
+	return nil
+}
+
+// NextPolicyScheduleRun returns when schedule is next due: the next cron time in the
+// schedule's timezone, or RunAt for a one-shot schedule that has not run yet. It returns nil
+// when the schedule is done.
+func NextPolicyScheduleRun(schedule *models.PolicySchedule, after time.Time) (*time.Time, error) {
+	if schedule.Cron == "" {
+		if schedule.RunAt == nil || schedule.LastRunAt != nil {
+			return nil, nil
+		}
+		runAt := schedule.RunAt.UTC()
+		return &runAt, nil
+	}
+
+	spec, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+	location := time.UTC
+	if schedule.Timezone != "" {
+		if location, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", schedule.Timezone)
+		}
+	}
+	next := spec.Next(after.In(location))
+	if next.IsZero() {
+		return nil, nil
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+// PolicyToggleResult reports a bulk enable or disable
+type PolicyToggleResult struct {
+	Matched []string `json:"matched"` // Selected policies, by ID
+	Changed []string `json:"changed"` // Selected policies that were not already in the target state
+}
+
+// SetPoliciesEnabled enables or disables every policy selector selects, recording each change
+// under actor. Policies already in the target state are left untouched. On error the result
+// lists the policies changed so far.
+func SetPoliciesEnabled(store Storage, selector models.PolicySelector, enabled bool, actor string) (*PolicyToggleResult, error) {
+	if selector.IsEmpty() {
+		return nil, fmt.Errorf("policy selector is empty")
+	}
+	policies, err := listAllPolicies(store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	result := &PolicyToggleResult{Matched: []string{}, Changed: []string{}}
+	for _, policy := range policies {
+		if !selector.Matches(policy) {
+			continue
+		}
+		result.Matched = append(result.Matched, policy.ID)
+		if policy.Enabled == enabled {
+			continue
+		}
+
+		updated := *policy
+		updated.Enabled = enabled
+		if err := store.UpdatePolicy(&updated); err != nil {
+			return result, fmt.Errorf("failed to update policy %s: %w", policy.ID, err)
+		}
+		if err := RecordPolicyChange(store, models.PolicyChangeUpdate, actor, policy, &updated); err != nil {
+			log.Printf("Failed to record bulk toggle of policy %s: %v", policy.ID, err)
+		}
+		result.Changed = append(result.Changed, policy.ID)
+	}
+	return result, nil
+}
+
+// listAllPolicies pages through ListPolicies, which unlike GetPolicies includes disabled policies
+func listAllPolicies(store Storage) ([]*models.Policy, error) {
+	all := []*models.Policy{}
+	opts := ListOptions{Limit: MaxListLimit}
+	for {
+		page, info, err := store.ListPolicies(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if info.NextCursor == "" {
+			return all, nil
+		}
+		opts.Cursor = info.NextCursor
+	}
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestPolicyScheduleStore(t *testing.T) {
+	stores := map[string]interface {
+		PolicyScheduleStore
+		EventStore
+	}{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			store.SetEventPublisher(publisher)
+
+			runAt := time.Now().Add(time.Hour).Truncate(time.Second)
+			for _, schedule := range []*models.PolicySchedule{
+				{ID: "sched-weekend", Tags: models.JSONStringSlice{" lockdown", "lockdown"}, Enabled: true, Cron: "0 18 * * FRI"},
+				{ID: "sched-once", PolicyIDs: models.JSONStringSlice{"pol-1"}, Enabled: false, RunAt: &runAt, CreatedBy: "alice"},
+			} {
+				if err := store.CreatePolicySchedule(schedule); err != nil {
+					t.Fatalf("CreatePolicySchedule(%s) failed: %v", schedule.ID, err)
+				}
+			}
+
+			listed, err := store.ListPolicySchedules()
+			if err != nil || len(listed) != 2 || listed[0].ID != "sched-once" || listed[1].ID != "sched-weekend" {
+				t.Fatalf("Expected sched-once and sched-weekend by ID, got %v (%v)", listed, err)
+			}
+			weekend, err := store.GetPolicySchedule("sched-weekend")
+			if err != nil || len(weekend.Tags) != 1 || weekend.Tags[0] != "lockdown" || weekend.NextRunAt == nil {
+				t.Fatalf("Unexpected schedule %+v (%v)", weekend, err)
+			}
+			if next := weekend.NextRunAt.UTC(); next.Weekday() != time.Friday || next.Hour() != 18 || next.Minute() != 0 {
+				t.Errorf("Expected the next run on a Friday at 18:00 UTC, got %v", next)
+			}
+			once, _ := store.GetPolicySchedule("sched-once")
+			if once.NextRunAt == nil || !once.NextRunAt.Equal(runAt) {
+				t.Errorf("Expected the one-shot schedule due at %v, got %v", runAt, once.NextRunAt)
+			}
+
+			due, err := store.DuePolicySchedules(runAt)
+			if err != nil || len(due) != 1 || due[0].ID != "sched-once" {
+				t.Errorf("Expected sched-once due at run_at, got %v (%v)", due, err)
+			}
+			if err := store.CompletePolicyScheduleRun("sched-once", runAt, nil); err != nil {
+				t.Fatalf("CompletePolicyScheduleRun failed: %v", err)
+			}
+			if due, _ := store.DuePolicySchedules(runAt); len(due) != 0 {
+				t.Errorf("Expected nothing due after the run, got %v", due)
+			}
+			if once, _ := store.GetPolicySchedule("sched-once"); once.LastRunAt == nil || !once.LastRunAt.Equal(runAt) || once.NextRunAt != nil {
+				t.Errorf("Expected the run to be recorded, got %+v", once)
+			}
+
+			if err := store.DeletePolicySchedule("sched-weekend"); err != nil {
+				t.Fatalf("DeletePolicySchedule failed: %v", err)
+			}
+			if _, err := store.GetPolicySchedule("sched-weekend"); err == nil {
+				t.Error("Expected the deleted schedule to be gone")
+			}
+			if want := "deleted policy_schedule sched-weekend "; len(*publisher) != 4 || (*publisher)[3] != want {
+				t.Errorf("Expected created, updated and deleted schedule events, got %v", *publisher)
+			}
+
+			past := time.Now().Add(-time.Minute)
+			invalid := []*models.PolicySchedule{
+				{ID: "bad-1", Enabled: true, Cron: "0 18 * * FRI"},
+				{ID: "bad-2", Tags: models.JSONStringSlice{"lockdown"}},
+				{ID: "bad-3", Tags: models.JSONStringSlice{"lockdown"}, Cron: "0 18 * * FRI", RunAt: &runAt},
+				{ID: "bad-4", Tags: models.JSONStringSlice{"lockdown"}, Cron: "0 25 * * *"},
+				{ID: "bad-5", Tags: models.JSONStringSlice{"lockdown"}, Cron: "0 18 * * FRI", Timezone: "Mars/Olympus"},
+				{ID: "bad-6", Tags: models.JSONStringSlice{"lockdown"}, RunAt: &past},
+				{ID: "bad-7", Tags: models.JSONStringSlice{"lockdown"}, Cron: "0 0 30 2 *"},
+			}
+			for _, schedule := range invalid {
+				if err := store.CreatePolicySchedule(schedule); !errors.Is(err, ErrInvalidPolicySchedule) {
+					t.Errorf("%s: expected ErrInvalidPolicySchedule, got %v", schedule.ID, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPoliciesEnabled(t *testing.T) {
+	stores := map[string]interface {
+		Storage
+		PolicyChangeStore
+	}{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			allow := models.JSONStatements{{Sid: "Allow", Effect: "Allow",
+				Action: models.JSONActionResource{Single: "document:read"}, Resource: models.JSONActionResource{Single: "*"}}}
+			// Created enabled and then disabled: the policies table defaults enabled to true
+			for _, policy := range []*models.Policy{
+				{ID: "pol-lockdown-a", PolicyName: "pol-lockdown-a", Tags: models.JSONStringSlice{"lockdown"}, Statement: allow},
+				{ID: "pol-lockdown-b", PolicyName: "pol-lockdown-b", Tags: models.JSONStringSlice{"lockdown", "domain=pci"}, Statement: allow},
+				{ID: "pol-named", PolicyName: "pol-named", Statement: allow},
+				{ID: "pol-other", PolicyName: "pol-other", Tags: models.JSONStringSlice{"other"}, Statement: allow},
+			} {
+				policy.Enabled = true
+				if err := store.CreatePolicy(policy); err != nil {
+					t.Fatalf("CreatePolicy(%s) failed: %v", policy.ID, err)
+				}
+				if policy.ID != "pol-lockdown-b" {
+					policy.Enabled = false
+					if err := store.UpdatePolicy(policy); err != nil {
+						t.Fatalf("UpdatePolicy(%s) failed: %v", policy.ID, err)
+					}
+				}
+			}
+
+			selector := models.PolicySelector{PolicyIDs: []string{"pol-named"}, Tags: []string{"lockdown"}}
+			result, err := SetPoliciesEnabled(store, selector, true, "alice")
+			if err != nil {
+				t.Fatalf("SetPoliciesEnabled failed: %v", err)
+			}
+			if len(result.Matched) != 3 || len(result.Changed) != 2 || result.Changed[0] != "pol-lockdown-a" || result.Changed[1] != "pol-named" {
+				t.Errorf("Unexpected toggle result %+v", result)
+			}
+			for id, enabled := range map[string]bool{"pol-lockdown-a": true, "pol-lockdown-b": true, "pol-named": true, "pol-other": false} {
+				if policy, _ := store.GetPolicy(id); policy.Enabled != enabled {
+					t.Errorf("Expected %s enabled=%v", id, enabled)
+				}
+			}
+			changes, _ := store.GetPolicyChanges("pol-named", 0)
+			if len(changes) != 1 || changes[0].Actor != "alice" {
+				t.Errorf("Expected the toggle to be recorded, got %+v", changes)
+			}
+
+			if _, err := SetPoliciesEnabled(store, models.PolicySelector{}, true, "alice"); err == nil {
+				t.Error("Expected an error for an empty selector")
+			}
+		})
+	}
+}
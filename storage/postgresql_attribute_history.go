@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// RecordAttributeSnapshot stores a point-in-time attribute snapshot
+func (s *PostgreSQLStorage) RecordAttributeSnapshot(snapshot *models.AttributeSnapshot) error {
+	return recordAttributeSnapshot(s.db, snapshot)
+}
+
+// GetAttributeSnapshot returns the latest snapshot of an entity at or before asOf
+func (s *PostgreSQLStorage) GetAttributeSnapshot(entityType, entityID string, asOf time.Time) (*models.AttributeSnapshot, error) {
+	var snapshot models.AttributeSnapshot
+	result := s.db.Where("entity_type = ? AND entity_id = ? AND valid_from <= ?", entityType, entityID, asOf.UTC()).
+		Order("valid_from DESC, id DESC").
+		First(&snapshot)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get attribute snapshot: %w", result.Error)
+	}
+	return &snapshot, nil
+}
+
+// recordAttributeSnapshot inserts a snapshot using db (a transaction when called from CRUD methods)
+func recordAttributeSnapshot(db *gorm.DB, snapshot *models.AttributeSnapshot) error {
+	if snapshot.ValidFrom.IsZero() {
+		snapshot.ValidFrom = time.Now().UTC()
+	}
+	if err := db.Create(snapshot).Error; err != nil {
+		return fmt.Errorf("failed to record attribute snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateElevation creates a new elevation
+func (s *PostgreSQLStorage) CreateElevation(elevation *models.Elevation) error {
+	now := time.Now()
+	if err := ValidateElevation(elevation, now); err != nil {
+		return err
+	}
+	// Stored in UTC so expiry comparisons also hold where times are compared as text (SQLite)
+	elevation.CreatedAt = now.UTC()
+	elevation.ExpiresAt = elevation.ExpiresAt.UTC()
+	if err := s.db.Create(elevation).Error; err != nil {
+		return fmt.Errorf("failed to create elevation: %w", err)
+	}
+	s.publish(events.Created, events.EntityElevation, elevation.ID, "")
+	return nil
+}
+
+// GetElevation retrieves an elevation by ID
+func (s *PostgreSQLStorage) GetElevation(id string) (*models.Elevation, error) {
+	var elevation models.Elevation
+	result := s.db.Where("id = ?", id).First(&elevation)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("elevation not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get elevation: %w", result.Error)
+	}
+	return &elevation, nil
+}
+
+// DeleteElevation ends an elevation
+func (s *PostgreSQLStorage) DeleteElevation(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.Elevation{}).Error; err != nil {
+		return fmt.Errorf("failed to delete elevation: %w", err)
+	}
+	s.publish(events.Deleted, events.EntityElevation, id, "")
+	return nil
+}
+
+// ListElevations lists the elevations of a subject, or all elevations when subjectID is empty
+func (s *PostgreSQLStorage) ListElevations(subjectID string) ([]*models.Elevation, error) {
+	query := s.db.Order("id")
+	if subjectID != "" {
+		query = query.Where("subject_id = ?", subjectID)
+	}
+	var elevations []*models.Elevation
+	if err := query.Find(&elevations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list elevations: %w", err)
+	}
+	return elevations, nil
+}
+
+// FindActiveElevations returns the elevations of a subject active at a point in time
+func (s *PostgreSQLStorage) FindActiveElevations(subjectID string, at time.Time) ([]*models.Elevation, error) {
+	var elevations []*models.Elevation
+	if err := s.db.Where("subject_id = ? AND created_at <= ? AND expires_at > ?", subjectID, at.UTC(), at.UTC()).
+		Order("id").Find(&elevations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find elevations: %w", err)
+	}
+	return elevations, nil
+}
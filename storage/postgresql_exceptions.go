@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateException creates a new access exception
+func (s *PostgreSQLStorage) CreateException(exception *models.AccessException) error {
+	if err := ValidateException(exception, time.Now()); err != nil {
+		return err
+	}
+	// Stored in UTC so expiry comparisons also hold where times are compared as text (SQLite)
+	exception.ExpiresAt = exception.ExpiresAt.UTC()
+	if err := s.db.Create(exception).Error; err != nil {
+		return fmt.Errorf("failed to create access exception: %w", err)
+	}
+	return nil
+}
+
+// GetException retrieves an access exception by ID
+func (s *PostgreSQLStorage) GetException(id string) (*models.AccessException, error) {
+	var exception models.AccessException
+	result := s.db.Where("id = ?", id).First(&exception)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("access exception not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get access exception: %w", result.Error)
+	}
+	return &exception, nil
+}
+
+// DeleteException deletes an access exception
+func (s *PostgreSQLStorage) DeleteException(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.AccessException{}).Error; err != nil {
+		return fmt.Errorf("failed to delete access exception: %w", err)
+	}
+	return nil
+}
+
+// ListExceptions lists the access exceptions of a subject, or of every subject when subjectID is empty
+func (s *PostgreSQLStorage) ListExceptions(subjectID string) ([]*models.AccessException, error) {
+	query := s.db.Order("id")
+	if subjectID != "" {
+		query = query.Where("subject_id = ?", subjectID)
+	}
+	var exceptions []*models.AccessException
+	if err := query.Find(&exceptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list access exceptions: %w", err)
+	}
+	return exceptions, nil
+}
+
+// FindActiveExceptions returns the unexpired exceptions of a subject/resource/action
+func (s *PostgreSQLStorage) FindActiveExceptions(subjectID, resourceID, action string, at time.Time) ([]*models.AccessException, error) {
+	var exceptions []*models.AccessException
+	if err := s.db.Where("subject_id = ? AND resource_id = ? AND action = ? AND expires_at > ?", subjectID, resourceID, action, at.UTC()).
+		Order("id").Find(&exceptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to find access exceptions: %w", err)
+	}
+	return exceptions, nil
+}
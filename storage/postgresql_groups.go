@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateGroup creates a new group
+func (s *PostgreSQLStorage) CreateGroup(group *models.Group) error {
+	if err := s.db.Create(group).Error; err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	return nil
+}
+
+// GetGroup retrieves a group by ID
+func (s *PostgreSQLStorage) GetGroup(id string) (*models.Group, error) {
+	var group models.Group
+	result := s.db.Where("id = ?", id).First(&group)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("group not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get group: %w", result.Error)
+	}
+	return &group, nil
+}
+
+// DeleteGroup deletes a group together with its memberships
+func (s *PostgreSQLStorage) DeleteGroup(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ? OR (member_type = ? AND member_id = ?)", id, models.GroupMemberGroup, id).
+			Delete(&models.GroupMembership{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group memberships: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.Group{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		return nil
+	})
+}
+
+// AddGroupMember adds a subject or nested group to a group
+func (s *PostgreSQLStorage) AddGroupMember(groupID, memberType, memberID string) error {
+	if err := validateGroupMember(s, groupID, memberType, memberID); err != nil {
+		return err
+	}
+	membership := &models.GroupMembership{GroupID: groupID, MemberType: memberType, MemberID: memberID}
+	if err := s.db.Where(membership).FirstOrCreate(membership).Error; err != nil {
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupMember removes a direct member from a group
+func (s *PostgreSQLStorage) RemoveGroupMember(groupID, memberType, memberID string) error {
+	if err := s.db.Where("group_id = ? AND member_type = ? AND member_id = ?", groupID, memberType, memberID).
+		Delete(&models.GroupMembership{}).Error; err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+	return nil
+}
+
+// GetDirectGroups returns the groups a subject or group is a direct member of
+func (s *PostgreSQLStorage) GetDirectGroups(memberType, memberID string) ([]string, error) {
+	var groupIDs []string
+	if err := s.db.Model(&models.GroupMembership{}).
+		Where("member_type = ? AND member_id = ?", memberType, memberID).
+		Order("group_id").
+		Pluck("group_id", &groupIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	return groupIDs, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// ListSubjects returns a page of subjects
+func (s *PostgreSQLStorage) ListSubjects(opts ListOptions) ([]*models.Subject, *PageInfo, error) {
+	var subjects []*models.Subject
+	info, err := s.listPage(&models.Subject{}, &subjects, opts, subjectListSpec)
+	return subjects, info, err
+}
+
+// ListResources returns a page of resources
+func (s *PostgreSQLStorage) ListResources(opts ListOptions) ([]*models.Resource, *PageInfo, error) {
+	var resources []*models.Resource
+	info, err := s.listPage(&models.Resource{}, &resources, opts, resourceListSpec)
+	return resources, info, err
+}
+
+// ListActions returns a page of actions
+func (s *PostgreSQLStorage) ListActions(opts ListOptions) ([]*models.Action, *PageInfo, error) {
+	var actions []*models.Action
+	info, err := s.listPage(&models.Action{}, &actions, opts, actionListSpec)
+	return actions, info, err
+}
+
+// ListPolicies returns a page of policies, including disabled ones unless opts.Enabled is set
+func (s *PostgreSQLStorage) ListPolicies(opts ListOptions) ([]*models.Policy, *PageInfo, error) {
+	var policies []*models.Policy
+	info, err := s.listPage(&models.Policy{}, &policies, opts, policyListSpec)
+	return policies, info, err
+}
+
+// listPage counts the records of model matching opts and loads the requested page into dest
+func (s *PostgreSQLStorage) listPage(model, dest interface{}, opts ListOptions, spec listSpec) (*PageInfo, error) {
+	query, err := opts.resolve(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := applyListFilters(s.db.Model(model), opts, spec)
+
+	var total int64
+	if err := filtered.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count %s: %w", spec.entity, err)
+	}
+
+	if err := filtered.Order(query.orderClause()).Limit(query.limit).Offset(query.offset).Find(dest).Error; err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", spec.entity, err)
+	}
+
+	return query.pageInfo(total, listLength(dest)), nil
+}
+
+// applyListFilters adds the WHERE clauses of opts; columns come from spec, never from user input
+func applyListFilters(db *gorm.DB, opts ListOptions, spec listSpec) *gorm.DB {
+	if opts.Type != "" {
+		db = db.Where(spec.typeColumn+" = ?", opts.Type)
+	}
+	if opts.Enabled != nil {
+		db = db.Where("enabled = ?", *opts.Enabled)
+	}
+	if opts.Namespace != nil {
+		db = db.Where("COALESCE(namespace, '') = ?", *opts.Namespace)
+	}
+	if opts.UpdatedSince != nil {
+		db = db.Where(spec.updatedColumn+" >= ?", opts.UpdatedSince.UTC())
+	}
+	if opts.Owner != "" {
+		db = db.Where("owner = ?", opts.Owner)
+	}
+	if opts.Tag != "" {
+		// resolve has already rejected tags that normalize to nothing
+		tag, _ := normalizeSearchTag(opts.Tag)
+		db = whereHasTag(db, tag)
+	}
+	return db
+}
+
+// whereHasTag keeps rows whose tags column contains tag: jsonb containment (GIN-indexed) on
+// PostgreSQL, json_each on SQLite where tags are stored as JSON text
+func whereHasTag(db *gorm.DB, tag string) *gorm.DB {
+	if db.Dialector.Name() == "sqlite" {
+		return db.Where("EXISTS (SELECT 1 FROM json_each(tags) WHERE json_each.value = ?)", tag)
+	}
+	containment, _ := tagContainment(tag)
+	return db.Where("tags @> ?::jsonb", containment)
+}
+
+// listLength returns the number of records loaded into a List* destination slice
+func listLength(dest interface{}) int {
+	switch records := dest.(type) {
+	case *[]*models.Subject:
+		return len(*records)
+	case *[]*models.Resource:
+		return len(*records)
+	case *[]*models.Action:
+		return len(*records)
+	case *[]*models.Policy:
+		return len(*records)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"fmt"
+
+	"abac_go_example/models"
+)
+
+// RecordPolicyChange appends a policy change to the audit trail
+func (s *PostgreSQLStorage) RecordPolicyChange(change *models.PolicyChange) error {
+	stampPolicyChange(change)
+	if err := s.db.Create(change).Error; err != nil {
+		return fmt.Errorf("failed to record policy change: %w", err)
+	}
+	return nil
+}
+
+// GetPolicyChanges returns up to limit changes of a policy, newest first
+func (s *PostgreSQLStorage) GetPolicyChanges(policyID string, limit int) ([]*models.PolicyChange, error) {
+	var changes []*models.PolicyChange
+	result := s.db.Where("policy_id = ?", policyID).
+		Order("created_at DESC, id DESC").
+		Limit(policyChangeLimit(limit)).
+		Find(&changes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get policy changes: %w", result.Error)
+	}
+	return changes, nil
+}
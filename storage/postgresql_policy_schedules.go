@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// CreatePolicySchedule creates a new policy schedule
+func (s *PostgreSQLStorage) CreatePolicySchedule(schedule *models.PolicySchedule) error {
+	now := time.Now()
+	if err := ValidatePolicySchedule(schedule, now); err != nil {
+		return err
+	}
+	// Stored in UTC so due-time comparisons also hold where times are compared as text (SQLite)
+	schedule.CreatedAt = now.UTC()
+	schedule.Tags = models.NormalizeTags(schedule.Tags)
+	if schedule.RunAt != nil {
+		runAt := schedule.RunAt.UTC()
+		schedule.RunAt = &runAt
+	}
+	if err := s.db.Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to create policy schedule: %w", err)
+	}
+	s.publish(events.Created, events.EntityPolicySchedule, schedule.ID, "")
+	return nil
+}
+
+// GetPolicySchedule retrieves a policy schedule by ID
+func (s *PostgreSQLStorage) GetPolicySchedule(id string) (*models.PolicySchedule, error) {
+	var schedule models.PolicySchedule
+	result := s.db.Where("id = ?", id).First(&schedule)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("policy schedule not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get policy schedule: %w", result.Error)
+	}
+	return &schedule, nil
+}
+
+// DeletePolicySchedule deletes a policy schedule
+func (s *PostgreSQLStorage) DeletePolicySchedule(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.PolicySchedule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete policy schedule: %w", err)
+	}
+	s.publish(events.Deleted, events.EntityPolicySchedule, id, "")
+	return nil
+}
+
+// ListPolicySchedules lists all policy schedules
+func (s *PostgreSQLStorage) ListPolicySchedules() ([]*models.PolicySchedule, error) {
+	var schedules []*models.PolicySchedule
+	if err := s.db.Order("id").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list policy schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// DuePolicySchedules returns the policy schedules due at a point in time
+func (s *PostgreSQLStorage) DuePolicySchedules(at time.Time) ([]*models.PolicySchedule, error) {
+	var schedules []*models.PolicySchedule
+	if err := s.db.Where("next_run_at IS NOT NULL AND next_run_at <= ?", at.UTC()).
+		Order("next_run_at, id").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to find due policy schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// ClaimPolicyScheduleRun claims a due schedule with a conditional update on its next_run_at
+func (s *PostgreSQLStorage) ClaimPolicyScheduleRun(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	result := s.db.Model(&models.PolicySchedule{}).Where("id = ? AND next_run_at = ?", id, dueAt.UTC()).
+		Update("next_run_at", leaseUntil.UTC())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim policy schedule run: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CompletePolicyScheduleRun records a schedule run and its next due time
+func (s *PostgreSQLStorage) CompletePolicyScheduleRun(id string, ranAt time.Time, next *time.Time) error {
+	ranAt = ranAt.UTC()
+	if next != nil {
+		utc := next.UTC()
+		next = &utc
+	}
+	result := s.db.Model(&models.PolicySchedule{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_run_at": ranAt, "next_run_at": next})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete policy schedule run: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("policy schedule not found: %s", id)
+	}
+	s.publish(events.Updated, events.EntityPolicySchedule, id, "")
+	return nil
+}
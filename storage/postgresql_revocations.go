@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateRevocation creates a new revocation
+func (s *PostgreSQLStorage) CreateRevocation(revocation *models.Revocation) error {
+	if err := ValidateRevocation(revocation, time.Now()); err != nil {
+		return err
+	}
+	// Stored in UTC so expiry comparisons also hold where times are compared as text (SQLite)
+	if revocation.ExpiresAt != nil {
+		expiresAt := revocation.ExpiresAt.UTC()
+		revocation.ExpiresAt = &expiresAt
+	}
+	if err := s.db.Create(revocation).Error; err != nil {
+		return fmt.Errorf("failed to create revocation: %w", err)
+	}
+	s.publish(events.Created, events.EntityRevocation, revocation.ID, "")
+	return nil
+}
+
+// GetRevocation retrieves a revocation by ID
+func (s *PostgreSQLStorage) GetRevocation(id string) (*models.Revocation, error) {
+	var revocation models.Revocation
+	result := s.db.Where("id = ?", id).First(&revocation)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("revocation not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get revocation: %w", result.Error)
+	}
+	return &revocation, nil
+}
+
+// DeleteRevocation lifts a revocation
+func (s *PostgreSQLStorage) DeleteRevocation(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.Revocation{}).Error; err != nil {
+		return fmt.Errorf("failed to delete revocation: %w", err)
+	}
+	s.publish(events.Deleted, events.EntityRevocation, id, "")
+	return nil
+}
+
+// ListRevocations lists every revocation by ID
+func (s *PostgreSQLStorage) ListRevocations() ([]*models.Revocation, error) {
+	var revocations []*models.Revocation
+	if err := s.db.Order("id").Find(&revocations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list revocations: %w", err)
+	}
+	return revocations, nil
+}
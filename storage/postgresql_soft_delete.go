@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+
+	"abac_go_example/events"
+	"abac_go_example/models"
+)
+
+// ListDeletedSubjects returns the soft-deleted subjects
+func (s *PostgreSQLStorage) ListDeletedSubjects() ([]*models.Subject, error) {
+	var subjects []*models.Subject
+	if err := s.listDeleted(&subjects); err != nil {
+		return nil, fmt.Errorf("failed to list deleted subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// ListDeletedResources returns the soft-deleted resources
+func (s *PostgreSQLStorage) ListDeletedResources() ([]*models.Resource, error) {
+	var resources []*models.Resource
+	if err := s.listDeleted(&resources); err != nil {
+		return nil, fmt.Errorf("failed to list deleted resources: %w", err)
+	}
+	return resources, nil
+}
+
+// ListDeletedActions returns the soft-deleted actions
+func (s *PostgreSQLStorage) ListDeletedActions() ([]*models.Action, error) {
+	var actions []*models.Action
+	if err := s.listDeleted(&actions); err != nil {
+		return nil, fmt.Errorf("failed to list deleted actions: %w", err)
+	}
+	return actions, nil
+}
+
+// ListDeletedPolicies returns the soft-deleted policies
+func (s *PostgreSQLStorage) ListDeletedPolicies() ([]*models.Policy, error) {
+	var policies []*models.Policy
+	if err := s.listDeleted(&policies); err != nil {
+		return nil, fmt.Errorf("failed to list deleted policies: %w", err)
+	}
+	return policies, nil
+}
+
+// RestoreSubject undeletes a soft-deleted subject
+func (s *PostgreSQLStorage) RestoreSubject(id string) error {
+	if err := s.restore(&models.Subject{}, "subject", id); err != nil {
+		return err
+	}
+	s.publish(events.Restored, events.EntitySubject, id, "")
+	return nil
+}
+
+// RestoreResource undeletes a soft-deleted resource
+func (s *PostgreSQLStorage) RestoreResource(id string) error {
+	if err := s.restore(&models.Resource{}, "resource", id); err != nil {
+		return err
+	}
+	s.publish(events.Restored, events.EntityResource, id, "")
+	return nil
+}
+
+// RestoreAction undeletes a soft-deleted action
+func (s *PostgreSQLStorage) RestoreAction(id string) error {
+	return s.restore(&models.Action{}, "action", id)
+}
+
+// RestorePolicy undeletes a soft-deleted policy; the PDP evaluates it again
+func (s *PostgreSQLStorage) RestorePolicy(id string) error {
+	if err := s.restore(&models.Policy{}, "policy", id); err != nil {
+		return err
+	}
+	s.publish(events.Restored, events.EntityPolicy, id, "")
+	return nil
+}
+
+// listDeleted loads the soft-deleted rows of the table of dest (a pointer to a model slice)
+func (s *PostgreSQLStorage) listDeleted(dest interface{}) error {
+	return s.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id").Find(dest).Error
+}
+
+// restore clears deleted_at of the row id of the table of model
+func (s *PostgreSQLStorage) restore(model interface{}, kind, id string) error {
+	result := s.db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to restore %s: %w", kind, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w %s: %s", ErrNotDeleted, kind, id)
+	}
+	return nil
+}
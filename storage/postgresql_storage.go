@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"abac_go_example/events"
 	"abac_go_example/models"
 
 	"gorm.io/gorm"
@@ -11,8 +12,12 @@ import (
 
 // PostgreSQLStorage implements Storage interface using PostgreSQL with GORM
 type PostgreSQLStorage struct {
-	db             *gorm.DB
-	userRepository *UserRepository
+	db              *gorm.DB
+	userRepository  *UserRepository
+	attributeCipher AttributeCipher             // Set by EnableAttributeEncryption
+	tenantID        string                      // Set on views returned by ForTenant
+	events          EventPublisher              // Set by SetEventPublisher
+	subjectTypes    *models.SubjectTypeRegistry // Set by SetSubjectTypes
 }
 
 // NewPostgreSQLStorage creates a new PostgreSQL storage instance
@@ -31,6 +36,9 @@ func NewPostgreSQLStorage(config *DatabaseConfig) (*PostgreSQLStorage, error) {
 	if err := storage.migrate(); err != nil {
 		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
+	if err := registerTenantCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant isolation: %w", err)
+	}
 
 	return storage, nil
 }
@@ -44,6 +52,14 @@ func (s *PostgreSQLStorage) migrate() error {
 		&models.Action{},
 		&models.Policy{},
 		&models.AuditLog{},
+		&models.AttributeSnapshot{},
+		&models.Group{},
+		&models.GroupMembership{},
+		&models.PolicyChange{},
+		&models.AccessException{},
+		&models.Revocation{},
+		&models.Elevation{},
+		&models.PolicySchedule{},
 		// User-based ABAC models
 		&models.Company{},
 		&models.Department{},
@@ -95,6 +111,19 @@ func (s *PostgreSQLStorage) GetAction(name string) (*models.Action, error) {
 	return &action, nil
 }
 
+// GetPolicy retrieves a policy (enabled or not) by ID
+func (s *PostgreSQLStorage) GetPolicy(id string) (*models.Policy, error) {
+	var policy models.Policy
+	result := s.db.Where("id = ?", id).First(&policy)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrPolicyNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get policy: %w", result.Error)
+	}
+	return &policy, nil
+}
+
 // GetPolicies retrieves all policies
 func (s *PostgreSQLStorage) GetPolicies() ([]*models.Policy, error) {
 	var policies []*models.Policy
@@ -105,6 +134,16 @@ func (s *PostgreSQLStorage) GetPolicies() ([]*models.Policy, error) {
 	return policies, nil
 }
 
+// GetNamespacePolicies retrieves enabled global policies plus those of namespace
+func (s *PostgreSQLStorage) GetNamespacePolicies(namespace string) ([]*models.Policy, error) {
+	var policies []*models.Policy
+	result := s.db.Where("enabled = ? AND (namespace = '' OR namespace IS NULL OR namespace = ?)", true, namespace).Find(&policies)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get policies of namespace %q: %w", namespace, result.Error)
+	}
+	return policies, nil
+}
+
 // GetAllSubjects retrieves all subjects
 func (s *PostgreSQLStorage) GetAllSubjects() ([]*models.Subject, error) {
 	var subjects []*models.Subject
@@ -135,21 +174,96 @@ func (s *PostgreSQLStorage) GetAllActions() ([]*models.Action, error) {
 	return actions, nil
 }
 
+// FindSubjectsByAttribute retrieves subjects whose attribute matches value (uses the attributes GIN index)
+func (s *PostgreSQLStorage) FindSubjectsByAttribute(key string, value interface{}) ([]*models.Subject, error) {
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+	scalar, element, err := attributeContainment(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var subjects []*models.Subject
+	result := s.db.Where("attributes @> ?::jsonb OR attributes @> ?::jsonb", scalar, element).Order("id").Find(&subjects)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find subjects by attribute: %w", result.Error)
+	}
+	return subjects, nil
+}
+
+// FindResourcesByAttribute retrieves resources whose attribute matches value (uses the attributes GIN index)
+func (s *PostgreSQLStorage) FindResourcesByAttribute(key string, value interface{}) ([]*models.Resource, error) {
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+	scalar, element, err := attributeContainment(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*models.Resource
+	result := s.db.Where("attributes @> ?::jsonb OR attributes @> ?::jsonb", scalar, element).Order("id").Find(&resources)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find resources by attribute: %w", result.Error)
+	}
+	return resources, nil
+}
+
+// FindResourcesByTag retrieves resources carrying tag (uses the tags GIN index)
+func (s *PostgreSQLStorage) FindResourcesByTag(tag string) ([]*models.Resource, error) {
+	tag, err := normalizeSearchTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	containment, err := tagContainment(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*models.Resource
+	result := s.db.Where("tags @> ?::jsonb", containment).Order("id").Find(&resources)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find resources by tag: %w", result.Error)
+	}
+	return resources, nil
+}
+
 // CreateSubject creates a new subject
 func (s *PostgreSQLStorage) CreateSubject(subject *models.Subject) error {
-	result := s.db.Create(subject)
-	if result.Error != nil {
-		return fmt.Errorf("failed to create subject: %w", result.Error)
+	if err := validateSubjects(s.subjectTypes, subject); err != nil {
+		return err
+	}
+
+	// Record the attributes in the same transaction so point-in-time history never misses a change
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(subject).Error; err != nil {
+			return err
+		}
+		return recordAttributeSnapshot(tx, newAttributeSnapshot(models.SnapshotEntitySubject, subject.ID, subject.Attributes))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create subject: %w", err)
 	}
+	s.publish(events.Created, events.EntitySubject, subject.ID, subject.TenantID)
 	return nil
 }
 
 // CreateResource creates a new resource
 func (s *PostgreSQLStorage) CreateResource(resource *models.Resource) error {
-	result := s.db.Create(resource)
-	if result.Error != nil {
-		return fmt.Errorf("failed to create resource: %w", result.Error)
+	resource.Tags = models.NormalizeTags(resource.Tags)
+
+	// Record the attributes in the same transaction so point-in-time history never misses a change
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(resource).Error; err != nil {
+			return err
+		}
+		return recordAttributeSnapshot(tx, newAttributeSnapshot(models.SnapshotEntityResource, resource.ID, resource.Attributes))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create resource: %w", err)
 	}
+	s.publish(events.Created, events.EntityResource, resource.ID, resource.TenantID)
 	return nil
 }
 
@@ -164,28 +278,52 @@ func (s *PostgreSQLStorage) CreateAction(action *models.Action) error {
 
 // CreatePolicy creates a new policy
 func (s *PostgreSQLStorage) CreatePolicy(policy *models.Policy) error {
+	policy.Revision = initialPolicyRevision
+	policy.DeletedAt = gorm.DeletedAt{} // Policies are created live; see SoftDeleteStore
+	policy.Tags = models.NormalizeTags(policy.Tags)
 	result := s.db.Create(policy)
 	if result.Error != nil {
 		return fmt.Errorf("failed to create policy: %w", result.Error)
 	}
+	s.publish(events.Created, events.EntityPolicy, policy.ID, policy.TenantID)
 	return nil
 }
 
 // UpdateSubject updates an existing subject
 func (s *PostgreSQLStorage) UpdateSubject(subject *models.Subject) error {
-	result := s.db.Save(subject)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update subject: %w", result.Error)
+	if err := validateSubjects(s.subjectTypes, subject); err != nil {
+		return err
 	}
+
+	// Record the attributes in the same transaction so point-in-time history never misses a change
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(subject).Error; err != nil {
+			return err
+		}
+		return recordAttributeSnapshot(tx, newAttributeSnapshot(models.SnapshotEntitySubject, subject.ID, subject.Attributes))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update subject: %w", err)
+	}
+	s.publish(events.Updated, events.EntitySubject, subject.ID, subject.TenantID)
 	return nil
 }
 
 // UpdateResource updates an existing resource
 func (s *PostgreSQLStorage) UpdateResource(resource *models.Resource) error {
-	result := s.db.Save(resource)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update resource: %w", result.Error)
+	resource.Tags = models.NormalizeTags(resource.Tags)
+
+	// Record the attributes in the same transaction so point-in-time history never misses a change
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(resource).Error; err != nil {
+			return err
+		}
+		return recordAttributeSnapshot(tx, newAttributeSnapshot(models.SnapshotEntityResource, resource.ID, resource.Attributes))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update resource: %w", err)
 	}
+	s.publish(events.Updated, events.EntityResource, resource.ID, resource.TenantID)
 	return nil
 }
 
@@ -199,11 +337,29 @@ func (s *PostgreSQLStorage) UpdateAction(action *models.Action) error {
 }
 
 // UpdatePolicy updates an existing policy
+// It succeeds only when policy.Revision matches the stored revision and then increments policy.Revision;
+// a stale revision returns ErrRevisionConflict so concurrent edits are never silently overwritten.
 func (s *PostgreSQLStorage) UpdatePolicy(policy *models.Policy) error {
-	result := s.db.Save(policy)
+	expected := policy.Revision
+	policy.Revision = expected + 1
+	policy.DeletedAt = gorm.DeletedAt{}
+	policy.Tags = models.NormalizeTags(policy.Tags)
+
+	// Conditional UPDATE ... WHERE id = ? AND revision = ? is atomic, no row lock needed
+	result := s.db.Model(policy).Where("revision = ?", expected).Select("*").Omit("created_at").Updates(policy)
 	if result.Error != nil {
+		policy.Revision = expected
 		return fmt.Errorf("failed to update policy: %w", result.Error)
 	}
+	if result.RowsAffected == 0 {
+		policy.Revision = expected
+		current, err := s.GetPolicy(policy.ID)
+		if err != nil {
+			return err
+		}
+		return revisionConflict(policy.ID, expected, current.Revision)
+	}
+	s.publish(events.Updated, events.EntityPolicy, policy.ID, policy.TenantID)
 	return nil
 }
 
@@ -213,6 +369,9 @@ func (s *PostgreSQLStorage) DeleteSubject(id string) error {
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete subject: %w", result.Error)
 	}
+	if result.RowsAffected > 0 {
+		s.publish(events.Deleted, events.EntitySubject, id, "")
+	}
 	return nil
 }
 
@@ -222,6 +381,9 @@ func (s *PostgreSQLStorage) DeleteResource(id string) error {
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete resource: %w", result.Error)
 	}
+	if result.RowsAffected > 0 {
+		s.publish(events.Deleted, events.EntityResource, id, "")
+	}
 	return nil
 }
 
@@ -240,6 +402,9 @@ func (s *PostgreSQLStorage) DeletePolicy(id string) error {
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete policy: %w", result.Error)
 	}
+	if result.RowsAffected > 0 {
+		s.publish(events.Deleted, events.EntityPolicy, id, "")
+	}
 	return nil
 }
 
@@ -264,6 +429,10 @@ func (s *PostgreSQLStorage) GetAuditLogs(limit, offset int) ([]*models.AuditLog,
 
 // Close closes the database connection
 func (s *PostgreSQLStorage) Close() error {
+	// Tenant views share the connection of the storage they were created from
+	if s.tenantID != "" {
+		return nil
+	}
 	sqlDB, err := s.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"abac_go_example/models"
+)
+
+// ErrInvalidTag is returned by tag search for empty tags
+var ErrInvalidTag = errors.New("invalid resource tag")
+
+// normalizeSearchTag normalizes a searched tag like stored tags ("pii = true" -> "pii=true")
+func normalizeSearchTag(tag string) (string, error) {
+	normalized := models.NormalizeTags([]string{tag})
+	if len(normalized) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+	}
+	return normalized[0], nil
+}
+
+// tagContainment returns the jsonb document matched by FindResourcesByTag
+func tagContainment(tag string) (string, error) {
+	data, err := json.Marshal([]string{tag})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"abac_go_example/models"
+)
+
+// ErrInvalidRevocation is returned when creating a revocation without exactly one of subject or
+// session, without a reason, or with an expiry that is not in the future
+var ErrInvalidRevocation = errors.New("invalid revocation")
+
+// RevocationStore is implemented by storages that keep the revocation list: subjects and sessions
+// whose access the PDP suspends before policy evaluation. Writes publish events.EntityRevocation.
+type RevocationStore interface {
+	// CreateRevocation validates and stores a revocation (CreatedAt defaults to now)
+	CreateRevocation(revocation *models.Revocation) error
+	GetRevocation(id string) (*models.Revocation, error)
+	// DeleteRevocation lifts a revocation
+	DeleteRevocation(id string) error
+	// ListRevocations returns every revocation, expired ones included, by ID
+	ListRevocations() ([]*models.Revocation, error)
+}
+
+// ValidateRevocation checks that a revocation names exactly one subject or session, carries a
+// reason and, when it expires, expires after now
+func ValidateRevocation(revocation *models.Revocation, now time.Time) error {
+	switch {
+	case revocation.ID == "":
+		return fmt.Errorf("%w: id is required", ErrInvalidRevocation)
+	case (revocation.SubjectID == "") == (revocation.SessionID == ""):
+		return fmt.Errorf("%w: exactly one of subject_id and session_id is required", ErrInvalidRevocation)
+	case strings.TrimSpace(revocation.Reason) == "":
+		return fmt.Errorf("%w: reason is required", ErrInvalidRevocation)
+	case !revocation.ActiveAt(now):
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidRevocation)
+	}
+	return nil
+}
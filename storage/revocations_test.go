@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestRevocationStore(t *testing.T) {
+	stores := map[string]interface {
+		RevocationStore
+		EventStore
+	}{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			store.SetEventPublisher(publisher)
+
+			expiresAt := time.Now().Add(time.Hour).In(time.FixedZone("ICT", 7*3600))
+			for _, revocation := range []*models.Revocation{
+				{ID: "rev-2", SessionID: "sess-1", Reason: "Stolen token", CreatedBy: "secops", ExpiresAt: &expiresAt},
+				{ID: "rev-1", SubjectID: "sub-1", Reason: "Compromised account", CreatedBy: "secops"},
+			} {
+				if err := store.CreateRevocation(revocation); err != nil {
+					t.Fatalf("CreateRevocation(%s) failed: %v", revocation.ID, err)
+				}
+			}
+
+			listed, err := store.ListRevocations()
+			if err != nil || len(listed) != 2 || listed[0].ID != "rev-1" || listed[1].ID != "rev-2" {
+				t.Fatalf("Expected rev-1 and rev-2 by ID, got %v (%v)", listed, err)
+			}
+			if listed[0].ExpiresAt != nil || listed[1].ExpiresAt == nil || !listed[1].ExpiresAt.Equal(expiresAt) {
+				t.Errorf("Unexpected expiries %v and %v", listed[0].ExpiresAt, listed[1].ExpiresAt)
+			}
+			revocation, err := store.GetRevocation("rev-2")
+			if err != nil || revocation.SessionID != "sess-1" || revocation.Reason != "Stolen token" {
+				t.Errorf("Unexpected revocation %v (%v)", revocation, err)
+			}
+
+			if err := store.DeleteRevocation("rev-2"); err != nil {
+				t.Fatalf("DeleteRevocation failed: %v", err)
+			}
+			if _, err := store.GetRevocation("rev-2"); err == nil {
+				t.Error("Expected the lifted revocation to be gone")
+			}
+			if want := "deleted revocation rev-2 "; len(*publisher) != 3 || (*publisher)[2] != want {
+				t.Errorf("Expected two created and one deleted revocation events, got %v", *publisher)
+			}
+
+			past := time.Now().Add(-time.Minute)
+			invalid := []*models.Revocation{
+				{ID: "bad-1", Reason: "Neither subject nor session"},
+				{ID: "bad-2", SubjectID: "sub-1", SessionID: "sess-1", Reason: "Both"},
+				{ID: "bad-3", SubjectID: "sub-1", Reason: " "},
+				{ID: "bad-4", SubjectID: "sub-1", Reason: "Already expired", ExpiresAt: &past},
+			}
+			for _, revocation := range invalid {
+				if err := store.CreateRevocation(revocation); !errors.Is(err, ErrInvalidRevocation) {
+					t.Errorf("%s: expected ErrInvalidRevocation, got %v", revocation.ID, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"errors"
+
+	"abac_go_example/models"
+)
+
+// ErrNotDeleted is returned when restoring an entity that does not exist or is not soft-deleted
+var ErrNotDeleted = errors.New("no soft-deleted entity")
+
+// SoftDeleteStore is implemented by storages whose deletes of subjects, resources, actions and
+// policies only set DeletedAt. Soft-deleted entities are invisible to every other method (the
+// PDP never evaluates a soft-deleted policy) and still hold their ID and unique names, so they
+// must be restored rather than re-created.
+type SoftDeleteStore interface {
+	// ListDeletedX return the soft-deleted entities, most recently deleted first
+	ListDeletedSubjects() ([]*models.Subject, error)
+	ListDeletedResources() ([]*models.Resource, error)
+	ListDeletedActions() ([]*models.Action, error)
+	ListDeletedPolicies() ([]*models.Policy, error)
+
+	// RestoreX clears DeletedAt, or returns ErrNotDeleted
+	RestoreSubject(id string) error
+	RestoreResource(id string) error
+	RestoreAction(id string) error
+	RestorePolicy(id string) error
+}
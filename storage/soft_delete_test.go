@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestSoftDelete(t *testing.T) {
+	type softDeleteStorage interface {
+		Storage
+		SoftDeleteStore
+	}
+	stores := map[string]softDeleteStorage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := store.CreateSubject(&models.Subject{ID: "sub-001", SubjectType: "user"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.CreateResource(&models.Resource{ID: "res-001", ResourceType: "document"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.CreateAction(&models.Action{ID: "act-001", ActionName: "document:read"}); err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range []string{"pol-001", "pol-002"} {
+				if err := store.CreatePolicy(&models.Policy{ID: id, PolicyName: id, Version: "1", Enabled: true}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := errors.Join(store.DeleteSubject("sub-001"), store.DeleteResource("res-001"),
+				store.DeleteAction("act-001"), store.DeletePolicy("pol-001")); err != nil {
+				t.Fatal(err)
+			}
+
+			// Soft-deleted entities are invisible to regular reads
+			if _, err := store.GetSubject("sub-001"); err == nil {
+				t.Error("expected the deleted subject to be hidden")
+			}
+			if _, err := store.GetResource("res-001"); err == nil {
+				t.Error("expected the deleted resource to be hidden")
+			}
+			if _, err := store.GetAction("document:read"); err == nil {
+				t.Error("expected the deleted action to be hidden")
+			}
+			if _, err := store.GetPolicy("pol-001"); !errors.Is(err, ErrPolicyNotFound) {
+				t.Errorf("expected ErrPolicyNotFound, got %v", err)
+			}
+			if policies, _ := store.GetPolicies(); len(policies) != 1 || policies[0].ID != "pol-002" {
+				t.Errorf("expected only pol-002 to be evaluated, got %v", policies)
+			}
+			if err := store.CreatePolicy(&models.Policy{ID: "pol-001", PolicyName: "pol-001-again", Version: "1"}); err == nil {
+				t.Error("expected re-creating a soft-deleted policy ID to fail")
+			}
+
+			subjects, err := store.ListDeletedSubjects()
+			if err != nil || len(subjects) != 1 || subjects[0].ID != "sub-001" || !subjects[0].DeletedAt.Valid {
+				t.Errorf("expected sub-001 with DeletedAt, got %v (%v)", subjects, err)
+			}
+			if resources, _ := store.ListDeletedResources(); len(resources) != 1 {
+				t.Errorf("expected 1 deleted resource, got %d", len(resources))
+			}
+			if actions, _ := store.ListDeletedActions(); len(actions) != 1 {
+				t.Errorf("expected 1 deleted action, got %d", len(actions))
+			}
+			policies, err := store.ListDeletedPolicies()
+			if err != nil || len(policies) != 1 || policies[0].ID != "pol-001" {
+				t.Errorf("expected pol-001, got %v (%v)", policies, err)
+			}
+
+			if err := errors.Join(store.RestoreSubject("sub-001"), store.RestoreResource("res-001"),
+				store.RestoreAction("act-001"), store.RestorePolicy("pol-001")); err != nil {
+				t.Fatal(err)
+			}
+			if subject, err := store.GetSubject("sub-001"); err != nil || subject.DeletedAt.Valid {
+				t.Errorf("expected the restored subject to be live, got %+v (%v)", subject, err)
+			}
+			if _, err := store.GetAction("document:read"); err != nil {
+				t.Errorf("expected the restored action to be readable: %v", err)
+			}
+			if policies, _ := store.GetPolicies(); len(policies) != 2 {
+				t.Errorf("expected both policies to be evaluated after restore, got %d", len(policies))
+			}
+			if policies, _ := store.ListDeletedPolicies(); len(policies) != 0 {
+				t.Errorf("expected no deleted policies, got %v", policies)
+			}
+
+			if err := store.RestorePolicy("pol-002"); !errors.Is(err, ErrNotDeleted) {
+				t.Errorf("expected ErrNotDeleted for a live policy, got %v", err)
+			}
+			if err := store.RestoreSubject("sub-missing"); !errors.Is(err, ErrNotDeleted) {
+				t.Errorf("expected ErrNotDeleted for an unknown subject, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abac_go_example/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// SQLiteMemoryPath opens a private in-memory database
+const SQLiteMemoryPath = ":memory:"
+
+// SQLiteConfig holds SQLite connection configuration
+type SQLiteConfig struct {
+	Path        string // Database file path or SQLiteMemoryPath
+	BusyTimeout time.Duration
+}
+
+// DefaultSQLiteConfig returns a default SQLite configuration
+func DefaultSQLiteConfig() *SQLiteConfig {
+	return &SQLiteConfig{
+		Path:        getEnv("SQLITE_PATH", "abac.db"),
+		BusyTimeout: time.Duration(getEnvAsInt("SQLITE_BUSY_TIMEOUT_MS", 5000)) * time.Millisecond,
+	}
+}
+
+// DSN returns the SQLite connection string with foreign keys enforced like PostgreSQL
+func (c *SQLiteConfig) DSN() string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=%d", c.Path, c.BusyTimeout.Milliseconds())
+}
+
+// NewSQLiteConnection creates a new SQLite database connection
+func NewSQLiteConnection(config *SQLiteConfig) (*gorm.DB, error) {
+	if config == nil {
+		config = DefaultSQLiteConfig()
+	}
+
+	db, err := gorm.Open(sqlite.Open(config.DSN()), newGormConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	// SQLite allows a single writer; an in-memory database also only exists on its own connection
+	sqlDB.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// SQLiteStorage implements Storage using SQLite for embedded deployments and integration tests.
+// It runs the same GORM queries and schema as PostgreSQLStorage; jsonb columns are stored as JSON text.
+type SQLiteStorage struct {
+	*PostgreSQLStorage
+}
+
+// NewSQLiteStorage creates a new SQLite storage instance
+func NewSQLiteStorage(config *SQLiteConfig) (*SQLiteStorage, error) {
+	db, err := NewSQLiteConnection(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database connection: %w", err)
+	}
+
+	storage := &SQLiteStorage{
+		PostgreSQLStorage: &PostgreSQLStorage{
+			db:             db,
+			userRepository: NewUserRepository(db),
+		},
+	}
+
+	// Auto-migrate the schema
+	if err := storage.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
+	}
+	if err := registerTenantCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant isolation: %w", err)
+	}
+
+	return storage, nil
+}
+
+// ForTenant scopes the storage to tenantID, keeping the SQLite-specific queries (see TenantStore)
+func (s *SQLiteStorage) ForTenant(tenantID string) (Storage, error) {
+	scoped, err := s.forTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteStorage{PostgreSQLStorage: scoped}, nil
+}
+
+// attributePath returns the SQLite JSON path of a top-level attribute
+func attributePath(key string) string {
+	return fmt.Sprintf(`$."%s"`, key)
+}
+
+// FindSubjectsByAttribute retrieves subjects whose attribute matches value.
+// SQLite has no jsonb containment, so rows having the attribute are filtered in Go.
+func (s *SQLiteStorage) FindSubjectsByAttribute(key string, value interface{}) ([]*models.Subject, error) {
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+
+	var candidates []*models.Subject
+	result := s.db.Where("json_extract(attributes, ?) IS NOT NULL", attributePath(key)).Order("id").Find(&candidates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find subjects by attribute: %w", result.Error)
+	}
+
+	subjects := make([]*models.Subject, 0, len(candidates))
+	for _, subject := range candidates {
+		if attributeMatches(subject.Attributes, key, value) {
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects, nil
+}
+
+// FindResourcesByAttribute retrieves resources whose attribute matches value
+func (s *SQLiteStorage) FindResourcesByAttribute(key string, value interface{}) ([]*models.Resource, error) {
+	if err := validateAttributeKey(key); err != nil {
+		return nil, err
+	}
+
+	var candidates []*models.Resource
+	result := s.db.Where("json_extract(attributes, ?) IS NOT NULL", attributePath(key)).Order("id").Find(&candidates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find resources by attribute: %w", result.Error)
+	}
+
+	resources := make([]*models.Resource, 0, len(candidates))
+	for _, resource := range candidates {
+		if attributeMatches(resource.Attributes, key, value) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// FindResourcesByTag retrieves resources carrying tag
+func (s *SQLiteStorage) FindResourcesByTag(tag string) ([]*models.Resource, error) {
+	tag, err := normalizeSearchTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*models.Resource
+	result := s.db.Where("EXISTS (SELECT 1 FROM json_each(resources.tags) WHERE json_each.value = ?)", tag).Order("id").Find(&resources)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find resources by tag: %w", result.Error)
+	}
+	return resources, nil
+}
+
+// EnsureAuditPartitions is a no-op: SQLite has no table partitioning
+func (s *SQLiteStorage) EnsureAuditPartitions(ctx context.Context, from time.Time, months int) error {
+	return nil
+}
+
+// ListAuditPartitions returns no partitions; retention falls back to DeleteAuditLogsBefore
+func (s *SQLiteStorage) ListAuditPartitions(ctx context.Context) ([]AuditPartition, error) {
+	return []AuditPartition{}, nil
+}
+
+// DropAuditPartition is not supported on SQLite
+func (s *SQLiteStorage) DropAuditPartition(ctx context.Context, name string) error {
+	return fmt.Errorf("audit partitions are not supported on sqlite")
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"abac_go_example/clock"
+	"abac_go_example/models"
+)
+
+func TestSQLiteStorage_Entities(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	SeedTestData(t, sqliteStorage.PostgreSQLStorage)
+
+	subject, err := sqliteStorage.GetSubject("sub-001")
+	if err != nil {
+		t.Fatalf("GetSubject failed: %v", err)
+	}
+	if subject.Attributes["department"] != "engineering" || subject.Attributes["clearance_level"] != float64(3) {
+		t.Errorf("Unexpected subject attributes: %v", subject.Attributes)
+	}
+
+	action, err := sqliteStorage.GetAction("write")
+	if err != nil || action.ID != "act-002" {
+		t.Fatalf("GetAction failed: %v (%+v)", err, action)
+	}
+
+	policies, err := sqliteStorage.GetPolicies()
+	if err != nil {
+		t.Fatalf("GetPolicies failed: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("Expected 2 policies, got %d", len(policies))
+	}
+	for _, policy := range policies {
+		if policy.ID != "pol-004" {
+			continue
+		}
+		statement := policy.Statement[0]
+		if len(statement.Action.Multiple) != 2 || statement.Condition["Bool"] == nil {
+			t.Errorf("Policy statement did not round-trip: %+v", statement)
+		}
+	}
+
+	// Disabled policies are filtered like on PostgreSQL
+	policies[0].Enabled = false
+	if err := sqliteStorage.UpdatePolicy(policies[0]); err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+	if policies, _ = sqliteStorage.GetPolicies(); len(policies) != 1 {
+		t.Errorf("Expected 1 enabled policy, got %d", len(policies))
+	}
+
+	if err := sqliteStorage.DeleteSubject("sub-001"); err != nil {
+		t.Fatalf("DeleteSubject failed: %v", err)
+	}
+	if _, err := sqliteStorage.GetSubject("sub-001"); err == nil {
+		t.Error("Expected error for deleted subject")
+	}
+}
+
+func TestSQLiteStorage_Users(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	db := sqliteStorage.db
+
+	records := []interface{}{
+		&models.Company{ID: "comp-1", CompanyCode: "ACME", CompanyName: "Acme"},
+		&models.Department{ID: "dept-1", CompanyID: "comp-1", DepartmentCode: "ENG", DepartmentName: "Engineering"},
+		&models.Position{ID: "pos-1", PositionCode: "SE", PositionName: "Engineer", PositionLevel: 3},
+		&models.Role{ID: "role-1", RoleCode: "developer", RoleName: "Developer"},
+	}
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create %T: %v", record, err)
+		}
+	}
+
+	user := &models.User{ID: "user-1", Username: "jdoe", Email: "jdoe@example.com", FullName: "John Doe", Status: "active", EmployeeID: "E1"}
+	if err := sqliteStorage.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	profile := &models.UserProfile{
+		ID: "prof-1", UserID: "user-1", CompanyID: "comp-1", DepartmentID: "dept-1", PositionID: "pos-1",
+		AccessLevel: 2, SecurityClearance: "secret", Attributes: models.JSONMap{"team": "platform"},
+	}
+	if err := sqliteStorage.CreateUserProfile(profile); err != nil {
+		t.Fatalf("CreateUserProfile failed: %v", err)
+	}
+	if err := sqliteStorage.AssignRole("user-1", "role-1", ""); err != nil {
+		t.Fatalf("AssignRole failed: %v", err)
+	}
+
+	// Foreign keys are enforced like on PostgreSQL
+	orphan := &models.UserProfile{ID: "prof-2", UserID: "user-1", CompanyID: "missing", DepartmentID: "dept-1", PositionID: "pos-1"}
+	if err := sqliteStorage.CreateUserProfile(orphan); err == nil {
+		t.Error("Expected foreign key violation for unknown company")
+	}
+
+	subject, err := sqliteStorage.BuildSubjectFromUser("user-1")
+	if err != nil {
+		t.Fatalf("BuildSubjectFromUser failed: %v", err)
+	}
+	attributes := subject.GetAttributes()
+	if attributes["department_code"] != "ENG" || attributes["clearance"] != "secret" {
+		t.Errorf("Unexpected subject attributes: %v", attributes)
+	}
+
+	roles, err := sqliteStorage.GetUserRoles("user-1")
+	if err != nil || len(roles) != 1 || roles[0].RoleCode != "developer" {
+		t.Errorf("Unexpected roles: %v (%v)", roles, err)
+	}
+}
+
+func TestSQLiteStorage_AuditRetention(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+
+	for _, createdAt := range []time.Time{
+		time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC),
+	} {
+		auditLog := &models.AuditLog{RequestID: "req", SubjectID: "s", ResourceID: "r", ActionID: "a", Decision: "permit", CreatedAt: createdAt}
+		if err := sqliteStorage.LogAudit(auditLog); err != nil {
+			t.Fatalf("LogAudit failed: %v", err)
+		}
+	}
+
+	// Without partitions the retention job deletes expired rows directly
+	job := NewAuditRetentionJob(sqliteStorage, nil, &AuditRetentionConfig{RetentionDays: 30})
+	job.SetClock(clock.NewMockClock(time.Date(2024, time.June, 20, 0, 0, 0, 0, time.UTC)))
+	result, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if result.DeletedLogs != 1 || len(result.DroppedPartitions) != 0 {
+		t.Errorf("Unexpected retention result: %+v", result)
+	}
+
+	remaining, err := sqliteStorage.GetAuditLogs(10, 0)
+	if err != nil || len(remaining) != 1 {
+		t.Errorf("Expected 1 remaining audit log, got %d (%v)", len(remaining), err)
+	}
+}
+
+func TestSQLiteStorage_AttributeHistory(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	before := time.Now().UTC().Add(-time.Minute)
+
+	subject := &models.Subject{ID: "sub-history", ExternalID: "ext-history", SubjectType: "user", Attributes: models.JSONMap{"department": "engineering"}}
+	if err := sqliteStorage.CreateSubject(subject); err != nil {
+		t.Fatalf("CreateSubject failed: %v", err)
+	}
+	subject.Attributes = models.JSONMap{"department": "finance"}
+	if err := sqliteStorage.UpdateSubject(subject); err != nil {
+		t.Fatalf("UpdateSubject failed: %v", err)
+	}
+
+	snapshot, err := sqliteStorage.GetAttributeSnapshot(models.SnapshotEntitySubject, "sub-history", time.Now().UTC().Add(time.Minute))
+	if err != nil || snapshot == nil || snapshot.Attributes["department"] != "finance" {
+		t.Fatalf("Expected latest snapshot to hold the update, got %+v (err=%v)", snapshot, err)
+	}
+
+	if snapshot, err := sqliteStorage.GetAttributeSnapshot(models.SnapshotEntitySubject, "sub-history", before); err != nil || snapshot != nil {
+		t.Errorf("Expected no snapshot before creation, got %+v (err=%v)", snapshot, err)
+	}
+
+	backfilled := &models.AttributeSnapshot{
+		EntityType: models.SnapshotEntitySubject,
+		EntityID:   "sub-history",
+		Attributes: models.JSONMap{"department": "sales"},
+		ValidFrom:  before.Add(-time.Hour),
+	}
+	if err := sqliteStorage.RecordAttributeSnapshot(backfilled); err != nil {
+		t.Fatalf("RecordAttributeSnapshot failed: %v", err)
+	}
+	snapshot, err = sqliteStorage.GetAttributeSnapshot(models.SnapshotEntitySubject, "sub-history", before)
+	if err != nil || snapshot == nil || snapshot.Attributes["department"] != "sales" {
+		t.Errorf("Expected backfilled snapshot, got %+v (err=%v)", snapshot, err)
+	}
+}
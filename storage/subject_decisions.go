@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"abac_go_example/constants"
+	"abac_go_example/models"
+)
+
+// Limits of a subject decision history query
+const (
+	DefaultSubjectDecisionLimit = 100
+	MaxSubjectDecisionLimit     = 1000
+)
+
+// SubjectDecisionStore is implemented by storages that can read a subject's decision history from
+// the audit logs, e.g. for support when a user reports "I can't access X"
+type SubjectDecisionStore interface {
+	// SubjectDecisions returns up to limit permit/deny PDP decisions (source AuditSourceEvaluation)
+	// of subjectID created at or after since, newest first
+	SubjectDecisions(subjectID string, since time.Time, limit int) ([]*models.AuditLog, error)
+}
+
+// DeniedResource aggregates a subject's denies on one resource
+type DeniedResource struct {
+	ResourceID   string    `json:"resource_id"`
+	Denies       int       `json:"denies"`
+	Actions      []string  `json:"actions"` // Denied actions, sorted
+	LastDeniedAt time.Time `json:"last_denied_at"`
+}
+
+// SubjectDecisionSummary aggregates a subject's decision history
+type SubjectDecisionSummary struct {
+	Total              int              `json:"total"`
+	Permits            int              `json:"permits"`
+	Denies             int              `json:"denies"`
+	DenyRate           float64          `json:"deny_rate"` // Denies / Total, 0 without decisions
+	TopDeniedResources []DeniedResource `json:"top_denied_resources"`
+}
+
+// isDecisionLog reports whether an audit entry records a PDP permit or deny on a resource, as opposed
+// to an administrative action
+func isDecisionLog(auditLog *models.AuditLog) bool {
+	if auditLog.Source != models.AuditSourceEvaluation || auditLog.ResourceID == "" || auditLog.ActionID == "" {
+		return false
+	}
+	return auditLog.Decision == constants.ResultPermit || auditLog.Decision == constants.ResultDeny
+}
+
+// SummarizeSubjectDecisions counts decisions and ranks the top most denied resources, by denies
+// then most recent deny then ID
+func SummarizeSubjectDecisions(decisions []*models.AuditLog, top int) *SubjectDecisionSummary {
+	summary := &SubjectDecisionSummary{TopDeniedResources: []DeniedResource{}}
+	denied := map[string]*DeniedResource{}
+	deniedActions := map[string]map[string]bool{}
+	for _, decision := range decisions {
+		summary.Total++
+		if decision.Decision != constants.ResultDeny {
+			summary.Permits++
+			continue
+		}
+		summary.Denies++
+
+		resource, ok := denied[decision.ResourceID]
+		if !ok {
+			resource = &DeniedResource{ResourceID: decision.ResourceID}
+			denied[decision.ResourceID] = resource
+			deniedActions[decision.ResourceID] = map[string]bool{}
+		}
+		resource.Denies++
+		if decision.CreatedAt.After(resource.LastDeniedAt) {
+			resource.LastDeniedAt = decision.CreatedAt
+		}
+		if !deniedActions[decision.ResourceID][decision.ActionID] {
+			deniedActions[decision.ResourceID][decision.ActionID] = true
+			resource.Actions = append(resource.Actions, decision.ActionID)
+		}
+	}
+	if summary.Total > 0 {
+		summary.DenyRate = float64(summary.Denies) / float64(summary.Total)
+	}
+
+	for _, resource := range denied {
+		sort.Strings(resource.Actions)
+		summary.TopDeniedResources = append(summary.TopDeniedResources, *resource)
+	}
+	sort.Slice(summary.TopDeniedResources, func(i, j int) bool {
+		a, b := summary.TopDeniedResources[i], summary.TopDeniedResources[j]
+		if a.Denies != b.Denies {
+			return a.Denies > b.Denies
+		}
+		if !a.LastDeniedAt.Equal(b.LastDeniedAt) {
+			return a.LastDeniedAt.After(b.LastDeniedAt)
+		}
+		return a.ResourceID < b.ResourceID
+	})
+	if top >= 0 && len(summary.TopDeniedResources) > top {
+		summary.TopDeniedResources = summary.TopDeniedResources[:top]
+	}
+	return summary
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestSubjectDecisions(t *testing.T) {
+	stores := map[string]interface {
+		Storage
+		SubjectDecisionStore
+	}{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			since := time.Now().Add(-time.Minute)
+			for i, entry := range []models.AuditLog{
+				{SubjectID: "user-1", ResourceID: "doc-1", ActionID: "document:read", Decision: "permit", Source: models.AuditSourceEvaluation},
+				{SubjectID: "user-1", ResourceID: "doc-2", ActionID: "document:read", Decision: "deny", Source: models.AuditSourceEvaluation},
+				{SubjectID: "user-2", ResourceID: "doc-2", ActionID: "document:read", Decision: "deny", Source: models.AuditSourceEvaluation},
+				{SubjectID: "user-1", ResourceID: "elev-1", ActionID: "elevation:create", Decision: "permit"}, // Administrative action by user-1
+				{SubjectID: "user-1", ResourceID: "doc-2", ActionID: "document:write", Decision: "deny", Source: models.AuditSourceEvaluation},
+			} {
+				entry.RequestID = fmt.Sprintf("req-%d", i)
+				if err := store.LogAudit(&entry); err != nil {
+					t.Fatalf("LogAudit failed: %v", err)
+				}
+			}
+
+			decisions, err := store.SubjectDecisions("user-1", since, 10)
+			if err != nil || len(decisions) != 3 {
+				t.Fatalf("Expected 3 decisions of user-1, got %v (%v)", decisions, err)
+			}
+			if decisions[0].RequestID != "req-4" || decisions[2].RequestID != "req-0" {
+				t.Errorf("Expected newest first, got %s..%s", decisions[0].RequestID, decisions[2].RequestID)
+			}
+			if limited, _ := store.SubjectDecisions("user-1", since, 1); len(limited) != 1 || limited[0].RequestID != "req-4" {
+				t.Errorf("Expected only the newest decision, got %v", limited)
+			}
+			if later, _ := store.SubjectDecisions("user-1", time.Now().Add(time.Minute), 10); len(later) != 0 {
+				t.Errorf("Expected no decisions after since, got %v", later)
+			}
+		})
+	}
+}
+
+func TestSummarizeSubjectDecisions(t *testing.T) {
+	now := time.Now()
+	decisions := []*models.AuditLog{
+		{ResourceID: "doc-1", ActionID: "document:read", Decision: "permit", CreatedAt: now},
+		{ResourceID: "doc-2", ActionID: "document:write", Decision: "deny", CreatedAt: now},
+		{ResourceID: "doc-3", ActionID: "document:read", Decision: "deny", CreatedAt: now.Add(-time.Hour)},
+		{ResourceID: "doc-2", ActionID: "document:read", Decision: "deny", CreatedAt: now.Add(-time.Hour)},
+		{ResourceID: "doc-4", ActionID: "document:read", Decision: "deny", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	summary := SummarizeSubjectDecisions(decisions, 2)
+	if summary.Total != 5 || summary.Permits != 1 || summary.Denies != 4 || summary.DenyRate != 0.8 {
+		t.Errorf("Unexpected counts %+v", summary)
+	}
+	top := summary.TopDeniedResources
+	if len(top) != 2 || top[0].ResourceID != "doc-2" || top[1].ResourceID != "doc-3" {
+		t.Fatalf("Expected doc-2 then doc-3 (more recent than doc-4), got %+v", top)
+	}
+	if top[0].Denies != 2 || !top[0].LastDeniedAt.Equal(now) || len(top[0].Actions) != 2 || top[0].Actions[0] != "document:read" {
+		t.Errorf("Unexpected doc-2 aggregation %+v", top[0])
+	}
+
+	if empty := SummarizeSubjectDecisions(nil, 5); empty.Total != 0 || empty.DenyRate != 0 || len(empty.TopDeniedResources) != 0 {
+		t.Errorf("Unexpected empty summary %+v", empty)
+	}
+}
@@ -0,0 +1,35 @@
+package storage
+
+import "abac_go_example/models"
+
+// SubjectTypeStore is implemented by storages that enforce a subject type registry
+type SubjectTypeStore interface {
+	// SetSubjectTypes rejects subject creates, updates and bulk creates whose type is not
+	// registered or lacks a required attribute; nil accepts any subject type
+	SetSubjectTypes(registry *models.SubjectTypeRegistry)
+}
+
+// SetSubjectTypes enforces registry on the subject writes of this storage and of tenant views created afterwards
+func (s *PostgreSQLStorage) SetSubjectTypes(registry *models.SubjectTypeRegistry) {
+	s.subjectTypes = registry
+}
+
+// SetSubjectTypes enforces registry on the subject writes of the mock storage
+func (m *MockStorage) SetSubjectTypes(registry *models.SubjectTypeRegistry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subjectTypes = registry
+}
+
+// validateSubjects checks subjects against registry, if any
+func validateSubjects(registry *models.SubjectTypeRegistry, subjects ...*models.Subject) error {
+	if registry == nil {
+		return nil
+	}
+	for _, subject := range subjects {
+		if err := registry.ValidateSubject(subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
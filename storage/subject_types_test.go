@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"abac_go_example/models"
+)
+
+func TestSubjectTypeStore(t *testing.T) {
+	type subjectTypeStorage interface {
+		Storage
+		SubjectTypeStore
+	}
+	stores := map[string]subjectTypeStorage{"mock": NewMockStorage(), "sqlite": NewSQLiteTestStorage(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			// Any type is accepted until a registry is set
+			if err := store.CreateSubject(&models.Subject{ID: "legacy-1", SubjectType: "employee"}); err != nil {
+				t.Fatalf("CreateSubject failed: %v", err)
+			}
+
+			store.SetSubjectTypes(models.DefaultSubjectTypeRegistry())
+			if err := store.CreateSubject(&models.Subject{ID: "sub-1", SubjectType: "robot"}); !errors.Is(err, models.ErrUnknownSubjectType) {
+				t.Errorf("Expected ErrUnknownSubjectType, got %v", err)
+			}
+			device := &models.Subject{ID: "dev-1", SubjectType: "device", Attributes: models.JSONMap{"device_id": "ABC-123"}}
+			if err := store.CreateSubject(device); err != nil {
+				t.Fatalf("CreateSubject failed: %v", err)
+			}
+
+			device.Attributes = models.JSONMap{"managed": true}
+			if err := store.UpdateSubject(device); !errors.Is(err, models.ErrMissingSubjectAttribute) {
+				t.Errorf("Expected ErrMissingSubjectAttribute on update, got %v", err)
+			}
+
+			err := store.BulkCreateSubjects([]*models.Subject{
+				{ID: "svc-1", SubjectType: "service", Attributes: models.JSONMap{"service_name": "billing"}},
+				{ID: "svc-2", SubjectType: "service"},
+			})
+			if !errors.Is(err, models.ErrMissingSubjectAttribute) {
+				t.Errorf("Expected ErrMissingSubjectAttribute on bulk create, got %v", err)
+			}
+			if _, err := store.GetSubject("svc-1"); err == nil {
+				t.Error("Expected the bulk create to write nothing")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Names of the GORM callbacks enforcing tenant isolation
+const (
+	tenantScopeCallback  = "abac:tenant_scope"
+	tenantAssignCallback = "abac:tenant_assign"
+	tenantFieldName      = "TenantID"
+)
+
+// ErrTenantMismatch is returned when a tenant-scoped storage writes a record of another tenant
+var ErrTenantMismatch = errors.New("record belongs to another tenant")
+
+// TenantStore is implemented by storages that can be scoped to one tenant
+type TenantStore interface {
+	// ForTenant returns a view of the storage whose reads, updates and deletes of subjects,
+	// resources, policies and attribute history only match rows of tenantID, and whose creates
+	// assign tenantID. The view shares the connection; closing it does not close the storage.
+	ForTenant(tenantID string) (Storage, error)
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context that scopes GORM statements run with it to tenantID
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// ForTenant scopes the storage to tenantID (see TenantStore). Users, actions, groups, exceptions,
+// audit logs and policy changes are not tenant-scoped; raw SQL is never filtered.
+func (s *PostgreSQLStorage) ForTenant(tenantID string) (Storage, error) {
+	return s.forTenant(tenantID)
+}
+
+func (s *PostgreSQLStorage) forTenant(tenantID string) (*PostgreSQLStorage, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant id is required")
+	}
+	if current, scoped := TenantFromContext(s.db.Statement.Context); scoped && current != tenantID {
+		return nil, fmt.Errorf("storage is already scoped to tenant %q", current)
+	}
+	return &PostgreSQLStorage{
+		db:              s.db.WithContext(WithTenant(s.db.Statement.Context, tenantID)),
+		userRepository:  s.userRepository,
+		attributeCipher: s.attributeCipher,
+		tenantID:        tenantID,
+		events:          s.events,
+		subjectTypes:    s.subjectTypes,
+	}, nil
+}
+
+// registerTenantCallbacks installs the GORM callbacks that filter statements run with a tenant
+// context (WithTenant) by tenant_id, on every table whose model has a TenantID field
+func registerTenantCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register(tenantScopeCallback, tenantScope),
+		callbacks.Row().Before("gorm:row").Register(tenantScopeCallback, tenantScope),
+		callbacks.Update().Before("gorm:update").Register(tenantScopeCallback, tenantScope),
+		callbacks.Update().Before("gorm:update").Register(tenantAssignCallback, tenantAssign),
+		callbacks.Delete().Before("gorm:delete").Register(tenantScopeCallback, tenantScope),
+		callbacks.Create().Before("gorm:create").Register(tenantAssignCallback, tenantAssign),
+	)
+}
+
+// tenantScope adds "tenant_id = ?" to reads, updates and deletes of tenant-owned tables
+func tenantScope(db *gorm.DB) {
+	tenantID, scoped := TenantFromContext(db.Statement.Context)
+	if !scoped || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(tenantFieldName)
+	if field == nil {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+// tenantAssign sets TenantID on written records and rejects records of another tenant.
+// Upserts are turned into plain inserts: Save falls back to an upsert when its UPDATE matches
+// no row, which for a row of another tenant would otherwise take the row over.
+func tenantAssign(db *gorm.DB) {
+	tenantID, scoped := TenantFromContext(db.Statement.Context)
+	if !scoped || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(tenantFieldName)
+	if field == nil {
+		return
+	}
+	delete(db.Statement.Clauses, "ON CONFLICT")
+
+	assign := func(record reflect.Value) {
+		if record.Kind() != reflect.Struct {
+			return
+		}
+		current, isZero := field.ValueOf(db.Statement.Context, record)
+		if isZero {
+			db.AddError(field.Set(db.Statement.Context, record, tenantID))
+			return
+		}
+		if current != tenantID {
+			db.AddError(fmt.Errorf("%w: %v", ErrTenantMismatch, current))
+		}
+	}
+
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			assign(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		assign(value)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abac_go_example/models"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	sqliteStorage := NewSQLiteTestStorage(t)
+	acmeStore, err := sqliteStorage.ForTenant("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	globexStore, _ := sqliteStorage.ForTenant("globex")
+	if _, ok := acmeStore.(*SQLiteStorage); !ok {
+		t.Fatalf("expected a SQLite tenant view, got %T", acmeStore)
+	}
+
+	acmeSubject := &models.Subject{ID: "sub-acme", SubjectType: "user", Attributes: models.JSONMap{"department": "engineering"}}
+	if err := acmeStore.CreateSubject(acmeSubject); err != nil || acmeSubject.TenantID != "acme" {
+		t.Fatalf("expected CreateSubject to assign the tenant, got %q (%v)", acmeSubject.TenantID, err)
+	}
+	if err := globexStore.BulkCreateSubjects([]*models.Subject{{ID: "sub-globex", SubjectType: "user"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := acmeStore.CreatePolicy(&models.Policy{ID: "pol-acme", PolicyName: "Acme", Version: "1", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := acmeStore.CreateSubject(&models.Subject{ID: "sub-x", SubjectType: "user", TenantID: "globex"}); !errors.Is(err, ErrTenantMismatch) {
+		t.Errorf("expected ErrTenantMismatch, got %v", err)
+	}
+
+	// Reads only see the tenant's rows
+	if _, err := globexStore.GetSubject("sub-acme"); err == nil {
+		t.Error("expected another tenant's subject to be invisible")
+	}
+	if subjects, err := acmeStore.GetAllSubjects(); err != nil || len(subjects) != 1 || subjects[0].ID != "sub-acme" {
+		t.Errorf("expected only acme subjects, got %v (%v)", subjects, err)
+	}
+	if policies, _, err := globexStore.ListPolicies(ListOptions{}); err != nil || len(policies) != 0 {
+		t.Errorf("expected no globex policies, got %v (%v)", policies, err)
+	}
+	if found, err := globexStore.FindSubjectsByAttribute("department", "engineering"); err != nil || len(found) != 0 {
+		t.Errorf("expected attribute search to stay within the tenant, got %v (%v)", found, err)
+	}
+	history := globexStore.(AttributeHistoryStore)
+	if snapshot, err := history.GetAttributeSnapshot(models.SnapshotEntitySubject, "sub-acme", time.Now().Add(time.Minute)); err != nil || snapshot != nil {
+		t.Errorf("expected another tenant's attribute history to be invisible, got %v (%v)", snapshot, err)
+	}
+
+	// Writes cannot reach or take over another tenant's rows
+	if err := globexStore.UpdateSubject(&models.Subject{ID: "sub-acme", SubjectType: "service"}); err == nil {
+		t.Error("expected updating another tenant's subject to fail")
+	}
+	if err := globexStore.DeleteSubject("sub-acme"); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := acmeStore.GetPolicy("pol-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.TenantID = ""
+	policy.Description = "updated"
+	if err := acmeStore.UpdatePolicy(policy); err != nil || policy.TenantID != "acme" {
+		t.Errorf("expected UpdatePolicy to keep the tenant, got %q (%v)", policy.TenantID, err)
+	}
+	if _, err := globexStore.GetPolicy("pol-acme"); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("expected ErrPolicyNotFound for another tenant's policy, got %v", err)
+	}
+
+	// The unscoped storage sees every tenant
+	stored, err := sqliteStorage.GetSubject("sub-acme")
+	if err != nil || stored.TenantID != "acme" || stored.SubjectType != "user" {
+		t.Errorf("expected the acme subject unchanged, got %+v (%v)", stored, err)
+	}
+	if subjects, _ := sqliteStorage.GetAllSubjects(); len(subjects) != 2 {
+		t.Errorf("expected subjects of both tenants, got %d", len(subjects))
+	}
+
+	// Closing a view keeps the shared connection open
+	acmeStore.Close()
+	if _, err := sqliteStorage.GetSubject("sub-acme"); err != nil {
+		t.Errorf("expected the storage to stay open, got %v", err)
+	}
+	if _, err := sqliteStorage.ForTenant(""); err == nil {
+		t.Error("expected an empty tenant to be rejected")
+	}
+}
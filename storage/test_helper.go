@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"testing"
+	"time"
 
 	"abac_go_example/models"
 )
@@ -39,6 +40,20 @@ func NewTestStorage(t *testing.T) *PostgreSQLStorage {
 	return storage
 }
 
+// NewSQLiteTestStorage creates an in-memory SQLite storage instance for testing.
+// Unlike NewTestStorage it needs no external database and is never skipped.
+func NewSQLiteTestStorage(t *testing.T) *SQLiteStorage {
+	t.Setenv("DB_LOG_LEVEL", "silent")
+
+	storage, err := NewSQLiteStorage(&SQLiteConfig{Path: SQLiteMemoryPath, BusyTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create sqlite test storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	return storage
+}
+
 // cleanupTestTables cleans up test data
 func cleanupTestTables(t *testing.T, storage *PostgreSQLStorage) {
 	// Delete all test data